@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -51,6 +52,16 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Keep the configuration as loaded from the file for change detection
+	loaded := *config
+
+	if auditFile := auditFileFlag(); auditFile != "" {
+		config.Audit.File = auditFile
+	}
+	if hasFlag("--dry-run") {
+		config.CLI.DryRun = true
+	}
+
 	// Create production handler with all tools registered
 	handler, err := mcp.CreateProductionHandler(config)
 	if err != nil {
@@ -75,6 +86,15 @@ func main() {
 	}
 	logger.Info("Server started successfully")
 
+	if hasFlag("--watch") || config.Server.Watch {
+		if reloader, ok := server.(mcp.ConfigReloader); ok {
+			configPath := mcp.ConfigPath()
+			watcher := mcp.NewConfigWatcher(logger, configPath, &loaded, reloader)
+			go watcher.Run(ctx)
+			logger.Info("Watching configuration for changes", "path", configPath)
+		}
+	}
+
 	// Wait for context cancellation
 	<-ctx.Done()
 
@@ -115,6 +135,29 @@ func detectTransport() mcp.TransportType {
 	return mcp.TransportStdio
 }
 
+// hasFlag reports whether the boolean flag name was given
+func hasFlag(name string) bool {
+	for _, arg := range os.Args[1:] {
+		if arg == name {
+			return true
+		}
+	}
+	return false
+}
+
+// auditFileFlag returns the value of the --audit-file flag, or an empty string
+func auditFileFlag() string {
+	for i, arg := range os.Args {
+		if value, ok := strings.CutPrefix(arg, "--audit-file="); ok {
+			return value
+		}
+		if arg == "--audit-file" && i+1 < len(os.Args) {
+			return os.Args[i+1]
+		}
+	}
+	return ""
+}
+
 // printHelp displays usage information
 func printHelp() {
 	log.Println("go-invoice-mcp - MCP server for go-invoice CLI integration")
@@ -125,6 +168,9 @@ func printHelp() {
 	log.Println("  --stdio      Use stdio transport (default)")
 	log.Println("  --http       Use HTTP transport")
 	log.Println("  --config     Path to MCP configuration file")
+	log.Println("  --audit-file Append every tool command execution to this JSONL file")
+	log.Println("  --dry-run    Return the command mutating tools would run instead of running it")
+	log.Println("  --watch      Reload the configuration file when it changes")
 	log.Println("  --version    Show version information")
 	log.Println("  --help       Show this help message")
 	log.Println()
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/render"
+)
+
+// maxLogoSize caps the logo file inlined into generated invoices, keeping them small
+// enough to email
+const maxLogoSize = 2 << 20
+
+var (
+	// ErrLogoNotImage is returned for a BUSINESS_LOGO file that is not an image
+	ErrLogoNotImage = fmt.Errorf("logo is not an image")
+	// ErrLogoTooLarge is returned for a BUSINESS_LOGO file over maxLogoSize
+	ErrLogoTooLarge = fmt.Errorf("logo file is too large")
+)
+
+// addBusinessLogo sets the logo shown in the invoice header from BUSINESS_LOGO. A URL is
+// linked as is; a local file is inlined as a data URI so the HTML is self-contained. A
+// logo that cannot be used is reported as a warning and the invoice renders without it.
+func (a *App) addBusinessLogo(data *render.TemplateData, cfg *config.Config) {
+	logo := strings.TrimSpace(cfg.Business.Logo)
+	if logo == "" {
+		return
+	}
+
+	uri, err := businessLogoURI(logo)
+	if err != nil {
+		a.logger.Printf("⚠️  Skipping business logo: %v\n", err)
+		return
+	}
+	data.Business.Logo = uri
+}
+
+// businessLogoURI returns the image source for logo, an http(s) URL or a local image path
+func businessLogoURI(logo string) (template.URL, error) {
+	if parsed, err := url.Parse(logo); err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "" {
+		return template.URL(logo), nil //nolint:gosec // http(s) URL from the user's own configuration
+	}
+
+	path, err := config.ExpandPath(logo)
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Open(path) // #nosec G304 -- Path comes from the user's own configuration
+	if err != nil {
+		return "", fmt.Errorf("failed to open logo: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	content, err := io.ReadAll(io.LimitReader(file, maxLogoSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read logo: %w", err)
+	}
+	if len(content) > maxLogoSize {
+		return "", fmt.Errorf("%w: %s is over %d MB", ErrLogoTooLarge, path, maxLogoSize>>20)
+	}
+
+	contentType := logoContentType(path, content)
+	if !strings.HasPrefix(contentType, "image/") {
+		return "", fmt.Errorf("%w: %s is %s", ErrLogoNotImage, path, contentType)
+	}
+
+	uri := "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(content)
+	return template.URL(uri), nil //nolint:gosec // Data URI of an image we read and encoded
+}
+
+// logoContentType sniffs the image type of a logo file. SVG is text, so it is recognized by
+// its extension instead.
+func logoContentType(path string, content []byte) string {
+	if strings.EqualFold(filepath.Ext(path), ".svg") && strings.Contains(string(content), "<svg") {
+		return "image/svg+xml"
+	}
+	contentType, _, _ := strings.Cut(http.DetectContentType(content), ";")
+	return contentType
+}
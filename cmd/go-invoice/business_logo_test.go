@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/render"
+)
+
+func TestBusinessLogoURI(t *testing.T) {
+	dir := t.TempDir()
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	writeFile := func(name string, content []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, content, 0o600))
+		return path
+	}
+
+	t.Run("URL", func(t *testing.T) {
+		uri, err := businessLogoURI("https://example.com/logo.png")
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/logo.png", string(uri))
+	})
+
+	t.Run("FileInlined", func(t *testing.T) {
+		uri, err := businessLogoURI(writeFile("logo.png", png))
+		require.NoError(t, err)
+		assert.Equal(t, "data:image/png;base64,"+base64.StdEncoding.EncodeToString(png), string(uri))
+	})
+
+	t.Run("SVG", func(t *testing.T) {
+		uri, err := businessLogoURI(writeFile("logo.svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(uri), "data:image/svg+xml;base64,"))
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := businessLogoURI(filepath.Join(dir, "missing.png"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("NotAnImage", func(t *testing.T) {
+		_, err := businessLogoURI(writeFile("logo.png.txt", []byte("not an image")))
+		require.ErrorIs(t, err, ErrLogoNotImage)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		_, err := businessLogoURI(writeFile("huge.png", append(png, make([]byte, maxLogoSize)...)))
+		require.ErrorIs(t, err, ErrLogoTooLarge)
+	})
+}
+
+func TestAddBusinessLogo(t *testing.T) {
+	app := &App{logger: cli.NewLogger(false)}
+	data := &render.TemplateData{}
+
+	// An unusable logo is skipped rather than failing the generation
+	app.addBusinessLogo(data, &config.Config{Business: config.BusinessConfig{Logo: filepath.Join(t.TempDir(), "missing.png")}})
+	assert.Empty(t, data.Business.Logo)
+
+	app.addBusinessLogo(data, &config.Config{Business: config.BusinessConfig{Logo: "https://example.com/logo.png"}})
+	assert.Equal(t, "https://example.com/logo.png", string(data.Business.Logo))
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -12,8 +14,10 @@ import (
 	// removed unused imports
 	"github.com/spf13/cobra"
 
+	"github.com/mrz1836/go-invoice/internal/encoding/csvout"
 	"github.com/mrz1836/go-invoice/internal/models"
 	"github.com/mrz1836/go-invoice/internal/services"
+	"github.com/mrz1836/go-invoice/internal/storage"
 )
 
 // buildClientCommand creates the client command with all subcommands
@@ -21,7 +25,7 @@ func (a *App) buildClientCommand() *cobra.Command {
 	clientCmd := &cobra.Command{
 		Use:   "client",
 		Short: "Client management commands",
-		Long:  "Create, list, show, update, and delete clients",
+		Long:  "Create, list, show, update, merge, and delete clients, and print client statements",
 	}
 
 	// Add client subcommands
@@ -30,16 +34,49 @@ func (a *App) buildClientCommand() *cobra.Command {
 	clientCmd.AddCommand(a.buildClientShowCommand())
 	clientCmd.AddCommand(a.buildClientUpdateCommand())
 	clientCmd.AddCommand(a.buildClientDeleteCommand())
+	clientCmd.AddCommand(a.buildClientMergeCommand())
+	clientCmd.AddCommand(a.buildClientStatementCommand())
 
 	return clientCmd
 }
 
+// findClientByIDOrName looks a client up by ID, falling back to a unique case-insensitive
+// match on part of the client name
+func findClientByIDOrName(ctx context.Context, clientStorage storage.ClientStorage, identifier string) (*models.Client, error) {
+	client, err := clientStorage.GetClient(ctx, models.ClientID(identifier))
+	if err == nil {
+		return client, nil
+	}
+
+	listResult, err := clientStorage.ListClients(ctx, true, 100, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search clients: %w", err)
+	}
+
+	var matches []*models.Client
+	searchLower := strings.ToLower(identifier)
+	for _, c := range listResult.Clients {
+		if strings.Contains(strings.ToLower(c.Name), searchLower) {
+			matches = append(matches, c)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", models.ErrClientNotFound, identifier)
+	}
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("%w matching '%s'", models.ErrMultipleClientsFound, identifier)
+	}
+	return matches[0], nil
+}
+
 // buildClientCreateCommand creates the client create command
 func (a *App) buildClientCreateCommand() *cobra.Command {
-	var name, email, phone, address, taxID string
+	var name, email, phone, address, taxID, templateName, exemptionReason, cryptoFeeCurrency string
 	var cryptoFeeEnabled bool
 	var cryptoFeeAmount float64
 	var lateFeeEnabled bool
+	var taxExempt bool
 
 	cmd := &cobra.Command{
 		Use:   "create",
@@ -47,7 +84,10 @@ func (a *App) buildClientCreateCommand() *cobra.Command {
 		Long:  "Create a new client with contact information",
 		Example: `  go-invoice client create --name "Acme Corp" --email "contact@acme.com"
   go-invoice client create --name "John Smith" --email "john@example.com" --phone "+1-555-123-4567"
-  go-invoice client create --name "Acme Company" --email "billing@acme.com" --crypto-fee --crypto-fee-amount 25.00 --late-fee`,
+  go-invoice client create --name "Acme Company" --email "billing@acme.com" --crypto-fee --crypto-fee-amount 25.00 --late-fee
+  go-invoice client create --name "Chain Labs" --email "ap@chainlabs.io" --crypto-fee-currency USDC
+  go-invoice client create --name "Brand Co" --email "ap@brand.co" --template brandco
+  go-invoice client create --name "City Library" --email "ap@library.org" --tax-exempt --exemption-reason "501(c)(3) nonprofit"`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 
@@ -59,20 +99,24 @@ func (a *App) buildClientCreateCommand() *cobra.Command {
 			}
 
 			// Create storage and services
-			invoiceStorage, clientStorage := a.createStorageInstances(config.Storage.DataDir)
-			idGen := services.NewUUIDGenerator()
+			invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+			idGen := a.idGenerator(config.Storage)
 			clientService := services.NewClientService(clientStorage, invoiceStorage, a.logger, idGen)
 
 			// Create client request
 			req := models.CreateClientRequest{
-				Name:             name,
-				Email:            email,
-				Phone:            phone,
-				Address:          address,
-				TaxID:            taxID,
-				CryptoFeeEnabled: cryptoFeeEnabled,
-				CryptoFeeAmount:  cryptoFeeAmount,
-				LateFeeEnabled:   lateFeeEnabled,
+				Name:              name,
+				Email:             email,
+				Phone:             phone,
+				Address:           address,
+				TaxID:             taxID,
+				TaxExempt:         taxExempt,
+				ExemptionReason:   exemptionReason,
+				CryptoFeeEnabled:  cryptoFeeEnabled,
+				CryptoFeeAmount:   cryptoFeeAmount,
+				CryptoFeeCurrency: strings.ToUpper(strings.TrimSpace(cryptoFeeCurrency)),
+				LateFeeEnabled:    lateFeeEnabled,
+				TemplateName:      templateName,
 			}
 
 			client, err := clientService.CreateClient(ctx, req)
@@ -81,12 +125,20 @@ func (a *App) buildClientCreateCommand() *cobra.Command {
 			}
 
 			a.logger.Info("Client created successfully", "name", client.Name, "id", client.ID)
+			a.logger.Result(string(client.ID))
 			if cryptoFeeEnabled {
 				a.logger.Printf("💰 Crypto service fee enabled: $%.2f\n", cryptoFeeAmount)
 			}
+			if client.CryptoFeeCurrency != "" {
+				a.logger.Printf("💱 Invoices show the amount due in %s\n", client.CryptoFeeCurrency)
+			}
 			if lateFeeEnabled {
 				a.logger.Printf("⚠️  Late fee policy enabled (1.5%% per month / 18%% APR)\n")
 			}
+			if client.TemplateName != "" {
+				a.logger.Printf("🎨 Invoice template: %s\n", client.TemplateName)
+			}
+			a.displayTaxExemption(client)
 			return nil
 		},
 	}
@@ -96,9 +148,13 @@ func (a *App) buildClientCreateCommand() *cobra.Command {
 	cmd.Flags().StringVar(&phone, "phone", "", "Client phone number")
 	cmd.Flags().StringVar(&address, "address", "", "Client address")
 	cmd.Flags().StringVar(&taxID, "tax-id", "", "Tax ID (EIN, VAT number, etc.)")
+	cmd.Flags().BoolVar(&taxExempt, "tax-exempt", false, "Client is exempt from tax; no tax is charged on its invoices")
+	cmd.Flags().StringVar(&exemptionReason, "exemption-reason", "", "Reason printed on invoices instead of the tax line (e.g. reverse charge)")
 	cmd.Flags().BoolVar(&cryptoFeeEnabled, "crypto-fee", false, "Enable cryptocurrency service fee for this client")
 	cmd.Flags().Float64Var(&cryptoFeeAmount, "crypto-fee-amount", 25.00, "Cryptocurrency service fee amount")
+	cmd.Flags().StringVar(&cryptoFeeCurrency, "crypto-fee-currency", "", "Show the amount due in this crypto currency (USDC or BSV) at the rate when generated")
 	cmd.Flags().BoolVar(&lateFeeEnabled, "late-fee", true, "Enable late fee policy on invoices (default: true)")
+	cmd.Flags().StringVar(&templateName, "template", "", "Default invoice template for this client")
 
 	if err := cmd.MarkFlagRequired("name"); err != nil {
 		return cmd
@@ -116,6 +172,8 @@ func (a *App) buildClientListCommand() *cobra.Command {
 	var activeOnly, inactiveOnly bool
 	var search string
 	var limit int
+	var fuzzy bool
+	var maxDistance int
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -123,7 +181,9 @@ func (a *App) buildClientListCommand() *cobra.Command {
 		Long:  "List all clients with filtering options",
 		Example: `  go-invoice client list
   go-invoice client list --search "Acme"
-  go-invoice client list --inactive --output json`,
+  go-invoice client list --search "acme" --fuzzy
+  go-invoice client list --inactive --output json
+  go-invoice client list --output csv > clients.csv`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 
@@ -135,30 +195,33 @@ func (a *App) buildClientListCommand() *cobra.Command {
 			}
 
 			// Create storage and services
-			invoiceStorage, clientStorage := a.createStorageInstances(config.Storage.DataDir)
-			_ = invoiceStorage // unused in this command
+			invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
 
 			// Determine active filter
 			activeFilter := !inactiveOnly
 
-			// List clients
-			result, err := clientStorage.ListClients(ctx, activeFilter, limit, 0)
-			if err != nil {
-				return fmt.Errorf("failed to list clients: %w", err)
-			}
-
-			// Filter by search if provided
-			filteredClients := result.Clients
+			var filteredClients []*models.Client
 			if search != "" {
-				var filtered []*models.Client
-				searchLower := strings.ToLower(search)
-				for _, client := range result.Clients {
-					if strings.Contains(strings.ToLower(client.Name), searchLower) ||
-						strings.Contains(strings.ToLower(client.Email), searchLower) {
-						filtered = append(filtered, client)
-					}
+				// Search by name, email or address, best match first
+				clientService := services.NewClientService(clientStorage, invoiceStorage, a.logger, a.idGenerator(config.Storage))
+				matches, err := clientService.SearchClients(ctx, search, services.SearchOptions{
+					ActiveOnly:  activeFilter,
+					Fuzzy:       fuzzy,
+					MaxDistance: maxDistance,
+					Limit:       limit,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to search clients: %w", err)
 				}
-				filteredClients = filtered
+				for _, match := range matches {
+					filteredClients = append(filteredClients, match.Client)
+				}
+			} else {
+				result, err := clientStorage.ListClients(ctx, activeFilter, limit, 0)
+				if err != nil {
+					return fmt.Errorf("failed to list clients: %w", err)
+				}
+				filteredClients = result.Clients
 			}
 
 			// Output results
@@ -168,6 +231,13 @@ func (a *App) buildClientListCommand() *cobra.Command {
 				encoder.SetIndent("", "  ")
 				return encoder.Encode(filteredClients)
 
+			case "csv":
+				data, err := clientsCSV(filteredClients)
+				if err != nil {
+					return err
+				}
+				a.logger.Printf("%s", data)
+
 			default:
 				if len(filteredClients) == 0 {
 					a.logger.Info("No clients found")
@@ -201,15 +271,35 @@ func (a *App) buildClientListCommand() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVar(&outputFormat, "output", "table", "Output format (table, json)")
+	cmd.Flags().StringVar(&outputFormat, "output", "table", "Output format (table, json, csv)")
 	cmd.Flags().BoolVar(&activeOnly, "active", false, "Show only active clients")
 	cmd.Flags().BoolVar(&inactiveOnly, "inactive", false, "Show only inactive clients")
-	cmd.Flags().StringVar(&search, "search", "", "Search clients by name or email")
+	cmd.Flags().StringVar(&search, "search", "", "Search clients by name, email or address")
+	cmd.Flags().BoolVar(&fuzzy, "fuzzy", false, "Also match names with small typos when searching")
+	cmd.Flags().IntVar(&maxDistance, "max-distance", 2, "Maximum number of typos allowed by --fuzzy")
 	cmd.Flags().IntVar(&limit, "limit", 100, "Maximum number of clients to return")
 
 	return cmd
 }
 
+// clientsCSV encodes clients as CSV, one record per client
+func clientsCSV(clients []*models.Client) ([]byte, error) {
+	header := []string{"ID", "Name", "Email", "Phone", "Address", "TaxID", "Active"}
+	records := make([][]string, 0, len(clients))
+	for _, client := range clients {
+		records = append(records, []string{
+			string(client.ID),
+			client.Name,
+			client.Email,
+			client.Phone,
+			client.Address,
+			client.TaxID,
+			strconv.FormatBool(client.Active),
+		})
+	}
+	return csvout.Marshal(header, records)
+}
+
 // buildClientShowCommand creates the client show command
 func (a *App) buildClientShowCommand() *cobra.Command {
 	var outputFormat string
@@ -230,37 +320,15 @@ func (a *App) buildClientShowCommand() *cobra.Command {
 			}
 
 			// Create storage and services
-			invoiceStorage, clientStorage := a.createStorageInstances(config.Storage.DataDir)
+			invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
 
-			// Try to find by ID first
-			client, err := clientStorage.GetClient(ctx, models.ClientID(args[0]))
+			client, err := findClientByIDOrName(ctx, clientStorage, args[0])
 			if err != nil {
-				// Try to find by name
-				listResult, listErr := clientStorage.ListClients(ctx, true, 100, 0)
-				if listErr != nil {
-					return fmt.Errorf("failed to search clients: %w", listErr)
-				}
-
-				var matches []*models.Client
-				searchLower := strings.ToLower(args[0])
-				for _, c := range listResult.Clients {
-					if strings.Contains(strings.ToLower(c.Name), searchLower) {
-						matches = append(matches, c)
-					}
-				}
-
-				if len(matches) == 0 {
-					return fmt.Errorf("%w: %s", models.ErrClientNotFound, args[0])
-				}
-				if len(matches) > 1 {
-					return fmt.Errorf("%w matching '%s'", models.ErrMultipleClientsFound, args[0])
-				}
-				client = matches[0]
+				return err
 			}
 
 			// Get invoice statistics
-			idGen := services.NewUUIDGenerator()
-			invoiceService := services.NewInvoiceService(invoiceStorage, clientStorage, a.logger, idGen)
+			invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
 			filter := models.InvoiceFilter{}
 			result, err := invoiceService.ListInvoices(ctx, filter)
 			if err != nil {
@@ -309,6 +377,16 @@ func (a *App) buildClientShowCommand() *cobra.Command {
 				if _, err := fmt.Fprintf(os.Stdout, "  Tax ID:   %s\n", client.TaxID); err != nil {
 					return fmt.Errorf("failed to write output: %w", err)
 				}
+				if client.TaxExempt {
+					if _, err := fmt.Fprintf(os.Stdout, "  Tax:      exempt (%s)\n", cmp.Or(client.ExemptionReason, "no reason given")); err != nil {
+						return fmt.Errorf("failed to write output: %w", err)
+					}
+				}
+				if client.TemplateName != "" {
+					if _, err := fmt.Fprintf(os.Stdout, "  Template: %s\n", client.TemplateName); err != nil {
+						return fmt.Errorf("failed to write output: %w", err)
+					}
+				}
 				status := "Active"
 				if !client.Active {
 					status = "Inactive"
@@ -338,11 +416,12 @@ func (a *App) buildClientShowCommand() *cobra.Command {
 
 // buildClientUpdateCommand creates the client update command
 func (a *App) buildClientUpdateCommand() *cobra.Command {
-	var name, email, phone, address, taxID string
+	var name, email, phone, address, taxID, templateName, exemptionReason, cryptoFeeCurrency string
 	var activate, deactivate bool
 	var cryptoFeeEnabled bool
 	var cryptoFeeAmount float64
 	var lateFeeEnabled bool
+	var taxExempt bool
 
 	cmd := &cobra.Command{
 		Use:   "update [client-id or name]",
@@ -360,8 +439,8 @@ func (a *App) buildClientUpdateCommand() *cobra.Command {
 			}
 
 			// Create storage and services
-			invoiceStorage, clientStorage := a.createStorageInstances(config.Storage.DataDir)
-			idGen := services.NewUUIDGenerator()
+			invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+			idGen := a.idGenerator(config.Storage)
 			clientService := services.NewClientService(clientStorage, invoiceStorage, a.logger, idGen)
 
 			// Find client
@@ -412,6 +491,14 @@ func (a *App) buildClientUpdateCommand() *cobra.Command {
 				client.TaxID = taxID
 				updated = true
 			}
+			if cmd.Flags().Changed("tax-exempt") {
+				client.TaxExempt = taxExempt
+				updated = true
+			}
+			if cmd.Flags().Changed("exemption-reason") {
+				client.ExemptionReason = strings.TrimSpace(exemptionReason)
+				updated = true
+			}
 			if activate && deactivate {
 				return models.ErrCannotActivateDeactivate
 			}
@@ -431,10 +518,18 @@ func (a *App) buildClientUpdateCommand() *cobra.Command {
 				client.CryptoFeeAmount = cryptoFeeAmount
 				updated = true
 			}
+			if cmd.Flags().Changed("crypto-fee-currency") {
+				client.CryptoFeeCurrency = strings.ToUpper(strings.TrimSpace(cryptoFeeCurrency))
+				updated = true
+			}
 			if cmd.Flags().Changed("late-fee") {
 				client.LateFeeEnabled = lateFeeEnabled
 				updated = true
 			}
+			if cmd.Flags().Changed("template") {
+				client.TemplateName = strings.TrimSpace(templateName)
+				updated = true
+			}
 
 			if !updated {
 				return models.ErrNoUpdatesSpecified
@@ -450,11 +545,18 @@ func (a *App) buildClientUpdateCommand() *cobra.Command {
 			if client.CryptoFeeEnabled {
 				a.logger.Printf("💰 Crypto service fee: $%.2f\n", client.CryptoFeeAmount)
 			}
+			if client.CryptoFeeCurrency != "" {
+				a.logger.Printf("💱 Invoices show the amount due in %s\n", client.CryptoFeeCurrency)
+			}
 			if client.LateFeeEnabled {
 				a.logger.Printf("⚠️  Late fee policy enabled (1.5%% per month / 18%% APR)\n")
 			} else {
 				a.logger.Printf("ℹ️  Late fee policy disabled for this client\n")
 			}
+			if client.TemplateName != "" {
+				a.logger.Printf("🎨 Invoice template: %s\n", client.TemplateName)
+			}
+			a.displayTaxExemption(client)
 			return nil
 		},
 	}
@@ -464,11 +566,15 @@ func (a *App) buildClientUpdateCommand() *cobra.Command {
 	cmd.Flags().StringVar(&phone, "phone", "", "Update client phone")
 	cmd.Flags().StringVar(&address, "address", "", "Update client address")
 	cmd.Flags().StringVar(&taxID, "tax-id", "", "Update tax ID")
+	cmd.Flags().BoolVar(&taxExempt, "tax-exempt", false, "Mark the client tax exempt (--tax-exempt=false to charge tax again)")
+	cmd.Flags().StringVar(&exemptionReason, "exemption-reason", "", "Update the reason printed on invoices instead of the tax line")
 	cmd.Flags().BoolVar(&activate, "activate", false, "Activate client")
 	cmd.Flags().BoolVar(&deactivate, "deactivate", false, "Deactivate client")
 	cmd.Flags().BoolVar(&cryptoFeeEnabled, "crypto-fee", false, "Enable cryptocurrency service fee for this client")
 	cmd.Flags().Float64Var(&cryptoFeeAmount, "crypto-fee-amount", 25.00, "Cryptocurrency service fee amount")
+	cmd.Flags().StringVar(&cryptoFeeCurrency, "crypto-fee-currency", "", "Show the amount due in this crypto currency (USDC or BSV); empty to stop")
 	cmd.Flags().BoolVar(&lateFeeEnabled, "late-fee", true, "Enable late fee policy on invoices")
+	cmd.Flags().StringVar(&templateName, "template", "", "Set the default invoice template for this client (empty to clear)")
 
 	return cmd
 }
@@ -493,8 +599,8 @@ func (a *App) buildClientDeleteCommand() *cobra.Command {
 			}
 
 			// Create storage and services
-			invoiceStorage, clientStorage := a.createStorageInstances(config.Storage.DataDir)
-			idGen := services.NewUUIDGenerator()
+			invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+			idGen := a.idGenerator(config.Storage)
 			clientService := services.NewClientService(clientStorage, invoiceStorage, a.logger, idGen)
 
 			// Find client
@@ -561,3 +667,15 @@ func (a *App) buildClientDeleteCommand() *cobra.Command {
 
 	return cmd
 }
+
+// displayTaxExemption notes when no tax will be charged to a client
+func (a *App) displayTaxExemption(client *models.Client) {
+	if !client.TaxExempt {
+		return
+	}
+	if client.ExemptionReason != "" {
+		a.logger.Printf("🧾 Tax exempt: %s\n", client.ExemptionReason)
+	} else {
+		a.logger.Printf("🧾 Tax exempt\n")
+	}
+}
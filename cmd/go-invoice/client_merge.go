@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/services"
+)
+
+// ErrMergeClientsRequired is returned when client merge is run without --from or --into
+var ErrMergeClientsRequired = fmt.Errorf("--from and --into are required")
+
+// buildClientMergeCommand creates the client merge subcommand
+func (a *App) buildClientMergeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "merge",
+		Short: "Merge a duplicate client into another client",
+		Long: `Move every invoice of the --from client onto the --into client, then deactivate
+the --from client (or delete it permanently with --delete).
+
+If any invoice cannot be updated, the invoices already moved are put back and the
+--from client is left untouched. Paid invoices are only moved with --force.`,
+		Example: `  # Merge a duplicate into the client to keep
+  go-invoice client merge --from CLIENT-002 --into CLIENT-001
+
+  # Also move paid invoices and delete the duplicate
+  go-invoice client merge --from CLIENT-002 --into CLIENT-001 --force --delete`,
+		Args: cobra.NoArgs,
+		RunE: a.runClientMerge,
+	}
+
+	cmd.Flags().String("from", "", "ID of the client to merge away (required)")
+	cmd.Flags().String("into", "", "ID of the client to keep (required)")
+	cmd.Flags().Bool("force", false, "Also move paid invoices")
+	cmd.Flags().Bool("delete", false, "Permanently delete the merged client instead of deactivating it")
+
+	return cmd
+}
+
+// runClientMerge handles the client merge command
+func (a *App) runClientMerge(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	from, _ := cmd.Flags().GetString("from")
+	into, _ := cmd.Flags().GetString("into")
+	force, _ := cmd.Flags().GetBool("force")
+	deleteSource, _ := cmd.Flags().GetBool("delete")
+
+	if from == "" || into == "" {
+		return ErrMergeClientsRequired
+	}
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	clientService := services.NewClientService(clientStorage, invoiceStorage, a.logger, a.idGenerator(config.Storage))
+
+	result, err := clientService.MergeClients(ctx, models.ClientID(from), models.ClientID(into),
+		services.MergeClientsOptions{Force: force, Delete: deleteSource})
+	if errors.Is(err, services.ErrMergePaidInvoices) {
+		return fmt.Errorf("%w; use --force to move them", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to merge clients: %w", err)
+	}
+
+	a.logger.Printf("✅ Merged %s (%s) into %s (%s)\n", result.From.Name, result.From.ID, result.Into.Name, result.Into.ID)
+	a.logger.Printf("   Invoices updated: %d\n", len(result.InvoicesUpdated))
+	if result.SourceDeleted {
+		a.logger.Printf("   %s was deleted\n", result.From.ID)
+	} else {
+		a.logger.Printf("   %s was deactivated\n", result.From.ID)
+	}
+
+	return nil
+}
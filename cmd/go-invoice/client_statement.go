@@ -0,0 +1,339 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/encoding/csvout"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/render"
+	"github.com/mrz1836/go-invoice/internal/templates"
+)
+
+// ErrInvalidStatementOutput is returned when a statement is printed with an unknown --output format
+var ErrInvalidStatementOutput = fmt.Errorf("invalid output format (must be table, csv, json, or html)")
+
+// statementTemplateName is the name the statement template is registered under
+const statementTemplateName = "statement"
+
+// clientStatement lists a client's invoices in a date range with running totals, the
+// outstanding balance by age, and the totals per currency
+type clientStatement struct {
+	Client   *models.Client        `json:"client"`
+	Business config.BusinessConfig `json:"-"`
+	From     string                `json:"from,omitempty"`
+	To       string                `json:"to,omitempty"`
+	AsOf     string                `json:"as_of"`
+	Rows     []*statementRow       `json:"invoices"`
+	Totals   []*statementTotal     `json:"totals"`
+	Aging    []agingBucket         `json:"aging"`
+}
+
+// statementRow is one invoice on a statement. The running totals are those of the
+// invoice's currency up to and including this row; voided invoices do not add to them.
+type statementRow struct {
+	Number             string       `json:"number"`
+	Date               string       `json:"date"`
+	DueDate            string       `json:"due_date"`
+	Status             string       `json:"status"`
+	Currency           string       `json:"currency"`
+	Voided             bool         `json:"voided,omitempty"`
+	Billed             money.Amount `json:"billed"`
+	Paid               money.Amount `json:"paid"`
+	Outstanding        money.Amount `json:"outstanding"`
+	RunningBilled      money.Amount `json:"running_billed"`
+	RunningPaid        money.Amount `json:"running_paid"`
+	RunningOutstanding money.Amount `json:"running_outstanding"`
+}
+
+// statementTotal holds a statement's totals for one currency
+type statementTotal struct {
+	Currency    string       `json:"currency"`
+	Billed      money.Amount `json:"billed"`
+	Paid        money.Amount `json:"paid"`
+	Outstanding money.Amount `json:"outstanding"`
+}
+
+// buildClientStatementCommand creates the client statement subcommand
+func (a *App) buildClientStatementCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "statement [client-id or name]",
+		Short: "Print a statement of a client's invoices and balance",
+		Long: `List every invoice for the client dated in the range, oldest first, with running
+totals of the amounts billed, paid and outstanding, followed by the outstanding
+balance in the 0-30, 31-60, 61-90 and 90+ day aging buckets.
+
+Balances are computed as of the --to date (default: today), so payments received
+later are not counted. Voided invoices are listed but left out of every total,
+and amounts in different currencies are always totaled separately.
+
+With --output html the statement is rendered with the built-in printable
+statement template, or with the template file given by --template. Templates
+receive the statement with .Business, .Client, .From, .To, .AsOf, .Rows,
+.Totals and .Aging, and can use the same functions as invoice templates.`,
+		Example: `  # Statement for the first quarter
+  go-invoice client statement CLIENT-001 --from 2024-01-01 --to 2024-03-31
+
+  # As CSV for a spreadsheet
+  go-invoice client statement "Acme" --output csv
+
+  # Printable HTML statement
+  go-invoice client statement CLIENT-001 --output html > statement.html
+  go-invoice client statement CLIENT-001 --output html --template statement.html > statement.html`,
+		Args: cobra.ExactArgs(1),
+		RunE: a.runClientStatement,
+	}
+
+	cmd.Flags().String("from", "", "Include invoices dated on or after this date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().String("to", "", "Include invoices dated on or before this date (DATE_FORMAT or YYYY-MM-DD, default: today)")
+	cmd.Flags().String("output", "table", "Output format (table, csv, json, html)")
+	cmd.Flags().String("template", "", "Statement template file for --output html (default: built-in template)")
+
+	return cmd
+}
+
+// runClientStatement handles the client statement command
+func (a *App) runClientStatement(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	switch outputFormat {
+	case "table", "csv", "json", "html":
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidStatementOutput, outputFormat)
+	}
+	templatePath, _ := cmd.Flags().GetString("template")
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	var filter models.InvoiceFilter
+	if err = a.buildDateRangeFilter(cmd, &filter, config); err != nil {
+		return err
+	}
+	asOf := time.Now()
+	if !filter.DateTo.IsZero() {
+		asOf = filter.DateTo
+	}
+
+	_, clientStorage := a.createStorageInstances(config.Storage)
+	client, err := findClientByIDOrName(ctx, clientStorage, args[0])
+	if err != nil {
+		return err
+	}
+
+	filter.ClientID = client.ID
+	invoiceService := a.createInvoiceService(config)
+	result, err := invoiceService.ListInvoices(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	applyDefaultCurrency(config, result.Invoices...)
+
+	statement := buildClientStatement(client, result.Invoices, filter, asOf, config.Invoice.DateLayout())
+	statement.Business = config.Business
+
+	switch outputFormat {
+	case "json":
+		data, err := json.MarshalIndent(statement, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal statement: %w", err)
+		}
+		a.logger.Println(string(data))
+		return nil
+	case "csv":
+		return a.outputClientStatementCSV(statement)
+	case "html":
+		html, err := a.renderClientStatement(ctx, statement, templatePath)
+		if err != nil {
+			return err
+		}
+		a.logger.Printf("%s", html)
+		return nil
+	default:
+		return a.outputClientStatementTable(statement)
+	}
+}
+
+// buildClientStatement lists the client's invoices oldest first with running totals per
+// currency, computing balances from the payments received by asOf. Dates are formatted
+// with dateLayout.
+func buildClientStatement(client *models.Client, invoices []*models.Invoice, filter models.InvoiceFilter, asOf time.Time, dateLayout string) *clientStatement {
+	statement := &clientStatement{
+		Client: client,
+		AsOf:   asOf.Format(dateLayout),
+		Rows:   []*statementRow{},
+		Totals: []*statementTotal{},
+		Aging:  make([]agingBucket, len(agingBuckets)),
+	}
+	if !filter.DateFrom.IsZero() {
+		statement.From = filter.DateFrom.Format(dateLayout)
+	}
+	if !filter.DateTo.IsZero() {
+		statement.To = filter.DateTo.Format(dateLayout)
+	}
+	for i, bucket := range agingBuckets {
+		statement.Aging[i] = agingBucket{Label: bucket.Label, Amounts: make(map[string]money.Amount)}
+	}
+
+	sorted := make([]*models.Invoice, len(invoices))
+	copy(sorted, invoices)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].Date.Equal(sorted[j].Date) {
+			return sorted[i].Date.Before(sorted[j].Date)
+		}
+		return sorted[i].Number < sorted[j].Number
+	})
+
+	totals := make(map[string]*statementTotal)
+	for _, inv := range sorted {
+		total, ok := totals[inv.Currency]
+		if !ok {
+			total = &statementTotal{Currency: inv.Currency}
+			totals[inv.Currency] = total
+			statement.Totals = append(statement.Totals, total)
+		}
+
+		row := &statementRow{
+			Number:   inv.Number,
+			Date:     inv.Date.Format(dateLayout),
+			DueDate:  inv.DueDate.Format(dateLayout),
+			Status:   inv.Status,
+			Currency: inv.Currency,
+			Voided:   inv.Status == models.StatusVoided,
+			Billed:   inv.Total,
+		}
+
+		switch {
+		case row.Voided:
+			row.Billed = 0
+		case inv.Status == models.StatusPaid:
+			row.Paid = inv.Total
+		default:
+			due := money.FromFloat(inv.AmountDueAt(asOf))
+			row.Paid = inv.Total - due
+			if due > 0 {
+				row.Outstanding = due
+				bucket := &statement.Aging[agingBucketIndex(inv.AgeInDaysAt(asOf))]
+				bucket.Count++
+				bucket.Amounts[inv.Currency] += due
+			}
+		}
+
+		total.Billed += row.Billed
+		total.Paid += row.Paid
+		total.Outstanding += row.Outstanding
+		row.RunningBilled, row.RunningPaid, row.RunningOutstanding = total.Billed, total.Paid, total.Outstanding
+		statement.Rows = append(statement.Rows, row)
+	}
+
+	sort.Slice(statement.Totals, func(i, j int) bool { return statement.Totals[i].Currency < statement.Totals[j].Currency })
+
+	return statement
+}
+
+// outputClientStatementTable prints the statement rows, the totals and the aging of the outstanding balance
+func (a *App) outputClientStatementTable(statement *clientStatement) error {
+	a.logger.Printf("📄 Statement for %s (%s)\n", statement.Client.Name, statement.Client.ID)
+	if statement.From != "" || statement.To != "" {
+		a.logger.Printf("   Period: %s to %s\n", cmp.Or(statement.From, "start"), cmp.Or(statement.To, statement.AsOf))
+	}
+	a.logger.Printf("   Balances as of %s\n\n", statement.AsOf)
+
+	if len(statement.Rows) == 0 {
+		a.logger.Println("No invoices in this period")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "INVOICE\tDATE\tDUE\tSTATUS\tBILLED\tPAID\tOUTSTANDING\tBALANCE"); err != nil {
+		return fmt.Errorf("failed to write table header: %w", err)
+	}
+	for _, r := range statement.Rows {
+		status := r.Status
+		if r.Voided {
+			status += " (excluded)"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Number, r.Date, r.DueDate, status,
+			money.Format(r.Billed.Float64(), r.Currency), money.Format(r.Paid.Float64(), r.Currency),
+			money.Format(r.Outstanding.Float64(), r.Currency), money.Format(r.RunningOutstanding.Float64(), r.Currency)); err != nil {
+			return fmt.Errorf("failed to write table row for %s: %w", r.Number, err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush table: %w", err)
+	}
+
+	a.logger.Println("")
+	for _, t := range statement.Totals {
+		a.logger.Printf("💰 %s  billed %s  paid %s  outstanding %s\n", t.Currency,
+			money.Format(t.Billed.Float64(), t.Currency), money.Format(t.Paid.Float64(), t.Currency),
+			money.Format(t.Outstanding.Float64(), t.Currency))
+	}
+
+	a.logger.Printf("\n⏳ Outstanding by age:\n")
+	for _, bucket := range statement.Aging {
+		a.logger.Printf("   %s: %d%s\n", bucket.Label, bucket.Count, formatCurrencyAmounts(bucket.Amounts))
+	}
+
+	return nil
+}
+
+// outputClientStatementCSV prints one CSV record per invoice with the running totals
+func (a *App) outputClientStatementCSV(statement *clientStatement) error {
+	header := []string{"number", "date", "due_date", "status", "currency", "billed", "paid", "outstanding",
+		"running_billed", "running_paid", "running_outstanding"}
+	records := make([][]string, 0, len(statement.Rows))
+	for _, r := range statement.Rows {
+		records = append(records, []string{r.Number, r.Date, r.DueDate, r.Status, r.Currency, r.Billed.String(), r.Paid.String(),
+			r.Outstanding.String(), r.RunningBilled.String(), r.RunningPaid.String(), r.RunningOutstanding.String()})
+	}
+
+	data, err := csvout.Marshal(header, records)
+	if err != nil {
+		return err
+	}
+	a.logger.Printf("%s", data)
+	return nil
+}
+
+// renderClientStatement renders the statement with the template file at templatePath, or
+// with the built-in statement template when templatePath is empty
+func (a *App) renderClientStatement(ctx context.Context, statement *clientStatement, templatePath string) (string, error) {
+	engine := render.NewHTMLTemplateEngine(&SimpleFileReader{}, &LoggerWrapper{logger: a.logger})
+
+	if templatePath == "" {
+		if err := engine.ParseTemplateString(ctx, statementTemplateName, templates.DefaultStatementTemplate); err != nil {
+			return "", fmt.Errorf("failed to load statement template: %w", err)
+		}
+	} else if err := engine.LoadTemplate(ctx, statementTemplateName, filepath.Clean(templatePath)); err != nil {
+		return "", fmt.Errorf("failed to load template file %s: %w", templatePath, err)
+	}
+
+	tmpl, err := engine.GetTemplate(ctx, statementTemplateName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get statement template: %w", err)
+	}
+	html, err := tmpl.ExecuteToString(ctx, statement)
+	if err != nil {
+		return "", fmt.Errorf("failed to render statement: %w", err)
+	}
+	return html, nil
+}
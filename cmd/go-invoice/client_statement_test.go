@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func TestBuildClientStatement(t *testing.T) {
+	asOf := time.Date(2025, 6, 30, 23, 59, 59, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return asOf.AddDate(0, 0, -days) }
+	client := &models.Client{ID: "CLIENT-1", Name: "Acme"}
+
+	invoices := []*models.Invoice{
+		{Number: "INV-3", Currency: "USD", Status: models.StatusSent, Total: money.FromFloat(500), Date: daysAgo(45),
+			Payments: []models.Payment{{Amount: 200, Date: daysAgo(10)}, {Amount: 100, Date: asOf.AddDate(0, 0, 5)}}},
+		{Number: "INV-1", Currency: "USD", Status: models.StatusPaid, Total: money.FromFloat(900), Date: daysAgo(90)},
+		{Number: "INV-2", Currency: "USD", Status: models.StatusVoided, Total: money.FromFloat(700), Date: daysAgo(60)},
+		{Number: "INV-4", Currency: "EUR", Status: models.StatusOverdue, Total: money.FromFloat(300), Date: daysAgo(100)},
+	}
+	filter := models.InvoiceFilter{DateFrom: daysAgo(120), DateTo: asOf}
+
+	statement := buildClientStatement(client, invoices, filter, asOf, "02/01/2006")
+
+	assert.Equal(t, "02/03/2025", statement.From)
+	assert.Equal(t, "30/06/2025", statement.To)
+	assert.Equal(t, "30/06/2025", statement.AsOf)
+
+	require.Len(t, statement.Rows, 4)
+	numbers := make([]string, len(statement.Rows))
+	for i, r := range statement.Rows {
+		numbers[i] = r.Number
+	}
+	assert.Equal(t, []string{"INV-4", "INV-1", "INV-2", "INV-3"}, numbers, "rows are listed oldest first")
+	assert.Equal(t, "16/05/2025", statement.Rows[3].Date)
+
+	voided := statement.Rows[2]
+	assert.True(t, voided.Voided)
+	assert.Equal(t, money.Amount(0), voided.Billed, "voided invoices are excluded from totals")
+	assert.Equal(t, money.FromFloat(900), voided.RunningBilled)
+
+	partial := statement.Rows[3]
+	assert.Equal(t, money.FromFloat(200), partial.Paid, "only payments by the as-of date count")
+	assert.Equal(t, money.FromFloat(300), partial.Outstanding)
+	assert.Equal(t, money.FromFloat(1400), partial.RunningBilled)
+	assert.Equal(t, money.FromFloat(1100), partial.RunningPaid)
+	assert.Equal(t, money.FromFloat(300), partial.RunningOutstanding)
+
+	require.Len(t, statement.Totals, 2)
+	assert.Equal(t, "EUR", statement.Totals[0].Currency)
+	assert.Equal(t, money.FromFloat(300), statement.Totals[0].Outstanding)
+	assert.Equal(t, money.FromFloat(1400), statement.Totals[1].Billed)
+
+	assert.Equal(t, money.FromFloat(300), statement.Aging[1].Amounts["USD"])
+	assert.Equal(t, money.FromFloat(300), statement.Aging[3].Amounts["EUR"])
+	assert.Equal(t, 0, statement.Aging[0].Count)
+}
+
+func TestRenderClientStatement(t *testing.T) {
+	app := &App{logger: cli.NewLogger(false)}
+	asOf := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	invoices := []*models.Invoice{
+		{Number: "INV-1", Currency: "USD", Status: models.StatusSent, Total: money.FromFloat(1250), Date: asOf.AddDate(0, 0, -5)},
+	}
+	statement := buildClientStatement(&models.Client{ID: "CLIENT-1", Name: "Acme & Sons"}, invoices, models.InvoiceFilter{}, asOf, config.DateLayoutISO)
+
+	html, err := app.renderClientStatement(context.Background(), statement, "")
+	require.NoError(t, err)
+	assert.Contains(t, html, "Statement - Acme &amp; Sons")
+	assert.Contains(t, html, "INV-1")
+	assert.Contains(t, html, "$1,250.00")
+}
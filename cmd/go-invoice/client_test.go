@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// awkwardClientName needs quoting in CSV: it holds a comma and double quotes
+const awkwardClientName = `Smith, Jones & Co "LLC"`
+
+func TestClientsCSV(t *testing.T) {
+	clients := []*models.Client{
+		{ID: "CLIENT-1", Name: awkwardClientName, Email: "billing@smithjones.example", Address: "1 Main St\nSuite 2", Active: true},
+		{ID: "CLIENT-2", Name: "Globex", Email: "ap@globex.example"},
+	}
+
+	data, err := clientsCSV(clients)
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"ID", "Name", "Email", "Phone", "Address", "TaxID", "Active"}, records[0])
+	assert.Equal(t, []string{"CLIENT-1", awkwardClientName, "billing@smithjones.example", "", "1 Main St\nSuite 2", "", "true"}, records[1])
+	assert.Equal(t, "false", records[2][6])
+}
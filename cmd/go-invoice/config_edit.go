@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+)
+
+// ErrInvalidConfigAssignment is returned when config set is not given KEY=VALUE
+var ErrInvalidConfigAssignment = fmt.Errorf("expected KEY=VALUE")
+
+// buildConfigSetCommand creates the config set subcommand
+func (a *App) buildConfigSetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set KEY=VALUE",
+		Short: "Change a single configuration value",
+		Long: `Change one value in the configuration file. The value is checked against the
+key type and the whole configuration is validated before the file is saved.
+Comments and the order of the other keys are kept as they are.
+
+An empty value removes the setting so its default is used.`,
+		Example: `  # Change the default payment terms
+  go-invoice config set PAYMENT_TERMS="Net 15"
+
+  # Turn on automatic backups
+  go-invoice config set AUTO_BACKUP=true`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			name, value, ok := strings.Cut(args[0], "=")
+			if !ok || strings.TrimSpace(name) == "" {
+				return fmt.Errorf("%w, got %q", ErrInvalidConfigAssignment, args[0])
+			}
+
+			configPath, _ := cmd.Flags().GetString("config")
+			return a.setConfigValue(ctx, configPath, name, value)
+		},
+	}
+}
+
+// buildConfigEditCommand creates the config edit subcommand
+func (a *App) buildConfigEditCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Interactively change a configuration value",
+		Long: `Pick a configuration key from a list showing the current values, then enter
+its new value. The change is validated and saved the same way as 'config set'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			configPath, _ := cmd.Flags().GetString("config")
+			return a.runConfigEdit(ctx, configPath)
+		},
+	}
+}
+
+// runConfigEdit prompts for a key and a new value and saves it
+func (a *App) runConfigEdit(ctx context.Context, configPath string) error {
+	configPath = config.ResolvePath(configPath).Path
+	file, err := config.NewWriter(configPath).Read(ctx)
+	if err != nil {
+		return err
+	}
+	values, err := file.Values()
+	if err != nil {
+		return err
+	}
+
+	keys := config.Keys()
+	options := make([]string, len(keys))
+	for i, key := range keys {
+		current := key.MaskValue(values[key.Name])
+		if current == "" {
+			current = "(not set)"
+		}
+		options[i] = fmt.Sprintf("%-32s %s", key.Name, current)
+	}
+
+	prompter := cli.NewPrompter(a.logger)
+	index, _, err := prompter.PromptSelect(ctx, "Which setting do you want to change?", options, 0)
+	if err != nil {
+		return fmt.Errorf("failed to select setting: %w", err)
+	}
+	key := keys[index]
+
+	a.logger.Printf("%s: %s\n", key.Name, key.Description)
+	var value string
+	if key.Sensitive() {
+		value, err = prompter.PromptString(ctx, "New value (leave empty to keep the current value)", "")
+		if err == nil && value == "" {
+			a.logger.Println("No changes made.")
+			return nil
+		}
+	} else {
+		value, err = prompter.PromptString(ctx, "New value", values[key.Name])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get new value: %w", err)
+	}
+
+	if value == values[key.Name] {
+		a.logger.Println("No changes made.")
+		return nil
+	}
+
+	return a.setConfigValue(ctx, configPath, key.Name, value)
+}
+
+// setConfigValue saves one configuration value and reports the change
+func (a *App) setConfigValue(ctx context.Context, configPath, name, value string) error {
+	configPath = config.ResolvePath(configPath).Path
+	if _, err := a.configService.SetValue(ctx, configPath, name, value); err != nil {
+		return fmt.Errorf("failed to update configuration: %w", err)
+	}
+
+	key, _ := config.LookupKey(name)
+	if value == "" {
+		a.logger.Printf("✅ %s cleared (default will be used)\n", key.Name)
+	} else {
+		a.logger.Printf("✅ %s set to %s\n", key.Name, key.MaskValue(value))
+	}
+	a.logger.Printf("📁 Configuration saved to: %s\n", configPath)
+	return nil
+}
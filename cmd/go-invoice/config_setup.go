@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+)
+
+// Config setup errors
+var (
+	ErrSetupFlagsMissing = fmt.Errorf("missing required flags")
+	ErrConfigFileExists  = fmt.Errorf("configuration file already exists")
+)
+
+// configSetupValues holds the settings written to a new configuration file, whether they
+// were answered in the setup wizard or given as flags
+type configSetupValues struct {
+	businessName        string
+	businessEmail       string
+	businessAddress     string
+	businessPhone       string
+	businessWebsite     string
+	paymentTerms        string
+	bankName            string
+	bankAccount         string
+	bankRouting         string
+	paymentInstructions string
+	invoicePrefix       string
+	invoiceStartNumber  int
+	currency            string
+	vatRate             float64
+	invoiceDueDays      int
+	dataDir             string
+	autoBackup          bool
+}
+
+// missingFlags returns the required flags that were not given, in the order they are listed in help
+func (v configSetupValues) missingFlags() []string {
+	var missing []string
+	if strings.TrimSpace(v.businessName) == "" {
+		missing = append(missing, "--business-name")
+	}
+	if strings.TrimSpace(v.businessEmail) == "" {
+		missing = append(missing, "--email")
+	}
+	if strings.TrimSpace(v.businessAddress) == "" {
+		missing = append(missing, "--address")
+	}
+	return missing
+}
+
+// defaultSetupDataDir returns the data directory config setup suggests
+func defaultSetupDataDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".go-invoice")
+}
+
+// runConfigSetupNonInteractive writes the configuration file from flag values without prompting
+func (a *App) runConfigSetupNonInteractive(ctx context.Context, configPath string, values configSetupValues, force bool) error {
+	if missing := values.missingFlags(); len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrSetupFlagsMissing, strings.Join(missing, ", "))
+	}
+
+	if _, err := os.Stat(configPath); err == nil && !force {
+		return fmt.Errorf("%w at %s (use --force to replace it)", ErrConfigFileExists, configPath)
+	}
+
+	if err := a.writeSetupConfig(ctx, configPath, values); err != nil {
+		return err
+	}
+
+	a.logger.Result(configPath)
+	a.logger.Printf("✅ Configuration saved to: %s\n", configPath)
+	return nil
+}
+
+// writeSetupConfig generates the configuration file for values, validates it and writes
+// it to configPath, then makes sure the default invoice template exists
+func (a *App) writeSetupConfig(ctx context.Context, configPath string, values configSetupValues) error {
+	configFile := a.generateConfigFile(values)
+	if _, err := a.configService.ValidateFile(ctx, configFile); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if err := config.NewWriter(configPath).Write(ctx, configFile); err != nil {
+		return err
+	}
+
+	// Create templates directory and default invoice template
+	if err := a.createDefaultTemplate(); err != nil {
+		a.logger.Printf("⚠️  Warning: Could not create default template: %v\n", err)
+		a.logger.Println("   You may need to create templates/invoice.html manually")
+	}
+	return nil
+}
+
+// promptVATRate asks for the VAT rate until it is a decimal between 0 and 1, pointing out
+// answers that look like a percentage
+func (a *App) promptVATRate(ctx context.Context, prompter *cli.Prompter) (float64, error) {
+	for {
+		rate, err := prompter.PromptFloat(ctx, "VAT/Tax Rate (as decimal, e.g., 0.10 for 10%)", 0.0)
+		if err != nil {
+			return 0, err
+		}
+		problem := config.VATRateProblem(rate)
+		if problem == "" {
+			return rate, nil
+		}
+		a.logger.Printf("⚠️  %s\n", problem)
+	}
+}
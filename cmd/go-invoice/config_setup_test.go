@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+)
+
+// newSetupTestApp returns an App able to validate configuration, working in a temporary directory
+func newSetupTestApp(t *testing.T) *App {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	logger := cli.NewLogger(false)
+	return &App{logger: logger, configService: config.NewConfigService(logger, config.NewSimpleValidator(logger))}
+}
+
+func TestConfigSetupNonInteractive(t *testing.T) {
+	app := newSetupTestApp(t)
+	configPath := filepath.Join(t.TempDir(), "config")
+
+	cmd := app.buildConfigSetupCommand()
+	cmd.Flags().String("config", configPath, "")
+	cmd.SetArgs([]string{
+		"--non-interactive", "--business-name", "Acme Consulting", "--email", "billing@acme.example",
+		"--address", `1 Main St\nSpringfield`, "--currency", "EUR", "--vat-rate", "0.2", "--data-dir", "/srv/invoices",
+	})
+	require.NoError(t, cmd.Execute())
+
+	// The file is the one the wizard writes for the same answers
+	written, err := os.ReadFile(configPath) //nolint:gosec // Test file in a temporary directory
+	require.NoError(t, err)
+	expected := app.generateConfigFile(configSetupValues{
+		businessName: "Acme Consulting", businessEmail: "billing@acme.example", businessAddress: `1 Main St\nSpringfield`,
+		paymentTerms: "Net 30", invoicePrefix: "INV", invoiceStartNumber: 1000, currency: "EUR", vatRate: 0.2,
+		invoiceDueDays: 30, dataDir: "/srv/invoices", autoBackup: true,
+	})
+	assert.Equal(t, string(expected.Bytes()), string(written))
+
+	loaded, err := app.configService.LoadConfig(context.Background(), configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Consulting", loaded.Business.Name)
+	assert.Equal(t, "EUR", loaded.Invoice.Currency)
+	assert.InDelta(t, 0.2, loaded.Invoice.VATRate, 0.0001)
+	assert.FileExists(t, filepath.Join("templates", "invoice.html"))
+}
+
+func TestConfigSetupNonInteractiveErrors(t *testing.T) {
+	app := newSetupTestApp(t)
+	ctx := context.Background()
+	configPath := filepath.Join(t.TempDir(), "config")
+	values := configSetupValues{
+		businessName: "Acme", paymentTerms: "Net 30", invoicePrefix: "INV", invoiceStartNumber: 1000,
+		currency: "USD", invoiceDueDays: 30, dataDir: t.TempDir(),
+	}
+
+	// Every missing required flag is named
+	err := app.runConfigSetupNonInteractive(ctx, configPath, values, false)
+	require.ErrorIs(t, err, ErrSetupFlagsMissing)
+	assert.Equal(t, "missing required flags: --email, --address", err.Error())
+	assert.NoFileExists(t, configPath)
+
+	// Values are validated before anything is written
+	values.businessEmail, values.businessAddress = "billing@acme.example", "1 Main St"
+	values.vatRate = 20
+	err = app.runConfigSetupNonInteractive(ctx, configPath, values, false)
+	require.ErrorIs(t, err, config.ErrConfigValidationError)
+	assert.NoFileExists(t, configPath)
+
+	// An existing file is kept unless forced
+	values.vatRate = 0.2
+	require.NoError(t, app.runConfigSetupNonInteractive(ctx, configPath, values, false))
+	values.businessName = "Acme Two"
+	require.ErrorIs(t, app.runConfigSetupNonInteractive(ctx, configPath, values, false), ErrConfigFileExists)
+	require.NoError(t, app.runConfigSetupNonInteractive(ctx, configPath, values, true))
+
+	loaded, err := app.configService.LoadConfig(ctx, configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Two", loaded.Business.Name)
+}
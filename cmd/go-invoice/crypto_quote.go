@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+
+	"github.com/mrz1836/go-invoice/internal/blockchain"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// snapshotCryptoQuote stores the amount due in currency on the invoice at the current exchange
+// rate. Drafts are quoted again on every generation; an invoice that has left draft keeps the
+// quote it was sent with, so later rate moves do not change it. When no rate can be fetched the
+// invoice is generated without a quote.
+func (a *App) snapshotCryptoQuote(ctx context.Context, invoice *models.Invoice, currency string, cfg *config.Config) {
+	crypto := cfg.Business.CryptoPayments
+	enabled := (currency == string(models.PaymentMethodUSDC) && crypto.USDCEnabled) ||
+		(currency == string(models.PaymentMethodBSV) && crypto.BSVEnabled)
+	if !enabled {
+		if invoice.Status == models.StatusDraft {
+			invoice.CryptoQuote = nil
+		}
+		return
+	}
+	if !invoice.NeedsCryptoQuote(currency) {
+		a.logger.Debug("keeping crypto quote of sent invoice", "currency", currency, "as_of", invoice.CryptoQuote.AsOf)
+		return
+	}
+
+	fiatCurrency := invoice.GetCurrency(cfg.Invoice.Currency)
+	rate, err := a.getRateProvider().GetRate(ctx, blockchain.TokenType(currency), fiatCurrency)
+	if err == nil {
+		err = invoice.SetCryptoQuote(currency, fiatCurrency, rate.Rate, rate.AsOf, rate.Provider)
+	}
+	if err != nil {
+		a.logger.Error("failed to quote amount due in crypto", "currency", currency, "error", err)
+		a.logger.Printf("⚠️  Could not fetch the %s exchange rate, the invoice will not show a %s amount\n", currency, currency)
+		invoice.CryptoQuote = nil
+		return
+	}
+
+	a.logger.Printf("💱 Amount due: %s %s (rate %s as of %s)\n", invoice.CryptoQuote.FormatAmount(), currency,
+		invoice.CryptoQuote.FormatRate(), invoice.CryptoQuote.AsOf.Format("2006-01-02 15:04"))
+}
+
+// getRateProvider returns the exchange rate provider, CoinGecko unless one was injected
+func (a *App) getRateProvider() blockchain.RateProvider {
+	if a.rateProvider == nil {
+		a.rateProvider = blockchain.NewCoinGeckoRateProvider()
+	}
+	return a.rateProvider
+}
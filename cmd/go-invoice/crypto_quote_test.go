@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/blockchain"
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+func TestSnapshotCryptoQuote(t *testing.T) {
+	ctx := context.Background()
+	asOf := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &config.Config{
+		Business: config.BusinessConfig{
+			CryptoPayments: config.CryptoPayments{USDCEnabled: true},
+		},
+		Invoice: config.InvoiceConfig{Currency: "USD"},
+	}
+
+	newApp := func() (*App, *blockchain.MockRateProvider) {
+		rates := blockchain.NewMockRateProvider()
+		rates.SetRate(blockchain.TokenTypeUSDC, "USD", 1.0002)
+		rates.SetAsOf(asOf)
+		return &App{logger: cli.NewLogger(false), rateProvider: rates}, rates
+	}
+
+	t.Run("DraftIsQuotedAgain", func(t *testing.T) {
+		app, rates := newApp()
+		invoice := newConfirmTestInvoice()
+		invoice.Status = models.StatusDraft
+
+		app.snapshotCryptoQuote(ctx, invoice, "USDC", cfg)
+		require.NotNil(t, invoice.CryptoQuote)
+		assert.InDelta(t, 1500.3, invoice.CryptoQuote.Amount, 1e-9)
+		assert.Equal(t, asOf, invoice.CryptoQuote.AsOf)
+		assert.Equal(t, "mock", invoice.CryptoQuote.Source)
+
+		rates.SetRate(blockchain.TokenTypeUSDC, "USD", 1)
+		app.snapshotCryptoQuote(ctx, invoice, "USDC", cfg)
+		assert.InDelta(t, 1500.0, invoice.CryptoQuote.Amount, 1e-9)
+		assert.Equal(t, 2, rates.Calls())
+	})
+
+	t.Run("SentInvoiceKeepsQuote", func(t *testing.T) {
+		app, rates := newApp()
+		invoice := newConfirmTestInvoice()
+		invoice.Status = models.StatusDraft
+		app.snapshotCryptoQuote(ctx, invoice, "USDC", cfg)
+		invoice.Status = models.StatusSent
+
+		// A later rate move does not change the invoice that was sent
+		rates.SetRate(blockchain.TokenTypeUSDC, "USD", 2)
+		app.snapshotCryptoQuote(ctx, invoice, "USDC", cfg)
+		assert.InDelta(t, 1500.3, invoice.CryptoQuote.Amount, 1e-9)
+		assert.Equal(t, 1, rates.Calls())
+	})
+
+	t.Run("RateErrorSkipsQuote", func(t *testing.T) {
+		app, rates := newApp()
+		rates.SetError(errors.New("offline"))
+		invoice := newConfirmTestInvoice()
+		invoice.Status = models.StatusDraft
+		invoice.CryptoQuote = &models.CryptoQuote{Currency: "USDC", Rate: 1, Amount: 1500}
+
+		app.snapshotCryptoQuote(ctx, invoice, "USDC", cfg)
+		assert.Nil(t, invoice.CryptoQuote)
+	})
+
+	t.Run("CurrencyNotEnabled", func(t *testing.T) {
+		app, rates := newApp()
+		invoice := newConfirmTestInvoice()
+		invoice.Status = models.StatusDraft
+
+		app.snapshotCryptoQuote(ctx, invoice, "BSV", cfg)
+		assert.Nil(t, invoice.CryptoQuote)
+		assert.Equal(t, 0, rates.Calls())
+	})
+}
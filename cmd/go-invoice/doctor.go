@@ -0,0 +1,281 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+)
+
+// ErrDoctorChecksFailed is returned when any critical doctor check fails
+var ErrDoctorChecksFailed = fmt.Errorf("health checks failed")
+
+// doctorStatus is the outcome of a single doctor check
+type doctorStatus int
+
+const (
+	doctorPass doctorStatus = iota
+	doctorWarn              // Worth fixing, but the CLI still works
+	doctorFail              // Critical: commands will fail until it is fixed
+)
+
+// doctorCheck is the result of one doctor check, with a hint on how to fix it
+type doctorCheck struct {
+	Name   string
+	Status doctorStatus
+	Detail string
+	Hint   string
+}
+
+// buildDoctorCommand creates the doctor command
+func (a *App) buildDoctorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check configuration, storage and templates",
+		Long: `Check that go-invoice is ready to use in one shot:
+
+- the configuration loads and validates
+- the data directory is initialized and writable
+- stored invoices and clients pass validation and the indexes match the data files
+- the default template can be found
+
+Invoice and client counts are reported at the end. Each failing check prints a
+hint on how to fix it, and the command exits non-zero when a critical check fails.`,
+		Example: `  go-invoice doctor
+  go-invoice doctor --config ./client-a.env`,
+		Args: cobra.NoArgs,
+		RunE: a.runDoctor,
+	}
+}
+
+// runDoctor handles the doctor command
+func (a *App) runDoctor(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	configPath, _ := cmd.Flags().GetString("config")
+
+	a.logger.Println("🩺 go-invoice doctor")
+	a.logger.Println("")
+
+	checks := []doctorCheck{}
+	cfg, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		checks = append(checks, doctorCheck{
+			Name:   "Configuration",
+			Status: doctorFail,
+			Detail: err.Error(),
+			Hint:   "Run 'go-invoice config setup' or fix the reported values, then 'go-invoice config validate'",
+		})
+	} else {
+		checks = append(checks, doctorCheck{
+			Name:   "Configuration",
+			Status: doctorPass,
+			Detail: fmt.Sprintf("loaded and valid (%s)", config.ResolvePath(configPath).Path),
+		})
+		checks = append(checks, a.doctorStorageChecks(ctx, cfg)...)
+	}
+
+	failed := 0
+	for _, check := range checks {
+		a.printDoctorCheck(check)
+		if check.Status == doctorFail {
+			failed++
+		}
+	}
+
+	a.logger.Println("")
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d", ErrDoctorChecksFailed, failed, len(checks))
+	}
+	a.logger.Println("✅ All critical checks passed")
+	return nil
+}
+
+// printDoctorCheck prints a check result, followed by its hint when it did not pass
+func (a *App) printDoctorCheck(check doctorCheck) {
+	icon := "✅"
+	switch check.Status {
+	case doctorWarn:
+		icon = "⚠️ "
+	case doctorFail:
+		icon = "❌"
+	case doctorPass:
+	}
+
+	a.logger.Printf("%s %s: %s\n", icon, check.Name, check.Detail)
+	if check.Status != doctorPass && check.Hint != "" {
+		a.logger.Printf("   → %s\n", check.Hint)
+	}
+}
+
+// doctorStorageChecks checks the data directory, stored data, indexes and templates for cfg.
+// Checks that need initialized storage are skipped when it is not initialized.
+func (a *App) doctorStorageChecks(ctx context.Context, cfg *config.Config) []doctorCheck {
+	dataDir := cfg.Storage.DataDir
+
+	var (
+		store     storageBackend
+		jsonStore *jsonStorage.JSONStorage
+	)
+	if cfg.Storage.Backend == config.StorageBackendSQLite {
+		store = a.newSQLiteStorage(cfg.Storage)
+	} else {
+		jsonStore = jsonStorage.NewJSONStorage(dataDir, a.logger)
+		if cfg.Storage.BackupDir != "" {
+			jsonStore.SetBackupDir(cfg.Storage.BackupDir)
+		}
+		jsonStore.SetLockTimeout(cfg.Storage.LockTimeout)
+		store = jsonStore
+	}
+
+	info, err := store.GetStorageInfo(ctx)
+	if err != nil {
+		return []doctorCheck{{
+			Name:   "Data directory",
+			Status: doctorFail,
+			Detail: err.Error(),
+			Hint:   fmt.Sprintf("Check that %s is readable", dataDir),
+		}}
+	}
+	if !info.Initialized {
+		return []doctorCheck{
+			{
+				Name:   "Data directory",
+				Status: doctorFail,
+				Detail: fmt.Sprintf("%s is not initialized", dataDir),
+				Hint:   "Run 'go-invoice init' to create it",
+			},
+			doctorTemplateCheck(cfg),
+		}
+	}
+
+	detail := fmt.Sprintf("%s (%s storage v%s)", info.Path, info.Type, info.Version)
+	if info.LastBackupTime != "" {
+		detail += ", last backup " + info.LastBackupTime
+	}
+
+	checks := []doctorCheck{
+		{Name: "Data directory", Status: doctorPass, Detail: detail},
+		doctorWritableCheck(cfg.Storage.Dir()),
+	}
+
+	if err := store.Validate(ctx); err != nil {
+		hint := "Run 'go-invoice storage verify' for details and 'go-invoice storage verify --repair' to fix what it can"
+		if jsonStore == nil {
+			hint = fmt.Sprintf("Restore %s from a copy of the database file", dataDir)
+		}
+		checks = append(checks, doctorCheck{Name: "Stored data", Status: doctorFail, Detail: err.Error(), Hint: hint})
+	} else {
+		checks = append(checks, doctorCheck{Name: "Stored data", Status: doctorPass, Detail: "invoice and client records are valid"})
+	}
+
+	// SQLite maintains its own indexes
+	if jsonStore != nil {
+		checks = append(checks, doctorIndexCheck(ctx, jsonStore))
+	}
+	checks = append(checks, doctorTemplateCheck(cfg), doctorCountsCheck(ctx, store))
+	return checks
+}
+
+// doctorWritableCheck checks a file can be created in the data directory
+func doctorWritableCheck(dataDir string) doctorCheck {
+	file, err := os.CreateTemp(dataDir, ".doctor-*")
+	if err != nil {
+		return doctorCheck{
+			Name:   "Writable",
+			Status: doctorFail,
+			Detail: err.Error(),
+			Hint:   fmt.Sprintf("Fix the permissions on %s so go-invoice can save invoices", dataDir),
+		}
+	}
+	_ = file.Close()
+	_ = os.Remove(file.Name())
+
+	return doctorCheck{Name: "Writable", Status: doctorPass, Detail: "data directory accepts new files"}
+}
+
+// doctorIndexCheck checks the invoice and client indexes list exactly the data files
+func doctorIndexCheck(ctx context.Context, store *jsonStorage.JSONStorage) doctorCheck {
+	report, err := store.Verify(ctx, jsonStorage.VerifyOptions{})
+	if err != nil {
+		return doctorCheck{
+			Name:   "Indexes",
+			Status: doctorFail,
+			Detail: err.Error(),
+			Hint:   "Run 'go-invoice storage reindex' to rebuild them",
+		}
+	}
+
+	issues := 0
+	for _, issue := range report.Issues {
+		if issue.Resource == "index" {
+			issues++
+		}
+	}
+	if issues > 0 {
+		return doctorCheck{
+			Name:   "Indexes",
+			Status: doctorFail,
+			Detail: fmt.Sprintf("%d index issue(s) found", issues),
+			Hint:   "Run 'go-invoice storage reindex' to rebuild them",
+		}
+	}
+
+	return doctorCheck{Name: "Indexes", Status: doctorPass, Detail: "consistent with the data files"}
+}
+
+// doctorTemplateCheck checks the configured default template can be found. Falling back to the
+// embedded template only warns, since generation still works.
+func doctorTemplateCheck(cfg *config.Config) doctorCheck {
+	name := cmp.Or(cfg.Invoice.DefaultTemplate, "default")
+
+	sources, err := discoverTemplates(cfg.Storage.Dir())
+	if err == nil {
+		var source templateSource
+		if source, err = findTemplateSource(sources, name); err == nil {
+			if source.Path != "" {
+				return doctorCheck{Name: "Templates", Status: doctorPass, Detail: fmt.Sprintf("%q loads from %s", name, source.Path)}
+			}
+			return doctorCheck{
+				Name:   "Templates",
+				Status: doctorWarn,
+				Detail: fmt.Sprintf("%q uses the embedded default template", name),
+				Hint: fmt.Sprintf("Add ./%s or templates in %s to customize invoices",
+					projectTemplatePath, filepath.Join(cfg.Storage.Dir(), "templates")),
+			}
+		}
+	}
+
+	return doctorCheck{
+		Name:   "Templates",
+		Status: doctorFail,
+		Detail: err.Error(),
+		Hint:   "Set INVOICE_TEMPLATE to one of the names from 'go-invoice template list'",
+	}
+}
+
+// doctorCountsCheck reports how many invoices and clients are stored
+func doctorCountsCheck(ctx context.Context, store invoiceClientStore) doctorCheck {
+	invoices, err := store.CountInvoices(ctx, models.InvoiceFilter{})
+	if err != nil {
+		return doctorCheck{Name: "Records", Status: doctorFail, Detail: err.Error(), Hint: "Run 'go-invoice storage reindex'"}
+	}
+	clients, err := store.ListClients(ctx, false, 0, 0)
+	if err != nil {
+		return doctorCheck{Name: "Records", Status: doctorFail, Detail: err.Error(), Hint: "Run 'go-invoice storage verify'"}
+	}
+
+	return doctorCheck{
+		Name:   "Records",
+		Status: doctorPass,
+		Detail: fmt.Sprintf("%d invoice(s), %d client(s)", invoices, clients.TotalCount),
+	}
+}
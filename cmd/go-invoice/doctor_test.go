@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+)
+
+// doctorStatuses maps each check name to its status
+func doctorStatuses(checks []doctorCheck) map[string]doctorStatus {
+	statuses := make(map[string]doctorStatus, len(checks))
+	for _, check := range checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestDoctorStorageChecks(t *testing.T) {
+	app := &App{logger: cli.NewLogger(false)}
+	ctx := context.Background()
+
+	t.Run("NotInitialized", func(t *testing.T) {
+		cfg := &config.Config{Storage: config.StorageConfig{DataDir: t.TempDir()}}
+
+		statuses := doctorStatuses(app.doctorStorageChecks(ctx, cfg))
+		assert.Equal(t, doctorFail, statuses["Data directory"])
+		assert.NotContains(t, statuses, "Indexes")
+	})
+
+	t.Run("Healthy", func(t *testing.T) {
+		dataDir := t.TempDir()
+		require.NoError(t, jsonStorage.NewJSONStorage(dataDir, app.logger).Initialize(ctx))
+		clientService := app.createClientService(config.StorageConfig{DataDir: dataDir})
+		_, err := clientService.CreateClient(ctx, models.CreateClientRequest{Name: "Acme Corp", Email: "ap@acme.test"})
+		require.NoError(t, err)
+
+		cfg := &config.Config{Storage: config.StorageConfig{DataDir: dataDir}}
+		checks := app.doctorStorageChecks(ctx, cfg)
+
+		for _, check := range checks {
+			assert.NotEqual(t, doctorFail, check.Status, "%s: %s", check.Name, check.Detail)
+		}
+		statuses := doctorStatuses(checks)
+		assert.Equal(t, doctorPass, statuses["Writable"])
+		assert.Equal(t, doctorPass, statuses["Indexes"])
+		for _, check := range checks {
+			if check.Name == "Records" {
+				assert.Equal(t, "0 invoice(s), 1 client(s)", check.Detail)
+			}
+		}
+	})
+
+	t.Run("SQLite", func(t *testing.T) {
+		storageConfig := config.StorageConfig{
+			Backend: config.StorageBackendSQLite,
+			DataDir: filepath.Join(t.TempDir(), "invoices.db"),
+		}
+		t.Cleanup(app.closeStores)
+		require.NoError(t, app.createStorage(storageConfig).Initialize(ctx))
+
+		checks := app.doctorStorageChecks(ctx, &config.Config{Storage: storageConfig})
+		for _, check := range checks {
+			assert.NotEqual(t, doctorFail, check.Status, "%s: %s", check.Name, check.Detail)
+		}
+		statuses := doctorStatuses(checks)
+		assert.Equal(t, doctorPass, statuses["Stored data"])
+		assert.NotContains(t, statuses, "Indexes")
+	})
+
+	t.Run("CorruptIndex", func(t *testing.T) {
+		dataDir := t.TempDir()
+		require.NoError(t, jsonStorage.NewJSONStorage(dataDir, app.logger).Initialize(ctx))
+		require.NoError(t, os.WriteFile(filepath.Join(dataDir, "index", "invoices.json"), []byte("{"), 0o600))
+
+		cfg := &config.Config{Storage: config.StorageConfig{DataDir: dataDir}}
+		statuses := doctorStatuses(app.doctorStorageChecks(ctx, cfg))
+		assert.Equal(t, doctorFail, statuses["Indexes"])
+	})
+}
+
+func TestDoctorTemplateCheck(t *testing.T) {
+	dataDir := t.TempDir()
+	cfg := &config.Config{Storage: config.StorageConfig{DataDir: dataDir}}
+
+	assert.NotEqual(t, doctorFail, doctorTemplateCheck(cfg).Status)
+
+	cfg.Invoice.DefaultTemplate = "missing"
+	assert.Equal(t, doctorFail, doctorTemplateCheck(cfg).Status)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "templates"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "templates", "missing.html"), []byte("<html></html>"), 0o600))
+	assert.Equal(t, doctorPass, doctorTemplateCheck(cfg).Status)
+}
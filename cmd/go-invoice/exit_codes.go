@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/qr"
+	"github.com/mrz1836/go-invoice/internal/services"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// Errors classified by exit code, beyond the storage error types
+var (
+	notFoundErrors = []error{ //nolint:gochecknoglobals // Read-only error classification table
+		models.ErrInvoiceNotFound, models.ErrClientNotFound, models.ErrWorkItemNotFound, models.ErrLineItemNotFound,
+		services.ErrInvoiceNumberNotFound, ErrClientNotFound, ErrNoClientsFound, models.ErrTemplateNotFound,
+	}
+	conflictErrors = []error{ //nolint:gochecknoglobals // Read-only error classification table
+		services.ErrConcurrentUpdate, models.ErrInvoiceNumberExists, models.ErrClientEmailExists,
+		models.ErrAttachmentExists, ErrConfigFileExists, ErrMultipleClientsFound, models.ErrInvoiceLocked,
+		models.ErrInvoiceNotLocked, models.ErrCannotChangeLineItemsOnPaid, models.ErrCannotChangeLineItemsOnNonDraft,
+		models.ErrCannotDeletePaidInvoice, models.ErrCannotPayVoidedInvoice, models.ErrCannotVoidPaidInvoice,
+		models.ErrCannotChangeTaxOnIssuedInvoice, models.ErrCannotChangeTaxOnVoidedInvoice,
+		models.ErrInvoiceNotDeleted, models.ErrInvoiceAlreadyDeleted, models.ErrClientInactive,
+		models.ErrCannotAddWorkItemToNonDraft, models.ErrCannotRemoveWorkItemFromNonDraft,
+		models.ErrCannotSendNonDraftInvoice, models.ErrCannotSendEmptyInvoice, models.ErrCannotMarkNonSentAsPaid,
+		models.ErrCannotRemindInvoice, models.ErrClientHasActiveInvoices, models.ErrCannotDeactivateClientWithActiveInvoices,
+		models.ErrMultipleClientsFound, ErrCannotDeletePaidInvoice, ErrCannotUpdateInvoiceStatus,
+	}
+	validationErrors = []error{ //nolint:gochecknoglobals // Read-only error classification table
+		models.ErrValidationFailed, models.ErrInvoiceValidationFailed, models.ErrClientValidationFailed,
+		models.ErrWorkItemValidationFailed, models.ErrLineItemValidationFailed, models.ErrFilterValidationFailed,
+		models.ErrCreateInvoiceRequestInvalid, models.ErrUpdateInvoiceRequestInvalid, models.ErrCreateClientRequestInvalid,
+		models.ErrInvalidCurrency, models.ErrTaxRateOutOfRange, models.ErrInvalidStatus,
+		models.ErrPaymentExceedsBalance, models.ErrPaymentAmountNotPositive, models.ErrPaymentDateRequired,
+		models.ErrInvoiceIDRequired, models.ErrPaymentMethodRequired, models.ErrInvalidPaymentMethod,
+		models.ErrInvalidDiscount, models.ErrDiscountExceedsAmount, models.ErrConflictingDiscounts,
+		models.ErrInvalidAttachmentName, models.ErrInvalidNumberFormat, models.ErrInvalidTermsAnchor,
+		models.ErrInvalidUSDCAddress, models.ErrInvalidBSVAddress, models.ErrInvalidExchangeRate,
+		models.ErrConfirmationRequired, models.ErrInvoiceIDEmpty, models.ErrClientIDEmpty, models.ErrEmailEmpty,
+		models.ErrNameRequired, models.ErrEmailRequired, models.ErrDescriptionRequired,
+		models.ErrClientNameTooLong, models.ErrClientEmailInvalid, models.ErrClientPhoneInvalid,
+		models.ErrClientAddressTooLong, models.ErrClientTaxIDTooLong, models.ErrClientApproverContactsTooLong,
+		models.ErrHoursMustBePositive, models.ErrHoursExceedLimit, models.ErrRateMustBePositive,
+		models.ErrRateExceedsLimit, models.ErrDescriptionTooLong, models.ErrInvalidLineItemType,
+		models.ErrLineItemAmountRequired, models.ErrLineItemQuantityRequired,
+	}
+	configErrors = []error{ //nolint:gochecknoglobals // Read-only error classification table
+		config.ErrConfigValidationError, config.ErrConfigNotFound, config.ErrConfigFileNotFound,
+		ErrStorageNeedsJSON, ErrStorageNeedsSQLite,
+	}
+	usageErrors = []error{ //nolint:gochecknoglobals // Read-only error classification table
+		ErrMergeClientsRequired, ErrInvalidStatementOutput, ErrInvalidConfigAssignment, ErrSetupFlagsMissing,
+		ErrClientIDRequired, ErrInvoiceIDRequired, ErrInvalidDelimiter, ErrInvalidColumnMap, ErrInvalidProgressMode,
+		ErrClientNameRequired, ErrNoUpdatesSpecified, ErrInvalidStatus, ErrHourlyLineItemRequiresFlags, ErrHourlyLineItemRequiresRate,
+		ErrFixedLineItemRequiresAmount, ErrQuantityLineItemRequiresAll, ErrInvalidLineItemType,
+		ErrAttachmentFileRequired, ErrImportFileRequired, ErrListTemplateRequired, ErrInvalidListTemplate,
+		ErrMarkStatusRequired, ErrVoidRequiresYes, ErrPaymentAmountRequired, ErrRemindTargetRequired, ErrSearchQueryRequired,
+		ErrRemindTargetConflict, ErrInvalidGroupBy, ErrTaxRateRequired, ErrIssuedInvoicesNeedForce,
+		ErrInvalidReportOutput, ErrInvalidBackupKeep, ErrInvalidGenerateFormat, ErrQRNetworkRequiresQRFormat,
+		qr.ErrUnsupportedNetwork, models.ErrUnlockReasonRequired, ErrNoLineItemChanges, ErrLineItemFlagMismatch,
+		ErrGenerateAllWithInvoiceID, ErrGenerateAllFlagConflict, models.ErrNoUpdatesSpecified, models.ErrCannotActivateDeactivate,
+	}
+)
+
+// cobraUsagePrefixes start the messages of usage errors cobra creates itself
+var cobraUsagePrefixes = []string{ //nolint:gochecknoglobals // Read-only error classification table
+	"unknown command", "unknown flag", "unknown shorthand flag", "required flag(s)",
+	"if any flags in the group", "at least one of the flags in the group",
+}
+
+// jsonError is printed on stdout in place of the result when a command run with --json fails
+type jsonError struct {
+	Error    string `json:"error"`
+	Kind     string `json:"kind"`      // The error kind, e.g. not_found or conflict
+	ExitCode int    `json:"exit_code"` // The process exit code, see cli.ExitOK and friends
+}
+
+// writeJSONError writes err as a jsonError, classified the same way as the exit code
+func writeJSONError(w io.Writer, err error) error {
+	kind := classifyError(err)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jsonError{Error: err.Error(), Kind: kind.String(), ExitCode: kind.ExitCode()})
+}
+
+// exitCode returns the process exit code for the error a command returned
+func exitCode(err error) int {
+	if err == nil {
+		return cli.ExitOK
+	}
+	return classifyError(err).ExitCode()
+}
+
+// classifyError works out why a command failed. An explicit cli.Error kind wins; otherwise
+// the storage error types and the known sentinel errors decide, and anything else is an
+// internal failure.
+func classifyError(err error) cli.ErrorKind {
+	if kind, ok := cli.KindOf(err); ok {
+		return kind
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return cli.KindInterrupted
+	case isAny(err, configErrors):
+		return cli.KindConfig
+	case storage.IsNotFound(err) || isAny(err, notFoundErrors):
+		return cli.KindNotFound
+	case storage.IsConflict(err) || storage.IsVersionMismatch(err) || isAny(err, conflictErrors):
+		return cli.KindConflict
+	case storage.IsInvalidFilter(err) || isValidationError(err) || isAny(err, validationErrors):
+		return cli.KindValidation
+	case isAny(err, usageErrors) || hasAnyPrefix(err.Error(), cobraUsagePrefixes):
+		return cli.KindUsage
+	default:
+		return cli.KindInternal
+	}
+}
+
+// markUsageErrors makes flag parsing and argument count errors of cmd and every
+// subcommand usage errors
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return cli.WithKind(cli.KindUsage, err)
+	})
+	if args := cmd.Args; args != nil {
+		cmd.Args = func(cmd *cobra.Command, posArgs []string) error {
+			return cli.WithKind(cli.KindUsage, args(cmd, posArgs))
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+// isValidationError reports whether err carries a field validation error
+func isValidationError(err error) bool {
+	var validationErr models.ValidationError
+	return errors.As(err, &validationErr)
+}
+
+// isAny reports whether err matches any of targets
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnyPrefix reports whether s starts with any of prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
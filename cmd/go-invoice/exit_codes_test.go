@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/services"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, cli.ExitOK},
+		{"unexpected", errors.New("disk on fire"), cli.ExitInternal},                            //nolint:err113 // test-only error
+		{"explicit kind", cli.WithKind(cli.KindConflict, errors.New("busy")), cli.ExitConflict}, //nolint:err113 // test-only error
+		{"storage not found", fmt.Errorf("failed to retrieve invoice: %w", storage.NewNotFoundError("invoice", "abc")), cli.ExitNotFound},
+		{"invoice number not found", fmt.Errorf("%w: INV-404", services.ErrInvoiceNumberNotFound), cli.ExitNotFound},
+		{"storage conflict", storage.NewConflictError("invoice", "abc", ""), cli.ExitConflict},
+		{"version mismatch", fmt.Errorf("save: %w", storage.NewVersionMismatchError("invoice", "abc", 2, 3)), cli.ExitConflict},
+		{"concurrent update", services.ErrConcurrentUpdate, cli.ExitConflict},
+		{"delete paid invoice", ErrCannotDeletePaidInvoice, cli.ExitConflict},
+		{"overpayment", fmt.Errorf("failed to record payment: %w", models.ErrPaymentExceedsBalance), cli.ExitValidation},
+		{"field validation", models.ValidationError{Field: "number", Message: "is required"}, cli.ExitValidation},
+		{"invoice validation", fmt.Errorf("%w: number is required", models.ErrInvoiceValidationFailed), cli.ExitValidation},
+		{"invalid filter", storage.NewInvalidFilterError("limit", -1, "must be positive"), cli.ExitValidation},
+		{"config", fmt.Errorf("failed to load configuration: %w", config.ErrConfigNotFound), cli.ExitConfig},
+		{"missing flag", ErrPaymentAmountRequired, cli.ExitUsage},
+		{"cobra unknown command", errors.New(`unknown command "bogus" for "go-invoice"`), cli.ExitUsage}, //nolint:err113 // test-only error
+		{"cobra required flag", errors.New(`required flag(s) "name" not set`), cli.ExitUsage},            //nolint:err113 // test-only error
+		{"interrupted", fmt.Errorf("listing: %w", context.Canceled), cli.ExitInterrupted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exitCode(tt.err))
+		})
+	}
+}
+
+// TestClassifyModelErrors runs every exported error of the models package through
+// classifyError, so that a new sentinel has to be given a kind rather than silently
+// becoming an internal error
+func TestClassifyModelErrors(t *testing.T) {
+	type classified struct {
+		err  error
+		want cli.ErrorKind
+	}
+	sentinels := map[string]classified{
+		"ErrWorkItemNotFound":                         {models.ErrWorkItemNotFound, cli.KindNotFound},
+		"ErrLineItemNotFound":                         {models.ErrLineItemNotFound, cli.KindNotFound},
+		"ErrClientNotFound":                           {models.ErrClientNotFound, cli.KindNotFound},
+		"ErrInvoiceNotFound":                          {models.ErrInvoiceNotFound, cli.KindNotFound},
+		"ErrTemplateNotFound":                         {models.ErrTemplateNotFound, cli.KindNotFound},
+		"ErrCannotVoidPaidInvoice":                    {models.ErrCannotVoidPaidInvoice, cli.KindConflict},
+		"ErrCannotPayVoidedInvoice":                   {models.ErrCannotPayVoidedInvoice, cli.KindConflict},
+		"ErrAttachmentExists":                         {models.ErrAttachmentExists, cli.KindConflict},
+		"ErrClientInactive":                           {models.ErrClientInactive, cli.KindConflict},
+		"ErrCannotDeletePaidInvoice":                  {models.ErrCannotDeletePaidInvoice, cli.KindConflict},
+		"ErrInvoiceAlreadyDeleted":                    {models.ErrInvoiceAlreadyDeleted, cli.KindConflict},
+		"ErrInvoiceNotDeleted":                        {models.ErrInvoiceNotDeleted, cli.KindConflict},
+		"ErrCannotAddWorkItemToNonDraft":              {models.ErrCannotAddWorkItemToNonDraft, cli.KindConflict},
+		"ErrCannotRemoveWorkItemFromNonDraft":         {models.ErrCannotRemoveWorkItemFromNonDraft, cli.KindConflict},
+		"ErrCannotChangeLineItemsOnNonDraft":          {models.ErrCannotChangeLineItemsOnNonDraft, cli.KindConflict},
+		"ErrCannotChangeLineItemsOnPaid":              {models.ErrCannotChangeLineItemsOnPaid, cli.KindConflict},
+		"ErrCannotSendNonDraftInvoice":                {models.ErrCannotSendNonDraftInvoice, cli.KindConflict},
+		"ErrCannotSendEmptyInvoice":                   {models.ErrCannotSendEmptyInvoice, cli.KindConflict},
+		"ErrCannotMarkNonSentAsPaid":                  {models.ErrCannotMarkNonSentAsPaid, cli.KindConflict},
+		"ErrCannotChangeTaxOnIssuedInvoice":           {models.ErrCannotChangeTaxOnIssuedInvoice, cli.KindConflict},
+		"ErrCannotChangeTaxOnVoidedInvoice":           {models.ErrCannotChangeTaxOnVoidedInvoice, cli.KindConflict},
+		"ErrInvoiceNumberExists":                      {models.ErrInvoiceNumberExists, cli.KindConflict},
+		"ErrCannotRemindInvoice":                      {models.ErrCannotRemindInvoice, cli.KindConflict},
+		"ErrInvoiceLocked":                            {models.ErrInvoiceLocked, cli.KindConflict},
+		"ErrInvoiceNotLocked":                         {models.ErrInvoiceNotLocked, cli.KindConflict},
+		"ErrClientHasActiveInvoices":                  {models.ErrClientHasActiveInvoices, cli.KindConflict},
+		"ErrCannotDeactivateClientWithActiveInvoices": {models.ErrCannotDeactivateClientWithActiveInvoices, cli.KindConflict},
+		"ErrClientEmailExists":                        {models.ErrClientEmailExists, cli.KindConflict},
+		"ErrMultipleClientsFound":                     {models.ErrMultipleClientsFound, cli.KindConflict},
+		"ErrInvalidExchangeRate":                      {models.ErrInvalidExchangeRate, cli.KindValidation},
+		"ErrValidationFailed":                         {models.ErrValidationFailed, cli.KindValidation},
+		"ErrClientValidationFailed":                   {models.ErrClientValidationFailed, cli.KindValidation},
+		"ErrClientNameTooLong":                        {models.ErrClientNameTooLong, cli.KindValidation},
+		"ErrClientEmailInvalid":                       {models.ErrClientEmailInvalid, cli.KindValidation},
+		"ErrClientPhoneInvalid":                       {models.ErrClientPhoneInvalid, cli.KindValidation},
+		"ErrClientAddressTooLong":                     {models.ErrClientAddressTooLong, cli.KindValidation},
+		"ErrClientTaxIDTooLong":                       {models.ErrClientTaxIDTooLong, cli.KindValidation},
+		"ErrClientApproverContactsTooLong":            {models.ErrClientApproverContactsTooLong, cli.KindValidation},
+		"ErrCreateClientRequestInvalid":               {models.ErrCreateClientRequestInvalid, cli.KindValidation},
+		"ErrInvoiceValidationFailed":                  {models.ErrInvoiceValidationFailed, cli.KindValidation},
+		"ErrInvalidStatus":                            {models.ErrInvalidStatus, cli.KindValidation},
+		"ErrInvalidTermsAnchor":                       {models.ErrInvalidTermsAnchor, cli.KindValidation},
+		"ErrInvalidNumberFormat":                      {models.ErrInvalidNumberFormat, cli.KindValidation},
+		"ErrInvalidCurrency":                          {models.ErrInvalidCurrency, cli.KindValidation},
+		"ErrPaymentAmountNotPositive":                 {models.ErrPaymentAmountNotPositive, cli.KindValidation},
+		"ErrPaymentExceedsBalance":                    {models.ErrPaymentExceedsBalance, cli.KindValidation},
+		"ErrPaymentDateRequired":                      {models.ErrPaymentDateRequired, cli.KindValidation},
+		"ErrWorkItemValidationFailed":                 {models.ErrWorkItemValidationFailed, cli.KindValidation},
+		"ErrHoursMustBePositive":                      {models.ErrHoursMustBePositive, cli.KindValidation},
+		"ErrHoursExceedLimit":                         {models.ErrHoursExceedLimit, cli.KindValidation},
+		"ErrRateMustBePositive":                       {models.ErrRateMustBePositive, cli.KindValidation},
+		"ErrRateExceedsLimit":                         {models.ErrRateExceedsLimit, cli.KindValidation},
+		"ErrDescriptionTooLong":                       {models.ErrDescriptionTooLong, cli.KindValidation},
+		"ErrLineItemValidationFailed":                 {models.ErrLineItemValidationFailed, cli.KindValidation},
+		"ErrInvalidLineItemType":                      {models.ErrInvalidLineItemType, cli.KindValidation},
+		"ErrLineItemAmountRequired":                   {models.ErrLineItemAmountRequired, cli.KindValidation},
+		"ErrLineItemQuantityRequired":                 {models.ErrLineItemQuantityRequired, cli.KindValidation},
+		"ErrInvalidDiscount":                          {models.ErrInvalidDiscount, cli.KindValidation},
+		"ErrDiscountExceedsAmount":                    {models.ErrDiscountExceedsAmount, cli.KindValidation},
+		"ErrConflictingDiscounts":                     {models.ErrConflictingDiscounts, cli.KindValidation},
+		"ErrInvalidAttachmentName":                    {models.ErrInvalidAttachmentName, cli.KindValidation},
+		"ErrInvalidUSDCAddress":                       {models.ErrInvalidUSDCAddress, cli.KindValidation},
+		"ErrInvalidBSVAddress":                        {models.ErrInvalidBSVAddress, cli.KindValidation},
+		"ErrFilterValidationFailed":                   {models.ErrFilterValidationFailed, cli.KindValidation},
+		"ErrCreateInvoiceRequestInvalid":              {models.ErrCreateInvoiceRequestInvalid, cli.KindValidation},
+		"ErrUpdateInvoiceRequestInvalid":              {models.ErrUpdateInvoiceRequestInvalid, cli.KindValidation},
+		"ErrInvoiceIDEmpty":                           {models.ErrInvoiceIDEmpty, cli.KindValidation},
+		"ErrConfirmationRequired":                     {models.ErrConfirmationRequired, cli.KindValidation},
+		"ErrTaxRateOutOfRange":                        {models.ErrTaxRateOutOfRange, cli.KindValidation},
+		"ErrClientIDEmpty":                            {models.ErrClientIDEmpty, cli.KindValidation},
+		"ErrEmailEmpty":                               {models.ErrEmailEmpty, cli.KindValidation},
+		"ErrInvoiceIDRequired":                        {models.ErrInvoiceIDRequired, cli.KindValidation},
+		"ErrPaymentMethodRequired":                    {models.ErrPaymentMethodRequired, cli.KindValidation},
+		"ErrInvalidPaymentMethod":                     {models.ErrInvalidPaymentMethod, cli.KindValidation},
+		"ErrNameRequired":                             {models.ErrNameRequired, cli.KindValidation},
+		"ErrEmailRequired":                            {models.ErrEmailRequired, cli.KindValidation},
+		"ErrDescriptionRequired":                      {models.ErrDescriptionRequired, cli.KindValidation},
+		"ErrUnlockReasonRequired":                     {models.ErrUnlockReasonRequired, cli.KindUsage},
+		"ErrCannotActivateDeactivate":                 {models.ErrCannotActivateDeactivate, cli.KindUsage},
+		"ErrNoUpdatesSpecified":                       {models.ErrNoUpdatesSpecified, cli.KindUsage},
+		// Only programming errors are internal
+		"ErrClientCannotBeNil":    {models.ErrClientCannotBeNil, cli.KindInternal},
+		"ErrTemplateCannotReload": {models.ErrTemplateCannotReload, cli.KindInternal},
+	}
+
+	declared := modelErrorNames(t)
+	for _, name := range declared {
+		_, ok := sentinels[name]
+		assert.True(t, ok, "models.%s has no expected kind in this test; add it to an error table in exit_codes.go", name)
+	}
+	assert.Len(t, sentinels, len(declared), "the test lists errors the models package no longer declares")
+
+	for name, tt := range sentinels {
+		assert.Equal(t, tt.want, classifyError(fmt.Errorf("command failed: %w", tt.err)), name)
+	}
+}
+
+// modelErrorNames returns the names of the exported Err variables declared by the models package
+func modelErrorNames(t *testing.T) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "internal", "models", "*.go"))
+	require.NoError(t, err)
+
+	var names []string
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, parseErr := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		require.NoError(t, parseErr, file)
+
+		for _, decl := range parsed.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for _, ident := range valueSpec.Names {
+					if ident.IsExported() && strings.HasPrefix(ident.Name, "Err") {
+						names = append(names, ident.Name)
+					}
+				}
+			}
+		}
+	}
+	require.NotEmpty(t, names)
+	return names
+}
+
+func TestWriteJSONError(t *testing.T) {
+	var buf bytes.Buffer
+	err := fmt.Errorf("failed to get invoice: %w", storage.NewNotFoundError("invoice", "abc"))
+	require.NoError(t, writeJSONError(&buf, err))
+
+	var got jsonError
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, jsonError{Error: err.Error(), Kind: "not_found", ExitCode: cli.ExitNotFound}, got)
+}
+
+func TestMarkUsageErrors(t *testing.T) {
+	root := &cobra.Command{Use: "root"}
+	child := &cobra.Command{Use: "child", Args: cobra.ExactArgs(1), RunE: func(*cobra.Command, []string) error { return nil }}
+	child.Flags().Int("count", 0, "")
+	root.AddCommand(child)
+	markUsageErrors(root)
+
+	root.SetArgs([]string{"child"})
+	root.SilenceErrors, root.SilenceUsage = true, true
+	assert.Equal(t, cli.ExitUsage, exitCode(root.Execute()))
+
+	root.SetArgs([]string{"child", "one", "--count", "many"})
+	assert.Equal(t, cli.ExitUsage, exitCode(root.Execute()))
+
+	root.SetArgs([]string{"child", "one"})
+	assert.Equal(t, cli.ExitOK, exitCode(root.Execute()))
+}
@@ -2,28 +2,52 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/spf13/cobra"
 
 	"github.com/mrz1836/go-invoice/internal/cli"
 	"github.com/mrz1836/go-invoice/internal/config"
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
 	"github.com/mrz1836/go-invoice/internal/render"
 	"github.com/mrz1836/go-invoice/internal/services"
-	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
 	"github.com/mrz1836/go-invoice/internal/templates"
 )
 
+// projectTemplatePath is the invoice template created by "init" in the working directory.
+// Other *.html files next to it are available to generate by name.
+var projectTemplatePath = filepath.Join("templates", "invoice.html")
+
+// embeddedTemplateLabel describes the default template when no project template exists
+const embeddedTemplateLabel = "(embedded)"
+
+// ErrUnknownTemplate is returned when a template name matches no available template
+var ErrUnknownTemplate = fmt.Errorf("unknown template")
+
+// templateSource is an invoice template that can be selected by name
+type templateSource struct {
+	Name string
+	Path string // Empty for the embedded default template
+}
+
 // buildGenerateCommand creates the generate command with subcommands
 func (a *App) buildGenerateCommand() *cobra.Command {
+	invoiceCmd := a.buildGenerateInvoiceCommand()
+
 	generateCmd := &cobra.Command{
-		Use:   "generate",
+		Use:   "generate [invoice-id | --all]",
 		Short: "Generate HTML invoices from stored data",
 		Long: `Generate professional HTML invoices using customizable templates.
 
@@ -34,14 +58,48 @@ Features:
 - Multiple output formats
 - Template preview functionality
 
+"generate <invoice-id>" is shorthand for "generate invoice <invoice-id>" and
+accepts the same flags.
+
+Batch Mode:
+"generate --all" renders every invoice matching --status, --client, --from, --to
+and --po (the "invoice list" filters) to <out-dir>/<invoice-number>.html, with
+characters that are unsafe in file names replaced. Setting any of these flags
+without an invoice ID also selects batch mode. Each invoice is reported as it is
+generated, a failure does not stop the rest of the batch, and the command exits
+with an error when any invoice failed. --template, --format and the other
+generation flags apply to every invoice; a batch never prompts for confirmation.
+
 Examples:
+  go-invoice generate INV-001 --output invoice.html
   go-invoice generate invoice INV-001
   go-invoice generate invoice INV-001 --template professional
-  go-invoice generate invoice INV-001 --output /path/to/output.html`,
+  go-invoice generate invoice INV-001 --template ./templates/brand.html
+  go-invoice generate invoice INV-001 --output /path/to/output.html
+  go-invoice generate --all --status sent --from 2025-03-01 --to 2025-03-31 --out-dir ./invoices
+  go-invoice generate --all --client "Acme Corp" --template professional --format qr`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if isGenerateBatch(cmd) {
+				if len(args) > 0 {
+					return ErrGenerateAllWithInvoiceID
+				}
+				return a.runGenerateAll(cmd)
+			}
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			return invoiceCmd.RunE(cmd, args)
+		},
 	}
 
+	// Share the invoice flags so "generate <id>" behaves like "generate invoice <id>"
+	generateCmd.Flags().AddFlagSet(invoiceCmd.Flags())
+	generateCmd.MarkFlagsMutuallyExclusive("detailed", "summarized")
+	addGenerateBatchFlags(generateCmd)
+
 	// Add generate subcommands
-	generateCmd.AddCommand(a.buildGenerateInvoiceCommand())
+	generateCmd.AddCommand(invoiceCmd)
 	generateCmd.AddCommand(a.buildGeneratePreviewCommand())
 	generateCmd.AddCommand(a.buildGenerateTemplateListCommand())
 
@@ -50,15 +108,6 @@ Examples:
 
 // buildGenerateInvoiceCommand creates the invoice generation command
 func (a *App) buildGenerateInvoiceCommand() *cobra.Command {
-	var (
-		templateName string
-		outputPath   string
-		openBrowser  bool
-		validate     bool
-		currency     string
-		taxRate      float64
-	)
-
 	cmd := &cobra.Command{
 		Use:   "invoice <invoice-id>",
 		Short: "Generate HTML invoice for a specific invoice ID",
@@ -71,15 +120,48 @@ The generated HTML is optimized for printing and includes:
 - Accurate financial calculations
 - Print-friendly formatting
 
-Template Options:
-- default: Clean, professional template (default)
-- professional: Professional template with additional styling
-- minimal: Simple, minimal template
+Template Selection:
+The template is chosen in this order: the --template flag, the template set on
+the invoice, the template set on the client, then INVOICE_TEMPLATE from the
+config (default: "default"). Named templates are loaded from
+templates/<name>.html in the current directory and <data-dir>/templates/<name>.html
+(see "template list"), and --template also accepts a path to a template file. The "default" template is templates/invoice.html in the current
+directory when present, otherwise the embedded default. The template used is
+shown in the output.
+
+Confirmation:
+Use --confirm (or INVOICE_CONFIRM_BEFORE_GENERATE=true in the config) to review
+a summary of the client, total, line items, due date and payment methods before
+the invoice is generated. Non-interactive runs must also pass --yes.
+
+Render Style:
+Use --summarized to group hourly items that share a rate into rate bands
+(hours and amounts summed per rate) for a compact invoice, or --detailed to
+list every item. The default comes from INVOICE_RENDER_STYLE. Grouping only
+affects the generated document; stored items and totals are unchanged.
+
+Document Archive:
+With STORE_GENERATED_DOCUMENTS=true every generated document is also saved to
+<data-dir>/documents/<invoice-id>/ and recorded on the invoice with its path,
+format, timestamp and checksum ("invoice show" lists them). Regenerating an
+invoice that has already been sent warns before the previous output is replaced.
+
+Payment QR Codes:
+Use --format qr to embed a QR code under each configured USDC and BSV address.
+The code encodes a payment URI with the address and the amount due, so a wallet
+can pay the invoice in one scan. Use --qr-network usdc or --qr-network bsv to
+embed only one of them. Networks without an address are left out. The amount is
+taken from the crypto quote on the invoice (see "client update
+--crypto-fee-currency"); without one, USDC is quoted 1:1 for USD invoices and
+other codes carry only the address.
 
 Examples:
   go-invoice generate invoice INV-001
   go-invoice generate invoice INV-001 --template professional
-  go-invoice generate invoice INV-001 --output invoice.html --open`,
+  go-invoice generate invoice INV-001 --output invoice.html --open
+  go-invoice generate invoice INV-001 --confirm
+  go-invoice generate invoice INV-001 --summarized
+  go-invoice generate invoice INV-001 --format qr --qr-network bsv`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, cancel := context.WithCancel(cmd.Context())
@@ -88,27 +170,50 @@ Examples:
 			invoiceID := args[0]
 			configPath, _ := cmd.Flags().GetString("config")
 
-			return a.executeGenerateInvoice(ctx, invoiceID, configPath, GenerateInvoiceOptions{
-				TemplateName: templateName,
-				OutputPath:   outputPath,
-				OpenBrowser:  openBrowser,
-				Validate:     validate,
-				Currency:     currency,
-				TaxRate:      taxRate,
-			})
+			return a.executeGenerateInvoice(ctx, invoiceID, configPath, generateInvoiceOptions(cmd))
 		},
 	}
 
-	cmd.Flags().StringVar(&templateName, "template", "default", "Template to use for generation")
-	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output file path (default: <invoice-number>.html)")
-	cmd.Flags().BoolVar(&openBrowser, "open", false, "Open generated invoice in default browser")
-	cmd.Flags().BoolVar(&validate, "validate", true, "Validate calculations before generation")
-	cmd.Flags().StringVar(&currency, "currency", "", "Override currency for display (default from config)")
-	cmd.Flags().Float64Var(&taxRate, "tax-rate", -1, "Override tax rate (-1 to use invoice rate)")
+	cmd.Flags().String("template", "", "Template name or template file path (default: invoice, client or config template)")
+	cmd.Flags().StringP("output", "o", "", "Output file path (default: <data-dir>/generated/<invoice-number>.html)")
+	cmd.Flags().Bool("open", false, "Open generated invoice in default browser")
+	cmd.Flags().Bool("validate", true, "Validate calculations before generation")
+	cmd.Flags().String("currency", "", "Override currency for display (default from config)")
+	cmd.Flags().Float64("tax-rate", -1, "Override tax rate (-1 to use invoice rate)")
+	cmd.Flags().Bool("confirm", false, "Review an invoice summary and confirm before generating (default from config)")
+	cmd.Flags().BoolP("yes", "y", false, "Automatically answer yes to the confirmation prompt")
+	cmd.Flags().Bool("detailed", false, "List every line item individually (default from config)")
+	cmd.Flags().Bool("summarized", false, "Group hourly items by rate into rate bands (default from config)")
+	cmd.Flags().String("format", generateFormatHTML, "Output format: html, or qr to embed payment QR codes")
+	cmd.Flags().String("qr-network", "", "Only embed the QR code for this network with --format qr: usdc or bsv (default: every configured address)")
+	cmd.MarkFlagsMutuallyExclusive("detailed", "summarized")
 
 	return cmd
 }
 
+// generateInvoiceOptions reads the generate invoice flags. It works on both "generate"
+// and "generate invoice", which share the flags.
+func generateInvoiceOptions(cmd *cobra.Command) GenerateInvoiceOptions {
+	flags := cmd.Flags()
+	options := GenerateInvoiceOptions{ConfirmSet: flags.Changed("confirm")}
+	options.TemplateName, _ = flags.GetString("template")
+	options.OutputPath, _ = flags.GetString("output")
+	options.OpenBrowser, _ = flags.GetBool("open")
+	options.Validate, _ = flags.GetBool("validate")
+	options.Currency, _ = flags.GetString("currency")
+	options.TaxRate, _ = flags.GetFloat64("tax-rate")
+	options.Confirm, _ = flags.GetBool("confirm")
+	options.AssumeYes, _ = flags.GetBool("yes")
+	options.Format, _ = flags.GetString("format")
+	options.QRNetwork, _ = flags.GetString("qr-network")
+
+	detailed, _ := flags.GetBool("detailed")
+	summarized, _ := flags.GetBool("summarized")
+	options.RenderStyle = resolveRenderStyleFlag(detailed, summarized)
+
+	return options
+}
+
 // buildGeneratePreviewCommand creates the template preview command
 func (a *App) buildGeneratePreviewCommand() *cobra.Command {
 	var (
@@ -185,6 +290,10 @@ Example:
 func (a *App) executeGenerateInvoice(ctx context.Context, invoiceID, configPath string, options GenerateInvoiceOptions) error {
 	a.logger.Info("executing generate invoice", "invoice_id", invoiceID, "template", options.TemplateName)
 
+	if err := validateGenerateFormat(options.Format, options.QRNetwork); err != nil {
+		return err
+	}
+
 	start := time.Now()
 
 	// Setup services and retrieve invoice
@@ -195,13 +304,53 @@ func (a *App) executeGenerateInvoice(ctx context.Context, invoiceID, configPath
 
 	a.logger.Printf("📄 Generating invoice: %s (%s)\n", invoice.Number, invoice.Client.Name)
 
+	html, confirmed, err := a.prepareInvoiceDocument(ctx, config, renderService, invoiceService, invoice, &options)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		a.logger.Println("❌ Generation canceled")
+		return nil
+	}
+
+	// Warn before replacing a document the client may already have received
+	a.warnIfOverwritingIssuedDocument(invoice, a.resolveOutputPath(options.OutputPath, invoice.Number, config.Storage.Dir()), html)
+
+	// Write output file
+	outputPath, err := a.writeGeneratedInvoice(html, options.OutputPath, invoice.Number, config.Storage.Dir())
+	if err != nil {
+		return err
+	}
+
+	// Display results and handle browser opening
+	a.displayGenerationResults(outputPath, html, options, time.Since(start))
+
+	// Keep a versioned copy and record it on the invoice for an audit trail
+	if config.Storage.StoreDocuments {
+		doc, storeErr := a.storeGeneratedDocument(ctx, invoiceService, invoice, html, options.TemplateName, config.Storage.Dir())
+		if storeErr != nil {
+			a.logger.Printf("⚠️  Could not archive generated document: %v\n", storeErr)
+		} else {
+			a.logger.Printf("🗄️  Archived: %s\n", doc.Path)
+			a.logger.Printf("   Checksum: %s\n", doc.Checksum)
+		}
+	}
+
+	return nil
+}
+
+// prepareInvoiceDocument brings the invoice up to date with the latest client settings,
+// crypto fee and quote, asks for confirmation when enabled, saves the invoice and renders
+// the document. It resolves options.TemplateName and options.TemplateSource, and returns
+// false without an error when the user declines to generate.
+func (a *App) prepareInvoiceDocument(ctx context.Context, config *config.Config, renderService render.InvoiceRenderer, invoiceService *services.InvoiceService, invoice *models.Invoice, options *GenerateInvoiceOptions) (string, bool, error) {
 	// Validate calculations if requested
-	if validateErr := a.validateCalculationsIfRequested(ctx, options, invoice, config); validateErr != nil {
-		return validateErr
+	if validateErr := a.validateCalculationsIfRequested(ctx, *options, invoice, config); validateErr != nil {
+		return "", false, validateErr
 	}
 
 	// Fetch fresh client data first to get latest crypto fee settings
-	clientService := a.createClientService(config.Storage.DataDir)
+	clientService := a.createClientService(config.Storage)
 	freshClient, err := clientService.GetClient(ctx, invoice.Client.ID)
 	if err != nil {
 		a.logger.Error("failed to get fresh client data", "client_id", invoice.Client.ID, "error", err)
@@ -213,6 +362,16 @@ func (a *App) executeGenerateInvoice(ctx context.Context, invoiceID, configPath
 		a.logger.Debug("using fresh client data", "client_id", freshClient.ID, "crypto_fee_enabled", freshClient.CryptoFeeEnabled)
 	}
 
+	// Pick the template now that the latest client settings are known
+	options.TemplateName, options.TemplateSource = resolveTemplateName(options.TemplateName, invoice, config)
+	if isTemplateFilePath(options.TemplateName) {
+		if loadErr := a.loadTemplateFile(ctx, renderService, options.TemplateName); loadErr != nil {
+			return "", false, loadErr
+		}
+	} else if checkErr := checkTemplateName(options.TemplateName, config.Storage.Dir()); checkErr != nil {
+		return "", false, checkErr
+	}
+
 	// Apply crypto service fee if enabled for this client (using fresh client data)
 	cryptoEnabled := config.Business.CryptoPayments.USDCEnabled || config.Business.CryptoPayments.BSVEnabled
 	feeEnabled := freshClient.CryptoFeeEnabled
@@ -220,7 +379,19 @@ func (a *App) executeGenerateInvoice(ctx context.Context, invoiceID, configPath
 
 	// Apply crypto fee if client has it enabled
 	if cryptoErr := invoice.SetCryptoFee(ctx, cryptoEnabled, feeEnabled, feeAmount); cryptoErr != nil {
-		return fmt.Errorf("failed to set crypto fee: %w", cryptoErr)
+		if !errors.Is(cryptoErr, models.ErrInvoiceLocked) {
+			return "", false, fmt.Errorf("failed to set crypto fee: %w", cryptoErr)
+		}
+		a.logger.Printf("⚠️  %v; keeping the fee the invoice was sent with\n", cryptoErr)
+	}
+
+	// Quote the amount due in the client's crypto currency at today's rate
+	a.snapshotCryptoQuote(ctx, invoice, freshClient.CryptoFeeCurrency, config)
+
+	// Give the user a last look before anything is saved or written
+	confirmed, err := a.confirmInvoiceGeneration(ctx, invoice, config, *options)
+	if err != nil || !confirmed {
+		return "", false, err
 	}
 
 	// Save the updated invoice with crypto fee back to storage
@@ -228,28 +399,86 @@ func (a *App) executeGenerateInvoice(ctx context.Context, invoiceID, configPath
 		a.logger.Error("failed to save invoice with crypto fee", "error", updateErr)
 		// Continue anyway - we can still generate the HTML even if save fails
 	} else {
-		a.logger.Debug("invoice updated with crypto fee", "crypto_fee", invoice.CryptoFee, "new_total", invoice.Total)
+		a.logger.Debug("invoice updated with crypto fee", "crypto_fee", invoice.CryptoFee, "new_total", invoice.Total, "crypto_quote", invoice.CryptoQuote != nil)
 	}
 
 	// Create data structure for template (client is already fresh in invoice now)
-	invoiceData := a.createInvoiceData(invoice, config)
+	invoiceData := render.BuildTemplateData(config, invoice, nil)
+	applyRenderStyle(invoiceData, options.RenderStyle, config.Invoice.RenderStyle)
+	a.addBusinessLogo(invoiceData, config)
+	if options.Format == generateFormatQR {
+		if qrErr := a.addPaymentQRCodes(invoiceData, invoice, config, options.QRNetwork); qrErr != nil {
+			return "", false, qrErr
+		}
+	}
 
 	// Generate HTML content using template engine directly to support data
 	html, err := a.renderInvoice(ctx, renderService, invoiceData, options.TemplateName)
 	if err != nil {
-		return fmt.Errorf("failed to render invoice: %w", err)
+		return "", false, fmt.Errorf("failed to render invoice: %w", err)
 	}
 
-	// Write output file
-	outputPath, err := a.writeGeneratedInvoice(html, options.OutputPath, invoice.Number, config.Storage.DataDir)
+	return html, true, nil
+}
+
+// storeGeneratedDocument writes a versioned copy of the document to documents/<invoice-id>/
+// and records it in the invoice's generated document history
+func (a *App) storeGeneratedDocument(ctx context.Context, invoiceService *services.InvoiceService, invoice *models.Invoice, html, templateName, dataDir string) (*models.GeneratedDoc, error) {
+	dir := filepath.Join(dataDir, "documents", string(invoice.ID))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create documents directory: %w", err)
+	}
+
+	doc := models.NewGeneratedDoc("", models.DocumentFormatHTML, []byte(html), invoice.Status, templateName)
+
+	// Skip identical regenerations so the history only grows when something changed
+	if count := len(invoice.GeneratedDocuments); count > 0 {
+		latest := invoice.GeneratedDocuments[count-1]
+		if latest.Checksum == doc.Checksum && latest.Status == doc.Status {
+			return &latest, nil
+		}
+	}
+
+	filename := fmt.Sprintf("%s-%s-%s.%s", sanitizeInvoiceNumber(invoice.Number),
+		doc.GeneratedAt.Format("20060102-150405"), doc.Checksum[:8], doc.Format)
+	doc.Path = filepath.Join(dir, filename)
+
+	if err := os.WriteFile(doc.Path, []byte(html), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write document: %w", err)
+	}
+
+	invoice.RecordGeneratedDocument(doc)
+	if err := invoiceService.UpdateInvoiceDirectly(ctx, invoice); err != nil {
+		return nil, fmt.Errorf("failed to record document on invoice: %w", err)
+	}
+
+	return &doc, nil
+}
+
+// warnIfOverwritingIssuedDocument warns when writing html to outputPath would replace a
+// different document that was generated after the invoice was issued
+func (a *App) warnIfOverwritingIssuedDocument(invoice *models.Invoice, outputPath, html string) {
+	existing, err := os.ReadFile(filepath.Clean(outputPath))
 	if err != nil {
-		return err
+		return
 	}
 
-	// Display results and handle browser opening
-	a.displayGenerationResults(outputPath, html, options, time.Since(start))
+	existingChecksum := models.DocumentChecksum(existing)
+	if existingChecksum == models.DocumentChecksum([]byte(html)) {
+		return
+	}
 
-	return nil
+	previous := invoice.FindGeneratedDocument(existingChecksum)
+	switch {
+	case previous != nil && previous.WasIssued():
+		a.logger.Printf("⚠️  Regenerating will overwrite %s\n", outputPath)
+		a.logger.Printf("   That version was generated %s while the invoice was %s and may have been sent to the client.\n",
+			previous.GeneratedAt.Format("2006-01-02 15:04"), previous.Status)
+		a.logger.Printf("   A copy is archived at %s\n", previous.Path)
+	case invoice.Status != models.StatusDraft:
+		a.logger.Printf("⚠️  Regenerating will overwrite %s for an invoice that is already %s.\n", outputPath, invoice.Status)
+		a.logger.Println("   The client may hold the previous version. Set STORE_GENERATED_DOCUMENTS=true to keep every version.")
+	}
 }
 
 // setupGenerateServices sets up configuration and services for invoice generation
@@ -267,16 +496,12 @@ func (a *App) setupGenerateServices(ctx context.Context, configPath, invoiceID s
 	}
 
 	// Create invoice service and get invoice
-	invoiceService := a.createInvoiceService(config.Storage.DataDir)
+	invoiceService := a.createInvoiceService(config)
 
-	// Try to get invoice by ID first, then by number if that fails
-	invoice, err := invoiceService.GetInvoice(ctx, models.InvoiceID(invoiceID))
+	// Try to get invoice by ID first, then by number; invoices in the trash are skipped
+	invoice, err := invoiceService.FindInvoice(ctx, invoiceID, false)
 	if err != nil {
-		// If getting by ID failed, try getting by invoice number
-		invoice, err = invoiceService.GetInvoiceByNumber(ctx, invoiceID)
-		if err != nil {
-			return nil, nil, nil, nil, fmt.Errorf("%w: '%s'", models.ErrInvoiceNotFound, invoiceID)
-		}
+		return nil, nil, nil, nil, err
 	}
 
 	return config, renderService, invoice, invoiceService, nil
@@ -311,20 +536,120 @@ func (a *App) buildCalculationOptions(options GenerateInvoiceOptions, invoice *m
 
 	// Apply defaults if values not specified
 	if options.TaxRate < 0 {
-		calcOptions.TaxRate = invoice.TaxRate
+		calcOptions.TaxRate = invoice.EffectiveTaxRate()
 	}
 	if options.Currency == "" {
-		calcOptions.Currency = config.Invoice.Currency
+		calcOptions.Currency = invoice.GetCurrency(config.Invoice.Currency)
 	}
 
 	return calcOptions
 }
 
+// confirmInvoiceGeneration displays a review summary and asks the user to confirm generation.
+// It returns true without prompting when confirmation is disabled or --yes was provided.
+func (a *App) confirmInvoiceGeneration(ctx context.Context, invoice *models.Invoice, cfg *config.Config, options GenerateInvoiceOptions) (bool, error) {
+	confirm := cfg.Invoice.ConfirmBeforeGenerate
+	if options.ConfirmSet {
+		confirm = options.Confirm
+	}
+	if !confirm {
+		return true, nil
+	}
+
+	a.displayConfirmationSummary(invoice, cfg)
+
+	if options.AssumeYes {
+		a.logger.Println("✅ Confirmed via --yes")
+		return true, nil
+	}
+
+	if !isInteractiveInput() {
+		a.logger.Println("Non-interactive environment detected. Use --yes to confirm without prompting.")
+		return false, fmt.Errorf("%w: use --yes flag to generate without interactive confirmation", models.ErrConfirmationRequired)
+	}
+
+	prompter := cli.NewPrompter(a.logger)
+	confirmed, err := prompter.PromptConfirm(ctx, "Please review the invoice summary above.")
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, fmt.Errorf("%w: use --yes flag to generate without interactive confirmation", models.ErrConfirmationRequired)
+		}
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	return confirmed, nil
+}
+
+// displayConfirmationSummary prints the key invoice details a user should check before generating
+func (a *App) displayConfirmationSummary(invoice *models.Invoice, cfg *config.Config) {
+	currency := invoice.GetCurrency(cfg.Invoice.Currency)
+
+	a.logger.Println("")
+	a.logger.Println("🔎 Invoice Review")
+	a.logger.Println("=================")
+	a.logger.Printf("   Invoice:    %s\n", invoice.Number)
+	if invoice.Client.Email != "" {
+		a.logger.Printf("   Client:     %s <%s>\n", invoice.Client.Name, invoice.Client.Email)
+	} else {
+		a.logger.Printf("   Client:     %s\n", invoice.Client.Name)
+	}
+	a.logger.Printf("   Line Items: %d\n", len(invoice.GetAllItems()))
+	a.logger.Printf("   Due Date:   %s\n", cfg.Invoice.FormatDate(invoice.DueDate))
+	a.logger.Printf("   Total:      %s\n", money.Format(invoice.Total.Float64(), currency))
+
+	methods := paymentMethodsSummary(invoice, cfg)
+	if len(methods) == 0 {
+		a.logger.Println("   Payment:    ⚠️  no payment methods configured")
+	} else {
+		a.logger.Printf("   Payment:    %s\n", strings.Join(methods, ", "))
+	}
+	a.logger.Println("")
+}
+
+// paymentMethodsSummary lists the payment methods that will appear on the invoice
+func paymentMethodsSummary(invoice *models.Invoice, cfg *config.Config) []string {
+	var methods []string
+
+	bank := cfg.Business.BankDetails
+	if bank.ACHEnabled {
+		methods = append(methods, "ACH")
+	}
+	if bank.IBAN != "" || bank.SWIFT != "" {
+		methods = append(methods, "Wire")
+	}
+
+	crypto := cfg.Business.CryptoPayments
+	if crypto.USDCEnabled && invoice.GetUSDCAddress(crypto.USDCAddress) != "" {
+		methods = append(methods, "USDC")
+	}
+	if crypto.BSVEnabled && invoice.GetBSVAddress(crypto.BSVAddress) != "" {
+		methods = append(methods, "BSV")
+	}
+
+	return methods
+}
+
+// isInteractiveInput reports whether stdin is attached to a terminal
+func isInteractiveInput() bool {
+	fileInfo, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return (fileInfo.Mode() & os.ModeCharDevice) != 0
+}
+
+// isTerminalOutput reports whether stdout is attached to a terminal
+func isTerminalOutput() bool {
+	fileInfo, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return (fileInfo.Mode() & os.ModeCharDevice) != 0
+}
+
 // writeGeneratedInvoice writes the generated HTML to a file
-func (a *App) writeGeneratedInvoice(html, _, invoiceNumber, dataDir string) (string, error) {
-	// Always use the default generated directory - ignore inputPath for consistency
-	// Invoices should always go to ~/.go-invoice/generated/ for predictable location
-	outputPath := a.createSafeFilename(invoiceNumber, dataDir)
+func (a *App) writeGeneratedInvoice(html, requestedPath, invoiceNumber, dataDir string) (string, error) {
+	outputPath := a.resolveOutputPath(requestedPath, invoiceNumber, dataDir)
 
 	// Ensure output directory exists
 	if err := a.ensureOutputDirectory(outputPath); err != nil {
@@ -339,15 +664,51 @@ func (a *App) writeGeneratedInvoice(html, _, invoiceNumber, dataDir string) (str
 	return outputPath, nil
 }
 
+// resolveOutputPath returns the requested output path, or the invoice's file in the data
+// directory's generated folder when no path was requested
+func (a *App) resolveOutputPath(requestedPath, invoiceNumber, dataDir string) string {
+	if requestedPath != "" {
+		return filepath.Clean(requestedPath)
+	}
+	return a.createSafeFilename(invoiceNumber, dataDir)
+}
+
 // createSafeFilename creates a safe filename from invoice number in the data directory's generated subdirectory
 func (a *App) createSafeFilename(invoiceNumber, dataDir string) string {
-	safeNumber := strings.ReplaceAll(invoiceNumber, "/", "-")
-	safeNumber = strings.ReplaceAll(safeNumber, "\\", "-")
-	filename := fmt.Sprintf("%s.html", safeNumber)
+	filename := fmt.Sprintf("%s.html", sanitizeInvoiceNumber(invoiceNumber))
 	generatedDir := filepath.Join(dataDir, "generated")
 	return filepath.Join(generatedDir, filename)
 }
 
+// sanitizeInvoiceNumber makes an invoice number safe to use as a file name on any platform.
+// Letters, digits, '-', '_' and '.' are kept and everything else, including path separators
+// and spaces, becomes '-'. Leading dots are dropped so the name cannot be hidden or refer to
+// a parent directory, and names Windows reserves for devices are prefixed.
+func sanitizeInvoiceNumber(invoiceNumber string) string {
+	safeNumber := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, strings.TrimSpace(invoiceNumber))
+	safeNumber = strings.TrimRight(strings.TrimLeft(safeNumber, "."), ".")
+
+	if safeNumber == "" {
+		return "invoice"
+	}
+	if windowsReservedNames[strings.ToUpper(strings.SplitN(safeNumber, ".", 2)[0])] {
+		return "invoice-" + safeNumber
+	}
+	return safeNumber
+}
+
+// windowsReservedNames are device names Windows will not use as file names, with any extension
+var windowsReservedNames = map[string]bool{ //nolint:gochecknoglobals // Read-only lookup table
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
 // ensureOutputDirectory ensures the output directory exists
 func (a *App) ensureOutputDirectory(outputPath string) error {
 	if dir := filepath.Dir(outputPath); dir != "." {
@@ -360,10 +721,14 @@ func (a *App) ensureOutputDirectory(outputPath string) error {
 
 // displayGenerationResults displays the generation results and optionally opens browser
 func (a *App) displayGenerationResults(outputPath, html string, options GenerateInvoiceOptions, duration time.Duration) {
+	a.logger.Result(outputPath)
 	a.logger.Printf("✅ Invoice generated successfully!\n")
 	a.logger.Printf("   Output: %s\n", outputPath)
 	a.logger.Printf("   Size: %d bytes\n", len(html))
-	a.logger.Printf("   Template: %s\n", options.TemplateName)
+	a.logger.Printf("   Template: %s (from %s)\n", options.TemplateName, options.TemplateSource)
+	if options.RenderStyle != "" {
+		a.logger.Printf("   Style: %s\n", options.RenderStyle)
+	}
 	a.logger.Printf("   Generation time: %v\n", duration)
 
 	// Open in browser if requested
@@ -399,9 +764,9 @@ func (a *App) executeGeneratePreview(ctx context.Context, invoiceID, configPath
 		a.logger.Println("📄 Generating preview with sample data")
 	} else {
 		// Create invoice service and get real invoice
-		invoiceService := a.createInvoiceService(config.Storage.DataDir)
+		invoiceService := a.createInvoiceService(config)
 
-		invoice, err = invoiceService.GetInvoice(ctx, models.InvoiceID(invoiceID))
+		invoice, err = invoiceService.FindInvoice(ctx, invoiceID, false)
 		if err != nil {
 			return fmt.Errorf("failed to retrieve invoice: %w", err)
 		}
@@ -420,7 +785,7 @@ func (a *App) executeGeneratePreview(ctx context.Context, invoiceID, configPath
 	a.logger.Printf("   Template: %s\n", options.TemplateName)
 	a.logger.Printf("   Size: %d bytes\n", len(html))
 	a.logger.Printf("   Work Items: %d\n", len(invoice.WorkItems))
-	a.logger.Printf("   Total: %.2f %s\n", invoice.Total, config.Invoice.Currency)
+	a.logger.Printf("   Total: %s\n", money.Format(invoice.Total.Float64(), invoice.GetCurrency(config.Invoice.Currency)))
 
 	// Show first few lines of HTML
 	lines := strings.Split(html, "\n")
@@ -457,7 +822,7 @@ func (a *App) executeGenerateTemplateList(ctx context.Context, configPath string
 	}
 
 	// Get available templates
-	templates, err := renderService.ListAvailableTemplates(ctx)
+	sources, err := discoverTemplates(config.Storage.Dir())
 	if err != nil {
 		return fmt.Errorf("failed to list templates: %w", err)
 	}
@@ -465,16 +830,12 @@ func (a *App) executeGenerateTemplateList(ctx context.Context, configPath string
 	a.logger.Println("📝 Available Invoice Templates")
 	a.logger.Println("==============================")
 
-	if len(templates) == 0 {
-		a.logger.Println("No templates found.")
-		return nil
-	}
-
-	for _, templateName := range templates {
-		a.logger.Printf("\n🎨 %s\n", templateName)
+	for _, source := range sources {
+		a.logger.Printf("\n🎨 %s\n", source.Name)
+		a.logger.Printf("   Path: %s\n", cmp.Or(source.Path, embeddedTemplateLabel))
 
 		// Get template info
-		info, err := renderService.GetTemplateInfo(ctx, templateName)
+		info, err := renderService.GetTemplateInfo(ctx, source.Name)
 		if err != nil {
 			a.logger.Printf("   Error getting template info: %v\n", err)
 			continue
@@ -503,13 +864,13 @@ func (a *App) executeGenerateTemplateList(ctx context.Context, configPath string
 		}
 	}
 
-	a.logger.Printf("\nTotal: %d template(s)\n", len(templates))
+	a.logger.Printf("\nTotal: %d template(s)\n", len(sources))
 	return nil
 }
 
 // Helper methods
 
-func (a *App) createRenderService(ctx context.Context, _ *config.Config) (*render.TemplateRenderer, error) {
+func (a *App) createRenderService(ctx context.Context, config *config.Config) (*render.TemplateRenderer, error) {
 	// Create file reader
 	fileReader := &SimpleFileReader{}
 
@@ -524,6 +885,17 @@ func (a *App) createRenderService(ctx context.Context, _ *config.Config) (*rende
 		return nil, fmt.Errorf("failed to load built-in templates: %w", err)
 	}
 
+	// Load the named templates from the data and project template directories
+	if config != nil {
+		sources, err := discoverTemplates(config.Storage.Dir())
+		if err != nil {
+			return nil, err
+		}
+		if err := a.loadTemplateSources(ctx, engine, sources); err != nil {
+			return nil, fmt.Errorf("failed to load custom templates: %w", err)
+		}
+	}
+
 	// Create template cache
 	cache := &SimpleTemplateCache{
 		templates: make(map[string]render.Template),
@@ -551,27 +923,36 @@ func (a *App) createRenderService(ctx context.Context, _ *config.Config) (*rende
 	return renderer, nil
 }
 
-func (a *App) createInvoiceService(dataDir string) *services.InvoiceService {
+func (a *App) createInvoiceService(cfg *config.Config) *services.InvoiceService {
 	// Create storage
-	storage := jsonStorage.NewJSONStorage(dataDir, a.logger)
+	storage := a.newStore(cfg.Storage)
 
 	// Create invoice service
-	invoiceService := services.NewInvoiceService(storage, storage, a.logger, &SimpleIDGenerator{})
+	invoiceService := a.newInvoiceService(cfg, storage, storage)
 
 	return invoiceService
 }
 
-func (a *App) createClientService(dataDir string) *services.ClientService {
+func (a *App) createClientService(storageConfig config.StorageConfig) *services.ClientService {
 	// Create storage
-	storage := jsonStorage.NewJSONStorage(dataDir, a.logger)
+	storage := a.newStore(storageConfig)
 
 	// Create client service
-	clientService := services.NewClientService(storage, storage, a.logger, &SimpleIDGenerator{})
+	clientService := services.NewClientService(storage, storage, a.logger, a.idGenerator(storageConfig))
 
 	return clientService
 }
 
 func (a *App) loadBuiltInTemplates(ctx context.Context, engine render.TemplateEngine) error {
+	// Prefer the project template created by "init", falling back to the embedded default
+	if _, err := os.Stat(projectTemplatePath); err == nil {
+		a.logger.Printf("✅ Loading template: %s\n", projectTemplatePath)
+		if err := engine.LoadTemplate(ctx, "default", projectTemplatePath); err != nil {
+			return fmt.Errorf("failed to load %s: %w", projectTemplatePath, err)
+		}
+		return nil
+	}
+
 	// Use embedded template (always available regardless of working directory)
 	a.logger.Printf("✅ Loading embedded template (size: %d bytes)\n", len(templates.DefaultInvoiceTemplate))
 	defaultTemplate := []byte(templates.DefaultInvoiceTemplate)
@@ -584,70 +965,156 @@ func (a *App) loadBuiltInTemplates(ctx context.Context, engine render.TemplateEn
 	return nil
 }
 
-func (a *App) createInvoiceData(invoice *models.Invoice, config *config.Config) *InvoiceData {
-	// Calculate total hours from all item types
-	totalHours := 0.0
+// discoverTemplates lists the templates available by name, "default" first and the rest
+// sorted by name. "default" is the project template (templates/invoice.html) when present,
+// otherwise the embedded template. Every other *.html file in the project templates
+// directory or in <data-dir>/templates is named after the file without its extension;
+// a project template wins over a data directory template with the same name.
+func discoverTemplates(dataDir string) ([]templateSource, error) {
+	defaultSource := templateSource{Name: "default"}
+	if _, err := os.Stat(projectTemplatePath); err == nil {
+		defaultSource.Path = projectTemplatePath
+	}
+
+	named := make(map[string]string)
+	for _, dir := range []string{filepath.Join(dataDir, "templates"), filepath.Dir(projectTemplatePath)} {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.html"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list templates in %s: %w", dir, err)
+		}
+		for _, path := range paths {
+			if path == projectTemplatePath {
+				continue
+			}
+			named[strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))] = path
+		}
+	}
+	delete(named, defaultSource.Name)
+
+	sources := []templateSource{defaultSource}
+	for _, name := range slices.Sorted(maps.Keys(named)) {
+		sources = append(sources, templateSource{Name: name, Path: named[name]})
+	}
+	return sources, nil
+}
+
+// findTemplateSource returns the template with the given name, or an error listing the
+// available names
+func findTemplateSource(sources []templateSource, name string) (templateSource, error) {
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		if source.Name == name {
+			return source, nil
+		}
+		names = append(names, source.Name)
+	}
+	return templateSource{}, fmt.Errorf("%w %q (available: %s)", ErrUnknownTemplate, name, strings.Join(names, ", "))
+}
 
-	// Count hours from legacy WorkItems
-	for _, item := range invoice.WorkItems {
-		totalHours += item.Hours
+// checkTemplateName reports an unknown template name before any work is done
+func checkTemplateName(name, dataDir string) error {
+	sources, err := discoverTemplates(dataDir)
+	if err != nil {
+		return err
 	}
+	_, err = findTemplateSource(sources, name)
+	return err
+}
 
-	// Count hours from modern LineItems (only hourly types)
-	for _, item := range invoice.LineItems {
-		if item.Type == models.LineItemTypeHourly && item.Hours != nil {
-			totalHours += *item.Hours
+// loadTemplateSources loads the named templates into the engine. The default template is
+// loaded separately by loadBuiltInTemplates.
+func (a *App) loadTemplateSources(ctx context.Context, engine render.TemplateEngine, sources []templateSource) error {
+	for _, source := range sources {
+		if source.Name == "default" {
+			continue
 		}
+		if err := engine.LoadTemplate(ctx, source.Name, source.Path); err != nil {
+			return fmt.Errorf("failed to load template %s: %w", source.Name, err)
+		}
+		a.logger.Debug("loaded custom template", "name", source.Name, "path", source.Path)
 	}
 
-	return &InvoiceData{
-		Invoice: *invoice,
-		Business: BusinessInfo{
-			Name:           config.Business.Name,
-			Address:        config.Business.Address,
-			Phone:          config.Business.Phone,
-			Email:          config.Business.Email,
-			Website:        config.Business.Website,
-			TaxID:          config.Business.TaxID,
-			PaymentTerms:   config.Business.PaymentTerms,
-			BankDetails:    config.Business.BankDetails,
-			CryptoPayments: config.Business.CryptoPayments,
-		},
-		Config: ConfigInfo{
-			Currency:       config.Invoice.Currency,
-			CurrencySymbol: getCurrencySymbol(config.Invoice.Currency),
-			DateFormat:     "January 2, 2006", // Default format
-			DecimalPlaces:  2,
-		},
-		TotalHours: totalHours,
+	return nil
+}
+
+// isTemplateFilePath reports whether a template name refers to a file rather than a loaded template
+func isTemplateFilePath(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".html", ".htm", ".tmpl", ".gohtml":
+		return true
 	}
+	return strings.ContainsAny(name, `/\`)
 }
 
-func (a *App) renderInvoice(ctx context.Context, renderService render.InvoiceRenderer, data *InvoiceData, templateName string) (string, error) {
-	// Always use type assertion to access the RenderData method with business info
+// loadTemplateFile loads a custom template file into the renderer using its path as the name
+func (a *App) loadTemplateFile(ctx context.Context, renderService render.InvoiceRenderer, path string) error {
 	templateRenderer, ok := renderService.(*render.TemplateRenderer)
 	if !ok {
-		return "", fmt.Errorf("%w: render service must be a TemplateRenderer to access business data", models.ErrTemplateNotFound)
+		return fmt.Errorf("%w: render service cannot load template files", models.ErrTemplateNotFound)
+	}
+	if err := templateRenderer.LoadTemplateFile(ctx, path, filepath.Clean(path)); err != nil {
+		return fmt.Errorf("failed to load template file %s: %w", path, err)
 	}
+	return nil
+}
 
-	return templateRenderer.RenderData(ctx, data, templateName)
+// resolveTemplateName picks the template for an invoice and reports where it came from.
+// The order is: flag, invoice template, client template, config default, then "default".
+func resolveTemplateName(flagTemplate string, invoice *models.Invoice, cfg *config.Config) (string, string) {
+	if name := strings.TrimSpace(flagTemplate); name != "" {
+		return name, "--template flag"
+	}
+	if name := strings.TrimSpace(invoice.TemplateName); name != "" {
+		return name, "invoice"
+	}
+	if name := strings.TrimSpace(invoice.Client.TemplateName); name != "" {
+		return name, "client " + invoice.Client.Name
+	}
+	if name := strings.TrimSpace(cfg.Invoice.DefaultTemplate); name != "" {
+		return name, "config"
+	}
+	return "default", "built-in default"
 }
 
-func getCurrencySymbol(currency string) string {
-	switch currency {
-	case "USD":
-		return "$"
-	case "EUR":
-		return "€"
-	case "GBP":
-		return "£"
-	case "CAD":
-		return "C$"
-	case "AUD":
-		return "A$"
+// resolveRenderStyleFlag converts the --detailed/--summarized flags into a render style.
+// An empty result means the config default should be used.
+func resolveRenderStyleFlag(detailed, summarized bool) string {
+	switch {
+	case summarized:
+		return render.RenderStyleSummarized
+	case detailed:
+		return render.RenderStyleDetailed
 	default:
-		return currency
+		return ""
+	}
+}
+
+// applyRenderStyle groups the invoice items in the template data into rate bands when the
+// summarized style is selected. Only the template data copy is changed, never the stored invoice.
+func applyRenderStyle(data *render.TemplateData, flagStyle, configStyle string) {
+	style := flagStyle
+	if style == "" {
+		style = configStyle
 	}
+	data.RenderStyle = render.RenderStyleDetailed
+
+	if style != render.RenderStyleSummarized {
+		return
+	}
+
+	data.RenderStyle = render.RenderStyleSummarized
+	data.LineItems = render.GroupLineItemsByRate(data.LineItems)
+	data.WorkItems = render.GroupWorkItemsByRate(data.WorkItems)
+}
+
+func (a *App) renderInvoice(ctx context.Context, renderService render.InvoiceRenderer, data *render.TemplateData, templateName string) (string, error) {
+	// Always use type assertion to access the RenderData method with business info
+	templateRenderer, ok := renderService.(*render.TemplateRenderer)
+	if !ok {
+		return "", fmt.Errorf("%w: render service must be a TemplateRenderer to access business data", models.ErrTemplateNotFound)
+	}
+
+	return templateRenderer.RenderData(ctx, data, templateName)
 }
 
 func (a *App) createSampleInvoice(_ *config.Config) *models.Invoice {
@@ -700,10 +1167,10 @@ func (a *App) createSampleInvoice(_ *config.Config) *models.Invoice {
 		WorkItems:   workItems,
 		Status:      models.StatusDraft,
 		Description: "Sample invoice for template preview",
-		Subtotal:    2312.50,
+		Subtotal:    money.FromFloat(2312.50),
 		TaxRate:     0.10,
-		TaxAmount:   231.25,
-		Total:       2543.75,
+		TaxAmount:   money.FromFloat(231.25),
+		Total:       money.FromFloat(2543.75),
 		CreatedAt:   time.Now().AddDate(0, 0, -1),
 		UpdatedAt:   time.Now(),
 		Version:     1,
@@ -739,12 +1206,19 @@ func (a *App) openInBrowser(path string) error {
 // Option types for generate commands
 
 type GenerateInvoiceOptions struct {
-	TemplateName string
-	OutputPath   string
-	OpenBrowser  bool
-	Validate     bool
-	Currency     string
-	TaxRate      float64
+	TemplateName   string
+	TemplateSource string
+	OutputPath     string
+	OpenBrowser    bool
+	Validate       bool
+	Currency       string
+	TaxRate        float64
+	Confirm        bool
+	ConfirmSet     bool
+	AssumeYes      bool
+	RenderStyle    string
+	Format         string // generateFormatHTML or generateFormatQR
+	QRNetwork      string // Network to render a QR code for with generateFormatQR; empty for all
 }
 
 type GeneratePreviewOptions struct {
@@ -754,33 +1228,6 @@ type GeneratePreviewOptions struct {
 
 // Data structures for templates
 
-type InvoiceData struct {
-	models.Invoice
-
-	Business   BusinessInfo `json:"business"`
-	Config     ConfigInfo   `json:"config"`
-	TotalHours float64      `json:"total_hours"`
-}
-
-type BusinessInfo struct {
-	Name           string                `json:"name"`
-	Address        string                `json:"address"`
-	Phone          string                `json:"phone"`
-	Email          string                `json:"email"`
-	Website        string                `json:"website"`
-	TaxID          string                `json:"tax_id"`
-	PaymentTerms   string                `json:"payment_terms"`
-	BankDetails    config.BankDetails    `json:"bank_details"`
-	CryptoPayments config.CryptoPayments `json:"crypto_payments"`
-}
-
-type ConfigInfo struct {
-	Currency       string `json:"currency"`
-	CurrencySymbol string `json:"currency_symbol"`
-	DateFormat     string `json:"date_format"`
-	DecimalPlaces  int    `json:"decimal_places"`
-}
-
 // LoggerWrapper wraps cli.SimpleLogger to implement render.Logger interface
 type LoggerWrapper struct {
 	logger *cli.SimpleLogger
@@ -795,8 +1242,7 @@ func (l *LoggerWrapper) Info(msg string, keysAndValues ...interface{}) {
 }
 
 func (l *LoggerWrapper) Warn(msg string, keysAndValues ...interface{}) {
-	// SimpleLogger doesn't have Warn, so use Error instead
-	l.logger.Error(msg, keysAndValues...)
+	l.logger.Warn(msg, keysAndValues...)
 }
 
 func (l *LoggerWrapper) Error(msg string, keysAndValues ...interface{}) {
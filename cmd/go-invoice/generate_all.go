@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/render"
+	"github.com/mrz1836/go-invoice/internal/services"
+)
+
+// Batch generation errors
+var (
+	ErrGenerateAllWithInvoiceID = fmt.Errorf("--all and the invoice filters cannot be combined with an invoice ID")
+	ErrGenerateAllFlagConflict  = fmt.Errorf("--output, --open and --confirm generate a single invoice; use --out-dir with --all")
+	ErrGenerateIncomplete       = fmt.Errorf("some invoices could not be generated")
+)
+
+// generateBatchFlags select batch mode on the generate command when any of them is set
+var generateBatchFlags = []string{"all", "status", "client", "from", "to", "po", "out-dir"} //nolint:gochecknoglobals // Read-only flag list
+
+// generateBatchResult counts the outcome of a batch generation
+type generateBatchResult struct {
+	Generated int
+	Failed    int
+}
+
+// addGenerateBatchFlags adds the batch mode flags to the generate command
+func addGenerateBatchFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("all", false, "Generate every invoice matching the filters instead of a single invoice")
+	cmd.Flags().String("status", "", "With --all, only invoices with this status (draft, sent, paid, overdue, voided)")
+	cmd.Flags().String("client", "", "With --all, only invoices for this client name or ID")
+	cmd.Flags().String("from", "", "With --all, only invoices dated on or after this date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().String("to", "", "With --all, only invoices dated on or before this date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().String("po", "", "With --all, only invoices with this purchase order number")
+	cmd.Flags().String("out-dir", "", "With --all, directory to write <invoice-number>.html files to (default: <data-dir>/generated)")
+}
+
+// isGenerateBatch reports whether the generate command was asked to run in batch mode
+func isGenerateBatch(cmd *cobra.Command) bool {
+	for _, name := range generateBatchFlags {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// runGenerateAll renders every invoice matching the list filters to the output directory
+func (a *App) runGenerateAll(cmd *cobra.Command) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	options := generateInvoiceOptions(cmd)
+	if options.OutputPath != "" || options.OpenBrowser || options.Confirm {
+		return ErrGenerateAllFlagConflict
+	}
+	if err := validateGenerateFormat(options.Format, options.QRNetwork); err != nil {
+		return err
+	}
+
+	// A batch never stops to ask, even when INVOICE_CONFIRM_BEFORE_GENERATE is set
+	options.ConfirmSet = true
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	renderService, err := a.createRenderService(ctx, config)
+	if err != nil {
+		return fmt.Errorf("failed to create render service: %w", err)
+	}
+	invoiceService := a.createInvoiceService(config)
+	clientService := a.createClientService(config.Storage)
+
+	// Select invoices with the same filters as "invoice list"
+	filter, err := a.buildInvoiceFilter(ctx, cmd, clientService, config)
+	if err != nil {
+		return err
+	}
+	result, err := invoiceService.ListInvoices(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list invoices: %w", err)
+	}
+	if err = a.checkSkippedInvoices(result.Warnings, false); err != nil {
+		return err
+	}
+
+	if len(result.Invoices) == 0 {
+		a.logger.Println("No matching invoices found")
+		return nil
+	}
+
+	outDir, _ := cmd.Flags().GetString("out-dir")
+	if outDir == "" {
+		outDir = filepath.Join(config.Storage.Dir(), "generated")
+	}
+
+	start := time.Now()
+	a.logger.Printf("📄 Generating %d invoice(s) into %s\n\n", len(result.Invoices), outDir)
+
+	counts := a.generateInvoices(ctx, config, renderService, invoiceService, result.Invoices, outDir, options)
+
+	a.logger.Println("")
+	a.logger.Printf("Generated: %d, failed: %d (%v)\n", counts.Generated, counts.Failed, time.Since(start).Round(time.Millisecond))
+
+	if counts.Failed > 0 {
+		return fmt.Errorf("%w: %d failed", ErrGenerateIncomplete, counts.Failed)
+	}
+	return nil
+}
+
+// generateInvoices renders each invoice to <outDir>/<invoice-number>.html, reporting every
+// invoice as it goes. A failure is reported and the rest of the batch continues.
+func (a *App) generateInvoices(ctx context.Context, config *config.Config, renderService render.InvoiceRenderer,
+	invoiceService *services.InvoiceService, invoices []*models.Invoice, outDir string, options GenerateInvoiceOptions,
+) generateBatchResult {
+	var counts generateBatchResult
+	used := make(map[string]bool, len(invoices))
+
+	for _, invoice := range invoices {
+		// Each invoice picks its own template from the flag, invoice, client or config
+		invoiceOptions := options
+		invoiceOptions.OutputPath = filepath.Join(outDir, batchFilename(invoice, used))
+
+		html, _, err := a.prepareInvoiceDocument(ctx, config, renderService, invoiceService, invoice, &invoiceOptions)
+		if err != nil {
+			a.logger.Printf("   ❌ %s: %v\n", invoice.Number, err)
+			counts.Failed++
+			continue
+		}
+
+		a.warnIfOverwritingIssuedDocument(invoice, invoiceOptions.OutputPath, html)
+		outputPath, err := a.writeGeneratedInvoice(html, invoiceOptions.OutputPath, invoice.Number, config.Storage.Dir())
+		if err != nil {
+			a.logger.Printf("   ❌ %s: %v\n", invoice.Number, err)
+			counts.Failed++
+			continue
+		}
+
+		a.logger.Result(outputPath)
+		a.logger.Printf("   ✅ %s: %s (%s)\n", invoice.Number, outputPath, invoiceOptions.TemplateName)
+		counts.Generated++
+
+		if config.Storage.StoreDocuments {
+			if _, storeErr := a.storeGeneratedDocument(ctx, invoiceService, invoice, html, invoiceOptions.TemplateName, config.Storage.Dir()); storeErr != nil {
+				a.logger.Printf("   ⚠️  %s: could not archive generated document: %v\n", invoice.Number, storeErr)
+			}
+		}
+	}
+
+	return counts
+}
+
+// batchFilename returns the file name for an invoice in a batch. Two invoice numbers can
+// sanitize to the same name, so later ones get the invoice ID appended instead of
+// overwriting an earlier file.
+func batchFilename(invoice *models.Invoice, used map[string]bool) string {
+	name := sanitizeInvoiceNumber(invoice.Number)
+	if used[name] {
+		name = fmt.Sprintf("%s-%s", name, sanitizeInvoiceNumber(string(invoice.ID)))
+	}
+	used[name] = true
+	return name + ".html"
+}
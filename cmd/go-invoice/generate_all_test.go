@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/services"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+)
+
+func TestSanitizeInvoiceNumber(t *testing.T) {
+	for number, want := range map[string]string{
+		"INV-2025-001":      "INV-2025-001",
+		"INV/2025/001":      "INV-2025-001",
+		`INV\2025\001`:      "INV-2025-001",
+		"INV: 001?":         "INV--001-",
+		"../../etc/passwd":  "-..-etc-passwd",
+		".hidden":           "hidden",
+		"  ":                "invoice",
+		"...":               "invoice",
+		"CON":               "invoice-CON",
+		"nul.2025":          "invoice-nul.2025",
+		"Rechnung-Nr.ä-001": "Rechnung-Nr.ä-001",
+	} {
+		assert.Equal(t, want, sanitizeInvoiceNumber(number), number)
+	}
+}
+
+func TestBatchFilename(t *testing.T) {
+	used := make(map[string]bool)
+
+	assert.Equal(t, "INV-001.html", batchFilename(&models.Invoice{ID: "a1", Number: "INV/001"}, used))
+	assert.Equal(t, "INV-002.html", batchFilename(&models.Invoice{ID: "b2", Number: "INV-002"}, used))
+	// A number that sanitizes to a name already written keeps its own file
+	assert.Equal(t, "INV-001-c3.html", batchFilename(&models.Invoice{ID: "c3", Number: "INV-001"}, used))
+}
+
+func TestIsGenerateBatch(t *testing.T) {
+	app := &App{logger: cli.NewLogger(false)}
+
+	for args, want := range map[string][]string{
+		"single":  {"--template", "professional"},
+		"all":     {"--all"},
+		"filter":  {"--status", "sent"},
+		"out-dir": {"--out-dir", "./invoices"},
+	} {
+		cmd := app.buildGenerateCommand()
+		require.NoError(t, cmd.ParseFlags(want), args)
+		assert.Equal(t, args != "single", isGenerateBatch(cmd), args)
+	}
+}
+
+func TestGenerateInvoices(t *testing.T) {
+	app := &App{logger: cli.NewLogger(false)}
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	outDir := filepath.Join(t.TempDir(), "invoices")
+
+	storage := jsonStorage.NewJSONStorage(dataDir, app.logger)
+	require.NoError(t, storage.Initialize(ctx))
+	cfg := &config.Config{
+		Business: config.BusinessConfig{Name: "Test Business", Email: "billing@test.example"},
+		Invoice:  config.InvoiceConfig{Currency: "USD"},
+		Storage:  config.StorageConfig{DataDir: dataDir},
+	}
+	// Invoices created in the same second need unique IDs
+	idGen := services.NewUUIDGenerator()
+	clientService := services.NewClientService(storage, storage, app.logger, idGen)
+	invoiceService := services.NewInvoiceService(storage, storage, app.logger, idGen)
+	renderService, err := app.createRenderService(ctx, cfg)
+	require.NoError(t, err)
+
+	client, err := clientService.CreateClient(ctx, models.CreateClientRequest{Name: "Acme Corp", Email: "billing@acme.test"})
+	require.NoError(t, err)
+
+	var invoices []*models.Invoice
+	for _, number := range []string{"INV-2025-001", "INV-2025-002", "INV-2025-003"} {
+		invoice, createErr := invoiceService.CreateInvoice(ctx, models.CreateInvoiceRequest{
+			Number:   number,
+			ClientID: client.ID,
+			Date:     time.Now(),
+			DueDate:  time.Now().AddDate(0, 0, 30),
+		})
+		require.NoError(t, createErr)
+		invoices = append(invoices, invoice)
+	}
+
+	// An invoice whose template is missing fails without stopping the batch
+	invoices[1].TemplateName = "missing"
+
+	counts := app.generateInvoices(ctx, cfg, renderService, invoiceService, invoices, outDir, GenerateInvoiceOptions{Format: generateFormatHTML})
+	assert.Equal(t, generateBatchResult{Generated: 2, Failed: 1}, counts)
+
+	for _, name := range []string{"INV-2025-001.html", "INV-2025-003.html"} {
+		content, readErr := os.ReadFile(filepath.Join(outDir, name)) //nolint:gosec // test path
+		require.NoError(t, readErr, name)
+		assert.Contains(t, string(content), "Acme Corp", name)
+	}
+	assert.NoFileExists(t, filepath.Join(outDir, "INV-2025-002.html"))
+}
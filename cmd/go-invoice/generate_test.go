@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/render"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+	"github.com/mrz1836/go-invoice/internal/templates"
+)
+
+func newConfirmTestInvoice() *models.Invoice {
+	return &models.Invoice{
+		ID:      "test-001",
+		Number:  "TEST-001",
+		Date:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		DueDate: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		Client: models.Client{
+			ID:    "client-1",
+			Name:  "Acme Corp",
+			Email: "billing@acme.test",
+		},
+		Total: money.FromFloat(1500.00),
+	}
+}
+
+func TestConfirmInvoiceGeneration(t *testing.T) {
+	app := &App{
+		logger: cli.NewLogger(false),
+	}
+	ctx := context.Background()
+
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		cfg := &config.Config{Invoice: config.InvoiceConfig{Currency: "USD"}}
+
+		confirmed, err := app.confirmInvoiceGeneration(ctx, newConfirmTestInvoice(), cfg, GenerateInvoiceOptions{})
+		require.NoError(t, err)
+		assert.True(t, confirmed)
+	})
+
+	t.Run("FlagOverridesConfigDefault", func(t *testing.T) {
+		cfg := &config.Config{Invoice: config.InvoiceConfig{Currency: "USD", ConfirmBeforeGenerate: true}}
+
+		confirmed, err := app.confirmInvoiceGeneration(ctx, newConfirmTestInvoice(), cfg, GenerateInvoiceOptions{
+			Confirm:    false,
+			ConfirmSet: true,
+		})
+		require.NoError(t, err)
+		assert.True(t, confirmed)
+	})
+
+	t.Run("AssumeYesSkipsPrompt", func(t *testing.T) {
+		cfg := &config.Config{Invoice: config.InvoiceConfig{Currency: "USD"}}
+
+		confirmed, err := app.confirmInvoiceGeneration(ctx, newConfirmTestInvoice(), cfg, GenerateInvoiceOptions{
+			Confirm:    true,
+			ConfirmSet: true,
+			AssumeYes:  true,
+		})
+		require.NoError(t, err)
+		assert.True(t, confirmed)
+	})
+
+	t.Run("NonInteractiveWithoutYesErrors", func(t *testing.T) {
+		reader, writer, err := os.Pipe()
+		require.NoError(t, err)
+		defer func() { _ = reader.Close() }()
+		require.NoError(t, writer.Close())
+
+		originalStdin := os.Stdin
+		os.Stdin = reader
+		defer func() { os.Stdin = originalStdin }()
+
+		cfg := &config.Config{Invoice: config.InvoiceConfig{Currency: "USD", ConfirmBeforeGenerate: true}}
+
+		confirmed, err := app.confirmInvoiceGeneration(ctx, newConfirmTestInvoice(), cfg, GenerateInvoiceOptions{})
+		require.ErrorIs(t, err, models.ErrConfirmationRequired)
+		assert.False(t, confirmed)
+	})
+}
+
+func TestPaymentMethodsSummary(t *testing.T) {
+	invoice := newConfirmTestInvoice()
+
+	t.Run("NoMethodsConfigured", func(t *testing.T) {
+		cfg := &config.Config{}
+		assert.Empty(t, paymentMethodsSummary(invoice, cfg))
+	})
+
+	t.Run("AllMethodsConfigured", func(t *testing.T) {
+		cfg := &config.Config{
+			Business: config.BusinessConfig{
+				BankDetails: config.BankDetails{
+					ACHEnabled: true,
+					IBAN:       "GB00TEST0000000000",
+				},
+				CryptoPayments: config.CryptoPayments{
+					USDCEnabled: true,
+					USDCAddress: "0x0000000000000000000000000000000000000001",
+					BSVEnabled:  true,
+					BSVAddress:  "1BoatSLRHtKNngkdXEeobR76b53LETtpyT",
+				},
+			},
+		}
+		assert.Equal(t, []string{"ACH", "Wire", "USDC", "BSV"}, paymentMethodsSummary(invoice, cfg))
+	})
+
+	t.Run("CryptoEnabledWithoutAddress", func(t *testing.T) {
+		cfg := &config.Config{
+			Business: config.BusinessConfig{
+				CryptoPayments: config.CryptoPayments{USDCEnabled: true},
+			},
+		}
+		assert.Empty(t, paymentMethodsSummary(invoice, cfg))
+	})
+}
+
+func TestApplyRenderStyle(t *testing.T) {
+	cfg := &config.Config{Invoice: config.InvoiceConfig{Currency: "USD"}}
+
+	newInvoice := func() *models.Invoice {
+		hoursA, hoursB, rate := 8.0, 2.0, 125.0
+		return &models.Invoice{
+			ID:     "test-001",
+			Number: "TEST-001",
+			LineItems: []models.LineItem{
+				{ID: "a", Type: models.LineItemTypeHourly, Hours: &hoursA, Rate: &rate, Description: "Development", Total: money.FromFloat(1000)},
+				{ID: "b", Type: models.LineItemTypeHourly, Hours: &hoursB, Rate: &rate, Description: "Development", Total: money.FromFloat(250)},
+			},
+			Subtotal: money.FromFloat(1250),
+			Total:    money.FromFloat(1250),
+		}
+	}
+
+	t.Run("SummarizedFlagGroupsItems", func(t *testing.T) {
+		invoice := newInvoice()
+		data := render.BuildTemplateData(cfg, invoice, nil)
+		applyRenderStyle(data, resolveRenderStyleFlag(false, true), "detailed")
+
+		assert.Equal(t, "summarized", data.RenderStyle)
+		require.Len(t, data.LineItems, 1)
+		assert.InDelta(t, 1250.0, data.LineItems[0].Total.Float64(), 0.001)
+		assert.InDelta(t, 10.0, data.TotalHours, 0.001)
+		assert.InDelta(t, invoice.Total.Float64(), data.Total.Float64(), 0.001)
+
+		// Stored invoice data must not change
+		assert.Len(t, invoice.LineItems, 2)
+	})
+
+	t.Run("DetailedFlagOverridesConfig", func(t *testing.T) {
+		data := render.BuildTemplateData(cfg, newInvoice(), nil)
+		applyRenderStyle(data, resolveRenderStyleFlag(true, false), "summarized")
+
+		assert.Equal(t, "detailed", data.RenderStyle)
+		assert.Len(t, data.LineItems, 2)
+	})
+
+	t.Run("ConfigDefaultUsedWithoutFlag", func(t *testing.T) {
+		data := render.BuildTemplateData(cfg, newInvoice(), nil)
+		applyRenderStyle(data, resolveRenderStyleFlag(false, false), "summarized")
+
+		assert.Equal(t, "summarized", data.RenderStyle)
+		assert.Len(t, data.LineItems, 1)
+	})
+}
+
+func TestResolveTemplateName(t *testing.T) {
+	newInvoice := func(invoiceTemplate, clientTemplate string) *models.Invoice {
+		invoice := newConfirmTestInvoice()
+		invoice.TemplateName = invoiceTemplate
+		invoice.Client.TemplateName = clientTemplate
+		return invoice
+	}
+	cfg := &config.Config{Invoice: config.InvoiceConfig{DefaultTemplate: "house"}}
+
+	tests := []struct {
+		name           string
+		flag           string
+		invoice        *models.Invoice
+		cfg            *config.Config
+		expectedName   string
+		expectedSource string
+	}{
+		{"FlagWins", "minimal", newInvoice("invoice-tpl", "client-tpl"), cfg, "minimal", "--template flag"},
+		{"InvoiceOverClient", "", newInvoice("invoice-tpl", "client-tpl"), cfg, "invoice-tpl", "invoice"},
+		{"ClientOverConfig", "", newInvoice("", "client-tpl"), cfg, "client-tpl", "client Acme Corp"},
+		{"ConfigDefault", "", newInvoice("", ""), cfg, "house", "config"},
+		{"BuiltInFallback", "  ", newInvoice("", ""), &config.Config{}, "default", "built-in default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, source := resolveTemplateName(tt.flag, tt.invoice, tt.cfg)
+			assert.Equal(t, tt.expectedName, name)
+			assert.Equal(t, tt.expectedSource, source)
+		})
+	}
+}
+
+func TestDiscoverTemplates(t *testing.T) {
+	app := &App{
+		logger: cli.NewLogger(false),
+	}
+	ctx := context.Background()
+
+	originalPath := projectTemplatePath
+	defer func() { projectTemplatePath = originalPath }()
+
+	t.Run("EmbeddedDefaultOnly", func(t *testing.T) {
+		projectTemplatePath = filepath.Join(t.TempDir(), "templates", "invoice.html")
+
+		sources, err := discoverTemplates(filepath.Join(t.TempDir(), "missing"))
+		require.NoError(t, err)
+		assert.Equal(t, []templateSource{{Name: "default"}}, sources)
+	})
+
+	t.Run("ProjectAndDataDirTemplates", func(t *testing.T) {
+		projectDir := filepath.Join(t.TempDir(), "templates")
+		dataDir := t.TempDir()
+		require.NoError(t, os.MkdirAll(projectDir, 0o750))
+		require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "templates"), 0o750))
+		projectTemplatePath = filepath.Join(projectDir, "invoice.html")
+
+		for _, path := range []string{
+			projectTemplatePath,
+			filepath.Join(projectDir, "minimal.html"),
+			filepath.Join(projectDir, "notes.txt"),
+			filepath.Join(dataDir, "templates", "brandco.html"),
+			filepath.Join(dataDir, "templates", "minimal.html"),
+		} {
+			require.NoError(t, os.WriteFile(path, []byte(`<h1>{{.Number}}</h1>`), 0o600))
+		}
+
+		sources, err := discoverTemplates(dataDir)
+		require.NoError(t, err)
+		assert.Equal(t, []templateSource{
+			{Name: "default", Path: projectTemplatePath},
+			{Name: "brandco", Path: filepath.Join(dataDir, "templates", "brandco.html")},
+			{Name: "minimal", Path: filepath.Join(projectDir, "minimal.html")},
+		}, sources)
+
+		engine := render.NewHTMLTemplateEngine(&SimpleFileReader{}, &LoggerWrapper{logger: app.logger})
+		require.NoError(t, app.loadTemplateSources(ctx, engine, sources))
+		_, err = engine.GetTemplate(ctx, "minimal")
+		require.NoError(t, err)
+		_, err = engine.GetTemplate(ctx, "notes")
+		require.Error(t, err)
+
+		source, err := findTemplateSource(sources, "brandco")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dataDir, "templates", "brandco.html"), source.Path)
+
+		_, err = findTemplateSource(sources, "fancy")
+		require.ErrorIs(t, err, ErrUnknownTemplate)
+		assert.Contains(t, err.Error(), "available: default, brandco, minimal")
+	})
+}
+
+func TestStoreGeneratedDocument(t *testing.T) {
+	app := &App{
+		logger: cli.NewLogger(false),
+	}
+	ctx := context.Background()
+	dataDir := t.TempDir()
+
+	storage := jsonStorage.NewJSONStorage(dataDir, app.logger)
+	require.NoError(t, storage.Initialize(ctx))
+	clientService := app.createClientService(config.StorageConfig{DataDir: dataDir})
+	invoiceService := app.createInvoiceService(&config.Config{Storage: config.StorageConfig{DataDir: dataDir}})
+
+	client, err := clientService.CreateClient(ctx, models.CreateClientRequest{Name: "Acme Corp", Email: "billing@acme.test"})
+	require.NoError(t, err)
+	invoice, err := invoiceService.CreateInvoice(ctx, models.CreateInvoiceRequest{
+		Number:   "INV-001",
+		ClientID: client.ID,
+		Date:     time.Now(),
+		DueDate:  time.Now().AddDate(0, 0, 30),
+	})
+	require.NoError(t, err)
+
+	doc, err := app.storeGeneratedDocument(ctx, invoiceService, invoice, "<html>v1</html>", "default", dataDir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dataDir, "documents", string(invoice.ID)), filepath.Dir(doc.Path))
+	assert.Equal(t, models.DocumentChecksum([]byte("<html>v1</html>")), doc.Checksum)
+
+	content, err := os.ReadFile(doc.Path) //nolint:gosec // test path
+	require.NoError(t, err)
+	assert.Equal(t, "<html>v1</html>", string(content))
+
+	// Identical content is not recorded twice
+	_, err = app.storeGeneratedDocument(ctx, invoiceService, invoice, "<html>v1</html>", "default", dataDir)
+	require.NoError(t, err)
+
+	_, err = app.storeGeneratedDocument(ctx, invoiceService, invoice, "<html>v2</html>", "default", dataDir)
+	require.NoError(t, err)
+
+	saved, err := invoiceService.GetInvoice(ctx, invoice.ID)
+	require.NoError(t, err)
+	require.Len(t, saved.GeneratedDocuments, 2)
+	assert.Equal(t, models.StatusDraft, saved.GeneratedDocuments[0].Status)
+}
+
+func TestIsTemplateFilePath(t *testing.T) {
+	assert.False(t, isTemplateFilePath("default"))
+	assert.False(t, isTemplateFilePath("brandco"))
+	assert.True(t, isTemplateFilePath("brand.html"))
+	assert.True(t, isTemplateFilePath("./templates/brand"))
+	assert.True(t, isTemplateFilePath("invoice.tmpl"))
+}
+
+func TestResolveOutputPath(t *testing.T) {
+	app := &App{
+		logger: cli.NewLogger(false),
+	}
+
+	assert.Equal(t, "invoice.html", app.resolveOutputPath("./invoice.html", "INV-001", "/data"))
+	assert.Equal(t, filepath.Join("/data", "generated", "INV-2024-001.html"), app.resolveOutputPath("", "INV/2024/001", "/data"))
+}
+
+func TestLoadBuiltInTemplatesPrefersProjectTemplate(t *testing.T) {
+	app := &App{
+		logger: cli.NewLogger(false),
+	}
+	ctx := context.Background()
+
+	originalPath := projectTemplatePath
+	defer func() { projectTemplatePath = originalPath }()
+
+	t.Run("EmbeddedWhenMissing", func(t *testing.T) {
+		projectTemplatePath = filepath.Join(t.TempDir(), "missing.html")
+		engine := render.NewHTMLTemplateEngine(&SimpleFileReader{}, &LoggerWrapper{logger: app.logger})
+
+		require.NoError(t, app.loadBuiltInTemplates(ctx, engine))
+		tmpl, err := engine.GetTemplate(ctx, "default")
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(templates.DefaultInvoiceTemplate)), tmpl.GetInfo().SizeBytes)
+	})
+
+	t.Run("ProjectTemplateWhenPresent", func(t *testing.T) {
+		projectTemplatePath = filepath.Join(t.TempDir(), "invoice.html")
+		require.NoError(t, os.WriteFile(projectTemplatePath, []byte(`<p>{{.Number}}</p>`), 0o600))
+		engine := render.NewHTMLTemplateEngine(&SimpleFileReader{}, &LoggerWrapper{logger: app.logger})
+
+		require.NoError(t, app.loadBuiltInTemplates(ctx, engine))
+		tmpl, err := engine.GetTemplate(ctx, "default")
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(`<p>{{.Number}}</p>`)), tmpl.GetInfo().SizeBytes)
+	})
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,16 +11,18 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/mrz1836/go-invoice/internal/config"
 	"github.com/mrz1836/go-invoice/internal/csv"
 	"github.com/mrz1836/go-invoice/internal/models"
 	"github.com/mrz1836/go-invoice/internal/services"
-	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
 )
 
 // Import command errors
 var (
 	ErrClientIDRequired  = fmt.Errorf("client ID is required (use --client flag)")
 	ErrInvoiceIDRequired = fmt.Errorf("invoice ID is required (use --invoice flag)")
+	ErrInvalidDelimiter  = fmt.Errorf("delimiter must be a single character or \\t")
+	ErrInvalidColumnMap  = fmt.Errorf("column map entries must look like header=field")
 )
 
 // detectFileFormat detects the format based on file extension
@@ -46,8 +49,72 @@ func detectFileFormat(filename, specifiedFormat string) string {
 	}
 }
 
+// parseDelimiter parses the --delimiter flag; "\t" and "tab" select a tab and an
+// empty value keeps the format's default delimiter
+func parseDelimiter(value string) (rune, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return 0, nil
+	case `\t`, "tab":
+		return '\t', nil
+	}
+
+	runes := []rune(value)
+	if len(runes) != 1 || runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidDelimiter, value)
+	}
+	return runes[0], nil
+}
+
+// parseColumnMap parses the --column-map flag, a comma-separated list of
+// header=field pairs such as "task=Description,duration=Hours"
+func parseColumnMap(value string) (map[string]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	columnMap := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		header, field, ok := strings.Cut(pair, "=")
+		header, field = strings.TrimSpace(header), strings.TrimSpace(field)
+		if !ok || header == "" || field == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidColumnMap, pair)
+		}
+		columnMap[header] = field
+	}
+	return columnMap, nil
+}
+
+// ImportParseFlags holds the flags shared by the import commands that control how
+// the data file is parsed
+type ImportParseFlags struct {
+	Delimiter      string
+	ColumnMap      string
+	HoursFormat    string
+	HoursPrecision int
+	Progress       string
+}
+
+// addImportParseFlags registers the parsing flags on an import command
+func addImportParseFlags(cmd *cobra.Command, flags *ImportParseFlags) {
+	cmd.Flags().StringVar(&flags.Delimiter, "delimiter", "", "Field delimiter overriding the format's default (e.g. ';' or '\\t')")
+	cmd.Flags().StringVar(&flags.ColumnMap, "column-map", "", "Map file headers onto fields (e.g. task=Description,duration=Hours)")
+	cmd.Flags().StringVar(&flags.HoursFormat, "hours-format", csv.HoursFormatAuto, "Hours column format (auto, decimal, clock, duration)")
+	cmd.Flags().IntVar(&flags.HoursPrecision, "hours-precision", csv.DefaultHoursPrecision, "Decimal places durations are rounded to (0-2)")
+	cmd.Flags().StringVar(&flags.Progress, "progress", importProgressAuto, "Progress output while rows are parsed (auto, json, none)")
+}
+
 // buildImportCommand creates the import command with subcommands
 func (a *App) buildImportCommand() *cobra.Command {
+	var (
+		dataFile   string
+		invoiceID  string
+		dryRun     bool
+		skipErrors bool
+		format     string
+		parseFlags ImportParseFlags
+	)
+
 	importCmd := &cobra.Command{
 		Use:   "import",
 		Short: "Import timesheet data from CSV or JSON files",
@@ -66,9 +133,43 @@ JSON Formats:
 - Simple array format: [{"date": "2025-08-01", "hours": 8, "rate": 150, "description": "Work"}]
 - Structured format with metadata: {"metadata": {...}, "work_items": [...]}
 
-Can create new invoices or append to existing ones.`,
+Can create new invoices or append to existing ones.
+
+Rows that fail to parse are reported with their line number and the import is
+aborted without changes, unless --skip-errors is passed to import the valid rows.
+
+Examples:
+  go-invoice import --file hours.csv --invoice INV-001
+  go-invoice import create timesheet.csv --client CLIENT_001`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			if dataFile == "" {
+				return cmd.Help()
+			}
+			if invoiceID == "" {
+				return ErrInvoiceIDRequired
+			}
+
+			configPath, _ := cmd.Flags().GetString("config")
+			return a.executeImportAppend(ctx, dataFile, configPath, ImportAppendOptions{
+				InvoiceID:  invoiceID,
+				DryRun:     dryRun,
+				Format:     format,
+				ParseFlags: parseFlags,
+				SkipErrors: skipErrors,
+			})
+		},
 	}
 
+	importCmd.Flags().StringVar(&dataFile, "file", "", "File to import into an existing invoice (same as \"import append\")")
+	importCmd.Flags().StringVar(&invoiceID, "invoice", "", "Invoice ID or number to append to (required with --file)")
+	importCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate only, don't append to invoice")
+	importCmd.Flags().BoolVar(&skipErrors, "skip-errors", false, "Import valid rows even if some rows are rejected")
+	importCmd.Flags().StringVar(&format, "format", "auto", "Import format (auto, csv, json, excel, tsv)")
+	addImportParseFlags(importCmd, &parseFlags)
+
 	// Add import subcommands
 	importCmd.AddCommand(a.buildImportCreateCommand())
 	importCmd.AddCommand(a.buildImportAppendCommand())
@@ -87,7 +188,9 @@ func (a *App) buildImportCreateCommand() *cobra.Command {
 		dueDate       string
 		dryRun        bool
 		interactive   bool
+		skipErrors    bool
 		format        string
+		parseFlags    ImportParseFlags
 	)
 
 	cmd := &cobra.Command{
@@ -97,7 +200,7 @@ func (a *App) buildImportCreateCommand() *cobra.Command {
 
 For CSV files, columns should include:
 - date (work date)
-- hours (hours worked)
+- hours (decimal hours, or durations such as 1:30, 01:30:00 or 1h30m)
 - rate (hourly rate)
 - description (work description)
 
@@ -105,12 +208,16 @@ For JSON files, use either format:
 - Array: [{"date": "2025-08-01", "hours": 8, "rate": 150, "description": "Work"}]
 - Structured: {"metadata": {...}, "work_items": [...]}
 
-Format is auto-detected from file extension, or use --format flag.
+Format is auto-detected from file extension (.tsv and .tab files are tab-separated),
+or use --format flag. Use --delimiter to override the field delimiter and
+--column-map to map headers from other tools onto the expected columns.
 
 Examples:
   go-invoice import create timesheet.csv --client CLIENT_001
   go-invoice import create timesheet.json --client CLIENT_001
-  go-invoice import create data.txt --format json --client CLIENT_001`,
+  go-invoice import create data.txt --format json --client CLIENT_001
+  go-invoice import create hours.txt --delimiter '\t' --client CLIENT_001
+  go-invoice import create export.csv --column-map "task=Description,duration=Hours" --client CLIENT_001`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, cancel := context.WithCancel(cmd.Context())
@@ -131,7 +238,9 @@ Examples:
 				DueDate:       dueDate,
 				DryRun:        dryRun,
 				Interactive:   interactive,
+				SkipErrors:    skipErrors,
 				Format:        format,
+				ParseFlags:    parseFlags,
 			})
 		},
 	}
@@ -139,11 +248,13 @@ Examples:
 	cmd.Flags().StringVar(&clientID, "client", "", "Client ID for the new invoice (required)")
 	cmd.Flags().StringVar(&invoiceNumber, "number", "", "Invoice number (auto-generated if not provided)")
 	cmd.Flags().StringVar(&description, "description", "", "Invoice description")
-	cmd.Flags().StringVar(&invoiceDate, "date", "", "Invoice date (YYYY-MM-DD, default: today)")
-	cmd.Flags().StringVar(&dueDate, "due-date", "", "Due date (YYYY-MM-DD, default: 30 days from invoice date)")
+	cmd.Flags().StringVar(&invoiceDate, "date", "", "Invoice date (DATE_FORMAT or YYYY-MM-DD, default: today)")
+	cmd.Flags().StringVar(&dueDate, "due-date", "", "Due date (DATE_FORMAT or YYYY-MM-DD, default: 30 days from invoice date)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate only, don't create invoice")
 	cmd.Flags().BoolVar(&interactive, "interactive", false, "Interactive mode for resolving ambiguous data")
+	cmd.Flags().BoolVar(&skipErrors, "skip-errors", false, "Import valid rows even if some rows are rejected")
 	cmd.Flags().StringVar(&format, "format", "auto", "Import format (auto, csv, json, excel, tsv)")
+	addImportParseFlags(cmd, &parseFlags)
 
 	return cmd
 }
@@ -154,7 +265,9 @@ func (a *App) buildImportAppendCommand() *cobra.Command {
 		invoiceID   string
 		dryRun      bool
 		interactive bool
+		skipErrors  bool
 		format      string
+		parseFlags  ImportParseFlags
 	)
 
 	cmd := &cobra.Command{
@@ -162,7 +275,7 @@ func (a *App) buildImportAppendCommand() *cobra.Command {
 		Short: "Import data and append to existing invoice",
 		Long: `Import timesheet data from a CSV or JSON file and append work items to an existing invoice.
 
-The invoice must be in draft status to accept additional work items.
+The invoice must be in draft or sent status to accept additional work items.
 
 Examples:
   go-invoice import append timesheet.csv --invoice INV-001
@@ -183,7 +296,9 @@ Examples:
 				InvoiceID:   invoiceID,
 				DryRun:      dryRun,
 				Interactive: interactive,
+				SkipErrors:  skipErrors,
 				Format:      format,
+				ParseFlags:  parseFlags,
 			})
 		},
 	}
@@ -191,14 +306,19 @@ Examples:
 	cmd.Flags().StringVar(&invoiceID, "invoice", "", "Invoice ID to append to (required)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate only, don't append to invoice")
 	cmd.Flags().BoolVar(&interactive, "interactive", false, "Interactive mode for resolving ambiguous data")
+	cmd.Flags().BoolVar(&skipErrors, "skip-errors", false, "Import valid rows even if some rows are rejected")
 	cmd.Flags().StringVar(&format, "format", "auto", "Import format (auto, csv, json, excel, tsv)")
+	addImportParseFlags(cmd, &parseFlags)
 
 	return cmd
 }
 
 // buildImportValidateCommand creates the validation command
 func (a *App) buildImportValidateCommand() *cobra.Command {
-	var format string
+	var (
+		format     string
+		parseFlags ImportParseFlags
+	)
 
 	cmd := &cobra.Command{
 		Use:   "validate <file>",
@@ -224,12 +344,14 @@ Examples:
 			configPath, _ := cmd.Flags().GetString("config")
 
 			return a.executeImportValidate(ctx, dataFile, configPath, ImportValidateOptions{
-				Format: format,
+				Format:     format,
+				ParseFlags: parseFlags,
 			})
 		},
 	}
 
 	cmd.Flags().StringVar(&format, "format", "auto", "Import format (auto, csv, json, excel, tsv)")
+	addImportParseFlags(cmd, &parseFlags)
 
 	return cmd
 }
@@ -239,6 +361,10 @@ Examples:
 func (a *App) executeImportCreate(ctx context.Context, dataFile, configPath string, options ImportCreateOptions) error {
 	// Detect file format
 	fileFormat := detectFileFormat(dataFile, options.Format)
+	parseOptions, err := a.createParseOptions(fileFormat, options.ParseFlags)
+	if err != nil {
+		return err
+	}
 	a.logger.Info("executing import create", "file", dataFile, "client", options.ClientID, "format", fileFormat)
 
 	// Load configuration
@@ -246,9 +372,10 @@ func (a *App) executeImportCreate(ctx context.Context, dataFile, configPath stri
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	parseOptions.DefaultRate = config.Invoice.DefaultRate
 
 	// Create import service
-	importService := a.createImportService(config.Storage.DataDir)
+	importService := a.createImportService(config)
 
 	// Open data file
 	file, err := os.Open(dataFile) // #nosec G304 -- User-provided file path is expected in CLI
@@ -264,17 +391,17 @@ func (a *App) executeImportCreate(ctx context.Context, dataFile, configPath stri
 	// Parse dates or use defaults
 	invoiceDate := time.Now()
 	if options.InvoiceDate != "" {
-		invoiceDate, err = time.Parse("2006-01-02", options.InvoiceDate)
+		invoiceDate, err = config.Invoice.ParseDate(options.InvoiceDate)
 		if err != nil {
-			return fmt.Errorf("invalid invoice date format (use YYYY-MM-DD): %w", err)
+			return fmt.Errorf("invalid --date: %w", err)
 		}
 	}
 
 	dueDate := invoiceDate.AddDate(0, 0, 30) // Default: 30 days from invoice date
 	if options.DueDate != "" {
-		dueDate, err = time.Parse("2006-01-02", options.DueDate)
+		dueDate, err = config.Invoice.ParseDate(options.DueDate)
 		if err != nil {
-			return fmt.Errorf("invalid due date format (use YYYY-MM-DD): %w", err)
+			return fmt.Errorf("invalid --due-date: %w", err)
 		}
 	}
 
@@ -283,9 +410,15 @@ func (a *App) executeImportCreate(ctx context.Context, dataFile, configPath stri
 		ClientID:     models.ClientID(options.ClientID),
 		InvoiceDate:  invoiceDate,
 		DueDate:      dueDate,
-		ParseOptions: a.createParseOptions(fileFormat),
+		ParseOptions: parseOptions,
 		DryRun:       options.DryRun,
 		Format:       fileFormat,
+		Numbering:    invoiceNumbering(config),
+		Currency:     strings.ToUpper(config.Invoice.Currency),
+		TaxRounding:  config.Invoice.TaxRounding,
+		Rounding:     hourlyRounding(config),
+
+		FailOnRejectedRows: !options.SkipErrors,
 	}
 
 	if options.InvoiceNumber != "" {
@@ -299,6 +432,7 @@ func (a *App) executeImportCreate(ctx context.Context, dataFile, configPath stri
 	// Execute import
 	result, err := importService.ImportToNewInvoice(ctx, file, req)
 	if err != nil {
+		a.displayRejectedRows(result, err)
 		return fmt.Errorf("import failed: %w", err)
 	}
 
@@ -311,6 +445,10 @@ func (a *App) executeImportCreate(ctx context.Context, dataFile, configPath stri
 func (a *App) executeImportAppend(ctx context.Context, dataFile, configPath string, options ImportAppendOptions) error {
 	// Detect file format
 	fileFormat := detectFileFormat(dataFile, options.Format)
+	parseOptions, err := a.createParseOptions(fileFormat, options.ParseFlags)
+	if err != nil {
+		return err
+	}
 	a.logger.Info("executing import append", "file", dataFile, "invoice", options.InvoiceID, "format", fileFormat)
 
 	// Load configuration
@@ -318,9 +456,10 @@ func (a *App) executeImportAppend(ctx context.Context, dataFile, configPath stri
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	parseOptions.DefaultRate = config.Invoice.DefaultRate
 
 	// Create import service
-	importService := a.createImportService(config.Storage.DataDir)
+	importService := a.createImportService(config)
 
 	// Open data file
 	file, err := os.Open(dataFile) // #nosec G304 -- User-provided file path is expected in CLI
@@ -334,29 +473,29 @@ func (a *App) executeImportAppend(ctx context.Context, dataFile, configPath stri
 	}()
 
 	// Get invoice by ID or number
-	invoiceService := a.createInvoiceService(config.Storage.DataDir)
+	invoiceService := a.createInvoiceService(config)
 
-	// Try to get invoice by ID first, then by number
-	invoice, err := invoiceService.GetInvoice(ctx, models.InvoiceID(options.InvoiceID))
+	// Try to get invoice by ID first, then by number; invoices in the trash are skipped
+	invoice, err := invoiceService.FindInvoice(ctx, options.InvoiceID, false)
 	if err != nil {
-		// If not found by ID, try by number
-		invoice, err = invoiceService.GetInvoiceByNumber(ctx, options.InvoiceID)
-		if err != nil {
-			return fmt.Errorf("failed to find invoice '%s': %w", options.InvoiceID, err)
-		}
+		return fmt.Errorf("failed to find invoice '%s': %w", options.InvoiceID, err)
 	}
 
 	// Prepare import request using the resolved invoice ID
 	req := services.AppendToInvoiceRequest{
 		InvoiceID:    string(invoice.ID),
-		ParseOptions: a.createParseOptions(fileFormat),
+		ParseOptions: parseOptions,
 		DryRun:       options.DryRun,
 		Format:       fileFormat,
+		Rounding:     hourlyRounding(config),
+
+		FailOnRejectedRows: !options.SkipErrors,
 	}
 
 	// Execute import
 	result, err := importService.AppendToInvoice(ctx, file, req)
 	if err != nil {
+		a.displayRejectedRows(result, err)
 		return fmt.Errorf("import append failed: %w", err)
 	}
 
@@ -369,6 +508,10 @@ func (a *App) executeImportAppend(ctx context.Context, dataFile, configPath stri
 func (a *App) executeImportValidate(ctx context.Context, dataFile, configPath string, options ImportValidateOptions) error {
 	// Detect file format
 	fileFormat := detectFileFormat(dataFile, options.Format)
+	parseOptions, err := a.createParseOptions(fileFormat, options.ParseFlags)
+	if err != nil {
+		return err
+	}
 	a.logger.Info("executing import validation", "file", dataFile, "format", fileFormat)
 
 	// Load configuration
@@ -376,9 +519,10 @@ func (a *App) executeImportValidate(ctx context.Context, dataFile, configPath st
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	parseOptions.DefaultRate = config.Invoice.DefaultRate
 
 	// Create import service
-	importService := a.createImportService(config.Storage.DataDir)
+	importService := a.createImportService(config)
 
 	// Open data file
 	file, err := os.Open(dataFile) // #nosec G304 -- User-provided file path is expected in CLI
@@ -393,7 +537,7 @@ func (a *App) executeImportValidate(ctx context.Context, dataFile, configPath st
 
 	// Prepare validation request
 	req := csv.ValidateImportRequest{
-		Options: a.createParseOptions(fileFormat),
+		Options: parseOptions,
 	}
 
 	// Execute validation
@@ -410,29 +554,48 @@ func (a *App) executeImportValidate(ctx context.Context, dataFile, configPath st
 
 // Helper methods
 
-func (a *App) createImportService(dataDir string) *services.ImportService {
+func (a *App) createImportService(cfg *config.Config) *services.ImportService {
 	// Create storage
-	storage := jsonStorage.NewJSONStorage(dataDir, a.logger)
+	storage := a.newStore(cfg.Storage)
 
 	// Create services with dependency injection
-	invoiceService := services.NewInvoiceService(storage, storage, a.logger, &SimpleIDGenerator{})
-	clientService := services.NewClientService(storage, storage, a.logger, &SimpleIDGenerator{})
+	invoiceService := a.newInvoiceService(cfg, storage, storage)
+	clientService := services.NewClientService(storage, storage, a.logger, a.idGenerator(cfg.Storage))
 
 	// Create CSV components (validator is shared between CSV and JSON parsers)
 	validator := csv.NewWorkItemValidator(a.logger)
-	csvParser := csv.NewCSVParser(validator, a.logger, &SimpleIDGenerator{})
+	csvParser := csv.NewCSVParser(validator, a.logger, csvIDGenerator{gen: a.idGenerator(cfg.Storage), logger: a.logger})
 
 	// Create import service (JSON parser will be created internally)
-	importService := services.NewImportService(csvParser, invoiceService, clientService, validator, a.logger, &SimpleIDGenerator{})
+	importService := services.NewImportService(csvParser, invoiceService, clientService, validator, a.logger, a.idGenerator(cfg.Storage))
 
 	return importService
 }
 
-func (a *App) createParseOptions(format string) csv.ParseOptions {
+func (a *App) createParseOptions(format string, flags ImportParseFlags) (csv.ParseOptions, error) {
+	comma, err := parseDelimiter(flags.Delimiter)
+	if err != nil {
+		return csv.ParseOptions{}, err
+	}
+	headers, err := parseColumnMap(flags.ColumnMap)
+	if err != nil {
+		return csv.ParseOptions{}, err
+	}
+	progress, err := a.newImportProgress(flags.Progress)
+	if err != nil {
+		return csv.ParseOptions{}, err
+	}
+	precision := flags.HoursPrecision
+
 	options := csv.ParseOptions{
-		ContinueOnError: false,
+		ContinueOnError: true, // Collect every rejected row so they can all be reported
 		SkipEmptyRows:   true,
 		Format:          format,
+		Delimiter:       comma,
+		ColumnMap:       headers,
+		HoursFormat:     flags.HoursFormat,
+		HoursPrecision:  &precision,
+		Progress:        progress,
 	}
 
 	// Set default format if not specified
@@ -440,7 +603,7 @@ func (a *App) createParseOptions(format string) csv.ParseOptions {
 		options.Format = "standard"
 	}
 
-	return options
+	return options, nil
 }
 
 func (a *App) displayImportResult(result *csv.ImportResult, isDryRun bool) {
@@ -458,6 +621,13 @@ func (a *App) displayImportResult(result *csv.ImportResult, isDryRun bool) {
 	if result.InvoiceID != "" {
 		a.logger.Printf("Invoice ID: %s\n", result.InvoiceID)
 	}
+	if result.InvoiceTotal > 0 {
+		if isDryRun {
+			a.logger.Printf("Invoice Total (after import): $%.2f\n", result.InvoiceTotal)
+		} else {
+			a.logger.Printf("Invoice Total: $%.2f\n", result.InvoiceTotal)
+		}
+	}
 
 	// Display parsing statistics
 	if result.ParseResult != nil {
@@ -488,6 +658,20 @@ func (a *App) displayImportResult(result *csv.ImportResult, isDryRun bool) {
 	a.logger.Println("")
 }
 
+// displayRejectedRows prints the rows that caused an import to be aborted
+func (a *App) displayRejectedRows(result *csv.ImportResult, err error) {
+	if !errors.Is(err, services.ErrImportRowsRejected) || result == nil || result.ParseResult == nil {
+		return
+	}
+
+	a.logger.Printf("❌ Rejected rows:\n")
+	for _, parseError := range result.ParseResult.Errors {
+		a.logger.Printf("  Line %d: %s\n", parseError.Line, parseError.Message)
+	}
+	a.logger.Println("")
+	a.logger.Println("No work items were imported. Fix the rows above or pass --skip-errors to import the valid rows.")
+}
+
 func (a *App) displayValidationResult(result *csv.ValidationResult) {
 	if result.Valid {
 		a.logger.Println("✅ Validation Passed")
@@ -545,36 +729,43 @@ type ImportCreateOptions struct {
 	DueDate       string
 	DryRun        bool
 	Interactive   bool
+	SkipErrors    bool
 	Format        string
+	ParseFlags    ImportParseFlags
 }
 
 type ImportAppendOptions struct {
 	InvoiceID   string
 	DryRun      bool
 	Interactive bool
+	SkipErrors  bool
 	Format      string
+	ParseFlags  ImportParseFlags
 }
 
 type ImportValidateOptions struct {
-	Format string
-}
-
-// SimpleIDGenerator provides basic ID generation for the import service
-type SimpleIDGenerator struct{}
-
-func (g *SimpleIDGenerator) GenerateInvoiceID(_ context.Context) (models.InvoiceID, error) {
-	return models.InvoiceID(fmt.Sprintf("inv_%d", time.Now().Unix())), nil
+	Format     string
+	ParseFlags ImportParseFlags
 }
 
-func (g *SimpleIDGenerator) GenerateClientID(_ context.Context) (models.ClientID, error) {
-	return models.ClientID(fmt.Sprintf("client_%d", time.Now().Unix())), nil
+// csvIDGenerator adapts the app ID generator to the csv.IDGenerator the timesheet parsers use
+type csvIDGenerator struct {
+	gen    services.IDGenerator
+	logger services.Logger
 }
 
-func (g *SimpleIDGenerator) GenerateWorkItemID(_ context.Context) (string, error) {
-	return fmt.Sprintf("work_%d", time.Now().Unix()), nil
-}
+// GenerateID returns a new work item ID. csv.IDGenerator cannot return an error, so a
+// failure is logged and a random UUID is used instead.
+func (g csvIDGenerator) GenerateID() string {
+	ctx := context.Background()
+	id, err := g.gen.GenerateWorkItemID(ctx)
+	if err == nil {
+		return id
+	}
 
-// GenerateID implements csv.IDGenerator interface
-func (g *SimpleIDGenerator) GenerateID() string {
-	return fmt.Sprintf("work_%d", time.Now().Unix())
+	g.logger.Error("failed to generate work item ID, using a random UUID", "error", err)
+	if id, err = services.NewUUIDGenerator().GenerateWorkItemID(ctx); err != nil {
+		g.logger.Error("failed to generate work item ID", "error", err)
+	}
+	return id
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/csv"
+)
+
+// Import progress modes selected with --progress
+const (
+	importProgressAuto = "auto"
+	importProgressJSON = "json"
+	importProgressNone = "none"
+)
+
+// ErrInvalidProgressMode is returned when an import is run with an unknown --progress mode
+var ErrInvalidProgressMode = fmt.Errorf("invalid progress mode (must be auto, json, or none)")
+
+// terminalProgress redraws a single percentage line as rows are parsed
+type terminalProgress struct {
+	logger  *cli.SimpleLogger
+	percent int
+}
+
+// ReportProgress redraws the progress line when the percentage changes, ending it on the last row
+func (p *terminalProgress) ReportProgress(report csv.ProgressReport) {
+	percent := int(report.Percentage)
+	if percent == p.percent && report.ProcessedRows != report.TotalRows {
+		return
+	}
+	p.percent = percent
+
+	p.logger.Printf("\r⏳ Importing: %3d%% (%d/%d rows)", percent, report.ProcessedRows, report.TotalRows)
+	if report.ProcessedRows == report.TotalRows {
+		p.logger.Printf("\n")
+	}
+}
+
+// jsonProgress writes machine-readable progress lines, read by the MCP executor
+type jsonProgress struct {
+	w io.Writer
+}
+
+// ReportProgress writes report as a progress line
+func (p *jsonProgress) ReportProgress(report csv.ProgressReport) {
+	line, err := csv.FormatProgressLine(report)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintln(p.w, line)
+}
+
+// newImportProgress returns the progress reporter for a --progress mode. In auto mode
+// progress is drawn only on a terminal and never in quiet mode; a nil reporter reports nothing.
+func (a *App) newImportProgress(mode string) (csv.ProgressReporter, error) {
+	switch mode {
+	case "", importProgressAuto:
+		if a.logger.IsQuiet() || !isTerminalOutput() {
+			return nil, nil //nolint:nilnil // No reporter means no progress output
+		}
+		return &terminalProgress{logger: a.logger, percent: -1}, nil
+	case importProgressJSON:
+		return &jsonProgress{w: os.Stderr}, nil
+	case importProgressNone:
+		return nil, nil //nolint:nilnil // No reporter means no progress output
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProgressMode, mode)
+	}
+}
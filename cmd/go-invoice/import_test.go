@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// errIDsExhausted is returned by exhaustedIDGenerator
+var errIDsExhausted = errors.New("no more IDs")
+
+// exhaustedIDGenerator is an ID generator that always fails
+type exhaustedIDGenerator struct{}
+
+func (exhaustedIDGenerator) GenerateInvoiceID(context.Context) (models.InvoiceID, error) {
+	return "", errIDsExhausted
+}
+
+func (exhaustedIDGenerator) GenerateClientID(context.Context) (models.ClientID, error) {
+	return "", errIDsExhausted
+}
+
+func (exhaustedIDGenerator) GenerateWorkItemID(context.Context) (string, error) {
+	return "", errIDsExhausted
+}
+
+func TestCSVIDGenerator(t *testing.T) {
+	gen := csvIDGenerator{gen: exhaustedIDGenerator{}, logger: cli.NewLogger(false)}
+
+	// A failing generator falls back to random UUIDs rather than empty IDs
+	first, second := gen.GenerateID(), gen.GenerateID()
+	assert.Len(t, first, 36)
+	assert.NotEqual(t, first, second)
+}
+
+func TestDetectFileFormat(t *testing.T) {
+	assert.Equal(t, "tsv", detectFileFormat("hours.TSV", "auto"))
+	assert.Equal(t, "tsv", detectFileFormat("hours.tab", ""))
+	assert.Equal(t, "csv", detectFileFormat("hours.csv", "auto"))
+	assert.Equal(t, "json", detectFileFormat("hours.json", "auto"))
+	assert.Equal(t, "csv", detectFileFormat("hours.tsv", "csv"), "explicit format wins")
+}
+
+func TestParseDelimiter(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    rune
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: `\t`, want: '\t'},
+		{value: "tab", want: '\t'},
+		{value: "\t", want: '\t'},
+		{value: ";", want: ';'},
+		{value: "|", want: '|'},
+		{value: "::", wantErr: true},
+		{value: `"`, wantErr: true},
+		{value: "\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseDelimiter(tt.value)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidDelimiter)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseColumnMap(t *testing.T) {
+	columnMap, err := parseColumnMap("task=Description, duration = Hours")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"task": "Description", "duration": "Hours"}, columnMap)
+
+	columnMap, err = parseColumnMap("")
+	require.NoError(t, err)
+	assert.Nil(t, columnMap)
+
+	for _, value := range []string{"task", "task=", "=Description", "task=Description,"} {
+		_, err := parseColumnMap(value)
+		require.ErrorIs(t, err, ErrInvalidColumnMap, value)
+	}
+}
@@ -1,22 +1,27 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"text/template"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/mrz1836/go-invoice/internal/cli"
 	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/encoding"
+	"github.com/mrz1836/go-invoice/internal/encoding/csvout"
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
 	"github.com/mrz1836/go-invoice/internal/services"
 	"github.com/mrz1836/go-invoice/internal/storage"
-	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
 )
 
 // Invoice command errors
@@ -32,24 +37,26 @@ var (
 	ErrNoClientsFound              = fmt.Errorf("no clients found matching")
 	ErrMultipleClientsFound        = fmt.Errorf("multiple clients found matching")
 	ErrHourlyLineItemRequiresFlags = fmt.Errorf("hourly line items require --hours and --rate flags")
+	ErrHourlyLineItemRequiresRate  = fmt.Errorf("hourly line items require --rate when no DEFAULT_RATE is configured")
 	ErrFixedLineItemRequiresAmount = fmt.Errorf("fixed line items require --amount flag")
 	ErrQuantityLineItemRequiresAll = fmt.Errorf("quantity line items require --quantity and --unit-price flags")
 	ErrInvalidLineItemType         = fmt.Errorf("invalid line item type (must be hourly, fixed, or quantity)")
 	ErrEndDateBeforeDate           = fmt.Errorf("end-date cannot be before date")
+	ErrInvoicesSkipped             = fmt.Errorf("invoices could not be read")
 )
 
-// getInvoiceByIDOrNumber is a helper function to get an invoice by ID or number
+// getInvoiceByIDOrNumber is a helper function to get an invoice by ID or number. Invoices
+// in the trash are not found; restore them first.
 func (a *App) getInvoiceByIDOrNumber(ctx context.Context, invoiceService *services.InvoiceService, identifier string) (*models.Invoice, error) {
-	// Try by ID first
-	invoice, err := invoiceService.GetInvoice(ctx, models.InvoiceID(identifier))
-	if err != nil {
-		// If not found by ID, try by number
-		invoice, err = invoiceService.GetInvoiceByNumber(ctx, identifier)
-		if err != nil {
-			return nil, fmt.Errorf("%w: '%s'", models.ErrInvoiceNotFound, identifier)
-		}
+	return invoiceService.FindInvoice(ctx, identifier, false)
+}
+
+// applyDefaultCurrency sets the configured currency on invoices created before per-invoice
+// currency, so that output and reports can read invoice.Currency directly
+func applyDefaultCurrency(cfg *config.Config, invoices ...*models.Invoice) {
+	for _, invoice := range invoices {
+		invoice.Currency = invoice.GetCurrency(cfg.Invoice.Currency)
 	}
-	return invoice, nil
 }
 
 // buildInvoiceCommand creates the invoice command with all subcommands
@@ -64,12 +71,28 @@ func (a *App) buildInvoiceCommand() *cobra.Command {
 
 	// Add invoice subcommands
 	invoiceCmd.AddCommand(a.buildInvoiceCreateCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceCloneCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceExportCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceImportCommand())
 	invoiceCmd.AddCommand(a.buildInvoiceListCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceSearchCommand())
 	invoiceCmd.AddCommand(a.buildInvoiceShowCommand())
 	invoiceCmd.AddCommand(a.buildInvoiceUpdateCommand())
 	invoiceCmd.AddCommand(a.buildInvoiceDeleteCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceRestoreCommand())
 	invoiceCmd.AddCommand(a.buildInvoiceAddLineItemCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceLineItemsCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceUpdateLineItemCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceRemoveLineItemCommand())
 	invoiceCmd.AddCommand(a.buildInvoiceRecalculateCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceSetTaxRateCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceMarkCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceSweepOverdueCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceRemindCommand())
+	invoiceCmd.AddCommand(a.buildInvoicePaymentCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceAttachCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceUnlockCommand())
+	invoiceCmd.AddCommand(a.buildInvoiceLockCommand())
 
 	return invoiceCmd
 }
@@ -89,9 +112,24 @@ If the specified client doesn't exist and --create-client is used, it will be cr
   # Create invoice with specific dates
   go-invoice invoice create --client "Acme Corp" --date 2024-01-15 --due-date 2024-02-15
 
+  # Create invoice with net terms counted from the end of the month (net 30 EOM)
+  go-invoice invoice create --client "Acme Corp" --date 2024-03-10 --terms-anchor eom
+
+  # Bill this invoice in euros instead of the configured currency
+  go-invoice invoice create --client "Acme GmbH" --currency EUR
+
+  # Charge 21% tax on this invoice (rates are decimals, not percentages)
+  go-invoice invoice create --client "Acme GmbH" --tax-rate 0.21
+
+  # Create invoice with the client's purchase order number
+  go-invoice invoice create --client "Acme Corp" --po 4500012345 --client-ref "PRJ-ALPHA"
+
   # Create invoice and client if needed
   go-invoice invoice create --client "New Client" --create-client --email "client@example.com"
 
+  # Preview the number, due date and client without saving anything
+  go-invoice invoice create --client "New Client" --create-client --email "client@example.com" --dry-run
+
   # Interactive mode
   go-invoice invoice create --interactive`,
 		RunE: a.runInvoiceCreate,
@@ -101,6 +139,7 @@ If the specified client doesn't exist and --create-client is used, it will be cr
 	cmd.Flags().String("client", "", "Client name or ID (required unless --interactive)")
 	cmd.Flags().String("date", "", "Invoice date (default: today)")
 	cmd.Flags().String("due-date", "", "Due date (default: based on payment terms)")
+	cmd.Flags().String("terms-anchor", "", "Count payment terms from the invoice date (issue) or end of month (eom) (default from config)")
 	cmd.Flags().String("description", "", "Invoice description")
 	cmd.Flags().Bool("interactive", false, "Interactive mode to prompt for missing information")
 	cmd.Flags().Bool("create-client", false, "Create client if it doesn't exist")
@@ -109,6 +148,12 @@ If the specified client doesn't exist and --create-client is used, it will be cr
 	cmd.Flags().String("phone", "", "Client phone (when creating new client)")
 	cmd.Flags().String("usdc-address", "", "Override USDC address for this invoice (uses global config if not set)")
 	cmd.Flags().String("bsv-address", "", "Override BSV address for this invoice (uses global config if not set)")
+	cmd.Flags().String("template", "", "Template used to generate this invoice (default: client or config template)")
+	cmd.Flags().String("currency", "", "ISO 4217 currency code for this invoice, e.g. EUR (default from config)")
+	cmd.Flags().Float64("tax-rate", 0, "Tax rate for this invoice as a decimal between 0 and 1, e.g. 0.21 for 21%")
+	cmd.Flags().String("po", "", "Client purchase order number, printed on the invoice")
+	cmd.Flags().String("client-ref", "", "Client reference, e.g. a project or cost center code, printed on the invoice")
+	cmd.Flags().Bool("dry-run", false, "Show the invoice (and any new client) that would be created without saving")
 
 	return cmd
 }
@@ -126,9 +171,9 @@ func (a *App) runInvoiceCreate(cmd *cobra.Command, _ []string) error {
 	}
 
 	// Create storage and services
-	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage.DataDir)
-	idGen := services.NewUUIDGenerator()
-	invoiceService := services.NewInvoiceService(invoiceStorage, clientStorage, a.logger, idGen)
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	idGen := a.idGenerator(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
 	clientService := services.NewClientService(clientStorage, invoiceStorage, a.logger, idGen)
 
 	// Get flags
@@ -138,10 +183,26 @@ func (a *App) runInvoiceCreate(cmd *cobra.Command, _ []string) error {
 	description, _ := cmd.Flags().GetString("description")
 	interactive, _ := cmd.Flags().GetBool("interactive")
 	createClient, _ := cmd.Flags().GetBool("create-client")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	termsAnchor, err := resolveTermsAnchor(cmd, config)
+	if err != nil {
+		return err
+	}
+
+	currency, err := resolveInvoiceCurrency(cmd, config)
+	if err != nil {
+		return err
+	}
+
+	taxRate, _ := cmd.Flags().GetFloat64("tax-rate")
+	if taxRate < 0 || taxRate > 1 {
+		return fmt.Errorf("%w: %v (use 0.21 for 21%%)", models.ErrTaxRateOutOfRange, taxRate)
+	}
 
 	// Interactive mode
 	if interactive {
-		return a.runInvoiceCreateInteractive(ctx, invoiceService, clientService, config)
+		return a.runInvoiceCreateInteractive(ctx, invoiceService, clientService, config, termsAnchor, currency, taxRate, dryRun)
 	}
 
 	// Validate required fields
@@ -152,43 +213,49 @@ func (a *App) runInvoiceCreate(cmd *cobra.Command, _ []string) error {
 	// Parse dates
 	invoiceDate := time.Now()
 	if dateStr != "" {
-		parsedDate, parseErr := time.Parse("2006-01-02", dateStr)
+		parsedDate, parseErr := config.Invoice.ParseDate(dateStr)
 		if parseErr != nil {
-			return fmt.Errorf("invalid date format (use YYYY-MM-DD): %w", parseErr)
+			return fmt.Errorf("invalid --date: %w", parseErr)
 		}
 		invoiceDate = parsedDate
 	}
 
-	dueDate := invoiceDate.AddDate(0, 0, config.Invoice.DefaultDueDays)
+	dueDate := models.CalculateDueDate(invoiceDate, config.Invoice.DefaultDueDays, termsAnchor)
 
 	if dueDateStr != "" {
-		parsedDueDate, parseErr := time.Parse("2006-01-02", dueDateStr)
+		parsedDueDate, parseErr := config.Invoice.ParseDate(dueDateStr)
 		if parseErr != nil {
-			return fmt.Errorf("invalid due date format (use YYYY-MM-DD): %w", parseErr)
+			return fmt.Errorf("invalid --due-date: %w", parseErr)
 		}
 		dueDate = parsedDueDate
 	}
 
 	// Find or create client
-	client, err := a.findOrCreateClient(ctx, clientService, clientName, createClient, cmd)
+	client, newClient, err := a.findOrCreateClient(ctx, clientService, clientName, createClient, dryRun, cmd)
 	if err != nil {
 		return err
 	}
-
-	// Generate next invoice number
-	nextNumber := a.generateNextInvoiceNumber(ctx, invoiceService, config.Invoice.Prefix, config.Invoice.StartNumber)
+	a.warnTaxExemptClient(client, taxRate)
 
 	// Get crypto address overrides if provided
 	usdcAddress, _ := cmd.Flags().GetString("usdc-address")
 	bsvAddress, _ := cmd.Flags().GetString("bsv-address")
+	templateName, _ := cmd.Flags().GetString("template")
+	poNumber, _ := cmd.Flags().GetString("po")
+	clientRef, _ := cmd.Flags().GetString("client-ref")
 
 	// Create invoice request
 	req := models.CreateInvoiceRequest{
-		Number:      nextNumber,
-		Date:        invoiceDate,
-		DueDate:     dueDate,
-		ClientID:    client.ID,
-		Description: description,
+		Date:            invoiceDate,
+		DueDate:         dueDate,
+		ClientID:        client.ID,
+		Description:     description,
+		TemplateName:    strings.TrimSpace(templateName),
+		Currency:        currency,
+		TaxRate:         taxRate,
+		TaxRounding:     config.Invoice.TaxRounding,
+		PONumber:        strings.TrimSpace(poNumber),
+		ClientReference: strings.TrimSpace(clientRef),
 	}
 
 	// Add crypto address overrides if provided
@@ -199,24 +266,45 @@ func (a *App) runInvoiceCreate(cmd *cobra.Command, _ []string) error {
 		req.BSVAddress = &bsvAddress
 	}
 
-	invoice, err := invoiceService.CreateInvoice(ctx, req)
+	if dryRun {
+		invoice, previewErr := invoiceService.PreviewInvoiceWithNextNumber(ctx, req, invoiceNumbering(config), client)
+		if previewErr != nil {
+			return fmt.Errorf("failed to preview invoice: %w", previewErr)
+		}
+		a.displayInvoiceCreatePreview(invoice, client, newClient, config.Invoice.DateLayout())
+		return a.logger.ResultJSON(invoice)
+	}
+
+	// Allocate the next invoice number and create the invoice together
+	invoice, err := invoiceService.CreateInvoiceWithNextNumber(ctx, req, invoiceNumbering(config))
 	if err != nil {
 		return fmt.Errorf("failed to create invoice: %w", err)
 	}
 
 	// Display success message
+	a.logger.Result(invoice.Number)
 	a.logger.Printf("✅ Invoice created successfully!\n")
 	a.logger.Printf("   Invoice Number: %s\n", invoice.Number)
 	a.logger.Printf("   Client: %s\n", client.Name)
-	a.logger.Printf("   Date: %s\n", invoice.Date.Format("2006-01-02"))
-	a.logger.Printf("   Due Date: %s\n", invoice.DueDate.Format("2006-01-02"))
+	a.logger.Printf("   Date: %s\n", config.Invoice.FormatDate(invoice.Date))
+	a.logger.Printf("   Due Date: %s\n", config.Invoice.FormatDate(invoice.DueDate))
+	a.logger.Printf("   Currency: %s\n", invoice.Currency)
+	if invoice.TaxRate > 0 {
+		a.logger.Printf("   Tax Rate: %.2f%%\n", invoice.TaxRate*100)
+	}
 	a.logger.Printf("   Status: %s\n", invoice.Status)
+	if invoice.PONumber != "" {
+		a.logger.Printf("   PO Number: %s\n", invoice.PONumber)
+	}
+	if invoice.ClientReference != "" {
+		a.logger.Printf("   Client Reference: %s\n", invoice.ClientReference)
+	}
 	a.logger.Printf("\n")
 	a.logger.Printf("💡 Next steps:\n")
 	a.logger.Printf("   • Import work items: go-invoice import --file hours.csv --invoice %s\n", invoice.ID)
 	a.logger.Printf("   • Generate invoice: go-invoice generate %s\n", invoice.ID)
 
-	return nil
+	return a.logger.ResultJSON(invoice)
 }
 
 // buildInvoiceListCommand creates the invoice list subcommand
@@ -237,24 +325,51 @@ func (a *App) buildInvoiceListCommand() *cobra.Command {
   # Filter by date range
   go-invoice invoice list --from 2024-01-01 --to 2024-12-31
 
+  # Find the invoice for a purchase order
+  go-invoice invoice list --po 4500012345
+
+  # Invoices created or changed since the last sync
+  go-invoice invoice list --changed-since 2024-06-01T00:00:00Z --output json
+
   # Sort by amount descending
   go-invoice invoice list --sort amount --desc
 
   # Output as JSON
-  go-invoice invoice list --output json`,
+  go-invoice invoice list --output json
+
+  # Custom columns with a Go template, one line per invoice
+  go-invoice invoice list --output template --format '{{.Number}} {{.Client.Name}} {{money .Total .Currency}} due {{date .DueDate}}'
+
+  # Outstanding and paid totals per client
+  go-invoice invoice list --group-by client
+
+  # JSON with a summary object alongside the invoices
+  go-invoice invoice list --output json --summary --group-by month
+
+  # Include invoices in the trash
+  go-invoice invoice list --include-deleted
+
+  # Fail instead of skipping invoice files that cannot be read
+  go-invoice invoice list --strict`,
 		RunE: a.runInvoiceList,
 	}
 
 	// Add flags
 	cmd.Flags().String("status", "", "Filter by status (draft, sent, paid, overdue, canceled)")
 	cmd.Flags().String("client", "", "Filter by client name or ID")
-	cmd.Flags().String("from", "", "Filter from date (YYYY-MM-DD)")
-	cmd.Flags().String("to", "", "Filter to date (YYYY-MM-DD)")
-	cmd.Flags().String("sort", "date", "Sort by field (date, amount, status, client)")
+	cmd.Flags().String("from", "", "Filter from date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().String("to", "", "Filter to date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().String("po", "", "Filter by purchase order number (exact match)")
+	cmd.Flags().String("changed-since", "", "Only invoices created or updated after this time (RFC 3339)")
+	cmd.Flags().String("sort", "date", "Sort by field (date, amount, status, client, number)")
 	cmd.Flags().Bool("desc", false, "Sort in descending order")
-	cmd.Flags().String("output", "table", "Output format (table, json, csv)")
+	cmd.Flags().String("output", "table", "Output format (table, json, csv, template)")
+	cmd.Flags().String("format", "", "Go template for --output template, run once per invoice (helpers: money, date, daysUntilDue, upper, lower)")
 	cmd.Flags().Int("limit", 0, "Limit number of results (0 = no limit)")
-	cmd.Flags().Bool("summary", false, "Show summary statistics")
+	cmd.Flags().Bool("summary", false, "Show summary statistics (with json output, adds a summary object)")
+	cmd.Flags().String("group-by", "", "Show subtotals per group (client, status, month)")
+	cmd.Flags().Bool("include-deleted", false, "Include deleted invoices (in the trash)")
+	cmd.Flags().Bool("strict", false, "Fail when an invoice file cannot be read instead of skipping it with a warning")
 
 	return cmd
 }
@@ -264,6 +379,25 @@ func (a *App) runInvoiceList(cmd *cobra.Command, _ []string) error {
 	ctx, cancel := context.WithCancel(cmd.Context())
 	defer cancel()
 
+	// Get output options
+	outputFormat, _ := cmd.Flags().GetString("output")
+	showSummary, _ := cmd.Flags().GetBool("summary")
+	groupBy, _ := cmd.Flags().GetString("group-by")
+	strict, _ := cmd.Flags().GetBool("strict")
+	if err := validateGroupBy(groupBy); err != nil {
+		return err
+	}
+
+	// Parse a custom template up front so a mistake is reported before anything is listed
+	var listTemplate *template.Template
+	if outputFormat == outputTemplate {
+		format, _ := cmd.Flags().GetString("format")
+		var err error
+		if listTemplate, err = parseListTemplate(format); err != nil {
+			return err
+		}
+	}
+
 	// Load configuration
 	configPath, _ := cmd.Flags().GetString("config")
 	config, err := a.configService.LoadConfig(ctx, configPath)
@@ -272,13 +406,13 @@ func (a *App) runInvoiceList(cmd *cobra.Command, _ []string) error {
 	}
 
 	// Create storage and services
-	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage.DataDir)
-	idGen := services.NewUUIDGenerator()
-	invoiceService := services.NewInvoiceService(invoiceStorage, clientStorage, a.logger, idGen)
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	idGen := a.idGenerator(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
 	clientService := services.NewClientService(clientStorage, invoiceStorage, a.logger, idGen)
 
 	// Build filter from flags
-	filter, err := a.buildInvoiceFilter(ctx, cmd, clientService)
+	filter, err := a.buildInvoiceFilter(ctx, cmd, clientService, config)
 	if err != nil {
 		return err
 	}
@@ -288,30 +422,57 @@ func (a *App) runInvoiceList(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to list invoices: %w", err)
 	}
+	if err := a.checkSkippedInvoices(result.Warnings, strict); err != nil {
+		return err
+	}
 	invoices := result.Invoices
 
-	// Get output format
-	outputFormat, _ := cmd.Flags().GetString("output")
-	showSummary, _ := cmd.Flags().GetBool("summary")
+	applyDefaultCurrency(config, invoices...)
 
 	// Display results based on format
 	switch outputFormat {
 	case "json":
+		if showSummary || groupBy != "" {
+			return a.outputInvoicesJSONWithSummary(invoices, buildInvoiceSummary(invoices, groupBy))
+		}
 		return a.outputInvoicesJSON(invoices)
 	case "csv":
-		a.outputInvoicesCSV(invoices)
-		return nil
+		return a.outputInvoicesCSV(invoices, config.Invoice.DateLayout())
+	case outputTemplate:
+		return a.outputInvoicesTemplate(listTemplate, invoices)
 	default:
-		if err := a.outputInvoicesTable(ctx, invoices, clientService); err != nil {
+		if err := a.outputInvoicesTable(ctx, invoices, clientService, config.Invoice.DateLayout()); err != nil {
 			return err
 		}
+		if filter.Limit > 0 && len(invoices) > 0 {
+			a.logger.Printf("\nPage %d of %d (%d invoices)\n", result.CurrentPage, result.TotalPages, result.TotalCount)
+		}
+		summary := buildInvoiceSummary(invoices, groupBy)
+		if groupBy != "" {
+			a.displayInvoiceGroups(summary)
+		}
 		if showSummary {
-			a.displayInvoiceSummary(invoices, config.Invoice.Currency)
+			a.displayInvoiceSummary(summary)
 		}
 		return nil
 	}
 }
 
+// checkSkippedInvoices reports the invoices a list skipped because their files could not
+// be read: as an error in strict mode, otherwise as warnings so the list still shows
+func (a *App) checkSkippedInvoices(warnings []string, strict bool) error {
+	if len(warnings) == 0 {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("%w: %s", ErrInvoicesSkipped, strings.Join(warnings, "; "))
+	}
+	for _, warning := range warnings {
+		a.logger.Warn("invoice skipped", "reason", warning)
+	}
+	return nil
+}
+
 // buildInvoiceShowCommand creates the invoice show subcommand
 func (a *App) buildInvoiceShowCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -326,14 +487,17 @@ func (a *App) buildInvoiceShowCommand() *cobra.Command {
   go-invoice invoice show INV-001 --output json
 
   # Show with work items
-  go-invoice invoice show INV-001 --show-items`,
+  go-invoice invoice show INV-001 --show-items
+
+  # Show when the invoice was created, sent, paid and changed
+  go-invoice invoice show INV-001 --show-history`,
 		RunE: a.runInvoiceShow,
 	}
 
 	// Add flags
 	cmd.Flags().String("output", "text", "Output format (text, json, yaml)")
 	cmd.Flags().Bool("show-items", false, "Show detailed work items")
-	cmd.Flags().Bool("show-history", false, "Show status history")
+	cmd.Flags().Bool("show-history", false, "Show the history of changes to the invoice")
 
 	return cmd
 }
@@ -353,9 +517,9 @@ func (a *App) runInvoiceShow(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create storage and services
-	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage.DataDir)
-	idGen := services.NewUUIDGenerator()
-	invoiceService := services.NewInvoiceService(invoiceStorage, clientStorage, a.logger, idGen)
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	idGen := a.idGenerator(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
 	clientService := services.NewClientService(clientStorage, invoiceStorage, a.logger, idGen)
 
 	// Get invoice - try by ID first, then by number
@@ -370,6 +534,8 @@ func (a *App) runInvoiceShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get client: %w", err)
 	}
 
+	applyDefaultCurrency(config, invoice)
+
 	// Get output format
 	outputFormat, _ := cmd.Flags().GetString("output")
 	showItems, _ := cmd.Flags().GetBool("show-items")
@@ -384,15 +550,13 @@ func (a *App) runInvoiceShow(cmd *cobra.Command, args []string) error {
 		}
 		a.logger.Println(string(data))
 	case "yaml":
-		// For now, we'll use JSON format for YAML output
-		// In a real implementation, we would use a YAML library
-		data, err := json.MarshalIndent(invoice, "", "  ")
+		data, err := encoding.MarshalYAML(invoice)
 		if err != nil {
 			return fmt.Errorf("failed to marshal invoice: %w", err)
 		}
-		a.logger.Println(string(data))
+		a.logger.Printf("%s", data)
 	default:
-		a.displayInvoiceDetails(invoice, client, config.Invoice.Currency, showItems, showHistory)
+		a.displayInvoiceDetails(invoice, client, invoice.Currency, config.Invoice.DateLayout(), showItems, showHistory)
 	}
 
 	return nil
@@ -417,6 +581,14 @@ Work items should be managed through the import command.`,
   # Update description
   go-invoice invoice update INV-001 --description "January consulting services"
 
+  # Add a note to the invoice footer, or clear it
+  go-invoice invoice update INV-001 --notes "PO #4521 - please reference on payment"
+  go-invoice invoice update INV-001 --notes ""
+
+  # Apply a 10% invoice discount, or remove it again
+  go-invoice invoice update INV-001 --discount 10%
+  go-invoice invoice update INV-001 --discount none
+
   # Interactive update
   go-invoice invoice update INV-001 --interactive`,
 		RunE: a.runInvoiceUpdate,
@@ -424,15 +596,20 @@ Work items should be managed through the import command.`,
 
 	// Add flags
 	cmd.Flags().String("status", "", "Update status (draft, sent, paid, overdue, canceled)")
-	cmd.Flags().String("date", "", "Update invoice date (YYYY-MM-DD)")
-	cmd.Flags().String("due-date", "", "Update due date (YYYY-MM-DD)")
+	cmd.Flags().String("date", "", "Update invoice date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().String("due-date", "", "Update due date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().String("terms-anchor", "", "Anchor for recalculating the due date when --date changes: issue or eom (default from config)")
 	cmd.Flags().String("description", "", "Update description")
-	cmd.Flags().String("notes", "", "Update internal notes")
+	cmd.Flags().String("notes", "", "Set notes shown in the invoice footer (empty to clear)")
+	cmd.Flags().String("po", "", "Set the client purchase order number (empty to clear)")
+	cmd.Flags().String("client-ref", "", "Set the client reference (empty to clear)")
 	cmd.Flags().Bool("interactive", false, "Interactive mode to select fields to update")
 	cmd.Flags().String("usdc-address", "", "Override USDC address for this invoice")
 	cmd.Flags().String("bsv-address", "", "Override BSV address for this invoice")
 	cmd.Flags().Bool("clear-usdc-address", false, "Clear USDC address override (use global config)")
 	cmd.Flags().Bool("clear-bsv-address", false, "Clear BSV address override (use global config)")
+	cmd.Flags().String("template", "", "Set the template used to generate this invoice (empty to use the client or config template)")
+	cmd.Flags().String("discount", "", "Set the invoice discount, a percentage (10%) or a fixed amount (25.00); none removes it")
 
 	return cmd
 }
@@ -452,7 +629,7 @@ func (a *App) runInvoiceUpdate(cmd *cobra.Command, args []string) error {
 
 	// Check if interactive mode
 	if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
-		return a.runInvoiceUpdateInteractive(ctx, invoiceService, invoice)
+		return a.runInvoiceUpdateInteractive(ctx, invoiceService, invoice, config.Invoice.DateLayout())
 	}
 
 	// Build update request - use the actual invoice ID from the retrieved invoice
@@ -466,7 +643,7 @@ func (a *App) runInvoiceUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Perform update and display results
-	return a.executeUpdateAndDisplay(ctx, invoiceService, invoice, req)
+	return a.executeUpdateAndDisplay(ctx, invoiceService, invoice, req, config.Invoice.DateLayout())
 }
 
 // setupUpdateCommand sets up the invoice service and validates the invoice
@@ -479,9 +656,9 @@ func (a *App) setupUpdateCommand(ctx context.Context, cmd *cobra.Command, invoic
 	}
 
 	// Create storage and services
-	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage.DataDir)
-	idGen := services.NewUUIDGenerator()
-	invoiceService := services.NewInvoiceService(invoiceStorage, clientStorage, a.logger, idGen)
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
+	invoiceService.SetNotifier(a.newNotifier(config.Webhook))
 
 	// Get current invoice - try by ID first, then by number
 	invoice, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, invoiceID)
@@ -516,7 +693,7 @@ func (a *App) buildUpdateRequest(cmd *cobra.Command, invoiceID string, cfg *conf
 
 	// Update invoice date
 	if dateStr, _ := cmd.Flags().GetString("date"); dateStr != "" {
-		if err := a.validateAndSetInvoiceDate(&req, dateStr); err != nil {
+		if err := a.validateAndSetInvoiceDate(&req, dateStr, cfg); err != nil {
 			return req, false, err
 		}
 		hasUpdates = true
@@ -527,7 +704,7 @@ func (a *App) buildUpdateRequest(cmd *cobra.Command, invoiceID string, cfg *conf
 	dueDateStr, _ := cmd.Flags().GetString("due-date")
 	if dueDateStr != "" {
 		// User explicitly set due date, use it
-		if err := a.validateAndSetDueDate(&req, dueDateStr); err != nil {
+		if err := a.validateAndSetDueDate(&req, dueDateStr, cfg); err != nil {
 			return req, false, err
 		}
 		hasUpdates = true
@@ -538,9 +715,17 @@ func (a *App) buildUpdateRequest(cmd *cobra.Command, invoiceID string, cfg *conf
 		if dueDays == 0 {
 			dueDays = 30 // Default to 30 days if not configured
 		}
-		newDueDate := req.Date.AddDate(0, 0, dueDays)
+		termsAnchor, err := resolveTermsAnchor(cmd, cfg)
+		if err != nil {
+			return req, false, err
+		}
+		newDueDate := models.CalculateDueDate(*req.Date, dueDays, termsAnchor)
 		req.DueDate = &newDueDate
-		a.logger.Printf("   Note: Due date automatically adjusted to %d days from invoice date\n", dueDays)
+		if termsAnchor == models.TermsAnchorEndOfMonth {
+			a.logger.Printf("   Note: Due date automatically adjusted to net %d EOM (%s)\n", dueDays, cfg.Invoice.FormatDate(newDueDate))
+		} else {
+			a.logger.Printf("   Note: Due date automatically adjusted to %d days from invoice date\n", dueDays)
+		}
 	}
 
 	// Update description
@@ -575,14 +760,100 @@ func (a *App) buildUpdateRequest(cmd *cobra.Command, invoiceID string, cfg *conf
 		a.logger.Printf("   Note: BSV address override will be cleared (will use global config)\n")
 	}
 
-	// Handle notes (not yet supported)
-	if notes, _ := cmd.Flags().GetString("notes"); notes != "" {
-		a.logger.Debug("notes update not yet supported", "notes", notes)
+	// Update template override
+	if cmd.Flags().Changed("template") {
+		templateName, _ := cmd.Flags().GetString("template")
+		templateName = strings.TrimSpace(templateName)
+		req.TemplateName = &templateName
+		hasUpdates = true
+	}
+
+	// Update invoice discount
+	if cmd.Flags().Changed("discount") {
+		discountStr, _ := cmd.Flags().GetString("discount")
+		if err := setUpdateDiscount(&req, discountStr); err != nil {
+			return req, false, err
+		}
+		hasUpdates = true
+	}
+
+	// Update notes and client references; an empty value clears them
+	if notes := changedStringFlag(cmd, "notes"); notes != nil {
+		req.Notes = notes
+		hasUpdates = true
+	}
+	if poNumber := changedStringFlag(cmd, "po"); poNumber != nil {
+		req.PONumber = poNumber
+		hasUpdates = true
+	}
+	if clientRef := changedStringFlag(cmd, "client-ref"); clientRef != nil {
+		req.ClientReference = clientRef
+		hasUpdates = true
 	}
 
 	return req, hasUpdates, nil
 }
 
+// warnTaxExemptClient warns that a tax-exempt client will not be charged the invoice's tax rate
+func (a *App) warnTaxExemptClient(client *models.Client, taxRate float64) {
+	if !client.TaxExempt || taxRate <= 0 {
+		return
+	}
+	a.logger.Printf("⚠️  %s is tax exempt%s: the %.1f%% tax rate will not be charged on this invoice\n",
+		client.Name, parenthesize(client.ExemptionReason), taxRate*100)
+}
+
+// parenthesize returns " (text)", or an empty string when text is empty
+func parenthesize(text string) string {
+	if text == "" {
+		return ""
+	}
+	return " (" + text + ")"
+}
+
+// changedStringFlag returns the trimmed value of a string flag, or nil when the flag was not
+// given, so an explicitly empty value can clear a field
+func changedStringFlag(cmd *cobra.Command, name string) *string {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+	value, _ := cmd.Flags().GetString(name)
+	value = strings.TrimSpace(value)
+	return &value
+}
+
+// setUpdateDiscount sets the invoice discount from a flag value. A percentage replaces any fixed
+// amount and vice versa; "none", "0" or an empty value removes the discount.
+func setUpdateDiscount(req *models.UpdateInvoiceRequest, value string) error {
+	percent, amount := 0.0, 0.0
+
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "none", "0", "0%":
+	default:
+		discount, err := models.ParseDiscount(value)
+		if err != nil {
+			return err
+		}
+		if discount.Type == models.DiscountTypePercent {
+			percent = discount.Value
+		} else {
+			amount = discount.Value
+		}
+	}
+
+	req.DiscountPercent = &percent
+	req.DiscountAmount = &amount
+	return nil
+}
+
+// invoiceDiscountLabel describes a percentage invoice discount for display, e.g. " (10%)"
+func invoiceDiscountLabel(invoice *models.Invoice) string {
+	if invoice.DiscountPercent == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s%%)", strconv.FormatFloat(invoice.DiscountPercent, 'f', -1, 64))
+}
+
 // validateAndSetStatus validates and sets the status in the update request
 func (a *App) validateAndSetStatus(req *models.UpdateInvoiceRequest, status string) error {
 	validStatuses := []string{"draft", "sent", "paid", "overdue", "voided"}
@@ -598,27 +869,45 @@ func (a *App) validateAndSetStatus(req *models.UpdateInvoiceRequest, status stri
 }
 
 // validateAndSetInvoiceDate validates and sets the invoice date in the update request
-func (a *App) validateAndSetInvoiceDate(req *models.UpdateInvoiceRequest, dateStr string) error {
-	invoiceDate, err := time.Parse("2006-01-02", dateStr)
+func (a *App) validateAndSetInvoiceDate(req *models.UpdateInvoiceRequest, dateStr string, cfg *config.Config) error {
+	invoiceDate, err := cfg.Invoice.ParseDate(dateStr)
 	if err != nil {
-		return fmt.Errorf("invalid invoice date format (use YYYY-MM-DD): %w", err)
+		return fmt.Errorf("invalid --date: %w", err)
 	}
 	req.Date = &invoiceDate
 	return nil
 }
 
+// resolveTermsAnchor returns the terms anchor from the --terms-anchor flag, falling back to the config default
+func resolveTermsAnchor(cmd *cobra.Command, cfg *config.Config) (models.TermsAnchor, error) {
+	value := cfg.Invoice.TermsAnchor
+	if flagValue, _ := cmd.Flags().GetString("terms-anchor"); flagValue != "" {
+		value = flagValue
+	}
+	return models.ParseTermsAnchor(value)
+}
+
+// resolveInvoiceCurrency returns the --currency flag value, falling back to the configured currency
+func resolveInvoiceCurrency(cmd *cobra.Command, cfg *config.Config) (string, error) {
+	value := cfg.Invoice.Currency
+	if flagValue, _ := cmd.Flags().GetString("currency"); flagValue != "" {
+		value = flagValue
+	}
+	return models.NormalizeCurrency(value)
+}
+
 // validateAndSetDueDate validates and sets the due date in the update request
-func (a *App) validateAndSetDueDate(req *models.UpdateInvoiceRequest, dueDateStr string) error {
-	dueDate, err := time.Parse("2006-01-02", dueDateStr)
+func (a *App) validateAndSetDueDate(req *models.UpdateInvoiceRequest, dueDateStr string, cfg *config.Config) error {
+	dueDate, err := cfg.Invoice.ParseDate(dueDateStr)
 	if err != nil {
-		return fmt.Errorf("invalid due date format (use YYYY-MM-DD): %w", err)
+		return fmt.Errorf("invalid --due-date: %w", err)
 	}
 	req.DueDate = &dueDate
 	return nil
 }
 
 // executeUpdateAndDisplay performs the update and displays results
-func (a *App) executeUpdateAndDisplay(ctx context.Context, invoiceService *services.InvoiceService, originalInvoice *models.Invoice, req models.UpdateInvoiceRequest) error {
+func (a *App) executeUpdateAndDisplay(ctx context.Context, invoiceService *services.InvoiceService, originalInvoice *models.Invoice, req models.UpdateInvoiceRequest, dateLayout string) error {
 	// Perform update
 	updatedInvoice, err := invoiceService.UpdateInvoice(ctx, req)
 	if err != nil {
@@ -626,12 +915,12 @@ func (a *App) executeUpdateAndDisplay(ctx context.Context, invoiceService *servi
 	}
 
 	// Display success message
-	a.displayUpdateResults(originalInvoice, updatedInvoice, req)
-	return nil
+	a.displayUpdateResults(originalInvoice, updatedInvoice, req, dateLayout)
+	return a.logger.ResultJSON(updatedInvoice)
 }
 
 // displayUpdateResults displays the update results to the user
-func (a *App) displayUpdateResults(original, updated *models.Invoice, req models.UpdateInvoiceRequest) {
+func (a *App) displayUpdateResults(original, updated *models.Invoice, req models.UpdateInvoiceRequest, dateLayout string) {
 	a.logger.Printf("✅ Invoice updated successfully!\n")
 	a.logger.Printf("   Invoice Number: %s\n", updated.Number)
 
@@ -641,19 +930,51 @@ func (a *App) displayUpdateResults(original, updated *models.Invoice, req models
 
 	if req.Date != nil {
 		a.logger.Printf("   Invoice Date: %s → %s\n",
-			original.Date.Format("2006-01-02"),
-			updated.Date.Format("2006-01-02"))
+			original.Date.Format(dateLayout),
+			updated.Date.Format(dateLayout))
 	}
 
 	if req.DueDate != nil {
 		a.logger.Printf("   Due Date: %s → %s\n",
-			original.DueDate.Format("2006-01-02"),
-			updated.DueDate.Format("2006-01-02"))
+			original.DueDate.Format(dateLayout),
+			updated.DueDate.Format(dateLayout))
 	}
 
 	if req.Description != nil {
 		a.logger.Printf("   Description updated\n")
 	}
+
+	if req.PONumber != nil {
+		a.logger.Printf("   PO Number: %q → %q\n", original.PONumber, updated.PONumber)
+	}
+
+	if req.ClientReference != nil {
+		a.logger.Printf("   Client Reference: %q → %q\n", original.ClientReference, updated.ClientReference)
+	}
+
+	if req.Notes != nil {
+		if updated.Notes == "" {
+			a.logger.Printf("   Notes cleared\n")
+		} else {
+			a.logger.Printf("   Notes updated\n")
+		}
+	}
+
+	if req.DiscountPercent != nil || req.DiscountAmount != nil {
+		if updated.DiscountTotal == 0 {
+			a.logger.Printf("   Discount: removed\n")
+		} else {
+			a.logger.Printf("   Discount%s: -%s (total %s)\n", invoiceDiscountLabel(updated), updated.DiscountTotal, updated.Total)
+		}
+	}
+
+	if req.TemplateName != nil {
+		if updated.TemplateName == "" {
+			a.logger.Printf("   Template: cleared (uses client or config template)\n")
+		} else {
+			a.logger.Printf("   Template: %s\n", updated.TemplateName)
+		}
+	}
 }
 
 // buildInvoiceDeleteCommand creates the invoice delete subcommand
@@ -663,8 +984,10 @@ func (a *App) buildInvoiceDeleteCommand() *cobra.Command {
 		Short: "Delete an invoice",
 		Long: `Delete an invoice from the system.
 
-By default, this performs a soft delete (marks as deleted but retains data).
-Use --hard to permanently remove the invoice and all associated data.
+By default, this performs a soft delete: the invoice is moved to the trash,
+hidden from 'invoice list' and kept on disk so it can be brought back with
+'invoice restore'. Use --hard to permanently remove the invoice, including
+one that is already in the trash.
 
 Note: You cannot delete invoices that are paid or have associated transactions.`,
 		Args: cobra.ExactArgs(1),
@@ -701,12 +1024,16 @@ func (a *App) runInvoiceDelete(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create storage and services
-	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage.DataDir)
-	idGen := services.NewUUIDGenerator()
-	invoiceService := services.NewInvoiceService(invoiceStorage, clientStorage, a.logger, idGen)
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
 
-	// Get invoice to verify it exists and check status - try by ID first, then by number
-	invoice, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, invoiceID)
+	// Get flags
+	hardDelete, _ := cmd.Flags().GetBool("hard")
+	force, _ := cmd.Flags().GetBool("force")
+
+	// Get invoice to verify it exists and check status - try by ID first, then by number.
+	// Invoices in the trash can still be permanently deleted.
+	invoice, err := invoiceService.FindInvoice(ctx, invoiceID, true)
 	if err != nil {
 		return fmt.Errorf("failed to get invoice: %w", err)
 	}
@@ -715,13 +1042,9 @@ func (a *App) runInvoiceDelete(cmd *cobra.Command, args []string) error {
 	if invoice.Status == models.StatusPaid {
 		return ErrCannotDeletePaidInvoice
 	}
-
-	// Get storage for hard delete
-	invoiceStorage, _ = a.createStorageInstances(config.Storage.DataDir)
-
-	// Get flags
-	hardDelete, _ := cmd.Flags().GetBool("hard")
-	force, _ := cmd.Flags().GetBool("force")
+	if invoice.IsDeleted() && !hardDelete {
+		return fmt.Errorf("%w: %s (use --hard to remove it permanently)", models.ErrInvoiceAlreadyDeleted, invoice.Number)
+	}
 
 	// Confirmation prompt
 	if !force {
@@ -732,8 +1055,8 @@ func (a *App) runInvoiceDelete(cmd *cobra.Command, args []string) error {
 
 		a.logger.Printf("⚠️  About to %s invoice %s\n", deleteType, invoice.Number)
 		a.logger.Printf("   Client: %s\n", invoice.Client.Name)
-		a.logger.Printf("   Date: %s\n", invoice.Date.Format("2006-01-02"))
-		a.logger.Printf("   Total: %.2f\n", invoice.Total)
+		a.logger.Printf("   Date: %s\n", config.Invoice.FormatDate(invoice.Date))
+		a.logger.Printf("   Total: %s\n", money.Format(invoice.Total.Float64(), invoice.GetCurrency(config.Invoice.Currency)))
 		a.logger.Printf("\n")
 
 		if hardDelete {
@@ -760,49 +1083,101 @@ func (a *App) runInvoiceDelete(cmd *cobra.Command, args []string) error {
 
 	// Perform delete
 	if hardDelete {
-		// For hard delete, we'll delete from storage directly
-		err = invoiceStorage.DeleteInvoice(ctx, models.InvoiceID(invoiceID))
+		err = invoiceService.HardDeleteInvoice(ctx, invoice.ID)
 		if err != nil {
 			return fmt.Errorf("failed to delete invoice: %w", err)
 		}
 		a.logger.Printf("✅ Invoice %s permanently deleted\n", invoice.Number)
-	} else {
-		err = invoiceService.DeleteInvoice(ctx, models.InvoiceID(invoiceID))
-		if err != nil {
-			return fmt.Errorf("failed to delete invoice: %w", err)
-		}
-		a.logger.Printf("✅ Invoice %s deleted\n", invoice.Number)
+		// The invoice is gone, so report it as it was before the delete
+		return a.logger.ResultJSON(invoice)
+	}
+
+	err = invoiceService.DeleteInvoice(ctx, invoice.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete invoice: %w", err)
+	}
+	a.logger.Printf("🗑️  Invoice %s moved to trash\n", invoice.Number)
+	a.logger.Printf("   Restore it with: go-invoice invoice restore %s\n", invoice.Number)
+
+	if !a.logger.IsJSON() {
+		return nil
+	}
+	deleted, err := invoiceService.FindInvoice(ctx, string(invoice.ID), true)
+	if err != nil {
+		return fmt.Errorf("failed to get deleted invoice: %w", err)
+	}
+	return a.logger.ResultJSON(deleted)
+}
+
+// buildInvoiceRestoreCommand creates the invoice restore subcommand
+func (a *App) buildInvoiceRestoreCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [invoice-id]",
+		Short: "Restore a deleted invoice from the trash",
+		Long: `Restore an invoice that was soft deleted with 'invoice delete'.
+
+The invoice is listed again by 'invoice list'. Invoices removed with --hard
+cannot be restored.`,
+		Args: cobra.ExactArgs(1),
+		Example: `  # Restore a deleted invoice
+  go-invoice invoice restore INV-001
+
+  # Find deleted invoices first
+  go-invoice invoice list --include-deleted`,
+		RunE: a.runInvoiceRestore,
 	}
 
+	return cmd
+}
+
+// runInvoiceRestore handles the invoice restore command
+func (a *App) runInvoiceRestore(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	invoiceService := a.createInvoiceService(config)
+
+	invoice, err := invoiceService.RestoreInvoice(ctx, models.InvoiceID(args[0]))
+	if err != nil {
+		return fmt.Errorf("failed to restore invoice: %w", err)
+	}
+
+	a.logger.Printf("✅ Invoice %s restored\n", invoice.Number)
 	return nil
 }
 
 // Helper methods
 
 // createStorageInstances creates invoice and client storage instances
-func (a *App) createStorageInstances(dataDir string) (storage.InvoiceStorage, storage.ClientStorage) {
-	// Use a.logger directly - it satisfies the Logger interface
-	jsonStore := jsonStorage.NewJSONStorage(dataDir, a.logger)
-	return jsonStore, jsonStore
+func (a *App) createStorageInstances(storageConfig config.StorageConfig) (storage.InvoiceStorage, storage.ClientStorage) {
+	store := a.newStore(storageConfig)
+	return store, store
 }
 
-// findOrCreateClient finds an existing client or creates a new one if allowed
-func (a *App) findOrCreateClient(ctx context.Context, clientService *services.ClientService, clientName string, createIfMissing bool, cmd *cobra.Command) (*models.Client, error) {
+// findOrCreateClient finds an existing client or creates a new one if allowed. In a dry
+// run the new client is validated and built but not saved. The bool result reports
+// whether the client is new.
+func (a *App) findOrCreateClient(ctx context.Context, clientService *services.ClientService, clientName string, createIfMissing, dryRun bool, cmd *cobra.Command) (*models.Client, bool, error) {
 	// Try to find existing client
-	clients, err := a.searchClientsByName(ctx, clientService, clientName)
+	clients, err := a.searchClients(ctx, clientService, clientName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search for client: %w", err)
+		return nil, false, fmt.Errorf("failed to search for client: %w", err)
 	}
 
-	// If found, return the first exact match or the only result
-	for _, client := range clients {
-		if strings.EqualFold(client.Name, clientName) {
-			return client, nil
-		}
+	// If found, return the exact match or the only result
+	if client := exactClientMatch(clients, clientName); client != nil {
+		return client, false, nil
 	}
 
 	if len(clients) == 1 {
-		return clients[0], nil
+		return clients[0], false, nil
 	}
 
 	if len(clients) > 1 {
@@ -811,18 +1186,18 @@ func (a *App) findOrCreateClient(ctx context.Context, clientService *services.Cl
 		for i, client := range clients {
 			a.logger.Printf("  %d. %s (%s)\n", i+1, client.Name, client.Email)
 		}
-		return nil, ErrSpecifyMoreSpecific
+		return nil, false, ErrSpecifyMoreSpecific
 	}
 
 	// No client found
 	if !createIfMissing {
-		return nil, fmt.Errorf("%w '%s' (use --create-client to create)", ErrClientNotFound, clientName)
+		return nil, false, fmt.Errorf("%w '%s' (use --create-client to create)", ErrClientNotFound, clientName)
 	}
 
 	// Create new client
 	email, _ := cmd.Flags().GetString("email")
 	if email == "" {
-		return nil, ErrEmailRequiredForNewClient
+		return nil, false, ErrEmailRequiredForNewClient
 	}
 
 	address, _ := cmd.Flags().GetString("address")
@@ -835,17 +1210,25 @@ func (a *App) findOrCreateClient(ctx context.Context, clientService *services.Cl
 		Phone:   phone,
 	}
 
+	if dryRun {
+		client, previewErr := clientService.PreviewClient(ctx, req)
+		if previewErr != nil {
+			return nil, false, fmt.Errorf("failed to create client: %w", previewErr)
+		}
+		return client, true, nil
+	}
+
 	client, err := clientService.CreateClient(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client: %w", err)
+		return nil, false, fmt.Errorf("failed to create client: %w", err)
 	}
 
 	a.logger.Printf("✅ Created new client: %s\n", client.Name)
-	return client, nil
+	return client, true, nil
 }
 
 // buildInvoiceFilter builds an invoice filter from command flags
-func (a *App) buildInvoiceFilter(ctx context.Context, cmd *cobra.Command, clientService *services.ClientService) (models.InvoiceFilter, error) {
+func (a *App) buildInvoiceFilter(ctx context.Context, cmd *cobra.Command, clientService *services.ClientService, cfg *config.Config) (models.InvoiceFilter, error) {
 	filter := models.InvoiceFilter{}
 
 	// Build status filter
@@ -859,13 +1242,27 @@ func (a *App) buildInvoiceFilter(ctx context.Context, cmd *cobra.Command, client
 	}
 
 	// Build date range filter
-	if err := a.buildDateRangeFilter(cmd, &filter); err != nil {
+	if err := a.buildDateRangeFilter(cmd, &filter, cfg); err != nil {
 		return filter, err
 	}
 
+	// Build changed-since filter
+	if err := a.buildChangedSinceFilter(cmd, &filter); err != nil {
+		return filter, err
+	}
+
+	// Build purchase order filter
+	poNumber, _ := cmd.Flags().GetString("po")
+	filter.PONumber = strings.TrimSpace(poNumber)
+
+	// Build sort order
+	a.buildSortFilter(cmd, &filter)
+
 	// Build limit filter
 	a.buildLimitFilter(cmd, &filter)
 
+	filter.IncludeDeleted, _ = cmd.Flags().GetBool("include-deleted")
+
 	return filter, nil
 }
 
@@ -894,11 +1291,16 @@ func (a *App) buildClientFilter(ctx context.Context, cmd *cobra.Command, clientS
 		return nil
 	}
 
-	clients, err := a.searchClientsByName(ctx, clientService, clientName)
+	clients, err := a.searchClients(ctx, clientService, clientName)
 	if err != nil {
 		return fmt.Errorf("failed to search for client: %w", err)
 	}
 
+	if client := exactClientMatch(clients, clientName); client != nil {
+		filter.ClientID = client.ID
+		return nil
+	}
+
 	if len(clients) == 0 {
 		return fmt.Errorf("%w: %s", ErrNoClientsFound, clientName)
 	}
@@ -911,22 +1313,23 @@ func (a *App) buildClientFilter(ctx context.Context, cmd *cobra.Command, clientS
 	return nil
 }
 
-// buildDateRangeFilter builds the date range filter from command flags
-func (a *App) buildDateRangeFilter(cmd *cobra.Command, filter *models.InvoiceFilter) error {
+// buildDateRangeFilter builds the date range filter from the --from and --to flags, given
+// in the configured date format or ISO 8601
+func (a *App) buildDateRangeFilter(cmd *cobra.Command, filter *models.InvoiceFilter, cfg *config.Config) error {
 	// From date
 	if fromStr, _ := cmd.Flags().GetString("from"); fromStr != "" {
-		fromDate, err := time.Parse("2006-01-02", fromStr)
+		fromDate, err := cfg.Invoice.ParseDate(fromStr)
 		if err != nil {
-			return fmt.Errorf("invalid from date format (use YYYY-MM-DD): %w", err)
+			return fmt.Errorf("invalid --from: %w", err)
 		}
 		filter.DateFrom = fromDate
 	}
 
 	// To date
 	if toStr, _ := cmd.Flags().GetString("to"); toStr != "" {
-		toDate, err := time.Parse("2006-01-02", toStr)
+		toDate, err := cfg.Invoice.ParseDate(toStr)
 		if err != nil {
-			return fmt.Errorf("invalid to date format (use YYYY-MM-DD): %w", err)
+			return fmt.Errorf("invalid --to: %w", err)
 		}
 		// Set to end of day
 		toDate = toDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
@@ -936,6 +1339,32 @@ func (a *App) buildDateRangeFilter(cmd *cobra.Command, filter *models.InvoiceFil
 	return nil
 }
 
+// buildChangedSinceFilter builds the updated-after filter from the --changed-since flag
+func (a *App) buildChangedSinceFilter(cmd *cobra.Command, filter *models.InvoiceFilter) error {
+	sinceStr, _ := cmd.Flags().GetString("changed-since")
+	if sinceStr == "" {
+		return nil
+	}
+
+	since, err := time.Parse(time.RFC3339, sinceStr)
+	if err != nil {
+		return fmt.Errorf("invalid changed-since time (use RFC 3339, e.g. 2024-06-01T00:00:00Z): %w", err)
+	}
+	filter.UpdatedAfter = since
+	return nil
+}
+
+// buildSortFilter builds the sort order from command flags. Without --sort or --desc the
+// storage default, newest invoices first, is kept.
+func (a *App) buildSortFilter(cmd *cobra.Command, filter *models.InvoiceFilter) {
+	if !cmd.Flags().Changed("sort") && !cmd.Flags().Changed("desc") {
+		return
+	}
+
+	filter.SortBy, _ = cmd.Flags().GetString("sort")
+	filter.SortDesc, _ = cmd.Flags().GetBool("desc")
+}
+
 // buildLimitFilter builds the limit filter from command flags
 func (a *App) buildLimitFilter(cmd *cobra.Command, filter *models.InvoiceFilter) {
 	if limit, _ := cmd.Flags().GetInt("limit"); limit > 0 {
@@ -954,26 +1383,39 @@ func (a *App) outputInvoicesJSON(invoices []*models.Invoice) error {
 	return nil
 }
 
-func (a *App) outputInvoicesCSV(invoices []*models.Invoice) {
-	// CSV header
-	a.logger.Println("Number,Date,DueDate,ClientName,Status,SubTotal,Tax,Total")
+func (a *App) outputInvoicesCSV(invoices []*models.Invoice, dateLayout string) error {
+	data, err := invoicesCSV(invoices, dateLayout)
+	if err != nil {
+		return err
+	}
+	a.logger.Printf("%s", data)
+	return nil
+}
 
-	// CSV rows
+// invoicesCSV encodes invoices as CSV, one record per invoice, with dates in dateLayout
+func invoicesCSV(invoices []*models.Invoice, dateLayout string) ([]byte, error) {
+	// PONumber and ClientReference let accounting match invoices to client records
+	header := []string{"Number", "Date", "DueDate", "ClientName", "Status", "SubTotal", "Tax", "Total", "Currency", "PONumber", "ClientReference"}
+	records := make([][]string, 0, len(invoices))
 	for _, inv := range invoices {
-		a.logger.Printf("%s,%s,%s,%s,%s,%.2f,%.2f,%.2f\n",
+		records = append(records, []string{
 			inv.Number,
-			inv.Date.Format("2006-01-02"),
-			inv.DueDate.Format("2006-01-02"),
+			inv.Date.Format(dateLayout),
+			inv.DueDate.Format(dateLayout),
 			inv.Client.Name,
 			inv.Status,
-			inv.Subtotal,
-			inv.TaxAmount,
-			inv.Total,
-		)
-	}
+			inv.Subtotal.String(),
+			inv.TaxAmount.String(),
+			inv.Total.String(),
+			inv.Currency,
+			inv.PONumber,
+			inv.ClientReference,
+		})
+	}
+	return csvout.Marshal(header, records)
 }
 
-func (a *App) outputInvoicesTable(_ context.Context, invoices []*models.Invoice, _ *services.ClientService) error {
+func (a *App) outputInvoicesTable(_ context.Context, invoices []*models.Invoice, _ *services.ClientService, dateLayout string) error {
 	if len(invoices) == 0 {
 		a.logger.Println("No invoices found")
 		return nil
@@ -1003,14 +1445,17 @@ func (a *App) outputInvoicesTable(_ context.Context, invoices []*models.Invoice,
 
 		// Format status with color (in a real terminal)
 		status := inv.Status
+		if inv.IsDeleted() {
+			status += " (deleted)"
+		}
 
-		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%.2f\n",
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
 			inv.Number,
 			clientName,
-			inv.Date.Format("2006-01-02"),
-			inv.DueDate.Format("2006-01-02"),
+			inv.Date.Format(dateLayout),
+			inv.DueDate.Format(dateLayout),
 			status,
-			inv.Total,
+			money.Format(inv.Total.Float64(), inv.Currency),
 		); err != nil {
 			return fmt.Errorf("failed to write table row for invoice %s: %w", inv.Number, err)
 		}
@@ -1019,43 +1464,7 @@ func (a *App) outputInvoicesTable(_ context.Context, invoices []*models.Invoice,
 	return nil
 }
 
-func (a *App) displayInvoiceSummary(invoices []*models.Invoice, currency string) {
-	var totalAmount, paidAmount, unpaidAmount float64
-	var draftCount, sentCount, paidCount, overdueCount int
-
-	for _, inv := range invoices {
-		totalAmount += inv.Total
-
-		switch inv.Status {
-		case models.StatusDraft:
-			draftCount++
-			unpaidAmount += inv.Total
-		case models.StatusSent:
-			sentCount++
-			unpaidAmount += inv.Total
-		case models.StatusPaid:
-			paidCount++
-			paidAmount += inv.Total
-		case models.StatusOverdue:
-			overdueCount++
-			unpaidAmount += inv.Total
-		}
-	}
-
-	a.logger.Printf("\n📊 Summary\n")
-	a.logger.Printf("─────────\n")
-	a.logger.Printf("Total Invoices: %d\n", len(invoices))
-	a.logger.Printf("  Draft: %d\n", draftCount)
-	a.logger.Printf("  Sent: %d\n", sentCount)
-	a.logger.Printf("  Paid: %d\n", paidCount)
-	a.logger.Printf("  Overdue: %d\n", overdueCount)
-	a.logger.Printf("\n")
-	a.logger.Printf("Total Amount: %.2f %s\n", totalAmount, currency)
-	a.logger.Printf("  Paid: %.2f %s\n", paidAmount, currency)
-	a.logger.Printf("  Unpaid: %.2f %s\n", unpaidAmount, currency)
-}
-
-func (a *App) displayInvoiceDetails(invoice *models.Invoice, client *models.Client, currency string, showItems, _ bool) {
+func (a *App) displayInvoiceDetails(invoice *models.Invoice, client *models.Client, currency, dateLayout string, showItems, showHistory bool) {
 	a.logger.Printf("📄 Invoice %s\n", invoice.Number)
 	a.logger.Printf("════════════════════\n")
 	a.logger.Printf("\n")
@@ -1067,22 +1476,60 @@ func (a *App) displayInvoiceDetails(invoice *models.Invoice, client *models.Clie
 	}
 	a.logger.Printf("\n")
 
-	a.logger.Printf("Date: %s\n", invoice.Date.Format("2006-01-02"))
-	a.logger.Printf("Due Date: %s\n", invoice.DueDate.Format("2006-01-02"))
+	a.logger.Printf("Date: %s\n", invoice.Date.Format(dateLayout))
+	a.logger.Printf("Due Date: %s\n", invoice.DueDate.Format(dateLayout))
 	a.logger.Printf("Status: %s\n", invoice.Status)
+	if invoice.IsDeleted() {
+		a.logger.Printf("Deleted: %s (restore with 'go-invoice invoice restore %s')\n", invoice.DeletedAt.Format(dateLayout), invoice.Number)
+	}
+	if invoice.Locked && invoice.LockedAt != nil {
+		a.logger.Printf("Locked: since %s (unlock with 'go-invoice invoice unlock %s --reason ...')\n", invoice.LockedAt.Format(dateLayout), invoice.Number)
+	}
+	for _, unlock := range invoice.Unlocks {
+		a.logger.Printf("Unlocked: %s by %s: %s\n", unlock.At.Format("2006-01-02 15:04"), unlock.By, unlock.Reason)
+	}
 
+	if invoice.PONumber != "" {
+		a.logger.Printf("PO Number: %s\n", invoice.PONumber)
+	}
+	if invoice.ClientReference != "" {
+		a.logger.Printf("Client Reference: %s\n", invoice.ClientReference)
+	}
 	if invoice.Description != "" {
 		a.logger.Printf("Description: %s\n", invoice.Description)
 	}
+	if invoice.TemplateName != "" {
+		a.logger.Printf("Template: %s\n", invoice.TemplateName)
+	}
 
 	a.logger.Printf("\n")
 	a.logger.Printf("💰 Financial Summary\n")
 	a.logger.Printf("──────────────────\n")
-	a.logger.Printf("Subtotal: %.2f %s\n", invoice.Subtotal, currency)
+	a.logger.Printf("Subtotal: %s\n", money.Format(invoice.Subtotal.Float64(), currency))
+	if invoice.DiscountTotal > 0 {
+		a.logger.Printf("Discount%s: -%s\n", invoiceDiscountLabel(invoice), money.Format(invoice.DiscountTotal.Float64(), currency))
+	}
 	if invoice.TaxAmount > 0 {
-		a.logger.Printf("Tax: %.2f %s\n", invoice.TaxAmount, currency)
+		a.logger.Printf("Tax: %s\n", money.Format(invoice.TaxAmount.Float64(), currency))
+	} else if invoice.Client.TaxExempt {
+		a.logger.Printf("Tax: exempt%s\n", parenthesize(invoice.Client.ExemptionReason))
+	}
+	a.logger.Printf("Total: %s\n", money.Format(invoice.Total.Float64(), currency))
+	if len(invoice.Payments) > 0 {
+		a.logger.Printf("Paid: %s\n", money.Format(invoice.AmountPaid(), currency))
+		a.logger.Printf("Balance Due: %s\n", money.FormatAccounting(invoice.AmountDue(), currency))
+	}
+
+	if len(invoice.Payments) > 0 {
+		a.logger.Printf("\n")
+		a.logger.Printf("💵 Payments\n")
+		a.logger.Printf("──────────\n")
+
+		for _, payment := range invoice.Payments {
+			a.logger.Printf("%s  %14s  %-5s  %s\n",
+				payment.Date.Format(dateLayout), money.Format(payment.Amount, currency), payment.Method, payment.Reference)
+		}
 	}
-	a.logger.Printf("Total: %.2f %s\n", invoice.Total, currency)
 
 	if showItems && len(invoice.WorkItems) > 0 {
 		a.logger.Printf("\n")
@@ -1091,13 +1538,46 @@ func (a *App) displayInvoiceDetails(invoice *models.Invoice, client *models.Clie
 
 		for i, item := range invoice.WorkItems {
 			a.logger.Printf("\n%d. %s\n", i+1, item.Description)
-			a.logger.Printf("   Date: %s\n", item.Date.Format("2006-01-02"))
-			a.logger.Printf("   Hours: %.2f @ %.2f/hour = %.2f %s\n",
-				item.Hours, item.Rate, item.Total, currency)
+			a.logger.Printf("   Date: %s\n", item.Date.Format(dateLayout))
+			a.logger.Printf("   Hours: %.2f @ %s/hour = %s\n",
+				item.Hours, money.Format(item.Rate, currency), money.Format(item.Total, currency))
 		}
 	}
 
-	// Notes field not yet available in Invoice model
+	if len(invoice.GeneratedDocuments) > 0 {
+		a.logger.Printf("\n")
+		a.logger.Printf("📁 Generated Documents\n")
+		a.logger.Printf("────────────────────\n")
+
+		for _, doc := range invoice.GeneratedDocuments {
+			a.logger.Printf("%s  %-4s  %-7s  %s\n",
+				doc.GeneratedAt.Format("2006-01-02 15:04:05"), doc.Format, doc.Status, doc.Checksum[:min(12, len(doc.Checksum))])
+			a.logger.Printf("   %s\n", doc.Path)
+		}
+	}
+
+	if len(invoice.Attachments) > 0 {
+		a.logger.Printf("\n")
+		a.logger.Printf("📎 Attachments\n")
+		a.logger.Printf("─────────────\n")
+
+		for _, attachment := range invoice.Attachments {
+			a.logger.Printf("%s  %-24s  %8d bytes  %s\n",
+				attachment.AddedAt.Format("2006-01-02 15:04:05"), attachment.ContentType, attachment.Size, attachment.Filename)
+			a.logger.Printf("   %s\n", attachment.Path)
+		}
+	}
+
+	if invoice.Notes != "" {
+		a.logger.Printf("\n")
+		a.logger.Printf("📝 Notes\n")
+		a.logger.Printf("────────\n")
+		a.logger.Printf("%s\n", invoice.Notes)
+	}
+
+	if showHistory {
+		a.displayInvoiceHistory(invoice)
+	}
 
 	a.logger.Printf("\n")
 	a.logger.Printf("🕒 Timestamps\n")
@@ -1106,9 +1586,25 @@ func (a *App) displayInvoiceDetails(invoice *models.Invoice, client *models.Clie
 	a.logger.Printf("Updated: %s\n", invoice.UpdatedAt.Format("2006-01-02 15:04:05"))
 }
 
+// displayInvoiceHistory prints the invoice's events, oldest first
+func (a *App) displayInvoiceHistory(invoice *models.Invoice) {
+	a.logger.Printf("\n")
+	a.logger.Printf("📜 History\n")
+	a.logger.Printf("─────────\n")
+
+	history := invoice.History()
+	if len(history) == 0 {
+		a.logger.Printf("No history recorded for this invoice\n")
+		return
+	}
+	for _, event := range history {
+		a.logger.Printf("%s  %-16s  %s\n", event.At.Format("2006-01-02 15:04:05"), event.Type, event.Summary)
+	}
+}
+
 // Interactive mode helpers
 
-func (a *App) runInvoiceCreateInteractive(ctx context.Context, invoiceService *services.InvoiceService, clientService *services.ClientService, config *config.Config) error {
+func (a *App) runInvoiceCreateInteractive(ctx context.Context, invoiceService *services.InvoiceService, clientService *services.ClientService, config *config.Config, termsAnchor models.TermsAnchor, currency string, taxRate float64, dryRun bool) error {
 	a.logger.Println("🔨 Create New Invoice - Interactive Mode")
 	a.logger.Println("=====================================")
 	a.logger.Println("")
@@ -1126,6 +1622,7 @@ func (a *App) runInvoiceCreateInteractive(ctx context.Context, invoiceService *s
 	}
 
 	var client *models.Client
+	newClient := false
 	if len(clientResult.Clients) > 0 {
 		// Add option to create new client
 		options := []string{"Create new client"}
@@ -1140,23 +1637,27 @@ func (a *App) runInvoiceCreateInteractive(ctx context.Context, invoiceService *s
 
 		if index == 0 {
 			// Create new client
-			client, err = a.createClientInteractive(ctx, clientService, prompter)
+			client, err = a.createClientInteractive(ctx, clientService, prompter, dryRun)
 			if err != nil {
 				return err
 			}
+			newClient = true
 		} else {
 			client = clientResult.Clients[index-1]
 		}
 	} else {
 		// No clients exist, create new one
 		a.logger.Println("No clients found. Let's create one.")
-		client, err = a.createClientInteractive(ctx, clientService, prompter)
+		client, err = a.createClientInteractive(ctx, clientService, prompter, dryRun)
 		if err != nil {
 			return err
 		}
+		newClient = true
 	}
 
-	a.logger.Printf("\n✅ Client selected: %s\n\n", client.Name)
+	a.logger.Printf("\n✅ Client selected: %s\n", client.Name)
+	a.warnTaxExemptClient(client, taxRate)
+	a.logger.Printf("\n")
 
 	// Prompt for invoice details
 	a.logger.Println("Step 2: Invoice details")
@@ -1169,7 +1670,7 @@ func (a *App) runInvoiceCreateInteractive(ctx context.Context, invoiceService *s
 	}
 
 	// Due date
-	defaultDueDate := invoiceDate.AddDate(0, 0, config.Invoice.DefaultDueDays)
+	defaultDueDate := models.CalculateDueDate(invoiceDate, config.Invoice.DefaultDueDays, termsAnchor)
 	dueDate, err := prompter.PromptDate(ctx, "Due date", defaultDueDate)
 	if err != nil {
 		return fmt.Errorf("due date selection canceled: %w", err)
@@ -1181,14 +1682,38 @@ func (a *App) runInvoiceCreateInteractive(ctx context.Context, invoiceService *s
 		return fmt.Errorf("description input canceled: %w", err)
 	}
 
-	// Generate invoice number
-	nextNumber := a.generateNextInvoiceNumber(ctx, invoiceService, config.Invoice.Prefix, config.Invoice.StartNumber)
+	req := models.CreateInvoiceRequest{
+		Date:        invoiceDate,
+		DueDate:     dueDate,
+		ClientID:    client.ID,
+		Description: description,
+		Currency:    currency,
+		TaxRate:     taxRate,
+		TaxRounding: config.Invoice.TaxRounding,
+	}
+
+	if dryRun {
+		invoice, previewErr := invoiceService.PreviewInvoiceWithNextNumber(ctx, req, invoiceNumbering(config), client)
+		if previewErr != nil {
+			return fmt.Errorf("failed to preview invoice: %w", previewErr)
+		}
+		a.logger.Printf("\n")
+		a.displayInvoiceCreatePreview(invoice, client, newClient, config.Invoice.DateLayout())
+		return a.logger.ResultJSON(invoice)
+	}
+
+	// Preview the invoice number; it is allocated when the invoice is created
+	nextNumber, err := invoiceService.NextInvoiceNumber(ctx, invoiceNumbering(config), invoiceDate)
+	if err != nil {
+		return fmt.Errorf("failed to determine invoice number: %w", err)
+	}
 
 	a.logger.Printf("\n📋 Invoice Summary:\n")
 	a.logger.Printf("   Number: %s\n", nextNumber)
 	a.logger.Printf("   Client: %s\n", client.Name)
-	a.logger.Printf("   Date: %s\n", invoiceDate.Format("2006-01-02"))
-	a.logger.Printf("   Due Date: %s\n", dueDate.Format("2006-01-02"))
+	a.logger.Printf("   Date: %s\n", config.Invoice.FormatDate(invoiceDate))
+	a.logger.Printf("   Due Date: %s\n", config.Invoice.FormatDate(dueDate))
+	a.logger.Printf("   Currency: %s\n", currency)
 	if description != "" {
 		a.logger.Printf("   Description: %s\n", description)
 	}
@@ -1206,20 +1731,13 @@ func (a *App) runInvoiceCreateInteractive(ctx context.Context, invoiceService *s
 	}
 
 	// Create invoice
-	req := models.CreateInvoiceRequest{
-		Number:      nextNumber,
-		Date:        invoiceDate,
-		DueDate:     dueDate,
-		ClientID:    client.ID,
-		Description: description,
-	}
-
-	invoice, err := invoiceService.CreateInvoice(ctx, req)
+	invoice, err := invoiceService.CreateInvoiceWithNextNumber(ctx, req, invoiceNumbering(config))
 	if err != nil {
 		return fmt.Errorf("failed to create invoice: %w", err)
 	}
 
 	// Display success message
+	a.logger.Result(invoice.Number)
 	a.logger.Printf("\n✅ Invoice created successfully!\n")
 	a.logger.Printf("   Invoice Number: %s\n", invoice.Number)
 	a.logger.Printf("   Invoice ID: %s\n", invoice.ID)
@@ -1228,10 +1746,10 @@ func (a *App) runInvoiceCreateInteractive(ctx context.Context, invoiceService *s
 	a.logger.Printf("   • Import work items: go-invoice import --file hours.csv --invoice %s\n", invoice.ID)
 	a.logger.Printf("   • Generate invoice: go-invoice generate %s\n", invoice.ID)
 
-	return nil
+	return a.logger.ResultJSON(invoice)
 }
 
-func (a *App) runInvoiceUpdateInteractive(ctx context.Context, invoiceService *services.InvoiceService, invoice *models.Invoice) error {
+func (a *App) runInvoiceUpdateInteractive(ctx context.Context, invoiceService *services.InvoiceService, invoice *models.Invoice, dateLayout string) error {
 	a.logger.Printf("🔧 Update Invoice %s - Interactive Mode\n", invoice.Number)
 	a.logger.Println("=====================================")
 	a.logger.Println("")
@@ -1241,8 +1759,8 @@ func (a *App) runInvoiceUpdateInteractive(ctx context.Context, invoiceService *s
 	// Show current invoice details
 	a.logger.Println("Current Invoice Details:")
 	a.logger.Printf("   Status: %s\n", invoice.Status)
-	a.logger.Printf("   Date: %s\n", invoice.Date.Format("2006-01-02"))
-	a.logger.Printf("   Due Date: %s\n", invoice.DueDate.Format("2006-01-02"))
+	a.logger.Printf("   Date: %s\n", invoice.Date.Format(dateLayout))
+	a.logger.Printf("   Due Date: %s\n", invoice.DueDate.Format(dateLayout))
 	a.logger.Printf("   Description: %s\n", invoice.Description)
 	a.logger.Println("")
 
@@ -1299,7 +1817,7 @@ func (a *App) runInvoiceUpdateInteractive(ctx context.Context, invoiceService *s
 		a.logger.Printf("   Status: %s → %s\n", invoice.Status, *req.Status)
 	}
 	if req.DueDate != nil {
-		a.logger.Printf("   Due Date: %s → %s\n", invoice.DueDate.Format("2006-01-02"), req.DueDate.Format("2006-01-02"))
+		a.logger.Printf("   Due Date: %s → %s\n", invoice.DueDate.Format(dateLayout), req.DueDate.Format(dateLayout))
 	}
 	if req.Description != nil {
 		a.logger.Printf("   Description: %s → %s\n", invoice.Description, *req.Description)
@@ -1323,11 +1841,11 @@ func (a *App) runInvoiceUpdateInteractive(ctx context.Context, invoiceService *s
 	}
 
 	a.logger.Printf("\n✅ Invoice %s updated successfully!\n", updatedInvoice.Number)
-	return nil
+	return a.logger.ResultJSON(updatedInvoice)
 }
 
 // createClientInteractive creates a new client through interactive prompts
-func (a *App) createClientInteractive(ctx context.Context, clientService *services.ClientService, prompter *cli.Prompter) (*models.Client, error) {
+func (a *App) createClientInteractive(ctx context.Context, clientService *services.ClientService, prompter *cli.Prompter, dryRun bool) (*models.Client, error) {
 	a.logger.Println("\n📝 Create New Client")
 	a.logger.Println("-------------------")
 
@@ -1360,6 +1878,14 @@ func (a *App) createClientInteractive(ctx context.Context, clientService *servic
 		Address: address,
 	}
 
+	if dryRun {
+		client, err := clientService.PreviewClient(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client: %w", err)
+		}
+		return client, nil
+	}
+
 	client, err := clientService.CreateClient(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %w", err)
@@ -1369,32 +1895,95 @@ func (a *App) createClientInteractive(ctx context.Context, clientService *servic
 	return client, nil
 }
 
-// generateNextInvoiceNumber generates the next available invoice number using timestamp format
-func (a *App) generateNextInvoiceNumber(_ context.Context, _ *services.InvoiceService, prefix string, _ int) string {
-	// Generate invoice number based on current date and time
-	now := time.Now()
-	return fmt.Sprintf("%s-%s", prefix, now.Format("20060102-150405"))
+// displayInvoiceCreatePreview prints the invoice, and the client when it is new, that a
+// dry run of invoice create would have saved
+func (a *App) displayInvoiceCreatePreview(invoice *models.Invoice, client *models.Client, newClient bool, dateLayout string) {
+	a.logger.Printf("🔍 Invoice Preview (dry run — nothing saved)\n")
+	a.logger.Printf("   Invoice Number: %s\n", invoice.Number)
+	a.logger.Printf("   Client: %s\n", client.Name)
+	a.logger.Printf("   Date: %s\n", invoice.Date.Format(dateLayout))
+	a.logger.Printf("   Due Date: %s\n", invoice.DueDate.Format(dateLayout))
+	a.logger.Printf("   Currency: %s\n", invoice.Currency)
+	if invoice.TaxRate > 0 {
+		a.logger.Printf("   Tax Rate: %.2f%%\n", invoice.TaxRate*100)
+	}
+	a.logger.Printf("   Status: %s\n", invoice.Status)
+	if invoice.PONumber != "" {
+		a.logger.Printf("   PO Number: %s\n", invoice.PONumber)
+	}
+	if invoice.ClientReference != "" {
+		a.logger.Printf("   Client Reference: %s\n", invoice.ClientReference)
+	}
+	if invoice.Description != "" {
+		a.logger.Printf("   Description: %s\n", invoice.Description)
+	}
+	if invoice.TemplateName != "" {
+		a.logger.Printf("   Template: %s\n", invoice.TemplateName)
+	}
+	if invoice.USDCAddressOverride != nil {
+		a.logger.Printf("   USDC Address: %s\n", *invoice.USDCAddressOverride)
+	}
+	if invoice.BSVAddressOverride != nil {
+		a.logger.Printf("   BSV Address: %s\n", *invoice.BSVAddressOverride)
+	}
+
+	if newClient {
+		a.logger.Printf("\n")
+		a.logger.Printf("👤 New client that would be created:\n")
+		a.logger.Printf("   Name: %s\n", client.Name)
+		a.logger.Printf("   Email: %s\n", client.Email)
+		if client.Phone != "" {
+			a.logger.Printf("   Phone: %s\n", client.Phone)
+		}
+		if client.Address != "" {
+			a.logger.Printf("   Address: %s\n", client.Address)
+		}
+	}
+
+	a.logger.Printf("\n")
+	a.logger.Printf("Run the same command without --dry-run to create it.\n")
+}
+
+// invoiceNumbering returns the invoice numbering settings from the configuration
+func invoiceNumbering(cfg *config.Config) models.InvoiceNumbering {
+	return models.InvoiceNumbering{
+		Format:      cfg.Invoice.NumberFormat,
+		Prefix:      cfg.Invoice.Prefix,
+		StartNumber: cfg.Invoice.StartNumber,
+	}
 }
 
-// searchClientsByName searches for clients by name
-func (a *App) searchClientsByName(ctx context.Context, clientService *services.ClientService, name string) ([]*models.Client, error) {
-	// Get all clients and filter by name
-	// This is a simple implementation - in a real system, you'd want server-side search
-	result, err := clientService.ListClients(ctx, true, 0, 0) // activeOnly=true, no limit
+// hourlyRounding returns the configured rounding and minimum-charge rule for hourly entries
+func hourlyRounding(cfg *config.Config) models.HourlyRounding {
+	return models.HourlyRounding{
+		IncrementMinutes: cfg.Invoice.HourlyRoundingMinutes,
+		MinimumHours:     cfg.Invoice.MinimumHours,
+	}
+}
+
+// searchClients finds active clients matching query by name, email or address, best match first
+func (a *App) searchClients(ctx context.Context, clientService *services.ClientService, query string) ([]*models.Client, error) {
+	matches, err := clientService.SearchClients(ctx, query, services.SearchOptions{ActiveOnly: true})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list clients: %w", err)
+		return nil, err
+	}
+
+	clients := make([]*models.Client, 0, len(matches))
+	for _, match := range matches {
+		clients = append(clients, match.Client)
 	}
 
-	var matches []*models.Client
-	nameLower := strings.ToLower(name)
+	return clients, nil
+}
 
-	for _, client := range result.Clients {
-		if strings.Contains(strings.ToLower(client.Name), nameLower) {
-			matches = append(matches, client)
+// exactClientMatch returns the client whose name or email equals query, ignoring case
+func exactClientMatch(clients []*models.Client, query string) *models.Client {
+	for _, client := range clients {
+		if strings.EqualFold(client.Name, query) || strings.EqualFold(client.Email, query) {
+			return client
 		}
 	}
-
-	return matches, nil
+	return nil
 }
 
 // buildInvoiceAddLineItemCommand creates the invoice add-line-item subcommand
@@ -1407,10 +1996,16 @@ func (a *App) buildInvoiceAddLineItemCommand() *cobra.Command {
 Line Item Types:
   hourly   - Time-based billing (hours × rate)
   fixed    - Flat fee or fixed amount (retainers, setup fees)
-  quantity - Quantity-based billing (quantity × unit price)`,
+  quantity - Quantity-based billing (quantity × unit price)
+
+The type defaults to DEFAULT_LINE_ITEM_TYPE (hourly when unset), and hourly items
+without --rate are billed at DEFAULT_RATE. Explicit flags always win.`,
 		Example: `  # Add hourly work item (default type)
   go-invoice invoice add-line-item INV-001 --description "Development work" --hours 8 --rate 125
 
+  # Bill hours at the configured DEFAULT_RATE
+  go-invoice invoice add-line-item INV-001 --description "Development work" --hours 8
+
   # Add monthly retainer (fixed amount)
   go-invoice invoice add-line-item INV-001 --type fixed --description "Monthly Retainer - August" --amount 2000
 
@@ -1418,20 +2013,23 @@ Line Item Types:
   go-invoice invoice add-line-item INV-001 --type fixed --description "Project Setup Fee" --amount 500
 
   # Add quantity-based item (licenses, materials)
-  go-invoice invoice add-line-item INV-001 --type quantity --description "SSL Certificates" --quantity 2 --unit-price 50`,
+  go-invoice invoice add-line-item INV-001 --type quantity --description "SSL Certificates" --quantity 2 --unit-price 50
+
+  # Add an item with a discount (a percentage or a fixed amount)
+  go-invoice invoice add-line-item INV-001 --description "Development work" --hours 8 --rate 125 --discount 10%`,
 		Args: cobra.ExactArgs(1),
 		RunE: a.runInvoiceAddLineItem,
 	}
 
 	// Common flags
-	cmd.Flags().String("type", "hourly", "Line item type: hourly, fixed, or quantity")
+	cmd.Flags().String("type", "", "Line item type: hourly, fixed, or quantity (default: DEFAULT_LINE_ITEM_TYPE, or hourly)")
 	cmd.Flags().String("description", "", "Line item description (required)")
-	cmd.Flags().String("date", "", "Line item date (required, format: YYYY-MM-DD)")
+	cmd.Flags().String("date", "", "Line item date (required, DATE_FORMAT or YYYY-MM-DD)")
 	cmd.Flags().String("end-date", "", "Line item end date (optional, for date ranges like monthly retainers)")
 
 	// Hourly flags
 	cmd.Flags().Float64("hours", 0, "Hours worked (for hourly type)")
-	cmd.Flags().Float64("rate", 0, "Hourly rate (for hourly type)")
+	cmd.Flags().Float64("rate", 0, "Hourly rate (for hourly type, default: DEFAULT_RATE)")
 
 	// Fixed flags
 	cmd.Flags().Float64("amount", 0, "Fixed amount (for fixed type)")
@@ -1440,6 +2038,9 @@ Line Item Types:
 	cmd.Flags().Float64("quantity", 0, "Quantity (for quantity type)")
 	cmd.Flags().Float64("unit-price", 0, "Unit price (for quantity type)")
 
+	// Discount flags
+	cmd.Flags().String("discount", "", "Discount on this item, a percentage (10%) or a fixed amount (25.00)")
+
 	// Mark required flags
 	_ = cmd.MarkFlagRequired("description")
 	_ = cmd.MarkFlagRequired("date")
@@ -1470,10 +2071,27 @@ func (a *App) runInvoiceAddLineItem(cmd *cobra.Command, args []string) error {
 	quantity, _ := cmd.Flags().GetFloat64("quantity")
 	unitPrice, _ := cmd.Flags().GetFloat64("unit-price")
 
+	// Get config path from flag
+	configPath, _ := cmd.Flags().GetString("config")
+
+	// Load configuration
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
 	// Parse date (required flag, so dateStr is always set)
-	itemDate, err := time.Parse("2006-01-02", dateStr)
+	itemDate, err := config.Invoice.ParseDate(dateStr)
 	if err != nil {
-		return fmt.Errorf("invalid date format (use YYYY-MM-DD): %w", err)
+		return fmt.Errorf("invalid --date: %w", err)
+	}
+
+	// Parse optional discount
+	var discount *models.Discount
+	if discountStr, _ := cmd.Flags().GetString("discount"); discountStr != "" {
+		if discount, err = models.ParseDiscount(discountStr); err != nil {
+			return err
+		}
 	}
 
 	// Parse optional end date
@@ -1481,9 +2099,9 @@ func (a *App) runInvoiceAddLineItem(cmd *cobra.Command, args []string) error {
 	var endDate *time.Time
 	if endDateStr != "" {
 		var parsed time.Time
-		parsed, err = time.Parse("2006-01-02", endDateStr)
+		parsed, err = config.Invoice.ParseDate(endDateStr)
 		if err != nil {
-			return fmt.Errorf("invalid end-date format (use YYYY-MM-DD): %w", err)
+			return fmt.Errorf("invalid --end-date: %w", err)
 		}
 		if parsed.Before(itemDate) {
 			return ErrEndDateBeforeDate
@@ -1491,19 +2109,9 @@ func (a *App) runInvoiceAddLineItem(cmd *cobra.Command, args []string) error {
 		endDate = &parsed
 	}
 
-	// Get config path from flag
-	configPath, _ := cmd.Flags().GetString("config")
-
-	// Load configuration
-	config, err := a.configService.LoadConfig(ctx, configPath)
-	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
-	}
-
 	// Initialize storage and services
-	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage.DataDir)
-	idGen := services.NewUUIDGenerator()
-	invoiceService := services.NewInvoiceService(invoiceStorage, clientStorage, a.logger, idGen)
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
 
 	// Get invoice
 	invoice, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, invoiceIdentifier)
@@ -1511,14 +2119,18 @@ func (a *App) runInvoiceAddLineItem(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Create line item based on type
+	// Create line item based on type, falling back to the configured defaults
 	var lineItem models.LineItem
+	lineItemType = cmp.Or(lineItemType, config.Invoice.DefaultLineItemType, string(models.LineItemTypeHourly))
 
 	switch models.LineItemType(lineItemType) {
 	case models.LineItemTypeHourly:
-		if hours == 0 || rate == 0 {
+		if hours == 0 {
 			return ErrHourlyLineItemRequiresFlags
 		}
+		if rate, err = defaultHourlyRate(rate, config); err != nil {
+			return err
+		}
 		lineItem = models.LineItem{
 			Type:        models.LineItemTypeHourly,
 			Date:        itemDate,
@@ -1526,7 +2138,7 @@ func (a *App) runInvoiceAddLineItem(cmd *cobra.Command, args []string) error {
 			Description: description,
 			Hours:       &hours,
 			Rate:        &rate,
-			Total:       hours * rate,
+			Total:       money.Product(hours, rate),
 			CreatedAt:   time.Now(),
 		}
 
@@ -1540,7 +2152,7 @@ func (a *App) runInvoiceAddLineItem(cmd *cobra.Command, args []string) error {
 			EndDate:     endDate,
 			Description: description,
 			Amount:      &amount,
-			Total:       amount,
+			Total:       money.FromFloat(amount),
 			CreatedAt:   time.Now(),
 		}
 
@@ -1555,7 +2167,7 @@ func (a *App) runInvoiceAddLineItem(cmd *cobra.Command, args []string) error {
 			Description: description,
 			Quantity:    &quantity,
 			UnitPrice:   &unitPrice,
-			Total:       quantity * unitPrice,
+			Total:       money.Product(quantity, unitPrice),
 			CreatedAt:   time.Now(),
 		}
 
@@ -1563,21 +2175,60 @@ func (a *App) runInvoiceAddLineItem(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("%w: %s", ErrInvalidLineItemType, lineItemType)
 	}
 
+	if discount != nil {
+		lineItem.Discount = discount
+		if err := lineItem.RecalculateTotal(ctx); err != nil {
+			return fmt.Errorf("failed to apply discount: %w", err)
+		}
+	}
+
+	// Bill hourly items in the configured increments, with the minimum charge
+	if err := lineItem.ApplyHourlyRounding(ctx, hourlyRounding(config)); err != nil {
+		return fmt.Errorf("failed to apply hourly rounding: %w", err)
+	}
+
 	// Add line item to invoice
+	legacyWorkItems := 0
+	if invoice.HasOnlyWorkItems() {
+		legacyWorkItems = len(invoice.WorkItems)
+	}
 	updatedInvoice, err := invoiceService.AddLineItemToInvoice(ctx, invoice.ID, lineItem)
 	if err != nil {
 		return fmt.Errorf("failed to add line item: %w", err)
 	}
+	switch {
+	case legacyWorkItems == 0:
+	case len(updatedInvoice.WorkItems) == 0:
+		a.logger.Printf("Note: converted %d legacy work items to line items first\n", legacyWorkItems)
+	default:
+		a.logger.Printf("⚠️  Invoice %s now mixes %d legacy work items with line items (LEGACY_WORK_ITEMS=keep)\n",
+			updatedInvoice.Number, legacyWorkItems)
+	}
 
 	// Display success message
 	a.logger.Printf("✅ Line item added to invoice %s\n\n", updatedInvoice.Number)
 	a.logger.Printf("Type:        %s\n", lineItem.Type)
 	a.logger.Printf("Description: %s\n", description)
 	a.logger.Printf("Details:     %s\n", lineItem.GetDetails())
-	a.logger.Printf("Amount:      %s\n\n", lineItem.GetFormattedTotal())
-	a.logger.Printf("Updated Total: $%.2f\n", updatedInvoice.Total)
+	currency := updatedInvoice.GetCurrency(config.Invoice.Currency)
+	if lineItem.Discount != nil {
+		a.logger.Printf("Discount:    -%s\n", money.Format(lineItem.DiscountAmount().Float64(), currency))
+	}
+	a.logger.Printf("Amount:      %s\n\n", money.Format(lineItem.Total.Float64(), currency))
+	a.logger.Printf("Updated Total: %s\n", money.Format(updatedInvoice.Total.Float64(), currency))
 
-	return nil
+	return a.logger.ResultJSON(updatedInvoice)
+}
+
+// defaultHourlyRate returns rate, or the configured default rate when no rate was given
+func defaultHourlyRate(rate float64, cfg *config.Config) (float64, error) {
+	if rate != 0 {
+		return rate, nil
+	}
+	if cfg.Invoice.DefaultRate <= 0 {
+		return 0, ErrHourlyLineItemRequiresRate
+	}
+	return cfg.Invoice.DefaultRate, nil
 }
 
 // buildInvoiceRecalculateCommand creates the invoice recalculate subcommand
@@ -1590,7 +2241,7 @@ func (a *App) buildInvoiceRecalculateCommand() *cobra.Command {
 This command is useful when invoice totals are out of sync due to data migration,
 bugs, or manual edits. It will:
   - Recalculate subtotal from all work items and line items
-  - Apply any crypto service fees
+  - Apply any invoice discount and crypto service fees
   - Update all total fields
   - Preserve all other invoice data`,
 		Example: `  # Recalculate totals for an invoice
@@ -1622,9 +2273,8 @@ func (a *App) runInvoiceRecalculate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Initialize storage and services
-	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage.DataDir)
-	idGen := services.NewUUIDGenerator()
-	invoiceService := services.NewInvoiceService(invoiceStorage, clientStorage, a.logger, idGen)
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
 
 	// Get invoice
 	invoice, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, invoiceIdentifier)
@@ -1636,18 +2286,25 @@ func (a *App) runInvoiceRecalculate(cmd *cobra.Command, args []string) error {
 	originalSubtotal := invoice.Subtotal
 	originalTotal := invoice.Total
 
+	currency := invoice.GetCurrency(config.Invoice.Currency)
+
 	a.logger.Printf("📊 Recalculating totals for invoice %s\n\n", invoice.Number)
 	a.logger.Printf("Current totals:\n")
-	a.logger.Printf("  Subtotal: $%.2f\n", originalSubtotal)
-	a.logger.Printf("  Total:    $%.2f\n\n", originalTotal)
+	a.logger.Printf("  Subtotal: %s\n", money.Format(originalSubtotal.Float64(), currency))
+	a.logger.Printf("  Total:    %s\n\n", money.Format(originalTotal.Float64(), currency))
 
 	// Recalculate totals using the invoice model method
 	if err := invoice.RecalculateTotals(ctx); err != nil {
 		return fmt.Errorf("failed to recalculate invoice totals: %w", err)
 	}
 
+	// A locked invoice keeps the totals it was sent with
+	if invoice.Locked && (invoice.Subtotal != originalSubtotal || invoice.Total != originalTotal) {
+		return fmt.Errorf("%w, total would change to %s", invoice.EnsureUnlocked(), money.Format(invoice.Total.Float64(), currency))
+	}
+
 	// Update the invoice in storage
-	invoiceStorage, _ = a.createStorageInstances(config.Storage.DataDir)
+	invoiceStorage, _ = a.createStorageInstances(config.Storage)
 	if err := invoiceStorage.UpdateInvoice(ctx, invoice); err != nil {
 		return fmt.Errorf("failed to save recalculated invoice: %w", err)
 	}
@@ -1655,16 +2312,14 @@ func (a *App) runInvoiceRecalculate(cmd *cobra.Command, args []string) error {
 	// Display results
 	a.logger.Printf("✅ Invoice totals recalculated successfully!\n\n")
 	a.logger.Printf("New totals:\n")
-	a.logger.Printf("  Subtotal: $%.2f", invoice.Subtotal)
+	a.logger.Printf("  Subtotal: %s", money.Format(invoice.Subtotal.Float64(), currency))
 	if invoice.Subtotal != originalSubtotal {
-		diff := invoice.Subtotal - originalSubtotal
-		a.logger.Printf(" (%+.2f)", diff)
+		a.logger.Printf(" (%s)", formatMoneyChange(invoice.Subtotal-originalSubtotal, currency))
 	}
 	a.logger.Printf("\n")
-	a.logger.Printf("  Total:    $%.2f", invoice.Total)
+	a.logger.Printf("  Total:    %s", money.Format(invoice.Total.Float64(), currency))
 	if invoice.Total != originalTotal {
-		diff := invoice.Total - originalTotal
-		a.logger.Printf(" (%+.2f)", diff)
+		a.logger.Printf(" (%s)", formatMoneyChange(invoice.Total-originalTotal, currency))
 	}
 	a.logger.Printf("\n\n")
 
@@ -1672,9 +2327,20 @@ func (a *App) runInvoiceRecalculate(cmd *cobra.Command, args []string) error {
 	a.logger.Printf("Breakdown:\n")
 	a.logger.Printf("  Work Items:  %d items\n", len(invoice.WorkItems))
 	a.logger.Printf("  Line Items:  %d items\n", len(invoice.LineItems))
+	if invoice.DiscountTotal > 0 {
+		a.logger.Printf("  Discount:    -%s\n", money.Format(invoice.DiscountTotal.Float64(), currency))
+	}
 	if invoice.CryptoFee > 0 {
-		a.logger.Printf("  Crypto Fee:  $%.2f\n", invoice.CryptoFee)
+		a.logger.Printf("  Crypto Fee:  %s\n", money.Format(invoice.CryptoFee.Float64(), currency))
 	}
 
 	return nil
 }
+
+// formatMoneyChange formats a difference between two amounts with an explicit sign
+func formatMoneyChange(diff money.Amount, currency string) string {
+	if diff > 0 {
+		return "+" + money.Format(diff.Float64(), currency)
+	}
+	return money.Format(diff.Float64(), currency)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/services"
+)
+
+// ErrAttachmentFileRequired is returned when invoice attach is run without --file
+var ErrAttachmentFileRequired = fmt.Errorf("--file is required")
+
+// buildInvoiceAttachCommand creates the invoice attach subcommand
+func (a *App) buildInvoiceAttachCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attach <invoice-id-or-number>",
+		Short: "Attach a supporting file to an invoice",
+		Long: `Copy a supporting file, such as a receipt or timesheet, into the data directory
+under attachments/<invoice-id>/ and record it on the invoice. Attachments are
+listed by 'invoice show'.
+
+The file is stored under its own name unless --name is given. Names must be
+plain file names and unique per invoice.
+
+Attachments are kept while an invoice is in the trash and removed when it is
+permanently deleted with 'invoice delete --hard'. They are not included in
+'storage backup'.`,
+		Example: `  # Attach a receipt
+  go-invoice invoice attach INV-001 --file receipt.pdf
+
+  # Store it under a different name
+  go-invoice invoice attach INV-001 --file ~/Downloads/scan-0042.pdf --name hotel-receipt.pdf`,
+		Args: cobra.ExactArgs(1),
+		RunE: a.runInvoiceAttach,
+	}
+
+	cmd.Flags().StringP("file", "f", "", "File to attach (required)")
+	cmd.Flags().String("name", "", "Filename to store the attachment under (default: the file's name)")
+
+	return cmd
+}
+
+// runInvoiceAttach handles the invoice attach command
+func (a *App) runInvoiceAttach(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	filePath, _ := cmd.Flags().GetString("file")
+	name, _ := cmd.Flags().GetString("name")
+	if filePath == "" {
+		return ErrAttachmentFileRequired
+	}
+	if name == "" {
+		name = filepath.Base(filePath)
+	}
+	if err := models.ValidateAttachmentFilename(name); err != nil {
+		return fmt.Errorf("%w: %q", err, name)
+	}
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	invoiceService := a.createInvoiceService(config)
+	invoice, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	attachment, err := a.attachFile(ctx, invoiceService, invoice, filePath, name, config.Storage.Dir())
+	if err != nil {
+		return err
+	}
+
+	a.logger.Result(attachment.Path)
+	a.logger.Printf("📎 Attached %s to invoice %s\n", attachment.Filename, invoice.Number)
+	a.logger.Printf("   Type: %s\n", attachment.ContentType)
+	a.logger.Printf("   Size: %d bytes\n", attachment.Size)
+	a.logger.Printf("   Stored: %s\n", filepath.Join(config.Storage.Dir(), filepath.FromSlash(attachment.Path)))
+
+	return nil
+}
+
+// attachFile copies the file at filePath to the invoice's attachment directory as name and
+// records it on the invoice. The copy is removed again if the invoice cannot be updated.
+func (a *App) attachFile(ctx context.Context, invoiceService *services.InvoiceService, invoice *models.Invoice, filePath, name, dataDir string) (*models.Attachment, error) {
+	if invoice.FindAttachment(name) != nil {
+		return nil, fmt.Errorf("%w on invoice %s: %s", models.ErrAttachmentExists, invoice.Number, name)
+	}
+
+	content, err := os.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	attachment := models.NewAttachment(invoice.ID, name, attachmentContentType(name, content), content)
+	if err := invoice.AddAttachment(attachment); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, name)
+	}
+
+	dest := filepath.Join(dataDir, filepath.FromSlash(attachment.Path))
+	if err := os.MkdirAll(filepath.Dir(dest), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create attachments directory: %w", err)
+	}
+	if err := os.WriteFile(dest, content, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write attachment: %w", err)
+	}
+
+	if err := invoiceService.UpdateInvoiceDirectly(ctx, invoice); err != nil {
+		if removeErr := os.Remove(dest); removeErr != nil {
+			a.logger.Error("failed to remove attachment copy", "path", dest, "error", removeErr)
+		}
+		return nil, fmt.Errorf("failed to record attachment on invoice: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// attachmentContentType guesses a MIME type from the file extension, falling back to
+// sniffing the content
+func attachmentContentType(name string, content []byte) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(name)); contentType != "" {
+		return contentType
+	}
+	return http.DetectContentType(content)
+}
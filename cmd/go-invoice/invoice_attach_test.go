@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// attachTestEnv is a configuration file with a data directory holding one draft invoice
+type attachTestEnv struct {
+	configPath string
+	storageDir string // Directory holding the data, attachments/ included
+	invoice    *models.Invoice
+}
+
+// newAttachTestEnv writes a configuration for backend to a temporary directory and creates a
+// client and a draft invoice INV-001 in its storage
+func newAttachTestEnv(t *testing.T, backend string) *attachTestEnv {
+	t.Helper()
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, "data")
+	if backend == config.StorageBackendSQLite {
+		dataDir = filepath.Join(dir, "invoices.db")
+	}
+	configPath := filepath.Join(dir, ".env.config")
+	require.NoError(t, os.WriteFile(configPath, fmt.Appendf(nil,
+		"BUSINESS_NAME=Test Business\nBUSINESS_ADDRESS=1 Main St\nBUSINESS_EMAIL=billing@test.example\n"+
+			"STORAGE_BACKEND=%s\nDATA_DIR=%s\n", backend, dataDir), 0o600))
+
+	app := NewApp()
+	defer app.closeStores()
+	cfg, err := app.configService.LoadConfig(ctx, configPath)
+	require.NoError(t, err)
+	require.NoError(t, app.createStorage(cfg.Storage).Initialize(ctx))
+
+	client, err := app.createClientService(cfg.Storage).CreateClient(ctx, models.CreateClientRequest{
+		Name: "Acme Corp", Email: "billing@acme.test",
+	})
+	require.NoError(t, err)
+	invoice, err := app.createInvoiceService(cfg).CreateInvoice(ctx, models.CreateInvoiceRequest{
+		Number:   "INV-001",
+		ClientID: client.ID,
+		Date:     time.Now(),
+		DueDate:  time.Now().AddDate(0, 0, 30),
+	})
+	require.NoError(t, err)
+
+	return &attachTestEnv{configPath: configPath, storageDir: cfg.Storage.Dir(), invoice: invoice}
+}
+
+// run executes the go-invoice command line args against the environment's configuration
+func (e *attachTestEnv) run(t *testing.T, args ...string) error {
+	t.Helper()
+
+	app := NewApp()
+	app.rootCmd.SetArgs(append([]string{"--config", e.configPath}, args...))
+	app.rootCmd.SetOut(io.Discard)
+	app.rootCmd.SetErr(io.Discard)
+	app.rootCmd.SilenceErrors, app.rootCmd.SilenceUsage = true, true
+	return app.Execute()
+}
+
+// reload returns the stored invoice, including invoices in the trash
+func (e *attachTestEnv) reload(t *testing.T) *models.Invoice {
+	t.Helper()
+	ctx := context.Background()
+
+	app := NewApp()
+	defer app.closeStores()
+	cfg, err := app.configService.LoadConfig(ctx, e.configPath)
+	require.NoError(t, err)
+	invoice, err := app.createInvoiceService(cfg).FindInvoice(ctx, string(e.invoice.ID), true)
+	require.NoError(t, err)
+	return invoice
+}
+
+// attachmentsDir returns the directory holding the invoice's attachments
+func (e *attachTestEnv) attachmentsDir() string {
+	return filepath.Join(e.storageDir, models.AttachmentsDir, string(e.invoice.ID))
+}
+
+// writeReceipt writes a file to attach and returns its path
+func writeReceipt(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte("%PDF-1.4 receipt"), 0o600))
+	return path
+}
+
+func TestInvoiceAttachCommand(t *testing.T) {
+	t.Run("AttachesFile", func(t *testing.T) {
+		env := newAttachTestEnv(t, config.StorageBackendJSON)
+
+		require.NoError(t, env.run(t, "invoice", "attach", "INV-001", "--file", writeReceipt(t, "receipt.pdf")))
+
+		content, err := os.ReadFile(filepath.Join(env.attachmentsDir(), "receipt.pdf")) //nolint:gosec // test path
+		require.NoError(t, err)
+		assert.Equal(t, "%PDF-1.4 receipt", string(content))
+
+		attachments := env.reload(t).Attachments
+		require.Len(t, attachments, 1)
+		assert.Equal(t, "receipt.pdf", attachments[0].Filename)
+		assert.Equal(t, "application/pdf", attachments[0].ContentType)
+		assert.Equal(t, models.AttachmentPath(env.invoice.ID, "receipt.pdf"), attachments[0].Path)
+	})
+
+	t.Run("StoresUnderName", func(t *testing.T) {
+		env := newAttachTestEnv(t, config.StorageBackendJSON)
+
+		require.NoError(t, env.run(t, "invoice", "attach", "INV-001",
+			"--file", writeReceipt(t, "scan-0042.pdf"), "--name", "hotel-receipt.pdf"))
+
+		assert.FileExists(t, filepath.Join(env.attachmentsDir(), "hotel-receipt.pdf"))
+		assert.NoFileExists(t, filepath.Join(env.attachmentsDir(), "scan-0042.pdf"))
+	})
+
+	t.Run("RejectsPathTraversalName", func(t *testing.T) {
+		env := newAttachTestEnv(t, config.StorageBackendJSON)
+
+		for _, name := range []string{"../x", "..", `..\x`, "sub/x"} {
+			err := env.run(t, "invoice", "attach", "INV-001", "--file", writeReceipt(t, "receipt.pdf"), "--name", name)
+			require.ErrorIs(t, err, models.ErrInvalidAttachmentName, name)
+		}
+
+		assert.NoFileExists(t, filepath.Join(env.storageDir, models.AttachmentsDir, "x"))
+		assert.NoDirExists(t, env.attachmentsDir())
+		assert.Empty(t, env.reload(t).Attachments)
+	})
+
+	t.Run("RejectsDuplicateName", func(t *testing.T) {
+		env := newAttachTestEnv(t, config.StorageBackendJSON)
+		receipt := writeReceipt(t, "receipt.pdf")
+
+		require.NoError(t, env.run(t, "invoice", "attach", "INV-001", "--file", receipt))
+		err := env.run(t, "invoice", "attach", "INV-001", "--file", receipt)
+		require.ErrorIs(t, err, models.ErrAttachmentExists)
+		assert.Len(t, env.reload(t).Attachments, 1)
+	})
+
+	t.Run("RequiresFile", func(t *testing.T) {
+		env := newAttachTestEnv(t, config.StorageBackendJSON)
+
+		require.ErrorIs(t, env.run(t, "invoice", "attach", "INV-001"), ErrAttachmentFileRequired)
+	})
+
+	for _, backend := range []string{config.StorageBackendJSON, config.StorageBackendSQLite} {
+		t.Run("RemovedWithInvoice/"+backend, func(t *testing.T) {
+			env := newAttachTestEnv(t, backend)
+			require.NoError(t, env.run(t, "invoice", "attach", "INV-001", "--file", writeReceipt(t, "receipt.pdf")))
+
+			// The trash keeps attachments so a restored invoice still has them
+			require.NoError(t, env.run(t, "invoice", "delete", "INV-001", "--force"))
+			assert.FileExists(t, filepath.Join(env.attachmentsDir(), "receipt.pdf"))
+
+			require.NoError(t, env.run(t, "invoice", "delete", string(env.invoice.ID), "--hard", "--force"))
+			assert.NoDirExists(t, env.attachmentsDir())
+		})
+	}
+}
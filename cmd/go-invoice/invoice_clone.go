@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/services"
+)
+
+// buildInvoiceCloneCommand creates the invoice clone subcommand
+func (a *App) buildInvoiceCloneCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clone <invoice-id-or-number>",
+		Short: "Create a new draft invoice from an existing one",
+		Long: `Copy an invoice into a new draft with the next invoice number.
+
+Work items, line items, the crypto fee, tax rate, discount, description, notes and
+crypto address overrides are copied and the totals recalculated. Payments, generated
+documents, attachments, status and timestamps are not.
+
+The clone keeps the source's payment term unless --due-date is given.`,
+		Example: `  # Bill the same work again, dated today
+  go-invoice invoice clone INV-001
+
+  # Clone for the next month
+  go-invoice invoice clone INV-001 --date 2024-02-01
+
+  # Clone for a different client
+  go-invoice invoice clone INV-001 --client "Globex"`,
+		Args: cobra.ExactArgs(1),
+		RunE: a.runInvoiceClone,
+	}
+
+	cmd.Flags().String("client", "", "Client name or ID to bill instead of the source invoice's client")
+	cmd.Flags().String("date", "", "Invoice date (default: today)")
+	cmd.Flags().String("due-date", "", "Due date (default: keeps the source invoice's payment term)")
+
+	return cmd
+}
+
+// runInvoiceClone handles the invoice clone command
+func (a *App) runInvoiceClone(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	clientName, _ := cmd.Flags().GetString("client")
+	dateStr, _ := cmd.Flags().GetString("date")
+	dueDateStr, _ := cmd.Flags().GetString("due-date")
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	var options services.CloneInvoiceOptions
+	if dateStr != "" {
+		if options.Date, err = config.Invoice.ParseDate(dateStr); err != nil {
+			return fmt.Errorf("invalid --date: %w", err)
+		}
+	}
+	if dueDateStr != "" {
+		if options.DueDate, err = config.Invoice.ParseDate(dueDateStr); err != nil {
+			return fmt.Errorf("invalid --due-date: %w", err)
+		}
+	}
+
+	// Create storage and services
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	idGen := a.idGenerator(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
+	clientService := services.NewClientService(clientStorage, invoiceStorage, a.logger, idGen)
+
+	source, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	if clientName != "" {
+		client, _, findErr := a.findOrCreateClient(ctx, clientService, clientName, false, false, cmd)
+		if findErr != nil {
+			return findErr
+		}
+		options.ClientID = client.ID
+	}
+
+	invoice, err := invoiceService.CloneInvoice(ctx, source.ID, invoiceNumbering(config), options)
+	if err != nil {
+		return fmt.Errorf("failed to clone invoice: %w", err)
+	}
+	a.warnTaxExemptClient(&invoice.Client, invoice.TaxRate)
+
+	a.logger.Result(invoice.Number)
+	a.logger.Printf("✅ Invoice %s cloned from %s\n", invoice.Number, source.Number)
+	a.logger.Printf("   Client: %s\n", invoice.Client.Name)
+	a.logger.Printf("   Date: %s\n", invoice.Date.Format(config.Invoice.DateLayout()))
+	a.logger.Printf("   Due Date: %s\n", invoice.DueDate.Format(config.Invoice.DateLayout()))
+	a.logger.Printf("   Items: %d\n", len(invoice.WorkItems)+len(invoice.LineItems))
+	a.logger.Printf("   Total: %s\n", money.Format(invoice.Total.Float64(), invoice.GetCurrency(config.Invoice.Currency)))
+	a.logger.Printf("   Status: %s\n", invoice.Status)
+
+	return nil
+}
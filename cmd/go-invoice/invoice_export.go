@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/services"
+)
+
+// ErrImportFileRequired indicates invoice import was run without --file
+var ErrImportFileRequired = fmt.Errorf("--file is required")
+
+// buildInvoiceExportCommand creates the invoice export subcommand
+func (a *App) buildInvoiceExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <invoice-id-or-number>",
+		Short: "Export an invoice as JSON",
+		Long: `Write a complete invoice, including its client details, as JSON.
+
+The file can be brought into another data directory with "invoice import", for example
+to move an invoice between machines or keep a copy of a single invoice.`,
+		Example: `  # Export an invoice to a file
+  go-invoice invoice export INV-001 --output inv.json
+
+  # Print the export to stdout
+  go-invoice invoice export INV-001`,
+		Args: cobra.ExactArgs(1),
+		RunE: a.runInvoiceExport,
+	}
+
+	cmd.Flags().StringP("output", "o", "", "File to write the export to (default: stdout)")
+
+	return cmd
+}
+
+// runInvoiceExport handles the invoice export command
+func (a *App) runInvoiceExport(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Create storage and services
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
+
+	invoice, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	export, err := invoiceService.ExportInvoice(ctx, invoice.ID)
+	if err != nil {
+		return fmt.Errorf("failed to export invoice: %w", err)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode invoice: %w", err)
+	}
+	data = append(data, '\n')
+
+	if outputPath == "" {
+		if _, err = os.Stdout.Write(data); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+		return nil
+	}
+
+	if err = os.WriteFile(outputPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	a.logger.Printf("✅ Invoice %s exported to %s\n", invoice.Number, outputPath)
+	return nil
+}
+
+// buildInvoiceImportCommand creates the invoice import subcommand
+func (a *App) buildInvoiceImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import an invoice exported as JSON",
+		Long: `Store an invoice written by "invoice export".
+
+The invoice gets a new ID unless --keep-id is set, and its totals are recalculated
+rather than taken from the file. If its client does not exist in this data directory,
+the client is created from the details in the file. The import is refused when the
+invoice number is already in use, or when the file was written by a newer version of
+go-invoice.`,
+		Example: `  # Import an exported invoice
+  go-invoice invoice import --file inv.json
+
+  # Keep the invoice ID from the file
+  go-invoice invoice import --file inv.json --keep-id`,
+		Args: cobra.NoArgs,
+		RunE: a.runInvoiceImport,
+	}
+
+	cmd.Flags().StringP("file", "f", "", "JSON file written by invoice export (required)")
+	cmd.Flags().Bool("keep-id", false, "Keep the invoice ID from the file instead of generating a new one")
+
+	return cmd
+}
+
+// runInvoiceImport handles the invoice import command
+func (a *App) runInvoiceImport(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	filePath, _ := cmd.Flags().GetString("file")
+	keepID, _ := cmd.Flags().GetBool("keep-id")
+	if filePath == "" {
+		return ErrImportFileRequired
+	}
+
+	data, err := os.ReadFile(filepath.Clean(filePath))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	var export services.InvoiceExport
+	if err = json.Unmarshal(data, &export); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Create storage and services
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
+
+	result, err := invoiceService.ImportInvoice(ctx, &export, services.ImportInvoiceOptions{KeepID: keepID})
+	if err != nil {
+		return fmt.Errorf("failed to import invoice: %w", err)
+	}
+
+	invoice := result.Invoice
+	a.logger.Result(invoice.Number)
+	a.logger.Printf("✅ Invoice %s imported\n", invoice.Number)
+	a.logger.Printf("   ID: %s\n", invoice.ID)
+	if result.ClientCreated {
+		a.logger.Printf("   Client: %s (created)\n", invoice.Client.Name)
+	} else {
+		a.logger.Printf("   Client: %s\n", invoice.Client.Name)
+	}
+	a.logger.Printf("   Status: %s\n", invoice.Status)
+
+	return nil
+}
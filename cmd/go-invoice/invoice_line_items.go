@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/services"
+)
+
+// Line item command errors
+var (
+	ErrNoLineItemChanges    = fmt.Errorf("nothing to update: set at least one of --description, --date, --end-date, --hours, --rate, --amount, --quantity, --unit-price or --discount")
+	ErrLineItemFlagMismatch = fmt.Errorf("flag does not apply to this line item type")
+)
+
+// lineItemTypeFlags lists the flags that only apply to one line item type
+//
+//nolint:gochecknoglobals // Lookup table for update-line-item flag validation
+var lineItemTypeFlags = []struct {
+	name     string
+	itemType models.LineItemType
+}{
+	{"hours", models.LineItemTypeHourly},
+	{"rate", models.LineItemTypeHourly},
+	{"amount", models.LineItemTypeFixed},
+	{"quantity", models.LineItemTypeQuantity},
+	{"unit-price", models.LineItemTypeQuantity},
+}
+
+// buildInvoiceLineItemsCommand creates the invoice line-items subcommand
+func (a *App) buildInvoiceLineItemsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "line-items <invoice-id-or-number>",
+		Short: "List an invoice's line items",
+		Long: `List the line items on an invoice with their IDs, which remove-line-item and
+update-line-item take to select an item.`,
+		Example: `  # List line items
+  go-invoice invoice line-items INV-001
+
+  # As JSON
+  go-invoice invoice line-items INV-001 --output json`,
+		Args: cobra.ExactArgs(1),
+		RunE: a.runInvoiceLineItems,
+	}
+
+	cmd.Flags().String("output", "table", "Output format (table, json)")
+
+	return cmd
+}
+
+// runInvoiceLineItems handles the invoice line-items command
+func (a *App) runInvoiceLineItems(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	config, invoiceService, err := a.loadLineItemServices(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	invoice, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, args[0])
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == "json" {
+		data, err := json.MarshalIndent(invoice.LineItems, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal line items: %w", err)
+		}
+		a.logger.Println(string(data))
+		return nil
+	}
+
+	if len(invoice.LineItems) == 0 {
+		a.logger.Printf("Invoice %s has no line items\n", invoice.Number)
+		return nil
+	}
+
+	currency := invoice.GetCurrency(config.Invoice.Currency)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "ID\tTYPE\tDATE\tDESCRIPTION\tDETAILS\tTOTAL"); err != nil {
+		return fmt.Errorf("failed to write table header: %w", err)
+	}
+	for _, item := range invoice.LineItems {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			item.ID,
+			item.Type,
+			item.Date.Format(config.Invoice.DateLayout()),
+			item.Description,
+			item.GetDetails(),
+			item.GetFormattedTotal(currency),
+		); err != nil {
+			return fmt.Errorf("failed to write table row for line item %s: %w", item.ID, err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write line items: %w", err)
+	}
+
+	return nil
+}
+
+// buildInvoiceRemoveLineItemCommand creates the invoice remove-line-item subcommand
+func (a *App) buildInvoiceRemoveLineItemCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove-line-item <invoice-id-or-number> <item-id>",
+		Short: "Remove a line item from an invoice",
+		Long: `Remove a line item from a draft or unlocked invoice and recalculate its totals.
+Use 'go-invoice invoice line-items' to find the item ID. Paid and locked invoices
+are refused.`,
+		Example: `  go-invoice invoice remove-line-item INV-001 3f2a9c1e-...`,
+		Args:    cobra.ExactArgs(2),
+		RunE:    a.runInvoiceRemoveLineItem,
+	}
+
+	return cmd
+}
+
+// runInvoiceRemoveLineItem handles the invoice remove-line-item command
+func (a *App) runInvoiceRemoveLineItem(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	config, invoiceService, err := a.loadLineItemServices(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	invoice, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, args[0])
+	if err != nil {
+		return err
+	}
+
+	updated, err := invoiceService.RemoveLineItemFromInvoice(ctx, invoice.ID, args[1])
+	if err != nil {
+		return fmt.Errorf("failed to remove line item: %w", err)
+	}
+
+	currency := updated.GetCurrency(config.Invoice.Currency)
+	a.logger.Printf("✅ Line item %s removed from invoice %s\n", args[1], updated.Number)
+	a.logger.Printf("Updated Total: %s\n", money.Format(updated.Total.Float64(), currency))
+
+	return a.logger.ResultJSON(updated)
+}
+
+// buildInvoiceUpdateLineItemCommand creates the invoice update-line-item subcommand
+func (a *App) buildInvoiceUpdateLineItemCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-line-item <invoice-id-or-number> <item-id>",
+		Short: "Change a line item on an invoice",
+		Long: `Change the fields of a line item on a draft or unlocked invoice and recalculate
+its totals. Only the flags given change; the item type cannot change. Use
+'go-invoice invoice line-items' to find the item ID. Paid and locked invoices
+are refused.`,
+		Example: `  # Correct the hours of an hourly item
+  go-invoice invoice update-line-item INV-001 3f2a9c1e-... --hours 9
+
+  # Change a fixed fee and its description
+  go-invoice invoice update-line-item INV-001 7b4d... --amount 750 --description "Setup and migration"
+
+  # Remove an item's discount
+  go-invoice invoice update-line-item INV-001 7b4d... --discount none`,
+		Args: cobra.ExactArgs(2),
+		RunE: a.runInvoiceUpdateLineItem,
+	}
+
+	cmd.Flags().String("description", "", "New description")
+	cmd.Flags().String("date", "", "New date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().String("end-date", "", "New end date (DATE_FORMAT or YYYY-MM-DD, or none to clear it)")
+	cmd.Flags().Float64("hours", 0, "Hours worked (hourly items)")
+	cmd.Flags().Float64("rate", 0, "Hourly rate (hourly items)")
+	cmd.Flags().Float64("amount", 0, "Fixed amount (fixed items)")
+	cmd.Flags().Float64("quantity", 0, "Quantity (quantity items)")
+	cmd.Flags().Float64("unit-price", 0, "Unit price (quantity items)")
+	cmd.Flags().String("discount", "", "Discount, a percentage (10%) or a fixed amount (25.00), or none to clear it")
+
+	return cmd
+}
+
+// runInvoiceUpdateLineItem handles the invoice update-line-item command
+func (a *App) runInvoiceUpdateLineItem(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	if !lineItemFlagsChanged(cmd) {
+		return ErrNoLineItemChanges
+	}
+
+	config, invoiceService, err := a.loadLineItemServices(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	invoice, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, args[0])
+	if err != nil {
+		return err
+	}
+
+	updated, err := invoiceService.UpdateLineItemOnInvoice(ctx, invoice.ID, args[1], func(item *models.LineItem) error {
+		return applyLineItemFlags(ctx, cmd, item, config)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update line item: %w", err)
+	}
+
+	item := updated.FindLineItem(args[1])
+	currency := updated.GetCurrency(config.Invoice.Currency)
+	a.logger.Printf("✅ Line item updated on invoice %s\n\n", updated.Number)
+	a.logger.Printf("Description: %s\n", item.Description)
+	a.logger.Printf("Details:     %s\n", item.GetDetails())
+	a.logger.Printf("Amount:      %s\n\n", money.Format(item.Total.Float64(), currency))
+	a.logger.Printf("Updated Total: %s\n", money.Format(updated.Total.Float64(), currency))
+
+	return a.logger.ResultJSON(updated)
+}
+
+// loadLineItemServices loads the configuration and creates the invoice service for the line
+// item commands
+func (a *App) loadLineItemServices(ctx context.Context, cmd *cobra.Command) (*config.Config, *services.InvoiceService, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	invoiceStorage, clientStorage := a.createStorageInstances(cfg.Storage)
+	invoiceService := a.newInvoiceService(cfg, invoiceStorage, clientStorage)
+	return cfg, invoiceService, nil
+}
+
+// lineItemFlagsChanged reports whether any update-line-item field flag was given
+func lineItemFlagsChanged(cmd *cobra.Command) bool {
+	for _, name := range []string{"description", "date", "end-date", "hours", "rate", "amount", "quantity", "unit-price", "discount"} {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyLineItemFlags sets the fields of item given by the update-line-item flags. Hourly
+// items are billed again with the configured rounding when their hours change.
+func applyLineItemFlags(ctx context.Context, cmd *cobra.Command, item *models.LineItem, cfg *config.Config) error {
+	flags := cmd.Flags()
+	for _, typeFlag := range lineItemTypeFlags {
+		if flags.Changed(typeFlag.name) && item.Type != typeFlag.itemType {
+			return fmt.Errorf("%w: --%s is for %s items, item %s is %s",
+				ErrLineItemFlagMismatch, typeFlag.name, typeFlag.itemType, item.ID, item.Type)
+		}
+	}
+
+	if flags.Changed("description") {
+		description, _ := flags.GetString("description")
+		if err := item.UpdateDescription(ctx, description); err != nil {
+			return err
+		}
+	}
+	if flags.Changed("date") {
+		value, _ := flags.GetString("date")
+		date, err := cfg.Invoice.ParseDate(value)
+		if err != nil {
+			return fmt.Errorf("invalid --date: %w", err)
+		}
+		item.Date = date
+	}
+	if flags.Changed("end-date") {
+		value, _ := flags.GetString("end-date")
+		if value == "none" {
+			item.EndDate = nil
+		} else {
+			endDate, err := cfg.Invoice.ParseDate(value)
+			if err != nil {
+				return fmt.Errorf("invalid --end-date: %w", err)
+			}
+			item.EndDate = &endDate
+		}
+	}
+	if item.EndDate != nil && item.EndDate.Before(item.Date) {
+		return ErrEndDateBeforeDate
+	}
+
+	setFloat := func(name string, field **float64) {
+		if flags.Changed(name) {
+			value, _ := flags.GetFloat64(name)
+			*field = &value
+		}
+	}
+	setFloat("rate", &item.Rate)
+	setFloat("amount", &item.Amount)
+	setFloat("quantity", &item.Quantity)
+	setFloat("unit-price", &item.UnitPrice)
+	if flags.Changed("hours") {
+		hours, _ := flags.GetFloat64("hours")
+		item.Hours = &hours
+		item.LoggedHours = nil
+	}
+
+	if flags.Changed("discount") {
+		value, _ := flags.GetString("discount")
+		item.Discount = nil
+		if value != "none" {
+			discount, err := models.ParseDiscount(value)
+			if err != nil {
+				return err
+			}
+			item.Discount = discount
+		}
+	}
+
+	if flags.Changed("hours") {
+		if err := item.ApplyHourlyRounding(ctx, hourlyRounding(cfg)); err != nil {
+			return fmt.Errorf("failed to apply hourly rounding: %w", err)
+		}
+	}
+
+	return item.RecalculateTotal(ctx)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/services"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+)
+
+func TestApplyLineItemFlags(t *testing.T) {
+	ctx := context.Background()
+	app := &App{}
+	cfg := &config.Config{Invoice: config.InvoiceConfig{DateFormat: "eu", HourlyRoundingMinutes: 15}}
+
+	newHourly := func() *models.LineItem {
+		hours, rate := 8.0, 100.0
+		return &models.LineItem{
+			ID: "item-1", Type: models.LineItemTypeHourly, Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			Description: "Development", Hours: &hours, Rate: &rate, Total: money.FromFloat(800),
+		}
+	}
+	apply := func(item *models.LineItem, args ...string) error {
+		cmd := app.buildInvoiceUpdateLineItemCommand()
+		require.NoError(t, cmd.ParseFlags(args))
+		return applyLineItemFlags(ctx, cmd, item, cfg)
+	}
+
+	t.Run("HoursAreRoundedAndTotalled", func(t *testing.T) {
+		item := newHourly()
+		require.NoError(t, apply(item, "--hours", "8.9", "--description", "Development and review"))
+
+		assert.InDelta(t, 9.0, *item.Hours, 0.001)
+		require.NotNil(t, item.LoggedHours)
+		assert.InDelta(t, 8.9, *item.LoggedHours, 0.001)
+		assert.Equal(t, money.FromFloat(900), item.Total)
+		assert.Equal(t, "Development and review", item.Description)
+	})
+
+	t.Run("DatesAndDiscount", func(t *testing.T) {
+		item := newHourly()
+		require.NoError(t, apply(item, "--date", "02/03/2025", "--end-date", "2025-03-31", "--discount", "10%"))
+
+		assert.Equal(t, time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), item.Date)
+		require.NotNil(t, item.EndDate)
+		assert.Equal(t, money.FromFloat(720), item.Total)
+
+		require.NoError(t, apply(item, "--end-date", "none", "--discount", "none"))
+		assert.Nil(t, item.EndDate)
+		assert.Nil(t, item.Discount)
+		assert.Equal(t, money.FromFloat(800), item.Total)
+
+		require.ErrorIs(t, apply(item, "--end-date", "01/03/2025"), ErrEndDateBeforeDate)
+	})
+
+	t.Run("FlagForAnotherType", func(t *testing.T) {
+		err := apply(newHourly(), "--amount", "500")
+		require.ErrorIs(t, err, ErrLineItemFlagMismatch)
+		assert.ErrorContains(t, err, "--amount is for fixed items")
+	})
+}
+
+func TestLineItemFlagsChanged(t *testing.T) {
+	app := &App{}
+
+	cmd := app.buildInvoiceUpdateLineItemCommand()
+	require.NoError(t, cmd.ParseFlags(nil))
+	assert.False(t, lineItemFlagsChanged(cmd))
+
+	cmd = app.buildInvoiceUpdateLineItemCommand()
+	require.NoError(t, cmd.ParseFlags([]string{"--rate", "150"}))
+	assert.True(t, lineItemFlagsChanged(cmd))
+}
+
+func TestNewInvoiceServiceAppliesLegacyItemsPolicy(t *testing.T) {
+	ctx := context.Background()
+	app := &App{logger: cli.NewLogger(false)}
+	dataDir := t.TempDir()
+
+	store := jsonStorage.NewJSONStorage(dataDir, app.logger)
+	require.NoError(t, store.Initialize(ctx))
+
+	legacy := &models.Invoice{
+		ID:      "INV-LEGACY",
+		Number:  "INV-LEGACY",
+		Status:  models.StatusDraft,
+		Version: 1,
+		Client: models.Client{
+			ID: "CLIENT-1", Name: "Acme Corp", Email: "billing@acme.test", Active: true,
+			CreatedAt: time.Now(), UpdatedAt: time.Now(),
+		},
+		Date:    time.Now(),
+		DueDate: time.Now().AddDate(0, 0, 30),
+		WorkItems: []models.WorkItem{
+			{ID: "WORK-1", Date: time.Now(), Hours: 8, Rate: 100, Description: "Legacy work", Total: 800, CreatedAt: time.Now()},
+		},
+		Subtotal:  money.FromFloat(800),
+		Total:     money.FromFloat(800),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, store.CreateInvoice(ctx, legacy))
+
+	// Every command builds its invoice service through createInvoiceService or
+	// newInvoiceService, so LEGACY_WORK_ITEMS=keep reaches all of them
+	cfg := &config.Config{
+		Invoice: config.InvoiceConfig{LegacyWorkItems: string(services.LegacyItemsKeep)},
+		Storage: config.StorageConfig{DataDir: dataDir},
+	}
+	amount := 200.0
+	updated, err := app.createInvoiceService(cfg).AddLineItemToInvoice(ctx, legacy.ID, models.LineItem{
+		Type: models.LineItemTypeFixed, Date: time.Now(), Description: "Maintenance",
+		Amount: &amount, Total: money.FromFloat(200),
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, updated.WorkItems, 1)
+	assert.Len(t, updated.LineItems, 1)
+}
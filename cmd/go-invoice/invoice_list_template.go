@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// Invoice list template errors
+var (
+	ErrListTemplateRequired = fmt.Errorf("--output template requires --format with a Go template")
+	ErrInvalidListTemplate  = fmt.Errorf("invalid list template")
+	ErrTemplateMoneyValue   = fmt.Errorf("money expects an amount")
+)
+
+// outputTemplate is the --output value that formats each invoice with a user template
+const outputTemplate = "template"
+
+// listTemplateFuncs are the helper functions available to invoice list templates
+var listTemplateFuncs = template.FuncMap{ //nolint:gochecknoglobals // Read-only template function table
+	"money":        templateMoney,
+	"date":         templateDate,
+	"daysUntilDue": func(invoice *models.Invoice) int { return invoice.GetDaysUntilDue() },
+	"upper":        strings.ToUpper,
+	"lower":        strings.ToLower,
+}
+
+// parseListTemplate parses the --format template for invoice list. The template is also
+// run once against an empty invoice, so references to unknown fields are reported
+// before anything is listed.
+func parseListTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrListTemplateRequired
+	}
+
+	tmpl, err := template.New("list").Funcs(listTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidListTemplate, err)
+	}
+	if err := tmpl.Execute(io.Discard, &models.Invoice{}); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidListTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// outputInvoicesTemplate writes one line per invoice formatted with tmpl
+func (a *App) outputInvoicesTemplate(tmpl *template.Template, invoices []*models.Invoice) error {
+	var b strings.Builder
+	for _, inv := range invoices {
+		if err := tmpl.Execute(&b, inv); err != nil {
+			return fmt.Errorf("failed to format invoice %s: %w", inv.Number, err)
+		}
+		if !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+	}
+
+	a.logger.Printf("%s", b.String())
+	return nil
+}
+
+// templateMoney formats an amount in currency, e.g. {{money .Total .Currency}} or
+// {{money .AmountDue "EUR"}}
+func templateMoney(amount any, currency string) (string, error) {
+	switch value := amount.(type) {
+	case money.Amount:
+		return money.Format(value.Float64(), currency), nil
+	case float64:
+		return money.Format(value, currency), nil
+	case int:
+		return money.Format(float64(value), currency), nil
+	default:
+		return "", fmt.Errorf("%w, got %T", ErrTemplateMoneyValue, amount)
+	}
+}
+
+// templateDate formats t with an optional Go time layout, YYYY-MM-DD by default,
+// e.g. {{date .DueDate}} or {{date .Date "Jan 2, 2006"}}
+func templateDate(t time.Time, layout ...string) string {
+	if len(layout) > 0 && layout[0] != "" {
+		return t.Format(layout[0])
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func TestParseListTemplate(t *testing.T) {
+	_, err := parseListTemplate("")
+	require.ErrorIs(t, err, ErrListTemplateRequired)
+
+	// Syntax errors and unknown fields are caught before listing
+	for _, text := range []string{"{{.Number", "{{.NoSuchField}}", "{{nosuchfunc .Number}}"} {
+		_, err = parseListTemplate(text)
+		require.ErrorIs(t, err, ErrInvalidListTemplate, text)
+	}
+
+	tmpl, err := parseListTemplate("{{.Number}} {{.Client.Name}} {{money .Total .Currency}}")
+	require.NoError(t, err)
+
+	invoice := &models.Invoice{
+		Number:   "INV-001",
+		Client:   models.Client{Name: "Acme Corp"},
+		Total:    money.FromFloat(1234.5),
+		Currency: "USD",
+		DueDate:  time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+	var b strings.Builder
+	require.NoError(t, tmpl.Execute(&b, invoice))
+	assert.Equal(t, "INV-001 Acme Corp $1,234.50", b.String())
+}
+
+func TestListTemplateFuncs(t *testing.T) {
+	due := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2025-07-01", templateDate(due))
+	assert.Equal(t, "Jul 1, 2025", templateDate(due, "Jan 2, 2006"))
+
+	formatted, err := templateMoney(money.FromFloat(10), "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, money.Format(10, "EUR"), formatted)
+	formatted, err = templateMoney(2.5, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "$2.50", formatted)
+	_, err = templateMoney("ten", "USD")
+	require.ErrorIs(t, err, ErrTemplateMoneyValue)
+
+	tmpl, err := parseListTemplate("{{.Number}}: {{daysUntilDue .}} {{upper .Status}}\n")
+	require.NoError(t, err)
+	var b strings.Builder
+	require.NoError(t, tmpl.Execute(&b, &models.Invoice{Number: "INV-002", Status: models.StatusSent, DueDate: time.Now().AddDate(0, 0, 10).Add(time.Hour)}))
+	assert.Equal(t, "INV-002: 10 SENT\n", b.String())
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/services"
+)
+
+// buildInvoiceUnlockCommand creates the invoice unlock subcommand
+func (a *App) buildInvoiceUnlockCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unlock <invoice-id-or-number>",
+		Short: "Unlock a sent invoice so its financial details can be corrected",
+		Long: `Unlock a locked invoice so its financial details can change again.
+
+Invoices are locked when they are sent: line items, the discount, the tax rate,
+the crypto fee and the payment addresses can no longer change, so the invoice
+the client received stays the invoice on record. Status changes and payments
+are still recorded on a locked invoice.
+
+Unlocking is an escape hatch for correcting a mistake. A reason is required,
+and the reason, who unlocked the invoice and when are kept on the invoice and
+shown by "invoice show". Lock it again with "invoice lock" once it is fixed.`,
+		Example: `  go-invoice invoice unlock INV-001 --reason "Wrong hourly rate on the March work"
+  go-invoice invoice unlock INV-001 --reason "Client asked for a discount" --by alice`,
+		Args: cobra.ExactArgs(1),
+		RunE: a.runInvoiceUnlock,
+	}
+
+	cmd.Flags().String("reason", "", "Why the invoice is being unlocked (required)")
+	cmd.Flags().String("by", "", "Who is unlocking the invoice (default: the current user)")
+
+	return cmd
+}
+
+// buildInvoiceLockCommand creates the invoice lock subcommand
+func (a *App) buildInvoiceLockCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lock <invoice-id-or-number>",
+		Short: "Lock an invoice's financial details",
+		Long: `Lock an invoice so its line items, discount, tax rate, crypto fee and payment
+addresses can no longer change. Invoices are locked automatically when they are
+sent; use this to lock an invoice again after correcting it with "invoice unlock".`,
+		Example: `  go-invoice invoice lock INV-001`,
+		Args:    cobra.ExactArgs(1),
+		RunE:    a.runInvoiceLock,
+	}
+}
+
+// runInvoiceUnlock handles the invoice unlock command
+func (a *App) runInvoiceUnlock(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	reason, _ := cmd.Flags().GetString("reason")
+	if strings.TrimSpace(reason) == "" {
+		return models.ErrUnlockReasonRequired
+	}
+	by, _ := cmd.Flags().GetString("by")
+	by = cmp.Or(strings.TrimSpace(by), currentUsername())
+
+	invoiceService, invoice, err := a.setupInvoiceLockCommand(ctx, cmd, args[0])
+	if err != nil {
+		return err
+	}
+
+	updated, err := invoiceService.UnlockInvoice(ctx, invoice.ID, by, reason)
+	if err != nil {
+		return err
+	}
+
+	a.logger.Printf("🔓 Invoice %s unlocked by %s\n", updated.Number, by)
+	a.logger.Printf("   Reason: %s\n", strings.TrimSpace(reason))
+	a.logger.Printf("   Lock it again with 'go-invoice invoice lock %s' once it is corrected\n", updated.Number)
+	return nil
+}
+
+// runInvoiceLock handles the invoice lock command
+func (a *App) runInvoiceLock(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	invoiceService, invoice, err := a.setupInvoiceLockCommand(ctx, cmd, args[0])
+	if err != nil {
+		return err
+	}
+	if invoice.Locked {
+		a.logger.Printf("🔒 Invoice %s is already locked\n", invoice.Number)
+		return nil
+	}
+
+	updated, err := invoiceService.LockInvoice(ctx, invoice.ID)
+	if err != nil {
+		return err
+	}
+
+	a.logger.Printf("🔒 Invoice %s locked\n", updated.Number)
+	return nil
+}
+
+// setupInvoiceLockCommand loads the configuration and the invoice a lock command works on
+func (a *App) setupInvoiceLockCommand(ctx context.Context, cmd *cobra.Command, identifier string) (*services.InvoiceService, *models.Invoice, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
+
+	invoice, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, identifier)
+	if err != nil {
+		return nil, nil, err
+	}
+	return invoiceService, invoice, nil
+}
+
+// currentUsername returns the name of the user running the command, for audit records
+func currentUsername() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	return cmp.Or(os.Getenv("USER"), os.Getenv("USERNAME"), "unknown")
+}
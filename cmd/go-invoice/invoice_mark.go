@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/services"
+)
+
+// Bulk status command errors
+var (
+	ErrMarkStatusRequired      = fmt.Errorf("--status is required")
+	ErrVoidRequiresYes         = fmt.Errorf("voiding invoices requires --yes")
+	ErrStatusUpdatesIncomplete = fmt.Errorf("some invoices could not be updated")
+)
+
+// buildInvoiceMarkCommand creates the invoice mark subcommand
+func (a *App) buildInvoiceMarkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mark",
+		Short: "Change the status of all matching invoices",
+		Long: `Move every invoice matching the filters to a new status.
+
+Each invoice goes through the same business rules as "invoice update --status", so
+for example a paid invoice is never voided, and an invoice whose payments cover its
+total stays paid. An invoice that cannot be changed is reported and the rest of the
+batch continues.
+
+Voiding invoices cannot be undone from the invoice's point of view and requires --yes.`,
+		Example: `  # Mark this year's drafts for a client as sent
+  go-invoice invoice mark --status sent --client "Acme Corp" --from 2024-01-01 --current-status draft
+
+  # Preview the change without saving
+  go-invoice invoice mark --status sent --client "Acme Corp" --dry-run
+
+  # Void every draft for a client
+  go-invoice invoice mark --status voided --client "Acme Corp" --current-status draft --yes`,
+		Args: cobra.NoArgs,
+		RunE: a.runInvoiceMark,
+	}
+
+	cmd.Flags().String("status", "", "Status to move invoices to (draft, sent, paid, overdue, voided)")
+	cmd.Flags().String("current-status", "", "Only change invoices currently in this status")
+	cmd.Flags().String("client", "", "Only change invoices for this client")
+	cmd.Flags().String("from", "", "Only change invoices dated on or after this date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().String("to", "", "Only change invoices dated on or before this date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().Bool("dry-run", false, "Show what would change without saving")
+	cmd.Flags().BoolP("yes", "y", false, "Confirm destructive transitions such as voiding")
+
+	return cmd
+}
+
+// runInvoiceMark handles the invoice mark command
+func (a *App) runInvoiceMark(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	status, _ := cmd.Flags().GetString("status")
+	currentStatus, _ := cmd.Flags().GetString("current-status")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	assumeYes, _ := cmd.Flags().GetBool("yes")
+
+	if status == "" {
+		return ErrMarkStatusRequired
+	}
+	for _, value := range []string{status, currentStatus} {
+		if value != "" && !slices.Contains(models.ValidInvoiceStatuses, value) {
+			return fmt.Errorf("%w: %s (must be one of: %s)", ErrInvalidStatus, value, strings.Join(models.ValidInvoiceStatuses, ", "))
+		}
+	}
+	if status == models.StatusVoided && !dryRun && !assumeYes {
+		return ErrVoidRequiresYes
+	}
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Create storage and services
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	idGen := a.idGenerator(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
+	invoiceService.SetNotifier(a.newNotifier(config.Webhook))
+	clientService := services.NewClientService(clientStorage, invoiceStorage, a.logger, idGen)
+
+	// Build filter from the client, date and current status flags
+	filter := models.InvoiceFilter{Status: currentStatus}
+	if err = a.buildClientFilter(ctx, cmd, clientService, &filter); err != nil {
+		return err
+	}
+	if err = a.buildDateRangeFilter(cmd, &filter, config); err != nil {
+		return err
+	}
+
+	result, err := invoiceService.ListInvoices(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	if len(result.Invoices) == 0 {
+		a.logger.Println("No matching invoices found")
+		return nil
+	}
+
+	if dryRun {
+		a.logger.Printf("🔍 Dry run: previewing status %s on %d invoice(s)\n\n", status, len(result.Invoices))
+	} else {
+		a.logger.Printf("🏷️  Marking %d invoice(s) as %s\n\n", len(result.Invoices), status)
+	}
+
+	updated, skipped, failed := 0, 0, 0
+	for _, invoice := range result.Invoices {
+		if invoice.Status == status {
+			a.logger.Printf("   ➖ %s: already %s\n", invoice.Number, status)
+			skipped++
+			continue
+		}
+
+		// Check the transition first; a fully paid invoice, for one, stays paid
+		preview := *invoice
+		if err := preview.UpdateStatus(ctx, status); err != nil {
+			a.logger.Printf("   ❌ %s: %v\n", invoice.Number, err)
+			failed++
+			continue
+		}
+		if preview.Status == invoice.Status {
+			a.logger.Printf("   ➖ %s: stays %s\n", invoice.Number, invoice.Status)
+			skipped++
+			continue
+		}
+
+		if dryRun {
+			a.logger.Printf("   ✅ %s: %s → %s\n", invoice.Number, invoice.Status, preview.Status)
+			updated++
+			continue
+		}
+
+		saved, err := invoiceService.UpdateInvoice(ctx, models.UpdateInvoiceRequest{ID: invoice.ID, Status: &status})
+		if err != nil {
+			a.logger.Printf("   ❌ %s: %v\n", invoice.Number, err)
+			failed++
+			continue
+		}
+		a.logger.Printf("   ✅ %s: %s → %s\n", saved.Number, invoice.Status, saved.Status)
+		updated++
+	}
+
+	a.logger.Println("")
+	if dryRun {
+		a.logger.Printf("Would update: %d, would fail: %d, skipped: %d (no changes saved)\n", updated, failed, skipped)
+		return nil
+	}
+	a.logger.Printf("Updated: %d, failed: %d, skipped: %d\n", updated, failed, skipped)
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d failed", ErrStatusUpdatesIncomplete, failed)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// ErrPaymentAmountRequired is returned when invoice payment is run without --amount
+var ErrPaymentAmountRequired = fmt.Errorf("--amount is required")
+
+// buildInvoicePaymentCommand creates the invoice payment subcommand
+func (a *App) buildInvoicePaymentCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "payment <invoice-id-or-number>",
+		Short: "Record a full or partial payment",
+		Long: `Record a payment received against an invoice.
+
+Payments may be partial. The amount must be positive and cannot exceed the
+outstanding balance. Once the payments cover the invoice total, the invoice
+is marked as paid automatically.`,
+		Example: `  # Record a partial wire payment
+  go-invoice invoice payment INV-001 --amount 500 --date 2024-03-01 --method wire
+
+  # Record the remaining balance paid in USDC today
+  go-invoice invoice payment INV-001 --amount 250 --method usdc --reference 0xabc123`,
+		Args: cobra.ExactArgs(1),
+		RunE: a.runInvoicePayment,
+	}
+
+	cmd.Flags().Float64("amount", 0, "Amount received (required)")
+	cmd.Flags().String("date", "", "Date the payment was received (DATE_FORMAT or YYYY-MM-DD, default: today)")
+	cmd.Flags().String("method", string(models.PaymentMethodOther), "Payment method (usdc, bsv, ach, wire, other)")
+	cmd.Flags().String("reference", "", "Transaction hash, wire reference or check number")
+
+	return cmd
+}
+
+// runInvoicePayment handles the invoice payment command
+func (a *App) runInvoicePayment(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	if !cmd.Flags().Changed("amount") {
+		return ErrPaymentAmountRequired
+	}
+	amount, _ := cmd.Flags().GetFloat64("amount")
+	dateStr, _ := cmd.Flags().GetString("date")
+	methodStr, _ := cmd.Flags().GetString("method")
+	reference, _ := cmd.Flags().GetString("reference")
+
+	method, err := models.ParsePaymentMethod(methodStr)
+	if err != nil {
+		return err
+	}
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	paymentDate := time.Now()
+	if dateStr != "" {
+		paymentDate, err = config.Invoice.ParseDate(dateStr)
+		if err != nil {
+			return fmt.Errorf("invalid --date: %w", err)
+		}
+	}
+
+	// Create storage and services
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
+	invoiceService.SetNotifier(a.newNotifier(config.Webhook))
+
+	invoice, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, args[0])
+	if err != nil {
+		return err
+	}
+	previousStatus := invoice.Status
+
+	updated, err := invoiceService.RecordPayment(ctx, invoice.ID, models.Payment{
+		Amount:    amount,
+		Date:      paymentDate,
+		Method:    method,
+		Reference: strings.TrimSpace(reference),
+	})
+	if err != nil {
+		return err
+	}
+
+	currency := updated.GetCurrency(config.Invoice.Currency)
+	a.logger.Printf("✅ Payment recorded for invoice %s\n", updated.Number)
+	a.logger.Printf("   Amount: %s (%s, %s)\n", money.Format(amount, currency), method, paymentDate.Format(config.Invoice.DateLayout()))
+	a.logger.Printf("   Paid: %s of %s\n", money.Format(updated.AmountPaid(), currency), money.Format(updated.Total.Float64(), currency))
+	a.logger.Printf("   Balance Due: %s\n", money.FormatAccounting(updated.AmountDue(), currency))
+	if updated.Status != previousStatus {
+		a.logger.Printf("   Status: %s → %s\n", previousStatus, updated.Status)
+	}
+
+	return nil
+}
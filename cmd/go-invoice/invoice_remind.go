@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/notify"
+	"github.com/mrz1836/go-invoice/internal/services"
+	"github.com/mrz1836/go-invoice/internal/templates"
+)
+
+// Payment reminder errors
+var (
+	ErrRemindTargetRequired = fmt.Errorf("specify an invoice or --all-overdue")
+	ErrRemindTargetConflict = fmt.Errorf("cannot combine an invoice with --all-overdue")
+	ErrSMTPNotConfigured    = fmt.Errorf("sending reminders requires SMTP_HOST to be configured")
+	ErrReminderNoRecipient  = fmt.Errorf("client has no email address")
+	ErrInvoiceNotOverdue    = fmt.Errorf("invoice is not past its due date")
+	ErrRemindersFailed      = fmt.Errorf("some reminders could not be sent")
+)
+
+// buildInvoiceRemindCommand creates the invoice remind subcommand
+func (a *App) buildInvoiceRemindCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remind [invoice-id-or-number]",
+		Short: "Email payment reminders for overdue invoices",
+		Long: `Email the client a payment reminder for an overdue invoice, showing the
+amount due and how many days late it is, through the configured SMTP server
+(SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM).
+
+With --all-overdue every sent or overdue invoice past its due date is reminded,
+skipping invoices reminded within the last --every days (REMINDER_INTERVAL_DAYS,
+default 7). The time of each reminder is saved on the invoice.
+
+Draft, paid and voided invoices are never reminded. With --dry-run the emails are
+printed instead of sent and nothing is saved.`,
+		Example: `  # Remind one client about an overdue invoice
+  go-invoice invoice remind INV-001
+
+  # Preview the reminders that would go out
+  go-invoice invoice remind --all-overdue --dry-run
+
+  # Weekly from cron, after the overdue sweep
+  0 7 * * * go-invoice invoice remind --all-overdue --quiet`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: a.runInvoiceRemind,
+	}
+
+	cmd.Flags().Bool("all-overdue", false, "Remind every overdue invoice not reminded recently")
+	cmd.Flags().Int("every", 0, "Days to wait before reminding about the same invoice again (default: REMINDER_INTERVAL_DAYS)")
+	cmd.Flags().Bool("dry-run", false, "Print the reminder emails without sending or saving")
+
+	return cmd
+}
+
+// runInvoiceRemind handles the invoice remind command
+func (a *App) runInvoiceRemind(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	allOverdue, _ := cmd.Flags().GetBool("all-overdue")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	switch {
+	case allOverdue && len(args) > 0:
+		return ErrRemindTargetConflict
+	case !allOverdue && len(args) == 0:
+		return ErrRemindTargetRequired
+	}
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if !dryRun && cfg.Email.SMTPHost == "" {
+		return ErrSMTPNotConfigured
+	}
+
+	intervalDays := cfg.Email.ReminderIntervalDays
+	if cmd.Flags().Changed("every") {
+		intervalDays, _ = cmd.Flags().GetInt("every")
+	}
+
+	invoiceService := a.createInvoiceService(cfg)
+	now := time.Now()
+
+	var invoices []*models.Invoice
+	if allOverdue {
+		invoices, err = invoiceService.ListRemindableInvoices(ctx, now, time.Duration(intervalDays)*24*time.Hour)
+		if err != nil {
+			return err
+		}
+	} else {
+		invoice, findErr := a.getInvoiceByIDOrNumber(ctx, invoiceService, args[0])
+		if findErr != nil {
+			return findErr
+		}
+		if !invoice.CanRemind() {
+			return fmt.Errorf("%w, current status: %s", models.ErrCannotRemindInvoice, invoice.Status)
+		}
+		if !now.After(invoice.DueDate) {
+			return fmt.Errorf("%w: %s is due %s", ErrInvoiceNotOverdue, invoice.Number, cfg.Invoice.FormatDate(invoice.DueDate))
+		}
+		invoices = []*models.Invoice{invoice}
+	}
+
+	if len(invoices) == 0 {
+		a.logger.Result("0")
+		a.logger.Println("✅ No overdue invoices need a reminder")
+		return nil
+	}
+
+	var mailer notify.Mailer
+	if !dryRun {
+		mailer = notify.NewSMTPMailer(cfg.Email.SMTPHost, cfg.Email.SMTPPort, cfg.Email.Username, cfg.Email.Password)
+	}
+
+	sent, failed := a.sendReminders(ctx, cfg, invoiceService, mailer, invoices, now)
+	a.logger.Result(strconv.Itoa(sent))
+	if failed > 0 {
+		return fmt.Errorf("%w: %d failed", ErrRemindersFailed, failed)
+	}
+	return nil
+}
+
+// sendReminders emails a reminder for each invoice and records it on the invoice, or only
+// prints the emails when mailer is nil. It returns how many reminders were sent and failed.
+func (a *App) sendReminders(ctx context.Context, cfg *config.Config, invoiceService *services.InvoiceService,
+	mailer notify.Mailer, invoices []*models.Invoice, now time.Time,
+) (sent, failed int) {
+	if mailer == nil {
+		a.logger.Printf("🔍 Dry run: %d reminder(s) would be sent\n", len(invoices))
+	} else {
+		a.logger.Printf("✉️  Sending %d reminder(s)\n", len(invoices))
+	}
+
+	for _, invoice := range invoices {
+		msg, err := buildReminderMessage(cfg, invoice, now)
+		if err != nil {
+			a.logger.Printf("   ❌ %s: %v\n", invoice.Number, err)
+			failed++
+			continue
+		}
+
+		if mailer == nil {
+			a.logger.Printf("\n   To: %s\n   Subject: %s\n\n%s\n", msg.To, msg.Subject, msg.Body)
+			sent++
+			continue
+		}
+
+		if err := mailer.Send(ctx, msg); err != nil {
+			a.logger.Printf("   ❌ %s: %v\n", invoice.Number, err)
+			failed++
+			continue
+		}
+		// The email is out; failing to save only means the next run may remind again
+		if _, err := invoiceService.RecordReminder(ctx, invoice.ID, now); err != nil {
+			a.logger.Printf("   ⚠️  %s: reminder sent but not saved: %v\n", invoice.Number, err)
+		}
+		a.logger.Printf("   • %s  %s <%s>  %d days late\n", invoice.Number, invoice.Client.Name, msg.To, -invoice.DaysUntilDueAt(now))
+		sent++
+	}
+
+	return sent, failed
+}
+
+// buildReminderMessage renders the payment reminder email for invoice as of now
+func buildReminderMessage(cfg *config.Config, invoice *models.Invoice, now time.Time) (notify.Message, error) {
+	if invoice.Client.Email == "" {
+		return notify.Message{}, ErrReminderNoRecipient
+	}
+
+	currency := invoice.GetCurrency(cfg.Invoice.Currency)
+	subject, body, err := notify.RenderReminder(templates.DefaultReminderTemplate, notify.Reminder{
+		Number:              invoice.Number,
+		ClientName:          invoice.Client.Name,
+		Date:                cfg.Invoice.FormatDate(invoice.Date),
+		DueDate:             cfg.Invoice.FormatDate(invoice.DueDate),
+		DaysOverdue:         -invoice.DaysUntilDueAt(now),
+		AmountDue:           money.Format(invoice.AmountDue(), currency),
+		PaymentTerms:        cfg.Business.PaymentTerms,
+		PaymentInstructions: cfg.Business.BankDetails.PaymentInstructions,
+		BusinessName:        cfg.Business.Name,
+		BusinessEmail:       cfg.Business.Email,
+	})
+	if err != nil {
+		return notify.Message{}, err
+	}
+
+	from := cfg.Email.From
+	if from == "" {
+		from = cfg.Business.Email
+	}
+	return notify.Message{From: from, To: invoice.Client.Email, Subject: subject, Body: body}, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/notify"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+)
+
+func TestBuildReminderMessage(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	cfg := &config.Config{
+		Business: config.BusinessConfig{Name: "Consulting LLC", Email: "billing@consulting.test", PaymentTerms: "Net 30"},
+		Invoice:  config.InvoiceConfig{Currency: "USD", DateFormat: "long"},
+	}
+	invoice := &models.Invoice{
+		Number:  "INV-001",
+		Date:    now.AddDate(0, 0, -40),
+		DueDate: now.AddDate(0, 0, -10),
+		Client:  models.Client{Name: "Acme Corp", Email: "ap@acme.test"},
+		Status:  models.StatusOverdue,
+		Total:   money.FromFloat(1250),
+	}
+
+	msg, err := buildReminderMessage(cfg, invoice, now)
+	require.NoError(t, err)
+	assert.Equal(t, "billing@consulting.test", msg.From)
+	assert.Equal(t, "ap@acme.test", msg.To)
+	assert.Equal(t, "Payment reminder: invoice INV-001 is 10 days overdue", msg.Subject)
+	assert.Contains(t, msg.Body, "Amount due: $1,250.00")
+	assert.Contains(t, msg.Body, "issued on May 6, 2025, was due on June 5, 2025", "dates follow DATE_FORMAT")
+
+	cfg.Email.From = "reminders@consulting.test"
+	msg, err = buildReminderMessage(cfg, invoice, now)
+	require.NoError(t, err)
+	assert.Equal(t, "reminders@consulting.test", msg.From)
+
+	invoice.Client.Email = ""
+	_, err = buildReminderMessage(cfg, invoice, now)
+	require.ErrorIs(t, err, ErrReminderNoRecipient)
+}
+
+func TestSendReminders(t *testing.T) {
+	app := &App{logger: cli.NewLogger(false)}
+	ctx := context.Background()
+	dataDir := t.TempDir()
+
+	store := jsonStorage.NewJSONStorage(dataDir, app.logger)
+	require.NoError(t, store.Initialize(ctx))
+	clientService := app.createClientService(config.StorageConfig{DataDir: dataDir})
+	invoiceService := app.createInvoiceService(&config.Config{Storage: config.StorageConfig{DataDir: dataDir}})
+
+	client, err := clientService.CreateClient(ctx, models.CreateClientRequest{Name: "Acme Corp", Email: "ap@acme.test"})
+	require.NoError(t, err)
+	invoice, err := invoiceService.CreateInvoice(ctx, models.CreateInvoiceRequest{
+		Number:   "INV-001",
+		ClientID: client.ID,
+		Date:     time.Now().AddDate(0, 0, -40),
+		DueDate:  time.Now().AddDate(0, 0, -10),
+	})
+	require.NoError(t, err)
+	invoice.Status = models.StatusOverdue
+	invoice.Total = money.FromFloat(100)
+	require.NoError(t, invoiceService.UpdateInvoiceDirectly(ctx, invoice))
+
+	cfg := &config.Config{Business: config.BusinessConfig{Name: "Consulting LLC", Email: "billing@consulting.test"}}
+	now := time.Now()
+
+	// A dry run sends and saves nothing
+	sent, failed := app.sendReminders(ctx, cfg, invoiceService, nil, []*models.Invoice{invoice}, now)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 0, failed)
+	saved, err := invoiceService.GetInvoice(ctx, invoice.ID)
+	require.NoError(t, err)
+	assert.Nil(t, saved.LastReminderAt)
+
+	mailer := &notify.FakeMailer{}
+	sent, failed = app.sendReminders(ctx, cfg, invoiceService, mailer, []*models.Invoice{invoice}, now)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 0, failed)
+	require.Len(t, mailer.Messages(), 1)
+	assert.Equal(t, "ap@acme.test", mailer.Messages()[0].To)
+
+	saved, err = invoiceService.GetInvoice(ctx, invoice.ID)
+	require.NoError(t, err)
+	require.NotNil(t, saved.LastReminderAt)
+	assert.True(t, saved.LastReminderAt.Equal(now))
+
+	// A failed send is not recorded
+	mailer.Err = errors.New("connection refused") //nolint:err113 // test-only error
+	sent, failed = app.sendReminders(ctx, cfg, invoiceService, mailer, []*models.Invoice{saved}, now.Add(time.Hour))
+	assert.Equal(t, 0, sent)
+	assert.Equal(t, 1, failed)
+	saved, err = invoiceService.GetInvoice(ctx, invoice.ID)
+	require.NoError(t, err)
+	assert.True(t, saved.LastReminderAt.Equal(now))
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/services"
+)
+
+// ErrSearchQueryRequired is returned when invoice search is given a blank query
+var ErrSearchQueryRequired = fmt.Errorf("search query is required")
+
+// buildInvoiceSearchCommand creates the invoice search subcommand
+func (a *App) buildInvoiceSearchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search [query]",
+		Short: "Find invoices by text",
+		Long: `Search invoice numbers, client names, descriptions, line item descriptions and
+notes for every word of the query, ignoring case.
+
+Unlike "invoice list", which filters on exact field values, search ranks the results by
+relevance: a word in the invoice number or client name counts for more than one in the
+notes. Use --status and the date filters to narrow the invoices searched.`,
+		Example: `  # Find consulting invoices
+  go-invoice invoice search "consulting"
+
+  # Unpaid invoices mentioning a project, this year
+  go-invoice invoice search "website redesign" --status sent --from 2025-01-01
+
+  # Best five matches as JSON, with their scores
+  go-invoice invoice search "acme" --limit 5 --output json`,
+		Args: cobra.ExactArgs(1),
+		RunE: a.runInvoiceSearch,
+	}
+
+	cmd.Flags().String("status", "", "Only search invoices with this status (draft, sent, paid, overdue, voided)")
+	cmd.Flags().String("from", "", "Only search invoices dated on or after this date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().String("to", "", "Only search invoices dated on or before this date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().Int("limit", 20, "Maximum number of results (0 = no limit)")
+	cmd.Flags().String("output", "table", "Output format (table, json)")
+	cmd.Flags().Bool("include-deleted", false, "Also search deleted invoices (in the trash)")
+
+	return cmd
+}
+
+// runInvoiceSearch handles the invoice search command
+func (a *App) runInvoiceSearch(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	query := strings.TrimSpace(args[0])
+	if query == "" {
+		return ErrSearchQueryRequired
+	}
+	outputFormat, _ := cmd.Flags().GetString("output")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Build the structured filters that narrow the search
+	var filter models.InvoiceFilter
+	if err = a.buildStatusFilter(cmd, &filter); err != nil {
+		return err
+	}
+	if err = a.buildDateRangeFilter(cmd, &filter, config); err != nil {
+		return err
+	}
+	filter.IncludeDeleted, _ = cmd.Flags().GetBool("include-deleted")
+
+	// Create storage and services
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
+
+	matches, err := invoiceService.SearchInvoices(ctx, query, services.InvoiceSearchOptions{Filter: filter, Limit: limit})
+	if err != nil {
+		return fmt.Errorf("failed to search invoices: %w", err)
+	}
+
+	for _, match := range matches {
+		applyDefaultCurrency(config, match.Invoice)
+	}
+
+	if outputFormat == "json" {
+		data, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal search results: %w", err)
+		}
+		a.logger.Println(string(data))
+		return nil
+	}
+	return a.outputInvoiceMatchesTable(query, matches, config.Invoice.DateLayout())
+}
+
+// outputInvoiceMatchesTable prints search results in ranked order with the fields each matched
+func (a *App) outputInvoiceMatchesTable(query string, matches []*services.InvoiceMatch, dateLayout string) error {
+	if len(matches) == 0 {
+		a.logger.Printf("No invoices found matching %q\n", query)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "NUMBER\tCLIENT\tDATE\tSTATUS\tAMOUNT\tSCORE\tMATCHED"); err != nil {
+		return fmt.Errorf("failed to write table header: %w", err)
+	}
+	if _, err := fmt.Fprintln(w, "------\t------\t----\t------\t------\t-----\t-------"); err != nil {
+		return fmt.Errorf("failed to write table separator: %w", err)
+	}
+
+	for _, match := range matches {
+		inv := match.Invoice
+		status := inv.Status
+		if inv.IsDeleted() {
+			status += " (deleted)"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			inv.Number,
+			inv.Client.Name,
+			inv.Date.Format(dateLayout),
+			status,
+			money.Format(inv.Total.Float64(), inv.Currency),
+			match.Score,
+			strings.Join(match.Fields, ", "),
+		); err != nil {
+			return fmt.Errorf("failed to write table row for invoice %s: %w", inv.Number, err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+	return nil
+}
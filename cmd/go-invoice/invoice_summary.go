@@ -0,0 +1,290 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// ErrInvalidGroupBy is returned when invoice list is run with an unknown --group-by value
+var ErrInvalidGroupBy = fmt.Errorf("invalid group-by (must be client, status, or month)")
+
+// Invoice list grouping options
+const (
+	groupByClient = "client"
+	groupByStatus = "status"
+	groupByMonth  = "month"
+)
+
+// agingBuckets are the age ranges, in days since the invoice date, used to age unpaid invoices
+var agingBuckets = []struct {
+	Label   string
+	MaxDays int
+}{
+	{Label: "0-30", MaxDays: 30},
+	{Label: "31-60", MaxDays: 60},
+	{Label: "61-90", MaxDays: 90},
+	{Label: "90+", MaxDays: math.MaxInt},
+}
+
+// currencyTotals holds invoice amounts for a single currency
+type currencyTotals struct {
+	Currency string       `json:"currency"`
+	Total    money.Amount `json:"total"`
+	Paid     money.Amount `json:"paid"`
+	Unpaid   money.Amount `json:"unpaid"`
+}
+
+// agingBucket holds the unpaid invoices whose age falls in one aging range
+type agingBucket struct {
+	Label   string                  `json:"label"`
+	Count   int                     `json:"count"`
+	Amounts map[string]money.Amount `json:"amounts"`
+}
+
+// invoiceGroup holds the subtotals of the invoices sharing a client, status or month
+type invoiceGroup struct {
+	Key    string            `json:"key"`
+	Count  int               `json:"count"`
+	Totals []*currencyTotals `json:"totals"`
+}
+
+// invoiceSummary is the summary printed by invoice list --summary and --group-by
+type invoiceSummary struct {
+	Count    int               `json:"count"`
+	Statuses map[string]int    `json:"statuses"`
+	Totals   []*currencyTotals `json:"totals"`
+	Aging    []agingBucket     `json:"aging"`
+	GroupBy  string            `json:"group_by,omitempty"`
+	Groups   []invoiceGroup    `json:"groups,omitempty"`
+}
+
+// isUnpaid reports whether an invoice's total counts as unpaid in summaries
+func isUnpaid(inv *models.Invoice) bool {
+	switch inv.Status {
+	case models.StatusDraft, models.StatusSent, models.StatusOverdue:
+		return true
+	default:
+		return false
+	}
+}
+
+// summarizeInvoicesByCurrency totals invoice amounts per currency, so amounts in different
+// currencies are never added together. It returns the totals and the currencies in sorted order.
+func summarizeInvoicesByCurrency(invoices []*models.Invoice) (map[string]*currencyTotals, []string) {
+	totals := make(map[string]*currencyTotals)
+	var currencies []string
+
+	for _, inv := range invoices {
+		t, ok := totals[inv.Currency]
+		if !ok {
+			t = &currencyTotals{Currency: inv.Currency}
+			totals[inv.Currency] = t
+			currencies = append(currencies, inv.Currency)
+		}
+
+		t.Total += inv.Total
+		switch {
+		case isUnpaid(inv):
+			t.Unpaid += inv.Total
+		case inv.Status == models.StatusPaid:
+			t.Paid += inv.Total
+		}
+	}
+
+	sort.Strings(currencies)
+	return totals, currencies
+}
+
+// currencyTotalsList returns the per-currency totals of invoices in currency order
+func currencyTotalsList(invoices []*models.Invoice) []*currencyTotals {
+	totals, currencies := summarizeInvoicesByCurrency(invoices)
+	list := make([]*currencyTotals, 0, len(currencies))
+	for _, currency := range currencies {
+		list = append(list, totals[currency])
+	}
+	return list
+}
+
+// agingBucketIndex returns the index in agingBuckets for an invoice that is ageDays old
+func agingBucketIndex(ageDays int) int {
+	for i, bucket := range agingBuckets {
+		if ageDays <= bucket.MaxDays {
+			return i
+		}
+	}
+	return len(agingBuckets) - 1
+}
+
+// ageInvoices sorts the unpaid invoices into the aging buckets by GetAgeInDays
+func ageInvoices(invoices []*models.Invoice) []agingBucket {
+	buckets := make([]agingBucket, len(agingBuckets))
+	for i, bucket := range agingBuckets {
+		buckets[i] = agingBucket{Label: bucket.Label, Amounts: make(map[string]money.Amount)}
+	}
+
+	for _, inv := range invoices {
+		if !isUnpaid(inv) {
+			continue
+		}
+		bucket := &buckets[agingBucketIndex(inv.GetAgeInDays())]
+		bucket.Count++
+		bucket.Amounts[inv.Currency] += inv.Total
+	}
+
+	return buckets
+}
+
+// invoiceGroupKey returns the group an invoice belongs to for groupBy
+func invoiceGroupKey(inv *models.Invoice, groupBy string) string {
+	switch groupBy {
+	case groupByClient:
+		if inv.Client.Name != "" {
+			return inv.Client.Name
+		}
+		return string(inv.Client.ID)
+	case groupByStatus:
+		return inv.Status
+	default:
+		return inv.Date.Format("2006-01")
+	}
+}
+
+// groupInvoices subtotals invoices per client, status or month, in key order
+func groupInvoices(invoices []*models.Invoice, groupBy string) []invoiceGroup {
+	members := make(map[string][]*models.Invoice)
+	var keys []string
+	for _, inv := range invoices {
+		key := invoiceGroupKey(inv, groupBy)
+		if _, ok := members[key]; !ok {
+			keys = append(keys, key)
+		}
+		members[key] = append(members[key], inv)
+	}
+	sort.Strings(keys)
+
+	groups := make([]invoiceGroup, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, invoiceGroup{
+			Key:    key,
+			Count:  len(members[key]),
+			Totals: currencyTotalsList(members[key]),
+		})
+	}
+	return groups
+}
+
+// buildInvoiceSummary summarizes invoices, grouping them when groupBy is set
+func buildInvoiceSummary(invoices []*models.Invoice, groupBy string) *invoiceSummary {
+	summary := &invoiceSummary{
+		Count:    len(invoices),
+		Statuses: make(map[string]int),
+		Totals:   currencyTotalsList(invoices),
+		Aging:    ageInvoices(invoices),
+		GroupBy:  groupBy,
+	}
+	for _, inv := range invoices {
+		summary.Statuses[inv.Status]++
+	}
+	if groupBy != "" {
+		summary.Groups = groupInvoices(invoices, groupBy)
+	}
+	return summary
+}
+
+// validateGroupBy checks a --group-by value; empty means no grouping
+func validateGroupBy(groupBy string) error {
+	switch groupBy {
+	case "", groupByClient, groupByStatus, groupByMonth:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidGroupBy, groupBy)
+	}
+}
+
+// outputInvoicesJSONWithSummary prints the invoices together with their summary as one JSON object
+func (a *App) outputInvoicesJSONWithSummary(invoices []*models.Invoice, summary *invoiceSummary) error {
+	if invoices == nil {
+		invoices = []*models.Invoice{}
+	}
+	data, err := json.MarshalIndent(struct {
+		Invoices []*models.Invoice `json:"invoices"`
+		Summary  *invoiceSummary   `json:"summary"`
+	}{Invoices: invoices, Summary: summary}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal invoices: %w", err)
+	}
+	a.logger.Println(string(data))
+	return nil
+}
+
+func (a *App) displayInvoiceSummary(summary *invoiceSummary) {
+	var text strings.Builder
+	writeInvoiceSummary(&text, summary)
+	a.logger.Printf("%s", text.String())
+}
+
+// writeInvoiceSummary writes the counts, per-currency totals and aging of summary to w
+func writeInvoiceSummary(w io.Writer, summary *invoiceSummary) {
+	_, _ = fmt.Fprintf(w, "\n📊 Summary\n")
+	_, _ = fmt.Fprintf(w, "─────────\n")
+	_, _ = fmt.Fprintf(w, "Total Invoices: %d\n", summary.Count)
+	_, _ = fmt.Fprintf(w, "  Draft: %d\n", summary.Statuses[models.StatusDraft])
+	_, _ = fmt.Fprintf(w, "  Sent: %d\n", summary.Statuses[models.StatusSent])
+	_, _ = fmt.Fprintf(w, "  Paid: %d\n", summary.Statuses[models.StatusPaid])
+	_, _ = fmt.Fprintf(w, "  Overdue: %d\n", summary.Statuses[models.StatusOverdue])
+
+	for _, t := range summary.Totals {
+		_, _ = fmt.Fprintf(w, "\n")
+		_, _ = fmt.Fprintf(w, "Total Amount: %s\n", money.Format(t.Total.Float64(), t.Currency))
+		_, _ = fmt.Fprintf(w, "  Paid: %s\n", money.Format(t.Paid.Float64(), t.Currency))
+		_, _ = fmt.Fprintf(w, "  Unpaid: %s\n", money.Format(t.Unpaid.Float64(), t.Currency))
+	}
+
+	_, _ = fmt.Fprintf(w, "\n")
+	_, _ = fmt.Fprintf(w, "Unpaid by Age (days)\n")
+	for _, bucket := range summary.Aging {
+		_, _ = fmt.Fprintf(w, "  %s: %d%s\n", bucket.Label, bucket.Count, formatCurrencyAmounts(bucket.Amounts))
+	}
+}
+
+// displayInvoiceGroups prints the subtotals of each invoice group
+func (a *App) displayInvoiceGroups(summary *invoiceSummary) {
+	a.logger.Printf("\n📊 By %s\n", summary.GroupBy)
+	a.logger.Printf("─────────\n")
+	for _, group := range summary.Groups {
+		a.logger.Printf("%s (%d)\n", group.Key, group.Count)
+		for _, t := range group.Totals {
+			a.logger.Printf("  Total: %s  Paid: %s  Outstanding: %s\n",
+				money.Format(t.Total.Float64(), t.Currency),
+				money.Format(t.Paid.Float64(), t.Currency),
+				money.Format(t.Unpaid.Float64(), t.Currency))
+		}
+	}
+}
+
+// formatCurrencyAmounts formats per-currency amounts as " — $10.00, 5,00 €", or "" when empty
+func formatCurrencyAmounts(amounts map[string]money.Amount) string {
+	currencies := make([]string, 0, len(amounts))
+	for currency := range amounts {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	formatted := ""
+	for i, currency := range currencies {
+		if i == 0 {
+			formatted = " — "
+		} else {
+			formatted += ", "
+		}
+		formatted += money.Format(amounts[currency].Float64(), currency)
+	}
+	return formatted
+}
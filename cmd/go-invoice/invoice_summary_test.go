@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func TestAgingBucketIndex(t *testing.T) {
+	tests := map[int]string{
+		0:   "0-30",
+		30:  "0-30",
+		31:  "31-60",
+		60:  "31-60",
+		61:  "61-90",
+		90:  "61-90",
+		91:  "90+",
+		400: "90+",
+	}
+
+	for days, label := range tests {
+		assert.Equal(t, label, agingBuckets[agingBucketIndex(days)].Label, "age %d days", days)
+	}
+}
+
+func TestBuildInvoiceSummary(t *testing.T) {
+	now := time.Now()
+	acme := models.Client{ID: "CLIENT-1", Name: "Acme"}
+	globex := models.Client{ID: "CLIENT-2", Name: "Globex"}
+	invoices := []*models.Invoice{
+		{Client: acme, Currency: "USD", Total: money.FromFloat(100), Status: models.StatusPaid, Date: now.AddDate(0, 0, -5)},
+		{Client: acme, Currency: "USD", Total: money.FromFloat(40), Status: models.StatusSent, Date: now.AddDate(0, 0, -45)},
+		{Client: globex, Currency: "EUR", Total: money.FromFloat(75), Status: models.StatusOverdue, Date: now.AddDate(0, 0, -120)},
+	}
+
+	t.Run("Totals", func(t *testing.T) {
+		summary := buildInvoiceSummary(invoices, "")
+
+		assert.Equal(t, 3, summary.Count)
+		assert.Equal(t, 1, summary.Statuses[models.StatusPaid])
+		assert.Empty(t, summary.Groups)
+		require.Len(t, summary.Totals, 2)
+		assert.Equal(t, "EUR", summary.Totals[0].Currency)
+		assert.Equal(t, money.FromFloat(40), summary.Totals[1].Unpaid)
+	})
+
+	t.Run("Aging", func(t *testing.T) {
+		aging := buildInvoiceSummary(invoices, "").Aging
+
+		require.Len(t, aging, len(agingBuckets))
+		assert.Equal(t, 0, aging[0].Count, "paid invoices are not aged")
+		assert.Equal(t, 1, aging[1].Count)
+		assert.Equal(t, money.FromFloat(40), aging[1].Amounts["USD"])
+		assert.Equal(t, 1, aging[3].Count)
+		assert.Equal(t, money.FromFloat(75), aging[3].Amounts["EUR"])
+	})
+
+	t.Run("GroupByClient", func(t *testing.T) {
+		groups := buildInvoiceSummary(invoices, groupByClient).Groups
+
+		require.Len(t, groups, 2)
+		assert.Equal(t, "Acme", groups[0].Key)
+		assert.Equal(t, 2, groups[0].Count)
+		require.Len(t, groups[0].Totals, 1)
+		assert.Equal(t, money.FromFloat(100), groups[0].Totals[0].Paid)
+		assert.Equal(t, money.FromFloat(40), groups[0].Totals[0].Unpaid)
+		assert.Equal(t, "Globex", groups[1].Key)
+	})
+
+	t.Run("GroupByMonth", func(t *testing.T) {
+		groups := groupInvoices([]*models.Invoice{
+			{Currency: "USD", Total: money.FromFloat(10), Date: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)},
+			{Currency: "USD", Total: money.FromFloat(20), Date: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+			{Currency: "USD", Total: money.FromFloat(30), Date: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		}, groupByMonth)
+
+		require.Len(t, groups, 2)
+		assert.Equal(t, "2025-02", groups[0].Key)
+		assert.Equal(t, "2025-03", groups[1].Key)
+		assert.Equal(t, money.FromFloat(40), groups[1].Totals[0].Total)
+	})
+
+	t.Run("GroupByStatus", func(t *testing.T) {
+		groups := groupInvoices(invoices, groupByStatus)
+
+		require.Len(t, groups, 3)
+		assert.Equal(t, models.StatusOverdue, groups[0].Key)
+	})
+}
+
+func TestValidateGroupBy(t *testing.T) {
+	for _, groupBy := range []string{"", groupByClient, groupByStatus, groupByMonth} {
+		require.NoError(t, validateGroupBy(groupBy))
+	}
+	require.ErrorIs(t, validateGroupBy("year"), ErrInvalidGroupBy)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/services"
+)
+
+// Overdue sweep errors
+var (
+	ErrWebhookNotConfigured = fmt.Errorf("--notify requires WEBHOOK_URL to be configured")
+	ErrOverdueSweepFailed   = fmt.Errorf("some overdue invoices could not be updated")
+)
+
+// buildInvoiceSweepOverdueCommand creates the invoice sweep-overdue subcommand
+func (a *App) buildInvoiceSweepOverdueCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sweep-overdue",
+		Short: "Mark sent invoices past their due date as overdue",
+		Long: `Find every sent invoice past its due date and change its status to overdue,
+printing how many changed.
+
+Draft, paid and voided invoices are never touched, and invoices that are already
+overdue are left as they are, so running the sweep again changes nothing until
+another invoice falls due. Run it from cron to drive reminders.
+
+With --notify the configured webhook (WEBHOOK_URL) is notified for each invoice
+that becomes overdue.`,
+		Example: `  # Mark overdue invoices
+  go-invoice invoice sweep-overdue
+
+  # Preview which invoices would change
+  go-invoice invoice sweep-overdue --dry-run
+
+  # Nightly from cron, notifying the webhook
+  0 6 * * * go-invoice invoice sweep-overdue --notify --quiet`,
+		Args: cobra.NoArgs,
+		RunE: a.runInvoiceSweepOverdue,
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show which invoices would become overdue without saving")
+	cmd.Flags().Bool("notify", false, "Notify the configured webhook for each invoice that becomes overdue")
+
+	return cmd
+}
+
+// runInvoiceSweepOverdue handles the invoice sweep-overdue command
+func (a *App) runInvoiceSweepOverdue(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	notifyWebhook, _ := cmd.Flags().GetBool("notify")
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if notifyWebhook && config.Webhook.URL == "" {
+		return ErrWebhookNotConfigured
+	}
+
+	invoiceService := a.createInvoiceService(config)
+	if notifyWebhook && !dryRun {
+		invoiceService.SetNotifier(a.newNotifier(config.Webhook))
+	}
+
+	result, err := invoiceService.SweepOverdueInvoices(ctx, dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to sweep overdue invoices: %w", err)
+	}
+
+	a.logger.Result(strconv.Itoa(len(result.Overdue)))
+	a.displayOverdueSweep(result, config.Invoice.DateLayout())
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%w: %d failed", ErrOverdueSweepFailed, len(result.Failed))
+	}
+	return nil
+}
+
+// displayOverdueSweep prints the invoices an overdue sweep changed, or would change
+func (a *App) displayOverdueSweep(result *services.OverdueSweepResult, dateLayout string) {
+	switch {
+	case len(result.Overdue) == 0 && len(result.Failed) == 0:
+		a.logger.Println("✅ No sent invoices are past their due date")
+		return
+	case result.DryRun:
+		a.logger.Printf("🔍 Dry run: %d invoice(s) would be marked overdue\n", len(result.Overdue))
+	default:
+		a.logger.Printf("⏰ Marked %d invoice(s) as overdue\n", len(result.Overdue))
+	}
+
+	for _, invoice := range result.Overdue {
+		a.logger.Printf("   • %s  %s  due %s (%d days late)\n", invoice.Number, invoice.Client.Name,
+			invoice.DueDate.Format(dateLayout), -invoice.GetDaysUntilDue())
+	}
+	for _, number := range result.Failed {
+		a.logger.Printf("   ❌ %s: could not be updated\n", number)
+	}
+}
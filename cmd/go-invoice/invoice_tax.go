@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/services"
+)
+
+// Tax rate command errors
+var (
+	ErrTaxRateRequired          = fmt.Errorf("--rate is required")
+	ErrIssuedInvoicesNeedForce  = fmt.Errorf("changing tax on sent, overdue or paid invoices requires --force")
+	ErrTaxRateUpdatesIncomplete = fmt.Errorf("some invoices could not be updated")
+)
+
+// buildInvoiceSetTaxRateCommand creates the invoice set-tax-rate subcommand
+func (a *App) buildInvoiceSetTaxRateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-tax-rate",
+		Short: "Apply a new tax rate to matching invoices",
+		Long: `Apply a new tax rate to every invoice matching the filters and recalculate totals.
+
+By default only draft invoices are changed. Each invoice is saved individually with
+version checking, so an invoice modified concurrently is reported as failed rather
+than overwritten.
+
+Changing tax on invoices that have already been sent or paid alters documents your
+client has received and may have legal and accounting implications. This requires
+--force and should normally be handled with a credit note instead.`,
+		Example: `  # Preview a VAT change on all drafts
+  go-invoice invoice set-tax-rate --rate 0.21 --dry-run
+
+  # Apply to drafts for one client
+  go-invoice invoice set-tax-rate --rate 0.21 --client "Acme Corp"
+
+  # Retroactively change sent invoices (use with care)
+  go-invoice invoice set-tax-rate --rate 0.21 --status sent --force`,
+		RunE: a.runInvoiceSetTaxRate,
+	}
+
+	cmd.Flags().Float64("rate", -1, "New tax rate as a decimal between 0 and 1 (e.g. 0.21 for 21%)")
+	cmd.Flags().String("status", models.StatusDraft, "Only update invoices with this status (draft, sent, overdue, paid)")
+	cmd.Flags().String("client", "", "Only update invoices for this client")
+	cmd.Flags().Bool("dry-run", false, "Show what would change without saving")
+	cmd.Flags().Bool("force", false, "Allow changing tax on already issued (sent, overdue, paid) invoices")
+
+	return cmd
+}
+
+// runInvoiceSetTaxRate handles the invoice set-tax-rate command
+func (a *App) runInvoiceSetTaxRate(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	rate, _ := cmd.Flags().GetFloat64("rate")
+	status, _ := cmd.Flags().GetString("status")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if !cmd.Flags().Changed("rate") {
+		return ErrTaxRateRequired
+	}
+	if rate < 0 || rate > 1 {
+		return fmt.Errorf("%w: %v (use 0.21 for 21%%)", models.ErrTaxRateOutOfRange, rate)
+	}
+	if status == models.StatusVoided {
+		return fmt.Errorf("%w: %s", models.ErrCannotChangeTaxOnVoidedInvoice, status)
+	}
+	if status != models.StatusDraft && !force {
+		return fmt.Errorf("%w (status: %s)", ErrIssuedInvoicesNeedForce, status)
+	}
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Create storage and services
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	idGen := a.idGenerator(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
+	clientService := services.NewClientService(clientStorage, invoiceStorage, a.logger, idGen)
+
+	// Build filter from status and client flags
+	filter := models.InvoiceFilter{}
+	if err = a.buildStatusFilter(cmd, &filter); err != nil {
+		return err
+	}
+	if err = a.buildClientFilter(ctx, cmd, clientService, &filter); err != nil {
+		return err
+	}
+
+	result, err := invoiceService.ListInvoices(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	if len(result.Invoices) == 0 {
+		a.logger.Println("No matching invoices found")
+		return nil
+	}
+
+	if status != models.StatusDraft {
+		a.logger.Println("⚠️  WARNING: You are changing the tax rate on invoices that have already been issued.")
+		a.logger.Println("⚠️  The client may already hold a copy with the original tax amount. Changing it")
+		a.logger.Println("⚠️  retroactively can have legal and accounting consequences; consider a credit note.")
+		a.logger.Println("")
+	}
+
+	if dryRun {
+		a.logger.Printf("🔍 Dry run: previewing tax rate %.2f%% on %d invoice(s)\n\n", rate*100, len(result.Invoices))
+	} else {
+		a.logger.Printf("🧾 Applying tax rate %.2f%% to %d invoice(s)\n\n", rate*100, len(result.Invoices))
+	}
+
+	updated, unchanged, failed := 0, 0, 0
+	for _, invoice := range result.Invoices {
+		if invoice.TaxRate == rate {
+			a.logger.Printf("   ➖ %s: already at %.2f%%\n", invoice.Number, rate*100)
+			unchanged++
+			continue
+		}
+
+		oldRate, oldTotal := invoice.TaxRate, invoice.Total
+
+		if dryRun {
+			preview := *invoice
+			preview.TaxRate = rate
+			if err := preview.RecalculateTotals(ctx); err != nil {
+				return fmt.Errorf("failed to preview invoice %s: %w", invoice.Number, err)
+			}
+			a.displayTaxRateChange(invoice.Number, oldRate, rate, oldTotal, preview.Total)
+			updated++
+			continue
+		}
+
+		saved, err := invoiceService.SetInvoiceTaxRate(ctx, invoice.ID, rate, force)
+		if err != nil {
+			a.logger.Printf("   ❌ %s: %v\n", invoice.Number, err)
+			failed++
+			continue
+		}
+		a.displayTaxRateChange(saved.Number, oldRate, rate, oldTotal, saved.Total)
+		updated++
+	}
+
+	a.logger.Println("")
+	if dryRun {
+		a.logger.Printf("Would update: %d, unchanged: %d (no changes saved)\n", updated, unchanged)
+		return nil
+	}
+	a.logger.Printf("Updated: %d, unchanged: %d, failed: %d\n", updated, unchanged, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%w: %d failed", ErrTaxRateUpdatesIncomplete, failed)
+	}
+	return nil
+}
+
+// displayTaxRateChange prints the before and after values for a single invoice
+func (a *App) displayTaxRateChange(number string, oldRate, newRate float64, oldTotal, newTotal money.Amount) {
+	a.logger.Printf("   ✅ %s: tax %.2f%% → %.2f%%, total $%.2f → $%.2f (%+.2f)\n",
+		number, oldRate*100, newRate*100, oldTotal, newTotal, newTotal-oldTotal)
+}
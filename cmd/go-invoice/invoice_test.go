@@ -1,15 +1,20 @@
 package main
 
 import (
+	"bytes"
+	"encoding/csv"
 	"testing"
 	"time"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mrz1836/go-invoice/internal/cli"
 	"github.com/mrz1836/go-invoice/internal/config"
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/render"
 )
 
 func TestBuildInvoiceRecalculateCommand(t *testing.T) {
@@ -32,11 +37,48 @@ func TestBuildInvoiceRecalculateCommand(t *testing.T) {
 	assert.NotNil(t, cmd.RunE, "Command should have RunE function")
 }
 
-func TestCreateInvoiceData(t *testing.T) {
+func TestBuildInvoiceMarkCommand(t *testing.T) {
 	app := &App{
 		logger: cli.NewLogger(false),
 	}
 
+	cmd := app.buildInvoiceMarkCommand()
+
+	assert.Equal(t, "mark", cmd.Use)
+	assert.NotEmpty(t, cmd.Example, "Command should have examples")
+	assert.NotNil(t, cmd.RunE, "Command should have RunE function")
+
+	for _, name := range []string{"status", "current-status", "client", "from", "to", "dry-run", "yes"} {
+		assert.NotNil(t, cmd.Flags().Lookup(name), "missing flag --%s", name)
+	}
+}
+
+func TestRunInvoiceMarkValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want error
+	}{
+		{"missing status", nil, ErrMarkStatusRequired},
+		{"unknown status", []string{"--status", "archived"}, ErrInvalidStatus},
+		{"unknown current status", []string{"--status", "sent", "--current-status", "bogus"}, ErrInvalidStatus},
+		{"void without yes", []string{"--status", "voided"}, ErrVoidRequiresYes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &App{logger: cli.NewLogger(false)}
+			cmd := app.buildInvoiceMarkCommand()
+			cmd.SetContext(t.Context())
+			require.NoError(t, cmd.ParseFlags(tt.args))
+
+			err := app.runInvoiceMark(cmd, nil)
+			require.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestBuildTemplateData(t *testing.T) {
 	cfg := &config.Config{
 		Business: config.BusinessConfig{
 			Name:    "Test Business",
@@ -77,7 +119,7 @@ func TestCreateInvoiceData(t *testing.T) {
 			LineItems: []models.LineItem{},
 		}
 
-		data := app.createInvoiceData(invoice, cfg)
+		data := render.BuildTemplateData(cfg, invoice, nil)
 
 		assert.InDelta(t, 12.0, data.TotalHours, 0.01, "Should count hours from WorkItems")
 	})
@@ -101,7 +143,7 @@ func TestCreateInvoiceData(t *testing.T) {
 					Description: "Development",
 					Hours:       &hours1,
 					Rate:        &rate1,
-					Total:       1250.0,
+					Total:       money.FromFloat(1250.0),
 					CreatedAt:   time.Now(),
 				},
 				{
@@ -111,13 +153,13 @@ func TestCreateInvoiceData(t *testing.T) {
 					Description: "Consulting",
 					Hours:       &hours2,
 					Rate:        &rate2,
-					Total:       750.0,
+					Total:       money.FromFloat(750.0),
 					CreatedAt:   time.Now(),
 				},
 			},
 		}
 
-		data := app.createInvoiceData(invoice, cfg)
+		data := render.BuildTemplateData(cfg, invoice, nil)
 
 		assert.InDelta(t, 15.0, data.TotalHours, 0.01, "Should count hours from hourly LineItems")
 	})
@@ -149,13 +191,13 @@ func TestCreateInvoiceData(t *testing.T) {
 					Description: "New work",
 					Hours:       &hours,
 					Rate:        &rate,
-					Total:       1000.0,
+					Total:       money.FromFloat(1000.0),
 					CreatedAt:   time.Now(),
 				},
 			},
 		}
 
-		data := app.createInvoiceData(invoice, cfg)
+		data := render.BuildTemplateData(cfg, invoice, nil)
 
 		assert.InDelta(t, 13.0, data.TotalHours, 0.01, "Should count hours from both WorkItems and LineItems")
 	})
@@ -178,7 +220,7 @@ func TestCreateInvoiceData(t *testing.T) {
 					Description: "Hourly work",
 					Hours:       &hours,
 					Rate:        &rate,
-					Total:       1250.0,
+					Total:       money.FromFloat(1250.0),
 					CreatedAt:   time.Now(),
 				},
 				{
@@ -187,13 +229,13 @@ func TestCreateInvoiceData(t *testing.T) {
 					Date:        time.Now(),
 					Description: "Fixed price work",
 					Amount:      &fixedAmount,
-					Total:       1000.0,
+					Total:       money.FromFloat(1000.0),
 					CreatedAt:   time.Now(),
 				},
 			},
 		}
 
-		data := app.createInvoiceData(invoice, cfg)
+		data := render.BuildTemplateData(cfg, invoice, nil)
 
 		// Should only count hours from hourly items, not fixed
 		assert.InDelta(t, 10.0, data.TotalHours, 0.01, "Should only count hours from hourly LineItems, not fixed")
@@ -218,7 +260,7 @@ func TestCreateInvoiceData(t *testing.T) {
 					Description: "Hourly work",
 					Hours:       &hours,
 					Rate:        &rate,
-					Total:       1000.0,
+					Total:       money.FromFloat(1000.0),
 					CreatedAt:   time.Now(),
 				},
 				{
@@ -228,13 +270,13 @@ func TestCreateInvoiceData(t *testing.T) {
 					Description: "Licenses",
 					Quantity:    &quantity,
 					UnitPrice:   &unitPrice,
-					Total:       500.0,
+					Total:       money.FromFloat(500.0),
 					CreatedAt:   time.Now(),
 				},
 			},
 		}
 
-		data := app.createInvoiceData(invoice, cfg)
+		data := render.BuildTemplateData(cfg, invoice, nil)
 
 		// Should only count hours from hourly items, not quantity items
 		assert.InDelta(t, 8.0, data.TotalHours, 0.01, "Should only count hours from hourly LineItems")
@@ -245,22 +287,136 @@ func TestCreateInvoiceData(t *testing.T) {
 			ID:        "test-006",
 			Number:    "TEST-006",
 			Date:      time.Now(),
-			Subtotal:  5000.0,
-			Total:     5025.0,
-			CryptoFee: 25.0,
+			Subtotal:  money.FromFloat(5000.0),
+			Total:     money.FromFloat(5025.0),
+			CryptoFee: money.FromFloat(25.0),
 			WorkItems: []models.WorkItem{},
 			LineItems: []models.LineItem{},
 		}
 
-		data := app.createInvoiceData(invoice, cfg)
+		data := render.BuildTemplateData(cfg, invoice, nil)
 
 		require.NotNil(t, data, "Invoice data should not be nil")
-		assert.Equal(t, invoice.Number, data.Number, "Invoice should be embedded")
-		assert.InDelta(t, invoice.Subtotal, data.Subtotal, 0.01, "Subtotal should be preserved")
-		assert.InDelta(t, invoice.Total, data.Total, 0.01, "Total should be preserved")
-		assert.InDelta(t, invoice.CryptoFee, data.CryptoFee, 0.01, "CryptoFee should be preserved")
+		assert.Equal(t, invoice.Number, data.Number, "Invoice fields should be promoted")
+		assert.InDelta(t, invoice.Subtotal.Float64(), data.Subtotal.Float64(), 0.01, "Subtotal should be preserved")
+		assert.InDelta(t, invoice.Total.Float64(), data.Total.Float64(), 0.01, "Total should be preserved")
+		assert.InDelta(t, invoice.CryptoFee.Float64(), data.CryptoFee.Float64(), 0.01, "CryptoFee should be preserved")
 		assert.Equal(t, cfg.Business.Name, data.Business.Name, "Business info should be populated")
 		assert.Equal(t, "USD", data.Config.Currency, "Config should be populated")
 		assert.Equal(t, "$", data.Config.CurrencySymbol, "Currency symbol should be set")
 	})
 }
+
+func TestResolveTermsAnchor(t *testing.T) {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("terms-anchor", "", "")
+		return cmd
+	}
+
+	t.Run("ConfigDefault", func(t *testing.T) {
+		cfg := &config.Config{Invoice: config.InvoiceConfig{TermsAnchor: "eom"}}
+		anchor, err := resolveTermsAnchor(newCmd(), cfg)
+		require.NoError(t, err)
+		assert.Equal(t, models.TermsAnchorEndOfMonth, anchor)
+	})
+
+	t.Run("FlagOverridesConfig", func(t *testing.T) {
+		cmd := newCmd()
+		require.NoError(t, cmd.Flags().Set("terms-anchor", "issue"))
+		cfg := &config.Config{Invoice: config.InvoiceConfig{TermsAnchor: "eom"}}
+		anchor, err := resolveTermsAnchor(cmd, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, models.TermsAnchorIssue, anchor)
+	})
+
+	t.Run("InvalidFlag", func(t *testing.T) {
+		cmd := newCmd()
+		require.NoError(t, cmd.Flags().Set("terms-anchor", "quarter"))
+		_, err := resolveTermsAnchor(cmd, &config.Config{})
+		require.ErrorIs(t, err, models.ErrInvalidTermsAnchor)
+	})
+}
+
+func TestDefaultHourlyRate(t *testing.T) {
+	withDefault := &config.Config{Invoice: config.InvoiceConfig{DefaultRate: 125}}
+
+	rate, err := defaultHourlyRate(0, withDefault)
+	require.NoError(t, err)
+	assert.InDelta(t, 125.0, rate, 1e-9)
+
+	rate, err = defaultHourlyRate(150, withDefault)
+	require.NoError(t, err)
+	assert.InDelta(t, 150.0, rate, 1e-9)
+
+	_, err = defaultHourlyRate(0, &config.Config{})
+	require.ErrorIs(t, err, ErrHourlyLineItemRequiresRate)
+}
+
+func TestCheckSkippedInvoices(t *testing.T) {
+	app := &App{logger: cli.NewLogger(false)}
+	warnings := []string{"skipped invoice file broken.json: failed to decode JSON: unexpected EOF"}
+
+	require.NoError(t, app.checkSkippedInvoices(nil, true))
+	require.NoError(t, app.checkSkippedInvoices(warnings, false))
+
+	err := app.checkSkippedInvoices(warnings, true)
+	require.ErrorIs(t, err, ErrInvoicesSkipped)
+	assert.Contains(t, err.Error(), "broken.json")
+}
+
+func TestApplyDefaultCurrency(t *testing.T) {
+	cfg := &config.Config{Invoice: config.InvoiceConfig{Currency: "EUR"}}
+	legacy := &models.Invoice{Number: "INV-001"}
+	priced := &models.Invoice{Number: "INV-002", Currency: "GBP"}
+
+	applyDefaultCurrency(cfg, legacy, priced)
+
+	assert.Equal(t, "EUR", legacy.Currency)
+	assert.Equal(t, "GBP", priced.Currency)
+}
+
+func TestSummarizeInvoicesByCurrency(t *testing.T) {
+	invoices := []*models.Invoice{
+		{Currency: "USD", Total: money.FromFloat(100), Status: models.StatusPaid},
+		{Currency: "EUR", Total: money.FromFloat(50), Status: models.StatusSent},
+		{Currency: "USD", Total: money.FromFloat(25.5), Status: models.StatusSent},
+	}
+
+	totals, currencies := summarizeInvoicesByCurrency(invoices)
+
+	assert.Equal(t, []string{"EUR", "USD"}, currencies)
+	require.Contains(t, totals, "USD")
+	require.Contains(t, totals, "EUR")
+	assert.Equal(t, money.FromFloat(125.5), totals["USD"].Total)
+	assert.Equal(t, money.FromFloat(100), totals["USD"].Paid)
+	assert.Equal(t, money.FromFloat(25.5), totals["USD"].Unpaid)
+	assert.Equal(t, money.FromFloat(50), totals["EUR"].Unpaid)
+}
+
+func TestInvoicesCSV(t *testing.T) {
+	invoices := []*models.Invoice{{
+		Number:   "INV-001",
+		Date:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		DueDate:  time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		Client:   models.Client{Name: awkwardClientName},
+		Status:   models.StatusSent,
+		Subtotal: money.FromFloat(1000),
+		Total:    money.FromFloat(1000),
+		Currency: "USD",
+		PONumber: "PO-7, rev \"B\"",
+	}}
+
+	data, err := invoicesCSV(invoices, "02.01.2006")
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Len(t, records[1], len(records[0]))
+	assert.Equal(t, "INV-001", records[1][0])
+	assert.Equal(t, "01.03.2025", records[1][1])
+	assert.Equal(t, "31.03.2025", records[1][2])
+	assert.Equal(t, awkwardClientName, records[1][3])
+	assert.Equal(t, "PO-7, rev \"B\"", records[1][9])
+}
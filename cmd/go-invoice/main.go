@@ -12,10 +12,16 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/mrz1836/go-invoice/internal/blockchain"
 	"github.com/mrz1836/go-invoice/internal/cli"
 	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/notify"
+	"github.com/mrz1836/go-invoice/internal/services"
 	"github.com/mrz1836/go-invoice/internal/storage"
 	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+	"github.com/mrz1836/go-invoice/internal/storage/memory"
+	"github.com/mrz1836/go-invoice/internal/storage/sqlite"
 	"github.com/mrz1836/go-invoice/internal/templates"
 )
 
@@ -31,9 +37,16 @@ var (
 
 // App represents the main application with dependency injection
 type App struct {
-	logger        *cli.SimpleLogger
-	configService *config.ConfigService
-	rootCmd       *cobra.Command
+	logger           *cli.SimpleLogger
+	configService    *config.ConfigService
+	rootCmd          *cobra.Command
+	autoBackupStores []*jsonStorage.JSONStorage // Stores with automatic backups running
+	webhooks         []*notify.WebhookNotifier  // Webhooks notified by this command
+	inMemory         bool                       // Keep invoices and clients in memory (--in-memory)
+	memoryStore      *memory.MemoryStorage      // Shared store used when inMemory is set
+	sqliteStores     []*sqlite.SQLiteStorage    // SQLite databases to close when the command finishes
+	rateProvider     blockchain.RateProvider    // Exchange rates for crypto quotes, CoinGecko when nil
+	idGen            services.IDGenerator       // IDs of new records, see idGenerator
 }
 
 // NewApp creates a new application instance with dependency injection
@@ -68,25 +81,39 @@ Key features:
 - Printer-optimized output`,
 		Version: fmt.Sprintf("%s (%s, built %s)", version, commit, buildDate),
 		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
-			// Get debug flag from command
-			debug, _ := cmd.Flags().GetBool("debug")
-			if debug {
-				a.logger = cli.NewLogger(true)
-				// Update config service with debug logger
-				validator := config.NewSimpleValidator(a.logger)
-				a.configService = config.NewConfigService(a.logger, validator)
+			logger, err := newLoggerFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			a.logger = logger
+			a.inMemory, _ = cmd.Flags().GetBool("in-memory")
+			// Update config service with the configured logger
+			validator := config.NewSimpleValidator(a.logger)
+			a.configService = config.NewConfigService(a.logger, validator)
+			if dateFormat, _ := cmd.Flags().GetString("date-format"); dateFormat != "" {
+				a.configService.SetOverride("DATE_FORMAT", dateFormat)
+			}
+			if idGenerator, _ := cmd.Flags().GetString("id-generator"); idGenerator != "" {
+				a.configService.SetOverride("ID_GENERATOR", idGenerator)
 			}
 			return nil
 		},
 	}
 
 	// Add persistent flags
-	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug logging")
-
-	// Default config path to ~/.go-invoice/.env.config
-	homeDir, _ := os.UserHomeDir()
-	defaultConfigPath := filepath.Join(homeDir, ".go-invoice", ".env.config")
-	rootCmd.PersistentFlags().String("config", defaultConfigPath, "Path to configuration file")
+	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug logging (same as --log-level debug)")
+	rootCmd.PersistentFlags().String("log-level", "", "Log level: debug, info, warn or error (default: info)")
+	rootCmd.PersistentFlags().String("log-format", cli.LogFormatText, "Log format: text or json")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Print only results on stdout and send status messages to stderr")
+	rootCmd.PersistentFlags().Bool("no-emoji", false, "Strip emoji from status messages (default: on when stdout is not a terminal)")
+	rootCmd.PersistentFlags().Bool("json", false, "Print the result of invoice create, update, delete and add-line-item, or the error, as JSON on stdout")
+	rootCmd.PersistentFlags().String("date-format", "", "Date format for output and date flags: iso, us, eu, long or a layout such as DD.MM.YYYY (default: DATE_FORMAT, then iso)")
+	rootCmd.PersistentFlags().String("id-generator", "", "IDs of new records: uuid, or sequential[:prefix] or seeded:<seed> for tests and reproducible exports (default: ID_GENERATOR, then uuid)")
+	rootCmd.PersistentFlags().Bool("in-memory", false, "Keep invoices and clients in memory instead of the data directory; nothing is saved")
+
+	// Without --config the file is searched for, see config.ResolvePath
+	rootCmd.PersistentFlags().String("config", "", "Path to configuration file (default: $GO_INVOICE_CONFIG, "+
+		"$XDG_CONFIG_HOME/go-invoice/config, ~/.go-invoice/config, ~/.go-invoice/.env.config, then ./.env.config)")
 
 	// Add subcommands
 	rootCmd.AddCommand(a.buildConfigCommand())
@@ -95,19 +122,46 @@ Key features:
 	rootCmd.AddCommand(a.buildInvoiceCommand())
 	rootCmd.AddCommand(a.buildImportCommand())
 	rootCmd.AddCommand(a.buildGenerateCommand())
+	rootCmd.AddCommand(a.buildTemplateCommand())
 	rootCmd.AddCommand(a.buildMigrateLateFeeCommand())
 	rootCmd.AddCommand(a.buildPaymentCommand())
+	rootCmd.AddCommand(a.buildReportCommand())
+	rootCmd.AddCommand(a.buildStorageCommand())
+	rootCmd.AddCommand(a.buildTUICommand())
 	rootCmd.AddCommand(a.buildUpgradeCommand())
+	rootCmd.AddCommand(a.buildDoctorCommand())
 
+	markUsageErrors(rootCmd)
 	return rootCmd
 }
 
+// newLoggerFromFlags builds the logger selected by the global output flags.
+// An explicit --log-level wins over --debug, and --no-emoji defaults to on when
+// stdout is not a terminal.
+func newLoggerFromFlags(cmd *cobra.Command) (*cli.SimpleLogger, error) {
+	debug, _ := cmd.Flags().GetBool("debug")
+	level, _ := cmd.Flags().GetString("log-level")
+	format, _ := cmd.Flags().GetString("log-format")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	noEmoji, _ := cmd.Flags().GetBool("no-emoji")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	if level == "" && debug {
+		level = "debug"
+	}
+	if !cmd.Flags().Changed("no-emoji") {
+		noEmoji = !isTerminalOutput()
+	}
+
+	return cli.NewLoggerWithOptions(cli.LoggerOptions{Level: level, Format: format, Quiet: quiet, NoEmoji: noEmoji, JSON: jsonOutput})
+}
+
 // buildConfigCommand creates the config command with subcommands
 func (a *App) buildConfigCommand() *cobra.Command {
 	configCmd := &cobra.Command{
 		Use:   "config",
 		Short: "Configuration management commands",
-		Long:  "Manage application configuration including validation and display",
+		Long:  "Manage application configuration including validation, display and editing",
 	}
 
 	// Add config subcommands
@@ -115,13 +169,18 @@ func (a *App) buildConfigCommand() *cobra.Command {
 	configCmd.AddCommand(a.buildConfigSetupClaudeCommand())
 	configCmd.AddCommand(a.buildConfigValidateCommand())
 	configCmd.AddCommand(a.buildConfigShowCommand())
+	configCmd.AddCommand(a.buildConfigSetCommand())
+	configCmd.AddCommand(a.buildConfigEditCommand())
 
 	return configCmd
 }
 
 // buildConfigSetupCommand creates the config setup subcommand
 func (a *App) buildConfigSetupCommand() *cobra.Command {
-	return &cobra.Command{
+	var values configSetupValues
+	var nonInteractive, force bool
+
+	cmd := &cobra.Command{
 		Use:   "setup",
 		Short: "Set up initial business configuration",
 		Long: `Interactive setup wizard to configure your business information,
@@ -132,20 +191,53 @@ This wizard will prompt you for:
 - Business information (name, address, email, etc.)
 - Invoice settings (prefix, currency, VAT rate, etc.)
 - Payment terms and banking details
-- Storage directory preferences`,
+- Storage directory preferences
+
+With --non-interactive nothing is prompted for: the values come from flags, and
+--business-name, --email and --address are required. An existing configuration
+file is only replaced with --force.`,
+		Example: `  # Run the setup wizard
+  go-invoice config setup
+
+  # Provision a configuration from a script
+  go-invoice config setup --non-interactive \
+    --business-name "Acme Consulting" --email billing@acme.example \
+    --address "1 Main St\nSpringfield" --currency EUR --vat-rate 0.2`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			ctx, cancel := context.WithCancel(cmd.Context())
 			defer cancel()
 
 			configPath, _ := cmd.Flags().GetString("config")
-			if configPath == "" {
-				homeDir, _ := os.UserHomeDir()
-				configPath = filepath.Join(homeDir, ".go-invoice", ".env.config")
-			}
+			configPath = config.ResolvePath(configPath).Path
 
+			if nonInteractive {
+				return a.runConfigSetupNonInteractive(ctx, configPath, values, force)
+			}
 			return a.runConfigSetup(ctx, configPath)
 		},
 	}
+
+	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Write the configuration from flags without prompting")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing configuration file (with --non-interactive)")
+	cmd.Flags().StringVar(&values.businessName, "business-name", "", "Business name (required with --non-interactive)")
+	cmd.Flags().StringVar(&values.businessEmail, "email", "", "Business email (required with --non-interactive)")
+	cmd.Flags().StringVar(&values.businessAddress, "address", "", "Business address, use \\n for line breaks (required with --non-interactive)")
+	cmd.Flags().StringVar(&values.businessPhone, "phone", "", "Business phone")
+	cmd.Flags().StringVar(&values.businessWebsite, "website", "", "Business website")
+	cmd.Flags().StringVar(&values.paymentTerms, "payment-terms", "Net 30", "Payment terms")
+	cmd.Flags().StringVar(&values.bankName, "bank-name", "", "Bank name")
+	cmd.Flags().StringVar(&values.bankAccount, "bank-account", "", "Bank account number")
+	cmd.Flags().StringVar(&values.bankRouting, "bank-routing", "", "Bank routing number")
+	cmd.Flags().StringVar(&values.paymentInstructions, "payment-instructions", "", "Payment instructions")
+	cmd.Flags().StringVar(&values.invoicePrefix, "prefix", "INV", "Invoice number prefix")
+	cmd.Flags().IntVar(&values.invoiceStartNumber, "start-number", 1000, "First invoice sequence number")
+	cmd.Flags().StringVar(&values.currency, "currency", "USD", "Default currency code")
+	cmd.Flags().Float64Var(&values.vatRate, "vat-rate", 0, "VAT/tax rate as a decimal, e.g. 0.10 for 10%")
+	cmd.Flags().IntVar(&values.invoiceDueDays, "due-days", 30, "Default days until an invoice is due")
+	cmd.Flags().StringVar(&values.dataDir, "data-dir", defaultSetupDataDir(), "Data directory")
+	cmd.Flags().BoolVar(&values.autoBackup, "auto-backup", true, "Back up automatically")
+
+	return cmd
 }
 
 // buildConfigValidateCommand creates the config validate subcommand
@@ -177,25 +269,41 @@ func (a *App) buildConfigValidateCommand() *cobra.Command {
 
 // buildConfigShowCommand creates the config show subcommand
 func (a *App) buildConfigShowCommand() *cobra.Command {
-	return &cobra.Command{
+	var showSecrets bool
+
+	cmd := &cobra.Command{
 		Use:   "show",
 		Short: "Display current configuration",
-		Long:  "Display the current configuration with sensitive data masked",
+		Long: `Display the current configuration with sensitive data masked. Bank account,
+routing and IBAN numbers show only their last four characters and API keys are
+hidden entirely. Use --show-secrets to display them in full.
+
+The first line names the configuration file that was loaded. Without --config it is
+the first file found in $GO_INVOICE_CONFIG, $XDG_CONFIG_HOME/go-invoice/config,
+~/.go-invoice/config, ~/.go-invoice/.env.config and ./.env.config.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			ctx, cancel := context.WithCancel(cmd.Context())
 			defer cancel()
 
 			configPath, _ := cmd.Flags().GetString("config")
+			source := config.ResolvePath(configPath)
 
 			config, err := a.configService.LoadConfig(ctx, configPath)
 			if err != nil {
 				return fmt.Errorf("failed to load configuration: %w", err)
 			}
 
-			a.displayConfig(config)
+			if !showSecrets {
+				config = config.Redacted()
+			}
+			a.displayConfig(config, source)
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Show bank details and API keys unmasked")
+
+	return cmd
 }
 
 // buildInitCommand creates the init command for storage initialization
@@ -229,7 +337,11 @@ This command must be run before using other invoice management commands.`,
 			}
 
 			a.logger.Println("✅ Storage system initialized successfully!")
-			a.logger.Printf("   Data directory: %s\n", config.Storage.DataDir)
+			if config.Storage.Backend == "sqlite" {
+				a.logger.Printf("   Database: %s\n", config.Storage.DataDir)
+			} else {
+				a.logger.Printf("   Data directory: %s\n", config.Storage.DataDir)
+			}
 			a.logger.Printf("   Backup directory: %s\n", config.Storage.BackupDir)
 			a.logger.Println("")
 			a.logger.Println("💡 Next steps:")
@@ -245,7 +357,7 @@ This command must be run before using other invoice management commands.`,
 // initializeStorage sets up the storage system using the provided configuration
 func (a *App) initializeStorage(ctx context.Context, config *config.Config) error {
 	// Create storage instance
-	storage := a.createJSONStorage(config.Storage.DataDir)
+	storage := a.createStorage(config.Storage)
 
 	// Check if already initialized
 	if initialized, err := storage.IsInitialized(ctx); err != nil {
@@ -266,19 +378,58 @@ func (a *App) initializeStorage(ctx context.Context, config *config.Config) erro
 		return fmt.Errorf("storage validation failed: %w", err)
 	}
 
-	a.logger.Info("storage system initialized", "data_dir", config.Storage.DataDir)
+	a.logger.Info("storage system initialized", "backend", config.Storage.Backend, "data_dir", config.Storage.DataDir)
 	return nil
 }
 
-// createJSONStorage creates a new JSON storage instance
-func (a *App) createJSONStorage(dataDir string) storage.StorageInitializer {
-	return jsonStorage.NewJSONStorage(dataDir, a.logger)
+// createStorage creates the storage instance for the configured backend
+func (a *App) createStorage(storageConfig config.StorageConfig) storage.StorageInitializer {
+	if storageConfig.Backend == config.StorageBackendSQLite {
+		return a.newSQLiteStorage(storageConfig)
+	}
+	return jsonStorage.NewJSONStorage(storageConfig.DataDir, a.logger)
+}
+
+// newInvoiceService creates an invoice service over the given storage with the invoice
+// settings from the configuration, such as LEGACY_WORK_ITEMS, applied
+func (a *App) newInvoiceService(cfg *config.Config, invoiceStorage storage.InvoiceStorage, clientStorage storage.ClientStorage) *services.InvoiceService {
+	invoiceService := services.NewInvoiceService(invoiceStorage, clientStorage, a.logger, a.idGenerator(cfg.Storage))
+	invoiceService.SetLegacyItemsPolicy(services.LegacyItemsPolicy(cfg.Invoice.LegacyWorkItems))
+	return invoiceService
 }
 
-// displayConfig prints the configuration in a user-friendly format
-func (a *App) displayConfig(config *config.Config) {
+// idGenerator returns the ID generator selected by ID_GENERATOR. It is created once, and
+// every service of the command shares it, so sequential and seeded IDs do not repeat
+// between services.
+func (a *App) idGenerator(storageConfig config.StorageConfig) services.IDGenerator {
+	if a.idGen != nil {
+		return a.idGen
+	}
+
+	// The configuration is validated when it is loaded
+	spec, _ := config.ParseIDGenerator(storageConfig.IDGenerator)
+	switch spec.Kind {
+	case config.IDGeneratorSequential:
+		a.idGen = services.NewSequentialGenerator(spec.Prefix)
+	case config.IDGeneratorSeeded:
+		a.idGen = services.NewSeededUUIDGenerator(spec.Seed)
+	default:
+		a.idGen = services.NewUUIDGenerator()
+	}
+	return a.idGen
+}
+
+// displayConfig prints the configuration in a user-friendly format, starting with the
+// file it was loaded from
+func (a *App) displayConfig(config *config.Config, source config.PathResolution) {
 	a.logger.Println("📋 Current Configuration")
 	a.logger.Println("========================")
+	if source.Found {
+		a.logger.Printf("📁 Loaded from: %s\n", source.Path)
+	} else {
+		a.logger.Printf("📁 No configuration file found, using environment variables (tried %s)\n",
+			strings.Join(source.Tried, ", "))
+	}
 	a.logger.Println("")
 
 	a.logger.Println("🏢 Business Information:")
@@ -291,27 +442,112 @@ func (a *App) displayConfig(config *config.Config) {
 	if config.Business.Website != "" {
 		a.logger.Printf("  Website: %s\n", config.Business.Website)
 	}
+	if config.Business.Logo != "" {
+		a.logger.Printf("  Logo: %s\n", config.Business.Logo)
+	}
 	a.logger.Printf("  Payment Terms: %s\n", config.Business.PaymentTerms)
 	a.logger.Println("")
 
+	bank := config.Business.BankDetails
+	if bank.Name != "" || bank.AccountNumber != "" || bank.RoutingNumber != "" || bank.IBAN != "" {
+		a.logger.Println("🏦 Banking Information:")
+		if bank.Name != "" {
+			a.logger.Printf("  Bank: %s\n", bank.Name)
+		}
+		if bank.AccountNumber != "" {
+			a.logger.Printf("  Account Number: %s\n", bank.AccountNumber)
+		}
+		if bank.RoutingNumber != "" {
+			a.logger.Printf("  Routing Number: %s\n", bank.RoutingNumber)
+		}
+		if bank.IBAN != "" {
+			a.logger.Printf("  IBAN: %s\n", bank.IBAN)
+		}
+		if bank.SWIFT != "" {
+			a.logger.Printf("  SWIFT: %s\n", bank.SWIFT)
+		}
+		a.logger.Printf("  ACH Enabled: %v\n", bank.ACHEnabled)
+		a.logger.Println("")
+	}
+
+	crypto := config.Business.CryptoPayments
+	if crypto.USDCEnabled || crypto.BSVEnabled || crypto.EtherscanAPIKey != "" {
+		a.logger.Println("🪙 Crypto Payments:")
+		if crypto.USDCEnabled {
+			a.logger.Printf("  USDC Address: %s\n", crypto.USDCAddress)
+		}
+		if crypto.BSVEnabled {
+			a.logger.Printf("  BSV Address: %s\n", crypto.BSVAddress)
+		}
+		if crypto.EtherscanAPIKey != "" {
+			a.logger.Printf("  Etherscan API Key: %s\n", crypto.EtherscanAPIKey)
+		}
+		a.logger.Println("")
+	}
+
 	a.logger.Println("🧾 Invoice Settings:")
 	a.logger.Printf("  Prefix: %s\n", config.Invoice.Prefix)
 	a.logger.Printf("  Start Number: %d\n", config.Invoice.StartNumber)
+	a.logger.Printf("  Number Format: %s\n", config.Invoice.NumberFormat)
 	a.logger.Printf("  Currency: %s\n", config.Invoice.Currency)
 	a.logger.Printf("  Default Due Days: %d\n", config.Invoice.DefaultDueDays)
+	if config.Invoice.TermsAnchor != "" {
+		a.logger.Printf("  Terms Anchor: %s\n", config.Invoice.TermsAnchor)
+	}
 	if config.Invoice.VATRate > 0 {
 		a.logger.Printf("  VAT Rate: %.1f%%\n", config.Invoice.VATRate*100)
 	}
+	if config.Invoice.TaxRounding != "" {
+		a.logger.Printf("  Tax Rounding: %s\n", config.Invoice.TaxRounding)
+	}
+	if config.Invoice.HourlyRoundingMinutes > 0 {
+		a.logger.Printf("  Hourly Rounding: %d minutes\n", config.Invoice.HourlyRoundingMinutes)
+	}
+	if config.Invoice.MinimumHours > 0 {
+		a.logger.Printf("  Minimum Hours: %g\n", config.Invoice.MinimumHours)
+	}
+	if config.Invoice.DefaultRate > 0 {
+		a.logger.Printf("  Default Rate: %s/hour\n", money.Format(config.Invoice.DefaultRate, config.Invoice.Currency))
+	}
+	if config.Invoice.DefaultLineItemType != "" {
+		a.logger.Printf("  Default Line Item Type: %s\n", config.Invoice.DefaultLineItemType)
+	}
 	a.logger.Println("")
 
 	a.logger.Println("💾 Storage Settings:")
+	a.logger.Printf("  Backend: %s\n", config.Storage.Backend)
 	a.logger.Printf("  Data Directory: %s\n", config.Storage.DataDir)
 	a.logger.Printf("  Backup Directory: %s\n", config.Storage.BackupDir)
 	a.logger.Printf("  Auto Backup: %v\n", config.Storage.AutoBackup)
 	if config.Storage.AutoBackup {
 		a.logger.Printf("  Backup Interval: %v\n", config.Storage.BackupInterval)
 	}
+	a.logger.Printf("  Store Generated Documents: %v\n", config.Storage.StoreDocuments)
+	a.logger.Printf("  ID Generator: %s\n", config.Storage.IDGenerator)
 	a.logger.Println("")
+
+	if config.Webhook.URL != "" {
+		a.logger.Println("🔔 Webhook Notifications:")
+		a.logger.Printf("  URL: %s\n", config.Webhook.URL)
+		if config.Webhook.Secret != "" {
+			a.logger.Printf("  Secret: %s\n", config.Webhook.Secret)
+		}
+		a.logger.Println("")
+	}
+
+	if config.Email.SMTPHost != "" {
+		a.logger.Println("✉️  Email Reminders:")
+		a.logger.Printf("  SMTP Server: %s:%d\n", config.Email.SMTPHost, config.Email.SMTPPort)
+		if config.Email.Username != "" {
+			a.logger.Printf("  Username: %s\n", config.Email.Username)
+			a.logger.Printf("  Password: %s\n", config.Email.Password)
+		}
+		if config.Email.From != "" {
+			a.logger.Printf("  From: %s\n", config.Email.From)
+		}
+		a.logger.Printf("  Remind Every: %d days\n", config.Email.ReminderIntervalDays)
+		a.logger.Println("")
+	}
 }
 
 // runConfigSetup runs the interactive configuration setup wizard
@@ -422,7 +658,7 @@ func (a *App) runConfigSetup(ctx context.Context, configPath string) error {
 		}
 	}
 
-	vatRate, err := prompter.PromptFloat(ctx, "VAT/Tax Rate (as decimal, e.g., 0.10 for 10%)", 0.0)
+	vatRate, err := a.promptVATRate(ctx, prompter)
 	if err != nil {
 		return fmt.Errorf("failed to get VAT rate: %w", err)
 	}
@@ -436,8 +672,7 @@ func (a *App) runConfigSetup(ctx context.Context, configPath string) error {
 	a.logger.Println("💾 Storage settings...")
 	a.logger.Println("")
 
-	defaultDataDir := filepath.Join(os.Getenv("HOME"), ".go-invoice")
-	dataDir, err := prompter.PromptString(ctx, "Data Directory", defaultDataDir)
+	dataDir, err := prompter.PromptString(ctx, "Data Directory", defaultSetupDataDir())
 	if err != nil {
 		return fmt.Errorf("failed to get data directory: %w", err)
 	}
@@ -447,23 +682,17 @@ func (a *App) runConfigSetup(ctx context.Context, configPath string) error {
 		return fmt.Errorf("failed to get auto backup setting: %w", err)
 	}
 
-	// Generate the configuration file content
-	configContent := a.generateConfigFileContent(
-		businessName, businessEmail, businessAddress, businessPhone, businessWebsite,
-		paymentTerms, bankName, bankAccount, bankRouting, paymentInstructions,
-		invoicePrefix, invoiceStartNumber, currency, vatRate, invoiceDueDays,
-		dataDir, autoBackup,
-	)
-
-	// Write the configuration file
-	if err := os.WriteFile(configPath, []byte(configContent), 0o600); err != nil {
-		return fmt.Errorf("failed to write configuration file: %w", err)
-	}
-
-	// Create templates directory and default invoice template
-	if err := a.createDefaultTemplate(); err != nil {
-		a.logger.Printf("⚠️  Warning: Could not create default template: %v\n", err)
-		a.logger.Println("   You may need to create templates/invoice.html manually")
+	// Generate, validate and write the configuration file
+	err = a.writeSetupConfig(ctx, configPath, configSetupValues{
+		businessName: businessName, businessEmail: businessEmail, businessAddress: businessAddress,
+		businessPhone: businessPhone, businessWebsite: businessWebsite, paymentTerms: paymentTerms,
+		bankName: bankName, bankAccount: bankAccount, bankRouting: bankRouting,
+		paymentInstructions: paymentInstructions, invoicePrefix: invoicePrefix,
+		invoiceStartNumber: invoiceStartNumber, currency: currency, vatRate: vatRate,
+		invoiceDueDays: invoiceDueDays, dataDir: dataDir, autoBackup: autoBackup,
+	})
+	if err != nil {
+		return err
 	}
 
 	a.logger.Println("")
@@ -478,63 +707,59 @@ func (a *App) runConfigSetup(ctx context.Context, configPath string) error {
 	return nil
 }
 
-// generateConfigFileContent creates the .env configuration file content
-func (a *App) generateConfigFileContent(
-	businessName, businessEmail, businessAddress, businessPhone, businessWebsite,
-	paymentTerms, bankName, bankAccount, bankRouting, paymentInstructions,
-	invoicePrefix string, invoiceStartNumber int, currency string, vatRate float64,
-	invoiceDueDays int, dataDir string, autoBackup bool,
-) string {
-	var content strings.Builder
-
-	content.WriteString("# go-invoice Configuration\n")
-	content.WriteString("# Generated by setup wizard\n\n")
-
-	content.WriteString("# Business Information\n")
-	fmt.Fprintf(&content, "BUSINESS_NAME=%s\n", businessName)
-	fmt.Fprintf(&content, "BUSINESS_EMAIL=%s\n", businessEmail)
-	fmt.Fprintf(&content, "BUSINESS_ADDRESS=%s\n", businessAddress)
-	if businessPhone != "" {
-		fmt.Fprintf(&content, "BUSINESS_PHONE=%s\n", businessPhone)
-	}
-	if businessWebsite != "" {
-		fmt.Fprintf(&content, "BUSINESS_WEBSITE=%s\n", businessWebsite)
-	}
-	fmt.Fprintf(&content, "PAYMENT_TERMS=%s\n", paymentTerms)
-	content.WriteString("\n")
-
-	if bankName != "" || bankAccount != "" || bankRouting != "" || paymentInstructions != "" {
-		content.WriteString("# Banking Information\n")
-		if bankName != "" {
-			fmt.Fprintf(&content, "BANK_NAME=%s\n", bankName)
+// generateConfigFile builds the .env configuration file written by config setup
+func (a *App) generateConfigFile(values configSetupValues) *config.EnvFile {
+	file := config.NewEnvFile()
+
+	file.Comment("go-invoice Configuration")
+	file.Comment("Generated by setup wizard")
+	file.Blank()
+
+	file.Comment(config.SectionBusiness)
+	file.Add("BUSINESS_NAME", values.businessName)
+	file.Add("BUSINESS_EMAIL", values.businessEmail)
+	file.Add("BUSINESS_ADDRESS", values.businessAddress)
+	if values.businessPhone != "" {
+		file.Add("BUSINESS_PHONE", values.businessPhone)
+	}
+	if values.businessWebsite != "" {
+		file.Add("BUSINESS_WEBSITE", values.businessWebsite)
+	}
+	file.Add("PAYMENT_TERMS", values.paymentTerms)
+	file.Blank()
+
+	if values.bankName != "" || values.bankAccount != "" || values.bankRouting != "" || values.paymentInstructions != "" {
+		file.Comment(config.SectionBanking)
+		if values.bankName != "" {
+			file.Add("BANK_NAME", values.bankName)
 		}
-		if bankAccount != "" {
-			fmt.Fprintf(&content, "BANK_ACCOUNT=%s\n", bankAccount)
+		if values.bankAccount != "" {
+			file.Add("BANK_ACCOUNT", values.bankAccount)
 		}
-		if bankRouting != "" {
-			fmt.Fprintf(&content, "BANK_ROUTING=%s\n", bankRouting)
+		if values.bankRouting != "" {
+			file.Add("BANK_ROUTING", values.bankRouting)
 		}
-		if paymentInstructions != "" {
-			fmt.Fprintf(&content, "PAYMENT_INSTRUCTIONS=%s\n", paymentInstructions)
+		if values.paymentInstructions != "" {
+			file.Add("PAYMENT_INSTRUCTIONS", values.paymentInstructions)
 		}
-		content.WriteString("\n")
+		file.Blank()
 	}
 
-	content.WriteString("# Invoice Settings\n")
-	fmt.Fprintf(&content, "INVOICE_PREFIX=%s\n", invoicePrefix)
-	fmt.Fprintf(&content, "INVOICE_START_NUMBER=%d\n", invoiceStartNumber)
-	fmt.Fprintf(&content, "CURRENCY=%s\n", currency)
-	if vatRate > 0 {
-		fmt.Fprintf(&content, "VAT_RATE=%.4f\n", vatRate)
+	file.Comment(config.SectionInvoice)
+	file.Add("INVOICE_PREFIX", values.invoicePrefix)
+	file.Add("INVOICE_START_NUMBER", strconv.Itoa(values.invoiceStartNumber))
+	file.Add("CURRENCY", values.currency)
+	if values.vatRate > 0 {
+		file.Add("VAT_RATE", strconv.FormatFloat(values.vatRate, 'f', 4, 64))
 	}
-	fmt.Fprintf(&content, "INVOICE_DUE_DAYS=%d\n", invoiceDueDays)
-	content.WriteString("\n")
+	file.Add("INVOICE_DUE_DAYS", strconv.Itoa(values.invoiceDueDays))
+	file.Blank()
 
-	content.WriteString("# Storage Settings\n")
-	fmt.Fprintf(&content, "DATA_DIR=%s\n", dataDir)
-	fmt.Fprintf(&content, "AUTO_BACKUP=%s\n", strconv.FormatBool(autoBackup))
+	file.Comment(config.SectionStorage)
+	file.Add("DATA_DIR", values.dataDir)
+	file.Add("AUTO_BACKUP", strconv.FormatBool(values.autoBackup))
 
-	return content.String()
+	return file
 }
 
 // createDefaultTemplate creates the templates directory and default invoice template
@@ -561,7 +786,8 @@ func (a *App) createDefaultTemplate() error {
 	return nil
 }
 
-// Execute runs the application with context cancellation support
+// Execute runs the application with context cancellation support. main turns the
+// returned error into the process exit code, see exitCode.
 func (a *App) Execute() error {
 	// Set up context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
@@ -578,14 +804,21 @@ func (a *App) Execute() error {
 	}()
 
 	// Execute with context
-	return a.rootCmd.ExecuteContext(ctx)
+	err := a.rootCmd.ExecuteContext(ctx)
+	a.stopAutoBackups(ctx)
+	a.waitForNotifications()
+	a.closeStores()
+	return err
 }
 
 func main() {
 	app := NewApp()
 
 	if err := app.Execute(); err != nil {
-		app.logger.Error("application failed", "error", err)
-		os.Exit(1)
+		if app.logger.IsJSON() {
+			_ = writeJSONError(os.Stdout, err)
+		}
+		app.logger.Error("application failed", "error", err, "kind", classifyError(err))
+		os.Exit(exitCode(err))
 	}
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+)
+
+func TestIDGenerator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("SharedBySequentialServices", func(t *testing.T) {
+		app := &App{logger: cli.NewLogger(false)}
+		dataDir := t.TempDir()
+		require.NoError(t, jsonStorage.NewJSONStorage(dataDir, app.logger).Initialize(ctx))
+		cfg := &config.Config{Storage: config.StorageConfig{DataDir: dataDir, IDGenerator: "sequential:test"}}
+
+		client, err := app.createClientService(cfg.Storage).CreateClient(ctx, models.CreateClientRequest{
+			Name: "Acme Corp", Email: "billing@acme.test",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.ClientID("test-client-000001"), client.ID)
+
+		invoiceService := app.createInvoiceService(cfg)
+		for i, want := range []models.InvoiceID{"test-inv-000001", "test-inv-000002"} {
+			invoice, createErr := invoiceService.CreateInvoice(ctx, models.CreateInvoiceRequest{
+				Number:   fmt.Sprintf("INV-%03d", i+1),
+				ClientID: client.ID,
+				Date:     time.Now(),
+				DueDate:  time.Now().AddDate(0, 0, 30),
+			})
+			require.NoError(t, createErr)
+			assert.Equal(t, want, invoice.ID)
+		}
+	})
+
+	t.Run("SeededRepeatsAcrossRuns", func(t *testing.T) {
+		storageConfig := config.StorageConfig{IDGenerator: "seeded:42"}
+		first, err := (&App{}).idGenerator(storageConfig).GenerateInvoiceID(ctx)
+		require.NoError(t, err)
+		second, err := (&App{}).idGenerator(storageConfig).GenerateInvoiceID(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("DefaultsToRandomUUIDs", func(t *testing.T) {
+		app := &App{}
+		first, err := app.idGenerator(config.StorageConfig{}).GenerateInvoiceID(ctx)
+		require.NoError(t, err)
+		second, err := app.idGenerator(config.StorageConfig{}).GenerateInvoiceID(ctx)
+		require.NoError(t, err)
+		assert.Len(t, string(first), 36)
+		assert.NotEqual(t, first, second)
+	})
+}
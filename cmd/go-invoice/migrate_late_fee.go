@@ -24,7 +24,7 @@ func (a *App) buildMigrateLateFeeCommand() *cobra.Command {
 			}
 
 			// Create storage instances
-			_, clientStorage := a.createStorageInstances(config.Storage.DataDir)
+			_, clientStorage := a.createStorageInstances(config.Storage)
 
 			// Get all clients
 			result, err := clientStorage.ListClients(ctx, false, 0, 0)
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/notify"
+)
+
+// newNotifier returns the notifier for the configured webhook, or nil when no webhook is
+// set. Deliveries still pending when the command ends are finished by waitForNotifications.
+func (a *App) newNotifier(webhook config.WebhookConfig) notify.Notifier {
+	if webhook.URL == "" {
+		return nil
+	}
+
+	notifier := notify.NewWebhookNotifier(webhook.URL, webhook.Secret, a.logger)
+	a.webhooks = append(a.webhooks, notifier)
+	return notifier
+}
+
+// waitForNotifications gives webhook deliveries started by the command a chance to finish,
+// for no longer than a single delivery may take
+func (a *App) waitForNotifications() {
+	if len(a.webhooks) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notify.DefaultWebhookTimeout)
+	defer cancel()
+	for _, webhook := range a.webhooks {
+		webhook.Wait(ctx)
+	}
+}
@@ -105,10 +105,10 @@ func (a *App) runPaymentVerify(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create storage and services
-	invoiceStorage, _ := a.createStorageInstances(config.Storage.DataDir)
-	idGen := services.NewUUIDGenerator()
-	invoiceService := services.NewInvoiceService(invoiceStorage, nil, a.logger, idGen)
+	invoiceStorage, _ := a.createStorageInstances(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, nil)
 	paymentService := services.NewPaymentService(invoiceStorage, a.logger)
+	paymentService.SetNotifier(a.newNotifier(config.Webhook))
 
 	// Get invoice
 	invoice, err := a.getInvoiceByIDOrNumber(ctx, invoiceService, invoiceIdentifier)
@@ -117,7 +117,7 @@ func (a *App) runPaymentVerify(cmd *cobra.Command, args []string) error {
 	}
 
 	a.logger.Printf("🔍 Verifying payment for invoice %s\n", invoice.Number)
-	a.logger.Printf("   Invoice Total: %.2f %s\n", invoice.Total, config.Invoice.Currency)
+	a.logger.Printf("   Invoice Total: %.2f %s\n", invoice.Total, invoice.GetCurrency(config.Invoice.Currency))
 	a.logger.Printf("   Status: %s\n", invoice.Status)
 	a.logger.Println("")
 
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/qr"
+	"github.com/mrz1836/go-invoice/internal/render"
+)
+
+// Output formats for generate invoice
+const (
+	generateFormatHTML = "html"
+	generateFormatQR   = "qr" // HTML with payment QR codes embedded
+)
+
+var (
+	// ErrInvalidGenerateFormat is returned for an unknown --format value
+	ErrInvalidGenerateFormat = fmt.Errorf("invalid format")
+	// ErrQRNetworkRequiresQRFormat is returned when --qr-network is used without --format qr
+	ErrQRNetworkRequiresQRFormat = fmt.Errorf("--qr-network requires --format qr")
+)
+
+// validateGenerateFormat checks the --format and --qr-network values
+func validateGenerateFormat(format, network string) error {
+	switch format {
+	case "", generateFormatHTML:
+		if network != "" {
+			return ErrQRNetworkRequiresQRFormat
+		}
+		return nil
+	case generateFormatQR:
+		if network == "" {
+			return nil
+		}
+		_, err := qr.ParseNetwork(network)
+		return err
+	}
+	return fmt.Errorf("%w: %q (use html or qr)", ErrInvalidGenerateFormat, format)
+}
+
+// addPaymentQRCodes embeds a payment QR code for each enabled crypto network with an address,
+// or only for network when one is given. Networks without an address are skipped.
+func (a *App) addPaymentQRCodes(data *render.TemplateData, invoice *models.Invoice, cfg *config.Config, network string) error {
+	networks := qr.Networks()
+	if network != "" {
+		selected, err := qr.ParseNetwork(network)
+		if err != nil {
+			return err
+		}
+		networks = []qr.Network{selected}
+	}
+
+	crypto := cfg.Business.CryptoPayments
+	fiatCurrency := invoice.GetCurrency(cfg.Invoice.Currency)
+	for _, n := range networks {
+		var (
+			enabled  bool
+			address  string
+			currency models.PaymentMethod
+			target   *template.URL
+		)
+		switch n {
+		case qr.NetworkUSDC:
+			enabled, address, currency, target = crypto.USDCEnabled, data.USDCAddress, models.PaymentMethodUSDC, &data.USDCQRCode
+		case qr.NetworkBSV:
+			enabled, address, currency, target = crypto.BSVEnabled, data.BSVAddress, models.PaymentMethodBSV, &data.BSVQRCode
+		}
+
+		if !enabled || address == "" {
+			if network != "" {
+				a.logger.Printf("⚠️  No %s address configured, the invoice will not include a QR code\n", currency)
+			}
+			continue
+		}
+
+		uri, err := qr.PaymentDataURI(n, address, paymentQRAmount(invoice, currency, fiatCurrency))
+		if err != nil {
+			return fmt.Errorf("failed to render %s QR code: %w", currency, err)
+		}
+		*target = template.URL(uri) //nolint:gosec // Data URI built from a PNG we encoded
+		a.logger.Printf("🔳 Embedded %s payment QR code\n", currency)
+	}
+
+	return nil
+}
+
+// paymentQRAmount returns the amount due in currency units for a payment QR code. The crypto
+// quote is used while it still matches the amount due; USDC is otherwise quoted 1:1 for USD
+// invoices. Zero leaves the amount out of the QR code, for the payer to enter.
+func paymentQRAmount(invoice *models.Invoice, currency models.PaymentMethod, fiatCurrency string) float64 {
+	due := invoice.AmountDue()
+	if due <= 0 {
+		return 0
+	}
+
+	quote := invoice.CryptoQuote
+	if quote != nil && quote.Currency == string(currency) && quote.FiatAmount == money.FromFloat(due) {
+		return quote.Amount
+	}
+	if currency == models.PaymentMethodUSDC && strings.EqualFold(fiatCurrency, "USD") {
+		return due
+	}
+	return 0
+}
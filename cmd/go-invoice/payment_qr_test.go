@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/qr"
+	"github.com/mrz1836/go-invoice/internal/render"
+)
+
+func TestValidateGenerateFormat(t *testing.T) {
+	require.NoError(t, validateGenerateFormat("", ""))
+	require.NoError(t, validateGenerateFormat(generateFormatHTML, ""))
+	require.NoError(t, validateGenerateFormat(generateFormatQR, ""))
+	require.NoError(t, validateGenerateFormat(generateFormatQR, "BSV"))
+
+	require.ErrorIs(t, validateGenerateFormat("pdf", ""), ErrInvalidGenerateFormat)
+	require.ErrorIs(t, validateGenerateFormat(generateFormatHTML, "usdc"), ErrQRNetworkRequiresQRFormat)
+	require.ErrorIs(t, validateGenerateFormat(generateFormatQR, "eth"), qr.ErrUnsupportedNetwork)
+}
+
+func TestAddPaymentQRCodes(t *testing.T) {
+	app := &App{logger: cli.NewLogger(false)}
+	newConfig := func() *config.Config {
+		return &config.Config{
+			Business: config.BusinessConfig{
+				CryptoPayments: config.CryptoPayments{
+					USDCEnabled: true,
+					USDCAddress: "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb1",
+					BSVEnabled:  true,
+					BSVAddress:  "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+				},
+			},
+			Invoice: config.InvoiceConfig{Currency: "USD"},
+		}
+	}
+
+	t.Run("AllConfiguredNetworks", func(t *testing.T) {
+		cfg, invoice := newConfig(), newConfirmTestInvoice()
+		data := render.BuildTemplateData(cfg, invoice, nil)
+
+		require.NoError(t, app.addPaymentQRCodes(data, invoice, cfg, ""))
+		assert.True(t, strings.HasPrefix(string(data.USDCQRCode), "data:image/png;base64,"))
+		assert.True(t, strings.HasPrefix(string(data.BSVQRCode), "data:image/png;base64,"))
+	})
+
+	t.Run("SelectedNetwork", func(t *testing.T) {
+		cfg, invoice := newConfig(), newConfirmTestInvoice()
+		data := render.BuildTemplateData(cfg, invoice, nil)
+
+		require.NoError(t, app.addPaymentQRCodes(data, invoice, cfg, "bsv"))
+		assert.Empty(t, data.USDCQRCode)
+		assert.NotEmpty(t, data.BSVQRCode)
+	})
+
+	t.Run("NoAddressOmitsQRCode", func(t *testing.T) {
+		cfg, invoice := newConfig(), newConfirmTestInvoice()
+		cfg.Business.CryptoPayments.BSVAddress = ""
+		cfg.Business.CryptoPayments.USDCEnabled = false
+		data := render.BuildTemplateData(cfg, invoice, nil)
+
+		require.NoError(t, app.addPaymentQRCodes(data, invoice, cfg, ""))
+		require.NoError(t, app.addPaymentQRCodes(data, invoice, cfg, "bsv"))
+		assert.Empty(t, data.USDCQRCode)
+		assert.Empty(t, data.BSVQRCode)
+	})
+}
+
+func TestPaymentQRAmount(t *testing.T) {
+	asOf := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("UsesMatchingQuote", func(t *testing.T) {
+		invoice := newConfirmTestInvoice()
+		require.NoError(t, invoice.SetCryptoQuote("BSV", "USD", 0.02, asOf, "mock"))
+
+		assert.InDelta(t, 30.0, paymentQRAmount(invoice, models.PaymentMethodBSV, "USD"), 1e-9)
+	})
+
+	t.Run("StaleQuoteIsIgnored", func(t *testing.T) {
+		invoice := newConfirmTestInvoice()
+		require.NoError(t, invoice.SetCryptoQuote("BSV", "USD", 0.02, asOf, "mock"))
+		invoice.Payments = []models.Payment{{Amount: 500, Date: asOf, Method: models.PaymentMethodACH}}
+
+		assert.Zero(t, paymentQRAmount(invoice, models.PaymentMethodBSV, "USD"))
+	})
+
+	t.Run("USDCForUSDInvoice", func(t *testing.T) {
+		invoice := newConfirmTestInvoice()
+
+		assert.InDelta(t, 1500.0, paymentQRAmount(invoice, models.PaymentMethodUSDC, "USD"), 1e-9)
+		assert.Zero(t, paymentQRAmount(invoice, models.PaymentMethodUSDC, "EUR"))
+		assert.Zero(t, paymentQRAmount(invoice, models.PaymentMethodBSV, "USD"))
+	})
+}
@@ -317,21 +317,22 @@ func (v *SimpleTemplateValidator) CheckSecurity(ctx context.Context, template re
 func (v *SimpleTemplateValidator) validateTemplateFunctions(_ context.Context, template render.Template) error {
 	// List of allowed template functions
 	allowedFunctions := map[string]bool{
-		"formatCurrency": true,
-		"formatDate":     true,
-		"upper":          true,
-		"lower":          true,
-		"title":          true,
-		"add":            true,
-		"multiply":       true,
-		"formatFloat":    true,
-		"len":            true,
-		"range":          true,
-		"if":             true,
-		"else":           true,
-		"end":            true,
-		"with":           true,
-		"default":        true,
+		"formatCurrency":   true,
+		"formatAccounting": true,
+		"formatDate":       true,
+		"upper":            true,
+		"lower":            true,
+		"title":            true,
+		"add":              true,
+		"multiply":         true,
+		"formatFloat":      true,
+		"len":              true,
+		"range":            true,
+		"if":               true,
+		"else":             true,
+		"end":              true,
+		"with":             true,
+		"default":          true,
 	}
 
 	// This is a simplified validation - in a real system you'd parse the AST
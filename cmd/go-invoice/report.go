@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/encoding/csvout"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// ErrInvalidReportOutput is returned when a report is run with an unknown --output format
+var ErrInvalidReportOutput = fmt.Errorf("invalid output format (must be table, csv, or json)")
+
+// agingReportTotalLabel is the client column value of the column total rows
+const agingReportTotalLabel = "TOTAL"
+
+// agingReport is the accounts receivable aging of unpaid invoices as of a date
+type agingReport struct {
+	AsOf    string                `json:"as_of"`
+	Buckets []string              `json:"buckets"`
+	Rows    []*agingReportRow     `json:"rows"`
+	Totals  []*agingReportRow     `json:"totals"`
+	Overdue []*agingReportInvoice `json:"effectively_overdue"`
+}
+
+// agingReportRow holds one client's outstanding amounts per aging bucket in one currency
+type agingReportRow struct {
+	Client   string         `json:"client"`
+	Currency string         `json:"currency"`
+	Buckets  []money.Amount `json:"buckets"`
+	Total    money.Amount   `json:"total"`
+}
+
+// agingReportInvoice is an invoice still marked sent after its due date
+type agingReportInvoice struct {
+	Number      string       `json:"number"`
+	Client      string       `json:"client"`
+	DueDate     string       `json:"due_date"`
+	DaysPastDue int          `json:"days_past_due"`
+	AmountDue   money.Amount `json:"amount_due"`
+	Currency    string       `json:"currency"`
+}
+
+// buildReportCommand creates the report command with subcommands
+func (a *App) buildReportCommand() *cobra.Command {
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Financial reports",
+		Long:  "Reports across all invoices, such as accounts receivable aging and revenue",
+	}
+
+	reportCmd.AddCommand(a.buildReportAgingCommand())
+	reportCmd.AddCommand(a.buildReportRevenueCommand())
+
+	return reportCmd
+}
+
+// reportOutputFormat returns the validated --output flag of a report command
+func reportOutputFormat(cmd *cobra.Command) (string, error) {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	switch outputFormat {
+	case "table", "csv", "json":
+		return outputFormat, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidReportOutput, outputFormat)
+	}
+}
+
+// buildReportAgingCommand creates the report aging subcommand
+func (a *App) buildReportAgingCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "aging",
+		Short: "Show outstanding balances by client and age",
+		Long: `Bucket every unpaid invoice (draft, sent, or overdue) into 0-30, 31-60, 61-90
+and 90+ days old, counted from the invoice date, and print the outstanding
+balance per client with column totals. Paid and voided invoices are excluded.
+
+With --as-of the report is computed at that date: invoices dated later are left
+out and only payments received by then reduce the balance. Invoices still
+marked sent after their due date are listed as effectively overdue.`,
+		Example: `  # Aging as of today
+  go-invoice report aging
+
+  # Aging at the end of last quarter, as CSV
+  go-invoice report aging --as-of 2025-06-30 --output csv`,
+		Args: cobra.NoArgs,
+		RunE: a.runReportAging,
+	}
+
+	cmd.Flags().String("as-of", "", "Compute the report as of this date (YYYY-MM-DD, default: today)")
+	cmd.Flags().String("output", "table", "Output format (table, csv, json)")
+
+	return cmd
+}
+
+// runReportAging handles the report aging command
+func (a *App) runReportAging(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	outputFormat, err := reportOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	asOf := time.Now()
+	if asOfStr, _ := cmd.Flags().GetString("as-of"); asOfStr != "" {
+		date, err := time.ParseInLocation("2006-01-02", asOfStr, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid as-of date format (use YYYY-MM-DD): %w", err)
+		}
+		// Count the whole as-of day
+		asOf = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	invoiceService := a.createInvoiceService(config)
+	result, err := invoiceService.ListInvoices(ctx, models.InvoiceFilter{})
+	if err != nil {
+		return fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	applyDefaultCurrency(config, result.Invoices...)
+
+	report := buildAgingReport(result.Invoices, asOf, config.Invoice.DateLayout())
+
+	switch outputFormat {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal aging report: %w", err)
+		}
+		a.logger.Println(string(data))
+		return nil
+	case "csv":
+		return a.outputAgingReportCSV(report)
+	default:
+		return a.outputAgingReportTable(report)
+	}
+}
+
+// buildAgingReport buckets the outstanding balance of unpaid invoices by age as of asOf,
+// formatting dates with dateLayout
+func buildAgingReport(invoices []*models.Invoice, asOf time.Time, dateLayout string) *agingReport {
+	report := &agingReport{
+		AsOf:    asOf.Format(dateLayout),
+		Buckets: make([]string, len(agingBuckets)),
+		Rows:    []*agingReportRow{},
+		Totals:  []*agingReportRow{},
+		Overdue: []*agingReportInvoice{},
+	}
+	for i, bucket := range agingBuckets {
+		report.Buckets[i] = bucket.Label
+	}
+
+	rows := make(map[string]*agingReportRow)
+	totals := make(map[string]*agingReportRow)
+	row := func(index map[string]*agingReportRow, list *[]*agingReportRow, client, currency string) *agingReportRow {
+		key := client + "\x00" + currency
+		r, ok := index[key]
+		if !ok {
+			r = &agingReportRow{Client: client, Currency: currency, Buckets: make([]money.Amount, len(agingBuckets))}
+			index[key] = r
+			*list = append(*list, r)
+		}
+		return r
+	}
+
+	for _, inv := range invoices {
+		if !isUnpaid(inv) || inv.Date.After(asOf) {
+			continue
+		}
+		due := money.FromFloat(inv.AmountDueAt(asOf))
+		if due <= 0 {
+			continue
+		}
+
+		client := invoiceGroupKey(inv, groupByClient)
+		bucket := agingBucketIndex(inv.AgeInDaysAt(asOf))
+		for _, r := range []*agingReportRow{
+			row(rows, &report.Rows, client, inv.Currency),
+			row(totals, &report.Totals, agingReportTotalLabel, inv.Currency),
+		} {
+			r.Buckets[bucket] += due
+			r.Total += due
+		}
+
+		if daysUntilDue := inv.DaysUntilDueAt(asOf); inv.Status == models.StatusSent && daysUntilDue < 0 {
+			report.Overdue = append(report.Overdue, &agingReportInvoice{
+				Number:      inv.Number,
+				Client:      client,
+				DueDate:     inv.DueDate.Format(dateLayout),
+				DaysPastDue: -daysUntilDue,
+				AmountDue:   due,
+				Currency:    inv.Currency,
+			})
+		}
+	}
+
+	sort.Slice(report.Rows, func(i, j int) bool {
+		if report.Rows[i].Client != report.Rows[j].Client {
+			return report.Rows[i].Client < report.Rows[j].Client
+		}
+		return report.Rows[i].Currency < report.Rows[j].Currency
+	})
+	sort.Slice(report.Totals, func(i, j int) bool { return report.Totals[i].Currency < report.Totals[j].Currency })
+	sort.Slice(report.Overdue, func(i, j int) bool { return report.Overdue[i].DaysPastDue > report.Overdue[j].DaysPastDue })
+
+	return report
+}
+
+// outputAgingReportTable prints the aging report as an aligned client by bucket matrix
+func (a *App) outputAgingReportTable(report *agingReport) error {
+	a.logger.Printf("📊 Accounts Receivable Aging as of %s\n\n", report.AsOf)
+	if len(report.Rows) == 0 {
+		a.logger.Println("No outstanding invoices")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := append(append([]string{"CLIENT"}, report.Buckets...), "TOTAL")
+	if _, err := fmt.Fprintln(w, strings.Join(header, "\t")); err != nil {
+		return fmt.Errorf("failed to write table header: %w", err)
+	}
+	for _, r := range append(report.Rows, report.Totals...) {
+		cells := []string{r.Client}
+		for _, amount := range r.Buckets {
+			cells = append(cells, money.Format(amount.Float64(), r.Currency))
+		}
+		cells = append(cells, money.Format(r.Total.Float64(), r.Currency))
+		if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+			return fmt.Errorf("failed to write table row for %s: %w", r.Client, err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush table: %w", err)
+	}
+
+	if len(report.Overdue) > 0 {
+		a.logger.Printf("\n⚠️  Sent but past due (effectively overdue):\n")
+		for _, inv := range report.Overdue {
+			a.logger.Printf("   %s  %s  due %s (%d days ago)  %s\n", inv.Number, inv.Client, inv.DueDate,
+				inv.DaysPastDue, money.Format(inv.AmountDue.Float64(), inv.Currency))
+		}
+	}
+
+	return nil
+}
+
+// outputAgingReportCSV prints the aging report as CSV, with the column totals as TOTAL rows
+func (a *App) outputAgingReportCSV(report *agingReport) error {
+	header := append(append([]string{"client", "currency"}, report.Buckets...), "total")
+	rows := append(append([]*agingReportRow(nil), report.Rows...), report.Totals...)
+	records := make([][]string, 0, len(rows))
+	for _, r := range rows {
+		record := []string{r.Client, r.Currency}
+		for _, amount := range r.Buckets {
+			record = append(record, amount.String())
+		}
+		records = append(records, append(record, r.Total.String()))
+	}
+
+	data, err := csvout.Marshal(header, records)
+	if err != nil {
+		return err
+	}
+	a.logger.Printf("%s", data)
+	return nil
+}
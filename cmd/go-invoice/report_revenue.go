@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/encoding/csvout"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// revenueReport sums paid invoices in a date range, overall and per month, client and tax rate
+type revenueReport struct {
+	From      string        `json:"from,omitempty"`
+	To        string        `json:"to,omitempty"`
+	Invoices  int           `json:"invoice_count"`
+	Totals    []*revenueRow `json:"totals"`
+	ByMonth   []*revenueRow `json:"by_month"`
+	ByClient  []*revenueRow `json:"by_client"`
+	TaxByRate []*revenueRow `json:"tax_by_rate"`
+}
+
+// revenueRow holds the summed amounts of the paid invoices sharing a key and currency
+type revenueRow struct {
+	Key        string       `json:"key,omitempty"`
+	Currency   string       `json:"currency"`
+	Invoices   int          `json:"invoices"`
+	Subtotal   money.Amount `json:"subtotal"`
+	CryptoFees money.Amount `json:"crypto_fees"`
+	Tax        money.Amount `json:"tax"`
+	Total      money.Amount `json:"total"`
+
+	rate float64 // Tax rate of a tax_by_rate row, for sorting
+}
+
+// buildReportRevenueCommand creates the report revenue subcommand
+func (a *App) buildReportRevenueCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revenue",
+		Short: "Show revenue from paid invoices",
+		Long: `Sum the subtotal, crypto fees, tax and total of paid invoices dated in the
+range, broken down by month and by client. Tax collected is also grouped by
+tax rate so it can be reconciled against tax filings.
+
+Amounts in different currencies are always reported separately.`,
+		Example: `  # Revenue for 2024
+  go-invoice report revenue --from 2024-01-01 --to 2024-12-31
+
+  # As CSV for a spreadsheet
+  go-invoice report revenue --from 2024-01-01 --to 2024-03-31 --output csv`,
+		Args: cobra.NoArgs,
+		RunE: a.runReportRevenue,
+	}
+
+	cmd.Flags().String("from", "", "Include invoices dated on or after this date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().String("to", "", "Include invoices dated on or before this date (DATE_FORMAT or YYYY-MM-DD)")
+	cmd.Flags().String("output", "table", "Output format (table, csv, json)")
+
+	return cmd
+}
+
+// runReportRevenue handles the report revenue command
+func (a *App) runReportRevenue(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	outputFormat, err := reportOutputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	filter := models.InvoiceFilter{Status: models.StatusPaid}
+	if err = a.buildDateRangeFilter(cmd, &filter, config); err != nil {
+		return err
+	}
+
+	invoiceService := a.createInvoiceService(config)
+	result, err := invoiceService.ListInvoices(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	applyDefaultCurrency(config, result.Invoices...)
+
+	report := buildRevenueReport(result.Invoices, filter, config.Invoice.Currency, config.Invoice.DateLayout())
+
+	switch outputFormat {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal revenue report: %w", err)
+		}
+		a.logger.Println(string(data))
+		return nil
+	case "csv":
+		return a.outputRevenueReportCSV(report)
+	default:
+		return a.outputRevenueReportTable(report)
+	}
+}
+
+// buildRevenueReport sums the paid invoices matching filter, giving its dates in dateLayout.
+// An empty range reports zero totals in defaultCurrency.
+func buildRevenueReport(invoices []*models.Invoice, filter models.InvoiceFilter, defaultCurrency, dateLayout string) *revenueReport {
+	report := &revenueReport{
+		Totals:    []*revenueRow{},
+		ByMonth:   []*revenueRow{},
+		ByClient:  []*revenueRow{},
+		TaxByRate: []*revenueRow{},
+	}
+	if !filter.DateFrom.IsZero() {
+		report.From = filter.DateFrom.Format(dateLayout)
+	}
+	if !filter.DateTo.IsZero() {
+		report.To = filter.DateTo.Format(dateLayout)
+	}
+
+	totals := make(map[string]*revenueRow)
+	months := make(map[string]*revenueRow)
+	clients := make(map[string]*revenueRow)
+	rates := make(map[string]*revenueRow)
+
+	for _, inv := range invoices {
+		if inv.Status != models.StatusPaid {
+			continue
+		}
+		report.Invoices++
+
+		rate := formatTaxRate(inv.EffectiveTaxRate())
+		addRevenue(totals, &report.Totals, "", inv)
+		addRevenue(months, &report.ByMonth, inv.Date.Format("2006-01"), inv)
+		addRevenue(clients, &report.ByClient, invoiceGroupKey(inv, groupByClient), inv)
+		addRevenue(rates, &report.TaxByRate, rate, inv).rate = inv.EffectiveTaxRate()
+	}
+
+	if report.Invoices == 0 {
+		report.Totals = append(report.Totals, &revenueRow{Currency: defaultCurrency})
+	}
+
+	sort.Slice(report.Totals, func(i, j int) bool { return report.Totals[i].Currency < report.Totals[j].Currency })
+	sortRevenueRows(report.ByMonth, func(a, b *revenueRow) bool { return a.Key < b.Key })
+	sortRevenueRows(report.ByClient, func(a, b *revenueRow) bool { return a.Key < b.Key })
+	sortRevenueRows(report.TaxByRate, func(a, b *revenueRow) bool { return a.rate < b.rate })
+
+	return report
+}
+
+// addRevenue adds inv's amounts to the row for key and the invoice currency, creating it if needed
+func addRevenue(index map[string]*revenueRow, list *[]*revenueRow, key string, inv *models.Invoice) *revenueRow {
+	id := key + "\x00" + inv.Currency
+	row, ok := index[id]
+	if !ok {
+		row = &revenueRow{Key: key, Currency: inv.Currency}
+		index[id] = row
+		*list = append(*list, row)
+	}
+
+	row.Invoices++
+	row.Subtotal += inv.Subtotal
+	row.CryptoFees += inv.CryptoFee
+	row.Tax += inv.TaxAmount
+	row.Total += inv.Total
+	return row
+}
+
+// sortRevenueRows orders rows by less, then by currency
+func sortRevenueRows(rows []*revenueRow, less func(a, b *revenueRow) bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if less(rows[i], rows[j]) {
+			return true
+		}
+		if less(rows[j], rows[i]) {
+			return false
+		}
+		return rows[i].Currency < rows[j].Currency
+	})
+}
+
+// formatTaxRate formats a tax rate fraction as a percentage, e.g. 0.0825 as "8.25%"
+func formatTaxRate(rate float64) string {
+	return strconv.FormatFloat(math.Round(rate*1e6)/1e4, 'f', -1, 64) + "%"
+}
+
+// revenueRange describes the report date range for headings
+func (r *revenueReport) revenueRange() string {
+	switch {
+	case r.From != "" && r.To != "":
+		return fmt.Sprintf("%s to %s", r.From, r.To)
+	case r.From != "":
+		return fmt.Sprintf("since %s", r.From)
+	case r.To != "":
+		return fmt.Sprintf("through %s", r.To)
+	default:
+		return "all dates"
+	}
+}
+
+// outputRevenueReportTable prints the revenue report as aligned tables, one per breakdown
+func (a *App) outputRevenueReportTable(report *revenueReport) error {
+	a.logger.Printf("💰 Revenue (%s)\n", report.revenueRange())
+	a.logger.Printf("Paid invoices: %d\n", report.Invoices)
+	if report.Invoices == 0 {
+		a.logger.Printf("No paid invoices in this range; all totals are zero.\n")
+	}
+
+	sections := []struct {
+		title  string
+		column string
+		rows   []*revenueRow
+	}{
+		{title: "Totals", column: "", rows: report.Totals},
+		{title: "By Month", column: "MONTH", rows: report.ByMonth},
+		{title: "By Client", column: "CLIENT", rows: report.ByClient},
+		{title: "Tax by Rate", column: "TAX RATE", rows: report.TaxByRate},
+	}
+
+	for _, section := range sections {
+		if len(section.rows) == 0 {
+			continue
+		}
+		a.logger.Printf("\n%s\n", section.title)
+		if err := writeRevenueTable(section.column, section.rows); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeRevenueTable writes revenue rows as an aligned table; an empty keyColumn omits the key
+func writeRevenueTable(keyColumn string, rows []*revenueRow) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	header := []string{"CURRENCY", "INVOICES", "SUBTOTAL", "CRYPTO FEES", "TAX", "TOTAL"}
+	if keyColumn != "" {
+		header = append([]string{keyColumn}, header...)
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(header, "\t")); err != nil {
+		return fmt.Errorf("failed to write table header: %w", err)
+	}
+
+	for _, row := range rows {
+		cells := []string{
+			row.Currency,
+			strconv.Itoa(row.Invoices),
+			money.Format(row.Subtotal.Float64(), row.Currency),
+			money.Format(row.CryptoFees.Float64(), row.Currency),
+			money.Format(row.Tax.Float64(), row.Currency),
+			money.Format(row.Total.Float64(), row.Currency),
+		}
+		if keyColumn != "" {
+			cells = append([]string{row.Key}, cells...)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+			return fmt.Errorf("failed to write table row: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush table: %w", err)
+	}
+	return nil
+}
+
+// outputRevenueReportCSV prints the revenue report as one CSV table with a section column
+func (a *App) outputRevenueReportCSV(report *revenueReport) error {
+	header := []string{"section", "key", "currency", "invoices", "subtotal", "crypto_fees", "tax", "total"}
+
+	sections := []struct {
+		name string
+		rows []*revenueRow
+	}{
+		{name: "total", rows: report.Totals},
+		{name: "month", rows: report.ByMonth},
+		{name: "client", rows: report.ByClient},
+		{name: "tax_rate", rows: report.TaxByRate},
+	}
+	var records [][]string
+	for _, section := range sections {
+		for _, row := range section.rows {
+			records = append(records, []string{
+				section.name,
+				row.Key,
+				row.Currency,
+				strconv.Itoa(row.Invoices),
+				row.Subtotal.String(),
+				row.CryptoFees.String(),
+				row.Tax.String(),
+				row.Total.String(),
+			})
+		}
+	}
+
+	data, err := csvout.Marshal(header, records)
+	if err != nil {
+		return err
+	}
+	a.logger.Printf("%s", data)
+	return nil
+}
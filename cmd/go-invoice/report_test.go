@@ -0,0 +1,132 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func TestBuildAgingReport(t *testing.T) {
+	asOf := time.Date(2025, 6, 30, 23, 59, 59, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return asOf.AddDate(0, 0, -days) }
+	acme := models.Client{ID: "CLIENT-1", Name: "Acme"}
+	globex := models.Client{ID: "CLIENT-2", Name: "Globex"}
+
+	invoices := []*models.Invoice{
+		// Sent and past due, partially paid before the as-of date
+		{Number: "INV-1", Client: acme, Currency: "USD", Status: models.StatusSent, Total: money.FromFloat(500),
+			Date: daysAgo(45), DueDate: daysAgo(15),
+			Payments: []models.Payment{{Amount: 200, Date: daysAgo(10)}, {Amount: 100, Date: asOf.AddDate(0, 0, 5)}}},
+		{Number: "INV-2", Client: acme, Currency: "USD", Status: models.StatusDraft, Total: money.FromFloat(80),
+			Date: daysAgo(3), DueDate: asOf.AddDate(0, 0, 27)},
+		{Number: "INV-3", Client: globex, Currency: "EUR", Status: models.StatusOverdue, Total: money.FromFloat(300),
+			Date: daysAgo(100), DueDate: daysAgo(70)},
+		// Excluded: paid, voided, and dated after the as-of date
+		{Number: "INV-4", Client: acme, Currency: "USD", Status: models.StatusPaid, Total: money.FromFloat(900), Date: daysAgo(20)},
+		{Number: "INV-5", Client: acme, Currency: "USD", Status: models.StatusVoided, Total: money.FromFloat(900), Date: daysAgo(20)},
+		{Number: "INV-6", Client: globex, Currency: "EUR", Status: models.StatusSent, Total: money.FromFloat(900), Date: asOf.AddDate(0, 0, 1)},
+	}
+
+	report := buildAgingReport(invoices, asOf, "01/02/2006")
+
+	assert.Equal(t, "06/30/2025", report.AsOf)
+	assert.Equal(t, []string{"0-30", "31-60", "61-90", "90+"}, report.Buckets)
+
+	require.Len(t, report.Rows, 2)
+	acmeRow := report.Rows[0]
+	assert.Equal(t, "Acme", acmeRow.Client)
+	assert.Equal(t, money.FromFloat(80), acmeRow.Buckets[0])
+	assert.Equal(t, money.FromFloat(300), acmeRow.Buckets[1], "only payments by the as-of date reduce the balance")
+	assert.Equal(t, money.FromFloat(380), acmeRow.Total)
+
+	globexRow := report.Rows[1]
+	assert.Equal(t, "EUR", globexRow.Currency)
+	assert.Equal(t, money.FromFloat(300), globexRow.Buckets[3])
+
+	require.Len(t, report.Totals, 2)
+	assert.Equal(t, agingReportTotalLabel, report.Totals[0].Client)
+	assert.Equal(t, "EUR", report.Totals[0].Currency)
+	assert.Equal(t, money.FromFloat(380), report.Totals[1].Total)
+
+	require.Len(t, report.Overdue, 1, "only sent invoices past due are flagged")
+	assert.Equal(t, "INV-1", report.Overdue[0].Number)
+	assert.Equal(t, 15, report.Overdue[0].DaysPastDue)
+	assert.Equal(t, "06/15/2025", report.Overdue[0].DueDate)
+}
+
+func TestBuildRevenueReport(t *testing.T) {
+	acme := models.Client{ID: "CLIENT-1", Name: "Acme"}
+	globex := models.Client{ID: "CLIENT-2", Name: "Globex"}
+	invoice := func(client models.Client, currency string, date time.Time, subtotal, cryptoFee, taxRate float64) *models.Invoice {
+		inv := &models.Invoice{
+			Client:    client,
+			Currency:  currency,
+			Date:      date,
+			Status:    models.StatusPaid,
+			Subtotal:  money.FromFloat(subtotal),
+			CryptoFee: money.FromFloat(cryptoFee),
+			TaxRate:   taxRate,
+		}
+		inv.TaxAmount = (inv.Subtotal + inv.CryptoFee).MulRate(taxRate)
+		inv.Total = inv.Subtotal + inv.CryptoFee + inv.TaxAmount
+		return inv
+	}
+
+	filter := models.InvoiceFilter{
+		DateFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		DateTo:   time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
+	}
+	invoices := []*models.Invoice{
+		invoice(acme, "USD", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), 1000, 25, 0.0825),
+		invoice(acme, "USD", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), 500, 0, 0.0825),
+		invoice(globex, "USD", time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC), 200, 0, 0),
+		invoice(globex, "EUR", time.Date(2024, 2, 21, 0, 0, 0, 0, time.UTC), 300, 0, 0.2),
+	}
+
+	report := buildRevenueReport(invoices, filter, "USD", "01/02/2006")
+
+	assert.Equal(t, "01/01/2024", report.From)
+	assert.Equal(t, "12/31/2024", report.To)
+	assert.Equal(t, 4, report.Invoices)
+
+	require.Len(t, report.Totals, 2)
+	assert.Equal(t, "EUR", report.Totals[0].Currency)
+	usd := report.Totals[1]
+	assert.Equal(t, 3, usd.Invoices)
+	assert.Equal(t, money.FromFloat(1700), usd.Subtotal)
+	assert.Equal(t, money.FromFloat(25), usd.CryptoFees)
+	assert.Equal(t, money.FromFloat(125.81), usd.Tax)
+	assert.Equal(t, money.FromFloat(1850.81), usd.Total)
+
+	require.Len(t, report.ByMonth, 3)
+	assert.Equal(t, "2024-01", report.ByMonth[0].Key)
+	assert.Equal(t, "2024-02", report.ByMonth[1].Key)
+	assert.Equal(t, "EUR", report.ByMonth[1].Currency)
+
+	require.Len(t, report.ByClient, 3)
+	assert.Equal(t, "Acme", report.ByClient[0].Key)
+	assert.Equal(t, money.FromFloat(1500), report.ByClient[0].Subtotal)
+
+	require.Len(t, report.TaxByRate, 3)
+	assert.Equal(t, "0%", report.TaxByRate[0].Key)
+	assert.Equal(t, "8.25%", report.TaxByRate[1].Key)
+	assert.Equal(t, 2, report.TaxByRate[1].Invoices)
+	assert.Equal(t, money.FromFloat(125.81), report.TaxByRate[1].Tax)
+	assert.Equal(t, "20%", report.TaxByRate[2].Key)
+
+	t.Run("EmptyRange", func(t *testing.T) {
+		empty := buildRevenueReport(nil, filter, "EUR", config.DateLayoutISO)
+
+		assert.Equal(t, 0, empty.Invoices)
+		require.Len(t, empty.Totals, 1)
+		assert.Equal(t, "EUR", empty.Totals[0].Currency)
+		assert.Equal(t, money.Amount(0), empty.Totals[0].Total)
+		assert.Empty(t, empty.ByMonth)
+	})
+}
@@ -0,0 +1,506 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/storage"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+	"github.com/mrz1836/go-invoice/internal/storage/memory"
+	"github.com/mrz1836/go-invoice/internal/storage/sqlite"
+)
+
+// Storage command errors
+var (
+	ErrInvalidBackupKeep   = fmt.Errorf("--keep must be zero or positive")
+	ErrStorageVerifyFailed = fmt.Errorf("storage verification found unresolved issues")
+	ErrStorageNeedsJSON    = fmt.Errorf("this command works on the JSON storage, but STORAGE_BACKEND is sqlite")
+	ErrStorageNeedsSQLite  = fmt.Errorf("importing needs STORAGE_BACKEND=sqlite")
+	ErrJSONStoreNotFound   = fmt.Errorf("no initialized JSON storage found")
+)
+
+// buildStorageCommand creates the storage command with subcommands
+func (a *App) buildStorageCommand() *cobra.Command {
+	storageCmd := &cobra.Command{
+		Use:   "storage",
+		Short: "Back up, restore, verify, reindex, migrate and import invoice data",
+		Long:  "Create backups of the invoice data directory, restore them, verify and repair stored data, rebuild indexes, migrate stored data and import a JSON data directory into SQLite",
+	}
+
+	storageCmd.AddCommand(a.buildStorageBackupCommand())
+	storageCmd.AddCommand(a.buildStorageRestoreCommand())
+	storageCmd.AddCommand(a.buildStorageVerifyCommand())
+	storageCmd.AddCommand(a.buildStorageReindexCommand())
+	storageCmd.AddCommand(a.buildStorageMigrateLineItemsCommand())
+	storageCmd.AddCommand(a.buildStorageImportJSONCommand())
+
+	return storageCmd
+}
+
+// buildStorageBackupCommand creates the storage backup subcommand
+func (a *App) buildStorageBackupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Create a backup of invoices and clients",
+		Long: `Snapshot the invoices/, clients/ and index/ directories into a timestamped
+tar.gz archive in the backup directory (storage.backup_dir, default <data_dir>/backups).
+Invoice attachments in attachments/ are not included; copy that directory separately.
+
+Use --keep to prune older backups after the new one is written.`,
+		Example: `  # Create a backup
+  go-invoice storage backup
+
+  # Create a backup and keep only the 7 most recent
+  go-invoice storage backup --keep 7`,
+		Args: cobra.NoArgs,
+		RunE: a.runStorageBackup,
+	}
+
+	cmd.Flags().Int("keep", 0, "Number of most recent backups to keep, deleting older ones (0 keeps all)")
+
+	return cmd
+}
+
+// buildStorageRestoreCommand creates the storage restore subcommand
+func (a *App) buildStorageRestoreCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore invoices and clients from a backup",
+		Long: `Restore the data directory from a backup created with 'go-invoice storage backup'.
+
+The archive is validated before anything is written. Restoring over a data
+directory that already contains invoices or clients requires --force. After
+the restore the data is validated again; if that fails the previous data is
+put back. Invoice attachments are not part of backups and are left as they are.`,
+		Example: `  # Restore into an empty data directory
+  go-invoice storage restore --from ~/.go-invoice/backups/go-invoice-backup-20240301-090000.tar.gz
+
+  # Replace existing data with the backup
+  go-invoice storage restore --from backup.tar.gz --force`,
+		Args: cobra.NoArgs,
+		RunE: a.runStorageRestore,
+	}
+
+	cmd.Flags().String("from", "", "Backup archive to restore (required)")
+	cmd.Flags().Bool("force", false, "Overwrite existing invoices and clients")
+	cmd.Flags().Bool("dry-run", false, "Validate the backup and show what would be restored without writing")
+	_ = cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+// buildStorageVerifyCommand creates the storage verify subcommand
+func (a *App) buildStorageVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check stored invoices and clients for corruption and inconsistencies",
+		Long: `Check that every invoice and client file parses and validates, that every invoice
+references an existing client and stores the totals a fresh calculation produces,
+and that the indexes list exactly the data files.
+
+With --repair the indexes are rebuilt, mismatched totals are recalculated on
+invoices that are not paid, and unparseable files are moved into <data_dir>/corrupt/.
+Invalid files and missing clients are only reported. The command fails while any
+issue remains.`,
+		Example: `  # Report problems without changing anything
+  go-invoice storage verify
+
+  # Fix what can be fixed automatically
+  go-invoice storage verify --repair`,
+		Args: cobra.NoArgs,
+		RunE: a.runStorageVerify,
+	}
+
+	cmd.Flags().Bool("repair", false, "Rebuild indexes, recalculate mismatched totals and quarantine unparseable files")
+
+	return cmd
+}
+
+// buildStorageReindexCommand creates the storage reindex subcommand
+func (a *App) buildStorageReindexCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reindex",
+		Short: "Rebuild the invoice and client indexes",
+		Long: `Rebuild index/invoices.json and index/clients.json from the invoice and client files.
+
+Invoice lists and counts are answered from the index. Run this when the index has
+drifted from the data files, for example after editing invoice files by hand.`,
+		Example: `  # Rebuild the indexes
+  go-invoice storage reindex`,
+		Args: cobra.NoArgs,
+		RunE: a.runStorageReindex,
+	}
+}
+
+// buildStorageMigrateLineItemsCommand creates the storage migrate-line-items subcommand
+func (a *App) buildStorageMigrateLineItemsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-line-items",
+		Short: "Convert legacy work items on stored invoices to line items",
+		Long: `Convert every invoice that still stores only legacy work items to line items,
+recalculate its totals and save it.
+
+Paid invoices are skipped so historical amounts are not altered.`,
+		Example: `  # See which invoices would be migrated
+  go-invoice storage migrate-line-items --dry-run
+
+  # Migrate them
+  go-invoice storage migrate-line-items`,
+		Args: cobra.NoArgs,
+		RunE: a.runStorageMigrateLineItems,
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Report the invoices that would be migrated without writing")
+
+	return cmd
+}
+
+// buildStorageImportJSONCommand creates the storage import-json subcommand
+func (a *App) buildStorageImportJSONCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import-json <json-data-dir>",
+		Short: "Copy invoices and clients from a JSON data directory into SQLite",
+		Long: `Copy every invoice and client from a JSON data directory into the SQLite
+database configured with STORAGE_BACKEND=sqlite and DATA_DIR, creating the
+database if needed.
+
+Records keep their IDs, timestamps and versions. Records already in the
+database are skipped, so the import can be run again. The JSON data directory
+is only read.`,
+		Example: `  # Switch an existing data directory to SQLite
+  go-invoice config set STORAGE_BACKEND=sqlite
+  go-invoice config set DATA_DIR=~/.go-invoice/go-invoice.db
+  go-invoice storage import-json ~/.go-invoice`,
+		Args: cobra.ExactArgs(1),
+		RunE: a.runStorageImportJSON,
+	}
+}
+
+// runStorageBackup handles the storage backup command
+func (a *App) runStorageBackup(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	keep, _ := cmd.Flags().GetInt("keep")
+	if keep < 0 {
+		return fmt.Errorf("%w, got %d", ErrInvalidBackupKeep, keep)
+	}
+
+	store, err := a.createBackupStorage(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	backupPath, err := store.CreateBackup(ctx, storage.BackupOptions{
+		IncludeInvoices:  true,
+		IncludeClients:   true,
+		CompressionLevel: gzip.DefaultCompression,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	a.logger.Result(backupPath)
+	a.logger.Printf("✅ Backup created: %s\n", backupPath)
+
+	if keep > 0 {
+		removed, err := store.PruneBackups(ctx, keep)
+		if err != nil {
+			return fmt.Errorf("failed to prune old backups: %w", err)
+		}
+		for _, path := range removed {
+			a.logger.Printf("   Removed old backup: %s\n", path)
+		}
+	}
+
+	return nil
+}
+
+// runStorageRestore handles the storage restore command
+func (a *App) runStorageRestore(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	from, _ := cmd.Flags().GetString("from")
+	force, _ := cmd.Flags().GetBool("force")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	store, err := a.createBackupStorage(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	result, err := store.RestoreBackup(ctx, storage.RestoreOptions{
+		SourcePath:        from,
+		OverwriteExisting: force,
+		ValidateData:      true,
+		DryRun:            dryRun,
+	})
+	if errors.Is(err, jsonStorage.ErrStoreNotEmpty) {
+		return fmt.Errorf("%w (use --force to replace it with the backup)", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	if result.DryRun {
+		a.logger.Printf("🔍 Backup is valid (dry run, nothing restored)\n")
+	} else {
+		a.logger.Printf("✅ Backup restored from %s\n", from)
+	}
+	a.logger.Printf("   Invoices: %d\n", result.InvoicesRestored)
+	a.logger.Printf("   Clients: %d\n", result.ClientsRestored)
+	for _, warning := range result.Warnings {
+		a.logger.Printf("   ⚠️  %s\n", warning)
+	}
+
+	return nil
+}
+
+// runStorageVerify handles the storage verify command
+func (a *App) runStorageVerify(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	repair, _ := cmd.Flags().GetBool("repair")
+
+	store, err := a.createBackupStorage(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	report, err := store.Verify(ctx, jsonStorage.VerifyOptions{Repair: repair})
+	if err != nil {
+		return fmt.Errorf("failed to verify storage: %w", err)
+	}
+
+	a.logger.Printf("🔍 Checked %d invoice(s) and %d client(s)\n", report.InvoicesChecked, report.ClientsChecked)
+	if len(report.Issues) == 0 {
+		a.logger.Printf("✅ No issues found\n")
+		return nil
+	}
+
+	a.logger.Printf("   Issues found: %d, fixed: %d\n", len(report.Issues), report.FixedCount())
+	for _, issue := range report.Issues {
+		if issue.Fixed {
+			a.logger.Printf("   ✅ %s %s: %s (%s)\n", issue.Resource, issue.ID, issue.Message, issue.Repair)
+		} else {
+			a.logger.Printf("   ⚠️  %s %s: %s\n", issue.Resource, issue.ID, issue.Message)
+		}
+	}
+
+	if unresolved := len(report.Unresolved()); unresolved > 0 {
+		if !repair {
+			a.logger.Printf("   Run with --repair to fix what can be fixed automatically\n")
+		}
+		return fmt.Errorf("%w: %d remaining", ErrStorageVerifyFailed, unresolved)
+	}
+	return nil
+}
+
+// runStorageReindex handles the storage reindex command
+func (a *App) runStorageReindex(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	store, err := a.createBackupStorage(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	invoices, clients, err := store.RebuildIndexes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild indexes: %w", err)
+	}
+
+	a.logger.Printf("✅ Indexes rebuilt: %d invoice(s), %d client(s)\n", invoices, clients)
+	return nil
+}
+
+// runStorageMigrateLineItems handles the storage migrate-line-items command
+func (a *App) runStorageMigrateLineItems(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	// Load configuration
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	invoiceService := a.createInvoiceService(config)
+	result, err := invoiceService.MigrateWorkItemsToLineItems(ctx, dryRun)
+	if err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	if result.DryRun {
+		a.logger.Printf("🔍 Dry run: %d invoice(s) would be migrated to line items\n", len(result.Migrated))
+	} else {
+		a.logger.Printf("✅ Migrated %d invoice(s) to line items\n", len(result.Migrated))
+	}
+	for _, number := range result.Migrated {
+		a.logger.Printf("   • %s\n", number)
+	}
+	a.logger.Printf("   Skipped (paid): %d\n", len(result.SkippedPaid))
+	for _, number := range result.SkippedPaid {
+		a.logger.Printf("   • %s\n", number)
+	}
+	a.logger.Printf("   Already using line items: %d\n", result.Unchanged)
+
+	return nil
+}
+
+// runStorageImportJSON handles the storage import-json command
+func (a *App) runStorageImportJSON(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Storage.Backend != config.StorageBackendSQLite {
+		return ErrStorageNeedsSQLite
+	}
+
+	source := jsonStorage.NewJSONStorage(args[0], a.logger)
+	if initialized, err := source.IsInitialized(ctx); err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[0], err)
+	} else if !initialized {
+		return fmt.Errorf("%w in %s", ErrJSONStoreNotFound, args[0])
+	}
+
+	store := a.newSQLiteStorage(cfg.Storage)
+	if err := store.Initialize(ctx); err != nil {
+		return fmt.Errorf("storage initialization failed: %w", err)
+	}
+
+	result, err := store.Import(ctx, source, source)
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	a.logger.Printf("✅ Imported into %s\n", store.Path())
+	a.logger.Printf("   Invoices: %d (%d already present)\n", result.InvoicesImported, result.InvoicesSkipped)
+	a.logger.Printf("   Clients: %d (%d already present)\n", result.ClientsImported, result.ClientsSkipped)
+	return nil
+}
+
+// createBackupStorage loads the configuration and returns the JSON storage with its backup
+// directory set, for the storage commands that work on its files. A backend that does not
+// report SupportsBackups is rejected.
+func (a *App) createBackupStorage(ctx context.Context, cmd *cobra.Command) (*jsonStorage.JSONStorage, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	store := a.newBackend(cfg.Storage)
+	info, err := store.GetStorageInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage info: %w", err)
+	}
+	jsonStore, ok := store.(*jsonStorage.JSONStorage)
+	if !info.SupportsBackups || !ok {
+		return nil, ErrStorageNeedsJSON
+	}
+	return jsonStore, nil
+}
+
+// invoiceClientStore is a storage backend holding both invoices and clients
+type invoiceClientStore interface {
+	storage.InvoiceStorage
+	storage.ClientStorage
+}
+
+// storageBackend is a configured storage backend that can also set itself up
+type storageBackend interface {
+	invoiceClientStore
+	storage.StorageInitializer
+}
+
+// newStore returns the storage the services use: with --in-memory one in-memory store shared
+// by the whole command, otherwise the backend storageConfig selects
+func (a *App) newStore(storageConfig config.StorageConfig) invoiceClientStore {
+	if !a.inMemory {
+		return a.newBackend(storageConfig)
+	}
+
+	if a.memoryStore == nil {
+		a.memoryStore = memory.NewMemoryStorage(a.logger)
+		if err := a.memoryStore.Initialize(context.Background()); err != nil {
+			a.logger.Error("failed to initialize in-memory storage", "error", err)
+		}
+		a.logger.Info("using in-memory storage, nothing is saved to disk")
+	}
+	return a.memoryStore
+}
+
+// newBackend returns the SQLite storage when storageConfig selects it, otherwise the JSON storage
+func (a *App) newBackend(storageConfig config.StorageConfig) storageBackend {
+	if storageConfig.Backend == config.StorageBackendSQLite {
+		return a.newSQLiteStorage(storageConfig)
+	}
+	return a.newJSONStorage(storageConfig)
+}
+
+// newSQLiteStorage creates the SQLite storage for the database file in storageConfig; the
+// database is closed by closeStores once the command finishes
+func (a *App) newSQLiteStorage(storageConfig config.StorageConfig) *sqlite.SQLiteStorage {
+	store := sqlite.NewSQLiteStorage(storageConfig.DataDir, a.logger)
+	a.sqliteStores = append(a.sqliteStores, store)
+	return store
+}
+
+// closeStores closes the SQLite databases opened for this command
+func (a *App) closeStores() {
+	for _, store := range a.sqliteStores {
+		if err := store.Close(); err != nil {
+			a.logger.Error("failed to close database", "path", store.Path(), "error", err)
+		}
+	}
+	a.sqliteStores = nil
+}
+
+// newJSONStorage creates the JSON storage for storageConfig and starts automatic backups when
+// they are enabled; the schedulers are stopped by stopAutoBackups once the command finishes
+func (a *App) newJSONStorage(storageConfig config.StorageConfig) *jsonStorage.JSONStorage {
+	store := jsonStorage.NewJSONStorage(storageConfig.DataDir, a.logger)
+	if storageConfig.BackupDir != "" {
+		store.SetBackupDir(storageConfig.BackupDir)
+	}
+	store.SetLockTimeout(storageConfig.LockTimeout)
+
+	if storageConfig.AutoBackup {
+		options := jsonStorage.AutoBackupOptions{Interval: storageConfig.BackupInterval}
+		if err := store.StartAutoBackup(context.Background(), options); err != nil {
+			a.logger.Error("failed to start automatic backups", "error", err)
+		} else {
+			a.autoBackupStores = append(a.autoBackupStores, store)
+		}
+	}
+
+	return store
+}
+
+// stopAutoBackups stops the backup schedulers started for this command, backing up pending
+// changes when a backup is due
+func (a *App) stopAutoBackups(ctx context.Context) {
+	for _, store := range a.autoBackupStores {
+		if err := store.StopAutoBackup(ctx); err != nil {
+			a.logger.Error("automatic backup failed", "error", err)
+		}
+	}
+	a.autoBackupStores = nil
+}
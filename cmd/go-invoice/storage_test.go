@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+func TestNewStoreInMemory(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	app := &App{logger: cli.NewLogger(false), inMemory: true}
+	storageConfig := config.StorageConfig{DataDir: dataDir}
+
+	// Services created for the same command share one store, and nothing reaches the data directory
+	client, err := app.createClientService(storageConfig).CreateClient(ctx,
+		models.CreateClientRequest{Name: "Acme Corp", Email: "ap@acme.test"})
+	require.NoError(t, err)
+
+	_, clientStorage := app.createStorageInstances(storageConfig)
+	stored, err := clientStorage.GetClient(ctx, client.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Corp", stored.Name)
+	assert.NoDirExists(t, filepath.Join(dataDir, "clients"))
+}
+
+func TestNewStoreSQLite(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "data", "invoices.db")
+	app := &App{logger: cli.NewLogger(false)}
+	storageConfig := config.StorageConfig{Backend: config.StorageBackendSQLite, DataDir: dbPath}
+
+	require.NoError(t, app.createStorage(storageConfig).Initialize(ctx))
+	client, err := app.createClientService(storageConfig).CreateClient(ctx,
+		models.CreateClientRequest{Name: "Acme Corp", Email: "ap@acme.test"})
+	require.NoError(t, err)
+	app.closeStores()
+
+	// Every record lives in the database file, and the file is reopened after closing
+	_, clientStorage := app.createStorageInstances(storageConfig)
+	t.Cleanup(app.closeStores)
+	stored, err := clientStorage.GetClient(ctx, client.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Corp", stored.Name)
+	assert.FileExists(t, dbPath)
+	assert.NoDirExists(t, filepath.Join(filepath.Dir(dbPath), "clients"))
+}
+
+func TestStorageFileCommandsNeedJSON(t *testing.T) {
+	env := newAttachTestEnv(t, config.StorageBackendSQLite)
+
+	for _, command := range []string{"backup", "verify", "reindex"} {
+		err := env.run(t, "storage", command)
+		require.ErrorIs(t, err, ErrStorageNeedsJSON, command)
+		assert.Equal(t, cli.KindConfig, classifyError(err), command)
+	}
+
+	require.NoError(t, newAttachTestEnv(t, config.StorageBackendJSON).run(t, "storage", "verify"))
+}
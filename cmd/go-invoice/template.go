@@ -0,0 +1,319 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/render"
+	"github.com/mrz1836/go-invoice/internal/templates"
+)
+
+// Template command errors
+var (
+	ErrTemplateLintFailed       = fmt.Errorf("template lint failed")
+	ErrTemplateValidationFailed = fmt.Errorf("template validation failed")
+)
+
+// buildTemplateCommand creates the template command with subcommands
+func (a *App) buildTemplateCommand() *cobra.Command {
+	templateCmd := &cobra.Command{
+		Use:   "template",
+		Short: "Work with invoice templates",
+		Long:  "Check and manage the HTML templates used to generate invoices.",
+	}
+
+	templateCmd.AddCommand(a.buildTemplateListCommand())
+	templateCmd.AddCommand(a.buildTemplateShowCommand())
+	templateCmd.AddCommand(a.buildTemplateLintCommand())
+	templateCmd.AddCommand(a.buildTemplateValidateCommand())
+
+	return templateCmd
+}
+
+// buildTemplateListCommand creates the template list command
+func (a *App) buildTemplateListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the templates available to generate",
+		Long: `List the templates that can be selected with "generate --template <name>".
+
+"default" is templates/invoice.html in the current directory when present,
+otherwise the embedded default template. Every other .html file in the
+templates directory, or in <data-dir>/templates, is available under its file
+name without the extension: templates/minimal.html is "minimal".`,
+		Example: `  go-invoice template list`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			configPath, _ := cmd.Flags().GetString("config")
+			return a.executeTemplateList(ctx, configPath)
+		},
+	}
+}
+
+// buildTemplateShowCommand creates the template show command
+func (a *App) buildTemplateShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "show <name>",
+		Short:   "Print the file path of a template",
+		Long:    `Print the file a named template is loaded from, for example to open it in an editor.`,
+		Example: `  go-invoice template show minimal`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			configPath, _ := cmd.Flags().GetString("config")
+			return a.executeTemplateShow(ctx, configPath, args[0])
+		},
+	}
+}
+
+// buildTemplateLintCommand creates the template lint command
+func (a *App) buildTemplateLintCommand() *cobra.Command {
+	var strict bool
+
+	cmd := &cobra.Command{
+		Use:   "lint <template-file>",
+		Short: "Check a custom template against the invoice data model",
+		Long: `Lint a custom invoice template before using it for generation.
+
+The linter:
+- Parses the template and reports syntax errors
+- Checks every field reference (e.g. {{.Client.Name}}) resolves against the
+  template data (invoice, client, business, config and computed fields)
+- Renders the template against a synthetic sample invoice to catch runtime errors
+
+Problems are reported with line and column numbers. Unknown field references
+are warnings; parse and render failures are errors.`,
+		Example: `  go-invoice template lint templates/mine.html
+  go-invoice template lint templates/mine.html --strict`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			return a.executeTemplateLint(ctx, args[0], strict)
+		},
+	}
+
+	cmd.Flags().BoolVar(&strict, "strict", false, "Treat warnings as errors")
+
+	return cmd
+}
+
+// buildTemplateValidateCommand creates the template validate command
+func (a *App) buildTemplateValidateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [template-file]",
+		Short: "Validate a template and fail on any problem (for CI)",
+		Long: `Validate an invoice template and exit non-zero if anything is wrong.
+
+Runs the same checks as "template lint" (parsing, field references against the
+template data, and a render of a sample invoice), but every problem is an error:
+a reference to a field that does not exist on the template data fails validation
+instead of producing a warning.
+
+Without a file, the template "generate" uses by default is validated:
+templates/invoice.html in the current directory when present, otherwise the
+embedded default template.`,
+		Example: `  # Validate the project template (or the embedded default)
+  go-invoice template validate
+
+  # Validate a specific template, e.g. in a CI step
+  go-invoice template validate templates/mine.html`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return a.executeTemplateValidate(ctx, path)
+		},
+	}
+
+	return cmd
+}
+
+// executeTemplateList prints the available templates and where each is loaded from
+func (a *App) executeTemplateList(ctx context.Context, configPath string) error {
+	cfg, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	sources, err := discoverTemplates(cfg.Storage.Dir())
+	if err != nil {
+		return err
+	}
+
+	for _, source := range sources {
+		a.logger.Printf("%-20s %s\n", source.Name, cmp.Or(source.Path, embeddedTemplateLabel))
+	}
+	return nil
+}
+
+// executeTemplateShow prints the path of a named template
+func (a *App) executeTemplateShow(ctx context.Context, configPath, name string) error {
+	cfg, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	sources, err := discoverTemplates(cfg.Storage.Dir())
+	if err != nil {
+		return err
+	}
+
+	source, err := findTemplateSource(sources, name)
+	if err != nil {
+		return err
+	}
+
+	a.logger.Println(cmp.Or(source.Path, embeddedTemplateLabel))
+	return nil
+}
+
+// executeTemplateLint lints a template file and prints any issues found
+func (a *App) executeTemplateLint(ctx context.Context, path string, strict bool) error {
+	a.logger.Info("executing template lint", "path", path)
+
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to read template file: %w", err)
+	}
+
+	a.logger.Printf("🔍 Linting template: %s\n", path)
+
+	failed, err := a.reportTemplateIssues(ctx, path, string(content), strict)
+	if err != nil {
+		return err
+	}
+	if failed {
+		return fmt.Errorf("%w: %s", ErrTemplateLintFailed, path)
+	}
+
+	return nil
+}
+
+// executeTemplateValidate validates a template file, or the default template when path
+// is empty, treating every issue as a failure
+func (a *App) executeTemplateValidate(ctx context.Context, path string) error {
+	a.logger.Info("executing template validate", "path", path)
+
+	name, content := path, templates.DefaultInvoiceTemplate
+	if path != "" {
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return fmt.Errorf("failed to read template file: %w", err)
+		}
+		content = string(data)
+	} else if data, err := os.ReadFile(projectTemplatePath); err == nil {
+		name, content = projectTemplatePath, string(data)
+	} else {
+		name = "embedded default template"
+	}
+
+	a.logger.Printf("🔍 Validating template: %s\n", name)
+
+	failed, err := a.reportTemplateIssues(ctx, name, content, true)
+	if err != nil {
+		return err
+	}
+	if failed {
+		return fmt.Errorf("%w: %s", ErrTemplateValidationFailed, name)
+	}
+
+	a.logger.Println("✅ Template is valid")
+	return nil
+}
+
+// reportTemplateIssues lints template content against sample data, prints every issue found
+// and reports whether the template failed; with strict, warnings count as failures
+func (a *App) reportTemplateIssues(ctx context.Context, path, content string, strict bool) (bool, error) {
+	result, err := render.LintTemplate(ctx, filepath.Base(path), content, a.createLintSampleData())
+	if err != nil {
+		return false, fmt.Errorf("failed to lint template: %w", err)
+	}
+
+	warnings := 0
+	for _, issue := range result.Issues {
+		icon := "❌"
+		if issue.Severity == render.LintSeverityWarning {
+			icon = "⚠️ "
+			warnings++
+		}
+		a.logger.Printf("%s %s:%s\n", icon, path, issue.String())
+	}
+
+	errorCount := len(result.Issues) - warnings
+	if result.Rendered {
+		a.logger.Println("✅ Sample invoice rendered successfully")
+	}
+	a.logger.Printf("\n%d error(s), %d warning(s)\n", errorCount, warnings)
+
+	return result.HasErrors() || (strict && warnings > 0), nil
+}
+
+// createLintSampleData builds synthetic template data covering every item type
+func (a *App) createLintSampleData() *render.TemplateData {
+	cfg := &config.Config{
+		Business: config.BusinessConfig{
+			Name:         "Sample Business LLC",
+			Address:      "1 Sample Way, Sample City",
+			Phone:        "+1-555-000-0000",
+			Email:        "billing@sample.test",
+			Website:      "https://sample.test",
+			TaxID:        "00-0000000",
+			PaymentTerms: "Net 30",
+			BankDetails: config.BankDetails{
+				Name:                "Sample Bank",
+				AccountNumber:       "000000000",
+				RoutingNumber:       "000000000",
+				PaymentInstructions: "Include the invoice number with your payment",
+				ACHEnabled:          true,
+			},
+			CryptoPayments: config.CryptoPayments{
+				USDCEnabled: true,
+				USDCAddress: "0x0000000000000000000000000000000000000000",
+				BSVEnabled:  true,
+				BSVAddress:  "1111111111111111111114oLvT2",
+			},
+		},
+		Invoice: config.InvoiceConfig{
+			Currency: "USD",
+		},
+	}
+
+	invoice := a.createSampleInvoice(cfg)
+	invoice.CryptoFee = money.FromFloat(25.00)
+	invoice.Client.LateFeeEnabled = true
+
+	hours, rate := 2.0, 125.0
+	amount := 500.0
+	quantity, unitPrice := 3.0, 15.0
+	invoice.LineItems = []models.LineItem{
+		{ID: "line_001", Type: models.LineItemTypeHourly, Date: invoice.Date, Description: "Sample hourly work", Hours: &hours, Rate: &rate, Total: money.Product(hours, rate)},
+		{ID: "line_002", Type: models.LineItemTypeFixed, Date: invoice.Date, Description: "Sample fixed fee", Amount: &amount, Total: money.FromFloat(amount)},
+		{ID: "line_003", Type: models.LineItemTypeQuantity, Date: invoice.Date, Description: "Sample licenses", Quantity: &quantity, UnitPrice: &unitPrice, Total: money.Product(quantity, unitPrice)},
+	}
+	invoice.CryptoQuote = &models.CryptoQuote{
+		Currency: "USDC", FiatCurrency: "USD", FiatAmount: invoice.Total,
+		Rate: 1, Amount: invoice.Total.Float64(), AsOf: invoice.Date, Source: "sample",
+	}
+
+	return render.BuildTemplateData(cfg, invoice, nil)
+}
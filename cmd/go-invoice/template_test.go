@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/render"
+	"github.com/mrz1836/go-invoice/internal/templates"
+)
+
+func TestDefaultTemplateLintsClean(t *testing.T) {
+	app := &App{
+		logger: cli.NewLogger(false),
+	}
+
+	result, err := render.LintTemplate(context.Background(), "default", templates.DefaultInvoiceTemplate, app.createLintSampleData())
+	require.NoError(t, err)
+	assert.Empty(t, result.Issues)
+	assert.True(t, result.Rendered)
+}
+
+func TestExecuteTemplateValidate(t *testing.T) {
+	app := &App{
+		logger: cli.NewLogger(false),
+	}
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("DefaultTemplate", func(t *testing.T) {
+		require.NoError(t, app.executeTemplateValidate(context.Background(), ""))
+	})
+
+	t.Run("ValidFile", func(t *testing.T) {
+		path := write("valid.html", "<p>{{.Number}} for {{.Client.Name}}</p>{{range .LineItems}}{{.Description}}{{end}}")
+		require.NoError(t, app.executeTemplateValidate(context.Background(), path))
+	})
+
+	t.Run("UnknownField", func(t *testing.T) {
+		path := write("unknown.html", "<p>{{.Number}}</p>\n{{range .Invoice.Items}}{{.Description}}{{end}}")
+		err := app.executeTemplateValidate(context.Background(), path)
+		require.ErrorIs(t, err, ErrTemplateValidationFailed)
+	})
+
+	t.Run("ParseError", func(t *testing.T) {
+		path := write("broken.html", "<p>{{if .Number}}</p>")
+		err := app.executeTemplateValidate(context.Background(), path)
+		require.ErrorIs(t, err, ErrTemplateValidationFailed)
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		err := app.executeTemplateValidate(context.Background(), filepath.Join(dir, "missing.html"))
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrTemplateValidationFailed)
+	})
+}
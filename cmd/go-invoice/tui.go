@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/services"
+)
+
+// Keys understood by the invoice browser
+const (
+	tuiKeyUp       = "up"
+	tuiKeyDown     = "down"
+	tuiKeyEnter    = "enter"
+	tuiKeyEscape   = "esc"
+	tuiKeyCtrlC    = "ctrl+c"
+	tuiKeyFilter   = "f"
+	tuiKeyStatus   = "s"
+	tuiKeyGenerate = "g"
+	tuiKeyReload   = "r"
+	tuiKeyQuit     = "q"
+)
+
+// tuiDefaultListRows is the number of invoice rows shown when the terminal size is unknown
+const tuiDefaultListRows = 15
+
+// tuiStatusFilters are the status filters cycled by the filter key; empty shows every status
+//
+//nolint:gochecknoglobals // Constant-like filter cycle
+var tuiStatusFilters = []string{"", models.StatusDraft, models.StatusSent, models.StatusOverdue, models.StatusPaid, models.StatusVoided}
+
+// tuiAction is work the browser asks the terminal loop to do after a key press
+type tuiAction int
+
+const (
+	tuiActionNone tuiAction = iota
+	tuiActionQuit
+	tuiActionAdvanceStatus
+	tuiActionGenerate
+	tuiActionReload
+)
+
+// tuiState is the state of the invoice browser, kept apart from the terminal so it can be tested
+type tuiState struct {
+	invoices []*models.Invoice // Every invoice, newest first
+	visible  []*models.Invoice // Invoices matching the status filter
+	filter   int               // Index into tuiStatusFilters
+	cursor   int               // Index into visible of the selected invoice
+	detail   bool              // Show the detail pane for the selected invoice
+	message  string            // Result of the last action
+}
+
+// setInvoices replaces the invoices, keeping the selection on the same invoice when it is still listed
+func (t *tuiState) setInvoices(invoices []*models.Invoice) {
+	var selectedID models.InvoiceID
+	if selected := t.selected(); selected != nil {
+		selectedID = selected.ID
+	}
+
+	t.invoices = invoices
+	t.applyFilter()
+
+	for i, invoice := range t.visible {
+		if invoice.ID == selectedID {
+			t.cursor = i
+		}
+	}
+}
+
+// applyFilter rebuilds the visible invoices from the status filter
+func (t *tuiState) applyFilter() {
+	status := tuiStatusFilters[t.filter]
+	t.visible = t.visible[:0]
+	for _, invoice := range t.invoices {
+		if status == "" || invoice.Status == status {
+			t.visible = append(t.visible, invoice)
+		}
+	}
+	t.cursor = max(0, min(t.cursor, len(t.visible)-1))
+}
+
+// selected returns the selected invoice, or nil when none is listed
+func (t *tuiState) selected() *models.Invoice {
+	if t.cursor < 0 || t.cursor >= len(t.visible) {
+		return nil
+	}
+	return t.visible[t.cursor]
+}
+
+// handleKey applies a key press and returns the action the terminal loop should take
+func (t *tuiState) handleKey(key string) tuiAction {
+	t.message = ""
+
+	switch key {
+	case tuiKeyUp, "k":
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case tuiKeyDown, "j":
+		if t.cursor < len(t.visible)-1 {
+			t.cursor++
+		}
+	case tuiKeyEnter, " ":
+		t.detail = !t.detail
+	case tuiKeyFilter:
+		t.filter = (t.filter + 1) % len(tuiStatusFilters)
+		t.cursor = 0
+		t.applyFilter()
+	case tuiKeyStatus, tuiKeyGenerate:
+		if t.selected() == nil {
+			t.message = "No invoice selected"
+			return tuiActionNone
+		}
+		if key == tuiKeyStatus {
+			return tuiActionAdvanceStatus
+		}
+		return tuiActionGenerate
+	case tuiKeyReload:
+		return tuiActionReload
+	case tuiKeyQuit, tuiKeyEscape, tuiKeyCtrlC:
+		return tuiActionQuit
+	}
+
+	return tuiActionNone
+}
+
+// nextInvoiceStatus returns the status the status key moves an invoice to: drafts are sent,
+// and sent or overdue invoices are paid. Paid and voided invoices have no next status.
+func nextInvoiceStatus(status string) (string, bool) {
+	switch status {
+	case models.StatusDraft:
+		return models.StatusSent, true
+	case models.StatusSent, models.StatusOverdue:
+		return models.StatusPaid, true
+	default:
+		return "", false
+	}
+}
+
+// render writes the browser screen to w, listing at most rows invoices with dates in dateLayout
+func (t *tuiState) render(w io.Writer, rows int, defaultCurrency, dateLayout string) {
+	filter := cmp.Or(tuiStatusFilters[t.filter], "all")
+	_, _ = fmt.Fprintf(w, "go-invoice — %d of %d invoices (status: %s)\n", len(t.visible), len(t.invoices), filter)
+	_, _ = fmt.Fprintf(w, "↑/↓ move  enter details  f filter  s advance status  g generate  r reload  q quit\n\n")
+
+	if len(t.visible) == 0 {
+		_, _ = fmt.Fprintf(w, "No invoices found\n")
+	} else {
+		// Scroll so the selected invoice is always on screen
+		start := 0
+		if t.cursor >= rows {
+			start = t.cursor - rows + 1
+		}
+		end := min(start+rows, len(t.visible))
+
+		table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintf(table, "  NUMBER\tCLIENT\tDATE\tDUE DATE\tSTATUS\tAMOUNT\n")
+		for i := start; i < end; i++ {
+			invoice := t.visible[i]
+			marker := " "
+			if i == t.cursor {
+				marker = ">"
+			}
+			_, _ = fmt.Fprintf(table, "%s %s\t%s\t%s\t%s\t%s\t%s\n", marker, invoice.Number, invoice.Client.Name,
+				invoice.Date.Format(dateLayout), invoice.DueDate.Format(dateLayout), invoice.Status,
+				money.Format(invoice.Total.Float64(), invoice.GetCurrency(defaultCurrency)))
+		}
+		_ = table.Flush()
+	}
+
+	if selected := t.selected(); t.detail && selected != nil {
+		writeTUIDetail(w, selected, defaultCurrency, dateLayout)
+	}
+
+	writeInvoiceSummary(w, buildInvoiceSummary(t.visible, ""))
+
+	if t.message != "" {
+		_, _ = fmt.Fprintf(w, "\n%s\n", t.message)
+	}
+}
+
+// writeTUIDetail writes the detail pane for invoice
+func writeTUIDetail(w io.Writer, invoice *models.Invoice, defaultCurrency, dateLayout string) {
+	currency := invoice.GetCurrency(defaultCurrency)
+
+	_, _ = fmt.Fprintf(w, "\n📄 %s\n", invoice.Number)
+	_, _ = fmt.Fprintf(w, "─────────\n")
+	_, _ = fmt.Fprintf(w, "Client: %s <%s>\n", invoice.Client.Name, invoice.Client.Email)
+	_, _ = fmt.Fprintf(w, "Date: %s  Due: %s  Status: %s\n",
+		invoice.Date.Format(dateLayout), invoice.DueDate.Format(dateLayout), invoice.Status)
+	if invoice.Description != "" {
+		_, _ = fmt.Fprintf(w, "Description: %s\n", invoice.Description)
+	}
+	for _, item := range invoice.GetAllItems() {
+		_, _ = fmt.Fprintf(w, "  • %s  %s\n", item.Description, money.Format(item.Total.Float64(), currency))
+	}
+	_, _ = fmt.Fprintf(w, "Subtotal: %s  Tax: %s  Total: %s\n",
+		money.Format(invoice.Subtotal.Float64(), currency),
+		money.Format(invoice.TaxAmount.Float64(), currency),
+		money.Format(invoice.Total.Float64(), currency))
+	if len(invoice.Payments) > 0 {
+		_, _ = fmt.Fprintf(w, "Paid: %s  Due: %s\n",
+			money.Format(invoice.AmountPaid(), currency), money.Format(invoice.AmountDue(), currency))
+	}
+}
+
+// readTUIKey reads one key press from r, decoding arrow key escape sequences
+func readTUIKey(r *bufio.Reader) (string, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+
+	switch b {
+	case 3:
+		return tuiKeyCtrlC, nil
+	case '\r', '\n':
+		return tuiKeyEnter, nil
+	case 27:
+		// A lone escape, or the start of an arrow key sequence already in the buffer
+		if r.Buffered() < 2 {
+			return tuiKeyEscape, nil
+		}
+		seq := make([]byte, 2)
+		if _, err = io.ReadFull(r, seq); err != nil {
+			return "", err
+		}
+		switch string(seq) {
+		case "[A", "OA":
+			return tuiKeyUp, nil
+		case "[B", "OB":
+			return tuiKeyDown, nil
+		}
+		return "", nil
+	}
+
+	return string(b), nil
+}
+
+// buildTUICommand creates the tui command
+func (a *App) buildTUICommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tui",
+		Short: "Browse invoices interactively",
+		Long: `Open a terminal browser for your invoices.
+
+Move with the arrow keys (or j/k), press enter to show the selected invoice's details,
+f to cycle the status filter, s to move the selected invoice to its next status
+(draft → sent → paid), g to generate it, r to reload and q to quit. The summary below
+the list covers the invoices shown.
+
+The browser needs an interactive terminal; elsewhere it prints a message and exits.`,
+		Args: cobra.NoArgs,
+		RunE: a.runTUI,
+	}
+}
+
+// runTUI handles the tui command
+func (a *App) runTUI(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	if !isInteractiveInput() || !isTerminalOutput() {
+		a.logger.Println("The invoice browser needs an interactive terminal. Use \"go-invoice invoice list\" instead.")
+		return nil
+	}
+
+	configPath, _ := cmd.Flags().GetString("config")
+	config, err := a.configService.LoadConfig(ctx, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Keep routine service logs from drawing over the screen
+	logger := a.logger
+	quietLogger, err := cli.NewLoggerWithOptions(cli.LoggerOptions{Level: "error"})
+	if err != nil {
+		return err
+	}
+	a.logger = quietLogger
+	defer func() { a.logger = logger }()
+
+	invoiceStorage, clientStorage := a.createStorageInstances(config.Storage)
+	invoiceService := a.newInvoiceService(config, invoiceStorage, clientStorage)
+	invoiceService.SetNotifier(a.newNotifier(config.Webhook))
+
+	state := &tuiState{}
+	load := func() error {
+		result, listErr := invoiceService.ListInvoices(ctx, models.InvoiceFilter{})
+		if listErr != nil {
+			return fmt.Errorf("failed to list invoices: %w", listErr)
+		}
+		state.setInvoices(result.Invoices)
+		return nil
+	}
+	if err = load(); err != nil {
+		return err
+	}
+
+	fd := int(os.Stdin.Fd()) // #nosec G115 -- File descriptors fit in an int
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to switch the terminal to raw mode: %w", err)
+	}
+	defer func() { _ = term.Restore(fd, oldState) }()
+
+	input := bufio.NewReader(os.Stdin)
+	for {
+		a.drawTUI(state, config.Invoice.Currency, config.Invoice.DateLayout())
+
+		key, keyErr := readTUIKey(input)
+		if keyErr != nil {
+			return keyErr
+		}
+
+		switch state.handleKey(key) {
+		case tuiActionQuit:
+			_, _ = fmt.Fprint(os.Stdout, "\x1b[2J\x1b[H")
+			return nil
+		case tuiActionReload:
+			if err = load(); err != nil {
+				state.message = err.Error()
+			}
+		case tuiActionAdvanceStatus:
+			state.message = a.advanceTUIInvoiceStatus(ctx, invoiceService, state.selected())
+			if err = load(); err != nil {
+				state.message = err.Error()
+			}
+		case tuiActionGenerate:
+			// Generation prints progress and may ask for confirmation, so run it on a normal terminal
+			_ = term.Restore(fd, oldState)
+			_, _ = fmt.Fprint(os.Stdout, "\x1b[2J\x1b[H")
+			a.logger = logger
+			genErr := a.executeGenerateInvoice(ctx, string(state.selected().ID), configPath,
+				GenerateInvoiceOptions{Validate: true, TaxRate: -1})
+			a.logger = quietLogger
+			if genErr != nil {
+				a.logger.Printf("\n❌ %v\n", genErr)
+			}
+			a.logger.Printf("\nPress any key to return to the invoice list")
+			if _, err = term.MakeRaw(fd); err != nil {
+				return fmt.Errorf("failed to switch the terminal to raw mode: %w", err)
+			}
+			_, _ = input.ReadByte()
+			if err = load(); err != nil {
+				state.message = err.Error()
+			}
+		case tuiActionNone:
+		}
+	}
+}
+
+// drawTUI clears the terminal and draws the browser, sized to fit the terminal
+func (a *App) drawTUI(state *tuiState, defaultCurrency, dateLayout string) {
+	rows := tuiDefaultListRows
+	if _, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil { // #nosec G115 -- File descriptors fit in an int
+		// Leave room for the header, summary and detail pane
+		rows = max(3, height-30)
+	}
+
+	var screen strings.Builder
+	state.render(&screen, rows, defaultCurrency, dateLayout)
+
+	// Raw mode does not turn \n into \r\n
+	_, _ = fmt.Fprint(os.Stdout, "\x1b[2J\x1b[H"+strings.ReplaceAll(screen.String(), "\n", "\r\n"))
+}
+
+// advanceTUIInvoiceStatus moves invoice to its next status and describes the outcome
+func (a *App) advanceTUIInvoiceStatus(ctx context.Context, invoiceService *services.InvoiceService, invoice *models.Invoice) string {
+	next, ok := nextInvoiceStatus(invoice.Status)
+	if !ok {
+		return fmt.Sprintf("%s is %s; it has no next status", invoice.Number, invoice.Status)
+	}
+
+	updated, err := invoiceService.UpdateInvoice(ctx, models.UpdateInvoiceRequest{ID: invoice.ID, Status: &next})
+	if err != nil {
+		return fmt.Sprintf("❌ %s: %v", invoice.Number, err)
+	}
+	return fmt.Sprintf("✅ %s: %s → %s", updated.Number, invoice.Status, updated.Status)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// newTUITestState returns a browser over a draft, a sent and a paid invoice
+func newTUITestState() *tuiState {
+	now := time.Now()
+	client := models.Client{ID: "CLIENT-1", Name: "Acme", Email: "billing@acme.example"}
+	state := &tuiState{}
+	state.setInvoices([]*models.Invoice{
+		{ID: "inv-1", Number: "INV-0001", Client: client, Currency: "USD", Total: money.FromFloat(100), Status: models.StatusDraft, Date: now, DueDate: now},
+		{ID: "inv-2", Number: "INV-0002", Client: client, Currency: "USD", Total: money.FromFloat(200), Status: models.StatusSent, Date: now, DueDate: now},
+		{ID: "inv-3", Number: "INV-0003", Client: client, Currency: "USD", Total: money.FromFloat(300), Status: models.StatusPaid, Date: now, DueDate: now},
+	})
+	return state
+}
+
+func TestTUIStateNavigationAndFilter(t *testing.T) {
+	state := newTUITestState()
+	require.Len(t, state.visible, 3)
+
+	assert.Equal(t, tuiActionNone, state.handleKey(tuiKeyUp))
+	assert.Equal(t, "INV-0001", state.selected().Number, "the cursor stops at the top")
+	state.handleKey(tuiKeyDown)
+	state.handleKey("j")
+	state.handleKey(tuiKeyDown)
+	assert.Equal(t, "INV-0003", state.selected().Number, "the cursor stops at the bottom")
+
+	// The first filter shows drafts only
+	state.handleKey(tuiKeyFilter)
+	require.Len(t, state.visible, 1)
+	assert.Equal(t, "INV-0001", state.selected().Number)
+
+	// Reloading keeps the selection on the same invoice
+	state.handleKey(tuiKeyFilter)
+	assert.Equal(t, "INV-0002", state.selected().Number)
+	state.setInvoices(state.invoices)
+	assert.Equal(t, "INV-0002", state.selected().Number)
+
+	// Cycling through every filter comes back to all invoices
+	for range len(tuiStatusFilters) - 2 {
+		state.handleKey(tuiKeyFilter)
+	}
+	assert.Len(t, state.visible, 3)
+}
+
+func TestTUIStateActions(t *testing.T) {
+	state := newTUITestState()
+
+	assert.Equal(t, tuiActionAdvanceStatus, state.handleKey(tuiKeyStatus))
+	assert.Equal(t, tuiActionGenerate, state.handleKey(tuiKeyGenerate))
+	assert.Equal(t, tuiActionReload, state.handleKey(tuiKeyReload))
+	assert.Equal(t, tuiActionQuit, state.handleKey(tuiKeyQuit))
+	assert.Equal(t, tuiActionQuit, state.handleKey(tuiKeyCtrlC))
+
+	state.handleKey(tuiKeyEnter)
+	assert.True(t, state.detail)
+
+	// Nothing to act on without invoices
+	state.setInvoices(nil)
+	assert.Equal(t, tuiActionNone, state.handleKey(tuiKeyStatus))
+	assert.Equal(t, "No invoice selected", state.message)
+}
+
+func TestNextInvoiceStatus(t *testing.T) {
+	tests := map[string]string{
+		models.StatusDraft:   models.StatusSent,
+		models.StatusSent:    models.StatusPaid,
+		models.StatusOverdue: models.StatusPaid,
+		models.StatusPaid:    "",
+		models.StatusVoided:  "",
+	}
+
+	for status, want := range tests {
+		next, ok := nextInvoiceStatus(status)
+		assert.Equal(t, want, next, status)
+		assert.Equal(t, want != "", ok, status)
+	}
+}
+
+func TestTUIStateRender(t *testing.T) {
+	state := newTUITestState()
+	state.handleKey(tuiKeyDown)
+	state.handleKey(tuiKeyEnter)
+
+	var screen strings.Builder
+	state.render(&screen, 2, "USD", "02/01/2006")
+	output := screen.String()
+
+	assert.Contains(t, output, "3 of 3 invoices (status: all)")
+	assert.Contains(t, output, "> INV-0002")
+	assert.NotContains(t, output, "INV-0003  ", "only two rows fit")
+	assert.Contains(t, output, "📄 INV-0002")
+	assert.Contains(t, output, "Date: "+state.selected().Date.Format("02/01/2006"), "dates follow DATE_FORMAT")
+	assert.Contains(t, output, "Total Invoices: 3")
+	assert.Contains(t, output, "Unpaid by Age (days)")
+}
+
+func TestReadTUIKey(t *testing.T) {
+	input := bufio.NewReader(strings.NewReader("\x1b[A\x1b[Bq\r\x03"))
+	// Fill the buffer as a terminal read would
+	_, err := input.Peek(1)
+	require.NoError(t, err)
+
+	for _, want := range []string{tuiKeyUp, tuiKeyDown, tuiKeyQuit, tuiKeyEnter, tuiKeyCtrlC} {
+		key, keyErr := readTUIKey(input)
+		require.NoError(t, keyErr)
+		assert.Equal(t, want, key)
+	}
+}
@@ -0,0 +1,81 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MockRateProvider is a RateProvider returning configured rates, for tests and offline use
+type MockRateProvider struct {
+	rates map[string]float64 // token/fiat -> rate
+	asOf  time.Time
+	err   error
+	calls int
+}
+
+// NewMockRateProvider creates a mock rate provider with no rates configured
+func NewMockRateProvider() *MockRateProvider {
+	return &MockRateProvider{
+		rates: make(map[string]float64),
+		asOf:  time.Now(),
+	}
+}
+
+// SetRate configures the rate, in token units per one unit of fiat, returned for token and fiatCurrency
+func (m *MockRateProvider) SetRate(token TokenType, fiatCurrency string, rate float64) {
+	m.rates[mockRateKey(token, fiatCurrency)] = rate
+}
+
+// SetAsOf configures the time reported on returned rates
+func (m *MockRateProvider) SetAsOf(asOf time.Time) {
+	m.asOf = asOf
+}
+
+// SetError configures an error to return from GetRate
+func (m *MockRateProvider) SetError(err error) {
+	m.err = err
+}
+
+// Calls returns how many times GetRate was called
+func (m *MockRateProvider) Calls() int {
+	return m.calls
+}
+
+// GetRate returns the configured rate from fiatCurrency to token
+func (m *MockRateProvider) GetRate(ctx context.Context, token TokenType, fiatCurrency string) (*Rate, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	rate, ok := m.rates[mockRateKey(token, fiatCurrency)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrRateUnavailable, fiatCurrency, token)
+	}
+
+	return &Rate{
+		Token:        token,
+		FiatCurrency: strings.ToUpper(fiatCurrency),
+		Rate:         rate,
+		AsOf:         m.asOf,
+		Provider:     m.Name(),
+	}, nil
+}
+
+// Name returns the provider name
+func (m *MockRateProvider) Name() string {
+	return "mock"
+}
+
+// mockRateKey returns the key a mock rate is stored under
+func mockRateKey(token TokenType, fiatCurrency string) string {
+	return string(token) + "/" + strings.ToUpper(fiatCurrency)
+}
@@ -0,0 +1,132 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrRateUnavailable is returned when a rate provider has no rate for a token and currency
+	ErrRateUnavailable = errors.New("exchange rate unavailable")
+	// ErrCoinGeckoAPIStatus is returned when the CoinGecko API returns a non-200 status
+	ErrCoinGeckoAPIStatus = errors.New("coingecko API returned non-200 status")
+)
+
+// CoinGeckoURL is the CoinGecko public API endpoint
+const CoinGeckoURL = "https://api.coingecko.com/api/v3"
+
+// Rate is an exchange rate from a fiat currency to a token
+type Rate struct {
+	Token        TokenType // Token the rate converts to
+	FiatCurrency string    // ISO 4217 code the rate converts from
+	Rate         float64   // Token units per one unit of fiat currency
+	AsOf         time.Time // When the rate was fetched
+	Provider     string    // Provider name (e.g., "coingecko", "mock")
+}
+
+// RateProvider fetches fiat to token exchange rates, so amounts due in fiat can be quoted in
+// the token a client pays with
+type RateProvider interface {
+	// GetRate returns the current rate from fiatCurrency to token
+	GetRate(ctx context.Context, token TokenType, fiatCurrency string) (*Rate, error)
+
+	// Name returns the provider name (e.g., "coingecko", "mock")
+	Name() string
+}
+
+// coinGeckoIDs maps tokens to their CoinGecko coin IDs
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var coinGeckoIDs = map[TokenType]string{
+	TokenTypeUSDC: "usd-coin",
+	TokenTypeBSV:  "bitcoin-cash-sv",
+}
+
+// CoinGeckoRateProvider implements RateProvider with the CoinGecko simple price API
+type CoinGeckoRateProvider struct {
+	apiURL     string
+	httpClient *http.Client
+}
+
+// NewCoinGeckoRateProvider creates a rate provider using the CoinGecko public API
+func NewCoinGeckoRateProvider() *CoinGeckoRateProvider {
+	return &CoinGeckoRateProvider{
+		apiURL: CoinGeckoURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetRate returns the current rate from fiatCurrency to token
+func (c *CoinGeckoRateProvider) GetRate(ctx context.Context, token TokenType, fiatCurrency string) (*Rate, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	coinID, ok := coinGeckoIDs[token]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported token %s", ErrRateUnavailable, token)
+	}
+	currency := strings.ToLower(fiatCurrency)
+
+	params := url.Values{}
+	params.Set("ids", coinID)
+	params.Set("vs_currencies", currency)
+	reqURL := fmt.Sprintf("%s/simple/price?%s", c.apiURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange rate: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d", ErrCoinGeckoAPIStatus, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// The response holds the fiat price of one token, e.g. {"usd-coin":{"usd":0.9998}}
+	var prices map[string]map[string]float64
+	if unmarshalErr := json.Unmarshal(body, &prices); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", unmarshalErr)
+	}
+
+	price := prices[coinID][currency]
+	if price <= 0 {
+		return nil, fmt.Errorf("%w: no %s price for %s", ErrRateUnavailable, strings.ToUpper(currency), token)
+	}
+
+	return &Rate{
+		Token:        token,
+		FiatCurrency: strings.ToUpper(fiatCurrency),
+		Rate:         1 / price,
+		AsOf:         time.Now(),
+		Provider:     c.Name(),
+	}, nil
+}
+
+// Name returns the provider name
+func (c *CoinGeckoRateProvider) Name() string {
+	return "coingecko"
+}
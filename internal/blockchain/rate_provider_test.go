@@ -0,0 +1,105 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoinGeckoRateProvider_GetRate(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         TokenType
+		response      string
+		statusCode    int
+		expectedRate  float64
+		expectedError error
+	}{
+		{
+			name:         "USDC",
+			token:        TokenTypeUSDC,
+			response:     `{"usd-coin":{"usd":0.5}}`,
+			statusCode:   http.StatusOK,
+			expectedRate: 2,
+		},
+		{
+			name:         "BSV",
+			token:        TokenTypeBSV,
+			response:     `{"bitcoin-cash-sv":{"usd":50}}`,
+			statusCode:   http.StatusOK,
+			expectedRate: 0.02,
+		},
+		{
+			name:          "MissingPrice",
+			token:         TokenTypeUSDC,
+			response:      `{}`,
+			statusCode:    http.StatusOK,
+			expectedError: ErrRateUnavailable,
+		},
+		{
+			name:          "RateLimited",
+			token:         TokenTypeUSDC,
+			response:      `{}`,
+			statusCode:    http.StatusTooManyRequests,
+			expectedError: ErrCoinGeckoAPIStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/simple/price", r.URL.Path)
+				assert.Equal(t, coinGeckoIDs[tt.token], r.URL.Query().Get("ids"))
+				assert.Equal(t, "usd", r.URL.Query().Get("vs_currencies"))
+
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			provider := NewCoinGeckoRateProvider()
+			provider.apiURL = server.URL
+
+			rate, err := provider.GetRate(context.Background(), tt.token, "USD")
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.token, rate.Token)
+			assert.Equal(t, "USD", rate.FiatCurrency)
+			assert.InDelta(t, tt.expectedRate, rate.Rate, 1e-12)
+			assert.Equal(t, "coingecko", rate.Provider)
+			assert.False(t, rate.AsOf.IsZero())
+		})
+	}
+}
+
+func TestMockRateProvider(t *testing.T) {
+	ctx := context.Background()
+	asOf := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	provider := NewMockRateProvider()
+	provider.SetRate(TokenTypeUSDC, "usd", 1.0002)
+	provider.SetAsOf(asOf)
+
+	rate, err := provider.GetRate(ctx, TokenTypeUSDC, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, &Rate{Token: TokenTypeUSDC, FiatCurrency: "USD", Rate: 1.0002, AsOf: asOf, Provider: "mock"}, rate)
+
+	_, err = provider.GetRate(ctx, TokenTypeBSV, "USD")
+	require.ErrorIs(t, err, ErrRateUnavailable)
+
+	errOffline := errors.New("offline")
+	provider.SetError(errOffline)
+	_, err = provider.GetRate(ctx, TokenTypeUSDC, "USD")
+	require.ErrorIs(t, err, errOffline)
+
+	assert.Equal(t, 3, provider.Calls())
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// emojiRanges are the code point ranges treated as emoji decorations, including the
+// variation selector and zero width joiner used to build emoji sequences
+var emojiRanges = [][2]rune{
+	{0x200D, 0x200D},   // Zero width joiner
+	{0x2139, 0x2139},   // ℹ
+	{0x231A, 0x23FF},   // ⌚ ⏰ ⏳
+	{0x2600, 0x27BF},   // Miscellaneous symbols and dingbats: ✅ ⚠ ❌ ✏
+	{0x2B00, 0x2BFF},   // ⭐ ⬆
+	{0xFE0F, 0xFE0F},   // Emoji presentation selector
+	{0x1F000, 0x1FAFF}, // Pictographs: 📋 💰 🎨
+}
+
+// isEmoji reports whether r is an emoji decoration
+func isEmoji(r rune) bool {
+	for _, bounds := range emojiRanges {
+		if r >= bounds[0] && r <= bounds[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// StripEmoji removes emoji from text along with the spaces that follow them, so
+// "✅ Invoice created" becomes "Invoice created" and indentation before an emoji is kept
+func StripEmoji(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	skipSpaces := false
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+
+		switch {
+		case isEmoji(r):
+			skipSpaces = true
+		case skipSpaces && r == ' ':
+		default:
+			skipSpaces = false
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
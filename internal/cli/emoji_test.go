@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripEmoji(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Checkmark", "✅ Invoice created successfully!\n", "Invoice created successfully!\n"},
+		{"VariationSelector", "⚠️  Late fee policy enabled", "Late fee policy enabled"},
+		{"Pictograph", "📋 Invoice Details", "Invoice Details"},
+		{"KeepsIndentation", "   ✅ INV-001: draft → sent", "   INV-001: draft → sent"},
+		{"KeepsOtherSymbols", "• 2.00 × $15.00 — 50%", "• 2.00 × $15.00 — 50%"},
+		{"NoEmoji", "Total: 3 invoice(s)", "Total: 3 invoice(s)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, StripEmoji(tt.input))
+		})
+	}
+}
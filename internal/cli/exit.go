@@ -0,0 +1,107 @@
+package cli
+
+import "errors"
+
+// Process exit codes. They are part of the command line interface: scripts branch on
+// them, so existing values must never change.
+const (
+	ExitOK          = 0   // The command succeeded
+	ExitInternal    = 1   // Unexpected failure, such as an I/O error
+	ExitUsage       = 2   // Unknown command, bad flag or wrong arguments
+	ExitNotFound    = 3   // The invoice, client or file does not exist
+	ExitConflict    = 4   // The change conflicts with existing data or a concurrent edit
+	ExitValidation  = 5   // The input or resulting data failed validation
+	ExitConfig      = 6   // The configuration is missing or invalid
+	ExitInterrupted = 130 // Interrupted by Ctrl-C or SIGTERM
+)
+
+// ErrorKind classifies why a command failed
+type ErrorKind int
+
+// Error kinds, each mapping to one exit code
+const (
+	KindInternal ErrorKind = iota
+	KindUsage
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindConfig
+	KindInterrupted
+)
+
+// ExitCode returns the process exit code for the kind
+func (k ErrorKind) ExitCode() int {
+	switch k {
+	case KindUsage:
+		return ExitUsage
+	case KindNotFound:
+		return ExitNotFound
+	case KindConflict:
+		return ExitConflict
+	case KindValidation:
+		return ExitValidation
+	case KindConfig:
+		return ExitConfig
+	case KindInterrupted:
+		return ExitInterrupted
+	case KindInternal:
+		return ExitInternal
+	default:
+		return ExitInternal
+	}
+}
+
+// String returns the kind name used in logs
+func (k ErrorKind) String() string {
+	switch k {
+	case KindUsage:
+		return "usage"
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindValidation:
+		return "validation"
+	case KindConfig:
+		return "config"
+	case KindInterrupted:
+		return "interrupted"
+	case KindInternal:
+		return "internal"
+	default:
+		return "internal"
+	}
+}
+
+// Error is a command failure with an explicit kind. The message is the wrapped error's,
+// so marking an error does not change what the user sees.
+type Error struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithKind marks err as a failure of the given kind; a nil err stays nil
+func WithKind(kind ErrorKind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// KindOf returns the kind of the outermost Error in err's chain
+func KindOf(err error) (ErrorKind, bool) {
+	var cliErr *Error
+	if errors.As(err, &cliErr) {
+		return cliErr.Kind, true
+	}
+	return KindInternal, false
+}
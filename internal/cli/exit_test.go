@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorKindExitCode(t *testing.T) {
+	codes := map[ErrorKind]int{
+		KindInternal:    1,
+		KindUsage:       2,
+		KindNotFound:    3,
+		KindConflict:    4,
+		KindValidation:  5,
+		KindConfig:      6,
+		KindInterrupted: 130,
+	}
+	for kind, code := range codes {
+		assert.Equal(t, code, kind.ExitCode(), kind.String())
+	}
+	assert.Equal(t, ExitInternal, ErrorKind(99).ExitCode())
+	assert.Equal(t, "not_found", KindNotFound.String())
+}
+
+func TestWithKind(t *testing.T) {
+	require.NoError(t, WithKind(KindUsage, nil))
+
+	base := errors.New("accepts 1 arg(s), received 0") //nolint:err113 // test-only error
+	err := fmt.Errorf("command failed: %w", WithKind(KindUsage, base))
+	assert.Equal(t, "command failed: accepts 1 arg(s), received 0", err.Error())
+	require.ErrorIs(t, err, base)
+
+	kind, ok := KindOf(err)
+	assert.True(t, ok)
+	assert.Equal(t, KindUsage, kind)
+
+	_, ok = KindOf(base)
+	assert.False(t, ok)
+}
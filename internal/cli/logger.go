@@ -2,61 +2,223 @@
 package cli
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
+	"slices"
+	"strings"
 )
 
-// SimpleLogger provides basic logging functionality
+// Log formats for the structured log methods
+const (
+	LogFormatText = "text" // [LEVEL] message key=value, through the standard log package
+	LogFormatJSON = "json" // One JSON object per line
+)
+
+// levelFatal is the slog level used by Fatal
+const levelFatal = slog.LevelError + 4
+
+// Logger option errors
+var (
+	ErrInvalidLogFormat = fmt.Errorf("invalid log format (use text or json)")
+	ErrInvalidLogLevel  = fmt.Errorf("invalid log level (use debug, info, warn or error)")
+)
+
+// LoggerOptions configures NewLoggerWithOptions
+type LoggerOptions struct {
+	Level   string    // debug, info, warn or error; empty means info
+	Format  string    // LogFormatText or LogFormatJSON; empty means text
+	Output  io.Writer // Destination for JSON logs; nil means stderr
+	Quiet   bool      // Send status output to stderr, leaving stdout for results
+	NoEmoji bool      // Strip emoji decorations from status output
+	JSON    bool      // Commands print their result as JSON on stdout; status output goes to stderr without emoji
+}
+
+// SimpleLogger separates terminal output from logs.
+//
+// Print, Printf and Println write user-facing status text to stdout, or to stderr
+// in quiet and JSON mode. Result writes the primary result of a command in quiet
+// mode, and ResultJSON the whole result object in JSON mode.
+// Debug, Info, Warn, Error and Fatal are structured logs backed by log/slog,
+// filtered by level and written as text or JSON so they can be parsed without
+// the UI text mixed in.
 type SimpleLogger struct {
-	debug bool
+	debug   bool
+	quiet   bool
+	noEmoji bool
+	json    bool
+	logger  *slog.Logger
 }
 
-// NewLogger creates a new simple logger
+// NewLogger creates a text logger at info level, or debug level when debug is true
 func NewLogger(debug bool) *SimpleLogger {
-	return &SimpleLogger{debug: debug}
+	level := slog.LevelInfo
+	if debug {
+		level = slog.LevelDebug
+	}
+	return newSimpleLogger(&textHandler{level: level}, level)
+}
+
+// NewLoggerWithOptions creates a logger with the given level and format
+func NewLoggerWithOptions(opts LoggerOptions) (*SimpleLogger, error) {
+	level, err := ParseLogLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var logger *SimpleLogger
+	switch strings.ToLower(opts.Format) {
+	case "", LogFormatText:
+		logger = newSimpleLogger(&textHandler{level: level}, level)
+	case LogFormatJSON:
+		output := opts.Output
+		if output == nil {
+			output = os.Stderr
+		}
+		handler := slog.NewJSONHandler(output, &slog.HandlerOptions{Level: level, ReplaceAttr: replaceLevelName})
+		logger = newSimpleLogger(handler, level)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidLogFormat, opts.Format)
+	}
+
+	logger.quiet = opts.Quiet || opts.JSON
+	logger.noEmoji = opts.NoEmoji || opts.JSON
+	logger.json = opts.JSON
+	return logger, nil
+}
+
+// ParseLogLevel converts debug, info, warn (or warning) and error to a slog level.
+// An empty level means info.
+func ParseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrInvalidLogLevel, level)
+	}
+}
+
+func newSimpleLogger(handler slog.Handler, level slog.Level) *SimpleLogger {
+	return &SimpleLogger{
+		debug:  level <= slog.LevelDebug,
+		logger: slog.New(handler),
+	}
+}
+
+// Debug logs a debug message if debug level is enabled
+func (l *SimpleLogger) Debug(msg string, fields ...any) {
+	l.log(slog.LevelDebug, msg, fields...)
 }
 
 // Info logs an info message
 func (l *SimpleLogger) Info(msg string, fields ...any) {
-	log.Printf("[INFO] %s %s", msg, l.formatFields(fields...))
+	l.log(slog.LevelInfo, msg, fields...)
 }
 
-// Error logs an error message
-func (l *SimpleLogger) Error(msg string, fields ...any) {
-	log.Printf("[ERROR] %s %s", msg, l.formatFields(fields...))
+// Warn logs a warning message
+func (l *SimpleLogger) Warn(msg string, fields ...any) {
+	l.log(slog.LevelWarn, msg, fields...)
 }
 
-// Debug logs a debug message if debug mode is enabled
-func (l *SimpleLogger) Debug(msg string, fields ...any) {
-	if l.debug {
-		log.Printf("[DEBUG] %s %s", msg, l.formatFields(fields...))
-	}
+// Error logs an error message
+func (l *SimpleLogger) Error(msg string, fields ...any) {
+	l.log(slog.LevelError, msg, fields...)
 }
 
 // Fatal logs a fatal message and exits
 func (l *SimpleLogger) Fatal(msg string, fields ...any) {
-	log.Printf("[FATAL] %s %s", msg, l.formatFields(fields...))
+	l.log(levelFatal, msg, fields...)
 	os.Exit(1)
 }
 
-// Print prints a message to stdout without any formatting
+// Print prints a status message without any formatting
 func (l *SimpleLogger) Print(msg string) {
-	fmt.Print(msg) //nolint:forbidigo // Console output for CLI
+	l.status(msg)
 }
 
-// Printf prints a formatted message to stdout
+// Printf prints a formatted status message
 func (l *SimpleLogger) Printf(format string, args ...any) {
-	fmt.Printf(format, args...) //nolint:forbidigo // Console output for CLI
+	l.status(fmt.Sprintf(format, args...))
 }
 
-// Println prints a message to stdout with a newline
+// Println prints a status message with a newline
 func (l *SimpleLogger) Println(msg string) {
-	fmt.Println(msg) //nolint:forbidigo // Console output for CLI
+	l.status(msg + "\n")
+}
+
+// Result prints the primary result of a command, such as a created invoice number,
+// on its own line in quiet mode so scripts can capture it from stdout. Otherwise it
+// prints nothing, as the status output already shows the result.
+func (l *SimpleLogger) Result(value string) {
+	if l.quiet && !l.json {
+		fmt.Println(value) //nolint:forbidigo // Console output for CLI
+	}
+}
+
+// ResultJSON prints the result of a command, such as the invoice it changed, as indented
+// JSON on stdout in JSON mode so scripts can parse the outcome. Otherwise it prints
+// nothing, as the status output already shows the result.
+func (l *SimpleLogger) ResultJSON(value any) error {
+	if !l.json {
+		return nil
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to write JSON result: %w", err)
+	}
+	return nil
+}
+
+// IsQuiet reports whether the logger is in quiet mode, where only results go to stdout
+func (l *SimpleLogger) IsQuiet() bool {
+	return l.quiet
+}
+
+// IsJSON reports whether the logger is in JSON mode, where results are printed as JSON
+func (l *SimpleLogger) IsJSON() bool {
+	return l.json
+}
+
+// status writes user-facing text to stdout, or to stderr in quiet mode
+func (l *SimpleLogger) status(text string) {
+	if l.noEmoji {
+		text = StripEmoji(text)
+	}
+	if l.quiet {
+		fmt.Fprint(os.Stderr, text)
+		return
+	}
+	fmt.Print(text) //nolint:forbidigo // Console output for CLI
+}
+
+// log converts key-value pairs to attributes and hands the record to slog.
+// Keys are formatted with %v and a trailing key without a value is dropped.
+func (l *SimpleLogger) log(level slog.Level, msg string, fields ...any) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		attrs = append(attrs, slog.Any(fmt.Sprint(fields[i]), fields[i+1]))
+	}
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
 }
 
 // formatFields formats key-value pairs for logging
-func (l *SimpleLogger) formatFields(fields ...any) string {
+func formatFields(fields ...any) string {
 	if len(fields) == 0 {
 		return ""
 	}
@@ -69,3 +231,65 @@ func (l *SimpleLogger) formatFields(fields ...any) string {
 	}
 	return result
 }
+
+// levelName returns the label printed for a level
+func levelName(level slog.Level) string {
+	if level >= levelFatal {
+		return "FATAL"
+	}
+	return level.String()
+}
+
+// replaceLevelName labels the fatal level in JSON output
+func replaceLevelName(_ []string, attr slog.Attr) slog.Attr {
+	if attr.Key == slog.LevelKey {
+		if level, ok := attr.Value.Any().(slog.Level); ok {
+			attr.Value = slog.StringValue(levelName(level))
+		}
+	}
+	return attr
+}
+
+// textHandler is a slog handler writing "[LEVEL] message key=value" lines through the
+// standard log package, so log.SetOutput and log flags apply
+type textHandler struct {
+	level  slog.Level
+	fields []any
+	group  string
+}
+
+// Enabled reports whether records at level are written
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// Handle writes a record
+func (h *textHandler) Handle(_ context.Context, record slog.Record) error {
+	fields := slices.Clone(h.fields)
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = append(fields, h.group+attr.Key, attr.Value.Any())
+		return true
+	})
+	log.Printf("[%s] %s %s", levelName(record.Level), record.Message, formatFields(fields...))
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to every record
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.fields = slices.Clone(h.fields)
+	for _, attr := range attrs {
+		clone.fields = append(clone.fields, h.group+attr.Key, attr.Value.Any())
+	}
+	return &clone
+}
+
+// WithGroup returns a handler that prefixes later keys with name
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.group = h.group + name + "."
+	return &clone
+}
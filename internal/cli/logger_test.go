@@ -2,13 +2,16 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -110,6 +113,29 @@ func (suite *LoggerTestSuite) TestDebugLoggingEnabled() {
 	suite.Contains(output, "debug message should appear")
 }
 
+// TestWarnLogging tests warning message logging
+func (suite *LoggerTestSuite) TestWarnLogging() {
+	suite.logger.Warn("disk nearly full", "free", "5%")
+
+	output := suite.logOutput.String()
+	suite.Contains(output, "[WARN]")
+	suite.Contains(output, "disk nearly full")
+	suite.Contains(output, "free=5%")
+}
+
+// TestLevelFiltering tests that messages below the configured level are dropped
+func (suite *LoggerTestSuite) TestLevelFiltering() {
+	logger, err := NewLoggerWithOptions(LoggerOptions{Level: "warn"})
+	suite.Require().NoError(err)
+
+	logger.Info("hidden info")
+	logger.Warn("shown warning")
+
+	output := suite.logOutput.String()
+	suite.NotContains(output, "hidden info")
+	suite.Contains(output, "shown warning")
+}
+
 // TestDebugLoggingWithFields tests debug message logging with fields
 func (suite *LoggerTestSuite) TestDebugLoggingWithFields() {
 	debugLogger := NewLogger(true)
@@ -158,7 +184,7 @@ func (suite *LoggerTestSuite) TestFormatFields() {
 
 	for _, tt := range tests {
 		suite.Run(tt.name, func() {
-			result := suite.logger.formatFields(tt.fields...)
+			result := formatFields(tt.fields...)
 			suite.Equal(tt.expected, result)
 		})
 	}
@@ -225,7 +251,7 @@ func (suite *LoggerTestSuite) TestFatalLoggingFormat() {
 	fields := []any{"component", "database", "error", "connection timeout"}
 
 	// Test the format that would be used (same as other log methods)
-	expectedFields := suite.logger.formatFields(fields...)
+	expectedFields := formatFields(fields...)
 	expectedFormat := "[FATAL] " + msg + " " + expectedFields
 
 	// Verify the format is constructed correctly
@@ -342,8 +368,6 @@ func TestLoggerTestSuite(t *testing.T) {
 
 // TestLoggerFieldFormatting tests edge cases in field formatting
 func TestLoggerFieldFormatting(t *testing.T) {
-	logger := NewLogger(false)
-
 	tests := []struct {
 		name   string
 		fields []any
@@ -374,8 +398,162 @@ func TestLoggerFieldFormatting(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := logger.formatFields(tt.fields...)
+			result := formatFields(tt.fields...)
 			assert.True(t, tt.check(result), "formatting check failed for: %s", result)
 		})
 	}
 }
+
+func TestNewLoggerWithOptions(t *testing.T) {
+	t.Run("JSONFormat", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := NewLoggerWithOptions(LoggerOptions{Level: "debug", Format: LogFormatJSON, Output: &buf})
+		require.NoError(t, err)
+		assert.True(t, logger.debug)
+
+		logger.Debug("loaded", "count", 3, "dangling")
+		logger.Error("failed", "error", "boom")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+		assert.Equal(t, "DEBUG", entry["level"])
+		assert.Equal(t, "loaded", entry["msg"])
+		assert.InDelta(t, 3, entry["count"], 0)
+		assert.NotContains(t, entry, "dangling")
+
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+		assert.Equal(t, "ERROR", entry["level"])
+		assert.Equal(t, "boom", entry["error"])
+	})
+
+	t.Run("DefaultsToTextAtInfo", func(t *testing.T) {
+		logger, err := NewLoggerWithOptions(LoggerOptions{})
+		require.NoError(t, err)
+		assert.False(t, logger.debug)
+		assert.IsType(t, &textHandler{}, logger.logger.Handler())
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		_, err := NewLoggerWithOptions(LoggerOptions{Format: "xml"})
+		require.ErrorIs(t, err, ErrInvalidLogFormat)
+	})
+
+	t.Run("InvalidLevel", func(t *testing.T) {
+		_, err := NewLoggerWithOptions(LoggerOptions{Level: "verbose"})
+		require.ErrorIs(t, err, ErrInvalidLogLevel)
+	})
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected slog.Level
+	}{
+		{"", slog.LevelInfo},
+		{"debug", slog.LevelDebug},
+		{"INFO", slog.LevelInfo},
+		{"warning", slog.LevelWarn},
+		{"error", slog.LevelError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			level, err := ParseLogLevel(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, level)
+		})
+	}
+}
+
+// captureOutput returns what fn writes to stdout and stderr
+func captureOutput(t *testing.T, fn func()) (string, string) {
+	t.Helper()
+
+	read := func(target **os.File) func() string {
+		original := *target
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		*target = w
+
+		output := make(chan string)
+		go func() {
+			var buf bytes.Buffer
+			_, _ = io.Copy(&buf, r)
+			output <- buf.String()
+		}()
+
+		return func() string {
+			_ = w.Close()
+			*target = original
+			return <-output
+		}
+	}
+
+	stdout, stderr := read(&os.Stdout), read(&os.Stderr)
+	fn()
+	return stdout(), stderr()
+}
+
+func TestQuietAndNoEmoji(t *testing.T) {
+	t.Run("DefaultPrintsStatusToStdout", func(t *testing.T) {
+		logger := NewLogger(false)
+
+		stdout, stderr := captureOutput(t, func() {
+			logger.Printf("✅ Invoice created: %s\n", "INV-001")
+			logger.Result("INV-001")
+		})
+
+		assert.Equal(t, "✅ Invoice created: INV-001\n", stdout)
+		assert.Empty(t, stderr)
+	})
+
+	t.Run("QuietSendsStatusToStderr", func(t *testing.T) {
+		logger, err := NewLoggerWithOptions(LoggerOptions{Quiet: true})
+		require.NoError(t, err)
+
+		stdout, stderr := captureOutput(t, func() {
+			logger.Printf("✅ Invoice created: %s\n", "INV-001")
+			logger.Println("Next steps")
+			logger.Result("INV-001")
+		})
+
+		assert.Equal(t, "INV-001\n", stdout)
+		assert.Equal(t, "✅ Invoice created: INV-001\nNext steps\n", stderr)
+	})
+
+	t.Run("NoEmojiStripsDecorations", func(t *testing.T) {
+		logger, err := NewLoggerWithOptions(LoggerOptions{NoEmoji: true})
+		require.NoError(t, err)
+
+		stdout, _ := captureOutput(t, func() {
+			logger.Println("⚠️  Invoice is overdue")
+		})
+
+		assert.Equal(t, "Invoice is overdue\n", stdout)
+	})
+}
+
+func TestJSONMode(t *testing.T) {
+	logger, err := NewLoggerWithOptions(LoggerOptions{JSON: true})
+	require.NoError(t, err)
+	assert.True(t, logger.IsJSON())
+	assert.True(t, logger.IsQuiet())
+
+	stdout, stderr := captureOutput(t, func() {
+		logger.Printf("✅ Invoice created: %s\n", "INV-001")
+		logger.Result("INV-001")
+		require.NoError(t, logger.ResultJSON(map[string]string{"number": "INV-001"}))
+	})
+
+	assert.JSONEq(t, `{"number": "INV-001"}`, stdout)
+	assert.Equal(t, "Invoice created: INV-001\n", stderr)
+
+	// Outside JSON mode ResultJSON prints nothing
+	stdout, _ = captureOutput(t, func() {
+		require.NoError(t, NewLogger(false).ResultJSON(map[string]string{"number": "INV-001"}))
+	})
+	assert.Empty(t, stdout)
+}
@@ -6,6 +6,7 @@ package config
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -19,6 +20,7 @@ import (
 var (
 	ErrConfigNil             = fmt.Errorf("config cannot be nil")
 	ErrConfigValidationError = fmt.Errorf("configuration validation failed")
+	ErrConfigNotFound        = fmt.Errorf("no configuration file found")
 )
 
 // Logger interface defined at point of use (consumer-driven design)
@@ -37,6 +39,7 @@ type Validator interface {
 type ConfigService struct {
 	logger    Logger
 	validator Validator
+	overrides map[string]string // Values from command-line flags, see SetOverride
 }
 
 // NewConfigService creates a new ConfigService with injected dependencies
@@ -47,7 +50,19 @@ func NewConfigService(logger Logger, validator Validator) *ConfigService {
 	}
 }
 
-// LoadConfig loads configuration from the specified path with context support
+// SetOverride makes LoadConfig use value for the configuration key name ahead of the
+// environment and the configuration file. It carries command-line flags such as
+// --date-format into the loaded configuration, where they are validated like any other
+// setting.
+func (s *ConfigService) SetOverride(name, value string) {
+	if s.overrides == nil {
+		s.overrides = make(map[string]string)
+	}
+	s.overrides[name] = value
+}
+
+// LoadConfig loads configuration from the specified path with context support. An empty
+// path searches the default locations, see ResolvePath.
 func (s *ConfigService) LoadConfig(ctx context.Context, path string) (*Config, error) {
 	select {
 	case <-ctx.Done():
@@ -55,15 +70,29 @@ func (s *ConfigService) LoadConfig(ctx context.Context, path string) (*Config, e
 	default:
 	}
 
+	resolution := ResolvePath(path)
+	path = resolution.Path
+	s.logger.Debug("resolved configuration path", "path", path, "found", resolution.Found,
+		"tried", strings.Join(resolution.Tried, ", "))
 	s.logger.Info("loading configuration", "path", path)
 
-	// Load .env file if it exists
-	if err := s.loadEnvFile(ctx, path); err != nil {
+	// Read the .env file if it exists
+	values, err := s.readEnvFile(ctx, path)
+	if err != nil {
 		return nil, fmt.Errorf("failed to load env file from %s: %w", path, err)
 	}
 
-	// Build configuration from environment variables
-	config, err := s.buildConfigFromEnv(ctx)
+	// Environment variables override the file, which overrides the defaults
+	if err := s.checkEnvOverrides(); err != nil {
+		return nil, err
+	}
+	env := overlayEnv(values)
+	config, err := buildConfig(ctx, func(key string) string {
+		if value, ok := s.overrides[key]; ok {
+			return value
+		}
+		return env(key)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to build config from environment: %w", err)
 	}
@@ -74,6 +103,9 @@ func (s *ConfigService) LoadConfig(ctx context.Context, path string) (*Config, e
 	// Validate configuration
 	if s.validator != nil {
 		if err := s.validator.ValidateConfig(ctx, config); err != nil {
+			if !resolution.Found {
+				return nil, fmt.Errorf("%w (tried %s): %w", ErrConfigNotFound, strings.Join(resolution.Tried, ", "), err)
+			}
 			return nil, fmt.Errorf("config validation failed: %w", err)
 		}
 	}
@@ -103,93 +135,253 @@ func (s *ConfigService) ValidateConfig(ctx context.Context, config *Config) erro
 	return nil
 }
 
-// loadEnvFile loads environment variables from the specified file
-func (s *ConfigService) loadEnvFile(ctx context.Context, path string) error {
+// ValidateFile validates the configuration file holds on its own, without environment
+// overrides, and returns it
+func (s *ConfigService) ValidateFile(ctx context.Context, file *EnvFile) (*Config, error) {
+	values, err := file.Values()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := buildConfig(ctx, func(key string) string { return values[key] })
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config: %w", err)
+	}
+	s.setDefaults(config)
+
+	if err := s.ValidateConfig(ctx, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// SetValue changes a single key in the configuration file at path, or in the file
+// ResolvePath picks when path is empty. The value is checked against the key type and
+// the resulting configuration is validated before the file is rewritten, keeping its
+// comments and key order.
+func (s *ConfigService) SetValue(ctx context.Context, path, name, value string) (*Config, error) {
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return nil, ctx.Err()
 	default:
 	}
 
-	if path == "" {
-		// Default to ~/.go-invoice/.env.config
-		path = filepath.Join(getDefaultDataDir(), ".env.config")
+	key, err := LookupKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := key.ValidateValue(value); err != nil {
+		return nil, err
+	}
+
+	path = ResolvePath(path).Path
+	writer := NewWriter(path)
+	file, err := writer.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	file.Set(key.Name, value)
+
+	values, err := file.Values()
+	if err != nil {
+		return nil, err
+	}
+	// Environment variables take precedence over the file, as in LoadConfig, except for
+	// the key being changed
+	env := overlayEnv(values)
+	config, err := buildConfig(ctx, func(k string) string {
+		if k == key.Name {
+			return value
+		}
+		return env(k)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config: %w", err)
+	}
+	s.setDefaults(config)
+
+	if err := s.ValidateConfig(ctx, config); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Write(ctx, file); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("configuration value updated", "path", path, "key", key.Name)
+	return config, nil
+}
+
+// readEnvFile reads the key/value pairs of the specified file, or none when it does not exist
+func (s *ConfigService) readEnvFile(ctx context.Context, path string) (map[string]string, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
 	}
 
 	// Check if file exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		s.logger.Debug("env file not found, using system environment", "path", path)
-		return nil
+		return map[string]string{}, nil
 	}
 
-	if err := godotenv.Load(path); err != nil {
-		return fmt.Errorf("failed to load environment file: %w", err)
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load environment file: %w", err)
 	}
 
 	s.logger.Debug("loaded environment file", "path", path)
+	return values, nil
+}
+
+// checkEnvOverrides checks that every configuration key set in the process environment
+// parses as its kind, so a mistyped override is reported instead of silently replaced
+// by the default
+func (s *ConfigService) checkEnvOverrides() error {
+	for _, key := range Keys() {
+		value := os.Getenv(key.Name)
+		if value == "" {
+			continue
+		}
+		if err := key.ValidateValue(value); err != nil {
+			return fmt.Errorf("environment override: %w", err)
+		}
+		s.logger.Debug("configuration value overridden by environment", "key", key.Name)
+	}
 	return nil
 }
 
-// buildConfigFromEnv constructs a Config object from environment variables
-func (s *ConfigService) buildConfigFromEnv(ctx context.Context) (*Config, error) {
+// overlayEnv returns the configuration values with process environment variables taking
+// precedence over values
+func overlayEnv(values map[string]string) envSource {
+	return func(key string) string {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+		return values[key]
+	}
+}
+
+// buildConfig constructs a Config object from the values returned by env
+func buildConfig(ctx context.Context, env envSource) (*Config, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
 	}
 
+	// The sqlite backend keeps everything in one database file inside the default data directory
+	backend := env.getEnv("STORAGE_BACKEND", StorageBackendJSON)
+	defaultDataDir := getDefaultDataDir()
+	if backend == StorageBackendSQLite {
+		defaultDataDir = filepath.Join(defaultDataDir, "go-invoice.db")
+	}
+
 	config := &Config{
 		Business: BusinessConfig{
-			Name:         getEnv("BUSINESS_NAME", ""),
-			Address:      getEnv("BUSINESS_ADDRESS", ""),
-			Phone:        getEnv("BUSINESS_PHONE", ""),
-			Email:        getEnv("BUSINESS_EMAIL", ""),
-			TaxID:        getEnv("BUSINESS_TAX_ID", ""),
-			VATID:        getEnv("BUSINESS_VAT_ID", ""),
-			Website:      getEnv("BUSINESS_WEBSITE", ""),
-			PaymentTerms: getEnv("PAYMENT_TERMS", "Net 30"),
+			Name:         env.getEnv("BUSINESS_NAME", ""),
+			Address:      env.getEnv("BUSINESS_ADDRESS", ""),
+			Phone:        env.getEnv("BUSINESS_PHONE", ""),
+			Email:        env.getEnv("BUSINESS_EMAIL", ""),
+			TaxID:        env.getEnv("BUSINESS_TAX_ID", ""),
+			VATID:        env.getEnv("BUSINESS_VAT_ID", ""),
+			Website:      env.getEnv("BUSINESS_WEBSITE", ""),
+			Logo:         env.getEnv("BUSINESS_LOGO", ""),
+			PaymentTerms: env.getEnv("PAYMENT_TERMS", "Net 30"),
 			BankDetails: BankDetails{
-				Name:                getEnv("BANK_NAME", ""),
-				AccountNumber:       getEnv("BANK_ACCOUNT", ""),
-				RoutingNumber:       getEnv("BANK_ROUTING", ""),
-				IBAN:                getEnv("BANK_IBAN", ""),
-				SWIFT:               getEnv("BANK_SWIFT", ""),
-				PaymentInstructions: getEnv("PAYMENT_INSTRUCTIONS", ""),
-				ACHEnabled:          getEnvBool("ACH_ENABLED", false),
+				Name:                env.getEnv("BANK_NAME", ""),
+				AccountNumber:       env.getEnv("BANK_ACCOUNT", ""),
+				RoutingNumber:       env.getEnv("BANK_ROUTING", ""),
+				IBAN:                env.getEnv("BANK_IBAN", ""),
+				SWIFT:               env.getEnv("BANK_SWIFT", ""),
+				PaymentInstructions: env.getEnv("PAYMENT_INSTRUCTIONS", ""),
+				ACHEnabled:          env.getEnvBool("ACH_ENABLED", false),
 			},
 			CryptoPayments: CryptoPayments{
-				USDCAddress:     getEnv("USDC_ADDRESS", ""),
-				USDCEnabled:     getEnvBool("USDC_ENABLED", false),
-				BSVAddress:      getEnv("BSV_ADDRESS", ""),
-				BSVEnabled:      getEnvBool("BSV_ENABLED", false),
-				EtherscanAPIKey: getEnv("ETHERSCAN_API_KEY", ""),
+				USDCAddress:     env.getEnv("USDC_ADDRESS", ""),
+				USDCEnabled:     env.getEnvBool("USDC_ENABLED", false),
+				BSVAddress:      env.getEnv("BSV_ADDRESS", ""),
+				BSVEnabled:      env.getEnvBool("BSV_ENABLED", false),
+				EtherscanAPIKey: env.getEnv("ETHERSCAN_API_KEY", ""),
 			},
 		},
 		Invoice: InvoiceConfig{
-			Prefix:         getEnv("INVOICE_PREFIX", "INV"),
-			StartNumber:    getEnvInt("INVOICE_START_NUMBER", 1000),
-			Footer:         getEnv("INVOICE_FOOTER", ""),
-			Currency:       getEnv("CURRENCY", "USD"),
-			VATRate:        getEnvFloat("VAT_RATE", 0.0),
-			DefaultDueDays: getEnvInt("INVOICE_DUE_DAYS", 30),
+			Prefix:                env.getEnv("INVOICE_PREFIX", "INV"),
+			StartNumber:           env.getEnvInt("INVOICE_START_NUMBER", 1000),
+			NumberFormat:          env.getEnv("INVOICE_NUMBER_FORMAT", "{prefix}-{seq}"),
+			Footer:                env.getEnv("INVOICE_FOOTER", ""),
+			Currency:              env.getEnv("CURRENCY", "USD"),
+			VATRate:               env.getEnvFloat("VAT_RATE", 0.0),
+			TaxRounding:           env.getEnv("TAX_ROUNDING", "half-up"),
+			HourlyRoundingMinutes: env.getEnvInt("HOURLY_ROUNDING_MINUTES", 0),
+			MinimumHours:          env.getEnvFloat("MINIMUM_HOURS", 0),
+			DefaultRate:           env.getEnvFloat("DEFAULT_RATE", 0),
+			DefaultLineItemType:   env.getEnv("DEFAULT_LINE_ITEM_TYPE", "hourly"),
+			LegacyWorkItems:       env.getEnv("LEGACY_WORK_ITEMS", "migrate"),
+			DefaultDueDays:        env.getEnvInt("INVOICE_DUE_DAYS", 30),
+			ConfirmBeforeGenerate: env.getEnvBool("INVOICE_CONFIRM_BEFORE_GENERATE", false),
+			RenderStyle:           env.getEnv("INVOICE_RENDER_STYLE", "detailed"),
+			TermsAnchor:           env.getEnv("TERMS_ANCHOR", "issue"),
+			DateFormat:            env.getEnv("DATE_FORMAT", ""),
+			DefaultTemplate:       env.getEnv("INVOICE_TEMPLATE", "default"),
 		},
 		Storage: StorageConfig{
-			DataDir:        getEnv("DATA_DIR", getDefaultDataDir()),
-			BackupDir:      getEnv("BACKUP_DIR", ""),
-			RetentionDays:  getEnvInt("RETENTION_DAYS", 365),
-			AutoBackup:     getEnvBool("AUTO_BACKUP", false),
-			BackupInterval: getEnvDuration("BACKUP_INTERVAL", 24*time.Hour),
+			Backend:        backend,
+			DataDir:        env.getEnv("DATA_DIR", defaultDataDir),
+			BackupDir:      env.getEnv("BACKUP_DIR", ""),
+			RetentionDays:  env.getEnvInt("RETENTION_DAYS", 365),
+			AutoBackup:     env.getEnvBool("AUTO_BACKUP", false),
+			BackupInterval: env.getEnvDuration("BACKUP_INTERVAL", 24*time.Hour),
+			LockTimeout:    env.getEnvDuration("STORAGE_LOCK_TIMEOUT", 10*time.Second),
+			StoreDocuments: env.getEnvBool("STORE_GENERATED_DOCUMENTS", false),
+			IDGenerator:    env.getEnv("ID_GENERATOR", IDGeneratorUUID),
+		},
+		Webhook: WebhookConfig{
+			URL:    env.getEnv("WEBHOOK_URL", ""),
+			Secret: env.getEnv("WEBHOOK_SECRET", ""),
+		},
+		Email: EmailConfig{
+			SMTPHost:             env.getEnv("SMTP_HOST", ""),
+			SMTPPort:             env.getEnvInt("SMTP_PORT", 587),
+			Username:             env.getEnv("SMTP_USERNAME", ""),
+			Password:             env.getEnv("SMTP_PASSWORD", ""),
+			From:                 env.getEnv("SMTP_FROM", ""),
+			ReminderIntervalDays: env.getEnvInt("REMINDER_INTERVAL_DAYS", 7),
 		},
 	}
 
+	// Expand ~ and environment variables in the storage paths
+	var err error
+	if config.Storage.DataDir, err = ExpandPath(config.Storage.DataDir); err != nil {
+		return nil, fmt.Errorf("DATA_DIR: %w", err)
+	}
+	if config.Storage.BackupDir, err = ExpandPath(config.Storage.BackupDir); err != nil {
+		return nil, fmt.Errorf("BACKUP_DIR: %w", err)
+	}
+
 	return config, nil
 }
 
 // setDefaults sets default values for configuration
 func (s *ConfigService) setDefaults(config *Config) {
 	if config.Storage.BackupDir == "" {
-		config.Storage.BackupDir = filepath.Join(config.Storage.DataDir, "backups")
+		config.Storage.BackupDir = filepath.Join(config.Storage.Dir(), "backups")
+	}
+}
+
+// VATRateProblem describes what is wrong with a VAT rate, or returns an empty string when it
+// is valid. Rates are decimals between 0 and 1; a value between 1 and 100 was most likely
+// meant as a percentage, and the message suggests the decimal instead.
+func VATRateProblem(rate float64) string {
+	if rate >= 0 && rate <= 1 {
+		return ""
+	}
+	if rate > 1 && rate <= 100 {
+		return fmt.Sprintf("VAT rate must be a decimal between 0 and 1, e.g. 0.10 for 10%%; "+
+			"%g looks like a percentage, did you mean %g?", rate, rate/100)
 	}
+	return "VAT rate must be a decimal between 0 and 1, e.g. 0.10 for 10%"
 }
 
 // getDefaultDataDir returns the default data directory
@@ -201,17 +393,20 @@ func getDefaultDataDir() string {
 	return filepath.Join(homeDir, ".go-invoice")
 }
 
+// envSource returns the value of a configuration key, or an empty string when it is not set
+type envSource func(key string) string
+
 // Helper functions for environment variable parsing
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+func (env envSource) getEnv(key, defaultValue string) string {
+	if value := env(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
 
-func getEnvInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
+func (env envSource) getEnvInt(key string, defaultValue int) int {
+	if value := env(key); value != "" {
 		if parsed, err := strconv.Atoi(value); err == nil {
 			return parsed
 		}
@@ -219,8 +414,8 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
-func getEnvFloat(key string, defaultValue float64) float64 {
-	if value := os.Getenv(key); value != "" {
+func (env envSource) getEnvFloat(key string, defaultValue float64) float64 {
+	if value := env(key); value != "" {
 		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
 			return parsed
 		}
@@ -228,8 +423,8 @@ func getEnvFloat(key string, defaultValue float64) float64 {
 	return defaultValue
 }
 
-func getEnvBool(key string, _ bool) bool {
-	if value := os.Getenv(key); value != "" {
+func (env envSource) getEnvBool(key string, _ bool) bool {
+	if value := env(key); value != "" {
 		if parsed, err := strconv.ParseBool(value); err == nil {
 			return parsed
 		}
@@ -237,8 +432,8 @@ func getEnvBool(key string, _ bool) bool {
 	return false
 }
 
-func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
+func (env envSource) getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := env(key); value != "" {
 		if parsed, err := time.ParseDuration(value); err == nil {
 			return parsed
 		}
@@ -287,17 +482,72 @@ func (v *SimpleValidator) ValidateConfig(ctx context.Context, config *Config) er
 	if config.Invoice.StartNumber < 1 {
 		errors = append(errors, "invoice start number must be greater than 0")
 	}
+	if config.Invoice.NumberFormat != "" && strings.Count(config.Invoice.NumberFormat, "{seq") != 1 {
+		errors = append(errors, "invoice number format must contain exactly one {seq} token")
+	}
 	if config.Invoice.Currency == "" {
 		errors = append(errors, "currency is required")
 	}
-	if config.Invoice.VATRate < 0 || config.Invoice.VATRate > 1 {
-		errors = append(errors, "VAT rate must be between 0 and 1")
+	if problem := VATRateProblem(config.Invoice.VATRate); problem != "" {
+		errors = append(errors, problem)
+	}
+	switch config.Invoice.TaxRounding {
+	case "", "half-up", "half-even", "down", "per-line":
+	default:
+		errors = append(errors, "tax rounding must be 'half-up', 'half-even', 'down' or 'per-line'")
+	}
+	if minutes := config.Invoice.HourlyRoundingMinutes; minutes < 0 || minutes > 60 {
+		errors = append(errors, "hourly rounding minutes must be between 0 and 60")
+	}
+	if hours := config.Invoice.MinimumHours; hours < 0 || hours > 24 {
+		errors = append(errors, "minimum hours must be between 0 and 24")
+	}
+	if config.Invoice.DefaultRate < 0 {
+		errors = append(errors, "default rate cannot be negative")
+	}
+	switch config.Invoice.DefaultLineItemType {
+	case "", "hourly", "fixed", "quantity":
+	default:
+		errors = append(errors, "default line item type must be 'hourly', 'fixed' or 'quantity'")
+	}
+	if policy := config.Invoice.LegacyWorkItems; policy != "" && policy != "migrate" && policy != "keep" {
+		errors = append(errors, "legacy work items must be 'migrate' or 'keep'")
+	}
+	if style := config.Invoice.RenderStyle; style != "" && style != "detailed" && style != "summarized" {
+		errors = append(errors, "invoice render style must be 'detailed' or 'summarized'")
+	}
+	if anchor := config.Invoice.TermsAnchor; anchor != "" && anchor != "issue" && anchor != "eom" {
+		errors = append(errors, "terms anchor must be 'issue' or 'eom'")
+	}
+	if _, err := DateLayout(config.Invoice.DateFormat); err != nil {
+		errors = append(errors, err.Error())
 	}
 
 	// Validate storage config
 	if config.Storage.DataDir == "" {
 		errors = append(errors, "data directory is required")
 	}
+	if backend := config.Storage.Backend; backend != "" && backend != StorageBackendJSON && backend != StorageBackendSQLite {
+		errors = append(errors, "storage backend must be 'json' or 'sqlite'")
+	}
+	if _, err := ParseIDGenerator(config.Storage.IDGenerator); err != nil {
+		errors = append(errors, err.Error())
+	}
+
+	// Validate webhook config
+	if webhookURL := config.Webhook.URL; webhookURL != "" {
+		if parsed, err := url.Parse(webhookURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errors = append(errors, "webhook URL must be an absolute http or https URL")
+		}
+	}
+
+	// Validate email config
+	if config.Email.SMTPHost != "" && (config.Email.SMTPPort < 1 || config.Email.SMTPPort > 65535) {
+		errors = append(errors, "SMTP port must be between 1 and 65535")
+	}
+	if config.Email.ReminderIntervalDays < 0 {
+		errors = append(errors, "reminder interval cannot be negative")
+	}
 
 	if len(errors) > 0 {
 		return fmt.Errorf("%w: %s", ErrConfigValidationError, strings.Join(errors, "; "))
@@ -4,10 +4,12 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -176,6 +178,55 @@ CURRENCY=GBP
 	suite.Equal("GBP", config.Invoice.Currency)
 }
 
+// TestLoadConfigEnvOverlay tests that environment variables override the file
+func (suite *ConfigTestSuite) TestLoadConfigEnvOverlay() {
+	suite.clearTestEnv()
+	defer suite.clearTestEnv()
+
+	envContent := `BUSINESS_NAME=File Business
+BUSINESS_ADDRESS=789 File Road
+BUSINESS_EMAIL=file@example.com
+CURRENCY=GBP
+VAT_RATE=0.2
+`
+	envFile := filepath.Join(suite.tempDir, "overlay.env")
+	suite.Require().NoError(os.WriteFile(envFile, []byte(envContent), 0o600))
+
+	ctx := context.Background()
+	suite.Require().NoError(os.Setenv("CURRENCY", "EUR"))
+	suite.Require().NoError(os.Setenv("INVOICE_DUE_DAYS", "14"))
+
+	config, err := suite.service.LoadConfig(ctx, envFile)
+	suite.Require().NoError(err)
+	suite.Equal("EUR", config.Invoice.Currency, "environment wins over the file")
+	suite.Equal(14, config.Invoice.DefaultDueDays, "environment wins over the default")
+	suite.InDelta(0.2, config.Invoice.VATRate, 0.0001, "file wins over the default")
+	suite.Equal("File Business", config.Business.Name)
+	suite.Equal("INV", config.Invoice.Prefix, "defaults still apply")
+
+	// The file is read without being exported into the process environment
+	_, exported := os.LookupEnv("BUSINESS_NAME")
+	suite.False(exported)
+
+	// Overridden values are validated like any other
+	suite.Require().NoError(os.Setenv("VAT_RATE", "1.5"))
+	_, err = suite.service.LoadConfig(ctx, envFile)
+	suite.Require().ErrorIs(err, ErrConfigValidationError)
+	suite.Contains(err.Error(), "VAT rate must be a decimal between 0 and 1")
+
+	suite.Require().NoError(os.Setenv("VAT_RATE", "0.1"))
+	suite.Require().NoError(os.Setenv("TERMS_ANCHOR", "someday"))
+	_, err = suite.service.LoadConfig(ctx, envFile)
+	suite.Require().ErrorIs(err, ErrConfigValidationError)
+	suite.Require().NoError(os.Unsetenv("TERMS_ANCHOR"))
+
+	// A value that does not parse is reported rather than replaced by the default
+	suite.Require().NoError(os.Setenv("INVOICE_DUE_DAYS", "two weeks"))
+	_, err = suite.service.LoadConfig(ctx, envFile)
+	suite.Require().ErrorIs(err, ErrInvalidConfigValue)
+	suite.Contains(err.Error(), "INVOICE_DUE_DAYS")
+}
+
 // TestLoadConfigContextCancellation tests context cancellation
 func (suite *ConfigTestSuite) TestLoadConfigContextCancellation() {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -261,6 +312,134 @@ func (suite *ConfigTestSuite) TestValidateConfig() {
 			},
 			wantErr: true,
 		},
+		{
+			name: "InvalidRenderStyle",
+			config: &Config{
+				Business: BusinessConfig{
+					Name:         "Valid Business",
+					Address:      "123 Valid St",
+					Email:        "valid@example.com",
+					PaymentTerms: testNetThirty,
+				},
+				Invoice: InvoiceConfig{
+					Prefix:      "VB",
+					StartNumber: 1000,
+					Currency:    testCurrencyUSD,
+					RenderStyle: "compact",
+				},
+				Storage: StorageConfig{
+					DataDir: "/tmp/test",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "InvalidTaxRounding",
+			config: &Config{
+				Business: BusinessConfig{
+					Name:         "Valid Business",
+					Address:      "123 Valid St",
+					Email:        "valid@example.com",
+					PaymentTerms: testNetThirty,
+				},
+				Invoice: InvoiceConfig{
+					Prefix:      "VB",
+					StartNumber: 1000,
+					Currency:    testCurrencyUSD,
+					TaxRounding: "bankers",
+				},
+				Storage: StorageConfig{
+					DataDir: "/tmp/test",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "InvalidHourlyRounding",
+			config: &Config{
+				Business: BusinessConfig{
+					Name:         "Valid Business",
+					Address:      "123 Valid St",
+					Email:        "valid@example.com",
+					PaymentTerms: testNetThirty,
+				},
+				Invoice: InvoiceConfig{
+					Prefix:                "VB",
+					StartNumber:           1000,
+					Currency:              testCurrencyUSD,
+					HourlyRoundingMinutes: -15,
+					MinimumHours:          30,
+				},
+				Storage: StorageConfig{
+					DataDir: "/tmp/test",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "InvalidLineItemDefaults",
+			config: &Config{
+				Business: BusinessConfig{
+					Name:         "Valid Business",
+					Address:      "123 Valid St",
+					Email:        "valid@example.com",
+					PaymentTerms: testNetThirty,
+				},
+				Invoice: InvoiceConfig{
+					Prefix:              "VB",
+					StartNumber:         1000,
+					Currency:            testCurrencyUSD,
+					DefaultRate:         -125,
+					DefaultLineItemType: "daily",
+				},
+				Storage: StorageConfig{
+					DataDir: "/tmp/test",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "InvalidTermsAnchor",
+			config: &Config{
+				Business: BusinessConfig{
+					Name:         "Valid Business",
+					Address:      "123 Valid St",
+					Email:        "valid@example.com",
+					PaymentTerms: testNetThirty,
+				},
+				Invoice: InvoiceConfig{
+					Prefix:      "VB",
+					StartNumber: 1000,
+					Currency:    testCurrencyUSD,
+					TermsAnchor: "month",
+				},
+				Storage: StorageConfig{
+					DataDir: "/tmp/test",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "InvalidStorageBackend",
+			config: &Config{
+				Business: BusinessConfig{
+					Name:         "Valid Business",
+					Address:      "123 Valid St",
+					Email:        "valid@example.com",
+					PaymentTerms: testNetThirty,
+				},
+				Invoice: InvoiceConfig{
+					Prefix:      "VB",
+					StartNumber: 1000,
+					Currency:    testCurrencyUSD,
+				},
+				Storage: StorageConfig{
+					Backend: "postgres",
+					DataDir: "/tmp/test",
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -283,10 +462,10 @@ func (suite *ConfigTestSuite) TestEnvHelperFunctions() {
 		suite.Require().NoError(os.Setenv("TEST_INT", "42"))
 		defer func() { suite.Require().NoError(os.Unsetenv("TEST_INT")) }()
 
-		result := getEnvInt("TEST_INT", 10)
+		result := envSource(os.Getenv).getEnvInt("TEST_INT", 10)
 		suite.Equal(42, result)
 
-		result = getEnvInt("NONEXISTENT_INT", 10)
+		result = envSource(os.Getenv).getEnvInt("NONEXISTENT_INT", 10)
 		suite.Equal(10, result)
 	})
 
@@ -294,10 +473,10 @@ func (suite *ConfigTestSuite) TestEnvHelperFunctions() {
 		suite.Require().NoError(os.Setenv("TEST_FLOAT", "3.14"))
 		defer func() { suite.Require().NoError(os.Unsetenv("TEST_FLOAT")) }()
 
-		result := getEnvFloat("TEST_FLOAT", 1.0)
+		result := envSource(os.Getenv).getEnvFloat("TEST_FLOAT", 1.0)
 		suite.InEpsilon(3.14, result, 1e-9)
 
-		result = getEnvFloat("NONEXISTENT_FLOAT", 1.0)
+		result = envSource(os.Getenv).getEnvFloat("NONEXISTENT_FLOAT", 1.0)
 		suite.InEpsilon(1.0, result, 1e-9)
 	})
 
@@ -305,10 +484,10 @@ func (suite *ConfigTestSuite) TestEnvHelperFunctions() {
 		suite.Require().NoError(os.Setenv("TEST_BOOL", "true"))
 		defer func() { suite.Require().NoError(os.Unsetenv("TEST_BOOL")) }()
 
-		result := getEnvBool("TEST_BOOL", false)
+		result := envSource(os.Getenv).getEnvBool("TEST_BOOL", false)
 		suite.True(result)
 
-		result = getEnvBool("NONEXISTENT_BOOL", false)
+		result = envSource(os.Getenv).getEnvBool("NONEXISTENT_BOOL", false)
 		suite.False(result)
 	})
 
@@ -318,10 +497,10 @@ func (suite *ConfigTestSuite) TestEnvHelperFunctions() {
 			suite.Require().NoError(os.Unsetenv("TEST_DURATION"))
 		}()
 
-		result := getEnvDuration("TEST_DURATION", time.Hour)
+		result := envSource(os.Getenv).getEnvDuration("TEST_DURATION", time.Hour)
 		suite.Equal(5*time.Minute, result)
 
-		result = getEnvDuration("NONEXISTENT_DURATION", time.Hour)
+		result = envSource(os.Getenv).getEnvDuration("NONEXISTENT_DURATION", time.Hour)
 		suite.Equal(time.Hour, result)
 	})
 }
@@ -337,11 +516,12 @@ func (suite *ConfigTestSuite) TestDefaultDataDir() {
 func (suite *ConfigTestSuite) clearTestEnv() {
 	testEnvVars := []string{
 		"BUSINESS_NAME", "BUSINESS_ADDRESS", "BUSINESS_EMAIL", "BUSINESS_PHONE",
-		"BUSINESS_TAX_ID", "BUSINESS_VAT_ID", "BUSINESS_WEBSITE", "PAYMENT_TERMS",
+		"BUSINESS_TAX_ID", "BUSINESS_VAT_ID", "BUSINESS_WEBSITE", "BUSINESS_LOGO", "PAYMENT_TERMS",
 		"BANK_NAME", "BANK_ACCOUNT", "BANK_ROUTING", "BANK_IBAN", "BANK_SWIFT",
 		"PAYMENT_INSTRUCTIONS", "INVOICE_PREFIX", "INVOICE_START_NUMBER",
 		"INVOICE_FOOTER", "CURRENCY", "VAT_RATE", "INVOICE_DUE_DAYS",
 		"DATA_DIR", "BACKUP_DIR", "RETENTION_DAYS", "AUTO_BACKUP", "BACKUP_INTERVAL",
+		"STORAGE_LOCK_TIMEOUT", "TERMS_ANCHOR", "STORAGE_BACKEND",
 	}
 
 	for _, envVar := range testEnvVars {
@@ -404,6 +584,48 @@ func TestSimpleValidator(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "InvalidLegacyWorkItems",
+			config: &Config{
+				Business: BusinessConfig{
+					Name:         "Test Business",
+					Address:      "123 Test St",
+					Email:        "test@example.com",
+					PaymentTerms: testNetThirty,
+				},
+				Invoice: InvoiceConfig{
+					Prefix:          "TEST",
+					StartNumber:     1,
+					Currency:        testCurrencyUSD,
+					LegacyWorkItems: "merge",
+				},
+				Storage: StorageConfig{
+					DataDir: "/tmp/test",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "InvalidIDGenerator",
+			config: &Config{
+				Business: BusinessConfig{
+					Name:         "Test Business",
+					Address:      "123 Test St",
+					Email:        "test@example.com",
+					PaymentTerms: testNetThirty,
+				},
+				Invoice: InvoiceConfig{
+					Prefix:      "TEST",
+					StartNumber: 1,
+					Currency:    testCurrencyUSD,
+				},
+				Storage: StorageConfig{
+					DataDir:     "/tmp/test",
+					IDGenerator: "seeded",
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -419,3 +641,142 @@ func TestSimpleValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestSimpleValidatorWebhookURL(t *testing.T) {
+	validator := NewSimpleValidator(&TestLogger{})
+	newConfig := func(webhookURL string) *Config {
+		return &Config{
+			Business: BusinessConfig{Name: "Test Business", Address: "123 Test St", Email: "test@example.com", PaymentTerms: testNetThirty},
+			Invoice:  InvoiceConfig{Prefix: "TEST", StartNumber: 1, Currency: testCurrencyUSD},
+			Storage:  StorageConfig{DataDir: "/tmp/test"},
+			Webhook:  WebhookConfig{URL: webhookURL},
+		}
+	}
+
+	for _, webhookURL := range []string{"", "https://hooks.example.com/invoices", "http://localhost:8080/hook"} {
+		assert.NoError(t, validator.ValidateConfig(context.Background(), newConfig(webhookURL)), webhookURL)
+	}
+	for _, webhookURL := range []string{"hooks.example.com", "ftp://example.com/hook", "https://", "://bad"} {
+		err := validator.ValidateConfig(context.Background(), newConfig(webhookURL))
+		assert.ErrorIs(t, err, ErrConfigValidationError, webhookURL)
+		assert.ErrorContains(t, err, "webhook URL", webhookURL)
+	}
+}
+
+func TestSimpleValidatorEmail(t *testing.T) {
+	validator := NewSimpleValidator(&TestLogger{})
+	newConfig := func(email EmailConfig) *Config {
+		return &Config{
+			Business: BusinessConfig{Name: "Test Business", Address: "123 Test St", Email: "test@example.com", PaymentTerms: testNetThirty},
+			Invoice:  InvoiceConfig{Prefix: "TEST", StartNumber: 1, Currency: testCurrencyUSD},
+			Storage:  StorageConfig{DataDir: "/tmp/test"},
+			Email:    email,
+		}
+	}
+
+	assert.NoError(t, validator.ValidateConfig(context.Background(), newConfig(EmailConfig{})))
+	assert.NoError(t, validator.ValidateConfig(context.Background(), newConfig(EmailConfig{SMTPHost: "smtp.example.com", SMTPPort: 587, ReminderIntervalDays: 7})))
+
+	err := validator.ValidateConfig(context.Background(), newConfig(EmailConfig{SMTPHost: "smtp.example.com", SMTPPort: 0}))
+	assert.ErrorIs(t, err, ErrConfigValidationError)
+	assert.ErrorContains(t, err, "SMTP port")
+
+	err = validator.ValidateConfig(context.Background(), newConfig(EmailConfig{ReminderIntervalDays: -1}))
+	assert.ErrorIs(t, err, ErrConfigValidationError)
+	assert.ErrorContains(t, err, "reminder interval")
+}
+
+func TestVATRateProblem(t *testing.T) {
+	for _, rate := range []float64{0, 0.1, 0.255, 1} {
+		assert.Empty(t, VATRateProblem(rate), rate)
+	}
+
+	// A percentage is recognized and the decimal suggested
+	assert.Equal(t, "VAT rate must be a decimal between 0 and 1, e.g. 0.10 for 10%; 20 looks like a percentage, did you mean 0.2?",
+		VATRateProblem(20))
+	assert.Contains(t, VATRateProblem(100), "did you mean 1?")
+
+	for _, rate := range []float64{-0.1, 150} {
+		assert.Equal(t, "VAT rate must be a decimal between 0 and 1, e.g. 0.10 for 10%", VATRateProblem(rate), rate)
+	}
+}
+
+func TestStorageBackend(t *testing.T) {
+	build := func(values map[string]string) *Config {
+		config, err := buildConfig(context.Background(), func(key string) string { return values[key] })
+		require.NoError(t, err)
+		return config
+	}
+
+	config := build(nil)
+	assert.Equal(t, StorageBackendJSON, config.Storage.Backend)
+	assert.Equal(t, getDefaultDataDir(), config.Storage.Dir())
+
+	// The sqlite backend defaults to a database file in the default data directory
+	config = build(map[string]string{"STORAGE_BACKEND": StorageBackendSQLite})
+	assert.Equal(t, filepath.Join(getDefaultDataDir(), "go-invoice.db"), config.Storage.DataDir)
+	assert.Equal(t, getDefaultDataDir(), config.Storage.Dir())
+
+	config = build(map[string]string{"STORAGE_BACKEND": StorageBackendSQLite, "DATA_DIR": "/srv/invoices/books.db"})
+	assert.Equal(t, "/srv/invoices/books.db", config.Storage.DataDir)
+	assert.Equal(t, "/srv/invoices", config.Storage.Dir())
+}
+
+// TestSetValue tests changing a single value in a configuration file
+func (suite *ConfigTestSuite) TestSetValue() {
+	ctx := context.Background()
+	original := "# go-invoice Configuration\n\n# Business Information\nBUSINESS_NAME=Test Business\n" +
+		"BUSINESS_ADDRESS=123 Test St\nBUSINESS_EMAIL=test@example.com\nPAYMENT_TERMS=Net 30\n\n" +
+		"# Storage Settings\nDATA_DIR=" + suite.tempDir + "\n"
+
+	writeConfig := func(name string) string {
+		path := filepath.Join(suite.tempDir, name)
+		suite.Require().NoError(os.WriteFile(path, []byte(original), 0o644))
+		return path
+	}
+
+	suite.Run("UpdatesInPlace", func() {
+		path := writeConfig("set-in-place.env")
+
+		config, err := suite.service.SetValue(ctx, path, "payment_terms", "Net 15")
+		suite.Require().NoError(err)
+		suite.Equal("Net 15", config.Business.PaymentTerms)
+
+		data, err := os.ReadFile(path) //nolint:gosec // test file path
+		suite.Require().NoError(err)
+		suite.Equal(strings.Replace(original, "PAYMENT_TERMS=Net 30", "PAYMENT_TERMS=Net 15", 1), string(data))
+	})
+
+	suite.Run("SensitiveValueKeepsFilePrivate", func() {
+		path := writeConfig("set-sensitive.env")
+
+		_, err := suite.service.SetValue(ctx, path, "BANK_ACCOUNT", "123456789")
+		suite.Require().NoError(err)
+
+		info, err := os.Stat(path)
+		suite.Require().NoError(err)
+		suite.Equal(os.FileMode(0o600), info.Mode().Perm())
+	})
+
+	suite.Run("RejectsInvalidValues", func() {
+		path := writeConfig("set-invalid.env")
+
+		_, err := suite.service.SetValue(ctx, path, "NOT_A_KEY", "1")
+		suite.Require().ErrorIs(err, ErrUnknownConfigKey)
+
+		_, err = suite.service.SetValue(ctx, path, "INVOICE_DUE_DAYS", "soon")
+		suite.Require().ErrorIs(err, ErrInvalidConfigValue)
+
+		_, err = suite.service.SetValue(ctx, path, "VAT_RATE", "1.5")
+		suite.Require().ErrorIs(err, ErrConfigValidationError)
+
+		data, err := os.ReadFile(path) //nolint:gosec // test file path
+		suite.Require().NoError(err)
+		suite.Equal(original, string(data), "file must be unchanged after a rejected value")
+	})
+
+	suite.Run("MissingFile", func() {
+		_, err := suite.service.SetValue(ctx, filepath.Join(suite.tempDir, "missing.env"), "PAYMENT_TERMS", "Net 15")
+		suite.Require().ErrorIs(err, ErrConfigFileNotFound)
+	})
+}
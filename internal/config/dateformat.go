@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayoutISO is the ISO 8601 date layout, always accepted when parsing dates
+const DateLayoutISO = "2006-01-02"
+
+// Date format errors
+var (
+	// ErrInvalidDateFormat is returned for a DATE_FORMAT that is neither a named format nor
+	// a usable date layout
+	ErrInvalidDateFormat = errors.New("invalid date format")
+
+	// ErrInvalidDate is returned for a date that matches neither the configured layout nor
+	// ISO 8601
+	ErrInvalidDate = errors.New("invalid date")
+)
+
+// namedDateFormats maps the DATE_FORMAT names to their layouts
+var namedDateFormats = map[string]string{
+	"iso":  DateLayoutISO,
+	"us":   "01/02/2006",
+	"eu":   "02/01/2006",
+	"long": "January 2, 2006",
+}
+
+// dateFormatTokens converts the placeholder tokens of a DATE_FORMAT such as DD.MM.YYYY to
+// Go layout elements, longest first
+var dateFormatTokens = strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02")
+
+// dateFormatExamples is shown when a DATE_FORMAT is rejected
+const dateFormatExamples = "use iso, us, eu or long, a pattern such as DD.MM.YYYY, " +
+	"or a Go layout such as 2006-01-02 or Jan 2, 2006"
+
+// DateLayout returns the Go time layout for a DATE_FORMAT value: one of the names iso
+// (2006-01-02), us (01/02/2006), eu (02/01/2006) and long (January 2, 2006), a pattern of
+// YYYY, YY, MM and DD such as DD.MM.YYYY, or a Go layout. An empty format is iso.
+//
+// A layout must show the year, month and day, so that every date it formats can be read
+// back.
+func DateLayout(format string) (string, error) {
+	format = strings.TrimSpace(format)
+	if format == "" {
+		return DateLayoutISO, nil
+	}
+	if layout, ok := namedDateFormats[strings.ToLower(format)]; ok {
+		return layout, nil
+	}
+
+	layout := dateFormatTokens.Replace(format)
+	reference := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+	parsed, err := time.Parse(layout, reference.Format(layout))
+	if err != nil || !parsed.Equal(reference) {
+		return "", fmt.Errorf("%w %q: %s", ErrInvalidDateFormat, format, dateFormatExamples)
+	}
+	return layout, nil
+}
+
+// ParseDate parses a date given in layout or, failing that, as ISO 8601 (2006-01-02)
+func ParseDate(value, layout string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if layout != "" && layout != DateLayoutISO {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	t, err := time.Parse(DateLayoutISO, value)
+	if err != nil {
+		if layout != "" && layout != DateLayoutISO {
+			return time.Time{}, fmt.Errorf("%w %q: use %s or %s", ErrInvalidDate, value, layout, DateLayoutISO)
+		}
+		return time.Time{}, fmt.Errorf("%w %q: use %s", ErrInvalidDate, value, DateLayoutISO)
+	}
+	return t, nil
+}
+
+// DateLayout returns the layout of the configured DATE_FORMAT, or ISO 8601 when it is
+// unset or invalid
+func (c *InvoiceConfig) DateLayout() string {
+	layout, err := DateLayout(c.DateFormat)
+	if err != nil {
+		return DateLayoutISO
+	}
+	return layout
+}
+
+// ParseDate parses a date flag value in the configured DATE_FORMAT or ISO 8601
+func (c *InvoiceConfig) ParseDate(value string) (time.Time, error) {
+	return ParseDate(value, c.DateLayout())
+}
+
+// FormatDate formats t in the configured DATE_FORMAT
+func (c *InvoiceConfig) FormatDate(t time.Time) string {
+	return t.Format(c.DateLayout())
+}
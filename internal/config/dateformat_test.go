@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateLayout(t *testing.T) {
+	for format, layout := range map[string]string{
+		"":                 "2006-01-02",
+		"iso":              "2006-01-02",
+		"US":               "01/02/2006",
+		"eu":               "02/01/2006",
+		"long":             "January 2, 2006",
+		"DD.MM.YYYY":       "02.01.2006",
+		"YYYY/MM/DD":       "2006/01/02",
+		"MM-DD-YY":         "01-02-06",
+		"Jan 2, 2006":      "Jan 2, 2006",
+		"2 January 2006":   "2 January 2006",
+		" 02-Jan-2006 ":    "02-Jan-2006",
+		"Mon, 02 Jan 2006": "Mon, 02 Jan 2006",
+	} {
+		got, err := DateLayout(format)
+		require.NoError(t, err, format)
+		assert.Equal(t, layout, got, format)
+	}
+
+	// Formats that drop the year, month or day, or are not layouts at all, are rejected
+	for _, format := range []string{"01/02", "MM/YYYY", "foo", "DD.MM", "15:04"} {
+		_, err := DateLayout(format)
+		require.ErrorIs(t, err, ErrInvalidDateFormat, format)
+		assert.ErrorContains(t, err, "DD.MM.YYYY", format)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC)
+
+	// The configured layout and ISO 8601 are both accepted
+	for _, value := range []string{"04.03.2025", "2025-03-04", " 04.03.2025 "} {
+		got, err := ParseDate(value, "02.01.2006")
+		require.NoError(t, err, value)
+		assert.True(t, want.Equal(got), value)
+	}
+
+	_, err := ParseDate("03/04/2025", "02.01.2006")
+	require.ErrorIs(t, err, ErrInvalidDate)
+	assert.ErrorContains(t, err, "02.01.2006 or 2006-01-02")
+
+	_, err = ParseDate("04.03.2025", DateLayoutISO)
+	require.ErrorIs(t, err, ErrInvalidDate)
+	assert.ErrorContains(t, err, "use 2006-01-02")
+}
+
+func TestInvoiceConfigDates(t *testing.T) {
+	date := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+	eu := InvoiceConfig{DateFormat: "eu"}
+	assert.Equal(t, "01/12/2025", eu.FormatDate(date))
+	parsed, err := eu.ParseDate("01/12/2025")
+	require.NoError(t, err)
+	assert.True(t, date.Equal(parsed))
+
+	// Unset and invalid formats fall back to ISO 8601
+	assert.Equal(t, "2025-12-01", (&InvoiceConfig{}).FormatDate(date))
+	assert.Equal(t, DateLayoutISO, (&InvoiceConfig{DateFormat: "foo"}).DateLayout())
+}
+
+func TestSimpleValidatorDateFormat(t *testing.T) {
+	validator := NewSimpleValidator(&TestLogger{})
+	newConfig := func(format string) *Config {
+		return &Config{
+			Business: BusinessConfig{Name: "Test Business", Address: "123 Test St", Email: "test@example.com", PaymentTerms: testNetThirty},
+			Invoice:  InvoiceConfig{Prefix: "TEST", StartNumber: 1, Currency: testCurrencyUSD, DateFormat: format},
+			Storage:  StorageConfig{DataDir: "/tmp/test"},
+		}
+	}
+
+	for _, format := range []string{"", "us", "DD.MM.YYYY", "Jan 2, 2006"} {
+		assert.NoError(t, validator.ValidateConfig(context.Background(), newConfig(format)), format)
+	}
+	err := validator.ValidateConfig(context.Background(), newConfig("DD/MM"))
+	require.ErrorIs(t, err, ErrConfigValidationError)
+	assert.ErrorContains(t, err, `invalid date format "DD/MM"`)
+}
+
+func TestLoadConfigDateFormatOverride(t *testing.T) {
+	t.Setenv("DATE_FORMAT", "")
+	require.NoError(t, os.Unsetenv("DATE_FORMAT"))
+
+	path := filepath.Join(t.TempDir(), ".env.config")
+	require.NoError(t, os.WriteFile(path, []byte("BUSINESS_NAME=Test Business\n"+
+		"BUSINESS_ADDRESS=123 Test St\nBUSINESS_EMAIL=test@example.com\nDATE_FORMAT=us\n"), 0o600))
+
+	logger := &TestLogger{}
+	service := NewConfigService(logger, NewSimpleValidator(logger))
+
+	config, err := service.LoadConfig(context.Background(), path)
+	require.NoError(t, err)
+	assert.Equal(t, "us", config.Invoice.DateFormat)
+
+	// A flag value wins over the file and is validated like it
+	service.SetOverride("DATE_FORMAT", "DD.MM.YYYY")
+	config, err = service.LoadConfig(context.Background(), path)
+	require.NoError(t, err)
+	assert.Equal(t, "02.01.2006", config.Invoice.DateLayout())
+
+	service.SetOverride("DATE_FORMAT", "MM/YYYY")
+	_, err = service.LoadConfig(context.Background(), path)
+	require.ErrorIs(t, err, ErrConfigValidationError)
+	assert.ErrorContains(t, err, `invalid date format "MM/YYYY": use iso, us, eu or long`)
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidIDGenerator is returned for an ID_GENERATOR value that names no ID generator
+var ErrInvalidIDGenerator = errors.New("invalid ID generator")
+
+// ID generators selectable with ID_GENERATOR
+const (
+	IDGeneratorUUID       = "uuid"
+	IDGeneratorSequential = "sequential"
+	IDGeneratorSeeded     = "seeded"
+)
+
+// idGeneratorExamples is shown when an ID_GENERATOR is rejected
+const idGeneratorExamples = "use uuid, sequential, sequential:<prefix> or seeded:<seed>"
+
+// IDGeneratorSpec is a parsed ID_GENERATOR value
+type IDGeneratorSpec struct {
+	Kind   string // IDGeneratorUUID, IDGeneratorSequential or IDGeneratorSeeded
+	Prefix string // With sequential, the prefix of the IDs, e.g. "test" for "test-inv-000001"
+	Seed   uint64 // With seeded, the seed of the UUID sequence
+}
+
+// ParseIDGenerator parses an ID_GENERATOR value: uuid for random UUIDs, sequential or
+// sequential:<prefix> for IDs such as "inv-000001", or seeded:<seed> for UUIDs that repeat
+// for the same seed. An empty value is uuid.
+//
+// Sequential and seeded IDs start over on every run, so they are meant for tests and
+// reproducible exports into empty data directories only.
+func ParseIDGenerator(value string) (IDGeneratorSpec, error) {
+	kind, arg, hasArg := strings.Cut(strings.TrimSpace(value), ":")
+	switch strings.ToLower(kind) {
+	case "", IDGeneratorUUID:
+		if !hasArg {
+			return IDGeneratorSpec{Kind: IDGeneratorUUID}, nil
+		}
+	case IDGeneratorSequential:
+		return IDGeneratorSpec{Kind: IDGeneratorSequential, Prefix: arg}, nil
+	case IDGeneratorSeeded:
+		if seed, err := strconv.ParseUint(arg, 10, 64); err == nil {
+			return IDGeneratorSpec{Kind: IDGeneratorSeeded, Seed: seed}, nil
+		}
+	}
+	return IDGeneratorSpec{}, fmt.Errorf("%w %q: %s", ErrInvalidIDGenerator, value, idGeneratorExamples)
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIDGenerator(t *testing.T) {
+	for value, want := range map[string]IDGeneratorSpec{
+		"":                {Kind: IDGeneratorUUID},
+		"uuid":            {Kind: IDGeneratorUUID},
+		" UUID ":          {Kind: IDGeneratorUUID},
+		"sequential":      {Kind: IDGeneratorSequential},
+		"sequential:test": {Kind: IDGeneratorSequential, Prefix: "test"},
+		"seeded:42":       {Kind: IDGeneratorSeeded, Seed: 42},
+	} {
+		got, err := ParseIDGenerator(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+
+	for _, value := range []string{"random", "uuid:4", "seeded", "seeded:", "seeded:-1", "seeded:abc"} {
+		_, err := ParseIDGenerator(value)
+		require.ErrorIs(t, err, ErrInvalidIDGenerator, value)
+	}
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config key errors
+var (
+	ErrUnknownConfigKey   = fmt.Errorf("unknown configuration key")
+	ErrInvalidConfigValue = fmt.Errorf("invalid configuration value")
+)
+
+// KeyKind is the type of value a configuration key holds
+type KeyKind string
+
+// Configuration key kinds
+const (
+	KindString   KeyKind = "string"
+	KindInt      KeyKind = "int"
+	KindFloat    KeyKind = "float"
+	KindBool     KeyKind = "bool"
+	KindDuration KeyKind = "duration"
+)
+
+// Configuration file sections, written as comments above their keys
+const (
+	SectionBusiness = "Business Information"
+	SectionBanking  = "Banking Information"
+	SectionCrypto   = "Crypto Payments"
+	SectionInvoice  = "Invoice Settings"
+	SectionStorage  = "Storage Settings"
+	SectionWebhook  = "Webhook Notifications"
+	SectionEmail    = "Email Reminders"
+)
+
+// Key describes a key of the .env.config file
+type Key struct {
+	Name        string
+	Kind        KeyKind
+	Section     string
+	Description string
+	Mask        Masking // How the value is hidden when displayed
+}
+
+// Keys returns every configuration key in the order setup writes them
+func Keys() []Key {
+	return []Key{
+		{Name: "BUSINESS_NAME", Kind: KindString, Section: SectionBusiness, Description: "Business name"},
+		{Name: "BUSINESS_EMAIL", Kind: KindString, Section: SectionBusiness, Description: "Business email"},
+		{Name: "BUSINESS_ADDRESS", Kind: KindString, Section: SectionBusiness, Description: "Business address (use \\n for line breaks)"},
+		{Name: "BUSINESS_PHONE", Kind: KindString, Section: SectionBusiness, Description: "Business phone"},
+		{Name: "BUSINESS_WEBSITE", Kind: KindString, Section: SectionBusiness, Description: "Business website"},
+		{Name: "BUSINESS_LOGO", Kind: KindString, Section: SectionBusiness, Description: "Logo for the invoice header: an image file path or an http(s) URL"},
+		{Name: "BUSINESS_TAX_ID", Kind: KindString, Section: SectionBusiness, Description: "Tax ID"},
+		{Name: "BUSINESS_VAT_ID", Kind: KindString, Section: SectionBusiness, Description: "VAT ID"},
+		{Name: "PAYMENT_TERMS", Kind: KindString, Section: SectionBusiness, Description: "Payment terms, e.g. Net 30"},
+		{Name: "BANK_NAME", Kind: KindString, Section: SectionBanking, Description: "Bank name"},
+		{Name: "BANK_ACCOUNT", Kind: KindString, Section: SectionBanking, Description: "Bank account number", Mask: MaskPartial},
+		{Name: "BANK_ROUTING", Kind: KindString, Section: SectionBanking, Description: "Bank routing number", Mask: MaskPartial},
+		{Name: "BANK_IBAN", Kind: KindString, Section: SectionBanking, Description: "IBAN", Mask: MaskPartial},
+		{Name: "BANK_SWIFT", Kind: KindString, Section: SectionBanking, Description: "SWIFT code"},
+		{Name: "PAYMENT_INSTRUCTIONS", Kind: KindString, Section: SectionBanking, Description: "Payment instructions"},
+		{Name: "ACH_ENABLED", Kind: KindBool, Section: SectionBanking, Description: "Accept ACH payments"},
+		{Name: "USDC_ADDRESS", Kind: KindString, Section: SectionCrypto, Description: "USDC payment address"},
+		{Name: "USDC_ENABLED", Kind: KindBool, Section: SectionCrypto, Description: "Accept USDC payments"},
+		{Name: "BSV_ADDRESS", Kind: KindString, Section: SectionCrypto, Description: "BSV payment address"},
+		{Name: "BSV_ENABLED", Kind: KindBool, Section: SectionCrypto, Description: "Accept BSV payments"},
+		{Name: "ETHERSCAN_API_KEY", Kind: KindString, Section: SectionCrypto, Description: "Etherscan API key", Mask: MaskFull},
+		{Name: "INVOICE_PREFIX", Kind: KindString, Section: SectionInvoice, Description: "Invoice number prefix"},
+		{Name: "INVOICE_START_NUMBER", Kind: KindInt, Section: SectionInvoice, Description: "First invoice sequence number"},
+		{Name: "INVOICE_NUMBER_FORMAT", Kind: KindString, Section: SectionInvoice, Description: "Invoice number format, e.g. {prefix}-{seq}"},
+		{Name: "INVOICE_FOOTER", Kind: KindString, Section: SectionInvoice, Description: "Invoice footer text"},
+		{Name: "CURRENCY", Kind: KindString, Section: SectionInvoice, Description: "Default currency code"},
+		{Name: "VAT_RATE", Kind: KindFloat, Section: SectionInvoice, Description: "VAT/tax rate as a decimal, e.g. 0.10"},
+		{Name: "TAX_ROUNDING", Kind: KindString, Section: SectionInvoice, Description: "Tax rounding: half-up, half-even, down or per-line"},
+		{Name: "HOURLY_ROUNDING_MINUTES", Kind: KindInt, Section: SectionInvoice, Description: "Round hourly entries up to this many minutes, 0 to bill exact hours"},
+		{Name: "MINIMUM_HOURS", Kind: KindFloat, Section: SectionInvoice, Description: "Minimum hours billed per hourly entry, 0 for none"},
+		{Name: "DEFAULT_RATE", Kind: KindFloat, Section: SectionInvoice, Description: "Hourly rate used when a line item or imported row has none, 0 for none"},
+		{Name: "DEFAULT_LINE_ITEM_TYPE", Kind: KindString, Section: SectionInvoice, Description: "Line item type used when add-line-item is given no --type: hourly, fixed or quantity"},
+		{Name: "LEGACY_WORK_ITEMS", Kind: KindString, Section: SectionInvoice, Description: "Adding a line item to an invoice with only legacy work items: migrate converts them to line items first, keep leaves them"},
+		{Name: "INVOICE_DUE_DAYS", Kind: KindInt, Section: SectionInvoice, Description: "Default days until an invoice is due"},
+		{Name: "INVOICE_CONFIRM_BEFORE_GENERATE", Kind: KindBool, Section: SectionInvoice, Description: "Confirm before generating invoices"},
+		{Name: "INVOICE_RENDER_STYLE", Kind: KindString, Section: SectionInvoice, Description: "Render style: detailed or summarized"},
+		{Name: "TERMS_ANCHOR", Kind: KindString, Section: SectionInvoice, Description: "Due date anchor: issue or eom"},
+		{Name: "DATE_FORMAT", Kind: KindString, Section: SectionInvoice, Description: "Date format for output and date flags: iso, us, eu, long, a pattern such as DD.MM.YYYY, or a Go layout"},
+		{Name: "INVOICE_TEMPLATE", Kind: KindString, Section: SectionInvoice, Description: "Default invoice template"},
+		{Name: "STORAGE_BACKEND", Kind: KindString, Section: SectionStorage, Description: "Storage backend: json or sqlite"},
+		{Name: "DATA_DIR", Kind: KindString, Section: SectionStorage, Description: "Data directory, or the database file with the sqlite backend"},
+		{Name: "BACKUP_DIR", Kind: KindString, Section: SectionStorage, Description: "Backup directory"},
+		{Name: "RETENTION_DAYS", Kind: KindInt, Section: SectionStorage, Description: "Days to keep backups"},
+		{Name: "AUTO_BACKUP", Kind: KindBool, Section: SectionStorage, Description: "Back up automatically"},
+		{Name: "BACKUP_INTERVAL", Kind: KindDuration, Section: SectionStorage, Description: "Time between automatic backups, e.g. 24h"},
+		{Name: "STORAGE_LOCK_TIMEOUT", Kind: KindDuration, Section: SectionStorage, Description: "How long to wait for another process to release the data directory, e.g. 10s"},
+		{Name: "STORE_GENERATED_DOCUMENTS", Kind: KindBool, Section: SectionStorage, Description: "Keep a record of generated documents"},
+		{Name: "ID_GENERATOR", Kind: KindString, Section: SectionStorage, Description: "IDs of new records: uuid, or sequential[:prefix] or seeded:<seed> for tests and reproducible exports"},
+		{Name: "WEBHOOK_URL", Kind: KindString, Section: SectionWebhook, Description: "URL notified when an invoice changes status"},
+		{Name: "WEBHOOK_SECRET", Kind: KindString, Section: SectionWebhook, Description: "Secret signing webhook payloads", Mask: MaskFull},
+		{Name: "SMTP_HOST", Kind: KindString, Section: SectionEmail, Description: "SMTP server used to send payment reminders"},
+		{Name: "SMTP_PORT", Kind: KindInt, Section: SectionEmail, Description: "SMTP server port"},
+		{Name: "SMTP_USERNAME", Kind: KindString, Section: SectionEmail, Description: "SMTP username"},
+		{Name: "SMTP_PASSWORD", Kind: KindString, Section: SectionEmail, Description: "SMTP password", Mask: MaskFull},
+		{Name: "SMTP_FROM", Kind: KindString, Section: SectionEmail, Description: "Sender address for reminders (default: business email)"},
+		{Name: "REMINDER_INTERVAL_DAYS", Kind: KindInt, Section: SectionEmail, Description: "Days before the same invoice is reminded again"},
+	}
+}
+
+// LookupKey returns the configuration key with the given name, ignoring case
+func LookupKey(name string) (Key, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	for _, key := range Keys() {
+		if key.Name == name {
+			return key, nil
+		}
+	}
+	return Key{}, fmt.Errorf("%w: %s", ErrUnknownConfigKey, name)
+}
+
+// ValidateValue checks value parses as the key's kind. An empty value is always valid and
+// makes the key fall back to its default.
+func (k Key) ValidateValue(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	var err error
+	switch k.Kind {
+	case KindInt:
+		_, err = strconv.Atoi(value)
+	case KindFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case KindBool:
+		_, err = strconv.ParseBool(value)
+	case KindDuration:
+		_, err = time.ParseDuration(value)
+	case KindString:
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s must be a %s, got %q", ErrInvalidConfigValue, k.Name, k.Kind, value)
+	}
+	return nil
+}
+
+// Sensitive reports whether the key's value is masked when displayed
+func (k Key) Sensitive() bool {
+	return k.Mask != MaskNone
+}
+
+// MaskValue returns value for display, masked according to the key's masking rule
+func (k Key) MaskValue(value string) string {
+	return k.Mask.Apply(value)
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Path expansion errors
+var (
+	ErrUndefinedPathVariable = fmt.Errorf("path refers to an undefined environment variable")
+	ErrUserHomeNotSupported  = fmt.Errorf("~user paths are not supported, use an absolute path or $HOME")
+)
+
+// ConfigPathEnv names the environment variable that points at the configuration file
+// when --config is not given
+const ConfigPathEnv = "GO_INVOICE_CONFIG"
+
+// PathResolution describes which configuration file was chosen and how it was found
+type PathResolution struct {
+	// Path is the configuration file to use. When no file was found it is the location a
+	// new configuration file belongs in.
+	Path string
+	// Found reports whether Path exists
+	Found bool
+	// Tried lists every path that was checked, in search order
+	Tried []string
+}
+
+// ResolvePath picks the configuration file. An explicit path (from --config) wins, then
+// $GO_INVOICE_CONFIG; otherwise the first existing file of $XDG_CONFIG_HOME/go-invoice/config,
+// ~/.go-invoice/config, ~/.go-invoice/.env.config and .env.config in the working directory.
+func ResolvePath(explicit string) PathResolution {
+	if explicit != "" {
+		return newPathResolution(explicit)
+	}
+	if path := os.Getenv(ConfigPathEnv); path != "" {
+		return newPathResolution(path)
+	}
+
+	candidates := searchPaths()
+	resolution := PathResolution{Tried: candidates}
+	for _, path := range candidates {
+		if isConfigFile(path) {
+			resolution.Path = path
+			resolution.Found = true
+			break
+		}
+	}
+	if !resolution.Found {
+		// New configuration files are written where setup has always put them
+		resolution.Path = filepath.Join(getDefaultDataDir(), ".env.config")
+	}
+
+	return resolution
+}
+
+// newPathResolution resolves to a single path that was chosen explicitly
+func newPathResolution(path string) PathResolution {
+	return PathResolution{Path: path, Found: isConfigFile(path), Tried: []string{path}}
+}
+
+// searchPaths returns the configuration file locations checked when none was chosen
+// explicitly, in search order
+func searchPaths() []string {
+	var paths []string
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		paths = append(paths, filepath.Join(xdg, "go-invoice", "config"))
+	}
+
+	home := getDefaultDataDir()
+	return append(paths,
+		filepath.Join(home, "config"),
+		filepath.Join(home, ".env.config"),
+		".env.config",
+	)
+}
+
+// isConfigFile reports whether path is an existing regular file. ~/.go-invoice/config
+// may be the directory created by "config setup-claude", which is not a configuration file.
+func isConfigFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// ExpandPath expands a leading ~ to the current user's home directory and $VAR or ${VAR}
+// to the value of the environment variable, so hand-edited paths such as ~/.go-invoice or
+// $HOME/invoices work. Another user's home (~alice) is not looked up and is an error, as
+// is a variable that is not set, rather than silently producing a different path.
+func ExpandPath(path string) (string, error) {
+	var undefined []string
+	expanded := os.Expand(path, func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			undefined = append(undefined, "$"+name)
+		}
+		return value
+	})
+	if len(undefined) > 0 {
+		return "", fmt.Errorf("%w: %s in %q", ErrUndefinedPathVariable, strings.Join(undefined, ", "), path)
+	}
+
+	if expanded != "~" && !strings.HasPrefix(expanded, "~/") && !strings.HasPrefix(expanded, "~"+string(filepath.Separator)) {
+		if strings.HasPrefix(expanded, "~") {
+			return "", fmt.Errorf("%w: %q", ErrUserHomeNotSupported, path)
+		}
+		return expanded, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to expand ~ in %q: %w", path, err)
+	}
+	return filepath.Join(home, expanded[1:]), nil
+}
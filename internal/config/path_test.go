@@ -0,0 +1,178 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupConfigSearch points the home directory, XDG config directory and working
+// directory at fresh temporary directories and clears the explicit override
+func setupConfigSearch(t *testing.T) (home, xdg, cwd string) {
+	t.Helper()
+	home, xdg, cwd = t.TempDir(), t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Setenv(ConfigPathEnv, "")
+	t.Chdir(cwd)
+	return home, xdg, cwd
+}
+
+// writeConfigFile creates a configuration file at path, including its directory
+func writeConfigFile(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte("BUSINESS_NAME=Search Test\n"), 0o600))
+}
+
+func TestResolvePathSearchOrder(t *testing.T) {
+	home, xdg, _ := setupConfigSearch(t)
+	xdgConfig := filepath.Join(xdg, "go-invoice", "config")
+	homeConfig := filepath.Join(home, ".go-invoice", "config")
+	legacyConfig := filepath.Join(home, ".go-invoice", ".env.config")
+
+	// Nothing exists yet: every location is tried and new files go to the legacy default
+	resolution := ResolvePath("")
+	assert.False(t, resolution.Found)
+	assert.Equal(t, legacyConfig, resolution.Path)
+	assert.Equal(t, []string{xdgConfig, homeConfig, legacyConfig, ".env.config"}, resolution.Tried)
+
+	// Each location wins over the ones after it
+	writeConfigFile(t, ".env.config")
+	assert.Equal(t, ".env.config", ResolvePath("").Path)
+
+	writeConfigFile(t, legacyConfig)
+	assert.Equal(t, legacyConfig, ResolvePath("").Path)
+
+	// A config directory, as created by setup-claude, is not a configuration file
+	require.NoError(t, os.MkdirAll(homeConfig, 0o750))
+	assert.Equal(t, legacyConfig, ResolvePath("").Path)
+	require.NoError(t, os.Remove(homeConfig))
+
+	writeConfigFile(t, homeConfig)
+	assert.Equal(t, homeConfig, ResolvePath("").Path)
+
+	writeConfigFile(t, xdgConfig)
+	resolution = ResolvePath("")
+	assert.True(t, resolution.Found)
+	assert.Equal(t, xdgConfig, resolution.Path)
+}
+
+func TestResolvePathOverrides(t *testing.T) {
+	home, _, _ := setupConfigSearch(t)
+	writeConfigFile(t, filepath.Join(home, ".go-invoice", "config"))
+
+	envConfig := filepath.Join(t.TempDir(), "env.config")
+	t.Setenv(ConfigPathEnv, envConfig)
+
+	// The environment override is used even before the file exists
+	resolution := ResolvePath("")
+	assert.Equal(t, PathResolution{Path: envConfig, Found: false, Tried: []string{envConfig}}, resolution)
+
+	writeConfigFile(t, envConfig)
+	assert.True(t, ResolvePath("").Found)
+
+	// An explicit path beats the environment
+	explicit := filepath.Join(t.TempDir(), "explicit.config")
+	assert.Equal(t, []string{explicit}, ResolvePath(explicit).Tried)
+	assert.Equal(t, explicit, ResolvePath(explicit).Path)
+}
+
+func TestLoadConfigSearchesPaths(t *testing.T) {
+	home, _, _ := setupConfigSearch(t)
+	// Unset the required keys; t.Setenv restores them, including anything the file exports
+	for _, key := range []string{"BUSINESS_NAME", "BUSINESS_ADDRESS", "BUSINESS_EMAIL"} {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+	logger := &TestLogger{}
+	service := NewConfigService(logger, NewSimpleValidator(logger))
+
+	// Without a file or environment there is nothing to load, and the error says where it looked
+	_, err := service.LoadConfig(context.Background(), "")
+	require.ErrorIs(t, err, ErrConfigNotFound)
+	assert.Contains(t, err.Error(), filepath.Join(home, ".go-invoice", "config"))
+	assert.Contains(t, err.Error(), ".env.config")
+	assert.Contains(t, logger.messages, "resolved configuration path")
+
+	path := filepath.Join(home, ".go-invoice", "config")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte("BUSINESS_NAME=Home Business\n"+
+		"BUSINESS_ADDRESS=1 Home Road\nBUSINESS_EMAIL=home@example.com\n"), 0o600))
+
+	config, err := service.LoadConfig(context.Background(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "Home Business", config.Business.Name)
+}
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("INVOICE_ROOT", "/srv/invoices")
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"", ""},
+		{"/var/lib/go-invoice", "/var/lib/go-invoice"},
+		{"relative/data", "relative/data"},
+		{"~", home},
+		{"~/.go-invoice", filepath.Join(home, ".go-invoice")},
+		{"$HOME/data", filepath.Join(home, "data")},
+		{"${INVOICE_ROOT}/2025", "/srv/invoices/2025"},
+		{"$INVOICE_ROOT/~backup", "/srv/invoices/~backup"},
+	}
+	for _, tt := range tests {
+		got, err := ExpandPath(tt.path)
+		require.NoError(t, err, tt.path)
+		assert.Equal(t, tt.want, got, tt.path)
+	}
+}
+
+func TestExpandPathErrors(t *testing.T) {
+	require.NoError(t, os.Unsetenv("GO_INVOICE_UNDEFINED_DIR"))
+
+	_, err := ExpandPath("~alice/invoices")
+	require.ErrorIs(t, err, ErrUserHomeNotSupported)
+	assert.Contains(t, err.Error(), "~alice/invoices")
+
+	_, err = ExpandPath("$GO_INVOICE_UNDEFINED_DIR/data")
+	require.ErrorIs(t, err, ErrUndefinedPathVariable)
+	assert.Contains(t, err.Error(), "$GO_INVOICE_UNDEFINED_DIR")
+}
+
+func TestLoadConfigExpandsStoragePaths(t *testing.T) {
+	home, _, cwd := setupConfigSearch(t)
+	t.Setenv("DATA_DIR", "")
+	t.Setenv("BACKUP_DIR", "")
+	path := filepath.Join(cwd, ".env.config")
+	require.NoError(t, os.WriteFile(path, []byte("BUSINESS_NAME=Expand Test\nBUSINESS_ADDRESS=1 Road\n"+
+		"BUSINESS_EMAIL=expand@example.com\nDATA_DIR=~/invoices\nBACKUP_DIR=${HOME}/backups\n"), 0o600))
+	logger := &TestLogger{}
+	service := NewConfigService(logger, NewSimpleValidator(logger))
+
+	config, err := service.LoadConfig(context.Background(), path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "invoices"), config.Storage.DataDir)
+	assert.Equal(t, filepath.Join(home, "backups"), config.Storage.BackupDir)
+
+	// The default backup directory follows the expanded data directory
+	require.NoError(t, os.WriteFile(path, []byte("BUSINESS_NAME=Expand Test\nBUSINESS_ADDRESS=1 Road\n"+
+		"BUSINESS_EMAIL=expand@example.com\nDATA_DIR=$HOME/data\n"), 0o600))
+	config, err = service.LoadConfig(context.Background(), path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "data", "backups"), config.Storage.BackupDir)
+
+	// Single quotes keep the dotenv reader from expanding the variable to an empty string first
+	require.NoError(t, os.Unsetenv("GO_INVOICE_UNDEFINED_DIR"))
+	require.NoError(t, os.WriteFile(path, []byte("BUSINESS_NAME=Expand Test\nBUSINESS_ADDRESS=1 Road\n"+
+		"BUSINESS_EMAIL=expand@example.com\nDATA_DIR='$GO_INVOICE_UNDEFINED_DIR/data'\n"), 0o600))
+	_, err = service.LoadConfig(context.Background(), path)
+	require.ErrorIs(t, err, ErrUndefinedPathVariable)
+	assert.Contains(t, err.Error(), "DATA_DIR")
+}
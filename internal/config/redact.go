@@ -0,0 +1,52 @@
+package config
+
+import "strings"
+
+// maskedValue replaces the hidden part of a sensitive value
+const maskedValue = "****"
+
+// partialMaskMinLength is the shortest value that keeps its last four characters when
+// partially masked; shorter values would give too much of themselves away
+const partialMaskMinLength = 8
+
+// Masking says how a sensitive value is hidden when displayed
+type Masking int
+
+// Masking rules
+const (
+	MaskNone    Masking = iota // Shown as is
+	MaskPartial                // Only the last four characters shown, e.g. account numbers
+	MaskFull                   // Hidden entirely, e.g. API keys
+)
+
+// Apply returns value hidden according to the masking rule. Empty values stay empty so
+// an unset field is still recognizable.
+func (m Masking) Apply(value string) string {
+	if value == "" || m == MaskNone {
+		return value
+	}
+	if m == MaskPartial && len(value) >= partialMaskMinLength {
+		return maskedValue + value[len(value)-4:]
+	}
+	return maskedValue
+}
+
+// Redacted returns a copy of the configuration that is safe to display: bank account,
+// routing number and IBAN show only their last four characters, and API keys, the
+// webhook secret and the SMTP password are hidden
+func (c *Config) Redacted() *Config {
+	redacted := *c
+
+	bank := &redacted.Business.BankDetails
+	bank.AccountNumber = MaskPartial.Apply(strings.TrimSpace(bank.AccountNumber))
+	bank.RoutingNumber = MaskPartial.Apply(strings.TrimSpace(bank.RoutingNumber))
+	bank.IBAN = MaskPartial.Apply(strings.TrimSpace(bank.IBAN))
+
+	crypto := &redacted.Business.CryptoPayments
+	crypto.EtherscanAPIKey = MaskFull.Apply(crypto.EtherscanAPIKey)
+
+	redacted.Webhook.Secret = MaskFull.Apply(redacted.Webhook.Secret)
+	redacted.Email.Password = MaskFull.Apply(redacted.Email.Password)
+
+	return &redacted
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskingApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		mask  Masking
+		value string
+		want  string
+	}{
+		{"NoneKeepsValue", MaskNone, "123456789", "123456789"},
+		{"PartialKeepsLastFour", MaskPartial, "123456789", "****6789"},
+		{"PartialHidesShortValues", MaskPartial, "1234567", "****"},
+		{"FullHidesEverything", MaskFull, "sk_live_abcdefgh", "****"},
+		{"EmptyStaysEmpty", MaskFull, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.mask.Apply(tt.value))
+		})
+	}
+}
+
+func TestConfigRedacted(t *testing.T) {
+	cfg := &Config{
+		Business: BusinessConfig{
+			Name: "Acme",
+			BankDetails: BankDetails{
+				Name:          "First Bank",
+				AccountNumber: "000123456789",
+				RoutingNumber: "021000021",
+				IBAN:          "GB82WEST12345698765432",
+				SWIFT:         "WESTGB2L",
+			},
+			CryptoPayments: CryptoPayments{
+				USDCAddress:     "0x52908400098527886E0F7030069857D2E4169EE7",
+				EtherscanAPIKey: "ABCDEFGHIJKLMNOP",
+			},
+		},
+		Webhook: WebhookConfig{URL: "https://hooks.example.com/invoices", Secret: "whsec-123456789"},
+		Email:   EmailConfig{SMTPHost: "smtp.example.com", Username: "billing", Password: "hunter22"},
+	}
+
+	redacted := cfg.Redacted()
+
+	assert.Equal(t, "****6789", redacted.Business.BankDetails.AccountNumber)
+	assert.Equal(t, "****0021", redacted.Business.BankDetails.RoutingNumber)
+	assert.Equal(t, "****5432", redacted.Business.BankDetails.IBAN)
+	assert.Equal(t, "****", redacted.Business.CryptoPayments.EtherscanAPIKey)
+	assert.Equal(t, "****", redacted.Webhook.Secret)
+	assert.Equal(t, "****", redacted.Email.Password)
+
+	// Non-sensitive fields are kept
+	assert.Equal(t, "Acme", redacted.Business.Name)
+	assert.Equal(t, "First Bank", redacted.Business.BankDetails.Name)
+	assert.Equal(t, "WESTGB2L", redacted.Business.BankDetails.SWIFT)
+	assert.Equal(t, cfg.Business.CryptoPayments.USDCAddress, redacted.Business.CryptoPayments.USDCAddress)
+	assert.Equal(t, cfg.Webhook.URL, redacted.Webhook.URL)
+	assert.Equal(t, "billing", redacted.Email.Username)
+
+	// The original is left untouched
+	assert.Equal(t, "000123456789", cfg.Business.BankDetails.AccountNumber)
+	assert.Equal(t, "ABCDEFGHIJKLMNOP", cfg.Business.CryptoPayments.EtherscanAPIKey)
+}
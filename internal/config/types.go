@@ -1,12 +1,17 @@
 package config
 
-import "time"
+import (
+	"path/filepath"
+	"time"
+)
 
 // Config represents the complete application configuration
 type Config struct {
 	Business BusinessConfig `json:"business" validate:"required"`
 	Invoice  InvoiceConfig  `json:"invoice" validate:"required"`
 	Storage  StorageConfig  `json:"storage" validate:"required"`
+	Webhook  WebhookConfig  `json:"webhook"`
+	Email    EmailConfig    `json:"email"`
 }
 
 // BusinessConfig contains business information for invoices
@@ -18,6 +23,7 @@ type BusinessConfig struct {
 	TaxID          string         `json:"tax_id,omitempty"`
 	VATID          string         `json:"vat_id,omitempty"`
 	Website        string         `json:"website,omitempty"`
+	Logo           string         `json:"logo,omitempty"`
 	PaymentTerms   string         `json:"payment_terms" validate:"required"`
 	BankDetails    BankDetails    `json:"bank_details,omitempty"`
 	CryptoPayments CryptoPayments `json:"crypto_payments,omitempty"`
@@ -45,21 +51,68 @@ type CryptoPayments struct {
 
 // InvoiceConfig contains invoice generation settings
 type InvoiceConfig struct {
-	Prefix         string  `json:"prefix" validate:"required"`
-	StartNumber    int     `json:"start_number" validate:"min=1"`
-	Footer         string  `json:"footer,omitempty"`
-	Currency       string  `json:"currency" validate:"required"`
-	VATRate        float64 `json:"vat_rate" validate:"min=0,max=1"`
-	DefaultDueDays int     `json:"default_due_days" validate:"min=0"`
+	Prefix                string  `json:"prefix" validate:"required"`
+	StartNumber           int     `json:"start_number" validate:"min=1"`
+	NumberFormat          string  `json:"number_format,omitempty"`
+	Footer                string  `json:"footer,omitempty"`
+	Currency              string  `json:"currency" validate:"required"`
+	VATRate               float64 `json:"vat_rate" validate:"min=0,max=1"`
+	TaxRounding           string  `json:"tax_rounding,omitempty"`
+	HourlyRoundingMinutes int     `json:"hourly_rounding_minutes,omitempty" validate:"min=0,max=60"`
+	MinimumHours          float64 `json:"minimum_hours,omitempty" validate:"min=0,max=24"`
+	DefaultRate           float64 `json:"default_rate,omitempty" validate:"min=0"`
+	DefaultLineItemType   string  `json:"default_line_item_type,omitempty"`
+	LegacyWorkItems       string  `json:"legacy_work_items,omitempty"` // "migrate" (default) or "keep"
+	DefaultDueDays        int     `json:"default_due_days" validate:"min=0"`
+	ConfirmBeforeGenerate bool    `json:"confirm_before_generate"`
+	RenderStyle           string  `json:"render_style,omitempty"`
+	TermsAnchor           string  `json:"terms_anchor,omitempty"`
+	DateFormat            string  `json:"date_format,omitempty"` // See DateLayout
+	DefaultTemplate       string  `json:"default_template,omitempty"`
 }
 
 // StorageConfig contains storage location settings
 type StorageConfig struct {
-	DataDir        string        `json:"data_dir" validate:"required"`
+	Backend        string        `json:"backend"`                      // "json" (default) or "sqlite"
+	DataDir        string        `json:"data_dir" validate:"required"` // The database file with the sqlite backend
 	BackupDir      string        `json:"backup_dir,omitempty"`
 	RetentionDays  int           `json:"retention_days" validate:"min=0"`
 	AutoBackup     bool          `json:"auto_backup"`
 	BackupInterval time.Duration `json:"backup_interval,omitempty"`
+	LockTimeout    time.Duration `json:"lock_timeout,omitempty"`
+	StoreDocuments bool          `json:"store_documents"`
+	IDGenerator    string        `json:"id_generator,omitempty"` // See ParseIDGenerator
+}
+
+// Storage backends selectable with STORAGE_BACKEND
+const (
+	StorageBackendJSON   = "json"
+	StorageBackendSQLite = "sqlite"
+)
+
+// Dir returns the directory holding the stored data, generated invoices and templates: the
+// data directory, or the directory of the database file with the sqlite backend
+func (s StorageConfig) Dir() string {
+	if s.Backend == StorageBackendSQLite {
+		return filepath.Dir(s.DataDir)
+	}
+	return s.DataDir
+}
+
+// WebhookConfig contains the optional webhook told about invoice status changes
+type WebhookConfig struct {
+	URL    string `json:"url,omitempty"`
+	Secret string `json:"secret,omitempty"` // Signs each payload with HMAC-SHA256
+}
+
+// EmailConfig contains the SMTP server used to email payment reminders
+type EmailConfig struct {
+	SMTPHost             string `json:"smtp_host,omitempty"`
+	SMTPPort             int    `json:"smtp_port,omitempty"`
+	Username             string `json:"username,omitempty"`
+	Password             string `json:"password,omitempty"`
+	From                 string `json:"from,omitempty"`                   // Sender address, defaults to the business email
+	ReminderIntervalDays int    `json:"reminder_interval_days,omitempty"` // Days to wait before reminding about the same invoice again
 }
 
 // LoadConfigRequest represents the configuration loading request.
@@ -0,0 +1,223 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// configFileMode keeps the config file private: it can hold bank account numbers and API keys
+const configFileMode = 0o600
+
+// ErrConfigFileNotFound is returned when editing a configuration file that does not exist
+var ErrConfigFileNotFound = fmt.Errorf("configuration file not found (run 'go-invoice config setup' first)")
+
+// envKeyPattern matches the key of a KEY=VALUE line
+var envKeyPattern = regexp.MustCompile(`^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_.]*)\s*[=:]`)
+
+// EnvFile is a .env.config file kept line by line, so values can be changed without
+// losing comments, blank lines or key order
+type EnvFile struct {
+	lines []envLine
+}
+
+// envLine is one line of an EnvFile; key is empty for comments and blank lines
+type envLine struct {
+	key  string
+	text string
+}
+
+// NewEnvFile creates an empty EnvFile
+func NewEnvFile() *EnvFile {
+	return &EnvFile{}
+}
+
+// ParseEnvFile parses the contents of a .env.config file
+func ParseEnvFile(data []byte) (*EnvFile, error) {
+	if _, err := godotenv.UnmarshalBytes(data); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
+	}
+
+	file := NewEnvFile()
+	text := strings.TrimSuffix(string(data), "\n")
+	if text == "" {
+		return file, nil
+	}
+	for _, line := range strings.Split(text, "\n") {
+		entry := envLine{text: line}
+		if match := envKeyPattern.FindStringSubmatch(line); match != nil {
+			entry.key = match[1]
+		}
+		file.lines = append(file.lines, entry)
+	}
+	return file, nil
+}
+
+// Comment appends a "# text" comment line
+func (f *EnvFile) Comment(text string) {
+	f.lines = append(f.lines, envLine{text: "# " + text})
+}
+
+// Blank appends an empty line
+func (f *EnvFile) Blank() {
+	f.lines = append(f.lines, envLine{})
+}
+
+// Add appends a KEY=VALUE line
+func (f *EnvFile) Add(key, value string) {
+	f.lines = append(f.lines, envLine{key: key, text: formatEnvLine(key, value)})
+}
+
+// Set changes the value of key in place. A key not yet in the file is added after the
+// last key of its section, or at the end under a new section comment.
+func (f *EnvFile) Set(key, value string) {
+	line := envLine{key: key, text: formatEnvLine(key, value)}
+
+	for i := range f.lines {
+		if f.lines[i].key == key {
+			f.lines[i] = line
+			return
+		}
+	}
+
+	section := ""
+	if k, err := LookupKey(key); err == nil {
+		section = k.Section
+	}
+	insertAt := -1
+	for i, existing := range f.lines {
+		if k, err := LookupKey(existing.key); err == nil && existing.key != "" && k.Section == section {
+			insertAt = i + 1
+		}
+	}
+	if insertAt >= 0 {
+		f.lines = append(f.lines[:insertAt], append([]envLine{line}, f.lines[insertAt:]...)...)
+		return
+	}
+
+	if len(f.lines) > 0 && f.lines[len(f.lines)-1].text != "" {
+		f.Blank()
+	}
+	if section != "" {
+		f.Comment(section)
+	}
+	f.lines = append(f.lines, line)
+}
+
+// Values returns the parsed value of every key in the file
+func (f *EnvFile) Values() (map[string]string, error) {
+	values, err := godotenv.UnmarshalBytes(f.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
+	}
+	return values, nil
+}
+
+// Bytes returns the file contents
+func (f *EnvFile) Bytes() []byte {
+	var b bytes.Buffer
+	for _, line := range f.lines {
+		b.WriteString(line.text)
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// formatEnvLine formats a KEY=VALUE line, quoting the value when godotenv would
+// otherwise read it differently
+func formatEnvLine(key, value string) string {
+	return key + "=" + quoteEnvValue(value)
+}
+
+// quoteEnvValue returns value as written in an env file. Plain values are left bare;
+// values with comments, quotes, variables or surrounding space are single quoted, or
+// double quoted with escapes when they contain a single quote or a newline.
+func quoteEnvValue(value string) string {
+	if !strings.ContainsAny(value, "#'\"$\\\n\r") && strings.TrimSpace(value) == value {
+		return value
+	}
+	if !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// Writer reads and writes a .env.config file
+type Writer struct {
+	path string
+}
+
+// NewWriter creates a Writer for the configuration file at path
+func NewWriter(path string) *Writer {
+	return &Writer{path: path}
+}
+
+// Path returns the configuration file path
+func (w *Writer) Path() string {
+	return w.path
+}
+
+// Read parses the configuration file
+func (w *Writer) Read(ctx context.Context) (*EnvFile, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	data, err := os.ReadFile(w.path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrConfigFileNotFound, w.path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+	return ParseEnvFile(data)
+}
+
+// Write replaces the configuration file with file. The new contents are written to a
+// temporary file first so a failed write never leaves a truncated config, and the file
+// is always left readable by its owner only.
+func (w *Writer) Write(ctx context.Context, file *EnvFile) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	dir := filepath.Dir(w.path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create configuration directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(w.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary configuration file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(file.Bytes()); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write configuration file: %w", err)
+	}
+	if err := tmp.Chmod(configFileMode); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to set configuration file permissions: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write configuration file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), w.path); err != nil {
+		return fmt.Errorf("failed to replace configuration file: %w", err)
+	}
+	return nil
+}
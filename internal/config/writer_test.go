@@ -0,0 +1,169 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvFileSet(t *testing.T) {
+	original := "# go-invoice Configuration\n\n# Business Information\nBUSINESS_NAME=Acme # inline comment\n" +
+		"PAYMENT_TERMS=Net 30\n\n# Storage Settings\nDATA_DIR=/data\n"
+
+	t.Run("ReplacesInPlace", func(t *testing.T) {
+		file, err := ParseEnvFile([]byte(original))
+		require.NoError(t, err)
+
+		file.Set("PAYMENT_TERMS", "Net 15")
+
+		assert.Equal(t, "# go-invoice Configuration\n\n# Business Information\nBUSINESS_NAME=Acme # inline comment\n"+
+			"PAYMENT_TERMS=Net 15\n\n# Storage Settings\nDATA_DIR=/data\n", string(file.Bytes()))
+	})
+
+	t.Run("AddsAfterSection", func(t *testing.T) {
+		file, err := ParseEnvFile([]byte(original))
+		require.NoError(t, err)
+
+		file.Set("BUSINESS_PHONE", "555-0100")
+
+		assert.Contains(t, string(file.Bytes()), "PAYMENT_TERMS=Net 30\nBUSINESS_PHONE=555-0100\n\n# Storage Settings")
+	})
+
+	t.Run("AddsNewSection", func(t *testing.T) {
+		file, err := ParseEnvFile([]byte(original))
+		require.NoError(t, err)
+
+		file.Set("BANK_ACCOUNT", "123456789")
+
+		assert.Equal(t, original+"\n# Banking Information\nBANK_ACCOUNT=123456789\n", string(file.Bytes()))
+	})
+
+	t.Run("RejectsUnparsableFile", func(t *testing.T) {
+		_, err := ParseEnvFile([]byte("BUSINESS_NAME=\"unterminated\n"))
+		require.Error(t, err)
+	})
+}
+
+func TestQuoteEnvValueRoundTrips(t *testing.T) {
+	values := []string{
+		"plain",
+		"Net 30",
+		"",
+		"Thanks # for your business",
+		"123 Main St\\nSpringfield",
+		"Bob's Consulting",
+		"line one\nline two",
+		"costs $5",
+		"  padded  ",
+		`say "hi"`,
+		"it's $5\nnow",
+	}
+
+	for _, value := range values {
+		file := NewEnvFile()
+		file.Add("INVOICE_FOOTER", value)
+
+		parsed, err := godotenv.UnmarshalBytes(file.Bytes())
+		require.NoError(t, err, "value %q", value)
+		assert.Equal(t, value, parsed["INVOICE_FOOTER"], "written as %q", file.Bytes())
+	}
+}
+
+func TestWriterWrite(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nested", ".env.config")
+	writer := NewWriter(path)
+
+	_, err := writer.Read(ctx)
+	require.ErrorIs(t, err, ErrConfigFileNotFound)
+
+	file := NewEnvFile()
+	file.Comment(SectionBanking)
+	file.Add("BANK_ACCOUNT", "123456789")
+	require.NoError(t, writer.Write(ctx, file))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	read, err := writer.Read(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, file.Bytes(), read.Bytes())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary file must be cleaned up")
+}
+
+func TestKeys(t *testing.T) {
+	t.Run("LookupIgnoresCase", func(t *testing.T) {
+		key, err := LookupKey(" vat_rate ")
+		require.NoError(t, err)
+		assert.Equal(t, "VAT_RATE", key.Name)
+		assert.Equal(t, KindFloat, key.Kind)
+
+		_, err = LookupKey("NOPE")
+		require.ErrorIs(t, err, ErrUnknownConfigKey)
+	})
+
+	t.Run("ValidateValue", func(t *testing.T) {
+		tests := []struct {
+			key     string
+			value   string
+			wantErr bool
+		}{
+			{"INVOICE_DUE_DAYS", "15", false},
+			{"INVOICE_DUE_DAYS", "fifteen", true},
+			{"VAT_RATE", "0.2", false},
+			{"VAT_RATE", "20%", true},
+			{"AUTO_BACKUP", "true", false},
+			{"AUTO_BACKUP", "maybe", true},
+			{"BACKUP_INTERVAL", "12h", false},
+			{"BACKUP_INTERVAL", "daily", true},
+			{"BUSINESS_NAME", "anything", false},
+			{"INVOICE_DUE_DAYS", "", false},
+		}
+
+		for _, tt := range tests {
+			key, err := LookupKey(tt.key)
+			require.NoError(t, err)
+
+			err = key.ValidateValue(tt.value)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidConfigValue, "%s=%s", tt.key, tt.value)
+			} else {
+				require.NoError(t, err, "%s=%s", tt.key, tt.value)
+			}
+		}
+	})
+
+	t.Run("MaskValue", func(t *testing.T) {
+		account, err := LookupKey("BANK_ACCOUNT")
+		require.NoError(t, err)
+		name, err := LookupKey("BUSINESS_NAME")
+		require.NoError(t, err)
+
+		assert.Equal(t, "****6789", account.MaskValue("123456789"))
+		assert.Equal(t, "****", account.MaskValue("123"))
+		assert.Equal(t, "Acme", name.MaskValue("Acme"))
+	})
+
+	t.Run("EveryKeyIsLoaded", func(t *testing.T) {
+		loaded := make(map[string]bool)
+		_, err := buildConfig(context.Background(), func(key string) string {
+			loaded[key] = true
+			return ""
+		})
+		require.NoError(t, err)
+
+		for _, key := range Keys() {
+			assert.True(t, loaded[key.Name], "%s is not read by buildConfig", key.Name)
+		}
+		assert.Len(t, Keys(), len(loaded))
+	})
+}
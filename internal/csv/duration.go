@@ -0,0 +1,132 @@
+package csv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Hours column formats accepted by ParseHours
+const (
+	HoursFormatAuto     = "auto"     // Decimal hours, falling back to clock or duration notation
+	HoursFormatDecimal  = "decimal"  // Decimal hours only, e.g. 1.5
+	HoursFormatClock    = "clock"    // H:MM or HH:MM:SS, e.g. 1:30 or 01:30:00
+	HoursFormatDuration = "duration" // Go-style durations, e.g. 1h30m or 90m
+)
+
+// DefaultHoursPrecision is the number of decimal places durations are rounded to
+const DefaultHoursPrecision = 2
+
+// maxDurationHours is the longest duration accepted for a single work item
+const maxDurationHours = 24
+
+// Duration parsing errors
+var (
+	ErrUnsupportedHoursFormat = fmt.Errorf("unsupported hours format (use auto, decimal, clock or duration)")
+	ErrInvalidHoursPrecision  = fmt.Errorf("hours precision must be between 0 and 2 decimal places")
+	ErrInvalidDuration        = fmt.Errorf("invalid duration")
+	ErrNegativeDuration       = fmt.Errorf("duration cannot be negative")
+	ErrDurationTooLarge       = fmt.Errorf("duration cannot exceed 24 hours")
+)
+
+// ValidHoursFormats lists the accepted hours formats
+var ValidHoursFormats = []string{HoursFormatAuto, HoursFormatDecimal, HoursFormatClock, HoursFormatDuration}
+
+// ParseHours converts an hours value to decimal hours.
+//
+// Decimal values are returned as-is. Clock (H:MM, HH:MM:SS) and Go-style (1h30m)
+// durations are converted to hours and rounded to precision decimal places.
+//
+// Parameters:
+// - value: The hours cell from the timesheet
+// - format: One of ValidHoursFormats; empty means HoursFormatAuto
+// - precision: Decimal places for converted durations, 0 to 2
+//
+// Notes:
+// - Negative durations and durations over 24 hours are rejected
+// - In auto mode values containing ":" are read as clock time, others as Go durations
+func ParseHours(value, format string, precision int) (float64, error) {
+	if precision < 0 || precision > DefaultHoursPrecision {
+		return 0, fmt.Errorf("%w, got %d", ErrInvalidHoursPrecision, precision)
+	}
+
+	value = strings.TrimSpace(value)
+
+	var duration time.Duration
+	var err error
+	switch strings.ToLower(format) {
+	case "", HoursFormatAuto:
+		if hours, parseErr := strconv.ParseFloat(value, 64); parseErr == nil {
+			return hours, nil
+		}
+		if strings.Contains(value, ":") {
+			duration, err = parseClockDuration(value)
+		} else {
+			duration, err = parseGoDuration(value)
+		}
+	case HoursFormatDecimal:
+		return strconv.ParseFloat(value, 64)
+	case HoursFormatClock:
+		duration, err = parseClockDuration(value)
+	case HoursFormatDuration:
+		duration, err = parseGoDuration(value)
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedHoursFormat, format)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if duration < 0 {
+		return 0, fmt.Errorf("%w: %s", ErrNegativeDuration, value)
+	}
+	if duration > maxDurationHours*time.Hour {
+		return 0, fmt.Errorf("%w: %s", ErrDurationTooLarge, value)
+	}
+
+	scale := math.Pow10(precision)
+	return math.Round(duration.Hours()*scale) / scale, nil
+}
+
+// parseClockDuration parses H:MM or HH:MM:SS, with minutes and seconds as two digits
+func parseClockDuration(value string) (time.Duration, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("%w: %s (expected H:MM or HH:MM:SS)", ErrInvalidDuration, value)
+	}
+
+	negative := strings.HasPrefix(parts[0], "-")
+	hours, err := strconv.Atoi(strings.TrimPrefix(parts[0], "-"))
+	if err != nil || hours < 0 {
+		return 0, fmt.Errorf("%w: %s (expected H:MM or HH:MM:SS)", ErrInvalidDuration, value)
+	}
+	if hours > maxDurationHours {
+		return 0, fmt.Errorf("%w: %s", ErrDurationTooLarge, value)
+	}
+
+	duration := time.Duration(hours) * time.Hour
+	for i, unit := range []time.Duration{time.Minute, time.Second}[:len(parts)-1] {
+		part := parts[i+1]
+		n, err := strconv.Atoi(part)
+		if err != nil || len(part) != 2 || n < 0 || n > 59 {
+			return 0, fmt.Errorf("%w: %s (minutes and seconds must be 00-59)", ErrInvalidDuration, value)
+		}
+		duration += time.Duration(n) * unit
+	}
+
+	if negative {
+		duration = -duration
+	}
+	return duration, nil
+}
+
+// parseGoDuration parses a Go-style duration such as 1h30m
+func parseGoDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s (expected decimal hours, H:MM or 1h30m)", ErrInvalidDuration, value)
+	}
+	return duration, nil
+}
@@ -0,0 +1,90 @@
+package csv
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHours(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		format    string
+		precision int
+		want      float64
+		wantErr   error
+	}{
+		{name: "Decimal", value: "7.25", precision: 2, want: 7.25},
+		{name: "ClockMinutes", value: "1:30", precision: 2, want: 1.5},
+		{name: "ClockSeconds", value: "01:20:00", precision: 2, want: 1.33},
+		{name: "ClockPadded", value: " 0:45 ", precision: 2, want: 0.75},
+		{name: "GoDuration", value: "1h30m", precision: 2, want: 1.5},
+		{name: "GoDurationMinutes", value: "100m", precision: 2, want: 1.67},
+		{name: "PrecisionOne", value: "1:20", precision: 1, want: 1.3},
+		{name: "PrecisionZero", value: "2h40m", precision: 0, want: 3},
+		{name: "ForcedClock", value: "2:15", format: HoursFormatClock, precision: 2, want: 2.25},
+		{name: "ForcedDuration", value: "45m", format: HoursFormatDuration, precision: 2, want: 0.75},
+		{name: "FullDay", value: "24:00", precision: 2, want: 24},
+		{name: "DecimalRejectsClock", value: "1:30", format: HoursFormatDecimal, precision: 2, wantErr: strconv.ErrSyntax},
+		{name: "ClockRejectsDuration", value: "1h30m", format: HoursFormatClock, precision: 2, wantErr: ErrInvalidDuration},
+		{name: "BadMinutes", value: "1:75", precision: 2, wantErr: ErrInvalidDuration},
+		{name: "SingleDigitMinutes", value: "1:5", precision: 2, wantErr: ErrInvalidDuration},
+		{name: "Garbage", value: "lots", precision: 2, wantErr: ErrInvalidDuration},
+		{name: "NegativeClock", value: "-0:30", precision: 2, wantErr: ErrNegativeDuration},
+		{name: "NegativeDuration", value: "-1h", precision: 2, wantErr: ErrNegativeDuration},
+		{name: "TooLargeClock", value: "36:00", precision: 2, wantErr: ErrDurationTooLarge},
+		{name: "TooLargeDuration", value: "9000h", precision: 2, wantErr: ErrDurationTooLarge},
+		{name: "JustOverADay", value: "24:00:01", precision: 2, wantErr: ErrDurationTooLarge},
+		{name: "UnknownFormat", value: "1", format: "minutes", precision: 2, wantErr: ErrUnsupportedHoursFormat},
+		{name: "PrecisionTooHigh", value: "1:30", precision: 3, wantErr: ErrInvalidHoursPrecision},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHours(tt.value, tt.format, tt.precision)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
+
+func TestParseTimesheetDurations(t *testing.T) {
+	parser := NewCSVParser(&MockValidator{}, &MockLogger{}, &MockIDGenerator{})
+	validDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+	csvData := "Date,Duration,Rate,Task\n" +
+		validDate + ",1:30,100,Planning\n" +
+		validDate + ",2h15m,100,Build\n" +
+		validDate + ",3.5,100,Review\n" +
+		validDate + ",-1:00,100,Refund\n" +
+		validDate + ",99:00,100,Marathon\n"
+
+	result, err := parser.ParseTimesheet(context.Background(), strings.NewReader(csvData), ParseOptions{ContinueOnError: true})
+	require.NoError(t, err)
+
+	require.Len(t, result.WorkItems, 3)
+	assert.InDelta(t, 1.5, result.WorkItems[0].Hours, 1e-9)
+	assert.InDelta(t, 2.25, result.WorkItems[1].Hours, 1e-9)
+	assert.InDelta(t, 225.0, result.WorkItems[1].Total, 1e-9)
+	assert.InDelta(t, 3.5, result.WorkItems[2].Hours, 1e-9)
+
+	require.Len(t, result.Errors, 2)
+	assert.Equal(t, 5, result.Errors[0].Line)
+	assert.Contains(t, result.Errors[0].Message, ErrNegativeDuration.Error())
+	assert.Equal(t, 6, result.Errors[1].Line)
+	assert.Contains(t, result.Errors[1].Message, ErrDurationTooLarge.Error())
+
+	t.Run("InvalidHoursFormat", func(t *testing.T) {
+		_, err := parser.ParseTimesheet(context.Background(), strings.NewReader(csvData), ParseOptions{HoursFormat: "minutes"})
+		require.ErrorIs(t, err, ErrUnsupportedHoursFormat)
+	})
+}
@@ -3,8 +3,10 @@ package csv
 import (
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -20,6 +22,7 @@ const (
 	fieldRate        = "rate"
 	formatStandard   = "standard"
 	formatTab        = "tab"
+	utf8BOM          = "\ufeff"
 )
 
 // CSV parsing errors
@@ -30,6 +33,7 @@ var (
 	ErrEmptyRow              = fmt.Errorf("empty row")
 	ErrNoRowsToProcess       = fmt.Errorf("no rows to process")
 	ErrRequiredFieldMissing  = fmt.Errorf("required field not found in header")
+	ErrRateMissing           = fmt.Errorf("rate is missing and no default rate is configured (set DEFAULT_RATE to use a standard rate)")
 	ErrFieldNotInHeader      = fmt.Errorf("field not found in header")
 	ErrFieldMissingInRow     = fmt.Errorf("field missing in row")
 	ErrFieldEmpty            = fmt.Errorf("field is empty")
@@ -88,14 +92,31 @@ func (p *CSVParser) ParseTimesheet(ctx context.Context, reader io.Reader, option
 
 	p.logger.Info("starting timesheet parsing", "format", options.Format)
 
+	if err := validateHoursOptions(options); err != nil {
+		return nil, err
+	}
+
 	// Create CSV reader with format-specific configuration
 	csvReader := csv.NewReader(reader)
-	p.configureReader(csvReader, options.Format)
-
-	// Read all rows
-	rows, err := csvReader.ReadAll()
-	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV data: %w", err)
+	p.configureReader(csvReader, options)
+
+	// Read all rows, remembering each row's line in the file since blank lines are skipped
+	var rows [][]string
+	var rowLines []int
+	for {
+		row, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if errors.Is(err, csv.ErrFieldCount) && isBlankRow(row) {
+			continue // A whitespace-only line (e.g. trailing the file) is not a row
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV data: %w", err)
+		}
+		line, _ := csvReader.FieldPos(0)
+		rows = append(rows, row)
+		rowLines = append(rowLines, line)
 	}
 
 	if len(rows) == 0 {
@@ -111,18 +132,29 @@ func (p *CSVParser) ParseTimesheet(ctx context.Context, reader io.Reader, option
 	// Parse data rows
 	var workItems []models.WorkItem
 	var parseErrors []ParseError
+	skippedRows := 0
 
+	// Every row has been read, so the total for progress is known up front
+	totalRows := len(rows) - dataStartRow
 	for i := dataStartRow; i < len(rows); i++ {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		default:
 		}
+		if i > dataStartRow {
+			ReportRowProgress(options, i-dataStartRow, totalRows, len(workItems), len(parseErrors))
+		}
 
-		lineNum := i + 1 // 1-based line numbers for user display
+		lineNum := rowLines[i] // 1-based line number in the file for user display
 		row := rows[i]
 
-		workItem, err := p.parseRow(ctx, row, headerMap, lineNum)
+		if options.SkipEmptyRows && isBlankRow(row) {
+			skippedRows++
+			continue
+		}
+
+		workItem, err := p.parseRow(ctx, row, headerMap, lineNum, options)
 		if err != nil {
 			parseError := ParseError{
 				Line:    lineNum,
@@ -156,9 +188,11 @@ func (p *CSVParser) ParseTimesheet(ctx context.Context, reader io.Reader, option
 		workItems = append(workItems, *workItem)
 	}
 
+	ReportRowProgress(options, totalRows, totalRows, len(workItems), len(parseErrors))
+
 	result := &ParseResult{
 		WorkItems:   workItems,
-		TotalRows:   len(rows) - dataStartRow,
+		TotalRows:   totalRows - skippedRows,
 		SuccessRows: len(workItems),
 		ErrorRows:   len(parseErrors),
 		Errors:      parseErrors,
@@ -224,7 +258,7 @@ func (p *CSVParser) ValidateFormat(ctx context.Context, reader io.Reader) error
 }
 
 // parseRow parses a single CSV row into a WorkItem
-func (p *CSVParser) parseRow(ctx context.Context, row []string, headerMap map[string]int, lineNum int) (*models.WorkItem, error) {
+func (p *CSVParser) parseRow(ctx context.Context, row []string, headerMap map[string]int, lineNum int, options ParseOptions) (*models.WorkItem, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -246,7 +280,7 @@ func (p *CSVParser) parseRow(ctx context.Context, row []string, headerMap map[st
 		return nil, err
 	}
 
-	rateStr, err := p.getFieldValue(row, headerMap, fieldRate, lineNum)
+	rate, err := p.parseRate(row, headerMap, lineNum, options)
 	if err != nil {
 		return nil, err
 	}
@@ -262,18 +296,12 @@ func (p *CSVParser) parseRow(ctx context.Context, row []string, headerMap map[st
 		return nil, fmt.Errorf("invalid date '%s': %w", dateStr, err)
 	}
 
-	// Parse hours
-	hours, err := strconv.ParseFloat(strings.TrimSpace(hoursStr), 64)
+	// Parse hours, converting durations such as 1:30 or 1h30m to decimal hours
+	hours, err := ParseHours(hoursStr, options.HoursFormat, hoursPrecision(options))
 	if err != nil {
 		return nil, fmt.Errorf("invalid hours '%s': %w", hoursStr, err)
 	}
 
-	// Parse rate
-	rate, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
-	if err != nil {
-		return nil, fmt.Errorf("invalid rate '%s': %w", rateStr, err)
-	}
-
 	// Generate ID for work item
 	id := p.idGenerator.GenerateID()
 
@@ -286,8 +314,51 @@ func (p *CSVParser) parseRow(ctx context.Context, row []string, headerMap map[st
 	return workItem, nil
 }
 
+// parseRate returns the row's rate, or the default rate when the row or file has none
+func (p *CSVParser) parseRate(row []string, headerMap map[string]int, lineNum int, options ParseOptions) (float64, error) {
+	if _, exists := headerMap[fieldRate]; !exists && options.DefaultRate > 0 {
+		return options.DefaultRate, nil
+	}
+
+	rateStr, err := p.getFieldValue(row, headerMap, fieldRate, lineNum)
+	if errors.Is(err, ErrFieldEmpty) || errors.Is(err, ErrFieldMissingInRow) {
+		if options.DefaultRate > 0 {
+			return options.DefaultRate, nil
+		}
+		return 0, fmt.Errorf("%w: %w", ErrRateMissing, err)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	rate, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate '%s': %w", rateStr, err)
+	}
+	return rate, nil
+}
+
+// validateHoursOptions checks the hours format and precision before any row is parsed
+func validateHoursOptions(options ParseOptions) error {
+	if options.HoursFormat != "" && !slices.Contains(ValidHoursFormats, strings.ToLower(options.HoursFormat)) {
+		return fmt.Errorf("%w: %s", ErrUnsupportedHoursFormat, options.HoursFormat)
+	}
+	if precision := hoursPrecision(options); precision < 0 || precision > DefaultHoursPrecision {
+		return fmt.Errorf("%w, got %d", ErrInvalidHoursPrecision, precision)
+	}
+	return nil
+}
+
+// hoursPrecision returns the decimal places durations are rounded to
+func hoursPrecision(options ParseOptions) int {
+	if options.HoursPrecision == nil {
+		return DefaultHoursPrecision
+	}
+	return *options.HoursPrecision
+}
+
 // processHeader processes the header row and returns field mapping
-func (p *CSVParser) processHeader(_ context.Context, rows [][]string, _ ParseOptions) (map[string]int, int, error) {
+func (p *CSVParser) processHeader(_ context.Context, rows [][]string, options ParseOptions) (map[string]int, int, error) {
 	if len(rows) == 0 {
 		return nil, 0, ErrNoRowsToProcess
 	}
@@ -295,18 +366,31 @@ func (p *CSVParser) processHeader(_ context.Context, rows [][]string, _ ParseOpt
 	headerRow := rows[0]
 	headerMap := make(map[string]int)
 
+	// User column mappings are matched case-insensitively against the file's headers
+	columnMap := make(map[string]string, len(options.ColumnMap))
+	for source, target := range options.ColumnMap {
+		columnMap[strings.ToLower(strings.TrimSpace(source))] = target
+	}
+
 	// Map header fields to column indices
 	for i, header := range headerRow {
+		if target, ok := columnMap[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, utf8BOM)))]; ok {
+			header = target
+		}
 		normalizedHeader := p.normalizeHeaderName(header)
 		headerMap[normalizedHeader] = i
 	}
 
-	// Validate required fields are present
+	// Validate required fields are present; the rate may come from the default rate instead
 	requiredFields := []string{fieldDate, fieldHours, fieldRate, fieldDescription}
 	for _, field := range requiredFields {
-		if _, exists := headerMap[field]; !exists {
-			return nil, 0, fmt.Errorf("%w: %s", ErrRequiredFieldMissing, field)
+		if _, exists := headerMap[field]; exists || (field == fieldRate && options.DefaultRate > 0) {
+			continue
+		}
+		if field == fieldRate {
+			return nil, 0, fmt.Errorf("%w: %s: %w", ErrRequiredFieldMissing, field, ErrRateMissing)
 		}
+		return nil, 0, fmt.Errorf("%w: %s", ErrRequiredFieldMissing, field)
 	}
 
 	p.logger.Debug("header processed", "fields", len(headerMap))
@@ -314,6 +398,16 @@ func (p *CSVParser) processHeader(_ context.Context, rows [][]string, _ ParseOpt
 	return headerMap, 1, nil // Data starts at row 1 (0-based)
 }
 
+// isBlankRow reports whether every cell in the row is empty or whitespace
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
 // getFieldValue retrieves a field value from a row using header mapping
 func (p *CSVParser) getFieldValue(row []string, headerMap map[string]int, fieldName string, _ int) (string, error) {
 	colIndex, exists := headerMap[fieldName]
@@ -335,7 +429,8 @@ func (p *CSVParser) getFieldValue(row []string, headerMap map[string]int, fieldN
 
 // normalizeHeaderName normalizes header names for consistent mapping
 func (p *CSVParser) normalizeHeaderName(header string) string {
-	normalized := strings.ToLower(strings.TrimSpace(header))
+	// Spreadsheet exports often start the file with a UTF-8 byte order mark
+	normalized := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, utf8BOM)))
 
 	// Handle common variations
 	switch normalized {
@@ -435,9 +530,9 @@ func (p *CSVParser) parseDate(dateStr string) (time.Time, error) {
 	return time.Time{}, ErrUnsupportedDateFormat
 }
 
-// configureReader configures CSV reader based on format
-func (p *CSVParser) configureReader(reader *csv.Reader, format string) {
-	switch format {
+// configureReader configures CSV reader based on format and delimiter override
+func (p *CSVParser) configureReader(reader *csv.Reader, options ParseOptions) {
+	switch options.Format {
 	case formatStandard, "rfc4180":
 		reader.Comma = ','
 	case formatTab, "tsv":
@@ -451,6 +546,10 @@ func (p *CSVParser) configureReader(reader *csv.Reader, format string) {
 		reader.Comma = ','
 	}
 
+	if options.Delimiter != 0 {
+		reader.Comma = options.Delimiter
+	}
+
 	reader.TrimLeadingSpace = true
 }
 
@@ -238,6 +238,32 @@ func (suite *CSVParserTestSuite) TestParseTimesheetContinueOnError() {
 	suite.Contains(parseError.Message, "invalid hours")
 }
 
+// TestParseTimesheetBOMAndBlankRows tests a spreadsheet export with a byte order mark,
+// upper-case headers and blank rows
+func (suite *CSVParserTestSuite) TestParseTimesheetBOMAndBlankRows() {
+	validDate := time.Now().AddDate(-1, 0, 0)
+	date1 := validDate.Format("2006-01-02")
+	date2 := validDate.AddDate(0, 0, 1).Format("2006-01-02")
+	date3 := validDate.AddDate(0, 0, 2).Format("2006-01-02")
+
+	csvData := fmt.Sprintf("\ufeffDATE,Hours,RATE,Description\n%s,8.0,100.00,Development work\n\n , , , \n%s,bad,100.00,Bug fixes\n%s,4.0,100.00,Code review\n",
+		date1, date2, date3)
+
+	options := ParseOptions{
+		Format:          formatStandard,
+		ContinueOnError: true,
+		SkipEmptyRows:   true,
+	}
+
+	result, err := suite.parser.ParseTimesheet(context.Background(), strings.NewReader(csvData), options)
+
+	suite.Require().NoError(err)
+	suite.Equal(3, result.TotalRows)
+	suite.Equal(2, result.SuccessRows)
+	suite.Require().Len(result.Errors, 1)
+	suite.Equal(5, result.Errors[0].Line) // Line numbers match the file despite the skipped blank lines
+}
+
 // TestParseTimesheetValidationError tests parsing with validation errors
 func (suite *CSVParserTestSuite) TestParseTimesheetValidationError() {
 	validDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
@@ -309,6 +335,155 @@ func (suite *CSVParserTestSuite) TestParseTimesheetDifferentFormats() {
 	}
 }
 
+// TestParseTimesheetDelimiterAndQuoting tests delimiter overrides, quoted fields and line endings
+func (suite *CSVParserTestSuite) TestParseTimesheetDelimiterAndQuoting() {
+	validDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+
+	tests := []struct {
+		name        string
+		csvData     string
+		options     ParseOptions
+		expected    int
+		description string
+	}{
+		{
+			name:        "DelimiterOverridesFormat",
+			csvData:     "Date|Hours|Rate|Description\n" + validDate + "|8.0|100.00|" + testDevWork,
+			options:     ParseOptions{Format: formatStandard, Delimiter: '|'},
+			expected:    1,
+			description: testDevWork,
+		},
+		{
+			name:        "TabDelimiterOnCSVFormat",
+			csvData:     "Date\tHours\tRate\tDescription\n" + validDate + "\t8.0\t100.00\tAPI, docs and tests",
+			options:     ParseOptions{Format: "csv", Delimiter: '\t'},
+			expected:    1,
+			description: "API, docs and tests",
+		},
+		{
+			name:        "QuotedFieldWithDelimiter",
+			csvData:     "Date,Hours,Rate,Description\n" + validDate + ",8.0,100.00,\"Design, build and \"\"ship\"\"\"",
+			options:     ParseOptions{Format: formatStandard},
+			expected:    1,
+			description: `Design, build and "ship"`,
+		},
+		{
+			name:        "QuotedFieldWithTab",
+			csvData:     "Date\tHours\tRate\tDescription\n" + validDate + "\t8.0\t100.00\t\"Review\tand merge\"",
+			options:     ParseOptions{Format: "tsv"},
+			expected:    1,
+			description: "Review\tand merge",
+		},
+		{
+			name:        "CRLFLineEndings",
+			csvData:     "Date,Hours,Rate,Description\r\n" + validDate + ",8.0,100.00," + testDevWork + "\r\n" + validDate + ",2.0,100.00,Review\r\n",
+			options:     ParseOptions{Format: formatStandard},
+			expected:    2,
+			description: testDevWork,
+		},
+		{
+			name:        "TrailingEmptyLines",
+			csvData:     "Date,Hours,Rate,Description\n" + validDate + ",8.0,100.00," + testDevWork + "\n\n   \n",
+			options:     ParseOptions{Format: formatStandard, SkipEmptyRows: true},
+			expected:    1,
+			description: testDevWork,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			result, err := suite.parser.ParseTimesheet(context.Background(), strings.NewReader(tt.csvData), tt.options)
+
+			suite.Require().NoError(err)
+			suite.Equal(tt.expected, result.TotalRows)
+			suite.Require().Len(result.WorkItems, tt.expected)
+			suite.Equal(tt.description, result.WorkItems[0].Description)
+		})
+	}
+}
+
+// TestParseTimesheetColumnMap tests mapping custom headers onto the expected fields
+func (suite *CSVParserTestSuite) TestParseTimesheetColumnMap() {
+	validDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+	csvData := "Day Worked,Billable,Price,Ticket Summary\n" + validDate + ",3.5,100.00," + testDevWork
+
+	suite.Run("MapsHeaders", func() {
+		options := ParseOptions{
+			Format: formatStandard,
+			ColumnMap: map[string]string{
+				"day worked":     "date",
+				"BILLABLE":       "Hours",
+				"price":          "hourly_rate",
+				"Ticket Summary": "description",
+			},
+		}
+
+		result, err := suite.parser.ParseTimesheet(context.Background(), strings.NewReader(csvData), options)
+
+		suite.Require().NoError(err)
+		suite.Require().Len(result.WorkItems, 1)
+		suite.InEpsilon(3.5, result.WorkItems[0].Hours, 0.001)
+		suite.InEpsilon(100.0, result.WorkItems[0].Rate, 0.001)
+		suite.Equal(testDevWork, result.WorkItems[0].Description)
+	})
+
+	suite.Run("UnmappedHeaderMissing", func() {
+		options := ParseOptions{
+			Format:    formatStandard,
+			ColumnMap: map[string]string{"day worked": "date", "billable": "hours", "price": "rate"},
+		}
+
+		_, err := suite.parser.ParseTimesheet(context.Background(), strings.NewReader(csvData), options)
+
+		suite.Require().ErrorIs(err, ErrRequiredFieldMissing)
+	})
+}
+
+// TestParseTimesheetDefaultRate tests filling in missing rates from the default rate
+func (suite *CSVParserTestSuite) TestParseTimesheetDefaultRate() {
+	validDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+	withRates := "date,hours,rate,description\n" +
+		validDate + ",2,,Planning\n" +
+		validDate + ",3,150," + testDevWork
+	withoutRates := "date,hours,description\n" + validDate + ",2,Planning"
+
+	suite.Run("EmptyRateUsesDefault", func() {
+		options := ParseOptions{Format: formatStandard, DefaultRate: 125}
+
+		result, err := suite.parser.ParseTimesheet(context.Background(), strings.NewReader(withRates), options)
+
+		suite.Require().NoError(err)
+		suite.Require().Len(result.WorkItems, 2)
+		suite.InEpsilon(125.0, result.WorkItems[0].Rate, 0.001)
+		suite.InEpsilon(250.0, result.WorkItems[0].Total, 0.001)
+		suite.InEpsilon(150.0, result.WorkItems[1].Rate, 0.001) // An explicit rate wins
+	})
+
+	suite.Run("MissingColumnUsesDefault", func() {
+		options := ParseOptions{Format: formatStandard, DefaultRate: 125}
+
+		result, err := suite.parser.ParseTimesheet(context.Background(), strings.NewReader(withoutRates), options)
+
+		suite.Require().NoError(err)
+		suite.Require().Len(result.WorkItems, 1)
+		suite.InEpsilon(125.0, result.WorkItems[0].Rate, 0.001)
+	})
+
+	suite.Run("NoDefaultRate", func() {
+		options := ParseOptions{Format: formatStandard, ContinueOnError: true}
+
+		_, err := suite.parser.ParseTimesheet(context.Background(), strings.NewReader(withoutRates), options)
+		suite.Require().ErrorIs(err, ErrRequiredFieldMissing)
+		suite.Require().ErrorIs(err, ErrRateMissing)
+
+		result, err := suite.parser.ParseTimesheet(context.Background(), strings.NewReader(withRates), options)
+		suite.Require().NoError(err)
+		suite.Len(result.WorkItems, 1)
+		suite.Require().Len(result.Errors, 1)
+		suite.Contains(result.Errors[0].Message, "DEFAULT_RATE")
+	})
+}
+
 // TestParseTimesheetHeaderVariations tests different header name variations
 func (suite *CSVParserTestSuite) TestParseTimesheetHeaderVariations() {
 	validDate := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
@@ -653,7 +828,7 @@ func TestParseRow(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
-			workItem, err := parser.parseRow(ctx, tt.row, headerMap, tt.lineNum)
+			workItem, err := parser.parseRow(ctx, tt.row, headerMap, tt.lineNum, ParseOptions{})
 
 			if tt.wantErr {
 				require.Error(t, err)
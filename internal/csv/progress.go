@@ -0,0 +1,66 @@
+package csv
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ProgressLinePrefix starts a machine-readable progress line: the prefix followed by a
+// ProgressReport encoded as JSON. The CLI writes these lines to stderr with
+// "import --progress json" and the MCP executor turns them into progress updates.
+const ProgressLinePrefix = "go-invoice-progress: "
+
+// progressOperation describes the work a parser reports progress for
+const progressOperation = "parsing rows"
+
+// ProgressReporter receives progress while a parser works through the rows of an import
+type ProgressReporter interface {
+	ReportProgress(report ProgressReport)
+}
+
+// ReportRowProgress sends the progress of processed of total rows to the reporter in
+// options, if there is one. To keep large files from flooding the reporter, updates
+// are only sent about every percent and for the last row.
+func ReportRowProgress(options ParseOptions, processed, total, successRows, errorRows int) {
+	if options.Progress == nil || total <= 0 {
+		return
+	}
+
+	step := max(1, total/100)
+	if processed != total && processed%step != 0 {
+		return
+	}
+
+	options.Progress.ReportProgress(ProgressReport{
+		Operation:     progressOperation,
+		TotalRows:     total,
+		ProcessedRows: processed,
+		SuccessRows:   successRows,
+		ErrorRows:     errorRows,
+		Percentage:    float64(processed) * 100 / float64(total),
+	})
+}
+
+// FormatProgressLine encodes report as a progress line, without the trailing newline
+func FormatProgressLine(report ProgressReport) (string, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+	return ProgressLinePrefix + string(data), nil
+}
+
+// ParseProgressLine decodes a line written by FormatProgressLine. It reports false for
+// any other line.
+func ParseProgressLine(line string) (ProgressReport, bool) {
+	payload, ok := strings.CutPrefix(strings.TrimRight(line, "\r\n"), ProgressLinePrefix)
+	if !ok {
+		return ProgressReport{}, false
+	}
+
+	var report ProgressReport
+	if err := json.Unmarshal([]byte(payload), &report); err != nil {
+		return ProgressReport{}, false
+	}
+	return report, true
+}
@@ -0,0 +1,92 @@
+package csv
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingProgress collects the progress reports it receives
+type recordingProgress struct {
+	reports []ProgressReport
+}
+
+func (r *recordingProgress) ReportProgress(report ProgressReport) {
+	r.reports = append(r.reports, report)
+}
+
+func TestReportRowProgress(t *testing.T) {
+	t.Run("NoReporter", func(_ *testing.T) {
+		ReportRowProgress(ParseOptions{}, 1, 10, 1, 0)
+	})
+
+	t.Run("SmallImportReportsEveryRow", func(t *testing.T) {
+		progress := &recordingProgress{}
+		for processed := 1; processed <= 3; processed++ {
+			ReportRowProgress(ParseOptions{Progress: progress}, processed, 3, processed, 0)
+		}
+
+		require.Len(t, progress.reports, 3)
+		assert.InDelta(t, 100.0, progress.reports[2].Percentage, 0.001)
+	})
+
+	t.Run("LargeImportIsThrottled", func(t *testing.T) {
+		progress := &recordingProgress{}
+		for processed := 1; processed <= 1055; processed++ {
+			ReportRowProgress(ParseOptions{Progress: progress}, processed, 1055, processed-1, 1)
+		}
+
+		// One report every 10 rows, plus the last row
+		require.Len(t, progress.reports, 106)
+		last := progress.reports[len(progress.reports)-1]
+		assert.Equal(t, 1055, last.ProcessedRows)
+		assert.Equal(t, 1054, last.SuccessRows)
+		assert.Equal(t, 1, last.ErrorRows)
+	})
+}
+
+func TestParseTimesheetReportsProgress(t *testing.T) {
+	date := time.Now().AddDate(-1, 0, 0).Format("2006-01-02")
+	var csvData strings.Builder
+	csvData.WriteString("Date,Hours,Rate,Description\n")
+	for i := range 5 {
+		_, _ = fmt.Fprintf(&csvData, "%s,%d,100,Work %d\n", date, i+1, i)
+	}
+
+	progress := &recordingProgress{}
+	parser := NewCSVParser(&MockValidator{}, &MockLogger{}, &MockIDGenerator{})
+	result, err := parser.ParseTimesheet(context.Background(), strings.NewReader(csvData.String()), ParseOptions{
+		Format:   formatStandard,
+		Progress: progress,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.SuccessRows)
+	require.Len(t, progress.reports, 5)
+	for i, report := range progress.reports {
+		assert.Equal(t, i+1, report.ProcessedRows)
+		assert.Equal(t, 5, report.TotalRows)
+	}
+}
+
+func TestProgressLineRoundTrip(t *testing.T) {
+	report := ProgressReport{Operation: progressOperation, TotalRows: 200, ProcessedRows: 50, SuccessRows: 49, ErrorRows: 1, Percentage: 25}
+
+	line, err := FormatProgressLine(report)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(line, ProgressLinePrefix))
+
+	parsed, ok := ParseProgressLine(line + "\n")
+	require.True(t, ok)
+	assert.Equal(t, report, parsed)
+
+	for _, other := range []string{"", "Error: something failed", ProgressLinePrefix + "{not json"} {
+		_, ok := ParseProgressLine(other)
+		assert.False(t, ok, other)
+	}
+}
@@ -8,10 +8,16 @@ import (
 
 // ParseOptions defines options for CSV parsing
 type ParseOptions struct {
-	Format          string `json:"format"`            // CSV format: "standard", "excel", "tab", etc.
-	ContinueOnError bool   `json:"continue_on_error"` // Continue parsing even if some rows fail
-	SkipEmptyRows   bool   `json:"skip_empty_rows"`   // Skip rows that are completely empty
-	DateFormat      string `json:"date_format"`       // Preferred date format for parsing
+	Format          string            `json:"format"`                    // CSV format: "standard", "excel", "tab", etc.
+	ContinueOnError bool              `json:"continue_on_error"`         // Continue parsing even if some rows fail
+	SkipEmptyRows   bool              `json:"skip_empty_rows"`           // Skip rows that are completely empty
+	DateFormat      string            `json:"date_format"`               // Preferred date format for parsing
+	Delimiter       rune              `json:"delimiter,omitempty"`       // Field delimiter overriding the format's default (0 = use format)
+	ColumnMap       map[string]string `json:"column_map,omitempty"`      // Source header to expected field, e.g. "task" -> "description"
+	HoursFormat     string            `json:"hours_format,omitempty"`    // Hours column format, one of ValidHoursFormats (empty = auto)
+	HoursPrecision  *int              `json:"hours_precision,omitempty"` // Decimal places for converted durations (nil = DefaultHoursPrecision)
+	DefaultRate     float64           `json:"default_rate,omitempty"`    // Rate for rows without one, or for files without a rate column (0 = rate required)
+	Progress        ProgressReporter  `json:"-"`                         // Receives rows processed while parsing (nil = no reporting)
 }
 
 // ParseResult represents the result of CSV parsing operation
@@ -75,6 +81,7 @@ type ImportResult struct {
 	InvoiceID      string          `json:"invoice_id"`       // ID of created/updated invoice
 	WorkItemsAdded int             `json:"work_items_added"` // Number of work items successfully added
 	TotalAmount    float64         `json:"total_amount"`     // Total amount of imported work items
+	InvoiceTotal   float64         `json:"invoice_total"`    // Invoice total after the import
 	Warnings       []ImportWarning `json:"warnings"`         // Non-fatal warnings
 	DryRun         bool            `json:"dry_run"`          // Whether this was a dry run
 }
@@ -0,0 +1,45 @@
+// Package csvout writes tabular command output as RFC 4180 CSV.
+package csvout
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrFieldCount is returned for a record whose length differs from the header's
+var ErrFieldCount = errors.New("wrong number of fields")
+
+// Write writes header and records to w as CSV. Fields containing commas, quotes or line
+// breaks are quoted with embedded quotes doubled, so every record reads back unchanged
+// with encoding/csv. Every record must have as many fields as the header.
+func Write(w io.Writer, header []string, records [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for i, record := range records {
+		if len(record) != len(header) {
+			return fmt.Errorf("%w in CSV record %d: got %d, want %d", ErrFieldCount, i+1, len(record), len(header))
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record %d: %w", i+1, err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// Marshal returns header and records encoded as by Write
+func Marshal(header []string, records [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Write(&buf, header, records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
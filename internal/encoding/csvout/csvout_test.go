@@ -0,0 +1,52 @@
+package csvout
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	header := []string{"id", "name", "notes"}
+	records := [][]string{
+		{"C1", `Smith, Jones & Co "LLC"`, "line one\nline two"},
+		{"C2", "Plain Name", ""},
+		{"C3", " leading space", `"quoted"`},
+	}
+
+	data, err := Marshal(header, records)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Smith, Jones & Co ""LLC"""`)
+
+	parsed, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, append([][]string{header}, records...), parsed)
+}
+
+func TestMarshalHeaderOnly(t *testing.T) {
+	data, err := Marshal([]string{"a", "b"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "a,b\n", string(data))
+}
+
+func TestMarshalFieldCount(t *testing.T) {
+	_, err := Marshal([]string{"a", "b"}, [][]string{{"1", "2"}, {"3"}})
+	require.ErrorIs(t, err, ErrFieldCount)
+	assert.Contains(t, err.Error(), "record 2")
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+var errWriteFailed = errors.New("write failed")
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWriteFailed }
+
+func TestWriteError(t *testing.T) {
+	err := Write(failingWriter{}, []string{"a"}, [][]string{{"1"}})
+	require.ErrorIs(t, err, errWriteFailed)
+}
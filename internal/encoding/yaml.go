@@ -0,0 +1,68 @@
+// Package encoding converts domain models to and from the text formats used for CLI output.
+package encoding
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlIndent is the number of spaces used for each YAML nesting level
+const yamlIndent = 2
+
+// MarshalYAML encodes v as YAML. Values go through encoding/json first so field names,
+// omitempty handling and time formats (RFC 3339) match the JSON representation exactly.
+func MarshalYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+
+	// JSON is valid YAML, so parsing it yields a node tree that keeps the field order
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to convert value to YAML: %w", err)
+	}
+	useBlockStyle(&doc)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(yamlIndent)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to write YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write YAML: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalYAML decodes YAML produced by MarshalYAML into v using v's JSON field names
+func UnmarshalYAML(data []byte, v interface{}) error {
+	var generic interface{}
+	if err := yaml.Unmarshal(data, &generic); err != nil {
+		return fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	jsonData, err := json.Marshal(generic)
+	if err != nil {
+		return fmt.Errorf("failed to convert YAML: %w", err)
+	}
+
+	if err := json.Unmarshal(jsonData, v); err != nil {
+		return fmt.Errorf("failed to decode YAML: %w", err)
+	}
+	return nil
+}
+
+// useBlockStyle clears the flow and quoting styles inherited from the JSON source so the
+// encoder writes block collections and only quotes scalars that need it
+func useBlockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		useBlockStyle(child)
+	}
+}
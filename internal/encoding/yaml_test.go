@@ -0,0 +1,92 @@
+package encoding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func newTestInvoice() *models.Invoice {
+	created := time.Date(2024, 3, 10, 9, 30, 0, 0, time.UTC)
+	hours, rate := 8.0, 125.0
+	usdc := "0x0000000000000000000000000000000000000001"
+
+	return &models.Invoice{
+		ID:      "inv-001",
+		Number:  "INV-001",
+		Date:    time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		DueDate: time.Date(2024, 4, 9, 0, 0, 0, 0, time.UTC),
+		Client: models.Client{
+			ID:        "client-001",
+			Name:      "Acme Corp",
+			Email:     "billing@acme.test",
+			Active:    true,
+			CreatedAt: created,
+			UpdatedAt: created,
+		},
+		WorkItems: []models.WorkItem{
+			{ID: "work-001", Date: created, Hours: 2.5, Rate: 100, Description: "Review: API # notes", Total: 250, CreatedAt: created},
+		},
+		LineItems: []models.LineItem{
+			{ID: "line-001", Type: models.LineItemTypeHourly, Date: created, Description: "true", Hours: &hours, Rate: &rate, Total: money.FromFloat(1000), CreatedAt: created},
+		},
+		Status:              models.StatusDraft,
+		Description:         "123",
+		Subtotal:            money.FromFloat(1250),
+		TaxRate:             0.1,
+		TaxAmount:           money.FromFloat(125),
+		Total:               money.FromFloat(1375),
+		USDCAddressOverride: &usdc,
+		CreatedAt:           created,
+		UpdatedAt:           created,
+		Version:             3,
+	}
+}
+
+func TestMarshalYAMLRoundTrip(t *testing.T) {
+	invoice := newTestInvoice()
+
+	data, err := MarshalYAML(invoice)
+	require.NoError(t, err)
+
+	var decoded models.Invoice
+	require.NoError(t, UnmarshalYAML(data, &decoded))
+	assert.Equal(t, *invoice, decoded)
+}
+
+func TestMarshalYAMLFormat(t *testing.T) {
+	data, err := MarshalYAML(newTestInvoice())
+	require.NoError(t, err)
+	output := string(data)
+
+	// Field names follow the JSON tags
+	assert.Contains(t, output, "due_date: ")
+	assert.Contains(t, output, "usdc_address_override: ")
+
+	// Times are RFC 3339
+	assert.Contains(t, output, `"2024-03-10T00:00:00Z"`)
+
+	// Nil pointers are omitted
+	assert.NotContains(t, output, "bsv_address_override")
+
+	// Strings that look like other types stay strings
+	assert.Contains(t, output, `description: "123"`)
+
+	// Output is valid YAML in block style
+	var generic map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(data, &generic))
+	assert.Equal(t, "INV-001", generic["number"])
+	assert.NotContains(t, output, "{")
+}
+
+func TestUnmarshalYAMLInvalid(t *testing.T) {
+	var invoice models.Invoice
+	require.Error(t, UnmarshalYAML([]byte("number: [unclosed"), &invoice))
+	require.Error(t, UnmarshalYAML([]byte("total: not-a-number"), &invoice))
+}
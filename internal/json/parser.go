@@ -125,12 +125,15 @@ func (p *JSONParser) parseSimpleFormat(data []byte) ([]WorkItemJSON, error) {
 }
 
 // convertToModelWorkItems converts JSON work items to model work items
-func (p *JSONParser) convertToModelWorkItems(jsonItems []WorkItemJSON, _ csv.ParseOptions) ([]models.WorkItem, []csv.ParseError) {
+func (p *JSONParser) convertToModelWorkItems(jsonItems []WorkItemJSON, options csv.ParseOptions) ([]models.WorkItem, []csv.ParseError) {
 	workItems := make([]models.WorkItem, 0, len(jsonItems))
 	var errors []csv.ParseError
 
 	for i, item := range jsonItems {
 		rowNum := i + 1
+		if i > 0 {
+			csv.ReportRowProgress(options, i, len(jsonItems), len(workItems), len(errors))
+		}
 
 		// Validate required fields
 		if item.Date == "" {
@@ -165,8 +168,11 @@ func (p *JSONParser) convertToModelWorkItems(jsonItems []WorkItemJSON, _ csv.Par
 			continue
 		}
 
-		// Use the rate from the JSON
+		// Use the rate from the JSON, or the default rate for items without one
 		rate := item.Rate
+		if rate == 0 {
+			rate = options.DefaultRate
+		}
 
 		// Create work item
 		workItem := models.WorkItem{
@@ -182,6 +188,7 @@ func (p *JSONParser) convertToModelWorkItems(jsonItems []WorkItemJSON, _ csv.Par
 		workItems = append(workItems, workItem)
 	}
 
+	csv.ReportRowProgress(options, len(jsonItems), len(jsonItems), len(workItems), len(errors))
 	return workItems, errors
 }
 
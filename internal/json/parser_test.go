@@ -174,6 +174,22 @@ func (suite *JSONParserTestSuite) TestParseTimesheetStructuredFormat() {
 	suite.InEpsilon(800.0, firstItem.Total, 0.001)
 }
 
+// TestParseTimesheetDefaultRate tests items without a rate use the default rate
+func (suite *JSONParserTestSuite) TestParseTimesheetDefaultRate() {
+	jsonData := `[
+		{"date": "2024-01-15", "hours": 8.0, "description": "Development work"},
+		{"date": "2024-01-16", "hours": 2.0, "rate": 150.00, "description": "Code review"}
+	]`
+
+	result, err := suite.parser.ParseTimesheet(context.Background(), strings.NewReader(jsonData), csv.ParseOptions{DefaultRate: 125})
+
+	suite.Require().NoError(err)
+	suite.Require().Len(result.WorkItems, 2)
+	suite.InEpsilon(125.0, result.WorkItems[0].Rate, 0.001)
+	suite.InEpsilon(1000.0, result.WorkItems[0].Total, 0.001)
+	suite.InEpsilon(150.0, result.WorkItems[1].Rate, 0.001)
+}
+
 // TestParseTimesheetSimpleFormat tests parsing simple array JSON format
 func (suite *JSONParserTestSuite) TestParseTimesheetSimpleFormat() {
 	jsonData := `[
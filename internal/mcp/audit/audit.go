@@ -0,0 +1,73 @@
+// Package audit records every CLI command the MCP server runs on behalf of a tool call.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// callerKey is the context key holding the caller of a tool call
+type callerKey struct{}
+
+// Event is one audited command execution
+type Event struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Tool       string    `json:"tool"`
+	Command    string    `json:"command"`
+	Args       []string  `json:"args"`
+	ArgsHash   string    `json:"argsHash"`
+	ExitCode   int       `json:"exitCode"`
+	DurationMS int64     `json:"durationMs"`
+	Caller     string    `json:"caller,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DryRun     bool      `json:"dryRun,omitempty"` // The command was resolved but not executed
+	Denied     bool      `json:"denied,omitempty"` // The command policy refused the command
+}
+
+// AuditSink stores audit events
+type AuditSink interface { //nolint:revive // named to match executor.AuditLogger
+	// Record stores one event
+	Record(ctx context.Context, event *Event) error
+}
+
+// NewEvent creates an event for a command run by tool. Secrets in args are redacted and
+// the hash is taken over the redacted arguments, so neither leaks a secret value.
+func NewEvent(ctx context.Context, tool, command string, args []string) *Event {
+	redacted := RedactArgs(args)
+	sum := sha256.Sum256([]byte(strings.Join(redacted, "\x00")))
+
+	return &Event{
+		Timestamp: time.Now().UTC(),
+		Tool:      tool,
+		Command:   command,
+		Args:      redacted,
+		ArgsHash:  hex.EncodeToString(sum[:]),
+		Caller:    CallerFromContext(ctx),
+	}
+}
+
+// Finish records the outcome of the command. err is the execution error, if the command
+// could not be run at all; its exit code is then reported as -1.
+func (e *Event) Finish(exitCode int, duration time.Duration, err error) {
+	e.ExitCode = exitCode
+	e.DurationMS = duration.Milliseconds()
+	if err != nil {
+		e.ExitCode = -1
+		e.Error = err.Error()
+	}
+}
+
+// WithCaller returns a context identifying who made the tool call, such as the
+// transport or remote address
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the caller stored by WithCaller, or an empty string
+func CallerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
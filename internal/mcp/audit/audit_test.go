@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTestExecution = errors.New("command not allowed")
+
+func TestRedactArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "PlainArgsUnchanged",
+			args: []string{"--config", "/home/me/.env.config", "invoice", "list", "--status", "paid"},
+			want: []string{"--config", "/home/me/.env.config", "invoice", "list", "--status", "paid"},
+		},
+		{
+			name: "SecretFlagValue",
+			args: []string{"--api-key", "abc123", "--client", "Acme"},
+			want: []string{"--api-key", redactedValue, "--client", "Acme"},
+		},
+		{
+			name: "SecretFlagAttachedValue",
+			args: []string{"--token=abc123"},
+			want: []string{"--token=" + redactedValue},
+		},
+		{
+			name: "SecretFlagWithoutValue",
+			args: []string{"--password", "--verbose"},
+			want: []string{"--password", "--verbose"},
+		},
+		{
+			name: "SensitiveConfigAssignment",
+			args: []string{"config", "set", "BANK_ACCOUNT=123456789"},
+			want: []string{"config", "set", "BANK_ACCOUNT=" + redactedValue},
+		},
+		{
+			name: "PlainConfigAssignment",
+			args: []string{"config", "set", "PAYMENT_TERMS=Net 15"},
+			want: []string{"config", "set", "PAYMENT_TERMS=Net 15"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RedactArgs(tt.args))
+		})
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	ctx := WithCaller(context.Background(), "stdio")
+
+	event := NewEvent(ctx, "config_set", "go-invoice", []string{"config", "set", "BANK_ACCOUNT=123456789"})
+	event.Finish(0, 250*time.Millisecond, nil)
+
+	assert.Equal(t, "config_set", event.Tool)
+	assert.Equal(t, "stdio", event.Caller)
+	assert.Equal(t, int64(250), event.DurationMS)
+	assert.NotContains(t, event.Args, "BANK_ACCOUNT=123456789")
+	assert.Len(t, event.ArgsHash, 64)
+
+	other := NewEvent(ctx, "config_set", "go-invoice", []string{"config", "set", "BANK_ACCOUNT=987654321"})
+	assert.Equal(t, event.ArgsHash, other.ArgsHash, "hash must not depend on secret values")
+
+	t.Run("FailedExecution", func(t *testing.T) {
+		failed := NewEvent(context.Background(), "invoice_list", "go-invoice", nil)
+		failed.Finish(0, 0, errTestExecution)
+
+		assert.Equal(t, -1, failed.ExitCode)
+		assert.Equal(t, errTestExecution.Error(), failed.Error)
+		assert.Empty(t, failed.Caller)
+	})
+}
+
+func TestFileSinkRecord(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+
+	sink, err := NewFileSink(path, DefaultMaxBytes, DefaultMaxBackups)
+	require.NoError(t, err)
+
+	for _, tool := range []string{"invoice_list", "client_list"} {
+		event := NewEvent(ctx, tool, "go-invoice", []string{"list"})
+		event.Finish(1, time.Second, nil)
+		require.NoError(t, sink.Record(ctx, event))
+	}
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	events := readEvents(t, path)
+	require.Len(t, events, 2)
+	assert.Equal(t, "invoice_list", events[0].Tool)
+	assert.Equal(t, "client_list", events[1].Tool)
+	assert.Equal(t, 1, events[1].ExitCode)
+	assert.Equal(t, int64(1000), events[1].DurationMS)
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	event := NewEvent(ctx, "invoice_list", "go-invoice", []string{"list"})
+	line, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	// Room for two events per file, keeping two old files
+	sink, err := NewFileSink(path, int64(2*(len(line)+1)), 2)
+	require.NoError(t, err)
+
+	for i := 0; i < 7; i++ {
+		require.NoError(t, sink.Record(ctx, event))
+	}
+
+	assert.Len(t, readEvents(t, path), 1)
+	assert.Len(t, readEvents(t, path+".1"), 2)
+	assert.Len(t, readEvents(t, path+".2"), 2)
+	assert.NoFileExists(t, path+".3")
+}
+
+func readEvents(t *testing.T, path string) []*Event {
+	t.Helper()
+
+	file, err := os.Open(path) //nolint:gosec // test file in a temp dir
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	var events []*Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, &event)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
@@ -0,0 +1,129 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Rotation defaults
+const (
+	DefaultMaxBytes   int64 = 10 * 1024 * 1024
+	DefaultMaxBackups       = 5
+)
+
+// ErrAuditWriteFailed is returned when an event cannot be written to the audit file
+var ErrAuditWriteFailed = errors.New("audit write failed")
+
+// FileSink appends events to a JSONL file, one event per line. When the file would grow
+// past maxBytes it is rotated: audit.jsonl becomes audit.jsonl.1, audit.jsonl.1 becomes
+// audit.jsonl.2 and so on, keeping at most maxBackups old files.
+type FileSink struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+	mu         sync.Mutex
+}
+
+// NewFileSink creates a FileSink writing to path. A maxBytes of zero or less disables
+// rotation and a maxBackups of zero or less keeps no old files.
+func NewFileSink(path string, maxBytes int64, maxBackups int) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	return &FileSink{
+		path:       path,
+		maxBytes:   maxBytes,
+		maxBackups: maxBackups,
+	}, nil
+}
+
+// Path returns the audit file path
+func (s *FileSink) Path() string {
+	return s.path
+}
+
+// Record appends event to the audit file, rotating it first if needed
+func (s *FileSink) Record(ctx context.Context, event *Event) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAuditWriteFailed, err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.rotateIfNeeded(int64(len(line))); err != nil {
+		return fmt.Errorf("%w: %w", ErrAuditWriteFailed, err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAuditWriteFailed, err)
+	}
+	if _, err := file.Write(line); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("%w: %w", ErrAuditWriteFailed, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrAuditWriteFailed, err)
+	}
+	return nil
+}
+
+// rotateIfNeeded rotates the audit file when writing n more bytes would take it past
+// maxBytes. A file that is still empty is never rotated, so an oversized event is
+// written rather than dropped.
+func (s *FileSink) rotateIfNeeded(n int64) error {
+	if s.maxBytes <= 0 {
+		return nil
+	}
+
+	info, err := os.Stat(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat audit file: %w", err)
+	}
+	if info.Size() == 0 || info.Size()+n <= s.maxBytes {
+		return nil
+	}
+
+	if s.maxBackups <= 0 {
+		if err := os.Remove(s.path); err != nil {
+			return fmt.Errorf("failed to remove audit file: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.Remove(s.backupPath(s.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove oldest audit file: %w", err)
+	}
+	for i := s.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(s.backupPath(i), s.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit file: %w", err)
+		}
+	}
+	if err := os.Rename(s.path, s.backupPath(1)); err != nil {
+		return fmt.Errorf("failed to rotate audit file: %w", err)
+	}
+	return nil
+}
+
+// backupPath returns the path of the nth rotated audit file
+func (s *FileSink) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", s.path, n)
+}
@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"strings"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+)
+
+// redactedValue replaces a secret argument value
+const redactedValue = "[REDACTED]"
+
+// secretNameParts mark a flag or setting name as holding a secret
+var secretNameParts = []string{"secret", "token", "password", "apikey", "api_key", "api-key", "account", "routing", "iban"}
+
+// RedactArgs returns a copy of args with secret values replaced. A value is secret when it
+// follows, or is attached with "=" to, a flag whose name looks like a secret, or when it
+// is a KEY=VALUE assignment of a sensitive configuration key.
+func RedactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	redactNext := false
+
+	for i, arg := range args {
+		switch {
+		case redactNext && !strings.HasPrefix(arg, "-"):
+			redacted[i] = redactedValue
+			redactNext = false
+			continue
+		case strings.HasPrefix(arg, "-"):
+			name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			secret := isSecretName(name)
+			redactNext = secret && !hasValue
+			if secret && hasValue {
+				arg = arg[:strings.Index(arg, "=")+1] + redactedValue
+			}
+		default:
+			redactNext = false
+			if name, _, ok := strings.Cut(arg, "="); ok && isSecretName(name) {
+				arg = name + "=" + redactedValue
+			}
+		}
+		redacted[i] = arg
+	}
+
+	return redacted
+}
+
+// isSecretName reports whether a flag or configuration key name holds a secret
+func isSecretName(name string) bool {
+	if key, err := config.LookupKey(name); err == nil {
+		return key.Sensitive()
+	}
+
+	name = strings.ToLower(name)
+	for _, part := range secretNameParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	invoiceConfig "github.com/mrz1836/go-invoice/internal/config"
 )
 
 const (
@@ -21,7 +24,9 @@ var (
 	ErrInvalidServerTimeout = errors.New("invalid server timeout")
 	ErrEmptyCLIPath         = errors.New("CLI path cannot be empty")
 	ErrInvalidCLIMaxTimeout = errors.New("invalid CLI max timeout")
+	ErrInvalidMaxOutputSize = errors.New("invalid CLI max output size")
 	ErrEmptyAllowedCommands = errors.New("allowed commands list cannot be empty")
+	ErrInvalidAllowedEnv    = errors.New("invalid allowed environment variable name")
 	ErrInvalidLogLevel      = errors.New("invalid log level")
 )
 
@@ -30,6 +35,7 @@ type Config struct {
 	Server   ServerConfig   `json:"server"`
 	CLI      CLIConfig      `json:"cli"`
 	Security SecurityConfig `json:"security"`
+	Audit    AuditConfig    `json:"audit"`
 	LogLevel string         `json:"logLevel"`
 }
 
@@ -39,6 +45,8 @@ type ServerConfig struct {
 	Port        int           `json:"port"`
 	Timeout     time.Duration `json:"timeout"`
 	ReadTimeout time.Duration `json:"readTimeout"`
+	// Watch reloads the configuration file when it changes
+	Watch bool `json:"watch,omitempty"`
 }
 
 // CLIConfig represents CLI bridge configuration
@@ -46,6 +54,10 @@ type CLIConfig struct {
 	Path       string        `json:"path"`
 	WorkingDir string        `json:"workingDir"`
 	MaxTimeout time.Duration `json:"maxTimeout"`
+	// MaxOutputSize caps the captured stdout and stderr of a command in bytes; 0 uses 10MB
+	MaxOutputSize int64 `json:"maxOutputSize,omitempty"`
+	// DryRun returns the command mutating tools would run instead of running it
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // SecurityConfig represents security-related configuration
@@ -56,6 +68,18 @@ type SecurityConfig struct {
 	FileAccessRestricted  bool     `json:"fileAccessRestricted"`
 	MaxCommandTimeout     string   `json:"maxCommandTimeout"`
 	EnableInputValidation bool     `json:"enableInputValidation"`
+	CommandPolicyFile     string   `json:"commandPolicyFile,omitempty"`
+	// AllowedEnv names server environment variables passed to the CLI in addition to the
+	// built-in allowlist; everything else is stripped
+	AllowedEnv []string `json:"allowedEnv,omitempty"`
+}
+
+// AuditConfig represents the tool execution audit log configuration
+type AuditConfig struct {
+	// File is the JSONL audit log path; auditing is off when empty
+	File       string `json:"file,omitempty"`
+	MaxSizeMB  int    `json:"maxSizeMB,omitempty"`
+	MaxBackups int    `json:"maxBackups,omitempty"`
 }
 
 // LoadConfig loads the MCP server configuration from file with validation
@@ -77,6 +101,16 @@ func LoadConfig(ctx context.Context) (*Config, error) {
 		return defaultConfig, nil
 	}
 
+	return loadConfigFile(ctx, configPath)
+}
+
+// ConfigPath returns the configuration file path used by LoadConfig
+func ConfigPath() string {
+	return getConfigPath()
+}
+
+// loadConfigFile reads, validates and applies environment overrides to the config at configPath
+func loadConfigFile(ctx context.Context, configPath string) (*Config, error) {
 	// #nosec G304 -- configPath is constructed from known sources (env vars, CLI args, defaults)
 	data, err := os.ReadFile(configPath)
 	if err != nil {
@@ -88,6 +122,11 @@ func LoadConfig(ctx context.Context) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
 	}
 
+	// Expand ~ and environment variables before the working directories are created
+	if err := expandConfigPaths(&config); err != nil {
+		return nil, fmt.Errorf("invalid path in config file %s: %w", configPath, err)
+	}
+
 	// Validate configuration
 	if err := validateConfig(ctx, &config); err != nil {
 		return nil, fmt.Errorf("config validation failed for %s: %w", configPath, err)
@@ -179,11 +218,21 @@ func validateConfig(ctx context.Context, config *Config) error {
 		return fmt.Errorf("%w: %v (must be positive)", ErrInvalidCLIMaxTimeout, config.CLI.MaxTimeout)
 	}
 
+	if config.CLI.MaxOutputSize < 0 {
+		return fmt.Errorf("%w: %d (must not be negative)", ErrInvalidMaxOutputSize, config.CLI.MaxOutputSize)
+	}
+
 	// Validate security configuration
 	if len(config.Security.AllowedCommands) == 0 {
 		return ErrEmptyAllowedCommands
 	}
 
+	for _, name := range config.Security.AllowedEnv {
+		if name == "" || strings.ContainsAny(name, "= \t") {
+			return fmt.Errorf("%w: %q", ErrInvalidAllowedEnv, name)
+		}
+	}
+
 	// Validate working directory exists or can be created
 	if err := ensureDirectoryExists(config.CLI.WorkingDir); err != nil {
 		return fmt.Errorf("failed to ensure CLI working directory %s exists: %w", config.CLI.WorkingDir, err)
@@ -208,6 +257,21 @@ func validateConfig(ctx context.Context, config *Config) error {
 	return nil
 }
 
+// expandConfigPaths expands ~ and environment variables in the working directories
+func expandConfigPaths(config *Config) error {
+	var err error
+	if config.CLI.WorkingDir, err = invoiceConfig.ExpandPath(config.CLI.WorkingDir); err != nil {
+		return fmt.Errorf("cli.workingDir: %w", err)
+	}
+	if config.Security.WorkingDir, err = invoiceConfig.ExpandPath(config.Security.WorkingDir); err != nil {
+		return fmt.Errorf("security.workingDir: %w", err)
+	}
+	if config.Security.CommandPolicyFile, err = invoiceConfig.ExpandPath(config.Security.CommandPolicyFile); err != nil {
+		return fmt.Errorf("security.commandPolicyFile: %w", err)
+	}
+	return nil
+}
+
 // applyEnvironmentOverrides applies environment variable overrides to configuration
 func applyEnvironmentOverrides(config *Config) {
 	if logLevel := os.Getenv("MCP_LOG_LEVEL"); logLevel != "" {
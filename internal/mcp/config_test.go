@@ -10,6 +10,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+
+	invoiceConfig "github.com/mrz1836/go-invoice/internal/config"
 )
 
 type ConfigTestSuite struct {
@@ -117,6 +119,32 @@ func (s *ConfigTestSuite) TestLoadConfigFromExistingFile() {
 	s.Contains(config.Security.AllowedCommands, "test-command")
 }
 
+func (s *ConfigTestSuite) TestLoadConfigExpandsWorkingDirs() {
+	s.T().Setenv("HOME", s.tempDir)
+	s.T().Setenv("MCP_WORK_ROOT", filepath.Join(s.tempDir, "work"))
+
+	testConfig := getDefaultConfig()
+	testConfig.CLI.WorkingDir = "~/cli"
+	testConfig.Security.WorkingDir = "${MCP_WORK_ROOT}/security"
+	configPath := filepath.Join(s.tempDir, "expand-config.json")
+	s.Require().NoError(saveConfig(configPath, testConfig))
+
+	config, err := loadConfigFile(context.Background(), configPath)
+	s.Require().NoError(err)
+	s.Equal(filepath.Join(s.tempDir, "cli"), config.CLI.WorkingDir)
+	s.Equal(filepath.Join(s.tempDir, "work", "security"), config.Security.WorkingDir)
+	s.DirExists(config.CLI.WorkingDir)
+	s.NoDirExists(filepath.Join(s.tempDir, "~"))
+
+	// An undefined variable is reported instead of creating a surprising directory
+	s.Require().NoError(os.Unsetenv("MCP_UNDEFINED_ROOT"))
+	testConfig.CLI.WorkingDir = "$MCP_UNDEFINED_ROOT/cli"
+	s.Require().NoError(saveConfig(configPath, testConfig))
+	_, err = loadConfigFile(context.Background(), configPath)
+	s.Require().ErrorIs(err, invoiceConfig.ErrUndefinedPathVariable)
+	s.Contains(err.Error(), "cli.workingDir")
+}
+
 func (s *ConfigTestSuite) TestLoadConfigContextCancellation() {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Immediately cancel
@@ -172,6 +200,13 @@ func (s *ConfigTestSuite) TestValidateConfigFailures() {
 			},
 			contains: "invalid CLI max timeout",
 		},
+		{
+			name: "NegativeMaxOutputSize",
+			modifier: func(c *Config) {
+				c.CLI.MaxOutputSize = -1
+			},
+			contains: "invalid CLI max output size",
+		},
 		{
 			name: "EmptyAllowedCommands",
 			modifier: func(c *Config) {
@@ -179,6 +214,13 @@ func (s *ConfigTestSuite) TestValidateConfigFailures() {
 			},
 			contains: "allowed commands list cannot be empty",
 		},
+		{
+			name: "InvalidAllowedEnv",
+			modifier: func(c *Config) {
+				c.Security.AllowedEnv = []string{"API_TOKEN=x"}
+			},
+			contains: "invalid allowed environment variable name",
+		},
 		{
 			name: "InvalidLogLevel",
 			modifier: func(c *Config) {
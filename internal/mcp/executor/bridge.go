@@ -2,11 +2,14 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
+	"sync"
 	"time"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/mcp/audit"
 )
 
 // BridgeError represents tool bridge errors.
@@ -58,10 +61,27 @@ type ToolCommand struct {
 	// ExpectJSON indicates if the command outputs JSON
 	ExpectJSON bool
 
-	// Timeout is the specific timeout for this command
+	// ReadOnly marks commands that change no data or files; they still run in dry-run mode
+	ReadOnly bool
+
+	// Environment names server environment variables this command needs beyond the
+	// sandbox allowlist
+	Environment []string
+
+	// Timeout is the specific timeout for this command, used when the caller's context
+	// carries no deadline
 	Timeout time.Duration
 }
 
+// commandTimeout returns the time left before ctx's deadline, so a tool timeout set by the
+// caller reaches the process, or fallback when ctx has no deadline
+func commandTimeout(ctx context.Context, fallback time.Duration) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return max(time.Until(deadline), time.Millisecond)
+	}
+	return fallback
+}
+
 // CLIBridge bridges MCP tool requests to CLI command execution.
 type CLIBridge struct {
 	logger       Logger
@@ -69,6 +89,9 @@ type CLIBridge struct {
 	fileHandler  FileHandler
 	toolCommands map[string]*ToolCommand
 	cliPath      string
+	auditSink    audit.AuditSink
+	dryRun       bool
+	mu           sync.RWMutex // guards cliPath and dryRun
 }
 
 // NewCLIBridge creates a new CLI bridge.
@@ -102,6 +125,45 @@ func NewCLIBridge(logger Logger, executor CommandExecutor, fileHandler FileHandl
 	return bridge
 }
 
+// SetAuditSink sets where command executions are audited. A nil sink disables auditing.
+func (b *CLIBridge) SetAuditSink(sink audit.AuditSink) {
+	b.auditSink = sink
+}
+
+// SetCLIPath changes the go-invoice binary run for later tool calls. Calls already
+// executing keep the path they started with.
+func (b *CLIBridge) SetCLIPath(cliPath string) {
+	if cliPath == "" {
+		cliPath = "go-invoice"
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cliPath = cliPath
+}
+
+// CLIPath returns the go-invoice binary run for tool calls.
+func (b *CLIBridge) CLIPath() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.cliPath
+}
+
+// SetDryRun turns dry-run mode on or off. In dry-run mode mutating tools are not executed;
+// they return the command they would run instead. Read-only tools still execute.
+func (b *CLIBridge) SetDryRun(dryRun bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dryRun = dryRun
+}
+
+// DryRun reports whether dry-run mode is on.
+func (b *CLIBridge) DryRun() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.dryRun
+}
+
 // ExecuteToolCommand executes a CLI command for an MCP tool.
 func (b *CLIBridge) ExecuteToolCommand(ctx context.Context, toolName string, input map[string]interface{}) (*ExecutionResponse, error) {
 	select {
@@ -158,10 +220,13 @@ func (b *CLIBridge) ExecuteToolCommand(ctx context.Context, toolName string, inp
 
 	// Prepare execution request
 	req := &ExecutionRequest{
-		Command:    b.cliPath,
+		Command:    b.CLIPath(),
 		Args:       fullArgs,
 		ExpectJSON: toolCmd.ExpectJSON,
-		Timeout:    toolCmd.Timeout,
+		InheritEnv: toolCmd.Environment,
+		Timeout:    commandTimeout(ctx, toolCmd.Timeout),
+
+		ProgressCallback: ProgressCallbackFromContext(ctx),
 	}
 
 	// Handle file operations if needed
@@ -176,6 +241,21 @@ func (b *CLIBridge) ExecuteToolCommand(ctx context.Context, toolName string, inp
 		}
 	}
 
+	// In dry-run mode, report what a mutating tool would run instead of running it
+	if !toolCmd.ReadOnly && b.DryRun() {
+		resp, err := dryRunResponse(toolName, req)
+		if err != nil {
+			return nil, err
+		}
+		b.logger.Info("dry run: tool command not executed",
+			"tool", toolName,
+			"command", req.Command,
+			"args", req.Args,
+		)
+		b.recordAudit(ctx, toolName, req, resp, nil)
+		return resp, nil
+	}
+
 	// Log command execution
 	b.logger.Info("executing tool command",
 		"tool", toolName,
@@ -185,6 +265,7 @@ func (b *CLIBridge) ExecuteToolCommand(ctx context.Context, toolName string, inp
 
 	// Execute the command
 	resp, err := b.executor.Execute(ctx, req)
+	b.recordAudit(ctx, toolName, req, resp, err)
 	if err != nil {
 		b.logger.Error("command execution failed",
 			"tool", toolName,
@@ -211,6 +292,58 @@ func (b *CLIBridge) ExecuteToolCommand(ctx context.Context, toolName string, inp
 	return resp, nil
 }
 
+// recordAudit writes an audit event for an executed command. Audit failures are logged
+// but never fail the tool call.
+func (b *CLIBridge) recordAudit(ctx context.Context, toolName string, req *ExecutionRequest, resp *ExecutionResponse, execErr error) {
+	if b.auditSink == nil {
+		return
+	}
+
+	event := audit.NewEvent(ctx, toolName, req.Command, req.Args)
+	event.DryRun = resp != nil && resp.Metadata[metaDryRun] == true
+	event.Denied = errors.Is(execErr, ErrPolicyViolation)
+	if resp != nil {
+		event.Finish(resp.ExitCode, resp.Duration, execErr)
+	} else {
+		event.Finish(0, 0, execErr)
+	}
+
+	if err := b.auditSink.Record(context.WithoutCancel(ctx), event); err != nil {
+		b.logger.Warn("failed to record audit event",
+			"tool", toolName,
+			"error", err,
+		)
+	}
+}
+
+// dryRunResult is the output of a tool call that dry-run mode kept from executing
+type dryRunResult struct {
+	DryRun  bool     `json:"dryRun"`
+	Tool    string   `json:"tool"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+}
+
+// dryRunResponse synthesizes the successful response of a command that was not executed.
+// Its output is the fully resolved command line.
+func dryRunResponse(toolName string, req *ExecutionRequest) (*ExecutionResponse, error) {
+	output, err := json.MarshalIndent(dryRunResult{
+		DryRun:  true,
+		Tool:    toolName,
+		Command: req.Command,
+		Args:    req.Args,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode dry run result: %w", err)
+	}
+
+	return &ExecutionResponse{
+		ExitCode: 0,
+		Stdout:   string(output),
+		Metadata: map[string]interface{}{metaDryRun: true},
+	}, nil
+}
+
 // prepareFilesForCommand prepares files for command execution.
 func (b *CLIBridge) prepareFilesForCommand(ctx context.Context, req *ExecutionRequest, input map[string]interface{}) error {
 	// Check for file_path parameter (common in import operations)
@@ -247,6 +380,7 @@ func (b *CLIBridge) registerToolCommands() {
 		SubCommands: []string{subCmdInvoice, "list"},
 		BuildArgs:   b.buildInvoiceListArgs,
 		ExpectJSON:  true,
+		ReadOnly:    true,
 		Timeout:     10 * time.Second,
 	}
 
@@ -256,6 +390,7 @@ func (b *CLIBridge) registerToolCommands() {
 		SubCommands: []string{subCmdInvoice, "show"},
 		BuildArgs:   b.buildInvoiceShowArgs,
 		ExpectJSON:  true,
+		ReadOnly:    true,
 		Timeout:     5 * time.Second,
 	}
 
@@ -306,6 +441,7 @@ func (b *CLIBridge) registerToolCommands() {
 		SubCommands: []string{"client", "list"},
 		BuildArgs:   b.buildClientListArgs,
 		ExpectJSON:  true,
+		ReadOnly:    true,
 		Timeout:     10 * time.Second,
 	}
 
@@ -315,6 +451,7 @@ func (b *CLIBridge) registerToolCommands() {
 		SubCommands: []string{"client", "show"},
 		BuildArgs:   b.buildClientShowArgs,
 		ExpectJSON:  true,
+		ReadOnly:    true,
 		Timeout:     5 * time.Second,
 	}
 
@@ -350,6 +487,7 @@ func (b *CLIBridge) registerToolCommands() {
 		SubCommands:   []string{"import", opValidate},
 		BuildArgs:     b.buildImportValidateArgs,
 		RequiresFiles: false, // Temporarily disable file validation to test
+		ReadOnly:      true,
 		Timeout:       10 * time.Second,
 	}
 
@@ -360,6 +498,7 @@ func (b *CLIBridge) registerToolCommands() {
 		BuildArgs:     b.buildImportPreviewArgs,
 		RequiresFiles: false, // Temporarily disable file validation to test
 		ExpectJSON:    false, // validate command doesn't output JSON by default
+		ReadOnly:      true,
 		Timeout:       10 * time.Second,
 	}
 
@@ -399,6 +538,7 @@ func (b *CLIBridge) registerToolCommands() {
 		SubCommands: []string{"config", "show"},
 		BuildArgs:   b.buildConfigShowArgs,
 		ExpectJSON:  true,
+		ReadOnly:    true,
 		Timeout:     5 * time.Second,
 	}
 
@@ -407,6 +547,7 @@ func (b *CLIBridge) registerToolCommands() {
 		Command:     b.cliPath,
 		SubCommands: []string{"config", opValidate},
 		BuildArgs:   b.buildConfigValidateArgs,
+		ReadOnly:    true,
 		Timeout:     5 * time.Second,
 	}
 
@@ -420,101 +561,65 @@ func (b *CLIBridge) registerToolCommands() {
 }
 
 // Helper functions to build command arguments for each tool
-// These map the MCP tool input parameters to CLI arguments
+// These map the MCP tool input parameters to CLI arguments, converting values by the
+// coercion rules in coerce.go
 
 // getConfigArgs returns the config path arguments for MCP commands
 func (b *CLIBridge) getConfigArgs() []string {
-	// Always use the absolute config path for MCP
-	homeDir, _ := os.UserHomeDir()
-	configPath := filepath.Join(homeDir, ".go-invoice", ".env.config")
-	return []string{"--config", configPath}
+	// Pass the file the CLI would find itself, so MCP and the CLI share one configuration
+	return []string{"--config", config.ResolvePath("").Path}
 }
 
 func (b *CLIBridge) buildInvoiceCreateArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
 	// Handle different client identifier options
 	if clientID, ok := input["client_id"].(string); ok && clientID != "" {
-		args = append(args, "--client-id", clientID)
+		args.add("--client-id", clientID)
 	} else if clientName, ok := input["client_name"].(string); ok && clientName != "" {
-		args = append(args, "--client", clientName)
+		args.add("--client", clientName)
 	} else if clientEmail, ok := input["client_email"].(string); ok && clientEmail != "" {
-		args = append(args, "--client-email", clientEmail)
+		args.add("--client-email", clientEmail)
 	} else {
 		return nil, fmt.Errorf("%w: one of client_id, client_name, or client_email is required", ErrMissingRequired)
 	}
 
 	// Optional parameters
-	if description, ok := input["description"].(string); ok && description != "" {
-		args = append(args, "--description", description)
-	}
-	if invoiceDate, ok := input["invoice_date"].(string); ok && invoiceDate != "" {
-		args = append(args, "--date", invoiceDate)
-	}
-	if dueDate, ok := input["due_date"].(string); ok && dueDate != "" {
-		args = append(args, "--due", dueDate)
-	}
-
-	// Handle work_items if provided
-	if workItems, ok := input["work_items"].([]interface{}); ok && len(workItems) > 0 {
-		// Add work items directly during creation
-		for _, item := range workItems {
-			if workItem, ok := item.(map[string]interface{}); ok {
-				if description, ok := workItem["description"].(string); ok && description != "" {
-					args = append(args, "--add-item-description", description)
-				}
-				if hours, ok := getFloatValue(workItem["hours"]); ok {
-					args = append(args, "--add-item-hours", fmt.Sprintf("%.2f", hours))
-				}
-				if rate, ok := getFloatValue(workItem["rate"]); ok {
-					args = append(args, "--add-item-rate", fmt.Sprintf("%.2f", rate))
-				}
-				if date, ok := workItem["date"].(string); ok && date != "" {
-					args = append(args, "--add-item-date", date)
-				}
-			}
-		}
-	}
-
-	// Handle create_client_if_missing
-	if createClient, ok := input["create_client_if_missing"].(bool); ok && createClient {
-		args = append(args, "--create-client")
-
-		// Add new client details if provided
-		if email, ok := input["new_client_email"].(string); ok && email != "" {
-			args = append(args, "--new-client-email", email)
-		}
-		if phone, ok := input["new_client_phone"].(string); ok && phone != "" {
-			args = append(args, "--new-client-phone", phone)
-		}
-		if address, ok := input["new_client_address"].(string); ok && address != "" {
-			args = append(args, "--new-client-address", address)
-		}
-	}
-
-	return args, nil
+	args.str("description", "--description")
+	args.str("invoice_date", "--date")
+	args.str("due_date", "--due")
+	args.number("tax_rate", "--tax-rate")
+
+	// Add work items directly during creation, one group of flags per item
+	args.objects("work_items", func(item *argList) {
+		item.str("description", "--add-item-description")
+		item.number("hours", "--add-item-hours")
+		item.number("rate", "--add-item-rate")
+		item.str("date", "--add-item-date")
+	})
+
+	// Handle create_client_if_missing, adding new client details if provided
+	if args.flag("create_client_if_missing", "--create-client") {
+		args.str("new_client_email", "--new-client-email")
+		args.str("new_client_phone", "--new-client-phone")
+		args.str("new_client_address", "--new-client-address")
+	}
+
+	return args.build()
 }
 
 func (b *CLIBridge) buildInvoiceListArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
-	args = append(args, "--output", "json") // Always output JSON for MCP
+	args.add("--output", "json") // Always output JSON for MCP
 
 	// Optional filters
-	if status, ok := input["status"].(string); ok && status != "" {
-		args = append(args, "--status", status)
-	}
-	if clientName, ok := input["client_name"].(string); ok && clientName != "" {
-		args = append(args, "--client", clientName)
-	}
-	if fromDate, ok := input["from_date"].(string); ok && fromDate != "" {
-		args = append(args, "--from", fromDate)
-	}
-	if toDate, ok := input["to_date"].(string); ok && toDate != "" {
-		args = append(args, "--to", toDate)
-	}
+	args.str("status", "--status")
+	args.str("client_name", "--client")
+	args.str("from_date", "--from")
+	args.str("to_date", "--to")
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildInvoiceShowArgs(input map[string]interface{}) ([]string, error) {
@@ -544,7 +649,7 @@ func (b *CLIBridge) buildInvoiceShowArgs(input map[string]interface{}) ([]string
 }
 
 func (b *CLIBridge) buildInvoiceUpdateArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
 	// Required: invoice_id or invoice_number
 	invoiceID, hasID := input[keyInvoiceID].(string)
@@ -559,32 +664,23 @@ func (b *CLIBridge) buildInvoiceUpdateArgs(input map[string]interface{}) ([]stri
 	if identifier == "" {
 		identifier = invoiceNumber
 	}
-	args = append(args, identifier)
+	args.add(identifier)
 
 	// At least one update field required
-	hasUpdate := false
-	if status, ok := input["status"].(string); ok && status != "" {
-		args = append(args, "--status", status)
-		hasUpdate = true
-	}
-	if dueDate, ok := input["due_date"].(string); ok && dueDate != "" {
-		args = append(args, "--due", dueDate)
-		hasUpdate = true
-	}
-	if description, ok := input["description"].(string); ok && description != "" {
-		args = append(args, "--description", description)
-		hasUpdate = true
-	}
+	before := len(args.args)
+	args.str("status", "--status")
+	args.str("due_date", "--due")
+	args.str("description", "--description")
 
-	if !hasUpdate {
+	if args.err == nil && len(args.args) == before {
 		return nil, ErrMissingUpdateFields
 	}
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildInvoiceDeleteArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
 	// Required: invoice_id or invoice_number
 	invoiceID, hasID := input[keyInvoiceID].(string)
@@ -601,23 +697,19 @@ func (b *CLIBridge) buildInvoiceDeleteArgs(input map[string]interface{}) ([]stri
 	}
 
 	// Add the identifier as positional argument first
-	args = append(args, identifier)
+	args.add(identifier)
 
 	// Optional: hard_delete (order matters - CLI expects it before --force)
-	if hardDelete, ok := input["hard_delete"].(bool); ok && hardDelete {
-		args = append(args, "--hard")
-	}
+	args.flag("hard_delete", "--hard")
 
 	// Optional: force (should come after other flags)
-	if force, ok := input["force"].(bool); ok && force {
-		args = append(args, "--force")
-	}
+	args.flag("force", "--force")
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildInvoiceAddItemArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
 	// Required: invoice_id or invoice_number
 	invoiceID, hasID := input[keyInvoiceID].(string)
@@ -632,65 +724,38 @@ func (b *CLIBridge) buildInvoiceAddItemArgs(input map[string]interface{}) ([]str
 	if identifier == "" {
 		identifier = invoiceNumber
 	}
-	args = append(args, identifier)
+	args.add(identifier)
 
 	// Check if we have work_items array (MCP schema format)
 	if workItems, ok := input["work_items"].([]interface{}); ok && len(workItems) > 0 {
-		// Handle array of work items
-		for _, item := range workItems {
-			if workItem, ok := item.(map[string]interface{}); ok {
-				// Add each work item
-				if description, ok := workItem["description"].(string); ok && description != "" {
-					args = append(args, "--description", description)
-				}
-
-				if hours, ok := getFloatValue(workItem["hours"]); ok {
-					args = append(args, "--hours", fmt.Sprintf("%.2f", hours))
-				}
-
-				if rate, ok := getFloatValue(workItem["rate"]); ok {
-					args = append(args, "--rate", fmt.Sprintf("%.2f", rate))
-				}
-
-				if date, ok := workItem["date"].(string); ok && date != "" {
-					args = append(args, "--date", date)
-				}
-
-				// This assumes the CLI can handle multiple work items with repeated flags
-				// If not, we may need to adjust the CLI or use a different approach
-			}
-		}
-	} else {
-		// Fallback to individual parameters for backwards compatibility
-		description, hasDesc := input["description"].(string)
-		hours, hasHours := getFloatValue(input["hours"])
-		rate, hasRate := getFloatValue(input["rate"])
-
-		if !hasDesc || description == "" {
-			return nil, fmt.Errorf("%w: description or work_items", ErrMissingRequired)
-		}
-		if !hasHours {
-			return nil, fmt.Errorf("%w: hours or work_items", ErrMissingRequired)
-		}
-		if !hasRate {
-			return nil, fmt.Errorf("%w: rate or work_items", ErrMissingRequired)
-		}
-
-		args = append(args, "--description", description)
-		args = append(args, "--hours", fmt.Sprintf("%.2f", hours))
-		args = append(args, "--rate", fmt.Sprintf("%.2f", rate))
+		// This assumes the CLI can handle multiple work items with repeated flags
+		args.objects("work_items", func(item *argList) {
+			item.str("description", "--description")
+			item.number("hours", "--hours")
+			item.number("rate", "--rate")
+			item.str("date", "--date")
+		})
+		return args.build()
+	}
 
-		// Optional: date
-		if date, ok := input["date"].(string); ok && date != "" {
-			args = append(args, "--date", date)
+	// Fallback to individual parameters for backwards compatibility
+	for _, field := range []string{"description", "hours", "rate"} {
+		if value, ok := input[field]; !ok || value == nil || value == "" {
+			return nil, fmt.Errorf("%w: %s or work_items", ErrMissingRequired, field)
 		}
 	}
+	args.str("description", "--description")
+	args.number("hours", "--hours")
+	args.number("rate", "--rate")
 
-	return args, nil
+	// Optional: date
+	args.str("date", "--date")
+
+	return args.build()
 }
 
 func (b *CLIBridge) buildInvoiceRemoveItemArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
 	// Required: invoice_id or invoice_number
 	invoiceID, hasID := input[keyInvoiceID].(string)
@@ -705,103 +770,73 @@ func (b *CLIBridge) buildInvoiceRemoveItemArgs(input map[string]interface{}) ([]
 	if identifier == "" {
 		identifier = invoiceNumber
 	}
-	args = append(args, identifier)
+	args.add(identifier)
 
 	// Determine removal criteria - work_item_id, work_item_description, work_item_date, or legacy item_id/item_index
-	hasRemovalCriteria := false
+	before := len(args.args)
 
 	if workItemID, ok := input["work_item_id"].(string); ok && workItemID != "" {
-		args = append(args, "--item-id", workItemID)
-		hasRemovalCriteria = true
-	} else if itemID, ok := input["item_id"].(string); ok && itemID != "" {
+		args.add("--item-id", workItemID)
+	} else {
 		// Legacy support
-		args = append(args, "--item-id", itemID)
-		hasRemovalCriteria = true
-	}
-
-	if workItemDesc, ok := input["work_item_description"].(string); ok && workItemDesc != "" {
-		args = append(args, "--description", workItemDesc)
-		hasRemovalCriteria = true
-	}
-
-	if workItemDate, ok := input["work_item_date"].(string); ok && workItemDate != "" {
-		args = append(args, "--date", workItemDate)
-		hasRemovalCriteria = true
+		args.str("item_id", "--item-id")
 	}
+	args.str("work_item_description", "--description")
+	args.str("work_item_date", "--date")
 
 	// Legacy index support
-	if !hasRemovalCriteria {
-		if itemIndex, ok := getIntValue(input["item_index"]); ok {
-			args = append(args, "--index", fmt.Sprintf("%d", itemIndex))
-			hasRemovalCriteria = true
-		}
+	if len(args.args) == before {
+		args.integer("item_index", "--index")
 	}
 
-	if !hasRemovalCriteria {
+	if args.err == nil && len(args.args) == before {
 		return nil, fmt.Errorf("%w: one of work_item_id, work_item_description, work_item_date, or item_index is required", ErrMissingRequired)
 	}
 
 	// Optional: remove_all_matching
-	if removeAll, ok := input["remove_all_matching"].(bool); ok && removeAll {
-		args = append(args, "--all")
-	}
+	args.flag("remove_all_matching", "--all")
 
 	// Optional: confirm
-	if confirm, ok := input["confirm"].(bool); ok && confirm {
-		args = append(args, "--yes")
-	}
+	args.flag("confirm", "--yes")
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildClientCreateArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
 	// Required: name
 	name, ok := input["name"].(string)
 	if !ok || name == "" {
 		return nil, fmt.Errorf("%w: name", ErrMissingRequired)
 	}
-	args = append(args, "--name", name)
+	args.add("--name", name)
 
 	// Optional parameters
-	if email, ok := input["email"].(string); ok && email != "" {
-		args = append(args, "--email", email)
-	}
-	if phone, ok := input["phone"].(string); ok && phone != "" {
-		args = append(args, "--phone", phone)
-	}
-	if address, ok := input["address"].(string); ok && address != "" {
-		args = append(args, "--address", address)
-	}
-	if taxID, ok := input["tax_id"].(string); ok && taxID != "" {
-		args = append(args, "--tax-id", taxID)
-	}
+	args.str("email", "--email")
+	args.str("phone", "--phone")
+	args.str("address", "--address")
+	args.str("tax_id", "--tax-id")
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildClientListArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
-	args = append(args, "--output", "json") // Always output JSON for MCP
+	args.add("--output", "json") // Always output JSON for MCP
 
 	// Optional filters
-	if active, ok := input["active"].(bool); ok {
-		if active {
-			args = append(args, "--active")
-		} else {
-			args = append(args, "--inactive")
-		}
-	}
+	args.choice("active", "--active", "--inactive")
+
 	// Check for name_search (MCP parameter) or search
 	if search, ok := input["name_search"].(string); ok && search != "" {
-		args = append(args, "--search", search)
-	} else if search, ok := input["search"].(string); ok && search != "" {
-		args = append(args, "--search", search)
+		args.add("--search", search)
+	} else {
+		args.str("search", "--search")
 	}
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildClientShowArgs(input map[string]interface{}) ([]string, error) {
@@ -822,249 +857,156 @@ func (b *CLIBridge) buildClientShowArgs(input map[string]interface{}) ([]string,
 }
 
 func (b *CLIBridge) buildClientUpdateArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
 	// Required: client identifier
 	if clientID, ok := input["client_id"].(string); ok && clientID != "" {
-		args = append(args, clientID)
+		args.add(clientID)
 	} else if clientName, ok := input["client_name"].(string); ok && clientName != "" {
-		args = append(args, clientName)
+		args.add(clientName)
 	} else {
 		return nil, ErrMissingClientIDOrName
 	}
 
 	// At least one update field required
-	hasUpdate := false
-	if name, ok := input["name"].(string); ok && name != "" {
-		args = append(args, "--name", name)
-		hasUpdate = true
-	}
-	if email, ok := input["email"].(string); ok && email != "" {
-		args = append(args, "--email", email)
-		hasUpdate = true
-	}
-	if phone, ok := input["phone"].(string); ok && phone != "" {
-		args = append(args, "--phone", phone)
-		hasUpdate = true
-	}
-	if address, ok := input["address"].(string); ok && address != "" {
-		args = append(args, "--address", address)
-		hasUpdate = true
-	}
-	if active, ok := input["active"].(bool); ok {
-		if active {
-			args = append(args, "--activate")
-		} else {
-			args = append(args, "--deactivate")
-		}
-		hasUpdate = true
-	}
-
-	if !hasUpdate {
+	before := len(args.args)
+	args.str("name", "--name")
+	args.str("email", "--email")
+	args.str("phone", "--phone")
+	args.str("address", "--address")
+	args.choice("active", "--activate", "--deactivate")
+
+	if args.err == nil && len(args.args) == before {
 		return nil, ErrMissingUpdateFields
 	}
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildClientDeleteArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
 	// Required: client identifier
 	if clientID, ok := input["client_id"].(string); ok && clientID != "" {
-		args = append(args, clientID)
+		args.add(clientID)
 	} else if clientName, ok := input["client_name"].(string); ok && clientName != "" {
-		args = append(args, clientName)
+		args.add(clientName)
 	} else {
 		return nil, ErrMissingClientIDOrName
 	}
 
 	// Optional: force
-	if force, ok := input["force"].(bool); ok && force {
-		args = append(args, "--force")
-	}
+	args.flag("force", "--force")
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildImportCSVArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
 	// Required: file_path
 	filePath, ok := input["file_path"].(string)
 	if !ok || filePath == "" {
 		return nil, fmt.Errorf("%w: file_path", ErrMissingRequired)
 	}
-	args = append(args, filePath)
+	args.add(filePath)
 
 	// Handle import_mode and required parameters
 	importMode, hasMode := input["import_mode"].(string)
 	if hasMode && importMode == "append_invoice" {
 		// For append mode, we need an invoice ID
 		if invoiceID, ok := input[keyInvoiceID].(string); ok && invoiceID != "" {
-			args = append(args, "--invoice", invoiceID)
+			args.add("--invoice", invoiceID)
 		} else if invoiceNumber, ok := input["invoice_number"].(string); ok && invoiceNumber != "" {
-			args = append(args, "--invoice", invoiceNumber)
+			args.add("--invoice", invoiceNumber)
 		} else {
 			return nil, fmt.Errorf("%w: invoice_id or invoice_number is required for append_invoice mode", ErrMissingRequired)
 		}
 	} else {
 		// Create new invoice mode - need client identifier
 		if clientID, ok := input["client_id"].(string); ok && clientID != "" {
-			args = append(args, "--client-id", clientID)
+			args.add("--client-id", clientID)
 		} else if clientName, ok := input["client_name"].(string); ok && clientName != "" {
-			args = append(args, "--client", clientName)
+			args.add("--client", clientName)
 		} else if clientEmail, ok := input["client_email"].(string); ok && clientEmail != "" {
-			args = append(args, "--client-email", clientEmail)
+			args.add("--client-email", clientEmail)
 		} else {
 			return nil, fmt.Errorf("%w: client_id, client_name, or client_email is required for new invoice", ErrMissingRequired)
 		}
 
-		// Optional: description for new invoice
-		if description, ok := input["description"].(string); ok && description != "" {
-			args = append(args, "--description", description)
-		}
-
-		// Optional: invoice_date for new invoice
-		if invoiceDate, ok := input["invoice_date"].(string); ok && invoiceDate != "" {
-			args = append(args, "--date", invoiceDate)
-		}
-
-		// Optional: due_days for new invoice
-		if dueDays, ok := getIntValue(input["due_days"]); ok {
-			args = append(args, "--due-days", fmt.Sprintf("%d", dueDays))
-		}
+		// Optional: description, invoice_date and due_days for new invoice
+		args.str("description", "--description")
+		args.str("invoice_date", "--date")
+		args.integer("due_days", "--due-days")
 	}
 
 	// Common optional parameters for both modes
-	if defaultRate, ok := getFloatValue(input["default_rate"]); ok {
-		args = append(args, "--default-rate", fmt.Sprintf("%.2f", defaultRate))
-	}
+	args.number("default_rate", "--default-rate")
+	args.flag("rate_override", "--rate-override")
+	args.flag("dry_run", "--dry-run")
+	args.str("delimiter", "--delimiter")
+	args.negatedFlag("has_header", "--no-header")
+	args.str("currency", "--currency")
 
-	if rateOverride, ok := input["rate_override"].(bool); ok && rateOverride {
-		args = append(args, "--rate-override")
-	}
-
-	if dryRun, ok := input["dry_run"].(bool); ok && dryRun {
-		args = append(args, "--dry-run")
-	}
-
-	if delimiter, ok := input["delimiter"].(string); ok && delimiter != "" {
-		args = append(args, "--delimiter", delimiter)
-	}
-
-	if hasHeader, ok := input["has_header"].(bool); ok && !hasHeader {
-		args = append(args, "--no-header")
-	}
+	// Report row progress on stderr for the executor to pick up
+	args.add("--progress", "json")
 
-	if currency, ok := input["currency"].(string); ok && currency != "" {
-		args = append(args, "--currency", currency)
-	}
-
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildImportValidateArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
 	// Required: file_path
 	filePath, ok := input["file_path"].(string)
 	if !ok || filePath == "" {
 		return nil, fmt.Errorf("%w: file_path", ErrMissingRequired)
 	}
-	args = append(args, filePath)
-
-	// Optional: delimiter
-	if delimiter, ok := input["delimiter"].(string); ok && delimiter != "" {
-		args = append(args, "--delimiter", delimiter)
-	}
+	args.add(filePath)
 
-	// Optional: has_header
-	if hasHeader, ok := input["has_header"].(bool); ok && !hasHeader {
-		args = append(args, "--no-header")
-	}
-
-	// Optional: validate_rates
-	if validateRates, ok := input["validate_rates"].(bool); ok && validateRates {
-		args = append(args, "--validate-rates")
-	}
+	// Optional parsing options
+	args.str("delimiter", "--delimiter")
+	args.negatedFlag("has_header", "--no-header")
 
-	// Optional: validate_dates
-	if validateDates, ok := input["validate_dates"].(bool); ok && validateDates {
-		args = append(args, "--validate-dates")
-	}
-
-	// Optional: validate_business
-	if validateBusiness, ok := input["validate_business"].(bool); ok && validateBusiness {
-		args = append(args, "--validate-business")
-	}
-
-	// Optional: max_hours_per_day
-	if maxHours, ok := getFloatValue(input["max_hours_per_day"]); ok {
-		args = append(args, "--max-hours", fmt.Sprintf("%.1f", maxHours))
-	}
-
-	// Optional: min_rate
-	if minRate, ok := getFloatValue(input["min_rate"]); ok {
-		args = append(args, "--min-rate", fmt.Sprintf("%.2f", minRate))
-	}
-
-	// Optional: max_rate
-	if maxRate, ok := getFloatValue(input["max_rate"]); ok {
-		args = append(args, "--max-rate", fmt.Sprintf("%.2f", maxRate))
-	}
-
-	// Optional: check_duplicates
-	if checkDuplicates, ok := input["check_duplicates"].(bool); ok && checkDuplicates {
-		args = append(args, "--check-duplicates")
-	}
-
-	// Optional: check_weekends
-	if checkWeekends, ok := input["check_weekends"].(bool); ok && checkWeekends {
-		args = append(args, "--check-weekends")
-	}
-
-	// Optional: quick_validate
-	if quickValidate, ok := input["quick_validate"].(bool); ok && quickValidate {
-		args = append(args, "--quick")
-	}
+	// Optional validation checks
+	args.flag("validate_rates", "--validate-rates")
+	args.flag("validate_dates", "--validate-dates")
+	args.flag("validate_business", "--validate-business")
+	args.number("max_hours_per_day", "--max-hours")
+	args.number("min_rate", "--min-rate")
+	args.number("max_rate", "--max-rate")
+	args.flag("check_duplicates", "--check-duplicates")
+	args.flag("check_weekends", "--check-weekends")
+	args.flag("quick_validate", "--quick")
 
 	// Optional: strict validation (legacy)
-	if strict, ok := input["strict"].(bool); ok && strict {
-		args = append(args, "--strict")
-	}
+	args.flag("strict", "--strict")
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildImportPreviewArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
 	// Required: file_path
 	filePath, ok := input["file_path"].(string)
 	if !ok || filePath == "" {
 		return nil, fmt.Errorf("%w: file_path", ErrMissingRequired)
 	}
-	args = append(args, filePath)
+	args.add(filePath)
 
 	// For preview, we use the validate command with dry-run
-	args = append(args, "--dry-run")
-
-	// Optional: delimiter
-	if delimiter, ok := input["delimiter"].(string); ok && delimiter != "" {
-		args = append(args, "--delimiter", delimiter)
-	}
+	args.add("--dry-run")
 
-	// Optional: has_header
-	if hasHeader, ok := input["has_header"].(bool); ok && !hasHeader {
-		args = append(args, "--no-header")
-	}
+	// Optional parsing options
+	args.str("delimiter", "--delimiter")
+	args.negatedFlag("has_header", "--no-header")
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildGenerateHTMLArgs(input map[string]interface{}) ([]string, error) {
-	args := b.getConfigArgs()
+	args := newArgList(input, b.getConfigArgs()...)
 
 	// Required: invoice identifier (invoice_id or invoice_number)
 	invoiceID, hasID := input[keyInvoiceID].(string)
@@ -1081,18 +1023,14 @@ func (b *CLIBridge) buildGenerateHTMLArgs(input map[string]interface{}) ([]strin
 	}
 
 	// Add invoice identifier as positional argument (this will be added after config args)
-	args = append(args, identifier)
+	args.add(identifier)
 
 	// Optional: template
-	if template, ok := input["template"].(string); ok && template != "" {
-		args = append(args, "--template", template)
-	}
+	args.str("template", "--template")
 
 	// Optional: output path - only pass --output if explicitly provided
 	// Let the CLI handle its own default path logic
-	if outputPath, ok := input["output_path"].(string); ok && outputPath != "" {
-		args = append(args, "--output", outputPath)
-	}
+	args.str("output_path", "--output")
 
 	// Handle batch_invoices if provided (not supported by CLI, but we should handle gracefully)
 	if batchInvoices, ok := input["batch_invoices"].([]interface{}); ok && len(batchInvoices) > 0 {
@@ -1103,189 +1041,96 @@ func (b *CLIBridge) buildGenerateHTMLArgs(input map[string]interface{}) ([]strin
 	// These parameters are part of the MCP schema but not implemented in the CLI:
 	// - company_name, custom_css, footer_text, include_logo, include_notes
 	// - web_preview, return_html (these conflict with file-based output)
-	_ = input["company_name"]
-	_ = input["custom_css"]
-	_ = input["footer_text"]
-	_ = input["include_logo"]
-	_ = input["include_notes"]
-	_ = input["web_preview"]
-	_ = input["return_html"]
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildGenerateSummaryArgs(input map[string]interface{}) ([]string, error) {
-	var args []string
-	args = append(args, "--output", "json") // Output JSON for MCP
+	args := newArgList(input, "--output", "json") // Output JSON for MCP
 
 	// Required: summary_type
 	summaryType, ok := input["summary_type"].(string)
 	if !ok || summaryType == "" {
 		summaryType = "revenue" // Default
 	}
-	args = append(args, "--type", summaryType)
+	args.add("--type", summaryType)
 
-	// Optional: period
-	if period, ok := input["period"].(string); ok && period != "" {
-		args = append(args, "--period", period)
-	}
-
-	// Optional: from/to dates
-	if fromDate, ok := input["from_date"].(string); ok && fromDate != "" {
-		args = append(args, "--from", fromDate)
-	}
-	if toDate, ok := input["to_date"].(string); ok && toDate != "" {
-		args = append(args, "--to", toDate)
-	}
+	// Optional: period and from/to dates
+	args.str("period", "--period")
+	args.str("from_date", "--from")
+	args.str("to_date", "--to")
 
 	// Optional: group by
-	if groupBy, ok := input["group_by"].(string); ok && groupBy != "" {
-		args = append(args, "--group-by", groupBy)
-	}
+	args.str("group_by", "--group-by")
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildExportDataArgs(input map[string]interface{}) ([]string, error) {
-	var args []string
+	args := newArgList(input)
 
 	// Required: export_type
 	exportType, ok := input["export_type"].(string)
 	if !ok || exportType == "" {
 		exportType = "invoices" // Default
 	}
-	args = append(args, "--type", exportType)
+	args.add("--type", exportType)
 
 	// Required: format
 	format, ok := input["format"].(string)
 	if !ok || format == "" {
 		format = "json" // Default
 	}
-	args = append(args, "--format", format)
+	args.add("--format", format)
 
 	// Optional: filters
-	if status, ok := input["status"].(string); ok && status != "" {
-		args = append(args, "--status", status)
-	}
-	if clientName, ok := input["client_name"].(string); ok && clientName != "" {
-		args = append(args, "--client", clientName)
-	}
-	if fromDate, ok := input["from_date"].(string); ok && fromDate != "" {
-		args = append(args, "--from", fromDate)
-	}
-	if toDate, ok := input["to_date"].(string); ok && toDate != "" {
-		args = append(args, "--to", toDate)
-	}
+	args.str("status", "--status")
+	args.str("client_name", "--client")
+	args.str("from_date", "--from")
+	args.str("to_date", "--to")
 
 	// Optional: output file
-	if outputFile, ok := input["output_file"].(string); ok && outputFile != "" {
-		args = append(args, "--output", outputFile)
-	}
+	args.str("output_file", "--output")
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildConfigShowArgs(input map[string]interface{}) ([]string, error) {
-	var args []string
-	args = append(args, "--output", "json") // Always output JSON for MCP
+	args := newArgList(input, "--output", "json") // Always output JSON for MCP
 
 	// Optional: section
-	if section, ok := input["section"].(string); ok && section != "" {
-		args = append(args, "--section", section)
-	}
+	args.str("section", "--section")
 
 	// Optional: show defaults
-	if showDefaults, ok := input["show_defaults"].(bool); ok && showDefaults {
-		args = append(args, "--show-defaults")
-	}
+	args.flag("show_defaults", "--show-defaults")
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildConfigValidateArgs(input map[string]interface{}) ([]string, error) {
-	var args []string
+	args := newArgList(input)
 
 	// Optional: config file path
-	if configPath, ok := input["config_path"].(string); ok && configPath != "" {
-		args = append(args, "--file", configPath)
-	}
+	args.str("config_path", "--file")
 
 	// Optional: strict validation
-	if strict, ok := input["strict"].(bool); ok && strict {
-		args = append(args, "--strict")
-	}
+	args.flag("strict", "--strict")
 
-	return args, nil
+	return args.build()
 }
 
 func (b *CLIBridge) buildConfigInitArgs(input map[string]interface{}) ([]string, error) {
-	var args []string
-
-	// Optional: template
-	if template, ok := input["template"].(string); ok && template != "" {
-		args = append(args, "--template", template)
-	}
+	args := newArgList(input)
 
-	// Optional: output path
-	if outputPath, ok := input["output_path"].(string); ok && outputPath != "" {
-		args = append(args, "--output", outputPath)
-	}
+	// Optional: template and output path
+	args.str("template", "--template")
+	args.str("output_path", "--output")
 
 	// Optional: force overwrite
-	if force, ok := input["force"].(bool); ok && force {
-		args = append(args, "--force")
-	}
+	args.flag("force", "--force")
 
 	// Optional: interactive mode
-	if interactive, ok := input["interactive"].(bool); ok && interactive {
-		args = append(args, "--interactive")
-	}
-
-	return args, nil
-}
-
-// Helper functions
-
-func getFloatValue(v interface{}) (float64, bool) {
-	switch val := v.(type) {
-	case float64:
-		return val, true
-	case float32:
-		return float64(val), true
-	case int:
-		return float64(val), true
-	case int64:
-		return float64(val), true
-	case string:
-		// Try to parse string as float
-		if strings.TrimSpace(val) == "" {
-			return 0, false
-		}
-		var f float64
-		_, err := fmt.Sscanf(val, "%f", &f)
-		return f, err == nil
-	default:
-		return 0, false
-	}
-}
+	args.flag("interactive", "--interactive")
 
-func getIntValue(v interface{}) (int, bool) {
-	switch val := v.(type) {
-	case int:
-		return val, true
-	case int64:
-		return int(val), true
-	case float64:
-		return int(val), true
-	case string:
-		// Try to parse string as int
-		if strings.TrimSpace(val) == "" {
-			return 0, false
-		}
-		var i int
-		_, err := fmt.Sscanf(val, "%d", &i)
-		return i, err == nil
-	default:
-		return 0, false
-	}
+	return args.build()
 }
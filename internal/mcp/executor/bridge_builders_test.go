@@ -47,6 +47,20 @@ func (suite *BridgeBuildersTestSuite) TestBuildInvoiceCreateArgsWithClientID() {
 	suite.Contains(args, "Test Invoice")
 }
 
+// TestBuildInvoiceCreateArgsWithTaxRate tests that the tax rate is passed as a decimal
+func (suite *BridgeBuildersTestSuite) TestBuildInvoiceCreateArgsWithTaxRate() {
+	input := map[string]interface{}{
+		"client_name": "ACME Corp",
+		"tax_rate":    0.0825,
+	}
+
+	args, err := suite.bridge.buildInvoiceCreateArgs(input)
+
+	suite.Require().NoError(err)
+	suite.Contains(args, "--tax-rate")
+	suite.Contains(args, "0.0825")
+}
+
 // TestBuildInvoiceCreateArgsWithClientName tests invoice creation with client_name
 func (suite *BridgeBuildersTestSuite) TestBuildInvoiceCreateArgsWithClientName() {
 	input := map[string]interface{}{
@@ -494,6 +508,7 @@ func (suite *BridgeBuildersTestSuite) TestBuildImportCSVArgs() {
 	suite.Contains(args, "/tmp/timesheet.csv")
 	suite.Contains(args, "--client-id")
 	suite.Contains(args, "--description")
+	suite.Equal([]string{"--progress", "json"}, args[len(args)-2:])
 }
 
 // TestBuildImportCSVArgsAppendMode tests CSV import in append mode
@@ -2,13 +2,28 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+
+	"github.com/mrz1836/go-invoice/internal/mcp/audit"
 )
 
+// recordingSink collects audit events in memory
+type recordingSink struct {
+	events []*audit.Event
+}
+
+func (r *recordingSink) Record(_ context.Context, event *audit.Event) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
 // CLIBridgeTestSuite tests the CLIBridge implementation
 type CLIBridgeTestSuite struct {
 	suite.Suite
@@ -49,6 +64,17 @@ func (suite *CLIBridgeTestSuite) TestNewCLIBridgeWithCustomPath() {
 	suite.Equal("/usr/local/bin/go-invoice", bridge.cliPath)
 }
 
+// TestSetCLIPath tests changing the CLI path after construction
+func (suite *CLIBridgeTestSuite) TestSetCLIPath() {
+	bridge := NewCLIBridge(suite.logger, suite.executor, suite.fileHandler, "")
+
+	bridge.SetCLIPath("/opt/bin/go-invoice")
+	suite.Equal("/opt/bin/go-invoice", bridge.CLIPath())
+
+	bridge.SetCLIPath("")
+	suite.Equal("go-invoice", bridge.CLIPath())
+}
+
 // TestNewCLIBridgePanicsWithNilLogger tests constructor panics without logger
 func (suite *CLIBridgeTestSuite) TestNewCLIBridgePanicsWithNilLogger() {
 	suite.Panics(func() {
@@ -91,80 +117,130 @@ func (suite *CLIBridgeTestSuite) TestExecuteToolCommandContextCancellation() {
 	suite.Nil(result)
 }
 
-// TestBridgeErrorError tests BridgeError.Error() method
-func (suite *CLIBridgeTestSuite) TestBridgeErrorError() {
-	err := &BridgeError{Op: testStr, Msg: "test message"}
-	suite.Equal("bridge test: test message", err.Error())
+// TestExecuteToolCommandAuditsSuccess tests a successful command is audited
+func (suite *CLIBridgeTestSuite) TestExecuteToolCommandAuditsSuccess() {
+	sink := &recordingSink{}
+	suite.bridge.SetAuditSink(sink)
+	suite.expectExecutionLogs()
+	suite.executor.On("Execute", mock.Anything, mock.Anything).
+		Return(&ExecutionResponse{ExitCode: 0, Duration: 1500 * time.Millisecond}, nil).Once()
+
+	ctx := audit.WithCaller(context.Background(), "stdio")
+	_, err := suite.bridge.ExecuteToolCommand(ctx, "invoice_list", nil)
+	suite.Require().NoError(err)
+
+	suite.Require().Len(sink.events, 1)
+	event := sink.events[0]
+	suite.Equal("invoice_list", event.Tool)
+	suite.Equal("go-invoice", event.Command)
+	suite.Contains(event.Args, "list")
+	suite.NotEmpty(event.ArgsHash)
+	suite.Equal(0, event.ExitCode)
+	suite.Equal(int64(1500), event.DurationMS)
+	suite.Equal("stdio", event.Caller)
+	suite.Empty(event.Error)
 }
 
-func TestCLIBridgeTestSuite(t *testing.T) {
-	suite.Run(t, new(CLIBridgeTestSuite))
+// TestExecuteToolCommandAuditsFailure tests a command that cannot run is audited
+func (suite *CLIBridgeTestSuite) TestExecuteToolCommandAuditsFailure() {
+	sink := &recordingSink{}
+	suite.bridge.SetAuditSink(sink)
+	suite.expectExecutionLogs()
+	suite.executor.On("Execute", mock.Anything, mock.Anything).
+		Return((*ExecutionResponse)(nil), errTestCommandNotAllowed).Once()
+
+	_, err := suite.bridge.ExecuteToolCommand(context.Background(), "invoice_list", nil)
+	suite.Require().Error(err)
+
+	suite.Require().Len(sink.events, 1)
+	suite.Equal(-1, sink.events[0].ExitCode)
+	suite.Equal("command not allowed", sink.events[0].Error)
 }
 
-// TestGetFloatValue tests the getFloatValue helper
-func TestGetFloatValue(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    interface{}
-		expected float64
-		ok       bool
-	}{
-		{"Float64", float64(3.14), 3.14, true},
-		{"Float32", float32(2.5), 2.5, true},
-		{"Int", int(42), 42.0, true},
-		{"Int64", int64(100), 100.0, true},
-		{"StringFloat", "3.14", 3.14, true},
-		{"StringInt", "42", 42.0, true},
-		{"EmptyString", "", 0, false},
-		{"WhitespaceString", "   ", 0, false},
-		{"InvalidString", "not a number", 0, false},
-		{"NilValue", nil, 0, false},
-		{"BoolValue", true, 0, false},
-	}
+// TestExecuteToolCommandAuditsPolicyDenial tests a command refused by the command policy is audited as denied
+func (suite *CLIBridgeTestSuite) TestExecuteToolCommandAuditsPolicyDenial() {
+	sink := &recordingSink{}
+	suite.bridge.SetAuditSink(sink)
+	suite.expectExecutionLogs()
+	denied := fmt.Errorf("command validation failed: %w", testPolicy().Check("go-invoice", []string{"client", "delete"}))
+	suite.executor.On("Execute", mock.Anything, mock.Anything).
+		Return((*ExecutionResponse)(nil), denied).Once()
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, ok := getFloatValue(tt.input)
-			if ok != tt.ok {
-				t.Errorf("getFloatValue(%v) ok = %v, want %v", tt.input, ok, tt.ok)
-			}
-			if ok && result != tt.expected {
-				t.Errorf("getFloatValue(%v) = %v, want %v", tt.input, result, tt.expected)
-			}
-		})
-	}
+	_, err := suite.bridge.ExecuteToolCommand(context.Background(), "client_delete", map[string]interface{}{"client_id": "C1"})
+	suite.Require().ErrorIs(err, ErrPolicyViolation)
+
+	suite.Require().Len(sink.events, 1)
+	suite.True(sink.events[0].Denied)
+	suite.Contains(sink.events[0].Error, "is denied by rule")
 }
 
-// TestGetIntValue tests the getIntValue helper
-func TestGetIntValue(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    interface{}
-		expected int
-		ok       bool
-	}{
-		{"Int", int(42), 42, true},
-		{"Int64", int64(100), 100, true},
-		{"Float64", float64(3.9), 3, true}, // Truncated
-		{"StringInt", "42", 42, true},
-		{"EmptyString", "", 0, false},
-		{"WhitespaceString", "   ", 0, false},
-		{"InvalidString", "not a number", 0, false},
-		{"NilValue", nil, 0, false},
-		{"BoolValue", true, 0, false},
-	}
+// TestExecuteToolCommandInheritsDeclaredEnvironment tests a tool's declared variables reach the request
+func (suite *CLIBridgeTestSuite) TestExecuteToolCommandInheritsDeclaredEnvironment() {
+	suite.expectExecutionLogs()
+	suite.bridge.toolCommands["config_show"].Environment = []string{"XDG_CONFIG_HOME"}
+	suite.executor.On("Execute", mock.Anything, mock.MatchedBy(func(req *ExecutionRequest) bool {
+		return len(req.InheritEnv) == 1 && req.InheritEnv[0] == "XDG_CONFIG_HOME"
+	})).Return(&ExecutionResponse{ExitCode: 0}, nil).Once()
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, ok := getIntValue(tt.input)
-			if ok != tt.ok {
-				t.Errorf("getIntValue(%v) ok = %v, want %v", tt.input, ok, tt.ok)
-			}
-			if ok && result != tt.expected {
-				t.Errorf("getIntValue(%v) = %v, want %v", tt.input, result, tt.expected)
-			}
-		})
-	}
+	_, err := suite.bridge.ExecuteToolCommand(context.Background(), "config_show", nil)
+	suite.Require().NoError(err)
+	suite.executor.AssertExpectations(suite.T())
+}
+
+// TestExecuteToolCommandDryRun tests that dry-run mode reports mutating commands instead of running them
+func (suite *CLIBridgeTestSuite) TestExecuteToolCommandDryRun() {
+	sink := &recordingSink{}
+	suite.bridge.SetAuditSink(sink)
+	suite.bridge.SetDryRun(true)
+	suite.expectExecutionLogs()
+
+	resp, err := suite.bridge.ExecuteToolCommand(context.Background(), "invoice_create", map[string]interface{}{
+		"client_name": "Acme Corp",
+		"tax_rate":    0.1,
+	})
+	suite.Require().NoError(err)
+	suite.executor.AssertNotCalled(suite.T(), "Execute", mock.Anything, mock.Anything)
+
+	var result dryRunResult
+	suite.Require().NoError(json.Unmarshal([]byte(resp.Stdout), &result))
+	suite.True(result.DryRun)
+	suite.Equal("invoice_create", result.Tool)
+	suite.Equal("go-invoice", result.Command)
+	suite.Subset(result.Args, []string{"invoice", "create", "--client", "Acme Corp", "--tax-rate", "0.1"})
+	suite.Equal(0, resp.ExitCode)
+
+	suite.Require().Len(sink.events, 1)
+	suite.True(sink.events[0].DryRun)
+}
+
+// TestExecuteToolCommandDryRunReadOnly tests that read-only tools still run in dry-run mode
+func (suite *CLIBridgeTestSuite) TestExecuteToolCommandDryRunReadOnly() {
+	suite.bridge.SetDryRun(true)
+	suite.expectExecutionLogs()
+	suite.executor.On("Execute", mock.Anything, mock.Anything).
+		Return(&ExecutionResponse{ExitCode: 0, Stdout: "[]"}, nil).Once()
+
+	resp, err := suite.bridge.ExecuteToolCommand(context.Background(), "invoice_list", nil)
+	suite.Require().NoError(err)
+	suite.Equal("[]", resp.Stdout)
+	suite.executor.AssertExpectations(suite.T())
+}
+
+// expectExecutionLogs allows the log calls made around a command execution
+func (suite *CLIBridgeTestSuite) expectExecutionLogs() {
+	suite.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	suite.logger.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+}
+
+// TestBridgeErrorError tests BridgeError.Error() method
+func (suite *CLIBridgeTestSuite) TestBridgeErrorError() {
+	err := &BridgeError{Op: testStr, Msg: "test message"}
+	suite.Equal("bridge test: test message", err.Error())
+}
+
+func TestCLIBridgeTestSuite(t *testing.T) {
+	suite.Run(t, new(CLIBridgeTestSuite))
 }
 
 // TestBridgeErrorVariables tests that error variables are properly defined
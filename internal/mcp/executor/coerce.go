@@ -0,0 +1,292 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Tool input coercion
+//
+// MCP tool input is decoded JSON, so values arrive as strings, float64 numbers, booleans,
+// arrays and objects, while the go-invoice CLI takes string arguments. Each build*Args
+// function maps input fields to flags through an argList, which converts every value by
+// the same rules:
+//
+//   - string: passed as the flag value unchanged; an empty string omits the flag
+//   - number: the shortest decimal that round-trips (0.1 → "0.1", 8 → "8", 0.125 → "0.125"),
+//     so rates and tax rates reach the CLI unrounded; numeric strings such as "0.1" are
+//     accepted too
+//   - integer: as number, but a fraction such as 2.5 is rejected
+//   - boolean: true adds the bare flag (--force) and false omits it; flags with a negative
+//     form (--no-header) are added when the value is false instead. "true" and "false"
+//     strings are accepted.
+//   - array of scalars: the flag is repeated once per element, each coerced as above
+//   - array of objects: each object's fields are mapped in turn, so every element adds
+//     its own group of flags, e.g. --hours 8 --rate 125 per work item
+//   - missing or null: the flag is omitted so the CLI applies its own default
+//
+// A value of the wrong type, such as "abc" for a number, fails with ErrInvalidToolInput
+// naming the field rather than being dropped silently.
+
+// argList accumulates CLI arguments from tool input. The first coercion error is kept
+// and returned by build; later calls after an error are no-ops.
+type argList struct {
+	input map[string]interface{}
+	path  string // Field path prefix for errors, e.g. "work_items[0]."
+	args  []string
+	err   error
+}
+
+// newArgList starts an argument list for input with the given leading arguments
+func newArgList(input map[string]interface{}, base ...string) *argList {
+	return &argList{input: input, args: append([]string(nil), base...)}
+}
+
+// add appends arguments as they are
+func (l *argList) add(args ...string) {
+	l.args = append(l.args, args...)
+}
+
+// str adds flag with the field's value when it is a non-empty string. Numbers are
+// formatted as by number.
+func (l *argList) str(field, flag string) {
+	value, ok := l.lookup(field)
+	if !ok {
+		return
+	}
+	s, ok := coerceString(value)
+	if !ok {
+		l.fail(field, "a string", value)
+		return
+	}
+	if s != "" {
+		l.add(flag, s)
+	}
+}
+
+// number adds flag with the field's value formatted as a decimal
+func (l *argList) number(field, flag string) {
+	value, ok := l.lookup(field)
+	if !ok {
+		return
+	}
+	n, ok, valid := coerceNumber(value)
+	if !valid {
+		l.fail(field, "a number", value)
+		return
+	}
+	if ok {
+		l.add(flag, formatNumber(n))
+	}
+}
+
+// integer adds flag with the field's value, which must be a whole number
+func (l *argList) integer(field, flag string) {
+	value, ok := l.lookup(field)
+	if !ok {
+		return
+	}
+	n, ok, valid := coerceNumber(value)
+	if !valid || (ok && n != math.Trunc(n)) {
+		l.fail(field, "a whole number", value)
+		return
+	}
+	if ok {
+		l.add(flag, strconv.FormatInt(int64(n), 10))
+	}
+}
+
+// flag adds flag when the field is true and reports whether it did
+func (l *argList) flag(field, flag string) bool {
+	set, value := l.boolean(field)
+	if set && value {
+		l.add(flag)
+		return true
+	}
+	return false
+}
+
+// negatedFlag adds flag when the field is explicitly false, for options such as
+// has_header that the CLI turns off with --no-header
+func (l *argList) negatedFlag(field, flag string) {
+	if set, value := l.boolean(field); set && !value {
+		l.add(flag)
+	}
+}
+
+// choice adds onFlag when the field is true and offFlag when it is false, reporting
+// whether either was added
+func (l *argList) choice(field, onFlag, offFlag string) bool {
+	set, value := l.boolean(field)
+	switch {
+	case !set:
+		return false
+	case value:
+		l.add(onFlag)
+	default:
+		l.add(offFlag)
+	}
+	return true
+}
+
+// repeated adds flag once per element of an array field. A single scalar is treated as a
+// one-element array.
+func (l *argList) repeated(field, flag string) {
+	value, ok := l.lookup(field)
+	if !ok {
+		return
+	}
+
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		l.str(field, flag)
+		return
+	}
+	for i := range list.Len() {
+		element := list.Index(i).Interface()
+		s, ok := coerceString(element)
+		if !ok {
+			l.fail(fmt.Sprintf("%s[%d]", field, i), "a string or number", element)
+			return
+		}
+		if s != "" {
+			l.add(flag, s)
+		}
+	}
+}
+
+// objects maps each object in an array field with each, appending the flags it adds
+func (l *argList) objects(field string, each func(item *argList)) {
+	value, ok := l.lookup(field)
+	if !ok {
+		return
+	}
+
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		l.fail(field, "an array", value)
+		return
+	}
+	for i := range list.Len() {
+		element := list.Index(i).Interface()
+		object, ok := element.(map[string]interface{})
+		if !ok {
+			l.fail(fmt.Sprintf("%s[%d]", field, i), "an object", element)
+			return
+		}
+
+		item := &argList{input: object, path: fmt.Sprintf("%s%s[%d].", l.path, field, i)}
+		each(item)
+		if item.err != nil {
+			l.err = item.err
+			return
+		}
+		l.add(item.args...)
+	}
+}
+
+// build returns the arguments, or the first coercion error
+func (l *argList) build() ([]string, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.args, nil
+}
+
+// lookup returns the field's value, or false when it is missing or null or an earlier
+// field already failed
+func (l *argList) lookup(field string) (interface{}, bool) {
+	if l.err != nil {
+		return nil, false
+	}
+	value, ok := l.input[field]
+	return value, ok && value != nil
+}
+
+// boolean reads a boolean field, reporting whether it was set
+func (l *argList) boolean(field string) (set, value bool) {
+	raw, ok := l.lookup(field)
+	if !ok {
+		return false, false
+	}
+	switch typed := raw.(type) {
+	case bool:
+		return true, typed
+	case string:
+		if strings.TrimSpace(typed) == "" {
+			return false, false
+		}
+		if parsed, err := strconv.ParseBool(strings.TrimSpace(typed)); err == nil {
+			return true, parsed
+		}
+	}
+	l.fail(field, "a boolean", raw)
+	return false, false
+}
+
+// fail records a coercion error for field
+func (l *argList) fail(field, expected string, value interface{}) {
+	if l.err == nil {
+		l.err = fmt.Errorf("%w: %s%s must be %s, got %T %v", ErrInvalidToolInput, l.path, field, expected, value, value)
+	}
+}
+
+// coerceString converts a string or number to a flag value
+func coerceString(value interface{}) (string, bool) {
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+	if n, ok, valid := coerceNumber(value); valid && ok {
+		return formatNumber(n), true
+	}
+	return "", false
+}
+
+// coerceNumber converts a JSON number, Go number or numeric string. ok is false for an
+// empty string, which omits the flag; valid is false for anything that is not a number.
+func coerceNumber(value interface{}) (n float64, ok, valid bool) {
+	switch typed := value.(type) {
+	case float64:
+		n = typed
+	case float32:
+		n = float64(typed)
+	case int:
+		n = float64(typed)
+	case int32:
+		n = float64(typed)
+	case int64:
+		n = float64(typed)
+	case json.Number:
+		parsed, err := typed.Float64()
+		if err != nil {
+			return 0, false, false
+		}
+		n = parsed
+	case string:
+		trimmed := strings.TrimSpace(typed)
+		if trimmed == "" {
+			return 0, false, true
+		}
+		parsed, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil {
+			return 0, false, false
+		}
+		n = parsed
+	default:
+		return 0, false, false
+	}
+
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, false, false
+	}
+	return n, true, true
+}
+
+// formatNumber formats n as the shortest plain decimal that parses back to n
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
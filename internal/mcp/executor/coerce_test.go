@@ -0,0 +1,260 @@
+package executor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCoerceNumber tests the numeric forms accepted for number flags
+func TestCoerceNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected float64
+		ok       bool
+		valid    bool
+	}{
+		{"Float64", float64(3.14), 3.14, true, true},
+		{"Float32", float32(2.5), 2.5, true, true},
+		{"Int", int(42), 42.0, true, true},
+		{"Int64", int64(100), 100.0, true, true},
+		{"JSONNumber", json.Number("0.1"), 0.1, true, true},
+		{"StringFloat", "3.14", 3.14, true, true},
+		{"StringInt", "42", 42.0, true, true},
+		{"EmptyString", "", 0, false, true},
+		{"WhitespaceString", "   ", 0, false, true},
+		{"InvalidString", "not a number", 0, false, false},
+		{"PartlyNumericString", "12abc", 0, false, false},
+		{"NaNString", "NaN", 0, false, false},
+		{"NilValue", nil, 0, false, false},
+		{"BoolValue", true, 0, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok, valid := coerceNumber(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.valid, valid)
+			if ok {
+				assert.InDelta(t, tt.expected, result, 1e-9)
+			}
+		})
+	}
+}
+
+// TestFormatNumber tests that numbers reach the CLI unrounded
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "0.1", formatNumber(0.1))
+	assert.Equal(t, "8", formatNumber(8))
+	assert.Equal(t, "0.125", formatNumber(0.125))
+	assert.Equal(t, "1500000", formatNumber(1.5e6))
+	assert.Equal(t, "-2.5", formatNumber(-2.5))
+}
+
+// TestArgListRules tests each coercion rule on a decoded JSON payload
+func TestArgListRules(t *testing.T) {
+	var input map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"name": "Acme",
+		"empty": "",
+		"none": null,
+		"rate": 0.1,
+		"rate_text": "0.125",
+		"days": 30,
+		"force": true,
+		"quiet": false,
+		"quiet_text": "true",
+		"header": false,
+		"tags": ["a", "b", 3],
+		"items": [{"hours": 8, "note": "x"}, {"hours": 1.5}]
+	}`), &input))
+
+	args := newArgList(input, "base")
+	args.str("name", "--name")
+	args.str("empty", "--empty")
+	args.str("none", "--none")
+	args.str("missing", "--missing")
+	args.number("rate", "--rate")
+	args.number("rate_text", "--rate-text")
+	args.integer("days", "--days")
+	args.flag("force", "--force")
+	args.flag("quiet", "--quiet")
+	args.flag("quiet_text", "--quiet-text")
+	args.negatedFlag("header", "--no-header")
+	args.choice("quiet", "--on", "--off")
+	args.repeated("tags", "--tag")
+	args.objects("items", func(item *argList) {
+		item.number("hours", "--hours")
+		item.str("note", "--note")
+	})
+
+	argv, err := args.build()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"base",
+		"--name", "Acme",
+		"--rate", "0.1",
+		"--rate-text", "0.125",
+		"--days", "30",
+		"--force",
+		"--quiet-text",
+		"--no-header",
+		"--off",
+		"--tag", "a", "--tag", "b", "--tag", "3",
+		"--hours", "8", "--note", "x",
+		"--hours", "1.5",
+	}, argv)
+}
+
+// TestArgListErrors tests that mistyped values fail with the field path instead of being dropped
+func TestArgListErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input map[string]interface{}
+		build func(args *argList)
+		field string
+	}{
+		{
+			name:  "NumberFromText",
+			input: map[string]interface{}{"rate": "abc"},
+			build: func(args *argList) { args.number("rate", "--rate") },
+			field: "rate",
+		},
+		{
+			name:  "IntegerFraction",
+			input: map[string]interface{}{"days": 2.5},
+			build: func(args *argList) { args.integer("days", "--days") },
+			field: "days",
+		},
+		{
+			name:  "BoolFromText",
+			input: map[string]interface{}{"force": "yes please"},
+			build: func(args *argList) { args.flag("force", "--force") },
+			field: "force",
+		},
+		{
+			name:  "StringFromBool",
+			input: map[string]interface{}{"name": true},
+			build: func(args *argList) { args.str("name", "--name") },
+			field: "name",
+		},
+		{
+			name:  "NestedItem",
+			input: map[string]interface{}{"items": []interface{}{map[string]interface{}{"hours": 1}, map[string]interface{}{"hours": "lots"}}},
+			build: func(args *argList) {
+				args.objects("items", func(item *argList) { item.number("hours", "--hours") })
+			},
+			field: "items[1].hours",
+		},
+		{
+			name:  "ObjectsNotArray",
+			input: map[string]interface{}{"items": "none"},
+			build: func(args *argList) {
+				args.objects("items", func(item *argList) { item.number("hours", "--hours") })
+			},
+			field: "items",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := newArgList(tt.input)
+			tt.build(args)
+			argv, err := args.build()
+			require.ErrorIs(t, err, ErrInvalidToolInput)
+			assert.Contains(t, err.Error(), tt.field+" must be")
+			assert.Nil(t, argv)
+		})
+	}
+}
+
+// TestToolArgvByCategory tests the argv built for a representative tool in each category
+func TestToolArgvByCategory(t *testing.T) {
+	bridge := NewCLIBridge(new(MockLogger), new(MockCommandExecutor), new(MockFileHandler), "")
+	config := bridge.getConfigArgs()
+	withConfig := func(args ...string) []string { return append(append([]string(nil), config...), args...) }
+
+	tests := []struct {
+		tool     string
+		payload  string
+		expected []string
+	}{
+		// Invoice management
+		{
+			tool:    "invoice_create",
+			payload: `{"client_name": "Acme", "tax_rate": 0.1, "work_items": [{"date": "2025-08-01", "hours": 7.5, "rate": 0.125, "description": "Review"}], "create_client_if_missing": true, "new_client_email": "a@acme.com"}`,
+			expected: withConfig("--client", "Acme", "--tax-rate", "0.1",
+				"--add-item-description", "Review", "--add-item-hours", "7.5", "--add-item-rate", "0.125", "--add-item-date", "2025-08-01",
+				"--create-client", "--new-client-email", "a@acme.com"),
+		},
+		{
+			tool:     "invoice_delete",
+			payload:  `{"invoice_number": "INV-001", "hard_delete": true, "force": false}`,
+			expected: withConfig("INV-001", "--hard"),
+		},
+		{
+			tool:     "invoice_remove_item",
+			payload:  `{"invoice_id": "INV-001", "item_index": 2, "confirm": "true"}`,
+			expected: withConfig("INV-001", "--index", "2", "--yes"),
+		},
+		// Client management
+		{
+			tool:     "client_list",
+			payload:  `{"active": false, "name_search": "acme"}`,
+			expected: withConfig("--output", "json", "--inactive", "--search", "acme"),
+		},
+		{
+			tool:     "client_update",
+			payload:  `{"client_id": "c1", "phone": 5551234, "active": true}`,
+			expected: withConfig("c1", "--phone", "5551234", "--activate"),
+		},
+		// Data import
+		{
+			tool:    "import_csv",
+			payload: `{"file_path": "/tmp/hours.csv", "client_name": "Acme", "due_days": 30, "default_rate": 95.5, "has_header": false, "dry_run": true}`,
+			expected: withConfig("/tmp/hours.csv", "--client", "Acme", "--due-days", "30", "--default-rate", "95.5",
+				"--dry-run", "--no-header", "--progress", "json"),
+		},
+		{
+			tool:     "import_validate",
+			payload:  `{"file_path": "/tmp/hours.csv", "max_hours_per_day": 10, "min_rate": 50, "check_weekends": true}`,
+			expected: withConfig("/tmp/hours.csv", "--max-hours", "10", "--min-rate", "50", "--check-weekends"),
+		},
+		// Document generation and export
+		{
+			tool:     "generate_html",
+			payload:  `{"invoice_number": "INV-001", "template": "default", "include_logo": true}`,
+			expected: withConfig("INV-001", "--template", "default"),
+		},
+		{
+			tool:     "export_data",
+			payload:  `{"export_type": "clients", "format": "csv", "status": "paid"}`,
+			expected: []string{"--type", "clients", "--format", "csv", "--status", "paid"},
+		},
+		// Configuration
+		{
+			tool:     "config_show",
+			payload:  `{"section": "business", "show_defaults": true}`,
+			expected: []string{"--output", "json", "--section", "business", "--show-defaults"},
+		},
+		{
+			tool:     "config_init",
+			payload:  `{"output_path": "/tmp/.env", "force": true, "interactive": false}`,
+			expected: []string{"--output", "/tmp/.env", "--force"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			var input map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(tt.payload), &input))
+
+			argv, err := bridge.toolCommands[tt.tool].BuildArgs(input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, argv)
+		})
+	}
+}
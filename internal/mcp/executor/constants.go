@@ -4,4 +4,5 @@ const (
 	subCmdInvoice = "invoice"
 	opValidate    = "validate"
 	keyInvoiceID  = "invoice_id"
+	metaDryRun    = "dryRun"
 )
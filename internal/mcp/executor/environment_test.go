@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// envCLIScript stands in for the CLI and prints the environment it was started with
+const envCLIScript = `#!/bin/sh
+env
+`
+
+// runEnvCLI runs the env-printing CLI through a SecureExecutor and returns the child's
+// environment
+func runEnvCLI(t *testing.T, whitelist []string, req *ExecutionRequest) map[string]string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the mock CLI is a shell script")
+	}
+
+	script := filepath.Join(t.TempDir(), "env-cli")
+	require.NoError(t, os.WriteFile(script, []byte(envCLIScript), 0o700)) //nolint:gosec // The script must be executable
+
+	validator := new(MockCommandValidator)
+	validator.On("ValidateCommand", mock.Anything, script, mock.Anything).Return(nil)
+	validator.On("ValidateEnvironment", mock.Anything, mock.Anything).Return(nil)
+
+	sandbox := SandboxConfig{
+		AllowedCommands:      []string{script},
+		EnvironmentWhitelist: whitelist,
+		MaxExecutionTime:     time.Minute,
+	}
+	executor := NewSecureExecutor(newPermissiveLogger(), validator, sandbox, new(MockFileHandler))
+
+	req.Command = script
+	req.Timeout = 10 * time.Second
+	resp, err := executor.Execute(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, 0, resp.ExitCode, resp.Stderr)
+
+	env := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(resp.Stdout), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+func TestExecuteFiltersEnvironment(t *testing.T) {
+	t.Setenv("HOME", "/home/invoicer")
+	t.Setenv("GO_INVOICE_TEST_API_TOKEN", "s3cret")
+	t.Setenv("GO_INVOICE_TEST_CONFIGURED", "configured")
+	t.Setenv("GO_INVOICE_TEST_DECLARED", "declared")
+
+	t.Run("SecretsStripped", func(t *testing.T) {
+		env := runEnvCLI(t, nil, &ExecutionRequest{})
+
+		assert.NotContains(t, env, "GO_INVOICE_TEST_API_TOKEN")
+		assert.NotContains(t, env, "GO_INVOICE_TEST_CONFIGURED")
+		assert.NotContains(t, env, "GO_INVOICE_TEST_DECLARED")
+		assert.Equal(t, os.Getenv("PATH"), env["PATH"], "PATH is always passed")
+		assert.Equal(t, "/home/invoicer", env["HOME"], "HOME is always passed")
+	})
+
+	t.Run("ConfiguredAndDeclared", func(t *testing.T) {
+		env := runEnvCLI(t, []string{"GO_INVOICE_TEST_CONFIGURED"}, &ExecutionRequest{
+			InheritEnv: []string{"GO_INVOICE_TEST_DECLARED"},
+		})
+
+		assert.Equal(t, "configured", env["GO_INVOICE_TEST_CONFIGURED"])
+		assert.Equal(t, "declared", env["GO_INVOICE_TEST_DECLARED"])
+		assert.NotContains(t, env, "GO_INVOICE_TEST_API_TOKEN")
+	})
+
+	t.Run("ExplicitValues", func(t *testing.T) {
+		env := runEnvCLI(t, []string{"GO_INVOICE_TEST_CONFIGURED"}, &ExecutionRequest{
+			Environment: map[string]string{
+				"GO_INVOICE_TEST_CONFIGURED": "override",
+				"GO_INVOICE_TEST_API_TOKEN":  "injected",
+			},
+		})
+
+		assert.Equal(t, "override", env["GO_INVOICE_TEST_CONFIGURED"])
+		assert.NotContains(t, env, "GO_INVOICE_TEST_API_TOKEN")
+	})
+}
+
+func TestBuildEnvironmentHasNoDuplicates(t *testing.T) {
+	t.Setenv("HOME", "/home/invoicer")
+	executor := NewSecureExecutor(newPermissiveLogger(), new(MockCommandValidator), SandboxConfig{
+		EnvironmentWhitelist: []string{"PATH", "HOME"},
+	}, new(MockFileHandler))
+
+	env := executor.buildEnvironment(map[string]string{"HOME": "/tmp/home"}, []string{"HOME"})
+
+	var homes []string
+	for _, entry := range env {
+		if strings.HasPrefix(entry, "HOME=") {
+			homes = append(homes, entry)
+		}
+	}
+	assert.Equal(t, []string{"HOME=/tmp/home"}, homes)
+}
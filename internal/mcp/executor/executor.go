@@ -2,14 +2,14 @@
 package executor
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strings"
+	"slices"
 	"sync"
 	"time"
 )
@@ -33,6 +33,29 @@ var (
 	ErrInvalidWorkDir    = &Error{Op: opValidate, Msg: "invalid working directory"}
 )
 
+// processWaitDelay bounds how long Execute waits for output after a command is killed, in
+// case a process outside its group still holds the output pipes open
+const processWaitDelay = 2 * time.Second
+
+// alwaysInheritedEnv are passed to every command whatever the sandbox allowlist says
+var alwaysInheritedEnv = []string{"PATH", "HOME"}
+
+// TimeoutError reports a command that was killed at its deadline, as opposed to one that
+// ran and failed. It matches ErrTimeout with errors.Is.
+type TimeoutError struct {
+	Command string
+	Elapsed time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s: %s killed after %s", ErrTimeout.Error(), e.Command, e.Elapsed.Round(time.Millisecond))
+}
+
+// Is reports whether target is ErrTimeout
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
 // SecureExecutor implements the CommandExecutor interface with security features.
 type SecureExecutor struct {
 	logger      Logger
@@ -133,12 +156,16 @@ func (e *SecureExecutor) Execute(ctx context.Context, req *ExecutionRequest) (*E
 	// Build and execute the command
 	cmd := exec.CommandContext(execCtx, req.Command, req.Args...) //nolint:gosec // Command execution is the intended functionality
 	cmd.Dir = workDir
-	cmd.Env = e.buildEnvironment(req.Environment)
+	cmd.Env = e.buildEnvironment(req.Environment, req.InheritEnv)
+	killProcessGroupOnCancel(cmd)
+	cmd.WaitDelay = processWaitDelay
 
-	// Capture output
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	// Capture output; progress lines on stderr go to the progress callback instead
+	stdout := newCappedBuffer(e.sandbox.MaxOutputSize)
+	stderr := newCappedBuffer(e.sandbox.MaxOutputSize)
+	stderrWriter := &progressLineWriter{dst: stderr, callback: req.ProgressCallback}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderrWriter
 
 	// Log command execution
 	e.logger.Info("executing command",
@@ -159,19 +186,35 @@ func (e *SecureExecutor) Execute(ctx context.Context, req *ExecutionRequest) (*E
 	// Execute the command
 	err = cmd.Run()
 	duration := time.Since(start)
+	_ = stderrWriter.Flush() // Writes to a cappedBuffer cannot fail
 
-	// Check output size limits
-	if int64(stdout.Len()) > e.sandbox.MaxOutputSize || int64(stderr.Len()) > e.sandbox.MaxOutputSize {
-		return nil, ErrOutputTooLarge
+	// A command stopped at its deadline or by the caller did not fail on its own
+	if err != nil && execCtx.Err() != nil {
+		if ctxErr := ctx.Err(); errors.Is(ctxErr, context.Canceled) {
+			return nil, ctxErr
+		}
+		e.logger.Error("command timed out",
+			"command", req.Command,
+			"timeout", timeout,
+			"duration", duration,
+		)
+		return nil, &TimeoutError{Command: req.Command, Elapsed: duration}
 	}
 
 	// Build response
 	response := &ExecutionResponse{
-		ExitCode: 0,
-		Stdout:   stdout.String(),
-		Stderr:   stderr.String(),
-		Duration: duration,
-		Metadata: make(map[string]interface{}),
+		ExitCode:  0,
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		Duration:  duration,
+		Truncated: stdout.Truncated() || stderr.Truncated(),
+		Metadata:  make(map[string]interface{}),
+	}
+	if response.Truncated {
+		e.logger.Warn("command output truncated",
+			"command", req.Command,
+			"maxOutputSize", stdout.max,
+		)
 	}
 
 	// Handle execution error
@@ -179,9 +222,6 @@ func (e *SecureExecutor) Execute(ctx context.Context, req *ExecutionRequest) (*E
 		var exitErr *exec.ExitError
 		if errors.As(err, &exitErr) {
 			response.ExitCode = exitErr.ExitCode()
-		} else if errors.Is(err, context.DeadlineExceeded) {
-			response.ExitCode = -1
-			response.Error = ErrTimeout.Error()
 		} else {
 			response.ExitCode = -1
 			response.Error = err.Error()
@@ -232,6 +272,19 @@ func (e *SecureExecutor) ValidateCommand(ctx context.Context, command string, ar
 	return e.validator.ValidateCommand(ctx, command, args)
 }
 
+// SetAllowedCommands replaces the list of allowed commands. Commands already executing
+// are not affected.
+func (e *SecureExecutor) SetAllowedCommands(commands []string) {
+	allowedCmds := make(map[string]bool, len(commands))
+	for _, cmd := range commands {
+		allowedCmds[cmd] = true
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.allowedCmds = allowedCmds
+}
+
 // GetAllowedCommands returns the list of allowed commands.
 func (e *SecureExecutor) GetAllowedCommands(ctx context.Context) ([]string, error) {
 	select {
@@ -251,47 +304,39 @@ func (e *SecureExecutor) GetAllowedCommands(ctx context.Context) ([]string, erro
 	return commands, nil
 }
 
-// buildEnvironment builds the environment for command execution.
-func (e *SecureExecutor) buildEnvironment(additional map[string]string) []string {
-	// Start with a clean environment
-	env := make([]string, 0)
-
-	// Add whitelisted environment variables from current environment
-	for _, key := range e.sandbox.EnvironmentWhitelist {
-		if value, exists := os.LookupEnv(key); exists {
-			env = append(env, fmt.Sprintf("%s=%s", key, value))
+// buildEnvironment returns the environment of a command. Nothing is inherited from the
+// server except PATH, HOME, the sandbox allowlist and the inherit names a tool declares,
+// so secrets in the server's environment never reach the CLI. additional values are
+// added when allowlisted and replace inherited ones.
+func (e *SecureExecutor) buildEnvironment(additional map[string]string, inherit []string) []string {
+	values := make(map[string]string)
+	var names []string
+	set := func(key, value string) {
+		if _, exists := values[key]; !exists {
+			names = append(names, key)
 		}
+		values[key] = value
 	}
 
-	// Add additional environment variables
-	for key, value := range additional {
-		// Check if key is in whitelist
-		allowed := false
-		for _, whitelisted := range e.sandbox.EnvironmentWhitelist {
-			if key == whitelisted {
-				allowed = true
-				break
+	for _, list := range [][]string{alwaysInheritedEnv, e.sandbox.EnvironmentWhitelist, inherit} {
+		for _, key := range list {
+			if value, exists := os.LookupEnv(key); exists {
+				set(key, value)
 			}
 		}
-		if allowed {
-			env = append(env, fmt.Sprintf("%s=%s", key, value))
-		}
 	}
 
-	// Always add PATH if not already present
-	hasPath := false
-	for _, e := range env {
-		if strings.HasPrefix(e, "PATH=") {
-			hasPath = true
-			break
-		}
-	}
-	if !hasPath {
-		if path, exists := os.LookupEnv("PATH"); exists {
-			env = append(env, fmt.Sprintf("PATH=%s", path))
+	// Add additional environment variables
+	for _, key := range slices.Sorted(maps.Keys(additional)) {
+		if slices.Contains(e.sandbox.EnvironmentWhitelist, key) {
+			set(key, additional[key])
 		}
 	}
 
+	env := make([]string, 0, len(names))
+	for _, key := range names {
+		env = append(env, key+"="+values[key])
+	}
 	return env
 }
 
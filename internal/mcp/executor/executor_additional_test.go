@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -387,3 +388,18 @@ func TestExecutorErrorVariables(t *testing.T) {
 		})
 	}
 }
+
+// TestTimeoutError tests that a killed command matches ErrTimeout but not other failures
+func TestTimeoutError(t *testing.T) {
+	err := fmt.Errorf("tool failed: %w", &TimeoutError{Command: "go-invoice", Elapsed: 1500 * time.Millisecond})
+
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("errors.Is(%v, ErrTimeout) = false, want true", err)
+	}
+	if errors.Is(err, ErrCommandNotAllowed) {
+		t.Errorf("errors.Is(%v, ErrCommandNotAllowed) = true, want false", err)
+	}
+	if got, want := err.Error(), "tool failed: executor execute: command execution timeout: go-invoice killed after 1.5s"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
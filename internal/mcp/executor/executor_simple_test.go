@@ -60,7 +60,7 @@ func TestSecureExecutor_BasicFunctionality(t *testing.T) {
 		env := executor.buildEnvironment(map[string]string{
 			"HOME":      "/home/test",
 			"DANGEROUS": "value", // Should be filtered out
-		})
+		}, nil)
 
 		// Convert to map for easier checking
 		envMap := make(map[string]string)
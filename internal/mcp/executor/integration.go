@@ -3,9 +3,12 @@ package executor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/mrz1836/go-invoice/internal/mcp/audit"
 	"github.com/mrz1836/go-invoice/internal/mcp/tools"
 	"github.com/mrz1836/go-invoice/internal/mcp/types"
 )
@@ -72,6 +75,21 @@ func NewMCPExecutorBridge(
 	}
 }
 
+// SetAuditSink sets where command executions are audited. A nil sink disables auditing.
+func (m *MCPExecutorBridge) SetAuditSink(sink audit.AuditSink) {
+	m.bridge.SetAuditSink(sink)
+}
+
+// SetCLIPath changes the go-invoice binary run for later tool calls.
+func (m *MCPExecutorBridge) SetCLIPath(cliPath string) {
+	m.bridge.SetCLIPath(cliPath)
+}
+
+// SetDryRun turns dry-run mode on or off for tool calls; see CLIBridge.SetDryRun.
+func (m *MCPExecutorBridge) SetDryRun(dryRun bool) {
+	m.bridge.SetDryRun(dryRun)
+}
+
 // ExecuteCommand implements the CLIBridge interface.
 func (m *MCPExecutorBridge) ExecuteCommand(ctx context.Context, req *types.CommandRequest) (*types.CommandResponse, error) {
 	select {
@@ -114,6 +132,7 @@ func (m *MCPExecutorBridge) ExecuteCommand(ctx context.Context, req *types.Comma
 
 	// Execute the command
 	execResp, err := m.executor.Execute(ctx, execReq)
+	m.bridge.recordAudit(ctx, "", execReq, execResp, err)
 	if err != nil {
 		return nil, fmt.Errorf("command execution failed: %w", err)
 	}
@@ -165,6 +184,22 @@ func (m *MCPExecutorBridge) PrepareWorkspace(_ context.Context, _ string) (strin
 	return "", func() {}, nil
 }
 
+// errorCodeToolTimeout is the JSON-RPC server error returned when a tool is killed at its
+// timeout, so clients can tell it apart from a CLI failure (-32603)
+const errorCodeToolTimeout = -32001
+
+// Error codes of error content blocks
+const (
+	contentErrorCommandFailed = "command_failed"
+	contentErrorInvalidJSON   = "invalid_json"
+)
+
+// maxErrorDetail limits how much tool output an error block quotes
+const maxErrorDetail = 200
+
+// defaultToolTimeout applies to tools that declare no timeout of their own
+const defaultToolTimeout = 30 * time.Second
+
 // ToolCallHandler handles MCP tool calls using the executor.
 type ToolCallHandler struct {
 	logger       Logger
@@ -172,6 +207,7 @@ type ToolCallHandler struct {
 	toolRegistry *tools.DefaultToolRegistry
 	parser       OutputParser
 	tracker      ProgressTracker
+	maxTimeout   time.Duration
 }
 
 // NewToolCallHandler creates a new tool call handler.
@@ -207,6 +243,24 @@ func NewToolCallHandler(
 	}
 }
 
+// SetMaxTimeout sets the server-wide limit on how long any tool may run, capping each
+// tool's own timeout. Zero leaves tool timeouts uncapped.
+func (h *ToolCallHandler) SetMaxTimeout(maxTimeout time.Duration) {
+	h.maxTimeout = maxTimeout
+}
+
+// toolTimeout returns how long tool may run: its own timeout, capped by the server limit
+func (h *ToolCallHandler) toolTimeout(tool *tools.MCPTool) time.Duration {
+	timeout := tool.Timeout
+	if timeout <= 0 {
+		timeout = defaultToolTimeout
+	}
+	if h.maxTimeout > 0 && timeout > h.maxTimeout {
+		timeout = h.maxTimeout
+	}
+	return timeout
+}
+
 // HandleToolCall processes an MCP tool call request.
 func (h *ToolCallHandler) HandleToolCall(ctx context.Context, req *types.MCPRequest) (*types.MCPResponse, error) {
 	select {
@@ -263,12 +317,27 @@ func (h *ToolCallHandler) HandleToolCall(ctx context.Context, req *types.MCPRequ
 		)
 	}
 
-	// Execute tool via bridge
-	resp, err := h.bridge.bridge.ExecuteToolCommand(ctx, params.Name, params.Arguments)
+	// Reuse a cached result of a read-only tool, otherwise execute tool via bridge. The
+	// CLI process is killed if it outlives the tool's timeout.
+	timeout := h.toolTimeout(toolDef)
+	toolCtx, cancel := context.WithTimeout(ctx, timeout)
+	resp, err := h.executeTool(toolCtx, params.Name, params.Arguments)
+	cancel()
 	if err != nil {
 		if operation != nil {
 			operation.Complete(err)
 		}
+		if errors.Is(err, ErrTimeout) {
+			return &types.MCPResponse{
+				JSONRPC: "2.0",
+				ID:      req.ID,
+				Error: &types.MCPError{
+					Code:    errorCodeToolTimeout,
+					Message: "Tool execution timed out",
+					Data:    fmt.Sprintf("Tool %s did not finish within %s", params.Name, timeout),
+				},
+			}, nil
+		}
 		return &types.MCPResponse{
 			JSONRPC: "2.0",
 			ID:      req.ID,
@@ -301,9 +370,10 @@ func (h *ToolCallHandler) HandleToolCall(ctx context.Context, req *types.MCPRequ
 		}
 	}
 
-	result := types.ToolCallResult{
-		Content: content,
-		IsError: resp.ExitCode != 0,
+	result := types.ToolResult{
+		Content:   content,
+		IsError:   resp.ExitCode != 0 || hasErrorContent(content),
+		Truncated: resp.Truncated,
 	}
 
 	return &types.MCPResponse{
@@ -313,39 +383,60 @@ func (h *ToolCallHandler) HandleToolCall(ctx context.Context, req *types.MCPRequ
 	}, nil
 }
 
-// parseToolOutput parses tool output based on the tool definition.
-func (h *ToolCallHandler) parseToolOutput(ctx context.Context, _ *tools.MCPTool, resp *ExecutionResponse) ([]types.Content, error) {
+// executeTool runs a tool through the CLI bridge, answering from the registry's result
+// cache when the tool is cacheable and a fresh result is available. Only successful
+// results are recorded.
+func (h *ToolCallHandler) executeTool(ctx context.Context, toolName string, input map[string]interface{}) (*ExecutionResponse, error) {
+	if cached, ok := h.toolRegistry.CachedResult(ctx, toolName, input); ok {
+		if resp, ok := cached.(*ExecutionResponse); ok {
+			h.logger.Debug("tool result served from cache", "tool", toolName)
+			return resp, nil
+		}
+	}
+
+	resp, err := h.bridge.bridge.ExecuteToolCommand(ctx, toolName, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.ExitCode == 0 {
+		h.toolRegistry.RecordResult(ctx, toolName, input, resp)
+	}
+	return resp, nil
+}
+
+// parseToolOutput converts a tool's output into content blocks according to the tool's
+// OutputType. A CLI failure, or output of a json tool that is not valid JSON, becomes an
+// error block.
+func (h *ToolCallHandler) parseToolOutput(ctx context.Context, tool *tools.MCPTool, resp *ExecutionResponse) ([]types.Content, error) {
 	// Check for context cancellation
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
 
-	// Parse output generically since OutputSchema is not currently supported
-
 	// Check for error output
 	if resp.ExitCode != 0 {
 		err := h.parser.ExtractError(ctx, resp.Stdout, resp.Stderr, resp.ExitCode)
 		if err != nil {
 			return []types.Content{
-				{
-					Type: "text",
-					Text: fmt.Sprintf("Error: %v\n\nStderr:\n%s", err, resp.Stderr),
-				},
+				errorContent(contentErrorCommandFailed, fmt.Sprintf("Error: %v\n\nStderr:\n%s", err, resp.Stderr), err.Error(), resp.Stderr),
 			}, nil
 		}
 	}
 
-	// Return raw output
 	content := []types.Content{}
-	if resp.Stdout != "" {
+	switch {
+	case resp.ExitCode == 0 && !resp.Truncated && tool != nil && tool.OutputType == tools.OutputTypeJSON:
+		content = append(content, jsonContent(resp.Stdout))
+	case resp.Stdout != "":
 		content = append(content, types.Content{
-			Type: "text",
+			Type: types.ContentTypeText,
 			Text: resp.Stdout,
 		})
 	}
 	if resp.Stderr != "" && resp.ExitCode != 0 {
 		content = append(content, types.Content{
-			Type: "text",
+			Type: types.ContentTypeText,
 			Text: fmt.Sprintf("Error output:\n%s", resp.Stderr),
 		})
 	}
@@ -353,7 +444,7 @@ func (h *ToolCallHandler) parseToolOutput(ctx context.Context, _ *tools.MCPTool,
 	// Add file references if any
 	for _, file := range resp.OutputFiles {
 		content = append(content, types.Content{
-			Type: "text",
+			Type: types.ContentTypeText,
 			Text: fmt.Sprintf("Generated file: %s (size: %d bytes)", file.Path, file.Size),
 		})
 	}
@@ -361,6 +452,56 @@ func (h *ToolCallHandler) parseToolOutput(ctx context.Context, _ *tools.MCPTool,
 	return content, nil
 }
 
+// jsonContent returns stdout of a json tool as a json block, or an error block when it
+// is not a single valid JSON value
+func jsonContent(stdout string) types.Content {
+	var value json.RawMessage
+	if err := json.Unmarshal([]byte(stdout), &value); err != nil {
+		return errorContent(contentErrorInvalidJSON,
+			fmt.Sprintf("Tool output is not valid JSON: %v", err),
+			"tool output is not valid JSON: "+err.Error(),
+			truncateDetail(stdout))
+	}
+
+	return types.Content{
+		Type: types.ContentTypeJSON,
+		Text: strings.TrimSpace(stdout),
+		Data: value,
+	}
+}
+
+// errorContent builds an error block
+func errorContent(code, text, message, detail string) types.Content {
+	return types.Content{
+		Type: types.ContentTypeError,
+		Text: text,
+		Error: &types.ContentError{
+			Code:    code,
+			Message: message,
+			Detail:  detail,
+		},
+	}
+}
+
+// truncateDetail shortens output quoted in an error to maxErrorDetail bytes
+func truncateDetail(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) <= maxErrorDetail {
+		return output
+	}
+	return strings.ToValidUTF8(output[:maxErrorDetail], "") + "..."
+}
+
+// hasErrorContent reports whether any block describes a failure
+func hasErrorContent(content []types.Content) bool {
+	for _, block := range content {
+		if block.Type == types.ContentTypeError {
+			return true
+		}
+	}
+	return false
+}
+
 // Helper functions
 
 func convertParams(from, to interface{}) error {
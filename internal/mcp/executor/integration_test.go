@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -407,6 +408,166 @@ func (s *ToolCallHandlerTestSuite) TestHandleToolCallUnknownTool() {
 	s.Contains(resp.Error.Data.(string), "Unknown tool")
 }
 
+func (s *ToolCallHandlerTestSuite) TestHandleToolCallUsesResultCache() {
+	ctx := context.Background()
+	s.allowAnyLogs()
+
+	for _, tool := range tools.CreateInvoiceManagementTools() {
+		s.Require().NoError(s.toolRegistry.RegisterTool(ctx, tool))
+	}
+	s.toolRegistry.EnableResultCache(tools.DefaultResultCacheSize)
+
+	s.mockValidator.On("ValidateAgainstSchema", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	s.tracker.On("StartOperation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return((*Operation)(nil), nil)
+	s.executor.On("Execute", mock.Anything, mock.Anything).
+		Return(&ExecutionResponse{ExitCode: 0, Stdout: "[]"}, nil).Once()
+
+	handler := NewToolCallHandler(s.logger, s.bridge, s.toolRegistry, s.parser, s.tracker)
+	req := &types.MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params: map[string]interface{}{
+			"name":      "invoice_list",
+			"arguments": map[string]interface{}{"status": "paid"},
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := handler.HandleToolCall(ctx, req)
+		s.Require().NoError(err)
+		s.Nil(resp.Error)
+	}
+
+	// The second call is answered from the cache
+	s.executor.AssertNumberOfCalls(s.T(), "Execute", 1)
+	hits, misses := s.toolRegistry.ResultCacheStats()
+	s.Equal(uint64(1), hits)
+	s.Equal(uint64(1), misses)
+}
+
+func (s *ToolCallHandlerTestSuite) TestHandleToolCallTimeout() {
+	ctx := context.Background()
+	s.allowAnyLogs()
+	s.registerInvoiceTools(ctx)
+
+	s.executor.On("Execute", mock.Anything, mock.Anything).
+		Return((*ExecutionResponse)(nil), &TimeoutError{Command: "test-cli", Elapsed: time.Second}).Once()
+
+	handler := NewToolCallHandler(s.logger, s.bridge, s.toolRegistry, s.parser, s.tracker)
+	resp, err := handler.HandleToolCall(ctx, s.toolCallRequest("invoice_list"))
+	s.Require().NoError(err)
+	s.Require().NotNil(resp.Error)
+	s.Equal(errorCodeToolTimeout, resp.Error.Code)
+	s.Contains(resp.Error.Data.(string), "invoice_list")
+}
+
+func (s *ToolCallHandlerTestSuite) TestHandleToolCallCliFailureIsNotTimeout() {
+	ctx := context.Background()
+	s.allowAnyLogs()
+	s.registerInvoiceTools(ctx)
+
+	s.executor.On("Execute", mock.Anything, mock.Anything).
+		Return((*ExecutionResponse)(nil), errTestCommandNotAllowed).Once()
+
+	handler := NewToolCallHandler(s.logger, s.bridge, s.toolRegistry, s.parser, s.tracker)
+	resp, err := handler.HandleToolCall(ctx, s.toolCallRequest("invoice_list"))
+	s.Require().NoError(err)
+	s.Require().NotNil(resp.Error)
+	s.Equal(-32603, resp.Error.Code)
+}
+
+func (s *ToolCallHandlerTestSuite) TestHandleToolCallJSONResult() {
+	ctx := context.Background()
+	s.allowAnyLogs()
+	s.registerInvoiceTools(ctx)
+
+	s.executor.On("Execute", mock.Anything, mock.Anything).
+		Return(&ExecutionResponse{ExitCode: 0, Stdout: `{"number": "INV-001"}`}, nil).Once()
+	s.executor.On("Execute", mock.Anything, mock.Anything).
+		Return(&ExecutionResponse{ExitCode: 0, Stdout: "not json"}, nil).Once()
+
+	handler := NewToolCallHandler(s.logger, s.bridge, s.toolRegistry, s.parser, s.tracker)
+
+	resp, err := handler.HandleToolCall(ctx, s.toolCallRequest("invoice_show"))
+	s.Require().NoError(err)
+	result, ok := resp.Result.(types.ToolResult)
+	s.Require().True(ok)
+	s.False(result.IsError)
+	s.Require().Len(result.Content, 1)
+	s.Equal(types.ContentTypeJSON, result.Content[0].Type)
+
+	// Malformed output from a json tool is a failed call, not a raw string
+	resp, err = handler.HandleToolCall(ctx, s.toolCallRequest("invoice_list"))
+	s.Require().NoError(err)
+	result, ok = resp.Result.(types.ToolResult)
+	s.Require().True(ok)
+	s.True(result.IsError)
+	s.Require().Len(result.Content, 1)
+	s.Equal(types.ContentTypeError, result.Content[0].Type)
+	s.Equal(contentErrorInvalidJSON, result.Content[0].Error.Code)
+}
+
+func (s *ToolCallHandlerTestSuite) TestHandleToolCallTimeoutCappedByMax() {
+	ctx := context.Background()
+	s.allowAnyLogs()
+	s.registerInvoiceTools(ctx)
+
+	var timeout time.Duration
+	s.executor.On("Execute", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { timeout = args.Get(1).(*ExecutionRequest).Timeout }).
+		Return(&ExecutionResponse{ExitCode: 0, Stdout: "[]"}, nil)
+
+	handler := NewToolCallHandler(s.logger, s.bridge, s.toolRegistry, s.parser, s.tracker)
+
+	// Without a cap the tool's own timeout applies
+	tool, err := s.toolRegistry.GetTool(ctx, "invoice_list")
+	s.Require().NoError(err)
+	_, err = handler.HandleToolCall(ctx, s.toolCallRequest("invoice_list"))
+	s.Require().NoError(err)
+	s.LessOrEqual(timeout, tool.Timeout)
+	s.Greater(timeout, tool.Timeout-time.Second)
+
+	handler.SetMaxTimeout(2 * time.Second)
+	_, err = handler.HandleToolCall(ctx, s.toolCallRequest("invoice_show"))
+	s.Require().NoError(err)
+	s.LessOrEqual(timeout, 2*time.Second)
+	s.Greater(timeout, time.Second)
+}
+
+// registerInvoiceTools registers the invoice tools and accepts any input for them
+func (s *ToolCallHandlerTestSuite) registerInvoiceTools(ctx context.Context) {
+	for _, tool := range tools.CreateInvoiceManagementTools() {
+		s.Require().NoError(s.toolRegistry.RegisterTool(ctx, tool))
+	}
+	s.mockValidator.On("ValidateAgainstSchema", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	s.tracker.On("StartOperation", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return((*Operation)(nil), nil)
+}
+
+// toolCallRequest builds a tools/call request for tool
+func (s *ToolCallHandlerTestSuite) toolCallRequest(tool string) *types.MCPRequest {
+	return &types.MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params: map[string]interface{}{
+			"name":      tool,
+			"arguments": map[string]interface{}{"invoice_number": "INV-001"},
+		},
+	}
+}
+
+// allowAnyLogs accepts log calls with any number of key-value pairs
+func (s *ToolCallHandlerTestSuite) allowAnyLogs() {
+	for _, level := range []string{"Debug", "Info", "Warn", "Error"} {
+		args := []interface{}{mock.Anything}
+		for len(args) <= 11 {
+			s.logger.On(level, args...).Maybe()
+			args = append(args, mock.Anything)
+		}
+	}
+}
+
 func TestToolCallHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(ToolCallHandlerTestSuite))
 }
@@ -572,6 +733,74 @@ func TestParseToolOutput(t *testing.T) {
 		assert.Contains(t, content[2].Text, "output.pdf")
 	})
 
+	jsonTool := &tools.MCPTool{
+		Name:        "json_tool",
+		Description: "JSON tool",
+		OutputType:  tools.OutputTypeJSON,
+	}
+
+	t.Run("JSONOutput", func(t *testing.T) {
+		resp := &ExecutionResponse{
+			ExitCode: 0,
+			Stdout:   "[{\"number\": \"INV-001\", \"total\": 125.5}]\n",
+		}
+
+		content, err := handler.parseToolOutput(context.Background(), jsonTool, resp)
+		require.NoError(t, err)
+		require.Len(t, content, 1)
+		assert.Equal(t, types.ContentTypeJSON, content[0].Type)
+		assert.JSONEq(t, `[{"number": "INV-001", "total": 125.5}]`, string(content[0].Data))
+		assert.JSONEq(t, `[{"number": "INV-001", "total": 125.5}]`, content[0].Text)
+		assert.False(t, hasErrorContent(content))
+	})
+
+	t.Run("MalformedJSONOutput", func(t *testing.T) {
+		resp := &ExecutionResponse{
+			ExitCode: 0,
+			Stdout:   "Loading invoices...\n[{\"number\": ",
+		}
+
+		content, err := handler.parseToolOutput(context.Background(), jsonTool, resp)
+		require.NoError(t, err)
+		require.Len(t, content, 1)
+		assert.Equal(t, types.ContentTypeError, content[0].Type)
+		require.NotNil(t, content[0].Error)
+		assert.Equal(t, contentErrorInvalidJSON, content[0].Error.Code)
+		assert.Equal(t, "Loading invoices...\n[{\"number\":", content[0].Error.Detail)
+		assert.Empty(t, content[0].Data)
+		assert.True(t, hasErrorContent(content))
+	})
+
+	t.Run("TruncatedJSONOutput", func(t *testing.T) {
+		resp := &ExecutionResponse{
+			ExitCode:  0,
+			Stdout:    "[{\"number\": \"INV-001\"},\n... [output truncated: exceeded 32 bytes]\n",
+			Truncated: true,
+		}
+
+		content, err := handler.parseToolOutput(context.Background(), jsonTool, resp)
+		require.NoError(t, err)
+		require.Len(t, content, 1)
+		assert.Equal(t, types.ContentTypeText, content[0].Type)
+		assert.Equal(t, resp.Stdout, content[0].Text)
+		assert.False(t, hasErrorContent(content))
+	})
+
+	t.Run("EmptyJSONOutput", func(t *testing.T) {
+		content, err := handler.parseToolOutput(context.Background(), jsonTool, &ExecutionResponse{ExitCode: 0})
+		require.NoError(t, err)
+		require.Len(t, content, 1)
+		assert.Equal(t, types.ContentTypeError, content[0].Type)
+	})
+
+	t.Run("LongDetailTruncated", func(t *testing.T) {
+		content, err := handler.parseToolOutput(context.Background(), jsonTool,
+			&ExecutionResponse{ExitCode: 0, Stdout: strings.Repeat("x", 1000)})
+		require.NoError(t, err)
+		require.NotNil(t, content[0].Error)
+		assert.Len(t, content[0].Error.Detail, maxErrorDetail+len("..."))
+	})
+
 	t.Run("ContextCanceled", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -0,0 +1,53 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// defaultMaxOutputSize caps captured stdout and stderr when the sandbox sets no limit
+const defaultMaxOutputSize = 10 * 1024 * 1024 // 10MB
+
+// cappedBuffer keeps the first max bytes written to it and discards the rest, so a
+// command that writes without bound cannot exhaust memory. Writes never fail: the
+// command keeps running until it exits or its deadline kills it.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	max       int64
+	truncated bool
+}
+
+// newCappedBuffer returns a buffer that keeps at most maxSize bytes
+func newCappedBuffer(maxSize int64) *cappedBuffer {
+	if maxSize <= 0 {
+		maxSize = defaultMaxOutputSize
+	}
+	return &cappedBuffer{max: maxSize}
+}
+
+// Write keeps as much of p as fits and reports all of it written
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	room := b.max - int64(b.buf.Len())
+	if int64(len(p)) <= room {
+		return b.buf.Write(p)
+	}
+
+	b.truncated = true
+	if room > 0 {
+		b.buf.Write(p[:room])
+	}
+	return len(p), nil
+}
+
+// Truncated reports whether output was discarded
+func (b *cappedBuffer) Truncated() bool {
+	return b.truncated
+}
+
+// String returns the kept output, ending in a marker when output was discarded
+func (b *cappedBuffer) String() string {
+	if !b.truncated {
+		return b.buf.String()
+	}
+	return b.buf.String() + fmt.Sprintf("\n... [output truncated: exceeded %d bytes]\n", b.max)
+}
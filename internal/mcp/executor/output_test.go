@@ -0,0 +1,126 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// floodCLIScript stands in for a runaway CLI: it writes $1 bytes to stdout and to stderr
+// and then exits normally
+const floodCLIScript = `#!/bin/sh
+head -c "$1" /dev/zero | tr '\0' 'x'
+head -c "$1" /dev/zero | tr '\0' 'y' >&2
+echo done
+`
+
+// newPermissiveLogger returns a mock logger that accepts any log call
+func newPermissiveLogger() *MockLogger {
+	logger := new(MockLogger)
+	for _, level := range []string{"Debug", "Info", "Warn", "Error"} {
+		args := []interface{}{mock.Anything}
+		for len(args) <= 11 {
+			logger.On(level, args...).Maybe()
+			args = append(args, mock.Anything)
+		}
+	}
+	return logger
+}
+
+func TestCappedBuffer(t *testing.T) {
+	t.Run("UnderLimit", func(t *testing.T) {
+		buf := newCappedBuffer(10)
+		n, err := buf.Write([]byte("hello"))
+		require.NoError(t, err)
+		assert.Equal(t, 5, n)
+		assert.False(t, buf.Truncated())
+		assert.Equal(t, "hello", buf.String())
+	})
+
+	t.Run("ExactlyAtLimit", func(t *testing.T) {
+		buf := newCappedBuffer(5)
+		_, _ = buf.Write([]byte("hel"))
+		_, _ = buf.Write([]byte("lo"))
+		assert.False(t, buf.Truncated())
+		assert.Equal(t, "hello", buf.String())
+	})
+
+	t.Run("OverLimit", func(t *testing.T) {
+		buf := newCappedBuffer(5)
+		n, err := buf.Write([]byte("hello world"))
+		require.NoError(t, err)
+		assert.Equal(t, 11, n, "writes report everything written so the command keeps running")
+
+		n, err = buf.Write([]byte("more"))
+		require.NoError(t, err)
+		assert.Equal(t, 4, n)
+
+		assert.True(t, buf.Truncated())
+		assert.Equal(t, "hello\n... [output truncated: exceeded 5 bytes]\n", buf.String())
+	})
+
+	t.Run("DefaultLimit", func(t *testing.T) {
+		assert.Equal(t, int64(defaultMaxOutputSize), newCappedBuffer(0).max)
+	})
+}
+
+// TestExecuteTruncatesLargeOutput tests that a command writing far more than the output
+// limit runs to completion with its output cut at the limit and memory use bounded
+func TestExecuteTruncatesLargeOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the mock CLI is a shell script")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "flood-cli")
+	require.NoError(t, os.WriteFile(script, []byte(floodCLIScript), 0o700)) //nolint:gosec // The script must be executable
+
+	validator := new(MockCommandValidator)
+	validator.On("ValidateCommand", mock.Anything, script, mock.Anything).Return(nil)
+	validator.On("ValidateEnvironment", mock.Anything, mock.Anything).Return(nil)
+
+	const limit = 64 * 1024
+	const emitted = 32 * 1024 * 1024
+	sandbox := SandboxConfig{
+		AllowedCommands:      []string{script},
+		EnvironmentWhitelist: []string{"PATH"},
+		MaxExecutionTime:     time.Minute,
+		MaxOutputSize:        limit,
+	}
+	executor := NewSecureExecutor(newPermissiveLogger(), validator, sandbox, new(MockFileHandler))
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	resp, err := executor.Execute(context.Background(), &ExecutionRequest{
+		Command: script,
+		Args:    []string{strconv.Itoa(emitted)},
+		Timeout: 30 * time.Second,
+	})
+
+	runtime.ReadMemStats(&after)
+	require.NoError(t, err)
+	assert.Equal(t, 0, resp.ExitCode, "the command finishes despite the discarded output")
+	assert.True(t, resp.Truncated)
+
+	for name, output := range map[string]string{"stdout": resp.Stdout, "stderr": resp.Stderr} {
+		kept, marker, found := strings.Cut(output, "\n... [output truncated")
+		assert.True(t, found, name)
+		assert.Len(t, kept, limit, name)
+		assert.Contains(t, marker, "exceeded 65536 bytes", name)
+	}
+	assert.NotContains(t, resp.Stdout, "done", "output after the limit is dropped")
+
+	// Everything allocated while running the command is a small fraction of its output
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(emitted/4))
+}
@@ -0,0 +1,243 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Command policy errors
+var (
+	ErrInvalidCommandPolicy = errors.New("invalid command policy")
+	errEmptyPattern         = errors.New("empty pattern")
+)
+
+// CommandPolicy restricts which subcommands and flags of each command may run. It is
+// deny-by-default: a command no rule names, or a subcommand no allow pattern matches, is
+// refused.
+//
+// A policy file is JSON:
+//
+//	{
+//	  "commands": [
+//	    {
+//	      "command": "go-invoice",
+//	      "globalFlags": ["--config"],
+//	      "allow": ["invoice list *", "invoice show *", "client *"],
+//	      "deny": ["client delete *"],
+//	      "flags": ["--output", "--status", "--client*"]
+//	    }
+//	  ]
+//	}
+type CommandPolicy struct {
+	Commands []CommandRule `json:"commands"`
+}
+
+// CommandRule is the policy for the commands matching Command.
+//
+// Allow and Deny patterns are matched against the arguments after any leading global
+// flags, one space-separated token per argument. A token is a glob ("inv*"), "*" matches
+// any single argument, and a final "*" matches any remaining arguments, including none.
+// Deny patterns win over allow patterns.
+type CommandRule struct {
+	// Command is a glob matched against the command as given and its base name
+	Command string `json:"command"`
+
+	// GlobalFlags are flags that take a value and may precede the subcommand, such as
+	// --config. They are skipped before matching and always permitted.
+	GlobalFlags []string `json:"globalFlags,omitempty"`
+
+	// Allow lists the permitted argument patterns, e.g. "invoice list *"
+	Allow []string `json:"allow"`
+
+	// Deny lists refused argument patterns, e.g. "invoice delete *"
+	Deny []string `json:"deny,omitempty"`
+
+	// Flags lists globs of the flags permitted after the subcommand; empty permits any
+	Flags []string `json:"flags,omitempty"`
+}
+
+// LoadCommandPolicy reads and validates a command policy file.
+func LoadCommandPolicy(policyPath string) (*CommandPolicy, error) {
+	data, err := os.ReadFile(policyPath) //nolint:gosec // Path comes from the server configuration
+	if err != nil {
+		return nil, fmt.Errorf("failed to read command policy: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var policy CommandPolicy
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidCommandPolicy, policyPath, err)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", policyPath, err)
+	}
+	return &policy, nil
+}
+
+// Validate checks that every rule names a command and has well-formed patterns.
+func (p *CommandPolicy) Validate() error {
+	if len(p.Commands) == 0 {
+		return fmt.Errorf("%w: no commands", ErrInvalidCommandPolicy)
+	}
+
+	for i, rule := range p.Commands {
+		if err := checkGlob(rule.Command); err != nil {
+			return fmt.Errorf("%w: commands[%d].command: %w", ErrInvalidCommandPolicy, i, err)
+		}
+		fields := []struct {
+			name     string
+			patterns []string
+		}{{"globalFlags", rule.GlobalFlags}, {"allow", rule.Allow}, {"deny", rule.Deny}, {"flags", rule.Flags}}
+		for _, field := range fields {
+			for j, pattern := range field.patterns {
+				if strings.TrimSpace(pattern) == "" {
+					return fmt.Errorf("%w: commands[%d].%s[%d]: %w", ErrInvalidCommandPolicy, i, field.name, j, errEmptyPattern)
+				}
+				for _, token := range strings.Fields(pattern) {
+					if err := checkGlob(token); err != nil {
+						return fmt.Errorf("%w: commands[%d].%s[%d]: %w", ErrInvalidCommandPolicy, i, field.name, j, err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Check returns nil when the policy permits running command with args, or an error
+// wrapping ErrPolicyViolation that says why not.
+func (p *CommandPolicy) Check(command string, args []string) error {
+	rule := p.ruleFor(command)
+	if rule == nil {
+		return fmt.Errorf("%w: command %q is not in the command policy", ErrPolicyViolation, command)
+	}
+
+	rest := rule.skipGlobalFlags(args)
+	invocation := strings.TrimSpace(filepath.Base(command) + " " + strings.Join(leadingWords(rest), " "))
+
+	for _, pattern := range rule.Deny {
+		if matchArgs(pattern, rest) {
+			return fmt.Errorf("%w: %q is denied by rule %q", ErrPolicyViolation, invocation, pattern)
+		}
+	}
+
+	allowed := false
+	for _, pattern := range rule.Allow {
+		if matchArgs(pattern, rest) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %q is not an allowed subcommand", ErrPolicyViolation, invocation)
+	}
+
+	if len(rule.Flags) > 0 {
+		for _, arg := range rest {
+			name, ok := flagName(arg)
+			if ok && !matchAny(rule.Flags, name) {
+				return fmt.Errorf("%w: flag %s is not allowed for %q", ErrPolicyViolation, name, invocation)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ruleFor returns the first rule whose command pattern matches command
+func (p *CommandPolicy) ruleFor(command string) *CommandRule {
+	for i := range p.Commands {
+		rule := &p.Commands[i]
+		if globMatch(rule.Command, command) || globMatch(rule.Command, filepath.Base(command)) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// skipGlobalFlags drops leading global flags and their values from args
+func (r *CommandRule) skipGlobalFlags(args []string) []string {
+	for len(args) > 0 {
+		name, ok := flagName(args[0])
+		if !ok || !matchAny(r.GlobalFlags, name) {
+			break
+		}
+		if strings.Contains(args[0], "=") || len(args) == 1 {
+			args = args[1:]
+		} else {
+			args = args[2:]
+		}
+	}
+	return args
+}
+
+// matchArgs reports whether args match a space-separated pattern
+func matchArgs(pattern string, args []string) bool {
+	tokens := strings.Fields(pattern)
+	for i, token := range tokens {
+		if token == "*" && i == len(tokens)-1 {
+			return true
+		}
+		if i >= len(args) || !globMatch(token, args[i]) {
+			return false
+		}
+	}
+	return len(tokens) == len(args)
+}
+
+// leadingWords returns the arguments before the first flag, naming the subcommand
+func leadingWords(args []string) []string {
+	for i, arg := range args {
+		if _, ok := flagName(arg); ok {
+			return args[:i]
+		}
+	}
+	return args
+}
+
+// flagName returns the name of a flag argument such as --status or --status=paid.
+// Negative numbers are values, not flags.
+func flagName(arg string) (string, bool) {
+	if len(arg) < 2 || arg[0] != '-' || (arg[1] >= '0' && arg[1] <= '9') || arg[1] == '.' {
+		return "", false
+	}
+	name, _, _ := strings.Cut(arg, "=")
+	return name, true
+}
+
+// matchAny reports whether value matches any of the globs
+func matchAny(globs []string, value string) bool {
+	for _, glob := range globs {
+		if globMatch(glob, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch matches value against a glob. "*" alone matches anything, including values
+// containing slashes such as file paths.
+func globMatch(glob, value string) bool {
+	if glob == "*" {
+		return true
+	}
+	matched, err := path.Match(glob, value)
+	return err == nil && matched
+}
+
+// checkGlob reports a malformed glob
+func checkGlob(glob string) error {
+	if glob == "" {
+		return errEmptyPattern
+	}
+	_, err := path.Match(glob, "")
+	return err
+}
@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testPolicy allows read-only invoice commands and client commands except delete
+func testPolicy() *CommandPolicy {
+	return &CommandPolicy{Commands: []CommandRule{{
+		Command:     "go-invoice",
+		GlobalFlags: []string{"--config"},
+		Allow:       []string{"invoice list *", "invoice show *", "client *"},
+		Deny:        []string{"client delete *"},
+		Flags:       []string{"--output", "--status", "--client*", "--id"},
+	}}}
+}
+
+func TestCommandPolicyCheck(t *testing.T) {
+	policy := testPolicy()
+	require.NoError(t, policy.Validate())
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		reason  string
+	}{
+		{"AllowedSubcommand", "go-invoice", []string{"invoice", "list", "--status", "paid"}, ""},
+		{"AllowedWithoutArgs", "go-invoice", []string{"invoice", "list"}, ""},
+		{"GlobalFlagSkipped", "go-invoice", []string{"--config", "/tmp/cfg.env", "invoice", "show", "--id", "INV-001"}, ""},
+		{"GlobalFlagWithValue", "go-invoice", []string{"--config=/tmp/cfg.env", "client", "list"}, ""},
+		{"CommandByPath", "/usr/local/bin/go-invoice", []string{"client", "show", "--client-id", "C1"}, ""},
+		{"NegativeNumberIsNotAFlag", "go-invoice", []string{"client", "update", "-5"}, ""},
+		{"DeniedSubcommand", "go-invoice", []string{"client", "delete", "--id", "C1"}, `"go-invoice client delete" is denied by rule "client delete *"`},
+		{"SubcommandNotAllowed", "go-invoice", []string{"invoice", "delete", "--id", "INV-001"}, `"go-invoice invoice delete" is not an allowed subcommand`},
+		{"DenyByDefault", "go-invoice", []string{"config", "init"}, `"go-invoice config init" is not an allowed subcommand`},
+		{"NoSubcommand", "go-invoice", nil, `"go-invoice" is not an allowed subcommand`},
+		{"GlobalFlagOnlyBeforeSubcommand", "go-invoice", []string{"invoice", "--config", "x", "list"}, "is not an allowed subcommand"},
+		{"FlagNotAllowed", "go-invoice", []string{"invoice", "list", "--format", "csv"}, `flag --format is not allowed for "go-invoice invoice list"`},
+		{"UnknownCommand", "rm", []string{"-rf", "/tmp/x"}, `command "rm" is not in the command policy`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.command, tt.args)
+			if tt.reason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrPolicyViolation)
+			assert.Contains(t, err.Error(), tt.reason)
+		})
+	}
+}
+
+func TestMatchArgs(t *testing.T) {
+	tests := []struct {
+		pattern string
+		args    []string
+		want    bool
+	}{
+		{"invoice list", []string{"invoice", "list"}, true},
+		{"invoice list", []string{"invoice", "list", "--all"}, false},
+		{"invoice *", []string{"invoice"}, true},
+		{"invoice * show", []string{"invoice", "x", "show"}, true},
+		{"invoice * show", []string{"invoice", "show"}, false},
+		{"inv* list *", []string{"invoices", "list", "a", "b"}, true},
+		{"*", nil, true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchArgs(tt.pattern, tt.args), "%q %v", tt.pattern, tt.args)
+	}
+}
+
+func TestLoadCommandPolicy(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		policy, err := LoadCommandPolicy(write("valid.json",
+			`{"commands": [{"command": "go-invoice", "allow": ["invoice list *"], "deny": ["invoice list --all"]}]}`))
+		require.NoError(t, err)
+		require.Len(t, policy.Commands, 1)
+		require.NoError(t, policy.Check("go-invoice", []string{"invoice", "list"}))
+		require.ErrorIs(t, policy.Check("go-invoice", []string{"invoice", "list", "--all"}), ErrPolicyViolation)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := LoadCommandPolicy(filepath.Join(dir, "missing.json"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	invalid := map[string]string{
+		"Malformed":     `{"commands": [`,
+		"UnknownField":  `{"commands": [{"command": "go-invoice", "allows": ["invoice *"]}]}`,
+		"NoCommands":    `{"commands": []}`,
+		"EmptyCommand":  `{"commands": [{"command": "", "allow": ["invoice *"]}]}`,
+		"EmptyPattern":  `{"commands": [{"command": "go-invoice", "allow": ["  "]}]}`,
+		"BadGlob":       `{"commands": [{"command": "go-invoice", "allow": ["invoice [list"]}]}`,
+		"BadFlagGlob":   `{"commands": [{"command": "go-invoice", "allow": ["*"], "flags": ["--[x"]}]}`,
+		"BadCommandRef": `{"commands": [{"command": "go-[invoice", "allow": ["*"]}]}`,
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadCommandPolicy(write(name+".json", content))
+			require.ErrorIs(t, err, ErrInvalidCommandPolicy)
+		})
+	}
+}
+
+func TestValidateCommandWithPolicy(t *testing.T) {
+	logger := new(MockLogger)
+	logger.On("Debug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	logger.On("Warn", "command denied by policy", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once()
+
+	validator := NewDefaultCommandValidator(logger, SandboxConfig{AllowedCommands: []string{"go-invoice"}})
+	ctx := context.Background()
+
+	// Without a policy every subcommand passes
+	require.NoError(t, validator.ValidateCommand(ctx, "go-invoice", []string{"client", "delete", "--id", "C1"}))
+
+	validator.SetCommandPolicy(testPolicy())
+	require.NoError(t, validator.ValidateCommand(ctx, "go-invoice", []string{"client", "list"}))
+	err := validator.ValidateCommand(ctx, "go-invoice", []string{"client", "delete", "--id", "C1"})
+	require.ErrorIs(t, err, ErrPolicyViolation)
+	logger.AssertExpectations(t)
+
+	validator.SetCommandPolicy(nil)
+	require.NoError(t, validator.ValidateCommand(ctx, "go-invoice", []string{"client", "delete", "--id", "C1"}))
+}
@@ -0,0 +1,103 @@
+//go:build linux
+
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// prSetChildSubreaper is the prctl option that makes orphaned descendants children of the
+// calling process instead of init
+const prSetChildSubreaper = 36
+
+// slowCLIScript stands in for a CLI that hangs: it starts a child of its own, records both
+// process IDs and waits far longer than any test timeout
+const slowCLIScript = `#!/bin/sh
+sleep 30 &
+echo "$$ $!" > "$1"
+wait
+`
+
+// TestExecuteKillsProcessGroupAtDeadline tests that a command running past its timeout is
+// killed along with the processes it started, and that none of them is left as a zombie
+func TestExecuteKillsProcessGroupAtDeadline(t *testing.T) {
+	// Become the reaper of the slow CLI's orphaned child so the test can check that it
+	// was killed and can collect it, rather than leaving it to init
+	_, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetChildSubreaper, 1, 0)
+	require.Zero(t, errno)
+	t.Cleanup(func() { _, _, _ = syscall.RawSyscall(syscall.SYS_PRCTL, prSetChildSubreaper, 0, 0) })
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "slow-cli")
+	require.NoError(t, os.WriteFile(script, []byte(slowCLIScript), 0o700)) //nolint:gosec // The script must be executable
+	pidFile := filepath.Join(dir, "pids")
+
+	logger := newPermissiveLogger()
+	validator := new(MockCommandValidator)
+	validator.On("ValidateCommand", mock.Anything, script, mock.Anything).Return(nil)
+	validator.On("ValidateEnvironment", mock.Anything, mock.Anything).Return(nil)
+
+	sandbox := SandboxConfig{
+		AllowedCommands:      []string{script},
+		EnvironmentWhitelist: []string{"PATH"},
+		MaxExecutionTime:     time.Minute,
+		MaxOutputSize:        1024 * 1024,
+	}
+	executor := NewSecureExecutor(logger, validator, sandbox, new(MockFileHandler))
+
+	timeout := 500 * time.Millisecond
+	start := time.Now()
+	resp, err := executor.Execute(context.Background(), &ExecutionRequest{
+		Command: script,
+		Args:    []string{pidFile},
+		Timeout: timeout,
+	})
+	elapsed := time.Since(start)
+
+	require.ErrorIs(t, err, ErrTimeout)
+	var timeoutErr *TimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, script, timeoutErr.Command)
+	assert.Nil(t, resp)
+	assert.GreaterOrEqual(t, elapsed, timeout)
+	assert.Less(t, elapsed, timeout+processWaitDelay, "Execute should return once the process group is killed")
+
+	data, err := os.ReadFile(pidFile) //nolint:gosec // Path is inside the test's temp dir
+	require.NoError(t, err)
+	pids := strings.Fields(string(data))
+	require.Len(t, pids, 2)
+	cliPID, err := strconv.Atoi(pids[0])
+	require.NoError(t, err)
+	childPID, err := strconv.Atoi(pids[1])
+	require.NoError(t, err)
+
+	// Execute waited for the CLI itself, so it is gone rather than a zombie
+	assert.NoDirExists(t, "/proc/"+pids[0], "CLI process %d was not reaped", cliPID)
+
+	// The CLI's child was killed with the group, not left sleeping
+	var status syscall.WaitStatus
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		reaped, waitErr := syscall.Wait4(childPID, &status, syscall.WNOHANG, nil)
+		require.NoError(t, waitErr)
+		if reaped == childPID {
+			break
+		}
+		require.True(t, time.Now().Before(deadline), "child process %d is still running", childPID)
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, status.Signaled())
+	assert.Equal(t, syscall.SIGKILL, status.Signal())
+	assert.NoDirExists(t, "/proc/"+pids[1], "child process %d was left as a zombie", childPID)
+}
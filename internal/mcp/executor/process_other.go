@@ -0,0 +1,9 @@
+//go:build !unix
+
+package executor
+
+import "os/exec"
+
+// killProcessGroupOnCancel keeps the default cancellation, which kills only the command
+// itself, on platforms without process groups
+func killProcessGroupOnCancel(_ *exec.Cmd) {}
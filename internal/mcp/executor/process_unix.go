@@ -0,0 +1,18 @@
+//go:build unix
+
+package executor
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroupOnCancel starts cmd in its own process group and, when its context ends,
+// kills the whole group, so processes the CLI started do not outlive it
+func killProcessGroupOnCancel(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		// A negative pid signals every process in the group
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
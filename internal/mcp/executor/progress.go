@@ -1,14 +1,18 @@
 package executor
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/mrz1836/go-invoice/internal/csv"
 )
 
 // Progress errors
@@ -472,3 +476,82 @@ func EstimateOperationTime(command string, args []string, historicalData map[str
 		return 10 * time.Second
 	}
 }
+
+// maxProgressLine bounds the partial line progressLineWriter holds while waiting for a newline
+const maxProgressLine = 64 * 1024
+
+// progressLineWriter passes command output through to dst, except for the progress
+// lines written by "import --progress json", which are turned into progress updates
+type progressLineWriter struct {
+	dst      io.Writer
+	callback ProgressFunc
+	pending  []byte
+}
+
+// Write handles every complete line in p, keeping a trailing partial line for the next write.
+// A partial line longer than maxProgressLine cannot be a progress line and is passed on.
+func (w *progressLineWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		end := bytes.IndexByte(w.pending, '\n')
+		if end < 0 {
+			if len(w.pending) > maxProgressLine {
+				if _, err := w.dst.Write(w.pending); err != nil {
+					return 0, err
+				}
+				w.pending = w.pending[:0]
+			}
+			return len(p), nil
+		}
+		if err := w.writeLine(w.pending[:end+1]); err != nil {
+			return 0, err
+		}
+		w.pending = w.pending[end+1:]
+	}
+}
+
+// Flush handles a final line that did not end with a newline
+func (w *progressLineWriter) Flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	err := w.writeLine(w.pending)
+	w.pending = nil
+	return err
+}
+
+// writeLine reports a progress line or copies any other line to dst
+func (w *progressLineWriter) writeLine(line []byte) error {
+	report, ok := csv.ParseProgressLine(string(line))
+	if !ok {
+		_, err := w.dst.Write(line)
+		return err
+	}
+
+	if w.callback != nil {
+		w.callback(&ProgressUpdate{
+			Stage:     report.Operation,
+			Percent:   int(report.Percentage),
+			Message:   fmt.Sprintf("%d of %d rows processed", report.ProcessedRows, report.TotalRows),
+			Current:   report.ProcessedRows,
+			Total:     report.TotalRows,
+			Timestamp: time.Now(),
+		})
+	}
+	return nil
+}
+
+// progressCallbackKey is the context key for a tool call's progress callback
+type progressCallbackKey struct{}
+
+// WithProgressCallback returns a context that makes CLIBridge.ExecuteToolCommand send
+// progress updates for the tool call, such as the rows processed by an import, to fn
+func WithProgressCallback(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressCallbackKey{}, fn)
+}
+
+// ProgressCallbackFromContext returns the callback set by WithProgressCallback, or nil
+func ProgressCallbackFromContext(ctx context.Context) ProgressFunc {
+	fn, _ := ctx.Value(progressCallbackKey{}).(ProgressFunc)
+	return fn
+}
@@ -1,10 +1,15 @@
 package executor
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+
+	"github.com/mrz1836/go-invoice/internal/csv"
 )
 
 // NoOpLogger is a simple logger that does nothing (for testing)
@@ -311,3 +316,49 @@ func TestProgressErrorVariables(t *testing.T) {
 		})
 	}
 }
+
+// TestProgressLineWriter tests that import progress lines become progress updates
+func TestProgressLineWriter(t *testing.T) {
+	line, err := csv.FormatProgressLine(csv.ProgressReport{Operation: "parsing rows", TotalRows: 4, ProcessedRows: 2, Percentage: 50})
+	require.NoError(t, err)
+
+	var stderr bytes.Buffer
+	var updates []*ProgressUpdate
+	writer := &progressLineWriter{dst: &stderr, callback: func(update *ProgressUpdate) {
+		updates = append(updates, update)
+	}}
+
+	// Lines may be split across writes
+	output := "warning: rate missing\n" + line + "\nError: trailing"
+	for _, chunk := range []string{output[:10], output[10:40], output[40:]} {
+		_, err = writer.Write([]byte(chunk))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Flush())
+
+	assert.Equal(t, "warning: rate missing\nError: trailing", stderr.String())
+	require.Len(t, updates, 1)
+	assert.Equal(t, "parsing rows", updates[0].Stage)
+	assert.Equal(t, 50, updates[0].Percent)
+	assert.Equal(t, 2, updates[0].Current)
+	assert.Equal(t, 4, updates[0].Total)
+
+	// Without a callback, progress lines are still kept out of the output
+	stderr.Reset()
+	writer = &progressLineWriter{dst: &stderr}
+	_, err = writer.Write([]byte(line + "\n"))
+	require.NoError(t, err)
+	assert.Empty(t, stderr.String())
+}
+
+// TestProgressCallbackContext tests passing a progress callback through a context
+func TestProgressCallbackContext(t *testing.T) {
+	assert.Nil(t, ProgressCallbackFromContext(context.Background()))
+
+	called := false
+	ctx := WithProgressCallback(context.Background(), func(_ *ProgressUpdate) { called = true })
+	fn := ProgressCallbackFromContext(ctx)
+	require.NotNil(t, fn)
+	fn(&ProgressUpdate{})
+	assert.True(t, called)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // Common security errors
@@ -24,6 +25,9 @@ type DefaultCommandValidator struct {
 	sandbox      SandboxConfig
 	allowedPaths map[string]bool
 	blockedPaths map[string]bool
+
+	policyMu sync.RWMutex
+	policy   *CommandPolicy
 }
 
 // NewDefaultCommandValidator creates a new command validator.
@@ -51,6 +55,14 @@ func NewDefaultCommandValidator(logger Logger, sandbox SandboxConfig) *DefaultCo
 	}
 }
 
+// SetCommandPolicy restricts commands to those the policy allows. A nil policy removes
+// the restriction.
+func (v *DefaultCommandValidator) SetCommandPolicy(policy *CommandPolicy) {
+	v.policyMu.Lock()
+	defer v.policyMu.Unlock()
+	v.policy = policy
+}
+
 // ValidateCommand checks if a command is safe to execute.
 func (v *DefaultCommandValidator) ValidateCommand(ctx context.Context, command string, args []string) error {
 	select {
@@ -89,6 +101,20 @@ func (v *DefaultCommandValidator) ValidateCommand(ctx context.Context, command s
 		return err
 	}
 
+	// Check the command against the allowed-commands policy
+	v.policyMu.RLock()
+	policy := v.policy
+	v.policyMu.RUnlock()
+	if policy != nil {
+		if err := policy.Check(command, args); err != nil {
+			v.logger.Warn("command denied by policy",
+				"command", command,
+				"error", err,
+			)
+			return err
+		}
+	}
+
 	v.logger.Debug("command validated successfully",
 		"command", command,
 		"argCount", len(args),
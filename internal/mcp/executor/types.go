@@ -35,6 +35,10 @@ type ExecutionRequest struct {
 	// Environment contains additional environment variables
 	Environment map[string]string `json:"environment,omitempty"`
 
+	// InheritEnv names server environment variables passed to the command in addition to
+	// PATH, HOME and the sandbox EnvironmentWhitelist
+	InheritEnv []string `json:"inheritEnv,omitempty"`
+
 	// Timeout is the maximum execution time (default: 30s)
 	Timeout time.Duration `json:"timeout,omitempty"`
 
@@ -62,6 +66,10 @@ type ExecutionResponse struct {
 	// Duration is the execution time
 	Duration time.Duration `json:"duration"`
 
+	// Truncated is set when stdout or stderr exceeded the sandbox MaxOutputSize and was cut
+	// short; the kept output ends in a truncation marker
+	Truncated bool `json:"truncated,omitempty"`
+
 	// OutputFiles are files generated by the command
 	OutputFiles []FileReference `json:"outputFiles,omitempty"`
 
@@ -170,13 +178,15 @@ type SandboxConfig struct {
 	// MaxExecutionTime is the maximum allowed execution time
 	MaxExecutionTime time.Duration `json:"maxExecutionTime"`
 
-	// MaxOutputSize is the maximum allowed output size in bytes
+	// MaxOutputSize is the maximum captured size of stdout and of stderr in bytes; output
+	// beyond it is discarded
 	MaxOutputSize int64 `json:"maxOutputSize"`
 
 	// MaxFileSize is the maximum allowed file size in bytes
 	MaxFileSize int64 `json:"maxFileSize"`
 
-	// EnvironmentWhitelist lists allowed environment variables
+	// EnvironmentWhitelist lists the environment variables commands inherit from the
+	// server; all others except PATH and HOME are stripped
 	EnvironmentWhitelist []string `json:"environmentWhitelist"`
 
 	// EnableNetworkIsolation prevents network access
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/mrz1836/go-invoice/internal/mcp/audit"
 	"github.com/mrz1836/go-invoice/internal/mcp/executor"
 	"github.com/mrz1836/go-invoice/internal/mcp/tools"
 	"github.com/mrz1836/go-invoice/internal/mcp/types"
@@ -16,6 +17,10 @@ type ProductionMCPHandler struct {
 	toolRegistry    *tools.DefaultToolRegistry
 	toolCallHandler *executor.ToolCallHandler
 	config          *Config
+
+	// Set by CreateProductionHandler so configuration reloads reach the executor
+	bridge   *executor.MCPExecutorBridge
+	executor *executor.SecureExecutor
 }
 
 // NewProductionMCPHandler creates a new MCP handler with full Phase 3 integration.
@@ -110,29 +115,41 @@ func (h *ProductionMCPHandler) HandleToolsList(ctx context.Context, req *types.M
 
 	h.logger.Debug("handling tools/list request")
 
-	// Get all tools from registry
-	allTools, err := h.toolRegistry.ListTools(ctx, "")
+	params, err := parseToolListParams(req.Params)
+	if err != nil {
+		return invalidToolListParams(req, err), nil
+	}
+
+	// Get the tools of the requested category, or all tools, sorted by name
+	allTools, err := h.toolRegistry.ListTools(ctx, tools.CategoryType(params.Category))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tools: %w", err)
 	}
 
+	page, nextCursor, err := pageTools(allTools, params)
+	if err != nil {
+		return invalidToolListParams(req, err), nil
+	}
+
 	// Convert to MCP tool format
-	tools := make([]Tool, 0, len(allTools))
-	for _, toolDef := range allTools {
+	toolList := make([]Tool, 0, len(page))
+	for _, toolDef := range page {
 		tool := Tool{
 			Name:        toolDef.Name,
 			Description: toolDef.Description,
 			InputSchema: toolDef.InputSchema,
 		}
-		tools = append(tools, tool)
+		toolList = append(toolList, tool)
 	}
 
 	result := ToolListResult{
-		Tools: tools,
+		Tools:      toolList,
+		NextCursor: nextCursor,
 	}
 
 	h.logger.Info("tools list returned",
-		"count", len(tools),
+		"count", len(toolList),
+		"hasMore", nextCursor != "",
 	)
 
 	return &types.MCPResponse{
@@ -154,6 +171,27 @@ func (h *ProductionMCPHandler) HandleToolCall(ctx context.Context, req *types.MC
 	return h.toolCallHandler.HandleToolCall(ctx, req)
 }
 
+// ReloadConfig applies the log level, CLI path and command allowlist of cfg. Tool calls
+// already running finish with the settings they started with.
+func (h *ProductionMCPHandler) ReloadConfig(ctx context.Context, cfg *Config) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if setter, ok := h.logger.(levelSetter); ok {
+		setter.SetLevel(cfg.LogLevel)
+	}
+	if h.bridge != nil {
+		h.bridge.SetCLIPath(cfg.CLI.Path)
+	}
+	if h.executor != nil {
+		h.executor.SetAllowedCommands(cfg.Security.AllowedCommands)
+	}
+	return nil
+}
+
 // CreateProductionHandler creates a production-ready MCP handler with all integrations.
 func CreateProductionHandler(config *Config) (MCPHandler, error) {
 	ctx := context.Background()
@@ -165,6 +203,7 @@ func CreateProductionHandler(config *Config) (MCPHandler, error) {
 	securityConfig := executor.DefaultSecurityConfig()
 	// Apply security settings from config
 	securityConfig.Sandbox.AllowedCommands = config.Security.AllowedCommands
+	securityConfig.Sandbox.EnvironmentWhitelist = append(securityConfig.Sandbox.EnvironmentWhitelist, config.Security.AllowedEnv...)
 
 	// Allow both the configured working directory and current working directory
 	// This enables MCP import operations from the project directory
@@ -175,6 +214,13 @@ func CreateProductionHandler(config *Config) (MCPHandler, error) {
 	}
 	securityConfig.Sandbox.AllowedPaths = allowedPaths
 	securityConfig.StrictMode = config.Security.SandboxEnabled
+	securityConfig.PolicyFile = config.Security.CommandPolicyFile
+	if config.CLI.MaxTimeout > 0 {
+		securityConfig.Sandbox.MaxExecutionTime = config.CLI.MaxTimeout
+	}
+	if config.CLI.MaxOutputSize > 0 {
+		securityConfig.Sandbox.MaxOutputSize = config.CLI.MaxOutputSize
+	}
 
 	// Create audit logger
 	var auditLogger executor.AuditLogger
@@ -188,6 +234,13 @@ func CreateProductionHandler(config *Config) (MCPHandler, error) {
 
 	// Create validator
 	validator := executor.NewDefaultCommandValidator(logger, securityConfig.Sandbox)
+	if securityConfig.PolicyFile != "" {
+		policy, err := executor.LoadCommandPolicy(securityConfig.PolicyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load command policy: %w", err)
+		}
+		validator.SetCommandPolicy(policy)
+	}
 
 	// Create file handler
 	fileHandler := executor.NewDefaultFileHandler(logger, validator, securityConfig.Sandbox)
@@ -202,8 +255,9 @@ func CreateProductionHandler(config *Config) (MCPHandler, error) {
 	tracker := executor.NewDefaultProgressTracker(logger)
 
 	// Create tool registry
-	inputValidator := tools.NewDefaultInputValidator(logger)
+	inputValidator := tools.NewJSONSchemaValidator(logger)
 	toolRegistry := tools.NewDefaultToolRegistry(inputValidator, logger)
+	toolRegistry.EnableResultCache(tools.DefaultResultCacheSize)
 
 	// Register all tool categories
 	if err := tools.RegisterInvoiceManagementTools(ctx, toolRegistry); err != nil {
@@ -235,6 +289,21 @@ func CreateProductionHandler(config *Config) (MCPHandler, error) {
 		config.CLI.Path,
 	)
 
+	// Persist every tool command execution when an audit file is configured
+	if config.Audit.File != "" {
+		auditSink, err := newAuditSink(config.Audit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create audit sink: %w", err)
+		}
+		bridge.SetAuditSink(auditSink)
+	}
+
+	// Report mutating tool commands instead of running them
+	if config.CLI.DryRun {
+		bridge.SetDryRun(true)
+		logger.Warn("dry-run mode: mutating tools will not be executed")
+	}
+
 	// Create tool call handler
 	toolCallHandler := executor.NewToolCallHandler(
 		logger,
@@ -243,14 +312,17 @@ func CreateProductionHandler(config *Config) (MCPHandler, error) {
 		parser,
 		tracker,
 	)
+	toolCallHandler.SetMaxTimeout(config.CLI.MaxTimeout)
 
 	// Create handler
-	handler := NewProductionMCPHandler(
-		logger,
-		toolRegistry,
-		toolCallHandler,
-		config,
-	)
+	handler := &ProductionMCPHandler{
+		logger:          logger,
+		toolRegistry:    toolRegistry,
+		toolCallHandler: toolCallHandler,
+		config:          config,
+		bridge:          bridge,
+		executor:        secureExecutor,
+	}
 
 	// Get tool count safely
 	toolList, err := toolRegistry.ListTools(ctx, "")
@@ -261,9 +333,25 @@ func CreateProductionHandler(config *Config) (MCPHandler, error) {
 
 	logger.Info("production MCP handler created",
 		"auditEnabled", securityConfig.AuditEnabled,
+		"auditFile", config.Audit.File,
 		"strictMode", securityConfig.StrictMode,
 		"toolCount", toolCount,
 	)
 
 	return handler, nil
 }
+
+// newAuditSink creates the JSONL audit sink described by cfg, falling back to the default
+// rotation settings
+func newAuditSink(cfg AuditConfig) (*audit.FileSink, error) {
+	maxBytes := audit.DefaultMaxBytes
+	if cfg.MaxSizeMB > 0 {
+		maxBytes = int64(cfg.MaxSizeMB) * 1024 * 1024
+	}
+	maxBackups := audit.DefaultMaxBackups
+	if cfg.MaxBackups > 0 {
+		maxBackups = cfg.MaxBackups
+	}
+
+	return audit.NewFileSink(cfg.File, maxBytes, maxBackups)
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -23,48 +24,62 @@ const (
 	LogLevelError
 )
 
+// levelSetter is implemented by loggers whose level can change at runtime
+type levelSetter interface {
+	SetLevel(level string)
+}
+
 // DefaultLogger implements the Logger interface with structured logging
 type DefaultLogger struct {
-	level  LogLevel
+	level  atomic.Int32
 	logger *log.Logger
 }
 
 // NewLogger creates a new logger with the specified level
 func NewLogger(level string) Logger {
-	logLevel := parseLogLevel(level)
 	// Use 0 flags to avoid duplicate timestamps
-	logger := log.New(os.Stderr, "", 0)
-
-	return &DefaultLogger{
-		level:  logLevel,
-		logger: logger,
+	logger := &DefaultLogger{
+		logger: log.New(os.Stderr, "", 0),
 	}
+	logger.SetLevel(level)
+
+	return logger
+}
+
+// SetLevel changes the logging level; it is safe to call while other goroutines log
+func (l *DefaultLogger) SetLevel(level string) {
+	l.level.Store(int32(parseLogLevel(level)))
+}
+
+// Level returns the current logging level
+func (l *DefaultLogger) Level() LogLevel {
+	return LogLevel(l.level.Load())
 }
 
 // Debug logs a debug message with key-value pairs
 func (l *DefaultLogger) Debug(msg string, keysAndValues ...interface{}) {
-	if l.level <= LogLevelDebug {
+	if l.Level() <= LogLevelDebug {
 		l.log("DEBUG", msg, keysAndValues...)
 	}
 }
 
 // Info logs an info message with key-value pairs
 func (l *DefaultLogger) Info(msg string, keysAndValues ...interface{}) {
-	if l.level <= LogLevelInfo {
+	if l.Level() <= LogLevelInfo {
 		l.log("INFO", msg, keysAndValues...)
 	}
 }
 
 // Warn logs a warning message with key-value pairs
 func (l *DefaultLogger) Warn(msg string, keysAndValues ...interface{}) {
-	if l.level <= LogLevelWarn {
+	if l.Level() <= LogLevelWarn {
 		l.log("WARN", msg, keysAndValues...)
 	}
 }
 
 // Error logs an error message with key-value pairs
 func (l *DefaultLogger) Error(msg string, keysAndValues ...interface{}) {
-	if l.level <= LogLevelError {
+	if l.Level() <= LogLevelError {
 		l.log("ERROR", msg, keysAndValues...)
 	}
 }
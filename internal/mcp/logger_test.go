@@ -158,7 +158,7 @@ func TestDefaultLoggerCreation(t *testing.T) {
 			// Verify logger is of correct type
 			defaultLogger, ok := logger.(*DefaultLogger)
 			assert.True(t, ok)
-			assert.Equal(t, tt.expected, defaultLogger.level)
+			assert.Equal(t, tt.expected, defaultLogger.Level())
 		})
 	}
 }
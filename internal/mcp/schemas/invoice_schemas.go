@@ -88,6 +88,13 @@ func InvoiceCreateSchema() map[string]interface{} {
 				"pattern":      "^[\\d\\s\\+\\-\\(\\)\\.\\/ext]+$",
 				keyExamples:    []string{"+1-555-123-4567", "(555) 123-4567"},
 			},
+			"tax_rate": map[string]interface{}{
+				keyType:        typeNumber,
+				keyMinimum:     0.0,
+				keyMaximum:     1.0,
+				keyDescription: "Tax rate for this invoice as a decimal between 0 and 1, not a percentage: use 0.10 for 10%. Defaults to no tax.",
+				keyExamples:    []interface{}{0.1, 0.2, 0.0825},
+			},
 			"usdc_address": map[string]interface{}{
 				keyType:        typeString,
 				keyDescription: "Override USDC cryptocurrency address for this specific invoice. If not provided, uses the global USDC address from configuration. Useful when you want a unique payment address for this invoice.",
@@ -460,7 +467,7 @@ func InvoiceAddLineItemSchema() map[string]interface{} {
 							keyType:        typeNumber,
 							keyMinimum:     0.01,
 							keyMaximum:     10000.0,
-							keyDescription: "Hourly rate (required for 'hourly' type).",
+							keyDescription: "Hourly rate (required for 'hourly' type unless DEFAULT_RATE is configured).",
 							keyExamples:    []interface{}{75.0, 125.0, 200.0},
 						},
 						// Fixed type fields
@@ -494,7 +501,7 @@ func InvoiceAddLineItemSchema() map[string]interface{} {
 							keyProperties: map[string]interface{}{
 								keyType: map[string]interface{}{"const": "hourly"},
 							},
-							keyRequired: []string{keyHours, keyRate},
+							keyRequired: []string{keyHours}, // The rate falls back to DEFAULT_RATE
 						},
 						// Fixed type validation
 						map[string]interface{}{
@@ -28,6 +28,12 @@ func TestInvoiceCreateSchema(t *testing.T) {
 	assert.Contains(t, properties, keyDescription)
 	assert.Contains(t, properties, "work_items")
 
+	// Tax rate is a decimal, never a percentage
+	taxRate, ok := properties["tax_rate"].(map[string]interface{})
+	require.True(t, ok, "tax_rate should be a property")
+	assert.InDelta(t, 0.0, taxRate[keyMinimum], 0)
+	assert.InDelta(t, 1.0, taxRate[keyMaximum], 0)
+
 	// Verify that client identification fields are optional in schema (validation logic handles requirements)
 	required, ok := schema[keyRequired].([]string)
 	require.True(t, ok, "required should be a string slice")
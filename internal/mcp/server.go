@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"os"
 	"sync"
+
+	"github.com/mrz1836/go-invoice/internal/mcp/audit"
 )
 
 // Static errors for err113 compliance
@@ -111,6 +113,18 @@ func (s *DefaultServer) Shutdown(ctx context.Context) error {
 	}
 }
 
+// ReloadConfig applies the live-reloadable settings of cfg to the server logger and handler
+func (s *DefaultServer) ReloadConfig(ctx context.Context, cfg *Config) error {
+	if setter, ok := s.logger.(levelSetter); ok {
+		setter.SetLevel(cfg.LogLevel)
+	}
+
+	if reloader, ok := s.handler.(ConfigReloader); ok {
+		return reloader.ReloadConfig(ctx, cfg)
+	}
+	return nil
+}
+
 // startStdioTransport starts the server using stdio transport for Claude Code
 func (s *DefaultServer) startStdioTransport(ctx context.Context) error {
 	s.logger.Info("Starting stdio transport")
@@ -161,6 +175,7 @@ func (s *DefaultServer) startHTTPTransport(ctx context.Context) error {
 
 // handleStdioRequests handles MCP requests over stdio
 func (s *DefaultServer) handleStdioRequests(ctx context.Context) {
+	ctx = audit.WithCaller(ctx, "stdio")
 	decoder := json.NewDecoder(os.Stdin)
 	encoder := json.NewEncoder(os.Stdout)
 
@@ -215,7 +230,7 @@ func (s *DefaultServer) handleStdioRequests(ctx context.Context) {
 
 // handleHTTPRequest handles MCP requests over HTTP
 func (s *DefaultServer) handleHTTPRequest(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	ctx := audit.WithCaller(r.Context(), "http:"+r.RemoteAddr)
 
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
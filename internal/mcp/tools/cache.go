@@ -0,0 +1,241 @@
+package tools
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultResultCacheSize is the number of tool results kept when no size is given
+const DefaultResultCacheSize = 128
+
+// ResultCache is a bounded, least-recently-used cache of tool call results.
+//
+// Entries are keyed by tool name and normalized input and expire after the TTL they
+// were stored with. When the cache is full the least recently used entry is evicted.
+//
+// Notes:
+// - Thread-safe for concurrent access
+// - Values are stored as-is; callers must not modify a cached result
+// - Hit and miss counts are kept for registry metrics
+type ResultCache struct {
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // front is most recently used
+	mu       sync.Mutex
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+	now      func() time.Time
+}
+
+// resultCacheEntry is one cached result
+type resultCacheEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// NewResultCache creates a result cache holding at most capacity entries. A capacity of
+// zero or less uses DefaultResultCacheSize.
+func NewResultCache(capacity int) *ResultCache {
+	if capacity <= 0 {
+		capacity = DefaultResultCacheSize
+	}
+
+	return &ResultCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get returns the cached value for key if present and not expired, counting a hit or miss
+func (c *ResultCache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	entry := element.Value.(*resultCacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.removeElement(element)
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	c.hits.Add(1)
+	return entry.value, true
+}
+
+// Put stores value under key for ttl, evicting the least recently used entry if the
+// cache is full. A ttl of zero or less stores nothing.
+func (c *ResultCache) Put(key string, value interface{}, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*resultCacheEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&resultCacheEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// Clear removes every entry, keeping the hit and miss counts
+func (c *ResultCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Len returns the number of cached entries, including expired ones not yet evicted
+func (c *ResultCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Stats returns the number of cache hits and misses so far
+func (c *ResultCache) Stats() (hits, misses uint64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+// removeElement drops an entry; the caller must hold mu
+func (c *ResultCache) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*resultCacheEntry).key)
+}
+
+// ResultCacheKey returns the cache key for a call to toolName with input. The input is
+// normalized by JSON encoding, which sorts map keys, so equal inputs give equal keys
+// regardless of key order or numeric type.
+func ResultCacheKey(toolName string, input map[string]interface{}) (string, bool) {
+	if len(input) == 0 {
+		return toolName + "\x00{}", true
+	}
+	normalized, err := json.Marshal(input)
+	if err != nil {
+		return "", false
+	}
+	return toolName + "\x00" + string(normalized), true
+}
+
+// EnableResultCache turns on result caching for tools that declare a CacheTTL.
+//
+// Parameters:
+// - size: Maximum number of cached results; zero or less uses DefaultResultCacheSize
+//
+// Notes:
+// - Calling it again replaces the cache, dropping cached results and counts
+// - Thread-safe for concurrent access
+func (r *DefaultToolRegistry) EnableResultCache(size int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.results = NewResultCache(size)
+}
+
+// CachedResult returns a cached result for a call to toolName with input.
+//
+// Returns:
+// - interface{}: The result stored by RecordResult
+// - bool: False when caching is disabled, the tool is not cacheable, or nothing is cached
+//
+// Notes:
+// - Only calls to tools with a CacheTTL count as cache hits or misses
+// - Thread-safe for concurrent access
+func (r *DefaultToolRegistry) CachedResult(ctx context.Context, toolName string, input map[string]interface{}) (interface{}, bool) {
+	if ctx.Err() != nil {
+		return nil, false
+	}
+
+	cache, ttl := r.resultCacheFor(toolName)
+	if cache == nil || ttl <= 0 {
+		return nil, false
+	}
+
+	key, ok := ResultCacheKey(toolName, input)
+	if !ok {
+		return nil, false
+	}
+	return cache.Get(key)
+}
+
+// RecordResult records the successful result of a call to toolName with input.
+//
+// Results of tools with a CacheTTL are cached for that long. Any other tool may have
+// changed data, so its success clears the cache rather than letting reads go stale.
+//
+// Notes:
+// - Only successful results should be recorded
+// - No-op when caching is disabled
+// - Thread-safe for concurrent access
+func (r *DefaultToolRegistry) RecordResult(ctx context.Context, toolName string, input map[string]interface{}, result interface{}) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	cache, ttl := r.resultCacheFor(toolName)
+	if cache == nil {
+		return
+	}
+
+	if ttl <= 0 {
+		cache.Clear()
+		return
+	}
+
+	if key, ok := ResultCacheKey(toolName, input); ok {
+		cache.Put(key, result, ttl)
+	}
+}
+
+// ResultCacheStats returns the result cache hit and miss counts, zero when disabled
+func (r *DefaultToolRegistry) ResultCacheStats() (hits, misses uint64) {
+	r.mu.RLock()
+	cache := r.results
+	r.mu.RUnlock()
+
+	if cache == nil {
+		return 0, 0
+	}
+	return cache.Stats()
+}
+
+// resultCacheFor returns the result cache and the cache TTL of toolName
+func (r *DefaultToolRegistry) resultCacheFor(toolName string) (*ResultCache, time.Duration) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.results == nil {
+		return nil, 0
+	}
+	var ttl time.Duration
+	if tool, ok := r.tools[toolName]; ok {
+		ttl = tool.CacheTTL
+	}
+	return r.results, ttl
+}
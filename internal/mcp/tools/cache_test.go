@@ -0,0 +1,175 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultCache(t *testing.T) {
+	t.Run("GetAndPut", func(t *testing.T) {
+		cache := NewResultCache(2)
+
+		_, ok := cache.Get("a")
+		assert.False(t, ok)
+
+		cache.Put("a", 1, time.Minute)
+		value, ok := cache.Get("a")
+		require.True(t, ok)
+		assert.Equal(t, 1, value)
+
+		hits, misses := cache.Stats()
+		assert.Equal(t, uint64(1), hits)
+		assert.Equal(t, uint64(1), misses)
+	})
+
+	t.Run("EvictsLeastRecentlyUsed", func(t *testing.T) {
+		cache := NewResultCache(2)
+		cache.Put("a", 1, time.Minute)
+		cache.Put("b", 2, time.Minute)
+
+		// Touch a so b is the least recently used
+		_, _ = cache.Get("a")
+		cache.Put("c", 3, time.Minute)
+
+		assert.Equal(t, 2, cache.Len())
+		_, ok := cache.Get("b")
+		assert.False(t, ok, "b should have been evicted")
+		_, ok = cache.Get("a")
+		assert.True(t, ok)
+		_, ok = cache.Get("c")
+		assert.True(t, ok)
+	})
+
+	t.Run("ExpiresAfterTTL", func(t *testing.T) {
+		cache := NewResultCache(2)
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		cache.Put("a", 1, time.Second)
+		_, ok := cache.Get("a")
+		assert.True(t, ok)
+
+		now = now.Add(time.Second)
+		_, ok = cache.Get("a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, cache.Len(), "expired entry should be dropped")
+	})
+
+	t.Run("ZeroTTLIsNotCached", func(t *testing.T) {
+		cache := NewResultCache(0)
+		cache.Put("a", 1, 0)
+		assert.Equal(t, 0, cache.Len())
+	})
+}
+
+func TestResultCacheKey(t *testing.T) {
+	first, ok := ResultCacheKey("invoice_list", map[string]interface{}{"status": "paid", "limit": 10})
+	require.True(t, ok)
+	second, ok := ResultCacheKey("invoice_list", map[string]interface{}{"limit": 10.0, "status": "paid"})
+	require.True(t, ok)
+	assert.Equal(t, first, second, "key order and numeric type must not matter")
+
+	other, ok := ResultCacheKey("client_list", map[string]interface{}{"status": "paid", "limit": 10})
+	require.True(t, ok)
+	assert.NotEqual(t, first, other)
+
+	empty, ok := ResultCacheKey("invoice_list", nil)
+	require.True(t, ok)
+	emptyMap, ok := ResultCacheKey("invoice_list", map[string]interface{}{})
+	require.True(t, ok)
+	assert.Equal(t, empty, emptyMap)
+}
+
+func TestRegistryResultCache(t *testing.T) {
+	ctx := context.Background()
+	logger := new(MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	registry := NewDefaultToolRegistry(new(MockInputValidator), logger)
+	newTool := func(name string, ttl time.Duration) *MCPTool {
+		return &MCPTool{
+			Name:        name,
+			Description: name,
+			InputSchema: map[string]interface{}{keyType: keyObject},
+			Category:    CategoryInvoiceManagement,
+			CLICommand:  toolCLIName,
+			Version:     toolVersion,
+			Timeout:     10 * time.Second,
+			CacheTTL:    ttl,
+		}
+	}
+	require.NoError(t, registry.RegisterTool(ctx, newTool("invoice_list", time.Minute)))
+	require.NoError(t, registry.RegisterTool(ctx, newTool("invoice_create", 0)))
+
+	input := map[string]interface{}{"status": "paid"}
+
+	t.Run("DisabledByDefault", func(t *testing.T) {
+		registry.RecordResult(ctx, "invoice_list", input, "result")
+		_, ok := registry.CachedResult(ctx, "invoice_list", input)
+		assert.False(t, ok)
+	})
+
+	registry.EnableResultCache(4)
+
+	t.Run("CachesReadOnlyTools", func(t *testing.T) {
+		registry.RecordResult(ctx, "invoice_list", input, "result")
+		value, ok := registry.CachedResult(ctx, "invoice_list", input)
+		require.True(t, ok)
+		assert.Equal(t, "result", value)
+	})
+
+	t.Run("NeverCachesMutatingTools", func(t *testing.T) {
+		registry.RecordResult(ctx, "invoice_create", input, "created")
+		_, ok := registry.CachedResult(ctx, "invoice_create", input)
+		assert.False(t, ok)
+
+		_, ok = registry.CachedResult(ctx, "invoice_list", input)
+		assert.False(t, ok, "a mutating tool call should clear cached reads")
+	})
+
+	t.Run("NegativeTTLRejected", func(t *testing.T) {
+		logger.On("Error", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		err := registry.RegisterTool(ctx, newTool("invoice_bad", -time.Second))
+		require.ErrorIs(t, err, ErrToolCacheTTLInvalid)
+	})
+
+	t.Run("UnknownOutputTypeRejected", func(t *testing.T) {
+		tool := newTool("invoice_yaml", 0)
+		tool.OutputType = "yaml"
+		require.ErrorIs(t, registry.RegisterTool(ctx, tool), ErrToolOutputTypeInvalid)
+	})
+
+	hits, misses := registry.ResultCacheStats()
+	assert.Equal(t, uint64(1), hits)
+	assert.Equal(t, uint64(1), misses, "calls to uncacheable tools are not counted")
+}
+
+func TestOnlyReadOnlyToolsAreCached(t *testing.T) {
+	readOnly := map[string]bool{
+		"invoice_list": true,
+		"invoice_show": true,
+		"client_list":  true,
+		"client_show":  true,
+		"config_show":  true,
+	}
+
+	var all []*MCPTool
+	all = append(all, CreateInvoiceManagementTools()...)
+	all = append(all, CreateClientManagementTools()...)
+	all = append(all, CreateDataImportTools()...)
+	all = append(all, CreateDocumentGenerationTools()...)
+	all = append(all, CreateConfigurationManagementTools()...)
+
+	for _, tool := range all {
+		if readOnly[tool.Name] {
+			assert.Positive(t, tool.CacheTTL, "%s should be cached", tool.Name)
+		} else {
+			assert.Zero(t, tool.CacheTTL, "%s changes data or files and must not be cached", tool.Name)
+		}
+	}
+}
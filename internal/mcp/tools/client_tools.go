@@ -191,6 +191,8 @@ func createClientListTool() *MCPTool {
 		HelpText:   "Provides comprehensive client discovery with business intelligence features. Supports name search, status filtering, and various export formats for CRM integration and business analysis.",
 		Version:    toolVersion,
 		Timeout:    20 * time.Second,
+		CacheTTL:   readOnlyCacheTTL,
+		OutputType: OutputTypeJSON,
 	}
 }
 
@@ -266,6 +268,8 @@ func createClientShowTool() *MCPTool {
 		HelpText:   "Displays comprehensive client information with configurable detail levels and business intelligence. Supports lookup by client ID, name, or email with financial analytics.",
 		Version:    toolVersion,
 		Timeout:    15 * time.Second,
+		CacheTTL:   readOnlyCacheTTL,
+		OutputType: OutputTypeJSON,
 	}
 }
 
@@ -128,6 +128,8 @@ func createConfigShowTool() *MCPTool {
 		HelpText:   "Displays system configuration with flexible formatting and filtering. Supports sensitive data protection and environment-specific views for troubleshooting.",
 		Version:    toolVersion,
 		Timeout:    20 * time.Second,
+		CacheTTL:   readOnlyCacheTTL,
+		OutputType: OutputTypeJSON,
 	}
 }
 
@@ -1,5 +1,10 @@
 package tools
 
+import "time"
+
+// readOnlyCacheTTL is how long results of read-only tools such as listings are reused
+const readOnlyCacheTTL = 30 * time.Second
+
 const (
 	// Tool metadata
 	toolCLIName = "go-invoice"
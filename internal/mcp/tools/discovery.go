@@ -54,6 +54,9 @@ type ToolDiscoveryService struct {
 
 	// searchIndex maintains optimized search data structures
 	searchIndex *ToolSearchIndex
+
+	// recommendations scores tools against free-text goals
+	recommendations *recommendationIndex
 }
 
 // ToolSearchIndex provides optimized data structures for tool search operations.
@@ -301,25 +304,26 @@ func (s *ToolDiscoveryService) DiscoverToolsByCategory(ctx context.Context, cate
 	return result, nil
 }
 
-// GetToolRecommendations provides intelligent tool recommendations based on context.
+// GetToolRecommendations recommends tools for a goal described in natural language.
 //
-// This method analyzes usage patterns and tool relationships to provide
-// relevant tool recommendations for different workflows.
+// Tools are ranked by TF-IDF relevance of the goal's words to each tool's name, category
+// and description, with name matches counting most. Rare words such as "csv" weigh more
+// than words shared by many tools such as "invoice".
 //
 // Parameters:
 // - ctx: Context for cancellation and timeout
-// - context: Usage context or workflow description
+// - context: Goal or workflow description, e.g. "create and send invoices"
 // - limit: Maximum number of recommendations to return
 //
 // Returns:
-// - []ToolRecommendation: Ranked tool recommendations with rationale
-// - error: Recommendation error or context cancellation
+// - []ToolRecommendation: Recommendations ranked by confidence, with rationale
+// - error: Context cancellation
 //
 // Notes:
-// - Uses intelligent algorithms to match tools to workflows
-// - Provides rationale for each recommendation
-// - Considers tool popularity and usage patterns
-// - Optimized for workflow-based tool discovery
+//   - Confidence (0.0 to 1.0) is the share of the goal the tool covers
+//   - Results are deterministic; equal confidence ranks by tool name
+//   - An empty goal, or one that matches no tool well, returns no recommendations
+//     rather than arbitrary tools
 func (s *ToolDiscoveryService) GetToolRecommendations(ctx context.Context, context string, limit int) ([]ToolRecommendation, error) {
 	select {
 	case <-ctx.Done():
@@ -335,16 +339,11 @@ func (s *ToolDiscoveryService) GetToolRecommendations(ctx context.Context, conte
 		"context", context,
 		"limit", limit)
 
-	// Analyze context for workflow patterns
-	workflow := s.analyzeWorkflowContext(context)
-
-	// Get recommendations based on workflow analysis
-	recommendations := s.generateRecommendations(workflow, limit)
+	recommendations := s.recommendations.recommend(context, limit)
 
 	s.logger.Debug("tool recommendations generated",
 		"context", context,
-		"recommendationCount", len(recommendations),
-		"workflow", workflow)
+		"recommendationCount", len(recommendations))
 
 	return recommendations, nil
 }
@@ -381,6 +380,7 @@ func (s *ToolDiscoveryService) buildSearchIndex(ctx context.Context) error {
 	for _, tool := range allTools {
 		s.indexTool(tool)
 	}
+	s.recommendations = newRecommendationIndex(allTools)
 
 	s.logger.Debug("search index built successfully",
 		"toolCount", len(allTools),
@@ -684,49 +684,6 @@ func (s *ToolDiscoveryService) getRecommendedToolsForCategory(_ CategoryType, to
 	return tools[:limit]
 }
 
-func (s *ToolDiscoveryService) analyzeWorkflowContext(context string) string {
-	// Simple workflow analysis
-	context = strings.ToLower(context)
-	if strings.Contains(context, fieldInvoice) {
-		return "invoice_management"
-	}
-	if strings.Contains(context, fieldClient) {
-		return "client_management"
-	}
-	if strings.Contains(context, "import") {
-		return "data_import"
-	}
-	if strings.Contains(context, "export") || strings.Contains(context, "generate") {
-		return "data_export"
-	}
-	if strings.Contains(context, fieldConfig) {
-		return "configuration"
-	}
-	return "general"
-}
-
-func (s *ToolDiscoveryService) generateRecommendations(workflow string, limit int) []ToolRecommendation {
-	// Simple recommendation logic based on workflow
-	var recommendations []ToolRecommendation
-
-	switch workflow {
-	case "invoice_management":
-		recommendations = append(recommendations, ToolRecommendation{
-			Confidence: 0.9,
-			Rationale:  "Essential for invoice creation workflow",
-			UseCase:    "Creating new invoices for clients",
-		})
-	case "client_management":
-		recommendations = append(recommendations, ToolRecommendation{
-			Confidence: 0.8,
-			Rationale:  "Essential for client management workflow",
-			UseCase:    "Managing client information and contacts",
-		})
-	}
-
-	return recommendations[:minInt(limit, len(recommendations))]
-}
-
 // minInt returns the minimum of two integers.
 func minInt(a, b int) int {
 	if a < b {
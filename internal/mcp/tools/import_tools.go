@@ -213,10 +213,10 @@ func createImportValidateTool() *MCPTool {
 			{
 				Description: "Validate malformed CSV file to identify format errors",
 				Input: map[string]interface{}{
-					fieldFilePath:   "/path/to/corrupt-timesheet.csv",
-					"has_header":    true,
-					"strict_mode":   true,
-					"error_details": true,
+					fieldFilePath:    "/path/to/corrupt-timesheet.csv",
+					"has_header":     true,
+					"validate_dates": true,
+					"validate_rates": true,
 				},
 				ExpectedOutput: "Detailed error report identifying malformed data, invalid fields, and corrupt entries with suggestions for correction",
 				UseCase:        "Error detection and troubleshooting for problematic import files",
@@ -269,7 +269,7 @@ func createImportPreviewTool() *MCPTool {
 				Description: "Preview with custom rate transformations",
 				Input: map[string]interface{}{
 					fieldFilePath:       "/path/to/contractor-hours.csv",
-					fieldClientID:       "contractor_client",
+					fieldClientName:     "Contractor Client",
 					fieldImportMode:     "new_invoice",
 					"default_rate":      125.0,
 					"rate_override":     true,
@@ -281,12 +281,12 @@ func createImportPreviewTool() *MCPTool {
 			{
 				Description: "Preview large import with statistical summary",
 				Input: map[string]interface{}{
-					fieldFilePath:     "/path/to/large-dataset.csv",
-					fieldClientEmail:  "enterprise@company.com",
-					fieldImportMode:   "new_invoice",
-					"summary_only":    true,
-					"show_statistics": true,
-					"sample_preview":  true,
+					fieldFilePath:    "/path/to/large-dataset.csv",
+					fieldClientName:  "Enterprise Company",
+					fieldImportMode:  "new_invoice",
+					"summary_only":   true,
+					"show_totals":    true,
+					"sample_preview": true,
 				},
 				ExpectedOutput: "Statistical summary with sample data preview for large imports",
 				UseCase:        "Understanding large dataset structure before full import",
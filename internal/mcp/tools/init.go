@@ -265,7 +265,7 @@ func (tsi *ToolSystemInitializer) initializeValidator(ctx context.Context) error
 
 	tsi.logger.Debug("initializing input validator")
 
-	validator := NewDefaultInputValidator(tsi.logger)
+	validator := NewJSONSchemaValidator(tsi.logger)
 	if validator == nil {
 		return ErrValidatorCreationFailed
 	}
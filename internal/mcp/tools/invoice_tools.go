@@ -205,6 +205,8 @@ func createInvoiceListTool() *MCPTool {
 		HelpText:   "Provides comprehensive invoice discovery with filtering by status, client, dates, and amounts. Supports table, JSON, and CSV output formats for different workflows.",
 		Version:    toolVersion,
 		Timeout:    20 * time.Second,
+		CacheTTL:   readOnlyCacheTTL,
+		OutputType: OutputTypeJSON,
 	}
 }
 
@@ -263,6 +265,8 @@ func createInvoiceShowTool() *MCPTool {
 		HelpText:   "Displays comprehensive invoice information with configurable detail levels and output formats. Supports lookup by invoice ID or number.",
 		Version:    toolVersion,
 		Timeout:    15 * time.Second,
+		CacheTTL:   readOnlyCacheTTL,
+		OutputType: OutputTypeJSON,
 	}
 }
 
@@ -552,9 +556,9 @@ func createInvoiceAddLineItemTool() *MCPTool {
 						{
 							keyType:          "hourly",
 							fieldDate:        exampleDate,
-							fieldHours:       40.0,
+							fieldHours:       8.0,
 							fieldRate:        125.0,
-							fieldDescription: "Development work - 40 hours",
+							fieldDescription: "Development work",
 						},
 						{
 							keyType:          "fixed",
@@ -0,0 +1,290 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+// JSONSchemaValidator is the InputValidator used by the tool system. It enforces the JSON
+// Schema keywords the tool schemas rely on: type, required, properties,
+// additionalProperties, items, minLength, maxLength, minimum, maximum, pattern and format.
+//
+// Unlike the checks in DefaultInputValidator, it reads schemas written as Go literals the
+// way the schemas package writes them (required as []string, bounds as int or float64),
+// validates every element of an array against its items schema, and reports the full path
+// of the failing field, e.g. work_items[1].rate. Required fields, formats and errors are
+// handled by the embedded DefaultInputValidator, so messages read the same.
+type JSONSchemaValidator struct {
+	*DefaultInputValidator
+}
+
+// NewJSONSchemaValidator creates a schema validator. The logger must be non-nil.
+func NewJSONSchemaValidator(logger Logger) *JSONSchemaValidator {
+	return &JSONSchemaValidator{DefaultInputValidator: NewDefaultInputValidator(logger)}
+}
+
+// ValidateAgainstSchema validates input against an object schema, returning a
+// *ValidationError for the first field that breaks a constraint
+func (v *JSONSchemaValidator) ValidateAgainstSchema(ctx context.Context, input, schema map[string]interface{}) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := v.validateObject(ctx, "", input, schema); err != nil {
+		v.logger.Debug("schema validation failed", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// validateValue checks value at path against schema
+func (v *JSONSchemaValidator) validateValue(ctx context.Context, path string, value interface{}, schema map[string]interface{}) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := v.validateType(ctx, path, value, schema[keyType]); err != nil {
+		return err
+	}
+
+	switch typed := value.(type) {
+	case string:
+		return v.validateString(ctx, path, typed, schema)
+	case map[string]interface{}:
+		return v.validateObject(ctx, path, typed, schema)
+	}
+	if number, ok := schemaNumber(value); ok {
+		return v.validateNumber(ctx, path, number, schema)
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		return v.validateArray(ctx, path, value, items)
+	}
+	return nil
+}
+
+// validateType checks the value's JSON type against the schema type, a name or a list of names
+func (v *JSONSchemaValidator) validateType(ctx context.Context, path string, value, schemaType interface{}) error {
+	var allowed []string
+	switch typed := schemaType.(type) {
+	case nil:
+		return nil
+	case string:
+		allowed = []string{typed}
+	case []string:
+		allowed = typed
+	case []interface{}:
+		for _, t := range typed {
+			if name, ok := t.(string); ok {
+				allowed = append(allowed, name)
+			}
+		}
+	}
+
+	actual := jsonTypeOf(value)
+	for _, expected := range allowed {
+		switch {
+		case expected == actual:
+			return nil
+		case expected == typeNumber && actual == "integer":
+			return nil
+		}
+	}
+
+	return v.BuildValidationError(ctx, path,
+		fmt.Sprintf("expected type %s, got %s", joinTypes(allowed), actual),
+		[]string{fmt.Sprintf("provide a value of type %s", joinTypes(allowed))})
+}
+
+// validateObject checks required fields, each declared property and, when the schema
+// forbids them, undeclared properties
+func (v *JSONSchemaValidator) validateObject(ctx context.Context, path string, input, schema map[string]interface{}) error {
+	if required := schemaStrings(schema[keyRequired]); len(required) > 0 {
+		if err := v.ValidateRequired(ctx, input, required); err != nil {
+			var validationErr *ValidationError
+			if path != "" && errors.As(err, &validationErr) {
+				validationErr.Field = path
+			}
+			return err
+		}
+	}
+
+	properties, _ := schema[keyProperties].(map[string]interface{})
+	names := make([]string, 0, len(input))
+	for name := range input {
+		names = append(names, name)
+	}
+	slices.Sort(names) // Report the same field first on every run
+
+	for _, name := range names {
+		fieldSchema, declared := properties[name]
+		if !declared {
+			if schema["additionalProperties"] == false {
+				return v.BuildValidationError(ctx, joinPath(path, name),
+					"unexpected property not allowed by schema",
+					[]string{"remove this property or check if it's misspelled"})
+			}
+			continue
+		}
+		if fieldMap, ok := fieldSchema.(map[string]interface{}); ok {
+			if err := v.validateValue(ctx, joinPath(path, name), input[name], fieldMap); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateArray checks every element of an array against the items schema
+func (v *JSONSchemaValidator) validateArray(ctx context.Context, path string, value interface{}, items map[string]interface{}) error {
+	list := reflect.ValueOf(value)
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return nil
+	}
+
+	for i := range list.Len() {
+		if err := v.validateValue(ctx, fmt.Sprintf("%s[%d]", path, i), list.Index(i).Interface(), items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateString checks length, pattern and format. Length counts characters, not bytes.
+func (v *JSONSchemaValidator) validateString(ctx context.Context, path, value string, schema map[string]interface{}) error {
+	length := utf8.RuneCountInString(value)
+
+	if minLength, ok := schemaNumber(schema["minLength"]); ok && float64(length) < minLength {
+		return v.BuildValidationError(ctx, path,
+			fmt.Sprintf("string too short: minimum length is %d, got %d", int(minLength), length),
+			[]string{fmt.Sprintf("provide a string with at least %d characters", int(minLength))})
+	}
+	if maxLength, ok := schemaNumber(schema["maxLength"]); ok && float64(length) > maxLength {
+		return v.BuildValidationError(ctx, path,
+			fmt.Sprintf("string too long: maximum length is %d, got %d", int(maxLength), length),
+			[]string{fmt.Sprintf("provide a string with at most %d characters", int(maxLength))})
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		matched, err := regexp.MatchString(pattern, value)
+		if err != nil {
+			return v.BuildValidationError(ctx, path, "invalid pattern in schema", []string{"check schema pattern definition"})
+		}
+		if !matched {
+			return v.BuildValidationError(ctx, path,
+				fmt.Sprintf("string does not match required pattern: %s", pattern),
+				[]string{"provide a string that matches the required pattern"})
+		}
+	}
+
+	if format, ok := schema[fieldFormat].(string); ok && value != "" {
+		return v.ValidateFormat(ctx, path, value, format)
+	}
+	return nil
+}
+
+// validateNumber checks the minimum and maximum bounds, both inclusive
+func (v *JSONSchemaValidator) validateNumber(ctx context.Context, path string, value float64, schema map[string]interface{}) error {
+	if minimum, ok := schemaNumber(schema["minimum"]); ok && value < minimum {
+		return v.BuildValidationError(ctx, path,
+			fmt.Sprintf("value too small: minimum is %g, got %g", minimum, value),
+			[]string{fmt.Sprintf("provide a value >= %g", minimum)})
+	}
+	if maximum, ok := schemaNumber(schema["maximum"]); ok && value > maximum {
+		return v.BuildValidationError(ctx, path,
+			fmt.Sprintf("value too large: maximum is %g, got %g", maximum, value),
+			[]string{fmt.Sprintf("provide a value <= %g", maximum)})
+	}
+	return nil
+}
+
+// jsonTypeOf returns the JSON Schema type of a decoded or Go-literal value. Whole numbers
+// are "integer", which also satisfies "number".
+func jsonTypeOf(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	if number, ok := schemaNumber(value); ok {
+		if number == math.Trunc(number) && !math.IsInf(number, 0) {
+			return "integer"
+		}
+		return typeNumber
+	}
+
+	switch value.(type) {
+	case string:
+		return typeString
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return keyObject
+	}
+	if kind := reflect.TypeOf(value).Kind(); kind == reflect.Slice || kind == reflect.Array {
+		return "array"
+	}
+	return "unknown"
+}
+
+// schemaNumber converts the numeric types found in decoded input and Go-literal schemas
+func schemaNumber(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// schemaStrings reads a list of names written as []string or decoded as []interface{}
+func schemaStrings(value interface{}) []string {
+	switch typed := value.(type) {
+	case []string:
+		return typed
+	case []interface{}:
+		names := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// joinPath appends a property name to a field path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// joinTypes formats the allowed types for an error message
+func joinTypes(types []string) string {
+	if len(types) == 1 {
+		return types[0]
+	}
+	return fmt.Sprintf("one of %v", types)
+}
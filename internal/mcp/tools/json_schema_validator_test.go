@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/mcp/schemas"
+)
+
+func newTestJSONSchemaValidator() *JSONSchemaValidator {
+	return NewJSONSchemaValidator(&TestLogger{})
+}
+
+// decodeInput parses a payload the way MCP requests arrive, so numbers are float64 and
+// nested values are []interface{} and map[string]interface{}
+func decodeInput(t *testing.T, payload string) map[string]interface{} {
+	t.Helper()
+	var input map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(payload), &input))
+	return input
+}
+
+func TestJSONSchemaValidatorInvoiceCreate(t *testing.T) {
+	validator := newTestJSONSchemaValidator()
+	schema := schemas.InvoiceCreateSchema()
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		payload string
+		field   string // Empty when the payload is valid
+	}{
+		{
+			name:    "valid",
+			payload: `{"client_name": "Acme Corp", "client_email": "billing@acme.com", "tax_rate": 0.1, "work_items": [{"date": "2025-08-01", "hours": 8, "rate": 125, "description": "Development"}]}`,
+		},
+		{
+			name:    "negative rate",
+			payload: `{"client_name": "Acme Corp", "work_items": [{"date": "2025-08-01", "hours": 8, "rate": -125, "description": "Development"}]}`,
+			field:   "work_items[0].rate",
+		},
+		{
+			name:    "hours over a day",
+			payload: `{"client_name": "Acme Corp", "work_items": [{"date": "2025-08-01", "hours": 8, "rate": 125, "description": "Development"}, {"date": "2025-08-02", "hours": 25, "rate": 125, "description": "Review"}]}`,
+			field:   "work_items[1].hours",
+		},
+		{
+			name:    "missing required work item field",
+			payload: `{"client_name": "Acme Corp", "work_items": [{"date": "2025-08-01", "hours": 8, "description": "Development"}]}`,
+			field:   "work_items[0]",
+		},
+		{
+			name:    "bad email",
+			payload: `{"client_name": "Acme Corp", "client_email": "not-an-email"}`,
+			field:   "client_email",
+		},
+		{
+			name:    "client name too long",
+			payload: `{"client_name": "` + strings.Repeat("a", 201) + `"}`,
+			field:   "client_name",
+		},
+		{
+			name:    "empty client name",
+			payload: `{"client_name": ""}`,
+			field:   "client_name",
+		},
+		{
+			name:    "wrong type",
+			payload: `{"client_name": "Acme Corp", "tax_rate": "ten percent"}`,
+			field:   "tax_rate",
+		},
+		{
+			name:    "unknown property",
+			payload: `{"client_name": "Acme Corp", "client_nmae": "typo"}`,
+			field:   "client_nmae",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateAgainstSchema(ctx, decodeInput(t, tt.payload), schema)
+			if tt.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr), "expected a validation error, got %v", err)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
+
+func TestJSONSchemaValidatorConstraintTypes(t *testing.T) {
+	validator := newTestJSONSchemaValidator()
+	ctx := context.Background()
+
+	// Bounds written as int in Go-literal schemas are enforced like decoded float64 bounds
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name":  map[string]interface{}{"type": "string", "minLength": 2, "maxLength": 3},
+			"count": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": int64(10)},
+		},
+		"required": []interface{}{"name"},
+	}
+
+	require.NoError(t, validator.ValidateAgainstSchema(ctx, map[string]interface{}{"name": "ééé", "count": 10}, schema),
+		"length counts characters, not bytes")
+	require.Error(t, validator.ValidateAgainstSchema(ctx, map[string]interface{}{"name": "a"}, schema))
+	require.Error(t, validator.ValidateAgainstSchema(ctx, map[string]interface{}{"name": "abcd"}, schema))
+	require.Error(t, validator.ValidateAgainstSchema(ctx, map[string]interface{}{"name": "ab", "count": 11}, schema))
+	require.Error(t, validator.ValidateAgainstSchema(ctx, map[string]interface{}{"name": "ab", "count": 1.5}, schema),
+		"integer rejects fractions")
+	require.Error(t, validator.ValidateAgainstSchema(ctx, map[string]interface{}{"count": 2}, schema),
+		"required decoded as []interface{}")
+}
+
+func TestJSONSchemaValidatorToolExamples(t *testing.T) {
+	validator := newTestJSONSchemaValidator()
+	ctx := context.Background()
+
+	// Every example shown to the client must pass its own tool's schema
+	var tools []*MCPTool
+	tools = append(tools, CreateInvoiceManagementTools()...)
+	tools = append(tools, CreateClientManagementTools()...)
+	tools = append(tools, CreateDataImportTools()...)
+	tools = append(tools, CreateDocumentGenerationTools()...)
+	tools = append(tools, CreateConfigurationManagementTools()...)
+
+	for _, tool := range tools {
+		for _, example := range tool.Examples {
+			assert.NoError(t, validator.ValidateAgainstSchema(ctx, example.Input, tool.InputSchema),
+				"%s example %q", tool.Name, example.Description)
+		}
+	}
+}
+
+func TestInitializeToolSystemUsesJSONSchemaValidator(t *testing.T) {
+	components, err := InitializeToolSystem(context.Background(), &TestLogger{})
+	require.NoError(t, err)
+	assert.IsType(t, &JSONSchemaValidator{}, components.Validator)
+
+	input := decodeInput(t, `{"client_name": "Acme Corp", "work_items": [{"date": "2025-08-01", "hours": 8, "rate": -1, "description": "Development"}]}`)
+	require.Error(t, components.Registry.ValidateToolInput(context.Background(), "invoice_create", input))
+}
@@ -0,0 +1,232 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Field weights for recommendation scoring. A goal word found in a tool's name says more
+// about the tool than the same word in its category, and far more than one in its
+// description.
+const (
+	recommendNameWeight        = 3.0
+	recommendCategoryWeight    = 2.0
+	recommendDescriptionWeight = 1.0
+
+	// minRecommendationConfidence drops tools that cover too little of the goal to be
+	// worth suggesting
+	minRecommendationConfidence = 0.2
+)
+
+// recommendStopWords are common words in goals that say nothing about which tool fits
+var recommendStopWords = map[string]bool{
+	"about": true, "all": true, "and": true, "any": true, "are": true, "can": true,
+	"for": true, "from": true, "help": true, "how": true, "into": true, "like": true,
+	"need": true, "new": true, "our": true, "please": true, "some": true, "that": true,
+	"the": true, "their": true, "them": true, "then": true, "this": true, "use": true,
+	"using": true, "want": true, "what": true, "when": true, "which": true, "will": true,
+	"with": true, "would": true, "you": true, "your": true,
+}
+
+// recommendationIndex scores tools against a free-text goal with TF-IDF over their
+// names, categories and descriptions. It is built once with the search index.
+type recommendationIndex struct {
+	tools []*MCPTool
+
+	// weights maps each tool (by position in tools) to the summed field weight of each
+	// term it contains
+	weights []map[string]float64
+
+	// names holds the terms of each tool's name, for the rationale
+	names []map[string]bool
+
+	// docFreq counts the tools containing each term
+	docFreq map[string]int
+}
+
+// goalTerm is a distinct stemmed term of a goal with the word it came from
+type goalTerm struct {
+	stem string
+	word string
+	idf  float64
+}
+
+// newRecommendationIndex indexes tools in name order, so that equal scores rank
+// deterministically
+func newRecommendationIndex(tools []*MCPTool) *recommendationIndex {
+	tools = append([]*MCPTool(nil), tools...)
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+
+	index := &recommendationIndex{
+		tools:   tools,
+		weights: make([]map[string]float64, len(tools)),
+		names:   make([]map[string]bool, len(tools)),
+		docFreq: make(map[string]int),
+	}
+
+	for i, tool := range tools {
+		weights := make(map[string]float64)
+		addField := func(text string, weight float64) {
+			seen := make(map[string]bool)
+			for _, term := range recommendTerms(text) {
+				if !seen[term.stem] {
+					seen[term.stem] = true
+					weights[term.stem] += weight
+				}
+			}
+		}
+		addField(tool.Name, recommendNameWeight)
+		addField(string(tool.Category), recommendCategoryWeight)
+		addField(tool.Description, recommendDescriptionWeight)
+
+		index.weights[i] = weights
+		index.names[i] = make(map[string]bool)
+		for _, term := range recommendTerms(tool.Name) {
+			index.names[i][term.stem] = true
+		}
+		for stem := range weights {
+			index.docFreq[stem]++
+		}
+	}
+
+	return index
+}
+
+// recommend returns up to limit tools for goal, best first. Confidence is the share of
+// the goal's IDF-weighted terms the tool covers, where a term counts fully when it is in
+// the tool's name and partly when it only appears in the category or description.
+// Goals with no meaningful words, or that match no tool well, return nothing.
+func (idx *recommendationIndex) recommend(goal string, limit int) []ToolRecommendation {
+	terms := idx.goalTerms(goal)
+	if len(terms) == 0 {
+		return []ToolRecommendation{}
+	}
+
+	var totalIDF float64
+	for _, term := range terms {
+		totalIDF += term.idf
+	}
+
+	recommendations := make([]ToolRecommendation, 0, len(idx.tools))
+	for i, tool := range idx.tools {
+		var score float64
+		var inName, elsewhere []string
+		for _, term := range terms {
+			weight := idx.weights[i][term.stem]
+			if weight == 0 {
+				continue
+			}
+			score += term.idf * math.Min(weight/recommendNameWeight, 1)
+			if idx.names[i][term.stem] {
+				inName = append(inName, term.word)
+			} else {
+				elsewhere = append(elsewhere, term.word)
+			}
+		}
+
+		confidence := score / totalIDF
+		if confidence < minRecommendationConfidence {
+			continue
+		}
+
+		recommendations = append(recommendations, ToolRecommendation{
+			Tool:       tool,
+			Confidence: math.Round(confidence*100) / 100,
+			Rationale:  recommendRationale(inName, elsewhere, len(terms)),
+			UseCase:    recommendUseCase(tool),
+		})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Confidence > recommendations[j].Confidence
+	})
+	return recommendations[:minInt(limit, len(recommendations))]
+}
+
+// goalTerms returns the distinct terms of goal weighted by inverse document frequency.
+// Terms no tool contains keep the highest weight, so a goal that is mostly unknown words
+// gives low confidence everywhere.
+func (idx *recommendationIndex) goalTerms(goal string) []goalTerm {
+	var terms []goalTerm
+	seen := make(map[string]bool)
+	for _, term := range recommendTerms(goal) {
+		if seen[term.stem] {
+			continue
+		}
+		seen[term.stem] = true
+		term.idf = math.Log(1 + float64(len(idx.tools))/float64(idx.docFreq[term.stem]+1))
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// recommendTerms splits text into lowercase words, drops short and stop words, and stems
+// the rest so that "invoices", "invoice" and "invoicing" compare equal
+func recommendTerms(text string) []goalTerm {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]goalTerm, 0, len(words))
+	for _, word := range words {
+		if len(word) < 3 || recommendStopWords[word] {
+			continue
+		}
+		terms = append(terms, goalTerm{stem: stemWord(word), word: word})
+	}
+	return terms
+}
+
+// stemWord strips one common English suffix and a trailing "e", a light stemmer that is
+// enough to match plurals and verb forms of the words used in tool definitions
+func stemWord(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			word = strings.TrimSuffix(word, suffix)
+			break
+		}
+	}
+	if len(word) > 3 {
+		word = strings.TrimSuffix(word, "e")
+	}
+	return word
+}
+
+// recommendRationale explains which goal words a tool matched and where
+func recommendRationale(inName, elsewhere []string, goalTerms int) string {
+	var parts []string
+	if len(inName) > 0 {
+		parts = append(parts, "name matches "+quoteWords(inName))
+	}
+	if len(elsewhere) > 0 {
+		parts = append(parts, "description or category mentions "+quoteWords(elsewhere))
+	}
+
+	rationale := "Tool " + strings.Join(parts, " and ")
+	if matched := len(inName) + len(elsewhere); matched < goalTerms {
+		rationale += fmt.Sprintf(" (%d of %d goal terms)", matched, goalTerms)
+	}
+	return rationale
+}
+
+// quoteWords formats words as a quoted, comma-separated list
+func quoteWords(words []string) string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = fmt.Sprintf("%q", word)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// recommendUseCase returns the use case of the tool's first example that names one
+func recommendUseCase(tool *MCPTool) string {
+	for _, example := range tool.Examples {
+		if example.UseCase != "" {
+			return example.UseCase
+		}
+	}
+	return ""
+}
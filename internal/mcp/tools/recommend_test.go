@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRecommendationService returns a discovery service over every registered tool
+func newRecommendationService(t *testing.T) *ToolDiscoveryService {
+	t.Helper()
+
+	components, err := InitializeToolSystem(context.Background(), &TestLogger{})
+	require.NoError(t, err)
+	return components.DiscoveryService
+}
+
+func TestGetToolRecommendationsRanking(t *testing.T) {
+	service := newRecommendationService(t)
+	ctx := context.Background()
+
+	t.Run("CreateAndSendInvoices", func(t *testing.T) {
+		recommendations, err := service.GetToolRecommendations(ctx, "I need to create and send invoices", 10)
+		require.NoError(t, err)
+		require.NotEmpty(t, recommendations)
+		assert.Equal(t, "invoice_create", recommendations[0].Tool.Name)
+
+		// Every invoice tool that is recommended outranks every configuration tool
+		lastInvoice, firstConfig := -1, len(recommendations)
+		for i, rec := range recommendations {
+			switch rec.Tool.Category {
+			case CategoryInvoiceManagement:
+				lastInvoice = i
+			case CategoryConfiguration:
+				firstConfig = min(firstConfig, i)
+			}
+		}
+		assert.Less(t, lastInvoice, firstConfig)
+	})
+
+	t.Run("RareTermsDecide", func(t *testing.T) {
+		recommendations, err := service.GetToolRecommendations(ctx, "import hours from a csv timesheet", 3)
+		require.NoError(t, err)
+		require.NotEmpty(t, recommendations)
+		assert.Equal(t, "import_csv", recommendations[0].Tool.Name)
+	})
+
+	t.Run("FullMatch", func(t *testing.T) {
+		recommendations, err := service.GetToolRecommendations(ctx, "validate configuration", 3)
+		require.NoError(t, err)
+		require.NotEmpty(t, recommendations)
+		assert.Equal(t, "config_validate", recommendations[0].Tool.Name)
+		assert.InDelta(t, 1.0, recommendations[0].Confidence, 1e-9)
+	})
+
+	t.Run("ConfidenceAndRationale", func(t *testing.T) {
+		recommendations, err := service.GetToolRecommendations(ctx, "show me my clients", 5)
+		require.NoError(t, err)
+		require.NotEmpty(t, recommendations)
+		assert.Equal(t, "client_show", recommendations[0].Tool.Name)
+		assert.Contains(t, recommendations[0].Rationale, `"clients"`)
+
+		for i, rec := range recommendations {
+			assert.GreaterOrEqual(t, rec.Confidence, minRecommendationConfidence)
+			assert.LessOrEqual(t, rec.Confidence, 1.0)
+			assert.NotEmpty(t, rec.Rationale)
+			if i > 0 {
+				assert.LessOrEqual(t, rec.Confidence, recommendations[i-1].Confidence)
+			}
+		}
+	})
+
+	t.Run("Deterministic", func(t *testing.T) {
+		first, err := service.GetToolRecommendations(ctx, "generate an html invoice for a client", 5)
+		require.NoError(t, err)
+		for range 10 {
+			again, err := service.GetToolRecommendations(ctx, "generate an html invoice for a client", 5)
+			require.NoError(t, err)
+			assert.Equal(t, first, again)
+		}
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		recommendations, err := service.GetToolRecommendations(ctx, "invoice client import export config", 2)
+		require.NoError(t, err)
+		assert.Len(t, recommendations, 2)
+	})
+}
+
+func TestGetToolRecommendationsWithoutMeaningfulGoal(t *testing.T) {
+	service := newRecommendationService(t)
+
+	for _, goal := range []string{"", "   ", "xyzzy plugh frobnicate", "the and of", "?!"} {
+		t.Run(goal, func(t *testing.T) {
+			recommendations, err := service.GetToolRecommendations(context.Background(), goal, 5)
+			require.NoError(t, err)
+			assert.Empty(t, recommendations)
+		})
+	}
+
+	t.Run("MostlyGarbage", func(t *testing.T) {
+		recommendations, err := service.GetToolRecommendations(context.Background(), "xyzzy plugh frobnicate quux invoice", 5)
+		require.NoError(t, err)
+		for _, rec := range recommendations {
+			assert.Less(t, rec.Confidence, 0.5, rec.Tool.Name)
+		}
+	})
+}
+
+func TestStemWord(t *testing.T) {
+	tests := []struct {
+		words []string
+		stem  string
+	}{
+		{[]string{"invoice", "invoices", "invoiced", "invoicing"}, "invoic"},
+		{[]string{"create", "creates", "created", "creating"}, "creat"},
+		{[]string{"client", "clients"}, "client"},
+		{[]string{"csv"}, "csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stem, func(t *testing.T) {
+			for _, word := range tt.words {
+				assert.Equal(t, tt.stem, stemWord(word), word)
+			}
+		})
+	}
+}
+
+func TestRecommendRationale(t *testing.T) {
+	assert.Equal(t, `Tool name matches "create", "invoices" (2 of 3 goal terms)`,
+		recommendRationale([]string{"create", "invoices"}, nil, 3))
+	assert.Equal(t, `Tool name matches "import" and description or category mentions "timesheet"`,
+		recommendRationale([]string{"import"}, []string{"timesheet"}, 2))
+	assert.True(t, strings.HasPrefix(recommendRationale(nil, []string{"csv"}, 1), "Tool description"))
+}
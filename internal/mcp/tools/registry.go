@@ -21,6 +21,8 @@ var (
 	ErrToolCategoryInvalid   = errors.New("invalid tool category")
 	ErrToolTimeoutInvalid    = errors.New("tool timeout must be between 1 second and 10 minutes")
 	ErrToolSchemaTypeInvalid = errors.New("tool input schema type must be 'object'")
+	ErrToolCacheTTLInvalid   = errors.New("tool cache TTL cannot be negative")
+	ErrToolOutputTypeInvalid = errors.New("invalid tool output type")
 	ErrValidatorNil          = errors.New("validator cannot be nil")
 	ErrLoggerNil             = errors.New("logger cannot be nil")
 )
@@ -55,6 +57,9 @@ type DefaultToolRegistry struct {
 	// logger provides structured logging for registry operations
 	logger Logger
 
+	// results caches results of read-only tools; nil when caching is disabled
+	results *ResultCache
+
 	// mu protects concurrent access to tools and categories maps
 	mu sync.RWMutex
 }
@@ -452,6 +457,16 @@ func (r *DefaultToolRegistry) validateToolDefinition(tool *MCPTool) error {
 		return fmt.Errorf("%w, got: %v", ErrToolTimeoutInvalid, tool.Timeout)
 	}
 
+	if tool.CacheTTL < 0 {
+		return fmt.Errorf("%w, got: %v", ErrToolCacheTTLInvalid, tool.CacheTTL)
+	}
+
+	switch tool.OutputType {
+	case "", OutputTypeText, OutputTypeJSON:
+	default:
+		return fmt.Errorf("%w: %s", ErrToolOutputTypeInvalid, tool.OutputType)
+	}
+
 	// Validate input schema structure
 	if schemaType, exists := tool.InputSchema[keyType]; exists {
 		if schemaType != keyObject {
@@ -330,10 +330,14 @@ func (r *CompleteToolRegistry) GetRegistrationMetrics(ctx context.Context) (*Reg
 	default:
 	}
 
+	cacheHits, cacheMisses := r.ResultCacheStats()
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	return &RegistrationMetrics{
+		CacheHits:          cacheHits,
+		CacheMisses:        cacheMisses,
 		TotalTools:         r.toolCount,
 		TotalCategories:    r.categoryCount,
 		InitializationTime: r.initializationTime,
@@ -359,6 +363,8 @@ func (r *CompleteToolRegistry) GetRegistrationMetrics(ctx context.Context) (*Reg
 // - InitializationTime: When the registry was initialized
 // - Uptime: How long the registry has been running
 // - ToolsByCategory: Tool count breakdown by category
+// - CacheHits: Tool calls answered from the result cache
+// - CacheMisses: Cacheable tool calls that had to run the CLI
 //
 // Notes:
 // - Used for monitoring and alerting systems
@@ -370,6 +376,8 @@ type RegistrationMetrics struct {
 	InitializationTime time.Time            `json:"initializationTime"`
 	Uptime             time.Duration        `json:"uptime"`
 	ToolsByCategory    map[CategoryType]int `json:"toolsByCategory"`
+	CacheHits          uint64               `json:"cacheHits"`
+	CacheMisses        uint64               `json:"cacheMisses"`
 }
 
 // Comment: Registration functions are implemented in their respective tool files:
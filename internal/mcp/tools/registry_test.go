@@ -771,6 +771,41 @@ func (s *RegistryTestSuite) TestConcurrentOperations() {
 		}
 		s.Equal(0, errorCount, "Should have no errors for concurrent get operations")
 	})
+
+	s.Run("ConcurrentCachedResults", func() {
+		tool := &MCPTool{
+			Name:        "concurrent_cache_test",
+			Description: "Test tool for concurrent result caching",
+			InputSchema: map[string]interface{}{keyType: keyObject},
+			Category:    CategoryConfiguration,
+			CLICommand:  strTest,
+			Version:     toolVersion,
+			Timeout:     10 * time.Second,
+			CacheTTL:    time.Minute,
+		}
+
+		s.logger.On("Info", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		s.Require().NoError(s.registry.RegisterTool(context.Background(), tool))
+		s.registry.EnableResultCache(8)
+
+		// Readers and writers share a handful of keys so entries are hit, replaced and evicted
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(id int) {
+				defer wg.Done()
+				input := map[string]interface{}{"page": id % 10}
+				if _, ok := s.registry.CachedResult(context.Background(), tool.Name, input); !ok {
+					s.registry.RecordResult(context.Background(), tool.Name, input, id)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		hits, misses := s.registry.ResultCacheStats()
+		s.Equal(uint64(100), hits+misses, "Every cacheable call should count as a hit or a miss")
+		s.LessOrEqual(s.registry.results.Len(), 8, "Cache should stay within its size")
+	})
 }
 
 func (s *RegistryTestSuite) TestToolDefensiveCopying() {
@@ -40,12 +40,15 @@ import (
 // - HelpText: Additional guidance for tool usage
 // - Version: Tool version for compatibility tracking
 // - Timeout: Maximum execution time for this tool
+// - CacheTTL: How long a successful result may be reused; zero disables caching
+// - OutputType: How stdout is returned to the client; empty means OutputTypeText
 //
 // Notes:
 // - All MCPTool instances should be immutable after creation
 // - InputSchema must be valid JSON Schema Draft 7 format
 // - Examples should cover common use cases and edge cases
 // - Category should align with predefined CategoryType values
+// - CacheTTL must only be set on read-only tools, never on tools that change data
 type MCPTool struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
@@ -57,8 +60,22 @@ type MCPTool struct {
 	HelpText    string                 `json:"helpText,omitempty"`
 	Version     string                 `json:"version"`
 	Timeout     time.Duration          `json:"timeout"`
+	CacheTTL    time.Duration          `json:"cacheTTL,omitempty"`
+	OutputType  OutputType             `json:"outputType,omitempty"`
 }
 
+// OutputType describes how a tool's stdout is interpreted for MCP clients.
+type OutputType string
+
+const (
+	// OutputTypeText returns stdout unchanged as a text block
+	OutputTypeText OutputType = "text"
+
+	// OutputTypeJSON parses stdout as JSON and returns it as a json block. Output that is
+	// not valid JSON is reported as an error.
+	OutputTypeJSON OutputType = "json"
+)
+
 // MCPToolExample provides usage examples for Claude to understand tool capabilities.
 //
 // Examples help Claude understand how to use tools effectively and provide context
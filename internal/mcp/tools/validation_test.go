@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+
+	"github.com/mrz1836/go-invoice/internal/mcp/schemas"
 )
 
 // ValidationTestSuite provides comprehensive tests for the input validation system
@@ -166,6 +168,21 @@ func (s *ValidationTestSuite) TestValidateAgainstSchema_BasicValidation() {
 	}
 }
 
+func (s *ValidationTestSuite) TestValidateInvoiceCreateTaxRate() {
+	s.logger.On("Debug", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	ctx := context.Background()
+	schema := schemas.InvoiceCreateSchema()
+
+	for _, rate := range []float64{0, 0.1, 1} {
+		s.NoError(s.validator.ValidateAgainstSchema(ctx, map[string]interface{}{"client_name": "Acme", "tax_rate": rate}, schema), rate)
+	}
+
+	// A percentage such as 10 for 10% is rejected rather than billed at 1000%
+	for _, rate := range []float64{10, -0.1} {
+		s.Error(s.validator.ValidateAgainstSchema(ctx, map[string]interface{}{"client_name": "Acme", "tax_rate": rate}, schema), rate)
+	}
+}
+
 func (s *ValidationTestSuite) TestValidateRequired() {
 	ctx := context.Background()
 	tests := []struct {
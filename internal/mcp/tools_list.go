@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mrz1836/go-invoice/internal/mcp/tools"
+	"github.com/mrz1836/go-invoice/internal/mcp/types"
+)
+
+const (
+	// defaultToolPageSize is the tools/list page size when the request sets no limit. It
+	// exceeds the built-in tool count, so clients that do not page get every tool.
+	defaultToolPageSize = 50
+
+	// maxToolPageSize caps the limit a client may request
+	maxToolPageSize = 100
+
+	// toolCursorPrefix versions the cursor format
+	toolCursorPrefix = "v1:"
+)
+
+var (
+	// ErrInvalidToolListParams is returned for tools/list parameters that cannot be parsed
+	ErrInvalidToolListParams = errors.New("invalid tools/list params")
+
+	// ErrInvalidCursor is returned for a tools/list cursor this server did not issue
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// parseToolListParams reads the optional tools/list parameters. A missing params object
+// lists the first page of all tools.
+func parseToolListParams(raw interface{}) (*ToolListParams, error) {
+	params := &ToolListParams{}
+	if raw == nil {
+		return params, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToolListParams, err)
+	}
+	if err := json.Unmarshal(data, params); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToolListParams, err)
+	}
+	if params.Limit < 0 {
+		return nil, fmt.Errorf("%w: limit must not be negative", ErrInvalidToolListParams)
+	}
+	return params, nil
+}
+
+// invalidToolListParams answers a tools/list request whose parameters cannot be used
+func invalidToolListParams(req *types.MCPRequest, err error) *types.MCPResponse {
+	return &types.MCPResponse{
+		JSONRPC: jsonRPCVersion,
+		ID:      req.ID,
+		Error: &types.MCPError{
+			Code:    -32602,
+			Message: "Invalid params",
+			Data:    err.Error(),
+		},
+	}
+}
+
+// encodeToolCursor returns the cursor for the page following the tool named lastName.
+// The cursor is opaque to clients: the URL-safe base64 of "v1:" and the tool name.
+func encodeToolCursor(lastName string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(toolCursorPrefix + lastName))
+}
+
+// decodeToolCursor returns the name of the last tool on the page before cursor
+func decodeToolCursor(cursor string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	name, ok := strings.CutPrefix(string(data), toolCursorPrefix)
+	if !ok || name == "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	return name, nil
+}
+
+// pageTools filters tools, which must be sorted by name as the registry lists them, and
+// returns one page with the cursor for the next. Because the cursor holds the last tool
+// name rather than an offset, paging stays stable when tools are added or removed
+// between requests.
+func pageTools(all []*tools.MCPTool, params *ToolListParams) ([]*tools.MCPTool, string, error) {
+	after := ""
+	if params.Cursor != "" {
+		name, err := decodeToolCursor(params.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = name
+	}
+
+	limit := params.Limit
+	if limit == 0 {
+		limit = defaultToolPageSize
+	}
+	limit = min(limit, maxToolPageSize)
+
+	// Skip everything up to and including the last tool already returned
+	start := sort.Search(len(all), func(i int) bool { return all[i].Name > after })
+
+	nameFilter := strings.ToLower(params.Name)
+	page := make([]*tools.MCPTool, 0, min(limit, len(all)-start))
+	for _, tool := range all[start:] {
+		if nameFilter != "" && !strings.Contains(strings.ToLower(tool.Name), nameFilter) {
+			continue
+		}
+		if len(page) == limit {
+			return page, encodeToolCursor(page[len(page)-1].Name), nil
+		}
+		page = append(page, tool)
+	}
+	return page, "", nil
+}
@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/mcp/tools"
+	"github.com/mrz1836/go-invoice/internal/mcp/types"
+)
+
+// newToolListHandler returns a production handler over a registry holding every tool
+func newToolListHandler(t *testing.T) *ProductionMCPHandler {
+	t.Helper()
+
+	ctx := context.Background()
+	logger := NewTestLogger()
+	registry := tools.NewDefaultToolRegistry(tools.NewJSONSchemaValidator(logger), logger)
+	require.NoError(t, tools.RegisterInvoiceManagementTools(ctx, registry))
+	require.NoError(t, tools.RegisterClientManagementTools(ctx, registry))
+	require.NoError(t, tools.RegisterDataImportTools(ctx, registry))
+	require.NoError(t, tools.RegisterDocumentGenerationTools(ctx, registry))
+	require.NoError(t, tools.RegisterConfigTools(ctx, registry))
+
+	return &ProductionMCPHandler{logger: logger, toolRegistry: registry}
+}
+
+// listTools sends a tools/list request with params and returns its result
+func listTools(t *testing.T, handler *ProductionMCPHandler, params interface{}) ToolListResult {
+	t.Helper()
+
+	resp, err := handler.HandleToolsList(context.Background(), &types.MCPRequest{
+		JSONRPC: jsonRPCVersion,
+		ID:      1,
+		Method:  methodToolsList,
+		Params:  params,
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp.Error)
+
+	result, ok := resp.Result.(ToolListResult)
+	require.True(t, ok, "Result should be ToolListResult")
+	return result
+}
+
+// toolNames returns the names of listed tools
+func toolNames(list []Tool) []string {
+	names := make([]string, 0, len(list))
+	for _, tool := range list {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestHandleToolsListWithoutParams(t *testing.T) {
+	handler := newToolListHandler(t)
+	all, err := handler.toolRegistry.ListTools(context.Background(), "")
+	require.NoError(t, err)
+
+	result := listTools(t, handler, nil)
+	assert.Len(t, result.Tools, len(all))
+	assert.IsNonDecreasing(t, toolNames(result.Tools))
+	assert.Empty(t, result.NextCursor)
+}
+
+func TestHandleToolsListPaging(t *testing.T) {
+	handler := newToolListHandler(t)
+	everything := toolNames(listTools(t, handler, nil).Tools)
+
+	var paged []string
+	params := map[string]interface{}{"limit": 5}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, len(everything), "paging did not terminate")
+
+		result := listTools(t, handler, params)
+		assert.LessOrEqual(t, len(result.Tools), 5)
+		paged = append(paged, toolNames(result.Tools)...)
+		if result.NextCursor == "" {
+			break
+		}
+		params = map[string]interface{}{"limit": 5, "cursor": result.NextCursor}
+	}
+
+	assert.Equal(t, everything, paged)
+}
+
+func TestHandleToolsListFilters(t *testing.T) {
+	handler := newToolListHandler(t)
+
+	t.Run("Category", func(t *testing.T) {
+		result := listTools(t, handler, map[string]interface{}{"category": string(tools.CategoryClientManagement)})
+		require.NotEmpty(t, result.Tools)
+		for _, name := range toolNames(result.Tools) {
+			assert.True(t, strings.HasPrefix(name, "client_"), name)
+		}
+	})
+
+	t.Run("UnknownCategory", func(t *testing.T) {
+		result := listTools(t, handler, map[string]interface{}{"category": "nonexistent"})
+		assert.Empty(t, result.Tools)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("NameSubstringIgnoresCase", func(t *testing.T) {
+		result := listTools(t, handler, map[string]interface{}{"name": "ITEM"})
+		assert.Contains(t, toolNames(result.Tools), "invoice_add_item")
+		for _, name := range toolNames(result.Tools) {
+			assert.Contains(t, name, "item")
+		}
+	})
+
+	t.Run("FilteredPages", func(t *testing.T) {
+		first := listTools(t, handler, map[string]interface{}{"name": "invoice", "limit": 2})
+		require.Len(t, first.Tools, 2)
+		require.NotEmpty(t, first.NextCursor)
+
+		second := listTools(t, handler, map[string]interface{}{"name": "invoice", "limit": 2, "cursor": first.NextCursor})
+		require.NotEmpty(t, second.Tools)
+		assert.Greater(t, second.Tools[0].Name, first.Tools[1].Name)
+		for _, name := range toolNames(second.Tools) {
+			assert.Contains(t, name, "invoice")
+		}
+	})
+}
+
+func TestHandleToolsListInvalidParams(t *testing.T) {
+	handler := newToolListHandler(t)
+
+	tests := []struct {
+		name   string
+		params interface{}
+	}{
+		{"MalformedCursor", map[string]interface{}{"cursor": "%%%"}},
+		{"ForeignCursor", map[string]interface{}{"cursor": "b2Zmc2V0PTEw"}},
+		{"NegativeLimit", map[string]interface{}{"limit": -1}},
+		{"WrongType", map[string]interface{}{"limit": "ten"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := handler.HandleToolsList(context.Background(), &types.MCPRequest{
+				JSONRPC: jsonRPCVersion,
+				ID:      1,
+				Method:  methodToolsList,
+				Params:  tt.params,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, resp.Error)
+			assert.Equal(t, -32602, resp.Error.Code)
+		})
+	}
+}
+
+func TestHandleToolsListContextCancellation(t *testing.T) {
+	handler := newToolListHandler(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	resp, err := handler.HandleToolsList(ctx, &types.MCPRequest{
+		JSONRPC: jsonRPCVersion,
+		ID:      1,
+		Method:  methodToolsList,
+		Params:  map[string]interface{}{"limit": 5},
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, resp)
+}
+
+func TestPageTools(t *testing.T) {
+	all := make([]*tools.MCPTool, 0, 150)
+	for i := range 150 {
+		all = append(all, &tools.MCPTool{Name: fmt.Sprintf("tool_%03d", i)})
+	}
+
+	t.Run("LimitCapped", func(t *testing.T) {
+		page, next, err := pageTools(all, &ToolListParams{Limit: 1000})
+		require.NoError(t, err)
+		assert.Len(t, page, maxToolPageSize)
+		assert.NotEmpty(t, next)
+	})
+
+	t.Run("CursorSurvivesRegistryChanges", func(t *testing.T) {
+		page, next, err := pageTools(all, &ToolListParams{Limit: 10})
+		require.NoError(t, err)
+		require.Equal(t, "tool_009", page[9].Name)
+
+		// A tool sorting before the cursor neither repeats nor shifts the next page
+		changed := append([]*tools.MCPTool{{Name: "tool_000a"}}, all...)
+		page, _, err = pageTools(changed, &ToolListParams{Limit: 10, Cursor: next})
+		require.NoError(t, err)
+		assert.Equal(t, "tool_010", page[0].Name)
+	})
+
+	t.Run("NoCursorOnExactLastPage", func(t *testing.T) {
+		page, next, err := pageTools(all[:20], &ToolListParams{Limit: 10, Cursor: encodeToolCursor("tool_009")})
+		require.NoError(t, err)
+		assert.Len(t, page, 10)
+		assert.Empty(t, next)
+	})
+}
+
+func TestToolCursorRoundTrip(t *testing.T) {
+	name, err := decodeToolCursor(encodeToolCursor("invoice_list"))
+	require.NoError(t, err)
+	assert.Equal(t, "invoice_list", name)
+
+	_, err = decodeToolCursor(encodeToolCursor(""))
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -10,6 +10,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -57,6 +58,10 @@ type Transport interface {
 
 	// IsHealthy checks transport health
 	IsHealthy(ctx context.Context) bool
+
+	// Addr returns the address the transport is listening on, or an empty string for
+	// transports that do not listen
+	Addr() string
 }
 
 // TransportConfig holds configuration for transports
@@ -122,6 +127,11 @@ func (t *StdioTransport) Type() types.TransportType {
 	return types.TransportStdio
 }
 
+// Addr returns an empty string; stdio does not listen on an address
+func (t *StdioTransport) Addr() string {
+	return ""
+}
+
 // Start initializes the stdio transport
 func (t *StdioTransport) Start(_ context.Context) error {
 	t.mu.Lock()
@@ -265,6 +275,7 @@ type HTTPTransport struct {
 	logger     Logger
 	config     *TransportConfig
 	server     *http.Server
+	listener   net.Listener
 	handler    http.Handler
 	mcpHandler types.MCPHandler
 	mu         sync.RWMutex
@@ -323,7 +334,7 @@ func (t *HTTPTransport) Start(ctx context.Context) error {
 		return ErrTransportClosed
 	}
 
-	addr := fmt.Sprintf("%s:%d", t.config.Host, t.config.Port)
+	addr := net.JoinHostPort(t.config.Host, strconv.Itoa(t.config.Port))
 	t.server = &http.Server{
 		Addr:         addr,
 		Handler:      t.handler,
@@ -334,26 +345,58 @@ func (t *HTTPTransport) Start(ctx context.Context) error {
 		},
 	}
 
-	// Start server in background
+	t.logger.Info("starting HTTP transport", "addr", addr)
+
+	// Bind before returning so the address, including an OS-assigned port, is known
+	// and the transport can serve as soon as Start returns
+	lc := &net.ListenConfig{}
+	listener, err := lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	t.listener = listener
+
 	go func() {
-		t.logger.Info("starting HTTP transport", "addr", addr)
-		if err := t.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := t.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			t.logger.Error("HTTP server error", "error", err)
 		}
 	}()
-
-	// Wait for server to be ready
-	time.Sleep(100 * time.Millisecond)
 	t.ready = true
 
 	t.logger.Info("HTTP transport started",
-		"host", t.config.Host,
-		"port", t.config.Port,
+		"addr", listener.Addr().String(),
 	)
 
 	return nil
 }
 
+// Addr returns the address the transport is listening on, such as "127.0.0.1:54321".
+// It is empty until Start succeeds.
+func (t *HTTPTransport) Addr() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.listener == nil {
+		return ""
+	}
+	return t.listener.Addr().String()
+}
+
+// Port returns the port the transport is listening on, which is the OS-assigned port
+// when the configured port is 0. It is 0 until Start succeeds.
+func (t *HTTPTransport) Port() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.listener == nil {
+		return 0
+	}
+	if tcpAddr, ok := t.listener.Addr().(*net.TCPAddr); ok {
+		return tcpAddr.Port
+	}
+	return 0
+}
+
 // Stop gracefully shuts down the HTTP transport
 func (t *HTTPTransport) Stop(ctx context.Context) error {
 	t.mu.Lock()
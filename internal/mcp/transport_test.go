@@ -325,6 +325,56 @@ func (s *TransportTestSuite) TestHTTPTransportStartStop() {
 	s.Equal(ErrTransportClosed, err)
 }
 
+func (s *TransportTestSuite) TestHTTPTransportAddr() {
+	handler := &mockMCPHandler{}
+	config := &TransportConfig{
+		Type:           types.TransportHTTP,
+		Host:           "127.0.0.1",
+		Port:           0, // Auto-assign port
+		ReadTimeout:    5 * time.Second,
+		WriteTimeout:   5 * time.Second,
+		MaxMessageSize: 1024 * 1024,
+	}
+
+	transport := NewHTTPTransport(s.logger, config, handler)
+	ctx := context.Background()
+
+	s.Empty(transport.Addr(), "No address before Start")
+	s.Zero(transport.Port())
+
+	s.Require().NoError(transport.Start(ctx))
+	defer func() { _ = transport.Stop(ctx) }()
+
+	s.NotZero(transport.Port())
+	s.Equal(fmt.Sprintf("127.0.0.1:%d", transport.Port()), transport.Addr())
+
+	// The health endpoint answers on the reported address as soon as Start returns
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+transport.Addr()+"/health", nil)
+	s.Require().NoError(err)
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	defer func() { _ = resp.Body.Close() }()
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	s.Empty(NewStdioTransport(s.logger, nil).Addr())
+}
+
+func (s *TransportTestSuite) TestHTTPTransportStartPortInUse() {
+	handler := &mockMCPHandler{}
+	config := &TransportConfig{Type: types.TransportHTTP, Host: "127.0.0.1", MaxMessageSize: 1024}
+
+	first := NewHTTPTransport(s.logger, config, handler)
+	ctx := context.Background()
+	s.Require().NoError(first.Start(ctx))
+	defer func() { _ = first.Stop(ctx) }()
+
+	taken := *config
+	taken.Port = first.Port()
+	second := NewHTTPTransport(s.logger, &taken, handler)
+	s.Require().Error(second.Start(ctx))
+	s.False(second.IsHealthy(ctx))
+}
+
 func (s *TransportTestSuite) TestHTTPTransportHandleMCPRequest() {
 	handler := &mockMCPHandler{
 		response: &types.MCPResponse{
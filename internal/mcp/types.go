@@ -23,6 +23,8 @@ type (
 	Capabilities = types.Capabilities
 	// ServerInfo represents server identification information
 	ServerInfo = types.ServerInfo
+	// ToolListParams represents the filtering and paging parameters of tools/list
+	ToolListParams = types.ToolListParams
 	// ToolListResult represents the result of listing tools
 	ToolListResult = types.ToolListResult
 	// Tool represents a tool definition
@@ -31,6 +33,8 @@ type (
 	ToolCallParams = types.ToolCallParams
 	// ToolCallResult represents the result of calling a tool
 	ToolCallResult = types.ToolCallResult
+	// ToolResult represents the typed content of a tool call result
+	ToolResult = types.ToolResult
 	// Content represents message content
 	Content = types.Content
 	// CommandRequest represents a command request
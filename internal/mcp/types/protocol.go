@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"encoding/json"
 )
 
 // TransportType represents the MCP transport mechanism
@@ -43,18 +44,49 @@ type ToolCallParams struct {
 	Arguments map[string]interface{} `json:"arguments,omitempty"`
 }
 
-// ToolCallResult represents the result of an MCP tool call.
-type ToolCallResult struct {
+// ToolResult represents the result of an MCP tool call: typed content blocks and whether
+// the tool failed.
+type ToolResult struct {
 	Content []Content `json:"content"`
 	IsError bool      `json:"isError,omitempty"`
+
+	// Truncated is set when the tool's output exceeded the size limit and was cut short.
+	// Truncated JSON output is returned as text.
+	Truncated bool `json:"truncated,omitempty"`
 }
 
+// ToolCallResult is the original name of ToolResult.
+type ToolCallResult = ToolResult
+
+// Content block types of a tool result
+const (
+	// ContentTypeText is plain text in Text
+	ContentTypeText = "text"
+
+	// ContentTypeJSON is a JSON value in Data, with the same JSON in Text for clients that
+	// only read text
+	ContentTypeJSON = "json"
+
+	// ContentTypeError is a failure described by Error, with a readable message in Text
+	ContentTypeError = "error"
+)
+
 // Content represents MCP content.
 type Content struct {
-	Type     string `json:"type"`
-	Text     string `json:"text,omitempty"`
-	Resource string `json:"resource,omitempty"`
-	MimeType string `json:"mimeType,omitempty"`
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	Data     json.RawMessage `json:"data,omitempty"`
+	Error    *ContentError   `json:"error,omitempty"`
+	Resource string          `json:"resource,omitempty"`
+	MimeType string          `json:"mimeType,omitempty"`
+}
+
+// ContentError describes why a tool failed. Code is stable for clients to act on;
+// Message and Detail are for people.
+type ContentError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Detail  string `json:"detail,omitempty"`
 }
 
 // InitializeResult represents the result of MCP initialization.
@@ -106,9 +138,20 @@ type Tool struct {
 	InputSchema map[string]interface{} `json:"inputSchema"`
 }
 
-// ToolListResult represents the result of listing MCP tools.
+// ToolListParams represents optional tools/list request parameters for filtering and
+// paging the tool list.
+type ToolListParams struct {
+	Cursor   string `json:"cursor,omitempty"`
+	Category string `json:"category,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+}
+
+// ToolListResult represents the result of listing MCP tools. NextCursor is set when more
+// tools follow this page.
 type ToolListResult struct {
-	Tools []Tool `json:"tools"`
+	Tools      []Tool `json:"tools"`
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 // InitializeParams represents MCP initialize request parameters
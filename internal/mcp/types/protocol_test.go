@@ -357,6 +357,31 @@ func (s *ProtocolTypesTestSuite) TestContent() {
 		s.Equal(content.Text, decoded.Text)
 		s.Equal(content.MimeType, decoded.MimeType)
 	})
+
+	s.Run("JSONContentSerialization", func() {
+		content := Content{
+			Type: ContentTypeJSON,
+			Text: `[{"id": "INV-001"}]`,
+			Data: json.RawMessage(`[{"id": "INV-001"}]`),
+		}
+
+		data, err := json.Marshal(content)
+		s.Require().NoError(err)
+		s.JSONEq(`{"type": "json", "text": "[{\"id\": \"INV-001\"}]", "data": [{"id": "INV-001"}]}`, string(data))
+	})
+
+	s.Run("ErrorContentSerialization", func() {
+		content := Content{
+			Type:  ContentTypeError,
+			Text:  "Tool output is not valid JSON",
+			Error: &ContentError{Code: "invalid_json", Message: "tool output is not valid JSON"},
+		}
+
+		data, err := json.Marshal(content)
+		s.Require().NoError(err)
+		s.JSONEq(`{"type": "error", "text": "Tool output is not valid JSON",
+			"error": {"code": "invalid_json", "message": "tool output is not valid JSON"}}`, string(data))
+	})
 }
 
 func (s *ProtocolTypesTestSuite) TestInitializeResult() {
@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config watch timing defaults
+const (
+	defaultWatchInterval = 250 * time.Millisecond
+	defaultWatchDebounce = 500 * time.Millisecond
+)
+
+// ConfigReloader applies configuration changes that can take effect without a restart
+type ConfigReloader interface {
+	// ReloadConfig applies the log level, CLI path and command allowlist of cfg
+	ReloadConfig(ctx context.Context, cfg *Config) error
+}
+
+// ConfigWatcher polls the MCP configuration file and hot-reloads it when it changes.
+//
+// The log level, CLI path and security allowlist are applied live through the target;
+// changes to any other setting are logged with a request to restart the server.
+//
+// A change is only reloaded once the file has been stable for the debounce period, so
+// editors that write a temporary file and then rename it trigger a single reload.
+//
+// Notes:
+// - An invalid file is logged and the running configuration is kept
+// - Safe to run while requests are being handled
+type ConfigWatcher struct {
+	logger   Logger
+	path     string
+	target   ConfigReloader
+	current  *Config
+	interval time.Duration
+	debounce time.Duration
+	mu       sync.Mutex
+}
+
+// NewConfigWatcher creates a watcher for the configuration file at path. current is the
+// configuration the server was started with, as loaded from the file.
+func NewConfigWatcher(logger Logger, path string, current *Config, target ConfigReloader) *ConfigWatcher {
+	if logger == nil {
+		panic("logger is required")
+	}
+	if current == nil {
+		panic("current config is required")
+	}
+	if target == nil {
+		panic("target is required")
+	}
+
+	return &ConfigWatcher{
+		logger:   logger,
+		path:     path,
+		target:   target,
+		current:  current,
+		interval: defaultWatchInterval,
+		debounce: defaultWatchDebounce,
+	}
+}
+
+// Run watches the configuration file until ctx is canceled
+func (w *ConfigWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	last := statConfigFile(w.path)
+	var changedAt time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		state := statConfigFile(w.path)
+		if !state.equal(last) {
+			last = state
+			changedAt = time.Now()
+			continue
+		}
+
+		if !changedAt.IsZero() && state.exists && time.Since(changedAt) >= w.debounce {
+			changedAt = time.Time{}
+			_ = w.Reload(ctx)
+		}
+	}
+}
+
+// Reload reads the configuration file and applies it. Settings that need a restart are
+// reported in the log but not applied.
+func (w *ConfigWatcher) Reload(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cfg, err := loadConfigFile(ctx, w.path)
+	if err != nil {
+		w.logger.Error("Configuration reload failed, keeping current configuration", "path", w.path, "error", err)
+		return err
+	}
+
+	if changed := restartRequiredChanges(w.current, cfg); len(changed) > 0 {
+		w.logger.Warn("Configuration changes require a restart to take effect", "settings", strings.Join(changed, ", "))
+	}
+
+	if cfg.LogLevel != w.current.LogLevel || cfg.CLI.Path != w.current.CLI.Path ||
+		!slices.Equal(cfg.Security.AllowedCommands, w.current.Security.AllowedCommands) {
+		if err := w.target.ReloadConfig(ctx, cfg); err != nil {
+			w.logger.Error("Configuration reload failed", "path", w.path, "error", err)
+			return err
+		}
+		w.logger.Info("Configuration reloaded",
+			"logLevel", cfg.LogLevel,
+			"cliPath", cfg.CLI.Path,
+			"allowedCommands", cfg.Security.AllowedCommands,
+		)
+	}
+
+	w.current = cfg
+	return nil
+}
+
+// restartRequiredChanges lists the settings that differ between old and updated and
+// cannot be applied to a running server
+func restartRequiredChanges(old, updated *Config) []string {
+	var changed []string
+	if old.Server != updated.Server {
+		changed = append(changed, "server")
+	}
+	if old.CLI.WorkingDir != updated.CLI.WorkingDir {
+		changed = append(changed, "cli.workingDir")
+	}
+	if old.CLI.MaxTimeout != updated.CLI.MaxTimeout {
+		changed = append(changed, "cli.maxTimeout")
+	}
+	if old.CLI.MaxOutputSize != updated.CLI.MaxOutputSize {
+		changed = append(changed, "cli.maxOutputSize")
+	}
+	if old.CLI.DryRun != updated.CLI.DryRun {
+		changed = append(changed, "cli.dryRun")
+	}
+
+	oldSecurity, updatedSecurity := old.Security, updated.Security
+	oldSecurity.AllowedCommands, updatedSecurity.AllowedCommands = nil, nil
+	if !reflect.DeepEqual(oldSecurity, updatedSecurity) {
+		changed = append(changed, "security")
+	}
+	if old.Audit != updated.Audit {
+		changed = append(changed, "audit")
+	}
+	return changed
+}
+
+// configFileState identifies a version of the configuration file
+type configFileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// statConfigFile returns the current state of the configuration file
+func statConfigFile(path string) configFileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return configFileState{}
+	}
+	return configFileState{exists: true, size: info.Size(), modTime: info.ModTime()}
+}
+
+// equal reports whether two states describe the same file version
+func (s configFileState) equal(other configFileState) bool {
+	return s.exists == other.exists && s.size == other.size && s.modTime.Equal(other.modTime)
+}
@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/mcp/executor"
+	"github.com/mrz1836/go-invoice/internal/mcp/tools"
+)
+
+// recordingReloader records the configurations it is asked to apply
+type recordingReloader struct {
+	mu      sync.Mutex
+	applied []*Config
+}
+
+func (r *recordingReloader) ReloadConfig(_ context.Context, cfg *Config) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.applied = append(r.applied, cfg)
+	return nil
+}
+
+func (r *recordingReloader) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.applied)
+}
+
+func (r *recordingReloader) last() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.applied[len(r.applied)-1]
+}
+
+// writeWatchConfig writes cfg to path the way editors do: a temporary file renamed into place
+func writeWatchConfig(t *testing.T, path string, cfg *Config) {
+	t.Helper()
+
+	data, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	tmp := path + ".swp"
+	require.NoError(t, os.WriteFile(tmp, data, 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func newWatchConfig(dir string) *Config {
+	cfg := getDefaultConfig()
+	cfg.CLI.WorkingDir = dir
+	cfg.Security.WorkingDir = dir
+	return cfg
+}
+
+func TestConfigWatcherReload(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mcp-config.json")
+
+	current := newWatchConfig(dir)
+	writeWatchConfig(t, path, current)
+
+	logger := NewTestLogger()
+	reloader := &recordingReloader{}
+	watcher := NewConfigWatcher(logger, path, current, reloader)
+
+	t.Run("AppliesLiveSettings", func(t *testing.T) {
+		updated := newWatchConfig(dir)
+		updated.LogLevel = "debug"
+		updated.CLI.Path = "/usr/local/bin/go-invoice"
+		updated.Security.AllowedCommands = []string{"go-invoice", "/usr/local/bin/go-invoice"}
+		writeWatchConfig(t, path, updated)
+
+		require.NoError(t, watcher.Reload(ctx))
+		require.Equal(t, 1, reloader.count())
+		assert.Equal(t, "debug", reloader.last().LogLevel)
+		assert.Equal(t, "/usr/local/bin/go-invoice", reloader.last().CLI.Path)
+		assert.Equal(t, updated.Security.AllowedCommands, reloader.last().Security.AllowedCommands)
+		assert.False(t, logger.HasMessage("WARN", "Configuration changes require a restart to take effect"))
+	})
+
+	t.Run("WarnsOnRestartRequiredSettings", func(t *testing.T) {
+		updated := newWatchConfig(dir)
+		updated.LogLevel = "debug"
+		updated.CLI.Path = "/usr/local/bin/go-invoice"
+		updated.Security.AllowedCommands = []string{"go-invoice", "/usr/local/bin/go-invoice"}
+		updated.Server.Port = 8089
+		writeWatchConfig(t, path, updated)
+
+		require.NoError(t, watcher.Reload(ctx))
+		assert.Equal(t, 1, reloader.count(), "nothing live changed")
+		assert.True(t, logger.HasMessageWithKV("WARN", "Configuration changes require a restart to take effect", "settings", "server"))
+	})
+
+	t.Run("KeepsCurrentOnInvalidFile", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(`{"logLevel": "verbose"`), 0o600))
+
+		require.Error(t, watcher.Reload(ctx))
+		assert.Equal(t, 1, reloader.count())
+		assert.True(t, logger.HasMessage("ERROR", "Configuration reload failed, keeping current configuration"))
+	})
+}
+
+func TestConfigWatcherRunDebounces(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mcp-config.json")
+	current := newWatchConfig(dir)
+	writeWatchConfig(t, path, current)
+
+	reloader := &recordingReloader{}
+	watcher := NewConfigWatcher(NewTestLogger(), path, current, reloader)
+	watcher.interval = 10 * time.Millisecond
+	watcher.debounce = 100 * time.Millisecond
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watcher.Run(ctx)
+	}()
+
+	// A burst of saves reloads once, with the last contents
+	for _, level := range []string{"warn", "error", "debug"} {
+		updated := newWatchConfig(dir)
+		updated.LogLevel = level
+		writeWatchConfig(t, path, updated)
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	require.Eventually(t, func() bool { return reloader.count() > 0 }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(250 * time.Millisecond)
+	assert.Equal(t, 1, reloader.count())
+	assert.Equal(t, "debug", reloader.last().LogLevel)
+
+	cancel()
+	<-done
+}
+
+func TestProductionHandlerReloadConfig(t *testing.T) {
+	ctx := context.Background()
+	logger := NewLogger("info")
+
+	sandbox := executor.DefaultSecurityConfig().Sandbox
+	validator := executor.NewDefaultCommandValidator(logger, sandbox)
+	fileHandler := executor.NewDefaultFileHandler(logger, validator, sandbox)
+	secureExecutor := executor.NewSecureExecutor(logger, validator, sandbox, fileHandler)
+	parser := executor.NewDefaultOutputParser(logger)
+	tracker := executor.NewDefaultProgressTracker(logger)
+	registry := tools.NewDefaultToolRegistry(tools.NewDefaultInputValidator(logger), logger)
+	bridge := executor.NewMCPExecutorBridge(logger, secureExecutor, parser, tracker, fileHandler, registry, nil, nil, "go-invoice")
+
+	handler := &ProductionMCPHandler{
+		logger:   logger,
+		config:   getDefaultConfig(),
+		bridge:   bridge,
+		executor: secureExecutor,
+	}
+	server := NewServerWithHandler(logger, handler, handler.config)
+
+	cfg := getDefaultConfig()
+	cfg.LogLevel = "error"
+	cfg.CLI.Path = "/opt/go-invoice/bin/go-invoice"
+	cfg.Security.AllowedCommands = []string{"go-invoice"}
+
+	reloader, ok := server.(ConfigReloader)
+	require.True(t, ok)
+	require.NoError(t, reloader.ReloadConfig(ctx, cfg))
+
+	assert.Equal(t, LogLevelError, logger.(*DefaultLogger).Level())
+
+	allowed, err := secureExecutor.GetAllowedCommands(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go-invoice"}, allowed)
+	require.NoError(t, secureExecutor.ValidateCommand(ctx, cfg.CLI.Path, nil), "allowed by base name")
+	require.ErrorIs(t, secureExecutor.ValidateCommand(ctx, "echo", nil), executor.ErrCommandNotAllowed)
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// AttachmentsDir is the directory in the data directory holding invoice attachments,
+// one subdirectory per invoice ID
+const AttachmentsDir = "attachments"
+
+// Attachment records a supporting file, such as a receipt or timesheet, stored with an invoice
+type Attachment struct {
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Path        string    `json:"path"` // Relative to the data directory, always with forward slashes
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"` // SHA-256 of the file content, hex encoded
+	AddedAt     time.Time `json:"added_at"`
+}
+
+// NewAttachment creates the record of content attached to invoice id as filename
+func NewAttachment(id InvoiceID, filename, contentType string, content []byte) Attachment {
+	return Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		Path:        AttachmentPath(id, filename),
+		Size:        int64(len(content)),
+		Checksum:    DocumentChecksum(content),
+		AddedAt:     time.Now(),
+	}
+}
+
+// AttachmentPath returns the data directory relative path of an invoice attachment
+func AttachmentPath(id InvoiceID, filename string) string {
+	return path.Join(AttachmentsDir, string(id), filename)
+}
+
+// ValidateAttachmentFilename rejects names that could escape the invoice's attachment
+// directory: empty names, "." and "..", and names containing a path separator
+func ValidateAttachmentFilename(filename string) error {
+	if strings.TrimSpace(filename) == "" || filename == "." || filename == ".." ||
+		strings.ContainsAny(filename, "/\\\x00") {
+		return ErrInvalidAttachmentName
+	}
+	return nil
+}
+
+// FindAttachment returns the attachment with the given filename, or nil
+func (i *Invoice) FindAttachment(filename string) *Attachment {
+	for idx := range i.Attachments {
+		if i.Attachments[idx].Filename == filename {
+			return &i.Attachments[idx]
+		}
+	}
+	return nil
+}
+
+// AddAttachment records an attachment on the invoice; filenames are unique per invoice
+func (i *Invoice) AddAttachment(attachment Attachment) error {
+	if err := ValidateAttachmentFilename(attachment.Filename); err != nil {
+		return err
+	}
+	if i.FindAttachment(attachment.Filename) != nil {
+		return ErrAttachmentExists
+	}
+	i.Attachments = append(i.Attachments, attachment)
+	return nil
+}
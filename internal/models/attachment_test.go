@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAttachmentFilename(t *testing.T) {
+	for _, name := range []string{"receipt.pdf", "timesheet 2024-01.xlsx", ".hidden", "a..b.txt"} {
+		require.NoError(t, ValidateAttachmentFilename(name), name)
+	}
+	for _, name := range []string{"", "  ", ".", "..", "../receipt.pdf", "dir/receipt.pdf", `..\receipt.pdf`, "/etc/passwd", "a\x00b"} {
+		require.ErrorIs(t, ValidateAttachmentFilename(name), ErrInvalidAttachmentName, name)
+	}
+}
+
+func TestInvoiceAddAttachment(t *testing.T) {
+	invoice := &Invoice{ID: "inv-1"}
+	attachment := NewAttachment(invoice.ID, "receipt.pdf", "application/pdf", []byte("%PDF"))
+
+	assert.Equal(t, "attachments/inv-1/receipt.pdf", attachment.Path)
+	assert.Equal(t, int64(4), attachment.Size)
+	assert.Equal(t, DocumentChecksum([]byte("%PDF")), attachment.Checksum)
+
+	require.NoError(t, invoice.AddAttachment(attachment))
+	require.ErrorIs(t, invoice.AddAttachment(attachment), ErrAttachmentExists)
+	require.ErrorIs(t, invoice.AddAttachment(Attachment{Filename: "../x"}), ErrInvalidAttachmentName)
+
+	require.Len(t, invoice.Attachments, 1)
+	assert.Equal(t, "application/pdf", invoice.FindAttachment("receipt.pdf").ContentType)
+	assert.Nil(t, invoice.FindAttachment("missing.pdf"))
+}
@@ -51,7 +51,10 @@ func (c *Client) Validate(ctx context.Context) error {
 		AddLengthRange("phone", c.Phone, 10, 20).
 		AddMaxLength("address", c.Address, 500).
 		AddMaxLength("tax_id", c.TaxID, 50).
+		AddMaxLength("exemption_reason", c.ExemptionReason, 200).
 		AddMaxLength("approver_contacts", c.ApproverContacts, 500).
+		AddMaxLength("template_name", c.TemplateName, 100).
+		AddValidOption("crypto_fee_currency", c.CryptoFeeCurrency, CryptoCurrencies()).
 		AddTimeRequired("created_at", c.CreatedAt).
 		AddTimeRequired("updated_at", c.UpdatedAt).
 		AddTimeOrder("updated_at", c.CreatedAt, c.UpdatedAt, "created_at", "updated_at").
@@ -232,15 +235,19 @@ func (c *Client) HasCompleteInfo() bool {
 
 // CreateClientRequest represents a request to create a new client
 type CreateClientRequest struct {
-	Name             string  `json:"name"`
-	Email            string  `json:"email"`
-	Phone            string  `json:"phone,omitempty"`
-	Address          string  `json:"address,omitempty"`
-	TaxID            string  `json:"tax_id,omitempty"`
-	ApproverContacts string  `json:"approver_contacts,omitempty"`
-	CryptoFeeEnabled bool    `json:"crypto_fee_enabled"`
-	CryptoFeeAmount  float64 `json:"crypto_fee_amount,omitempty"`
-	LateFeeEnabled   bool    `json:"late_fee_enabled"`
+	Name              string  `json:"name"`
+	Email             string  `json:"email"`
+	Phone             string  `json:"phone,omitempty"`
+	Address           string  `json:"address,omitempty"`
+	TaxID             string  `json:"tax_id,omitempty"`
+	TaxExempt         bool    `json:"tax_exempt,omitempty"`
+	ExemptionReason   string  `json:"exemption_reason,omitempty"`
+	ApproverContacts  string  `json:"approver_contacts,omitempty"`
+	CryptoFeeEnabled  bool    `json:"crypto_fee_enabled"`
+	CryptoFeeAmount   float64 `json:"crypto_fee_amount,omitempty"`
+	CryptoFeeCurrency string  `json:"crypto_fee_currency,omitempty"`
+	LateFeeEnabled    bool    `json:"late_fee_enabled"`
+	TemplateName      string  `json:"template_name,omitempty"`
 }
 
 // Validate validates the create client request
@@ -259,6 +266,9 @@ func (r *CreateClientRequest) Validate(ctx context.Context) error {
 		AddLengthRange("phone", r.Phone, 10, 20).
 		AddMaxLength("address", r.Address, 500).
 		AddMaxLength("tax_id", r.TaxID, 50).
+		AddMaxLength("exemption_reason", r.ExemptionReason, 200).
 		AddMaxLength("approver_contacts", r.ApproverContacts, 500).
+		AddMaxLength("template_name", r.TemplateName, 100).
+		AddValidOption("crypto_fee_currency", r.CryptoFeeCurrency, CryptoCurrencies()).
 		Build(ErrCreateClientRequestInvalid)
 }
@@ -1156,6 +1156,25 @@ func (suite *ClientTestSuite) TestCreateClientRequestValidate() {
 			},
 			expectError: false,
 		},
+		{
+			name: "ValidRequestWithCryptoFeeCurrency",
+			request: CreateClientRequest{
+				Name:              testClientName,
+				Email:             testClientEmail,
+				CryptoFeeCurrency: "BSV",
+			},
+			expectError: false,
+		},
+		{
+			name: "InvalidCryptoFeeCurrency",
+			request: CreateClientRequest{
+				Name:              testClientName,
+				Email:             testClientEmail,
+				CryptoFeeCurrency: "ETH",
+			},
+			expectError: true,
+			errorMsg:    "validation failed for field 'crypto_fee_currency'",
+		},
 		{
 			name: "EmptyName",
 			request: CreateClientRequest{
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// base58Alphabet is the bitcoin base58 alphabet
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// bsvAddressVersions are the base58check version bytes of P2PKH and P2SH addresses on mainnet and testnet
+var bsvAddressVersions = map[byte]struct{}{0x00: {}, 0x05: {}, 0x6f: {}, 0xc4: {}}
+
+// ValidateUSDCAddress checks address is an ERC-20 address: 0x followed by 40 hex characters.
+// Mixed-case addresses must carry a valid EIP-55 checksum; all lower or upper case addresses
+// have no checksum to verify.
+func ValidateUSDCAddress(address string) error {
+	hexPart, ok := strings.CutPrefix(address, "0x")
+	if !ok || len(hexPart) != 40 {
+		return fmt.Errorf("%w: %q", ErrInvalidUSDCAddress, address)
+	}
+	if _, err := hex.DecodeString(hexPart); err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidUSDCAddress, address)
+	}
+
+	if hexPart == strings.ToLower(hexPart) || hexPart == strings.ToUpper(hexPart) {
+		return nil
+	}
+	if hexPart != eip55Checksum(hexPart) {
+		return fmt.Errorf("%w: %q has an invalid EIP-55 checksum (check for a typo)", ErrInvalidUSDCAddress, address)
+	}
+	return nil
+}
+
+// eip55Checksum returns the EIP-55 mixed-case form of a 40 character hex address
+func eip55Checksum(hexPart string) string {
+	lower := strings.ToLower(hexPart)
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(lower))
+	digest := hash.Sum(nil)
+
+	out := []byte(lower)
+	for i, c := range out {
+		nibble := digest[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if c >= 'a' && nibble&0x0f >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return string(out)
+}
+
+// ValidateBSVAddress checks address is a base58check encoded P2PKH or P2SH bitcoin address
+func ValidateBSVAddress(address string) error {
+	decoded, ok := decodeBase58(address)
+	if !ok || len(decoded) != 25 {
+		return fmt.Errorf("%w: %q", ErrInvalidBSVAddress, address)
+	}
+	if _, ok := bsvAddressVersions[decoded[0]]; !ok {
+		return fmt.Errorf("%w: %q has an unknown version byte", ErrInvalidBSVAddress, address)
+	}
+
+	first := sha256.Sum256(decoded[:21])
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], decoded[21:]) {
+		return fmt.Errorf("%w: %q has an invalid checksum (check for a typo)", ErrInvalidBSVAddress, address)
+	}
+	return nil
+}
+
+// decodeBase58 decodes a bitcoin base58 string, keeping leading '1's as zero bytes
+func decodeBase58(s string) ([]byte, bool) {
+	if s == "" {
+		return nil, false
+	}
+
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range s {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit < 0 {
+			return nil, false
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+
+	zeros := len(s) - len(strings.TrimLeft(s, "1"))
+	return append(make([]byte, zeros), n.Bytes()...), true
+}
+
+// SetUSDCAddressOverride sets the per-invoice USDC address after validating it.
+// An empty address clears the override so the configured address is used.
+func (i *Invoice) SetUSDCAddressOverride(ctx context.Context, address string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := i.EnsureUnlocked(); err != nil {
+		return err
+	}
+
+	address = strings.TrimSpace(address)
+	if address != "" {
+		if err := ValidateUSDCAddress(address); err != nil {
+			return err
+		}
+	}
+	i.USDCAddressOverride = &address
+	return nil
+}
+
+// SetBSVAddressOverride sets the per-invoice BSV address after validating it.
+// An empty address clears the override so the configured address is used.
+func (i *Invoice) SetBSVAddressOverride(ctx context.Context, address string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := i.EnsureUnlocked(); err != nil {
+		return err
+	}
+
+	address = strings.TrimSpace(address)
+	if address != "" {
+		if err := ValidateBSVAddress(address); err != nil {
+			return err
+		}
+	}
+	i.BSVAddressOverride = &address
+	return nil
+}
+
+// validateAddressOverrides checks any per-invoice crypto address overrides are well formed
+func (i *Invoice) validateAddressOverrides() error {
+	if i.HasUSDCAddressOverride() {
+		if err := ValidateUSDCAddress(*i.USDCAddressOverride); err != nil {
+			return err
+		}
+	}
+	if i.HasBSVAddressOverride() {
+		if err := ValidateBSVAddress(*i.BSVAddressOverride); err != nil {
+			return err
+		}
+	}
+	return nil
+}
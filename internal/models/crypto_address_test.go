@@ -0,0 +1,127 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUSDCAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		wantErr bool
+	}{
+		{"ChecksummedMixedCase", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", false},
+		{"ChecksummedMixedCase2", "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", false},
+		{"AllLowerCase", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", false},
+		{"AllUpperCase", "0x52908400098527886E0F7030069857D2E4169EE7", false},
+		{"BadChecksum", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", true},
+		{"MissingPrefix", "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		{"TooShort", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe", true},
+		{"TooLong", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed0", true},
+		{"NotHex", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", true},
+		{"Empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUSDCAddress(tt.address)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidUSDCAddress)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateBSVAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		wantErr bool
+	}{
+		{"P2PKH", "1BoatSLRHtKNngkdXEeobR76b53LETtpyT", false},
+		{"Genesis", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", false},
+		{"P2SH", "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", false},
+		{"Testnet", "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", false},
+		{"Typo", "1BoatSLRHtKNngkdXEeobR76b53LETtpyU", true},
+		{"InvalidCharacter", "1BoatSLRHtKNngkdXEeobR76b53LETtpy0", true},
+		{"TooShort", "1BoatSLRHtKNngkdXEeob", true},
+		{"EthereumAddress", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		{"Empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBSVAddress(tt.address)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidBSVAddress)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestInvoiceSetAddressOverrides(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ValidAddresses", func(t *testing.T) {
+		invoice := createTestInvoice(t, ctx)
+
+		require.NoError(t, invoice.SetUSDCAddressOverride(ctx, " 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed "))
+		require.NoError(t, invoice.SetBSVAddressOverride(ctx, "1BoatSLRHtKNngkdXEeobR76b53LETtpyT"))
+
+		assert.Equal(t, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", invoice.GetUSDCAddress("0xdefault"))
+		assert.Equal(t, "1BoatSLRHtKNngkdXEeobR76b53LETtpyT", invoice.GetBSVAddress("default"))
+	})
+
+	t.Run("InvalidAddressLeavesOverrideUnchanged", func(t *testing.T) {
+		invoice := createTestInvoice(t, ctx)
+
+		err := invoice.SetUSDCAddressOverride(ctx, "0x123")
+		require.ErrorIs(t, err, ErrInvalidUSDCAddress)
+		assert.Nil(t, invoice.USDCAddressOverride)
+
+		err = invoice.SetBSVAddressOverride(ctx, "not-an-address")
+		require.ErrorIs(t, err, ErrInvalidBSVAddress)
+		assert.Nil(t, invoice.BSVAddressOverride)
+	})
+
+	t.Run("EmptyClearsOverride", func(t *testing.T) {
+		invoice := createTestInvoice(t, ctx)
+		require.NoError(t, invoice.SetUSDCAddressOverride(ctx, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+
+		require.NoError(t, invoice.SetUSDCAddressOverride(ctx, ""))
+		require.NoError(t, invoice.SetBSVAddressOverride(ctx, ""))
+
+		require.NotNil(t, invoice.USDCAddressOverride)
+		assert.Empty(t, *invoice.USDCAddressOverride)
+		assert.False(t, invoice.HasUSDCAddressOverride())
+		assert.False(t, invoice.HasBSVAddressOverride())
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		invoice := createTestInvoice(t, ctx)
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		require.ErrorIs(t, invoice.SetUSDCAddressOverride(canceled, ""), context.Canceled)
+		require.ErrorIs(t, invoice.SetBSVAddressOverride(canceled, ""), context.Canceled)
+	})
+}
+
+func TestSetCryptoFeeValidatesAddressOverrides(t *testing.T) {
+	ctx := context.Background()
+	typo := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"
+
+	invoice := createTestInvoice(t, ctx)
+	invoice.USDCAddressOverride = &typo
+
+	require.ErrorIs(t, invoice.SetCryptoFee(ctx, true, true, 25), ErrInvalidUSDCAddress)
+	require.NoError(t, invoice.SetCryptoFee(ctx, false, false, 0), "addresses are not shown when crypto payments are off")
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// ErrInvalidExchangeRate is returned when a crypto quote is given a rate that is not positive
+var ErrInvalidExchangeRate = errors.New("exchange rate must be positive")
+
+// cryptoDecimals is the number of decimal places each crypto currency is quoted in
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var cryptoDecimals = map[PaymentMethod]int{
+	PaymentMethodUSDC: 6,
+	PaymentMethodBSV:  8,
+}
+
+// CryptoCurrencies lists the crypto currencies a client can be quoted in
+func CryptoCurrencies() []string {
+	return []string{string(PaymentMethodUSDC), string(PaymentMethodBSV)}
+}
+
+// CryptoQuote is the crypto amount due on an invoice at the exchange rate fetched when the
+// invoice was generated. It is stored on the invoice so later rate moves do not change an
+// invoice that has already been sent.
+type CryptoQuote struct {
+	Currency     string       `json:"currency"`         // Crypto currency, USDC or BSV
+	FiatCurrency string       `json:"fiat_currency"`    // ISO 4217 code of the amount converted
+	FiatAmount   money.Amount `json:"fiat_amount"`      // Amount due when the quote was taken
+	Rate         float64      `json:"rate"`             // Crypto units per one unit of fiat
+	Amount       float64      `json:"amount"`           // Crypto amount due
+	AsOf         time.Time    `json:"as_of"`            // When the rate was fetched
+	Source       string       `json:"source,omitempty"` // Rate provider, e.g. coingecko
+}
+
+// NeedsCryptoQuote reports whether generating the invoice should fetch a new rate for currency.
+// Drafts are quoted afresh every time; once the invoice has left draft its quote is kept, and
+// only an invoice without a matching quote gets one.
+func (i *Invoice) NeedsCryptoQuote(currency string) bool {
+	if currency == "" {
+		return false
+	}
+	if i.Status == StatusDraft || i.CryptoQuote == nil {
+		return true
+	}
+	return i.CryptoQuote.Currency != currency
+}
+
+// SetCryptoQuote converts the amount due to currency at rate, in crypto units per one unit of
+// fiatCurrency, and stores the result on the invoice
+func (i *Invoice) SetCryptoQuote(currency, fiatCurrency string, rate float64, asOf time.Time, source string) error {
+	decimals, ok := cryptoDecimals[PaymentMethod(currency)]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidPaymentMethod, currency)
+	}
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return fmt.Errorf("%w, got %g", ErrInvalidExchangeRate, rate)
+	}
+
+	due := i.AmountDue()
+	scale := math.Pow10(decimals)
+	i.CryptoQuote = &CryptoQuote{
+		Currency:     currency,
+		FiatCurrency: fiatCurrency,
+		FiatAmount:   money.FromFloat(due),
+		Rate:         rate,
+		Amount:       math.Round(due*rate*scale) / scale,
+		AsOf:         asOf,
+		Source:       source,
+	}
+	return nil
+}
+
+// FormatAmount returns the crypto amount due with the currency's full precision, without
+// trailing zeros
+func (q *CryptoQuote) FormatAmount() string {
+	return formatCryptoValue(q.Amount, cryptoDecimals[PaymentMethod(q.Currency)])
+}
+
+// FormatRate returns the rate with enough precision to reproduce the quoted amount
+func (q *CryptoQuote) FormatRate() string {
+	return formatCryptoValue(q.Rate, cryptoDecimals[PaymentMethod(q.Currency)]+2)
+}
+
+// formatCryptoValue formats value with at most decimals decimal places
+func formatCryptoValue(value float64, decimals int) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.*f", decimals, value), "0"), ".")
+}
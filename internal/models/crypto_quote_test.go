@@ -0,0 +1,109 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func TestSetCryptoQuote(t *testing.T) {
+	asOf := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("USDC", func(t *testing.T) {
+		invoice := &Invoice{Total: money.FromFloat(1500)}
+
+		require.NoError(t, invoice.SetCryptoQuote("USDC", "USD", 1.0002, asOf, "mock"))
+		quote := invoice.CryptoQuote
+		require.NotNil(t, quote)
+		assert.Equal(t, "USDC", quote.Currency)
+		assert.Equal(t, "USD", quote.FiatCurrency)
+		assert.Equal(t, money.FromFloat(1500), quote.FiatAmount)
+		assert.InDelta(t, 1500.3, quote.Amount, 1e-9)
+		assert.Equal(t, asOf, quote.AsOf)
+		assert.Equal(t, "mock", quote.Source)
+	})
+
+	t.Run("BSVRoundsToEightDecimals", func(t *testing.T) {
+		invoice := &Invoice{Total: money.FromFloat(100)}
+
+		require.NoError(t, invoice.SetCryptoQuote("BSV", "USD", 1/67.0, asOf, "mock"))
+		assert.InDelta(t, 1.49253731, invoice.CryptoQuote.Amount, 1e-12)
+	})
+
+	t.Run("QuotesAmountDueAfterPayments", func(t *testing.T) {
+		invoice := &Invoice{
+			Total:    money.FromFloat(1000),
+			Payments: []Payment{{Amount: 400, Date: asOf}},
+		}
+
+		require.NoError(t, invoice.SetCryptoQuote("USDC", "USD", 1, asOf, "mock"))
+		assert.Equal(t, money.FromFloat(600), invoice.CryptoQuote.FiatAmount)
+		assert.InDelta(t, 600.0, invoice.CryptoQuote.Amount, 1e-9)
+	})
+
+	t.Run("InvalidRate", func(t *testing.T) {
+		invoice := &Invoice{Total: money.FromFloat(100)}
+
+		for _, rate := range []float64{0, -1} {
+			require.ErrorIs(t, invoice.SetCryptoQuote("USDC", "USD", rate, asOf, "mock"), ErrInvalidExchangeRate)
+		}
+		assert.Nil(t, invoice.CryptoQuote)
+	})
+
+	t.Run("InvalidCurrency", func(t *testing.T) {
+		invoice := &Invoice{Total: money.FromFloat(100)}
+
+		require.ErrorIs(t, invoice.SetCryptoQuote("ETH", "USD", 1, asOf, "mock"), ErrInvalidPaymentMethod)
+		assert.Nil(t, invoice.CryptoQuote)
+	})
+}
+
+func TestNeedsCryptoQuote(t *testing.T) {
+	usdcQuote := &CryptoQuote{Currency: "USDC", Rate: 1, Amount: 100}
+
+	tests := []struct {
+		name     string
+		status   string
+		quote    *CryptoQuote
+		currency string
+		expected bool
+	}{
+		{"NoCurrency", StatusDraft, nil, "", false},
+		{"DraftWithoutQuote", StatusDraft, nil, "USDC", true},
+		{"DraftWithQuote", StatusDraft, usdcQuote, "USDC", true},
+		{"SentWithQuote", StatusSent, usdcQuote, "USDC", false},
+		{"SentWithoutQuote", StatusSent, nil, "USDC", true},
+		{"SentWithOtherCurrency", StatusSent, usdcQuote, "BSV", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := &Invoice{Status: tt.status, CryptoQuote: tt.quote}
+			assert.Equal(t, tt.expected, invoice.NeedsCryptoQuote(tt.currency))
+		})
+	}
+}
+
+func TestCryptoQuoteFormat(t *testing.T) {
+	tests := []struct {
+		name           string
+		quote          CryptoQuote
+		expectedAmount string
+		expectedRate   string
+	}{
+		{"USDCWhole", CryptoQuote{Currency: "USDC", Amount: 1500, Rate: 1}, "1500", "1"},
+		{"USDCFraction", CryptoQuote{Currency: "USDC", Amount: 1500.3, Rate: 1.0002}, "1500.3", "1.0002"},
+		{"BSV", CryptoQuote{Currency: "BSV", Amount: 1.49253731, Rate: 1 / 67.0}, "1.49253731", "0.0149253731"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedAmount, tt.quote.FormatAmount())
+			assert.Equal(t, tt.expectedRate, tt.quote.FormatRate())
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// iso4217Codes lists the active ISO 4217 currency codes
+var iso4217Codes = map[string]struct{}{
+	"AED": {}, "AFN": {}, "ALL": {}, "AMD": {}, "ANG": {}, "AOA": {}, "ARS": {}, "AUD": {}, "AWG": {}, "AZN": {},
+	"BAM": {}, "BBD": {}, "BDT": {}, "BGN": {}, "BHD": {}, "BIF": {}, "BMD": {}, "BND": {}, "BOB": {}, "BRL": {},
+	"BSD": {}, "BTN": {}, "BWP": {}, "BYN": {}, "BZD": {}, "CAD": {}, "CDF": {}, "CHF": {}, "CLP": {}, "CNY": {},
+	"COP": {}, "CRC": {}, "CUP": {}, "CVE": {}, "CZK": {}, "DJF": {}, "DKK": {}, "DOP": {}, "DZD": {}, "EGP": {},
+	"ERN": {}, "ETB": {}, "EUR": {}, "FJD": {}, "FKP": {}, "GBP": {}, "GEL": {}, "GHS": {}, "GIP": {}, "GMD": {},
+	"GNF": {}, "GTQ": {}, "GYD": {}, "HKD": {}, "HNL": {}, "HTG": {}, "HUF": {}, "IDR": {}, "ILS": {}, "INR": {},
+	"IQD": {}, "IRR": {}, "ISK": {}, "JMD": {}, "JOD": {}, "JPY": {}, "KES": {}, "KGS": {}, "KHR": {}, "KMF": {},
+	"KPW": {}, "KRW": {}, "KWD": {}, "KYD": {}, "KZT": {}, "LAK": {}, "LBP": {}, "LKR": {}, "LRD": {}, "LSL": {},
+	"LYD": {}, "MAD": {}, "MDL": {}, "MGA": {}, "MKD": {}, "MMK": {}, "MNT": {}, "MOP": {}, "MRU": {}, "MUR": {},
+	"MVR": {}, "MWK": {}, "MXN": {}, "MYR": {}, "MZN": {}, "NAD": {}, "NGN": {}, "NIO": {}, "NOK": {}, "NPR": {},
+	"NZD": {}, "OMR": {}, "PAB": {}, "PEN": {}, "PGK": {}, "PHP": {}, "PKR": {}, "PLN": {}, "PYG": {}, "QAR": {},
+	"RON": {}, "RSD": {}, "RUB": {}, "RWF": {}, "SAR": {}, "SBD": {}, "SCR": {}, "SDG": {}, "SEK": {}, "SGD": {},
+	"SHP": {}, "SLE": {}, "SOS": {}, "SRD": {}, "SSP": {}, "STN": {}, "SVC": {}, "SYP": {}, "SZL": {}, "THB": {},
+	"TJS": {}, "TMT": {}, "TND": {}, "TOP": {}, "TRY": {}, "TTD": {}, "TWD": {}, "TZS": {}, "UAH": {}, "UGX": {},
+	"USD": {}, "UYU": {}, "UZS": {}, "VES": {}, "VND": {}, "VUV": {}, "WST": {}, "XAF": {}, "XCD": {}, "XCG": {},
+	"XOF": {}, "XPF": {}, "YER": {}, "ZAR": {}, "ZMW": {}, "ZWG": {},
+}
+
+// NormalizeCurrency upper-cases a currency code and checks it is an ISO 4217 code
+func NormalizeCurrency(code string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	if !IsValidCurrency(normalized) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, code)
+	}
+	return normalized, nil
+}
+
+// IsValidCurrency reports whether code is an active ISO 4217 currency code
+func IsValidCurrency(code string) bool {
+	_, ok := iso4217Codes[code]
+	return ok
+}
+
+// GetCurrency returns the invoice currency, or defaultCurrency for invoices created before
+// invoices carried their own currency
+func (i *Invoice) GetCurrency(defaultCurrency string) string {
+	if i.Currency != "" {
+		return i.Currency
+	}
+	return defaultCurrency
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"Uppercase", "EUR", "EUR", false},
+		{"Lowercase", "gbp", "GBP", false},
+		{"Whitespace", " usd ", "USD", false},
+		{"Unknown", "XYZ", "", true},
+		{"TooLong", "EURO", "", true},
+		{"Empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := NormalizeCurrency(tt.input)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidCurrency)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, code)
+		})
+	}
+}
+
+func TestInvoiceGetCurrency(t *testing.T) {
+	assert.Equal(t, "EUR", (&Invoice{Currency: "EUR"}).GetCurrency("USD"))
+	assert.Equal(t, "USD", (&Invoice{}).GetCurrency("USD"))
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// DiscountType represents how a discount is expressed
+type DiscountType string
+
+const (
+	// DiscountTypePercent takes a percentage off (Value 10 means 10%)
+	DiscountTypePercent DiscountType = "percent"
+	// DiscountTypeFixed takes a fixed amount off
+	DiscountTypeFixed DiscountType = "fixed"
+)
+
+// Discount reduces a line item total by a percentage or a fixed amount
+type Discount struct {
+	Type  DiscountType `json:"type"`
+	Value float64      `json:"value"`
+}
+
+// ParseDiscount parses "10%" as a percentage discount and "25" or "25.50" as a fixed amount
+func ParseDiscount(value string) (*Discount, error) {
+	value = strings.TrimSpace(value)
+
+	discount := &Discount{Type: DiscountTypeFixed}
+	if trimmed, ok := strings.CutSuffix(value, "%"); ok {
+		discount.Type = DiscountTypePercent
+		value = strings.TrimSpace(trimmed)
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidDiscount, value)
+	}
+	discount.Value = parsed
+
+	if err := discount.Validate(); err != nil {
+		return nil, err
+	}
+	return discount, nil
+}
+
+// Validate checks the discount is a percentage up to 100 or a positive amount
+func (d *Discount) Validate() error {
+	switch d.Type {
+	case DiscountTypePercent:
+		if d.Value <= 0 || d.Value > 100 {
+			return fmt.Errorf("%w: percentage must be greater than 0 and at most 100, got %v", ErrInvalidDiscount, d.Value)
+		}
+	case DiscountTypeFixed:
+		if d.Value <= 0 {
+			return fmt.Errorf("%w: amount must be greater than 0, got %v", ErrInvalidDiscount, d.Value)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidDiscount, d.Type)
+	}
+	return nil
+}
+
+// AmountOff returns how much the discount takes off amount, never more than amount itself
+func (d *Discount) AmountOff(amount money.Amount) money.Amount {
+	if d == nil {
+		return 0
+	}
+	if d.Type == DiscountTypePercent {
+		return discountOff(amount, d.Value, 0)
+	}
+	return discountOff(amount, 0, money.FromFloat(d.Value))
+}
+
+// String formats the discount as it is written on the command line, e.g. "10%" or "25.00"
+func (d *Discount) String() string {
+	if d.Type == DiscountTypePercent {
+		return strconv.FormatFloat(d.Value, 'f', -1, 64) + "%"
+	}
+	return money.FromFloat(d.Value).String()
+}
+
+// discountOff returns the percent and fixed discounts on amount, rounded half-up to the cent
+// and capped at amount so a discount never makes a total negative
+func discountOff(amount money.Amount, percent float64, fixed money.Amount) money.Amount {
+	off := amount.MulRate(percent/100) + fixed
+	if off > amount {
+		return max(amount, 0)
+	}
+	return off
+}
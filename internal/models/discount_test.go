@@ -0,0 +1,132 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func TestParseDiscount(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *Discount
+		wantErr  bool
+	}{
+		{"Percent", "10%", &Discount{Type: DiscountTypePercent, Value: 10}, false},
+		{"FractionalPercent", " 12.5 % ", &Discount{Type: DiscountTypePercent, Value: 12.5}, false},
+		{"FullPercent", "100%", &Discount{Type: DiscountTypePercent, Value: 100}, false},
+		{"Fixed", "25.50", &Discount{Type: DiscountTypeFixed, Value: 25.5}, false},
+		{"PercentOver100", "150%", nil, true},
+		{"ZeroPercent", "0%", nil, true},
+		{"NegativeFixed", "-5", nil, true},
+		{"NotANumber", "ten", nil, true},
+		{"Empty", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discount, err := ParseDiscount(tt.input)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidDiscount)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, discount)
+		})
+	}
+}
+
+func TestDiscountAmountOff(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount *Discount
+		amount   float64
+		expected float64
+	}{
+		{"Nil", nil, 100, 0},
+		{"Percent", &Discount{Type: DiscountTypePercent, Value: 10}, 250, 25},
+		{"PercentRoundsHalfUp", &Discount{Type: DiscountTypePercent, Value: 15}, 33.33, 5},
+		{"FullPercent", &Discount{Type: DiscountTypePercent, Value: 100}, 80, 80},
+		{"Fixed", &Discount{Type: DiscountTypeFixed, Value: 25}, 100, 25},
+		{"FixedCappedAtAmount", &Discount{Type: DiscountTypeFixed, Value: 150}, 100, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, money.FromFloat(tt.expected), tt.discount.AmountOff(money.FromFloat(tt.amount)))
+		})
+	}
+}
+
+func TestDiscountString(t *testing.T) {
+	assert.Equal(t, "12.5%", (&Discount{Type: DiscountTypePercent, Value: 12.5}).String())
+	assert.Equal(t, "25.00", (&Discount{Type: DiscountTypeFixed, Value: 25}).String())
+}
+
+func TestLineItemDiscount(t *testing.T) {
+	ctx := context.Background()
+	date := time.Now().Add(-24 * time.Hour)
+
+	t.Run("PercentOnHourly", func(t *testing.T) {
+		item, err := NewHourlyLineItem(ctx, "item-1", date, 8, 125, "Development")
+		require.NoError(t, err)
+
+		item.Discount = &Discount{Type: DiscountTypePercent, Value: 10}
+		require.NoError(t, item.RecalculateTotal(ctx))
+
+		assert.Equal(t, money.FromFloat(1000), item.GrossTotal())
+		assert.Equal(t, money.FromFloat(100), item.DiscountAmount())
+		assert.Equal(t, money.FromFloat(900), item.Total)
+		assert.Equal(t, "8.00 hours @ $125.00/hr less 10% discount", item.GetDetails())
+		require.NoError(t, item.Validate(ctx))
+	})
+
+	t.Run("FixedOnQuantity", func(t *testing.T) {
+		item, err := NewQuantityLineItem(ctx, "item-2", date, 3, 50, "Licenses")
+		require.NoError(t, err)
+
+		item.Discount = &Discount{Type: DiscountTypeFixed, Value: 20}
+		require.NoError(t, item.RecalculateTotal(ctx))
+
+		assert.Equal(t, money.FromFloat(130), item.Total)
+		require.NoError(t, item.Validate(ctx))
+	})
+
+	t.Run("FullPercentLeavesZeroTotal", func(t *testing.T) {
+		item, err := NewFixedLineItem(ctx, "item-3", date, 500, "Setup")
+		require.NoError(t, err)
+
+		item.Discount = &Discount{Type: DiscountTypePercent, Value: 100}
+		require.NoError(t, item.RecalculateTotal(ctx))
+
+		assert.Equal(t, money.Amount(0), item.Total)
+		require.NoError(t, item.Validate(ctx))
+	})
+
+	t.Run("FixedExceedingAmountRejected", func(t *testing.T) {
+		item, err := NewFixedLineItem(ctx, "item-4", date, 100, "Setup")
+		require.NoError(t, err)
+
+		item.Discount = &Discount{Type: DiscountTypeFixed, Value: 150}
+		require.NoError(t, item.RecalculateTotal(ctx))
+
+		assert.Equal(t, money.Amount(0), item.Total, "total never goes negative")
+		err = item.Validate(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), ErrDiscountExceedsAmount.Error())
+	})
+
+	t.Run("StaleTotalRejected", func(t *testing.T) {
+		item, err := NewFixedLineItem(ctx, "item-5", date, 100, "Setup")
+		require.NoError(t, err)
+
+		item.Discount = &Discount{Type: DiscountTypePercent, Value: 10}
+		require.Error(t, item.Validate(ctx), "total must be net of the discount")
+	})
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Document formats for generated invoice documents
+const (
+	// DocumentFormatHTML is a rendered HTML invoice
+	DocumentFormatHTML = "html"
+)
+
+// GeneratedDoc records a rendered invoice document that was saved to disk
+type GeneratedDoc struct {
+	Path        string    `json:"path"`
+	Format      string    `json:"format"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Checksum    string    `json:"checksum"`           // SHA-256 of the document content, hex encoded
+	Status      string    `json:"status"`             // Invoice status when the document was generated
+	Template    string    `json:"template,omitempty"` // Template used to render the document
+}
+
+// NewGeneratedDoc creates a document record for content written to path
+func NewGeneratedDoc(path, format string, content []byte, status, template string) GeneratedDoc {
+	return GeneratedDoc{
+		Path:        path,
+		Format:      format,
+		GeneratedAt: time.Now(),
+		Checksum:    DocumentChecksum(content),
+		Status:      status,
+		Template:    template,
+	}
+}
+
+// DocumentChecksum returns the hex encoded SHA-256 checksum of content
+func DocumentChecksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// WasIssued reports whether the document was generated after the invoice left draft,
+// meaning it may be the copy the client received
+func (d GeneratedDoc) WasIssued() bool {
+	return d.Status != "" && d.Status != StatusDraft
+}
+
+// RecordGeneratedDocument appends a generated document to the invoice's history
+func (i *Invoice) RecordGeneratedDocument(doc GeneratedDoc) {
+	i.GeneratedDocuments = append(i.GeneratedDocuments, doc)
+}
+
+// FindGeneratedDocument returns the most recent document with the given checksum, or nil
+func (i *Invoice) FindGeneratedDocument(checksum string) *GeneratedDoc {
+	for idx := len(i.GeneratedDocuments) - 1; idx >= 0; idx-- {
+		if i.GeneratedDocuments[idx].Checksum == checksum {
+			return &i.GeneratedDocuments[idx]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentChecksum(t *testing.T) {
+	// SHA-256 of the empty input
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", DocumentChecksum(nil))
+	assert.NotEqual(t, DocumentChecksum([]byte("a")), DocumentChecksum([]byte("b")))
+}
+
+func TestNewGeneratedDoc(t *testing.T) {
+	doc := NewGeneratedDoc("/tmp/doc.html", DocumentFormatHTML, []byte("<html></html>"), StatusSent, "default")
+
+	assert.Equal(t, "/tmp/doc.html", doc.Path)
+	assert.Equal(t, DocumentFormatHTML, doc.Format)
+	assert.Equal(t, DocumentChecksum([]byte("<html></html>")), doc.Checksum)
+	assert.Equal(t, "default", doc.Template)
+	assert.False(t, doc.GeneratedAt.IsZero())
+	assert.True(t, doc.WasIssued())
+
+	draft := NewGeneratedDoc("/tmp/draft.html", DocumentFormatHTML, nil, StatusDraft, "")
+	assert.False(t, draft.WasIssued())
+}
+
+func TestInvoiceGeneratedDocuments(t *testing.T) {
+	invoice := &Invoice{}
+	assert.Nil(t, invoice.FindGeneratedDocument("missing"))
+
+	first := NewGeneratedDoc("/docs/v1.html", DocumentFormatHTML, []byte("v1"), StatusDraft, "")
+	second := NewGeneratedDoc("/docs/v2.html", DocumentFormatHTML, []byte("v1"), StatusSent, "")
+	invoice.RecordGeneratedDocument(first)
+	invoice.RecordGeneratedDocument(second)
+
+	require.Len(t, invoice.GeneratedDocuments, 2)
+
+	// The most recent document with a matching checksum wins
+	found := invoice.FindGeneratedDocument(DocumentChecksum([]byte("v1")))
+	require.NotNil(t, found)
+	assert.Equal(t, "/docs/v2.html", found.Path)
+}
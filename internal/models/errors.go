@@ -22,6 +22,15 @@ var (
 	ErrWorkItemNotFound        = fmt.Errorf("work item not found")
 	ErrInvalidStatus           = fmt.Errorf("invalid status")
 	ErrCannotVoidPaidInvoice   = fmt.Errorf("cannot void a paid invoice")
+	ErrInvalidTermsAnchor      = fmt.Errorf("invalid terms anchor (must be issue or eom)")
+	ErrInvalidNumberFormat     = fmt.Errorf("invalid invoice number format")
+	ErrInvalidCurrency         = fmt.Errorf("invalid currency (must be an ISO 4217 code such as USD or EUR)")
+
+	// Payment-related errors
+	ErrPaymentAmountNotPositive = fmt.Errorf("payment amount must be greater than 0")
+	ErrPaymentExceedsBalance    = fmt.Errorf("payment exceeds the outstanding balance")
+	ErrPaymentDateRequired      = fmt.Errorf("payment date is required")
+	ErrCannotPayVoidedInvoice   = fmt.Errorf("cannot record a payment on a voided invoice")
 
 	// Work item-related errors
 	ErrWorkItemValidationFailed = fmt.Errorf("work item validation failed")
@@ -38,6 +47,19 @@ var (
 	ErrLineItemAmountRequired   = fmt.Errorf("amount is required for fixed line items")
 	ErrLineItemQuantityRequired = fmt.Errorf("quantity and unit price are required for quantity line items")
 
+	// Discount-related errors
+	ErrInvalidDiscount       = fmt.Errorf("invalid discount (use a percentage such as 10%% or an amount such as 25.00)")
+	ErrDiscountExceedsAmount = fmt.Errorf("discount cannot exceed the amount it applies to")
+	ErrConflictingDiscounts  = fmt.Errorf("use either a percentage or a fixed invoice discount, not both")
+
+	// Attachment-related errors
+	ErrInvalidAttachmentName = fmt.Errorf("invalid attachment filename (must be a plain file name without directories)")
+	ErrAttachmentExists      = fmt.Errorf("an attachment with this filename already exists")
+
+	// Crypto address errors
+	ErrInvalidUSDCAddress = fmt.Errorf("invalid USDC address (must be 0x followed by 40 hex characters)")
+	ErrInvalidBSVAddress  = fmt.Errorf("invalid BSV address (must be a base58check bitcoin address)")
+
 	// Request validation errors
 	ErrFilterValidationFailed      = fmt.Errorf("filter validation failed")
 	ErrCreateInvoiceRequestInvalid = fmt.Errorf("create invoice request validation failed")
@@ -50,12 +72,23 @@ var (
 	ErrInvoiceNotFound                  = fmt.Errorf("invoice not found")
 	ErrConfirmationRequired             = fmt.Errorf("confirmation required")
 	ErrCannotDeletePaidInvoice          = fmt.Errorf("cannot delete paid invoice")
+	ErrInvoiceAlreadyDeleted            = fmt.Errorf("invoice is already deleted")
+	ErrInvoiceNotDeleted                = fmt.Errorf("invoice is not deleted")
 	ErrCannotAddWorkItemToNonDraft      = fmt.Errorf("can only add work items to draft invoices")
 	ErrCannotRemoveWorkItemFromNonDraft = fmt.Errorf("can only remove work items from draft invoices")
+	ErrCannotChangeLineItemsOnNonDraft  = fmt.Errorf("can only change line items on draft or unlocked invoices")
+	ErrCannotChangeLineItemsOnPaid      = fmt.Errorf("cannot change line items on a paid invoice")
 	ErrCannotSendNonDraftInvoice        = fmt.Errorf("can only send draft invoices")
 	ErrCannotSendEmptyInvoice           = fmt.Errorf("cannot send invoice with no work items")
 	ErrCannotMarkNonSentAsPaid          = fmt.Errorf("can only mark sent or overdue invoices as paid")
+	ErrTaxRateOutOfRange                = fmt.Errorf("tax rate must be between 0 and 1")
+	ErrCannotChangeTaxOnIssuedInvoice   = fmt.Errorf("cannot change tax rate on an issued invoice without force")
+	ErrCannotChangeTaxOnVoidedInvoice   = fmt.Errorf("cannot change tax rate on a voided invoice")
 	ErrInvoiceNumberExists              = fmt.Errorf("invoice number already exists")
+	ErrCannotRemindInvoice              = fmt.Errorf("can only remind sent or overdue invoices with a balance due")
+	ErrInvoiceLocked                    = fmt.Errorf("invoice is locked, its financial details cannot change after it was sent")
+	ErrInvoiceNotLocked                 = fmt.Errorf("invoice is not locked")
+	ErrUnlockReasonRequired             = fmt.Errorf("a reason is required to unlock an invoice")
 
 	// Client service errors
 	ErrClientIDEmpty                            = fmt.Errorf("client ID cannot be empty")
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"context"
+	"math"
+)
+
+// minutesPerHour converts between billed hours and rounding increments
+const minutesPerHour = 60
+
+// HourlyRounding rounds logged hours up to a billing increment and enforces a minimum charge.
+// The zero value disables both rules and bills hours exactly as logged.
+type HourlyRounding struct {
+	IncrementMinutes int     `json:"increment_minutes,omitempty"` // Round hours up to this many minutes, e.g. 15
+	MinimumHours     float64 `json:"minimum_hours,omitempty"`     // Bill at least this many hours per entry
+}
+
+// Enabled reports whether the rule changes any hours
+func (r HourlyRounding) Enabled() bool {
+	return r.IncrementMinutes > 0 || r.MinimumHours > 0
+}
+
+// Apply returns the hours to bill for hours logged: rounded up to the increment, then raised to
+// the minimum. Hours already on an increment are left alone.
+func (r HourlyRounding) Apply(hours float64) float64 {
+	billed := hours
+
+	if r.IncrementMinutes > 0 {
+		// Round the minutes to a microsecond first so 0.25 hours is exactly 15 minutes and not
+		// a float hair above it
+		minutes := math.Round(hours*minutesPerHour*1e6) / 1e6
+		increments := math.Ceil(minutes / float64(r.IncrementMinutes))
+		billed = increments * float64(r.IncrementMinutes) / minutesPerHour
+	}
+
+	if billed < r.MinimumHours {
+		billed = r.MinimumHours
+	}
+
+	return billed
+}
+
+// ApplyHourlyRounding bills an hourly line item with rule, keeping the hours as logged in
+// LoggedHours when they change. Other line item types are left alone.
+func (l *LineItem) ApplyHourlyRounding(ctx context.Context, rule HourlyRounding) error {
+	if l.Type != LineItemTypeHourly || l.Hours == nil || !rule.Enabled() {
+		return nil
+	}
+
+	logged := *l.Hours
+	if l.LoggedHours != nil {
+		logged = *l.LoggedHours
+	}
+
+	billed := rule.Apply(logged)
+	if billed == logged {
+		l.LoggedHours = nil
+	} else {
+		l.LoggedHours = &logged
+	}
+	l.Hours = &billed
+
+	return l.RecalculateTotal(ctx)
+}
+
+// ApplyHourlyRounding bills the work item with rule, recalculating its total
+func (w *WorkItem) ApplyHourlyRounding(rule HourlyRounding) {
+	if !rule.Enabled() {
+		return
+	}
+
+	w.Hours = rule.Apply(w.Hours)
+	w.Total = math.Round(w.Hours*w.Rate*100) / 100
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func TestHourlyRoundingApply(t *testing.T) {
+	quarter := HourlyRounding{IncrementMinutes: 15}
+	withMinimum := HourlyRounding{IncrementMinutes: 15, MinimumHours: 1}
+
+	cases := []struct {
+		name  string
+		rule  HourlyRounding
+		hours float64
+		want  float64
+	}{
+		{"DisabledKeepsExactHours", HourlyRounding{}, 1.1, 1.1},
+		{"ExactlyOnIncrement", quarter, 0.25, 0.25},
+		{"ExactlyOnWholeHour", quarter, 2, 2},
+		{"JustOverIncrement", quarter, 1.01, 1.25},
+		{"JustUnderIncrement", quarter, 1.24, 1.25},
+		{"TenthOfAnHour", quarter, 0.1, 0.25},
+		{"SixMinuteIncrements", HourlyRounding{IncrementMinutes: 6}, 0.15, 0.2},
+		{"BelowMinimum", withMinimum, 0.25, 1},
+		{"ExactlyMinimum", withMinimum, 1, 1},
+		{"AboveMinimumStillRounds", withMinimum, 1.1, 1.25},
+		{"MinimumWithoutIncrement", HourlyRounding{MinimumHours: 0.5}, 0.3, 0.5},
+		{"MinimumWithoutIncrementAbove", HourlyRounding{MinimumHours: 0.5}, 0.7, 0.7},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.want, tc.rule.Apply(tc.hours), 1e-9)
+		})
+	}
+}
+
+func TestLineItemApplyHourlyRounding(t *testing.T) {
+	ctx := context.Background()
+	rule := HourlyRounding{IncrementMinutes: 15, MinimumHours: 0.5}
+
+	t.Run("RoundsAndRecordsLoggedHours", func(t *testing.T) {
+		item, err := NewHourlyLineItem(ctx, "line-1", time.Now(), 1.1, 100, "Development")
+		require.NoError(t, err)
+
+		require.NoError(t, item.ApplyHourlyRounding(ctx, rule))
+		assert.InDelta(t, 1.25, *item.Hours, 1e-9)
+		require.NotNil(t, item.LoggedHours)
+		assert.InDelta(t, 1.1, *item.LoggedHours, 1e-9)
+		assert.Equal(t, money.Amount(12500), item.Total)
+		assert.Equal(t, "1.25 hours @ $100.00/hr (rounded from 1.10 logged)", item.GetDetails())
+		require.NoError(t, item.Validate(ctx))
+	})
+
+	t.Run("EnforcesMinimum", func(t *testing.T) {
+		item, err := NewHourlyLineItem(ctx, "line-2", time.Now(), 0.1, 100, "Quick call")
+		require.NoError(t, err)
+
+		require.NoError(t, item.ApplyHourlyRounding(ctx, rule))
+		assert.InDelta(t, 0.5, *item.Hours, 1e-9)
+		assert.Equal(t, money.Amount(5000), item.Total)
+	})
+
+	t.Run("OnIncrementLeavesDetailsAlone", func(t *testing.T) {
+		item, err := NewHourlyLineItem(ctx, "line-3", time.Now(), 0.75, 100, "Review")
+		require.NoError(t, err)
+
+		require.NoError(t, item.ApplyHourlyRounding(ctx, rule))
+		assert.Nil(t, item.LoggedHours)
+		assert.Equal(t, "0.75 hours @ $100.00/hr", item.GetDetails())
+	})
+
+	t.Run("DiscountAppliesToRoundedHours", func(t *testing.T) {
+		item, err := NewHourlyLineItem(ctx, "line-4", time.Now(), 1.1, 100, "Development")
+		require.NoError(t, err)
+		item.Discount = &Discount{Type: DiscountTypePercent, Value: 10}
+
+		require.NoError(t, item.ApplyHourlyRounding(ctx, rule))
+		assert.Equal(t, money.Amount(11250), item.Total)
+	})
+
+	t.Run("IgnoresOtherTypes", func(t *testing.T) {
+		item, err := NewFixedLineItem(ctx, "line-5", time.Now(), 10, "Fee")
+		require.NoError(t, err)
+
+		require.NoError(t, item.ApplyHourlyRounding(ctx, rule))
+		assert.Equal(t, money.Amount(1000), item.Total)
+	})
+}
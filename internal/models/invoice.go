@@ -3,32 +3,60 @@ package models
 import (
 	"context"
 	"fmt"
-	"math"
 	"strings"
 	"time"
+
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// Length limits for the optional free-text invoice fields
+const (
+	MaxInvoiceNotesLength    = 2000
+	MaxPONumberLength        = 50
+	MaxClientReferenceLength = 100
 )
 
 // Invoice represents a complete invoice entity
 type Invoice struct {
-	ID                  InvoiceID  `json:"id"`
-	Number              string     `json:"number"`
-	Date                time.Time  `json:"date"`
-	DueDate             time.Time  `json:"due_date"`
-	Client              Client     `json:"client"`
-	WorkItems           []WorkItem `json:"work_items"`           // Deprecated: kept for backward compatibility
-	LineItems           []LineItem `json:"line_items,omitempty"` // New: flexible line items
-	Status              string     `json:"status"`
-	Description         string     `json:"description,omitempty"`
-	Subtotal            float64    `json:"subtotal"`
-	CryptoFee           float64    `json:"crypto_fee"`
-	TaxRate             float64    `json:"tax_rate"`
-	TaxAmount           float64    `json:"tax_amount"`
-	Total               float64    `json:"total"`
-	USDCAddressOverride *string    `json:"usdc_address_override,omitempty"` // Optional per-invoice USDC address override
-	BSVAddressOverride  *string    `json:"bsv_address_override,omitempty"`  // Optional per-invoice BSV address override
-	CreatedAt           time.Time  `json:"created_at"`
-	UpdatedAt           time.Time  `json:"updated_at"`
-	Version             int        `json:"version"` // For optimistic locking
+	ID                  InvoiceID       `json:"id"`
+	Number              string          `json:"number"`
+	Date                time.Time       `json:"date"`
+	DueDate             time.Time       `json:"due_date"`
+	Client              Client          `json:"client"`
+	WorkItems           []WorkItem      `json:"work_items"`           // Deprecated: kept for backward compatibility
+	LineItems           []LineItem      `json:"line_items,omitempty"` // New: flexible line items
+	Status              string          `json:"status"`
+	Description         string          `json:"description,omitempty"`
+	Notes               string          `json:"notes,omitempty"`            // Free-form notes printed in the invoice footer
+	PONumber            string          `json:"po_number,omitempty"`        // Client purchase order number
+	ClientReference     string          `json:"client_reference,omitempty"` // Client's own reference for the work, e.g. a project code
+	Subtotal            money.Amount    `json:"subtotal"`
+	DiscountPercent     float64         `json:"discount_percent,omitempty"` // Invoice discount as a percentage of the subtotal (10 = 10%)
+	DiscountAmount      money.Amount    `json:"discount_amount,omitempty"`  // Invoice discount as a fixed amount
+	DiscountTotal       money.Amount    `json:"discount_total,omitempty"`   // Invoice discount taken off the subtotal
+	CryptoFee           money.Amount    `json:"crypto_fee"`
+	CryptoQuote         *CryptoQuote    `json:"crypto_quote,omitempty"` // Crypto amount due, fixed at the rate when generated
+	TaxRate             float64         `json:"tax_rate"`
+	TaxAmount           money.Amount    `json:"tax_amount"`
+	TaxRounding         string          `json:"tax_rounding,omitempty"` // Tax rounding strategy (see TaxRoundings); empty means half-up
+	Total               money.Amount    `json:"total"`
+	Currency            string          `json:"currency,omitempty"`              // ISO 4217 code; empty on invoices created before per-invoice currency
+	USDCAddressOverride *string         `json:"usdc_address_override,omitempty"` // Optional per-invoice USDC address override
+	BSVAddressOverride  *string         `json:"bsv_address_override,omitempty"`  // Optional per-invoice BSV address override
+	TemplateName        string          `json:"template_name,omitempty"`         // Optional per-invoice template, overrides the client template
+	GeneratedDocuments  []GeneratedDoc  `json:"generated_documents,omitempty"`   // Documents rendered and archived for this invoice
+	Attachments         []Attachment    `json:"attachments,omitempty"`           // Supporting files such as receipts and timesheets
+	Payments            []Payment       `json:"payments,omitempty"`              // Payments received, possibly partial
+	LastReminderAt      *time.Time      `json:"last_reminder_at,omitempty"`      // When the last payment reminder was emailed
+	Locked              bool            `json:"locked,omitempty"`                // Financial details are frozen, set when the invoice is sent
+	LockedAt            *time.Time      `json:"locked_at,omitempty"`             // When the invoice was locked
+	Unlocks             []InvoiceUnlock `json:"unlocks,omitempty"`               // Who unlocked the invoice for editing, and why
+	Events              []InvoiceEvent  `json:"events,omitempty"`                // Append-only history of changes to the invoice
+	DeletedAt           *time.Time      `json:"deleted_at,omitempty"`            // Set while the invoice is soft deleted (in the trash)
+	CreatedAt           time.Time       `json:"created_at"`
+	UpdatedAt           time.Time       `json:"updated_at"`
+	Version             int             `json:"version"`        // For optimistic locking
+	SchemaVersion       int             `json:"schema_version"` // Shape of the stored record, upgraded by storage on read
 }
 
 // WorkItem represents a single work entry on an invoice
@@ -44,19 +72,23 @@ type WorkItem struct {
 
 // Client represents customer information
 type Client struct {
-	ID               ClientID  `json:"id"`
-	Name             string    `json:"name"`
-	Email            string    `json:"email"`
-	Phone            string    `json:"phone,omitempty"`
-	Address          string    `json:"address,omitempty"`
-	TaxID            string    `json:"tax_id,omitempty"`
-	ApproverContacts string    `json:"approver_contacts,omitempty"`
-	Active           bool      `json:"active"`
-	CryptoFeeEnabled bool      `json:"crypto_fee_enabled"`
-	CryptoFeeAmount  float64   `json:"crypto_fee_amount,omitempty"`
-	LateFeeEnabled   bool      `json:"late_fee_enabled"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	ID                ClientID  `json:"id"`
+	Name              string    `json:"name"`
+	Email             string    `json:"email"`
+	Phone             string    `json:"phone,omitempty"`
+	Address           string    `json:"address,omitempty"`
+	TaxID             string    `json:"tax_id,omitempty"`
+	TaxExempt         bool      `json:"tax_exempt,omitempty"`       // No tax is charged on this client's invoices
+	ExemptionReason   string    `json:"exemption_reason,omitempty"` // Printed on invoices instead of the tax line
+	ApproverContacts  string    `json:"approver_contacts,omitempty"`
+	Active            bool      `json:"active"`
+	CryptoFeeEnabled  bool      `json:"crypto_fee_enabled"`
+	CryptoFeeAmount   float64   `json:"crypto_fee_amount,omitempty"`
+	CryptoFeeCurrency string    `json:"crypto_fee_currency,omitempty"` // USDC or BSV: invoices show the amount due in this currency
+	LateFeeEnabled    bool      `json:"late_fee_enabled"`
+	TemplateName      string    `json:"template_name,omitempty"` // Optional template used for this client's invoices
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 // NewInvoice creates a new invoice with validation
@@ -143,6 +175,41 @@ func (i *Invoice) validateBasicFields(errors *[]ValidationError) {
 			Value:   i.Number,
 		})
 	}
+
+	if i.Currency != "" && !IsValidCurrency(i.Currency) {
+		*errors = append(*errors, ValidationError{
+			Field:   "currency",
+			Message: "must be an ISO 4217 currency code",
+			Value:   i.Currency,
+		})
+	}
+
+	if !IsValidTaxRounding(i.TaxRounding) {
+		*errors = append(*errors, ValidationError{
+			Field:   "tax_rounding",
+			Message: "must be one of " + strings.Join(TaxRoundings(), ", "),
+			Value:   i.TaxRounding,
+		})
+	}
+
+	// Optional free-text fields are only checked for length
+	for _, field := range []struct {
+		name   string
+		value  string
+		maxLen int
+	}{
+		{"notes", i.Notes, MaxInvoiceNotesLength},
+		{"po_number", i.PONumber, MaxPONumberLength},
+		{"client_reference", i.ClientReference, MaxClientReferenceLength},
+	} {
+		if len(field.value) > field.maxLen {
+			*errors = append(*errors, ValidationError{
+				Field:   field.name,
+				Message: fmt.Sprintf("cannot exceed %d characters", field.maxLen),
+				Value:   len(field.value),
+			})
+		}
+	}
 }
 
 // validateDates validates date and due_date fields
@@ -221,6 +288,30 @@ func (i *Invoice) validateFinancials(errors *[]ValidationError) {
 		})
 	}
 
+	if i.DiscountPercent < 0 || i.DiscountPercent > 100 {
+		*errors = append(*errors, ValidationError{
+			Field:   "discount_percent",
+			Message: "must be between 0 and 100",
+			Value:   i.DiscountPercent,
+		})
+	}
+
+	if i.DiscountAmount < 0 {
+		*errors = append(*errors, ValidationError{
+			Field:   "discount_amount",
+			Message: "must be non-negative",
+			Value:   i.DiscountAmount,
+		})
+	}
+
+	if i.DiscountPercent > 0 && i.DiscountAmount > 0 {
+		*errors = append(*errors, ValidationError{
+			Field:   "discount",
+			Message: ErrConflictingDiscounts.Error(),
+			Value:   fmt.Sprintf("%v%%, %v", i.DiscountPercent, i.DiscountAmount),
+		})
+	}
+
 	if i.Subtotal < 0 {
 		*errors = append(*errors, ValidationError{
 			Field:   "subtotal",
@@ -306,6 +397,10 @@ func (i *Invoice) AddWorkItem(ctx context.Context, item WorkItem) error {
 	default:
 	}
 
+	if err := i.EnsureUnlocked(); err != nil {
+		return err
+	}
+
 	// Validate the work item
 	if err := item.Validate(ctx); err != nil {
 		return fmt.Errorf("invalid work item: %w", err)
@@ -335,6 +430,10 @@ func (i *Invoice) AddWorkItemWithoutVersionIncrement(ctx context.Context, item W
 	default:
 	}
 
+	if err := i.EnsureUnlocked(); err != nil {
+		return err
+	}
+
 	// Validate the work item
 	if err := item.Validate(ctx); err != nil {
 		return fmt.Errorf("invalid work item: %w", err)
@@ -362,6 +461,10 @@ func (i *Invoice) RemoveWorkItem(ctx context.Context, itemID string) error {
 	default:
 	}
 
+	if err := i.EnsureUnlocked(); err != nil {
+		return err
+	}
+
 	// Find and remove the item
 	found := false
 	for idx, item := range i.WorkItems {
@@ -398,12 +501,12 @@ func (i *Invoice) RecalculateTotals(ctx context.Context) error {
 	default:
 	}
 
-	// Calculate subtotal from both work items and line items
-	subtotal := 0.0
+	// Calculate subtotal from both work items and line items, in whole cents
+	var subtotal money.Amount
 
 	// Add work items (for backward compatibility)
 	for _, item := range i.WorkItems {
-		subtotal += item.Total
+		subtotal += money.FromFloat(item.Total)
 	}
 
 	// Add line items
@@ -411,20 +514,33 @@ func (i *Invoice) RecalculateTotals(ctx context.Context) error {
 		subtotal += item.Total
 	}
 
-	// Round to avoid floating point precision issues
-	i.Subtotal = math.Round(subtotal*100) / 100
+	i.Subtotal = subtotal
+
+	// Take the invoice discount off the subtotal before the crypto fee and tax are added
+	i.DiscountTotal = discountOff(i.Subtotal, i.DiscountPercent, i.DiscountAmount)
 
-	// Calculate tax amount on (subtotal + crypto fee)
-	taxableAmount := i.Subtotal + i.CryptoFee
-	i.TaxAmount = math.Round(taxableAmount*i.TaxRate*100) / 100
+	// Calculate tax amount on (discounted subtotal + crypto fee), rounded with the invoice's strategy
+	taxableAmount := i.Subtotal - i.DiscountTotal + i.CryptoFee
+	i.TaxAmount = i.computeTax(taxableAmount)
 
-	// Calculate total (subtotal + crypto fee + tax)
-	i.Total = math.Round((taxableAmount+i.TaxAmount)*100) / 100
+	// Calculate total (discounted subtotal + crypto fee + tax)
+	i.Total = taxableAmount + i.TaxAmount
 
 	return nil
 }
 
-// SetCryptoFee sets the cryptocurrency service fee if applicable
+// EffectiveTaxRate returns the tax rate charged on the invoice: zero for a tax-exempt client,
+// whatever rate is set on the invoice, and TaxRate otherwise
+func (i *Invoice) EffectiveTaxRate() float64 {
+	if i.Client.TaxExempt {
+		return 0
+	}
+	return i.TaxRate
+}
+
+// SetCryptoFee sets the cryptocurrency service fee if applicable. When crypto payments are
+// enabled, any per-invoice address overrides must be valid so a mistyped address is not
+// printed on the invoice.
 func (i *Invoice) SetCryptoFee(ctx context.Context, cryptoPaymentsEnabled, feeEnabled bool, feeAmount float64) error {
 	select {
 	case <-ctx.Done():
@@ -432,13 +548,27 @@ func (i *Invoice) SetCryptoFee(ctx context.Context, cryptoPaymentsEnabled, feeEn
 	default:
 	}
 
+	if cryptoPaymentsEnabled {
+		if err := i.validateAddressOverrides(); err != nil {
+			return err
+		}
+	}
+
 	// Apply crypto service fee if crypto payments are enabled and fee is enabled
+	var fee money.Amount
 	if cryptoPaymentsEnabled && feeEnabled {
-		i.CryptoFee = feeAmount
-	} else {
-		i.CryptoFee = 0.0
+		fee = money.FromFloat(feeAmount)
 	}
 
+	// A locked invoice keeps the totals it was sent with
+	if i.Locked {
+		if fee != i.CryptoFee {
+			return fmt.Errorf("%w, crypto fee would change from %s to %s", i.EnsureUnlocked(), i.CryptoFee, fee)
+		}
+		return nil
+	}
+	i.CryptoFee = fee
+
 	// Recalculate totals with the new crypto fee
 	return i.RecalculateTotals(ctx)
 }
@@ -470,9 +600,20 @@ func (i *Invoice) UpdateStatus(ctx context.Context, newStatus string) error {
 		return ErrCannotVoidPaidInvoice
 	}
 
+	// Sending an invoice locks its financial details
+	now := time.Now()
+	if newStatus == StatusSent && i.Status != StatusSent {
+		i.Lock(now)
+	}
+
+	// An invoice whose payments cover the total is paid, whatever status was requested
+	if newStatus != StatusVoided && i.IsFullyPaid() {
+		newStatus = StatusPaid
+	}
+
 	// Update status
 	i.Status = newStatus
-	i.UpdatedAt = time.Now()
+	i.UpdatedAt = now
 	// Version should only be incremented by the storage layer during save
 	// i.Version++
 
@@ -484,14 +625,29 @@ func (i *Invoice) IsOverdue() bool {
 	return i.Status != StatusPaid && i.Status != StatusVoided && time.Now().After(i.DueDate)
 }
 
+// IsDeleted reports whether the invoice has been soft deleted
+func (i *Invoice) IsDeleted() bool {
+	return i.DeletedAt != nil
+}
+
 // GetAgeInDays returns the age of the invoice in days
 func (i *Invoice) GetAgeInDays() int {
-	return int(time.Since(i.Date).Hours() / 24)
+	return i.AgeInDaysAt(time.Now())
+}
+
+// AgeInDaysAt returns the age of the invoice in days as of asOf
+func (i *Invoice) AgeInDaysAt(asOf time.Time) int {
+	return int(asOf.Sub(i.Date).Hours() / 24)
 }
 
 // GetDaysUntilDue returns the number of days until the due date (negative if overdue)
 func (i *Invoice) GetDaysUntilDue() int {
-	return int(time.Until(i.DueDate).Hours() / 24)
+	return i.DaysUntilDueAt(time.Now())
+}
+
+// DaysUntilDueAt returns the number of days from asOf until the due date (negative if overdue)
+func (i *Invoice) DaysUntilDueAt(asOf time.Time) int {
+	return int(i.DueDate.Sub(asOf).Hours() / 24)
 }
 
 // AddLineItem adds a line item to the invoice and recalculates totals
@@ -502,6 +658,10 @@ func (i *Invoice) AddLineItem(ctx context.Context, item LineItem) error {
 	default:
 	}
 
+	if err := i.EnsureUnlocked(); err != nil {
+		return err
+	}
+
 	// Validate the line item
 	if err := item.Validate(ctx); err != nil {
 		return fmt.Errorf("invalid line item: %w", err)
@@ -531,6 +691,10 @@ func (i *Invoice) AddLineItemWithoutVersionIncrement(ctx context.Context, item L
 	default:
 	}
 
+	if err := i.EnsureUnlocked(); err != nil {
+		return err
+	}
+
 	// Validate the line item
 	if err := item.Validate(ctx); err != nil {
 		return fmt.Errorf("invalid line item: %w", err)
@@ -552,41 +716,102 @@ func (i *Invoice) AddLineItemWithoutVersionIncrement(ctx context.Context, item L
 
 // RemoveLineItem removes a line item by ID and recalculates totals
 func (i *Invoice) RemoveLineItem(ctx context.Context, itemID string) error {
+	if err := i.RemoveLineItemWithoutVersionIncrement(ctx, itemID); err != nil {
+		return err
+	}
+
+	// Update version
+	i.Version++
+
+	return nil
+}
+
+// RemoveLineItemWithoutVersionIncrement removes a line item by ID without incrementing
+// version, for updates whose version is handled by storage
+func (i *Invoice) RemoveLineItemWithoutVersionIncrement(ctx context.Context, itemID string) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
 	}
 
-	// Find and remove the item
-	found := false
-	for idx, item := range i.LineItems {
-		if item.ID == itemID {
-			// Remove item by slicing
-			i.LineItems = append(i.LineItems[:idx], i.LineItems[idx+1:]...)
-			found = true
-			break
-		}
+	if err := i.EnsureUnlocked(); err != nil {
+		return err
 	}
 
-	if !found {
+	// Find and remove the item
+	idx := i.lineItemIndex(itemID)
+	if idx < 0 {
 		return fmt.Errorf("%w: %s", ErrLineItemNotFound, itemID)
 	}
+	i.LineItems = append(i.LineItems[:idx], i.LineItems[idx+1:]...)
 
 	// Recalculate totals
 	if err := i.RecalculateTotals(ctx); err != nil {
 		return fmt.Errorf("failed to recalculate totals after removing line item: %w", err)
 	}
 
-	// Update timestamp and version
+	// Update timestamp
 	i.UpdatedAt = time.Now()
-	i.Version++
 
 	return nil
 }
 
-// MigrateWorkItemsToLineItems converts all WorkItems to LineItems for backward compatibility
-// This is called automatically when an invoice is loaded from storage
+// ReplaceLineItem replaces the line item with the same ID and recalculates totals. The
+// version is not incremented; it is handled by storage.
+func (i *Invoice) ReplaceLineItem(ctx context.Context, item LineItem) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := i.EnsureUnlocked(); err != nil {
+		return err
+	}
+
+	idx := i.lineItemIndex(item.ID)
+	if idx < 0 {
+		return fmt.Errorf("%w: %s", ErrLineItemNotFound, item.ID)
+	}
+
+	// Validate the line item
+	if err := item.Validate(ctx); err != nil {
+		return fmt.Errorf("invalid line item: %w", err)
+	}
+	i.LineItems[idx] = item
+
+	// Recalculate totals
+	if err := i.RecalculateTotals(ctx); err != nil {
+		return fmt.Errorf("failed to recalculate totals after updating line item: %w", err)
+	}
+
+	// Update timestamp
+	i.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// FindLineItem returns the line item with the given ID, or nil when there is none
+func (i *Invoice) FindLineItem(itemID string) *LineItem {
+	if idx := i.lineItemIndex(itemID); idx >= 0 {
+		return &i.LineItems[idx]
+	}
+	return nil
+}
+
+// lineItemIndex returns the index of the line item with the given ID, or -1
+func (i *Invoice) lineItemIndex(itemID string) int {
+	for idx, item := range i.LineItems {
+		if item.ID == itemID {
+			return idx
+		}
+	}
+	return -1
+}
+
+// MigrateWorkItemsToLineItems converts all WorkItems to LineItems for backward compatibility.
+// The WorkItems are cleared once converted so RecalculateTotals does not count them twice.
 func (i *Invoice) MigrateWorkItemsToLineItems(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
@@ -604,8 +829,7 @@ func (i *Invoice) MigrateWorkItemsToLineItems(ctx context.Context) error {
 			i.LineItems = append(i.LineItems, *li)
 		}
 
-		// Clear work items after migration (optional - keep for backward compat)
-		// i.WorkItems = nil
+		i.WorkItems = nil
 	}
 
 	return nil
@@ -636,7 +860,7 @@ func (i *Invoice) GetAllItems() []LineItem {
 			Description: wi.Description,
 			Hours:       &hours,
 			Rate:        &rate,
-			Total:       wi.Total,
+			Total:       money.FromFloat(wi.Total),
 			CreatedAt:   wi.CreatedAt,
 		})
 	}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Invoice event types recorded in an invoice's history
+const (
+	EventCreated         = "created"
+	EventSent            = "sent"
+	EventStatusChanged   = "status_changed"
+	EventLineItemAdded   = "line_item_added"
+	EventLineItemRemoved = "line_item_removed"
+	EventLineItemUpdated = "line_item_updated"
+	EventPaymentRecorded = "payment_recorded"
+)
+
+// InvoiceEvent is an entry in an invoice's history. Events are only ever appended; once
+// recorded an event is never changed or removed.
+type InvoiceEvent struct {
+	At      time.Time `json:"at"`
+	Type    string    `json:"type"`
+	Summary string    `json:"summary"` // Human readable description, e.g. "Status changed from sent to paid"
+}
+
+// RecordEvent appends an event to the invoice's history
+func (i *Invoice) RecordEvent(eventType, summary string, at time.Time) {
+	i.Events = append(i.Events, InvoiceEvent{At: at, Type: eventType, Summary: summary})
+}
+
+// History returns a copy of the invoice's events in chronological order. Events recorded at
+// the same time keep the order they were recorded in.
+func (i *Invoice) History() []InvoiceEvent {
+	history := slices.Clone(i.Events)
+	slices.SortStableFunc(history, func(a, b InvoiceEvent) int {
+		return a.At.Compare(b.At)
+	})
+	return history
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvoiceHistory(t *testing.T) {
+	created := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	invoice := &Invoice{}
+	assert.Empty(t, invoice.History())
+
+	invoice.RecordEvent(EventSent, "Invoice sent", created.Add(time.Hour))
+	invoice.RecordEvent(EventCreated, "Invoice INV-001 created for Acme", created)
+	invoice.RecordEvent(EventLineItemAdded, `Added "Setup" ($500.00)`, created.Add(time.Hour))
+
+	history := invoice.History()
+	require.Len(t, history, 3)
+	assert.Equal(t, EventCreated, history[0].Type)
+	assert.Equal(t, EventSent, history[1].Type) // Same time: kept in recording order
+	assert.Equal(t, EventLineItemAdded, history[2].Type)
+
+	// History is a copy; the recorded events are not changed
+	history[0].Summary = "Changed"
+	assert.Equal(t, EventSent, invoice.Events[0].Type)
+	assert.Equal(t, "Invoice INV-001 created for Acme", invoice.Events[1].Summary)
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// InvoiceUnlock records an invoice being unlocked so its financial details can be corrected
+type InvoiceUnlock struct {
+	At     time.Time `json:"at"`
+	By     string    `json:"by"`
+	Reason string    `json:"reason"`
+}
+
+// Lock freezes the invoice's financial details: items, discount, tax rate, crypto fee and
+// payment addresses. Status changes and payments are still recorded on a locked invoice.
+func (i *Invoice) Lock(at time.Time) {
+	if i.Locked {
+		return
+	}
+	i.Locked = true
+	i.LockedAt = &at
+}
+
+// Unlock lets the financial details of a locked invoice change again, recording who unlocked
+// it and why
+func (i *Invoice) Unlock(by, reason string, at time.Time) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrUnlockReasonRequired
+	}
+	if !i.Locked {
+		return fmt.Errorf("%w: %s", ErrInvoiceNotLocked, i.Number)
+	}
+
+	i.Locked = false
+	i.LockedAt = nil
+	i.Unlocks = append(i.Unlocks, InvoiceUnlock{At: at, By: by, Reason: reason})
+	i.UpdatedAt = at
+	return nil
+}
+
+// EnsureUnlocked returns ErrInvoiceLocked when the invoice's financial details are locked
+func (i *Invoice) EnsureUnlocked() error {
+	if !i.Locked {
+		return nil
+	}
+	if i.LockedAt != nil {
+		return fmt.Errorf("%w: %s (locked %s)", ErrInvoiceLocked, i.Number, i.LockedAt.Format("2006-01-02"))
+	}
+	return fmt.Errorf("%w: %s", ErrInvoiceLocked, i.Number)
+}
+
+// AllowsItemChanges reports whether items can be added to or removed from the invoice: drafts
+// always, and issued invoices only once they have been explicitly unlocked
+func (i *Invoice) AllowsItemChanges() bool {
+	return i.Status == StatusDraft || (!i.Locked && len(i.Unlocks) > 0)
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// newLockTestInvoice returns a sent invoice with one fixed line item and one work item
+func newLockTestInvoice(ctx context.Context, t *testing.T) *Invoice {
+	t.Helper()
+
+	amount := 1000.0
+	invoice := &Invoice{
+		ID:      "inv_lock",
+		Number:  "INV-LOCK",
+		Status:  StatusDraft,
+		Date:    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		DueDate: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		LineItems: []LineItem{
+			{ID: "line_1", Type: LineItemTypeFixed, Date: time.Now(), Description: "Setup", Amount: &amount, Total: money.FromFloat(amount)},
+		},
+		WorkItems: []WorkItem{
+			{ID: "work_1", Date: time.Now(), Hours: 2, Rate: 100, Description: "Legacy work", Total: 200},
+		},
+	}
+	require.NoError(t, invoice.RecalculateTotals(ctx))
+	require.NoError(t, invoice.UpdateStatus(ctx, StatusSent))
+	return invoice
+}
+
+func TestUpdateStatusLocksSentInvoice(t *testing.T) {
+	ctx := context.Background()
+	invoice := newLockTestInvoice(ctx, t)
+
+	assert.True(t, invoice.Locked)
+	require.NotNil(t, invoice.LockedAt)
+	lockedAt := *invoice.LockedAt
+
+	// Later status changes keep the original lock
+	require.NoError(t, invoice.UpdateStatus(ctx, StatusOverdue))
+	require.NoError(t, invoice.UpdateStatus(ctx, StatusSent))
+	assert.True(t, invoice.Locked)
+	assert.Equal(t, lockedAt, *invoice.LockedAt)
+
+	draft := &Invoice{Status: StatusDraft}
+	require.NoError(t, draft.UpdateStatus(ctx, StatusOverdue))
+	assert.False(t, draft.Locked)
+}
+
+func TestLockedInvoiceMutations(t *testing.T) {
+	ctx := context.Background()
+	amount := 50.0
+	newItem := LineItem{ID: "line_2", Type: LineItemTypeFixed, Date: time.Now(), Description: "Extra", Amount: &amount, Total: money.FromFloat(amount), CreatedAt: time.Now()}
+	newWork := WorkItem{ID: "work_2", Date: time.Now(), Hours: 1, Rate: 100, Description: "More work", Total: 100, CreatedAt: time.Now()}
+
+	tests := []struct {
+		name    string
+		mutate  func(*Invoice) error
+		blocked bool
+	}{
+		{"AddLineItem", func(i *Invoice) error { return i.AddLineItem(ctx, newItem) }, true},
+		{"AddLineItemWithoutVersionIncrement", func(i *Invoice) error { return i.AddLineItemWithoutVersionIncrement(ctx, newItem) }, true},
+		{"RemoveLineItem", func(i *Invoice) error { return i.RemoveLineItem(ctx, "line_1") }, true},
+		{"AddWorkItem", func(i *Invoice) error { return i.AddWorkItem(ctx, newWork) }, true},
+		{"AddWorkItemWithoutVersionIncrement", func(i *Invoice) error { return i.AddWorkItemWithoutVersionIncrement(ctx, newWork) }, true},
+		{"RemoveWorkItem", func(i *Invoice) error { return i.RemoveWorkItem(ctx, "work_1") }, true},
+		{"ChangeCryptoFee", func(i *Invoice) error { return i.SetCryptoFee(ctx, true, true, 25) }, true},
+		{"SameCryptoFee", func(i *Invoice) error { return i.SetCryptoFee(ctx, true, false, 0) }, false},
+		{"SetUSDCAddressOverride", func(i *Invoice) error {
+			return i.SetUSDCAddressOverride(ctx, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+		}, true},
+		{"SetBSVAddressOverride", func(i *Invoice) error { return i.SetBSVAddressOverride(ctx, "") }, true},
+		{"MarkPaid", func(i *Invoice) error { return i.UpdateStatus(ctx, StatusPaid) }, false},
+		{"MarkOverdue", func(i *Invoice) error { return i.UpdateStatus(ctx, StatusOverdue) }, false},
+		{"Void", func(i *Invoice) error { return i.UpdateStatus(ctx, StatusVoided) }, false},
+		{"RecordPayment", func(i *Invoice) error {
+			return i.RecordPayment(ctx, Payment{Amount: 300, Date: time.Now(), Method: PaymentMethodACH})
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := newLockTestInvoice(ctx, t)
+			total, items, work := invoice.Total, len(invoice.LineItems), len(invoice.WorkItems)
+
+			err := tt.mutate(invoice)
+			if !tt.blocked {
+				require.NoError(t, err)
+				assert.Equal(t, total, invoice.Total)
+				return
+			}
+
+			require.ErrorIs(t, err, ErrInvoiceLocked)
+			assert.Contains(t, err.Error(), "INV-LOCK")
+			assert.Equal(t, total, invoice.Total)
+			assert.Len(t, invoice.LineItems, items)
+			assert.Len(t, invoice.WorkItems, work)
+			assert.Nil(t, invoice.USDCAddressOverride)
+			assert.Nil(t, invoice.BSVAddressOverride)
+
+			// The same change goes through once the invoice is unlocked
+			require.NoError(t, invoice.Unlock("alice", "Fix a mistake", time.Now()))
+			require.NoError(t, tt.mutate(invoice))
+		})
+	}
+}
+
+func TestUnlockInvoice(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+	t.Run("RecordsWhoAndWhy", func(t *testing.T) {
+		invoice := newLockTestInvoice(ctx, t)
+		assert.False(t, invoice.AllowsItemChanges())
+
+		require.NoError(t, invoice.Unlock("alice", "  Wrong rate  ", at))
+		assert.False(t, invoice.Locked)
+		assert.Nil(t, invoice.LockedAt)
+		assert.Equal(t, []InvoiceUnlock{{At: at, By: "alice", Reason: "Wrong rate"}}, invoice.Unlocks)
+		assert.True(t, invoice.AllowsItemChanges())
+		require.NoError(t, invoice.EnsureUnlocked())
+
+		// Locking again keeps the history
+		invoice.Lock(at.Add(time.Hour))
+		assert.True(t, invoice.Locked)
+		assert.False(t, invoice.AllowsItemChanges())
+		assert.Len(t, invoice.Unlocks, 1)
+	})
+
+	t.Run("ReasonRequired", func(t *testing.T) {
+		invoice := newLockTestInvoice(ctx, t)
+
+		require.ErrorIs(t, invoice.Unlock("alice", " ", at), ErrUnlockReasonRequired)
+		assert.True(t, invoice.Locked)
+		assert.Empty(t, invoice.Unlocks)
+	})
+
+	t.Run("NotLocked", func(t *testing.T) {
+		invoice := &Invoice{Number: "INV-DRAFT", Status: StatusDraft}
+
+		require.ErrorIs(t, invoice.Unlock("alice", "No reason", at), ErrInvoiceNotLocked)
+		assert.True(t, invoice.AllowsItemChanges())
+	})
+}
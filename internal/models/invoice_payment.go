@@ -0,0 +1,88 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// paymentTolerance absorbs floating point error when comparing amounts to the cent
+const paymentTolerance = 0.005
+
+// Payment records money received against an invoice
+type Payment struct {
+	Amount     float64       `json:"amount"`
+	Date       time.Time     `json:"date"`
+	Method     PaymentMethod `json:"method"`
+	Reference  string        `json:"reference,omitempty"` // Transaction hash, wire reference, check number, etc.
+	RecordedAt time.Time     `json:"recorded_at"`
+}
+
+// RecordPayment adds a payment to the invoice. The amount must be positive and may not exceed
+// the outstanding balance. When the payment settles the balance the invoice becomes paid.
+func (i *Invoice) RecordPayment(ctx context.Context, payment Payment) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if i.Status == StatusVoided {
+		return ErrCannotPayVoidedInvoice
+	}
+	if payment.Amount <= 0 {
+		return fmt.Errorf("%w: %.2f", ErrPaymentAmountNotPositive, payment.Amount)
+	}
+	if payment.Date.IsZero() {
+		return ErrPaymentDateRequired
+	}
+
+	payment.Amount = roundToCents(payment.Amount)
+	if due := i.AmountDue(); payment.Amount > due+paymentTolerance {
+		return fmt.Errorf("%w: %.2f paid, %.2f due", ErrPaymentExceedsBalance, payment.Amount, due)
+	}
+
+	if payment.RecordedAt.IsZero() {
+		payment.RecordedAt = time.Now()
+	}
+	i.Payments = append(i.Payments, payment)
+
+	// Re-apply the current status so a settled balance moves the invoice to paid
+	return i.UpdateStatus(ctx, i.Status)
+}
+
+// AmountPaid returns the sum of all recorded payments
+func (i *Invoice) AmountPaid() float64 {
+	paid := 0.0
+	for _, payment := range i.Payments {
+		paid += payment.Amount
+	}
+	return roundToCents(paid)
+}
+
+// AmountDue returns the outstanding balance; it is negative if the invoice has been overpaid
+func (i *Invoice) AmountDue() float64 {
+	return roundToCents(i.Total.Float64() - i.AmountPaid())
+}
+
+// AmountDueAt returns the outstanding balance counting only payments dated on or before asOf
+func (i *Invoice) AmountDueAt(asOf time.Time) float64 {
+	paid := 0.0
+	for _, payment := range i.Payments {
+		if !payment.Date.After(asOf) {
+			paid += payment.Amount
+		}
+	}
+	return roundToCents(i.Total.Float64() - paid)
+}
+
+// IsFullyPaid reports whether recorded payments cover the invoice total
+func (i *Invoice) IsFullyPaid() bool {
+	return len(i.Payments) > 0 && i.AmountDue() <= paymentTolerance
+}
+
+// roundToCents rounds an amount to two decimal places
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
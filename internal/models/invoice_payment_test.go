@@ -0,0 +1,112 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func newPayableInvoice(total float64) *Invoice {
+	return &Invoice{
+		ID:     "INV-PAY-001",
+		Number: "INV-0001",
+		Status: StatusSent,
+		Total:  money.FromFloat(total),
+	}
+}
+
+func TestInvoiceRecordPayment(t *testing.T) {
+	ctx := context.Background()
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("PartialThenFull", func(t *testing.T) {
+		invoice := newPayableInvoice(1000)
+
+		require.NoError(t, invoice.RecordPayment(ctx, Payment{Amount: 400, Date: date, Method: PaymentMethodWire}))
+		assert.InDelta(t, 400.0, invoice.AmountPaid(), 0.001)
+		assert.InDelta(t, 600.0, invoice.AmountDue(), 0.001)
+		assert.Equal(t, StatusSent, invoice.Status)
+		assert.False(t, invoice.Payments[0].RecordedAt.IsZero())
+
+		require.NoError(t, invoice.RecordPayment(ctx, Payment{Amount: 600, Date: date, Method: PaymentMethodUSDC}))
+		assert.InDelta(t, 0.0, invoice.AmountDue(), 0.001)
+		assert.Equal(t, StatusPaid, invoice.Status)
+		assert.Len(t, invoice.Payments, 2)
+	})
+
+	t.Run("RejectsNonPositiveAmount", func(t *testing.T) {
+		invoice := newPayableInvoice(100)
+
+		require.ErrorIs(t, invoice.RecordPayment(ctx, Payment{Amount: 0, Date: date}), ErrPaymentAmountNotPositive)
+		require.ErrorIs(t, invoice.RecordPayment(ctx, Payment{Amount: -5, Date: date}), ErrPaymentAmountNotPositive)
+		assert.Empty(t, invoice.Payments)
+	})
+
+	t.Run("RejectsAmountOverBalance", func(t *testing.T) {
+		invoice := newPayableInvoice(100)
+		require.NoError(t, invoice.RecordPayment(ctx, Payment{Amount: 60, Date: date}))
+
+		require.ErrorIs(t, invoice.RecordPayment(ctx, Payment{Amount: 40.01, Date: date}), ErrPaymentExceedsBalance)
+		assert.Len(t, invoice.Payments, 1)
+	})
+
+	t.Run("RequiresDate", func(t *testing.T) {
+		require.ErrorIs(t, newPayableInvoice(100).RecordPayment(ctx, Payment{Amount: 10}), ErrPaymentDateRequired)
+	})
+
+	t.Run("RejectsVoidedInvoice", func(t *testing.T) {
+		invoice := newPayableInvoice(100)
+		invoice.Status = StatusVoided
+
+		require.ErrorIs(t, invoice.RecordPayment(ctx, Payment{Amount: 10, Date: date}), ErrCannotPayVoidedInvoice)
+	})
+
+	t.Run("HandlesFloatingPointCents", func(t *testing.T) {
+		invoice := newPayableInvoice(0.3)
+		require.NoError(t, invoice.RecordPayment(ctx, Payment{Amount: 0.1, Date: date}))
+		require.NoError(t, invoice.RecordPayment(ctx, Payment{Amount: 0.2, Date: date}))
+
+		assert.Equal(t, StatusPaid, invoice.Status)
+	})
+}
+
+func TestInvoiceAmountDueAt(t *testing.T) {
+	ctx := context.Background()
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	invoice := newPayableInvoice(1000)
+	require.NoError(t, invoice.RecordPayment(ctx, Payment{Amount: 300, Date: march}))
+	require.NoError(t, invoice.RecordPayment(ctx, Payment{Amount: 200, Date: april}))
+
+	assert.InDelta(t, 1000.0, invoice.AmountDueAt(march.AddDate(0, 0, -1)), 0.001)
+	assert.InDelta(t, 700.0, invoice.AmountDueAt(march), 0.001, "payments on the as-of date count")
+	assert.InDelta(t, 500.0, invoice.AmountDueAt(april.AddDate(0, 0, 1)), 0.001)
+}
+
+func TestInvoiceUpdateStatusSettledBalance(t *testing.T) {
+	ctx := context.Background()
+	invoice := newPayableInvoice(100)
+	invoice.Payments = []Payment{{Amount: 100, Date: time.Now(), Method: PaymentMethodACH}}
+
+	require.NoError(t, invoice.UpdateStatus(ctx, StatusOverdue))
+	assert.Equal(t, StatusPaid, invoice.Status)
+}
+
+func TestParsePaymentMethod(t *testing.T) {
+	method, err := ParsePaymentMethod("wire")
+	require.NoError(t, err)
+	assert.Equal(t, PaymentMethodWire, method)
+
+	method, err = ParsePaymentMethod(" USDC ")
+	require.NoError(t, err)
+	assert.Equal(t, PaymentMethodUSDC, method)
+
+	_, err = ParsePaymentMethod("cash")
+	require.ErrorIs(t, err, ErrInvalidPaymentMethod)
+}
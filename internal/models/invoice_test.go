@@ -2,12 +2,15 @@ package models
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+
+	"github.com/mrz1836/go-invoice/internal/money"
 )
 
 type InvoiceTestSuite struct {
@@ -188,9 +191,9 @@ func (suite *InvoiceTestSuite) TestNewInvoice() {
 				assert.Equal(t, StatusDraft, invoice.Status)
 				assert.InDelta(t, tt.taxRate, invoice.TaxRate, 1e-9)
 				assert.Empty(t, invoice.WorkItems)
-				assert.InDelta(t, 0.0, invoice.Subtotal, 1e-9)
-				assert.InDelta(t, 0.0, invoice.TaxAmount, 1e-9)
-				assert.InDelta(t, 0.0, invoice.Total, 1e-9)
+				assert.InDelta(t, 0.0, invoice.Subtotal.Float64(), 1e-9)
+				assert.InDelta(t, 0.0, invoice.TaxAmount.Float64(), 1e-9)
+				assert.InDelta(t, 0.0, invoice.Total.Float64(), 1e-9)
 				assert.Equal(t, 1, invoice.Version)
 			}
 		})
@@ -245,9 +248,9 @@ func (suite *InvoiceTestSuite) TestInvoiceValidate() {
 				},
 				Status:    StatusDraft,
 				TaxRate:   0.1,
-				Subtotal:  100.0,
-				TaxAmount: 10.0,
-				Total:     110.0,
+				Subtotal:  money.FromFloat(100.0),
+				TaxAmount: money.FromFloat(10.0),
+				Total:     money.FromFloat(110.0),
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
 				Version:   1,
@@ -319,7 +322,7 @@ func (suite *InvoiceTestSuite) TestInvoiceValidate() {
 				},
 				Status:    StatusDraft,
 				TaxRate:   0.1,
-				Subtotal:  -100.0,
+				Subtotal:  money.FromFloat(-100.0),
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
 				Version:   1,
@@ -419,9 +422,9 @@ func (suite *InvoiceTestSuite) TestAddWorkItem() {
 	require.NoError(t, err)
 
 	assert.Len(t, invoice.WorkItems, 1)
-	assert.InDelta(t, 800.0, invoice.Subtotal, 1e-9)
-	assert.InDelta(t, 80.0, invoice.TaxAmount, 1e-9)
-	assert.InDelta(t, 880.0, invoice.Total, 1e-9)
+	assert.InDelta(t, 800.0, invoice.Subtotal.Float64(), 1e-9)
+	assert.InDelta(t, 80.0, invoice.TaxAmount.Float64(), 1e-9)
+	assert.InDelta(t, 880.0, invoice.Total.Float64(), 1e-9)
 	assert.Equal(t, 2, invoice.Version)
 
 	// Add another work item
@@ -439,9 +442,9 @@ func (suite *InvoiceTestSuite) TestAddWorkItem() {
 	require.NoError(t, err)
 
 	assert.Len(t, invoice.WorkItems, 2)
-	assert.InDelta(t, 1400.0, invoice.Subtotal, 1e-9)
-	assert.InDelta(t, 140.0, invoice.TaxAmount, 1e-9)
-	assert.InDelta(t, 1540.0, invoice.Total, 1e-9)
+	assert.InDelta(t, 1400.0, invoice.Subtotal.Float64(), 1e-9)
+	assert.InDelta(t, 140.0, invoice.TaxAmount.Float64(), 1e-9)
+	assert.InDelta(t, 1540.0, invoice.Total.Float64(), 1e-9)
 	assert.Equal(t, 3, invoice.Version)
 }
 
@@ -507,9 +510,9 @@ func (suite *InvoiceTestSuite) TestRemoveWorkItem() {
 				CreatedAt:   time.Now(),
 			},
 		},
-		Subtotal:  1400.0,
-		TaxAmount: 140.0,
-		Total:     1540.0,
+		Subtotal:  money.FromFloat(1400.0),
+		TaxAmount: money.FromFloat(140.0),
+		Total:     money.FromFloat(1540.0),
 		Version:   1,
 	}
 
@@ -519,9 +522,9 @@ func (suite *InvoiceTestSuite) TestRemoveWorkItem() {
 
 	assert.Len(t, invoice.WorkItems, 1)
 	assert.Equal(t, "ITEM-002", invoice.WorkItems[0].ID)
-	assert.InDelta(t, 600.0, invoice.Subtotal, 1e-9)
-	assert.InDelta(t, 60.0, invoice.TaxAmount, 1e-9)
-	assert.InDelta(t, 660.0, invoice.Total, 1e-9)
+	assert.InDelta(t, 600.0, invoice.Subtotal.Float64(), 1e-9)
+	assert.InDelta(t, 60.0, invoice.TaxAmount.Float64(), 1e-9)
+	assert.InDelta(t, 660.0, invoice.Total.Float64(), 1e-9)
 	assert.Equal(t, 2, invoice.Version)
 
 	// Try to remove non-existent item
@@ -606,9 +609,9 @@ func (suite *InvoiceTestSuite) TestRecalculateTotals() {
 			err := invoice.RecalculateTotals(suite.ctx)
 			require.NoError(t, err)
 
-			assert.InDelta(t, tt.expectedSubtotal, invoice.Subtotal, 1e-9)
-			assert.InDelta(t, tt.expectedTax, invoice.TaxAmount, 1e-9)
-			assert.InDelta(t, tt.expectedTotal, invoice.Total, 1e-9)
+			assert.InDelta(t, tt.expectedSubtotal, invoice.Subtotal.Float64(), 1e-9)
+			assert.InDelta(t, tt.expectedTax, invoice.TaxAmount.Float64(), 1e-9)
+			assert.InDelta(t, tt.expectedTotal, invoice.Total.Float64(), 1e-9)
 		})
 	}
 }
@@ -788,6 +791,20 @@ func (suite *InvoiceTestSuite) TestGetDaysUntilDue() {
 	}
 }
 
+func (suite *InvoiceTestSuite) TestAgeAndDueAt() {
+	t := suite.T()
+
+	invoice := &Invoice{
+		Date:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		DueDate: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+	asOf := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 60, invoice.AgeInDaysAt(asOf))
+	assert.Equal(t, -30, invoice.DaysUntilDueAt(asOf))
+	assert.Equal(t, 30, invoice.DaysUntilDueAt(invoice.Date))
+}
+
 func (suite *InvoiceTestSuite) TestSetCryptoFee() {
 	t := suite.T()
 
@@ -928,10 +945,10 @@ func (suite *InvoiceTestSuite) TestSetCryptoFee() {
 			require.NoError(t, err)
 
 			// Verify results
-			assert.InDelta(t, tt.expectedCryptoFee, invoice.CryptoFee, 1e-9, "crypto fee mismatch")
-			assert.InDelta(t, tt.expectedSubtotal, invoice.Subtotal, 1e-9, "subtotal mismatch")
-			assert.InDelta(t, tt.expectedTaxAmount, invoice.TaxAmount, 1e-9, "tax amount mismatch")
-			assert.InDelta(t, tt.expectedTotal, invoice.Total, 1e-9, "total mismatch")
+			assert.InDelta(t, tt.expectedCryptoFee, invoice.CryptoFee.Float64(), 1e-9, "crypto fee mismatch")
+			assert.InDelta(t, tt.expectedSubtotal, invoice.Subtotal.Float64(), 1e-9, "subtotal mismatch")
+			assert.InDelta(t, tt.expectedTaxAmount, invoice.TaxAmount.Float64(), 1e-9, "tax amount mismatch")
+			assert.InDelta(t, tt.expectedTotal, invoice.Total.Float64(), 1e-9, "total mismatch")
 		})
 	}
 }
@@ -1018,21 +1035,295 @@ func (suite *InvoiceTestSuite) TestRecalculateTotalsWithCryptoFee() {
 		suite.Run(tt.name, func() {
 			invoice := &Invoice{
 				WorkItems: tt.workItems,
-				CryptoFee: tt.cryptoFee,
+				CryptoFee: money.FromFloat(tt.cryptoFee),
 				TaxRate:   tt.taxRate,
 			}
 
 			err := invoice.RecalculateTotals(suite.ctx)
 			require.NoError(t, err)
 
-			assert.InDelta(t, tt.expectedSubtotal, invoice.Subtotal, 1e-9, "subtotal mismatch")
-			assert.InDelta(t, tt.expectedTax, invoice.TaxAmount, 1e-9, "tax amount mismatch")
-			assert.InDelta(t, tt.expectedTotal, invoice.Total, 1e-9, "total mismatch")
+			assert.InDelta(t, tt.expectedSubtotal, invoice.Subtotal.Float64(), 1e-9, "subtotal mismatch")
+			assert.InDelta(t, tt.expectedTax, invoice.TaxAmount.Float64(), 1e-9, "tax amount mismatch")
+			assert.InDelta(t, tt.expectedTotal, invoice.Total.Float64(), 1e-9, "total mismatch")
+		})
+	}
+}
+
+func (suite *InvoiceTestSuite) TestRecalculateTotalsTaxExempt() {
+	t := suite.T()
+
+	tests := []struct {
+		name            string
+		taxExempt       bool
+		discountPercent float64
+		cryptoFee       float64
+		taxRate         float64
+		expectedTax     float64
+		expectedTotal   float64
+	}{
+		{
+			name:          "TaxedWithCryptoFee",
+			cryptoFee:     25.00,
+			taxRate:       0.10,
+			expectedTax:   102.50,  // Tax on (1000 + 25)
+			expectedTotal: 1127.50, // 1000 + 25 + 102.50
+		},
+		{
+			name:          "ExemptWithCryptoFee",
+			taxExempt:     true,
+			cryptoFee:     25.00,
+			taxRate:       0.10,
+			expectedTax:   0.00,    // The crypto fee is not taxed either
+			expectedTotal: 1025.00, // 1000 + 25
+		},
+		{
+			name:            "ExemptWithDiscountAndCryptoFee",
+			taxExempt:       true,
+			discountPercent: 10,
+			cryptoFee:       25.00,
+			taxRate:         0.21,
+			expectedTax:     0.00,
+			expectedTotal:   925.00, // 1000 - 100 + 25
+		},
+		{
+			name:          "ExemptWithoutRate",
+			taxExempt:     true,
+			expectedTax:   0.00,
+			expectedTotal: 1000.00,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			invoice := &Invoice{
+				Client:          Client{TaxExempt: tt.taxExempt},
+				WorkItems:       []WorkItem{{Total: 1000.0}},
+				DiscountPercent: tt.discountPercent,
+				CryptoFee:       money.FromFloat(tt.cryptoFee),
+				TaxRate:         tt.taxRate,
+			}
+
+			require.NoError(t, invoice.RecalculateTotals(suite.ctx))
+
+			assert.InDelta(t, tt.expectedTax, invoice.TaxAmount.Float64(), 1e-9, "tax amount mismatch")
+			assert.InDelta(t, tt.expectedTotal, invoice.Total.Float64(), 1e-9, "total mismatch")
+			assert.InDelta(t, tt.taxRate, invoice.TaxRate, 1e-9, "the invoice keeps its configured rate")
+			if tt.taxExempt {
+				assert.Zero(t, invoice.EffectiveTaxRate())
+			} else {
+				assert.InDelta(t, tt.taxRate, invoice.EffectiveTaxRate(), 1e-9)
+			}
 		})
 	}
 }
 
 // TestInvoiceAddLineItem tests adding line items to invoices
+func (suite *InvoiceTestSuite) TestRecalculateTotalsWithDiscount() {
+	t := suite.T()
+
+	tests := []struct {
+		name                  string
+		workItemTotal         float64
+		discountPercent       float64
+		discountAmount        float64
+		cryptoFeeAmount       float64
+		taxRate               float64
+		expectedDiscountTotal float64
+		expectedTaxAmount     float64
+		expectedTotal         float64
+	}{
+		{
+			name:                  "PercentDiscount",
+			workItemTotal:         1000.00,
+			discountPercent:       10,
+			taxRate:               0.10,
+			expectedDiscountTotal: 100.00,
+			expectedTaxAmount:     90.00,  // Tax on (1000 - 100)
+			expectedTotal:         990.00, // 1000 - 100 + 90
+		},
+		{
+			name:                  "FixedDiscount",
+			workItemTotal:         1000.00,
+			discountAmount:        250.00,
+			taxRate:               0.10,
+			expectedDiscountTotal: 250.00,
+			expectedTaxAmount:     75.00,  // Tax on (1000 - 250)
+			expectedTotal:         825.00, // 1000 - 250 + 75
+		},
+		{
+			name:                  "PercentDiscountWithCryptoFee",
+			workItemTotal:         1000.00,
+			discountPercent:       10,
+			cryptoFeeAmount:       25.00,
+			taxRate:               0.10,
+			expectedDiscountTotal: 100.00,  // Discount applies to the subtotal, not the crypto fee
+			expectedTaxAmount:     92.50,   // Tax on (1000 - 100 + 25)
+			expectedTotal:         1017.50, // 1000 - 100 + 25 + 92.50
+		},
+		{
+			name:                  "FixedDiscountWithCryptoFee",
+			workItemTotal:         500.00,
+			discountAmount:        100.00,
+			cryptoFeeAmount:       25.00,
+			taxRate:               0.0825,
+			expectedDiscountTotal: 100.00,
+			expectedTaxAmount:     35.06,  // Tax on (500 - 100 + 25), rounded half-up
+			expectedTotal:         460.06, // 500 - 100 + 25 + 35.06
+		},
+		{
+			name:                  "PercentDiscountRoundsHalfUp",
+			workItemTotal:         33.33,
+			discountPercent:       15,
+			expectedDiscountTotal: 5.00, // 4.9995 rounds up
+			expectedTaxAmount:     0.00,
+			expectedTotal:         28.33,
+		},
+		{
+			name:                  "FullPercentDiscountKeepsCryptoFee",
+			workItemTotal:         1000.00,
+			discountPercent:       100,
+			cryptoFeeAmount:       25.00,
+			taxRate:               0.10,
+			expectedDiscountTotal: 1000.00,
+			expectedTaxAmount:     2.50,  // Tax on the crypto fee only
+			expectedTotal:         27.50, // 0 + 25 + 2.50
+		},
+		{
+			name:                  "FixedDiscountCappedAtSubtotal",
+			workItemTotal:         100.00,
+			discountAmount:        150.00,
+			taxRate:               0.10,
+			expectedDiscountTotal: 100.00, // Never more than the subtotal
+			expectedTaxAmount:     0.00,
+			expectedTotal:         0.00,
+		},
+		{
+			name:                  "NoDiscount",
+			workItemTotal:         1000.00,
+			cryptoFeeAmount:       25.00,
+			taxRate:               0.10,
+			expectedDiscountTotal: 0.00,
+			expectedTaxAmount:     102.50,
+			expectedTotal:         1127.50,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			invoice := &Invoice{
+				ID:              testInvoiceID001,
+				Number:          testInvoiceNum,
+				Date:            time.Now(),
+				DueDate:         time.Now().AddDate(0, 0, 30),
+				Status:          StatusDraft,
+				TaxRate:         tt.taxRate,
+				DiscountPercent: tt.discountPercent,
+				DiscountAmount:  money.FromFloat(tt.discountAmount),
+				WorkItems: []WorkItem{
+					{
+						ID:          testItemID001,
+						Date:        time.Now(),
+						Hours:       1.0,
+						Rate:        tt.workItemTotal,
+						Description: "Test work",
+						Total:       tt.workItemTotal,
+						CreatedAt:   time.Now(),
+					},
+				},
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+				Version:   1,
+			}
+
+			err := invoice.RecalculateTotals(suite.ctx)
+			require.NoError(t, err)
+
+			err = invoice.SetCryptoFee(suite.ctx, tt.cryptoFeeAmount > 0, tt.cryptoFeeAmount > 0, tt.cryptoFeeAmount)
+			require.NoError(t, err)
+
+			assert.InDelta(t, tt.workItemTotal, invoice.Subtotal.Float64(), 1e-9, "subtotal mismatch")
+			assert.InDelta(t, tt.expectedDiscountTotal, invoice.DiscountTotal.Float64(), 1e-9, "discount total mismatch")
+			assert.InDelta(t, tt.cryptoFeeAmount, invoice.CryptoFee.Float64(), 1e-9, "crypto fee mismatch")
+			assert.InDelta(t, tt.expectedTaxAmount, invoice.TaxAmount.Float64(), 1e-9, "tax amount mismatch")
+			assert.InDelta(t, tt.expectedTotal, invoice.Total.Float64(), 1e-9, "total mismatch")
+			assert.GreaterOrEqual(t, invoice.Total, money.Amount(0), "total must never be negative")
+		})
+	}
+}
+
+func (suite *InvoiceTestSuite) TestInvoiceValidateDiscount() {
+	t := suite.T()
+
+	tests := []struct {
+		name            string
+		discountPercent float64
+		discountAmount  float64
+		expectError     bool
+	}{
+		{name: "NoDiscount"},
+		{name: "Percent", discountPercent: 10},
+		{name: "FullPercent", discountPercent: 100},
+		{name: "Fixed", discountAmount: 50},
+		{name: "NegativePercent", discountPercent: -5, expectError: true},
+		{name: "PercentOver100", discountPercent: 101, expectError: true},
+		{name: "NegativeAmount", discountAmount: -1, expectError: true},
+		{name: "BothSet", discountPercent: 10, discountAmount: 50, expectError: true},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			invoice := createTestInvoice(t, suite.ctx)
+			invoice.Client = Client{ID: testClientID001, Name: testClientName, Email: testClientEmail, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+			invoice.DiscountPercent = tt.discountPercent
+			invoice.DiscountAmount = money.FromFloat(tt.discountAmount)
+
+			err := invoice.Validate(suite.ctx)
+			if tt.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func (suite *InvoiceTestSuite) TestInvoiceValidateOptionalText() {
+	t := suite.T()
+
+	tests := []struct {
+		name            string
+		notes           string
+		poNumber        string
+		clientReference string
+		expectError     bool
+	}{
+		{name: "NoNotes"},
+		{name: "Notes", notes: "PO #4521"},
+		{name: "MaxLength", notes: strings.Repeat("a", MaxInvoiceNotesLength)},
+		{name: "TooLong", notes: strings.Repeat("a", MaxInvoiceNotesLength+1), expectError: true},
+		{name: "PONumberTooLong", poNumber: strings.Repeat("1", MaxPONumberLength+1), expectError: true},
+		{name: "ClientReferenceTooLong", clientReference: strings.Repeat("r", MaxClientReferenceLength+1), expectError: true},
+		{name: "References", poNumber: "4500012345", clientReference: "PRJ-ALPHA"},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			invoice := createTestInvoice(t, suite.ctx)
+			invoice.Client = Client{ID: testClientID001, Name: testClientName, Email: testClientEmail, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+			invoice.Notes = tt.notes
+			invoice.PONumber = tt.poNumber
+			invoice.ClientReference = tt.clientReference
+
+			err := invoice.Validate(suite.ctx)
+			if tt.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func (suite *InvoiceTestSuite) TestInvoiceAddLineItem() {
 	t := suite.T()
 	ctx := suite.ctx
@@ -1049,14 +1340,14 @@ func (suite *InvoiceTestSuite) TestInvoiceAddLineItem() {
 			Description: testDevWork,
 			Hours:       &hours,
 			Rate:        &rate,
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 
 		err := invoice.AddLineItem(ctx, lineItem)
 		require.NoError(t, err)
 		assert.Len(t, invoice.LineItems, 1)
-		assert.InDelta(t, 1000.0, invoice.Subtotal, 1e-9)
+		assert.InDelta(t, 1000.0, invoice.Subtotal.Float64(), 1e-9)
 	})
 
 	t.Run("AddFixedLineItem", func(t *testing.T) {
@@ -1069,14 +1360,14 @@ func (suite *InvoiceTestSuite) TestInvoiceAddLineItem() {
 			Date:        time.Now(),
 			Description: "Monthly Retainer",
 			Amount:      &amount,
-			Total:       2000.0,
+			Total:       money.FromFloat(2000.0),
 			CreatedAt:   time.Now(),
 		}
 
 		err := invoice.AddLineItem(ctx, lineItem)
 		require.NoError(t, err)
 		assert.Len(t, invoice.LineItems, 1)
-		assert.InDelta(t, 2000.0, invoice.Subtotal, 1e-9)
+		assert.InDelta(t, 2000.0, invoice.Subtotal.Float64(), 1e-9)
 	})
 
 	t.Run("AddQuantityLineItem", func(t *testing.T) {
@@ -1091,14 +1382,14 @@ func (suite *InvoiceTestSuite) TestInvoiceAddLineItem() {
 			Description: "SSL Certificates",
 			Quantity:    &quantity,
 			UnitPrice:   &unitPrice,
-			Total:       150.0,
+			Total:       money.FromFloat(150.0),
 			CreatedAt:   time.Now(),
 		}
 
 		err := invoice.AddLineItem(ctx, lineItem)
 		require.NoError(t, err)
 		assert.Len(t, invoice.LineItems, 1)
-		assert.InDelta(t, 150.0, invoice.Subtotal, 1e-9)
+		assert.InDelta(t, 150.0, invoice.Subtotal.Float64(), 1e-9)
 	})
 
 	t.Run("AddMultipleLineItems", func(t *testing.T) {
@@ -1114,7 +1405,7 @@ func (suite *InvoiceTestSuite) TestInvoiceAddLineItem() {
 			Description: "Development",
 			Hours:       &hours,
 			Rate:        &rate,
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 		err := invoice.AddLineItem(ctx, lineItem1)
@@ -1128,14 +1419,14 @@ func (suite *InvoiceTestSuite) TestInvoiceAddLineItem() {
 			Date:        time.Now(),
 			Description: "Setup Fee",
 			Amount:      &amount,
-			Total:       500.0,
+			Total:       money.FromFloat(500.0),
 			CreatedAt:   time.Now(),
 		}
 		err = invoice.AddLineItem(ctx, lineItem2)
 		require.NoError(t, err)
 
 		assert.Len(t, invoice.LineItems, 2)
-		assert.InDelta(t, 1500.0, invoice.Subtotal, 1e-9)
+		assert.InDelta(t, 1500.0, invoice.Subtotal.Float64(), 1e-9)
 	})
 
 	t.Run("AddInvalidLineItem", func(t *testing.T) {
@@ -1147,13 +1438,36 @@ func (suite *InvoiceTestSuite) TestInvoiceAddLineItem() {
 			Type:        LineItemTypeHourly,
 			Date:        time.Now(),
 			Description: "Invalid",
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 
 		err := invoice.AddLineItem(ctx, lineItem)
 		require.Error(t, err)
 	})
+
+	t.Run("AddLineItemWithMismatchedTotal", func(t *testing.T) {
+		invoice := createTestInvoice(t, ctx)
+
+		hours := 8.0
+		rate := 125.0
+		lineItem := LineItem{
+			ID:          testLineItemID1,
+			Type:        LineItemTypeHourly,
+			Date:        time.Now(),
+			Description: testDevWork,
+			Hours:       &hours,
+			Rate:        &rate,
+			Total:       money.FromFloat(999.99),
+			CreatedAt:   time.Now(),
+		}
+
+		err := invoice.AddLineItem(ctx, lineItem)
+		require.ErrorIs(t, err, ErrLineItemValidationFailed)
+		assert.ErrorContains(t, err, "does not match 1000.00")
+		assert.Empty(t, invoice.LineItems)
+		assert.Equal(t, 1, invoice.Version)
+	})
 }
 
 // TestInvoiceRemoveLineItem tests removing line items from invoices
@@ -1174,7 +1488,7 @@ func (suite *InvoiceTestSuite) TestInvoiceRemoveLineItem() {
 			Description: "Development",
 			Hours:       &hours,
 			Rate:        &rate,
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 		err := invoice.AddLineItem(ctx, lineItem)
@@ -1184,7 +1498,7 @@ func (suite *InvoiceTestSuite) TestInvoiceRemoveLineItem() {
 		err = invoice.RemoveLineItem(ctx, testLineItemID1)
 		require.NoError(t, err)
 		assert.Empty(t, invoice.LineItems)
-		assert.InDelta(t, 0.0, invoice.Subtotal, 1e-9)
+		assert.InDelta(t, 0.0, invoice.Subtotal.Float64(), 1e-9)
 	})
 
 	t.Run("RemoveNonExistentLineItem", func(t *testing.T) {
@@ -1223,7 +1537,12 @@ func (suite *InvoiceTestSuite) TestInvoiceMigrateWorkItemsToLineItems() {
 		assert.Len(t, invoice.LineItems, 1)
 		assert.Equal(t, workItem.ID, invoice.LineItems[0].ID)
 		assert.Equal(t, LineItemTypeHourly, invoice.LineItems[0].Type)
-		assert.InDelta(t, workItem.Total, invoice.LineItems[0].Total, 1e-9)
+		assert.InDelta(t, workItem.Total, invoice.LineItems[0].Total.Float64(), 1e-9)
+		assert.Empty(t, invoice.WorkItems)
+
+		// Totals only count the converted items once
+		require.NoError(t, invoice.RecalculateTotals(ctx))
+		assert.InDelta(t, workItem.Total, invoice.Subtotal.Float64(), 1e-9)
 	})
 
 	t.Run("NoMigrationIfLineItemsExist", func(t *testing.T) {
@@ -1251,7 +1570,7 @@ func (suite *InvoiceTestSuite) TestInvoiceMigrateWorkItemsToLineItems() {
 			Description: "Other work",
 			Hours:       &hours,
 			Rate:        &rate,
-			Total:       600.0,
+			Total:       money.FromFloat(600.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem)
@@ -1284,7 +1603,7 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 			Description: "Development",
 			Hours:       &hours,
 			Rate:        &rate,
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem1)
@@ -1297,7 +1616,7 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 			Date:        time.Now(),
 			Description: "Setup",
 			Amount:      &amount,
-			Total:       500.0,
+			Total:       money.FromFloat(500.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem2)
@@ -1305,8 +1624,8 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 		err := invoice.RecalculateTotals(ctx)
 		require.NoError(t, err)
 
-		assert.InDelta(t, 1500.0, invoice.Subtotal, 1e-9)
-		assert.InDelta(t, 1500.0, invoice.Total, 1e-9)
+		assert.InDelta(t, 1500.0, invoice.Subtotal.Float64(), 1e-9)
+		assert.InDelta(t, 1500.0, invoice.Total.Float64(), 1e-9)
 	})
 
 	t.Run("CalculateWithBothWorkItemsAndLineItems", func(t *testing.T) {
@@ -1334,7 +1653,7 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 			Description: "New work",
 			Hours:       &hours,
 			Rate:        &rate,
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem)
@@ -1343,8 +1662,8 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 		require.NoError(t, err)
 
 		// Should include both work items and line items
-		assert.InDelta(t, 1400.0, invoice.Subtotal, 1e-9)
-		assert.InDelta(t, 1400.0, invoice.Total, 1e-9)
+		assert.InDelta(t, 1400.0, invoice.Subtotal.Float64(), 1e-9)
+		assert.InDelta(t, 1400.0, invoice.Total.Float64(), 1e-9)
 	})
 
 	t.Run("CalculateWithCryptoFee", func(t *testing.T) {
@@ -1359,13 +1678,13 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 			Date:        time.Now(),
 			Description: "Repository Maintenance",
 			Amount:      &amount,
-			Total:       5000.0,
+			Total:       money.FromFloat(5000.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem)
 
 		// Set crypto fee
-		invoice.CryptoFee = 25.0
+		invoice.CryptoFee = money.FromFloat(25.0)
 
 		err := invoice.RecalculateTotals(ctx)
 		require.NoError(t, err)
@@ -1373,9 +1692,9 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 		// Should be: subtotal $5000 + crypto fee $25 = $5025 (no tax in this test, but fee is there)
 		// Tax on (subtotal + crypto fee) = (5000 + 25) * 0.005 = 25.125
 		// Total = subtotal + crypto fee + tax = 5000 + 25 + 25.13 = 5050.13
-		assert.InDelta(t, 5000.0, invoice.Subtotal, 0.01, "Subtotal should be $5000")
-		assert.InDelta(t, 25.13, invoice.TaxAmount, 0.01, "Tax on (5000+25) at 0.5% should be $25.13")
-		assert.InDelta(t, 5050.13, invoice.Total, 0.01, "Total should be $5050.13")
+		assert.InDelta(t, 5000.0, invoice.Subtotal.Float64(), 0.01, "Subtotal should be $5000")
+		assert.InDelta(t, 25.13, invoice.TaxAmount.Float64(), 0.01, "Tax on (5000+25) at 0.5% should be $25.13")
+		assert.InDelta(t, 5050.13, invoice.Total.Float64(), 0.01, "Total should be $5050.13")
 	})
 
 	t.Run("CalculateWithMultipleLineItemTypes", func(t *testing.T) {
@@ -1392,7 +1711,7 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 			Description: "Development",
 			Hours:       &hours,
 			Rate:        &rate,
-			Total:       1500.0,
+			Total:       money.FromFloat(1500.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, hourlyItem)
@@ -1405,7 +1724,7 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 			Date:        time.Now(),
 			Description: "Setup Fee",
 			Amount:      &fixedAmount,
-			Total:       2000.0,
+			Total:       money.FromFloat(2000.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, fixedItem)
@@ -1420,7 +1739,7 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 			Description: "Licenses",
 			Quantity:    &quantity,
 			UnitPrice:   &unitPrice,
-			Total:       500.0,
+			Total:       money.FromFloat(500.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, quantityItem)
@@ -1429,8 +1748,8 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 		require.NoError(t, err)
 
 		// Total should be 1500 + 2000 + 500 = 4000
-		assert.InDelta(t, 4000.0, invoice.Subtotal, 1e-9, "Subtotal should include all line item types")
-		assert.InDelta(t, 4000.0, invoice.Total, 1e-9, "Total should equal subtotal with no tax")
+		assert.InDelta(t, 4000.0, invoice.Subtotal.Float64(), 1e-9, "Subtotal should include all line item types")
+		assert.InDelta(t, 4000.0, invoice.Total.Float64(), 1e-9, "Total should equal subtotal with no tax")
 	})
 
 	t.Run("CalculateWithEmptyInvoice", func(t *testing.T) {
@@ -1439,8 +1758,8 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 		err := invoice.RecalculateTotals(ctx)
 		require.NoError(t, err)
 
-		assert.InDelta(t, 0.0, invoice.Subtotal, 1e-9)
-		assert.InDelta(t, 0.0, invoice.Total, 1e-9)
+		assert.InDelta(t, 0.0, invoice.Subtotal.Float64(), 1e-9)
+		assert.InDelta(t, 0.0, invoice.Total.Float64(), 1e-9)
 	})
 
 	t.Run("CalculateWithOnlyWorkItems", func(t *testing.T) {
@@ -1473,8 +1792,8 @@ func (suite *InvoiceTestSuite) TestInvoiceRecalculateTotals() {
 		require.NoError(t, err)
 
 		// Should still work for backward compatibility
-		assert.InDelta(t, 1400.0, invoice.Subtotal, 1e-9, "Should calculate WorkItems correctly")
-		assert.InDelta(t, 1400.0, invoice.Total, 1e-9)
+		assert.InDelta(t, 1400.0, invoice.Subtotal.Float64(), 1e-9, "Should calculate WorkItems correctly")
+		assert.InDelta(t, 1400.0, invoice.Total.Float64(), 1e-9)
 	})
 }
 
@@ -1495,7 +1814,7 @@ func (suite *InvoiceTestSuite) TestInvoiceSetCryptoFee() {
 			Date:        time.Now(),
 			Description: "Repository Maintenance",
 			Amount:      &amount,
-			Total:       5000.0,
+			Total:       money.FromFloat(5000.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem)
@@ -1503,17 +1822,17 @@ func (suite *InvoiceTestSuite) TestInvoiceSetCryptoFee() {
 		// Initially calculate without crypto fee
 		err := invoice.RecalculateTotals(ctx)
 		require.NoError(t, err)
-		assert.InDelta(t, 5000.0, invoice.Subtotal, 0.01)
-		assert.InDelta(t, 5000.0, invoice.Total, 0.01)
+		assert.InDelta(t, 5000.0, invoice.Subtotal.Float64(), 0.01)
+		assert.InDelta(t, 5000.0, invoice.Total.Float64(), 0.01)
 
 		// Now set crypto fee - THIS WAS THE BUG
 		err = invoice.SetCryptoFee(ctx, true, true, 25.0)
 		require.NoError(t, err)
 
 		// After setting crypto fee, subtotal should STILL be $5000 (not $0!)
-		assert.InDelta(t, 5000.0, invoice.Subtotal, 0.01, "Subtotal should remain $5000 after SetCryptoFee")
-		assert.InDelta(t, 25.0, invoice.CryptoFee, 0.01, "Crypto fee should be $25")
-		assert.InDelta(t, 5025.0, invoice.Total, 0.01, "Total should be $5025 (5000+25)")
+		assert.InDelta(t, 5000.0, invoice.Subtotal.Float64(), 0.01, "Subtotal should remain $5000 after SetCryptoFee")
+		assert.InDelta(t, 25.0, invoice.CryptoFee.Float64(), 0.01, "Crypto fee should be $25")
+		assert.InDelta(t, 5025.0, invoice.Total.Float64(), 0.01, "Total should be $5025 (5000+25)")
 	})
 
 	t.Run("SetCryptoFeeWithWorkItems", func(t *testing.T) {
@@ -1539,9 +1858,9 @@ func (suite *InvoiceTestSuite) TestInvoiceSetCryptoFee() {
 		err = invoice.SetCryptoFee(ctx, true, true, 10.0)
 		require.NoError(t, err)
 
-		assert.InDelta(t, 1000.0, invoice.Subtotal, 0.01, "Subtotal should remain $1000")
-		assert.InDelta(t, 10.0, invoice.CryptoFee, 0.01)
-		assert.InDelta(t, 1010.0, invoice.Total, 0.01, "Total should be $1010")
+		assert.InDelta(t, 1000.0, invoice.Subtotal.Float64(), 0.01, "Subtotal should remain $1000")
+		assert.InDelta(t, 10.0, invoice.CryptoFee.Float64(), 0.01)
+		assert.InDelta(t, 1010.0, invoice.Total.Float64(), 0.01, "Total should be $1010")
 	})
 
 	t.Run("SetCryptoFeeWithBothItemTypes", func(t *testing.T) {
@@ -1568,23 +1887,23 @@ func (suite *InvoiceTestSuite) TestInvoiceSetCryptoFee() {
 			Date:        time.Now(),
 			Description: "New work",
 			Amount:      &amount,
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem)
 
 		err := invoice.RecalculateTotals(ctx)
 		require.NoError(t, err)
-		assert.InDelta(t, 1500.0, invoice.Subtotal, 0.01)
+		assert.InDelta(t, 1500.0, invoice.Subtotal.Float64(), 0.01)
 
 		// Set crypto fee
 		err = invoice.SetCryptoFee(ctx, true, true, 15.0)
 		require.NoError(t, err)
 
 		// Must include BOTH WorkItems and LineItems
-		assert.InDelta(t, 1500.0, invoice.Subtotal, 0.01, "Subtotal should include both item types")
-		assert.InDelta(t, 15.0, invoice.CryptoFee, 0.01)
-		assert.InDelta(t, 1515.0, invoice.Total, 0.01, "Total should be $1515")
+		assert.InDelta(t, 1500.0, invoice.Subtotal.Float64(), 0.01, "Subtotal should include both item types")
+		assert.InDelta(t, 15.0, invoice.CryptoFee.Float64(), 0.01)
+		assert.InDelta(t, 1515.0, invoice.Total.Float64(), 0.01, "Total should be $1515")
 	})
 
 	t.Run("DisableCryptoFee", func(t *testing.T) {
@@ -1598,7 +1917,7 @@ func (suite *InvoiceTestSuite) TestInvoiceSetCryptoFee() {
 			Date:        time.Now(),
 			Description: "Work",
 			Amount:      &amount,
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem)
@@ -1606,15 +1925,15 @@ func (suite *InvoiceTestSuite) TestInvoiceSetCryptoFee() {
 		// Set crypto fee first
 		err := invoice.SetCryptoFee(ctx, true, true, 10.0)
 		require.NoError(t, err)
-		assert.InDelta(t, 10.0, invoice.CryptoFee, 0.01)
+		assert.InDelta(t, 10.0, invoice.CryptoFee.Float64(), 0.01)
 
 		// Now disable it
 		err = invoice.SetCryptoFee(ctx, false, false, 0.0)
 		require.NoError(t, err)
 
-		assert.InDelta(t, 1000.0, invoice.Subtotal, 0.01)
-		assert.InDelta(t, 0.0, invoice.CryptoFee, 0.01, "Crypto fee should be zero when disabled")
-		assert.InDelta(t, 1000.0, invoice.Total, 0.01)
+		assert.InDelta(t, 1000.0, invoice.Subtotal.Float64(), 0.01)
+		assert.InDelta(t, 0.0, invoice.CryptoFee.Float64(), 0.01, "Crypto fee should be zero when disabled")
+		assert.InDelta(t, 1000.0, invoice.Total.Float64(), 0.01)
 	})
 
 	t.Run("SetCryptoFeeWithTax", func(t *testing.T) {
@@ -1628,7 +1947,7 @@ func (suite *InvoiceTestSuite) TestInvoiceSetCryptoFee() {
 			Date:        time.Now(),
 			Description: "Repository Maintenance",
 			Amount:      &amount,
-			Total:       5000.0,
+			Total:       money.FromFloat(5000.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem)
@@ -1640,10 +1959,10 @@ func (suite *InvoiceTestSuite) TestInvoiceSetCryptoFee() {
 		// Tax is calculated on (subtotal + crypto fee)
 		// Tax = (5000 + 25) * 0.005 = 25.125 rounded to 25.13
 		// Total = 5000 + 25 + 25.13 = 5050.13
-		assert.InDelta(t, 5000.0, invoice.Subtotal, 0.01)
-		assert.InDelta(t, 25.0, invoice.CryptoFee, 0.01)
-		assert.InDelta(t, 25.13, invoice.TaxAmount, 0.01, "Tax should be calculated on subtotal+fee")
-		assert.InDelta(t, 5050.13, invoice.Total, 0.01)
+		assert.InDelta(t, 5000.0, invoice.Subtotal.Float64(), 0.01)
+		assert.InDelta(t, 25.0, invoice.CryptoFee.Float64(), 0.01)
+		assert.InDelta(t, 25.13, invoice.TaxAmount.Float64(), 0.01, "Tax should be calculated on subtotal+fee")
+		assert.InDelta(t, 5050.13, invoice.Total.Float64(), 0.01)
 	})
 }
 
@@ -1680,7 +1999,7 @@ func (suite *InvoiceTestSuite) TestInvoiceHelperMethods() {
 			Description: "Development",
 			Hours:       &hours,
 			Rate:        &rate,
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem)
@@ -1714,7 +2033,7 @@ func (suite *InvoiceTestSuite) TestInvoiceHelperMethods() {
 			Description: "New work",
 			Hours:       &hours,
 			Rate:        &rate,
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem)
@@ -1748,7 +2067,7 @@ func (suite *InvoiceTestSuite) TestInvoiceHelperMethods() {
 			Description: "New work",
 			Hours:       &hours,
 			Rate:        &rate,
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem1)
@@ -1761,7 +2080,7 @@ func (suite *InvoiceTestSuite) TestInvoiceHelperMethods() {
 			Date:        time.Now(),
 			Description: "Setup",
 			Amount:      &amount,
-			Total:       500.0,
+			Total:       money.FromFloat(500.0),
 			CreatedAt:   time.Now(),
 		}
 		invoice.LineItems = append(invoice.LineItems, lineItem2)
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// propertyLineItemCount is the number of random line items summed per property check
+const propertyLineItemCount = 5000
+
+// randomHourlyItem returns an hourly line item with hours and rate in hundredths, together with
+// its exact total in cents computed with integer arithmetic
+func randomHourlyItem(rng *rand.Rand) (LineItem, int64) {
+	hundredthsOfHours := rng.Int63n(2400) + 1 // 0.01 - 24.00 hours
+	rateCents := rng.Int63n(50000) + 1        // 0.01 - 500.00 per hour
+
+	hours := float64(hundredthsOfHours) / 100
+	rate := float64(rateCents) / 100
+	exactCents := (hundredthsOfHours*rateCents + 50) / 100 // round half-up
+
+	return LineItem{
+		Type:  LineItemTypeHourly,
+		Hours: &hours,
+		Rate:  &rate,
+		Total: money.Product(hours, rate),
+	}, exactCents
+}
+
+// TestRecalculateTotalsNoDrift sums thousands of random line items and checks the invoice
+// totals match exact integer arithmetic to the cent, where the float implementation drifts.
+func TestRecalculateTotalsNoDrift(t *testing.T) {
+	ctx := context.Background()
+
+	property := func(seed int64, taxBasisPoints uint16) bool {
+		rng := rand.New(rand.NewSource(seed)) //nolint:gosec // deterministic test data
+		taxBP := int64(taxBasisPoints % 3000) // 0% - 29.99%
+
+		invoice := &Invoice{TaxRate: float64(taxBP) / 10000, Date: time.Now()}
+		var exactSubtotal int64
+		floatSubtotal := 0.0
+		for range propertyLineItemCount {
+			item, exactCents := randomHourlyItem(rng)
+			invoice.LineItems = append(invoice.LineItems, item)
+			exactSubtotal += exactCents
+			floatSubtotal += math.Round(*item.Hours**item.Rate*100) / 100
+		}
+
+		if err := invoice.RecalculateTotals(ctx); err != nil {
+			t.Logf("recalculate failed: %v", err)
+			return false
+		}
+
+		exactTax := (exactSubtotal*taxBP + 5000) / 10000 // round half-up
+		if invoice.Subtotal.Cents() != exactSubtotal ||
+			invoice.TaxAmount.Cents() != exactTax ||
+			invoice.Total.Cents() != exactSubtotal+exactTax {
+			t.Logf("seed %d: got subtotal %d tax %d total %d, want %d %d %d",
+				seed, invoice.Subtotal.Cents(), invoice.TaxAmount.Cents(), invoice.Total.Cents(),
+				exactSubtotal, exactTax, exactSubtotal+exactTax)
+			return false
+		}
+
+		// The float sum may be off by fractions of a cent (or whole cents where items sit on a
+		// half cent) but never by more than a cent per item
+		return math.Abs(floatSubtotal-float64(exactSubtotal)/100) <= float64(propertyLineItemCount)/100
+	}
+
+	require.NoError(t, quick.Check(property, &quick.Config{MaxCount: 50}))
+}
+
+// TestFloatSummationDrifts documents why totals are kept in cents: adding float amounts
+// accumulates representation error that integer cents do not
+func TestFloatSummationDrifts(t *testing.T) {
+	floatTotal := 0.0
+	var total money.Amount
+	for range 10000 {
+		floatTotal += 0.1
+		total += money.FromFloat(0.1)
+	}
+
+	assert.NotEqual(t, 1000.0, floatTotal) //nolint:testifylint // exact comparison is the point
+	assert.Equal(t, int64(100000), total.Cents())
+}
@@ -3,9 +3,10 @@ package models
 import (
 	"context"
 	"fmt"
-	"math"
 	"strings"
 	"time"
+
+	"github.com/mrz1836/go-invoice/internal/money"
 )
 
 // LineItemType represents the type of line item
@@ -38,8 +39,9 @@ type LineItem struct {
 	Description string       `json:"description"`
 
 	// For hourly items (Type == LineItemTypeHourly)
-	Hours *float64 `json:"hours,omitempty"`
-	Rate  *float64 `json:"rate,omitempty"`
+	Hours       *float64 `json:"hours,omitempty"`
+	Rate        *float64 `json:"rate,omitempty"`
+	LoggedHours *float64 `json:"logged_hours,omitempty"` // Hours as logged when billing rounded them (see HourlyRounding)
 
 	// For fixed items (Type == LineItemTypeFixed)
 	Amount *float64 `json:"amount,omitempty"`
@@ -48,8 +50,11 @@ type LineItem struct {
 	Quantity  *float64 `json:"quantity,omitempty"`
 	UnitPrice *float64 `json:"unit_price,omitempty"`
 
-	Total     float64   `json:"total"`
-	CreatedAt time.Time `json:"created_at"`
+	// Optional discount; Total is the amount after it is taken off
+	Discount *Discount `json:"discount,omitempty"`
+
+	Total     money.Amount `json:"total"`
+	CreatedAt time.Time    `json:"created_at"`
 }
 
 // NewHourlyLineItem creates a new hourly-based line item
@@ -60,7 +65,7 @@ func NewHourlyLineItem(ctx context.Context, id string, date time.Time, hours, ra
 	default:
 	}
 
-	total := math.Round(hours*rate*100) / 100
+	total := money.Product(hours, rate)
 
 	item := &LineItem{
 		ID:          id,
@@ -88,7 +93,7 @@ func NewFixedLineItem(ctx context.Context, id string, date time.Time, amount flo
 	default:
 	}
 
-	total := math.Round(amount*100) / 100
+	total := money.FromFloat(amount)
 
 	item := &LineItem{
 		ID:          id,
@@ -115,7 +120,7 @@ func NewQuantityLineItem(ctx context.Context, id string, date time.Time, quantit
 	default:
 	}
 
-	total := math.Round(quantity*unitPrice*100) / 100
+	total := money.Product(quantity, unitPrice)
 
 	item := &LineItem{
 		ID:          id,
@@ -149,7 +154,7 @@ func (l *LineItem) Validate(ctx context.Context) error {
 		AddDateNotFuture("date", l.Date, 24).
 		AddRequired("description", l.Description).
 		AddMaxLength("description", l.Description, 1000).
-		AddNonNegative("total", l.Total).
+		AddNonNegative("total", l.Total.Float64()).
 		AddTimeRequired("created_at", l.CreatedAt)
 
 	// Validate optional EndDate if provided
@@ -159,21 +164,28 @@ func (l *LineItem) Validate(ctx context.Context) error {
 		}
 	}
 
+	// Validate optional Discount if provided
+	if l.Discount != nil {
+		if err := l.Discount.Validate(); err != nil {
+			builder.AddCustom("discount", err.Error(), l.Discount)
+		} else if l.Discount.Type == DiscountTypeFixed && money.FromFloat(l.Discount.Value) > l.GrossTotal() {
+			builder.AddCustom("discount", ErrDiscountExceedsAmount.Error(), l.Discount)
+		}
+	}
+
 	// Validate type-specific fields
 	switch l.Type {
 	case LineItemTypeHourly:
 		if l.Hours == nil {
 			builder.AddCustom("hours", "is required for hourly line items", nil)
 		} else {
-			expectedTotal := math.Round(*l.Hours**l.Rate*100) / 100
-			builder.
-				AddFloatValidation("hours", *l.Hours, 24, "24 hours per entry").
-				AddFloatValidation("rate", *l.Rate, 10000, "$10,000 per hour").
-				AddCalculationValidation("total", l.Total, expectedTotal)
+			builder.AddFloatValidation("hours", *l.Hours, 24, "24 hours per entry")
 		}
 
 		if l.Rate == nil {
 			builder.AddCustom("rate", "is required for hourly line items", nil)
+		} else {
+			builder.AddFloatValidation("rate", *l.Rate, 10000, "$10,000 per hour")
 		}
 
 		// Ensure fixed/quantity fields are nil
@@ -188,11 +200,9 @@ func (l *LineItem) Validate(ctx context.Context) error {
 		if l.Amount == nil {
 			builder.AddCustom("amount", "is required for fixed line items", nil)
 		} else {
-			expectedTotal := math.Round(*l.Amount*100) / 100
 			builder.
 				AddPositive("amount", *l.Amount).
-				AddMaxValue("amount", *l.Amount, 1000000, "$1,000,000").
-				AddCalculationValidation("total", l.Total, expectedTotal)
+				AddMaxValue("amount", *l.Amount, 1000000, "$1,000,000")
 		}
 
 		// Ensure hourly/quantity fields are nil
@@ -207,15 +217,13 @@ func (l *LineItem) Validate(ctx context.Context) error {
 		if l.Quantity == nil {
 			builder.AddCustom("quantity", "is required for quantity line items", nil)
 		} else {
-			expectedTotal := math.Round(*l.Quantity**l.UnitPrice*100) / 100
-			builder.
-				AddFloatValidation("quantity", *l.Quantity, 10000, "10,000 units").
-				AddFloatValidation("unit_price", *l.UnitPrice, 100000, "$100,000 per unit").
-				AddCalculationValidation("total", l.Total, expectedTotal)
+			builder.AddFloatValidation("quantity", *l.Quantity, 10000, "10,000 units")
 		}
 
 		if l.UnitPrice == nil {
 			builder.AddCustom("unit_price", "is required for quantity line items", nil)
+		} else {
+			builder.AddFloatValidation("unit_price", *l.UnitPrice, 100000, "$100,000 per unit")
 		}
 
 		// Ensure hourly/fixed fields are nil
@@ -230,6 +238,11 @@ func (l *LineItem) Validate(ctx context.Context) error {
 		builder.AddValidOption("type", string(l.Type), ValidLineItemTypes)
 	}
 
+	// The stored total must equal what the typed fields produce, less any discount
+	if gross, ok := l.grossTotal(); ok {
+		builder.AddAmountMatch("total", l.Total, l.netTotal(gross))
+	}
+
 	return builder.Build(ErrLineItemValidationFailed)
 }
 
@@ -241,37 +254,80 @@ func (l *LineItem) RecalculateTotal(ctx context.Context) error {
 	default:
 	}
 
+	switch l.Type {
+	case LineItemTypeHourly, LineItemTypeFixed, LineItemTypeQuantity:
+		if gross, ok := l.grossTotal(); ok {
+			l.Total = l.netTotal(gross)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported line item type: %s", ErrInvalidLineItemType, l.Type)
+	}
+
+	return nil
+}
+
+// GrossTotal returns the line item amount before its discount
+func (l *LineItem) GrossTotal() money.Amount {
+	if gross, ok := l.grossTotal(); ok {
+		return gross
+	}
+	return l.Total
+}
+
+// grossTotal computes the amount before discount from the type-specific fields, reporting
+// false when they are missing
+func (l *LineItem) grossTotal() (money.Amount, bool) {
 	switch l.Type {
 	case LineItemTypeHourly:
 		if l.Hours != nil && l.Rate != nil {
-			l.Total = math.Round(*l.Hours**l.Rate*100) / 100
+			return money.Product(*l.Hours, *l.Rate), true
 		}
 	case LineItemTypeFixed:
 		if l.Amount != nil {
-			l.Total = math.Round(*l.Amount*100) / 100
+			return money.FromFloat(*l.Amount), true
 		}
 	case LineItemTypeQuantity:
 		if l.Quantity != nil && l.UnitPrice != nil {
-			l.Total = math.Round(*l.Quantity**l.UnitPrice*100) / 100
+			return money.Product(*l.Quantity, *l.UnitPrice), true
 		}
-	default:
-		return fmt.Errorf("%w: unsupported line item type: %s", ErrInvalidLineItemType, l.Type)
 	}
+	return 0, false
+}
 
-	return nil
+// DiscountAmount returns how much the line item discount takes off the gross amount
+func (l *LineItem) DiscountAmount() money.Amount {
+	return l.Discount.AmountOff(l.GrossTotal())
+}
+
+// netTotal returns gross less the line item discount
+func (l *LineItem) netTotal(gross money.Amount) money.Amount {
+	return gross - l.Discount.AmountOff(gross)
 }
 
-// GetFormattedTotal returns the total formatted as a currency string
-func (l *LineItem) GetFormattedTotal() string {
-	return fmt.Sprintf("$%.2f", l.Total)
+// GetFormattedTotal returns the total formatted in currency, e.g. "$1,234.56" or "1.234,56 €"
+func (l *LineItem) GetFormattedTotal(currency string) string {
+	return money.Format(l.Total.Float64(), currency)
 }
 
 // GetDetails returns a human-readable string describing the line item details
 func (l *LineItem) GetDetails() string {
+	details := l.baseDetails()
+	if l.Discount != nil {
+		details += fmt.Sprintf(" less %s discount", l.Discount)
+	}
+	return details
+}
+
+// baseDetails describes the line item quantities without its discount
+func (l *LineItem) baseDetails() string {
 	switch l.Type {
 	case LineItemTypeHourly:
 		if l.Hours != nil && l.Rate != nil {
-			return fmt.Sprintf("%.2f hours @ $%.2f/hr", *l.Hours, *l.Rate)
+			details := fmt.Sprintf("%.2f hours @ $%.2f/hr", *l.Hours, *l.Rate)
+			if l.LoggedHours != nil {
+				details += fmt.Sprintf(" (rounded from %.2f logged)", *l.LoggedHours)
+			}
+			return details
 		}
 	case LineItemTypeFixed:
 		return "Fixed amount"
@@ -322,7 +378,7 @@ func ConvertWorkItemToLineItem(ctx context.Context, wi WorkItem) (*LineItem, err
 		Description: wi.Description,
 		Hours:       &hours,
 		Rate:        &rate,
-		Total:       wi.Total,
+		Total:       money.FromFloat(wi.Total),
 		CreatedAt:   wi.CreatedAt,
 	}, nil
 }
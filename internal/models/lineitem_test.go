@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/money"
 )
 
 func TestNewHourlyLineItem(t *testing.T) {
@@ -25,7 +27,7 @@ func TestNewHourlyLineItem(t *testing.T) {
 		assert.InDelta(t, 8.0, *item.Hours, 1e-9)
 		assert.NotNil(t, item.Rate)
 		assert.InDelta(t, 125.0, *item.Rate, 1e-9)
-		assert.InDelta(t, 1000.0, item.Total, 1e-9)
+		assert.InDelta(t, 1000.0, item.Total.Float64(), 1e-9)
 		assert.Nil(t, item.Amount)
 		assert.Nil(t, item.Quantity)
 		assert.Nil(t, item.UnitPrice)
@@ -67,7 +69,7 @@ func TestNewFixedLineItem(t *testing.T) {
 		assert.Equal(t, "Monthly Retainer", item.Description)
 		assert.NotNil(t, item.Amount)
 		assert.InDelta(t, 2000.0, *item.Amount, 1e-9)
-		assert.InDelta(t, 2000.0, item.Total, 1e-9)
+		assert.InDelta(t, 2000.0, item.Total.Float64(), 1e-9)
 		assert.Nil(t, item.Hours)
 		assert.Nil(t, item.Rate)
 		assert.Nil(t, item.Quantity)
@@ -105,7 +107,7 @@ func TestNewQuantityLineItem(t *testing.T) {
 		assert.InDelta(t, 3.0, *item.Quantity, 1e-9)
 		assert.NotNil(t, item.UnitPrice)
 		assert.InDelta(t, 50.0, *item.UnitPrice, 1e-9)
-		assert.InDelta(t, 150.0, item.Total, 1e-9)
+		assert.InDelta(t, 150.0, item.Total.Float64(), 1e-9)
 		assert.Nil(t, item.Hours)
 		assert.Nil(t, item.Rate)
 		assert.Nil(t, item.Amount)
@@ -143,7 +145,7 @@ func TestLineItemValidation(t *testing.T) {
 			Hours:       &hours,
 			Rate:        &rate,
 			Amount:      &amount, // Should not be set for hourly
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 
@@ -165,7 +167,7 @@ func TestLineItemValidation(t *testing.T) {
 			Hours:       &hours, // Should not be set for fixed
 			Rate:        &rate,  // Should not be set for fixed
 			Amount:      &amount,
-			Total:       1000.0,
+			Total:       money.FromFloat(1000.0),
 			CreatedAt:   time.Now(),
 		}
 
@@ -181,13 +183,54 @@ func TestLineItemValidation(t *testing.T) {
 			Type:        "invalid",
 			Date:        date,
 			Description: "Test",
-			Total:       100.0,
+			Total:       money.FromFloat(100.0),
 			CreatedAt:   time.Now(),
 		}
 
 		err := item.Validate(ctx)
 		require.Error(t, err)
 	})
+
+	t.Run("TotalMismatch", func(t *testing.T) {
+		hours, rate := 8.0, 125.0
+		amount := 2000.0
+		quantity, unitPrice := 3.0, 49.99
+
+		for name, item := range map[string]*LineItem{
+			"Hourly":   {Type: LineItemTypeHourly, Hours: &hours, Rate: &rate, Total: money.FromFloat(1000.01)},
+			"Fixed":    {Type: LineItemTypeFixed, Amount: &amount, Total: money.FromFloat(2500)},
+			"Quantity": {Type: LineItemTypeQuantity, Quantity: &quantity, UnitPrice: &unitPrice, Total: money.FromFloat(149.98)},
+			"Discount": {
+				Type: LineItemTypeFixed, Amount: &amount, Total: money.FromFloat(2000),
+				Discount: &Discount{Type: DiscountTypePercent, Value: 10},
+			},
+		} {
+			item.ID = "item-1"
+			item.Date = time.Now()
+			item.Description = "Test"
+			item.CreatedAt = time.Now()
+
+			err := item.Validate(ctx)
+			require.ErrorIs(t, err, ErrLineItemValidationFailed, name)
+			assert.ErrorContains(t, err, "computed from its amounts", name)
+
+			// Recalculating brings the total back in line
+			require.NoError(t, item.RecalculateTotal(ctx), name)
+			require.NoError(t, item.Validate(ctx), name)
+		}
+	})
+
+	t.Run("MissingRateDoesNotPanic", func(t *testing.T) {
+		hours := 8.0
+		item := &LineItem{
+			ID: "item-1", Type: LineItemTypeHourly, Date: time.Now(), Description: "Test",
+			Hours: &hours, Total: money.FromFloat(1000), CreatedAt: time.Now(),
+		}
+
+		err := item.Validate(ctx)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "rate")
+	})
 }
 
 func TestLineItemRecalculateTotal(t *testing.T) {
@@ -211,7 +254,7 @@ func TestLineItemRecalculateTotal(t *testing.T) {
 
 		err := item.RecalculateTotal(ctx)
 		require.NoError(t, err)
-		assert.InDelta(t, 1000.0, item.Total, 1e-9)
+		assert.InDelta(t, 1000.0, item.Total.Float64(), 1e-9)
 	})
 
 	t.Run("RecalculateFixedTotal", func(t *testing.T) {
@@ -230,7 +273,7 @@ func TestLineItemRecalculateTotal(t *testing.T) {
 
 		err := item.RecalculateTotal(ctx)
 		require.NoError(t, err)
-		assert.InDelta(t, 2000.0, item.Total, 1e-9)
+		assert.InDelta(t, 2000.0, item.Total.Float64(), 1e-9)
 	})
 
 	t.Run("RecalculateQuantityTotal", func(t *testing.T) {
@@ -251,7 +294,7 @@ func TestLineItemRecalculateTotal(t *testing.T) {
 
 		err := item.RecalculateTotal(ctx)
 		require.NoError(t, err)
-		assert.InDelta(t, 150.0, item.Total, 1e-9)
+		assert.InDelta(t, 150.0, item.Total.Float64(), 1e-9)
 	})
 }
 
@@ -286,6 +329,15 @@ func TestLineItemGetDetails(t *testing.T) {
 	})
 }
 
+func TestLineItemGetFormattedTotal(t *testing.T) {
+	item, err := NewFixedLineItem(context.Background(), "item-1", time.Now(), 1234.5, "Retainer")
+	require.NoError(t, err)
+
+	assert.Equal(t, "$1,234.50", item.GetFormattedTotal("USD"))
+	assert.Equal(t, "1.234,50 €", item.GetFormattedTotal("EUR"))
+	assert.Equal(t, "¥1,235", item.GetFormattedTotal("JPY"))
+}
+
 func TestConvertWorkItemToLineItem(t *testing.T) {
 	ctx := context.Background()
 
@@ -311,7 +363,7 @@ func TestConvertWorkItemToLineItem(t *testing.T) {
 		assert.InDelta(t, wi.Hours, *li.Hours, 1e-9)
 		assert.NotNil(t, li.Rate)
 		assert.InDelta(t, wi.Rate, *li.Rate, 1e-9)
-		assert.InDelta(t, wi.Total, li.Total, 1e-9)
+		assert.InDelta(t, wi.Total, li.Total.Float64(), 1e-9)
 		assert.Equal(t, wi.CreatedAt, li.CreatedAt)
 	})
 }
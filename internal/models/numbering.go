@@ -0,0 +1,135 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultInvoiceNumberFormat produces numbers like INV-1000
+const DefaultInvoiceNumberFormat = "{prefix}-{seq}"
+
+// defaultSequenceWidth is the zero-padded width used when {seq} has no explicit width
+const defaultSequenceWidth = 4
+
+// numberFormatToken matches the tokens supported in invoice number formats
+var numberFormatToken = regexp.MustCompile(`\{(prefix|seq(?::(\d+))?|year|month)\}`)
+
+// InvoiceNumbering describes how sequential invoice numbers are generated.
+//
+// Format supports the tokens {prefix}, {seq}, {year} and {month}. {seq} is zero-padded
+// to four digits by default; use {seq:N} for a different width. The sequence is shared
+// across all numbers matching the format, so it does not restart each year or month.
+type InvoiceNumbering struct {
+	Format      string `json:"format"`
+	Prefix      string `json:"prefix"`
+	StartNumber int    `json:"start_number"`
+}
+
+// Validate checks that the format contains exactly one {seq} token and no unknown tokens
+func (n InvoiceNumbering) Validate() error {
+	format := n.format()
+	seqCount := 0
+	for _, match := range numberFormatToken.FindAllStringSubmatch(format, -1) {
+		if strings.HasPrefix(match[1], "seq") {
+			seqCount++
+		}
+	}
+	if seqCount != 1 {
+		return fmt.Errorf("%w: %q must contain exactly one {seq} token", ErrInvalidNumberFormat, format)
+	}
+	if rest := numberFormatToken.ReplaceAllString(format, ""); strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("%w: %q contains an unknown token", ErrInvalidNumberFormat, format)
+	}
+	return nil
+}
+
+// Number renders the invoice number for sequence seq issued on date
+func (n InvoiceNumbering) Number(seq int, date time.Time) string {
+	return numberFormatToken.ReplaceAllStringFunc(n.format(), func(token string) string {
+		match := numberFormatToken.FindStringSubmatch(token)
+		switch {
+		case match[1] == "prefix":
+			return n.Prefix
+		case match[1] == "year":
+			return date.Format("2006")
+		case match[1] == "month":
+			return date.Format("01")
+		default:
+			return fmt.Sprintf("%0*d", sequenceWidth(match[2]), seq)
+		}
+	})
+}
+
+// Sequence extracts the sequence number from an existing invoice number. It returns false
+// when number was not produced by this format and prefix.
+func (n InvoiceNumbering) Sequence(number string) (int, bool) {
+	match := n.pattern().FindStringSubmatch(number)
+	if match == nil {
+		return 0, false
+	}
+	seq, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return seq, true
+}
+
+// Next returns the number following the highest sequence found in existing, or the
+// start number when none of existing match the format
+func (n InvoiceNumbering) Next(existing []string, date time.Time) string {
+	next := n.StartNumber
+	if next < 1 {
+		next = 1
+	}
+	for _, number := range existing {
+		if seq, ok := n.Sequence(number); ok && seq >= next {
+			next = seq + 1
+		}
+	}
+	return n.Number(next, date)
+}
+
+// format returns the configured format or the default when empty
+func (n InvoiceNumbering) format() string {
+	if strings.TrimSpace(n.Format) == "" {
+		return DefaultInvoiceNumberFormat
+	}
+	return n.Format
+}
+
+// pattern builds an anchored regular expression matching numbers produced by the format,
+// capturing the sequence
+func (n InvoiceNumbering) pattern() *regexp.Regexp {
+	format := n.format()
+	var expr strings.Builder
+	expr.WriteString("^")
+	last := 0
+	for _, loc := range numberFormatToken.FindAllStringSubmatchIndex(format, -1) {
+		expr.WriteString(regexp.QuoteMeta(format[last:loc[0]]))
+		switch token := format[loc[2]:loc[3]]; {
+		case token == "prefix":
+			expr.WriteString(regexp.QuoteMeta(n.Prefix))
+		case token == "year":
+			expr.WriteString(`\d{4}`)
+		case token == "month":
+			expr.WriteString(`\d{2}`)
+		default:
+			expr.WriteString(`(\d+)`)
+		}
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(format[last:]))
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// sequenceWidth parses the optional {seq:N} width
+func sequenceWidth(width string) int {
+	if n, err := strconv.Atoi(width); err == nil && n > 0 {
+		return n
+	}
+	return defaultSequenceWidth
+}
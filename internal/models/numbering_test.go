@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvoiceNumberingNumber(t *testing.T) {
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		numbering InvoiceNumbering
+		seq       int
+		expected  string
+	}{
+		{"DefaultFormat", InvoiceNumbering{Prefix: "INV"}, 7, "INV-0007"},
+		{"YearAndSequence", InvoiceNumbering{Format: "{prefix}-{year}-{seq}", Prefix: "INV"}, 1, "INV-2024-0001"},
+		{"YearMonth", InvoiceNumbering{Format: "{prefix}{year}{month}-{seq:3}", Prefix: "AC"}, 12, "AC202403-012"},
+		{"WideSequence", InvoiceNumbering{Format: "{prefix}-{seq}", Prefix: "INV"}, 123456, "INV-123456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.numbering.Number(tt.seq, date))
+		})
+	}
+}
+
+func TestInvoiceNumberingNext(t *testing.T) {
+	date := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	numbering := InvoiceNumbering{Format: "{prefix}-{year}-{seq}", Prefix: "INV", StartNumber: 1}
+
+	t.Run("NoExistingUsesStartNumber", func(t *testing.T) {
+		assert.Equal(t, "INV-2024-0001", numbering.Next(nil, date))
+		assert.Equal(t, "INV-2024-1000", InvoiceNumbering{Format: numbering.Format, Prefix: "INV", StartNumber: 1000}.Next(nil, date))
+	})
+
+	t.Run("IgnoresOtherPrefixesAndFormats", func(t *testing.T) {
+		existing := []string{"INV-2024-0004", "ACME-2024-0100", "INV-20240101-120000", "INV-2023-0002"}
+		assert.Equal(t, "INV-2024-0005", numbering.Next(existing, date))
+	})
+
+	t.Run("SequenceContinuesAcrossYears", func(t *testing.T) {
+		assert.Equal(t, "INV-2024-0010", numbering.Next([]string{"INV-2023-0009"}, date))
+	})
+
+	t.Run("StartNumberIsMinimum", func(t *testing.T) {
+		high := InvoiceNumbering{Prefix: "INV", StartNumber: 1000}
+		assert.Equal(t, "INV-1000", high.Next([]string{"INV-0003"}, date))
+	})
+}
+
+func TestInvoiceNumberingValidate(t *testing.T) {
+	require.NoError(t, InvoiceNumbering{}.Validate())
+	require.NoError(t, InvoiceNumbering{Format: "{prefix}-{year}-{month}-{seq:6}"}.Validate())
+	require.ErrorIs(t, InvoiceNumbering{Format: "{prefix}-{year}"}.Validate(), ErrInvalidNumberFormat)
+	require.ErrorIs(t, InvoiceNumbering{Format: "{seq}-{seq}"}.Validate(), ErrInvalidNumberFormat)
+	require.ErrorIs(t, InvoiceNumbering{Format: "{prefix}-{day}-{seq}"}.Validate(), ErrInvalidNumberFormat)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -48,6 +49,25 @@ const (
 	PaymentMethodOther PaymentMethod = "Other"
 )
 
+// validPaymentMethods lists every supported payment method
+var validPaymentMethods = []PaymentMethod{
+	PaymentMethodUSDC,
+	PaymentMethodBSV,
+	PaymentMethodACH,
+	PaymentMethodWire,
+	PaymentMethodOther,
+}
+
+// ParsePaymentMethod parses a payment method name case-insensitively (e.g. "wire" or "usdc")
+func ParsePaymentMethod(value string) (PaymentMethod, error) {
+	for _, method := range validPaymentMethods {
+		if strings.EqualFold(strings.TrimSpace(value), string(method)) {
+			return method, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidPaymentMethod, value)
+}
+
 // PaymentVerification represents the result of verifying a payment on-chain
 type PaymentVerification struct {
 	InvoiceID       InvoiceID       `json:"invoice_id"`
@@ -114,16 +134,8 @@ func (r *VerifyPaymentRequest) Validate(ctx context.Context) error {
 	}
 
 	// Validate payment method
-	validMethods := []PaymentMethod{
-		PaymentMethodUSDC,
-		PaymentMethodBSV,
-		PaymentMethodACH,
-		PaymentMethodWire,
-		PaymentMethodOther,
-	}
-
 	valid := false
-	for _, method := range validMethods {
+	for _, method := range validPaymentMethods {
 		if r.PaymentMethod == method {
 			valid = true
 			break
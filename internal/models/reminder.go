@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// CanRemind reports whether a payment reminder may be sent for the invoice: it has been
+// sent or is overdue, still has a balance due and is not in the trash. Draft, paid and
+// voided invoices are never reminded.
+func (i *Invoice) CanRemind() bool {
+	if i.Status != StatusSent && i.Status != StatusOverdue {
+		return false
+	}
+	return !i.IsFullyPaid() && !i.IsDeleted()
+}
+
+// RemindedWithin reports whether a reminder was sent less than interval before asOf
+func (i *Invoice) RemindedWithin(asOf time.Time, interval time.Duration) bool {
+	return i.LastReminderAt != nil && asOf.Sub(*i.LastReminderAt) < interval
+}
+
+// RecordReminder notes that a payment reminder was sent at the given time
+func (i *Invoice) RecordReminder(at time.Time) {
+	i.LastReminderAt = &at
+	i.UpdatedAt = time.Now()
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func TestInvoiceCanRemind(t *testing.T) {
+	deleted := time.Now()
+	tests := []struct {
+		name    string
+		invoice Invoice
+		want    bool
+	}{
+		{"sent", Invoice{Status: StatusSent, Total: money.FromFloat(100)}, true},
+		{"overdue", Invoice{Status: StatusOverdue, Total: money.FromFloat(100)}, true},
+		{"draft", Invoice{Status: StatusDraft, Total: money.FromFloat(100)}, false},
+		{"paid", Invoice{Status: StatusPaid, Total: money.FromFloat(100)}, false},
+		{"voided", Invoice{Status: StatusVoided, Total: money.FromFloat(100)}, false},
+		{"settled by payments", Invoice{Status: StatusSent, Total: money.FromFloat(100), Payments: []Payment{{Amount: 100}}}, false},
+		{"deleted", Invoice{Status: StatusSent, Total: money.FromFloat(100), DeletedAt: &deleted}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.invoice.CanRemind())
+		})
+	}
+}
+
+func TestInvoiceReminders(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	invoice := &Invoice{Status: StatusOverdue}
+	assert.False(t, invoice.RemindedWithin(now, 7*24*time.Hour))
+
+	invoice.RecordReminder(now.AddDate(0, 0, -3))
+	require.NotNil(t, invoice.LastReminderAt)
+	assert.True(t, invoice.RemindedWithin(now, 7*24*time.Hour))
+	assert.False(t, invoice.RemindedWithin(now, 3*24*time.Hour))
+	assert.False(t, invoice.UpdatedAt.IsZero())
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// Tax rounding strategies accepted by TAX_ROUNDING and stored on invoices
+const (
+	// TaxRoundingHalfUp rounds the invoice tax half away from zero (25.125 -> 25.13); the default
+	TaxRoundingHalfUp = "half-up"
+	// TaxRoundingHalfEven rounds the invoice tax half to the even cent (25.125 -> 25.12)
+	TaxRoundingHalfEven = "half-even"
+	// TaxRoundingDown truncates the invoice tax to the cent (25.129 -> 25.12)
+	TaxRoundingDown = "down"
+	// TaxRoundingPerLine rounds the tax on each taxable line half-up before summing
+	TaxRoundingPerLine = "per-line"
+)
+
+// TaxRoundings returns the supported tax rounding strategies
+func TaxRoundings() []string {
+	return []string{TaxRoundingHalfUp, TaxRoundingHalfEven, TaxRoundingDown, TaxRoundingPerLine}
+}
+
+// IsValidTaxRounding reports whether strategy is a supported tax rounding strategy.
+// An empty strategy is valid and means TaxRoundingHalfUp.
+func IsValidTaxRounding(strategy string) bool {
+	switch strategy {
+	case "", TaxRoundingHalfUp, TaxRoundingHalfEven, TaxRoundingDown, TaxRoundingPerLine:
+		return true
+	default:
+		return false
+	}
+}
+
+// computeTax returns the tax on taxableAmount at the invoice's effective rate, rounded with the
+// invoice's TaxRounding strategy
+func (i *Invoice) computeTax(taxableAmount money.Amount) money.Amount {
+	rate := i.EffectiveTaxRate()
+
+	switch i.TaxRounding {
+	case TaxRoundingHalfEven:
+		return taxableAmount.MulRateRounded(rate, money.RoundHalfEven)
+	case TaxRoundingDown:
+		return taxableAmount.MulRateRounded(rate, money.RoundDown)
+	case TaxRoundingPerLine:
+		return i.perLineTax(rate)
+	default:
+		return taxableAmount.MulRate(rate)
+	}
+}
+
+// perLineTax rounds the tax on every taxable line half-up to the cent and sums the results.
+// The crypto fee is taxed as its own line and the invoice discount as a negative line, so the
+// taxable base matches the other strategies.
+func (i *Invoice) perLineTax(rate float64) money.Amount {
+	var tax money.Amount
+
+	for _, item := range i.WorkItems {
+		tax += money.FromFloat(item.Total).MulRate(rate)
+	}
+	for _, item := range i.LineItems {
+		tax += item.Total.MulRate(rate)
+	}
+
+	tax += i.CryptoFee.MulRate(rate)
+	tax -= i.DiscountTotal.MulRate(rate)
+
+	return tax
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func TestIsValidTaxRounding(t *testing.T) {
+	for _, strategy := range append(TaxRoundings(), "") {
+		assert.True(t, IsValidTaxRounding(strategy), strategy)
+	}
+	assert.False(t, IsValidTaxRounding("half-down"))
+	assert.False(t, IsValidTaxRounding("HALF-UP"))
+}
+
+// TestRecalculateTotalsTaxRounding runs the same inputs through every strategy, mostly at
+// half-cent boundaries where the strategies disagree
+func TestRecalculateTotalsTaxRounding(t *testing.T) {
+	cases := []struct {
+		name      string
+		lines     []money.Amount
+		cryptoFee money.Amount
+		discount  money.Amount
+		rate      float64
+		want      map[string]money.Amount
+	}{
+		{
+			// (5000 + 25) * 0.005 = 25.125; per line 25.00 + 0.125 -> 25.13
+			name:      "EvenCentTie",
+			lines:     []money.Amount{500000},
+			cryptoFee: 2500,
+			rate:      0.005,
+			want: map[string]money.Amount{
+				"":                  2513,
+				TaxRoundingHalfUp:   2513,
+				TaxRoundingHalfEven: 2512,
+				TaxRoundingDown:     2512,
+				TaxRoundingPerLine:  2513,
+			},
+		},
+		{
+			// 5027 * 0.005 = 25.135, the even neighbor is 25.14
+			name:  "OddCentTie",
+			lines: []money.Amount{502700},
+			rate:  0.005,
+			want: map[string]money.Amount{
+				"":                  2514,
+				TaxRoundingHalfUp:   2514,
+				TaxRoundingHalfEven: 2514,
+				TaxRoundingDown:     2513,
+				TaxRoundingPerLine:  2514,
+			},
+		},
+		{
+			// 19.99 * 0.0825 = 1.649175, no tie
+			name:  "BelowHalf",
+			lines: []money.Amount{1999},
+			rate:  0.0825,
+			want: map[string]money.Amount{
+				"":                  165,
+				TaxRoundingHalfUp:   165,
+				TaxRoundingHalfEven: 165,
+				TaxRoundingDown:     164,
+				TaxRoundingPerLine:  165,
+			},
+		},
+		{
+			// Each 0.50 line is taxed 0.025 and rounds up to 0.03, while 1.00 * 0.05 is exactly 0.05
+			name:  "PerLineRoundsUpEachLine",
+			lines: []money.Amount{50, 50},
+			rate:  0.05,
+			want: map[string]money.Amount{
+				"":                  5,
+				TaxRoundingHalfUp:   5,
+				TaxRoundingHalfEven: 5,
+				TaxRoundingDown:     5,
+				TaxRoundingPerLine:  6,
+			},
+		},
+		{
+			// 20.20 * 0.125 = 2.525; each 10.10 line is taxed 1.2625 -> 1.26
+			name:  "PerLineRoundsDownEachLine",
+			lines: []money.Amount{1010, 1010},
+			rate:  0.125,
+			want: map[string]money.Amount{
+				"":                  253,
+				TaxRoundingHalfUp:   253,
+				TaxRoundingHalfEven: 252,
+				TaxRoundingDown:     252,
+				TaxRoundingPerLine:  252,
+			},
+		},
+		{
+			// (100.00 - 0.10) * 0.05 = 4.995; per line 5.00 - 0.005 -> 5.00 - 0.01
+			name:     "DiscountAsNegativeLine",
+			lines:    []money.Amount{10000},
+			discount: 10,
+			rate:     0.05,
+			want: map[string]money.Amount{
+				"":                  500,
+				TaxRoundingHalfUp:   500,
+				TaxRoundingHalfEven: 500,
+				TaxRoundingDown:     499,
+				TaxRoundingPerLine:  499,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		for strategy, want := range tc.want {
+			t.Run(tc.name+"/"+strategy, func(t *testing.T) {
+				invoice := &Invoice{
+					TaxRate:        tc.rate,
+					TaxRounding:    strategy,
+					CryptoFee:      tc.cryptoFee,
+					DiscountAmount: tc.discount,
+				}
+				for _, total := range tc.lines {
+					invoice.LineItems = append(invoice.LineItems, LineItem{Type: LineItemTypeFixed, Total: total})
+				}
+
+				require.NoError(t, invoice.RecalculateTotals(context.Background()))
+				assert.Equal(t, want, invoice.TaxAmount)
+				assert.Equal(t, invoice.Subtotal-invoice.DiscountTotal+invoice.CryptoFee+want, invoice.Total)
+			})
+		}
+	}
+}
+
+func TestRecalculateTotalsPerLineWorkItems(t *testing.T) {
+	invoice := &Invoice{
+		TaxRate:     0.05,
+		TaxRounding: TaxRoundingPerLine,
+		WorkItems:   []WorkItem{{Total: 0.5}, {Total: 0.5}},
+		LineItems:   []LineItem{{Type: LineItemTypeFixed, Total: 50}},
+	}
+
+	require.NoError(t, invoice.RecalculateTotals(context.Background()))
+	assert.Equal(t, money.Amount(9), invoice.TaxAmount)
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TermsAnchor defines the date payment terms are counted from
+type TermsAnchor string
+
+const (
+	// TermsAnchorIssue counts payment terms from the invoice date (e.g. net 30)
+	TermsAnchorIssue TermsAnchor = "issue"
+	// TermsAnchorEndOfMonth counts payment terms from the end of the invoice month (e.g. net 30 EOM)
+	TermsAnchorEndOfMonth TermsAnchor = "eom"
+)
+
+// daysPerTermsMonth is the number of net days treated as one calendar month for EOM terms
+const daysPerTermsMonth = 30
+
+// ParseTermsAnchor parses a terms anchor value, defaulting to TermsAnchorIssue when empty
+func ParseTermsAnchor(value string) (TermsAnchor, error) {
+	switch TermsAnchor(strings.ToLower(strings.TrimSpace(value))) {
+	case "", TermsAnchorIssue:
+		return TermsAnchorIssue, nil
+	case TermsAnchorEndOfMonth:
+		return TermsAnchorEndOfMonth, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidTermsAnchor, value)
+	}
+}
+
+// CalculateDueDate returns the due date for an invoice issued on issueDate with netDays payment terms.
+//
+// With TermsAnchorIssue the due date is simply issueDate plus netDays.
+//
+// With TermsAnchorEndOfMonth the terms start at the end of the invoice month. Each full
+// 30 net days moves the due date to the last day of the following month, so month lengths
+// and year rollover are handled by the calendar rather than by day counts; any remaining
+// days are added after that. For example, net 30 EOM for an invoice dated March 10 is due
+// April 30, and for one dated January 31 it is due on the last day of February.
+func CalculateDueDate(issueDate time.Time, netDays int, anchor TermsAnchor) time.Time {
+	if anchor != TermsAnchorEndOfMonth {
+		return issueDate.AddDate(0, 0, netDays)
+	}
+
+	if netDays < 0 {
+		netDays = 0
+	}
+	months := netDays / daysPerTermsMonth
+	remainder := netDays % daysPerTermsMonth
+
+	// Day 0 of the month after the target month is the last day of the target month
+	endOfMonth := time.Date(
+		issueDate.Year(), issueDate.Month()+time.Month(months)+1, 0,
+		issueDate.Hour(), issueDate.Minute(), issueDate.Second(), issueDate.Nanosecond(),
+		issueDate.Location(),
+	)
+
+	return endOfMonth.AddDate(0, 0, remainder)
+}
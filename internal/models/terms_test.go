@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCalculateDueDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		issued  time.Time
+		netDays int
+		anchor  TermsAnchor
+		want    time.Time
+	}{
+		{"IssueNet30", date(2024, time.March, 10), 30, TermsAnchorIssue, date(2024, time.April, 9)},
+		{"IssueNet0", date(2024, time.March, 10), 0, TermsAnchorIssue, date(2024, time.March, 10)},
+		{"EOMNet30MidMonth", date(2024, time.March, 10), 30, TermsAnchorEndOfMonth, date(2024, time.April, 30)},
+		{"EOMNet0", date(2024, time.March, 10), 0, TermsAnchorEndOfMonth, date(2024, time.March, 31)},
+		{"EOMJan31Net30NonLeapYear", date(2023, time.January, 31), 30, TermsAnchorEndOfMonth, date(2023, time.February, 28)},
+		{"EOMJan31Net30LeapYear", date(2024, time.January, 31), 30, TermsAnchorEndOfMonth, date(2024, time.February, 29)},
+		{"EOMFebLeapYearNet0", date(2024, time.February, 3), 0, TermsAnchorEndOfMonth, date(2024, time.February, 29)},
+		{"EOMYearRollover", date(2024, time.December, 15), 30, TermsAnchorEndOfMonth, date(2025, time.January, 31)},
+		{"EOMNovNet60", date(2024, time.November, 1), 60, TermsAnchorEndOfMonth, date(2025, time.January, 31)},
+		{"EOMNet45", date(2024, time.March, 10), 45, TermsAnchorEndOfMonth, date(2024, time.May, 15)},
+		{"EOMNet15", date(2024, time.January, 20), 15, TermsAnchorEndOfMonth, date(2024, time.February, 15)},
+		{"EOMNegativeDays", date(2024, time.March, 10), -5, TermsAnchorEndOfMonth, date(2024, time.March, 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CalculateDueDate(tt.issued, tt.netDays, tt.anchor))
+		})
+	}
+}
+
+func TestCalculateDueDate_PreservesClockAndLocation(t *testing.T) {
+	loc := time.FixedZone("EST", -5*60*60)
+	issued := time.Date(2024, time.March, 10, 9, 30, 0, 0, loc)
+
+	due := CalculateDueDate(issued, 30, TermsAnchorEndOfMonth)
+	assert.Equal(t, time.Date(2024, time.April, 30, 9, 30, 0, 0, loc), due)
+}
+
+func TestParseTermsAnchor(t *testing.T) {
+	for input, want := range map[string]TermsAnchor{
+		"":       TermsAnchorIssue,
+		"issue":  TermsAnchorIssue,
+		"EOM":    TermsAnchorEndOfMonth,
+		" eom  ": TermsAnchorEndOfMonth,
+	} {
+		got, err := ParseTermsAnchor(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseTermsAnchor("net")
+	require.ErrorIs(t, err, ErrInvalidTermsAnchor)
+}
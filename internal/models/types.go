@@ -8,6 +8,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/mrz1836/go-invoice/internal/money"
 )
 
 // InvoiceID provides type-safe invoice identification.
@@ -28,6 +30,18 @@ const (
 // ValidInvoiceStatuses contains all valid invoice status values
 var ValidInvoiceStatuses = []string{StatusDraft, StatusSent, StatusPaid, StatusOverdue, StatusVoided} //nolint:gochecknoglobals // Constant-like status validation slice
 
+// Invoice list sort fields
+const (
+	InvoiceSortDate   = "date"
+	InvoiceSortAmount = "amount"
+	InvoiceSortStatus = "status"
+	InvoiceSortClient = "client"
+	InvoiceSortNumber = "number"
+)
+
+// ValidInvoiceSortFields contains all valid invoice sort fields
+var ValidInvoiceSortFields = []string{InvoiceSortDate, InvoiceSortAmount, InvoiceSortStatus, InvoiceSortClient, InvoiceSortNumber} //nolint:gochecknoglobals // Constant-like sort field validation slice
+
 // Validation patterns
 var (
 	invoiceIDPattern = regexp.MustCompile(`^[A-Z0-9-]+$`)
@@ -326,6 +340,20 @@ func (vb *ValidationBuilder) AddCalculationValidation(field string, actual, expe
 	return vb
 }
 
+// AddAmountMatch adds a validation error if the actual amount differs from the expected one.
+// Amounts are whole cents, so any difference is at least a cent and is a real mismatch
+// rather than floating-point noise.
+func (vb *ValidationBuilder) AddAmountMatch(field string, actual, expected money.Amount) *ValidationBuilder {
+	if actual != expected {
+		vb.errors = append(vb.errors, ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("does not match %.2f computed from its amounts", expected),
+			Value:   actual,
+		})
+	}
+	return vb
+}
+
 // AddFloatValidation adds comprehensive float validation (valid, positive, max)
 func (vb *ValidationBuilder) AddFloatValidation(field string, value, maxVal float64, unit string) *ValidationBuilder {
 	return vb.
@@ -441,6 +469,7 @@ type Validator interface {
 type InvoiceFilter struct {
 	Status      string    `json:"status,omitempty"`
 	ClientID    ClientID  `json:"client_id,omitempty"`
+	PONumber    string    `json:"po_number,omitempty"` // Exact purchase order number match
 	DateFrom    time.Time `json:"date_from,omitempty"`
 	DateTo      time.Time `json:"date_to,omitempty"`
 	DueDateFrom time.Time `json:"due_date_from,omitempty"`
@@ -449,6 +478,17 @@ type InvoiceFilter struct {
 	AmountMax   float64   `json:"amount_max,omitempty"`
 	Limit       int       `json:"limit,omitempty"`
 	Offset      int       `json:"offset,omitempty"`
+
+	// UpdatedAfter only returns invoices changed after this time, for incremental sync
+	UpdatedAfter time.Time `json:"updated_after,omitempty"`
+
+	// SortBy is one of ValidInvoiceSortFields; empty lists newest invoices first
+	SortBy string `json:"sort_by,omitempty"`
+	// SortDesc reverses the SortBy order
+	SortDesc bool `json:"sort_desc,omitempty"`
+
+	// IncludeDeleted also returns soft-deleted invoices, which are excluded by default
+	IncludeDeleted bool `json:"include_deleted,omitempty"`
 }
 
 // Validate validates the invoice filter parameters
@@ -461,6 +501,7 @@ func (f *InvoiceFilter) Validate(ctx context.Context) error {
 
 	return NewValidationBuilder().
 		AddValidOption("status", f.Status, ValidInvoiceStatuses).
+		AddValidOption("sort_by", f.SortBy, ValidInvoiceSortFields).
 		AddDateRange("date_range", f.DateFrom, f.DateTo, "date_from", "date_to").
 		AddDateRange("due_date_range", f.DueDateFrom, f.DueDateTo, "due_date_from", "due_date_to").
 		AddNonNegative("amount_min", f.AmountMin).
@@ -473,14 +514,22 @@ func (f *InvoiceFilter) Validate(ctx context.Context) error {
 
 // CreateInvoiceRequest represents a request to create a new invoice
 type CreateInvoiceRequest struct {
-	Number      string     `json:"number"`
-	ClientID    ClientID   `json:"client_id"`
-	Date        time.Time  `json:"date"`
-	DueDate     time.Time  `json:"due_date"`
-	Description string     `json:"description,omitempty"`
-	WorkItems   []WorkItem `json:"work_items,omitempty"`
-	USDCAddress *string    `json:"usdc_address,omitempty"` // Optional USDC address override for this invoice
-	BSVAddress  *string    `json:"bsv_address,omitempty"`  // Optional BSV address override for this invoice
+	Number       string     `json:"number"`
+	ClientID     ClientID   `json:"client_id"`
+	Date         time.Time  `json:"date"`
+	DueDate      time.Time  `json:"due_date"`
+	Description  string     `json:"description,omitempty"`
+	WorkItems    []WorkItem `json:"work_items,omitempty"`
+	USDCAddress  *string    `json:"usdc_address,omitempty"`  // Optional USDC address override for this invoice
+	BSVAddress   *string    `json:"bsv_address,omitempty"`   // Optional BSV address override for this invoice
+	TemplateName string     `json:"template_name,omitempty"` // Optional template used to render this invoice
+	Currency     string     `json:"currency,omitempty"`      // ISO 4217 currency code for this invoice
+	TaxRate      float64    `json:"tax_rate,omitempty"`      // Tax rate as a decimal between 0 and 1, e.g. 0.10 for 10%
+	TaxRounding  string     `json:"tax_rounding,omitempty"`  // Tax rounding strategy; empty means half-up
+
+	// Optional client references, printed in the invoice header
+	PONumber        string `json:"po_number,omitempty"`
+	ClientReference string `json:"client_reference,omitempty"`
 }
 
 // Validate validates the create invoice request
@@ -499,19 +548,32 @@ func (r *CreateInvoiceRequest) Validate(ctx context.Context) error {
 		AddTimeRequired("due_date", r.DueDate).
 		AddTimeOrder("due_date", r.Date, r.DueDate, "invoice date", "due date").
 		AddWorkItems(ctx, "work_items", r.WorkItems).
+		AddIf(r.Currency != "" && !IsValidCurrency(r.Currency), "currency", "must be an ISO 4217 currency code", r.Currency).
+		AddIf(r.TaxRate < 0 || r.TaxRate > 1, "tax_rate", "must be a decimal between 0 and 1, e.g. 0.10 for 10%", r.TaxRate).
+		AddIf(!IsValidTaxRounding(r.TaxRounding), "tax_rounding", "must be one of "+strings.Join(TaxRoundings(), ", "), r.TaxRounding).
+		AddMaxLength("po_number", r.PONumber, MaxPONumberLength).
+		AddMaxLength("client_reference", r.ClientReference, MaxClientReferenceLength).
 		BuildWithMessage("create invoice request validation failed")
 }
 
 // UpdateInvoiceRequest represents a request to update an invoice
 type UpdateInvoiceRequest struct {
-	ID          InvoiceID  `json:"id"`
-	Number      *string    `json:"number,omitempty"`
-	Date        *time.Time `json:"date,omitempty"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
-	Status      *string    `json:"status,omitempty"`
-	Description *string    `json:"description,omitempty"`
-	USDCAddress *string    `json:"usdc_address,omitempty"` // Optional USDC address override for this invoice
-	BSVAddress  *string    `json:"bsv_address,omitempty"`  // Optional BSV address override for this invoice
+	ID              InvoiceID  `json:"id"`
+	Number          *string    `json:"number,omitempty"`
+	Date            *time.Time `json:"date,omitempty"`
+	DueDate         *time.Time `json:"due_date,omitempty"`
+	Status          *string    `json:"status,omitempty"`
+	Description     *string    `json:"description,omitempty"`
+	Notes           *string    `json:"notes,omitempty"`            // Optional notes change; an empty string clears them
+	PONumber        *string    `json:"po_number,omitempty"`        // Optional purchase order change; an empty string clears it
+	ClientReference *string    `json:"client_reference,omitempty"` // Optional client reference change; an empty string clears it
+	USDCAddress     *string    `json:"usdc_address,omitempty"`     // Optional USDC address override for this invoice
+	BSVAddress      *string    `json:"bsv_address,omitempty"`      // Optional BSV address override for this invoice
+	TemplateName    *string    `json:"template_name,omitempty"`    // Optional template change; an empty string clears it
+
+	// Invoice discount changes; set both to replace a discount of the other kind, or both to 0 to clear it
+	DiscountPercent *float64 `json:"discount_percent,omitempty"`
+	DiscountAmount  *float64 `json:"discount_amount,omitempty"`
 }
 
 // Validate validates the update invoice request
@@ -522,11 +584,28 @@ func (r *UpdateInvoiceRequest) Validate(ctx context.Context) error {
 	default:
 	}
 
+	// Length of an optional field change, 0 when it is not being changed
+	length := func(value *string) int {
+		if value == nil {
+			return 0
+		}
+		return len(*value)
+	}
+
 	return NewValidationBuilder().
 		AddRequired("id", string(r.ID)).
 		AddRequiredPointer("number", r.Number, "cannot be empty").
 		AddPatternPointer("number", r.Number, invoiceIDPattern, "must contain only uppercase letters, numbers, and hyphens").
 		AddValidOptionPointer("status", r.Status, ValidInvoiceStatuses).
 		AddTimeOrderPointer("due_date", r.Date, r.DueDate, "invoice date", "due date").
+		AddIf(length(r.Notes) > MaxInvoiceNotesLength,
+			"notes", fmt.Sprintf("cannot exceed %d characters", MaxInvoiceNotesLength), length(r.Notes)).
+		AddIf(length(r.PONumber) > MaxPONumberLength,
+			"po_number", fmt.Sprintf("cannot exceed %d characters", MaxPONumberLength), length(r.PONumber)).
+		AddIf(length(r.ClientReference) > MaxClientReferenceLength,
+			"client_reference", fmt.Sprintf("cannot exceed %d characters", MaxClientReferenceLength), length(r.ClientReference)).
+		AddIf(r.DiscountPercent != nil && (*r.DiscountPercent < 0 || *r.DiscountPercent > 100),
+			"discount_percent", "must be between 0 and 100", r.DiscountPercent).
+		AddIf(r.DiscountAmount != nil && *r.DiscountAmount < 0, "discount_amount", "must be non-negative", r.DiscountAmount).
 		BuildWithMessage("update invoice request validation failed")
 }
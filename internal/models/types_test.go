@@ -311,6 +311,18 @@ func (suite *TypesTestSuite) TestCreateInvoiceRequestValidate() {
 			},
 			expectError: false,
 		},
+		{
+			name: "TaxRateAsPercentage",
+			request: CreateInvoiceRequest{
+				Number:   testInvoiceNum,
+				ClientID: testClientID001,
+				Date:     time.Now(),
+				DueDate:  time.Now().AddDate(0, 0, 30),
+				TaxRate:  10,
+			},
+			expectError: true,
+			errorMsg:    "validation failed for field 'tax_rate': must be a decimal between 0 and 1, e.g. 0.10 for 10%",
+		},
 		{
 			name: "EmptyNumber",
 			request: CreateInvoiceRequest{
@@ -503,6 +515,23 @@ func (suite *TypesTestSuite) TestUpdateInvoiceRequestValidate() {
 			},
 			expectError: false,
 		},
+		{
+			name: "ClearNotes",
+			request: UpdateInvoiceRequest{
+				ID:    testInvoiceID001,
+				Notes: ptrString(""),
+			},
+			expectError: false,
+		},
+		{
+			name: "NotesTooLong",
+			request: UpdateInvoiceRequest{
+				ID:    testInvoiceID001,
+				Notes: ptrString(strings.Repeat("a", MaxInvoiceNotesLength+1)),
+			},
+			expectError: true,
+			errorMsg:    "validation failed for field 'notes': cannot exceed 2000 characters",
+		},
 		{
 			name: "EmptyID",
 			request: UpdateInvoiceRequest{
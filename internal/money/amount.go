@@ -0,0 +1,149 @@
+package money
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// ErrInvalidAmount is returned when a JSON amount is not a decimal number
+var ErrInvalidAmount = fmt.Errorf("invalid amount")
+
+// centsPerUnit is the number of minor units an Amount stores per currency unit
+const centsPerUnit = 100
+
+// Amount is a monetary amount stored as an integer number of cents, so sums never drift.
+// It is written to JSON as a decimal number (e.g. 1234.5) to match the float values stored
+// by earlier versions, and prints as a decimal with fmt verbs such as %v and %.2f.
+type Amount int64
+
+// FromFloat converts a decimal amount to an Amount, rounding half-up (away from zero) to the cent.
+// The value is rounded from its shortest decimal form, so 2.675 becomes 2.68.
+func FromFloat(value float64) Amount {
+	r, ok := decimalRat(value)
+	if !ok {
+		return 0
+	}
+	return roundRat(r)
+}
+
+// Product multiplies two decimal values, such as hours and a rate, and rounds the result
+// half-up to the cent without intermediate floating point error
+func Product(a, b float64) Amount {
+	ra, okA := decimalRat(a)
+	rb, okB := decimalRat(b)
+	if !okA || !okB {
+		return 0
+	}
+	return roundRat(ra.Mul(ra, rb))
+}
+
+// Rounding selects how a fractional cent is rounded to a whole cent
+type Rounding int
+
+const (
+	// RoundHalfUp rounds halves away from zero, so 25.125 becomes 25.13
+	RoundHalfUp Rounding = iota
+	// RoundHalfEven rounds halves to the nearest even cent (banker's rounding), so 25.125 becomes 25.12
+	RoundHalfEven
+	// RoundDown truncates toward zero, so 25.129 becomes 25.12
+	RoundDown
+)
+
+// MulRate multiplies the amount by rate (e.g. a 0.0825 tax rate) and rounds half-up to the cent
+func (a Amount) MulRate(rate float64) Amount {
+	return a.MulRateRounded(rate, RoundHalfUp)
+}
+
+// MulRateRounded multiplies the amount by rate and rounds to the cent using mode
+func (a Amount) MulRateRounded(rate float64, mode Rounding) Amount {
+	r, ok := decimalRat(rate)
+	if !ok {
+		return 0
+	}
+	r.Mul(r, big.NewRat(int64(a), centsPerUnit))
+	return roundRatMode(r, mode)
+}
+
+// Cents returns the amount in cents
+func (a Amount) Cents() int64 {
+	return int64(a)
+}
+
+// Float64 returns the amount in currency units
+func (a Amount) Float64() float64 {
+	return float64(a) / centsPerUnit
+}
+
+// String returns the amount as a plain decimal with two places, e.g. "1234.50"
+func (a Amount) String() string {
+	return strconv.FormatFloat(a.Float64(), 'f', 2, 64)
+}
+
+// Format implements fmt.Formatter so amounts print as decimals with %v, %s, %f, %g and %e
+// (including precision, e.g. %.2f), while %d prints the number of cents
+func (a Amount) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'd':
+		_, _ = fmt.Fprintf(f, fmt.FormatString(f, verb), int64(a))
+	case 'v', 's':
+		_, _ = fmt.Fprintf(f, fmt.FormatString(f, 's'), a.String())
+	default:
+		_, _ = fmt.Fprintf(f, fmt.FormatString(f, verb), a.Float64())
+	}
+}
+
+// MarshalJSON writes the amount as a decimal number
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(a.Float64(), 'f', -1, 64)), nil
+}
+
+// UnmarshalJSON reads a decimal number, rounding half-up to the cent
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*a = 0
+		return nil
+	}
+
+	r, ok := new(big.Rat).SetString(string(data))
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidAmount, data)
+	}
+	*a = roundRat(r)
+	return nil
+}
+
+// decimalRat returns the exact rational value of value's shortest decimal representation
+func decimalRat(value float64) (*big.Rat, bool) {
+	return new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// roundRat converts a value in currency units to cents, rounding half away from zero
+func roundRat(r *big.Rat) Amount {
+	return roundRatMode(r, RoundHalfUp)
+}
+
+// roundRatMode converts a value in currency units to cents using mode
+func roundRatMode(r *big.Rat, mode Rounding) Amount {
+	scaled := new(big.Rat).Mul(r, big.NewRat(centsPerUnit, 1))
+	num, den := scaled.Num(), scaled.Denom()
+
+	// QuoRem truncates toward zero, which is already RoundDown
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if mode == RoundDown || rem.Sign() == 0 {
+		return Amount(quo.Int64())
+	}
+
+	cmp := rem.Abs(rem).Lsh(rem, 1).Cmp(den)
+	roundAway := cmp > 0 || (cmp == 0 && (mode == RoundHalfUp || quo.Bit(0) == 1))
+	if roundAway {
+		if num.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	return Amount(quo.Int64())
+}
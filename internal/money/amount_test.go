@@ -0,0 +1,127 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		expected Amount
+	}{
+		{"Whole", 1000, 100000},
+		{"Cents", 12.34, 1234},
+		{"HalfUp", 2.675, 268}, // 2.675 is 2.67499999... as a float64
+		{"HalfUpNegative", -2.675, -268},
+		{"HalfCent", 0.005, 1},
+		{"BelowHalfCent", 1.004, 100},
+		{"Negative", -42.5, -4250},
+		{"Zero", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FromFloat(tt.value))
+		})
+	}
+}
+
+func TestProduct(t *testing.T) {
+	assert.Equal(t, Amount(5000), Product(1.5, 33.33)) // 49.995 rounds up, float math gives 49.99
+	assert.Equal(t, Amount(100000), Product(8, 125))
+	assert.Equal(t, Amount(4125), Product(0.33, 125))
+	assert.Equal(t, Amount(4500), Product(3, 15))
+}
+
+func TestAmountMulRate(t *testing.T) {
+	assert.Equal(t, Amount(20500), Amount(102500).MulRate(0.20))
+	assert.Equal(t, Amount(165), Amount(1999).MulRate(0.0825)) // 164.9175 cents
+	assert.Equal(t, Amount(3), Amount(50).MulRate(0.05))       // exactly half a cent rounds up
+	assert.Equal(t, Amount(0), Amount(12345).MulRate(0))
+}
+
+func TestAmountMulRateRounded(t *testing.T) {
+	cases := []struct {
+		amount Amount
+		rate   float64
+		mode   Rounding
+		want   Amount
+	}{
+		{502500, 0.005, RoundHalfUp, 2513},   // 25.125
+		{502500, 0.005, RoundHalfEven, 2512}, // ties go to the even cent
+		{502700, 0.005, RoundHalfEven, 2514}, // 25.135
+		{502500, 0.005, RoundDown, 2512},
+		{1999, 0.0825, RoundHalfEven, 165}, // 164.9175 cents is not a tie
+		{1999, 0.0825, RoundDown, 164},
+		{-502500, 0.005, RoundHalfUp, -2513},
+		{-502500, 0.005, RoundHalfEven, -2512},
+		{-1999, 0.0825, RoundDown, -164},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, tc.amount.MulRateRounded(tc.rate, tc.mode), "%d * %v (mode %d)", tc.amount, tc.rate, tc.mode)
+	}
+}
+
+func TestAmountConversions(t *testing.T) {
+	a := FromFloat(1234.5)
+	assert.Equal(t, int64(123450), a.Cents())
+	assert.InDelta(t, 1234.5, a.Float64(), 1e-9)
+	assert.Equal(t, "1234.50", a.String())
+}
+
+func TestAmountFormatVerbs(t *testing.T) {
+	a := FromFloat(1234.5)
+	assert.Equal(t, "1234.50", fmt.Sprintf("%v", a))
+	assert.Equal(t, "1234.50", fmt.Sprintf("%s", a))
+	assert.Equal(t, "1234.50", fmt.Sprintf("%.2f", a))
+	assert.Equal(t, "+1234.5", fmt.Sprintf("%+.1f", a))
+	assert.Equal(t, "123450", fmt.Sprintf("%d", a))
+}
+
+func TestAmountJSON(t *testing.T) {
+	t.Run("WritesDecimal", func(t *testing.T) {
+		data, err := json.Marshal(struct {
+			Total Amount `json:"total"`
+		}{FromFloat(1234.5)})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"total":1234.5}`, string(data))
+	})
+
+	t.Run("ReadsLegacyFloats", func(t *testing.T) {
+		var decoded struct {
+			Subtotal Amount `json:"subtotal"`
+			Tax      Amount `json:"tax"`
+			Total    Amount `json:"total"`
+			Fee      Amount `json:"fee"`
+		}
+		err := json.Unmarshal([]byte(`{"subtotal":1000,"tax":82.50000000000001,"total":1082.5,"fee":null}`), &decoded)
+		require.NoError(t, err)
+		assert.Equal(t, Amount(100000), decoded.Subtotal)
+		assert.Equal(t, Amount(8250), decoded.Tax)
+		assert.Equal(t, Amount(108250), decoded.Total)
+		assert.Equal(t, Amount(0), decoded.Fee)
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		for _, a := range []Amount{0, 1, 99, 100, 123456789, -2550} {
+			data, err := json.Marshal(a)
+			require.NoError(t, err)
+
+			var decoded Amount
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, a, decoded)
+		}
+	})
+
+	t.Run("RejectsStrings", func(t *testing.T) {
+		var decoded Amount
+		require.ErrorIs(t, json.Unmarshal([]byte(`"abc"`), &decoded), ErrInvalidAmount)
+	})
+}
@@ -0,0 +1,135 @@
+// Package money formats monetary amounts with the symbol, separators and precision
+// conventional for each currency.
+package money
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// style describes how amounts in a currency are written
+type style struct {
+	symbol      string
+	decimals    int
+	decimalSep  string
+	groupSep    string
+	symbolAfter bool // Symbol follows the number ("1.234,56 €")
+	spaced      bool // Symbol and number are separated by a space
+}
+
+// defaultStyle is used for currencies without a specific entry; the ISO code is used as the symbol
+var defaultStyle = style{decimals: 2, decimalSep: ".", groupSep: ",", spaced: true}
+
+// styles holds the formatting conventions for common currencies
+var styles = map[string]style{
+	"USD": {symbol: "$", decimals: 2, decimalSep: ".", groupSep: ","},
+	"CAD": {symbol: "C$", decimals: 2, decimalSep: ".", groupSep: ","},
+	"AUD": {symbol: "A$", decimals: 2, decimalSep: ".", groupSep: ","},
+	"GBP": {symbol: "£", decimals: 2, decimalSep: ".", groupSep: ","},
+	"EUR": {symbol: "€", decimals: 2, decimalSep: ",", groupSep: ".", symbolAfter: true, spaced: true},
+	"CHF": {symbol: "CHF", decimals: 2, decimalSep: ".", groupSep: "'", spaced: true},
+	"SEK": {symbol: "kr", decimals: 2, decimalSep: ",", groupSep: " ", symbolAfter: true, spaced: true},
+	"NOK": {symbol: "kr", decimals: 2, decimalSep: ",", groupSep: " ", symbolAfter: true, spaced: true},
+	"DKK": {symbol: "kr", decimals: 2, decimalSep: ",", groupSep: ".", symbolAfter: true, spaced: true},
+	"JPY": {symbol: "¥", decimals: 0, decimalSep: ".", groupSep: ","},
+	"KRW": {symbol: "₩", decimals: 0, decimalSep: ".", groupSep: ","},
+}
+
+// zeroDecimalCurrencies lists ISO 4217 currencies that have no minor unit
+var zeroDecimalCurrencies = map[string]struct{}{
+	"BIF": {}, "CLP": {}, "DJF": {}, "GNF": {}, "ISK": {}, "JPY": {}, "KMF": {}, "KRW": {},
+	"PYG": {}, "RWF": {}, "UGX": {}, "VND": {}, "VUV": {}, "XAF": {}, "XOF": {}, "XPF": {},
+}
+
+// Format writes amount in currency, e.g. "$1,234.56", "1.234,56 €" or "¥1,235".
+// Negative amounts are prefixed with a minus sign.
+func Format(amount float64, currency string) string {
+	return format(amount, currency, false)
+}
+
+// FormatAccounting is like Format but writes negative amounts in parentheses, e.g. "($1,234.56)"
+func FormatAccounting(amount float64, currency string) string {
+	return format(amount, currency, true)
+}
+
+// Symbol returns the symbol for a currency code, or the code itself when it has no symbol
+func Symbol(currency string) string {
+	if s, ok := styles[currency]; ok {
+		return s.symbol
+	}
+	return currency
+}
+
+// Decimals returns the number of minor unit digits written for a currency
+func Decimals(currency string) int {
+	return lookup(currency).decimals
+}
+
+// lookup returns the style for a currency code
+func lookup(currency string) style {
+	if s, ok := styles[currency]; ok {
+		return s
+	}
+
+	s := defaultStyle
+	s.symbol = currency
+	if _, ok := zeroDecimalCurrencies[currency]; ok {
+		s.decimals = 0
+	}
+	return s
+}
+
+// format writes amount in currency, using parentheses for negatives when accounting is set
+func format(amount float64, currency string, accounting bool) string {
+	s := lookup(currency)
+
+	scale := math.Pow10(s.decimals)
+	minor := math.Round(math.Abs(amount) * scale)
+	negative := amount < 0 && minor != 0
+
+	number := groupDigits(strconv.FormatFloat(math.Floor(minor/scale), 'f', 0, 64), s.groupSep)
+	if s.decimals > 0 {
+		fraction := strconv.FormatFloat(math.Mod(minor, scale), 'f', 0, 64)
+		number += s.decimalSep + strings.Repeat("0", s.decimals-len(fraction)) + fraction
+	}
+
+	formatted := number
+	if s.symbol != "" {
+		sep := ""
+		if s.spaced {
+			sep = " "
+		}
+		if s.symbolAfter {
+			formatted = number + sep + s.symbol
+		} else {
+			formatted = s.symbol + sep + number
+		}
+	}
+
+	switch {
+	case !negative:
+		return formatted
+	case accounting:
+		return "(" + formatted + ")"
+	default:
+		return "-" + formatted
+	}
+}
+
+// groupDigits inserts sep between every group of three digits
+func groupDigits(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	b.Grow(len(digits) + len(digits)/3*len(sep))
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(sep)
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
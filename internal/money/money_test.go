@@ -0,0 +1,52 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		expected string
+	}{
+		{"USD", 1234.56, "USD", "$1,234.56"},
+		{"USDLarge", 1234567.891, "USD", "$1,234,567.89"},
+		{"USDSmall", 0.5, "USD", "$0.50"},
+		{"USDNegative", -42, "USD", "-$42.00"},
+		{"USDRoundsUpToWholeUnit", 9.999, "USD", "$10.00"},
+		{"EUR", 1234.56, "EUR", "1.234,56 €"},
+		{"GBP", 1234.56, "GBP", "£1,234.56"},
+		{"JPYHasNoCents", 1234.56, "JPY", "¥1,235"},
+		{"CHF", 1234567.5, "CHF", "CHF 1'234'567.50"},
+		{"SEK", 1234.5, "SEK", "1 234,50 kr"},
+		{"ZeroDecimalWithoutStyle", 1500.4, "CLP", "CLP 1,500"},
+		{"UnknownCurrency", 1234.56, "XYZ", "XYZ 1,234.56"},
+		{"NoCurrency", 1234.56, "", "1,234.56"},
+		{"NegativeZero", -0.001, "USD", "$0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Format(tt.amount, tt.currency))
+		})
+	}
+}
+
+func TestFormatAccounting(t *testing.T) {
+	assert.Equal(t, "($1,234.56)", FormatAccounting(-1234.56, "USD"))
+	assert.Equal(t, "(1.234,56 €)", FormatAccounting(-1234.56, "EUR"))
+	assert.Equal(t, "$1,234.56", FormatAccounting(1234.56, "USD"))
+}
+
+func TestSymbolAndDecimals(t *testing.T) {
+	assert.Equal(t, "$", Symbol("USD"))
+	assert.Equal(t, "¥", Symbol("JPY"))
+	assert.Equal(t, "XYZ", Symbol("XYZ"))
+	assert.Equal(t, 2, Decimals("EUR"))
+	assert.Equal(t, 0, Decimals("JPY"))
+	assert.Equal(t, 0, Decimals("ISK"))
+}
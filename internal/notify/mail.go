@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mail errors
+var (
+	ErrMailRecipientRequired = fmt.Errorf("email recipient is required")
+	ErrMailSenderRequired    = fmt.Errorf("email sender is required")
+)
+
+// Message is a plain text email
+type Message struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Mailer sends email. Unlike Notifier, delivery is synchronous and failures are returned,
+// so the caller only records a reminder that was actually handed to the mail server.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPMailer sends email through an SMTP server, upgrading to TLS when the server offers
+// STARTTLS and authenticating with PLAIN auth when a username is set
+type SMTPMailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+}
+
+// NewSMTPMailer creates a mailer for the SMTP server at host:port
+func NewSMTPMailer(host string, port int, username, password string) *SMTPMailer {
+	return &SMTPMailer{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		username: username,
+		password: password,
+	}
+}
+
+// Send delivers msg. The SMTP client does not take a context, so ctx is only checked
+// before the connection is made.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if msg.To == "" {
+		return ErrMailRecipientRequired
+	}
+	if msg.From == "" {
+		return ErrMailSenderRequired
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	if err := smtp.SendMail(m.addr, auth, msg.From, []string{msg.To}, FormatMessage(msg, time.Now())); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", msg.To, err)
+	}
+	return nil
+}
+
+// FormatMessage encodes msg as an RFC 5322 message with CRLF line endings, dated at date
+func FormatMessage(msg Message, date time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + headerValue(msg.From) + "\r\n")
+	b.WriteString("To: " + headerValue(msg.To) + "\r\n")
+	b.WriteString("Subject: " + headerValue(msg.Subject) + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// headerValue strips line breaks so a value cannot inject extra headers
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", "", "\n", " ").Replace(value)
+}
+
+// FakeMailer records messages instead of sending them, for tests
+type FakeMailer struct {
+	mu       sync.Mutex
+	messages []Message
+	Err      error // Returned by Send when set, without recording the message
+}
+
+// Send records msg
+func (f *FakeMailer) Send(_ context.Context, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.messages = append(f.messages, msg)
+	return nil
+}
+
+// Messages returns the messages recorded so far
+func (f *FakeMailer) Messages() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Message(nil), f.messages...)
+}
@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/templates"
+)
+
+func TestFormatMessage(t *testing.T) {
+	date := time.Date(2025, 6, 15, 9, 30, 0, 0, time.UTC)
+	raw := string(FormatMessage(Message{
+		From:    "billing@acme.example",
+		To:      "ap@client.example",
+		Subject: "Reminder\r\nBcc: attacker@example.com",
+		Body:    "Line one\nLine two\n",
+	}, date))
+
+	assert.Contains(t, raw, "From: billing@acme.example\r\n")
+	assert.Contains(t, raw, "To: ap@client.example\r\n")
+	assert.Contains(t, raw, "Subject: Reminder Bcc: attacker@example.com\r\n")
+	assert.Contains(t, raw, "Date: Sun, 15 Jun 2025 09:30:00 +0000\r\n")
+	assert.True(t, strings.HasSuffix(raw, "\r\n\r\nLine one\r\nLine two\r\n"))
+}
+
+func TestSMTPMailerRequiresAddresses(t *testing.T) {
+	mailer := NewSMTPMailer("localhost", 2525, "", "")
+
+	err := mailer.Send(context.Background(), Message{From: "billing@acme.example"})
+	require.ErrorIs(t, err, ErrMailRecipientRequired)
+
+	err = mailer.Send(context.Background(), Message{To: "ap@client.example"})
+	require.ErrorIs(t, err, ErrMailSenderRequired)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = mailer.Send(ctx, Message{From: "billing@acme.example", To: "ap@client.example"})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFakeMailer(t *testing.T) {
+	mailer := &FakeMailer{}
+	require.NoError(t, mailer.Send(context.Background(), Message{To: "ap@client.example"}))
+	require.Len(t, mailer.Messages(), 1)
+
+	mailer.Err = errors.New("connection refused") //nolint:err113 // test-only error
+	require.Error(t, mailer.Send(context.Background(), Message{To: "other@client.example"}))
+	assert.Len(t, mailer.Messages(), 1)
+}
+
+func TestRenderReminder(t *testing.T) {
+	subject, body, err := RenderReminder(templates.DefaultReminderTemplate, Reminder{
+		Number:              "INV-001",
+		ClientName:          "Acme Corp",
+		Date:                "2025-05-01",
+		DueDate:             "2025-05-31",
+		DaysOverdue:         12,
+		AmountDue:           "$1,250.00",
+		PaymentTerms:        "Net 30",
+		PaymentInstructions: "Wire to First Bank",
+		BusinessName:        "Consulting LLC",
+		BusinessEmail:       "billing@consulting.example",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Payment reminder: invoice INV-001 is 12 days overdue", subject)
+	assert.True(t, strings.HasPrefix(body, "Hello Acme Corp,"))
+	assert.Contains(t, body, "Amount due: $1,250.00")
+	assert.Contains(t, body, "Wire to First Bank")
+	assert.True(t, strings.HasSuffix(body, "billing@consulting.example\n"))
+
+	subject, _, err = RenderReminder(templates.DefaultReminderTemplate, Reminder{Number: "INV-002", DaysOverdue: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "Payment reminder: invoice INV-002 is 1 day overdue", subject)
+}
+
+func TestRenderReminderErrors(t *testing.T) {
+	_, _, err := RenderReminder("Hello {{.ClientName}}", Reminder{})
+	require.ErrorIs(t, err, ErrReminderSubjectMissing)
+
+	_, _, err = RenderReminder("Subject: {{.Missing}}", Reminder{})
+	require.Error(t, err)
+
+	_, _, err = RenderReminder("Subject: {{", Reminder{})
+	require.Error(t, err)
+}
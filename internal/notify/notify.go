@@ -0,0 +1,74 @@
+// Package notify tells integrators about invoice status changes, such as an invoice
+// becoming paid or overdue, by posting signed JSON payloads to a webhook, and emails
+// payment reminders to clients.
+package notify
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// eventTypePrefix starts every event type, followed by the invoice's new status
+const eventTypePrefix = "invoice."
+
+// Event describes an invoice status transition
+type Event struct {
+	Type          string           `json:"event"` // e.g. "invoice.paid" or "invoice.overdue"
+	InvoiceID     models.InvoiceID `json:"invoice_id"`
+	InvoiceNumber string           `json:"invoice_number"`
+	OldStatus     string           `json:"old_status"`
+	NewStatus     string           `json:"new_status"`
+	OccurredAt    time.Time        `json:"occurred_at"`
+}
+
+// NewStatusChangeEvent creates the event for invoice moving from oldStatus to its current status
+func NewStatusChangeEvent(invoice *models.Invoice, oldStatus string) Event {
+	return Event{
+		Type:          EventType(invoice.Status),
+		InvoiceID:     invoice.ID,
+		InvoiceNumber: invoice.Number,
+		OldStatus:     oldStatus,
+		NewStatus:     invoice.Status,
+		OccurredAt:    time.Now().UTC(),
+	}
+}
+
+// EventType returns the event type for an invoice entering status
+func EventType(status string) string {
+	return eventTypePrefix + status
+}
+
+// Notifier delivers invoice events. Delivery is best-effort: Notify never returns an error
+// and must not hold up the operation that changed the invoice.
+type Notifier interface {
+	Notify(ctx context.Context, event Event)
+}
+
+// Logger interface for notification failures
+type Logger interface {
+	Debug(msg string, fields ...any)
+	Error(msg string, fields ...any)
+}
+
+// FakeNotifier records events instead of delivering them, for tests
+type FakeNotifier struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// Notify records event
+func (f *FakeNotifier) Notify(_ context.Context, event Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+}
+
+// Events returns the events recorded so far
+func (f *FakeNotifier) Events() []Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Event(nil), f.events...)
+}
@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// subjectPrefix starts the first line of a reminder template
+const subjectPrefix = "Subject:"
+
+// ErrReminderSubjectMissing is returned when a reminder template does not start with a subject line
+var ErrReminderSubjectMissing = fmt.Errorf("reminder template must start with a \"Subject:\" line")
+
+// Reminder is the data passed to the payment reminder template
+type Reminder struct {
+	Number              string `json:"number"`
+	ClientName          string `json:"client_name"`
+	Date                string `json:"date"`
+	DueDate             string `json:"due_date"`
+	DaysOverdue         int    `json:"days_overdue"`
+	AmountDue           string `json:"amount_due"` // Formatted in the invoice currency
+	PaymentTerms        string `json:"payment_terms"`
+	PaymentInstructions string `json:"payment_instructions"`
+	BusinessName        string `json:"business_name"`
+	BusinessEmail       string `json:"business_email"`
+}
+
+// RenderReminder executes the reminder template text with data and splits the result into
+// the subject, taken from the leading "Subject:" line, and the body that follows it
+func RenderReminder(text string, data Reminder) (subject, body string, err error) {
+	tmpl, err := template.New("reminder").Parse(text)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse reminder template: %w", err)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", "", fmt.Errorf("failed to render reminder: %w", err)
+	}
+
+	first, rest, _ := strings.Cut(out.String(), "\n")
+	if !strings.HasPrefix(first, subjectPrefix) {
+		return "", "", ErrReminderSubjectMissing
+	}
+	subject = strings.TrimSpace(strings.TrimPrefix(first, subjectPrefix))
+	body = strings.TrimSpace(rest) + "\n"
+	return subject, body, nil
+}
@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 signature of the request body
+const SignatureHeader = "X-Signature"
+
+// signaturePrefix names the algorithm in the signature header value
+const signaturePrefix = "sha256="
+
+// DefaultWebhookTimeout bounds a single delivery, so a slow endpoint cannot hold up a command
+const DefaultWebhookTimeout = 5 * time.Second
+
+// ErrWebhookStatus is logged when the webhook endpoint answers with a non-2xx status
+var ErrWebhookStatus = fmt.Errorf("webhook endpoint returned an error status")
+
+// WebhookNotifier posts events as JSON to a webhook URL. Each delivery runs in the
+// background; Wait lets a command finish pending deliveries before it exits.
+type WebhookNotifier struct {
+	url     string
+	secret  string
+	client  *http.Client
+	logger  Logger
+	pending sync.WaitGroup
+}
+
+// NewWebhookNotifier creates a notifier posting to url. When secret is set every request
+// is signed with it in the X-Signature header.
+func NewWebhookNotifier(url, secret string, logger Logger) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: DefaultWebhookTimeout},
+		logger: logger,
+	}
+}
+
+// Notify sends event in the background. Failures are logged, never returned.
+func (w *WebhookNotifier) Notify(ctx context.Context, event Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		w.logger.Error("failed to encode webhook event", "event", event.Type, "error", err)
+		return
+	}
+
+	// The delivery outlives the operation that triggered it, but not the timeout
+	ctx = context.WithoutCancel(ctx)
+	w.pending.Go(func() {
+		if err := w.deliver(ctx, body); err != nil {
+			w.logger.Error("webhook delivery failed", "event", event.Type, "invoice_id", event.InvoiceID, "error", err)
+			return
+		}
+		w.logger.Debug("webhook delivered", "event", event.Type, "invoice_id", event.InvoiceID)
+	})
+}
+
+// Wait blocks until pending deliveries finish or ctx is done
+func (w *WebhookNotifier) Wait(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		w.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// deliver posts body to the webhook URL
+func (w *WebhookNotifier) deliver(ctx context.Context, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, DefaultWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-invoice-webhook")
+	if w.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %s", ErrWebhookStatus, resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the X-Signature header value for body: "sha256=" followed by the hex
+// encoded HMAC-SHA256 of body keyed with secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature is the X-Signature value for body and secret,
+// for receivers checking a delivery
+func VerifySignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
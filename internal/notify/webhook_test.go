@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// recordingLogger collects logged error messages
+type recordingLogger struct {
+	mu     sync.Mutex
+	errors []string
+}
+
+func (l *recordingLogger) Debug(_ string, _ ...any) {}
+
+func (l *recordingLogger) Error(msg string, _ ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errors = append(l.errors, msg)
+}
+
+func (l *recordingLogger) Errors() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.errors...)
+}
+
+func TestWebhookNotifierDeliversSignedEvent(t *testing.T) {
+	type delivery struct {
+		body      []byte
+		signature string
+		mediaType string
+	}
+	received := make(chan delivery, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- delivery{body: body, signature: r.Header.Get(SignatureHeader), mediaType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	notifier := NewWebhookNotifier(server.URL, "s3cret", logger)
+	invoice := &models.Invoice{ID: "inv-1", Number: "INV-001", Status: models.StatusPaid}
+	notifier.Notify(t.Context(), NewStatusChangeEvent(invoice, models.StatusSent))
+	notifier.Wait(t.Context())
+
+	got := <-received
+	assert.Equal(t, "application/json", got.mediaType)
+	assert.True(t, VerifySignature("s3cret", got.body, got.signature))
+	assert.False(t, VerifySignature("other", got.body, got.signature))
+
+	var event Event
+	require.NoError(t, json.Unmarshal(got.body, &event))
+	assert.Equal(t, "invoice.paid", event.Type)
+	assert.Equal(t, models.InvoiceID("inv-1"), event.InvoiceID)
+	assert.Equal(t, models.StatusSent, event.OldStatus)
+	assert.Equal(t, models.StatusPaid, event.NewStatus)
+	assert.Empty(t, logger.Errors())
+}
+
+func TestWebhookNotifierLogsFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	notifier := NewWebhookNotifier(server.URL, "", logger)
+	notifier.Notify(t.Context(), Event{Type: EventType(models.StatusOverdue)})
+	notifier.Wait(t.Context())
+
+	assert.Equal(t, []string{"webhook delivery failed"}, logger.Errors())
+}
+
+func TestWebhookNotifierDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	notifier := NewWebhookNotifier(server.URL, "", &recordingLogger{})
+
+	start := time.Now()
+	notifier.Notify(t.Context(), Event{Type: EventType(models.StatusPaid)})
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Wait gives up when its context is done
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	notifier.Wait(ctx)
+	require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256("key", "The quick brown fox jumps over the lazy dog")
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		Sign("key", []byte("The quick brown fox jumps over the lazy dog")))
+}
+
+func TestFakeNotifier(t *testing.T) {
+	fake := &FakeNotifier{}
+	fake.Notify(t.Context(), Event{Type: "invoice.sent"})
+	fake.Notify(t.Context(), Event{Type: "invoice.paid"})
+
+	events := fake.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "invoice.paid", events[1].Type)
+}
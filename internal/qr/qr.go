@@ -0,0 +1,110 @@
+// Package qr renders payment QR codes that crypto wallets can scan to pay an invoice
+package qr
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+
+	"github.com/mrz1836/go-invoice/internal/blockchain"
+)
+
+var (
+	// ErrUnsupportedNetwork is returned for a network no payment URI can be built for
+	ErrUnsupportedNetwork = errors.New("unsupported QR network")
+	// ErrEmptyAddress is returned when a payment URI is requested without an address
+	ErrEmptyAddress = errors.New("payment address is required")
+)
+
+// Network is a payment network a QR code can be rendered for
+type Network string
+
+const (
+	// NetworkUSDC renders an ERC-20 USDC transfer on Ethereum mainnet
+	NetworkUSDC Network = "usdc"
+	// NetworkBSV renders a BSV payment
+	NetworkBSV Network = "bsv"
+)
+
+// DefaultSize is the width and height of rendered QR codes in pixels
+const DefaultSize = 256
+
+// Networks lists the networks QR codes can be rendered for
+func Networks() []Network {
+	return []Network{NetworkUSDC, NetworkBSV}
+}
+
+// ParseNetwork parses a network name, ignoring case
+func ParseNetwork(name string) (Network, error) {
+	network := Network(strings.ToLower(strings.TrimSpace(name)))
+	for _, supported := range Networks() {
+		if network == supported {
+			return network, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q (use usdc or bsv)", ErrUnsupportedNetwork, name)
+}
+
+// PaymentURI returns the URI a wallet reads from the QR code. USDC uses an EIP-681 token
+// transfer and BSV a BIP-21 style bitcoin: URI. The amount is in token units; when it is not
+// positive the URI carries only the address and the payer enters the amount.
+func PaymentURI(network Network, address string, amount float64) (string, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return "", ErrEmptyAddress
+	}
+
+	switch network {
+	case NetworkUSDC:
+		params := url.Values{}
+		params.Set("address", address)
+		if amount > 0 {
+			// USDC has 6 decimals, and the transfer amount is in the token's smallest unit
+			params.Set("uint256", strconv.FormatInt(int64(math.Round(amount*1e6)), 10))
+		}
+		return fmt.Sprintf("ethereum:%s@%d/transfer?%s",
+			blockchain.USDCMainnetContract, blockchain.EtherscanMainnetChainID, params.Encode()), nil
+	case NetworkBSV:
+		uri := "bitcoin:" + address
+		if amount > 0 {
+			uri += "?amount=" + strconv.FormatFloat(math.Round(amount*1e8)/1e8, 'f', -1, 64)
+		}
+		return uri, nil
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedNetwork, network)
+}
+
+// PNG encodes content as a size by size pixel QR code image
+func PNG(content string, size int) ([]byte, error) {
+	png, err := qrcode.Encode(content, qrcode.Medium, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return png, nil
+}
+
+// DataURI returns a PNG image as a data URI, for use as an inline <img> source
+func DataURI(png []byte) string {
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+}
+
+// PaymentDataURI renders the payment URI for address and amount as a PNG QR code data URI
+func PaymentDataURI(network Network, address string, amount float64) (string, error) {
+	uri, err := PaymentURI(network, address, amount)
+	if err != nil {
+		return "", err
+	}
+
+	png, err := PNG(uri, DefaultSize)
+	if err != nil {
+		return "", err
+	}
+	return DataURI(png), nil
+}
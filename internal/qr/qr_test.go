@@ -0,0 +1,111 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUSDCAddress = "0x742d35Cc6634C0532925a3b844Bc9e7595f0bEb1"
+	testBSVAddress  = "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"
+)
+
+func TestParseNetwork(t *testing.T) {
+	network, err := ParseNetwork(" USDC ")
+	require.NoError(t, err)
+	assert.Equal(t, NetworkUSDC, network)
+
+	network, err = ParseNetwork("bsv")
+	require.NoError(t, err)
+	assert.Equal(t, NetworkBSV, network)
+
+	_, err = ParseNetwork("eth")
+	require.ErrorIs(t, err, ErrUnsupportedNetwork)
+}
+
+func TestPaymentURI(t *testing.T) {
+	tests := []struct {
+		name        string
+		network     Network
+		address     string
+		amount      float64
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "USDCWithAmount",
+			network:  NetworkUSDC,
+			address:  testUSDCAddress,
+			amount:   1500.3,
+			expected: "ethereum:0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48@1/transfer?address=" + testUSDCAddress + "&uint256=1500300000",
+		},
+		{
+			name:     "USDCWithoutAmount",
+			network:  NetworkUSDC,
+			address:  testUSDCAddress,
+			expected: "ethereum:0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48@1/transfer?address=" + testUSDCAddress,
+		},
+		{
+			name:     "BSVWithAmount",
+			network:  NetworkBSV,
+			address:  testBSVAddress,
+			amount:   1.492537313,
+			expected: "bitcoin:" + testBSVAddress + "?amount=1.49253731",
+		},
+		{
+			name:     "BSVWithoutAmount",
+			network:  NetworkBSV,
+			address:  testBSVAddress,
+			expected: "bitcoin:" + testBSVAddress,
+		},
+		{
+			name:        "EmptyAddress",
+			network:     NetworkBSV,
+			address:     " ",
+			expectedErr: ErrEmptyAddress,
+		},
+		{
+			name:        "UnsupportedNetwork",
+			network:     Network("eth"),
+			address:     testUSDCAddress,
+			expectedErr: ErrUnsupportedNetwork,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri, err := PaymentURI(tt.network, tt.address, tt.amount)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, uri)
+		})
+	}
+}
+
+func TestPaymentDataURI(t *testing.T) {
+	uri, err := PaymentDataURI(NetworkBSV, testBSVAddress, 0.5)
+	require.NoError(t, err)
+
+	encoded, ok := strings.CutPrefix(uri, "data:image/png;base64,")
+	require.True(t, ok)
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSize, img.Bounds().Dx())
+	assert.Equal(t, DefaultSize, img.Bounds().Dy())
+
+	_, err = PaymentDataURI(NetworkUSDC, "", 1)
+	require.ErrorIs(t, err, ErrEmptyAddress)
+}
@@ -15,6 +15,7 @@ import (
 	"golang.org/x/text/language"
 
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
 )
 
 // Logger defines the interface for logging operations
@@ -243,6 +244,17 @@ func (r *TemplateRenderer) ValidateTemplate(ctx context.Context, templateName st
 	return tmpl.Validate(ctx)
 }
 
+// LoadTemplateFile loads a template file into the engine under name, replacing any cached copy
+func (r *TemplateRenderer) LoadTemplateFile(ctx context.Context, name, path string) error {
+	if err := r.engine.LoadTemplate(ctx, name, path); err != nil {
+		return err
+	}
+	if err := r.cache.Delete(ctx, name); err != nil {
+		r.logger.Debug("template was not cached", "template", name, "error", err)
+	}
+	return nil
+}
+
 // ListAvailableTemplates returns the names of all available templates
 func (r *TemplateRenderer) ListAvailableTemplates(ctx context.Context) ([]string, error) {
 	select {
@@ -445,61 +457,32 @@ func (e *HTMLTemplateEngine) ClearCache(ctx context.Context) error {
 	return nil
 }
 
-// formatNumberWithCommas formats a number with comma separators for thousands
-func formatNumberWithCommas(amount float64) string {
-	// Format to 2 decimal places first
-	str := fmt.Sprintf("%.2f", amount)
-
-	// Split into integer and decimal parts
-	parts := strings.Split(str, ".")
-	intPart := parts[0]
-	decimalPart := ""
-	if len(parts) > 1 {
-		decimalPart = "." + parts[1]
-	}
-
-	// Add commas to integer part
-	if len(intPart) <= 3 {
-		return intPart + decimalPart
-	}
-
-	// Process from right to left, adding commas every 3 digits
-	// Pre-allocate result slice with estimated capacity (intPart length + potential commas)
-	result := make([]byte, 0, len(intPart)+(len(intPart)/3)+1)
-	for i, digit := range []byte(intPart) {
-		if i > 0 && (len(intPart)-i)%3 == 0 {
-			result = append(result, ',')
-		}
-		result = append(result, digit)
-	}
-
-	return string(result) + decimalPart
-}
-
 // getCurrencySymbol converts currency codes to symbols
 func getCurrencySymbol(currency string) string {
-	switch currency {
-	case "USD":
-		return "$"
-	case "EUR":
-		return "€"
-	case "GBP":
-		return "£"
-	case "CAD":
-		return "C$"
-	case "AUD":
-		return "A$"
-	default:
-		return currency // fallback to currency code
-	}
+	return money.Symbol(currency)
 }
 
 // getTemplateFunctions returns useful template functions
 func (e *HTMLTemplateEngine) getTemplateFunctions() template.FuncMap {
+	return templateFunctions()
+}
+
+// templateFunctions returns the function map available to every invoice template
+func templateFunctions() template.FuncMap {
 	return template.FuncMap{
-		"formatCurrency": func(amount float64, currency string) string {
-			symbol := getCurrencySymbol(currency)
-			return fmt.Sprintf("%s%s", symbol, formatNumberWithCommas(amount))
+		"formatCurrency": func(amount interface{}, currency string) (string, error) {
+			value, err := amountValue(amount)
+			if err != nil {
+				return "", err
+			}
+			return money.Format(value, currency), nil
+		},
+		"formatAccounting": func(amount interface{}, currency string) (string, error) {
+			value, err := amountValue(amount)
+			if err != nil {
+				return "", err
+			}
+			return money.FormatAccounting(value, currency), nil
 		},
 		"formatDate": func(t time.Time, format string) string {
 			if format == "" {
@@ -543,6 +526,27 @@ func (e *HTMLTemplateEngine) getTemplateFunctions() template.FuncMap {
 	}
 }
 
+// ErrInvalidTemplateAmount is returned when a template passes a non-numeric value to a currency function
+var ErrInvalidTemplateAmount = fmt.Errorf("amount must be a number")
+
+// amountValue converts a template amount, either a stored money.Amount or a plain number
+// such as a rate, to a float64
+func amountValue(amount interface{}) (float64, error) {
+	switch v := amount.(type) {
+	case money.Amount:
+		return v.Float64(), nil
+	case float64:
+		return v, nil
+	case *float64:
+		if v != nil {
+			return *v, nil
+		}
+	case int:
+		return float64(v), nil
+	}
+	return 0, fmt.Errorf("%w: %T", ErrInvalidTemplateAmount, amount)
+}
+
 // getMinDateFromWorkItems finds the earliest date from work items or line items
 func getMinDateFromWorkItems(workItems interface{}) time.Time {
 	switch items := workItems.(type) {
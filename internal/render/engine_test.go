@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
 )
 
 // MockLogger implements the Logger interface for testing
@@ -699,10 +700,10 @@ func (suite *RenderTestSuite) createTestInvoice() *models.Invoice {
 		Client:    client,
 		WorkItems: workItems,
 		Status:    models.StatusDraft,
-		Subtotal:  1812.50,
+		Subtotal:  money.FromFloat(1812.50),
 		TaxRate:   0.10,
-		TaxAmount: 181.25,
-		Total:     1993.75,
+		TaxAmount: money.FromFloat(181.25),
+		Total:     money.FromFloat(1993.75),
 		CreatedAt: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 		UpdatedAt: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 		Version:   1,
@@ -785,7 +786,8 @@ func TestGetCurrencySymbol(t *testing.T) {
 		{"GBP", "GBP", "£"},
 		{"CAD", "CAD", "C$"},
 		{"AUD", "AUD", "A$"},
-		{"Unknown currency", "JPY", "JPY"},
+		{"JPY", "JPY", "¥"},
+		{"Unknown currency", "XYZ", "XYZ"},
 		{"Empty string", "", ""},
 		{"Lowercase (no match)", "usd", "usd"},
 	}
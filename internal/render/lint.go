@@ -0,0 +1,329 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"text/template"
+	"text/template/parse"
+)
+
+// LintSeverity indicates how serious a template lint issue is
+type LintSeverity string
+
+const (
+	// LintSeverityError marks problems that will make generation fail
+	LintSeverityError LintSeverity = "error"
+	// LintSeverityWarning marks problems that may produce wrong or missing output
+	LintSeverityWarning LintSeverity = "warning"
+)
+
+// LintIssue describes a single problem found in a template
+type LintIssue struct {
+	Line     int          `json:"line"`
+	Column   int          `json:"column"`
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+}
+
+// String formats the issue as "line:column: severity: message"
+func (i LintIssue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+	if i.Column == 0 {
+		return fmt.Sprintf("%d: %s: %s", i.Line, i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", i.Line, i.Column, i.Severity, i.Message)
+}
+
+// LintResult contains the outcome of linting a template
+type LintResult struct {
+	Name     string      `json:"name"`
+	Issues   []LintIssue `json:"issues"`
+	Rendered bool        `json:"rendered"`
+}
+
+// HasErrors reports whether any issue has error severity
+func (r *LintResult) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == LintSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// errorLocationPattern extracts "name:line:col" or "name:line" locations from template errors
+var errorLocationPattern = regexp.MustCompile(`:(\d+)(?::(\d+))?:`)
+
+// LintTemplate parses a template, checks every field reference against the type of sample,
+// and renders the template with sample to catch runtime errors. Returned issues are sorted
+// by position. The error return is reserved for context cancellation.
+func LintTemplate(ctx context.Context, name, content string, sample interface{}) (*LintResult, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	result := &LintResult{Name: name}
+
+	tmpl, err := template.New(name).Funcs(templateFunctions()).Parse(content)
+	if err != nil {
+		result.Issues = append(result.Issues, issueFromError(LintSeverityError, err))
+		return result, nil
+	}
+
+	// Static analysis of field references
+	rootType := reflect.TypeOf(sample)
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Root == nil {
+			continue
+		}
+		l := &templateLinter{
+			tree: t.Tree,
+			vars: map[string]reflect.Type{"$": rootType},
+		}
+		l.walk(t.Root, rootType)
+		result.Issues = append(result.Issues, l.issues...)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	// Render against sample data using the same engine as generation
+	htmlTmpl, err := htmltemplate.New(name).Funcs(templateFunctions()).Parse(content)
+	if err != nil {
+		result.Issues = append(result.Issues, issueFromError(LintSeverityError, err))
+	} else if err := htmlTmpl.Execute(io.Discard, sample); err != nil {
+		result.Issues = append(result.Issues, issueFromError(LintSeverityError, err))
+	} else {
+		result.Rendered = true
+	}
+
+	sort.SliceStable(result.Issues, func(i, j int) bool {
+		if result.Issues[i].Line != result.Issues[j].Line {
+			return result.Issues[i].Line < result.Issues[j].Line
+		}
+		return result.Issues[i].Column < result.Issues[j].Column
+	})
+
+	return result, nil
+}
+
+// issueFromError converts a template parse or execution error into a lint issue
+func issueFromError(severity LintSeverity, err error) LintIssue {
+	issue := LintIssue{Severity: severity, Message: err.Error()}
+	if match := errorLocationPattern.FindStringSubmatch(err.Error()); match != nil {
+		issue.Line, _ = strconv.Atoi(match[1])
+		if match[2] != "" {
+			issue.Column, _ = strconv.Atoi(match[2])
+		}
+	}
+	return issue
+}
+
+// templateLinter walks a parsed template tree, tracking the type of dot and variables
+type templateLinter struct {
+	tree   *parse.Tree
+	vars   map[string]reflect.Type
+	issues []LintIssue
+}
+
+// warn records a warning positioned at node
+func (l *templateLinter) warn(node parse.Node, format string, args ...interface{}) {
+	issue := LintIssue{Severity: LintSeverityWarning, Message: fmt.Sprintf(format, args...)}
+	location, _ := l.tree.ErrorContext(node)
+	if match := errorLocationPattern.FindStringSubmatch(location + ":"); match != nil {
+		issue.Line, _ = strconv.Atoi(match[1])
+		if match[2] != "" {
+			issue.Column, _ = strconv.Atoi(match[2])
+		}
+	}
+	l.issues = append(l.issues, issue)
+}
+
+// walk visits node with dot set to the given type; a nil type means unknown
+func (l *templateLinter) walk(node parse.Node, dot reflect.Type) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			l.walk(child, dot)
+		}
+	case *parse.ActionNode:
+		l.declare(n.Pipe, l.pipeType(n.Pipe, dot))
+	case *parse.IfNode:
+		l.declare(n.Pipe, l.pipeType(n.Pipe, dot))
+		l.walk(n.List, dot)
+		l.walk(n.ElseList, dot)
+	case *parse.WithNode:
+		typ := l.pipeType(n.Pipe, dot)
+		l.declare(n.Pipe, typ)
+		l.walk(n.List, typ)
+		l.walk(n.ElseList, dot)
+	case *parse.RangeNode:
+		typ := l.pipeType(n.Pipe, dot)
+		key, elem := rangeTypes(typ)
+		switch len(n.Pipe.Decl) {
+		case 1:
+			l.vars[n.Pipe.Decl[0].Ident[0]] = elem
+		case 2:
+			l.vars[n.Pipe.Decl[0].Ident[0]] = key
+			l.vars[n.Pipe.Decl[1].Ident[0]] = elem
+		}
+		l.walk(n.List, elem)
+		l.walk(n.ElseList, dot)
+	case *parse.TemplateNode:
+		l.pipeType(n.Pipe, dot)
+	}
+}
+
+// declare records the type of any variables declared by pipe
+func (l *templateLinter) declare(pipe *parse.PipeNode, typ reflect.Type) {
+	if pipe == nil {
+		return
+	}
+	for _, v := range pipe.Decl {
+		l.vars[v.Ident[0]] = typ
+	}
+}
+
+// pipeType checks every command in pipe and returns the type of its result
+func (l *templateLinter) pipeType(pipe *parse.PipeNode, dot reflect.Type) reflect.Type {
+	if pipe == nil {
+		return nil
+	}
+	var typ reflect.Type
+	for _, cmd := range pipe.Cmds {
+		typ = l.commandType(cmd, dot)
+	}
+	return typ
+}
+
+// commandType checks the arguments of cmd and returns the type of its result
+func (l *templateLinter) commandType(cmd *parse.CommandNode, dot reflect.Type) reflect.Type {
+	var typ reflect.Type
+	for i, arg := range cmd.Args {
+		argType := l.nodeType(arg, dot)
+		if i == 0 {
+			typ = argType
+		}
+	}
+	return typ
+}
+
+// nodeType resolves the type produced by an argument node, recording unknown references
+func (l *templateLinter) nodeType(node parse.Node, dot reflect.Type) reflect.Type {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		return l.resolve(node, dot, n.Ident)
+	case *parse.VariableNode:
+		base, ok := l.vars[n.Ident[0]]
+		if !ok {
+			return nil
+		}
+		return l.resolve(node, base, n.Ident[1:])
+	case *parse.ChainNode:
+		return l.resolve(node, l.nodeType(n.Node, dot), n.Field)
+	case *parse.PipeNode:
+		return l.pipeType(n, dot)
+	case *parse.IdentifierNode:
+		if fn, ok := templateFunctions()[n.Ident]; ok {
+			fnType := reflect.TypeOf(fn)
+			if fnType.NumOut() > 0 {
+				return fnType.Out(0)
+			}
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// resolve follows a chain of field or method names from typ, warning on the first name
+// that does not exist. It returns nil once the type can no longer be determined.
+func (l *templateLinter) resolve(node parse.Node, typ reflect.Type, names []string) reflect.Type {
+	for _, name := range names {
+		if typ == nil {
+			return nil
+		}
+
+		if method, ok := lookupMethod(typ, name); ok {
+			if method.Type.NumOut() == 0 {
+				return nil
+			}
+			typ = method.Type.Out(0)
+			continue
+		}
+
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		switch typ.Kind() {
+		case reflect.Struct:
+			field, ok := typ.FieldByName(name)
+			if !ok || !field.IsExported() {
+				l.warn(node, "unknown field %q on %s", name, typ.String())
+				return nil
+			}
+			typ = field.Type
+		case reflect.Map:
+			typ = typ.Elem()
+		case reflect.Interface:
+			return nil
+		default:
+			l.warn(node, "cannot access field %q on non-struct type %s", name, typ.String())
+			return nil
+		}
+	}
+	return typ
+}
+
+// lookupMethod finds an exported method on typ or on a pointer to typ
+func lookupMethod(typ reflect.Type, name string) (reflect.Method, bool) {
+	if typ.Kind() == reflect.Interface {
+		return reflect.Method{}, false
+	}
+	if method, ok := typ.MethodByName(name); ok {
+		return method, true
+	}
+	if typ.Kind() != reflect.Ptr {
+		return reflect.PointerTo(typ).MethodByName(name)
+	}
+	return reflect.Method{}, false
+}
+
+// rangeTypes returns the key and element types produced by ranging over typ
+func rangeTypes(typ reflect.Type) (reflect.Type, reflect.Type) {
+	if typ == nil {
+		return nil, nil
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		return reflect.TypeOf(0), typ.Elem()
+	case reflect.Map:
+		return typ.Key(), typ.Elem()
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		return nil, typ
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,105 @@
+package render
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+type lintTestData struct {
+	models.Invoice
+
+	Currency string
+}
+
+func newLintTestData() *lintTestData {
+	hours, rate := 2.0, 100.0
+	return &lintTestData{
+		Invoice: models.Invoice{
+			Number:  "INV-001",
+			Date:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Client:  models.Client{Name: "Acme"},
+			Total:   money.FromFloat(200),
+			Status:  models.StatusDraft,
+			DueDate: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+			LineItems: []models.LineItem{
+				{ID: "1", Type: models.LineItemTypeHourly, Hours: &hours, Rate: &rate, Total: money.FromFloat(200)},
+			},
+		},
+		Currency: "USD",
+	}
+}
+
+func TestLintTemplate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ValidTemplate", func(t *testing.T) {
+		content := `<h1>{{.Number}} {{.Client.Name}}</h1>
+{{$currency := .Currency}}{{range .LineItems}}<p>{{.Description}} {{formatCurrency .Total $currency}}</p>{{end}}
+{{with .Client}}{{.Email}}{{end}}
+{{.Invoice.GetUSDCAddress "0xabc"}} {{formatDate .DueDate "2006-01-02"}} {{.Status | upper}}`
+
+		result, err := LintTemplate(ctx, "valid.html", content, newLintTestData())
+		require.NoError(t, err)
+		assert.Empty(t, result.Issues)
+		assert.True(t, result.Rendered)
+		assert.False(t, result.HasErrors())
+	})
+
+	t.Run("UnknownFieldsReportLineNumbers", func(t *testing.T) {
+		content := "<h1>{{.Number}}</h1>\n{{if false}}{{.Client.Nmae}}{{end}}\n{{range .WorkItems}}{{.Cost}}{{end}}"
+
+		result, err := LintTemplate(ctx, "fields.html", content, newLintTestData())
+		require.NoError(t, err)
+		require.Len(t, result.Issues, 2)
+
+		assert.Equal(t, 2, result.Issues[0].Line)
+		assert.Equal(t, LintSeverityWarning, result.Issues[0].Severity)
+		assert.Contains(t, result.Issues[0].Message, `"Nmae"`)
+
+		assert.Equal(t, 3, result.Issues[1].Line)
+		assert.Contains(t, result.Issues[1].Message, `"Cost"`)
+		assert.Contains(t, result.Issues[1].Message, "models.WorkItem")
+
+		assert.False(t, result.HasErrors())
+		assert.True(t, result.Rendered)
+	})
+
+	t.Run("ParseError", func(t *testing.T) {
+		result, err := LintTemplate(ctx, "broken.html", "<p>\n{{if .Number}}\n", newLintTestData())
+		require.NoError(t, err)
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, LintSeverityError, result.Issues[0].Severity)
+		assert.Equal(t, 3, result.Issues[0].Line)
+		assert.True(t, result.HasErrors())
+		assert.False(t, result.Rendered)
+	})
+
+	t.Run("RuntimeError", func(t *testing.T) {
+		result, err := LintTemplate(ctx, "runtime.html", "<p>\n{{formatCurrency .Number .Currency}}</p>", newLintTestData())
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Issues)
+		assert.True(t, result.HasErrors())
+		assert.Equal(t, 2, result.Issues[len(result.Issues)-1].Line)
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := LintTemplate(canceled, "any.html", "{{.Number}}", newLintTestData())
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestLintIssueString(t *testing.T) {
+	assert.Equal(t, "3:7: warning: oops", LintIssue{Line: 3, Column: 7, Severity: LintSeverityWarning, Message: "oops"}.String())
+	assert.Equal(t, "3: error: oops", LintIssue{Line: 3, Severity: LintSeverityError, Message: "oops"}.String())
+	assert.Equal(t, "error: oops", LintIssue{Severity: LintSeverityError, Message: "oops"}.String())
+}
@@ -0,0 +1,120 @@
+package render
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// Render styles control how invoice items are presented in generated output
+const (
+	// RenderStyleDetailed renders every line item individually
+	RenderStyleDetailed = "detailed"
+	// RenderStyleSummarized groups hourly items that share a rate into rate bands
+	RenderStyleSummarized = "summarized"
+)
+
+// rateBandDescription is used when the items in a band have different descriptions
+const rateBandDescription = "Professional services"
+
+// GroupLineItemsByRate collapses hourly line items that share the same rate into a single
+// rate band, summing hours and totals. Fixed and quantity items are passed through unchanged.
+// Bands keep the position of the first item at that rate. The returned slice is a new copy
+// intended for presentation only; the input is not modified.
+func GroupLineItemsByRate(items []models.LineItem) []models.LineItem {
+	result := make([]models.LineItem, 0, len(items))
+	bandIndex := make(map[float64]int)
+
+	for _, item := range items {
+		if item.Type != models.LineItemTypeHourly || item.Hours == nil || item.Rate == nil {
+			result = append(result, item)
+			continue
+		}
+
+		rate := *item.Rate
+		idx, exists := bandIndex[rate]
+		if !exists {
+			hours := 0.0
+			bandRate := rate
+			bandIndex[rate] = len(result)
+			result = append(result, models.LineItem{
+				ID:          "band-" + formatBandRate(rate),
+				Type:        models.LineItemTypeHourly,
+				Date:        item.Date,
+				Description: item.Description,
+				Hours:       &hours,
+				Rate:        &bandRate,
+				CreatedAt:   item.CreatedAt,
+			})
+			idx = len(result) - 1
+		}
+
+		band := &result[idx]
+		*band.Hours += *item.Hours
+		band.Total += item.Total
+		if band.Description != item.Description {
+			band.Description = rateBandDescription
+		}
+
+		// Widen the band's date range to cover this item
+		itemEnd := item.Date
+		if item.EndDate != nil {
+			itemEnd = *item.EndDate
+		}
+		if item.Date.Before(band.Date) {
+			band.Date = item.Date
+		}
+		if itemEnd.After(band.Date) && (band.EndDate == nil || itemEnd.After(*band.EndDate)) {
+			end := itemEnd
+			band.EndDate = &end
+		}
+	}
+
+	return result
+}
+
+// GroupWorkItemsByRate collapses legacy work items that share the same rate into a single
+// rate band. Bands keep the position of the first item at that rate. The returned slice is a
+// new copy intended for presentation only; the input is not modified.
+func GroupWorkItemsByRate(items []models.WorkItem) []models.WorkItem {
+	result := make([]models.WorkItem, 0, len(items))
+	bandIndex := make(map[float64]int)
+
+	for _, item := range items {
+		idx, exists := bandIndex[item.Rate]
+		if !exists {
+			bandIndex[item.Rate] = len(result)
+			result = append(result, models.WorkItem{
+				ID:          "band-" + formatBandRate(item.Rate),
+				Date:        item.Date,
+				Rate:        item.Rate,
+				Description: item.Description,
+				CreatedAt:   item.CreatedAt,
+			})
+			idx = len(result) - 1
+		}
+
+		band := &result[idx]
+		band.Hours += item.Hours
+		band.Total = roundCents(band.Total + item.Total)
+		if band.Description != item.Description {
+			band.Description = rateBandDescription
+		}
+		if item.Date.Before(band.Date) {
+			band.Date = item.Date
+		}
+	}
+
+	return result
+}
+
+// formatBandRate formats a rate for use in a band identifier
+func formatBandRate(rate float64) string {
+	return fmt.Sprintf("%.2f", rate)
+}
+
+// roundCents rounds an amount to two decimal places
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
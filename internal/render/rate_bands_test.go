@@ -0,0 +1,119 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func hourlyItem(id string, day int, hours, rate float64, description string) models.LineItem {
+	return models.LineItem{
+		ID:          id,
+		Type:        models.LineItemTypeHourly,
+		Date:        time.Date(2024, 3, day, 0, 0, 0, 0, time.UTC),
+		Description: description,
+		Hours:       &hours,
+		Rate:        &rate,
+		Total:       money.Product(hours, rate),
+	}
+}
+
+func sumLineItems(items []models.LineItem) money.Amount {
+	var total money.Amount
+	for _, item := range items {
+		total += item.Total
+	}
+	return total
+}
+
+func TestGroupLineItemsByRate(t *testing.T) {
+	amount := 500.0
+	fixed := models.LineItem{
+		ID:          "fixed-1",
+		Type:        models.LineItemTypeFixed,
+		Date:        time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Description: "Monthly retainer",
+		Amount:      &amount,
+		Total:       money.FromFloat(amount),
+	}
+
+	items := []models.LineItem{
+		hourlyItem("h1", 4, 8, 125, "Development"),
+		fixed,
+		hourlyItem("h2", 2, 7.5, 125, "Development"),
+		hourlyItem("h3", 5, 3.25, 95, "Code review"),
+		hourlyItem("h4", 6, 0.33, 125, "Development"),
+		hourlyItem("h5", 7, 1.5, 95, "Documentation"),
+	}
+
+	grouped := GroupLineItemsByRate(items)
+
+	t.Run("TotalsMatchDetailed", func(t *testing.T) {
+		assert.Equal(t, sumLineItems(items), sumLineItems(grouped))
+	})
+
+	t.Run("BandsKeepFirstPosition", func(t *testing.T) {
+		require.Len(t, grouped, 3)
+		assert.Equal(t, "band-125.00", grouped[0].ID)
+		assert.Equal(t, "fixed-1", grouped[1].ID)
+		assert.Equal(t, "band-95.00", grouped[2].ID)
+	})
+
+	t.Run("HoursSummedPerRate", func(t *testing.T) {
+		require.NotNil(t, grouped[0].Hours)
+		assert.InDelta(t, 15.83, *grouped[0].Hours, 0.0001)
+		assert.InDelta(t, 1978.75, grouped[0].Total.Float64(), 0.001)
+		assert.Equal(t, "Development", grouped[0].Description)
+	})
+
+	t.Run("MixedDescriptionsUseGenericLabel", func(t *testing.T) {
+		assert.Equal(t, rateBandDescription, grouped[2].Description)
+		assert.InDelta(t, 4.75, *grouped[2].Hours, 0.0001)
+	})
+
+	t.Run("DateRangeCoversBand", func(t *testing.T) {
+		assert.Equal(t, 2, grouped[0].Date.Day())
+		require.NotNil(t, grouped[0].EndDate)
+		assert.Equal(t, 6, grouped[0].EndDate.Day())
+	})
+
+	t.Run("InputIsNotModified", func(t *testing.T) {
+		require.Len(t, items, 6)
+		assert.InDelta(t, 8.0, *items[0].Hours, 0.0001)
+		assert.Equal(t, "h1", items[0].ID)
+	})
+}
+
+func TestGroupLineItemsByRate_Empty(t *testing.T) {
+	assert.Empty(t, GroupLineItemsByRate(nil))
+}
+
+func TestGroupWorkItemsByRate(t *testing.T) {
+	items := []models.WorkItem{
+		{ID: "w1", Date: time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), Hours: 4, Rate: 150, Description: "Consulting", Total: 600},
+		{ID: "w2", Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Hours: 2.5, Rate: 100, Description: "Support", Total: 250},
+		{ID: "w3", Date: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Hours: 1.25, Rate: 150, Description: "Consulting", Total: 187.5},
+	}
+
+	grouped := GroupWorkItemsByRate(items)
+
+	require.Len(t, grouped, 2)
+	assert.InDelta(t, 5.25, grouped[0].Hours, 0.0001)
+	assert.InDelta(t, 787.5, grouped[0].Total, 0.001)
+	assert.Equal(t, 2, grouped[0].Date.Day())
+	assert.Equal(t, "Consulting", grouped[0].Description)
+
+	detailedTotal, groupedTotal := 0.0, 0.0
+	for _, item := range items {
+		detailedTotal += item.Total
+	}
+	for _, item := range grouped {
+		groupedTotal += item.Total
+	}
+	assert.InDelta(t, detailedTotal, groupedTotal, 0.001)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
 )
 
 // MockTemplateCache implements TemplateCache for testing
@@ -468,10 +469,10 @@ func (suite *TemplateRendererTestSuite) createTestInvoice() *models.Invoice {
 		Client:    client,
 		WorkItems: workItems,
 		Status:    models.StatusDraft,
-		Subtotal:  1000.00,
+		Subtotal:  money.FromFloat(1000.00),
 		TaxRate:   0.10,
-		TaxAmount: 100.00,
-		Total:     1993.75, // intentionally different from subtotal+tax for testing
+		TaxAmount: money.FromFloat(100.00),
+		Total:     money.FromFloat(1993.75), // intentionally different from subtotal+tax for testing
 		CreatedAt: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 		UpdatedAt: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 		Version:   1,
@@ -0,0 +1,206 @@
+package render
+
+import (
+	"cmp"
+	"html/template"
+	"slices"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// defaultTemplateDateFormat is the date layout exposed to templates as .Config.DateFormat
+const defaultTemplateDateFormat = "January 2, 2006"
+
+// TemplateData is the data passed to invoice templates.
+//
+// It is the stable contract for custom templates: the field names below are kept
+// even when the storage models change. The invoice fields are promoted, so a
+// template reads {{.Number}} or {{.Total}}, while the parties and settings live
+// under {{.Client}}, {{.Business}} and {{.Config}}.
+type TemplateData struct {
+	InvoiceView
+
+	Client      ClientView   `json:"client"`
+	Business    BusinessView `json:"business"`
+	Config      ConfigView   `json:"config"`
+	TotalHours  float64      `json:"total_hours"`  // Hours across hourly line items and work items
+	RenderStyle string       `json:"render_style"` // RenderStyleDetailed or RenderStyleSummarized
+
+	// Payment QR codes as PNG data URIs, set only when generating with --format qr
+	USDCQRCode template.URL `json:"usdc_qr_code,omitempty"`
+	BSVQRCode  template.URL `json:"bsv_qr_code,omitempty"`
+}
+
+// InvoiceView holds the invoice fields available to templates
+type InvoiceView struct {
+	ID              string              `json:"id"`
+	Number          string              `json:"number"`
+	Status          string              `json:"status"`
+	Date            time.Time           `json:"date"`
+	DueDate         time.Time           `json:"due_date"`
+	Description     string              `json:"description"`
+	Notes           string              `json:"notes"`
+	PONumber        string              `json:"po_number"`
+	ClientReference string              `json:"client_reference"`
+	Currency        string              `json:"currency"` // ISO 4217 code, falling back to the configured currency
+	LineItems       []models.LineItem   `json:"line_items"`
+	WorkItems       []models.WorkItem   `json:"work_items"`
+	Subtotal        money.Amount        `json:"subtotal"`
+	DiscountPercent float64             `json:"discount_percent"`
+	DiscountTotal   money.Amount        `json:"discount_total"`
+	CryptoFee       money.Amount        `json:"crypto_fee"`
+	CryptoQuote     *models.CryptoQuote `json:"crypto_quote,omitempty"` // Amount due in the client's crypto currency, when quoted
+	TaxRate         float64             `json:"tax_rate"`
+	TaxAmount       money.Amount        `json:"tax_amount"`
+	Total           money.Amount        `json:"total"`
+	USDCAddress     string              `json:"usdc_address"` // Invoice override, or the business address
+	BSVAddress      string              `json:"bsv_address"`  // Invoice override, or the business address
+}
+
+// ClientView holds the client fields available to templates
+type ClientView struct {
+	Name             string `json:"name"`
+	Email            string `json:"email"`
+	Phone            string `json:"phone"`
+	Address          string `json:"address"`
+	TaxID            string `json:"tax_id"`
+	TaxExempt        bool   `json:"tax_exempt"`
+	ExemptionReason  string `json:"exemption_reason"`
+	ApproverContacts string `json:"approver_contacts"`
+	LateFeeEnabled   bool   `json:"late_fee_enabled"`
+}
+
+// BusinessView holds the sender's business details available to templates
+type BusinessView struct {
+	Name           string                `json:"name"`
+	Address        string                `json:"address"`
+	Phone          string                `json:"phone"`
+	Email          string                `json:"email"`
+	Website        string                `json:"website"`
+	Logo           template.URL          `json:"logo,omitempty"` // Image source for the header logo, set when generating
+	TaxID          string                `json:"tax_id"`
+	PaymentTerms   string                `json:"payment_terms"`
+	BankDetails    config.BankDetails    `json:"bank_details"`
+	CryptoPayments config.CryptoPayments `json:"crypto_payments"`
+}
+
+// ConfigView holds the formatting settings available to templates
+type ConfigView struct {
+	Currency       string `json:"currency"`
+	CurrencySymbol string `json:"currency_symbol"`
+	DateFormat     string `json:"date_format"`
+	DecimalPlaces  int    `json:"decimal_places"`
+}
+
+// BuildTemplateData builds the template data for an invoice.
+//
+// The client is usually the current client record; when nil, the client snapshot
+// stored on the invoice is used. Item slices are copied, so the result can be
+// reshaped for presentation (see GroupLineItemsByRate) without touching the invoice.
+func BuildTemplateData(cfg *config.Config, invoice *models.Invoice, client *models.Client) *TemplateData {
+	if client == nil {
+		client = &invoice.Client
+	}
+	currency := invoice.GetCurrency(cfg.Invoice.Currency)
+
+	return &TemplateData{
+		InvoiceView: InvoiceView{
+			ID:              string(invoice.ID),
+			Number:          invoice.Number,
+			Status:          invoice.Status,
+			Date:            invoice.Date,
+			DueDate:         invoice.DueDate,
+			Description:     invoice.Description,
+			Notes:           invoice.Notes,
+			PONumber:        invoice.PONumber,
+			ClientReference: invoice.ClientReference,
+			Currency:        currency,
+			LineItems:       slices.Clone(invoice.LineItems),
+			WorkItems:       slices.Clone(invoice.WorkItems),
+			Subtotal:        invoice.Subtotal,
+			DiscountPercent: invoice.DiscountPercent,
+			DiscountTotal:   invoice.DiscountTotal,
+			CryptoFee:       invoice.CryptoFee,
+			CryptoQuote:     invoice.CryptoQuote,
+			TaxRate:         invoice.TaxRate,
+			TaxAmount:       invoice.TaxAmount,
+			Total:           invoice.Total,
+			USDCAddress:     invoice.GetUSDCAddress(cfg.Business.CryptoPayments.USDCAddress),
+			BSVAddress:      invoice.GetBSVAddress(cfg.Business.CryptoPayments.BSVAddress),
+		},
+		Client: ClientView{
+			Name:             client.Name,
+			Email:            client.Email,
+			Phone:            client.Phone,
+			Address:          client.Address,
+			TaxID:            client.TaxID,
+			TaxExempt:        client.TaxExempt,
+			ExemptionReason:  client.ExemptionReason,
+			ApproverContacts: client.ApproverContacts,
+			LateFeeEnabled:   client.LateFeeEnabled,
+		},
+		Business: BusinessView{
+			Name:           cfg.Business.Name,
+			Address:        cfg.Business.Address,
+			Phone:          cfg.Business.Phone,
+			Email:          cfg.Business.Email,
+			Website:        cfg.Business.Website,
+			TaxID:          cfg.Business.TaxID,
+			PaymentTerms:   cfg.Business.PaymentTerms,
+			BankDetails:    cfg.Business.BankDetails,
+			CryptoPayments: cfg.Business.CryptoPayments,
+		},
+		Config: ConfigView{
+			Currency:       currency,
+			CurrencySymbol: money.Symbol(currency),
+			DateFormat:     templateDateFormat(cfg),
+			DecimalPlaces:  2,
+		},
+		TotalHours:  totalHours(invoice),
+		RenderStyle: RenderStyleDetailed,
+	}
+}
+
+// templateDateFormat returns the configured DATE_FORMAT layout, or the long default
+// when none is configured
+func templateDateFormat(cfg *config.Config) string {
+	if cfg.Invoice.DateFormat == "" {
+		return defaultTemplateDateFormat
+	}
+	return cfg.Invoice.DateLayout()
+}
+
+// Invoice returns the invoice view. It keeps templates written against the earlier
+// data layout, such as {{.Invoice.GetUSDCAddress ...}}, working.
+func (d *TemplateData) Invoice() *InvoiceView {
+	return &d.InvoiceView
+}
+
+// GetUSDCAddress returns the invoice's USDC address, or defaultAddress when none is set.
+// Prefer {{.USDCAddress}} in new templates.
+func (v *InvoiceView) GetUSDCAddress(defaultAddress string) string {
+	return cmp.Or(v.USDCAddress, defaultAddress)
+}
+
+// GetBSVAddress returns the invoice's BSV address, or defaultAddress when none is set.
+// Prefer {{.BSVAddress}} in new templates.
+func (v *InvoiceView) GetBSVAddress(defaultAddress string) string {
+	return cmp.Or(v.BSVAddress, defaultAddress)
+}
+
+// totalHours sums the hours of legacy work items and hourly line items
+func totalHours(invoice *models.Invoice) float64 {
+	total := 0.0
+	for _, item := range invoice.WorkItems {
+		total += item.Hours
+	}
+	for _, item := range invoice.LineItems {
+		if item.Type == models.LineItemTypeHourly && item.Hours != nil {
+			total += *item.Hours
+		}
+	}
+	return total
+}
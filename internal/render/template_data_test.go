@@ -0,0 +1,180 @@
+package render
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/config"
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/templates"
+)
+
+func newTemplateDataFixtures() (*config.Config, *models.Invoice) {
+	cfg := &config.Config{
+		Business: config.BusinessConfig{
+			Name:         "Test Business",
+			Address:      "123 Test St",
+			Email:        "billing@test.example",
+			PaymentTerms: "Net 30",
+			BankDetails:  config.BankDetails{Name: "Test Bank", AccountNumber: "123", ACHEnabled: true},
+			CryptoPayments: config.CryptoPayments{
+				USDCEnabled: true,
+				USDCAddress: "0xbusiness",
+				BSVEnabled:  true,
+				BSVAddress:  "1business",
+			},
+		},
+		Invoice: config.InvoiceConfig{Currency: "USD"},
+	}
+
+	hours, rate := 2.0, 100.0
+	amount := 50.0
+	invoice := &models.Invoice{
+		ID:        "inv_1",
+		Number:    "INV-001",
+		Status:    models.StatusSent,
+		Date:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		DueDate:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		Client:    models.Client{ID: "client_1", Name: "Snapshot Name", Email: "old@client.example"},
+		Notes:     "Thanks!",
+		PONumber:  "PO-7",
+		Subtotal:  money.FromFloat(250),
+		TaxRate:   0.1,
+		TaxAmount: money.FromFloat(25),
+		Total:     money.FromFloat(275),
+		LineItems: []models.LineItem{
+			{ID: "1", Type: models.LineItemTypeHourly, Description: "Work", Hours: &hours, Rate: &rate, Total: money.FromFloat(200)},
+			{ID: "2", Type: models.LineItemTypeFixed, Description: "Setup", Amount: &amount, Total: money.FromFloat(50)},
+		},
+		WorkItems: []models.WorkItem{{ID: "w1", Hours: 1.5, Rate: 100, Description: "Legacy", Total: 150}},
+	}
+	return cfg, invoice
+}
+
+func TestBuildTemplateData(t *testing.T) {
+	t.Run("CopiesInvoiceAndConfig", func(t *testing.T) {
+		cfg, invoice := newTemplateDataFixtures()
+
+		data := BuildTemplateData(cfg, invoice, nil)
+
+		assert.Equal(t, "INV-001", data.Number)
+		assert.Equal(t, "PO-7", data.PONumber)
+		assert.Equal(t, "Snapshot Name", data.Client.Name)
+		assert.Equal(t, "Test Business", data.Business.Name)
+		assert.Equal(t, "USD", data.Config.Currency)
+		assert.Equal(t, "$", data.Config.CurrencySymbol)
+		assert.Equal(t, RenderStyleDetailed, data.RenderStyle)
+		assert.InDelta(t, 3.5, data.TotalHours, 0.001)
+		assert.Equal(t, "0xbusiness", data.USDCAddress)
+		assert.Equal(t, "1business", data.BSVAddress)
+	})
+
+	t.Run("ClientOverridesSnapshot", func(t *testing.T) {
+		cfg, invoice := newTemplateDataFixtures()
+		client := &models.Client{Name: "Current Name", Email: "new@client.example", TaxExempt: true}
+
+		data := BuildTemplateData(cfg, invoice, client)
+
+		assert.Equal(t, "Current Name", data.Client.Name)
+		assert.Equal(t, "new@client.example", data.Client.Email)
+		assert.True(t, data.Client.TaxExempt)
+	})
+
+	t.Run("InvoiceAddressOverrides", func(t *testing.T) {
+		cfg, invoice := newTemplateDataFixtures()
+		usdc, bsv := "0xinvoice", "1invoice"
+		invoice.USDCAddressOverride = &usdc
+		invoice.BSVAddressOverride = &bsv
+
+		data := BuildTemplateData(cfg, invoice, nil)
+
+		assert.Equal(t, usdc, data.USDCAddress)
+		assert.Equal(t, bsv, data.BSVAddress)
+		assert.Equal(t, usdc, data.Invoice().GetUSDCAddress(cfg.Business.CryptoPayments.USDCAddress))
+	})
+
+	t.Run("DateFormat", func(t *testing.T) {
+		cfg, invoice := newTemplateDataFixtures()
+		assert.Equal(t, "January 2, 2006", BuildTemplateData(cfg, invoice, nil).Config.DateFormat)
+
+		cfg.Invoice.DateFormat = "DD.MM.YYYY"
+		data := BuildTemplateData(cfg, invoice, nil)
+		assert.Equal(t, "02.01.2006", data.Config.DateFormat)
+
+		ctx := context.Background()
+		engine := NewHTMLTemplateEngine(NewMockFileReader(), &MockLogger{})
+		require.NoError(t, engine.ParseTemplateString(ctx, "default", templates.DefaultInvoiceTemplate))
+		tmpl, err := engine.GetTemplate(ctx, "default")
+		require.NoError(t, err)
+		html, err := tmpl.ExecuteToString(ctx, data)
+		require.NoError(t, err)
+		assert.Contains(t, html, "<strong>Date:</strong> 01.01.2024")
+		assert.Contains(t, html, "<strong>Due Date:</strong> 31.01.2024")
+	})
+
+	t.Run("ItemsAreCopied", func(t *testing.T) {
+		cfg, invoice := newTemplateDataFixtures()
+
+		data := BuildTemplateData(cfg, invoice, nil)
+		data.LineItems[0].Description = "Changed"
+		data.LineItems = GroupLineItemsByRate(data.LineItems)
+
+		assert.Equal(t, "Work", invoice.LineItems[0].Description)
+		assert.Len(t, invoice.LineItems, 2)
+	})
+}
+
+func TestDefaultTemplateWithTemplateData(t *testing.T) {
+	cfg, invoice := newTemplateDataFixtures()
+	data := BuildTemplateData(cfg, invoice, nil)
+
+	result, err := LintTemplate(context.Background(), "default", templates.DefaultInvoiceTemplate, data)
+	require.NoError(t, err)
+	assert.Empty(t, result.Issues)
+	assert.True(t, result.Rendered)
+
+	t.Run("LegacyAddressLookup", func(t *testing.T) {
+		content := `{{.Invoice.GetUSDCAddress .Business.CryptoPayments.USDCAddress}} {{.Invoice.Number}}`
+
+		result, err := LintTemplate(context.Background(), "legacy.html", content, data)
+		require.NoError(t, err)
+		assert.Empty(t, result.Issues)
+		assert.True(t, result.Rendered)
+	})
+
+	t.Run("CryptoQuote", func(t *testing.T) {
+		ctx := context.Background()
+		asOf := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+		require.NoError(t, invoice.SetCryptoQuote("USDC", "USD", 1.0002, asOf, "mock"))
+
+		engine := NewHTMLTemplateEngine(NewMockFileReader(), &MockLogger{})
+		require.NoError(t, engine.ParseTemplateString(ctx, "default", templates.DefaultInvoiceTemplate))
+		tmpl, err := engine.GetTemplate(ctx, "default")
+		require.NoError(t, err)
+
+		html, err := tmpl.ExecuteToString(ctx, BuildTemplateData(cfg, invoice, nil))
+		require.NoError(t, err)
+		assert.Contains(t, html, "Pay 275.055 USDC (rate 1.0002 USDC per USD as of January 1, 2024 09:30 UTC)")
+		assert.NotContains(t, html, "BSV (rate")
+		assert.NotContains(t, html, "payment-qr\"")
+
+		qrData := BuildTemplateData(cfg, invoice, nil)
+		qrData.BSVQRCode = "data:image/png;base64,iVBORw0KGgo="
+		html, err = tmpl.ExecuteToString(ctx, qrData)
+		require.NoError(t, err)
+		assert.Contains(t, html, `<img class="payment-qr" src="data:image/png;base64,iVBORw0KGgo=" alt="BSV payment QR code"`)
+		assert.NotContains(t, html, "USDC payment QR code")
+		assert.NotContains(t, html, `class="company-logo"`)
+
+		logoData := BuildTemplateData(cfg, invoice, nil)
+		logoData.Business.Logo = "data:image/png;base64,iVBORw0KGgo="
+		html, err = tmpl.ExecuteToString(ctx, logoData)
+		require.NoError(t, err)
+		assert.Contains(t, html, `<img class="company-logo" src="data:image/png;base64,iVBORw0KGgo="`)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
 )
 
 const (
@@ -134,7 +135,7 @@ func (c *InvoiceCalculator) CalculateInvoiceTotals(ctx context.Context, invoice
 
 	// Add line items to subtotal
 	for _, lineItem := range invoice.LineItems {
-		subtotal += lineItem.Total
+		subtotal += lineItem.Total.Float64()
 	}
 
 	// Calculate tax
@@ -303,10 +304,10 @@ func (c *InvoiceCalculator) RecalculateInvoice(ctx context.Context, invoice *mod
 	}
 
 	// Update invoice with calculated values
-	invoice.Subtotal = result.Subtotal
+	invoice.Subtotal = money.FromFloat(result.Subtotal)
 	invoice.TaxRate = taxRate
-	invoice.TaxAmount = result.TaxAmount
-	invoice.Total = result.Total
+	invoice.TaxAmount = money.FromFloat(result.TaxAmount)
+	invoice.Total = money.FromFloat(result.Total)
 	invoice.UpdatedAt = time.Now()
 	invoice.Version++
 
@@ -333,7 +334,8 @@ func (c *InvoiceCalculator) GetCalculationSummary(ctx context.Context, invoices
 		CalculatedAt: time.Now(),
 	}
 
-	var totalSubtotal, totalTax, totalAmount, totalHours float64
+	var totalSubtotal, totalTax, totalAmount money.Amount
+	var totalHours float64
 
 	for _, invoice := range invoices {
 		totalSubtotal += invoice.Subtotal
@@ -345,17 +347,17 @@ func (c *InvoiceCalculator) GetCalculationSummary(ctx context.Context, invoices
 		}
 	}
 
-	summary.TotalSubtotal = c.roundAmount(totalSubtotal, &CalculationOptions{DecimalPlaces: 2})
-	summary.TotalTax = c.roundAmount(totalTax, &CalculationOptions{DecimalPlaces: 2})
-	summary.TotalAmount = c.roundAmount(totalAmount, &CalculationOptions{DecimalPlaces: 2})
+	summary.TotalSubtotal = totalSubtotal.Float64()
+	summary.TotalTax = totalTax.Float64()
+	summary.TotalAmount = totalAmount.Float64()
 	summary.TotalHours = c.roundAmount(totalHours, &CalculationOptions{DecimalPlaces: 2})
 
 	if totalHours > 0 {
-		summary.AverageRate = c.roundAmount(totalSubtotal/totalHours, &CalculationOptions{DecimalPlaces: 2})
+		summary.AverageRate = c.roundAmount(summary.TotalSubtotal/totalHours, &CalculationOptions{DecimalPlaces: 2})
 	}
 
 	if len(invoices) > 0 {
-		summary.AverageInvoiceAmount = c.roundAmount(totalAmount/float64(len(invoices)), &CalculationOptions{DecimalPlaces: 2})
+		summary.AverageInvoiceAmount = c.roundAmount(summary.TotalAmount/float64(len(invoices)), &CalculationOptions{DecimalPlaces: 2})
 	}
 
 	c.logger.Info("calculation summary completed", "invoices", len(invoices), "total", summary.TotalAmount)
@@ -430,24 +432,7 @@ func (c *InvoiceCalculator) roundAmount(amount float64, options *CalculationOpti
 
 // getCurrencySymbol returns the symbol for a currency code
 func (c *InvoiceCalculator) getCurrencySymbol(currency string) string {
-	symbols := map[string]string{
-		currencyUSD: "$",
-		"EUR":       "€",
-		"GBP":       "£",
-		"CAD":       "C$",
-		"AUD":       "A$",
-		"JPY":       "¥",
-		"CHF":       "CHF",
-		"SEK":       "kr",
-		"NOK":       "kr",
-		"DKK":       "kr",
-	}
-
-	if symbol, exists := symbols[currency]; exists {
-		return symbol
-	}
-
-	return currency // Return currency code if symbol not found
+	return money.Symbol(currency)
 }
 
 // CalculationSummary represents a summary of calculations across multiple invoices
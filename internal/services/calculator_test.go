@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
 )
 
 // MockLogger implements the Logger interface for testing
@@ -340,9 +341,9 @@ func (suite *CalculatorTestSuite) TestRecalculateInvoice() {
 	err := suite.calculator.RecalculateInvoice(ctx, invoice, 0.15)
 
 	suite.Require().NoError(err)
-	suite.NotEqual(originalTotal, invoice.Total)
+	suite.NotEqual(originalTotal, invoice.Total.Float64())
 	suite.Equal(originalVersion+1, invoice.Version)
-	suite.InDelta(2659.38, invoice.Total, 0.01) // 2312.50 + (2312.50 * 0.15)
+	suite.InDelta(2659.38, invoice.Total.Float64(), 0.01) // 2312.50 + (2312.50 * 0.15)
 }
 
 // TestGetCalculationSummary tests summary calculation for multiple invoices
@@ -550,7 +551,7 @@ func (suite *CalculatorTestSuite) TestRecalculateInvoiceErrorCases() {
 		suite.Require().NoError(err)
 		// Verify that negative tax rate is stored but tax amount is 0
 		suite.InDelta(-0.10, invoice.TaxRate, 1e-9)
-		suite.InDelta(0.0, invoice.TaxAmount, 1e-9) // calculateTax returns 0 for negative rates
+		suite.InDelta(0.0, invoice.TaxAmount.Float64(), 1e-9) // calculateTax returns 0 for negative rates
 	})
 
 	suite.Run("CanceledContext", func() {
@@ -782,10 +783,10 @@ func (suite *CalculatorTestSuite) createTestInvoice() *models.Invoice {
 		Client:    client,
 		WorkItems: workItems,
 		Status:    models.StatusDraft,
-		Subtotal:  2312.50,
+		Subtotal:  money.FromFloat(2312.50),
 		TaxRate:   0.10,
-		TaxAmount: 231.25,
-		Total:     2543.75,
+		TaxAmount: money.FromFloat(231.25),
+		Total:     money.FromFloat(2543.75),
 		CreatedAt: time.Now().AddDate(0, 0, -1),
 		UpdatedAt: time.Now(),
 		Version:   1,
@@ -810,7 +811,7 @@ func (suite *CalculatorTestSuite) TestCalculateInvoiceTotalsWithLineItems() {
 					Date:        time.Now(),
 					Description: "Repository Maintenance",
 					Amount:      &amount,
-					Total:       5000.0,
+					Total:       money.FromFloat(5000.0),
 				},
 			},
 			WorkItems: []models.WorkItem{}, // No work items
@@ -849,7 +850,7 @@ func (suite *CalculatorTestSuite) TestCalculateInvoiceTotalsWithLineItems() {
 					Date:        time.Now(),
 					Description: "Repository Maintenance",
 					Amount:      &amount,
-					Total:       5000.0,
+					Total:       money.FromFloat(5000.0),
 				},
 			},
 			WorkItems: []models.WorkItem{},
@@ -888,7 +889,7 @@ func (suite *CalculatorTestSuite) TestCalculateInvoiceTotalsWithLineItems() {
 					Date:        time.Now(),
 					Description: "Repository Maintenance",
 					Amount:      &amount,
-					Total:       5000.0,
+					Total:       money.FromFloat(5000.0),
 				},
 			},
 			WorkItems: []models.WorkItem{
@@ -942,7 +943,7 @@ func (suite *CalculatorTestSuite) TestCalculateInvoiceTotalsWithLineItems() {
 					Date:        time.Now(),
 					Description: "Fixed amount",
 					Amount:      &fixedAmount,
-					Total:       5000.0,
+					Total:       money.FromFloat(5000.0),
 				},
 				{
 					ID:          "line_hourly",
@@ -951,7 +952,7 @@ func (suite *CalculatorTestSuite) TestCalculateInvoiceTotalsWithLineItems() {
 					Description: "Hourly work",
 					Quantity:    &hourlyHours,
 					UnitPrice:   &hourlyRate,
-					Total:       1000.0,
+					Total:       money.FromFloat(1000.0),
 				},
 				{
 					ID:          "line_quantity",
@@ -960,7 +961,7 @@ func (suite *CalculatorTestSuite) TestCalculateInvoiceTotalsWithLineItems() {
 					Description: "Quantity-based",
 					Quantity:    &quantity,
 					UnitPrice:   &unitPrice,
-					Total:       500.0,
+					Total:       money.FromFloat(500.0),
 				},
 			},
 			WorkItems: []models.WorkItem{},
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+var (
+	// ErrMergeIntoSelf indicates a client merge whose source and target are the same client.
+	ErrMergeIntoSelf = fmt.Errorf("cannot merge a client into itself")
+	// ErrMergePaidInvoices indicates a merge whose source client has paid invoices and was not forced.
+	ErrMergePaidInvoices = fmt.Errorf("source client has paid invoices")
+	// ErrHardDeleteUnsupported indicates the client storage cannot delete clients permanently.
+	ErrHardDeleteUnsupported = fmt.Errorf("client storage does not support permanent deletion")
+)
+
+// MergeClientsOptions controls how MergeClients treats the source client
+type MergeClientsOptions struct {
+	Force  bool `json:"force"`  // Also move paid invoices
+	Delete bool `json:"delete"` // Permanently delete the source instead of deactivating it
+}
+
+// MergeClientsResult reports the outcome of MergeClients
+type MergeClientsResult struct {
+	From            *models.Client     `json:"from"`
+	Into            *models.Client     `json:"into"`
+	InvoicesUpdated []models.InvoiceID `json:"invoices_updated"`
+	SourceDeleted   bool               `json:"source_deleted"` // Otherwise the source was deactivated
+}
+
+// MergeClients moves every invoice of the client from onto the client into, then
+// deactivates from, or deletes it with options.Delete. Paid invoices are only moved with
+// options.Force.
+//
+// If an invoice cannot be updated, the invoices already moved are put back on the source
+// client and the source is left untouched.
+func (s *ClientService) MergeClients(ctx context.Context, from, into models.ClientID, options MergeClientsOptions) (*MergeClientsResult, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if from == into {
+		return nil, fmt.Errorf("%w: %s", ErrMergeIntoSelf, from)
+	}
+
+	var hardDeleter storage.ClientHardDeleter
+	if options.Delete {
+		var ok bool
+		if hardDeleter, ok = s.clientStorage.(storage.ClientHardDeleter); !ok {
+			return nil, ErrHardDeleteUnsupported
+		}
+	}
+
+	source, err := s.clientStorage.GetClient(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToRetrieveClient, err)
+	}
+	target, err := s.clientStorage.GetClient(ctx, into)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToRetrieveClient, err)
+	}
+
+	// Trashed invoices move too, so restoring one never points at a missing client
+	listed, err := s.invoiceStorage.ListInvoices(ctx, models.InvoiceFilter{ClientID: from, IncludeDeleted: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices of client %s: %w", from, err)
+	}
+
+	if !options.Force {
+		for _, invoice := range listed.Invoices {
+			if invoice.Status == models.StatusPaid {
+				return nil, fmt.Errorf("%w (invoice %s)", ErrMergePaidInvoices, invoice.Number)
+			}
+		}
+	}
+
+	s.logger.Info("merging clients", "from", from, "into", into, "invoices", len(listed.Invoices))
+
+	result := &MergeClientsResult{From: source, Into: target}
+	previous := make(map[models.InvoiceID]models.Client, len(listed.Invoices))
+	for _, invoice := range listed.Invoices {
+		original := invoice.Client
+		invoice.Client = *target
+		if err := s.invoiceStorage.UpdateInvoice(ctx, invoice); err != nil {
+			updateErr := fmt.Errorf("failed to move invoice %s: %w", invoice.Number, err)
+			if rollbackErr := s.rollbackMerge(ctx, previous); rollbackErr != nil {
+				return nil, errors.Join(updateErr, rollbackErr)
+			}
+			return nil, updateErr
+		}
+		previous[invoice.ID] = original
+		result.InvoicesUpdated = append(result.InvoicesUpdated, invoice.ID)
+	}
+
+	if options.Delete {
+		if err := hardDeleter.HardDeleteClient(ctx, from); err != nil {
+			return result, fmt.Errorf("invoices moved but failed to delete client %s: %w", from, err)
+		}
+		result.SourceDeleted = true
+	} else if source.Active {
+		if err := source.Deactivate(ctx); err != nil {
+			return result, fmt.Errorf("invoices moved but failed to deactivate client %s: %w", from, err)
+		}
+		if err := s.clientStorage.UpdateClient(ctx, source); err != nil {
+			return result, fmt.Errorf("invoices moved but failed to deactivate client %s: %w", from, err)
+		}
+	}
+
+	s.logger.Info("clients merged", "from", from, "into", into,
+		"invoices_updated", len(result.InvoicesUpdated), "source_deleted", result.SourceDeleted)
+	return result, nil
+}
+
+// rollbackMerge restores the client each already moved invoice had before the merge
+func (s *ClientService) rollbackMerge(ctx context.Context, moved map[models.InvoiceID]models.Client) error {
+	var errs []error
+	for id, client := range moved {
+		invoice, err := s.invoiceStorage.GetInvoice(ctx, id)
+		if err == nil {
+			invoice.Client = client
+			err = s.invoiceStorage.UpdateInvoice(ctx, invoice)
+		}
+		if err != nil {
+			s.logger.Error("failed to roll back merged invoice", "invoice_id", id, "error", err)
+			errs = append(errs, fmt.Errorf("failed to roll back invoice %s: %w", id, err))
+		}
+	}
+
+	s.logger.Info("client merge rolled back", "invoices", len(moved), "failed", len(errs))
+	return errors.Join(errs...)
+}
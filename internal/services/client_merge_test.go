@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+)
+
+// failingUpdateStorage fails the UpdateInvoice call numbered failOn, counting from 1
+type failingUpdateStorage struct {
+	*jsonStorage.JSONStorage
+
+	calls  int
+	failOn int
+}
+
+func (s *failingUpdateStorage) UpdateInvoice(ctx context.Context, invoice *models.Invoice) error {
+	s.calls++
+	if s.calls == s.failOn {
+		return ErrTestUpdateFailed
+	}
+	return s.JSONStorage.UpdateInvoice(ctx, invoice)
+}
+
+// mergeFixture holds a store with two clients, the source owning the given invoices
+type mergeFixture struct {
+	store    *jsonStorage.JSONStorage
+	from     *models.Client
+	into     *models.Client
+	invoices []*models.Invoice
+}
+
+func newMergeFixture(t *testing.T, statuses ...string) *mergeFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+
+	clients := NewClientService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+	into, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Acme Corp", Email: "billing@acme.example"})
+	require.NoError(t, err)
+	from, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Acme", Email: "acme@acme.example"})
+	require.NoError(t, err)
+
+	fixture := &mergeFixture{store: store, from: from, into: into}
+	invoiceService := NewInvoiceService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+	for i, status := range statuses {
+		invoice, createErr := invoiceService.CreateInvoice(ctx, models.CreateInvoiceRequest{
+			Number:   fmt.Sprintf("INV-%03d", i+1),
+			ClientID: from.ID,
+			Date:     time.Now(),
+			DueDate:  time.Now().AddDate(0, 0, 30),
+		})
+		require.NoError(t, createErr)
+		if status != models.StatusDraft {
+			invoice.Status = status
+			require.NoError(t, store.UpdateInvoice(ctx, invoice))
+		}
+		fixture.invoices = append(fixture.invoices, invoice)
+	}
+
+	return fixture
+}
+
+func TestMergeClients(t *testing.T) {
+	ctx := context.Background()
+	f := newMergeFixture(t, models.StatusDraft, models.StatusSent)
+	service := NewClientService(f.store, f.store, &SimpleTestLogger{}, NewUUIDGenerator())
+
+	result, err := service.MergeClients(ctx, f.from.ID, f.into.ID, MergeClientsOptions{})
+	require.NoError(t, err)
+	assert.Len(t, result.InvoicesUpdated, 2)
+	assert.False(t, result.SourceDeleted)
+
+	for _, invoice := range f.invoices {
+		saved, getErr := f.store.GetInvoice(ctx, invoice.ID)
+		require.NoError(t, getErr)
+		assert.Equal(t, f.into.ID, saved.Client.ID)
+		assert.Equal(t, "Acme Corp", saved.Client.Name)
+	}
+
+	source, err := f.store.GetClient(ctx, f.from.ID)
+	require.NoError(t, err)
+	assert.False(t, source.Active, "source client should be deactivated")
+}
+
+func TestMergeClientsDeletesSource(t *testing.T) {
+	ctx := context.Background()
+	f := newMergeFixture(t, models.StatusDraft)
+	service := NewClientService(f.store, f.store, &SimpleTestLogger{}, NewUUIDGenerator())
+
+	result, err := service.MergeClients(ctx, f.from.ID, f.into.ID, MergeClientsOptions{Delete: true})
+	require.NoError(t, err)
+	assert.True(t, result.SourceDeleted)
+
+	exists, err := f.store.ExistsClient(ctx, f.from.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestMergeClientsIntoItself(t *testing.T) {
+	f := newMergeFixture(t)
+	service := NewClientService(f.store, f.store, &SimpleTestLogger{}, NewUUIDGenerator())
+
+	_, err := service.MergeClients(context.Background(), f.from.ID, f.from.ID, MergeClientsOptions{})
+	require.ErrorIs(t, err, ErrMergeIntoSelf)
+}
+
+func TestMergeClientsWithPaidInvoices(t *testing.T) {
+	ctx := context.Background()
+	f := newMergeFixture(t, models.StatusDraft, models.StatusPaid)
+	service := NewClientService(f.store, f.store, &SimpleTestLogger{}, NewUUIDGenerator())
+
+	_, err := service.MergeClients(ctx, f.from.ID, f.into.ID, MergeClientsOptions{})
+	require.ErrorIs(t, err, ErrMergePaidInvoices)
+
+	// Nothing moved without --force
+	saved, err := f.store.GetInvoice(ctx, f.invoices[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.from.ID, saved.Client.ID)
+
+	result, err := service.MergeClients(ctx, f.from.ID, f.into.ID, MergeClientsOptions{Force: true})
+	require.NoError(t, err)
+	assert.Len(t, result.InvoicesUpdated, 2)
+}
+
+func TestMergeClientsRollsBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	f := newMergeFixture(t, models.StatusDraft, models.StatusDraft, models.StatusDraft)
+
+	// Two invoices are moved before the third fails
+	failing := &failingUpdateStorage{JSONStorage: f.store, failOn: 3}
+	service := NewClientService(f.store, failing, &SimpleTestLogger{}, NewUUIDGenerator())
+
+	_, err := service.MergeClients(ctx, f.from.ID, f.into.ID, MergeClientsOptions{})
+	require.ErrorIs(t, err, ErrTestUpdateFailed)
+
+	for _, invoice := range f.invoices {
+		saved, getErr := f.store.GetInvoice(ctx, invoice.ID)
+		require.NoError(t, getErr)
+		assert.Equal(t, f.from.ID, saved.Client.ID, "invoice %s should be back on the source client", invoice.Number)
+	}
+
+	source, err := f.store.GetClient(ctx, f.from.ID)
+	require.NoError(t, err)
+	assert.True(t, source.Active, "source client must not be deactivated after a failed merge")
+}
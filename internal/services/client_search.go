@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// defaultMaxEditDistance is the fuzzy threshold used when SearchOptions.MaxDistance is not set
+const defaultMaxEditDistance = 2
+
+// Client search match types, from strongest to weakest
+const (
+	// MatchExact means a field equals the query, ignoring case
+	MatchExact = "exact"
+	// MatchPrefix means a field starts with the query
+	MatchPrefix = "prefix"
+	// MatchSubstring means a field contains the query
+	MatchSubstring = "substring"
+	// MatchFuzzy means a word in the name is within the edit distance threshold of the query
+	MatchFuzzy = "fuzzy"
+)
+
+// Client search fields, in ranking order
+const (
+	SearchFieldName    = "name"
+	SearchFieldEmail   = "email"
+	SearchFieldAddress = "address"
+)
+
+// SearchOptions controls how SearchClients matches and limits results
+type SearchOptions struct {
+	ActiveOnly  bool `json:"active_only"`  // Exclude inactive clients
+	Fuzzy       bool `json:"fuzzy"`        // Also match names within MaxDistance edits of the query
+	MaxDistance int  `json:"max_distance"` // Maximum edit distance for fuzzy matches (default 2)
+	Limit       int  `json:"limit"`        // Maximum number of results, 0 for no limit
+}
+
+// ClientMatch is a ranked client search result
+type ClientMatch struct {
+	Client    *models.Client `json:"client"`
+	Field     string         `json:"field"`              // Field that matched: name, email or address
+	MatchType string         `json:"match_type"`         // exact, prefix, substring or fuzzy
+	Distance  int            `json:"distance,omitempty"` // Edit distance for fuzzy matches
+}
+
+// SearchClients finds clients whose name, email or address match query, case-insensitively.
+// Results are ranked by match type (exact, prefix, substring, fuzzy), then by field (name,
+// email, address), then by edit distance and name. When the storage keeps a client index
+// only the matching clients are loaded.
+func (s *ClientService) SearchClients(ctx context.Context, query string, opts SearchOptions) ([]*ClientMatch, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil, nil
+	}
+
+	entries, err := s.clientIndexEntries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client index: %w", err)
+	}
+
+	maxDistance := opts.MaxDistance
+	if maxDistance <= 0 {
+		maxDistance = defaultMaxEditDistance
+	}
+
+	type rankedEntry struct {
+		entry storage.ClientIndexEntry
+		match ClientMatch
+	}
+	var ranked []rankedEntry
+	for _, entry := range entries {
+		if opts.ActiveOnly && !entry.Active {
+			continue
+		}
+		if match, ok := matchClientEntry(entry, query, opts.Fuzzy, maxDistance); ok {
+			ranked = append(ranked, rankedEntry{entry: entry, match: match})
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i].match, ranked[j].match
+		if matchTypeRank(a.MatchType) != matchTypeRank(b.MatchType) {
+			return matchTypeRank(a.MatchType) < matchTypeRank(b.MatchType)
+		}
+		if searchFieldRank(a.Field) != searchFieldRank(b.Field) {
+			return searchFieldRank(a.Field) < searchFieldRank(b.Field)
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return strings.ToLower(ranked[i].entry.Name) < strings.ToLower(ranked[j].entry.Name)
+	})
+
+	if opts.Limit > 0 && len(ranked) > opts.Limit {
+		ranked = ranked[:opts.Limit]
+	}
+
+	matches := make([]*ClientMatch, 0, len(ranked))
+	for _, r := range ranked {
+		client, err := s.clientStorage.GetClient(ctx, r.entry.ID)
+		if err != nil {
+			if storage.IsNotFound(err) {
+				continue // Removed since the index was read
+			}
+			return nil, fmt.Errorf("%w: %w", ErrFailedToRetrieveClient, err)
+		}
+		match := r.match
+		match.Client = client
+		matches = append(matches, &match)
+	}
+
+	s.logger.Debug("client search completed", "query", query, "matches", len(matches))
+	return matches, nil
+}
+
+// clientIndexEntries returns search entries from the storage index, or from the full client
+// list when the storage does not keep an index
+func (s *ClientService) clientIndexEntries(ctx context.Context) ([]storage.ClientIndexEntry, error) {
+	if index, ok := s.clientStorage.(storage.ClientSearchIndex); ok {
+		return index.ListClientIndex(ctx)
+	}
+
+	result, err := s.clientStorage.ListClients(ctx, false, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]storage.ClientIndexEntry, 0, len(result.Clients))
+	for _, client := range result.Clients {
+		entries = append(entries, storage.NewClientIndexEntry(client))
+	}
+	return entries, nil
+}
+
+// matchClientEntry returns the strongest match of query against entry's fields
+func matchClientEntry(entry storage.ClientIndexEntry, query string, fuzzy bool, maxDistance int) (ClientMatch, bool) {
+	fields := []struct{ name, value string }{
+		{SearchFieldName, entry.Name},
+		{SearchFieldEmail, entry.Email},
+		{SearchFieldAddress, entry.Address},
+	}
+
+	var best ClientMatch
+	found := false
+	for _, field := range fields {
+		value := strings.ToLower(field.value)
+		if value == "" {
+			continue
+		}
+
+		var matchType string
+		switch {
+		case value == query:
+			matchType = MatchExact
+		case strings.HasPrefix(value, query):
+			matchType = MatchPrefix
+		case strings.Contains(value, query):
+			matchType = MatchSubstring
+		default:
+			continue
+		}
+
+		if !found || matchTypeRank(matchType) < matchTypeRank(best.MatchType) {
+			best = ClientMatch{Field: field.name, MatchType: matchType}
+			found = true
+		}
+	}
+
+	if found || !fuzzy {
+		return best, found
+	}
+
+	// Fuzzy matching compares the query with the whole name and with each word in it
+	name := strings.ToLower(entry.Name)
+	distance := editDistance(query, name)
+	for _, word := range strings.Fields(name) {
+		distance = min(distance, editDistance(query, word))
+	}
+	if distance <= maxDistance {
+		return ClientMatch{Field: SearchFieldName, MatchType: MatchFuzzy, Distance: distance}, true
+	}
+
+	return ClientMatch{}, false
+}
+
+// matchTypeRank orders match types from strongest to weakest
+func matchTypeRank(matchType string) int {
+	switch matchType {
+	case MatchExact:
+		return 0
+	case MatchPrefix:
+		return 1
+	case MatchSubstring:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// searchFieldRank orders matched fields by relevance
+func searchFieldRank(field string) int {
+	switch field {
+	case SearchFieldName:
+		return 0
+	case SearchFieldEmail:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
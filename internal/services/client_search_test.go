@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+func TestClientServiceSearchClients(t *testing.T) {
+	ctx := context.Background()
+
+	clients := []*models.Client{
+		{ID: "C1", Name: "Acme Corp", Email: "billing@acme.test", Active: true},
+		{ID: "C2", Name: "Acme", Email: "ops@acme-labs.test", Active: true},
+		{ID: "C3", Name: "Globex", Email: "ap@globex.test", Address: "12 Acme Street", Active: true},
+		{ID: "C4", Name: "Initech", Email: "pay@initech.test", Active: false},
+	}
+
+	newService := func() *ClientService {
+		clientStorage := new(MockClientStorage)
+		clientStorage.On("ListClients", ctx, false, 0, 0).Return(&storage.ClientListResult{Clients: clients}, nil)
+		for _, client := range clients {
+			clientStorage.On("GetClient", ctx, client.ID).Return(client, nil).Maybe()
+		}
+		return NewClientService(clientStorage, nil, new(MockLogger), nil)
+	}
+
+	ids := func(matches []*ClientMatch) []models.ClientID {
+		result := make([]models.ClientID, 0, len(matches))
+		for _, match := range matches {
+			result = append(result, match.Client.ID)
+		}
+		return result
+	}
+
+	t.Run("RanksExactPrefixAndOtherFields", func(t *testing.T) {
+		matches, err := newService().SearchClients(ctx, "ACME", SearchOptions{})
+		require.NoError(t, err)
+
+		assert.Equal(t, []models.ClientID{"C2", "C1", "C3"}, ids(matches))
+		assert.Equal(t, MatchExact, matches[0].MatchType)
+		assert.Equal(t, MatchPrefix, matches[1].MatchType)
+		assert.Equal(t, SearchFieldAddress, matches[2].Field)
+	})
+
+	t.Run("MatchesEmail", func(t *testing.T) {
+		matches, err := newService().SearchClients(ctx, "ap@globex", SearchOptions{})
+		require.NoError(t, err)
+
+		require.Len(t, matches, 1)
+		assert.Equal(t, SearchFieldEmail, matches[0].Field)
+	})
+
+	t.Run("ActiveOnly", func(t *testing.T) {
+		matches, err := newService().SearchClients(ctx, "initech", SearchOptions{ActiveOnly: true})
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("FuzzyWithinThreshold", func(t *testing.T) {
+		service := newService()
+
+		matches, err := service.SearchClients(ctx, "glbex", SearchOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+
+		matches, err = service.SearchClients(ctx, "glbex", SearchOptions{Fuzzy: true, MaxDistance: 1})
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, MatchFuzzy, matches[0].MatchType)
+		assert.Equal(t, 1, matches[0].Distance)
+
+		matches, err = service.SearchClients(ctx, "glbx", SearchOptions{Fuzzy: true, MaxDistance: 1})
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		matches, err := newService().SearchClients(ctx, "acme", SearchOptions{Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []models.ClientID{"C2"}, ids(matches))
+	})
+
+	t.Run("EmptyQuery", func(t *testing.T) {
+		matches, err := newService().SearchClients(ctx, "  ", SearchOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("acme", "acme"))
+	assert.Equal(t, 1, editDistance("acme", "acne"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	assert.Equal(t, 4, editDistance("", "acme"))
+}
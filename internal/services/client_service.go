@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
 	"github.com/mrz1836/go-invoice/internal/storage"
 )
 
@@ -56,6 +57,34 @@ func (s *ClientService) CreateClient(ctx context.Context, req models.CreateClien
 
 	s.logger.Info("creating client", "name", req.Name, "email", req.Email)
 
+	client, err := s.buildClient(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store client
+	if err := s.clientStorage.CreateClient(ctx, client); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToStoreClient, err)
+	}
+
+	s.logger.Info("client created successfully", "id", client.ID, "name", client.Name)
+	return client, nil
+}
+
+// PreviewClient validates req and builds the client CreateClient would store, without
+// storing it. Used by dry runs to show the client that would be created.
+func (s *ClientService) PreviewClient(ctx context.Context, req models.CreateClientRequest) (*models.Client, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	return s.buildClient(ctx, req)
+}
+
+// buildClient validates req and creates the client model without storing it
+func (s *ClientService) buildClient(ctx context.Context, req models.CreateClientRequest) (*models.Client, error) {
 	// Validate request
 	if err := req.Validate(ctx); err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrInvalidCreateClientRequest, err)
@@ -100,22 +129,24 @@ func (s *ClientService) CreateClient(ctx context.Context, req models.CreateClien
 	// Set crypto fee settings
 	client.CryptoFeeEnabled = req.CryptoFeeEnabled
 	client.CryptoFeeAmount = req.CryptoFeeAmount
+	client.CryptoFeeCurrency = req.CryptoFeeCurrency
 
 	// Set late fee settings
 	client.LateFeeEnabled = req.LateFeeEnabled
 
+	// Set tax exemption
+	client.TaxExempt = req.TaxExempt
+	client.ExemptionReason = strings.TrimSpace(req.ExemptionReason)
+
+	// Set client-specific template
+	client.TemplateName = strings.TrimSpace(req.TemplateName)
+
 	if req.ApproverContacts != "" {
 		if err := client.UpdateApproverContacts(ctx, req.ApproverContacts); err != nil {
 			return nil, fmt.Errorf("failed to set client approver contacts: %w", err)
 		}
 	}
 
-	// Store client
-	if err := s.clientStorage.CreateClient(ctx, client); err != nil {
-		return nil, fmt.Errorf("%w: %w", ErrFailedToStoreClient, err)
-	}
-
-	s.logger.Info("client created successfully", "id", client.ID, "name", client.Name)
 	return client, nil
 }
 
@@ -267,7 +298,7 @@ func (s *ClientService) GetClientWithInvoices(ctx context.Context, id models.Cli
 	}
 
 	// Calculate statistics
-	var totalAmount, paidAmount, outstandingAmount float64
+	var totalAmount, paidAmount, outstandingAmount money.Amount
 	var activeInvoiceCount int
 
 	for _, invoice := range invoiceResult.Invoices {
@@ -289,9 +320,9 @@ func (s *ClientService) GetClientWithInvoices(ctx context.Context, id models.Cli
 		Invoices:          invoiceResult.Invoices,
 		TotalInvoices:     len(invoiceResult.Invoices),
 		ActiveInvoices:    activeInvoiceCount,
-		TotalAmount:       totalAmount,
-		PaidAmount:        paidAmount,
-		OutstandingAmount: outstandingAmount,
+		TotalAmount:       totalAmount.Float64(),
+		PaidAmount:        paidAmount.Float64(),
+		OutstandingAmount: outstandingAmount.Float64(),
 	}
 
 	return result, nil
@@ -97,6 +97,23 @@ func (suite *ClientServiceTestSuite) TestCreateClient() {
 		assert.Equal(t, "123 Test St", client.Address)
 		assert.Equal(t, "TAX-123", client.TaxID)
 		assert.True(t, client.Active)
+		assert.False(t, client.TaxExempt)
+	})
+
+	suite.Run("TaxExempt", func() {
+		exemptRequest := request
+		exemptRequest.TaxExempt = true
+		exemptRequest.ExemptionReason = " Reverse charge "
+
+		suite.clientStorage.On("FindClientByEmail", suite.ctx, testClientEmail).Return(nil, storage.NewNotFoundError("client", "email:test@example.com")).Once()
+		suite.idGen.On("GenerateClientID", suite.ctx).Return(models.ClientID(testClientID), nil).Once()
+		suite.clientStorage.On("CreateClient", suite.ctx, mock.AnythingOfType("*models.Client")).Return(nil).Once()
+
+		client, err := suite.service.CreateClient(suite.ctx, exemptRequest)
+
+		require.NoError(t, err)
+		assert.True(t, client.TaxExempt)
+		assert.Equal(t, "Reverse charge", client.ExemptionReason)
 	})
 
 	// Duplicate email
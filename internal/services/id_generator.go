@@ -3,20 +3,48 @@ package services
 import (
 	"context"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"sync"
 
 	"github.com/mrz1836/go-invoice/internal/models"
 )
 
 // UUIDGenerator generates unique IDs using random UUIDs
-type UUIDGenerator struct{}
+type UUIDGenerator struct {
+	random io.Reader // Source of the UUID bytes; crypto/rand when nil
+}
 
-// NewUUIDGenerator creates a new UUID generator
+// NewUUIDGenerator creates a new UUID generator. This is the generator used in production.
 func NewUUIDGenerator() *UUIDGenerator {
 	return &UUIDGenerator{}
 }
 
+// NewSeededUUIDGenerator creates a UUID generator that produces the same sequence of
+// UUIDs for the same seed. It is meant for tests and reproducible exports only; the
+// IDs are predictable and must not be used where uniqueness across runs matters.
+func NewSeededUUIDGenerator(seed uint64) *UUIDGenerator {
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], seed)
+	return &UUIDGenerator{random: &lockedReader{r: mathrand.NewChaCha8(key)}}
+}
+
+// lockedReader makes a reader that is not safe for concurrent use safe to share
+type lockedReader struct {
+	mu sync.Mutex
+	r  io.Reader
+}
+
+// Read reads from the underlying reader while holding the lock
+func (l *lockedReader) Read(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Read(p)
+}
+
 // GenerateInvoiceID generates a unique invoice ID
 func (g *UUIDGenerator) GenerateInvoiceID(ctx context.Context) (models.InvoiceID, error) {
 	select {
@@ -67,9 +95,13 @@ func (g *UUIDGenerator) GenerateWorkItemID(ctx context.Context) (string, error)
 
 // generateUUID generates a random UUID v4
 func (g *UUIDGenerator) generateUUID() (string, error) {
+	random := g.random
+	if random == nil {
+		random = rand.Reader
+	}
+
 	b := make([]byte, 16)
-	_, err := rand.Read(b)
-	if err != nil {
+	if _, err := io.ReadFull(random, b); err != nil {
 		return "", err
 	}
 
@@ -86,3 +118,54 @@ func (g *UUIDGenerator) generateUUID() (string, error) {
 		hex.EncodeToString(b[10:16]),
 	), nil
 }
+
+// SequentialGenerator generates predictable IDs from a counter per kind of ID, such as
+// "test-inv-000001", "test-client-000001" and "test-work-000001" for the prefix "test".
+// It is meant for tests and reproducible exports only; production uses NewUUIDGenerator.
+type SequentialGenerator struct {
+	prefix   string
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+// NewSequentialGenerator creates a sequential generator whose IDs start with prefix.
+// Without a prefix the IDs start with the kind, e.g. "inv-000001".
+func NewSequentialGenerator(prefix string) *SequentialGenerator {
+	return &SequentialGenerator{prefix: prefix, counters: make(map[string]int)}
+}
+
+// GenerateInvoiceID returns the next invoice ID in the sequence
+func (g *SequentialGenerator) GenerateInvoiceID(ctx context.Context) (models.InvoiceID, error) {
+	id, err := g.next(ctx, "inv")
+	return models.InvoiceID(id), err
+}
+
+// GenerateClientID returns the next client ID in the sequence
+func (g *SequentialGenerator) GenerateClientID(ctx context.Context) (models.ClientID, error) {
+	id, err := g.next(ctx, "client")
+	return models.ClientID(id), err
+}
+
+// GenerateWorkItemID returns the next work item ID in the sequence
+func (g *SequentialGenerator) GenerateWorkItemID(ctx context.Context) (string, error) {
+	return g.next(ctx, "work")
+}
+
+// next advances the counter for kind and formats the ID
+func (g *SequentialGenerator) next(ctx context.Context, kind string) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	default:
+	}
+
+	g.mu.Lock()
+	g.counters[kind]++
+	n := g.counters[kind]
+	g.mu.Unlock()
+
+	if g.prefix == "" {
+		return fmt.Sprintf("%s-%06d", kind, n), nil
+	}
+	return fmt.Sprintf("%s-%s-%06d", g.prefix, kind, n), nil
+}
@@ -7,9 +7,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/mrz1836/go-invoice/internal/models"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
 )
 
 type UUIDGeneratorTestSuite struct {
@@ -392,3 +395,69 @@ func (suite *UUIDGeneratorTestSuite) parseHexByte(hexStr string) uint8 {
 	}
 	return result
 }
+
+func TestSeededUUIDGenerator(t *testing.T) {
+	ctx := context.Background()
+	first, second, other := NewSeededUUIDGenerator(42), NewSeededUUIDGenerator(42), NewSeededUUIDGenerator(7)
+
+	for range 3 {
+		a, err := first.GenerateInvoiceID(ctx)
+		require.NoError(t, err)
+		b, err := second.GenerateInvoiceID(ctx)
+		require.NoError(t, err)
+		c, err := other.GenerateInvoiceID(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, a, b, "the same seed gives the same sequence")
+		assert.NotEqual(t, a, c)
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, string(a))
+	}
+}
+
+func TestSequentialGenerator(t *testing.T) {
+	ctx := context.Background()
+	generator := NewSequentialGenerator("test")
+
+	invoiceID, err := generator.GenerateInvoiceID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.InvoiceID("test-inv-000001"), invoiceID)
+	invoiceID, err = generator.GenerateInvoiceID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.InvoiceID("test-inv-000002"), invoiceID)
+
+	clientID, err := generator.GenerateClientID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.ClientID("test-client-000001"), clientID, "each kind of ID has its own counter")
+
+	workItemID, err := NewSequentialGenerator("").GenerateWorkItemID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "work-000001", workItemID)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = generator.GenerateInvoiceID(cancelCtx)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSequentialGeneratorMakesServicesDeterministic(t *testing.T) {
+	ctx := context.Background()
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+
+	generator := NewSequentialGenerator("test")
+	clients := NewClientService(store, store, &SimpleTestLogger{}, generator)
+	invoices := NewInvoiceService(store, store, &SimpleTestLogger{}, generator)
+
+	client, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Acme", Email: "billing@acme.example"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ClientID("test-client-000001"), client.ID)
+
+	invoice, err := invoices.CreateInvoice(ctx, models.CreateInvoiceRequest{
+		Number:   "INV-001",
+		ClientID: client.ID,
+		Date:     time.Now(),
+		DueDate:  time.Now().AddDate(0, 0, 30),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.InvoiceID("test-inv-000001"), invoice.ID)
+}
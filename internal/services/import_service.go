@@ -23,6 +23,10 @@ var (
 	ErrDuplicateDetectionFailed = fmt.Errorf("duplicate detection failed")
 	// ErrBatchImportFailed indicates that batch import failed.
 	ErrBatchImportFailed = fmt.Errorf("batch import failed")
+	// ErrImportRowsRejected indicates that some rows could not be parsed and nothing was imported.
+	ErrImportRowsRejected = fmt.Errorf("import rows rejected")
+	// ErrInvoiceNotImportable indicates that the target invoice cannot accept imported items.
+	ErrInvoiceNotImportable = fmt.Errorf("invoice must be in draft or sent status to import work items")
 )
 
 // ImportService provides high-level import orchestration operations
@@ -103,6 +107,14 @@ func (s *ImportService) ImportToNewInvoice(ctx context.Context, reader io.Reader
 		return nil, fmt.Errorf("parsing failed (%s): %w", req.Format, err)
 	}
 
+	// Rejected rows abort the whole import unless the caller accepts a partial import
+	if req.FailOnRejectedRows && parseResult.ErrorRows > 0 {
+		return &csv.ImportResult{
+			ParseResult: parseResult,
+			DryRun:      req.DryRun,
+		}, fmt.Errorf("%w: %d of %d row(s)", ErrImportRowsRejected, parseResult.ErrorRows, parseResult.TotalRows)
+	}
+
 	if len(parseResult.WorkItems) == 0 {
 		return &csv.ImportResult{
 			ParseResult:    parseResult,
@@ -111,6 +123,8 @@ func (s *ImportService) ImportToNewInvoice(ctx context.Context, reader io.Reader
 		}, nil
 	}
 
+	applyHourlyRounding(parseResult.WorkItems, req.Rounding)
+
 	// Validate batch of work items
 	if validationErr := s.validator.ValidateBatch(ctx, parseResult.WorkItems); validationErr != nil {
 		return nil, fmt.Errorf("%w: %w", ErrBatchValidationFailed, validationErr)
@@ -127,23 +141,25 @@ func (s *ImportService) ImportToNewInvoice(ctx context.Context, reader io.Reader
 		return nil, fmt.Errorf("%w: %w", ErrClientVerificationFailed, err)
 	}
 
-	// Generate invoice number if not provided
-	invoiceNumber := req.InvoiceNumber
-	if invoiceNumber == "" {
-		invoiceNumber = s.generateInvoiceNumber(ctx)
-	}
-
 	// Create invoice
 	invoiceReq := models.CreateInvoiceRequest{
-		Number:      invoiceNumber,
+		Number:      req.InvoiceNumber,
 		ClientID:    req.ClientID,
 		Date:        req.InvoiceDate,
 		DueDate:     req.DueDate,
 		Description: req.Description,
+		Currency:    req.Currency,
+		TaxRounding: req.TaxRounding,
 		WorkItems:   s.convertToWorkItemRequests(parseResult.WorkItems),
 	}
 
-	invoice, err := s.invoiceService.CreateInvoice(ctx, invoiceReq)
+	// Generate the invoice number if not provided
+	var invoice *models.Invoice
+	if invoiceReq.Number == "" {
+		invoice, err = s.invoiceService.CreateInvoiceWithNextNumber(ctx, invoiceReq, req.Numbering)
+	} else {
+		invoice, err = s.invoiceService.CreateInvoice(ctx, invoiceReq)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("invoice creation failed: %w", err)
 	}
@@ -156,6 +172,7 @@ func (s *ImportService) ImportToNewInvoice(ctx context.Context, reader io.Reader
 		InvoiceID:      string(invoice.ID),
 		WorkItemsAdded: len(parseResult.WorkItems),
 		TotalAmount:    totalAmount,
+		InvoiceTotal:   invoice.Total.Float64(),
 		DryRun:         false,
 	}
 
@@ -186,6 +203,15 @@ func (s *ImportService) AppendToInvoice(ctx context.Context, reader io.Reader, r
 		return nil, fmt.Errorf("parsing failed (%s): %w", req.Format, err)
 	}
 
+	// Rejected rows abort the whole import unless the caller accepts a partial import
+	if req.FailOnRejectedRows && parseResult.ErrorRows > 0 {
+		return &csv.ImportResult{
+			ParseResult: parseResult,
+			InvoiceID:   req.InvoiceID,
+			DryRun:      req.DryRun,
+		}, fmt.Errorf("%w: %d of %d row(s)", ErrImportRowsRejected, parseResult.ErrorRows, parseResult.TotalRows)
+	}
+
 	if len(parseResult.WorkItems) == 0 {
 		return &csv.ImportResult{
 			ParseResult:    parseResult,
@@ -195,6 +221,8 @@ func (s *ImportService) AppendToInvoice(ctx context.Context, reader io.Reader, r
 		}, nil
 	}
 
+	applyHourlyRounding(parseResult.WorkItems, req.Rounding)
+
 	// Validate batch
 	if validationErr := s.validator.ValidateBatch(ctx, parseResult.WorkItems); validationErr != nil {
 		return nil, fmt.Errorf("%w: %w", ErrBatchValidationFailed, validationErr)
@@ -206,20 +234,27 @@ func (s *ImportService) AppendToInvoice(ctx context.Context, reader io.Reader, r
 		return nil, fmt.Errorf("%w: %w", ErrDuplicateDetectionFailed, err)
 	}
 
+	// Get the invoice once to add all work items in a single update
+	invoice, err := s.invoiceService.GetInvoice(ctx, models.InvoiceID(req.InvoiceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice for batch update: %w", err)
+	}
+	if invoice.Status != models.StatusDraft && invoice.Status != models.StatusSent {
+		return nil, fmt.Errorf("%w: %s is %s", ErrInvoiceNotImportable, invoice.Number, invoice.Status)
+	}
+	if err = invoice.EnsureUnlocked(); err != nil {
+		return nil, err
+	}
+
 	if req.DryRun {
 		result := s.createDryRunResult(parseResult)
 		result.InvoiceID = req.InvoiceID
 		result.Warnings = warnings
+		result.InvoiceTotal = invoice.Total.Float64() + result.TotalAmount
 		s.logger.Info("dry run append completed", "work_items", len(parseResult.WorkItems))
 		return result, nil
 	}
 
-	// Get the invoice once to add all work items in a single update
-	invoice, err := s.invoiceService.GetInvoice(ctx, models.InvoiceID(req.InvoiceID))
-	if err != nil {
-		return nil, fmt.Errorf("failed to get invoice for batch update: %w", err)
-	}
-
 	// Add work items to invoice in memory
 	successCount := 0
 	for _, workItem := range parseResult.WorkItems {
@@ -258,6 +293,7 @@ func (s *ImportService) AppendToInvoice(ctx context.Context, reader io.Reader, r
 
 	// Update invoice once with all work items
 	if successCount > 0 {
+		invoice.RecordEvent(models.EventLineItemAdded, fmt.Sprintf("Imported %d work items", successCount), time.Now())
 		if updateErr := s.invoiceService.UpdateInvoiceDirectly(ctx, invoice); updateErr != nil {
 			return nil, fmt.Errorf("failed to update invoice with work items: %w", updateErr)
 		}
@@ -270,6 +306,7 @@ func (s *ImportService) AppendToInvoice(ctx context.Context, reader io.Reader, r
 		InvoiceID:      req.InvoiceID,
 		WorkItemsAdded: successCount,
 		TotalAmount:    totalAmount,
+		InvoiceTotal:   invoice.Total.Float64(),
 		Warnings:       warnings,
 		DryRun:         false,
 	}
@@ -420,17 +457,18 @@ func (s *ImportService) calculateTotalAmount(workItems []models.WorkItem) float6
 	return total
 }
 
+// applyHourlyRounding bills each parsed work item in the requested increments, with the minimum charge
+func applyHourlyRounding(workItems []models.WorkItem, rule models.HourlyRounding) {
+	for i := range workItems {
+		workItems[i].ApplyHourlyRounding(rule)
+	}
+}
+
 func (s *ImportService) convertToWorkItemRequests(workItems []models.WorkItem) []models.WorkItem {
 	// Since models.WorkItem is already the correct type, return as-is
 	return workItems
 }
 
-func (s *ImportService) generateInvoiceNumber(_ context.Context) string {
-	// Generate a simple invoice number based on current date and time
-	now := time.Now()
-	return fmt.Sprintf("INV-%s", now.Format("20060102-150405"))
-}
-
 func (s *ImportService) detectDuplicates(ctx context.Context, invoiceID models.InvoiceID, newWorkItems []models.WorkItem) ([]csv.ImportWarning, error) {
 	// Get existing invoice
 	invoice, err := s.invoiceService.GetInvoice(ctx, invoiceID)
@@ -526,21 +564,30 @@ func (s *ImportService) generateValidationWarnings(workItems []models.WorkItem)
 
 // ImportToNewInvoiceRequest represents a request to import CSV data into a new invoice
 type ImportToNewInvoiceRequest struct {
-	ClientID      models.ClientID  `json:"client_id"`      // Client for the new invoice
-	ParseOptions  csv.ParseOptions `json:"parse_options"`  // Parsing options
-	InvoiceNumber string           `json:"invoice_number"` // Optional invoice number (generated if empty)
-	InvoiceDate   time.Time        `json:"invoice_date"`   // Invoice date
-	DueDate       time.Time        `json:"due_date"`       // Due date
-	Description   string           `json:"description"`    // Invoice description
-	DryRun        bool             `json:"dry_run"`        // Validate only, don't create
-	Format        string           `json:"format"`         // Import format: "csv" or "json"
+	ClientID      models.ClientID         `json:"client_id"`      // Client for the new invoice
+	ParseOptions  csv.ParseOptions        `json:"parse_options"`  // Parsing options
+	InvoiceNumber string                  `json:"invoice_number"` // Optional invoice number (generated if empty)
+	Numbering     models.InvoiceNumbering `json:"numbering"`      // Numbering used when InvoiceNumber is empty
+	InvoiceDate   time.Time               `json:"invoice_date"`   // Invoice date
+	DueDate       time.Time               `json:"due_date"`       // Due date
+	Description   string                  `json:"description"`    // Invoice description
+	Currency      string                  `json:"currency"`       // ISO 4217 currency code for the new invoice
+	TaxRounding   string                  `json:"tax_rounding"`   // Tax rounding strategy for the new invoice
+	Rounding      models.HourlyRounding   `json:"rounding"`       // Billing increment and minimum applied to imported hours
+	DryRun        bool                    `json:"dry_run"`        // Validate only, don't create
+	Format        string                  `json:"format"`         // Import format: "csv" or "json"
+
+	FailOnRejectedRows bool `json:"fail_on_rejected_rows"` // Import nothing if any row is rejected
 }
 
 // AppendToInvoiceRequest represents a request to append data to existing invoice
 type AppendToInvoiceRequest struct {
-	InvoiceID    string           `json:"invoice_id"`    // Existing invoice ID
-	ParseOptions csv.ParseOptions `json:"parse_options"` // Parsing options
-	DryRun       bool             `json:"dry_run"`       // Validate only, don't append
-	SkipDupes    bool             `json:"skip_dupes"`    // Skip duplicate work items
-	Format       string           `json:"format"`        // Import format: "csv" or "json"
+	InvoiceID    string                `json:"invoice_id"`    // Existing invoice ID
+	ParseOptions csv.ParseOptions      `json:"parse_options"` // Parsing options
+	DryRun       bool                  `json:"dry_run"`       // Validate only, don't append
+	SkipDupes    bool                  `json:"skip_dupes"`    // Skip duplicate work items
+	Format       string                `json:"format"`        // Import format: "csv" or "json"
+	Rounding     models.HourlyRounding `json:"rounding"`      // Billing increment and minimum applied to imported hours
+
+	FailOnRejectedRows bool `json:"fail_on_rejected_rows"` // Import nothing if any row is rejected
 }
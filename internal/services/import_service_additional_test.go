@@ -213,24 +213,6 @@ func (suite *AdditionalImportServiceTestSuite) TestHelperFunctions() {
 		suite.InDelta(0.0, result, 0.001)
 	})
 
-	suite.Run("generateInvoiceNumber", func() {
-		ctx := context.Background()
-		service := NewImportService(nil, nil, nil, nil, nil, nil)
-
-		number := service.generateInvoiceNumber(ctx)
-		suite.NotEmpty(number)
-		suite.Contains(number, "INV-")
-
-		// Test multiple generations to ensure format consistency
-		for i := 0; i < 3; i++ {
-			testNumber := service.generateInvoiceNumber(ctx)
-			suite.NotEmpty(testNumber)
-			suite.Contains(testNumber, "INV-")
-			// Each should follow the pattern INV-YYYYMMDD-HHMMSS
-			suite.Regexp(`^INV-\d{8}-\d{6}$`, testNumber)
-		}
-	})
-
 	suite.Run("calculateTotalAmount", func() {
 		service := NewImportService(nil, nil, nil, nil, nil, nil)
 
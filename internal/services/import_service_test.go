@@ -21,6 +21,7 @@ var (
 	errTestValidationError = fmt.Errorf("validation error")
 	errTestClientNotFound  = fmt.Errorf("client not found")
 	errTestNotFound        = errors.New("not found")
+	errTestDiskFull        = errors.New("disk full")
 )
 
 // Define interfaces for testing
@@ -1134,6 +1135,30 @@ func (suite *RealImportServiceTestSuite) TestImportToNewInvoiceDryRun() {
 	suite.Equal(1, result.WorkItemsAdded)
 }
 
+func (suite *RealImportServiceTestSuite) TestImportToNewInvoiceHourlyRounding() {
+	ctx := context.Background()
+	workItems := []models.WorkItem{
+		{ID: testWorkID001, Hours: 1.1, Rate: 100.0, Total: 110.0},
+		{ID: "work-002", Hours: 0.25, Rate: 100.0, Total: 25.0},
+	}
+	suite.csvParser.On("ParseTimesheet", ctx, mock.Anything, mock.Anything).
+		Return(&csv.ParseResult{WorkItems: workItems}, nil).Once()
+	suite.validator.On("ValidateBatch", ctx, mock.Anything).Return(nil).Once()
+
+	req := ImportToNewInvoiceRequest{
+		ClientID: testClientID,
+		Format:   "csv",
+		DryRun:   true,
+		Rounding: models.HourlyRounding{IncrementMinutes: 15, MinimumHours: 0.5},
+	}
+
+	result, err := suite.importService.ImportToNewInvoice(ctx, strings.NewReader("test"), req)
+	suite.Require().NoError(err)
+	suite.InDelta(1.25, workItems[0].Hours, 0.0001)
+	suite.InDelta(0.5, workItems[1].Hours, 0.0001)
+	suite.InDelta(175.0, result.TotalAmount, 0.001)
+}
+
 func (suite *RealImportServiceTestSuite) TestAppendToInvoiceContextCanceled() {
 	canceledCtx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -1179,6 +1204,57 @@ func (suite *RealImportServiceTestSuite) TestAppendToInvoiceEmptyResult() {
 	suite.Equal(testInvoiceID001, result.InvoiceID)
 }
 
+func (suite *RealImportServiceTestSuite) TestAppendToInvoiceRejectedRows() {
+	ctx := context.Background()
+	parseResult := &csv.ParseResult{
+		WorkItems: []models.WorkItem{{ID: testWorkID001, Hours: 8.0, Rate: 100.0, Total: 800.0}},
+		TotalRows: 2,
+		ErrorRows: 1,
+		Errors:    []csv.ParseError{{Line: 3, Message: "invalid hours"}},
+	}
+	suite.csvParser.On("ParseTimesheet", ctx, mock.Anything, mock.Anything).Return(parseResult, nil).Once()
+
+	req := AppendToInvoiceRequest{
+		InvoiceID:          testInvoiceID001,
+		Format:             "csv",
+		FailOnRejectedRows: true,
+	}
+
+	result, err := suite.importService.AppendToInvoice(ctx, strings.NewReader("test"), req)
+	suite.Require().ErrorIs(err, ErrImportRowsRejected)
+	suite.Require().NotNil(result)
+	suite.Equal(0, result.WorkItemsAdded)
+	suite.Len(result.ParseResult.Errors, 1)
+	suite.invoiceStorage.AssertNotCalled(suite.T(), "UpdateInvoice", mock.Anything, mock.Anything)
+}
+
+func (suite *RealImportServiceTestSuite) TestAppendToInvoiceRequiresDraftOrSent() {
+	ctx := context.Background()
+	now := time.Now()
+	workItems := []models.WorkItem{
+		{ID: testWorkID001, Hours: 8.0, Rate: 100.0, Total: 800.0, Date: now, Description: "Development", CreatedAt: now},
+	}
+	paidInvoice := &models.Invoice{
+		ID:     testInvoiceID001,
+		Number: testInvoiceNum,
+		Status: models.StatusPaid,
+	}
+
+	suite.csvParser.On("ParseTimesheet", ctx, mock.Anything, mock.Anything).
+		Return(&csv.ParseResult{WorkItems: workItems}, nil).Once()
+	suite.validator.On("ValidateBatch", ctx, workItems).Return(nil).Once()
+	suite.invoiceStorage.On("GetInvoice", ctx, models.InvoiceID(testInvoiceID001)).Return(paidInvoice, nil).Twice()
+
+	req := AppendToInvoiceRequest{
+		InvoiceID: testInvoiceID001,
+		Format:    "csv",
+	}
+
+	result, err := suite.importService.AppendToInvoice(ctx, strings.NewReader("test"), req)
+	suite.Require().ErrorIs(err, ErrInvoiceNotImportable)
+	suite.Nil(result)
+}
+
 func (suite *RealImportServiceTestSuite) TestBatchImportContextCanceled() {
 	canceledCtx, cancel := context.WithCancel(context.Background())
 	cancel()
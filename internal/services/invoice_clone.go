@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// CloneInvoiceOptions controls the invoice CloneInvoice creates
+type CloneInvoiceOptions struct {
+	ClientID models.ClientID `json:"client_id,omitempty"` // Bill this client instead of the source invoice's client
+	Date     time.Time       `json:"date"`                // Invoice date of the clone; today when zero
+	DueDate  time.Time       `json:"due_date"`            // Due date of the clone; keeps the source's payment term when zero
+}
+
+// CloneInvoice creates a new draft invoice from the invoice sourceID, numbered with the
+// next available number for numbering. Work items, line items, the crypto fee, tax rate,
+// discount, description and address overrides are copied and the totals recalculated.
+// Payments, generated documents, attachments, status and timestamps are not.
+func (s *InvoiceService) CloneInvoice(ctx context.Context, sourceID models.InvoiceID, numbering models.InvoiceNumbering, options CloneInvoiceOptions) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	source, err := s.invoiceStorage.GetInvoice(ctx, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve invoice: %w", err)
+	}
+
+	// Bill the client as it is today, not the snapshot stored on the source
+	clientID := source.Client.ID
+	if options.ClientID != "" {
+		clientID = options.ClientID
+	}
+	client, err := s.clientStorage.GetClient(ctx, clientID)
+	if err != nil {
+		if storage.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", models.ErrClientNotFound, clientID)
+		}
+		return nil, fmt.Errorf("failed to retrieve client: %w", err)
+	}
+
+	date := options.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	dueDate := options.DueDate
+	if dueDate.IsZero() {
+		dueDate = date.Add(source.DueDate.Sub(source.Date))
+	}
+
+	s.logger.Info("cloning invoice", "source", source.Number, "client_id", client.ID)
+
+	s.numberMu.Lock()
+	defer s.numberMu.Unlock()
+
+	invoice, err := s.withNextInvoiceNumber(ctx, numbering, date, func(number string) (*models.Invoice, error) {
+		return s.storeClone(ctx, source, client, number, date, dueDate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("invoice cloned successfully", "source", source.Number, "id", invoice.ID, "number", invoice.Number, "total", invoice.Total)
+	return invoice, nil
+}
+
+// storeClone builds the clone of source numbered number for client and stores it
+func (s *InvoiceService) storeClone(ctx context.Context, source *models.Invoice, client *models.Client, number string, date, dueDate time.Time) (*models.Invoice, error) {
+	req := models.CreateInvoiceRequest{
+		Number:          number,
+		ClientID:        client.ID,
+		Date:            date,
+		DueDate:         dueDate,
+		Description:     source.Description,
+		PONumber:        source.PONumber,
+		ClientReference: source.ClientReference,
+		TemplateName:    source.TemplateName,
+		Currency:        source.Currency,
+		TaxRounding:     source.TaxRounding,
+		USDCAddress:     clonePtr(source.USDCAddressOverride),
+		BSVAddress:      clonePtr(source.BSVAddressOverride),
+	}
+	if err := req.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid create invoice request: %w", err)
+	}
+
+	invoice, err := s.buildInvoice(ctx, req, client)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.Notes = source.Notes
+	invoice.CryptoFee = source.CryptoFee
+	invoice.TaxRate = source.TaxRate
+	invoice.DiscountPercent = source.DiscountPercent
+	invoice.DiscountAmount = source.DiscountAmount
+
+	if err = s.cloneInvoiceItems(ctx, source, invoice); err != nil {
+		return nil, err
+	}
+
+	if err = invoice.RecalculateTotals(ctx); err != nil {
+		return nil, fmt.Errorf("failed to recalculate totals: %w", err)
+	}
+
+	if err = s.invoiceStorage.CreateInvoice(ctx, invoice); err != nil {
+		return nil, fmt.Errorf("failed to store invoice: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// cloneInvoiceItems copies the work items and line items of source onto invoice under new IDs
+func (s *InvoiceService) cloneInvoiceItems(ctx context.Context, source, invoice *models.Invoice) error {
+	now := time.Now()
+
+	for _, item := range source.WorkItems {
+		id, err := s.idGenerator.GenerateWorkItemID(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to generate work item ID: %w", err)
+		}
+		item.ID = id
+		item.CreatedAt = now
+		invoice.WorkItems = append(invoice.WorkItems, item)
+	}
+
+	for _, item := range source.LineItems {
+		id, err := s.idGenerator.GenerateWorkItemID(ctx) // Reuse work item ID generator
+		if err != nil {
+			return fmt.Errorf("failed to generate line item ID: %w", err)
+		}
+
+		// Copy the pointer fields so the clone never shares values with the source
+		item.ID = id
+		item.CreatedAt = now
+		item.EndDate = clonePtr(item.EndDate)
+		item.Hours = clonePtr(item.Hours)
+		item.Rate = clonePtr(item.Rate)
+		item.Amount = clonePtr(item.Amount)
+		item.Quantity = clonePtr(item.Quantity)
+		item.UnitPrice = clonePtr(item.UnitPrice)
+		item.Discount = clonePtr(item.Discount)
+		invoice.LineItems = append(invoice.LineItems, item)
+	}
+
+	return nil
+}
+
+// clonePtr returns a pointer to a copy of *value, or nil
+func clonePtr[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	v := *value
+	return &v
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+)
+
+// newCloneSource stores a sent invoice for client with a legacy work item, a line item,
+// a crypto fee, tax and a payment
+func newCloneSource(ctx context.Context, t *testing.T, store *jsonStorage.JSONStorage, client *models.Client) *models.Invoice {
+	t.Helper()
+
+	usdc := "0x1234567890abcdef1234567890abcdef12345678"
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	invoice, err := models.NewInvoice(ctx, "source-invoice", "INV-0001", date, date.AddDate(0, 0, 14), *client, 0.1)
+	require.NoError(t, err)
+	invoice.Description = "March retainer"
+	invoice.USDCAddressOverride = &usdc
+	invoice.CryptoFee = money.FromFloat(25)
+
+	workItem, err := models.NewWorkItem(ctx, "work-1", date, 2, 100, "Legacy consulting")
+	require.NoError(t, err)
+	require.NoError(t, invoice.AddWorkItemWithoutVersionIncrement(ctx, *workItem))
+	lineItem, err := models.NewFixedLineItem(ctx, "line-1", date, 500, "Monthly retainer")
+	require.NoError(t, err)
+	require.NoError(t, invoice.AddLineItemWithoutVersionIncrement(ctx, *lineItem))
+
+	invoice.Status = models.StatusSent
+	require.NoError(t, invoice.RecordPayment(ctx, models.Payment{Amount: 100, Date: date, Method: models.PaymentMethodWire}))
+	require.NoError(t, store.CreateInvoice(ctx, invoice))
+
+	return invoice
+}
+
+func TestCloneInvoice(t *testing.T) {
+	ctx := context.Background()
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+
+	clients := NewClientService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+	client, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Acme Corp", Email: "billing@acme.example"})
+	require.NoError(t, err)
+	source := newCloneSource(ctx, t, store, client)
+
+	service := NewInvoiceService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+	numbering := models.InvoiceNumbering{Prefix: "INV", StartNumber: 1}
+	date := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	clone, err := service.CloneInvoice(ctx, source.ID, numbering, CloneInvoiceOptions{Date: date})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, source.ID, clone.ID)
+	assert.Equal(t, "INV-0002", clone.Number)
+	assert.Equal(t, models.StatusDraft, clone.Status)
+	assert.Equal(t, 1, clone.Version)
+	assert.Equal(t, date, clone.Date)
+	assert.Equal(t, date.AddDate(0, 0, 14), clone.DueDate, "the source's payment term is kept")
+	assert.Equal(t, client.ID, clone.Client.ID)
+	assert.Equal(t, "March retainer", clone.Description)
+	require.NotNil(t, clone.USDCAddressOverride)
+	assert.Equal(t, *source.USDCAddressOverride, *clone.USDCAddressOverride)
+	assert.Empty(t, clone.Payments)
+
+	// Both the legacy work item and the line item are kept, under new IDs
+	require.Len(t, clone.WorkItems, 1)
+	require.Len(t, clone.LineItems, 1)
+	assert.NotEqual(t, source.WorkItems[0].ID, clone.WorkItems[0].ID)
+	assert.NotEqual(t, source.LineItems[0].ID, clone.LineItems[0].ID)
+	assert.Equal(t, "Legacy consulting", clone.WorkItems[0].Description)
+	assert.Equal(t, "Monthly retainer", clone.LineItems[0].Description)
+	assert.NotSame(t, source.LineItems[0].Amount, clone.LineItems[0].Amount)
+
+	// (200 + 500 + 25 crypto fee) * 1.1 tax
+	assert.Equal(t, money.FromFloat(700), clone.Subtotal)
+	assert.Equal(t, money.FromFloat(25), clone.CryptoFee)
+	assert.InDelta(t, 0.1, clone.TaxRate, 0.0001)
+	assert.Equal(t, money.FromFloat(797.5), clone.Total)
+
+	saved, err := store.GetInvoice(ctx, clone.ID)
+	require.NoError(t, err)
+	assert.Equal(t, clone.Total, saved.Total)
+
+	// The source is untouched
+	original, err := store.GetInvoice(ctx, source.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSent, original.Status)
+	assert.Len(t, original.Payments, 1)
+}
+
+func TestCloneInvoiceToOtherClient(t *testing.T) {
+	ctx := context.Background()
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+
+	clients := NewClientService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+	client, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Acme Corp", Email: "billing@acme.example"})
+	require.NoError(t, err)
+	other, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Globex", Email: "ap@globex.example"})
+	require.NoError(t, err)
+	source := newCloneSource(ctx, t, store, client)
+
+	service := NewInvoiceService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+	clone, err := service.CloneInvoice(ctx, source.ID, models.InvoiceNumbering{Prefix: "INV", StartNumber: 1},
+		CloneInvoiceOptions{ClientID: other.ID})
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, clone.Client.ID)
+	assert.Equal(t, "Globex", clone.Client.Name)
+
+	_, err = service.CloneInvoice(ctx, source.ID, models.InvoiceNumbering{Prefix: "INV", StartNumber: 1},
+		CloneInvoiceOptions{ClientID: "missing-client"})
+	require.ErrorIs(t, err, models.ErrClientNotFound)
+}
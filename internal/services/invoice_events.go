@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// recordStatusEvent records the invoice moving from oldStatus to its current status in its
+// history. Nothing is recorded when the status did not change.
+func recordStatusEvent(invoice *models.Invoice, oldStatus string, at time.Time) {
+	switch {
+	case invoice.Status == oldStatus:
+		return
+	case invoice.Status == models.StatusSent && oldStatus == models.StatusDraft:
+		invoice.RecordEvent(models.EventSent, "Invoice sent", at)
+	default:
+		invoice.RecordEvent(models.EventStatusChanged, fmt.Sprintf("Status changed from %s to %s", oldStatus, invoice.Status), at)
+	}
+}
+
+// recordItemEvent records an item being added to the invoice in its history
+func recordItemEvent(invoice *models.Invoice, description string, total float64, at time.Time) {
+	invoice.RecordEvent(models.EventLineItemAdded,
+		fmt.Sprintf("Added %q (%s)", description, money.Format(total, invoice.Currency)), at)
+}
+
+// recordItemRemovedEvent records a line item being removed from the invoice in its history
+func recordItemRemovedEvent(invoice *models.Invoice, item models.LineItem, at time.Time) {
+	invoice.RecordEvent(models.EventLineItemRemoved,
+		fmt.Sprintf("Removed %q (%s)", item.Description, money.Format(item.Total.Float64(), invoice.Currency)), at)
+}
+
+// recordItemUpdatedEvent records a line item being changed in the invoice history
+func recordItemUpdatedEvent(invoice *models.Invoice, before, after models.LineItem, at time.Time) {
+	invoice.RecordEvent(models.EventLineItemUpdated,
+		fmt.Sprintf("Updated %q (%s → %s)", after.Description,
+			money.Format(before.Total.Float64(), invoice.Currency), money.Format(after.Total.Float64(), invoice.Currency)), at)
+}
+
+// recordPaymentEvent records a payment against the invoice in its history
+func recordPaymentEvent(invoice *models.Invoice, payment models.Payment, at time.Time) {
+	summary := fmt.Sprintf("Payment of %s recorded (%s)", money.Format(payment.Amount, invoice.Currency), payment.Method)
+	if payment.Reference != "" {
+		summary = fmt.Sprintf("Payment of %s recorded (%s, reference %s)", money.Format(payment.Amount, invoice.Currency), payment.Method, payment.Reference)
+	}
+	invoice.RecordEvent(models.EventPaymentRecorded, summary, at)
+}
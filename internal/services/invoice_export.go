@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// InvoiceExportSchemaVersion is the newest invoice export format this build reads and the one it writes
+const InvoiceExportSchemaVersion = 1
+
+var (
+	// ErrInvoiceExportMissing indicates an invoice export payload without an invoice.
+	ErrInvoiceExportMissing = fmt.Errorf("export does not contain an invoice")
+	// ErrInvoiceExportVersionMissing indicates an invoice export payload without a schema version.
+	ErrInvoiceExportVersionMissing = fmt.Errorf("export has no schema version")
+	// ErrInvoiceExportVersionUnsupported indicates an invoice export written by a newer version of go-invoice.
+	ErrInvoiceExportVersionUnsupported = fmt.Errorf("export schema version is newer than this version of go-invoice supports")
+)
+
+// InvoiceExport is a single invoice, with its embedded client, in a form that can be moved between data directories
+type InvoiceExport struct {
+	SchemaVersion int             `json:"schema_version"`
+	ExportedAt    time.Time       `json:"exported_at"`
+	Invoice       *models.Invoice `json:"invoice"`
+}
+
+// ImportInvoiceOptions controls how ImportInvoice stores an exported invoice
+type ImportInvoiceOptions struct {
+	KeepID bool `json:"keep_id"` // Keep the exported invoice ID instead of generating a new one
+}
+
+// ImportInvoiceResult reports the outcome of ImportInvoice
+type ImportInvoiceResult struct {
+	Invoice       *models.Invoice `json:"invoice"`
+	ClientCreated bool            `json:"client_created"` // The embedded client did not exist and was created
+}
+
+// ExportInvoice returns the invoice id wrapped for export
+func (s *InvoiceService) ExportInvoice(ctx context.Context, id models.InvoiceID) (*InvoiceExport, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	invoice, err := s.invoiceStorage.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve invoice: %w", err)
+	}
+
+	return &InvoiceExport{
+		SchemaVersion: InvoiceExportSchemaVersion,
+		ExportedAt:    time.Now(),
+		Invoice:       invoice,
+	}, nil
+}
+
+// ImportInvoice stores an exported invoice. The invoice gets a new ID unless options.KeepID
+// is set, its client is created from the embedded copy when it does not exist yet, and its
+// totals are recalculated rather than trusted. The invoice number must not be in use.
+func (s *InvoiceService) ImportInvoice(ctx context.Context, export *InvoiceExport, options ImportInvoiceOptions) (*ImportInvoiceResult, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	switch {
+	case export == nil || export.Invoice == nil:
+		return nil, ErrInvoiceExportMissing
+	case export.SchemaVersion == 0:
+		return nil, ErrInvoiceExportVersionMissing
+	case export.SchemaVersion > InvoiceExportSchemaVersion:
+		return nil, fmt.Errorf("%w (export version %d, supported up to %d)",
+			ErrInvoiceExportVersionUnsupported, export.SchemaVersion, InvoiceExportSchemaVersion)
+	}
+
+	invoice := export.Invoice
+	s.logger.Info("importing invoice", "id", invoice.ID, "number", invoice.Number, "client_id", invoice.Client.ID)
+
+	// Recalculate and validate before anything is stored, so a bad export creates no client
+	if err := invoice.RecalculateTotals(ctx); err != nil {
+		return nil, fmt.Errorf("failed to recalculate totals: %w", err)
+	}
+	invoice.Version = 1
+	invoice.UpdatedAt = time.Now()
+
+	if err := invoice.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid invoice in export: %w", err)
+	}
+
+	s.numberMu.Lock()
+	defer s.numberMu.Unlock()
+
+	if err := s.validateUniqueInvoiceNumber(ctx, invoice.Number); err != nil {
+		return nil, err
+	}
+
+	// The invoice keeps the client details it was issued with; the client record is only
+	// created from them when this data directory does not have it yet
+	result := &ImportInvoiceResult{Invoice: invoice}
+	_, err := s.clientStorage.GetClient(ctx, invoice.Client.ID)
+	switch {
+	case err == nil:
+	case storage.IsNotFound(err):
+		client := invoice.Client
+		if err = client.Validate(ctx); err != nil {
+			return nil, fmt.Errorf("invalid client in export: %w", err)
+		}
+		if err = s.clientStorage.CreateClient(ctx, &client); err != nil {
+			return nil, fmt.Errorf("failed to create client %s: %w", client.Name, err)
+		}
+		result.ClientCreated = true
+	default:
+		return nil, fmt.Errorf("failed to retrieve client: %w", err)
+	}
+
+	if !options.KeepID {
+		if invoice.ID, err = s.idGenerator.GenerateInvoiceID(ctx); err != nil {
+			return nil, fmt.Errorf("failed to generate invoice ID: %w", err)
+		}
+	}
+
+	if err = s.invoiceStorage.CreateInvoice(ctx, invoice); err != nil {
+		return nil, fmt.Errorf("failed to store invoice: %w", err)
+	}
+
+	s.logger.Info("invoice imported successfully", "id", invoice.ID, "number", invoice.Number,
+		"total", invoice.Total, "client_created", result.ClientCreated)
+	return result, nil
+}
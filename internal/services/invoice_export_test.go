@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+)
+
+// newExportTestStore returns an initialized store and an invoice service over it
+func newExportTestStore(ctx context.Context, t *testing.T) (*jsonStorage.JSONStorage, *InvoiceService) {
+	t.Helper()
+
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+	return store, NewInvoiceService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+}
+
+// exportRoundTrip exports id from service and decodes the JSON again, as a file would be
+func exportRoundTrip(ctx context.Context, t *testing.T, service *InvoiceService, id models.InvoiceID) *InvoiceExport {
+	t.Helper()
+
+	export, err := service.ExportInvoice(ctx, id)
+	require.NoError(t, err)
+	data, err := json.Marshal(export)
+	require.NoError(t, err)
+
+	var decoded InvoiceExport
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	return &decoded
+}
+
+func TestExportImportInvoiceToNewStore(t *testing.T) {
+	ctx := context.Background()
+	sourceStore, sourceService := newExportTestStore(ctx, t)
+	clients := NewClientService(sourceStore, sourceStore, &SimpleTestLogger{}, NewUUIDGenerator())
+	client, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Acme Corp", Email: "billing@acme.example"})
+	require.NoError(t, err)
+	source := newCloneSource(ctx, t, sourceStore, client)
+
+	export := exportRoundTrip(ctx, t, sourceService, source.ID)
+	assert.Equal(t, InvoiceExportSchemaVersion, export.SchemaVersion)
+	assert.Equal(t, "Acme Corp", export.Invoice.Client.Name)
+
+	// Tamper with the stored totals; import must not trust them
+	export.Invoice.Total = money.FromFloat(1)
+
+	targetStore, targetService := newExportTestStore(ctx, t)
+	result, err := targetService.ImportInvoice(ctx, export, ImportInvoiceOptions{})
+	require.NoError(t, err)
+	assert.True(t, result.ClientCreated)
+	assert.NotEqual(t, source.ID, result.Invoice.ID)
+	assert.Equal(t, source.Number, result.Invoice.Number)
+	assert.Equal(t, source.Total, result.Invoice.Total)
+
+	saved, err := targetStore.GetInvoice(ctx, result.Invoice.ID)
+	require.NoError(t, err)
+	assert.Len(t, saved.WorkItems, 1)
+	assert.Len(t, saved.LineItems, 1)
+	assert.Len(t, saved.Payments, 1)
+
+	importedClient, err := targetStore.GetClient(ctx, client.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Corp", importedClient.Name)
+}
+
+func TestImportInvoiceKeepID(t *testing.T) {
+	ctx := context.Background()
+	store, service := newExportTestStore(ctx, t)
+	clients := NewClientService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+	client, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Acme Corp", Email: "billing@acme.example"})
+	require.NoError(t, err)
+	source := newCloneSource(ctx, t, store, client)
+
+	export := exportRoundTrip(ctx, t, service, source.ID)
+
+	// The number is already in use in the same store
+	_, err = service.ImportInvoice(ctx, export, ImportInvoiceOptions{KeepID: true})
+	require.ErrorIs(t, err, models.ErrInvoiceNumberExists)
+
+	_, targetService := newExportTestStore(ctx, t)
+	result, err := targetService.ImportInvoice(ctx, export, ImportInvoiceOptions{KeepID: true})
+	require.NoError(t, err)
+	assert.Equal(t, source.ID, result.Invoice.ID)
+}
+
+func TestImportInvoiceRejectsBadPayloads(t *testing.T) {
+	ctx := context.Background()
+	_, service := newExportTestStore(ctx, t)
+
+	_, err := service.ImportInvoice(ctx, &InvoiceExport{SchemaVersion: InvoiceExportSchemaVersion}, ImportInvoiceOptions{})
+	require.ErrorIs(t, err, ErrInvoiceExportMissing)
+
+	_, err = service.ImportInvoice(ctx, &InvoiceExport{Invoice: &models.Invoice{}}, ImportInvoiceOptions{})
+	require.ErrorIs(t, err, ErrInvoiceExportVersionMissing)
+
+	_, err = service.ImportInvoice(ctx, &InvoiceExport{SchemaVersion: InvoiceExportSchemaVersion + 1, Invoice: &models.Invoice{}}, ImportInvoiceOptions{})
+	require.ErrorIs(t, err, ErrInvoiceExportVersionUnsupported)
+
+	_, err = service.ImportInvoice(ctx, &InvoiceExport{SchemaVersion: InvoiceExportSchemaVersion, Invoice: &models.Invoice{Number: "INV-0001"}}, ImportInvoiceOptions{})
+	require.ErrorIs(t, err, models.ErrInvoiceValidationFailed)
+}
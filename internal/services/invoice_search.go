@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// Invoice search fields, in ranking order
+const (
+	InvoiceSearchFieldNumber      = "number"
+	InvoiceSearchFieldClient      = "client"
+	InvoiceSearchFieldDescription = "description"
+	InvoiceSearchFieldItems       = "items"
+	InvoiceSearchFieldNotes       = "notes"
+)
+
+// invoiceSearchWeights is how much a query term found in each field adds to an invoice's
+// score; a field equal to the whole query earns its weight once more
+var invoiceSearchWeights = map[string]int{ //nolint:gochecknoglobals // Read-only ranking table
+	InvoiceSearchFieldNumber:      8,
+	InvoiceSearchFieldClient:      5,
+	InvoiceSearchFieldDescription: 4,
+	InvoiceSearchFieldItems:       2,
+	InvoiceSearchFieldNotes:       1,
+}
+
+// InvoiceSearchOptions narrows and limits SearchInvoices
+type InvoiceSearchOptions struct {
+	Filter models.InvoiceFilter `json:"filter"` // Structured filters such as status and dates; paging and sorting are ignored
+	Limit  int                  `json:"limit"`  // Maximum number of results, 0 for no limit
+}
+
+// InvoiceMatch is a ranked invoice search result
+type InvoiceMatch struct {
+	Invoice *models.Invoice `json:"invoice"`
+	Score   int             `json:"score"`
+	Fields  []string        `json:"fields"` // Fields the query matched, in ranking order
+}
+
+// SearchInvoices finds invoices whose number, client name, description, item descriptions
+// or notes contain every word of query, case-insensitively. Results are ranked by score,
+// which weighs where each word was found (number, client, description, items, notes),
+// then newest first. The structured filters in opts narrow the candidates through the
+// storage index before the remaining invoices are scanned for the text.
+func (s *InvoiceService) SearchInvoices(ctx context.Context, query string, opts InvoiceSearchOptions) ([]*InvoiceMatch, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	filter := opts.Filter
+	filter.Limit, filter.Offset = 0, 0
+	result, err := s.invoiceStorage.ListInvoices(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	for _, warning := range result.Warnings {
+		s.logger.Error("invoice skipped by search", "reason", warning)
+	}
+
+	var matches []*InvoiceMatch
+	for _, invoice := range result.Invoices {
+		if match, ok := matchInvoice(invoice, query, terms); ok {
+			matches = append(matches, match)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.Invoice.Date.Equal(b.Invoice.Date) {
+			return a.Invoice.Date.After(b.Invoice.Date)
+		}
+		return a.Invoice.Number < b.Invoice.Number
+	})
+
+	if opts.Limit > 0 && len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+
+	s.logger.Debug("invoice search completed", "query", query, "candidates", len(result.Invoices), "matches", len(matches))
+	return matches, nil
+}
+
+// matchInvoice scores invoice against the query terms. Every term must be found in at
+// least one field for the invoice to match.
+func matchInvoice(invoice *models.Invoice, query string, terms []string) (*InvoiceMatch, bool) {
+	itemDescriptions := make([]string, 0, len(invoice.LineItems)+len(invoice.WorkItems))
+	for _, item := range invoice.LineItems {
+		itemDescriptions = append(itemDescriptions, item.Description)
+	}
+	for _, item := range invoice.WorkItems {
+		itemDescriptions = append(itemDescriptions, item.Description)
+	}
+
+	fields := []struct{ name, value string }{
+		{InvoiceSearchFieldNumber, invoice.Number},
+		{InvoiceSearchFieldClient, invoice.Client.Name},
+		{InvoiceSearchFieldDescription, invoice.Description},
+		{InvoiceSearchFieldItems, strings.Join(itemDescriptions, "\n")},
+		{InvoiceSearchFieldNotes, invoice.Notes},
+	}
+
+	match := &InvoiceMatch{Invoice: invoice}
+	found := make([]bool, len(terms))
+	for _, field := range fields {
+		value := strings.ToLower(field.value)
+		if value == "" {
+			continue
+		}
+
+		weight := invoiceSearchWeights[field.name]
+		matched := false
+		for i, term := range terms {
+			if strings.Contains(value, term) {
+				match.Score += weight
+				found[i], matched = true, true
+			}
+		}
+		if !matched {
+			continue
+		}
+		if value == query {
+			match.Score += weight
+		}
+		match.Fields = append(match.Fields, field.name)
+	}
+
+	for _, ok := range found {
+		if !ok {
+			return nil, false
+		}
+	}
+	return match, true
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+func TestInvoiceServiceSearchInvoices(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	invoices := []*models.Invoice{
+		{ID: "I1", Number: "INV-001", Date: day(1), Client: models.Client{Name: "Acme Corp"}, Description: "Website redesign"},
+		{ID: "I2", Number: "INV-002", Date: day(2), Client: models.Client{Name: "Globex"}, Notes: "Consulting retainer for Q1"},
+		{ID: "I3", Number: "INV-003", Date: day(3), Client: models.Client{Name: "Initech"}, Description: "Consulting",
+			LineItems: []models.LineItem{{Description: "Strategy consulting"}}},
+		{ID: "I4", Number: "INV-004", Date: day(4), Client: models.Client{Name: "Umbrella"},
+			WorkItems: []models.WorkItem{{Description: "Consulting call"}}},
+	}
+
+	newService := func(filter models.InvoiceFilter) *InvoiceService {
+		invoiceStorage := new(MockInvoiceStorage)
+		invoiceStorage.On("ListInvoices", ctx, filter).Return(&storage.InvoiceListResult{Invoices: invoices}, nil)
+		return NewInvoiceService(invoiceStorage, nil, new(MockLogger), nil)
+	}
+
+	numbers := func(matches []*InvoiceMatch) []string {
+		result := make([]string, 0, len(matches))
+		for _, match := range matches {
+			result = append(result, match.Invoice.Number)
+		}
+		return result
+	}
+
+	t.Run("RanksByWhereTheTextWasFound", func(t *testing.T) {
+		matches, err := newService(models.InvoiceFilter{}).SearchInvoices(ctx, "  CONSULTING ", InvoiceSearchOptions{})
+		require.NoError(t, err)
+
+		// Description and items outrank items alone, which outrank notes
+		assert.Equal(t, []string{"INV-003", "INV-004", "INV-002"}, numbers(matches))
+		assert.Equal(t, []string{InvoiceSearchFieldDescription, InvoiceSearchFieldItems}, matches[0].Fields)
+		assert.Equal(t, []string{InvoiceSearchFieldNotes}, matches[2].Fields)
+	})
+
+	t.Run("EveryWordMustMatch", func(t *testing.T) {
+		matches, err := newService(models.InvoiceFilter{}).SearchInvoices(ctx, "acme redesign", InvoiceSearchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"INV-001"}, numbers(matches))
+
+		matches, err = newService(models.InvoiceFilter{}).SearchInvoices(ctx, "acme consulting", InvoiceSearchOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("NumberAndLimit", func(t *testing.T) {
+		matches, err := newService(models.InvoiceFilter{}).SearchInvoices(ctx, "inv-00", InvoiceSearchOptions{Limit: 2})
+		require.NoError(t, err)
+
+		// Equal scores are newest first
+		assert.Equal(t, []string{"INV-004", "INV-003"}, numbers(matches))
+	})
+
+	t.Run("FilterIgnoresPaging", func(t *testing.T) {
+		filter := models.InvoiceFilter{Status: models.StatusSent}
+		opts := InvoiceSearchOptions{Filter: models.InvoiceFilter{Status: models.StatusSent, Limit: 1, Offset: 3}}
+
+		matches, err := newService(filter).SearchInvoices(ctx, "consulting", opts)
+		require.NoError(t, err)
+		assert.Len(t, matches, 3)
+	})
+
+	t.Run("BlankQuery", func(t *testing.T) {
+		matches, err := NewInvoiceService(new(MockInvoiceStorage), nil, new(MockLogger), nil).SearchInvoices(ctx, "  ", InvoiceSearchOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/notify"
 	"github.com/mrz1836/go-invoice/internal/storage"
 )
 
@@ -15,8 +19,13 @@ import (
 var (
 	ErrInvoiceNumberEmpty    = errors.New("invoice number cannot be empty")
 	ErrInvoiceNumberNotFound = errors.New("invoice not found")
+	ErrConcurrentUpdate      = errors.New("invoice kept changing during the update")
 )
 
+// maxUpdateAttempts is how many times UpdateInvoiceWithRetry saves an invoice before
+// giving up on concurrent edits
+const maxUpdateAttempts = 3
+
 // Logger interface for service operations
 type Logger interface {
 	Info(msg string, fields ...any)
@@ -38,6 +47,11 @@ type InvoiceService struct {
 	clientStorage  storage.ClientStorage
 	logger         Logger
 	idGenerator    IDGenerator
+	notifier       notify.Notifier // Optional, told about status changes
+	legacyItems    LegacyItemsPolicy
+
+	// numberMu serializes number allocation with invoice creation so generated numbers stay unique
+	numberMu sync.Mutex
 }
 
 // NewInvoiceService creates a new invoice service with injected dependencies
@@ -55,6 +69,30 @@ func NewInvoiceService(
 	}
 }
 
+// SetNotifier sets the notifier told about invoice status changes made by the service
+func (s *InvoiceService) SetNotifier(notifier notify.Notifier) {
+	s.notifier = notifier
+}
+
+// LegacyItemsPolicy decides what AddLineItemToInvoice does with an invoice that still holds
+// only legacy work items
+type LegacyItemsPolicy string
+
+const (
+	// LegacyItemsMigrate converts the work items to line items before adding, so the invoice
+	// holds a single kind of item. It is the default.
+	LegacyItemsMigrate LegacyItemsPolicy = "migrate"
+	// LegacyItemsKeep leaves the work items in place and adds the line item beside them,
+	// logging that the invoice now mixes both kinds
+	LegacyItemsKeep LegacyItemsPolicy = "keep"
+)
+
+// SetLegacyItemsPolicy sets how line items are added to invoices with only legacy work items.
+// An empty policy is LegacyItemsMigrate.
+func (s *InvoiceService) SetLegacyItemsPolicy(policy LegacyItemsPolicy) {
+	s.legacyItems = policy
+}
+
 // CreateInvoice creates a new invoice with business logic validation
 func (s *InvoiceService) CreateInvoice(ctx context.Context, req models.CreateInvoiceRequest) (*models.Invoice, error) {
 	select {
@@ -63,6 +101,48 @@ func (s *InvoiceService) CreateInvoice(ctx context.Context, req models.CreateInv
 	default:
 	}
 
+	s.numberMu.Lock()
+	defer s.numberMu.Unlock()
+
+	return s.createInvoice(ctx, req)
+}
+
+// CreateInvoiceWithNextNumber creates a new invoice numbered with the next available
+// number for numbering. Storage rejects a number another process stored in the meantime,
+// and the number is then picked again, so concurrent creates never receive the same number.
+func (s *InvoiceService) CreateInvoiceWithNextNumber(ctx context.Context, req models.CreateInvoiceRequest, numbering models.InvoiceNumbering) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.numberMu.Lock()
+	defer s.numberMu.Unlock()
+
+	return s.withNextInvoiceNumber(ctx, numbering, req.Date, func(number string) (*models.Invoice, error) {
+		req.Number = number
+		return s.createInvoice(ctx, req)
+	})
+}
+
+// NextInvoiceNumber returns the number the next invoice created with numbering would receive.
+// It is intended for previews; use CreateInvoiceWithNextNumber to allocate the number.
+func (s *InvoiceService) NextInvoiceNumber(ctx context.Context, numbering models.InvoiceNumbering, date time.Time) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	default:
+	}
+
+	s.numberMu.Lock()
+	defer s.numberMu.Unlock()
+
+	return s.nextInvoiceNumber(ctx, numbering, date)
+}
+
+// createInvoice validates and stores a new invoice; callers must hold numberMu
+func (s *InvoiceService) createInvoice(ctx context.Context, req models.CreateInvoiceRequest) (*models.Invoice, error) {
 	s.logger.Info("creating invoice", "number", req.Number, "client_id", req.ClientID)
 
 	// Validate request
@@ -79,6 +159,49 @@ func (s *InvoiceService) CreateInvoice(ctx context.Context, req models.CreateInv
 		return nil, fmt.Errorf("failed to retrieve client: %w", err)
 	}
 
+	invoice, err := s.buildInvoice(ctx, req, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store invoice
+	if err := s.invoiceStorage.CreateInvoice(ctx, invoice); err != nil {
+		return nil, fmt.Errorf("failed to store invoice: %w", err)
+	}
+
+	s.logger.Info("invoice created successfully", "id", invoice.ID, "number", invoice.Number, "total", invoice.Total)
+	return invoice, nil
+}
+
+// PreviewInvoiceWithNextNumber builds the invoice CreateInvoiceWithNextNumber would
+// store for client, without storing anything. client may be one that has not been
+// created yet; req.ClientID is taken from it.
+func (s *InvoiceService) PreviewInvoiceWithNextNumber(ctx context.Context, req models.CreateInvoiceRequest, numbering models.InvoiceNumbering, client *models.Client) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.numberMu.Lock()
+	defer s.numberMu.Unlock()
+
+	number, err := s.nextInvoiceNumber(ctx, numbering, req.Date)
+	if err != nil {
+		return nil, err
+	}
+	req.Number = number
+	req.ClientID = client.ID
+
+	if err := req.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid create invoice request: %w", err)
+	}
+
+	return s.buildInvoice(ctx, req, client)
+}
+
+// buildInvoice creates the invoice model for a validated request without storing it
+func (s *InvoiceService) buildInvoice(ctx context.Context, req models.CreateInvoiceRequest, client *models.Client) (*models.Invoice, error) {
 	if !client.Active {
 		return nil, fmt.Errorf("%w: %s", models.ErrClientInactive, client.Name)
 	}
@@ -95,7 +218,7 @@ func (s *InvoiceService) CreateInvoice(ctx context.Context, req models.CreateInv
 	}
 
 	// Create invoice with work items
-	invoice, err := models.NewInvoice(ctx, invoiceID, req.Number, req.Date, req.DueDate, *client, 0.0)
+	invoice, err := models.NewInvoice(ctx, invoiceID, req.Number, req.Date, req.DueDate, *client, req.TaxRate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create invoice model: %w", err)
 	}
@@ -104,15 +227,20 @@ func (s *InvoiceService) CreateInvoice(ctx context.Context, req models.CreateInv
 	if req.Description != "" {
 		invoice.Description = req.Description
 	}
+	invoice.PONumber = req.PONumber
+	invoice.ClientReference = req.ClientReference
 
 	// Set crypto address overrides if provided
-	if req.USDCAddress != nil {
-		invoice.USDCAddressOverride = req.USDCAddress
-	}
-	if req.BSVAddress != nil {
-		invoice.BSVAddressOverride = req.BSVAddress
+	if err := setAddressOverrides(ctx, invoice, req.USDCAddress, req.BSVAddress); err != nil {
+		return nil, err
 	}
 
+	// Set invoice-specific template, currency and tax rounding if provided
+	invoice.TemplateName = req.TemplateName
+	invoice.Currency = req.Currency
+	invoice.TaxRounding = req.TaxRounding
+	invoice.RecordEvent(models.EventCreated, fmt.Sprintf("Invoice %s created for %s", invoice.Number, client.Name), invoice.CreatedAt)
+
 	// Add work items if provided
 	for _, workItemReq := range req.WorkItems {
 		workItemID, err := s.idGenerator.GenerateWorkItemID(ctx)
@@ -131,12 +259,6 @@ func (s *InvoiceService) CreateInvoice(ctx context.Context, req models.CreateInv
 		}
 	}
 
-	// Store invoice
-	if err := s.invoiceStorage.CreateInvoice(ctx, invoice); err != nil {
-		return nil, fmt.Errorf("failed to store invoice: %w", err)
-	}
-
-	s.logger.Info("invoice created successfully", "id", invoice.ID, "number", invoice.Number, "total", invoice.Total)
 	return invoice, nil
 }
 
@@ -172,8 +294,13 @@ func (s *InvoiceService) GetInvoiceByNumber(ctx context.Context, number string)
 		return nil, ErrInvoiceNumberEmpty
 	}
 
+	return s.findInvoiceByNumber(ctx, number, false)
+}
+
+// findInvoiceByNumber searches the stored invoices for number
+func (s *InvoiceService) findInvoiceByNumber(ctx context.Context, number string, includeDeleted bool) (*models.Invoice, error) {
 	// Use list functionality to find invoice by number
-	filter := models.InvoiceFilter{}
+	filter := models.InvoiceFilter{IncludeDeleted: includeDeleted}
 	result, err := s.invoiceStorage.ListInvoices(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search for invoice: %w", err)
@@ -203,18 +330,67 @@ func (s *InvoiceService) UpdateInvoice(ctx context.Context, req models.UpdateInv
 		return nil, fmt.Errorf("invalid update invoice request: %w", err)
 	}
 
-	// Get existing invoice
-	invoice, err := s.invoiceStorage.GetInvoice(ctx, req.ID)
+	invoice, err := s.UpdateInvoiceWithRetry(ctx, req.ID, func(invoice *models.Invoice) error {
+		return s.applyInvoiceUpdate(ctx, invoice, req)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve invoice for update: %w", err)
+		return nil, err
 	}
 
-	// Apply updates
+	s.logger.Info("invoice updated successfully", "id", invoice.ID, "version", invoice.Version)
+	return invoice, nil
+}
+
+// UpdateInvoiceWithRetry loads an invoice, applies mutate and saves the result. When another
+// writer saved the invoice in the meantime (a version mismatch), the latest version is
+// reloaded and mutate runs again, up to maxUpdateAttempts times. Errors from mutate, such as
+// a business rule rejecting the change, are returned as-is without retrying.
+//
+// mutate may run more than once, so it must only change the invoice it is given.
+func (s *InvoiceService) UpdateInvoiceWithRetry(ctx context.Context, id models.InvoiceID, mutate func(*models.Invoice) error) (*models.Invoice, error) {
+	var saveErr error
+	for attempt := 1; attempt <= maxUpdateAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		invoice, err := s.invoiceStorage.GetInvoice(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve invoice: %w", err)
+		}
+		oldStatus := invoice.Status
+
+		if err := mutate(invoice); err != nil {
+			return nil, err
+		}
+		// Recorded on the copy being saved, so a retry records it again on the reloaded invoice
+		// rather than conflicting with the other writer
+		recordStatusEvent(invoice, oldStatus, time.Now())
+
+		saveErr = s.invoiceStorage.UpdateInvoice(ctx, invoice)
+		if saveErr == nil {
+			notifyStatusChange(ctx, s.notifier, invoice, oldStatus)
+			return invoice, nil
+		}
+		if !storage.IsVersionMismatch(saveErr) {
+			return nil, fmt.Errorf("failed to update invoice in storage: %w", saveErr)
+		}
+
+		s.logger.Debug("invoice changed during update, retrying", "id", id, "attempt", attempt)
+	}
+
+	return nil, fmt.Errorf("%w after %d attempts: %w", ErrConcurrentUpdate, maxUpdateAttempts, saveErr)
+}
+
+// applyInvoiceUpdate applies the fields set in an update request to an invoice
+func (s *InvoiceService) applyInvoiceUpdate(ctx context.Context, invoice *models.Invoice, req models.UpdateInvoiceRequest) error {
 	if req.Number != nil {
 		// Check if new number is unique (excluding this invoice)
 		if *req.Number != invoice.Number {
 			if err := s.validateUniqueInvoiceNumber(ctx, *req.Number); err != nil {
-				return nil, err
+				return err
 			}
 		}
 		invoice.Number = *req.Number
@@ -228,34 +404,99 @@ func (s *InvoiceService) UpdateInvoice(ctx context.Context, req models.UpdateInv
 		invoice.DueDate = *req.DueDate
 	}
 
+	if req.Description != nil {
+		invoice.Description = *req.Description
+	}
+
+	if req.Notes != nil {
+		invoice.Notes = *req.Notes
+	}
+
+	if req.PONumber != nil {
+		invoice.PONumber = *req.PONumber
+	}
+
+	if req.ClientReference != nil {
+		invoice.ClientReference = *req.ClientReference
+	}
+
+	// Update crypto address overrides if provided
+	if err := setAddressOverrides(ctx, invoice, req.USDCAddress, req.BSVAddress); err != nil {
+		return err
+	}
+
+	if req.TemplateName != nil {
+		invoice.TemplateName = *req.TemplateName
+	}
+
+	if req.DiscountPercent != nil || req.DiscountAmount != nil {
+		if err := applyInvoiceDiscount(ctx, invoice, req.DiscountPercent, req.DiscountAmount); err != nil {
+			return err
+		}
+	}
+
+	// Change the status last: sending locks the invoice, and the changes above belong to the
+	// invoice as it is being sent
 	if req.Status != nil {
 		if err := invoice.UpdateStatus(ctx, *req.Status); err != nil {
-			return nil, fmt.Errorf("failed to update invoice status: %w", err)
+			return fmt.Errorf("failed to update invoice status: %w", err)
 		}
 	}
 
-	if req.Description != nil {
-		invoice.Description = *req.Description
+	return nil
+}
+
+// notifyStatusChange tells notifier that invoice moved from oldStatus to its current
+// status. Nothing is sent without a notifier or when the status did not change.
+func notifyStatusChange(ctx context.Context, notifier notify.Notifier, invoice *models.Invoice, oldStatus string) {
+	if notifier == nil || invoice.Status == oldStatus {
+		return
 	}
+	notifier.Notify(ctx, notify.NewStatusChangeEvent(invoice, oldStatus))
+}
 
-	// Update crypto address overrides if provided
-	if req.USDCAddress != nil {
-		invoice.USDCAddressOverride = req.USDCAddress
+// setAddressOverrides validates and sets the crypto address overrides that were provided
+func setAddressOverrides(ctx context.Context, invoice *models.Invoice, usdcAddress, bsvAddress *string) error {
+	if usdcAddress != nil {
+		if err := invoice.SetUSDCAddressOverride(ctx, *usdcAddress); err != nil {
+			return fmt.Errorf("failed to set USDC address override: %w", err)
+		}
 	}
-	if req.BSVAddress != nil {
-		invoice.BSVAddressOverride = req.BSVAddress
+	if bsvAddress != nil {
+		if err := invoice.SetBSVAddressOverride(ctx, *bsvAddress); err != nil {
+			return fmt.Errorf("failed to set BSV address override: %w", err)
+		}
 	}
+	return nil
+}
 
-	// Update invoice in storage
-	if err := s.invoiceStorage.UpdateInvoice(ctx, invoice); err != nil {
-		return nil, fmt.Errorf("failed to update invoice in storage: %w", err)
+// applyInvoiceDiscount sets the invoice discount and recalculates the totals. A fixed discount
+// larger than the subtotal is rejected rather than capped, so the user sees the mistake.
+func applyInvoiceDiscount(ctx context.Context, invoice *models.Invoice, percent, amount *float64) error {
+	if err := invoice.EnsureUnlocked(); err != nil {
+		return err
+	}
+	if percent != nil {
+		invoice.DiscountPercent = *percent
+	}
+	if amount != nil {
+		invoice.DiscountAmount = money.FromFloat(*amount)
+	}
+	if invoice.DiscountPercent > 0 && invoice.DiscountAmount > 0 {
+		return models.ErrConflictingDiscounts
 	}
 
-	s.logger.Info("invoice updated successfully", "id", invoice.ID, "version", invoice.Version)
-	return invoice, nil
+	if err := invoice.RecalculateTotals(ctx); err != nil {
+		return fmt.Errorf("failed to recalculate invoice totals: %w", err)
+	}
+	if invoice.DiscountAmount > invoice.Subtotal {
+		return fmt.Errorf("%w: discount %s, subtotal %s", models.ErrDiscountExceedsAmount, invoice.DiscountAmount, invoice.Subtotal)
+	}
+	return nil
 }
 
-// DeleteInvoice deletes an invoice
+// DeleteInvoice soft deletes an invoice: it is marked as deleted and hidden from listings,
+// but kept on disk so it can be brought back with RestoreInvoice
 func (s *InvoiceService) DeleteInvoice(ctx context.Context, id models.InvoiceID) error {
 	select {
 	case <-ctx.Done():
@@ -265,14 +506,42 @@ func (s *InvoiceService) DeleteInvoice(ctx context.Context, id models.InvoiceID)
 
 	s.logger.Info("deleting invoice", "id", id)
 
-	// Check if invoice exists and can be deleted - try by ID first, then by number
-	invoice, err := s.invoiceStorage.GetInvoice(ctx, id)
+	invoice, err := s.FindInvoice(ctx, string(id), true)
 	if err != nil {
-		// If not found by ID, try by number
-		invoice, err = s.GetInvoiceByNumber(ctx, string(id))
-		if err != nil {
-			return fmt.Errorf("failed to retrieve invoice for deletion: %w: invoice with ID '%s' not found", models.ErrInvoiceNotFound, id)
-		}
+		return fmt.Errorf("failed to retrieve invoice for deletion: %w", err)
+	}
+
+	// Business rule: don't delete paid invoices
+	if invoice.Status == models.StatusPaid {
+		return fmt.Errorf("%w: %s", models.ErrCannotDeletePaidInvoice, invoice.Number)
+	}
+	if invoice.IsDeleted() {
+		return fmt.Errorf("%w: %s", models.ErrInvoiceAlreadyDeleted, invoice.Number)
+	}
+
+	now := time.Now()
+	invoice.DeletedAt = &now
+	if err := s.invoiceStorage.UpdateInvoice(ctx, invoice); err != nil {
+		return fmt.Errorf("failed to delete invoice: %w", err)
+	}
+
+	s.logger.Info("invoice deleted successfully", "id", invoice.ID, "number", invoice.Number)
+	return nil
+}
+
+// HardDeleteInvoice permanently removes an invoice, including one that is in the trash
+func (s *InvoiceService) HardDeleteInvoice(ctx context.Context, id models.InvoiceID) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	s.logger.Info("permanently deleting invoice", "id", id)
+
+	invoice, err := s.FindInvoice(ctx, string(id), true)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve invoice for deletion: %w", err)
 	}
 
 	// Business rule: don't delete paid invoices
@@ -285,10 +554,55 @@ func (s *InvoiceService) DeleteInvoice(ctx context.Context, id models.InvoiceID)
 		return fmt.Errorf("failed to delete invoice: %w", err)
 	}
 
-	s.logger.Info("invoice deleted successfully", "id", id, "number", invoice.Number)
+	s.logger.Info("invoice permanently deleted", "id", invoice.ID, "number", invoice.Number)
 	return nil
 }
 
+// RestoreInvoice brings a soft-deleted invoice back from the trash
+func (s *InvoiceService) RestoreInvoice(ctx context.Context, id models.InvoiceID) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.logger.Info("restoring invoice", "id", id)
+
+	invoice, err := s.FindInvoice(ctx, string(id), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve invoice for restore: %w", err)
+	}
+	if !invoice.IsDeleted() {
+		return nil, fmt.Errorf("%w: %s", models.ErrInvoiceNotDeleted, invoice.Number)
+	}
+
+	invoice.DeletedAt = nil
+	if err := s.invoiceStorage.UpdateInvoice(ctx, invoice); err != nil {
+		return nil, fmt.Errorf("failed to restore invoice: %w", err)
+	}
+
+	s.logger.Info("invoice restored", "id", invoice.ID, "number", invoice.Number)
+	return invoice, nil
+}
+
+// FindInvoice looks an invoice up by ID, then by number. Soft-deleted invoices are only
+// found, by either, when includeDeleted is set.
+func (s *InvoiceService) FindInvoice(ctx context.Context, identifier string, includeDeleted bool) (*models.Invoice, error) {
+	invoice, err := s.invoiceStorage.GetInvoice(ctx, models.InvoiceID(identifier))
+	if err == nil {
+		if invoice.IsDeleted() && !includeDeleted {
+			return nil, fmt.Errorf("%w: invoice with ID '%s' is in the trash", models.ErrInvoiceNotFound, identifier)
+		}
+		return invoice, nil
+	}
+
+	invoice, err = s.findInvoiceByNumber(ctx, identifier, includeDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invoice with ID '%s' not found", models.ErrInvoiceNotFound, identifier)
+	}
+	return invoice, nil
+}
+
 // ListInvoices retrieves invoices with filtering and pagination
 func (s *InvoiceService) ListInvoices(ctx context.Context, filter models.InvoiceFilter) (*storage.InvoiceListResult, error) {
 	select {
@@ -302,7 +616,7 @@ func (s *InvoiceService) ListInvoices(ctx context.Context, filter models.Invoice
 		return nil, fmt.Errorf("failed to list invoices: %w", err)
 	}
 
-	s.logger.Debug("listed invoices", "count", len(result.Invoices), "total", result.TotalCount)
+	s.logger.Debug("listed invoices", "count", len(result.Invoices), "total", result.TotalCount, "skipped", len(result.Warnings))
 	return result, nil
 }
 
@@ -322,8 +636,11 @@ func (s *InvoiceService) AddWorkItemToInvoice(ctx context.Context, invoiceID mod
 		return nil, fmt.Errorf("failed to retrieve invoice: %w", err)
 	}
 
-	// Business rule: can only add work items to draft invoices
-	if invoice.Status != models.StatusDraft {
+	// Business rule: can only add work items to draft or explicitly unlocked invoices
+	if err := invoice.EnsureUnlocked(); err != nil {
+		return nil, err
+	}
+	if !invoice.AllowsItemChanges() {
 		return nil, fmt.Errorf("%w, current status: %s", models.ErrCannotAddWorkItemToNonDraft, invoice.Status)
 	}
 
@@ -343,6 +660,7 @@ func (s *InvoiceService) AddWorkItemToInvoice(ctx context.Context, invoiceID mod
 	if err := invoice.AddWorkItemWithoutVersionIncrement(ctx, workItemData); err != nil {
 		return nil, fmt.Errorf("failed to add work item: %w", err)
 	}
+	recordItemEvent(invoice, workItemData.Description, workItemData.Total, workItemData.CreatedAt)
 
 	// Update invoice in storage
 	if err := s.invoiceStorage.UpdateInvoice(ctx, invoice); err != nil {
@@ -369,8 +687,11 @@ func (s *InvoiceService) AddLineItemToInvoice(ctx context.Context, invoiceID mod
 		return nil, fmt.Errorf("failed to retrieve invoice: %w", err)
 	}
 
-	// Business rule: can only add line items to draft invoices
-	if invoice.Status != models.StatusDraft {
+	// Business rule: can only add line items to draft or explicitly unlocked invoices
+	if err := invoice.EnsureUnlocked(); err != nil {
+		return nil, err
+	}
+	if !invoice.AllowsItemChanges() {
 		return nil, fmt.Errorf("%w, current status: %s", models.ErrCannotAddWorkItemToNonDraft, invoice.Status)
 	}
 
@@ -386,10 +707,24 @@ func (s *InvoiceService) AddLineItemToInvoice(ctx context.Context, invoiceID mod
 	// Set creation time
 	lineItemData.CreatedAt = time.Now()
 
+	// Converge invoices holding only legacy work items on line items first, unless configured
+	// to keep them; totals count both kinds, each item once
+	if invoice.HasOnlyWorkItems() {
+		if s.legacyItems == LegacyItemsKeep {
+			s.logger.Info("adding line item beside legacy work items", "invoice_id", invoiceID, "work_items", len(invoice.WorkItems))
+		} else {
+			s.logger.Info("migrating legacy work items before adding line item", "invoice_id", invoiceID, "work_items", len(invoice.WorkItems))
+			if err := invoice.MigrateWorkItemsToLineItems(ctx); err != nil {
+				return nil, fmt.Errorf("failed to migrate work items: %w", err)
+			}
+		}
+	}
+
 	// Add line item to invoice
 	if err := invoice.AddLineItemWithoutVersionIncrement(ctx, lineItemData); err != nil {
 		return nil, fmt.Errorf("failed to add line item: %w", err)
 	}
+	recordItemEvent(invoice, lineItemData.Description, lineItemData.Total.Float64(), lineItemData.CreatedAt)
 
 	// Update invoice in storage
 	if err := s.invoiceStorage.UpdateInvoice(ctx, invoice); err != nil {
@@ -439,8 +774,11 @@ func (s *InvoiceService) RemoveWorkItemFromInvoice(ctx context.Context, invoiceI
 		return nil, fmt.Errorf("failed to retrieve invoice: %w", err)
 	}
 
-	// Business rule: can only remove work items from draft invoices
-	if invoice.Status != models.StatusDraft {
+	// Business rule: can only remove work items from draft or explicitly unlocked invoices
+	if err := invoice.EnsureUnlocked(); err != nil {
+		return nil, err
+	}
+	if !invoice.AllowsItemChanges() {
 		return nil, fmt.Errorf("%w, current status: %s", models.ErrCannotRemoveWorkItemFromNonDraft, invoice.Status)
 	}
 
@@ -458,40 +796,127 @@ func (s *InvoiceService) RemoveWorkItemFromInvoice(ctx context.Context, invoiceI
 	return invoice, nil
 }
 
-// SendInvoice marks an invoice as sent and updates the status
-func (s *InvoiceService) SendInvoice(ctx context.Context, id models.InvoiceID) (*models.Invoice, error) {
+// RemoveLineItemFromInvoice removes a line item from an invoice and recalculates its totals
+func (s *InvoiceService) RemoveLineItemFromInvoice(ctx context.Context, invoiceID models.InvoiceID, lineItemID string) (*models.Invoice, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	default:
 	}
 
-	s.logger.Info("sending invoice", "id", id)
+	s.logger.Info("removing line item from invoice", "invoice_id", invoiceID, "line_item_id", lineItemID)
 
-	// Get existing invoice
-	invoice, err := s.invoiceStorage.GetInvoice(ctx, id)
+	invoice, err := s.UpdateInvoiceWithRetry(ctx, invoiceID, func(invoice *models.Invoice) error {
+		if err := ensureLineItemsEditable(invoice); err != nil {
+			return err
+		}
+		item := invoice.FindLineItem(lineItemID)
+		if item == nil {
+			return fmt.Errorf("%w: %s", models.ErrLineItemNotFound, lineItemID)
+		}
+		removed := *item
+
+		if err := invoice.RemoveLineItemWithoutVersionIncrement(ctx, lineItemID); err != nil {
+			return fmt.Errorf("failed to remove line item: %w", err)
+		}
+		recordItemRemovedEvent(invoice, removed, time.Now())
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve invoice: %w", err)
+		return nil, err
+	}
+
+	s.logger.Info("line item removed successfully", "invoice_id", invoiceID, "line_item_id", lineItemID)
+	return invoice, nil
+}
+
+// UpdateLineItemOnInvoice applies update to a copy of a line item, recalculates the item
+// and invoice totals and saves the result. update may run more than once, see
+// UpdateInvoiceWithRetry.
+func (s *InvoiceService) UpdateLineItemOnInvoice(ctx context.Context, invoiceID models.InvoiceID, lineItemID string, update func(*models.LineItem) error) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
 	}
 
-	// Business rule: can only send draft invoices
-	if invoice.Status != models.StatusDraft {
-		return nil, fmt.Errorf("%w, current status: %s", models.ErrCannotSendNonDraftInvoice, invoice.Status)
+	s.logger.Info("updating line item on invoice", "invoice_id", invoiceID, "line_item_id", lineItemID)
+
+	invoice, err := s.UpdateInvoiceWithRetry(ctx, invoiceID, func(invoice *models.Invoice) error {
+		if err := ensureLineItemsEditable(invoice); err != nil {
+			return err
+		}
+		item := invoice.FindLineItem(lineItemID)
+		if item == nil {
+			return fmt.Errorf("%w: %s", models.ErrLineItemNotFound, lineItemID)
+		}
+		before, updated := *item, *item
+
+		if err := update(&updated); err != nil {
+			return err
+		}
+		updated.ID = lineItemID
+		if err := updated.RecalculateTotal(ctx); err != nil {
+			return fmt.Errorf("failed to recalculate line item total: %w", err)
+		}
+		if err := invoice.ReplaceLineItem(ctx, updated); err != nil {
+			return fmt.Errorf("failed to update line item: %w", err)
+		}
+		recordItemUpdatedEvent(invoice, before, updated, time.Now())
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Business rule: invoice must have work items
-	if len(invoice.WorkItems) == 0 {
-		return nil, models.ErrCannotSendEmptyInvoice
+	s.logger.Info("line item updated successfully", "invoice_id", invoiceID, "line_item_id", lineItemID)
+	return invoice, nil
+}
+
+// ensureLineItemsEditable returns an error unless the invoice's line items may change: it
+// must not be paid or locked, and must be a draft or explicitly unlocked
+func ensureLineItemsEditable(invoice *models.Invoice) error {
+	if invoice.Status == models.StatusPaid {
+		return fmt.Errorf("%w: %s", models.ErrCannotChangeLineItemsOnPaid, invoice.Number)
+	}
+	if err := invoice.EnsureUnlocked(); err != nil {
+		return err
+	}
+	if !invoice.AllowsItemChanges() {
+		return fmt.Errorf("%w, current status: %s", models.ErrCannotChangeLineItemsOnNonDraft, invoice.Status)
 	}
+	return nil
+}
 
-	// Update status to sent
-	if err := invoice.UpdateStatus(ctx, models.StatusSent); err != nil {
-		return nil, fmt.Errorf("failed to update invoice status: %w", err)
+// SendInvoice marks an invoice as sent and updates the status
+func (s *InvoiceService) SendInvoice(ctx context.Context, id models.InvoiceID) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
 	}
 
-	// Update invoice in storage
-	if err := s.invoiceStorage.UpdateInvoice(ctx, invoice); err != nil {
-		return nil, fmt.Errorf("failed to update invoice status in storage: %w", err)
+	s.logger.Info("sending invoice", "id", id)
+
+	invoice, err := s.UpdateInvoiceWithRetry(ctx, id, func(invoice *models.Invoice) error {
+		// Business rule: can only send draft invoices
+		if invoice.Status != models.StatusDraft {
+			return fmt.Errorf("%w, current status: %s", models.ErrCannotSendNonDraftInvoice, invoice.Status)
+		}
+
+		// Business rule: invoice must have work items
+		if len(invoice.WorkItems) == 0 {
+			return models.ErrCannotSendEmptyInvoice
+		}
+
+		// Update status to sent
+		if err := invoice.UpdateStatus(ctx, models.StatusSent); err != nil {
+			return fmt.Errorf("failed to update invoice status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	s.logger.Info("invoice sent successfully", "id", id, "number", invoice.Number)
@@ -508,33 +933,211 @@ func (s *InvoiceService) MarkInvoicePaid(ctx context.Context, id models.InvoiceI
 
 	s.logger.Info("marking invoice as paid", "id", id)
 
+	invoice, err := s.UpdateInvoiceWithRetry(ctx, id, func(invoice *models.Invoice) error {
+		// Business rule: can only mark sent or overdue invoices as paid
+		if invoice.Status != models.StatusSent && invoice.Status != models.StatusOverdue {
+			return fmt.Errorf("%w, current status: %s", models.ErrCannotMarkNonSentAsPaid, invoice.Status)
+		}
+
+		// Update status to paid
+		if err := invoice.UpdateStatus(ctx, models.StatusPaid); err != nil {
+			return fmt.Errorf("failed to update invoice status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("invoice marked as paid", "id", id, "number", invoice.Number, "amount", invoice.Total)
+	return invoice, nil
+}
+
+// RecordPayment records a full or partial payment against an invoice. The invoice moves to
+// paid automatically once its balance is settled.
+func (s *InvoiceService) RecordPayment(ctx context.Context, id models.InvoiceID, payment models.Payment) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.logger.Info("recording payment", "id", id, "amount", payment.Amount, "method", payment.Method)
+
+	invoice, err := s.UpdateInvoiceWithRetry(ctx, id, func(invoice *models.Invoice) error {
+		if err := invoice.RecordPayment(ctx, payment); err != nil {
+			return fmt.Errorf("failed to record payment: %w", err)
+		}
+		recordPaymentEvent(invoice, payment, time.Now())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("payment recorded", "id", id, "number", invoice.Number, "paid", invoice.AmountPaid(), "due", invoice.AmountDue())
+	return invoice, nil
+}
+
+// UnlockInvoice unlocks a sent invoice so its financial details can be corrected. Who unlocked
+// it and why is recorded on the invoice.
+func (s *InvoiceService) UnlockInvoice(ctx context.Context, id models.InvoiceID, by, reason string) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.logger.Info("unlocking invoice", "id", id, "by", by)
+
+	invoice, err := s.UpdateInvoiceWithRetry(ctx, id, func(invoice *models.Invoice) error {
+		return invoice.Unlock(by, reason, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("invoice unlocked", "id", id, "number", invoice.Number, "by", by, "reason", reason)
+	return invoice, nil
+}
+
+// LockInvoice locks an invoice's financial details again, for example once an unlocked
+// invoice has been corrected. Locking an invoice that is already locked changes nothing.
+func (s *InvoiceService) LockInvoice(ctx context.Context, id models.InvoiceID) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.logger.Info("locking invoice", "id", id)
+
+	invoice, err := s.UpdateInvoiceWithRetry(ctx, id, func(invoice *models.Invoice) error {
+		invoice.Lock(time.Now())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("invoice locked", "id", id, "number", invoice.Number)
+	return invoice, nil
+}
+
+// SetInvoiceTaxRate applies a new tax rate to an invoice and recalculates its totals.
+// Only draft invoices can be changed unless force is set, because changing tax on an
+// issued invoice alters a document the client has already received. Voided invoices
+// are never changed. The update goes through storage optimistic locking.
+func (s *InvoiceService) SetInvoiceTaxRate(ctx context.Context, id models.InvoiceID, taxRate float64, force bool) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if taxRate < 0 || taxRate > 1 {
+		return nil, fmt.Errorf("%w: %v", models.ErrTaxRateOutOfRange, taxRate)
+	}
+
+	s.logger.Info("setting invoice tax rate", "id", id, "tax_rate", taxRate, "force", force)
+
 	// Get existing invoice
 	invoice, err := s.invoiceStorage.GetInvoice(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve invoice: %w", err)
 	}
 
-	// Business rule: can only mark sent or overdue invoices as paid
-	if invoice.Status != models.StatusSent && invoice.Status != models.StatusOverdue {
-		return nil, fmt.Errorf("%w, current status: %s", models.ErrCannotMarkNonSentAsPaid, invoice.Status)
+	// Business rule: voided invoices are final, locked invoices must be unlocked first and
+	// issued invoices require force
+	if invoice.Status == models.StatusVoided {
+		return nil, fmt.Errorf("%w: %s", models.ErrCannotChangeTaxOnVoidedInvoice, invoice.Number)
+	}
+	if err := invoice.EnsureUnlocked(); err != nil {
+		return nil, err
+	}
+	if invoice.Status != models.StatusDraft && !force {
+		return nil, fmt.Errorf("%w, current status: %s", models.ErrCannotChangeTaxOnIssuedInvoice, invoice.Status)
 	}
 
-	// Update status to paid
-	if err := invoice.UpdateStatus(ctx, models.StatusPaid); err != nil {
-		return nil, fmt.Errorf("failed to update invoice status: %w", err)
+	invoice.TaxRate = taxRate
+	if err := invoice.RecalculateTotals(ctx); err != nil {
+		return nil, fmt.Errorf("failed to recalculate invoice totals: %w", err)
 	}
 
 	// Update invoice in storage
 	if err := s.invoiceStorage.UpdateInvoice(ctx, invoice); err != nil {
-		return nil, fmt.Errorf("failed to update invoice status in storage: %w", err)
+		return nil, fmt.Errorf("failed to update invoice tax rate in storage: %w", err)
 	}
 
-	s.logger.Info("invoice marked as paid", "id", id, "number", invoice.Number, "amount", invoice.Total)
+	s.logger.Info("invoice tax rate updated", "id", id, "number", invoice.Number, "total", invoice.Total)
 	return invoice, nil
 }
 
-// GetOverdueInvoices returns all overdue invoices
+// MigrateWorkItemsToLineItems converts every invoice that still stores only legacy work items
+// to line items, recalculates its totals and saves it. Paid invoices are skipped so historical
+// amounts are not altered. With dryRun the invoices that would change are reported without writing.
+func (s *InvoiceService) MigrateWorkItemsToLineItems(ctx context.Context, dryRun bool) (*LineItemMigrationResult, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.logger.Info("migrating work items to line items", "dry_run", dryRun)
+
+	result, err := s.invoiceStorage.ListInvoices(ctx, models.InvoiceFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices for migration: %w", err)
+	}
+
+	migration := &LineItemMigrationResult{DryRun: dryRun}
+	for _, invoice := range result.Invoices {
+		switch {
+		case !invoice.HasOnlyWorkItems():
+			migration.Unchanged++
+			continue
+		case invoice.Status == models.StatusPaid:
+			migration.SkippedPaid = append(migration.SkippedPaid, invoice.Number)
+			continue
+		case dryRun:
+			migration.Migrated = append(migration.Migrated, invoice.Number)
+			continue
+		}
+
+		if err := invoice.MigrateWorkItemsToLineItems(ctx); err != nil {
+			return migration, fmt.Errorf("failed to migrate invoice %s: %w", invoice.Number, err)
+		}
+		if err := invoice.RecalculateTotals(ctx); err != nil {
+			return migration, fmt.Errorf("failed to recalculate totals for invoice %s: %w", invoice.Number, err)
+		}
+		if err := s.invoiceStorage.UpdateInvoice(ctx, invoice); err != nil {
+			return migration, fmt.Errorf("failed to save migrated invoice %s: %w", invoice.Number, err)
+		}
+
+		s.logger.Info("invoice migrated to line items", "id", invoice.ID, "number", invoice.Number,
+			"line_items", len(invoice.LineItems), "total", invoice.Total)
+		migration.Migrated = append(migration.Migrated, invoice.Number)
+	}
+
+	s.logger.Info("work item migration completed", "migrated", len(migration.Migrated),
+		"skipped_paid", len(migration.SkippedPaid), "unchanged", migration.Unchanged, "dry_run", dryRun)
+	return migration, nil
+}
+
+// GetOverdueInvoices moves sent invoices past their due date to overdue and returns them
 func (s *InvoiceService) GetOverdueInvoices(ctx context.Context) ([]*models.Invoice, error) {
+	result, err := s.SweepOverdueInvoices(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	return result.Overdue, nil
+}
+
+// SweepOverdueInvoices moves every sent invoice past its due date to overdue. Draft, paid
+// and voided invoices are never touched, and invoices already overdue are not sent again,
+// so repeated sweeps change nothing new. With dryRun the invoices that would change are
+// reported without saving. An invoice that cannot be saved is logged and left for the next sweep.
+func (s *InvoiceService) SweepOverdueInvoices(ctx context.Context, dryRun bool) (*OverdueSweepResult, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -552,28 +1155,91 @@ func (s *InvoiceService) GetOverdueInvoices(ctx context.Context) ([]*models.Invo
 		return nil, fmt.Errorf("failed to get overdue invoices: %w", err)
 	}
 
-	// Filter for truly overdue invoices and update status
-	var overdueInvoices []*models.Invoice
+	sweep := &OverdueSweepResult{DryRun: dryRun}
 	for _, invoice := range result.Invoices {
-		if invoice.IsOverdue() {
-			// Update status to overdue
-			if err := invoice.UpdateStatus(ctx, models.StatusOverdue); err != nil {
-				s.logger.Error("failed to update overdue invoice status", "id", invoice.ID, "error", err)
-				continue
-			}
+		// A settled invoice would stay paid rather than become overdue
+		if invoice.Status != models.StatusSent || !invoice.IsOverdue() || invoice.IsFullyPaid() {
+			continue
+		}
+		if dryRun {
+			sweep.Overdue = append(sweep.Overdue, invoice)
+			continue
+		}
 
-			// Update in storage
-			if err := s.invoiceStorage.UpdateInvoice(ctx, invoice); err != nil {
-				s.logger.Error("failed to update overdue invoice in storage", "id", invoice.ID, "error", err)
-				continue
-			}
+		// Update status to overdue
+		if err := invoice.UpdateStatus(ctx, models.StatusOverdue); err != nil {
+			s.logger.Error("failed to update overdue invoice status", "id", invoice.ID, "error", err)
+			sweep.Failed = append(sweep.Failed, invoice.Number)
+			continue
+		}
+		recordStatusEvent(invoice, models.StatusSent, time.Now())
+
+		// Update in storage
+		if err := s.invoiceStorage.UpdateInvoice(ctx, invoice); err != nil {
+			s.logger.Error("failed to update overdue invoice in storage", "id", invoice.ID, "error", err)
+			sweep.Failed = append(sweep.Failed, invoice.Number)
+			continue
+		}
+		notifyStatusChange(ctx, s.notifier, invoice, models.StatusSent)
+
+		sweep.Overdue = append(sweep.Overdue, invoice)
+	}
+
+	s.logger.Info("found overdue invoices", "count", len(sweep.Overdue), "failed", len(sweep.Failed), "dry_run", dryRun)
+	return sweep, nil
+}
+
+// ListRemindableInvoices returns the invoices past their due date as of asOf that may be
+// reminded: sent or overdue, with a balance due, and not reminded within interval.
+// The invoices are ordered by due date, oldest first.
+func (s *InvoiceService) ListRemindableInvoices(ctx context.Context, asOf time.Time, interval time.Duration) ([]*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	result, err := s.invoiceStorage.ListInvoices(ctx, models.InvoiceFilter{DueDateTo: asOf})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices to remind: %w", err)
+	}
+
+	var remindable []*models.Invoice
+	for _, invoice := range result.Invoices {
+		if !invoice.CanRemind() || !asOf.After(invoice.DueDate) || invoice.RemindedWithin(asOf, interval) {
+			continue
+		}
+		remindable = append(remindable, invoice)
+	}
+	slices.SortStableFunc(remindable, func(a, b *models.Invoice) int {
+		return a.DueDate.Compare(b.DueDate)
+	})
+
+	return remindable, nil
+}
+
+// RecordReminder notes on the invoice that a payment reminder was sent at the given time.
+// Draft, paid and voided invoices cannot be reminded.
+func (s *InvoiceService) RecordReminder(ctx context.Context, id models.InvoiceID, at time.Time) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
 
-			overdueInvoices = append(overdueInvoices, invoice)
+	invoice, err := s.UpdateInvoiceWithRetry(ctx, id, func(invoice *models.Invoice) error {
+		if !invoice.CanRemind() {
+			return fmt.Errorf("%w, current status: %s", models.ErrCannotRemindInvoice, invoice.Status)
 		}
+		invoice.RecordReminder(at)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	s.logger.Info("found overdue invoices", "count", len(overdueInvoices))
-	return overdueInvoices, nil
+	s.logger.Info("payment reminder recorded", "id", id, "number", invoice.Number)
+	return invoice, nil
 }
 
 // GetInvoiceStatistics returns summary statistics for all invoices
@@ -594,7 +1260,7 @@ func (s *InvoiceService) GetInvoiceStatistics(ctx context.Context) (*InvoiceStat
 	stats := &InvoiceStatistics{}
 	stats.TotalInvoices = int(result.TotalCount)
 
-	var totalAmount, paidAmount, outstandingAmount float64
+	var totalAmount, paidAmount, outstandingAmount money.Amount
 
 	for _, invoice := range result.Invoices {
 		totalAmount += invoice.Total
@@ -616,9 +1282,9 @@ func (s *InvoiceService) GetInvoiceStatistics(ctx context.Context) (*InvoiceStat
 		}
 	}
 
-	stats.TotalAmount = totalAmount
-	stats.PaidAmount = paidAmount
-	stats.OutstandingAmount = outstandingAmount
+	stats.TotalAmount = totalAmount.Float64()
+	stats.PaidAmount = paidAmount.Float64()
+	stats.OutstandingAmount = outstandingAmount.Float64()
 
 	return stats, nil
 }
@@ -627,8 +1293,9 @@ func (s *InvoiceService) GetInvoiceStatistics(ctx context.Context) (*InvoiceStat
 
 func (s *InvoiceService) validateUniqueInvoiceNumber(ctx context.Context, number string) error {
 	// This is a simplified implementation - in a real system with many invoices,
-	// you'd want a more efficient approach using an index
-	filter := models.InvoiceFilter{}
+	// you'd want a more efficient approach using an index. Deleted invoices keep their
+	// numbers so they can be restored.
+	filter := models.InvoiceFilter{IncludeDeleted: true}
 	result, err := s.invoiceStorage.ListInvoices(ctx, filter)
 	if err != nil {
 		return fmt.Errorf("failed to check invoice number uniqueness: %w", err)
@@ -643,6 +1310,62 @@ func (s *InvoiceService) validateUniqueInvoiceNumber(ctx context.Context, number
 	return nil
 }
 
+// maxNumberAttempts is how many numbers an invoice created with the next number tries
+// before giving up, when other processes keep storing invoices with the numbers it picks
+const maxNumberAttempts = 5
+
+// withNextInvoiceNumber calls create with the next number for numbering and, when storage
+// reports that another process stored an invoice with the number first, picks the number
+// again. numberMu only keeps creates in this process apart; callers must hold it.
+func (s *InvoiceService) withNextInvoiceNumber(ctx context.Context, numbering models.InvoiceNumbering, date time.Time, create func(number string) (*models.Invoice, error)) (*models.Invoice, error) {
+	for attempt := 1; ; attempt++ {
+		number, err := s.nextInvoiceNumber(ctx, numbering, date)
+		if err != nil {
+			return nil, err
+		}
+
+		invoice, err := create(number)
+		if !errors.Is(err, models.ErrInvoiceNumberExists) || attempt == maxNumberAttempts {
+			return invoice, err
+		}
+		s.logger.Info("invoice number taken by another process, picking the next one", "number", number, "attempt", attempt)
+	}
+}
+
+// nextInvoiceNumber finds the highest existing number matching numbering and returns the one after it
+func (s *InvoiceService) nextInvoiceNumber(ctx context.Context, numbering models.InvoiceNumbering, date time.Time) (string, error) {
+	if err := numbering.Validate(); err != nil {
+		return "", err
+	}
+
+	result, err := s.invoiceStorage.ListInvoices(ctx, models.InvoiceFilter{IncludeDeleted: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to list invoices for numbering: %w", err)
+	}
+
+	existing := make([]string, 0, len(result.Invoices))
+	for _, invoice := range result.Invoices {
+		existing = append(existing, invoice.Number)
+	}
+
+	return numbering.Next(existing, date), nil
+}
+
+// LineItemMigrationResult summarizes a work item to line item migration
+type LineItemMigrationResult struct {
+	Migrated    []string `json:"migrated"`     // Numbers of invoices migrated, or that would be on a dry run
+	SkippedPaid []string `json:"skipped_paid"` // Numbers of legacy invoices left unchanged because they are paid
+	Unchanged   int      `json:"unchanged"`    // Invoices that already use line items or have no items
+	DryRun      bool     `json:"dry_run"`
+}
+
+// OverdueSweepResult summarizes an overdue sweep
+type OverdueSweepResult struct {
+	Overdue []*models.Invoice `json:"overdue"` // Invoices moved to overdue, or that would be on a dry run
+	Failed  []string          `json:"failed"`  // Numbers of overdue invoices that could not be saved
+	DryRun  bool              `json:"dry_run"`
+}
+
 // InvoiceStatistics represents summary statistics for invoices
 type InvoiceStatistics struct {
 	TotalInvoices     int     `json:"total_invoices"`
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,7 +13,10 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/notify"
 	"github.com/mrz1836/go-invoice/internal/storage"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
 )
 
 var errConnectionTimeout = errors.New("connection timeout")
@@ -76,6 +80,7 @@ func (suite *InvoiceServiceTestSuite) TestCreateInvoice() {
 		Date:        time.Now(),
 		DueDate:     time.Now().AddDate(0, 0, 30),
 		Description: "Test Invoice",
+		Currency:    "EUR",
 		WorkItems: []models.WorkItem{
 			{
 				ID:          testWorkID001,
@@ -108,6 +113,7 @@ func (suite *InvoiceServiceTestSuite) TestCreateInvoice() {
 		assert.Equal(t, testInvoiceNum, invoice.Number)
 		assert.Equal(t, client.ID, invoice.Client.ID)
 		assert.Equal(t, "Test Invoice", invoice.Description)
+		assert.Equal(t, "EUR", invoice.Currency)
 		assert.Len(t, invoice.WorkItems, 1)
 		assert.Equal(t, models.StatusDraft, invoice.Status)
 	})
@@ -167,7 +173,7 @@ func (suite *InvoiceServiceTestSuite) TestUpdateInvoice() {
 		ID:        testInvoiceID001,
 		Number:    testInvoiceNum,
 		Status:    models.StatusDraft,
-		Total:     1000.0,
+		Total:     money.FromFloat(1000.0),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 		Version:   1,
@@ -190,6 +196,23 @@ func (suite *InvoiceServiceTestSuite) TestUpdateInvoice() {
 		assert.Equal(t, "Updated description", updatedInvoice.Description)
 	})
 
+	suite.Run("NotesAndReferences", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(existingInvoice, nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(nil).Once()
+
+		updatedInvoice, err := suite.service.UpdateInvoice(suite.ctx, models.UpdateInvoiceRequest{
+			ID:              testInvoiceID001,
+			Notes:           ptrString("Thanks for your business"),
+			PONumber:        ptrString("4500012345"),
+			ClientReference: ptrString("PRJ-ALPHA"),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Thanks for your business", updatedInvoice.Notes)
+		assert.Equal(t, "4500012345", updatedInvoice.PONumber)
+		assert.Equal(t, "PRJ-ALPHA", updatedInvoice.ClientReference)
+	})
+
 	// Invoice not found
 	suite.Run("InvoiceNotFound", func() {
 		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(nil, storage.NewNotFoundError("invoice", testInvoiceID001)).Once()
@@ -200,6 +223,135 @@ func (suite *InvoiceServiceTestSuite) TestUpdateInvoice() {
 		assert.Nil(t, updatedInvoice)
 		assert.Contains(t, err.Error(), "invoice with ID 'INV-001' not found")
 	})
+
+	discountedInvoice := func() *models.Invoice {
+		return &models.Invoice{
+			ID:        testInvoiceID001,
+			Number:    testInvoiceNum,
+			Status:    models.StatusDraft,
+			TaxRate:   0.10,
+			CryptoFee: money.FromFloat(25),
+			LineItems: []models.LineItem{{ID: "item-1", Type: models.LineItemTypeFixed, Total: money.FromFloat(1000)}},
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Version:   1,
+		}
+	}
+
+	// Percent discount replaces a fixed one and is taken before the crypto fee and tax
+	suite.Run("PercentDiscount", func() {
+		invoice := discountedInvoice()
+		invoice.DiscountAmount = money.FromFloat(50)
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(invoice, nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(nil).Once()
+
+		updatedInvoice, err := suite.service.UpdateInvoice(suite.ctx, models.UpdateInvoiceRequest{
+			ID:              testInvoiceID001,
+			DiscountPercent: ptrFloat64(10),
+			DiscountAmount:  ptrFloat64(0),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, money.FromFloat(100), updatedInvoice.DiscountTotal)
+		assert.Equal(t, money.FromFloat(92.50), updatedInvoice.TaxAmount)
+		assert.Equal(t, money.FromFloat(1017.50), updatedInvoice.Total)
+	})
+
+	// Conflicting discounts
+	suite.Run("ConflictingDiscounts", func() {
+		invoice := discountedInvoice()
+		invoice.DiscountPercent = 10
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(invoice, nil).Once()
+
+		_, err := suite.service.UpdateInvoice(suite.ctx, models.UpdateInvoiceRequest{
+			ID:             testInvoiceID001,
+			DiscountAmount: ptrFloat64(50),
+		})
+
+		require.ErrorIs(t, err, models.ErrConflictingDiscounts)
+	})
+
+	// Fixed discount larger than the subtotal
+	suite.Run("DiscountExceedsSubtotal", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(discountedInvoice(), nil).Once()
+
+		_, err := suite.service.UpdateInvoice(suite.ctx, models.UpdateInvoiceRequest{
+			ID:             testInvoiceID001,
+			DiscountAmount: ptrFloat64(1500),
+		})
+
+		require.ErrorIs(t, err, models.ErrDiscountExceedsAmount)
+	})
+
+	// Mistyped crypto address override
+	suite.Run("InvalidUSDCAddress", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(discountedInvoice(), nil).Once()
+
+		_, err := suite.service.UpdateInvoice(suite.ctx, models.UpdateInvoiceRequest{
+			ID:          testInvoiceID001,
+			USDCAddress: ptrString("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe"),
+		})
+
+		require.ErrorIs(t, err, models.ErrInvalidUSDCAddress)
+	})
+}
+
+func (suite *InvoiceServiceTestSuite) TestUpdateInvoiceWithRetry() {
+	t := suite.T()
+
+	newInvoice := func(version int) *models.Invoice {
+		return &models.Invoice{ID: testInvoiceID001, Number: testInvoiceNum, Status: models.StatusDraft, Version: version}
+	}
+	mismatch := storage.NewVersionMismatchError("invoice", testInvoiceID001, 1, 2)
+
+	suite.Run("RetriesOnVersionMismatch", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(newInvoice(1), nil).Once()
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(newInvoice(2), nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.MatchedBy(func(inv *models.Invoice) bool { return inv.Version == 1 })).Return(mismatch).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.MatchedBy(func(inv *models.Invoice) bool { return inv.Version == 2 })).Return(nil).Once()
+
+		calls := 0
+		invoice, err := suite.service.UpdateInvoiceWithRetry(suite.ctx, testInvoiceID001, func(inv *models.Invoice) error {
+			calls++
+			inv.Notes = "Retried"
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 2, invoice.Version)
+		assert.Equal(t, "Retried", invoice.Notes)
+	})
+
+	suite.Run("GivesUpAfterMaxAttempts", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(newInvoice(1), nil).Times(maxUpdateAttempts)
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(mismatch).Times(maxUpdateAttempts)
+
+		_, err := suite.service.UpdateInvoiceWithRetry(suite.ctx, testInvoiceID001, func(*models.Invoice) error { return nil })
+
+		require.ErrorIs(t, err, ErrConcurrentUpdate)
+		assert.True(t, storage.IsVersionMismatch(err))
+	})
+
+	suite.Run("MutateErrorIsNotRetried", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(newInvoice(1), nil).Once()
+
+		_, err := suite.service.UpdateInvoiceWithRetry(suite.ctx, testInvoiceID001, func(inv *models.Invoice) error {
+			return inv.UpdateStatus(suite.ctx, "bogus")
+		})
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrConcurrentUpdate)
+	})
+
+	suite.Run("StorageErrorIsNotRetried", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(newInvoice(1), nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(errTestDiskFull).Once()
+
+		_, err := suite.service.UpdateInvoiceWithRetry(suite.ctx, testInvoiceID001, func(*models.Invoice) error { return nil })
+
+		require.ErrorIs(t, err, errTestDiskFull)
+	})
 }
 
 func (suite *InvoiceServiceTestSuite) TestDeleteInvoice() {
@@ -213,11 +365,31 @@ func (suite *InvoiceServiceTestSuite) TestDeleteInvoice() {
 		}
 
 		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(draftInvoice, nil).Once()
-		suite.storage.On("DeleteInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.MatchedBy(func(inv *models.Invoice) bool {
+			return inv.ID == testInvoiceID001 && inv.DeletedAt != nil
+		})).Return(nil).Once()
 
 		err := suite.service.DeleteInvoice(suite.ctx, testInvoiceID001)
 
 		require.NoError(t, err)
+		assert.True(t, draftInvoice.IsDeleted(), "invoice should be soft deleted, not removed")
+	})
+
+	// Already in the trash
+	suite.Run("AlreadyDeleted", func() {
+		deletedAt := time.Now()
+		deletedInvoice := &models.Invoice{
+			ID:        testInvoiceID001,
+			Number:    testInvoiceNum,
+			Status:    models.StatusDraft,
+			DeletedAt: &deletedAt,
+		}
+
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(deletedInvoice, nil).Once()
+
+		err := suite.service.DeleteInvoice(suite.ctx, testInvoiceID001)
+
+		require.ErrorIs(t, err, models.ErrInvoiceAlreadyDeleted)
 	})
 
 	// Cannot delete paid invoice
@@ -237,6 +409,112 @@ func (suite *InvoiceServiceTestSuite) TestDeleteInvoice() {
 	})
 }
 
+func (suite *InvoiceServiceTestSuite) TestHardDeleteInvoice() {
+	t := suite.T()
+
+	// Success - permanently removes an invoice in the trash
+	suite.Run("DeleteTrashedInvoice", func() {
+		deletedAt := time.Now()
+		deletedInvoice := &models.Invoice{
+			ID:        testInvoiceID001,
+			Status:    models.StatusDraft,
+			DeletedAt: &deletedAt,
+		}
+
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(deletedInvoice, nil).Once()
+		suite.storage.On("DeleteInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(nil).Once()
+
+		err := suite.service.HardDeleteInvoice(suite.ctx, testInvoiceID001)
+
+		require.NoError(t, err)
+	})
+
+	// Cannot delete paid invoice
+	suite.Run("CannotDeletePaid", func() {
+		paidInvoice := &models.Invoice{
+			ID:     testInvoiceID001,
+			Number: testInvoiceNum,
+			Status: models.StatusPaid,
+		}
+
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(paidInvoice, nil).Once()
+
+		err := suite.service.HardDeleteInvoice(suite.ctx, testInvoiceID001)
+
+		require.ErrorIs(t, err, models.ErrCannotDeletePaidInvoice)
+	})
+}
+
+func (suite *InvoiceServiceTestSuite) TestRestoreInvoice() {
+	t := suite.T()
+
+	// Success - clears the deleted marker
+	suite.Run("RestoreDeletedInvoice", func() {
+		deletedAt := time.Now()
+		deletedInvoice := &models.Invoice{
+			ID:        testInvoiceID001,
+			Number:    testInvoiceNum,
+			Status:    models.StatusDraft,
+			DeletedAt: &deletedAt,
+		}
+
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceNum)).Return(nil, storage.NewNotFoundError("invoice", testInvoiceNum)).Once()
+		suite.storage.On("ListInvoices", suite.ctx, models.InvoiceFilter{IncludeDeleted: true}).Return(&storage.InvoiceListResult{
+			Invoices:   []*models.Invoice{deletedInvoice},
+			TotalCount: 1,
+		}, nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.MatchedBy(func(inv *models.Invoice) bool {
+			return inv.ID == testInvoiceID001 && inv.DeletedAt == nil
+		})).Return(nil).Once()
+
+		restored, err := suite.service.RestoreInvoice(suite.ctx, testInvoiceNum)
+
+		require.NoError(t, err)
+		assert.False(t, restored.IsDeleted())
+	})
+
+	// Not in the trash
+	suite.Run("NotDeleted", func() {
+		activeInvoice := &models.Invoice{
+			ID:     testInvoiceID001,
+			Number: testInvoiceNum,
+			Status: models.StatusDraft,
+		}
+
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(activeInvoice, nil).Once()
+
+		restored, err := suite.service.RestoreInvoice(suite.ctx, testInvoiceID001)
+
+		require.ErrorIs(t, err, models.ErrInvoiceNotDeleted)
+		assert.Nil(t, restored)
+	})
+}
+
+func (suite *InvoiceServiceTestSuite) TestFindInvoice() {
+	t := suite.T()
+
+	deletedAt := time.Now()
+	deletedInvoice := &models.Invoice{
+		ID:        testInvoiceID001,
+		Number:    testInvoiceNum,
+		Status:    models.StatusDraft,
+		DeletedAt: &deletedAt,
+	}
+
+	// An invoice in the trash is not found by its ID unless deleted invoices are included
+	suite.Run("DeletedByID", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(deletedInvoice, nil).Twice()
+
+		invoice, err := suite.service.FindInvoice(suite.ctx, testInvoiceID001, false)
+		require.ErrorIs(t, err, models.ErrInvoiceNotFound)
+		assert.Nil(t, invoice)
+
+		invoice, err = suite.service.FindInvoice(suite.ctx, testInvoiceID001, true)
+		require.NoError(t, err)
+		assert.Equal(t, deletedInvoice, invoice)
+	})
+}
+
 func (suite *InvoiceServiceTestSuite) TestListInvoices() {
 	t := suite.T()
 
@@ -380,7 +658,7 @@ func (suite *InvoiceServiceTestSuite) TestAddLineItemToInvoice() {
 		Date:        time.Now(),
 		Description: "Repository Maintenance",
 		Amount:      &amount,
-		Total:       5000.0,
+		Total:       money.FromFloat(5000.0),
 	}
 
 	// Success case
@@ -478,6 +756,54 @@ func (suite *InvoiceServiceTestSuite) TestAddLineItemToInvoice() {
 		assert.Nil(t, updatedInvoice)
 		assert.Contains(t, err.Error(), "can only add work items to draft invoices")
 	})
+
+	// An invoice with only legacy work items is migrated first, so every item counts once
+	newLegacyInvoice := func(id models.InvoiceID) *models.Invoice {
+		return &models.Invoice{
+			ID:      id,
+			Number:  "INV-LEGACY",
+			Status:  models.StatusDraft,
+			Version: 1,
+			Client:  models.Client{ID: testClientID, Name: testClientName},
+			WorkItems: []models.WorkItem{
+				{ID: "WORK-1", Date: time.Now(), Hours: 8, Rate: 100, Description: "Legacy work", Total: 800},
+				{ID: "WORK-2", Date: time.Now(), Hours: 2, Rate: 100, Description: "More legacy work", Total: 200},
+			},
+			Subtotal: money.FromFloat(1000),
+			Total:    money.FromFloat(1000),
+		}
+	}
+
+	suite.Run("MigratesLegacyWorkItems", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID("INV-LEGACY-1")).Return(newLegacyInvoice("INV-LEGACY-1"), nil).Once()
+		suite.idGen.On("GenerateWorkItemID", suite.ctx).Return("LINE-003", nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(nil).Once()
+
+		updatedInvoice, err := suite.service.AddLineItemToInvoice(suite.ctx, "INV-LEGACY-1", newLineItem)
+
+		require.NoError(t, err)
+		assert.Empty(t, updatedInvoice.WorkItems)
+		require.Len(t, updatedInvoice.LineItems, 3)
+		assert.Equal(t, "WORK-1", updatedInvoice.LineItems[0].ID)
+		assert.Equal(t, "LINE-003", updatedInvoice.LineItems[2].ID)
+		assert.Equal(t, money.FromFloat(6000), updatedInvoice.Subtotal)
+	})
+
+	suite.Run("KeepsLegacyWorkItems", func() {
+		suite.service.SetLegacyItemsPolicy(LegacyItemsKeep)
+		defer suite.service.SetLegacyItemsPolicy("")
+
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID("INV-LEGACY-2")).Return(newLegacyInvoice("INV-LEGACY-2"), nil).Once()
+		suite.idGen.On("GenerateWorkItemID", suite.ctx).Return("LINE-004", nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(nil).Once()
+
+		updatedInvoice, err := suite.service.AddLineItemToInvoice(suite.ctx, "INV-LEGACY-2", newLineItem)
+
+		require.NoError(t, err)
+		assert.Len(t, updatedInvoice.WorkItems, 2)
+		assert.Len(t, updatedInvoice.LineItems, 1)
+		assert.Equal(t, money.FromFloat(6000), updatedInvoice.Subtotal)
+	})
 }
 
 func (suite *InvoiceServiceTestSuite) TestSendInvoice() {
@@ -568,6 +894,180 @@ func (suite *InvoiceServiceTestSuite) TestMarkInvoicePaid() {
 	})
 }
 
+func (suite *InvoiceServiceTestSuite) TestStatusChangeNotifications() {
+	t := suite.T()
+
+	notifier := &notify.FakeNotifier{}
+	suite.service.SetNotifier(notifier)
+
+	sentInvoice := &models.Invoice{ID: testInvoiceID001, Number: "INV-001", Status: models.StatusSent, Version: 1}
+	suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(sentInvoice, nil).Once()
+	suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(nil).Twice()
+
+	_, err := suite.service.MarkInvoicePaid(suite.ctx, testInvoiceID001)
+	require.NoError(t, err)
+
+	// Updates that keep the status are not reported
+	notes := "thanks"
+	paidInvoice := &models.Invoice{ID: testInvoiceID001, Number: "INV-001", Status: models.StatusPaid, Version: 2}
+	suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(paidInvoice, nil).Once()
+	_, err = suite.service.UpdateInvoice(suite.ctx, models.UpdateInvoiceRequest{ID: testInvoiceID001, Notes: &notes})
+	require.NoError(t, err)
+
+	events := notifier.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "invoice.paid", events[0].Type)
+	assert.Equal(t, models.InvoiceID(testInvoiceID001), events[0].InvoiceID)
+	assert.Equal(t, "INV-001", events[0].InvoiceNumber)
+	assert.Equal(t, models.StatusSent, events[0].OldStatus)
+	assert.Equal(t, models.StatusPaid, events[0].NewStatus)
+
+	// A failed save sends nothing
+	failingInvoice := &models.Invoice{ID: testInvoiceID001, Status: models.StatusSent, Version: 1}
+	suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(failingInvoice, nil).Once()
+	suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(errConnectionTimeout).Once()
+	_, err = suite.service.MarkInvoicePaid(suite.ctx, testInvoiceID001)
+	require.Error(t, err)
+	assert.Len(t, notifier.Events(), 1)
+}
+
+func (suite *InvoiceServiceTestSuite) TestSetInvoiceTaxRate() {
+	t := suite.T()
+
+	newInvoice := func(status string) *models.Invoice {
+		return &models.Invoice{
+			ID:       testInvoiceID001,
+			Number:   "INV-001",
+			Status:   status,
+			Version:  1,
+			Subtotal: money.FromFloat(1000),
+			Total:    money.FromFloat(1000),
+			WorkItems: []models.WorkItem{
+				{ID: testWorkID001, Hours: 10, Rate: 100, Total: 1000},
+			},
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+	}
+
+	suite.Run("DraftInvoice", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(newInvoice(models.StatusDraft), nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(nil).Once()
+
+		invoice, err := suite.service.SetInvoiceTaxRate(suite.ctx, testInvoiceID001, 0.21, false)
+
+		require.NoError(t, err)
+		assert.InDelta(t, 0.21, invoice.TaxRate, 1e-9)
+		assert.InDelta(t, 210.0, invoice.TaxAmount.Float64(), 0.001)
+		assert.InDelta(t, 1210.0, invoice.Total.Float64(), 0.001)
+	})
+
+	suite.Run("SentInvoiceRequiresForce", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(newInvoice(models.StatusSent), nil).Once()
+
+		invoice, err := suite.service.SetInvoiceTaxRate(suite.ctx, testInvoiceID001, 0.21, false)
+
+		require.ErrorIs(t, err, models.ErrCannotChangeTaxOnIssuedInvoice)
+		assert.Nil(t, invoice)
+	})
+
+	suite.Run("PaidInvoiceWithForce", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(newInvoice(models.StatusPaid), nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(nil).Once()
+
+		invoice, err := suite.service.SetInvoiceTaxRate(suite.ctx, testInvoiceID001, 0.1, true)
+
+		require.NoError(t, err)
+		assert.InDelta(t, 1100.0, invoice.Total.Float64(), 0.001)
+	})
+
+	suite.Run("VoidedInvoiceNeverChanged", func() {
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(newInvoice(models.StatusVoided), nil).Once()
+
+		_, err := suite.service.SetInvoiceTaxRate(suite.ctx, testInvoiceID001, 0.1, true)
+
+		require.ErrorIs(t, err, models.ErrCannotChangeTaxOnVoidedInvoice)
+	})
+
+	suite.Run("RateOutOfRange", func() {
+		_, err := suite.service.SetInvoiceTaxRate(suite.ctx, testInvoiceID001, 21, false)
+
+		require.ErrorIs(t, err, models.ErrTaxRateOutOfRange)
+	})
+
+	suite.Run("VersionConflict", func() {
+		conflict := storage.NewVersionMismatchError("invoice", testInvoiceID001, 1, 2)
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(newInvoice(models.StatusDraft), nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(conflict).Once()
+
+		_, err := suite.service.SetInvoiceTaxRate(suite.ctx, testInvoiceID001, 0.21, false)
+
+		require.Error(t, err)
+		assert.True(t, storage.IsVersionMismatch(err))
+	})
+}
+
+func (suite *InvoiceServiceTestSuite) TestMigrateWorkItemsToLineItems() {
+	t := suite.T()
+
+	newInvoices := func() []*models.Invoice {
+		hours, rate := 2.0, 50.0
+		return []*models.Invoice{
+			{
+				ID: testInvoiceID001, Number: "INV-001", Status: models.StatusDraft, Version: 1,
+				WorkItems: []models.WorkItem{{ID: testWorkID001, Date: time.Now(), Hours: 10, Rate: 100, Description: "Dev", Total: 1000}},
+			},
+			{
+				ID: "INV-002", Number: "INV-002", Status: models.StatusPaid, Version: 1,
+				WorkItems: []models.WorkItem{{ID: "WORK-002", Date: time.Now(), Hours: 1, Rate: 100, Description: "Dev", Total: 100}},
+			},
+			{
+				ID: "INV-003", Number: "INV-003", Status: models.StatusSent, Version: 1,
+				LineItems: []models.LineItem{{ID: "LINE-001", Type: models.LineItemTypeHourly, Hours: &hours, Rate: &rate, Total: money.FromFloat(100)}},
+			},
+		}
+	}
+
+	suite.Run("MigratesUnpaidLegacyInvoices", func() {
+		suite.storage.On("ListInvoices", suite.ctx, models.InvoiceFilter{}).Return(&storage.InvoiceListResult{Invoices: newInvoices()}, nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.MatchedBy(func(invoice *models.Invoice) bool {
+			return invoice.Number == "INV-001" && len(invoice.LineItems) == 1 && len(invoice.WorkItems) == 0 &&
+				invoice.Total == money.FromFloat(1000)
+		})).Return(nil).Once()
+
+		result, err := suite.service.MigrateWorkItemsToLineItems(suite.ctx, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"INV-001"}, result.Migrated)
+		assert.Equal(t, []string{"INV-002"}, result.SkippedPaid)
+		assert.Equal(t, 1, result.Unchanged)
+	})
+
+	suite.Run("DryRunDoesNotWrite", func() {
+		invoices := newInvoices()
+		suite.storage.On("ListInvoices", suite.ctx, models.InvoiceFilter{}).Return(&storage.InvoiceListResult{Invoices: invoices}, nil).Once()
+
+		result, err := suite.service.MigrateWorkItemsToLineItems(suite.ctx, true)
+
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, []string{"INV-001"}, result.Migrated)
+		assert.True(t, invoices[0].HasOnlyWorkItems(), "dry run must not modify invoices")
+	})
+
+	suite.Run("StopsOnSaveError", func() {
+		conflict := storage.NewVersionMismatchError("invoice", testInvoiceID001, 1, 2)
+		suite.storage.On("ListInvoices", suite.ctx, models.InvoiceFilter{}).Return(&storage.InvoiceListResult{Invoices: newInvoices()}, nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(conflict).Once()
+
+		result, err := suite.service.MigrateWorkItemsToLineItems(suite.ctx, false)
+
+		require.Error(t, err)
+		assert.True(t, storage.IsVersionMismatch(err))
+		assert.Empty(t, result.Migrated)
+	})
+}
+
 func (suite *InvoiceServiceTestSuite) TestGetOverdueInvoices() {
 	t := suite.T()
 
@@ -784,27 +1284,27 @@ func (suite *InvoiceServiceTestSuite) TestGetInvoiceStatistics() {
 	suite.Run("Success", func() {
 		// Mock single ListInvoices call with all invoices
 		allInvoices := []*models.Invoice{
-			{Status: models.StatusDraft, Total: 100.0},
-			{Status: models.StatusDraft, Total: 200.0},
-			{Status: models.StatusDraft, Total: 150.0},
-			{Status: models.StatusDraft, Total: 250.0},
-			{Status: models.StatusDraft, Total: 300.0},
-			{Status: models.StatusSent, Total: 400.0},
-			{Status: models.StatusSent, Total: 500.0},
-			{Status: models.StatusSent, Total: 600.0},
-			{Status: models.StatusPaid, Total: 1000.0},
-			{Status: models.StatusPaid, Total: 1200.0},
-			{Status: models.StatusPaid, Total: 800.0},
-			{Status: models.StatusPaid, Total: 900.0},
-			{Status: models.StatusPaid, Total: 700.0},
-			{Status: models.StatusPaid, Total: 600.0},
-			{Status: models.StatusPaid, Total: 500.0},
-			{Status: models.StatusPaid, Total: 400.0},
-			{Status: models.StatusPaid, Total: 300.0},
-			{Status: models.StatusPaid, Total: 200.0},
-			{Status: models.StatusOverdue, Total: 750.0},
-			{Status: models.StatusOverdue, Total: 850.0},
-			{Status: models.StatusVoided, Total: 50.0},
+			{Status: models.StatusDraft, Total: money.FromFloat(100.0)},
+			{Status: models.StatusDraft, Total: money.FromFloat(200.0)},
+			{Status: models.StatusDraft, Total: money.FromFloat(150.0)},
+			{Status: models.StatusDraft, Total: money.FromFloat(250.0)},
+			{Status: models.StatusDraft, Total: money.FromFloat(300.0)},
+			{Status: models.StatusSent, Total: money.FromFloat(400.0)},
+			{Status: models.StatusSent, Total: money.FromFloat(500.0)},
+			{Status: models.StatusSent, Total: money.FromFloat(600.0)},
+			{Status: models.StatusPaid, Total: money.FromFloat(1000.0)},
+			{Status: models.StatusPaid, Total: money.FromFloat(1200.0)},
+			{Status: models.StatusPaid, Total: money.FromFloat(800.0)},
+			{Status: models.StatusPaid, Total: money.FromFloat(900.0)},
+			{Status: models.StatusPaid, Total: money.FromFloat(700.0)},
+			{Status: models.StatusPaid, Total: money.FromFloat(600.0)},
+			{Status: models.StatusPaid, Total: money.FromFloat(500.0)},
+			{Status: models.StatusPaid, Total: money.FromFloat(400.0)},
+			{Status: models.StatusPaid, Total: money.FromFloat(300.0)},
+			{Status: models.StatusPaid, Total: money.FromFloat(200.0)},
+			{Status: models.StatusOverdue, Total: money.FromFloat(750.0)},
+			{Status: models.StatusOverdue, Total: money.FromFloat(850.0)},
+			{Status: models.StatusVoided, Total: money.FromFloat(50.0)},
 		}
 
 		suite.storage.On("ListInvoices", suite.ctx, mock.MatchedBy(func(filter models.InvoiceFilter) bool {
@@ -909,7 +1409,617 @@ func (suite *InvoiceServiceTestSuite) TestGetInvoiceByNumber() {
 	})
 }
 
-// Helper function
+// Helper functions
 func ptrString(s string) *string {
 	return &s
 }
+
+func ptrFloat64(f float64) *float64 {
+	return &f
+}
+
+func (suite *InvoiceServiceTestSuite) TestNextInvoiceNumber() {
+	t := suite.T()
+
+	numbering := models.InvoiceNumbering{Format: "{prefix}-{year}-{seq}", Prefix: "INV", StartNumber: 1}
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	suite.Run("StartsAtStartNumber", func() {
+		suite.storage.On("ListInvoices", suite.ctx, mock.Anything).Return(&storage.InvoiceListResult{
+			Invoices: []*models.Invoice{{Number: "OTHER-0042"}},
+		}, nil).Once()
+
+		number, err := suite.service.NextInvoiceNumber(suite.ctx, numbering, date)
+
+		require.NoError(t, err)
+		assert.Equal(t, "INV-2024-0001", number)
+	})
+
+	suite.Run("FollowsHighestExisting", func() {
+		suite.storage.On("ListInvoices", suite.ctx, mock.Anything).Return(&storage.InvoiceListResult{
+			Invoices: []*models.Invoice{{Number: "INV-2023-0007"}, {Number: "INV-2024-0003"}, {Number: "INV-20240301-120000"}},
+		}, nil).Once()
+
+		number, err := suite.service.NextInvoiceNumber(suite.ctx, numbering, date)
+
+		require.NoError(t, err)
+		assert.Equal(t, "INV-2024-0008", number)
+	})
+
+	suite.Run("InvalidFormat", func() {
+		_, err := suite.service.NextInvoiceNumber(suite.ctx, models.InvoiceNumbering{Format: "{prefix}", Prefix: "INV"}, date)
+
+		require.ErrorIs(t, err, models.ErrInvalidNumberFormat)
+	})
+}
+
+func TestCreateInvoiceWithNextNumberConcurrent(t *testing.T) {
+	ctx := context.Background()
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+
+	service := NewInvoiceService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+	client, err := NewClientService(store, store, &SimpleTestLogger{}, NewUUIDGenerator()).CreateClient(ctx, models.CreateClientRequest{
+		Name:  "Concurrent Client",
+		Email: "concurrent@example.com",
+	})
+	require.NoError(t, err)
+
+	numbering := models.InvoiceNumbering{Prefix: "INV", StartNumber: 1000}
+	const workers = 10
+
+	var wg sync.WaitGroup
+	numbers := make(chan string, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			invoice, createErr := service.CreateInvoiceWithNextNumber(ctx, models.CreateInvoiceRequest{
+				ClientID: client.ID,
+				Date:     time.Now(),
+				DueDate:  time.Now().AddDate(0, 0, 30),
+			}, numbering)
+			if createErr != nil {
+				t.Errorf("Concurrent create error: %v", createErr)
+				return
+			}
+			numbers <- invoice.Number
+		}()
+	}
+	wg.Wait()
+	close(numbers)
+
+	seen := make(map[string]bool)
+	for number := range numbers {
+		assert.False(t, seen[number], "duplicate invoice number %s", number)
+		seen[number] = true
+	}
+	assert.Len(t, seen, workers)
+	assert.True(t, seen["INV-1000"])
+	assert.True(t, seen["INV-1009"])
+}
+
+// racingStorage runs race right before its first CreateInvoice, standing in for another
+// process that stores an invoice in the same data directory in the meantime
+type racingStorage struct {
+	*jsonStorage.JSONStorage
+
+	race func()
+}
+
+func (s *racingStorage) CreateInvoice(ctx context.Context, invoice *models.Invoice) error {
+	if race := s.race; race != nil {
+		s.race = nil
+		race()
+	}
+	return s.JSONStorage.CreateInvoice(ctx, invoice)
+}
+
+func TestCreateInvoiceWithNextNumberAcrossProcesses(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+
+	// Each service has its own storage over the data directory, like two processes
+	otherStore := jsonStorage.NewJSONStorage(dataDir, &SimpleTestLogger{})
+	require.NoError(t, otherStore.Initialize(ctx))
+	other := NewInvoiceService(otherStore, otherStore, &SimpleTestLogger{}, NewUUIDGenerator())
+	client, err := NewClientService(otherStore, otherStore, &SimpleTestLogger{}, NewUUIDGenerator()).CreateClient(ctx, models.CreateClientRequest{
+		Name:  "Shared Client",
+		Email: "shared@example.com",
+	})
+	require.NoError(t, err)
+
+	numbering := models.InvoiceNumbering{Prefix: "INV", StartNumber: 1000}
+	req := models.CreateInvoiceRequest{ClientID: client.ID, Date: time.Now(), DueDate: time.Now().AddDate(0, 0, 30)}
+
+	// The other process stores INV-1000 after this one picked it but before it is stored
+	var otherNumber string
+	store := &racingStorage{JSONStorage: jsonStorage.NewJSONStorage(dataDir, &SimpleTestLogger{})}
+	store.race = func() {
+		invoice, raceErr := other.CreateInvoiceWithNextNumber(ctx, req, numbering)
+		require.NoError(t, raceErr)
+		otherNumber = invoice.Number
+	}
+	service := NewInvoiceService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+
+	invoice, err := service.CreateInvoiceWithNextNumber(ctx, req, numbering)
+	require.NoError(t, err)
+	assert.Equal(t, "INV-1000", otherNumber)
+	assert.Equal(t, "INV-1001", invoice.Number)
+}
+
+func TestPreviewInvoiceWithNextNumber(t *testing.T) {
+	ctx := context.Background()
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+
+	invoiceService := NewInvoiceService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+	clientService := NewClientService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+	numbering := models.InvoiceNumbering{Prefix: "INV", StartNumber: 1000}
+	req := models.CreateInvoiceRequest{
+		Date:        time.Now(),
+		DueDate:     time.Now().AddDate(0, 0, 30),
+		Description: "Preview",
+		Currency:    "USD",
+	}
+
+	t.Run("NewClientStoresNothing", func(t *testing.T) {
+		client, err := clientService.PreviewClient(ctx, models.CreateClientRequest{Name: "Preview Client", Email: "preview@example.com"})
+		require.NoError(t, err)
+
+		invoice, err := invoiceService.PreviewInvoiceWithNextNumber(ctx, req, numbering, client)
+		require.NoError(t, err)
+		assert.Equal(t, "INV-1000", invoice.Number)
+		assert.Equal(t, client.ID, invoice.Client.ID)
+		assert.Equal(t, "Preview", invoice.Description)
+		assert.Equal(t, models.StatusDraft, invoice.Status)
+
+		invoices, err := store.ListInvoices(ctx, models.InvoiceFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, invoices.Invoices)
+		clients, err := store.ListClients(ctx, false, 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, clients.Clients)
+	})
+
+	t.Run("MatchesNextCreate", func(t *testing.T) {
+		client, err := clientService.CreateClient(ctx, models.CreateClientRequest{Name: "Real Client", Email: "real@example.com"})
+		require.NoError(t, err)
+		req.ClientID = client.ID
+		_, err = invoiceService.CreateInvoiceWithNextNumber(ctx, req, numbering)
+		require.NoError(t, err)
+
+		preview, err := invoiceService.PreviewInvoiceWithNextNumber(ctx, req, numbering, client)
+		require.NoError(t, err)
+		created, err := invoiceService.CreateInvoiceWithNextNumber(ctx, req, numbering)
+		require.NoError(t, err)
+		assert.Equal(t, "INV-1001", preview.Number)
+		assert.Equal(t, created.Number, preview.Number)
+		assert.Equal(t, created.DueDate, preview.DueDate)
+
+		_, err = clientService.PreviewClient(ctx, models.CreateClientRequest{Name: "Copy", Email: "real@example.com"})
+		require.ErrorIs(t, err, models.ErrClientEmailExists, "a dry run still rejects a duplicate email")
+	})
+
+	t.Run("InactiveClient", func(t *testing.T) {
+		client := &models.Client{ID: "inactive", Name: "Inactive", Active: false}
+
+		_, err := invoiceService.PreviewInvoiceWithNextNumber(ctx, req, numbering, client)
+		require.ErrorIs(t, err, models.ErrClientInactive)
+	})
+}
+
+func (suite *InvoiceServiceTestSuite) TestRecordPayment() {
+	t := suite.T()
+
+	payment := models.Payment{Amount: 250, Date: time.Now(), Method: models.PaymentMethodWire}
+
+	suite.Run("PartialPayment", func() {
+		invoice := &models.Invoice{ID: testInvoiceID001, Number: testInvoiceNum, Status: models.StatusSent, Total: money.FromFloat(1000), Version: 1}
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(invoice, nil).Once()
+		suite.storage.On("UpdateInvoice", suite.ctx, mock.AnythingOfType("*models.Invoice")).Return(nil).Once()
+
+		updated, err := suite.service.RecordPayment(suite.ctx, testInvoiceID001, payment)
+
+		require.NoError(t, err)
+		assert.InDelta(t, 750.0, updated.AmountDue(), 0.001)
+		assert.Equal(t, models.StatusSent, updated.Status)
+	})
+
+	suite.Run("ExceedsBalance", func() {
+		invoice := &models.Invoice{ID: testInvoiceID001, Number: testInvoiceNum, Status: models.StatusSent, Total: money.FromFloat(100), Version: 1}
+		suite.storage.On("GetInvoice", suite.ctx, models.InvoiceID(testInvoiceID001)).Return(invoice, nil).Once()
+
+		updated, err := suite.service.RecordPayment(suite.ctx, testInvoiceID001, payment)
+
+		require.ErrorIs(t, err, models.ErrPaymentExceedsBalance)
+		assert.Nil(t, updated)
+	})
+}
+
+func TestUpdateInvoiceConcurrentWriters(t *testing.T) {
+	ctx := context.Background()
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+
+	service := NewInvoiceService(store, store, &SimpleTestLogger{}, NewUUIDGenerator())
+	client, err := NewClientService(store, store, &SimpleTestLogger{}, NewUUIDGenerator()).CreateClient(ctx, models.CreateClientRequest{
+		Name:  "Concurrent Client",
+		Email: "concurrent@example.com",
+	})
+	require.NoError(t, err)
+	invoice, err := service.CreateInvoice(ctx, models.CreateInvoiceRequest{
+		Number:   "INV-001",
+		ClientID: client.ID,
+		Date:     time.Now(),
+		DueDate:  time.Now().AddDate(0, 0, 30),
+	})
+	require.NoError(t, err)
+
+	// Each writer changes a different field; every failed save means another writer won,
+	// so with maxUpdateAttempts writers all of them must eventually succeed
+	requests := []models.UpdateInvoiceRequest{
+		{ID: invoice.ID, Notes: ptrString("Thanks")},
+		{ID: invoice.ID, PONumber: ptrString("PO-1")},
+		{ID: invoice.ID, ClientReference: ptrString("REF-1")},
+	}
+	require.Len(t, requests, maxUpdateAttempts)
+
+	var wg sync.WaitGroup
+	for _, req := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, updateErr := service.UpdateInvoice(ctx, req); updateErr != nil {
+				t.Errorf("Concurrent update error: %v", updateErr)
+			}
+		}()
+	}
+	wg.Wait()
+
+	saved, err := service.GetInvoice(ctx, invoice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Thanks", saved.Notes)
+	assert.Equal(t, "PO-1", saved.PONumber)
+	assert.Equal(t, "REF-1", saved.ClientReference)
+	assert.Equal(t, invoice.Version+len(requests), saved.Version)
+}
+
+func TestSweepOverdueInvoices(t *testing.T) {
+	ctx := context.Background()
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+
+	generator := NewSequentialGenerator("test")
+	clients := NewClientService(store, store, &SimpleTestLogger{}, generator)
+	invoices := NewInvoiceService(store, store, &SimpleTestLogger{}, generator)
+
+	client, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Acme", Email: "billing@acme.example"})
+	require.NoError(t, err)
+
+	create := func(number, status string, dueDays int) {
+		invoice, createErr := invoices.CreateInvoice(ctx, models.CreateInvoiceRequest{
+			Number:   number,
+			ClientID: client.ID,
+			Date:     time.Now().AddDate(0, 0, -60),
+			DueDate:  time.Now().AddDate(0, 0, dueDays),
+		})
+		require.NoError(t, createErr)
+		invoice.Status = status
+		require.NoError(t, invoices.UpdateInvoiceDirectly(ctx, invoice))
+	}
+	create("INV-001", models.StatusSent, -10) // Overdue
+	create("INV-002", models.StatusSent, 10)  // Not due yet
+	create("INV-003", models.StatusDraft, -10)
+	create("INV-004", models.StatusPaid, -10)
+	create("INV-005", models.StatusVoided, -10)
+	create("INV-006", models.StatusOverdue, -10)
+
+	// A dry run reports without saving
+	preview, err := invoices.SweepOverdueInvoices(ctx, true)
+	require.NoError(t, err)
+	assert.True(t, preview.DryRun)
+	require.Len(t, preview.Overdue, 1)
+	assert.Equal(t, "INV-001", preview.Overdue[0].Number)
+	unchanged, err := invoices.GetInvoiceByNumber(ctx, "INV-001")
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSent, unchanged.Status)
+
+	notifier := &notify.FakeNotifier{}
+	invoices.SetNotifier(notifier)
+
+	result, err := invoices.SweepOverdueInvoices(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, result.Overdue, 1)
+	assert.Empty(t, result.Failed)
+
+	statuses := map[string]string{
+		"INV-001": models.StatusOverdue,
+		"INV-002": models.StatusSent,
+		"INV-003": models.StatusDraft,
+		"INV-004": models.StatusPaid,
+		"INV-005": models.StatusVoided,
+		"INV-006": models.StatusOverdue,
+	}
+	for number, status := range statuses {
+		invoice, getErr := invoices.GetInvoiceByNumber(ctx, number)
+		require.NoError(t, getErr)
+		assert.Equal(t, status, invoice.Status, number)
+	}
+
+	events := notifier.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "invoice.overdue", events[0].Type)
+	assert.Equal(t, models.StatusSent, events[0].OldStatus)
+
+	// Sweeping again changes nothing
+	again, err := invoices.SweepOverdueInvoices(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, again.Overdue)
+	assert.Len(t, notifier.Events(), 1)
+}
+
+func TestInvoiceReminders(t *testing.T) {
+	ctx := context.Background()
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+
+	generator := NewSequentialGenerator("test")
+	clients := NewClientService(store, store, &SimpleTestLogger{}, generator)
+	invoices := NewInvoiceService(store, store, &SimpleTestLogger{}, generator)
+
+	client, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Acme", Email: "billing@acme.example"})
+	require.NoError(t, err)
+
+	now := time.Now()
+	create := func(number, status string, dueDays int, lastReminder *time.Time) *models.Invoice {
+		invoice, createErr := invoices.CreateInvoice(ctx, models.CreateInvoiceRequest{
+			Number:   number,
+			ClientID: client.ID,
+			Date:     now.AddDate(0, 0, -60),
+			DueDate:  now.AddDate(0, 0, dueDays),
+		})
+		require.NoError(t, createErr)
+		invoice.Status = status
+		invoice.Total = money.FromFloat(100)
+		invoice.LastReminderAt = lastReminder
+		require.NoError(t, invoices.UpdateInvoiceDirectly(ctx, invoice))
+		return invoice
+	}
+	recent := now.AddDate(0, 0, -2)
+	stale := now.AddDate(0, 0, -10)
+	create("INV-001", models.StatusOverdue, -5, nil)
+	create("INV-002", models.StatusSent, -20, &stale)
+	create("INV-003", models.StatusOverdue, -20, &recent) // Reminded too recently
+	create("INV-004", models.StatusSent, 10, nil)         // Not due yet
+	create("INV-005", models.StatusPaid, -20, nil)
+	create("INV-006", models.StatusVoided, -20, nil)
+	draft := create("INV-007", models.StatusDraft, -20, nil)
+
+	remindable, err := invoices.ListRemindableInvoices(ctx, now, 7*24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, remindable, 2)
+	assert.Equal(t, "INV-002", remindable[0].Number) // Oldest due date first
+	assert.Equal(t, "INV-001", remindable[1].Number)
+
+	reminded, err := invoices.RecordReminder(ctx, remindable[1].ID, now)
+	require.NoError(t, err)
+	require.NotNil(t, reminded.LastReminderAt)
+	assert.True(t, reminded.LastReminderAt.Equal(now))
+
+	remindable, err = invoices.ListRemindableInvoices(ctx, now, 7*24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, remindable, 1)
+	assert.Equal(t, "INV-002", remindable[0].Number)
+
+	_, err = invoices.RecordReminder(ctx, draft.ID, now)
+	require.ErrorIs(t, err, models.ErrCannotRemindInvoice)
+}
+
+func TestInvoiceLocking(t *testing.T) {
+	ctx := context.Background()
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+
+	generator := NewSequentialGenerator("test")
+	clients := NewClientService(store, store, &SimpleTestLogger{}, generator)
+	invoices := NewInvoiceService(store, store, &SimpleTestLogger{}, generator)
+
+	client, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Acme", Email: "billing@acme.example"})
+	require.NoError(t, err)
+
+	invoice, err := invoices.CreateInvoice(ctx, models.CreateInvoiceRequest{
+		Number:   "INV-001",
+		ClientID: client.ID,
+		Date:     time.Now(),
+		DueDate:  time.Now().AddDate(0, 0, 30),
+	})
+	require.NoError(t, err)
+	amount := 1000.0
+	setup := models.LineItem{Type: models.LineItemTypeFixed, Date: time.Now(), Description: "Setup", Amount: &amount, Total: money.FromFloat(amount)}
+	_, err = invoices.AddLineItemToInvoice(ctx, invoice.ID, setup)
+	require.NoError(t, err)
+
+	// Changes sent along with the status apply before the invoice locks
+	sent, err := invoices.UpdateInvoice(ctx, models.UpdateInvoiceRequest{
+		ID:              invoice.ID,
+		Status:          ptrString(models.StatusSent),
+		DiscountPercent: ptrFloat64(10),
+	})
+	require.NoError(t, err)
+	assert.True(t, sent.Locked)
+	assert.Equal(t, money.FromFloat(900), sent.Total)
+
+	// Financial changes are refused
+	_, err = invoices.UpdateInvoice(ctx, models.UpdateInvoiceRequest{ID: invoice.ID, DiscountPercent: ptrFloat64(20)})
+	require.ErrorIs(t, err, models.ErrInvoiceLocked)
+	_, err = invoices.SetInvoiceTaxRate(ctx, invoice.ID, 0.1, true)
+	require.ErrorIs(t, err, models.ErrInvoiceLocked)
+	_, err = invoices.AddLineItemToInvoice(ctx, invoice.ID, setup)
+	require.ErrorIs(t, err, models.ErrInvoiceLocked)
+
+	// Non-financial changes and status transitions are not
+	updated, err := invoices.UpdateInvoice(ctx, models.UpdateInvoiceRequest{ID: invoice.ID, Notes: ptrString("Thanks")})
+	require.NoError(t, err)
+	assert.Equal(t, "Thanks", updated.Notes)
+	updated, err = invoices.UpdateInvoice(ctx, models.UpdateInvoiceRequest{ID: invoice.ID, Status: ptrString(models.StatusOverdue)})
+	require.NoError(t, err)
+	assert.True(t, updated.Locked)
+	assert.Equal(t, money.FromFloat(900), updated.Total)
+
+	// Unlocking records who and why and lets items change again
+	_, err = invoices.UnlockInvoice(ctx, invoice.ID, "alice", "")
+	require.ErrorIs(t, err, models.ErrUnlockReasonRequired)
+	unlocked, err := invoices.UnlockInvoice(ctx, invoice.ID, "alice", "Missing setup fee")
+	require.NoError(t, err)
+	assert.False(t, unlocked.Locked)
+	require.Len(t, unlocked.Unlocks, 1)
+	assert.Equal(t, "alice", unlocked.Unlocks[0].By)
+
+	corrected, err := invoices.AddLineItemToInvoice(ctx, invoice.ID, setup)
+	require.NoError(t, err)
+	assert.Len(t, corrected.LineItems, 2)
+
+	relocked, err := invoices.LockInvoice(ctx, invoice.ID)
+	require.NoError(t, err)
+	assert.True(t, relocked.Locked)
+	_, err = invoices.AddLineItemToInvoice(ctx, invoice.ID, setup)
+	require.ErrorIs(t, err, models.ErrInvoiceLocked)
+
+	stored, err := invoices.GetInvoice(ctx, invoice.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Locked)
+	assert.Len(t, stored.Unlocks, 1)
+}
+
+func TestInvoiceEvents(t *testing.T) {
+	ctx := context.Background()
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+
+	generator := NewSequentialGenerator("test")
+	clients := NewClientService(store, store, &SimpleTestLogger{}, generator)
+	invoices := NewInvoiceService(store, store, &SimpleTestLogger{}, generator)
+
+	client, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Acme", Email: "billing@acme.example"})
+	require.NoError(t, err)
+
+	invoice, err := invoices.CreateInvoice(ctx, models.CreateInvoiceRequest{
+		Number:   "INV-001",
+		ClientID: client.ID,
+		Currency: "USD",
+		Date:     time.Now(),
+		DueDate:  time.Now().AddDate(0, 0, 30),
+	})
+	require.NoError(t, err)
+
+	amount := 500.0
+	_, err = invoices.AddLineItemToInvoice(ctx, invoice.ID, models.LineItem{
+		Type: models.LineItemTypeFixed, Date: time.Now(), Description: "Setup", Amount: &amount, Total: money.FromFloat(amount),
+	})
+	require.NoError(t, err)
+	_, err = invoices.UpdateInvoice(ctx, models.UpdateInvoiceRequest{ID: invoice.ID, Status: ptrString(models.StatusSent)})
+	require.NoError(t, err)
+	_, err = invoices.UpdateInvoice(ctx, models.UpdateInvoiceRequest{ID: invoice.ID, Notes: ptrString("Thanks")})
+	require.NoError(t, err)
+	_, err = invoices.RecordPayment(ctx, invoice.ID, models.Payment{Amount: 500, Date: time.Now(), Method: models.PaymentMethodACH, Reference: "ACH-1"})
+	require.NoError(t, err)
+
+	stored, err := invoices.GetInvoice(ctx, invoice.ID)
+	require.NoError(t, err)
+
+	var types, summaries []string
+	for _, event := range stored.History() {
+		types = append(types, event.Type)
+		summaries = append(summaries, event.Summary)
+	}
+	assert.Equal(t, []string{
+		models.EventCreated,
+		models.EventLineItemAdded,
+		models.EventSent,
+		models.EventPaymentRecorded,
+		models.EventStatusChanged,
+	}, types)
+	assert.Equal(t, []string{
+		"Invoice INV-001 created for Acme",
+		`Added "Setup" ($500.00)`,
+		"Invoice sent",
+		"Payment of $500.00 recorded (ACH, reference ACH-1)",
+		"Status changed from sent to paid",
+	}, summaries)
+}
+
+func TestLineItemChanges(t *testing.T) {
+	ctx := context.Background()
+	store := jsonStorage.NewJSONStorage(t.TempDir(), &SimpleTestLogger{})
+	require.NoError(t, store.Initialize(ctx))
+
+	generator := NewSequentialGenerator("test")
+	clients := NewClientService(store, store, &SimpleTestLogger{}, generator)
+	invoices := NewInvoiceService(store, store, &SimpleTestLogger{}, generator)
+
+	client, err := clients.CreateClient(ctx, models.CreateClientRequest{Name: "Acme", Email: "billing@acme.example"})
+	require.NoError(t, err)
+	invoice, err := invoices.CreateInvoice(ctx, models.CreateInvoiceRequest{
+		Number:   "INV-001",
+		ClientID: client.ID,
+		Currency: "USD",
+		Date:     time.Now(),
+		DueDate:  time.Now().AddDate(0, 0, 30),
+	})
+	require.NoError(t, err)
+
+	hours, rate, amount := 8.0, 100.0, 500.0
+	_, err = invoices.AddLineItemToInvoice(ctx, invoice.ID, models.LineItem{
+		Type: models.LineItemTypeHourly, Date: time.Now(), Description: "Development", Hours: &hours, Rate: &rate, Total: money.Product(hours, rate),
+	})
+	require.NoError(t, err)
+	withSetup, err := invoices.AddLineItemToInvoice(ctx, invoice.ID, models.LineItem{
+		Type: models.LineItemTypeFixed, Date: time.Now(), Description: "Setup", Amount: &amount, Total: money.FromFloat(amount),
+	})
+	require.NoError(t, err)
+	require.Len(t, withSetup.LineItems, 2)
+	hourlyID, fixedID := withSetup.LineItems[0].ID, withSetup.LineItems[1].ID
+
+	// Updating recalculates the item and the invoice, and is saved
+	updated, err := invoices.UpdateLineItemOnInvoice(ctx, invoice.ID, hourlyID, func(item *models.LineItem) error {
+		nine := 9.0
+		item.Hours = &nine
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, money.FromFloat(900), updated.LineItems[0].Total)
+	assert.Equal(t, money.FromFloat(1400), updated.Total)
+
+	_, err = invoices.UpdateLineItemOnInvoice(ctx, invoice.ID, "missing", func(*models.LineItem) error { return nil })
+	require.ErrorIs(t, err, models.ErrLineItemNotFound)
+
+	// Removing recalculates the invoice
+	removed, err := invoices.RemoveLineItemFromInvoice(ctx, invoice.ID, fixedID)
+	require.NoError(t, err)
+	assert.Len(t, removed.LineItems, 1)
+	assert.Equal(t, money.FromFloat(900), removed.Total)
+
+	stored, err := invoices.GetInvoice(ctx, invoice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, money.FromFloat(900), stored.Total)
+	var summaries []string
+	for _, event := range stored.History() {
+		summaries = append(summaries, event.Summary)
+	}
+	assert.Contains(t, summaries, `Updated "Development" ($800.00 → $900.00)`)
+	assert.Contains(t, summaries, `Removed "Setup" ($500.00)`)
+
+	// Sent invoices are locked, and paid invoices are refused even once unlocked
+	_, err = invoices.UpdateInvoice(ctx, models.UpdateInvoiceRequest{ID: invoice.ID, Status: ptrString(models.StatusSent)})
+	require.NoError(t, err)
+	_, err = invoices.RemoveLineItemFromInvoice(ctx, invoice.ID, hourlyID)
+	require.ErrorIs(t, err, models.ErrInvoiceLocked)
+
+	_, err = invoices.RecordPayment(ctx, invoice.ID, models.Payment{Amount: 900, Date: time.Now(), Method: models.PaymentMethodACH})
+	require.NoError(t, err)
+	_, err = invoices.UnlockInvoice(ctx, invoice.ID, "alice", "Correction")
+	require.NoError(t, err)
+	_, err = invoices.UpdateLineItemOnInvoice(ctx, invoice.ID, hourlyID, func(*models.LineItem) error { return nil })
+	require.ErrorIs(t, err, models.ErrCannotChangeLineItemsOnPaid)
+}
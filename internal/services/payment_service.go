@@ -9,6 +9,7 @@ import (
 
 	"github.com/mrz1836/go-invoice/internal/blockchain"
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/notify"
 	"github.com/mrz1836/go-invoice/internal/storage"
 )
 
@@ -26,7 +27,8 @@ var (
 // PaymentService provides payment verification and management operations
 type PaymentService struct {
 	invoiceStorage storage.InvoiceStorage
-	logger         Logger // Logger interface is defined in invoice_service.go
+	logger         Logger          // Logger interface is defined in invoice_service.go
+	notifier       notify.Notifier // Optional, told when an invoice is marked paid
 }
 
 // NewPaymentService creates a new payment service
@@ -40,6 +42,11 @@ func NewPaymentService(
 	}
 }
 
+// SetNotifier sets the notifier told when the service marks an invoice paid
+func (s *PaymentService) SetNotifier(notifier notify.Notifier) {
+	s.notifier = notifier
+}
+
 // VerifyPayment verifies payment for an invoice using a blockchain provider
 func (s *PaymentService) VerifyPayment(
 	ctx context.Context,
@@ -77,7 +84,7 @@ func (s *PaymentService) VerifyPayment(
 	verification := &models.PaymentVerification{
 		InvoiceID:      invoice.ID,
 		Method:         config.PaymentMethod,
-		ExpectedAmount: invoice.Total,
+		ExpectedAmount: invoice.Total.Float64(),
 		ReceivedAmount: balanceResult.Balance,
 		Currency:       string(token),
 		WalletAddress:  address,
@@ -90,7 +97,7 @@ func (s *PaymentService) VerifyPayment(
 	}
 
 	// Try to get transaction details if balance is sufficient
-	if balanceResult.Balance >= invoice.Total {
+	if balanceResult.Balance >= invoice.Total.Float64() {
 		txs, txErr := s.getRelevantTransactions(ctx, provider, address, token, invoice)
 		if txErr != nil {
 			s.logger.Debug("failed to get transactions", "error", txErr)
@@ -162,16 +169,19 @@ func (s *PaymentService) MarkInvoiceAsPaid(
 	}
 
 	// Update the invoice object
+	oldStatus := invoice.Status
 	invoice.Status = *updateReq.Status
 	if updateReq.Description != nil {
 		invoice.Description = *updateReq.Description
 	}
+	recordStatusEvent(invoice, oldStatus, time.Now())
 
 	// Perform update (storage layer handles version increment)
 	err = s.invoiceStorage.UpdateInvoice(ctx, invoice)
 	if err != nil {
 		return fmt.Errorf("failed to update invoice status: %w", err)
 	}
+	notifyStatusChange(ctx, s.notifier, invoice, oldStatus)
 
 	s.logger.Info("invoice marked as paid", "invoice_id", invoiceID, "method", verification.Method)
 	return nil
@@ -226,7 +236,7 @@ func (s *PaymentService) getRelevantTransactions(
 	startTime := invoice.CreatedAt
 	endTime := invoice.DueDate.AddDate(0, 0, 30)
 
-	minAmount := invoice.Total
+	minAmount := invoice.Total.Float64()
 
 	query := blockchain.TransactionQuery{
 		Address:   address,
@@ -11,6 +11,7 @@ import (
 
 	"github.com/mrz1836/go-invoice/internal/blockchain"
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
 )
 
 func TestPaymentService_VerifyPayment(t *testing.T) {
@@ -27,7 +28,7 @@ func TestPaymentService_VerifyPayment(t *testing.T) {
 	testInvoice := &models.Invoice{
 		ID:                  testInvoiceID001,
 		Number:              testInvoiceNum,
-		Total:               100.00,
+		Total:               money.FromFloat(100.00),
 		Client:              testClient,
 		Status:              models.StatusSent,
 		USDCAddressOverride: &usdcAddress,
@@ -177,7 +178,7 @@ func TestPaymentService_MarkInvoiceAsPaid(t *testing.T) {
 				ID:          testInvoiceID001,
 				Number:      testInvoiceNum,
 				Status:      tt.initialStatus,
-				Total:       100.00,
+				Total:       money.FromFloat(100.00),
 				Client:      testClient,
 				Description: "Test invoice",
 				Version:     1,
@@ -227,7 +228,7 @@ func TestPaymentService_AddressOverrides(t *testing.T) {
 	invoice := &models.Invoice{
 		ID:                  testInvoiceID001,
 		Number:              testInvoiceNum,
-		Total:               100.00,
+		Total:               money.FromFloat(100.00),
 		USDCAddressOverride: &invoiceAddress,
 		CreatedAt:           time.Now(),
 		DueDate:             time.Now().AddDate(0, 0, 30),
@@ -258,7 +259,7 @@ func TestPaymentService_NoAddressConfigured(t *testing.T) {
 	invoice := &models.Invoice{
 		ID:        testInvoiceID001,
 		Number:    testInvoiceNum,
-		Total:     100.00,
+		Total:     money.FromFloat(100.00),
 		CreatedAt: time.Now(),
 		DueDate:   time.Now().AddDate(0, 0, 30),
 	}
@@ -375,7 +376,7 @@ func TestPaymentService_UnsupportedPaymentMethods(t *testing.T) {
 
 	invoice := &models.Invoice{
 		ID:     testInvoiceID001,
-		Total:  100.00,
+		Total:  money.FromFloat(100.00),
 		Status: models.StatusSent,
 	}
 
@@ -581,7 +582,7 @@ func TestPaymentService_ContextCancellation(t *testing.T) {
 
 	invoice := &models.Invoice{
 		ID:    testInvoiceID001,
-		Total: 100.00,
+		Total: money.FromFloat(100.00),
 	}
 
 	config := PaymentVerificationConfig{
@@ -624,7 +625,7 @@ func TestPaymentService_MarkInvoiceAsPaid_WithPaymentNotes(t *testing.T) {
 		ID:          testInvoiceID001,
 		Number:      testInvoiceNum,
 		Status:      models.StatusSent,
-		Total:       100.00,
+		Total:       money.FromFloat(100.00),
 		Description: "Original description",
 		Version:     1,
 	}
@@ -664,7 +665,7 @@ func TestPaymentService_GetRelevantTransactions_TimeWindow(t *testing.T) {
 
 	invoice := &models.Invoice{
 		ID:        testInvoiceID001,
-		Total:     100.00,
+		Total:     money.FromFloat(100.00),
 		CreatedAt: createdAt,
 		DueDate:   dueDate,
 	}
@@ -4,8 +4,10 @@ package storage
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
 )
 
 // Storage error types for comprehensive error handling
@@ -223,12 +225,20 @@ type RestoreOptions struct {
 	DryRun            bool   `json:"dry_run"`
 }
 
-// InvoiceListResult represents the result of a list operation with pagination
+// InvoiceListResult represents the result of a list operation with pagination.
+// CurrentPage and TotalPages are 1-based; an unlimited list is a single page.
 type InvoiceListResult struct {
-	Invoices   []*models.Invoice `json:"invoices"`
-	TotalCount int64             `json:"total_count"`
-	HasMore    bool              `json:"has_more"`
-	NextOffset int               `json:"next_offset,omitempty"`
+	Invoices    []*models.Invoice `json:"invoices"`
+	TotalCount  int64             `json:"total_count"`
+	HasMore     bool              `json:"has_more"`
+	NextOffset  int               `json:"next_offset,omitempty"`
+	CurrentPage int               `json:"current_page"`
+	TotalPages  int               `json:"total_pages"`
+	PageSize    int               `json:"page_size"`
+
+	// Warnings lists the invoices on the page that could not be read and were skipped,
+	// each with the reason. Stores that never skip invoices leave it empty.
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // ClientListResult represents the result of a client list operation with pagination
@@ -238,3 +248,58 @@ type ClientListResult struct {
 	HasMore    bool             `json:"has_more"`
 	NextOffset int              `json:"next_offset,omitempty"`
 }
+
+// ClientIndexEntry holds the searchable fields of a client in the client index
+type ClientIndexEntry struct {
+	ID      models.ClientID `json:"id"`
+	Name    string          `json:"name"`
+	Email   string          `json:"email"`
+	Address string          `json:"address,omitempty"`
+	Active  bool            `json:"active"`
+}
+
+// NewClientIndexEntry builds the index entry for client
+func NewClientIndexEntry(client *models.Client) ClientIndexEntry {
+	return ClientIndexEntry{
+		ID:      client.ID,
+		Name:    client.Name,
+		Email:   client.Email,
+		Address: client.Address,
+		Active:  client.Active,
+	}
+}
+
+// InvoiceIndexEntry holds the fields of an invoice needed to filter, sort and count
+// invoices without loading the full invoice
+type InvoiceIndexEntry struct {
+	ID         models.InvoiceID `json:"id"`
+	Number     string           `json:"number"`
+	ClientID   models.ClientID  `json:"client_id"`
+	ClientName string           `json:"client_name"`
+	Date       time.Time        `json:"date"`
+	DueDate    time.Time        `json:"due_date"`
+	Status     string           `json:"status"`
+	PONumber   string           `json:"po_number,omitempty"`
+	Total      money.Amount     `json:"total"`
+	Version    int              `json:"version"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	DeletedAt  *time.Time       `json:"deleted_at,omitempty"`
+}
+
+// NewInvoiceIndexEntry builds the index entry for invoice
+func NewInvoiceIndexEntry(invoice *models.Invoice) InvoiceIndexEntry {
+	return InvoiceIndexEntry{
+		ID:         invoice.ID,
+		Number:     invoice.Number,
+		ClientID:   invoice.Client.ID,
+		ClientName: invoice.Client.Name,
+		Date:       invoice.Date,
+		DueDate:    invoice.DueDate,
+		Status:     invoice.Status,
+		PONumber:   invoice.PONumber,
+		Total:      invoice.Total,
+		Version:    invoice.Version,
+		UpdatedAt:  invoice.UpdatedAt,
+		DeletedAt:  invoice.DeletedAt,
+	}
+}
@@ -10,7 +10,9 @@ import (
 // This interface is consumer-driven and focuses on core CRUD operations
 type InvoiceStorage interface {
 	// CreateInvoice stores a new invoice
-	// Returns ConflictError if invoice with same ID already exists
+	// Returns ConflictError if invoice with same ID already exists, and an error wrapping
+	// models.ErrInvoiceNumberExists if any invoice, including one in the trash, has its number.
+	// Both checks and the write are atomic, also between processes sharing the storage.
 	CreateInvoice(ctx context.Context, invoice *models.Invoice) error
 
 	// GetInvoice retrieves an invoice by ID
@@ -67,6 +69,21 @@ type ClientStorage interface {
 	ExistsClient(ctx context.Context, id models.ClientID) (bool, error)
 }
 
+// ClientSearchIndex defines an optional interface for storages that keep a lightweight
+// index of client search fields, so searches do not need to load every client
+type ClientSearchIndex interface {
+	// ListClientIndex returns the indexed search fields for every client
+	ListClientIndex(ctx context.Context) ([]ClientIndexEntry, error)
+}
+
+// ClientHardDeleter defines an optional interface for storages that can remove a client
+// permanently rather than only marking it inactive
+type ClientHardDeleter interface {
+	// HardDeleteClient permanently removes a client
+	// Returns NotFoundError if client doesn't exist
+	HardDeleteClient(ctx context.Context, id models.ClientID) error
+}
+
 // StorageInitializer defines the interface for storage system initialization
 // Consumer-driven interface for setup and configuration operations
 type StorageInitializer interface {
@@ -145,13 +162,14 @@ type Transaction interface {
 
 // StorageInfo represents information about the storage system
 type StorageInfo struct {
-	Type             string `json:"type"`              // "json", "sqlite", etc.
-	Version          string `json:"version"`           // Storage format version
-	Path             string `json:"path"`              // Storage location
-	Initialized      bool   `json:"initialized"`       // Whether storage is initialized
-	ReadOnly         bool   `json:"read_only"`         // Whether storage is read-only
-	SupportsBackups  bool   `json:"supports_backups"`  // Whether backups are supported
-	SupportsIndexing bool   `json:"supports_indexing"` // Whether indexing is supported
+	Type             string `json:"type"`                       // "json", "sqlite", etc.
+	Version          string `json:"version"`                    // Storage format version
+	Path             string `json:"path"`                       // Storage location
+	Initialized      bool   `json:"initialized"`                // Whether storage is initialized
+	ReadOnly         bool   `json:"read_only"`                  // Whether storage is read-only
+	SupportsBackups  bool   `json:"supports_backups"`           // Whether backups are supported
+	SupportsIndexing bool   `json:"supports_indexing"`          // Whether indexing is supported
+	LastBackupTime   string `json:"last_backup_time,omitempty"` // When the newest backup was taken (RFC 3339)
 }
 
 // BackupInfo represents information about a backup
@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"cmp"
+	"sort"
+	"strings"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// Filtering, sorting and paging shared by the storage backends, so every backend answers
+// the same InvoiceFilter with the same invoices in the same order
+
+// MatchesInvoiceFilter reports whether the indexed invoice matches filter
+func MatchesInvoiceFilter(invoice InvoiceIndexEntry, filter models.InvoiceFilter) bool {
+	// Soft-deleted invoices are only listed on request
+	if invoice.DeletedAt != nil && !filter.IncludeDeleted {
+		return false
+	}
+
+	// Status filter
+	if filter.Status != "" && invoice.Status != filter.Status {
+		return false
+	}
+
+	// Client ID filter
+	if filter.ClientID != "" && invoice.ClientID != filter.ClientID {
+		return false
+	}
+
+	// Purchase order filter
+	if filter.PONumber != "" && invoice.PONumber != filter.PONumber {
+		return false
+	}
+
+	// Date range filters
+	if !filter.DateFrom.IsZero() && invoice.Date.Before(filter.DateFrom) {
+		return false
+	}
+	if !filter.DateTo.IsZero() && invoice.Date.After(filter.DateTo) {
+		return false
+	}
+
+	// Due date range filters
+	if !filter.DueDateFrom.IsZero() && invoice.DueDate.Before(filter.DueDateFrom) {
+		return false
+	}
+	if !filter.DueDateTo.IsZero() && invoice.DueDate.After(filter.DueDateTo) {
+		return false
+	}
+
+	// Amount range filters
+	if filter.AmountMin > 0 && invoice.Total.Float64() < filter.AmountMin {
+		return false
+	}
+	if filter.AmountMax > 0 && invoice.Total.Float64() > filter.AmountMax {
+		return false
+	}
+
+	// Changed-since filter; UpdatedAt is never before CreatedAt, so new invoices count as changes
+	if !filter.UpdatedAfter.IsZero() && !invoice.UpdatedAt.After(filter.UpdatedAfter) {
+		return false
+	}
+
+	return true
+}
+
+// SortInvoiceEntries orders invoices as requested by filter, newest first when no sort
+// field is set. Ties fall back to the invoice number and ID so pages are deterministic.
+func SortInvoiceEntries(invoices []InvoiceIndexEntry, filter models.InvoiceFilter) {
+	sortBy, desc := filter.SortBy, filter.SortDesc
+	if sortBy == "" {
+		sortBy, desc = models.InvoiceSortDate, true
+	}
+
+	compare := func(a, b InvoiceIndexEntry) int {
+		switch sortBy {
+		case models.InvoiceSortAmount:
+			return cmp.Compare(a.Total.Cents(), b.Total.Cents())
+		case models.InvoiceSortStatus:
+			return strings.Compare(a.Status, b.Status)
+		case models.InvoiceSortClient:
+			return strings.Compare(strings.ToLower(a.ClientName), strings.ToLower(b.ClientName))
+		case models.InvoiceSortNumber:
+			return strings.Compare(a.Number, b.Number)
+		default:
+			return a.Date.Compare(b.Date)
+		}
+	}
+
+	sort.SliceStable(invoices, func(i, j int) bool {
+		a, b := invoices[i], invoices[j]
+		order := compare(a, b)
+		if desc {
+			order = -order
+		}
+		if order == 0 {
+			order = cmp.Or(strings.Compare(a.Number, b.Number), strings.Compare(string(a.ID), string(b.ID)))
+		}
+		return order < 0
+	})
+}
+
+// CountFilter returns the part of filter CountInvoices honors: the match criteria without
+// paging, sorting, soft-deleted invoices or the changed-since filter
+func CountFilter(filter models.InvoiceFilter) models.InvoiceFilter {
+	return models.InvoiceFilter{
+		Status:      filter.Status,
+		ClientID:    filter.ClientID,
+		PONumber:    filter.PONumber,
+		DateFrom:    filter.DateFrom,
+		DateTo:      filter.DateTo,
+		DueDateFrom: filter.DueDateFrom,
+		DueDateTo:   filter.DueDateTo,
+		AmountMin:   filter.AmountMin,
+		AmountMax:   filter.AmountMax,
+	}
+}
+
+// PageBounds returns the slice bounds of the page filter requests from total matches.
+// A limit of zero or less returns everything from the offset on.
+func PageBounds(total int, filter models.InvoiceFilter) (start, end int) {
+	start = min(filter.Offset, total)
+
+	end = start + filter.Limit
+	if filter.Limit <= 0 || end > total {
+		end = total
+	}
+
+	return start, end
+}
+
+// NewInvoiceListResult wraps the invoices on the page ending at end with the paging
+// metadata for total matches of filter
+func NewInvoiceListResult(invoices []*models.Invoice, total, end int, filter models.InvoiceFilter) *InvoiceListResult {
+	result := &InvoiceListResult{
+		Invoices:   invoices,
+		TotalCount: int64(total),
+		HasMore:    end < total,
+	}
+
+	if result.HasMore {
+		result.NextOffset = end
+	}
+
+	// Page metadata; without a limit everything is on one page
+	result.PageSize = filter.Limit
+	result.CurrentPage = 1
+	result.TotalPages = 1
+	if filter.Limit <= 0 {
+		result.PageSize = total
+	} else {
+		result.CurrentPage = filter.Offset/filter.Limit + 1
+		if pages := (total + filter.Limit - 1) / filter.Limit; pages > 1 {
+			result.TotalPages = pages
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+func TestMatchesInvoiceFilter(t *testing.T) {
+	now := time.Now()
+	invoice := &models.Invoice{
+		ID:     "INV-001",
+		Number: "INV-2024-001",
+		Client: models.Client{
+			ID: "CLIENT-001",
+		},
+		Date:      now,
+		DueDate:   now.AddDate(0, 0, 30),
+		Status:    models.StatusSent,
+		PONumber:  "4500012345",
+		Total:     money.FromFloat(1500.0),
+		CreatedAt: now.Add(-24 * time.Hour),
+		UpdatedAt: now,
+	}
+
+	tests := []struct {
+		name     string
+		filter   models.InvoiceFilter
+		expected bool
+	}{
+		{
+			name:     "MatchingPONumber",
+			filter:   models.InvoiceFilter{PONumber: "4500012345"},
+			expected: true,
+		},
+		{
+			name:     "NonMatchingPONumber",
+			filter:   models.InvoiceFilter{PONumber: "45000123"},
+			expected: false,
+		},
+		{
+			name:     "EmptyFilter",
+			filter:   models.InvoiceFilter{},
+			expected: true,
+		},
+		{
+			name: "MatchingStatus",
+			filter: models.InvoiceFilter{
+				Status: models.StatusSent,
+			},
+			expected: true,
+		},
+		{
+			name: "NonMatchingStatus",
+			filter: models.InvoiceFilter{
+				Status: models.StatusPaid,
+			},
+			expected: false,
+		},
+		{
+			name: "MatchingClientID",
+			filter: models.InvoiceFilter{
+				ClientID: "CLIENT-001",
+			},
+			expected: true,
+		},
+		{
+			name: "NonMatchingClientID",
+			filter: models.InvoiceFilter{
+				ClientID: "CLIENT-002",
+			},
+			expected: false,
+		},
+		{
+			name: "DateInRange",
+			filter: models.InvoiceFilter{
+				DateFrom: now.AddDate(0, 0, -1),
+				DateTo:   now.AddDate(0, 0, 1),
+			},
+			expected: true,
+		},
+		{
+			name: "DateBeforeRange",
+			filter: models.InvoiceFilter{
+				DateFrom: now.AddDate(0, 0, 1),
+			},
+			expected: false,
+		},
+		{
+			name: "DateAfterRange",
+			filter: models.InvoiceFilter{
+				DateTo: now.AddDate(0, 0, -1),
+			},
+			expected: false,
+		},
+		{
+			name: "DueDateInRange",
+			filter: models.InvoiceFilter{
+				DueDateFrom: now.AddDate(0, 0, 29),
+				DueDateTo:   now.AddDate(0, 0, 31),
+			},
+			expected: true,
+		},
+		{
+			name: "AmountInRange",
+			filter: models.InvoiceFilter{
+				AmountMin: 1000.0,
+				AmountMax: 2000.0,
+			},
+			expected: true,
+		},
+		{
+			name: "AmountBelowRange",
+			filter: models.InvoiceFilter{
+				AmountMin: 2000.0,
+			},
+			expected: false,
+		},
+		{
+			name: "AmountAboveRange",
+			filter: models.InvoiceFilter{
+				AmountMax: 1000.0,
+			},
+			expected: false,
+		},
+		{
+			name: "UpdatedAfterSince",
+			filter: models.InvoiceFilter{
+				UpdatedAfter: now.Add(-time.Hour),
+			},
+			expected: true,
+		},
+		{
+			name: "NotUpdatedAfterSince",
+			filter: models.InvoiceFilter{
+				UpdatedAfter: now.Add(time.Hour),
+			},
+			expected: false,
+		},
+		{
+			name: "UpdatedExactlyAtSince",
+			filter: models.InvoiceFilter{
+				UpdatedAfter: now,
+			},
+			expected: false,
+		},
+		{
+			name: "UpdatedAfterWithOtherFilters",
+			filter: models.InvoiceFilter{
+				Status:       models.StatusSent,
+				ClientID:     "CLIENT-001",
+				UpdatedAfter: now.Add(-time.Hour),
+			},
+			expected: true,
+		},
+		{
+			name: "UpdatedAfterButOtherFilterFails",
+			filter: models.InvoiceFilter{
+				Status:       models.StatusPaid,
+				UpdatedAfter: now.Add(-time.Hour),
+			},
+			expected: false,
+		},
+		{
+			name: "MultipleMatchingFilters",
+			filter: models.InvoiceFilter{
+				Status:    models.StatusSent,
+				ClientID:  "CLIENT-001",
+				AmountMin: 1000.0,
+				AmountMax: 2000.0,
+			},
+			expected: true,
+		},
+		{
+			name: "OneNonMatchingFilter",
+			filter: models.InvoiceFilter{
+				Status:    models.StatusPaid, // Non-matching
+				ClientID:  "CLIENT-001",
+				AmountMin: 1000.0,
+				AmountMax: 2000.0,
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MatchesInvoiceFilter(NewInvoiceIndexEntry(invoice), tt.filter))
+		})
+	}
+}
+
+func TestSortInvoiceEntries(t *testing.T) {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []InvoiceIndexEntry{
+		{ID: "INV-B", Number: "2", Date: day, Total: money.FromFloat(50)},
+		{ID: "INV-A", Number: "1", Date: day, Total: money.FromFloat(200)},
+		{ID: "INV-C", Number: "3", Date: day.AddDate(0, 0, 1), Total: money.FromFloat(100)},
+	}
+
+	ids := func() []models.InvoiceID {
+		out := make([]models.InvoiceID, 0, len(entries))
+		for _, entry := range entries {
+			out = append(out, entry.ID)
+		}
+		return out
+	}
+
+	// Newest first by default, ties broken by number
+	SortInvoiceEntries(entries, models.InvoiceFilter{})
+	assert.Equal(t, []models.InvoiceID{"INV-C", "INV-A", "INV-B"}, ids())
+
+	SortInvoiceEntries(entries, models.InvoiceFilter{SortBy: models.InvoiceSortAmount})
+	assert.Equal(t, []models.InvoiceID{"INV-B", "INV-C", "INV-A"}, ids())
+}
+
+func TestInvoicePaging(t *testing.T) {
+	tests := []struct {
+		name                          string
+		total                         int
+		filter                        models.InvoiceFilter
+		start, end, page, pages, next int
+		hasMore                       bool
+	}{
+		{name: "NoLimit", total: 5, filter: models.InvoiceFilter{}, start: 0, end: 5, page: 1, pages: 1},
+		{name: "FirstPage", total: 5, filter: models.InvoiceFilter{Limit: 2}, start: 0, end: 2, page: 1, pages: 3, next: 2, hasMore: true},
+		{name: "LastPage", total: 5, filter: models.InvoiceFilter{Limit: 2, Offset: 4}, start: 4, end: 5, page: 3, pages: 3},
+		{name: "OffsetPastEnd", total: 5, filter: models.InvoiceFilter{Limit: 2, Offset: 10}, start: 5, end: 5, page: 6, pages: 3},
+		{name: "Empty", total: 0, filter: models.InvoiceFilter{Limit: 10}, start: 0, end: 0, page: 1, pages: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := PageBounds(tt.total, tt.filter)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+
+			result := NewInvoiceListResult(nil, tt.total, end, tt.filter)
+			assert.Equal(t, int64(tt.total), result.TotalCount)
+			assert.Equal(t, tt.page, result.CurrentPage)
+			assert.Equal(t, tt.pages, result.TotalPages)
+			assert.Equal(t, tt.hasMore, result.HasMore)
+			assert.Equal(t, tt.next, result.NextOffset)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package json
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// Automatic backup errors
+var (
+	ErrAutoBackupRunning    = fmt.Errorf("automatic backups are already running")
+	ErrAutoBackupNotRunning = fmt.Errorf("automatic backups are not running")
+)
+
+// Automatic backup defaults
+const (
+	DefaultAutoBackupInterval     = 24 * time.Hour
+	DefaultAutoBackupMaxMutations = 50
+	DefaultAutoBackupDebounce     = 2 * time.Second
+
+	// maxDebounceDelays bounds how many debounce periods a due backup may be pushed back by
+	// continuous writes before it is taken anyway
+	maxDebounceDelays = 10
+)
+
+// AutoBackupOptions configures automatic backups. A backup is taken after MaxMutations writes
+// or once Interval has passed with unsaved writes, whichever comes first, after the store has
+// been quiet for Debounce.
+type AutoBackupOptions struct {
+	Interval     time.Duration
+	MaxMutations int64
+	Debounce     time.Duration
+}
+
+// autoBackup is the state of a running backup scheduler
+type autoBackup struct {
+	options AutoBackupOptions
+	notify  chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+	pending atomic.Int64 // Mutations since the last backup
+}
+
+// StartAutoBackup starts a background scheduler that backs up the store as it changes.
+// The scheduler runs until StopAutoBackup is called or ctx is canceled.
+func (s *JSONStorage) StartAutoBackup(ctx context.Context, options AutoBackupOptions) error {
+	if options.Interval <= 0 {
+		options.Interval = DefaultAutoBackupInterval
+	}
+	if options.MaxMutations <= 0 {
+		options.MaxMutations = DefaultAutoBackupMaxMutations
+	}
+	if options.Debounce <= 0 {
+		options.Debounce = DefaultAutoBackupDebounce
+	}
+
+	ab := &autoBackup{
+		options: options,
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if !s.autoBackup.CompareAndSwap(nil, ab) {
+		return ErrAutoBackupRunning
+	}
+
+	s.logger.Info("automatic backups enabled", "interval", options.Interval,
+		"max_mutations", options.MaxMutations, "debounce", options.Debounce)
+	go s.runAutoBackup(ctx, ab)
+	return nil
+}
+
+// StopAutoBackup stops the backup scheduler. Pending changes are backed up before it returns
+// when a backup is due, so short-lived processes still honor the interval.
+func (s *JSONStorage) StopAutoBackup(ctx context.Context) error {
+	ab := s.autoBackup.Swap(nil)
+	if ab == nil {
+		return ErrAutoBackupNotRunning
+	}
+
+	close(ab.done)
+	<-ab.stopped
+
+	pending := ab.pending.Load()
+	if pending == 0 {
+		return nil
+	}
+	if pending < ab.options.MaxMutations {
+		last, err := s.LastBackupTime(ctx)
+		if err != nil {
+			return err
+		}
+		if !last.IsZero() && time.Since(last) < ab.options.Interval {
+			return nil
+		}
+	}
+
+	return s.takeAutoBackup(ctx, ab)
+}
+
+// LastBackupTime returns when the newest backup was taken, or the zero time when there is none
+func (s *JSONStorage) LastBackupTime(ctx context.Context) (time.Time, error) {
+	if last := s.lastBackup.Load(); last != 0 {
+		return time.Unix(0, last), nil
+	}
+
+	backups, err := s.ListBackups(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(backups) == 0 {
+		return time.Time{}, nil
+	}
+
+	last, err := time.Parse(time.RFC3339, backups[0].CreatedAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid backup time %q: %w", backups[0].CreatedAt, err)
+	}
+	return last, nil
+}
+
+// recordMutation counts a write for the backup scheduler without blocking the writer
+func (s *JSONStorage) recordMutation() {
+	ab := s.autoBackup.Load()
+	if ab == nil {
+		return
+	}
+
+	ab.pending.Add(1)
+	select {
+	case ab.notify <- struct{}{}:
+	default:
+	}
+}
+
+// runAutoBackup waits for a backup to become due, then takes it once writes have been quiet
+// for the debounce period
+func (s *JSONStorage) runAutoBackup(ctx context.Context, ab *autoBackup) {
+	defer close(ab.stopped)
+
+	ticker := time.NewTicker(ab.options.Interval)
+	defer ticker.Stop()
+
+	debounce := time.NewTimer(ab.options.Debounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	var dueSince time.Time
+	schedule := func() {
+		if dueSince.IsZero() {
+			dueSince = time.Now()
+		} else if time.Since(dueSince) >= maxDebounceDelays*ab.options.Debounce {
+			return // Let the running timer fire rather than waiting for writes to stop
+		}
+		debounce.Reset(ab.options.Debounce)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ab.done:
+			return
+		case <-ab.notify:
+			if !dueSince.IsZero() || ab.pending.Load() >= ab.options.MaxMutations {
+				schedule()
+			}
+		case <-ticker.C:
+			if ab.pending.Load() > 0 {
+				schedule()
+			}
+		case <-debounce.C:
+			dueSince = time.Time{}
+			if err := s.takeAutoBackup(ctx, ab); err != nil {
+				s.logger.Error("automatic backup failed", "error", err)
+			}
+		}
+	}
+}
+
+// takeAutoBackup backs up the store and clears the mutations it covers
+func (s *JSONStorage) takeAutoBackup(ctx context.Context, ab *autoBackup) error {
+	pending := ab.pending.Load()
+
+	backupPath, err := s.CreateBackup(ctx, storage.BackupOptions{
+		IncludeInvoices:  true,
+		IncludeClients:   true,
+		CompressionLevel: gzip.DefaultCompression,
+	})
+	if err != nil {
+		return err
+	}
+
+	ab.pending.Add(-pending)
+	s.logger.Info("automatic backup created", "path", backupPath, "mutations", pending)
+	return nil
+}
@@ -0,0 +1,157 @@
+package json
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// createTestClients writes count new clients to s
+func createTestClients(t *testing.T, s *JSONStorage, prefix string, count int) {
+	t.Helper()
+	for i := range count {
+		require.NoError(t, s.CreateClient(context.Background(), &models.Client{
+			ID:        models.ClientID(fmt.Sprintf("%s-%d", prefix, i)),
+			Name:      testClientName,
+			Email:     testClientEmail,
+			Active:    true,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}))
+	}
+}
+
+// backupCount returns the number of backups in the backup directory
+func backupCount(t *testing.T, s *JSONStorage) int {
+	t.Helper()
+	backups, err := s.ListBackups(context.Background())
+	require.NoError(t, err)
+	return len(backups)
+}
+
+func TestAutoBackupAfterMutations(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+
+	require.NoError(t, s.StartAutoBackup(ctx, AutoBackupOptions{Interval: time.Hour, MaxMutations: 3, Debounce: 10 * time.Millisecond}))
+	t.Cleanup(func() { _ = s.StopAutoBackup(ctx) })
+
+	createTestClients(t, s, "CLIENT-A", 2)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 0, backupCount(t, s), "no backup before the mutation threshold")
+
+	createTestClients(t, s, "CLIENT-B", 1)
+	require.Eventually(t, func() bool { return backupCount(t, s) == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	last, err := s.LastBackupTime(ctx)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), last, 5*time.Second)
+
+	info, err := s.GetStorageInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, last.Format(time.RFC3339), info.LastBackupTime)
+}
+
+func TestAutoBackupDebouncesRapidWrites(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+
+	require.NoError(t, s.StartAutoBackup(ctx, AutoBackupOptions{Interval: time.Hour, MaxMutations: 1, Debounce: 200 * time.Millisecond}))
+	t.Cleanup(func() { _ = s.StopAutoBackup(ctx) })
+
+	createTestClients(t, s, "CLIENT", 5)
+	require.Eventually(t, func() bool { return backupCount(t, s) == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, 1, backupCount(t, s), "a burst of writes should produce a single backup")
+}
+
+func TestAutoBackupAfterInterval(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+
+	require.NoError(t, s.StartAutoBackup(ctx, AutoBackupOptions{Interval: 50 * time.Millisecond, MaxMutations: 1000, Debounce: 10 * time.Millisecond}))
+	t.Cleanup(func() { _ = s.StopAutoBackup(ctx) })
+
+	time.Sleep(120 * time.Millisecond)
+	assert.Equal(t, 0, backupCount(t, s), "an unchanged store is not backed up")
+
+	createTestClients(t, s, "CLIENT", 1)
+	require.Eventually(t, func() bool { return backupCount(t, s) == 1 }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestStopAutoBackupFlushesDueChanges(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+	options := AutoBackupOptions{Interval: time.Hour, MaxMutations: 1000, Debounce: time.Hour}
+
+	// No backup exists yet, so pending changes are backed up on stop
+	require.NoError(t, s.StartAutoBackup(ctx, options))
+	createTestClients(t, s, "CLIENT-A", 1)
+	require.NoError(t, s.StopAutoBackup(ctx))
+	assert.Equal(t, 1, backupCount(t, s))
+
+	// The last backup is within the interval, so nothing is due
+	require.NoError(t, s.StartAutoBackup(ctx, options))
+	createTestClients(t, s, "CLIENT-B", 1)
+	require.NoError(t, s.StopAutoBackup(ctx))
+	assert.Equal(t, 1, backupCount(t, s))
+}
+
+func TestAutoBackupStartStop(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+
+	require.ErrorIs(t, s.StopAutoBackup(ctx), ErrAutoBackupNotRunning)
+	require.NoError(t, s.StartAutoBackup(ctx, AutoBackupOptions{}))
+	require.ErrorIs(t, s.StartAutoBackup(ctx, AutoBackupOptions{}), ErrAutoBackupRunning)
+	require.NoError(t, s.StopAutoBackup(ctx))
+
+	last, err := s.LastBackupTime(ctx)
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	info, err := s.GetStorageInfo(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, info.LastBackupTime)
+}
+
+func TestAutoBackupConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+
+	require.NoError(t, s.StartAutoBackup(ctx, AutoBackupOptions{Interval: time.Hour, MaxMutations: 5, Debounce: time.Millisecond}))
+
+	var wg sync.WaitGroup
+	for worker := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 10 {
+				assert.NoError(t, s.CreateClient(ctx, &models.Client{
+					ID:        models.ClientID(fmt.Sprintf("CLIENT-%d-%d", worker, i)),
+					Name:      testClientName,
+					Email:     testClientEmail,
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				}))
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, s.StopAutoBackup(ctx))
+
+	backups, err := s.ListBackups(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, backups)
+	for _, backup := range backups {
+		require.NoError(t, s.ValidateBackup(ctx, backup.Path))
+	}
+}
@@ -0,0 +1,714 @@
+package json
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// Backup errors
+var (
+	ErrBackupEncryptionUnsupported = fmt.Errorf("encrypted backups are not supported")
+	ErrBackupNothingSelected       = fmt.Errorf("backup must include invoices or clients")
+	ErrBackupPathEmpty             = fmt.Errorf("backup path cannot be empty")
+	ErrInvalidBackup               = fmt.Errorf("invalid backup archive")
+	ErrStoreNotEmpty               = fmt.Errorf("storage already contains data")
+)
+
+const (
+	// backupFilePrefix and backupFileExt make up backup file names: go-invoice-backup-<timestamp>.tar.gz
+	backupFilePrefix = "go-invoice-backup-"
+	backupFileExt    = ".tar.gz"
+
+	// backupManifestName is the archive entry describing the backup; it is written first
+	backupManifestName = "manifest.json"
+
+	// backupMetadataName is the storage metadata file, restored only into a store without one
+	backupMetadataName = "metadata.json"
+
+	// maxBackupEntrySize caps the size of a single archive entry
+	maxBackupEntrySize = 64 << 20
+)
+
+// backupManifest describes the contents of a backup archive
+type backupManifest struct {
+	Version      string   `json:"version"`
+	CreatedAt    string   `json:"created_at"`
+	Directories  []string `json:"directories"`
+	InvoiceCount int64    `json:"invoice_count"`
+	ClientCount  int64    `json:"client_count"`
+}
+
+// SetBackupDir sets the directory where backups are created and listed (defaults to <base>/backups)
+func (s *JSONStorage) SetBackupDir(dir string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.backupDir = dir
+}
+
+// CreateBackup snapshots the invoices/, clients/ and index/ directories into a timestamped
+// tar.gz archive in options.DestinationPath (or the backup directory) and returns its path.
+// The index is only included together with the clients it indexes.
+func (s *JSONStorage) CreateBackup(ctx context.Context, options storage.BackupOptions) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	default:
+	}
+
+	if options.Encryption {
+		return "", ErrBackupEncryptionUnsupported
+	}
+
+	var dirs []string
+	if options.IncludeInvoices {
+		dirs = append(dirs, "invoices")
+	}
+	if options.IncludeClients {
+		dirs = append(dirs, "clients", "index")
+	}
+	if len(dirs) == 0 {
+		return "", ErrBackupNothingSelected
+	}
+
+	unlock, err := s.rlock(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	destDir := options.DestinationPath
+	if destDir == "" {
+		destDir = s.backupDir
+	}
+	if err := os.MkdirAll(destDir, 0o750); err != nil {
+		return "", storage.NewStorageUnavailableError(
+			fmt.Sprintf("failed to create backup directory %s", destDir), err)
+	}
+
+	now := time.Now()
+	file, backupPath, err := createBackupFile(destDir, now)
+	if err != nil {
+		return "", err
+	}
+
+	manifest := &backupManifest{
+		Version:     "1.0",
+		CreatedAt:   now.Format(time.RFC3339Nano),
+		Directories: dirs,
+	}
+	if err = s.writeBackupArchive(ctx, file, manifest, options.CompressionLevel); err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(backupPath)
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	s.lastBackup.Store(now.UnixNano())
+	s.logger.Info("backup created", "path", backupPath,
+		"invoices", manifest.InvoiceCount, "clients", manifest.ClientCount)
+	return backupPath, nil
+}
+
+// RestoreBackup replaces the directories contained in a backup with the archived copies.
+// The archive is validated first, a store that already holds invoices or clients is only
+// overwritten when options.OverwriteExisting is set, and with options.ValidateData the store
+// is re-validated afterwards, putting the previous data back if validation fails.
+func (s *JSONStorage) RestoreBackup(ctx context.Context, options storage.RestoreOptions) (*storage.RestoreResult, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if options.SourcePath == "" {
+		return nil, ErrBackupPathEmpty
+	}
+
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	manifest, err := readBackupArchive(ctx, options.SourcePath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &storage.RestoreResult{
+		InvoicesRestored: manifest.InvoiceCount,
+		ClientsRestored:  manifest.ClientCount,
+		DryRun:           options.DryRun,
+	}
+
+	empty, err := s.isEmptyUnsafe()
+	if err != nil {
+		return nil, err
+	}
+	if !empty {
+		if !options.OverwriteExisting {
+			return nil, fmt.Errorf("%w: %s", ErrStoreNotEmpty, s.basePath)
+		}
+		if options.DryRun {
+			result.Warnings = append(result.Warnings, "existing invoices and clients would be replaced")
+		} else {
+			result.Warnings = append(result.Warnings, "existing invoices and clients were replaced")
+		}
+	}
+
+	if options.DryRun {
+		return result, nil
+	}
+
+	if err = os.MkdirAll(s.basePath, 0o750); err != nil {
+		return nil, storage.NewStorageUnavailableError(
+			fmt.Sprintf("failed to create directory %s", s.basePath), err)
+	}
+	staging, err := os.MkdirTemp(s.basePath, ".restore-")
+	if err != nil {
+		return nil, storage.NewStorageUnavailableError("failed to create restore directory", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	// Extract into a staging directory, then swap the directories into place
+	if _, err = readBackupArchive(ctx, options.SourcePath, func(name string, data []byte) error {
+		return writeStagedFile(staging, name, data)
+	}); err != nil {
+		return nil, err
+	}
+
+	previous := filepath.Join(staging, ".previous")
+	if err = os.Mkdir(previous, 0o750); err != nil {
+		return nil, storage.NewStorageUnavailableError("failed to create restore directory", err)
+	}
+
+	swapped, err := s.swapInRestoredDirs(staging, previous, manifest.Directories)
+	if err != nil {
+		s.rollbackRestore(previous, swapped)
+		return nil, err
+	}
+
+	if options.ValidateData {
+		if err = s.Validate(ctx); err != nil {
+			s.rollbackRestore(previous, swapped)
+			return nil, fmt.Errorf("restored data failed validation, previous data kept: %w", err)
+		}
+	}
+
+	// Only bring in the storage metadata when the store does not have its own
+	metadataPath := filepath.Join(s.basePath, backupMetadataName)
+	if _, statErr := os.Stat(metadataPath); os.IsNotExist(statErr) {
+		if renameErr := os.Rename(filepath.Join(staging, backupMetadataName), metadataPath); renameErr != nil && !os.IsNotExist(renameErr) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to restore storage metadata: %v", renameErr))
+		}
+	}
+
+	// A partial backup restores files without the matching indexes
+	if _, _, indexErr := s.rebuildInvoiceIndexUnsafe(ctx); indexErr != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to rebuild invoice index: %v", indexErr))
+	}
+	if _, indexErr := s.rebuildClientIndexUnsafe(ctx); indexErr != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to rebuild client index: %v", indexErr))
+	}
+
+	s.initialized = false
+	s.logger.Info("backup restored", "path", options.SourcePath,
+		"invoices", result.InvoicesRestored, "clients", result.ClientsRestored)
+	return result, nil
+}
+
+// ListBackups returns the backups in the backup directory, newest first
+func (s *JSONStorage) ListBackups(ctx context.Context) ([]*storage.BackupInfo, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	backupDir := s.backupDir
+	s.mu.RUnlock()
+
+	paths, err := filepath.Glob(filepath.Join(backupDir, backupFilePrefix+"*"+backupFileExt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	type listedBackup struct {
+		info      *storage.BackupInfo
+		createdAt time.Time
+	}
+
+	listed := make([]listedBackup, 0, len(paths))
+	for _, backupPath := range paths {
+		fileInfo, err := os.Stat(backupPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat backup %s: %w", backupPath, err)
+		}
+
+		info := &storage.BackupInfo{
+			Path:       backupPath,
+			SizeBytes:  fileInfo.Size(),
+			Compressed: true,
+		}
+		createdAt := fileInfo.ModTime()
+
+		manifest, err := readBackupManifest(backupPath)
+		if err != nil {
+			s.logger.Debug("skipping unreadable backup manifest", "path", backupPath, "error", err)
+		} else {
+			info.InvoiceCount = manifest.InvoiceCount
+			info.ClientCount = manifest.ClientCount
+			if parsed, parseErr := time.Parse(time.RFC3339Nano, manifest.CreatedAt); parseErr == nil {
+				createdAt = parsed
+			}
+		}
+		info.CreatedAt = createdAt.Format(time.RFC3339)
+
+		listed = append(listed, listedBackup{info: info, createdAt: createdAt})
+	}
+
+	sort.Slice(listed, func(i, j int) bool {
+		if !listed[i].createdAt.Equal(listed[j].createdAt) {
+			return listed[i].createdAt.After(listed[j].createdAt)
+		}
+		return listed[i].info.Path > listed[j].info.Path
+	})
+
+	backups := make([]*storage.BackupInfo, len(listed))
+	for i, l := range listed {
+		backups[i] = l.info
+	}
+	return backups, nil
+}
+
+// DeleteBackup removes a backup file
+func (s *JSONStorage) DeleteBackup(ctx context.Context, backupPath string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if backupPath == "" {
+		return ErrBackupPathEmpty
+	}
+
+	if err := os.Remove(backupPath); err != nil {
+		if os.IsNotExist(err) {
+			return storage.NewNotFoundError("backup", backupPath)
+		}
+		return fmt.Errorf("failed to delete backup %s: %w", backupPath, err)
+	}
+
+	s.logger.Info("backup deleted", "path", backupPath)
+	return nil
+}
+
+// PruneBackups deletes all but the newest keep backups and returns the paths it removed
+func (s *JSONStorage) PruneBackups(ctx context.Context, keep int) ([]string, error) {
+	if keep < 0 {
+		keep = 0
+	}
+
+	backups, err := s.ListBackups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(backups) <= keep {
+		return nil, nil
+	}
+
+	removed := make([]string, 0, len(backups)-keep)
+	for _, backup := range backups[keep:] {
+		if err := s.DeleteBackup(ctx, backup.Path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, backup.Path)
+	}
+
+	return removed, nil
+}
+
+// ValidateBackup checks that a backup archive is readable, only contains storage files,
+// that every file is well-formed JSON and that it matches its manifest
+func (s *JSONStorage) ValidateBackup(ctx context.Context, backupPath string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if backupPath == "" {
+		return ErrBackupPathEmpty
+	}
+
+	_, err := readBackupArchive(ctx, backupPath, nil)
+	return err
+}
+
+// createBackupFile creates a new backup file named after the timestamp, adding a
+// sequence number when a backup with that name already exists
+func createBackupFile(dir string, now time.Time) (*os.File, string, error) {
+	stamp := now.Format("20060102-150405")
+	for seq := 1; ; seq++ {
+		name := backupFilePrefix + stamp + backupFileExt
+		if seq > 1 {
+			name = fmt.Sprintf("%s%s-%d%s", backupFilePrefix, stamp, seq, backupFileExt)
+		}
+
+		backupPath := filepath.Join(dir, name)
+		file, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600) //nolint:gosec // Path is built from the configured backup directory
+		if err == nil {
+			return file, backupPath, nil
+		}
+		if !os.IsExist(err) {
+			return nil, "", fmt.Errorf("failed to create backup file: %w", err)
+		}
+	}
+}
+
+// writeBackupArchive writes the manifest, the storage metadata and the selected directories
+// as a gzip-compressed tar stream. Callers must hold s.mu.
+func (s *JSONStorage) writeBackupArchive(ctx context.Context, w io.Writer, manifest *backupManifest, level int) error {
+	files := make(map[string][]string, len(manifest.Directories))
+	for _, dir := range manifest.Directories {
+		paths, err := filepath.Glob(filepath.Join(s.basePath, dir, "*.json"))
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+		sort.Strings(paths)
+		files[dir] = paths
+
+		switch dir {
+		case "invoices":
+			manifest.InvoiceCount = int64(len(paths))
+		case "clients":
+			manifest.ClientCount = int64(len(paths))
+		}
+	}
+
+	gz, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return fmt.Errorf("invalid compression level %d: %w", level, err)
+	}
+	tw := tar.NewWriter(gz)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal backup manifest: %w", err)
+	}
+	if err = writeTarEntry(tw, backupManifestName, manifestData); err != nil {
+		return err
+	}
+
+	if data, readErr := os.ReadFile(filepath.Join(s.basePath, backupMetadataName)); readErr == nil { //nolint:gosec // Path is within the storage directory
+		if err = writeTarEntry(tw, backupMetadataName, data); err != nil {
+			return err
+		}
+	}
+
+	for _, dir := range manifest.Directories {
+		if err = tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     dir + "/",
+			Mode:     0o750,
+			ModTime:  time.Now(),
+		}); err != nil {
+			return fmt.Errorf("failed to write backup entry %s: %w", dir, err)
+		}
+
+		for _, filePath := range files[dir] {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			data, readErr := os.ReadFile(filePath) //nolint:gosec // Path comes from globbing the storage directory
+			if readErr != nil {
+				return fmt.Errorf("failed to read %s: %w", filePath, readErr)
+			}
+			if err = writeTarEntry(tw, path.Join(dir, filepath.Base(filePath)), data); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish backup archive: %w", err)
+	}
+	if err = gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish backup compression: %w", err)
+	}
+	return nil
+}
+
+// writeTarEntry writes a single regular file to the archive
+func writeTarEntry(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o600,
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to write backup entry %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write backup entry %s: %w", name, err)
+	}
+	return nil
+}
+
+// readBackupArchive validates every entry of a backup archive, passing each storage file
+// to fn when it is not nil, and returns the archive's manifest
+func readBackupArchive(ctx context.Context, backupPath string, fn func(name string, data []byte) error) (*backupManifest, error) {
+	file, err := os.Open(backupPath) //nolint:gosec // Backup path is provided by the user
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, storage.NewNotFoundError("backup", backupPath)
+		}
+		return nil, fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	var manifest *backupManifest
+	var invoices, clients int64
+	dirs := make(map[string]bool)
+	tr := tar.NewReader(gz)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+		}
+
+		name, err := backupEntryName(header)
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeDir {
+			dirs[name] = true
+			continue
+		}
+
+		data, err := readTarEntry(tr, name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case name == backupManifestName:
+			manifest = &backupManifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, fmt.Errorf("%w: malformed manifest: %w", ErrInvalidBackup, err)
+			}
+			continue
+		case strings.HasPrefix(name, "invoices/"):
+			invoices++
+		case strings.HasPrefix(name, "clients/"):
+			clients++
+		}
+
+		if fn != nil {
+			if err := fn(name, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidBackup, backupManifestName)
+	}
+	for _, dir := range manifest.Directories {
+		if !isBackupDirectory(dir) {
+			return nil, fmt.Errorf("%w: unexpected directory %q in manifest", ErrInvalidBackup, dir)
+		}
+		if !dirs[dir] {
+			return nil, fmt.Errorf("%w: missing directory %s/", ErrInvalidBackup, dir)
+		}
+	}
+	if invoices != manifest.InvoiceCount || clients != manifest.ClientCount {
+		return nil, fmt.Errorf("%w: contains %d invoices and %d clients, manifest lists %d and %d",
+			ErrInvalidBackup, invoices, clients, manifest.InvoiceCount, manifest.ClientCount)
+	}
+
+	return manifest, nil
+}
+
+// readBackupManifest reads only the manifest of a backup archive
+func readBackupManifest(backupPath string) (*backupManifest, error) {
+	file, err := os.Open(backupPath) //nolint:gosec // Path comes from globbing the backup directory
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = gz.Close() }()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if header.Name != backupManifestName {
+			continue
+		}
+
+		data, err := readTarEntry(tr, header.Name)
+		if err != nil {
+			return nil, err
+		}
+		var manifest backupManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, err
+		}
+		return &manifest, nil
+	}
+}
+
+// backupEntryName checks that an archive entry is a directory or JSON file the storage
+// knows about and returns its cleaned name, rejecting absolute paths, ".." and links
+func backupEntryName(header *tar.Header) (string, error) {
+	name := strings.TrimSuffix(header.Name, "/")
+	if name == "" || path.IsAbs(name) || strings.Contains(name, `\`) || path.Clean(name) != name {
+		return "", fmt.Errorf("%w: unsafe entry %q", ErrInvalidBackup, header.Name)
+	}
+
+	parts := strings.Split(name, "/")
+	switch header.Typeflag {
+	case tar.TypeDir:
+		if len(parts) == 1 && isBackupDirectory(name) {
+			return name, nil
+		}
+	case tar.TypeReg:
+		switch {
+		case len(parts) == 1 && (name == backupManifestName || name == backupMetadataName):
+			return name, nil
+		case len(parts) == 2 && isBackupDirectory(parts[0]) &&
+			strings.HasSuffix(parts[1], ".json") && !strings.HasPrefix(parts[1], "."):
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: unexpected entry %q", ErrInvalidBackup, header.Name)
+}
+
+// isBackupDirectory reports whether dir is one of the storage directories kept in backups
+func isBackupDirectory(dir string) bool {
+	return dir == "invoices" || dir == "clients" || dir == "index"
+}
+
+// readTarEntry reads the current archive entry, which must be well-formed JSON
+func readTarEntry(tr *tar.Reader, name string) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(tr, maxBackupEntrySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read %s: %w", ErrInvalidBackup, name, err)
+	}
+	if len(data) > maxBackupEntrySize {
+		return nil, fmt.Errorf("%w: %s is too large", ErrInvalidBackup, name)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("%w: %s is not valid JSON", ErrInvalidBackup, name)
+	}
+	return data, nil
+}
+
+// writeStagedFile writes an extracted archive entry below the staging directory
+func writeStagedFile(staging, name string, data []byte) error {
+	target := filepath.Join(staging, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+	}
+	if err := os.WriteFile(target, data, 0o600); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	return nil
+}
+
+// isEmptyUnsafe reports whether the store holds no invoice or client files. Callers must hold s.mu.
+func (s *JSONStorage) isEmptyUnsafe() (bool, error) {
+	for _, dir := range []string{s.invoicesDir, s.clientsDir} {
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return false, fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+		if len(files) > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// swapInRestoredDirs moves the current copies of dirs into previous and the staged copies
+// into the store, returning the directories that were replaced. Callers must hold s.mu.
+func (s *JSONStorage) swapInRestoredDirs(staging, previous string, dirs []string) ([]string, error) {
+	swapped := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		current := filepath.Join(s.basePath, dir)
+		restored := filepath.Join(staging, dir)
+
+		if err := os.MkdirAll(restored, 0o750); err != nil {
+			return swapped, fmt.Errorf("failed to prepare %s: %w", dir, err)
+		}
+		if err := os.Rename(current, filepath.Join(previous, dir)); err != nil && !os.IsNotExist(err) {
+			return swapped, fmt.Errorf("failed to move existing %s aside: %w", dir, err)
+		}
+		swapped = append(swapped, dir)
+		if err := os.Rename(restored, current); err != nil {
+			return swapped, fmt.Errorf("failed to restore %s: %w", dir, err)
+		}
+	}
+	return swapped, nil
+}
+
+// rollbackRestore puts back the directories moved aside by swapInRestoredDirs. Callers must hold s.mu.
+func (s *JSONStorage) rollbackRestore(previous string, swapped []string) {
+	for _, dir := range swapped {
+		current := filepath.Join(s.basePath, dir)
+		if err := os.RemoveAll(current); err != nil {
+			s.logger.Error("failed to remove restored directory", "dir", current, "error", err)
+			continue
+		}
+		if err := os.Rename(filepath.Join(previous, dir), current); err != nil && !os.IsNotExist(err) {
+			s.logger.Error("failed to put back previous directory", "dir", current, "error", err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package json
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	storageTypes "github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// newBackupTestStorage returns an initialized storage in a temporary directory holding one client
+func newBackupTestStorage(t *testing.T) *JSONStorage {
+	t.Helper()
+	ctx := context.Background()
+
+	s := NewJSONStorage(t.TempDir(), &MockLogger{})
+	require.NoError(t, s.Initialize(ctx))
+	require.NoError(t, s.CreateClient(ctx, &models.Client{
+		ID:        testClientID001,
+		Name:      testClientName,
+		Email:     testClientEmail,
+		Active:    true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+	return s
+}
+
+// fullBackup returns backup options including all data
+func fullBackup() storageTypes.BackupOptions {
+	return storageTypes.BackupOptions{IncludeInvoices: true, IncludeClients: true, CompressionLevel: gzip.DefaultCompression}
+}
+
+// writeTestArchive writes a tar.gz archive with the given entries; names ending in "/" are directories
+func writeTestArchive(t *testing.T, entries map[string]string) string {
+	t.Helper()
+
+	archivePath := filepath.Join(t.TempDir(), "archive.tar.gz")
+	file, err := os.Create(archivePath) //nolint:gosec // Test file path is controlled
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for name, content := range entries {
+		if strings.HasSuffix(name, "/") {
+			require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: name, Mode: 0o750}))
+			continue
+		}
+		require.NoError(t, writeTarEntry(tw, name, []byte(content)))
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return archivePath
+}
+
+func TestBackupAndRestoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := newBackupTestStorage(t)
+
+	backupPath, err := source.CreateBackup(ctx, fullBackup())
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(source.basePath, "backups"), filepath.Dir(backupPath))
+	require.NoError(t, source.ValidateBackup(ctx, backupPath))
+
+	target := NewJSONStorage(filepath.Join(t.TempDir(), "restored"), &MockLogger{})
+	result, err := target.RestoreBackup(ctx, storageTypes.RestoreOptions{SourcePath: backupPath, ValidateData: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.ClientsRestored)
+	assert.Equal(t, int64(0), result.InvoicesRestored)
+
+	client, err := target.GetClient(ctx, testClientID001)
+	require.NoError(t, err)
+	assert.Equal(t, testClientName, client.Name)
+
+	initialized, err := target.IsInitialized(ctx)
+	require.NoError(t, err)
+	assert.True(t, initialized)
+}
+
+func TestRestoreBackupRefusesNonEmptyStore(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+
+	backupPath, err := s.CreateBackup(ctx, fullBackup())
+	require.NoError(t, err)
+
+	_, err = s.RestoreBackup(ctx, storageTypes.RestoreOptions{SourcePath: backupPath})
+	require.ErrorIs(t, err, ErrStoreNotEmpty)
+
+	result, err := s.RestoreBackup(ctx, storageTypes.RestoreOptions{SourcePath: backupPath, OverwriteExisting: true, ValidateData: true})
+	require.NoError(t, err)
+	assert.Len(t, result.Warnings, 1)
+}
+
+func TestRestoreBackupRollsBackInvalidData(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+
+	// A client file that is valid JSON but fails client validation
+	archive := writeTestArchive(t, map[string]string{
+		backupManifestName:        `{"version":"1.0","directories":["clients"],"client_count":1}`,
+		"clients/":                "",
+		"clients/CLIENT-BAD.json": `{"id":"CLIENT-BAD"}`,
+	})
+	_, err := s.RestoreBackup(ctx, storageTypes.RestoreOptions{SourcePath: archive, OverwriteExisting: true, ValidateData: true})
+	require.Error(t, err)
+
+	client, err := s.GetClient(ctx, testClientID001)
+	require.NoError(t, err, "previous data should be put back")
+	assert.Equal(t, testClientName, client.Name)
+	assert.NoFileExists(t, filepath.Join(s.clientsDir, "CLIENT-BAD.json"))
+}
+
+func TestValidateBackupRejectsUnsafeArchives(t *testing.T) {
+	ctx := context.Background()
+	s := NewJSONStorage(t.TempDir(), &MockLogger{})
+	manifest := `{"version":"1.0","directories":[]}`
+
+	tests := map[string]map[string]string{
+		"PathTraversal":   {backupManifestName: manifest, "../evil.json": `{}`},
+		"NestedTraversal": {backupManifestName: manifest, "clients/../../evil.json": `{}`},
+		"AbsolutePath":    {backupManifestName: manifest, "/etc/evil.json": `{}`},
+		"UnknownFile":     {backupManifestName: manifest, "templates/x.json": `{}`},
+		"NotJSON":         {backupManifestName: manifest, "clients/a.json": `{`},
+		"MissingManifest": {"clients/a.json": `{}`},
+		"CountMismatch":   {backupManifestName: manifest, "clients/a.json": `{}`},
+		"MissingDir":      {backupManifestName: `{"version":"1.0","directories":["clients"]}`},
+	}
+
+	for name, entries := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, s.ValidateBackup(ctx, writeTestArchive(t, entries)), ErrInvalidBackup)
+		})
+	}
+
+	t.Run("NotGzip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "plain.tar.gz")
+		require.NoError(t, os.WriteFile(path, []byte("not an archive"), 0o600))
+		require.ErrorIs(t, s.ValidateBackup(ctx, path), ErrInvalidBackup)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		var notFound storageTypes.NotFoundError
+		require.ErrorAs(t, s.ValidateBackup(ctx, filepath.Join(t.TempDir(), "missing.tar.gz")), &notFound)
+	})
+}
+
+func TestPruneBackups(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+
+	var created []string
+	for range 4 {
+		backupPath, err := s.CreateBackup(ctx, fullBackup())
+		require.NoError(t, err)
+		created = append(created, backupPath)
+	}
+
+	backups, err := s.ListBackups(ctx)
+	require.NoError(t, err)
+	require.Len(t, backups, 4)
+	assert.Equal(t, created[3], backups[0].Path, "newest backup should be listed first")
+	assert.Equal(t, int64(1), backups[0].ClientCount)
+
+	removed, err := s.PruneBackups(ctx, 2)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, created[:2], removed)
+
+	backups, err = s.ListBackups(ctx)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, created[3], backups[0].Path)
+	assert.Equal(t, created[2], backups[1].Path)
+}
+
+func TestCreateBackupOptions(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+
+	_, err := s.CreateBackup(ctx, storageTypes.BackupOptions{})
+	require.ErrorIs(t, err, ErrBackupNothingSelected)
+
+	options := fullBackup()
+	options.Encryption = true
+	_, err = s.CreateBackup(ctx, options)
+	require.ErrorIs(t, err, ErrBackupEncryptionUnsupported)
+
+	options = fullBackup()
+	options.DestinationPath = filepath.Join(t.TempDir(), "elsewhere")
+	backupPath, err := s.CreateBackup(ctx, options)
+	require.NoError(t, err)
+	assert.Equal(t, options.DestinationPath, filepath.Dir(backupPath))
+}
@@ -0,0 +1,155 @@
+package json
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// clientIndex maps client IDs to their searchable fields as stored in index/clients.json
+type clientIndex map[models.ClientID]storage.ClientIndexEntry
+
+// ListClientIndex returns the indexed search fields for every client, sorted by name.
+// The index is rebuilt from the client files when it is missing or when the number of
+// entries no longer matches the number of client files (e.g. data written by an older version).
+func (s *JSONStorage) ListClientIndex(ctx context.Context) ([]storage.ClientIndexEntry, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	unlock, err := s.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index, fresh, err := s.loadClientIndexUnsafe(ctx)
+	unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if !fresh {
+		if unlock, err = s.lock(ctx); err != nil {
+			return nil, err
+		}
+		index, err = s.rebuildClientIndexUnsafe(ctx)
+		unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entries := make([]storage.ClientIndexEntry, 0, len(index))
+	for _, entry := range index {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries, nil
+}
+
+// clientIndexPath returns the path of the client index file
+func (s *JSONStorage) clientIndexPath() string {
+	return filepath.Join(s.indexDir, "clients.json")
+}
+
+// loadClientIndexUnsafe reads the client index and reports whether it is in sync with the
+// client files. Callers must hold s.mu.
+func (s *JSONStorage) loadClientIndexUnsafe(ctx context.Context) (clientIndex, bool, error) {
+	clientFiles, err := filepath.Glob(filepath.Join(s.clientsDir, "*.json"))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list client files: %w", err)
+	}
+
+	index := make(clientIndex)
+	if err := s.readJSONFile(ctx, s.clientIndexPath(), &index); err != nil {
+		if os.IsNotExist(err) {
+			return index, len(clientFiles) == 0, nil
+		}
+		s.logger.Error("failed to read client index, rebuilding", "error", err)
+		return index, false, nil
+	}
+
+	return index, len(index) == len(clientFiles), nil
+}
+
+// buildClientIndexUnsafe reads every client file into a new index. Callers must hold s.mu.
+func (s *JSONStorage) buildClientIndexUnsafe(ctx context.Context) (clientIndex, error) {
+	clientFiles, err := filepath.Glob(filepath.Join(s.clientsDir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list client files: %w", err)
+	}
+
+	index := make(clientIndex, len(clientFiles))
+	for _, filePath := range clientFiles {
+		var client models.Client
+		if err := s.readJSONFile(ctx, filePath, &client); err != nil {
+			s.logger.Error("failed to read client file for index", "file", filePath, "error", err)
+			continue
+		}
+		index[client.ID] = storage.NewClientIndexEntry(&client)
+	}
+
+	return index, nil
+}
+
+// rebuildClientIndexUnsafe regenerates the client index from the client files.
+// Callers must hold the write lock.
+func (s *JSONStorage) rebuildClientIndexUnsafe(ctx context.Context) (clientIndex, error) {
+	index, err := s.buildClientIndexUnsafe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The rebuilt index is still usable for this search if it cannot be saved
+	if err := s.writeClientIndexUnsafe(ctx, index); err != nil {
+		s.logger.Error("failed to save rebuilt client index", "error", err)
+		return index, nil
+	}
+
+	s.logger.Debug("client index rebuilt", "clients", len(index))
+	return index, nil
+}
+
+// writeClientIndexUnsafe saves the client index, creating the index directory if needed.
+// Callers must hold the write lock.
+func (s *JSONStorage) writeClientIndexUnsafe(ctx context.Context, index clientIndex) error {
+	if err := os.MkdirAll(s.indexDir, 0o750); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+	if err := s.writeJSONFile(ctx, s.clientIndexPath(), index); err != nil {
+		return fmt.Errorf("failed to write client index: %w", err)
+	}
+	return nil
+}
+
+// updateClientIndex records or removes a client in the index. Callers must hold the write lock.
+// Index errors are logged rather than returned; a stale index is rebuilt on the next search.
+func (s *JSONStorage) updateClientIndex(ctx context.Context, client *models.Client, operation string) {
+	index := make(clientIndex)
+	if err := s.readJSONFile(ctx, s.clientIndexPath(), &index); err != nil && !os.IsNotExist(err) {
+		s.logger.Error("failed to read client index", "error", err, "client_id", client.ID)
+		return
+	}
+
+	if operation == "delete" {
+		delete(index, client.ID)
+	} else {
+		index[client.ID] = storage.NewClientIndexEntry(client)
+	}
+
+	if err := s.writeClientIndexUnsafe(ctx, index); err != nil {
+		s.logger.Error("failed to update client index", "error", err, "client_id", client.ID)
+		return
+	}
+
+	s.logger.Debug("updating client index", "client_id", client.ID, "operation", operation)
+}
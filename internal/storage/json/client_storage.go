@@ -40,8 +40,11 @@ func (s *JSONStorage) CreateClient(ctx context.Context, client *models.Client) e
 		return fmt.Errorf("invalid client: %w", err)
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Check if client already exists
 	clientPath := s.getClientPath(client.ID)
@@ -53,7 +56,9 @@ func (s *JSONStorage) CreateClient(ctx context.Context, client *models.Client) e
 	if err := s.writeJSONFile(ctx, clientPath, client); err != nil {
 		return fmt.Errorf("failed to write client file: %w", err)
 	}
+	s.updateClientIndex(ctx, client, "create")
 
+	s.recordMutation()
 	s.logger.Info("client created", "id", client.ID, "name", client.Name)
 	return nil
 }
@@ -70,8 +75,11 @@ func (s *JSONStorage) GetClient(ctx context.Context, id models.ClientID) (*model
 		return nil, ErrClientIDCannotBeEmpty
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	unlock, err := s.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
 	clientPath := s.getClientPath(id)
 	var client models.Client
@@ -103,8 +111,11 @@ func (s *JSONStorage) UpdateClient(ctx context.Context, client *models.Client) e
 		return fmt.Errorf("invalid client: %w", err)
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Check if client exists
 	clientPath := s.getClientPath(client.ID)
@@ -119,7 +130,9 @@ func (s *JSONStorage) UpdateClient(ctx context.Context, client *models.Client) e
 	if err := s.writeJSONFile(ctx, clientPath, client); err != nil {
 		return fmt.Errorf("failed to write updated client: %w", err)
 	}
+	s.updateClientIndex(ctx, client, "update")
 
+	s.recordMutation()
 	s.logger.Info("client updated", "id", client.ID, "name", client.Name)
 	return nil
 }
@@ -136,8 +149,11 @@ func (s *JSONStorage) DeleteClient(ctx context.Context, id models.ClientID) erro
 		return ErrClientIDCannotBeEmpty
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Read existing client
 	client, err := s.getClientUnsafe(ctx, id)
@@ -157,7 +173,9 @@ func (s *JSONStorage) DeleteClient(ctx context.Context, id models.ClientID) erro
 	if err := s.writeJSONFile(ctx, clientPath, client); err != nil {
 		return fmt.Errorf("failed to update client for deletion: %w", err)
 	}
+	s.updateClientIndex(ctx, client, "update")
 
+	s.recordMutation()
 	s.logger.Info("client deleted (soft)", "id", id)
 	return nil
 }
@@ -170,8 +188,11 @@ func (s *JSONStorage) ListClients(ctx context.Context, activeOnly bool, limit, o
 	default:
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	unlock, err := s.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
 	// Read all client files
 	clientFiles, err := filepath.Glob(filepath.Join(s.clientsDir, "*.json"))
@@ -239,8 +260,11 @@ func (s *JSONStorage) FindClientByEmail(ctx context.Context, email string) (*mod
 		return nil, ErrEmailCannotBeEmpty
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	unlock, err := s.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
 	// Read all client files and search for matching email
 	clientFiles, err := filepath.Glob(filepath.Join(s.clientsDir, "*.json"))
@@ -273,11 +297,14 @@ func (s *JSONStorage) ExistsClient(ctx context.Context, id models.ClientID) (boo
 	default:
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	unlock, err := s.rlock(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
 
 	clientPath := s.getClientPath(id)
-	_, err := os.Stat(clientPath)
+	_, err = os.Stat(clientPath)
 	if err == nil {
 		return true, nil
 	}
@@ -314,8 +341,11 @@ func (s *JSONStorage) HardDeleteClient(ctx context.Context, id models.ClientID)
 		return ErrClientIDCannotBeEmpty
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	clientPath := s.getClientPath(id)
 
@@ -328,7 +358,9 @@ func (s *JSONStorage) HardDeleteClient(ctx context.Context, id models.ClientID)
 	if err := os.Remove(clientPath); err != nil {
 		return fmt.Errorf("failed to delete client file: %w", err)
 	}
+	s.updateClientIndex(ctx, &models.Client{ID: id}, "delete")
 
+	s.recordMutation()
 	s.logger.Info("client hard deleted", "id", id)
 	return nil
 }
@@ -341,8 +373,11 @@ func (s *JSONStorage) RestoreClient(ctx context.Context, id models.ClientID) err
 	default:
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Read existing client
 	client, err := s.getClientUnsafe(ctx, id)
@@ -359,7 +394,9 @@ func (s *JSONStorage) RestoreClient(ctx context.Context, id models.ClientID) err
 	if err := s.writeJSONFile(ctx, clientPath, client); err != nil {
 		return fmt.Errorf("failed to restore client: %w", err)
 	}
+	s.updateClientIndex(ctx, client, "update")
 
+	s.recordMutation()
 	s.logger.Info("client restored", "id", id)
 	return nil
 }
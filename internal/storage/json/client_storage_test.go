@@ -132,152 +132,6 @@ func (suite *ClientStorageTestSuite) TestCreateClient() {
 	assert.Equal(t, context.Canceled, err)
 }
 
-func (suite *ClientStorageTestSuite) TestGetClient() {
-	t := suite.T()
-
-	// Create test client
-	client := &models.Client{
-		ID:        testClientID001,
-		Name:      testClientName,
-		Email:     testClientEmail,
-		Phone:     "+1234567890",
-		Address:   "123 Test St",
-		Active:    true,
-		CreatedAt: time.Now().Truncate(time.Second),
-		UpdatedAt: time.Now().Truncate(time.Second),
-	}
-
-	// Save client
-	err := suite.storage.CreateClient(suite.ctx, client)
-	require.NoError(t, err)
-
-	// Test successful retrieval
-	retrieved, err := suite.storage.GetClient(suite.ctx, testClientID001)
-	require.NoError(t, err)
-	require.NotNil(t, retrieved)
-
-	assert.Equal(t, client.ID, retrieved.ID)
-	assert.Equal(t, client.Name, retrieved.Name)
-	assert.Equal(t, client.Email, retrieved.Email)
-	assert.Equal(t, client.Phone, retrieved.Phone)
-	assert.Equal(t, client.Address, retrieved.Address)
-	assert.True(t, retrieved.Active)
-	assert.WithinDuration(t, client.CreatedAt, retrieved.CreatedAt, time.Second)
-
-	// Test non-existent client
-	retrieved, err = suite.storage.GetClient(suite.ctx, "CLIENT-999")
-	require.Error(t, err)
-	assert.Nil(t, retrieved)
-	var notFoundErr storageTypes.NotFoundError
-	require.ErrorAs(t, err, &notFoundErr)
-	assert.Equal(t, "client", notFoundErr.Resource)
-	assert.Equal(t, "CLIENT-999", notFoundErr.ID)
-
-	// Test with empty ID
-	_, err = suite.storage.GetClient(suite.ctx, "")
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "client ID cannot be empty")
-
-	// Test with whitespace ID
-	_, err = suite.storage.GetClient(suite.ctx, "   ")
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "client ID cannot be empty")
-
-	// Test with context cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel()
-
-	retrieved, err = suite.storage.GetClient(ctx, testClientID001)
-	assert.Equal(t, context.Canceled, err)
-	assert.Nil(t, retrieved)
-}
-
-func (suite *ClientStorageTestSuite) TestUpdateClient() {
-	t := suite.T()
-
-	// Create test client
-	client := &models.Client{
-		ID:        testClientID001,
-		Name:      testClientName,
-		Email:     testClientEmail,
-		Active:    true,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Save client
-	err := suite.storage.CreateClient(suite.ctx, client)
-	require.NoError(t, err)
-
-	// Update client
-	client.Name = "Updated Client"
-	client.Email = "updated@example.com"
-	client.Phone = "+9876543210"
-
-	err = suite.storage.UpdateClient(suite.ctx, client)
-	require.NoError(t, err)
-
-	// Verify UpdatedAt was changed
-	assert.True(t, client.UpdatedAt.After(time.Now().Add(-time.Second)))
-
-	// Verify changes were saved
-	retrieved, err := suite.storage.GetClient(suite.ctx, testClientID001)
-	require.NoError(t, err)
-	assert.Equal(t, "Updated Client", retrieved.Name)
-	assert.Equal(t, "updated@example.com", retrieved.Email)
-	assert.Equal(t, "+9876543210", retrieved.Phone)
-	assert.WithinDuration(t, time.Now(), retrieved.UpdatedAt, time.Second)
-
-	// Test concurrent update (no optimistic locking in client storage)
-	// Multiple updates should succeed
-	concurrentClient := &models.Client{
-		ID:        testClientID001,
-		Name:      "Concurrent Update",
-		Email:     "concurrent@example.com",
-		Active:    true,
-		CreatedAt: client.CreatedAt,
-		UpdatedAt: time.Now(),
-	}
-
-	err = suite.storage.UpdateClient(suite.ctx, concurrentClient)
-	require.NoError(t, err)
-
-	// Verify last update wins
-	retrieved, err = suite.storage.GetClient(suite.ctx, testClientID001)
-	require.NoError(t, err)
-	assert.Equal(t, "Concurrent Update", retrieved.Name)
-	assert.Equal(t, "concurrent@example.com", retrieved.Email)
-
-	// Test updating non-existent client
-	nonExistent := &models.Client{
-		ID:        "CLIENT-999",
-		Name:      "Non-existent",
-		Email:     "nonexistent@example.com",
-		Active:    true,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	err = suite.storage.UpdateClient(suite.ctx, nonExistent)
-	require.Error(t, err)
-	var notFoundErr storageTypes.NotFoundError
-	require.ErrorAs(t, err, &notFoundErr)
-
-	// Test with nil client
-	err = suite.storage.UpdateClient(suite.ctx, nil)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "client cannot be nil")
-
-	// Test with invalid client
-	invalidClient := &models.Client{
-		ID: testClientID001,
-		// Missing required fields
-	}
-	err = suite.storage.UpdateClient(suite.ctx, invalidClient)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid client")
-}
-
 func (suite *ClientStorageTestSuite) TestDeleteClient() {
 	t := suite.T()
 
@@ -337,223 +191,11 @@ func (suite *ClientStorageTestSuite) TestDeleteClient() {
 	assert.Equal(t, context.Canceled, err)
 }
 
-func (suite *ClientStorageTestSuite) TestListClients() {
-	t := suite.T()
-
-	// Test empty list
-	result, err := suite.storage.ListClients(suite.ctx, true, 10, 0)
-	require.NoError(t, err)
-	require.NotNil(t, result)
-	assert.Empty(t, result.Clients)
-	assert.Equal(t, int64(0), result.TotalCount)
-	assert.False(t, result.HasMore)
-
-	// Create test clients
-	now := time.Now()
-	clients := []*models.Client{
-		{
-			ID:        testClientID001,
-			Name:      "Alpha Client",
-			Email:     "alpha@example.com",
-			Active:    true,
-			CreatedAt: now,
-			UpdatedAt: now,
-		},
-		{
-			ID:        "CLIENT-002",
-			Name:      "Beta Client",
-			Email:     "beta@example.com",
-			Active:    true,
-			CreatedAt: now,
-			UpdatedAt: now,
-		},
-		{
-			ID:        "CLIENT-003",
-			Name:      "Gamma Client",
-			Email:     "gamma@example.com",
-			Active:    false, // Inactive
-			CreatedAt: now,
-			UpdatedAt: now,
-		},
-		{
-			ID:        "CLIENT-004",
-			Name:      "Delta Client",
-			Email:     "delta@example.com",
-			Active:    true,
-			CreatedAt: now,
-			UpdatedAt: now,
-		},
-	}
-
-	// Save clients
-	for _, client := range clients {
-		err = suite.storage.CreateClient(suite.ctx, client)
-		require.NoError(t, err)
-	}
-
-	// Test list all active clients
-	result, err = suite.storage.ListClients(suite.ctx, true, 10, 0)
-	require.NoError(t, err)
-	assert.Len(t, result.Clients, 3) // Only active clients
-	assert.Equal(t, int64(3), result.TotalCount)
-	assert.False(t, result.HasMore)
-
-	// Verify sorting (by name ascending)
-	assert.Equal(t, "Alpha Client", result.Clients[0].Name)
-	assert.Equal(t, "Beta Client", result.Clients[1].Name)
-	assert.Equal(t, "Delta Client", result.Clients[2].Name)
-
-	// Test list all clients (including inactive)
-	result, err = suite.storage.ListClients(suite.ctx, false, 10, 0)
-	require.NoError(t, err)
-	assert.Len(t, result.Clients, 4) // All clients
-	assert.Equal(t, int64(4), result.TotalCount)
-
-	// Test pagination
-	result, err = suite.storage.ListClients(suite.ctx, true, 2, 0)
-	require.NoError(t, err)
-	assert.Len(t, result.Clients, 2)
-	assert.Equal(t, int64(3), result.TotalCount)
-	assert.True(t, result.HasMore)
-	assert.Equal(t, 2, result.NextOffset)
-
-	// Test offset
-	result, err = suite.storage.ListClients(suite.ctx, true, 2, 2)
-	require.NoError(t, err)
-	assert.Len(t, result.Clients, 1) // Only one remaining
-	assert.Equal(t, int64(3), result.TotalCount)
-	assert.False(t, result.HasMore)
-
-	// Test negative limit
-	result, err = suite.storage.ListClients(suite.ctx, true, -1, 0)
-	require.NoError(t, err)
-	assert.Len(t, result.Clients, 3) // All active clients
-
-	// Test large offset
-	result, err = suite.storage.ListClients(suite.ctx, true, 10, 100)
-	require.NoError(t, err)
-	assert.Empty(t, result.Clients)
-	assert.Equal(t, int64(3), result.TotalCount)
-	assert.False(t, result.HasMore)
-
-	// Test with corrupted client file
-	corruptPath := filepath.Join(suite.tempDir, "clients", "CORRUPT.json")
-	err = os.WriteFile(corruptPath, []byte("invalid json"), 0o600)
-	require.NoError(t, err)
-
-	// Should skip corrupted files
-	result, err = suite.storage.ListClients(suite.ctx, true, 10, 0)
-	require.NoError(t, err)
-	assert.Len(t, result.Clients, 3) // Only valid active clients
-
-	// Test with context cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel()
-
-	result, err = suite.storage.ListClients(ctx, true, 10, 0)
-	assert.Equal(t, context.Canceled, err)
-	assert.Nil(t, result)
-}
-
-func (suite *ClientStorageTestSuite) TestFindClientByEmail() {
+// TestListClientsSkipsCorruptFiles covers the file handling behind ListClients; sorting and
+// paging are covered by the conformance suite
+func (suite *ClientStorageTestSuite) TestListClientsSkipsCorruptFiles() {
 	t := suite.T()
 
-	// Test with non-existent email
-	client, err := suite.storage.FindClientByEmail(suite.ctx, "nonexistent@example.com")
-	require.Error(t, err)
-	assert.Nil(t, client)
-	var notFoundErr storageTypes.NotFoundError
-	require.ErrorAs(t, err, &notFoundErr)
-
-	// Create test clients
-	clients := []*models.Client{
-		{
-			ID:        testClientID001,
-			Name:      "Test Client 1",
-			Email:     "test1@example.com",
-			Active:    true,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		},
-		{
-			ID:        "CLIENT-002",
-			Name:      "Test Client 2",
-			Email:     "test2@example.com",
-			Active:    true,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		},
-		{
-			ID:        "CLIENT-003",
-			Name:      "Test Client 3",
-			Email:     "test3@example.com",
-			Active:    false, // Inactive
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		},
-	}
-
-	// Save clients
-	for _, c := range clients {
-		err = suite.storage.CreateClient(suite.ctx, c)
-		require.NoError(t, err)
-	}
-
-	// Test finding active client
-	client, err = suite.storage.FindClientByEmail(suite.ctx, "test1@example.com")
-	require.NoError(t, err)
-	require.NotNil(t, client)
-	assert.Equal(t, testClientID001, string(client.ID))
-	assert.Equal(t, "Test Client 1", client.Name)
-	assert.Equal(t, "test1@example.com", client.Email)
-
-	// Test finding another active client
-	client, err = suite.storage.FindClientByEmail(suite.ctx, "test2@example.com")
-	require.NoError(t, err)
-	require.NotNil(t, client)
-	assert.Equal(t, "CLIENT-002", string(client.ID))
-
-	// Test finding inactive client (should still find it)
-	client, err = suite.storage.FindClientByEmail(suite.ctx, "test3@example.com")
-	require.NoError(t, err)
-	require.NotNil(t, client)
-	assert.Equal(t, "CLIENT-003", string(client.ID))
-	assert.False(t, client.Active)
-
-	// Test with empty email
-	_, err = suite.storage.FindClientByEmail(suite.ctx, "")
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "email cannot be empty")
-
-	// Test with whitespace email
-	_, err = suite.storage.FindClientByEmail(suite.ctx, "   ")
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "email cannot be empty")
-
-	// Test case sensitivity
-	client, err = suite.storage.FindClientByEmail(suite.ctx, "TEST1@EXAMPLE.COM")
-	require.NoError(t, err)
-	require.NotNil(t, client)
-	assert.Equal(t, "test1@example.com", client.Email)
-
-	// Test with context cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel()
-
-	client, err = suite.storage.FindClientByEmail(ctx, "test1@example.com")
-	assert.Equal(t, context.Canceled, err)
-	assert.Nil(t, client)
-}
-
-func (suite *ClientStorageTestSuite) TestExistsClient() {
-	t := suite.T()
-
-	// Test non-existent client
-	exists, err := suite.storage.ExistsClient(suite.ctx, testClientID001)
-	require.NoError(t, err)
-	assert.False(t, exists)
-
-	// Create test client
 	client := &models.Client{
 		ID:        testClientID001,
 		Name:      testClientName,
@@ -562,32 +204,16 @@ func (suite *ClientStorageTestSuite) TestExistsClient() {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	require.NoError(t, suite.storage.CreateClient(suite.ctx, client))
 
-	// Save client
-	err = suite.storage.CreateClient(suite.ctx, client)
-	require.NoError(t, err)
-
-	// Test existing client
-	exists, err = suite.storage.ExistsClient(suite.ctx, testClientID001)
-	require.NoError(t, err)
-	assert.True(t, exists)
-
-	// Soft delete client
-	err = suite.storage.DeleteClient(suite.ctx, testClientID001)
-	require.NoError(t, err)
+	// A stray corrupted file is skipped
+	corruptPath := filepath.Join(suite.tempDir, "clients", "CORRUPT.json")
+	require.NoError(t, os.WriteFile(corruptPath, []byte("invalid json"), 0o600))
 
-	// Test after soft deletion (should still exist)
-	exists, err = suite.storage.ExistsClient(suite.ctx, testClientID001)
+	result, err := suite.storage.ListClients(suite.ctx, true, 10, 0)
 	require.NoError(t, err)
-	assert.True(t, exists)
-
-	// Test with context cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel()
-
-	exists, err = suite.storage.ExistsClient(ctx, testClientID001)
-	assert.Equal(t, context.Canceled, err)
-	assert.False(t, exists)
+	require.Len(t, result.Clients, 1)
+	assert.Equal(t, client.ID, result.Clients[0].ID)
 }
 
 func (suite *ClientStorageTestSuite) TestConcurrentClientAccess() {
@@ -1060,3 +686,59 @@ func (suite *ClientStorageTestSuite) TestEmailIndexConsistency() {
 	require.NoError(t, err)
 	assert.Equal(t, newClient.ID, found.ID)
 }
+
+func (suite *ClientStorageTestSuite) TestClientIndex() {
+	t := suite.T()
+
+	newClient := func(id, name, email string) *models.Client {
+		return &models.Client{
+			ID:        models.ClientID(id),
+			Name:      name,
+			Email:     email,
+			Active:    true,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+	}
+
+	acme := newClient("CLIENT-IDX-1", "Acme Corp", "billing@acme.test")
+	globex := newClient("CLIENT-IDX-2", "Globex", "ap@globex.test")
+	require.NoError(t, suite.storage.CreateClient(suite.ctx, acme))
+	require.NoError(t, suite.storage.CreateClient(suite.ctx, globex))
+
+	suite.Run("MaintainedOnWrite", func() {
+		acme.Address = "1 Road Runner Way"
+		require.NoError(t, suite.storage.UpdateClient(suite.ctx, acme))
+		require.NoError(t, suite.storage.DeleteClient(suite.ctx, globex.ID))
+
+		entries, err := suite.storage.ListClientIndex(suite.ctx)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "Acme Corp", entries[0].Name)
+		assert.Equal(t, "1 Road Runner Way", entries[0].Address)
+		assert.False(t, entries[1].Active)
+	})
+
+	suite.Run("RemovedOnHardDelete", func() {
+		require.NoError(t, suite.storage.HardDeleteClient(suite.ctx, globex.ID))
+
+		entries, err := suite.storage.ListClientIndex(suite.ctx)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, acme.ID, entries[0].ID)
+	})
+
+	suite.Run("RebuiltWhenStale", func() {
+		indexPath := filepath.Join(suite.tempDir, "index", "clients.json")
+		require.NoError(t, os.WriteFile(indexPath, []byte("{}"), 0o600))
+
+		entries, err := suite.storage.ListClientIndex(suite.ctx)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "Acme Corp", entries[0].Name)
+
+		data, err := os.ReadFile(indexPath) // #nosec G304 -- Test file path is controlled
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "CLIENT-IDX-1")
+	})
+}
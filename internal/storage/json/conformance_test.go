@@ -0,0 +1,13 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/mrz1836/go-invoice/internal/storage/storagetest"
+)
+
+func TestJSONStorageConformance(t *testing.T) {
+	storagetest.RunConformance(t, func(t *testing.T) storagetest.Backend {
+		return NewJSONStorage(t.TempDir(), &MockLogger{})
+	})
+}
@@ -0,0 +1,127 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// DefaultLockTimeout is how long an operation waits for another process to release the
+// storage lock file before giving up
+const DefaultLockTimeout = 10 * time.Second
+
+// lockFileName is the lock file in the storage directory that processes sharing it
+// (e.g. the CLI and the MCP server) lock around their reads and writes
+const lockFileName = ".lock"
+
+// lockRetryInterval is how often a busy lock file is retried
+const lockRetryInterval = 20 * time.Millisecond
+
+// ErrStorageLocked is returned when the lock file is still held by another process after
+// the lock timeout
+var ErrStorageLocked = fmt.Errorf("storage is locked by another process")
+
+// errLockHeld reports that a non-blocking lock attempt found the file locked
+var errLockHeld = errors.New("lock held")
+
+// SetLockTimeout sets how long operations wait for another process to release the storage
+// lock file (defaults to DefaultLockTimeout); zero or negative restores the default
+func (s *JSONStorage) SetLockTimeout(timeout time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lockTimeout = timeout
+}
+
+// lock takes the in-process write lock and an exclusive lock on the storage lock file.
+// The returned function releases both.
+func (s *JSONStorage) lock(ctx context.Context) (func(), error) {
+	s.mu.Lock()
+	release, err := s.lockFile(ctx, true)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	return func() {
+		release()
+		s.mu.Unlock()
+	}, nil
+}
+
+// rlock takes the in-process read lock and a shared lock on the storage lock file, so
+// reads wait out a write in progress in another process. The returned function releases both.
+func (s *JSONStorage) rlock(ctx context.Context) (func(), error) {
+	s.mu.RLock()
+	release, err := s.lockFile(ctx, false)
+	if err != nil {
+		s.mu.RUnlock()
+		return nil, err
+	}
+
+	return func() {
+		release()
+		s.mu.RUnlock()
+	}, nil
+}
+
+// lockFile locks the storage lock file, retrying while another process holds it until the
+// lock timeout. Before the storage directory exists there is nothing to coordinate, so no
+// lock is taken. Callers must hold s.mu.
+func (s *JSONStorage) lockFile(ctx context.Context, exclusive bool) (func(), error) {
+	path := filepath.Join(s.basePath, lockFileName)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600) // #nosec G304 -- Path is within the storage directory
+	if err != nil {
+		if os.IsNotExist(err) {
+			return func() {}, nil
+		}
+		return nil, storage.NewStorageUnavailableError("failed to open lock file", err)
+	}
+
+	closeFile := func() {
+		if closeErr := file.Close(); closeErr != nil {
+			s.logger.Error("failed to close lock file", "error", closeErr, "path", path)
+		}
+	}
+
+	timeout := s.lockTimeout
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		err = tryLockFile(file, exclusive)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLockHeld) {
+			closeFile()
+			return nil, storage.NewStorageUnavailableError("failed to lock storage", err)
+		}
+		if time.Now().After(deadline) {
+			closeFile()
+			return nil, storage.NewStorageUnavailableError(
+				fmt.Sprintf("timed out after %s waiting for %s", timeout, path), ErrStorageLocked)
+		}
+
+		select {
+		case <-ctx.Done():
+			closeFile()
+			return nil, ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+
+	return func() {
+		if unlockErr := unlockFile(file); unlockErr != nil {
+			s.logger.Error("failed to unlock lock file", "error", unlockErr, "path", path)
+		}
+		closeFile()
+	}, nil
+}
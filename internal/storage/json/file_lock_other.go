@@ -0,0 +1,15 @@
+//go:build !unix
+
+package json
+
+import "os"
+
+// tryLockFile is a no-op where flock is unavailable; only the in-process lock applies
+func tryLockFile(_ *os.File, _ bool) error {
+	return nil
+}
+
+// unlockFile is a no-op where flock is unavailable
+func unlockFile(_ *os.File) error {
+	return nil
+}
@@ -0,0 +1,137 @@
+//go:build unix
+
+package json
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// newLockTestInvoice returns a valid draft invoice for the lock tests
+func newLockTestInvoice(id string) *models.Invoice {
+	return &models.Invoice{
+		ID:     models.InvoiceID(id),
+		Number: id,
+		Client: models.Client{
+			ID:        testClientID001,
+			Name:      testClientName,
+			Email:     testClientEmail,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		Version:   1,
+		Date:      time.Now(),
+		DueDate:   time.Now().AddDate(0, 0, 30),
+		Status:    models.StatusDraft,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestFileLockKeepsIndexConsistentAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Two instances over one directory stand in for the CLI and the MCP server
+	first := NewJSONStorage(dir, &MockLogger{})
+	require.NoError(t, first.Initialize(ctx))
+	second := NewJSONStorage(dir, &MockLogger{})
+
+	const perInstance = 25
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*perInstance)
+	for i, s := range []*JSONStorage{first, second} {
+		for n := range perInstance {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				id := fmt.Sprintf("INV-%d-%03d", i, n)
+				if err := s.CreateInvoice(ctx, newLockTestInvoice(id)); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := s.GetInvoice(ctx, models.InvoiceID(id)); err != nil {
+					errs <- err
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent operation failed: %v", err)
+	}
+
+	// Every create must have made it into the index file without a rebuild
+	first.mu.RLock()
+	index, fresh, err := first.loadInvoiceIndexUnsafe(ctx)
+	first.mu.RUnlock()
+	require.NoError(t, err)
+	assert.True(t, fresh, "index out of sync with invoice files")
+	assert.Len(t, index, 2*perInstance, "creates lost from the index")
+}
+
+func TestFileLockTimesOutWhileHeldByAnotherInstance(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	holder := NewJSONStorage(dir, &MockLogger{})
+	require.NoError(t, holder.Initialize(ctx))
+	unlock, err := holder.lock(ctx)
+	require.NoError(t, err)
+	defer unlock()
+
+	waiter := NewJSONStorage(dir, &MockLogger{})
+	waiter.SetLockTimeout(50 * time.Millisecond)
+
+	err = waiter.CreateInvoice(ctx, newLockTestInvoice(testInvoiceID001))
+	require.Error(t, err)
+	require.ErrorIs(t, err, ErrStorageLocked)
+
+	_, err = waiter.GetInvoice(ctx, testInvoiceID001)
+	require.ErrorIs(t, err, ErrStorageLocked)
+}
+
+func TestFileLockReadsRetryUntilReleased(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	holder := NewJSONStorage(dir, &MockLogger{})
+	require.NoError(t, holder.Initialize(ctx))
+	require.NoError(t, holder.CreateInvoice(ctx, newLockTestInvoice(testInvoiceID001)))
+
+	unlock, err := holder.lock(ctx)
+	require.NoError(t, err)
+	released := time.Now().Add(100 * time.Millisecond)
+	time.AfterFunc(100*time.Millisecond, unlock)
+
+	reader := NewJSONStorage(dir, &MockLogger{})
+	invoice, err := reader.GetInvoice(ctx, testInvoiceID001)
+	require.NoError(t, err)
+	assert.Equal(t, models.InvoiceID(testInvoiceID001), invoice.ID)
+	assert.False(t, time.Now().Before(released), "read should wait for the lock to be released")
+}
+
+func TestFileLockHonorsContextCancellation(t *testing.T) {
+	dir := t.TempDir()
+
+	holder := NewJSONStorage(dir, &MockLogger{})
+	require.NoError(t, holder.Initialize(context.Background()))
+	unlock, err := holder.lock(context.Background())
+	require.NoError(t, err)
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	_, err = NewJSONStorage(dir, &MockLogger{}).GetInvoice(ctx, testInvoiceID001)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,29 @@
+//go:build unix
+
+package json
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLockFile takes an advisory flock on file without blocking, returning errLockHeld when
+// another open file holds a conflicting lock
+func tryLockFile(file *os.File, exclusive bool) error {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+
+	err := syscall.Flock(int(file.Fd()), how|syscall.LOCK_NB) // #nosec G115 -- File descriptors fit in an int
+	if errors.Is(err, syscall.EWOULDBLOCK) || errors.Is(err, syscall.EINTR) {
+		return errLockHeld
+	}
+	return err
+}
+
+// unlockFile releases the flock taken by tryLockFile
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN) // #nosec G115 -- File descriptors fit in an int
+}
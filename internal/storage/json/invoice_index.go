@@ -0,0 +1,222 @@
+package json
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// invoiceIndex maps invoice IDs to their list fields as stored in index/invoices.json
+type invoiceIndex map[models.InvoiceID]storage.InvoiceIndexEntry
+
+// RebuildIndexes regenerates the invoice and client indexes from the data files, for
+// when an index has drifted from the files (e.g. after files were edited by hand).
+//
+// Returns the number of invoices and clients indexed. Unreadable files are logged and
+// left out of the index.
+func (s *JSONStorage) RebuildIndexes(ctx context.Context) (invoices, clients int, err error) {
+	select {
+	case <-ctx.Done():
+		return 0, 0, ctx.Err()
+	default:
+	}
+
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer unlock()
+
+	invIndex, _, err := s.buildInvoiceIndexUnsafe(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := s.writeInvoiceIndexUnsafe(ctx, invIndex); err != nil {
+		return 0, 0, err
+	}
+
+	cliIndex, err := s.buildClientIndexUnsafe(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := s.writeClientIndexUnsafe(ctx, cliIndex); err != nil {
+		return 0, 0, err
+	}
+
+	s.logger.Info("indexes rebuilt", "invoices", len(invIndex), "clients", len(cliIndex))
+	return len(invIndex), len(cliIndex), nil
+}
+
+// invoiceIndexEntries returns the invoice index, rebuilding it from the invoice files
+// when it is missing or does not list exactly the invoice files on disk. A rebuild also
+// returns the files it could not read and left out of the index.
+func (s *JSONStorage) invoiceIndexEntries(ctx context.Context) (invoiceIndex, []string, error) {
+	unlock, err := s.rlock(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	index, fresh, err := s.loadInvoiceIndexUnsafe(ctx)
+	unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var skipped []string
+	if !fresh {
+		if unlock, err = s.lock(ctx); err != nil {
+			return nil, nil, err
+		}
+		index, skipped, err = s.rebuildInvoiceIndexUnsafe(ctx)
+		unlock()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return index, skipped, nil
+}
+
+// invoiceIndexPath returns the path of the invoice index file
+func (s *JSONStorage) invoiceIndexPath() string {
+	return filepath.Join(s.indexDir, "invoices.json")
+}
+
+// loadInvoiceIndexUnsafe reads the invoice index and reports whether it is in sync with
+// the invoice files. Callers must hold s.mu.
+func (s *JSONStorage) loadInvoiceIndexUnsafe(ctx context.Context) (invoiceIndex, bool, error) {
+	invoiceFiles, err := filepath.Glob(filepath.Join(s.invoicesDir, "*.json"))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list invoice files: %w", err)
+	}
+
+	index := make(invoiceIndex)
+	if err := s.readJSONFile(ctx, s.invoiceIndexPath(), &index); err != nil {
+		if os.IsNotExist(err) {
+			return index, len(invoiceFiles) == 0, nil
+		}
+		s.logger.Error("failed to read invoice index, rebuilding", "error", err)
+		return index, false, nil
+	}
+
+	if len(index) != len(invoiceFiles) {
+		return index, false, nil
+	}
+	for _, filePath := range invoiceFiles {
+		id := models.InvoiceID(strings.TrimSuffix(filepath.Base(filePath), ".json"))
+		entry, ok := index[id]
+		if !ok {
+			return index, false, nil
+		}
+		// Indexes written before updated_at was indexed cannot answer changed-since queries
+		if entry.UpdatedAt.IsZero() {
+			return index, false, nil
+		}
+	}
+
+	return index, true, nil
+}
+
+// buildInvoiceIndexUnsafe reads every invoice file into a new index, returning the files
+// that could not be read, each with the reason. Callers must hold s.mu.
+func (s *JSONStorage) buildInvoiceIndexUnsafe(ctx context.Context) (invoiceIndex, []string, error) {
+	invoiceFiles, err := filepath.Glob(filepath.Join(s.invoicesDir, "*.json"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list invoice files: %w", err)
+	}
+
+	index := make(invoiceIndex, len(invoiceFiles))
+	var skipped []string
+	for _, filePath := range invoiceFiles {
+		var invoice models.Invoice
+		if err := s.readInvoiceFileWithRetry(ctx, filePath, &invoice); err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			s.logger.Error("failed to read invoice file for index", "file", filePath, "error", err)
+			skipped = append(skipped, fmt.Sprintf("skipped invoice file %s: %v", filepath.Base(filePath), err))
+			continue
+		}
+		index[invoice.ID] = storage.NewInvoiceIndexEntry(&invoice)
+	}
+
+	return index, skipped, nil
+}
+
+// rebuildInvoiceIndexUnsafe regenerates the invoice index from the invoice files,
+// returning the files left out as for buildInvoiceIndexUnsafe. Callers must hold the write lock.
+func (s *JSONStorage) rebuildInvoiceIndexUnsafe(ctx context.Context) (invoiceIndex, []string, error) {
+	index, skipped, err := s.buildInvoiceIndexUnsafe(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The rebuilt index is still usable for this query if it cannot be saved
+	if err := s.writeInvoiceIndexUnsafe(ctx, index); err != nil {
+		s.logger.Error("failed to save rebuilt invoice index", "error", err)
+		return index, skipped, nil
+	}
+
+	s.logger.Debug("invoice index rebuilt", "invoices", len(index), "skipped", len(skipped))
+	return index, skipped, nil
+}
+
+// writeInvoiceIndexUnsafe saves the invoice index, creating the index directory if needed.
+// Callers must hold the write lock.
+func (s *JSONStorage) writeInvoiceIndexUnsafe(ctx context.Context, index invoiceIndex) error {
+	if err := os.MkdirAll(s.indexDir, 0o750); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+	if err := s.writeJSONFile(ctx, s.invoiceIndexPath(), index); err != nil {
+		return fmt.Errorf("failed to write invoice index: %w", err)
+	}
+	return nil
+}
+
+// checkInvoiceNumberUnsafe returns an error wrapping models.ErrInvoiceNumberExists when an
+// invoice, including one in the trash, already has number. Callers must hold the write lock,
+// so no other process can store the number before the caller does.
+func (s *JSONStorage) checkInvoiceNumberUnsafe(ctx context.Context, number string) error {
+	index, fresh, err := s.loadInvoiceIndexUnsafe(ctx)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		if index, _, err = s.rebuildInvoiceIndexUnsafe(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range index {
+		if entry.Number == number {
+			return fmt.Errorf("%w: %s", models.ErrInvoiceNumberExists, number)
+		}
+	}
+	return nil
+}
+
+// updateInvoiceIndex records or removes an invoice in the index. Callers must hold the
+// write lock. A stale index is rebuilt on the next list.
+func (s *JSONStorage) updateInvoiceIndex(ctx context.Context, invoice *models.Invoice, operation string) error {
+	index := make(invoiceIndex)
+	if err := s.readJSONFile(ctx, s.invoiceIndexPath(), &index); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read invoice index: %w", err)
+	}
+
+	if operation == "delete" {
+		delete(index, invoice.ID)
+	} else {
+		index[invoice.ID] = storage.NewInvoiceIndexEntry(invoice)
+	}
+
+	if err := s.writeInvoiceIndexUnsafe(ctx, index); err != nil {
+		return err
+	}
+
+	s.logger.Debug("updating invoice index", "invoice_id", invoice.ID, "operation", operation)
+	return nil
+}
@@ -0,0 +1,87 @@
+package json
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// ErrInvoiceSchemaUnsupported indicates an invoice file written by a newer version of go-invoice
+var ErrInvoiceSchemaUnsupported = fmt.Errorf("invoice schema version is newer than this version of go-invoice supports")
+
+// invoiceMigration upgrades an invoice record from schema version from to from+1
+type invoiceMigration struct {
+	from        int
+	description string
+	migrate     func(invoice *models.Invoice)
+}
+
+// invoiceMigrations upgrade stored invoices one schema version at a time, in order. Add a
+// migration here whenever the stored invoice shape changes; the newest schema version is
+// the number of migrations.
+//
+//nolint:gochecknoglobals // Registry of schema migrations
+var invoiceMigrations = []invoiceMigration{
+	{from: 0, description: "default fields missing from records written before schema versions", migrate: migrateInvoiceUnversioned},
+}
+
+// CurrentInvoiceSchemaVersion is the schema version invoices are written with
+var CurrentInvoiceSchemaVersion = len(invoiceMigrations) //nolint:gochecknoglobals // Derived from the migration registry
+
+// readInvoiceFile reads the invoice at path and upgrades it to the current schema version
+func (s *JSONStorage) readInvoiceFile(ctx context.Context, path string, invoice *models.Invoice) error {
+	if err := s.readJSONFile(ctx, path, invoice); err != nil {
+		return err
+	}
+	return s.upgradeInvoice(invoice)
+}
+
+// upgradeInvoice runs the migrations needed to bring invoice to CurrentInvoiceSchemaVersion.
+// The upgraded form is written the next time the invoice is saved.
+func (s *JSONStorage) upgradeInvoice(invoice *models.Invoice) error {
+	if invoice.SchemaVersion > CurrentInvoiceSchemaVersion {
+		return fmt.Errorf("%w: invoice %s has schema version %d, supported up to %d",
+			ErrInvoiceSchemaUnsupported, invoice.ID, invoice.SchemaVersion, CurrentInvoiceSchemaVersion)
+	}
+
+	for _, migration := range invoiceMigrations[invoice.SchemaVersion:] {
+		migration.migrate(invoice)
+		invoice.SchemaVersion = migration.from + 1
+		s.logger.Debug("upgraded invoice schema", "invoice_id", invoice.ID,
+			"schema_version", invoice.SchemaVersion, "migration", migration.description)
+	}
+
+	return nil
+}
+
+// migrateInvoiceUnversioned fills in what the oldest invoice files may lack: a version for
+// optimistic locking, a status, timestamps, and work item totals
+func migrateInvoiceUnversioned(invoice *models.Invoice) {
+	if invoice.Version < 1 {
+		invoice.Version = 1
+	}
+	if invoice.Status == "" {
+		invoice.Status = models.StatusDraft
+	}
+	if invoice.CreatedAt.IsZero() {
+		invoice.CreatedAt = invoice.Date
+	}
+	if invoice.UpdatedAt.IsZero() {
+		invoice.UpdatedAt = invoice.CreatedAt
+	}
+
+	if invoice.WorkItems == nil {
+		invoice.WorkItems = make([]models.WorkItem, 0)
+	}
+	for i := range invoice.WorkItems {
+		item := &invoice.WorkItems[i]
+		if item.Total == 0 {
+			item.Total = math.Round(item.Hours*item.Rate*100) / 100
+		}
+		if item.CreatedAt.IsZero() {
+			item.CreatedAt = invoice.CreatedAt
+		}
+	}
+}
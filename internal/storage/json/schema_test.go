@@ -0,0 +1,135 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// unversionedInvoiceJSON is an invoice as written before schema versions were recorded:
+// no schema_version, no status or version, and a work item without its total
+const unversionedInvoiceJSON = `{
+  "id": "INV-OLD",
+  "number": "INV-OLD",
+  "date": "2023-05-01T00:00:00Z",
+  "due_date": "2023-05-31T00:00:00Z",
+  "client": {
+    "id": "CLIENT-001",
+    "name": "Test Client",
+    "email": "test@example.com",
+    "active": true,
+    "created_at": "2023-01-01T00:00:00Z",
+    "updated_at": "2023-01-01T00:00:00Z"
+  },
+  "work_items": [
+    {"id": "work-1", "date": "2023-05-01T00:00:00Z", "hours": 3, "rate": 125.5, "description": "Consulting"}
+  ],
+  "subtotal": 376.5,
+  "crypto_fee": 0,
+  "tax_rate": 0,
+  "tax_amount": 0,
+  "total": 376.5
+}`
+
+// writeRawInvoice writes data as the stored file of invoice id
+func writeRawInvoice(t *testing.T, s *JSONStorage, id, data string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(s.getInvoicePath(models.InvoiceID(id)), []byte(data), 0o600))
+}
+
+// storedSchemaVersion reads the schema version recorded in the stored file of invoice id
+func storedSchemaVersion(t *testing.T, s *JSONStorage, id string) int {
+	t.Helper()
+	data, err := os.ReadFile(s.getInvoicePath(models.InvoiceID(id)))
+	require.NoError(t, err)
+
+	var record struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	require.NoError(t, json.Unmarshal(data, &record))
+	return record.SchemaVersion
+}
+
+func TestInvoiceMigrationRegistry(t *testing.T) {
+	for i, migration := range invoiceMigrations {
+		assert.Equal(t, i, migration.from, "migrations must be registered in order without gaps")
+		assert.NotEmpty(t, migration.description)
+	}
+	assert.Equal(t, len(invoiceMigrations), CurrentInvoiceSchemaVersion)
+}
+
+func TestUnversionedInvoiceUpgradesOnRead(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+	writeRawInvoice(t, s, "INV-OLD", unversionedInvoiceJSON)
+	_, _, err := s.RebuildIndexes(ctx)
+	require.NoError(t, err)
+
+	invoice, err := s.GetInvoice(ctx, "INV-OLD")
+	require.NoError(t, err)
+	assert.Equal(t, CurrentInvoiceSchemaVersion, invoice.SchemaVersion)
+	assert.Equal(t, 1, invoice.Version)
+	assert.Equal(t, models.StatusDraft, invoice.Status)
+	assert.False(t, invoice.CreatedAt.IsZero())
+	require.Len(t, invoice.WorkItems, 1)
+	assert.InDelta(t, 376.5, invoice.WorkItems[0].Total, 0.001)
+	require.NoError(t, invoice.Validate(ctx))
+
+	listed, err := s.ListInvoices(ctx, models.InvoiceFilter{})
+	require.NoError(t, err)
+	require.Len(t, listed.Invoices, 1)
+	assert.Equal(t, CurrentInvoiceSchemaVersion, listed.Invoices[0].SchemaVersion)
+
+	// Reading alone leaves the file as it was
+	assert.Zero(t, storedSchemaVersion(t, s, "INV-OLD"))
+
+	// Saving persists the upgraded form, which then round-trips unchanged
+	invoice.Description = "Migrated"
+	require.NoError(t, s.UpdateInvoice(ctx, invoice))
+	assert.Equal(t, CurrentInvoiceSchemaVersion, storedSchemaVersion(t, s, "INV-OLD"))
+
+	reloaded, err := s.GetInvoice(ctx, "INV-OLD")
+	require.NoError(t, err)
+	assert.Equal(t, "Migrated", reloaded.Description)
+	assert.Equal(t, 2, reloaded.Version)
+	assert.Equal(t, money.FromFloat(376.5), reloaded.Total)
+	assert.Equal(t, invoice.WorkItems, reloaded.WorkItems)
+}
+
+func TestCreateInvoiceWritesCurrentSchemaVersion(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+	require.NoError(t, s.CreateInvoice(ctx, newVerifyTestInvoice("INV-NEW", models.StatusDraft, money.FromFloat(100))))
+
+	assert.Equal(t, CurrentInvoiceSchemaVersion, storedSchemaVersion(t, s, "INV-NEW"))
+}
+
+func TestNewerSchemaInvoiceIsRejected(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+	require.NoError(t, s.CreateInvoice(ctx, newVerifyTestInvoice("INV-FUTURE", models.StatusDraft, money.FromFloat(100))))
+
+	invoice, err := s.GetInvoice(ctx, "INV-FUTURE")
+	require.NoError(t, err)
+	invoice.SchemaVersion = CurrentInvoiceSchemaVersion + 1
+	data, err := json.Marshal(invoice)
+	require.NoError(t, err)
+	writeRawInvoice(t, s, "INV-FUTURE", string(data))
+
+	_, err = s.GetInvoice(ctx, "INV-FUTURE")
+	require.ErrorIs(t, err, ErrInvoiceSchemaUnsupported)
+
+	// Verify reports the file but never quarantines it as corrupt
+	report, err := s.Verify(ctx, VerifyOptions{Repair: true})
+	require.NoError(t, err)
+	require.Len(t, report.Unresolved(), 1)
+	assert.Equal(t, VerifyIssueInvalid, report.Unresolved()[0].Kind)
+	assert.FileExists(t, s.getInvoicePath("INV-FUTURE"))
+}
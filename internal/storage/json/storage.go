@@ -6,15 +6,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/mrz1836/go-invoice/internal/models"
 	"github.com/mrz1836/go-invoice/internal/storage"
 )
 
+// listReadRetryDelay is how long listing waits before reading an invoice file that failed to read again
+const listReadRetryDelay = 50 * time.Millisecond
+
 // Invoice storage errors
 var (
 	ErrInvoiceCannotBeNil     = fmt.Errorf("invoice cannot be nil")
@@ -28,13 +31,18 @@ type JSONStorage struct {
 	clientsDir  string
 	indexDir    string
 	backupDir   string
+	attachDir   string
 	mu          sync.RWMutex
 	initialized bool
+	lockTimeout time.Duration
 	stats       *storage.StorageStats
 	logger      Logger
+	autoBackup  atomic.Pointer[autoBackup]
+	lastBackup  atomic.Int64 // UnixNano of the last backup taken by this instance
 }
 
-// Logger interface for storage operations
+// Logger interface for storage operations, satisfied by both cli.SimpleLogger and the
+// MCP server logger from mcp.NewLogger
 type Logger interface {
 	Info(msg string, fields ...any)
 	Error(msg string, fields ...any)
@@ -49,6 +57,7 @@ func NewJSONStorage(basePath string, logger Logger) *JSONStorage {
 		clientsDir:  filepath.Join(basePath, "clients"),
 		indexDir:    filepath.Join(basePath, "index"),
 		backupDir:   filepath.Join(basePath, "backups"),
+		attachDir:   filepath.Join(basePath, models.AttachmentsDir),
 		logger:      logger,
 		stats: &storage.StorageStats{
 			HealthStatus: storage.HealthStatusHealthy,
@@ -64,8 +73,17 @@ func (s *JSONStorage) Initialize(ctx context.Context) error {
 	default:
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	// The base directory holds the lock file, so it must exist before locking
+	if err := os.MkdirAll(s.basePath, 0o750); err != nil {
+		return storage.NewStorageUnavailableError(
+			fmt.Sprintf("failed to create directory %s", s.basePath), err)
+	}
+
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	s.logger.Info("initializing JSON storage", "path", s.basePath)
 
@@ -115,8 +133,11 @@ func (s *JSONStorage) IsInitialized(ctx context.Context) (bool, error) {
 	default:
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	unlock, err := s.rlock(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
 
 	if s.initialized {
 		return true, nil
@@ -153,7 +174,7 @@ func (s *JSONStorage) GetStorageInfo(ctx context.Context) (*storage.StorageInfo,
 		return nil, err
 	}
 
-	return &storage.StorageInfo{
+	info := &storage.StorageInfo{
 		Type:             "json",
 		Version:          "1.0",
 		Path:             s.basePath,
@@ -161,7 +182,17 @@ func (s *JSONStorage) GetStorageInfo(ctx context.Context) (*storage.StorageInfo,
 		ReadOnly:         false,
 		SupportsBackups:  true,
 		SupportsIndexing: true,
-	}, nil
+	}
+
+	lastBackup, err := s.LastBackupTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !lastBackup.IsZero() {
+		info.LastBackupTime = lastBackup.Format(time.RFC3339)
+	}
+
+	return info, nil
 }
 
 // Validate performs integrity checks on the storage system
@@ -214,16 +245,23 @@ func (s *JSONStorage) CreateInvoice(ctx context.Context, invoice *models.Invoice
 		return fmt.Errorf("invalid invoice: %w", err)
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Check if invoice already exists
 	invoicePath := s.getInvoicePath(invoice.ID)
 	if _, err := os.Stat(invoicePath); err == nil {
 		return storage.NewConflictError("invoice", string(invoice.ID), "")
 	}
+	if err := s.checkInvoiceNumberUnsafe(ctx, invoice.Number); err != nil {
+		return err
+	}
 
 	// Write invoice file atomically
+	invoice.SchemaVersion = CurrentInvoiceSchemaVersion
 	if err := s.writeJSONFile(ctx, invoicePath, invoice); err != nil {
 		return fmt.Errorf("failed to write invoice file: %w", err)
 	}
@@ -234,6 +272,7 @@ func (s *JSONStorage) CreateInvoice(ctx context.Context, invoice *models.Invoice
 		// Don't fail the operation for index errors
 	}
 
+	s.recordMutation()
 	s.logger.Info("invoice created", "id", invoice.ID, "number", invoice.Number)
 	return nil
 }
@@ -250,20 +289,13 @@ func (s *JSONStorage) GetInvoice(ctx context.Context, id models.InvoiceID) (*mod
 		return nil, ErrInvoiceIDCannotBeEmpty
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	invoicePath := s.getInvoicePath(id)
-	var invoice models.Invoice
-
-	if err := s.readJSONFile(ctx, invoicePath, &invoice); err != nil {
-		if os.IsNotExist(err) {
-			return nil, storage.NewNotFoundError("invoice", string(id))
-		}
-		return nil, fmt.Errorf("failed to read invoice file: %w", err)
+	unlock, err := s.rlock(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer unlock()
 
-	return &invoice, nil
+	return s.getInvoiceUnsafe(ctx, id)
 }
 
 // UpdateInvoice updates an existing invoice with optimistic locking
@@ -283,8 +315,11 @@ func (s *JSONStorage) UpdateInvoice(ctx context.Context, invoice *models.Invoice
 		return fmt.Errorf("invalid invoice: %w", err)
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Read existing invoice for optimistic locking
 	existing, err := s.getInvoiceUnsafe(ctx, invoice.ID)
@@ -301,9 +336,10 @@ func (s *JSONStorage) UpdateInvoice(ctx context.Context, invoice *models.Invoice
 			invoice.Version, existing.Version)
 	}
 
-	// Increment version
+	// Increment version; the record is always written in the current schema
 	invoice.Version++
 	invoice.UpdatedAt = time.Now()
+	invoice.SchemaVersion = CurrentInvoiceSchemaVersion
 
 	// Write updated invoice atomically
 	invoicePath := s.getInvoicePath(invoice.ID)
@@ -316,6 +352,7 @@ func (s *JSONStorage) UpdateInvoice(ctx context.Context, invoice *models.Invoice
 		s.logger.Error("failed to update invoice index", "error", err, "invoice_id", invoice.ID)
 	}
 
+	s.recordMutation()
 	s.logger.Info("invoice updated", "id", invoice.ID, "version", invoice.Version)
 	return nil
 }
@@ -332,8 +369,11 @@ func (s *JSONStorage) DeleteInvoice(ctx context.Context, id models.InvoiceID) er
 		return ErrInvoiceIDCannotBeEmpty
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	invoicePath := s.getInvoicePath(id)
 
@@ -347,11 +387,17 @@ func (s *JSONStorage) DeleteInvoice(ctx context.Context, id models.InvoiceID) er
 		return fmt.Errorf("failed to delete invoice file: %w", err)
 	}
 
+	// Attachments only exist for the invoice, so they go with it
+	if err := os.RemoveAll(s.getAttachmentsPath(id)); err != nil {
+		s.logger.Error("failed to delete invoice attachments", "error", err, "invoice_id", id)
+	}
+
 	// Update index
 	if err := s.updateInvoiceIndex(ctx, &models.Invoice{ID: id}, "delete"); err != nil {
 		s.logger.Error("failed to update invoice index", "error", err, "invoice_id", id)
 	}
 
+	s.recordMutation()
 	s.logger.Info("invoice deleted", "id", id)
 	return nil
 }
@@ -364,11 +410,14 @@ func (s *JSONStorage) ExistsInvoice(ctx context.Context, id models.InvoiceID) (b
 	default:
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	unlock, err := s.rlock(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
 
 	invoicePath := s.getInvoicePath(id)
-	_, err := os.Stat(invoicePath)
+	_, err = os.Stat(invoicePath)
 	if err == nil {
 		return true, nil
 	}
@@ -391,59 +440,89 @@ func (s *JSONStorage) ListInvoices(ctx context.Context, filter models.InvoiceFil
 		return nil, storage.NewInvalidFilterError("filter", filter, err.Error())
 	}
 
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	// Read all invoice files
-	invoiceFiles, err := filepath.Glob(filepath.Join(s.invoicesDir, "*.json"))
+	// Filter and sort on the index, then load only the invoices on the requested page
+	matches, warnings, err := s.queryInvoiceIndex(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list invoice files: %w", err)
+		return nil, err
 	}
 
-	var allInvoices []*models.Invoice
-	for _, filePath := range invoiceFiles {
-		var invoice models.Invoice
-		if err := s.readJSONFile(ctx, filePath, &invoice); err != nil {
-			s.logger.Error("failed to read invoice file", "file", filePath, "error", err)
-			continue // Skip corrupted files
-		}
+	start, end := storage.PageBounds(len(matches), filter)
 
-		// Apply filter
-		if s.matchesFilter(&invoice, filter) {
-			allInvoices = append(allInvoices, &invoice)
+	unlock, err := s.rlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	invoices := make([]*models.Invoice, 0, end-start)
+	for _, entry := range matches[start:end] {
+		invoice, err := s.getListedInvoiceUnsafe(ctx, entry.ID)
+		if err != nil {
+			if ctx.Err() != nil {
+				unlock()
+				return nil, ctx.Err()
+			}
+			// Skip files removed or corrupted since indexing, but report them
+			s.logger.Error("failed to read invoice file", "invoice_id", entry.ID, "error", err)
+			warnings = append(warnings, fmt.Sprintf("skipped invoice %s: %v", entry.ID, err))
+			continue
 		}
+		invoices = append(invoices, invoice)
 	}
+	unlock()
 
-	// Sort invoices (by date descending by default)
-	sort.Slice(allInvoices, func(i, j int) bool {
-		return allInvoices[i].Date.After(allInvoices[j].Date)
-	})
+	result := storage.NewInvoiceListResult(invoices, len(matches), end, filter)
+	result.Warnings = warnings
+	return result, nil
+}
 
-	// Apply pagination
-	totalCount := int64(len(allInvoices))
-	start := filter.Offset
-	if start > len(allInvoices) {
-		start = len(allInvoices)
+// getListedInvoiceUnsafe reads an invoice for ListInvoices like getInvoiceUnsafe, but
+// retries a file that fails to read, see readInvoiceFileWithRetry. Must be called with
+// the lock held.
+func (s *JSONStorage) getListedInvoiceUnsafe(ctx context.Context, id models.InvoiceID) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if err := s.readInvoiceFileWithRetry(ctx, s.getInvoicePath(id), &invoice); err != nil {
+		if os.IsNotExist(err) {
+			return nil, storage.NewNotFoundError("invoice", string(id))
+		}
+		return nil, fmt.Errorf("failed to read invoice file: %w", err)
 	}
+	return &invoice, nil
+}
 
-	end := start + filter.Limit
-	if filter.Limit <= 0 {
-		end = len(allInvoices)
-	} else if end > len(allInvoices) {
-		end = len(allInvoices)
+// readInvoiceFileWithRetry reads an invoice file, reading it once more after
+// listReadRetryDelay before declaring it corrupt, so an invoice being rewritten by another
+// process at that moment is not skipped. A missing file is reported straight away.
+func (s *JSONStorage) readInvoiceFileWithRetry(ctx context.Context, path string, invoice *models.Invoice) error {
+	err := s.readInvoiceFile(ctx, path, invoice)
+	if err == nil || os.IsNotExist(err) || ctx.Err() != nil {
+		return err
 	}
 
-	result := &storage.InvoiceListResult{
-		Invoices:   allInvoices[start:end],
-		TotalCount: totalCount,
-		HasMore:    end < len(allInvoices),
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(listReadRetryDelay):
 	}
+	*invoice = models.Invoice{}
+	return s.readInvoiceFile(ctx, path, invoice)
+}
 
-	if result.HasMore {
-		result.NextOffset = end
+// queryInvoiceIndex returns the index entries matching filter in the requested order,
+// along with the invoice files left out of the index because they could not be read
+func (s *JSONStorage) queryInvoiceIndex(ctx context.Context, filter models.InvoiceFilter) ([]storage.InvoiceIndexEntry, []string, error) {
+	index, skipped, err := s.invoiceIndexEntries(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return result, nil
+	matches := make([]storage.InvoiceIndexEntry, 0, len(index))
+	for _, entry := range index {
+		if storage.MatchesInvoiceFilter(entry, filter) {
+			matches = append(matches, entry)
+		}
+	}
+
+	storage.SortInvoiceEntries(matches, filter)
+	return matches, skipped, nil
 }
 
 // CountInvoices returns the total count of invoices matching the filter
@@ -454,23 +533,18 @@ func (s *JSONStorage) CountInvoices(ctx context.Context, filter models.InvoiceFi
 	default:
 	}
 
-	// Use ListInvoices but only count
-	result, err := s.ListInvoices(ctx, models.InvoiceFilter{
-		Status:      filter.Status,
-		ClientID:    filter.ClientID,
-		DateFrom:    filter.DateFrom,
-		DateTo:      filter.DateTo,
-		DueDateFrom: filter.DueDateFrom,
-		DueDateTo:   filter.DueDateTo,
-		AmountMin:   filter.AmountMin,
-		AmountMax:   filter.AmountMax,
-		Limit:       0, // No limit for counting
-	})
+	countFilter := storage.CountFilter(filter)
+	if err := countFilter.Validate(ctx); err != nil {
+		return 0, storage.NewInvalidFilterError("filter", countFilter, err.Error())
+	}
+
+	// Counting needs only the index, never the invoice files
+	matches, _, err := s.queryInvoiceIndex(ctx, countFilter)
 	if err != nil {
 		return 0, err
 	}
 
-	return result.TotalCount, nil
+	return int64(len(matches)), nil
 }
 
 // Helper methods
@@ -479,6 +553,11 @@ func (s *JSONStorage) getInvoicePath(id models.InvoiceID) string {
 	return filepath.Join(s.invoicesDir, fmt.Sprintf("%s.json", string(id)))
 }
 
+// getAttachmentsPath returns the directory holding an invoice's attachments
+func (s *JSONStorage) getAttachmentsPath(id models.InvoiceID) string {
+	return filepath.Join(s.attachDir, string(id))
+}
+
 func (s *JSONStorage) getClientPath(id models.ClientID) string {
 	return filepath.Join(s.clientsDir, fmt.Sprintf("%s.json", string(id)))
 }
@@ -487,7 +566,7 @@ func (s *JSONStorage) getInvoiceUnsafe(ctx context.Context, id models.InvoiceID)
 	invoicePath := s.getInvoicePath(id)
 	var invoice models.Invoice
 
-	if err := s.readJSONFile(ctx, invoicePath, &invoice); err != nil {
+	if err := s.readInvoiceFile(ctx, invoicePath, &invoice); err != nil {
 		if os.IsNotExist(err) {
 			return nil, storage.NewNotFoundError("invoice", string(id))
 		}
@@ -615,49 +694,9 @@ func (s *JSONStorage) readJSONFile(ctx context.Context, path string, data interf
 	return nil
 }
 
-func (s *JSONStorage) matchesFilter(invoice *models.Invoice, filter models.InvoiceFilter) bool {
-	// Status filter
-	if filter.Status != "" && invoice.Status != filter.Status {
-		return false
-	}
-
-	// Client ID filter
-	if filter.ClientID != "" && invoice.Client.ID != filter.ClientID {
-		return false
-	}
-
-	// Date range filters
-	if !filter.DateFrom.IsZero() && invoice.Date.Before(filter.DateFrom) {
-		return false
-	}
-	if !filter.DateTo.IsZero() && invoice.Date.After(filter.DateTo) {
-		return false
-	}
-
-	// Due date range filters
-	if !filter.DueDateFrom.IsZero() && invoice.DueDate.Before(filter.DueDateFrom) {
-		return false
-	}
-	if !filter.DueDateTo.IsZero() && invoice.DueDate.After(filter.DueDateTo) {
-		return false
-	}
-
-	// Amount range filters
-	if filter.AmountMin > 0 && invoice.Total < filter.AmountMin {
-		return false
-	}
-	if filter.AmountMax > 0 && invoice.Total > filter.AmountMax {
-		return false
-	}
-
-	return true
-}
-
 func (s *JSONStorage) initializeIndexes(ctx context.Context) error {
 	// Create invoice index file
-	invoiceIndexPath := filepath.Join(s.indexDir, "invoices.json")
-	invoiceIndex := make(map[string]interface{})
-	if err := s.writeJSONFile(ctx, invoiceIndexPath, invoiceIndex); err != nil {
+	if err := s.writeJSONFile(ctx, s.invoiceIndexPath(), make(invoiceIndex)); err != nil {
 		return fmt.Errorf("failed to create invoice index: %w", err)
 	}
 
@@ -671,13 +710,6 @@ func (s *JSONStorage) initializeIndexes(ctx context.Context) error {
 	return nil
 }
 
-func (s *JSONStorage) updateInvoiceIndex(_ context.Context, invoice *models.Invoice, operation string) error { //nolint:unparam // Placeholder for future index implementation
-	// For now, this is a placeholder - a full implementation would maintain
-	// search indexes for faster querying
-	s.logger.Debug("updating invoice index", "invoice_id", invoice.ID, "operation", operation)
-	return nil
-}
-
 func (s *JSONStorage) validateInvoiceFiles(ctx context.Context) error {
 	invoiceFiles, err := filepath.Glob(filepath.Join(s.invoicesDir, "*.json"))
 	if err != nil {
@@ -686,7 +718,7 @@ func (s *JSONStorage) validateInvoiceFiles(ctx context.Context) error {
 
 	for _, filePath := range invoiceFiles {
 		var invoice models.Invoice
-		if err := s.readJSONFile(ctx, filePath, &invoice); err != nil {
+		if err := s.readInvoiceFile(ctx, filePath, &invoice); err != nil {
 			return storage.NewCorruptedError("invoice", filepath.Base(filePath), err.Error())
 		}
 
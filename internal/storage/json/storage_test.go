@@ -15,7 +15,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/mrz1836/go-invoice/internal/cli"
+	"github.com/mrz1836/go-invoice/internal/mcp"
 	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
 	storageTypes "github.com/mrz1836/go-invoice/internal/storage"
 )
 
@@ -442,180 +445,6 @@ func (suite *JSONStorageTestSuite) TestCreateInvoice() {
 	assert.Equal(t, context.Canceled, err)
 }
 
-func (suite *JSONStorageTestSuite) TestGetInvoice() {
-	t := suite.T()
-
-	// Initialize storage
-	err := suite.storage.Initialize(suite.ctx)
-	require.NoError(t, err)
-
-	// Create test invoice
-	invoice := &models.Invoice{
-		ID:     testInvoiceID001,
-		Number: testInvoiceNum,
-		Client: models.Client{
-			ID:        testClientID001,
-			Name:      testClientName,
-			Email:     testClientEmail,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		},
-		Version:   1,
-		Date:      time.Now().Truncate(time.Second),
-		DueDate:   time.Now().AddDate(0, 0, 30).Truncate(time.Second),
-		Status:    models.StatusDraft,
-		CreatedAt: time.Now().Truncate(time.Second),
-		UpdatedAt: time.Now().Truncate(time.Second),
-	}
-
-	// Save invoice
-	err = suite.storage.CreateInvoice(suite.ctx, invoice)
-	require.NoError(t, err)
-
-	// Test successful retrieval
-	retrieved, err := suite.storage.GetInvoice(suite.ctx, testInvoiceID001)
-	require.NoError(t, err)
-	require.NotNil(t, retrieved)
-
-	assert.Equal(t, invoice.ID, retrieved.ID)
-	assert.Equal(t, invoice.Number, retrieved.Number)
-	assert.Equal(t, invoice.Client.ID, retrieved.Client.ID)
-	assert.WithinDuration(t, invoice.Date, retrieved.Date, time.Second)
-	assert.WithinDuration(t, invoice.DueDate, retrieved.DueDate, time.Second)
-
-	// Test non-existent invoice
-	retrieved, err = suite.storage.GetInvoice(suite.ctx, "INV-999")
-	require.Error(t, err)
-	assert.Nil(t, retrieved)
-	var notFoundErr storageTypes.NotFoundError
-	require.ErrorAs(t, err, &notFoundErr)
-	assert.Equal(t, "invoice", notFoundErr.Resource)
-	assert.Equal(t, "INV-999", notFoundErr.ID)
-
-	// Test with empty ID
-	_, err = suite.storage.GetInvoice(suite.ctx, "")
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "invoice ID cannot be empty")
-
-	// Test with whitespace ID
-	_, err = suite.storage.GetInvoice(suite.ctx, "   ")
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "invoice ID cannot be empty")
-
-	// Test with context cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel()
-
-	retrieved, err = suite.storage.GetInvoice(ctx, testInvoiceID001)
-	assert.Equal(t, context.Canceled, err)
-	assert.Nil(t, retrieved)
-}
-
-func (suite *JSONStorageTestSuite) TestUpdateInvoice() {
-	t := suite.T()
-
-	// Initialize storage
-	err := suite.storage.Initialize(suite.ctx)
-	require.NoError(t, err)
-
-	// Create test invoice
-	invoice := &models.Invoice{
-		ID:      testInvoiceID001,
-		Number:  testInvoiceNum,
-		Version: 1,
-		Client: models.Client{
-			ID:        testClientID001,
-			Name:      testClientName,
-			Email:     testClientEmail,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
-		},
-		Date:      time.Now(),
-		DueDate:   time.Now().AddDate(0, 0, 30),
-		Status:    models.StatusDraft,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Save invoice
-	err = suite.storage.CreateInvoice(suite.ctx, invoice)
-	require.NoError(t, err)
-
-	// Update invoice
-	invoice.Status = models.StatusSent
-	invoice.Description = "Updated description"
-
-	err = suite.storage.UpdateInvoice(suite.ctx, invoice)
-	require.NoError(t, err)
-
-	// Verify version was incremented
-	assert.Equal(t, 2, invoice.Version)
-
-	// Verify changes were saved
-	retrieved, err := suite.storage.GetInvoice(suite.ctx, testInvoiceID001)
-	require.NoError(t, err)
-	assert.Equal(t, models.StatusSent, retrieved.Status)
-	assert.Equal(t, "Updated description", retrieved.Description)
-	assert.Equal(t, 2, retrieved.Version)
-
-	// Test optimistic locking
-	outdatedInvoice := &models.Invoice{
-		ID:        testInvoiceID001,
-		Number:    testInvoiceNum,
-		Version:   1, // Old version
-		Client:    invoice.Client,
-		Date:      invoice.Date,
-		DueDate:   invoice.DueDate,
-		Status:    models.StatusPaid,
-		CreatedAt: invoice.CreatedAt,
-		UpdatedAt: invoice.UpdatedAt,
-	}
-
-	err = suite.storage.UpdateInvoice(suite.ctx, outdatedInvoice)
-	require.Error(t, err)
-	var versionErr storageTypes.VersionMismatchError
-	require.ErrorAs(t, err, &versionErr)
-	assert.Equal(t, "invoice", versionErr.Resource)
-	assert.Equal(t, testInvoiceID001, versionErr.ID)
-	assert.Equal(t, 1, versionErr.ExpectedVersion)
-	assert.Equal(t, 2, versionErr.ActualVersion)
-
-	// Test updating non-existent invoice
-	nonExistent := &models.Invoice{
-		ID:        "INV-999",
-		Number:    "INV-2024-999",
-		Version:   1,
-		Client:    invoice.Client,
-		Date:      invoice.Date,
-		DueDate:   invoice.DueDate,
-		Status:    models.StatusDraft,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	err = suite.storage.UpdateInvoice(suite.ctx, nonExistent)
-	require.Error(t, err)
-	var notFoundErr storageTypes.NotFoundError
-	require.ErrorAs(t, err, &notFoundErr)
-
-	// Test with nil invoice
-	err = suite.storage.UpdateInvoice(suite.ctx, nil)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "invoice cannot be nil")
-
-	// Test with invalid invoice
-	invalidInvoice := &models.Invoice{
-		ID:      testInvoiceID001,
-		Version: 2,
-		// Missing required fields - will fail validation
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	err = suite.storage.UpdateInvoice(suite.ctx, invalidInvoice)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid invoice")
-}
-
 func (suite *JSONStorageTestSuite) TestDeleteInvoice() {
 	t := suite.T()
 
@@ -651,13 +480,19 @@ func (suite *JSONStorageTestSuite) TestDeleteInvoice() {
 	_, err = os.Stat(invoicePath)
 	require.NoError(t, err)
 
+	// Attach a file the way the CLI does
+	attachmentsPath := filepath.Join(suite.tempDir, models.AttachmentsDir, string(testInvoiceID001))
+	require.NoError(t, os.MkdirAll(attachmentsPath, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(attachmentsPath, "receipt.pdf"), []byte("%PDF"), 0o600))
+
 	// Delete invoice
 	err = suite.storage.DeleteInvoice(suite.ctx, testInvoiceID001)
 	require.NoError(t, err)
 
-	// Verify file was deleted
+	// Verify file and attachments were deleted
 	_, err = os.Stat(invoicePath)
 	assert.True(t, os.IsNotExist(err))
+	assert.NoDirExists(t, attachmentsPath)
 
 	// Try to delete again
 	err = suite.storage.DeleteInvoice(suite.ctx, testInvoiceID001)
@@ -683,19 +518,13 @@ func (suite *JSONStorageTestSuite) TestDeleteInvoice() {
 	assert.Equal(t, context.Canceled, err)
 }
 
-func (suite *JSONStorageTestSuite) TestExistsInvoice() {
+// TestListInvoicesSkipsCorruptFiles covers the file handling behind ListInvoices; the
+// filter, sort and paging behavior is covered by the conformance suite
+func (suite *JSONStorageTestSuite) TestListInvoicesSkipsCorruptFiles() {
 	t := suite.T()
+	require.NoError(t, suite.storage.Initialize(suite.ctx))
 
-	// Initialize storage
-	err := suite.storage.Initialize(suite.ctx)
-	require.NoError(t, err)
-
-	// Test non-existent invoice
-	exists, err := suite.storage.ExistsInvoice(suite.ctx, testInvoiceID001)
-	require.NoError(t, err)
-	assert.False(t, exists)
-
-	// Create test invoice
+	now := time.Now()
 	invoice := &models.Invoice{
 		ID:     testInvoiceID001,
 		Number: testInvoiceNum,
@@ -703,224 +532,68 @@ func (suite *JSONStorageTestSuite) TestExistsInvoice() {
 			ID:        testClientID001,
 			Name:      testClientName,
 			Email:     testClientEmail,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			CreatedAt: now,
+			UpdatedAt: now,
 		},
 		Version:   1,
-		Date:      time.Now(),
-		DueDate:   time.Now().AddDate(0, 0, 30),
+		Date:      now,
+		DueDate:   now.AddDate(0, 0, 30),
 		Status:    models.StatusDraft,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Save invoice
-	err = suite.storage.CreateInvoice(suite.ctx, invoice)
-	require.NoError(t, err)
-
-	// Test existing invoice
-	exists, err = suite.storage.ExistsInvoice(suite.ctx, testInvoiceID001)
-	require.NoError(t, err)
-	assert.True(t, exists)
-
-	// Delete invoice
-	err = suite.storage.DeleteInvoice(suite.ctx, testInvoiceID001)
-	require.NoError(t, err)
-
-	// Test after deletion
-	exists, err = suite.storage.ExistsInvoice(suite.ctx, testInvoiceID001)
-	require.NoError(t, err)
-	assert.False(t, exists)
-
-	// Test with context cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel()
-
-	exists, err = suite.storage.ExistsInvoice(ctx, testInvoiceID001)
-	assert.Equal(t, context.Canceled, err)
-	assert.False(t, exists)
-}
-
-func (suite *JSONStorageTestSuite) TestListInvoices() {
-	t := suite.T()
-
-	// Initialize storage
-	err := suite.storage.Initialize(suite.ctx)
-	require.NoError(t, err)
-
-	// Test empty list
-	result, err := suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{})
-	require.NoError(t, err)
-	require.NotNil(t, result)
-	assert.Empty(t, result.Invoices)
-	assert.Equal(t, int64(0), result.TotalCount)
-	assert.False(t, result.HasMore)
-
-	// Create test invoices
-	now := time.Now()
-	client1 := models.Client{
-		ID:        testClientID001,
-		Name:      "Client One",
-		Email:     "client1@example.com",
+		Total:     money.FromFloat(1000.0),
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
-	client2 := models.Client{
-		ID:        "CLIENT-002",
-		Name:      "Client Two",
-		Email:     "client2@example.com",
-		CreatedAt: now,
-		UpdatedAt: now,
-	}
-
-	invoices := []*models.Invoice{
-		{
-			ID:        testInvoiceID001,
-			Number:    testInvoiceNum,
-			Client:    client1,
-			Version:   1,
-			Date:      now.AddDate(0, -2, 0),
-			DueDate:   now.AddDate(0, -1, 0),
-			Status:    models.StatusPaid,
-			Total:     1000.0,
-			CreatedAt: now,
-			UpdatedAt: now,
-		},
-		{
-			ID:        "INV-002",
-			Number:    "INV-2024-002",
-			Client:    client1,
-			Version:   1,
-			Date:      now.AddDate(0, -1, 0),
-			DueDate:   now,
-			Status:    models.StatusSent,
-			Total:     2000.0,
-			CreatedAt: now,
-			UpdatedAt: now,
-		},
-		{
-			ID:        "INV-003",
-			Number:    "INV-2024-003",
-			Client:    client2,
-			Version:   1,
-			Date:      now,
-			DueDate:   now.AddDate(0, 1, 0),
-			Status:    models.StatusDraft,
-			Total:     3000.0,
-			CreatedAt: now,
-			UpdatedAt: now,
-		},
-	}
+	require.NoError(t, suite.storage.CreateInvoice(suite.ctx, invoice))
 
-	// Save invoices
-	for _, inv := range invoices {
-		err = suite.storage.CreateInvoice(suite.ctx, inv)
-		require.NoError(t, err)
-	}
-
-	// Test list all
-	result, err = suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{})
-	require.NoError(t, err)
-	assert.Len(t, result.Invoices, 3)
-	assert.Equal(t, int64(3), result.TotalCount)
-	assert.False(t, result.HasMore)
-
-	// Verify default sorting (date descending)
-	assert.Equal(t, models.InvoiceID("INV-003"), result.Invoices[0].ID)
-	assert.Equal(t, models.InvoiceID("INV-002"), result.Invoices[1].ID)
-	assert.Equal(t, models.InvoiceID(testInvoiceID001), result.Invoices[2].ID)
-
-	// Test filter by status
-	result, err = suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{
-		Status: models.StatusPaid,
-	})
-	require.NoError(t, err)
-	assert.Len(t, result.Invoices, 1)
-	assert.Equal(t, models.InvoiceID(testInvoiceID001), result.Invoices[0].ID)
-
-	// Test filter by client
-	result, err = suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{
-		ClientID: testClientID001,
-	})
-	require.NoError(t, err)
-	assert.Len(t, result.Invoices, 2)
+	// A stray corrupted file is skipped, and reported
+	corruptPath := filepath.Join(suite.tempDir, "invoices", "CORRUPT.json")
+	require.NoError(t, os.WriteFile(corruptPath, []byte("invalid json"), 0o600))
 
-	// Test date range filter
-	result, err = suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{
-		DateFrom: now.AddDate(0, -2, 0),
-		DateTo:   now.AddDate(0, -1, 0),
-	})
+	result, err := suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{})
 	require.NoError(t, err)
-	assert.Len(t, result.Invoices, 2)
+	require.Len(t, result.Invoices, 1)
+	assert.Equal(t, invoice.ID, result.Invoices[0].ID)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "CORRUPT.json")
 
-	// Test amount range filter
-	result, err = suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{
-		AmountMin: 1500.0,
-		AmountMax: 2500.0,
-	})
+	// A file half written when first read is read again after a short delay
+	invoicePath := filepath.Join(suite.tempDir, "invoices", string(invoice.ID)+".json")
+	valid, err := os.ReadFile(invoicePath) //nolint:gosec // Test file path
 	require.NoError(t, err)
-	assert.Len(t, result.Invoices, 1)
-	assert.Equal(t, models.InvoiceID("INV-002"), result.Invoices[0].ID)
+	require.NoError(t, os.WriteFile(invoicePath, valid[:len(valid)/2], 0o600))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(listReadRetryDelay / 5)
+		assert.NoError(t, os.WriteFile(invoicePath, valid, 0o600))
+	}()
 
-	// Test pagination
-	result, err = suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{
-		Limit:  2,
-		Offset: 0,
-	})
-	require.NoError(t, err)
-	assert.Len(t, result.Invoices, 2)
-	assert.Equal(t, int64(3), result.TotalCount)
-	assert.True(t, result.HasMore)
-	assert.Equal(t, 2, result.NextOffset)
-
-	// Test offset
-	result, err = suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{
-		Limit:  2,
-		Offset: 2,
-	})
+	result, err = suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{})
+	<-done
 	require.NoError(t, err)
-	assert.Len(t, result.Invoices, 1)
-	assert.Equal(t, int64(3), result.TotalCount)
-	assert.False(t, result.HasMore)
+	require.Len(t, result.Invoices, 1)
+	assert.Len(t, result.Warnings, 1) // Only the stray file
 
-	// Test invalid filter
-	result, err = suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{
-		Status: "invalid-status",
-	})
-	require.Error(t, err)
-	assert.Nil(t, result)
-	var filterErr storageTypes.InvalidFilterError
-	require.ErrorAs(t, err, &filterErr)
-
-	// Test with corrupted invoice file
-	corruptPath := filepath.Join(suite.tempDir, "invoices", "CORRUPT.json")
-	err = os.WriteFile(corruptPath, []byte("invalid json"), 0o600)
-	require.NoError(t, err)
+	// Files that stay corrupted are skipped with a warning naming each of them
+	require.NoError(t, os.WriteFile(invoicePath, []byte("invalid json"), 0o600))
 
-	// Should skip corrupted files
 	result, err = suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{})
 	require.NoError(t, err)
-	assert.Len(t, result.Invoices, 3) // Only valid invoices
+	assert.Empty(t, result.Invoices)
+	require.Len(t, result.Warnings, 2)
+	assert.Contains(t, result.Warnings[0], "CORRUPT.json")
+	assert.Contains(t, result.Warnings[1], string(invoice.ID))
 }
 
-func (suite *JSONStorageTestSuite) TestCountInvoices() {
+func (suite *JSONStorageTestSuite) TestInvoiceIndex() {
 	t := suite.T()
+	require.NoError(t, suite.storage.Initialize(suite.ctx))
 
-	// Initialize storage
-	err := suite.storage.Initialize(suite.ctx)
-	require.NoError(t, err)
-
-	// Test empty count
-	count, err := suite.storage.CountInvoices(suite.ctx, models.InvoiceFilter{})
-	require.NoError(t, err)
-	assert.Equal(t, int64(0), count)
-
-	// Create test invoices
 	now := time.Now()
-	for i := 1; i <= 5; i++ {
-		invoice := &models.Invoice{
-			ID:     models.InvoiceID(fmt.Sprintf("INV-%03d", i)),
-			Number: fmt.Sprintf("INV-2024-%03d", i),
+	newInvoice := func(id string, total float64) *models.Invoice {
+		return &models.Invoice{
+			ID:     models.InvoiceID(id),
+			Number: "NUM-" + id,
 			Client: models.Client{
 				ID:        testClientID001,
 				Name:      testClientName,
@@ -932,45 +605,83 @@ func (suite *JSONStorageTestSuite) TestCountInvoices() {
 			Date:      now,
 			DueDate:   now.AddDate(0, 0, 30),
 			Status:    models.StatusDraft,
-			Total:     float64(i * 1000),
+			Total:     money.FromFloat(total),
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
+	}
 
-		if i%2 == 0 {
-			invoice.Status = models.StatusPaid
-		}
+	first := newInvoice("IDX-1", 100)
+	second := newInvoice("IDX-2", 200)
+	require.NoError(t, suite.storage.CreateInvoice(suite.ctx, first))
+	require.NoError(t, suite.storage.CreateInvoice(suite.ctx, second))
 
-		err = suite.storage.CreateInvoice(suite.ctx, invoice)
+	indexPath := filepath.Join(suite.tempDir, "index", "invoices.json")
+	readIndex := func() map[models.InvoiceID]storageTypes.InvoiceIndexEntry {
+		data, err := os.ReadFile(indexPath) // #nosec G304 -- Test file path is controlled
 		require.NoError(t, err)
+		index := make(map[models.InvoiceID]storageTypes.InvoiceIndexEntry)
+		require.NoError(t, json.Unmarshal(data, &index))
+		return index
 	}
 
-	// Test count all
-	count, err = suite.storage.CountInvoices(suite.ctx, models.InvoiceFilter{})
-	require.NoError(t, err)
-	assert.Equal(t, int64(5), count)
+	suite.Run("MaintainedOnWrite", func() {
+		first.Status = models.StatusSent
+		require.NoError(t, suite.storage.UpdateInvoice(suite.ctx, first))
+		require.NoError(t, suite.storage.DeleteInvoice(suite.ctx, second.ID))
 
-	// Test count with filter
-	count, err = suite.storage.CountInvoices(suite.ctx, models.InvoiceFilter{
-		Status: models.StatusPaid,
+		index := readIndex()
+		require.Len(t, index, 1)
+		assert.Equal(t, models.StatusSent, index[first.ID].Status)
+		assert.Equal(t, 2, index[first.ID].Version)
+		assert.Equal(t, testClientName, index[first.ID].ClientName)
 	})
-	require.NoError(t, err)
-	assert.Equal(t, int64(2), count)
 
-	// Test count with amount filter
-	count, err = suite.storage.CountInvoices(suite.ctx, models.InvoiceFilter{
-		AmountMin: 3000,
+	suite.Run("ListUsesIndex", func() {
+		// Counting is answered from the index without reading invoice files
+		invoicePath := filepath.Join(suite.tempDir, "invoices", "IDX-1.json")
+		data, err := os.ReadFile(invoicePath) // #nosec G304 -- Test file path is controlled
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(invoicePath, []byte("invalid json"), 0o600))
+
+		count, err := suite.storage.CountInvoices(suite.ctx, models.InvoiceFilter{Status: models.StatusSent})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		require.NoError(t, os.WriteFile(invoicePath, data, 0o600))
 	})
-	require.NoError(t, err)
-	assert.Equal(t, int64(3), count)
 
-	// Test with context cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel()
+	suite.Run("RebuiltWhenStale", func() {
+		require.NoError(t, os.WriteFile(indexPath, []byte("{}"), 0o600))
 
-	count, err = suite.storage.CountInvoices(ctx, models.InvoiceFilter{})
-	assert.Equal(t, context.Canceled, err)
-	assert.Equal(t, int64(0), count)
+		result, err := suite.storage.ListInvoices(suite.ctx, models.InvoiceFilter{})
+		require.NoError(t, err)
+		require.Len(t, result.Invoices, 1)
+		assert.Equal(t, first.ID, result.Invoices[0].ID)
+		assert.Contains(t, readIndex(), first.ID)
+	})
+
+	suite.Run("RebuildIndexes", func() {
+		// Index entries drift when files are edited by hand
+		edited := *first
+		edited.Status = models.StatusPaid
+		data, err := json.Marshal(&edited)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(suite.tempDir, "invoices", "IDX-1.json"), data, 0o600))
+
+		count, err := suite.storage.CountInvoices(suite.ctx, models.InvoiceFilter{Status: models.StatusPaid})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+
+		invoices, clients, err := suite.storage.RebuildIndexes(suite.ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, invoices)
+		assert.Equal(t, 0, clients)
+
+		count, err = suite.storage.CountInvoices(suite.ctx, models.InvoiceFilter{Status: models.StatusPaid})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
 }
 
 func (suite *JSONStorageTestSuite) TestConcurrentAccess() {
@@ -1201,138 +912,16 @@ func (suite *JSONStorageTestSuite) TestReadJSONFileError() {
 	assert.Contains(t, err.Error(), "failed to decode JSON")
 }
 
-func (suite *JSONStorageTestSuite) TestMatchesFilter() {
-	t := suite.T()
-
-	now := time.Now()
-	invoice := &models.Invoice{
-		ID:     testInvoiceID001,
-		Number: testInvoiceNum,
-		Client: models.Client{
-			ID: testClientID001,
-		},
-		Date:    now,
-		DueDate: now.AddDate(0, 0, 30),
-		Status:  models.StatusSent,
-		Total:   1500.0,
-	}
-
-	tests := []struct {
-		name     string
-		filter   models.InvoiceFilter
-		expected bool
-	}{
-		{
-			name:     "EmptyFilter",
-			filter:   models.InvoiceFilter{},
-			expected: true,
-		},
-		{
-			name: "MatchingStatus",
-			filter: models.InvoiceFilter{
-				Status: models.StatusSent,
-			},
-			expected: true,
-		},
-		{
-			name: "NonMatchingStatus",
-			filter: models.InvoiceFilter{
-				Status: models.StatusPaid,
-			},
-			expected: false,
-		},
-		{
-			name: "MatchingClientID",
-			filter: models.InvoiceFilter{
-				ClientID: testClientID001,
-			},
-			expected: true,
-		},
-		{
-			name: "NonMatchingClientID",
-			filter: models.InvoiceFilter{
-				ClientID: "CLIENT-002",
-			},
-			expected: false,
-		},
-		{
-			name: "DateInRange",
-			filter: models.InvoiceFilter{
-				DateFrom: now.AddDate(0, 0, -1),
-				DateTo:   now.AddDate(0, 0, 1),
-			},
-			expected: true,
-		},
-		{
-			name: "DateBeforeRange",
-			filter: models.InvoiceFilter{
-				DateFrom: now.AddDate(0, 0, 1),
-			},
-			expected: false,
-		},
-		{
-			name: "DateAfterRange",
-			filter: models.InvoiceFilter{
-				DateTo: now.AddDate(0, 0, -1),
-			},
-			expected: false,
-		},
-		{
-			name: "DueDateInRange",
-			filter: models.InvoiceFilter{
-				DueDateFrom: now.AddDate(0, 0, 29),
-				DueDateTo:   now.AddDate(0, 0, 31),
-			},
-			expected: true,
-		},
-		{
-			name: "AmountInRange",
-			filter: models.InvoiceFilter{
-				AmountMin: 1000.0,
-				AmountMax: 2000.0,
-			},
-			expected: true,
-		},
-		{
-			name: "AmountBelowRange",
-			filter: models.InvoiceFilter{
-				AmountMin: 2000.0,
-			},
-			expected: false,
-		},
-		{
-			name: "AmountAboveRange",
-			filter: models.InvoiceFilter{
-				AmountMax: 1000.0,
-			},
-			expected: false,
-		},
-		{
-			name: "MultipleMatchingFilters",
-			filter: models.InvoiceFilter{
-				Status:    models.StatusSent,
-				ClientID:  testClientID001,
-				AmountMin: 1000.0,
-				AmountMax: 2000.0,
-			},
-			expected: true,
-		},
-		{
-			name: "OneNonMatchingFilter",
-			filter: models.InvoiceFilter{
-				Status:    models.StatusPaid, // Non-matching
-				ClientID:  testClientID001,
-				AmountMin: 1000.0,
-				AmountMax: 2000.0,
-			},
-			expected: false,
-		},
+func TestStorageAcceptsApplicationLoggers(t *testing.T) {
+	loggers := map[string]Logger{
+		"CLI": cli.NewLogger(false),
+		"MCP": mcp.NewLogger("error"),
 	}
 
-	for _, tt := range tests {
-		suite.Run(tt.name, func() {
-			result := suite.storage.matchesFilter(invoice, tt.filter)
-			assert.Equal(t, tt.expected, result)
+	for name, logger := range loggers {
+		t.Run(name, func(t *testing.T) {
+			s := NewJSONStorage(t.TempDir(), logger)
+			require.NoError(t, s.Initialize(t.Context()))
 		})
 	}
 }
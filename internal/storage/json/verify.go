@@ -0,0 +1,394 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// Kinds of problems reported by Verify
+const (
+	VerifyIssueCorruptFile    = "corrupt_file"    // File is not valid JSON for its type
+	VerifyIssueInvalid        = "invalid"         // File parses but fails model validation
+	VerifyIssueMissingClient  = "missing_client"  // Invoice references a client with no client file
+	VerifyIssueTotalMismatch  = "total_mismatch"  // Stored total differs from the recalculated total
+	VerifyIssueCorruptIndex   = "corrupt_index"   // Index file cannot be read
+	VerifyIssueOrphanedIndex  = "orphaned_index"  // Index entry has no data file
+	VerifyIssueUnindexedEntry = "unindexed_entry" // Data file is missing from the index
+)
+
+// corruptDirName is the directory under the storage path that Verify moves unparseable files into
+const corruptDirName = "corrupt"
+
+// VerifyOptions configures a storage verification
+type VerifyOptions struct {
+	// Repair rebuilds the indexes, recalculates mismatched totals on unpaid invoices and
+	// moves unparseable files into corrupt/
+	Repair bool
+}
+
+// VerifyIssue is a single problem found by Verify
+type VerifyIssue struct {
+	Kind     string `json:"kind"`
+	Resource string `json:"resource"` // "invoice", "client" or "index"
+	ID       string `json:"id"`
+	Message  string `json:"message"`
+	Fixed    bool   `json:"fixed"`
+	Repair   string `json:"repair,omitempty"` // What the repair did, when Fixed
+}
+
+// VerifyReport is the outcome of Verify
+type VerifyReport struct {
+	InvoicesChecked int           `json:"invoices_checked"`
+	ClientsChecked  int           `json:"clients_checked"`
+	Issues          []VerifyIssue `json:"issues"`
+	Repair          bool          `json:"repair"`
+}
+
+// FixedCount returns the number of issues the repair resolved
+func (r *VerifyReport) FixedCount() int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Fixed {
+			count++
+		}
+	}
+	return count
+}
+
+// Unresolved returns the issues still present after the run
+func (r *VerifyReport) Unresolved() []VerifyIssue {
+	var unresolved []VerifyIssue
+	for _, issue := range r.Issues {
+		if !issue.Fixed {
+			unresolved = append(unresolved, issue)
+		}
+	}
+	return unresolved
+}
+
+// Verify checks every invoice and client file and their consistency with each other and
+// with the indexes: files must parse and validate, invoices must reference an existing
+// client and carry the totals RecalculateTotals produces, and the indexes must list
+// exactly the data files.
+//
+// With options.Repair, unparseable files are moved into corrupt/, mismatched totals are
+// recalculated on invoices that are not paid, and the indexes are rebuilt. Invalid files and
+// missing clients need a person to decide and are only reported.
+func (s *JSONStorage) Verify(ctx context.Context, options VerifyOptions) (*VerifyReport, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	var unlock func()
+	var err error
+	if options.Repair {
+		unlock, err = s.lock(ctx)
+	} else {
+		unlock, err = s.rlock(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	report := &VerifyReport{Repair: options.Repair}
+
+	clientIDs, err := s.verifyClientFilesUnsafe(ctx, report, options.Repair)
+	if err != nil {
+		return nil, err
+	}
+
+	invoiceIDs, err := s.verifyInvoiceFilesUnsafe(ctx, report, clientIDs, options.Repair)
+	if err != nil {
+		return nil, err
+	}
+
+	indexIssues := s.verifyIndexesUnsafe(ctx, report, invoiceIDs, clientIDs)
+
+	// Quarantined files and recalculated totals also leave the indexes stale
+	if options.Repair && (report.FixedCount() > 0 || len(indexIssues) > 0) {
+		if err := s.rebuildIndexesUnsafe(ctx); err != nil {
+			return report, err
+		}
+		for _, i := range indexIssues {
+			report.Issues[i].Fixed = true
+			report.Issues[i].Repair = "index rebuilt"
+		}
+	}
+
+	if report.FixedCount() > 0 {
+		s.recordMutation()
+	}
+
+	s.logger.Info("storage verified", "invoices", report.InvoicesChecked, "clients", report.ClientsChecked,
+		"issues", len(report.Issues), "fixed", report.FixedCount(), "repair", options.Repair)
+	return report, nil
+}
+
+// verifyClientFilesUnsafe checks the client files and returns the IDs of the clients that
+// could be read. Callers must hold s.mu.
+func (s *JSONStorage) verifyClientFilesUnsafe(ctx context.Context, report *VerifyReport, repair bool) (map[models.ClientID]bool, error) {
+	clientFiles, err := filepath.Glob(filepath.Join(s.clientsDir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list client files: %w", err)
+	}
+
+	clientIDs := make(map[models.ClientID]bool, len(clientFiles))
+	for _, filePath := range clientFiles {
+		report.ClientsChecked++
+
+		var client models.Client
+		if err := s.readJSONFile(ctx, filePath, &client); err != nil {
+			report.Issues = append(report.Issues, s.corruptFileIssue("client", filePath, err, repair))
+			continue
+		}
+
+		clientIDs[client.ID] = true
+		if err := client.Validate(ctx); err != nil {
+			report.Issues = append(report.Issues, VerifyIssue{
+				Kind: VerifyIssueInvalid, Resource: "client", ID: string(client.ID), Message: err.Error(),
+			})
+		}
+	}
+
+	return clientIDs, nil
+}
+
+// verifyInvoiceFilesUnsafe checks the invoice files and returns the IDs of the invoices that
+// could be read. Callers must hold s.mu, and the write lock when repairing.
+func (s *JSONStorage) verifyInvoiceFilesUnsafe(ctx context.Context, report *VerifyReport,
+	clientIDs map[models.ClientID]bool, repair bool,
+) (map[models.InvoiceID]bool, error) {
+	invoiceFiles, err := filepath.Glob(filepath.Join(s.invoicesDir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoice files: %w", err)
+	}
+
+	invoiceIDs := make(map[models.InvoiceID]bool, len(invoiceFiles))
+	for _, filePath := range invoiceFiles {
+		report.InvoicesChecked++
+
+		var invoice models.Invoice
+		if err := s.readInvoiceFile(ctx, filePath, &invoice); err != nil {
+			// A file from a newer go-invoice is valid data this build cannot check; never quarantine it
+			if errors.Is(err, ErrInvoiceSchemaUnsupported) {
+				invoiceIDs[invoice.ID] = true
+				report.Issues = append(report.Issues, VerifyIssue{
+					Kind: VerifyIssueInvalid, Resource: "invoice", ID: string(invoice.ID), Message: err.Error(),
+				})
+				continue
+			}
+			report.Issues = append(report.Issues, s.corruptFileIssue("invoice", filePath, err, repair))
+			continue
+		}
+
+		invoiceIDs[invoice.ID] = true
+		if err := invoice.Validate(ctx); err != nil {
+			report.Issues = append(report.Issues, VerifyIssue{
+				Kind: VerifyIssueInvalid, Resource: "invoice", ID: string(invoice.ID), Message: err.Error(),
+			})
+		}
+
+		if !clientIDs[invoice.Client.ID] {
+			report.Issues = append(report.Issues, VerifyIssue{
+				Kind: VerifyIssueMissingClient, Resource: "invoice", ID: string(invoice.ID),
+				Message: fmt.Sprintf("client %s does not exist", invoice.Client.ID),
+			})
+		}
+
+		issue, err := s.verifyInvoiceTotalUnsafe(ctx, &invoice, repair)
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil {
+			report.Issues = append(report.Issues, *issue)
+		}
+	}
+
+	return invoiceIDs, nil
+}
+
+// verifyInvoiceTotalUnsafe compares the stored total with a fresh calculation, saving the
+// recalculated invoice when repairing one that is not paid. Callers must hold s.mu, and the
+// write lock when repairing.
+func (s *JSONStorage) verifyInvoiceTotalUnsafe(ctx context.Context, invoice *models.Invoice, repair bool) (*VerifyIssue, error) {
+	recalculated := *invoice
+	if err := recalculated.RecalculateTotals(ctx); err != nil {
+		return nil, fmt.Errorf("failed to recalculate totals for invoice %s: %w", invoice.ID, err)
+	}
+	if recalculated.Total == invoice.Total {
+		return nil, nil //nolint:nilnil // No mismatch is not an error
+	}
+
+	issue := &VerifyIssue{
+		Kind:     VerifyIssueTotalMismatch,
+		Resource: "invoice",
+		ID:       string(invoice.ID),
+		Message:  fmt.Sprintf("stored total %s, recalculated %s", invoice.Total, recalculated.Total),
+	}
+
+	switch {
+	case !repair:
+	case invoice.Status == models.StatusPaid:
+		issue.Message += " (paid invoices are not recalculated)"
+	default:
+		recalculated.Version++
+		recalculated.UpdatedAt = time.Now()
+		if err := s.writeJSONFile(ctx, s.getInvoicePath(invoice.ID), &recalculated); err != nil {
+			return nil, fmt.Errorf("failed to save recalculated invoice %s: %w", invoice.ID, err)
+		}
+		issue.Fixed = true
+		issue.Repair = fmt.Sprintf("total recalculated to %s", recalculated.Total)
+	}
+
+	return issue, nil
+}
+
+// corruptFileIssue reports an unparseable file, moving it into corrupt/ when repairing.
+// Callers must hold the write lock when repairing.
+func (s *JSONStorage) corruptFileIssue(resource, filePath string, readErr error, repair bool) VerifyIssue {
+	issue := VerifyIssue{
+		Kind:     VerifyIssueCorruptFile,
+		Resource: resource,
+		ID:       strings.TrimSuffix(filepath.Base(filePath), ".json"),
+		Message:  readErr.Error(),
+	}
+	if !repair {
+		return issue
+	}
+
+	quarantined, err := s.quarantineFile(filePath)
+	if err != nil {
+		s.logger.Error("failed to quarantine corrupt file", "file", filePath, "error", err)
+		issue.Message += fmt.Sprintf(" (could not be moved: %v)", err)
+		return issue
+	}
+
+	issue.Fixed = true
+	issue.Repair = "moved to " + quarantined
+	return issue
+}
+
+// quarantineFile moves a file into corrupt/<data dir>/ under the storage path, keeping
+// earlier quarantined copies, and returns its new path
+func (s *JSONStorage) quarantineFile(filePath string) (string, error) {
+	dir := filepath.Join(s.basePath, corruptDirName, filepath.Base(filepath.Dir(filePath)))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	target := filepath.Join(dir, filepath.Base(filePath))
+	if _, err := os.Stat(target); err == nil {
+		target = fmt.Sprintf("%s.%d", target, time.Now().UnixNano())
+	}
+
+	if err := os.Rename(filePath, target); err != nil {
+		return "", fmt.Errorf("failed to move %s: %w", filePath, err)
+	}
+	return target, nil
+}
+
+// verifyIndexesUnsafe compares the indexes with the data files that could be read and
+// returns the positions in report.Issues of the index issues it added. Callers must hold s.mu.
+func (s *JSONStorage) verifyIndexesUnsafe(ctx context.Context, report *VerifyReport,
+	invoiceIDs map[models.InvoiceID]bool, clientIDs map[models.ClientID]bool,
+) []int {
+	var added []int
+	addIssue := func(issue VerifyIssue) {
+		added = append(added, len(report.Issues))
+		report.Issues = append(report.Issues, issue)
+	}
+
+	invIndex := make(invoiceIndex)
+	if err := s.readJSONFile(ctx, s.invoiceIndexPath(), &invIndex); err != nil {
+		addIssue(VerifyIssue{Kind: VerifyIssueCorruptIndex, Resource: "index", ID: "invoices", Message: err.Error()})
+	} else {
+		indexed := make(map[string]bool, len(invIndex))
+		for id := range invIndex {
+			indexed[string(id)] = true
+		}
+		fileIDs := make(map[string]bool, len(invoiceIDs))
+		for id := range invoiceIDs {
+			fileIDs[string(id)] = true
+		}
+		for _, issue := range compareIndex("invoice", indexed, fileIDs) {
+			addIssue(issue)
+		}
+	}
+
+	cliIndex := make(clientIndex)
+	if err := s.readJSONFile(ctx, s.clientIndexPath(), &cliIndex); err != nil {
+		addIssue(VerifyIssue{Kind: VerifyIssueCorruptIndex, Resource: "index", ID: "clients", Message: err.Error()})
+	} else {
+		indexed := make(map[string]bool, len(cliIndex))
+		for id := range cliIndex {
+			indexed[string(id)] = true
+		}
+		fileIDs := make(map[string]bool, len(clientIDs))
+		for id := range clientIDs {
+			fileIDs[string(id)] = true
+		}
+		for _, issue := range compareIndex("client", indexed, fileIDs) {
+			addIssue(issue)
+		}
+	}
+
+	return added
+}
+
+// compareIndex reports index entries without a file and files without an index entry,
+// sorted by ID
+func compareIndex(resource string, indexed, files map[string]bool) []VerifyIssue {
+	var issues []VerifyIssue
+	for id := range indexed {
+		if !files[id] {
+			issues = append(issues, VerifyIssue{
+				Kind: VerifyIssueOrphanedIndex, Resource: "index", ID: id,
+				Message: fmt.Sprintf("indexed %s has no readable file", resource),
+			})
+		}
+	}
+	for id := range files {
+		if !indexed[id] {
+			issues = append(issues, VerifyIssue{
+				Kind: VerifyIssueUnindexedEntry, Resource: "index", ID: id,
+				Message: fmt.Sprintf("%s file is missing from the index", resource),
+			})
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].ID != issues[j].ID {
+			return issues[i].ID < issues[j].ID
+		}
+		return issues[i].Kind < issues[j].Kind
+	})
+	return issues
+}
+
+// rebuildIndexesUnsafe regenerates and saves both indexes. Callers must hold the write lock.
+func (s *JSONStorage) rebuildIndexesUnsafe(ctx context.Context) error {
+	invIndex, _, err := s.buildInvoiceIndexUnsafe(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.writeInvoiceIndexUnsafe(ctx, invIndex); err != nil {
+		return err
+	}
+
+	cliIndex, err := s.buildClientIndexUnsafe(ctx)
+	if err != nil {
+		return err
+	}
+	return s.writeClientIndexUnsafe(ctx, cliIndex)
+}
@@ -0,0 +1,132 @@
+package json
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+)
+
+// newVerifyTestInvoice returns a valid invoice for testClientID001 with a stored total of total
+func newVerifyTestInvoice(id, status string, total money.Amount) *models.Invoice {
+	return &models.Invoice{
+		ID:     models.InvoiceID(id),
+		Number: id,
+		Client: models.Client{
+			ID:        testClientID001,
+			Name:      testClientName,
+			Email:     testClientEmail,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		WorkItems: []models.WorkItem{{
+			ID: "work-1", Date: time.Now(), Hours: 2, Rate: 50, Description: "Consulting", Total: 100, CreatedAt: time.Now(),
+		}},
+		Subtotal:  total,
+		Total:     total,
+		Version:   1,
+		Date:      time.Now(),
+		DueDate:   time.Now().AddDate(0, 0, 30),
+		Status:    status,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// issueKinds returns the kind of each issue keyed by ID
+func issueKinds(issues []VerifyIssue) map[string][]string {
+	kinds := make(map[string][]string)
+	for _, issue := range issues {
+		kinds[issue.ID] = append(kinds[issue.ID], issue.Kind)
+	}
+	return kinds
+}
+
+func TestVerifyCleanStorage(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+	require.NoError(t, s.CreateInvoice(ctx, newVerifyTestInvoice("INV-001", models.StatusDraft, money.FromFloat(100))))
+
+	report, err := s.Verify(ctx, VerifyOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.InvoicesChecked)
+	assert.Equal(t, 1, report.ClientsChecked)
+	assert.Empty(t, report.Issues)
+}
+
+func TestVerifyReportsAndRepairsIssues(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+
+	require.NoError(t, s.CreateInvoice(ctx, newVerifyTestInvoice("INV-DRAFT", models.StatusDraft, money.FromFloat(80))))
+	require.NoError(t, s.CreateInvoice(ctx, newVerifyTestInvoice("INV-PAID", models.StatusPaid, money.FromFloat(80))))
+	orphan := newVerifyTestInvoice("INV-ORPHAN", models.StatusDraft, money.FromFloat(100))
+	orphan.Client.ID = "CLIENT-GONE"
+	require.NoError(t, s.CreateInvoice(ctx, orphan))
+	require.NoError(t, s.CreateInvoice(ctx, newVerifyTestInvoice("INV-REMOVED", models.StatusDraft, money.FromFloat(100))))
+
+	// Remove a file behind the index's back and leave an unparseable one
+	require.NoError(t, os.Remove(s.getInvoicePath("INV-REMOVED")))
+	require.NoError(t, os.WriteFile(s.getInvoicePath("INV-BROKEN"), []byte("{not json"), 0o600))
+
+	report, err := s.Verify(ctx, VerifyOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.InvoicesChecked)
+	assert.Zero(t, report.FixedCount())
+	kinds := issueKinds(report.Issues)
+	assert.Equal(t, []string{VerifyIssueTotalMismatch}, kinds["INV-DRAFT"])
+	assert.Equal(t, []string{VerifyIssueTotalMismatch}, kinds["INV-PAID"])
+	assert.Equal(t, []string{VerifyIssueMissingClient}, kinds["INV-ORPHAN"])
+	assert.Equal(t, []string{VerifyIssueOrphanedIndex}, kinds["INV-REMOVED"])
+	assert.Equal(t, []string{VerifyIssueCorruptFile}, kinds["INV-BROKEN"])
+
+	report, err = s.Verify(ctx, VerifyOptions{Repair: true})
+	require.NoError(t, err)
+
+	unresolved := issueKinds(report.Unresolved())
+	assert.Equal(t, map[string][]string{
+		"INV-PAID":   {VerifyIssueTotalMismatch},
+		"INV-ORPHAN": {VerifyIssueMissingClient},
+	}, unresolved)
+
+	// The draft total was recalculated, the paid one left alone
+	draft, err := s.GetInvoice(ctx, "INV-DRAFT")
+	require.NoError(t, err)
+	assert.Equal(t, money.FromFloat(100), draft.Total)
+	assert.Equal(t, 2, draft.Version)
+	paid, err := s.GetInvoice(ctx, "INV-PAID")
+	require.NoError(t, err)
+	assert.Equal(t, money.FromFloat(80), paid.Total)
+
+	// The unparseable file was quarantined
+	assert.NoFileExists(t, s.getInvoicePath("INV-BROKEN"))
+	assert.FileExists(t, filepath.Join(s.basePath, corruptDirName, "invoices", "INV-BROKEN.json"))
+
+	// Only the issues needing a decision remain
+	report, err = s.Verify(ctx, VerifyOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, unresolved, issueKinds(report.Issues))
+}
+
+func TestVerifyReportsCorruptIndex(t *testing.T) {
+	ctx := context.Background()
+	s := newBackupTestStorage(t)
+	require.NoError(t, os.WriteFile(s.invoiceIndexPath(), []byte("["), 0o600))
+
+	report, err := s.Verify(ctx, VerifyOptions{})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, VerifyIssueCorruptIndex, report.Issues[0].Kind)
+
+	report, err = s.Verify(ctx, VerifyOptions{Repair: true})
+	require.NoError(t, err)
+	assert.Empty(t, report.Unresolved())
+	assert.Equal(t, 1, report.FixedCount())
+}
@@ -0,0 +1,278 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// Client storage errors
+var (
+	ErrClientCannotBeNil     = fmt.Errorf("client cannot be nil")
+	ErrClientIDCannotBeEmpty = fmt.Errorf("client ID cannot be empty")
+	ErrEmailCannotBeEmpty    = fmt.Errorf("email cannot be empty")
+)
+
+// CreateClient stores a copy of a new client
+func (s *MemoryStorage) CreateClient(ctx context.Context, client *models.Client) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if client == nil {
+		return ErrClientCannotBeNil
+	}
+
+	// Validate client
+	if err := client.Validate(ctx); err != nil {
+		return fmt.Errorf("invalid client: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.clients[client.ID]; exists {
+		return storage.NewConflictError("client", string(client.ID), "")
+	}
+
+	stored, err := clone(client)
+	if err != nil {
+		return fmt.Errorf("failed to store client: %w", err)
+	}
+	s.clients[client.ID] = stored
+
+	s.logger.Info("client created", "id", client.ID, "name", client.Name)
+	return nil
+}
+
+// GetClient returns a copy of a client by ID
+func (s *MemoryStorage) GetClient(ctx context.Context, id models.ClientID) (*models.Client, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if strings.TrimSpace(string(id)) == "" {
+		return nil, ErrClientIDCannotBeEmpty
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	client, exists := s.clients[id]
+	if !exists {
+		return nil, storage.NewNotFoundError("client", string(id))
+	}
+
+	return clone(client)
+}
+
+// UpdateClient updates an existing client, setting the caller's UpdatedAt
+func (s *MemoryStorage) UpdateClient(ctx context.Context, client *models.Client) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if client == nil {
+		return ErrClientCannotBeNil
+	}
+
+	// Validate client
+	if err := client.Validate(ctx); err != nil {
+		return fmt.Errorf("invalid client: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.clients[client.ID]; !exists {
+		return storage.NewNotFoundError("client", string(client.ID))
+	}
+
+	client.UpdatedAt = time.Now()
+
+	stored, err := clone(client)
+	if err != nil {
+		return fmt.Errorf("failed to store client: %w", err)
+	}
+	s.clients[client.ID] = stored
+
+	s.logger.Info("client updated", "id", client.ID, "name", client.Name)
+	return nil
+}
+
+// DeleteClient removes a client by ID (soft delete - marks as inactive)
+func (s *MemoryStorage) DeleteClient(ctx context.Context, id models.ClientID) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if strings.TrimSpace(string(id)) == "" {
+		return ErrClientIDCannotBeEmpty
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, exists := s.clients[id]
+	if !exists {
+		return storage.NewNotFoundError("client", string(id))
+	}
+
+	// Soft delete - mark as inactive
+	client.Active = false
+	client.UpdatedAt = time.Now()
+
+	s.logger.Info("client deleted (soft)", "id", id)
+	return nil
+}
+
+// HardDeleteClient completely removes a client from storage
+func (s *MemoryStorage) HardDeleteClient(ctx context.Context, id models.ClientID) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if strings.TrimSpace(string(id)) == "" {
+		return ErrClientIDCannotBeEmpty
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.clients[id]; !exists {
+		return storage.NewNotFoundError("client", string(id))
+	}
+	delete(s.clients, id)
+
+	s.logger.Info("client hard deleted", "id", id)
+	return nil
+}
+
+// ListClients retrieves copies of the clients sorted by name, with pagination.
+// A limit of zero or less returns every client from the offset on.
+func (s *MemoryStorage) ListClients(ctx context.Context, activeOnly bool, limit, offset int) (*storage.ClientListResult, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	allClients := make([]*models.Client, 0, len(s.clients))
+	for _, client := range s.clients {
+		// Filter by active status if requested
+		if activeOnly && !client.Active {
+			continue
+		}
+
+		copied, err := clone(client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy client %s: %w", client.ID, err)
+		}
+		allClients = append(allClients, copied)
+	}
+
+	// Sort clients by name, then ID so equal names page deterministically
+	sort.Slice(allClients, func(i, j int) bool {
+		a, b := strings.ToLower(allClients[i].Name), strings.ToLower(allClients[j].Name)
+		if a != b {
+			return a < b
+		}
+		return allClients[i].ID < allClients[j].ID
+	})
+
+	// Apply pagination
+	start := min(offset, len(allClients))
+	end := start + limit
+	if limit <= 0 || end > len(allClients) {
+		end = len(allClients)
+	}
+
+	result := &storage.ClientListResult{
+		Clients:    allClients[start:end],
+		TotalCount: int64(len(allClients)),
+		HasMore:    end < len(allClients),
+	}
+
+	if result.HasMore {
+		result.NextOffset = end
+	}
+
+	return result, nil
+}
+
+// ListClientIndex returns the indexed search fields for every client
+func (s *MemoryStorage) ListClientIndex(ctx context.Context) ([]storage.ClientIndexEntry, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]storage.ClientIndexEntry, 0, len(s.clients))
+	for _, client := range s.clients {
+		entries = append(entries, storage.NewClientIndexEntry(client))
+	}
+
+	return entries, nil
+}
+
+// FindClientByEmail finds a client by email address, ignoring case
+func (s *MemoryStorage) FindClientByEmail(ctx context.Context, email string) (*models.Client, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if strings.TrimSpace(email) == "" {
+		return nil, ErrEmailCannotBeEmpty
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	for _, client := range s.clients {
+		if strings.ToLower(client.Email) == email {
+			return clone(client)
+		}
+	}
+
+	return nil, storage.NewNotFoundError("client", fmt.Sprintf("email:%s", email))
+}
+
+// ExistsClient checks if a client exists
+func (s *MemoryStorage) ExistsClient(ctx context.Context, id models.ClientID) (bool, error) {
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, exists := s.clients[id]
+	return exists, nil
+}
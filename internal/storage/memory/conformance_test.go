@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/mrz1836/go-invoice/internal/storage/storagetest"
+)
+
+// nopLogger discards storage log messages
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
+func (nopLogger) Debug(string, ...any) {}
+
+func TestMemoryStorageConformance(t *testing.T) {
+	storagetest.RunConformance(t, func(_ *testing.T) storagetest.Backend {
+		return NewMemoryStorage(nopLogger{})
+	})
+}
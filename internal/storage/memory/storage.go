@@ -0,0 +1,354 @@
+// Package memory provides an in-memory storage implementation for the invoice system.
+// Nothing is persisted, which makes it suited to tests, demos and dry runs.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// Invoice storage errors
+var (
+	ErrInvoiceCannotBeNil     = fmt.Errorf("invoice cannot be nil")
+	ErrInvoiceIDCannotBeEmpty = fmt.Errorf("invoice ID cannot be empty")
+)
+
+// MemoryStorage keeps invoices and clients in maps guarded by a read/write mutex. It follows
+// the JSON storage semantics: optimistic locking on invoices, soft-deleted clients and the
+// same invoice filtering, sorting and paging.
+type MemoryStorage struct {
+	mu          sync.RWMutex
+	invoices    map[models.InvoiceID]*models.Invoice
+	clients     map[models.ClientID]*models.Client
+	initialized bool
+	logger      Logger
+}
+
+// Logger interface for storage operations, satisfied by both cli.SimpleLogger and the
+// MCP server logger from mcp.NewLogger
+type Logger interface {
+	Info(msg string, fields ...any)
+	Error(msg string, fields ...any)
+	Debug(msg string, fields ...any)
+}
+
+// NewMemoryStorage creates a new, empty in-memory storage instance
+func NewMemoryStorage(logger Logger) *MemoryStorage {
+	return &MemoryStorage{
+		invoices: make(map[models.InvoiceID]*models.Invoice),
+		clients:  make(map[models.ClientID]*models.Client),
+		logger:   logger,
+	}
+}
+
+// Initialize marks the storage ready for use; existing data is kept
+func (s *MemoryStorage) Initialize(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.initialized = true
+	s.logger.Info("in-memory storage initialized")
+	return nil
+}
+
+// IsInitialized checks if Initialize has been called
+func (s *MemoryStorage) IsInitialized(ctx context.Context) (bool, error) {
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.initialized, nil
+}
+
+// GetStorageInfo returns information about the storage system
+func (s *MemoryStorage) GetStorageInfo(ctx context.Context) (*storage.StorageInfo, error) {
+	initialized, err := s.IsInitialized(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage.StorageInfo{
+		Type:             "memory",
+		Version:          "1.0",
+		Path:             ":memory:",
+		Initialized:      initialized,
+		ReadOnly:         false,
+		SupportsBackups:  false,
+		SupportsIndexing: false,
+	}, nil
+}
+
+// Validate checks every stored invoice and client passes model validation
+func (s *MemoryStorage) Validate(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for id, invoice := range s.invoices {
+		if err := invoice.Validate(ctx); err != nil {
+			return fmt.Errorf("invoice validation failed: %w",
+				storage.NewCorruptedError("invoice", string(id), err.Error()))
+		}
+	}
+
+	for id, client := range s.clients {
+		if err := client.Validate(ctx); err != nil {
+			return fmt.Errorf("client validation failed: %w",
+				storage.NewCorruptedError("client", string(id), err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// CreateInvoice stores a copy of a new invoice
+func (s *MemoryStorage) CreateInvoice(ctx context.Context, invoice *models.Invoice) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if invoice == nil {
+		return ErrInvoiceCannotBeNil
+	}
+
+	// Validate invoice
+	if err := invoice.Validate(ctx); err != nil {
+		return fmt.Errorf("invalid invoice: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.invoices[invoice.ID]; exists {
+		return storage.NewConflictError("invoice", string(invoice.ID), "")
+	}
+	for _, existing := range s.invoices {
+		if existing.Number == invoice.Number {
+			return fmt.Errorf("%w: %s", models.ErrInvoiceNumberExists, invoice.Number)
+		}
+	}
+
+	stored, err := clone(invoice)
+	if err != nil {
+		return fmt.Errorf("failed to store invoice: %w", err)
+	}
+	s.invoices[invoice.ID] = stored
+
+	s.logger.Info("invoice created", "id", invoice.ID, "number", invoice.Number)
+	return nil
+}
+
+// GetInvoice returns a copy of an invoice by ID
+func (s *MemoryStorage) GetInvoice(ctx context.Context, id models.InvoiceID) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if strings.TrimSpace(string(id)) == "" {
+		return nil, ErrInvoiceIDCannotBeEmpty
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	invoice, exists := s.invoices[id]
+	if !exists {
+		return nil, storage.NewNotFoundError("invoice", string(id))
+	}
+
+	return clone(invoice)
+}
+
+// UpdateInvoice updates an existing invoice with optimistic locking, bumping the
+// caller's Version and UpdatedAt on success
+func (s *MemoryStorage) UpdateInvoice(ctx context.Context, invoice *models.Invoice) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if invoice == nil {
+		return ErrInvoiceCannotBeNil
+	}
+
+	// Validate invoice
+	if err := invoice.Validate(ctx); err != nil {
+		return fmt.Errorf("invalid invoice: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, exists := s.invoices[invoice.ID]
+	if !exists {
+		return storage.NewNotFoundError("invoice", string(invoice.ID))
+	}
+
+	// Check version for optimistic locking
+	if existing.Version != invoice.Version {
+		return storage.NewVersionMismatchError("invoice", string(invoice.ID),
+			invoice.Version, existing.Version)
+	}
+
+	updated := *invoice
+	updated.Version++
+	updated.UpdatedAt = time.Now()
+
+	stored, err := clone(&updated)
+	if err != nil {
+		return fmt.Errorf("failed to store invoice: %w", err)
+	}
+	s.invoices[invoice.ID] = stored
+
+	invoice.Version = updated.Version
+	invoice.UpdatedAt = updated.UpdatedAt
+
+	s.logger.Info("invoice updated", "id", invoice.ID, "version", invoice.Version)
+	return nil
+}
+
+// DeleteInvoice removes an invoice by ID
+func (s *MemoryStorage) DeleteInvoice(ctx context.Context, id models.InvoiceID) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if strings.TrimSpace(string(id)) == "" {
+		return ErrInvoiceIDCannotBeEmpty
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.invoices[id]; !exists {
+		return storage.NewNotFoundError("invoice", string(id))
+	}
+	delete(s.invoices, id)
+
+	s.logger.Info("invoice deleted", "id", id)
+	return nil
+}
+
+// ExistsInvoice checks if an invoice exists
+func (s *MemoryStorage) ExistsInvoice(ctx context.Context, id models.InvoiceID) (bool, error) {
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, exists := s.invoices[id]
+	return exists, nil
+}
+
+// ListInvoices retrieves copies of the invoices matching filter, sorted and paged
+func (s *MemoryStorage) ListInvoices(ctx context.Context, filter models.InvoiceFilter) (*storage.InvoiceListResult, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	// Validate filter
+	if err := filter.Validate(ctx); err != nil {
+		return nil, storage.NewInvalidFilterError("filter", filter, err.Error())
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := s.queryInvoices(filter)
+	start, end := storage.PageBounds(len(matches), filter)
+
+	invoices := make([]*models.Invoice, 0, end-start)
+	for _, entry := range matches[start:end] {
+		invoice, err := clone(s.invoices[entry.ID])
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy invoice %s: %w", entry.ID, err)
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	return storage.NewInvoiceListResult(invoices, len(matches), end, filter), nil
+}
+
+// CountInvoices returns the total count of invoices matching the filter
+func (s *MemoryStorage) CountInvoices(ctx context.Context, filter models.InvoiceFilter) (int64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+
+	countFilter := storage.CountFilter(filter)
+	if err := countFilter.Validate(ctx); err != nil {
+		return 0, storage.NewInvalidFilterError("filter", countFilter, err.Error())
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.queryInvoices(countFilter))), nil
+}
+
+// queryInvoices returns the index entries of the invoices matching filter in the requested
+// order. The caller must hold the lock.
+func (s *MemoryStorage) queryInvoices(filter models.InvoiceFilter) []storage.InvoiceIndexEntry {
+	matches := make([]storage.InvoiceIndexEntry, 0, len(s.invoices))
+	for _, invoice := range s.invoices {
+		if entry := storage.NewInvoiceIndexEntry(invoice); storage.MatchesInvoiceFilter(entry, filter) {
+			matches = append(matches, entry)
+		}
+	}
+
+	storage.SortInvoiceEntries(matches, filter)
+	return matches
+}
+
+// clone deep copies a record through its JSON encoding, the same form the JSON backend
+// stores, so callers never share memory with the stored copy
+func clone[T any](record *T) (*T, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied T
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
@@ -0,0 +1,360 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// Client storage errors
+var (
+	ErrClientCannotBeNil     = fmt.Errorf("client cannot be nil")
+	ErrClientIDCannotBeEmpty = fmt.Errorf("client ID cannot be empty")
+	ErrEmailCannotBeEmpty    = fmt.Errorf("email cannot be empty")
+)
+
+// CreateClient stores a new client
+func (s *SQLiteStorage) CreateClient(ctx context.Context, client *models.Client) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if client == nil {
+		return ErrClientCannotBeNil
+	}
+
+	// Validate client
+	if err := client.Validate(ctx); err != nil {
+		return fmt.Errorf("invalid client: %w", err)
+	}
+
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	created, err := insertClient(ctx, db, client)
+	if err != nil {
+		return fmt.Errorf("failed to write client: %w", err)
+	}
+	if !created {
+		return storage.NewConflictError("client", string(client.ID), "")
+	}
+
+	s.logger.Info("client created", "id", client.ID, "name", client.Name)
+	return nil
+}
+
+// insertClient inserts client unless its ID is already stored, reporting whether it did
+func insertClient(ctx context.Context, db *sql.DB, client *models.Client) (bool, error) {
+	data, err := json.Marshal(client)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := db.ExecContext(ctx, `
+		INSERT INTO clients (id, name_lower, email_lower, active, data) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO NOTHING`,
+		string(client.ID), strings.ToLower(client.Name), strings.ToLower(client.Email), client.Active, data)
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	return inserted == 1, err
+}
+
+// GetClient retrieves a client by ID
+func (s *SQLiteStorage) GetClient(ctx context.Context, id models.ClientID) (*models.Client, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if strings.TrimSpace(string(id)) == "" {
+		return nil, ErrClientIDCannotBeEmpty
+	}
+
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return queryClient(ctx, db, string(id), `SELECT data FROM clients WHERE id = ?`, string(id))
+}
+
+// queryClient decodes the client returned by query, or returns a NotFoundError for id
+func queryClient(ctx context.Context, db *sql.DB, id, query string, args ...any) (*models.Client, error) {
+	var data []byte
+	err := db.QueryRowContext(ctx, query, args...).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.NewNotFoundError("client", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client: %w", err)
+	}
+
+	var client models.Client
+	if err := json.Unmarshal(data, &client); err != nil {
+		return nil, storage.NewCorruptedError("client", id, err.Error())
+	}
+	return &client, nil
+}
+
+// UpdateClient updates an existing client
+func (s *SQLiteStorage) UpdateClient(ctx context.Context, client *models.Client) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if client == nil {
+		return ErrClientCannotBeNil
+	}
+
+	// Validate client
+	if err := client.Validate(ctx); err != nil {
+		return fmt.Errorf("invalid client: %w", err)
+	}
+
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	// Update timestamp
+	updated := *client
+	updated.UpdatedAt = time.Now()
+
+	if err := s.writeClient(ctx, db, &updated); err != nil {
+		return err
+	}
+	client.UpdatedAt = updated.UpdatedAt
+
+	s.logger.Info("client updated", "id", client.ID, "name", client.Name)
+	return nil
+}
+
+// writeClient replaces a stored client, returning a NotFoundError when it does not exist
+func (s *SQLiteStorage) writeClient(ctx context.Context, db *sql.DB, client *models.Client) error {
+	data, err := json.Marshal(client)
+	if err != nil {
+		return fmt.Errorf("failed to encode client: %w", err)
+	}
+
+	result, err := db.ExecContext(ctx,
+		`UPDATE clients SET name_lower = ?, email_lower = ?, active = ?, data = ? WHERE id = ?`,
+		strings.ToLower(client.Name), strings.ToLower(client.Email), client.Active, data, string(client.ID))
+	if err != nil {
+		return fmt.Errorf("failed to write client: %w", err)
+	}
+	if changed, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to write client: %w", err)
+	} else if changed == 0 {
+		return storage.NewNotFoundError("client", string(client.ID))
+	}
+	return nil
+}
+
+// DeleteClient removes a client by ID (soft delete - marks as inactive)
+func (s *SQLiteStorage) DeleteClient(ctx context.Context, id models.ClientID) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if strings.TrimSpace(string(id)) == "" {
+		return ErrClientIDCannotBeEmpty
+	}
+
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	client, err := queryClient(ctx, db, string(id), `SELECT data FROM clients WHERE id = ?`, string(id))
+	if err != nil {
+		return err
+	}
+
+	// Soft delete - mark as inactive
+	client.Active = false
+	client.UpdatedAt = time.Now()
+
+	if err := s.writeClient(ctx, db, client); err != nil {
+		return fmt.Errorf("failed to update client for deletion: %w", err)
+	}
+
+	s.logger.Info("client deleted (soft)", "id", id)
+	return nil
+}
+
+// HardDeleteClient completely removes a client from storage
+func (s *SQLiteStorage) HardDeleteClient(ctx context.Context, id models.ClientID) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if strings.TrimSpace(string(id)) == "" {
+		return ErrClientIDCannotBeEmpty
+	}
+
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.ExecContext(ctx, `DELETE FROM clients WHERE id = ?`, string(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete client: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete client: %w", err)
+	} else if deleted == 0 {
+		return storage.NewNotFoundError("client", string(id))
+	}
+
+	s.logger.Info("client hard deleted", "id", id)
+	return nil
+}
+
+// ListClients retrieves clients sorted by name, with pagination.
+// A limit of zero or less returns every client from the offset on.
+func (s *SQLiteStorage) ListClients(ctx context.Context, activeOnly bool, limit, offset int) (*storage.ClientListResult, error) {
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	where := ""
+	if activeOnly {
+		where = " WHERE active = 1"
+	}
+
+	var total int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM clients`+where).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count clients: %w", err)
+	}
+
+	// Apply pagination
+	start := min(max(offset, 0), total)
+	end := start + limit
+	if limit <= 0 || end > total {
+		end = total
+	}
+
+	clients, err := s.queryClients(ctx, db,
+		`SELECT id, data FROM clients`+where+` ORDER BY name_lower, id LIMIT ? OFFSET ?`, end-start, start)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &storage.ClientListResult{
+		Clients:    clients,
+		TotalCount: int64(total),
+		HasMore:    end < total,
+	}
+
+	if result.HasMore {
+		result.NextOffset = end
+	}
+
+	return result, nil
+}
+
+// queryClients decodes the clients returned by query, skipping corrupted rows
+func (s *SQLiteStorage) queryClients(ctx context.Context, db *sql.DB, query string, args ...any) ([]*models.Client, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	clients := make([]*models.Client, 0)
+	for rows.Next() {
+		var (
+			id   string
+			data []byte
+		)
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("failed to list clients: %w", err)
+		}
+
+		var client models.Client
+		if err := json.Unmarshal(data, &client); err != nil {
+			s.logger.Error("failed to decode client", "client_id", id, "error", err)
+			continue // Skip corrupted rows
+		}
+		clients = append(clients, &client)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+
+	return clients, nil
+}
+
+// ListClientIndex returns the indexed search fields for every client
+func (s *SQLiteStorage) ListClientIndex(ctx context.Context) ([]storage.ClientIndexEntry, error) {
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := s.queryClients(ctx, db, `SELECT id, data FROM clients ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]storage.ClientIndexEntry, 0, len(clients))
+	for _, client := range clients {
+		entries = append(entries, storage.NewClientIndexEntry(client))
+	}
+	return entries, nil
+}
+
+// FindClientByEmail finds a client by email address, ignoring case
+func (s *SQLiteStorage) FindClientByEmail(ctx context.Context, email string) (*models.Client, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if strings.TrimSpace(email) == "" {
+		return nil, ErrEmailCannotBeEmpty
+	}
+
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	return queryClient(ctx, db, fmt.Sprintf("email:%s", email),
+		`SELECT data FROM clients WHERE email_lower = ? ORDER BY id LIMIT 1`, email)
+}
+
+// ExistsClient checks if a client exists
+func (s *SQLiteStorage) ExistsClient(ctx context.Context, id models.ClientID) (bool, error) {
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return false, err
+	}
+
+	return exists(ctx, db, `SELECT 1 FROM clients WHERE id = ?`, string(id))
+}
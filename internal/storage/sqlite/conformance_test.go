@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mrz1836/go-invoice/internal/storage/storagetest"
+)
+
+// nopLogger discards storage log messages
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
+func (nopLogger) Debug(string, ...any) {}
+
+func TestSQLiteStorageConformance(t *testing.T) {
+	storagetest.RunConformance(t, func(t *testing.T) storagetest.Backend {
+		s := NewSQLiteStorage(filepath.Join(t.TempDir(), "invoices.db"), nopLogger{})
+		t.Cleanup(func() { assert.NoError(t, s.Close()) })
+		return s
+	})
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// ImportResult summarizes an import into the SQLite storage
+type ImportResult struct {
+	InvoicesImported int `json:"invoices_imported"`
+	InvoicesSkipped  int `json:"invoices_skipped"`
+	ClientsImported  int `json:"clients_imported"`
+	ClientsSkipped   int `json:"clients_skipped"`
+}
+
+// Import copies every client and invoice, including inactive clients and deleted invoices,
+// from another store such as the JSON storage. Records are copied as stored, keeping their
+// IDs, timestamps and versions. Records whose ID already exists are skipped, so an
+// interrupted import can be run again.
+func (s *SQLiteStorage) Import(ctx context.Context, invoices storage.InvoiceStorage, clients storage.ClientStorage) (*ImportResult, error) {
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	clientList, err := clients.ListClients(ctx, false, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clients: %w", err)
+	}
+
+	invoiceList, err := invoices.ListInvoices(ctx, models.InvoiceFilter{IncludeDeleted: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read invoices: %w", err)
+	}
+
+	result := &ImportResult{}
+
+	for _, client := range clientList.Clients {
+		inserted, err := insertClient(ctx, db, client)
+		if err != nil {
+			return result, fmt.Errorf("failed to import client %s: %w", client.ID, err)
+		}
+		if !inserted {
+			result.ClientsSkipped++
+			continue
+		}
+		result.ClientsImported++
+	}
+
+	for _, invoice := range invoiceList.Invoices {
+		inserted, err := insertInvoice(ctx, db, invoice)
+		if err != nil {
+			return result, fmt.Errorf("failed to import invoice %s: %w", invoice.ID, err)
+		}
+		if !inserted {
+			result.InvoicesSkipped++
+			continue
+		}
+		result.InvoicesImported++
+	}
+
+	s.logger.Info("import completed",
+		"invoices", result.InvoicesImported, "clients", result.ClientsImported,
+		"skipped", result.InvoicesSkipped+result.ClientsSkipped)
+	return result, nil
+}
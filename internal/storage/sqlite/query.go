@@ -0,0 +1,177 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+)
+
+// invoiceRow is an invoice with the columns filters and sorts use, extracted from the same
+// fields as storage.InvoiceIndexEntry
+type invoiceRow struct {
+	id              string
+	number          string
+	clientID        string
+	clientNameLower string
+	status          string
+	poNumber        string
+	date            int64
+	dueDate         int64
+	totalCents      int64
+	updatedAt       int64
+	deleted         bool
+	version         int
+	data            []byte
+}
+
+// newInvoiceRow encodes invoice for the invoices table
+func newInvoiceRow(invoice *models.Invoice) (*invoiceRow, error) {
+	data, err := json.Marshal(invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	return &invoiceRow{
+		id:              string(invoice.ID),
+		number:          invoice.Number,
+		clientID:        string(invoice.Client.ID),
+		clientNameLower: strings.ToLower(invoice.Client.Name),
+		status:          invoice.Status,
+		poNumber:        invoice.PONumber,
+		date:            timeKey(invoice.Date),
+		dueDate:         timeKey(invoice.DueDate),
+		totalCents:      invoice.Total.Cents(),
+		updatedAt:       timeKey(invoice.UpdatedAt),
+		deleted:         invoice.DeletedAt != nil,
+		version:         invoice.Version,
+		data:            data,
+	}, nil
+}
+
+// args returns the row's values in table column order
+func (r *invoiceRow) args() []any {
+	return []any{
+		r.id, r.number, r.clientID, r.clientNameLower, r.status, r.poNumber, r.date,
+		r.dueDate, r.totalCents, r.updatedAt, r.deleted, r.version, r.data,
+	}
+}
+
+// timeKey stores t as Unix nanoseconds so columns compare like time.Time. The zero time,
+// which has no Unix nanosecond value, sorts before every other time.
+func timeKey(t time.Time) int64 {
+	if t.IsZero() {
+		return math.MinInt64
+	}
+	return t.UnixNano()
+}
+
+// invoiceWhere builds the WHERE clause matching filter with the same rules as
+// storage.MatchesInvoiceFilter
+func invoiceWhere(filter models.InvoiceFilter) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+	add := func(condition string, arg any) {
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted = 0")
+	}
+	if filter.Status != "" {
+		add("status = ?", filter.Status)
+	}
+	if filter.ClientID != "" {
+		add("client_id = ?", string(filter.ClientID))
+	}
+	if filter.PONumber != "" {
+		add("po_number = ?", filter.PONumber)
+	}
+	if !filter.DateFrom.IsZero() {
+		add("date >= ?", timeKey(filter.DateFrom))
+	}
+	if !filter.DateTo.IsZero() {
+		add("date <= ?", timeKey(filter.DateTo))
+	}
+	if !filter.DueDateFrom.IsZero() {
+		add("due_date >= ?", timeKey(filter.DueDateFrom))
+	}
+	if !filter.DueDateTo.IsZero() {
+		add("due_date <= ?", timeKey(filter.DueDateTo))
+	}
+	if filter.AmountMin > 0 {
+		add("total_cents >= ?", minCents(filter.AmountMin))
+	}
+	if filter.AmountMax > 0 {
+		add("total_cents <= ?", maxCents(filter.AmountMax))
+	}
+	if !filter.UpdatedAfter.IsZero() {
+		add("updated_at > ?", timeKey(filter.UpdatedAfter))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// minCents returns the smallest total in cents whose amount is at least value, so the
+// indexed cents column filters exactly like comparing money.Amount.Float64 with value
+func minCents(value float64) int64 {
+	cents := int64(math.Ceil(value * 100))
+	for float64(cents-1)/100 >= value {
+		cents--
+	}
+	for float64(cents)/100 < value {
+		cents++
+	}
+	return cents
+}
+
+// maxCents returns the largest total in cents whose amount is at most value
+func maxCents(value float64) int64 {
+	cents := int64(math.Floor(value * 100))
+	for float64(cents+1)/100 <= value {
+		cents++
+	}
+	for float64(cents)/100 > value {
+		cents--
+	}
+	return cents
+}
+
+// invoiceSortColumns maps the invoice sort fields to their columns
+//
+//nolint:gochecknoglobals // Read-only lookup table
+var invoiceSortColumns = map[string]string{
+	models.InvoiceSortDate:   "date",
+	models.InvoiceSortAmount: "total_cents",
+	models.InvoiceSortStatus: "status",
+	models.InvoiceSortClient: "client_name_lower",
+	models.InvoiceSortNumber: "number",
+}
+
+// invoiceOrderBy builds the ORDER BY clause with the same order as
+// storage.SortInvoiceEntries: newest first by default, ties by number then ID
+func invoiceOrderBy(filter models.InvoiceFilter) string {
+	sortBy, desc := filter.SortBy, filter.SortDesc
+	if sortBy == "" {
+		sortBy, desc = models.InvoiceSortDate, true
+	}
+
+	column, ok := invoiceSortColumns[sortBy]
+	if !ok {
+		column = "date"
+	}
+
+	direction := " ASC"
+	if desc {
+		direction = " DESC"
+	}
+	return " ORDER BY " + column + direction + ", number ASC, id ASC"
+}
@@ -0,0 +1,653 @@
+// Package sqlite provides a SQLite storage implementation for the invoice system.
+// Invoices and clients are stored as JSON documents next to indexed columns, so listing,
+// filtering and counting run as SQL queries instead of reading every record.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite" // Registers the pure Go "sqlite" database/sql driver
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+// Invoice storage errors
+var (
+	ErrInvoiceCannotBeNil     = fmt.Errorf("invoice cannot be nil")
+	ErrInvoiceIDCannotBeEmpty = fmt.Errorf("invoice ID cannot be empty")
+	ErrNotInitialized         = fmt.Errorf("sqlite storage is not initialized, run 'go-invoice init'")
+)
+
+// schemaVersion is the version of the database layout created by Initialize
+const schemaVersion = "1"
+
+// schema creates the tables and the indexes that filters and sorts push down to
+const schema = `
+CREATE TABLE IF NOT EXISTS metadata (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+	id                TEXT PRIMARY KEY,
+	number            TEXT NOT NULL,
+	client_id         TEXT NOT NULL,
+	client_name_lower TEXT NOT NULL,
+	status            TEXT NOT NULL,
+	po_number         TEXT NOT NULL DEFAULT '',
+	date              INTEGER NOT NULL,
+	due_date          INTEGER NOT NULL,
+	total_cents       INTEGER NOT NULL,
+	updated_at        INTEGER NOT NULL,
+	deleted           INTEGER NOT NULL DEFAULT 0,
+	version           INTEGER NOT NULL,
+	data              BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_invoices_status ON invoices (status);
+CREATE INDEX IF NOT EXISTS idx_invoices_client_id ON invoices (client_id);
+CREATE INDEX IF NOT EXISTS idx_invoices_date ON invoices (date);
+CREATE INDEX IF NOT EXISTS idx_invoices_total ON invoices (total_cents);
+
+CREATE TABLE IF NOT EXISTS clients (
+	id          TEXT PRIMARY KEY,
+	name_lower  TEXT NOT NULL,
+	email_lower TEXT NOT NULL,
+	active      INTEGER NOT NULL,
+	data        BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_clients_name ON clients (name_lower);
+CREATE INDEX IF NOT EXISTS idx_clients_email ON clients (email_lower);
+`
+
+// SQLiteStorage stores invoices and clients in a single SQLite database file. It follows
+// the JSON storage semantics: optimistic locking on invoices through the version column,
+// soft-deleted clients and the same invoice filtering, sorting and paging.
+type SQLiteStorage struct {
+	path   string
+	logger Logger
+
+	mu sync.Mutex // Guards db while it is opened
+	db *sql.DB
+}
+
+// Logger interface for storage operations, satisfied by both cli.SimpleLogger and the
+// MCP server logger from mcp.NewLogger
+type Logger interface {
+	Info(msg string, fields ...any)
+	Error(msg string, fields ...any)
+	Debug(msg string, fields ...any)
+}
+
+// NewSQLiteStorage creates a SQLite storage for the database file at path. The file is
+// created by Initialize; nothing is opened until the storage is first used.
+func NewSQLiteStorage(path string, logger Logger) *SQLiteStorage {
+	return &SQLiteStorage{
+		path:   path,
+		logger: logger,
+	}
+}
+
+// Path returns the database file
+func (s *SQLiteStorage) Path() string {
+	return s.path
+}
+
+// Close closes the database; the storage reopens it when used again
+func (s *SQLiteStorage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return nil
+	}
+	err := s.db.Close()
+	s.db = nil
+	return err
+}
+
+// conn returns the open database, opening it first. Unless create is set the database
+// file must already exist, so reads never leave an empty database behind.
+func (s *SQLiteStorage) conn(ctx context.Context, create bool) (*sql.DB, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db != nil {
+		return s.db, nil
+	}
+
+	if !create {
+		if _, err := os.Stat(s.path); err != nil {
+			if os.IsNotExist(err) {
+				return nil, ErrNotInitialized
+			}
+			return nil, storage.NewStorageUnavailableError(fmt.Sprintf("database %s is not accessible", s.path), err)
+		}
+	}
+
+	// A single connection serializes writers, so concurrent calls in this process never
+	// see SQLITE_BUSY; other processes wait for the busy timeout
+	dsn := "file:" + s.path + "?_pragma=busy_timeout(10000)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, storage.NewStorageUnavailableError(fmt.Sprintf("failed to open database %s", s.path), err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, storage.NewStorageUnavailableError(fmt.Sprintf("failed to open database %s", s.path), err)
+	}
+
+	s.db = db
+	return db, nil
+}
+
+// Initialize creates the database file, tables and indexes
+func (s *SQLiteStorage) Initialize(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o750); err != nil {
+		return storage.NewStorageUnavailableError(
+			fmt.Sprintf("failed to create directory %s", filepath.Dir(s.path)), err)
+	}
+
+	db, err := s.conn(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("initializing SQLite storage", "path", s.path)
+
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO metadata (key, value) VALUES ('schema_version', ?), ('created_at', ?)`,
+		schemaVersion, time.Now().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	s.logger.Info("SQLite storage initialized successfully")
+	return nil
+}
+
+// IsInitialized checks if the database exists and holds the schema
+func (s *SQLiteStorage) IsInitialized(ctx context.Context) (bool, error) {
+	db, err := s.conn(ctx, false)
+	if errors.Is(err, ErrNotInitialized) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var version string
+	err = db.QueryRowContext(ctx, `SELECT value FROM metadata WHERE key = 'schema_version'`).Scan(&version)
+	if err != nil {
+		// A database without the metadata table has not been initialized
+		if errors.Is(err, sql.ErrNoRows) || strings.Contains(err.Error(), "no such table") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read metadata: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetStorageInfo returns information about the storage system
+func (s *SQLiteStorage) GetStorageInfo(ctx context.Context) (*storage.StorageInfo, error) {
+	initialized, err := s.IsInitialized(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage.StorageInfo{
+		Type:             "sqlite",
+		Version:          schemaVersion,
+		Path:             s.path,
+		Initialized:      initialized,
+		ReadOnly:         false,
+		SupportsBackups:  false, // storage backup, restore, verify and reindex work on JSON files only
+		SupportsIndexing: true,
+	}, nil
+}
+
+// Validate runs SQLite's integrity check and validates every stored invoice and client
+func (s *SQLiteStorage) Validate(ctx context.Context) error {
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("validating storage integrity")
+
+	var result string
+	if err := db.QueryRowContext(ctx, `PRAGMA integrity_check`).Scan(&result); err != nil {
+		return fmt.Errorf("integrity check failed: %w", err)
+	}
+	if result != "ok" {
+		return storage.NewCorruptedError("database", s.path, result)
+	}
+
+	if err := validateRecords(ctx, db, `SELECT id, data FROM invoices`, "invoice", func(data []byte) (validator, error) {
+		var invoice models.Invoice
+		err := json.Unmarshal(data, &invoice)
+		return &invoice, err
+	}); err != nil {
+		return fmt.Errorf("invoice validation failed: %w", err)
+	}
+
+	if err := validateRecords(ctx, db, `SELECT id, data FROM clients`, "client", func(data []byte) (validator, error) {
+		var client models.Client
+		err := json.Unmarshal(data, &client)
+		return &client, err
+	}); err != nil {
+		return fmt.Errorf("client validation failed: %w", err)
+	}
+
+	s.logger.Info("storage validation completed successfully")
+	return nil
+}
+
+// validator is a stored record that can check itself
+type validator interface {
+	Validate(ctx context.Context) error
+}
+
+// validateRecords decodes and validates every record returned by query
+func validateRecords(ctx context.Context, db *sql.DB, query, resource string, decode func([]byte) (validator, error)) error {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			id   string
+			data []byte
+		)
+		if err := rows.Scan(&id, &data); err != nil {
+			return err
+		}
+
+		record, err := decode(data)
+		if err != nil {
+			return storage.NewCorruptedError(resource, id, err.Error())
+		}
+		if err := record.Validate(ctx); err != nil {
+			return storage.NewCorruptedError(resource, id, err.Error())
+		}
+	}
+
+	return rows.Err()
+}
+
+// CreateInvoice stores a new invoice
+func (s *SQLiteStorage) CreateInvoice(ctx context.Context, invoice *models.Invoice) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if invoice == nil {
+		return ErrInvoiceCannotBeNil
+	}
+
+	// Validate invoice
+	if err := invoice.Validate(ctx); err != nil {
+		return fmt.Errorf("invalid invoice: %w", err)
+	}
+
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	created, err := insertNumberedInvoice(ctx, db, invoice)
+	if err != nil {
+		return fmt.Errorf("failed to write invoice: %w", err)
+	}
+	if !created {
+		var idTaken bool
+		err = db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM invoices WHERE id = ?)`, string(invoice.ID)).Scan(&idTaken)
+		switch {
+		case err != nil:
+			return fmt.Errorf("failed to check invoice conflict: %w", err)
+		case idTaken:
+			return storage.NewConflictError("invoice", string(invoice.ID), "")
+		default:
+			return fmt.Errorf("%w: %s", models.ErrInvoiceNumberExists, invoice.Number)
+		}
+	}
+
+	s.logger.Info("invoice created", "id", invoice.ID, "number", invoice.Number)
+	return nil
+}
+
+// insertNumberedInvoice inserts invoice unless its ID or its number is already stored,
+// reporting whether it did. It is a single statement, so processes sharing the database
+// cannot both store the same number.
+func insertNumberedInvoice(ctx context.Context, db *sql.DB, invoice *models.Invoice) (bool, error) {
+	row, err := newInvoiceRow(invoice)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := db.ExecContext(ctx, `
+		INSERT INTO invoices (id, number, client_id, client_name_lower, status, po_number, date,
+			due_date, total_cents, updated_at, deleted, version, data)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM invoices WHERE number = ?)
+		ON CONFLICT (id) DO NOTHING`,
+		append(row.args(), row.number)...)
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	return inserted == 1, err
+}
+
+// insertInvoice inserts invoice unless its ID is already stored, reporting whether it did
+func insertInvoice(ctx context.Context, db *sql.DB, invoice *models.Invoice) (bool, error) {
+	row, err := newInvoiceRow(invoice)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := db.ExecContext(ctx, `
+		INSERT INTO invoices (id, number, client_id, client_name_lower, status, po_number, date,
+			due_date, total_cents, updated_at, deleted, version, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO NOTHING`,
+		row.args()...)
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	return inserted == 1, err
+}
+
+// GetInvoice retrieves an invoice by ID
+func (s *SQLiteStorage) GetInvoice(ctx context.Context, id models.InvoiceID) (*models.Invoice, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if strings.TrimSpace(string(id)) == "" {
+		return nil, ErrInvoiceIDCannotBeEmpty
+	}
+
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err = db.QueryRowContext(ctx, `SELECT data FROM invoices WHERE id = ?`, string(id)).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.NewNotFoundError("invoice", string(id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read invoice: %w", err)
+	}
+
+	var invoice models.Invoice
+	if err := json.Unmarshal(data, &invoice); err != nil {
+		return nil, storage.NewCorruptedError("invoice", string(id), err.Error())
+	}
+	return &invoice, nil
+}
+
+// UpdateInvoice updates an existing invoice with optimistic locking: the row is only
+// written while its version still matches the caller's
+func (s *SQLiteStorage) UpdateInvoice(ctx context.Context, invoice *models.Invoice) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if invoice == nil {
+		return ErrInvoiceCannotBeNil
+	}
+
+	// Validate invoice
+	if err := invoice.Validate(ctx); err != nil {
+		return fmt.Errorf("invalid invoice: %w", err)
+	}
+
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	updated := *invoice
+	updated.Version++
+	updated.UpdatedAt = time.Now()
+
+	row, err := newInvoiceRow(&updated)
+	if err != nil {
+		return fmt.Errorf("failed to encode invoice: %w", err)
+	}
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE invoices SET number = ?, client_id = ?, client_name_lower = ?, status = ?,
+			po_number = ?, date = ?, due_date = ?, total_cents = ?, updated_at = ?, deleted = ?,
+			version = ?, data = ?
+		WHERE id = ? AND version = ?`,
+		append(row.args()[1:], row.id, invoice.Version)...)
+	if err != nil {
+		return fmt.Errorf("failed to write updated invoice: %w", err)
+	}
+
+	if changed, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to write updated invoice: %w", err)
+	} else if changed == 0 {
+		// Either the invoice is gone or another writer got there first
+		var actual int
+		err := db.QueryRowContext(ctx, `SELECT version FROM invoices WHERE id = ?`, string(invoice.ID)).Scan(&actual)
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.NewNotFoundError("invoice", string(invoice.ID))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read existing invoice: %w", err)
+		}
+		return storage.NewVersionMismatchError("invoice", string(invoice.ID), invoice.Version, actual)
+	}
+
+	invoice.Version = updated.Version
+	invoice.UpdatedAt = updated.UpdatedAt
+
+	s.logger.Info("invoice updated", "id", invoice.ID, "version", invoice.Version)
+	return nil
+}
+
+// DeleteInvoice removes an invoice by ID
+func (s *SQLiteStorage) DeleteInvoice(ctx context.Context, id models.InvoiceID) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	if strings.TrimSpace(string(id)) == "" {
+		return ErrInvoiceIDCannotBeEmpty
+	}
+
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.ExecContext(ctx, `DELETE FROM invoices WHERE id = ?`, string(id))
+	if err != nil {
+		return fmt.Errorf("failed to delete invoice: %w", err)
+	}
+	if deleted, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete invoice: %w", err)
+	} else if deleted == 0 {
+		return storage.NewNotFoundError("invoice", string(id))
+	}
+
+	// Attachments only exist for the invoice, so they go with it
+	if err := os.RemoveAll(s.attachmentsPath(id)); err != nil {
+		s.logger.Error("failed to delete invoice attachments", "error", err, "invoice_id", id)
+	}
+
+	s.logger.Info("invoice deleted", "id", id)
+	return nil
+}
+
+// attachmentsPath returns the directory holding an invoice's attachments, which are kept
+// next to the database file
+func (s *SQLiteStorage) attachmentsPath(id models.InvoiceID) string {
+	return filepath.Join(filepath.Dir(s.path), models.AttachmentsDir, string(id))
+}
+
+// ExistsInvoice checks if an invoice exists
+func (s *SQLiteStorage) ExistsInvoice(ctx context.Context, id models.InvoiceID) (bool, error) {
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return false, err
+	}
+
+	return exists(ctx, db, `SELECT 1 FROM invoices WHERE id = ?`, string(id))
+}
+
+// ListInvoices retrieves the invoices matching filter, filtered, sorted and paged in SQL
+func (s *SQLiteStorage) ListInvoices(ctx context.Context, filter models.InvoiceFilter) (*storage.InvoiceListResult, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	// Validate filter
+	if err := filter.Validate(ctx); err != nil {
+		return nil, storage.NewInvalidFilterError("filter", filter, err.Error())
+	}
+
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Count and page in one transaction so both see the same invoices
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	where, args := invoiceWhere(filter)
+
+	var total int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM invoices`+where, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count invoices: %w", err)
+	}
+
+	start, end := storage.PageBounds(total, filter)
+	query := `SELECT id, data FROM invoices` + where + invoiceOrderBy(filter) + ` LIMIT ? OFFSET ?`
+	rows, err := tx.QueryContext(ctx, query, append(args, end-start, start)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	invoices := make([]*models.Invoice, 0, end-start)
+	var warnings []string
+	for rows.Next() {
+		var (
+			id   string
+			data []byte
+		)
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("failed to list invoices: %w", err)
+		}
+
+		// Skip corrupted rows like the JSON storage skips corrupted files, but report them
+		var invoice models.Invoice
+		if err := json.Unmarshal(data, &invoice); err != nil {
+			s.logger.Error("failed to decode invoice", "invoice_id", id, "error", err)
+			warnings = append(warnings, fmt.Sprintf("skipped invoice %s: %v", id, err))
+			continue
+		}
+		invoices = append(invoices, &invoice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	// Paging still counts the skipped rows so the next page starts after them; the total does not
+	result := storage.NewInvoiceListResult(invoices, total, end, filter)
+	result.TotalCount -= int64(len(warnings))
+	result.Warnings = warnings
+	return result, nil
+}
+
+// CountInvoices returns the total count of invoices matching the filter
+func (s *SQLiteStorage) CountInvoices(ctx context.Context, filter models.InvoiceFilter) (int64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+
+	countFilter := storage.CountFilter(filter)
+	if err := countFilter.Validate(ctx); err != nil {
+		return 0, storage.NewInvalidFilterError("filter", countFilter, err.Error())
+	}
+
+	db, err := s.conn(ctx, false)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := invoiceWhere(countFilter)
+
+	var count int64
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM invoices`+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count invoices: %w", err)
+	}
+	return count, nil
+}
+
+// exists reports whether query returns a row
+func exists(ctx context.Context, db *sql.DB, query string, args ...any) (bool, error) {
+	var found int
+	err := db.QueryRowContext(ctx, query, args...).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
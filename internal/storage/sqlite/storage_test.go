@@ -0,0 +1,221 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	jsonStorage "github.com/mrz1836/go-invoice/internal/storage/json"
+)
+
+// newTestStorage returns an initialized storage in a temporary directory
+func newTestStorage(t *testing.T) *SQLiteStorage {
+	t.Helper()
+
+	s := NewSQLiteStorage(filepath.Join(t.TempDir(), "invoices.db"), nopLogger{})
+	t.Cleanup(func() { assert.NoError(t, s.Close()) })
+	require.NoError(t, s.Initialize(context.Background()))
+	return s
+}
+
+func TestNotInitialized(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "invoices.db")
+	s := NewSQLiteStorage(path, nopLogger{})
+
+	initialized, err := s.IsInitialized(ctx)
+	require.NoError(t, err)
+	assert.False(t, initialized)
+
+	_, err = s.GetInvoice(ctx, "INV-001")
+	require.ErrorIs(t, err, ErrNotInitialized)
+
+	_, err = s.ListClients(ctx, false, 0, 0)
+	require.ErrorIs(t, err, ErrNotInitialized)
+
+	// Reads must not leave an empty database behind
+	assert.NoFileExists(t, path)
+}
+
+func TestReopen(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+
+	client := &models.Client{
+		ID:        "CLIENT-001",
+		Name:      "Test Client",
+		Email:     "test@example.com",
+		Active:    true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, s.CreateClient(ctx, client))
+	require.NoError(t, s.Close())
+
+	reopened := NewSQLiteStorage(s.Path(), nopLogger{})
+	t.Cleanup(func() { assert.NoError(t, reopened.Close()) })
+
+	initialized, err := reopened.IsInitialized(ctx)
+	require.NoError(t, err)
+	assert.True(t, initialized)
+
+	// Initializing again keeps the stored records
+	require.NoError(t, reopened.Initialize(ctx))
+	found, err := reopened.FindClientByEmail(ctx, "TEST@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, client.ID, found.ID)
+	require.NoError(t, reopened.Validate(ctx))
+}
+
+func TestDeleteInvoiceRemovesAttachments(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+
+	client := models.Client{
+		ID: "CLIENT-001", Name: "Test Client", Email: "test@example.com", Active: true,
+		CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	require.NoError(t, s.CreateClient(ctx, &client))
+	invoice := &models.Invoice{
+		ID: "INV-001", Number: "INV-001", Client: client, Status: models.StatusDraft, Version: 1,
+		Date: time.Now(), DueDate: time.Now().AddDate(0, 0, 30), CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	require.NoError(t, s.CreateInvoice(ctx, invoice))
+
+	// Attachments are kept next to the database file
+	attachmentsPath := filepath.Join(filepath.Dir(s.Path()), models.AttachmentsDir, string(invoice.ID))
+	require.NoError(t, os.MkdirAll(attachmentsPath, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(attachmentsPath, "receipt.pdf"), []byte("%PDF"), 0o600))
+
+	require.NoError(t, s.DeleteInvoice(ctx, invoice.ID))
+	assert.NoDirExists(t, attachmentsPath)
+}
+
+func TestListInvoicesSkipsCorruptedRows(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStorage(t)
+
+	client := models.Client{
+		ID: "CLIENT-001", Name: "Test Client", Email: "test@example.com", Active: true,
+		CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	require.NoError(t, s.CreateClient(ctx, &client))
+	for _, id := range []models.InvoiceID{"INV-001", "INV-002", "INV-003"} {
+		require.NoError(t, s.CreateInvoice(ctx, &models.Invoice{
+			ID: id, Number: string(id), Client: client, Status: models.StatusDraft, Version: 1,
+			Date: time.Now(), DueDate: time.Now().AddDate(0, 0, 30), CreatedAt: time.Now(), UpdatedAt: time.Now(),
+		}))
+	}
+
+	db, err := s.conn(ctx, false)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE invoices SET data = ? WHERE id = ?`, []byte("{not json"), "INV-002")
+	require.NoError(t, err)
+
+	result, err := s.ListInvoices(ctx, models.InvoiceFilter{SortBy: "number"})
+	require.NoError(t, err)
+	require.Len(t, result.Invoices, 2)
+	assert.Equal(t, models.InvoiceID("INV-001"), result.Invoices[0].ID)
+	assert.Equal(t, models.InvoiceID("INV-003"), result.Invoices[1].ID)
+	assert.Equal(t, int64(2), result.TotalCount)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "skipped invoice INV-002")
+
+	// The page after a skipped row still starts after it
+	page, err := s.ListInvoices(ctx, models.InvoiceFilter{SortBy: "number", Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, page.Invoices, 1)
+	assert.True(t, page.HasMore)
+	assert.Equal(t, 2, page.NextOffset)
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	source := jsonStorage.NewJSONStorage(t.TempDir(), nopLogger{})
+	require.NoError(t, source.Initialize(ctx))
+
+	active := &models.Client{
+		ID: "CLIENT-001", Name: "Active Client", Email: "active@example.com",
+		Active: true, CreatedAt: now, UpdatedAt: now,
+	}
+	inactive := &models.Client{
+		ID: "CLIENT-002", Name: "Inactive Client", Email: "inactive@example.com",
+		Active: true, CreatedAt: now, UpdatedAt: now,
+	}
+	require.NoError(t, source.CreateClient(ctx, active))
+	require.NoError(t, source.CreateClient(ctx, inactive))
+	require.NoError(t, source.DeleteClient(ctx, inactive.ID))
+
+	invoice := &models.Invoice{
+		ID: "INV-001", Number: "INV-2024-001", Client: *active, Version: 1,
+		Date: now, DueDate: now.AddDate(0, 0, 30), Status: models.StatusSent,
+		Total: money.FromFloat(1500), CreatedAt: now, UpdatedAt: now,
+	}
+	require.NoError(t, source.CreateInvoice(ctx, invoice))
+	require.NoError(t, source.UpdateInvoice(ctx, invoice))
+
+	s := newTestStorage(t)
+
+	result, err := s.Import(ctx, source, source)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportResult{InvoicesImported: 1, ClientsImported: 2}, result)
+
+	imported, err := s.GetInvoice(ctx, invoice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, invoice.Version, imported.Version)
+	assert.Equal(t, invoice.Total, imported.Total)
+
+	// The version column carries over, so optimistic locking continues where it left off
+	require.NoError(t, s.UpdateInvoice(ctx, imported))
+	assert.Equal(t, invoice.Version+1, imported.Version)
+
+	clients, err := s.ListClients(ctx, true, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, clients.Clients, 1)
+	assert.Equal(t, active.ID, clients.Clients[0].ID)
+
+	count, err := s.CountInvoices(ctx, models.InvoiceFilter{Status: models.StatusSent, AmountMin: 1500})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// Importing again skips everything already present
+	result, err = s.Import(ctx, source, source)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportResult{InvoicesSkipped: 1, ClientsSkipped: 2}, result)
+}
+
+func TestAmountCents(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float64
+		min   int64
+		max   int64
+	}{
+		{name: "WholeAmount", value: 1500, min: 150000, max: 150000},
+		{name: "Cents", value: 0.29, min: 29, max: 29},
+		{name: "BetweenCents", value: 10.005, min: 1001, max: 1000},
+		{name: "SubCent", value: 0.001, min: 1, max: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.min, minCents(tt.value))
+			assert.Equal(t, tt.max, maxCents(tt.value))
+
+			// The thresholds agree with comparing the amount as a float
+			assert.GreaterOrEqual(t, money.Amount(tt.min).Float64(), tt.value)
+			assert.Less(t, money.Amount(tt.min-1).Float64(), tt.value)
+			assert.LessOrEqual(t, money.Amount(tt.max).Float64(), tt.value)
+			assert.Greater(t, money.Amount(tt.max+1).Float64(), tt.value)
+		})
+	}
+}
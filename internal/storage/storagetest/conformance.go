@@ -0,0 +1,811 @@
+// Package storagetest provides the behavioral test suite every storage backend must pass,
+// so the JSON, in-memory and future backends are interchangeable.
+//
+// A backend runs the suite from its own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.RunConformance(t, func(t *testing.T) storagetest.Backend {
+//			return NewBackend(t.TempDir())
+//		})
+//	}
+//
+// The contract the suite encodes:
+//
+//   - Every method checks its context first and returns ctx.Err() unchanged when it is done.
+//   - Creating an invoice or client with an ID already stored returns a ConflictError; nil
+//     records are rejected and invalid ones fail model validation ("invalid invoice").
+//     Creating an invoice with the number of another, even one in the trash, returns an
+//     error wrapping models.ErrInvoiceNumberExists and stores nothing.
+//   - Get, Update and Delete of a missing ID return a NotFoundError for the resource and ID.
+//     Get and Delete reject an empty or all-whitespace ID before looking anything up.
+//   - UpdateInvoice is optimistically locked: the stored Version must equal the caller's
+//     Version, otherwise a VersionMismatchError reports both and nothing is written. On
+//     success the caller's Version is incremented and UpdatedAt set. Of several concurrent
+//     updates from the same version exactly one wins.
+//   - DeleteInvoice removes the invoice; DeleteClient only marks the client inactive, so it
+//     can still be read, found by email and reported by ExistsClient.
+//   - Records are stored by value: changing a record after saving it, or one returned by a
+//     read, never changes the stored copy.
+//   - ListInvoices and CountInvoices match filters like storage.MatchesInvoiceFilter: soft-
+//     deleted invoices only with IncludeDeleted, newest first unless SortBy is set, ties broken
+//     by number. Pages carry the metadata of storage.NewInvoiceListResult and invalid filters
+//     return an InvalidFilterError. CountInvoices ignores paging and sorting.
+//   - ListClients sorts by name ignoring case; a limit of zero or less returns everything.
+//     FindClientByEmail ignores case and returns a NotFoundError when nothing matches.
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/go-invoice/internal/models"
+	"github.com/mrz1836/go-invoice/internal/money"
+	"github.com/mrz1836/go-invoice/internal/storage"
+)
+
+const (
+	testClientID001  = "CLIENT-001"
+	testClientName   = "Test Client"
+	testClientEmail  = "test@example.com"
+	testInvoiceID001 = "INV-001"
+	testInvoiceNum   = "INV-2024-001"
+)
+
+// Backend is the storage surface the conformance suite exercises
+type Backend interface {
+	storage.InvoiceStorage
+	storage.ClientStorage
+	storage.StorageInitializer
+}
+
+// Factory returns a new, empty and uninitialized backend. It is called once per test so
+// tests never share data; use t.TempDir for anything that must be cleaned up.
+type Factory func(t *testing.T) Backend
+
+// RunConformance runs the storage behavior tests against the backends built by factory
+func RunConformance(t *testing.T, factory Factory) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, ctx context.Context, s Backend)
+	}{
+		{"CreateInvoice", testCreateInvoice},
+		{"CreateInvoiceDuplicateNumber", testCreateInvoiceDuplicateNumber},
+		{"GetInvoice", testGetInvoice},
+		{"UpdateInvoice", testUpdateInvoice},
+		{"DeleteInvoice", testDeleteInvoice},
+		{"ExistsInvoice", testExistsInvoice},
+		{"ConcurrentInvoiceUpdates", testConcurrentInvoiceUpdates},
+		{"ReturnedInvoicesAreCopies", testReturnedInvoicesAreCopies},
+		{"ListInvoicesFilters", testListInvoicesFilters},
+		{"ListInvoicesSorting", testListInvoicesSorting},
+		{"ListInvoicesPaging", testListInvoicesPaging},
+		{"ListInvoicesDeleted", testListInvoicesDeleted},
+		{"CountInvoices", testCountInvoices},
+		{"CreateClient", testCreateClient},
+		{"GetClient", testGetClient},
+		{"UpdateClient", testUpdateClient},
+		{"DeleteClient", testDeleteClient},
+		{"ListClients", testListClients},
+		{"FindClientByEmail", testFindClientByEmail},
+		{"ExistsClient", testExistsClient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			s := factory(t)
+			require.NoError(t, s.Initialize(ctx))
+
+			initialized, err := s.IsInitialized(ctx)
+			require.NoError(t, err)
+			require.True(t, initialized)
+
+			tt.run(t, ctx, s)
+		})
+	}
+}
+
+// newInvoice returns a valid draft invoice for testClientID001
+func newInvoice(id models.InvoiceID, number string) *models.Invoice {
+	now := time.Now().Truncate(time.Second)
+	return &models.Invoice{
+		ID:     id,
+		Number: number,
+		Client: models.Client{
+			ID:        testClientID001,
+			Name:      testClientName,
+			Email:     testClientEmail,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		Version:   1,
+		Date:      now,
+		DueDate:   now.AddDate(0, 0, 30),
+		Status:    models.StatusDraft,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// newClient returns a valid active client
+func newClient(id models.ClientID, name, email string) *models.Client {
+	now := time.Now().Truncate(time.Second)
+	return &models.Client{
+		ID:        id,
+		Name:      name,
+		Email:     email,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// canceledContext returns a context that is already canceled
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func testCreateInvoice(t *testing.T, ctx context.Context, s Backend) {
+	invoice := newInvoice(testInvoiceID001, testInvoiceNum)
+	require.NoError(t, s.CreateInvoice(ctx, invoice))
+
+	// Duplicate IDs conflict
+	err := s.CreateInvoice(ctx, invoice)
+	var conflictErr storage.ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, "invoice", conflictErr.Resource)
+	assert.Equal(t, testInvoiceID001, conflictErr.ID)
+
+	err = s.CreateInvoice(ctx, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invoice cannot be nil")
+
+	err = s.CreateInvoice(ctx, &models.Invoice{ID: ""})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid invoice")
+
+	assert.Equal(t, context.Canceled, s.CreateInvoice(canceledContext(), newInvoice("INV-002", "INV-2024-002")))
+}
+
+func testCreateInvoiceDuplicateNumber(t *testing.T, ctx context.Context, s Backend) {
+	require.NoError(t, s.CreateInvoice(ctx, newInvoice(testInvoiceID001, testInvoiceNum)))
+
+	err := s.CreateInvoice(ctx, newInvoice("INV-002", testInvoiceNum))
+	require.ErrorIs(t, err, models.ErrInvoiceNumberExists)
+	exists, err := s.ExistsInvoice(ctx, "INV-002")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Numbers of invoices in the trash stay taken so the invoices can be restored
+	trashed, err := s.GetInvoice(ctx, testInvoiceID001)
+	require.NoError(t, err)
+	deletedAt := time.Now()
+	trashed.DeletedAt = &deletedAt
+	require.NoError(t, s.UpdateInvoice(ctx, trashed))
+	require.ErrorIs(t, s.CreateInvoice(ctx, newInvoice("INV-002", testInvoiceNum)), models.ErrInvoiceNumberExists)
+
+	require.NoError(t, s.CreateInvoice(ctx, newInvoice("INV-002", "INV-2024-002")))
+}
+
+func testGetInvoice(t *testing.T, ctx context.Context, s Backend) {
+	invoice := newInvoice(testInvoiceID001, testInvoiceNum)
+	require.NoError(t, s.CreateInvoice(ctx, invoice))
+
+	retrieved, err := s.GetInvoice(ctx, testInvoiceID001)
+	require.NoError(t, err)
+	require.NotNil(t, retrieved)
+	assert.Equal(t, invoice.ID, retrieved.ID)
+	assert.Equal(t, invoice.Number, retrieved.Number)
+	assert.Equal(t, invoice.Client.ID, retrieved.Client.ID)
+	assert.Equal(t, invoice.Version, retrieved.Version)
+	assert.WithinDuration(t, invoice.Date, retrieved.Date, time.Second)
+	assert.WithinDuration(t, invoice.DueDate, retrieved.DueDate, time.Second)
+
+	retrieved, err = s.GetInvoice(ctx, "INV-999")
+	assert.Nil(t, retrieved)
+	var notFoundErr storage.NotFoundError
+	require.ErrorAs(t, err, &notFoundErr)
+	assert.Equal(t, "invoice", notFoundErr.Resource)
+	assert.Equal(t, "INV-999", notFoundErr.ID)
+
+	for _, id := range []models.InvoiceID{"", "   "} {
+		_, err = s.GetInvoice(ctx, id)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invoice ID cannot be empty")
+	}
+
+	retrieved, err = s.GetInvoice(canceledContext(), testInvoiceID001)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, retrieved)
+}
+
+func testUpdateInvoice(t *testing.T, ctx context.Context, s Backend) {
+	invoice := newInvoice(testInvoiceID001, testInvoiceNum)
+	require.NoError(t, s.CreateInvoice(ctx, invoice))
+
+	invoice.Status = models.StatusSent
+	invoice.Description = "Updated description"
+	require.NoError(t, s.UpdateInvoice(ctx, invoice))
+
+	// A successful update bumps the caller's version
+	assert.Equal(t, 2, invoice.Version)
+
+	retrieved, err := s.GetInvoice(ctx, testInvoiceID001)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSent, retrieved.Status)
+	assert.Equal(t, "Updated description", retrieved.Description)
+	assert.Equal(t, 2, retrieved.Version)
+
+	// A stale version is rejected and leaves the stored invoice alone
+	stale := newInvoice(testInvoiceID001, testInvoiceNum)
+	stale.Status = models.StatusPaid
+	err = s.UpdateInvoice(ctx, stale)
+	var versionErr storage.VersionMismatchError
+	require.ErrorAs(t, err, &versionErr)
+	assert.Equal(t, "invoice", versionErr.Resource)
+	assert.Equal(t, testInvoiceID001, versionErr.ID)
+	assert.Equal(t, 1, versionErr.ExpectedVersion)
+	assert.Equal(t, 2, versionErr.ActualVersion)
+	assert.Equal(t, 1, stale.Version)
+
+	retrieved, err = s.GetInvoice(ctx, testInvoiceID001)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusSent, retrieved.Status)
+
+	err = s.UpdateInvoice(ctx, newInvoice("INV-999", "INV-2024-999"))
+	var notFoundErr storage.NotFoundError
+	require.ErrorAs(t, err, &notFoundErr)
+
+	err = s.UpdateInvoice(ctx, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invoice cannot be nil")
+
+	err = s.UpdateInvoice(ctx, &models.Invoice{ID: testInvoiceID001, Version: 2})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid invoice")
+
+	assert.Equal(t, context.Canceled, s.UpdateInvoice(canceledContext(), invoice))
+}
+
+func testDeleteInvoice(t *testing.T, ctx context.Context, s Backend) {
+	require.NoError(t, s.CreateInvoice(ctx, newInvoice(testInvoiceID001, testInvoiceNum)))
+	require.NoError(t, s.DeleteInvoice(ctx, testInvoiceID001))
+
+	_, err := s.GetInvoice(ctx, testInvoiceID001)
+	assert.True(t, storage.IsNotFound(err))
+
+	// Deleting twice is not found
+	err = s.DeleteInvoice(ctx, testInvoiceID001)
+	var notFoundErr storage.NotFoundError
+	require.ErrorAs(t, err, &notFoundErr)
+
+	for _, id := range []models.InvoiceID{"", "   "} {
+		err = s.DeleteInvoice(ctx, id)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invoice ID cannot be empty")
+	}
+
+	assert.Equal(t, context.Canceled, s.DeleteInvoice(canceledContext(), testInvoiceID001))
+}
+
+func testExistsInvoice(t *testing.T, ctx context.Context, s Backend) {
+	exists, err := s.ExistsInvoice(ctx, testInvoiceID001)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, s.CreateInvoice(ctx, newInvoice(testInvoiceID001, testInvoiceNum)))
+
+	exists, err = s.ExistsInvoice(ctx, testInvoiceID001)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, s.DeleteInvoice(ctx, testInvoiceID001))
+
+	exists, err = s.ExistsInvoice(ctx, testInvoiceID001)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, err = s.ExistsInvoice(canceledContext(), testInvoiceID001)
+	assert.Equal(t, context.Canceled, err)
+	assert.False(t, exists)
+}
+
+func testReturnedInvoicesAreCopies(t *testing.T, ctx context.Context, s Backend) {
+	invoice := newInvoice(testInvoiceID001, testInvoiceNum)
+	require.NoError(t, s.CreateInvoice(ctx, invoice))
+
+	// Changing the caller's invoice or a retrieved one must not reach the stored invoice
+	invoice.Description = "changed after create"
+	retrieved, err := s.GetInvoice(ctx, testInvoiceID001)
+	require.NoError(t, err)
+	assert.Empty(t, retrieved.Description)
+
+	retrieved.Status = models.StatusPaid
+	again, err := s.GetInvoice(ctx, testInvoiceID001)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusDraft, again.Status)
+}
+
+func testCreateClient(t *testing.T, ctx context.Context, s Backend) {
+	client := newClient(testClientID001, testClientName, testClientEmail)
+	require.NoError(t, s.CreateClient(ctx, client))
+
+	err := s.CreateClient(ctx, client)
+	var conflictErr storage.ConflictError
+	require.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, "client", conflictErr.Resource)
+	assert.Equal(t, testClientID001, conflictErr.ID)
+
+	err = s.CreateClient(ctx, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "client cannot be nil")
+
+	err = s.CreateClient(ctx, &models.Client{ID: ""})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid client")
+
+	assert.Equal(t, context.Canceled, s.CreateClient(canceledContext(), newClient("CLIENT-002", "Other", "other@example.com")))
+}
+
+func testGetClient(t *testing.T, ctx context.Context, s Backend) {
+	client := newClient(testClientID001, testClientName, testClientEmail)
+	client.Phone = "+1234567890"
+	client.Address = "123 Test St"
+	require.NoError(t, s.CreateClient(ctx, client))
+
+	retrieved, err := s.GetClient(ctx, testClientID001)
+	require.NoError(t, err)
+	require.NotNil(t, retrieved)
+	assert.Equal(t, client.ID, retrieved.ID)
+	assert.Equal(t, client.Name, retrieved.Name)
+	assert.Equal(t, client.Email, retrieved.Email)
+	assert.Equal(t, client.Phone, retrieved.Phone)
+	assert.Equal(t, client.Address, retrieved.Address)
+	assert.True(t, retrieved.Active)
+	assert.WithinDuration(t, client.CreatedAt, retrieved.CreatedAt, time.Second)
+
+	retrieved, err = s.GetClient(ctx, "CLIENT-999")
+	assert.Nil(t, retrieved)
+	var notFoundErr storage.NotFoundError
+	require.ErrorAs(t, err, &notFoundErr)
+	assert.Equal(t, "client", notFoundErr.Resource)
+	assert.Equal(t, "CLIENT-999", notFoundErr.ID)
+
+	for _, id := range []models.ClientID{"", "   "} {
+		_, err = s.GetClient(ctx, id)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "client ID cannot be empty")
+	}
+
+	retrieved, err = s.GetClient(canceledContext(), testClientID001)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, retrieved)
+}
+
+func testUpdateClient(t *testing.T, ctx context.Context, s Backend) {
+	client := newClient(testClientID001, testClientName, testClientEmail)
+	require.NoError(t, s.CreateClient(ctx, client))
+
+	client.Name = "Updated Client"
+	client.Email = "updated@example.com"
+	require.NoError(t, s.UpdateClient(ctx, client))
+	assert.WithinDuration(t, time.Now(), client.UpdatedAt, time.Second)
+
+	retrieved, err := s.GetClient(ctx, testClientID001)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated Client", retrieved.Name)
+	assert.Equal(t, "updated@example.com", retrieved.Email)
+
+	// Clients have no optimistic locking: the last update wins
+	other := newClient(testClientID001, "Last Update", "last@example.com")
+	require.NoError(t, s.UpdateClient(ctx, other))
+	retrieved, err = s.GetClient(ctx, testClientID001)
+	require.NoError(t, err)
+	assert.Equal(t, "Last Update", retrieved.Name)
+
+	err = s.UpdateClient(ctx, newClient("CLIENT-999", "Missing", "missing@example.com"))
+	var notFoundErr storage.NotFoundError
+	require.ErrorAs(t, err, &notFoundErr)
+
+	err = s.UpdateClient(ctx, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "client cannot be nil")
+
+	err = s.UpdateClient(ctx, &models.Client{ID: testClientID001})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid client")
+}
+
+func testDeleteClient(t *testing.T, ctx context.Context, s Backend) {
+	require.NoError(t, s.CreateClient(ctx, newClient(testClientID001, testClientName, testClientEmail)))
+
+	// Deleting a client only marks it inactive
+	require.NoError(t, s.DeleteClient(ctx, testClientID001))
+	retrieved, err := s.GetClient(ctx, testClientID001)
+	require.NoError(t, err)
+	assert.False(t, retrieved.Active)
+
+	err = s.DeleteClient(ctx, "CLIENT-999")
+	var notFoundErr storage.NotFoundError
+	require.ErrorAs(t, err, &notFoundErr)
+
+	for _, id := range []models.ClientID{"", "   "} {
+		err = s.DeleteClient(ctx, id)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "client ID cannot be empty")
+	}
+
+	assert.Equal(t, context.Canceled, s.DeleteClient(canceledContext(), testClientID001))
+}
+
+func testListClients(t *testing.T, ctx context.Context, s Backend) {
+	result, err := s.ListClients(ctx, true, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, result.Clients)
+	assert.Equal(t, int64(0), result.TotalCount)
+	assert.False(t, result.HasMore)
+
+	inactive := newClient("CLIENT-003", "Gamma Client", "gamma@example.com")
+	inactive.Active = false
+	for _, client := range []*models.Client{
+		newClient(testClientID001, "Alpha Client", "alpha@example.com"),
+		newClient("CLIENT-002", "beta Client", "beta@example.com"),
+		inactive,
+		newClient("CLIENT-004", "Delta Client", "delta@example.com"),
+	} {
+		require.NoError(t, s.CreateClient(ctx, client))
+	}
+
+	// Active clients sorted by name, ignoring case
+	result, err = s.ListClients(ctx, true, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, result.Clients, 3)
+	assert.Equal(t, int64(3), result.TotalCount)
+	assert.Equal(t, "Alpha Client", result.Clients[0].Name)
+	assert.Equal(t, "beta Client", result.Clients[1].Name)
+	assert.Equal(t, "Delta Client", result.Clients[2].Name)
+
+	result, err = s.ListClients(ctx, false, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, result.Clients, 4)
+
+	result, err = s.ListClients(ctx, true, 2, 0)
+	require.NoError(t, err)
+	assert.Len(t, result.Clients, 2)
+	assert.Equal(t, int64(3), result.TotalCount)
+	assert.True(t, result.HasMore)
+	assert.Equal(t, 2, result.NextOffset)
+
+	result, err = s.ListClients(ctx, true, 2, 2)
+	require.NoError(t, err)
+	assert.Len(t, result.Clients, 1)
+	assert.False(t, result.HasMore)
+
+	// No limit returns everything; an offset past the end returns nothing
+	result, err = s.ListClients(ctx, true, -1, 0)
+	require.NoError(t, err)
+	assert.Len(t, result.Clients, 3)
+
+	result, err = s.ListClients(ctx, true, 10, 100)
+	require.NoError(t, err)
+	assert.Empty(t, result.Clients)
+	assert.Equal(t, int64(3), result.TotalCount)
+
+	result, err = s.ListClients(canceledContext(), true, 10, 0)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, result)
+}
+
+func testFindClientByEmail(t *testing.T, ctx context.Context, s Backend) {
+	_, err := s.FindClientByEmail(ctx, "nobody@example.com")
+	var notFoundErr storage.NotFoundError
+	require.ErrorAs(t, err, &notFoundErr)
+
+	inactive := newClient("CLIENT-002", "Inactive", "inactive@example.com")
+	inactive.Active = false
+	require.NoError(t, s.CreateClient(ctx, newClient(testClientID001, testClientName, testClientEmail)))
+	require.NoError(t, s.CreateClient(ctx, inactive))
+
+	// Lookups ignore case and include inactive clients
+	client, err := s.FindClientByEmail(ctx, "TEST@EXAMPLE.COM")
+	require.NoError(t, err)
+	assert.Equal(t, models.ClientID(testClientID001), client.ID)
+
+	client, err = s.FindClientByEmail(ctx, "inactive@example.com")
+	require.NoError(t, err)
+	assert.False(t, client.Active)
+
+	for _, email := range []string{"", "   "} {
+		_, err = s.FindClientByEmail(ctx, email)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "email cannot be empty")
+	}
+
+	client, err = s.FindClientByEmail(canceledContext(), testClientEmail)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, client)
+}
+
+func testExistsClient(t *testing.T, ctx context.Context, s Backend) {
+	exists, err := s.ExistsClient(ctx, testClientID001)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, s.CreateClient(ctx, newClient(testClientID001, testClientName, testClientEmail)))
+
+	exists, err = s.ExistsClient(ctx, testClientID001)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	// Soft-deleted clients still exist
+	require.NoError(t, s.DeleteClient(ctx, testClientID001))
+	exists, err = s.ExistsClient(ctx, testClientID001)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = s.ExistsClient(canceledContext(), testClientID001)
+	assert.Equal(t, context.Canceled, err)
+	assert.False(t, exists)
+}
+
+func testConcurrentInvoiceUpdates(t *testing.T, ctx context.Context, s Backend) {
+	require.NoError(t, s.CreateInvoice(ctx, newInvoice(testInvoiceID001, testInvoiceNum)))
+
+	const writers = 8
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		succeeded  int
+		mismatched int
+	)
+	for i := range writers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// Every writer starts from version 1, so only the first update can apply
+			invoice := newInvoice(testInvoiceID001, testInvoiceNum)
+			invoice.Description = fmt.Sprintf("writer %d", i)
+			err := s.UpdateInvoice(ctx, invoice)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case storage.IsVersionMismatch(err):
+				mismatched++
+			default:
+				t.Errorf("unexpected update error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, writers-1, mismatched)
+
+	stored, err := s.GetInvoice(ctx, testInvoiceID001)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.Version)
+}
+
+// listFixture stores three invoices for two clients, one per status, a month apart:
+// INV-001 (paid, 1000, two months ago) and INV-002 (sent, 2000, last month, PO 4500012345)
+// for Client One, and INV-003 (draft, 3000, now) for Client Two
+func listFixture(t *testing.T, ctx context.Context, s Backend) time.Time {
+	t.Helper()
+
+	now := time.Now().Truncate(time.Second)
+	clientOne := models.Client{ID: testClientID001, Name: "Client One", Email: "one@example.com", CreatedAt: now, UpdatedAt: now}
+	clientTwo := models.Client{ID: "CLIENT-002", Name: "Client Two", Email: "two@example.com", CreatedAt: now, UpdatedAt: now}
+
+	paid := newInvoice(testInvoiceID001, testInvoiceNum)
+	paid.Client = clientOne
+	paid.Date, paid.DueDate = now.AddDate(0, -2, 0), now.AddDate(0, -1, 0)
+	paid.Status = models.StatusPaid
+	paid.Total = money.FromFloat(1000)
+
+	sent := newInvoice("INV-002", "INV-2024-002")
+	sent.Client = clientOne
+	sent.Date, sent.DueDate = now.AddDate(0, -1, 0), now
+	sent.Status = models.StatusSent
+	sent.PONumber = "4500012345"
+	sent.Total = money.FromFloat(2000)
+
+	draft := newInvoice("INV-003", "INV-2024-003")
+	draft.Client = clientTwo
+	draft.Date, draft.DueDate = now, now.AddDate(0, 1, 0)
+	draft.Total = money.FromFloat(3000)
+
+	for _, invoice := range []*models.Invoice{paid, sent, draft} {
+		require.NoError(t, s.CreateInvoice(ctx, invoice))
+	}
+	return now
+}
+
+// invoiceIDs returns the IDs of the listed invoices in order
+func invoiceIDs(result *storage.InvoiceListResult) []models.InvoiceID {
+	ids := make([]models.InvoiceID, 0, len(result.Invoices))
+	for _, invoice := range result.Invoices {
+		ids = append(ids, invoice.ID)
+	}
+	return ids
+}
+
+func testListInvoicesFilters(t *testing.T, ctx context.Context, s Backend) {
+	result, err := s.ListInvoices(ctx, models.InvoiceFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Invoices)
+	assert.Equal(t, int64(0), result.TotalCount)
+	assert.False(t, result.HasMore)
+
+	now := listFixture(t, ctx, s)
+
+	tests := []struct {
+		name   string
+		filter models.InvoiceFilter
+		want   []models.InvoiceID
+	}{
+		{"All", models.InvoiceFilter{}, []models.InvoiceID{"INV-003", "INV-002", testInvoiceID001}},
+		{"Status", models.InvoiceFilter{Status: models.StatusPaid}, []models.InvoiceID{testInvoiceID001}},
+		{"Client", models.InvoiceFilter{ClientID: testClientID001}, []models.InvoiceID{"INV-002", testInvoiceID001}},
+		{"PONumber", models.InvoiceFilter{PONumber: "4500012345"}, []models.InvoiceID{"INV-002"}},
+		{"DateRange", models.InvoiceFilter{DateFrom: now.AddDate(0, -2, 0), DateTo: now.AddDate(0, -1, 0)}, []models.InvoiceID{"INV-002", testInvoiceID001}},
+		{"DueDateRange", models.InvoiceFilter{DueDateFrom: now, DueDateTo: now.AddDate(0, 1, 0)}, []models.InvoiceID{"INV-003", "INV-002"}},
+		{"AmountRange", models.InvoiceFilter{AmountMin: 1500, AmountMax: 2500}, []models.InvoiceID{"INV-002"}},
+		{"NoMatch", models.InvoiceFilter{Status: models.StatusVoided}, []models.InvoiceID{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.ListInvoices(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, invoiceIDs(result))
+			assert.Equal(t, int64(len(tt.want)), result.TotalCount)
+
+			count, err := s.CountInvoices(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), count)
+		})
+	}
+
+	result, err = s.ListInvoices(ctx, models.InvoiceFilter{Status: "invalid-status"})
+	assert.Nil(t, result)
+	var filterErr storage.InvalidFilterError
+	require.ErrorAs(t, err, &filterErr)
+
+	result, err = s.ListInvoices(canceledContext(), models.InvoiceFilter{})
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, result)
+}
+
+func testListInvoicesSorting(t *testing.T, ctx context.Context, s Backend) {
+	listFixture(t, ctx, s)
+
+	tests := []struct {
+		sortBy string
+		desc   bool
+		want   []models.InvoiceID
+	}{
+		{models.InvoiceSortDate, false, []models.InvoiceID{testInvoiceID001, "INV-002", "INV-003"}},
+		{models.InvoiceSortAmount, true, []models.InvoiceID{"INV-003", "INV-002", testInvoiceID001}},
+		{models.InvoiceSortStatus, false, []models.InvoiceID{"INV-003", testInvoiceID001, "INV-002"}},
+		{models.InvoiceSortNumber, true, []models.InvoiceID{"INV-003", "INV-002", testInvoiceID001}},
+		// Ties on client name fall back to the invoice number
+		{models.InvoiceSortClient, true, []models.InvoiceID{"INV-003", testInvoiceID001, "INV-002"}},
+	}
+	for _, tt := range tests {
+		result, err := s.ListInvoices(ctx, models.InvoiceFilter{SortBy: tt.sortBy, SortDesc: tt.desc})
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, invoiceIDs(result), "sort by %s", tt.sortBy)
+	}
+
+	_, err := s.ListInvoices(ctx, models.InvoiceFilter{SortBy: "due_date"})
+	var filterErr storage.InvalidFilterError
+	require.ErrorAs(t, err, &filterErr)
+}
+
+func testListInvoicesPaging(t *testing.T, ctx context.Context, s Backend) {
+	listFixture(t, ctx, s)
+
+	tests := []struct {
+		name                        string
+		limit, offset               int
+		want                        []models.InvoiceID
+		hasMore                     bool
+		nextOffset, page, pages, sz int
+	}{
+		{"FirstPage", 2, 0, []models.InvoiceID{"INV-003", "INV-002"}, true, 2, 1, 2, 2},
+		{"LastPage", 2, 2, []models.InvoiceID{testInvoiceID001}, false, 0, 2, 2, 2},
+		{"ExactPage", 3, 0, []models.InvoiceID{"INV-003", "INV-002", testInvoiceID001}, false, 0, 1, 1, 3},
+		{"SingleItemPages", 1, 2, []models.InvoiceID{testInvoiceID001}, false, 0, 3, 3, 1},
+		{"NoLimit", 0, 0, []models.InvoiceID{"INV-003", "INV-002", testInvoiceID001}, false, 0, 1, 1, 3},
+		{"OffsetPastEnd", 2, 10, []models.InvoiceID{}, false, 0, 6, 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.ListInvoices(ctx, models.InvoiceFilter{Limit: tt.limit, Offset: tt.offset})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, invoiceIDs(result))
+			assert.Equal(t, int64(3), result.TotalCount)
+			assert.Equal(t, tt.hasMore, result.HasMore)
+			assert.Equal(t, tt.nextOffset, result.NextOffset)
+			assert.Equal(t, tt.page, result.CurrentPage)
+			assert.Equal(t, tt.pages, result.TotalPages)
+			assert.Equal(t, tt.sz, result.PageSize)
+		})
+	}
+
+	// An empty result is a single page
+	result, err := s.ListInvoices(ctx, models.InvoiceFilter{Status: models.StatusVoided, Limit: 2})
+	require.NoError(t, err)
+	assert.Empty(t, result.Invoices)
+	assert.Equal(t, 1, result.CurrentPage)
+	assert.Equal(t, 1, result.TotalPages)
+}
+
+func testListInvoicesDeleted(t *testing.T, ctx context.Context, s Backend) {
+	listFixture(t, ctx, s)
+
+	deleted, err := s.GetInvoice(ctx, "INV-002")
+	require.NoError(t, err)
+	deletedAt := time.Now()
+	deleted.DeletedAt = &deletedAt
+	require.NoError(t, s.UpdateInvoice(ctx, deleted))
+
+	// Soft-deleted invoices are hidden from lists and counts unless requested
+	result, err := s.ListInvoices(ctx, models.InvoiceFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []models.InvoiceID{"INV-003", testInvoiceID001}, invoiceIDs(result))
+	assert.Equal(t, int64(2), result.TotalCount)
+
+	count, err := s.CountInvoices(ctx, models.InvoiceFilter{IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	result, err = s.ListInvoices(ctx, models.InvoiceFilter{IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.Len(t, result.Invoices, 3)
+
+	// They can still be read by ID
+	stored, err := s.GetInvoice(ctx, "INV-002")
+	require.NoError(t, err)
+	assert.NotNil(t, stored.DeletedAt)
+}
+
+func testCountInvoices(t *testing.T, ctx context.Context, s Backend) {
+	count, err := s.CountInvoices(ctx, models.InvoiceFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	listFixture(t, ctx, s)
+
+	// Paging and sorting do not change the count
+	count, err = s.CountInvoices(ctx, models.InvoiceFilter{Limit: 1, Offset: 1, SortBy: models.InvoiceSortAmount})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	count, err = s.CountInvoices(ctx, models.InvoiceFilter{AmountMin: 2000})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	_, err = s.CountInvoices(ctx, models.InvoiceFilter{Status: "invalid-status"})
+	var filterErr storage.InvalidFilterError
+	require.ErrorAs(t, err, &filterErr)
+
+	count, err = s.CountInvoices(canceledContext(), models.InvoiceFilter{})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int64(0), count)
+}
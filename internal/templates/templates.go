@@ -7,3 +7,15 @@ import _ "embed"
 //
 //go:embed default.html
 var DefaultInvoiceTemplate string
+
+// DefaultStatementTemplate contains the embedded client statement template
+//
+//go:embed statement.html
+var DefaultStatementTemplate string
+
+// DefaultReminderTemplate contains the embedded payment reminder email template. It is a
+// text/template whose first line is the subject, written as "Subject: ...", followed by a
+// blank line and the message body.
+//
+//go:embed reminder.txt
+var DefaultReminderTemplate string
@@ -196,7 +196,7 @@ func (suite *IntegrationTestSuite) TestBasicInvoiceCreation() {
 	suite.Equal(models.StatusDraft, invoice.Status)
 
 	// Verify basic calculations (subtotal should be correct even if tax isn't applied)
-	suite.InDelta(800.0, invoice.Subtotal, 0.01)
+	suite.InDelta(800.0, invoice.Subtotal.Float64(), 0.01)
 	suite.Equal("INT-001", invoice.Number)
 }
 
@@ -432,7 +432,7 @@ func (suite *IntegrationTestSuite) TestCompleteWorkflow() {
 
 	// Calculate expected total: 4*120 + 6.5*120 + 2*100 = 480 + 780 + 200 = 1460
 	expectedSubtotal := 4.0*120.0 + 6.5*120.0 + 2.0*100.0
-	suite.InEpsilon(expectedSubtotal, invoice.Subtotal, 0.001)
+	suite.InEpsilon(expectedSubtotal, invoice.Subtotal.Float64(), 0.001)
 
 	// Step 5: Verify we can list and retrieve the invoice
 	listResult, err := suite.storage.ListInvoices(ctx, models.InvoiceFilter{})